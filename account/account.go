@@ -3,11 +3,13 @@ package account
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/prebid/go-gdpr/consentconstants"
 
 	"github.com/prebid/prebid-server/v2/config"
 	"github.com/prebid/prebid-server/v2/errortypes"
+	"github.com/prebid/prebid-server/v2/logger"
 	"github.com/prebid/prebid-server/v2/metrics"
 	"github.com/prebid/prebid-server/v2/openrtb_ext"
 	"github.com/prebid/prebid-server/v2/stored_requests"
@@ -23,6 +25,8 @@ func GetAccount(ctx context.Context, cfg *config.Configuration, fetcher stored_r
 		}}
 	}
 
+	logger.SetAccountID(ctx, accountID)
+
 	if accountJSON, accErrs := fetcher.FetchAccount(ctx, cfg.AccountDefaultsJSON(), accountID); len(accErrs) > 0 || accountJSON == nil {
 		// accountID does not reference a valid account
 		for _, e := range accErrs {
@@ -106,6 +110,15 @@ func setDerivedConfig(account *config.Account) {
 			pc.EnforceAlgoID = algo
 		}
 
+		// To look for a purpose's restricted vendors in O(1) time, for each purpose we fill this hash table with
+		// bidders located in the RestrictedVendors field of the GDPR.PurposeX struct
+		if pc.RestrictedVendors != nil {
+			pc.RestrictedVendorMap = make(map[string]struct{})
+			for _, v := range pc.RestrictedVendors {
+				pc.RestrictedVendorMap[v] = struct{}{}
+			}
+		}
+
 		// To look for a purpose's vendor exceptions in O(1) time, for each purpose we fill this hash table with bidders
 		// located in the VendorExceptions field of the GDPR.PurposeX struct
 		if pc.VendorExceptions == nil {
@@ -117,6 +130,14 @@ func setDerivedConfig(account *config.Account) {
 		}
 	}
 
+	// Consent strings have their publisher country in upper case
+	if countries := account.GDPR.PurposeOneTreatment.Countries; countries != nil {
+		account.GDPR.PurposeOneTreatment.Countries = make(map[string]config.AccountGDPRPurposeOneTreatmentCountry, len(countries))
+		for country, treatment := range countries {
+			account.GDPR.PurposeOneTreatment.Countries[strings.ToUpper(country)] = treatment
+		}
+	}
+
 	// To look for special feature 1's vendor exceptions in O(1) time, we fill this hash table with bidders
 	// located in the VendorExceptions field
 	if account.GDPR.SpecialFeature1.VendorExceptions != nil {
@@ -136,4 +157,6 @@ func setDerivedConfig(account *config.Account) {
 			account.GDPR.BasicEnforcementVendorsMap[v] = struct{}{}
 		}
 	}
+
+	account.Currency.NormalizeRates()
 }
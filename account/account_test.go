@@ -121,12 +121,13 @@ func TestGetAccount(t *testing.T) {
 
 func TestSetDerivedConfig(t *testing.T) {
 	tests := []struct {
-		description              string
-		purpose1VendorExceptions []string
-		feature1VendorExceptions []openrtb_ext.BidderName
-		basicEnforcementVendors  []string
-		enforceAlgo              string
-		wantEnforceAlgoID        config.TCF2EnforcementAlgo
+		description               string
+		purpose1VendorExceptions  []string
+		purpose2RestrictedVendors []string
+		feature1VendorExceptions  []openrtb_ext.BidderName
+		basicEnforcementVendors   []string
+		enforceAlgo               string
+		wantEnforceAlgoID         config.TCF2EnforcementAlgo
 	}{
 		{
 			description:              "Nil purpose 1 vendor exceptions",
@@ -140,6 +141,14 @@ func TestSetDerivedConfig(t *testing.T) {
 			description:              "Multiple purpose 1 vendor exceptions",
 			purpose1VendorExceptions: []string{"appnexus", "rubicon"},
 		},
+		{
+			description:               "Nil purpose 2 restricted vendors",
+			purpose2RestrictedVendors: nil,
+		},
+		{
+			description:               "Multiple purpose 2 restricted vendors",
+			purpose2RestrictedVendors: []string{"appnexus", "rubicon"},
+		},
 		{
 			description:              "Nil feature 1 vendor exceptions",
 			feature1VendorExceptions: nil,
@@ -183,6 +192,9 @@ func TestSetDerivedConfig(t *testing.T) {
 					VendorExceptions: tt.purpose1VendorExceptions,
 					EnforceAlgo:      tt.enforceAlgo,
 				},
+				Purpose2: config.AccountGDPRPurpose{
+					RestrictedVendors: tt.purpose2RestrictedVendors,
+				},
 				SpecialFeature1: config.AccountGDPRSpecialFeature{
 					VendorExceptions: tt.feature1VendorExceptions,
 				},
@@ -197,6 +209,11 @@ func TestSetDerivedConfig(t *testing.T) {
 			purpose1ExceptionMapKeys = append(purpose1ExceptionMapKeys, k)
 		}
 
+		purpose2RestrictedMapKeys := make([]string, 0)
+		for k := range account.GDPR.Purpose2.RestrictedVendorMap {
+			purpose2RestrictedMapKeys = append(purpose2RestrictedMapKeys, k)
+		}
+
 		feature1ExceptionMapKeys := make([]openrtb_ext.BidderName, 0)
 		for k := range account.GDPR.SpecialFeature1.VendorExceptionMap {
 			feature1ExceptionMapKeys = append(feature1ExceptionMapKeys, k)
@@ -208,9 +225,29 @@ func TestSetDerivedConfig(t *testing.T) {
 		}
 
 		assert.ElementsMatch(t, purpose1ExceptionMapKeys, tt.purpose1VendorExceptions, tt.description)
+		assert.ElementsMatch(t, purpose2RestrictedMapKeys, tt.purpose2RestrictedVendors, tt.description)
 		assert.ElementsMatch(t, feature1ExceptionMapKeys, tt.feature1VendorExceptions, tt.description)
 		assert.ElementsMatch(t, basicEnforcementMapKeys, tt.basicEnforcementVendors, tt.description)
 
 		assert.Equal(t, account.GDPR.Purpose1.EnforceAlgoID, tt.wantEnforceAlgoID, tt.description)
 	}
 }
+
+func TestSetDerivedConfigPurposeOneTreatmentCountries(t *testing.T) {
+	enabled := true
+	account := config.Account{GDPR: config.AccountGDPR{PurposeOneTreatment: config.AccountGDPRPurposeOneTreatment{
+		Countries: map[string]config.AccountGDPRPurposeOneTreatmentCountry{"de": {Enabled: &enabled}},
+	}}}
+
+	setDerivedConfig(&account)
+
+	assert.Equal(t, map[string]config.AccountGDPRPurposeOneTreatmentCountry{"DE": {Enabled: &enabled}}, account.GDPR.PurposeOneTreatment.Countries)
+}
+
+func TestSetDerivedConfigCurrencyRates(t *testing.T) {
+	account := config.Account{Currency: config.AccountCurrency{Rates: map[string]map[string]float64{"usd": {"eur": 0.9}}}}
+
+	setDerivedConfig(&account)
+
+	assert.Equal(t, map[string]map[string]float64{"USD": {"EUR": 0.9}}, account.Currency.Rates)
+}
@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v2/errortypes"
 	"github.com/prebid/prebid-server/v2/openrtb_ext"
 	"github.com/stretchr/testify/assert"
 )
@@ -90,7 +91,7 @@ func TestValidateAdServerTargeting(t *testing.T) {
 			},
 			expectedTargeting: []openrtb_ext.AdServerTarget(nil),
 			expectedWarnings: []openrtb_ext.ExtBidderMessage{
-				{Code: 10007, Message: "Key is empty for the ad server targeting object at index 0"},
+				{Code: 10007, Category: errortypes.CategoryTargeting, Message: "Key is empty for the ad server targeting object at index 0"},
 			},
 		},
 		{
@@ -100,7 +101,7 @@ func TestValidateAdServerTargeting(t *testing.T) {
 			},
 			expectedTargeting: []openrtb_ext.AdServerTarget(nil),
 			expectedWarnings: []openrtb_ext.ExtBidderMessage{
-				{Code: 10007, Message: "Incorrect source for the ad server targeting object at index 0"},
+				{Code: 10007, Category: errortypes.CategoryTargeting, Message: "Incorrect source for the ad server targeting object at index 0"},
 			},
 		},
 		{
@@ -110,7 +111,7 @@ func TestValidateAdServerTargeting(t *testing.T) {
 			},
 			expectedTargeting: []openrtb_ext.AdServerTarget(nil),
 			expectedWarnings: []openrtb_ext.ExtBidderMessage{
-				{Code: 10007, Message: "Value is empty for the ad server targeting object at index 0"},
+				{Code: 10007, Category: errortypes.CategoryTargeting, Message: "Value is empty for the ad server targeting object at index 0"},
 			},
 		},
 		{
@@ -123,7 +124,7 @@ func TestValidateAdServerTargeting(t *testing.T) {
 				{Key: "adt_key1", Source: "static", Value: "valid"},
 			},
 			expectedWarnings: []openrtb_ext.ExtBidderMessage{
-				{Code: 10007, Message: "Value is empty for the ad server targeting object at index 1"},
+				{Code: 10007, Category: errortypes.CategoryTargeting, Message: "Value is empty for the ad server targeting object at index 1"},
 			},
 		},
 	}
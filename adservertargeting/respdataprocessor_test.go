@@ -8,6 +8,7 @@ import (
 
 	"github.com/prebid/openrtb/v20/openrtb2"
 	"github.com/prebid/openrtb/v20/openrtb3"
+	"github.com/prebid/prebid-server/v2/errortypes"
 	"github.com/prebid/prebid-server/v2/openrtb_ext"
 	"github.com/stretchr/testify/assert"
 )
@@ -208,7 +209,7 @@ func TestProcessResponseTargetingData(t *testing.T) {
 				"inKey1": "inVal1",
 			},
 			expectedWarnings: []openrtb_ext.ExtBidderMessage{
-				{Code: 10007, Message: "incorrect value type for path: testData, value can only be string or number for bidder: bidderA, bid id: testBidId"},
+				{Code: 10007, Category: errortypes.CategoryTargeting, Message: "incorrect value type for path: testData, value can only be string or number for bidder: bidderA, bid id: testBidId"},
 			},
 		},
 	}
@@ -247,12 +248,12 @@ func TestBuildBidExt(t *testing.T) {
 			inputTargetingData: map[string]string{"inKey1": "inVal1"},
 			inputBid:           openrtb2.Bid{ID: "testBidId", ImpID: "testBidImpId1", Ext: json.RawMessage(`{"prebid": {"test": 1}}`)},
 			inputWarnings: []openrtb_ext.ExtBidderMessage{
-				{Code: 10007, Message: "incorrect value type for path: testData, value can only be string or number for bidder: bidderA, bid id: testBidId"},
+				{Code: 10007, Category: errortypes.CategoryTargeting, Message: "incorrect value type for path: testData, value can only be string or number for bidder: bidderA, bid id: testBidId"},
 			},
 			truncateTargetAttribute: 20,
 			expectedExt:             json.RawMessage(`{"prebid":{"targeting":{"inKey1":"inVal1"},"test":1}}`),
 			expectedWarnings: []openrtb_ext.ExtBidderMessage{
-				{Code: 10007, Message: "incorrect value type for path: testData, value can only be string or number for bidder: bidderA, bid id: testBidId"},
+				{Code: 10007, Category: errortypes.CategoryTargeting, Message: "incorrect value type for path: testData, value can only be string or number for bidder: bidderA, bid id: testBidId"},
 			},
 		},
 		{
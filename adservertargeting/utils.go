@@ -22,8 +22,9 @@ func splitAndGet(path string, data []byte, delimiter string) (string, error) {
 
 func createWarning(message string) openrtb_ext.ExtBidderMessage {
 	return openrtb_ext.ExtBidderMessage{
-		Code:    errortypes.AdServerTargetingWarningCode,
-		Message: message,
+		Code:     errortypes.AdServerTargetingWarningCode,
+		Category: errortypes.CategoryOf(errortypes.AdServerTargetingWarningCode),
+		Message:  message,
 	}
 }
 
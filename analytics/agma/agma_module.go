@@ -12,10 +12,10 @@ import (
 
 	"github.com/benbjohnson/clock"
 	"github.com/docker/go-units"
-	"github.com/golang/glog"
 	"github.com/prebid/go-gdpr/vendorconsent"
 	"github.com/prebid/prebid-server/v2/analytics"
 	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/logger"
 	"github.com/prebid/prebid-server/v2/openrtb_ext"
 )
 
@@ -93,7 +93,7 @@ func (l *AgmaLogger) start() {
 	for {
 		select {
 		case <-l.sigTermCh:
-			glog.Infof("[AgmaAnalytics] Received Close, trying to flush buffer")
+			logger.Infof("[AgmaAnalytics] Received Close, trying to flush buffer")
 			l.flush()
 			return
 		case event := <-l.bufferCh:
@@ -139,7 +139,7 @@ func (l *AgmaLogger) flush() {
 	if err != nil {
 		l.reset()
 		l.mux.Unlock()
-		glog.Warning("[AgmaAnalytics] fail to copy the buffer")
+		logger.Warning("[AgmaAnalytics] fail to copy the buffer")
 		return
 	}
 
@@ -223,7 +223,7 @@ func (l *AgmaLogger) LogAuctionObject(event *analytics.AuctionObject) {
 	}
 	data, err := serializeAnayltics(event.RequestWrapper, EventTypeAuction, code, event.StartTime)
 	if err != nil {
-		glog.Errorf("[AgmaAnalytics] Error serializing auction object: %v", err)
+		logger.Errorf("[AgmaAnalytics] Error serializing auction object: %v", err)
 		return
 	}
 	l.bufferCh <- data
@@ -239,7 +239,7 @@ func (l *AgmaLogger) LogAmpObject(event *analytics.AmpObject) {
 	}
 	data, err := serializeAnayltics(event.RequestWrapper, EventTypeAmp, code, event.StartTime)
 	if err != nil {
-		glog.Errorf("[AgmaAnalytics] Error serializing amp object: %v", err)
+		logger.Errorf("[AgmaAnalytics] Error serializing amp object: %v", err)
 		return
 	}
 	l.bufferCh <- data
@@ -255,7 +255,7 @@ func (l *AgmaLogger) LogVideoObject(event *analytics.VideoObject) {
 	}
 	data, err := serializeAnayltics(event.RequestWrapper, EventTypeVideo, code, event.StartTime)
 	if err != nil {
-		glog.Errorf("[AgmaAnalytics] Error serializing video object: %v", err)
+		logger.Errorf("[AgmaAnalytics] Error serializing video object: %v", err)
 		return
 	}
 	l.bufferCh <- data
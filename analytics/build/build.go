@@ -4,13 +4,14 @@ import (
 	"encoding/json"
 
 	"github.com/benbjohnson/clock"
-	"github.com/golang/glog"
 	"github.com/prebid/prebid-server/v2/analytics"
 	"github.com/prebid/prebid-server/v2/analytics/agma"
 	"github.com/prebid/prebid-server/v2/analytics/clients"
 	"github.com/prebid/prebid-server/v2/analytics/filesystem"
+	"github.com/prebid/prebid-server/v2/analytics/hub"
 	"github.com/prebid/prebid-server/v2/analytics/pubstack"
 	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/logger"
 	"github.com/prebid/prebid-server/v2/openrtb_ext"
 	"github.com/prebid/prebid-server/v2/ortb"
 	"github.com/prebid/prebid-server/v2/privacy"
@@ -23,7 +24,7 @@ func New(analytics *config.Analytics) analytics.Runner {
 		if mod, err := filesystem.NewFileLogger(analytics.File.Filename); err == nil {
 			modules["filelogger"] = mod
 		} else {
-			glog.Fatalf("Could not initialize FileLogger for file %v :%v", analytics.File.Filename, err)
+			logger.Fatalf("Could not initialize FileLogger for file %v :%v", analytics.File.Filename, err)
 		}
 	}
 
@@ -40,7 +41,7 @@ func New(analytics *config.Analytics) analytics.Runner {
 		if err == nil {
 			modules["pubstack"] = pubstackModule
 		} else {
-			glog.Errorf("Could not initialize PubstackModule: %v", err)
+			logger.Errorf("Could not initialize PubstackModule: %v", err)
 		}
 	}
 
@@ -52,7 +53,19 @@ func New(analytics *config.Analytics) analytics.Runner {
 		if err == nil {
 			modules["agma"] = agmaModule
 		} else {
-			glog.Errorf("Could not initialize Agma Anayltics: %v", err)
+			logger.Errorf("Could not initialize Agma Anayltics: %v", err)
+		}
+	}
+
+	if analytics.Hub.Forward.Enabled {
+		forwarder, err := hub.NewForwarder(
+			clients.GetDefaultHttpInstance(),
+			analytics.Hub.Forward,
+			clock.New())
+		if err == nil {
+			modules[hub.ModuleName] = forwarder
+		} else {
+			logger.Errorf("Could not initialize the analytics hub forwarder: %v", err)
 		}
 	}
 
@@ -160,7 +173,8 @@ func evaluateActivities(rw *openrtb_ext.RequestWrapper, ac privacy.ActivityContr
 }
 
 func updateReqWrapperForAnalytics(rw *openrtb_ext.RequestWrapper, adapterName string, isCloned bool) *openrtb_ext.RequestWrapper {
-	if rw == nil {
+	// the hub forwards the whole analytics object, for its own modules to take their entries from
+	if rw == nil || adapterName == hub.ModuleName {
 		return nil
 	}
 	reqExt, _ := rw.GetRequestExt()
@@ -10,6 +10,7 @@ import (
 
 	"github.com/prebid/openrtb/v20/openrtb2"
 	"github.com/prebid/prebid-server/v2/analytics"
+	"github.com/prebid/prebid-server/v2/analytics/hub"
 	"github.com/prebid/prebid-server/v2/config"
 	"github.com/prebid/prebid-server/v2/privacy"
 	"github.com/prebid/prebid-server/v2/util/ptrutil"
@@ -591,6 +592,19 @@ func TestUpdateReqWrapperForAnalytics(t *testing.T) {
 					Ext: []byte(`{"prebid":{"analytics":{"adapter1":{"client-analytics":true},"adapter2":{"client-analytics":false}}}}`)},
 			},
 		},
+		{
+			description: "Hub forwarder so ext.prebid.analytics is kept for the hub's modules",
+			givenReqWrapper: &openrtb_ext.RequestWrapper{
+				BidRequest: &openrtb2.BidRequest{
+					Ext: []byte(`{"prebid":{"analytics":{"adapter1":{"client-analytics":true},"adapter2":{"client-analytics":false}}}}`)},
+			},
+			givenAdapterName: hub.ModuleName,
+			givenIsCloned:    false,
+			expectedUpdatedBidRequest: &openrtb2.BidRequest{
+				Ext: []byte(`{"prebid":{"analytics":{"adapter1":{"client-analytics":true},"adapter2":{"client-analytics":false}}}}`),
+			},
+			expectedCloneRequest: nil,
+		},
 		{
 			description:               "Given request is nil, check there are no exceptions",
 			givenReqWrapper:           nil,
@@ -31,6 +31,8 @@ type AuctionObject struct {
 	HookExecutionOutcome []hookexecution.StageOutcome
 	SeatNonBid           []openrtb_ext.SeatNonBid
 	RequestWrapper       *openrtb_ext.RequestWrapper
+	// Test is true for test traffic, when it's kept apart from production traffic
+	Test bool
 }
 
 // Loggable object of a transaction at /openrtb2/amp endpoint
@@ -44,6 +46,8 @@ type AmpObject struct {
 	HookExecutionOutcome []hookexecution.StageOutcome
 	SeatNonBid           []openrtb_ext.SeatNonBid
 	RequestWrapper       *openrtb_ext.RequestWrapper
+	// Test is true for test traffic, when it's kept apart from production traffic
+	Test bool
 }
 
 // Loggable object of a transaction at /openrtb2/video endpoint
@@ -6,6 +6,7 @@ type EventType string
 // Possible values of events Prebid Server can receive for an ad.
 const (
 	Win  EventType = "win"
+	Loss EventType = "loss"
 	Imp  EventType = "imp"
 	Vast EventType = "vast"
 )
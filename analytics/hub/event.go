@@ -0,0 +1,236 @@
+package hub
+
+import (
+	"errors"
+	"time"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v2/analytics"
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/openrtb_ext"
+	"github.com/prebid/prebid-server/v2/privacy"
+)
+
+// Types of the events, one for each of the objects logged by analytics modules.
+const (
+	eventAuction      = "auction"
+	eventAmp          = "amp"
+	eventVideo        = "video"
+	eventCookieSync   = "cookie_sync"
+	eventSetUID       = "setuid"
+	eventNotification = "notification"
+)
+
+// Event is an analytics object in the form it's forwarded to the hub in. Errors are forwarded as their
+// messages, the request as the bid request it wraps, and the account as its ID and privacy settings only.
+// Hook execution outcomes aren't forwarded.
+type Event struct {
+	Type               string                        `json:"type"`
+	Status             int                           `json:"status,omitempty"`
+	Errors             []string                      `json:"errors,omitempty"`
+	StartTime          time.Time                     `json:"start_time,omitempty"`
+	Account            *config.Account               `json:"account,omitempty"`
+	Request            *openrtb2.BidRequest          `json:"request,omitempty"`
+	Response           *openrtb2.BidResponse         `json:"response,omitempty"`
+	SeatNonBid         []openrtb_ext.SeatNonBid      `json:"seat_non_bid,omitempty"`
+	AmpTargetingValues map[string]string             `json:"amp_targeting_values,omitempty"`
+	Origin             string                        `json:"origin,omitempty"`
+	VideoRequest       *openrtb_ext.BidRequestVideo  `json:"video_request,omitempty"`
+	VideoResponse      *openrtb_ext.BidResponseVideo `json:"video_response,omitempty"`
+	BidderStatus       []*analytics.CookieSyncBidder `json:"bidder_status,omitempty"`
+	Bidder             string                        `json:"bidder,omitempty"`
+	UID                string                        `json:"uid,omitempty"`
+	Success            bool                          `json:"success,omitempty"`
+	Notification       *analytics.EventRequest       `json:"notification,omitempty"`
+	Test               bool                          `json:"test,omitempty"`
+}
+
+func newAuctionEvent(ao *analytics.AuctionObject) *Event {
+	return &Event{
+		Type:       eventAuction,
+		Status:     ao.Status,
+		Errors:     errorMessages(ao.Errors),
+		StartTime:  ao.StartTime,
+		Account:    forwardedAccount(ao.Account),
+		Request:    bidRequest(ao.RequestWrapper),
+		Response:   ao.Response,
+		SeatNonBid: ao.SeatNonBid,
+		Test:       ao.Test,
+	}
+}
+
+func newAmpEvent(ao *analytics.AmpObject) *Event {
+	return &Event{
+		Type:               eventAmp,
+		Status:             ao.Status,
+		Errors:             errorMessages(ao.Errors),
+		StartTime:          ao.StartTime,
+		Request:            bidRequest(ao.RequestWrapper),
+		Response:           ao.AuctionResponse,
+		SeatNonBid:         ao.SeatNonBid,
+		AmpTargetingValues: ao.AmpTargetingValues,
+		Origin:             ao.Origin,
+		Test:               ao.Test,
+	}
+}
+
+func newVideoEvent(vo *analytics.VideoObject) *Event {
+	return &Event{
+		Type:          eventVideo,
+		Status:        vo.Status,
+		Errors:        errorMessages(vo.Errors),
+		StartTime:     vo.StartTime,
+		Request:       bidRequest(vo.RequestWrapper),
+		Response:      vo.Response,
+		SeatNonBid:    vo.SeatNonBid,
+		VideoRequest:  vo.VideoRequest,
+		VideoResponse: vo.VideoResponse,
+	}
+}
+
+func newCookieSyncEvent(cso *analytics.CookieSyncObject) *Event {
+	return &Event{
+		Type:         eventCookieSync,
+		Status:       cso.Status,
+		Errors:       errorMessages(cso.Errors),
+		BidderStatus: cso.BidderStatus,
+	}
+}
+
+func newSetUIDEvent(so *analytics.SetUIDObject) *Event {
+	return &Event{
+		Type:    eventSetUID,
+		Status:  so.Status,
+		Errors:  errorMessages(so.Errors),
+		Bidder:  so.Bidder,
+		UID:     so.UID,
+		Success: so.Success,
+	}
+}
+
+func newNotificationEvent(ne *analytics.NotificationEvent) *Event {
+	return &Event{
+		Type:         eventNotification,
+		Account:      forwardedAccount(ne.Account),
+		Notification: ne.Request,
+	}
+}
+
+// log logs the event with the runner, as the object it was forwarded from. The activity controls are those
+// of the forwarded account, for the objects which have one, so the hub's modules are subject to the same
+// privacy rules as on the edge. It returns false if the event's type is unknown.
+func (e *Event) log(runner analytics.Runner) bool {
+	var activityControl privacy.ActivityControl
+	if e.Account != nil {
+		activityControl = privacy.NewActivityControl(&e.Account.Privacy)
+	}
+
+	switch e.Type {
+	case eventAuction:
+		runner.LogAuctionObject(&analytics.AuctionObject{
+			Status:         e.Status,
+			Errors:         errorValues(e.Errors),
+			Response:       e.Response,
+			Account:        e.Account,
+			StartTime:      e.StartTime,
+			SeatNonBid:     e.SeatNonBid,
+			RequestWrapper: requestWrapper(e.Request),
+			Test:           e.Test,
+		}, activityControl)
+	case eventAmp:
+		runner.LogAmpObject(&analytics.AmpObject{
+			Status:             e.Status,
+			Errors:             errorValues(e.Errors),
+			AuctionResponse:    e.Response,
+			AmpTargetingValues: e.AmpTargetingValues,
+			Origin:             e.Origin,
+			StartTime:          e.StartTime,
+			SeatNonBid:         e.SeatNonBid,
+			RequestWrapper:     requestWrapper(e.Request),
+			Test:               e.Test,
+		}, activityControl)
+	case eventVideo:
+		runner.LogVideoObject(&analytics.VideoObject{
+			Status:         e.Status,
+			Errors:         errorValues(e.Errors),
+			Response:       e.Response,
+			VideoRequest:   e.VideoRequest,
+			VideoResponse:  e.VideoResponse,
+			StartTime:      e.StartTime,
+			SeatNonBid:     e.SeatNonBid,
+			RequestWrapper: requestWrapper(e.Request),
+		}, activityControl)
+	case eventCookieSync:
+		runner.LogCookieSyncObject(&analytics.CookieSyncObject{
+			Status:       e.Status,
+			Errors:       errorValues(e.Errors),
+			BidderStatus: e.BidderStatus,
+		})
+	case eventSetUID:
+		runner.LogSetUIDObject(&analytics.SetUIDObject{
+			Status:  e.Status,
+			Bidder:  e.Bidder,
+			UID:     e.UID,
+			Errors:  errorValues(e.Errors),
+			Success: e.Success,
+		})
+	case eventNotification:
+		runner.LogNotificationEventObject(&analytics.NotificationEvent{
+			Request: e.Notification,
+			Account: e.Account,
+		}, activityControl)
+	default:
+		return false
+	}
+	return true
+}
+
+// forwardedAccount returns the parts of the account the hub needs to rebuild its activity controls, so that
+// the account's keys and secrets never leave the instance.
+func forwardedAccount(account *config.Account) *config.Account {
+	if account == nil {
+		return nil
+	}
+	return &config.Account{
+		ID:      account.ID,
+		CCPA:    account.CCPA,
+		GDPR:    account.GDPR,
+		Privacy: account.Privacy,
+	}
+}
+
+func bidRequest(rw *openrtb_ext.RequestWrapper) *openrtb2.BidRequest {
+	if rw == nil {
+		return nil
+	}
+	return rw.BidRequest
+}
+
+func requestWrapper(req *openrtb2.BidRequest) *openrtb_ext.RequestWrapper {
+	if req == nil {
+		return nil
+	}
+	return &openrtb_ext.RequestWrapper{BidRequest: req}
+}
+
+func errorMessages(errs []error) []string {
+	if len(errs) == 0 {
+		return nil
+	}
+	messages := make([]string, len(errs))
+	for i, err := range errs {
+		messages[i] = err.Error()
+	}
+	return messages
+}
+
+func errorValues(messages []string) []error {
+	if len(messages) == 0 {
+		return nil
+	}
+	errs := make([]error, len(messages))
+	for i, message := range messages {
+		errs[i] = errors.New(message)
+	}
+	return errs
+}
@@ -0,0 +1,52 @@
+package hub
+
+import (
+	"testing"
+
+	"github.com/prebid/prebid-server/v2/analytics"
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/util/jsonutil"
+	"github.com/prebid/prebid-server/v2/util/ptrutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventAccountSecretsNotForwarded(t *testing.T) {
+	account := &config.Account{
+		ID:         "account1",
+		CCPA:       config.AccountCCPA{Enabled: ptrutil.ToPtr(true)},
+		DebugToken: config.AccountDebugToken{Keys: []string{"debug-token-key"}},
+		APIKeys: config.AccountAPIKeys{
+			Keys: []config.AccountAPIKey{{ID: "key1", SHA256: "api-key-hash"}},
+		},
+		EventWebhook:    config.AccountEventWebhook{Secret: "webhook-secret"},
+		PriceEncryption: config.AccountPriceEncryption{EncryptionKey: "encryption-key", IntegrityKey: "integrity-key"},
+	}
+
+	testCases := []struct {
+		description string
+		event       *Event
+	}{
+		{
+			description: "auction",
+			event:       newAuctionEvent(&analytics.AuctionObject{Account: account}),
+		},
+		{
+			description: "notification",
+			event:       newNotificationEvent(&analytics.NotificationEvent{Account: account}),
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			payload, err := jsonutil.Marshal(test.event)
+			require.NoError(t, err)
+
+			for _, secret := range []string{"debug-token-key", "api-key-hash", "webhook-secret", "encryption-key", "integrity-key"} {
+				assert.NotContains(t, string(payload), secret)
+			}
+			assert.Equal(t, "account1", test.event.Account.ID)
+			assert.Equal(t, account.CCPA, test.event.Account.CCPA)
+		})
+	}
+}
@@ -0,0 +1,120 @@
+package hub
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/docker/go-units"
+
+	"github.com/prebid/prebid-server/v2/analytics"
+	"github.com/prebid/prebid-server/v2/analytics/pubstack/eventchannel"
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/logger"
+	"github.com/prebid/prebid-server/v2/util/jsonutil"
+)
+
+// ModuleName is the name the forwarder is registered under, and the analytics component the activity
+// controls of the edge instances apply to.
+const ModuleName = "hub"
+
+// Forwarder is the analytics module of the edge instances. It sends every object it's given to the hub
+// as an Event, in gzipped batches of newline delimited JSON.
+type Forwarder struct {
+	channel   *eventchannel.EventChannel
+	sigTermCh chan os.Signal
+}
+
+// NewForwarder returns a forwarder which sends the batches to the hub with the client.
+func NewForwarder(client *http.Client, cfg config.AnalyticsHubForward, clock clock.Clock) (analytics.Module, error) {
+	maxByteSize, err := units.FromHumanSize(cfg.Buffers.BufferSize)
+	if err != nil {
+		return nil, fmt.Errorf("analytics.hub.forward.buffers.size: %v", err)
+	}
+	maxTime, err := time.ParseDuration(cfg.Buffers.Timeout)
+	if err != nil {
+		return nil, fmt.Errorf("analytics.hub.forward.buffers.timeout: %v", err)
+	}
+
+	f := &Forwarder{
+		channel:   eventchannel.NewEventChannel(newSender(client, cfg.URL, cfg.Token), clock, maxByteSize, int64(cfg.Buffers.EventCount), maxTime),
+		sigTermCh: make(chan os.Signal, 1),
+	}
+	signal.Notify(f.sigTermCh, os.Interrupt, syscall.SIGTERM)
+	go f.closeOnSigTerm()
+
+	logger.Infof("[hub] Forwarding analytics to %s", cfg.URL)
+	return f, nil
+}
+
+func (f *Forwarder) closeOnSigTerm() {
+	<-f.sigTermCh
+	logger.Info("[hub] Received Close, flushing the events not yet forwarded")
+	f.channel.Close()
+}
+
+func (f *Forwarder) LogAuctionObject(ao *analytics.AuctionObject) {
+	f.forward(newAuctionEvent(ao))
+}
+
+func (f *Forwarder) LogVideoObject(vo *analytics.VideoObject) {
+	f.forward(newVideoEvent(vo))
+}
+
+func (f *Forwarder) LogCookieSyncObject(cso *analytics.CookieSyncObject) {
+	f.forward(newCookieSyncEvent(cso))
+}
+
+func (f *Forwarder) LogSetUIDObject(so *analytics.SetUIDObject) {
+	f.forward(newSetUIDEvent(so))
+}
+
+func (f *Forwarder) LogAmpObject(ao *analytics.AmpObject) {
+	f.forward(newAmpEvent(ao))
+}
+
+func (f *Forwarder) LogNotificationEventObject(ne *analytics.NotificationEvent) {
+	f.forward(newNotificationEvent(ne))
+}
+
+func (f *Forwarder) forward(event *Event) {
+	payload, err := jsonutil.Marshal(event)
+	if err != nil {
+		logger.Warningf("[hub] Cannot serialize %s event: %v", event.Type, err)
+		return
+	}
+	f.channel.Push(append(payload, '\n'))
+}
+
+func newSender(client *http.Client, url, token string) eventchannel.Sender {
+	return func(payload []byte) error {
+		req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(payload))
+		if err != nil {
+			logger.Errorf("[hub] Creating request failed %v", err)
+			return err
+		}
+		req.Header.Set("Content-Type", "application/x-ndjson")
+		req.Header.Set("Content-Encoding", "gzip")
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+
+		resp, err := client.Do(req)
+		if err != nil {
+			logger.Errorf("[hub] Sending request failed %v", err)
+			return err
+		}
+		resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			logger.Errorf("[hub] Wrong code received %d instead of %d", resp.StatusCode, http.StatusOK)
+			return fmt.Errorf("wrong code received %d instead of %d", resp.StatusCode, http.StatusOK)
+		}
+		return nil
+	}
+}
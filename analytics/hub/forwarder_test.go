@@ -0,0 +1,97 @@
+package hub
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v2/analytics"
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/openrtb_ext"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// lockedRunner guards the mock runner, since the hub logs the batches on the test server's goroutines.
+type lockedRunner struct {
+	mockRunner
+	mux sync.Mutex
+}
+
+func (r *lockedRunner) serialize(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		r.mux.Lock()
+		defer r.mux.Unlock()
+		handler.ServeHTTP(w, req)
+	})
+}
+
+func (r *lockedRunner) count() int {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	return len(r.auctions) + len(r.amps) + len(r.videos) + len(r.cookieSyncs) + len(r.setUIDs) + len(r.notifications)
+}
+
+func TestNewForwarderInvalidBuffers(t *testing.T) {
+	testCases := []struct {
+		name    string
+		buffers config.AnalyticsHubBuffer
+	}{
+		{
+			name:    "size",
+			buffers: config.AnalyticsHubBuffer{BufferSize: "big", EventCount: 10, Timeout: "1s"},
+		},
+		{
+			name:    "timeout",
+			buffers: config.AnalyticsHubBuffer{BufferSize: "1MB", EventCount: 10, Timeout: "soon"},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := NewForwarder(&http.Client{}, config.AnalyticsHubForward{Enabled: true, URL: "http://hub", Buffers: test.buffers}, clock.NewMock())
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestForwardToIngest(t *testing.T) {
+	runner := &lockedRunner{}
+	ingest := NewIngestHandler(config.AnalyticsHubIngest{Enabled: true, Tokens: []string{"token"}, MaxBatchSize: 1 << 20}, runner)
+	server := httptest.NewServer(runner.serialize(ingest))
+	defer server.Close()
+
+	cfg := config.AnalyticsHubForward{
+		Enabled: true,
+		URL:     server.URL,
+		Token:   "token",
+		Buffers: config.AnalyticsHubBuffer{BufferSize: "1MB", EventCount: 6, Timeout: "1h"},
+	}
+	forwarder, err := NewForwarder(server.Client(), cfg, clock.NewMock())
+	require.NoError(t, err)
+
+	request := &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{ID: "request1"}}
+	forwarder.LogAuctionObject(&analytics.AuctionObject{Status: http.StatusOK, RequestWrapper: request})
+	forwarder.LogAmpObject(&analytics.AmpObject{Status: http.StatusOK, RequestWrapper: request, Origin: "https://publisher.com"})
+	forwarder.LogVideoObject(&analytics.VideoObject{Status: http.StatusOK, RequestWrapper: request})
+	forwarder.LogCookieSyncObject(&analytics.CookieSyncObject{Status: http.StatusOK, BidderStatus: []*analytics.CookieSyncBidder{{BidderCode: "appnexus"}}})
+	forwarder.LogSetUIDObject(&analytics.SetUIDObject{Status: http.StatusOK, Bidder: "appnexus"})
+	forwarder.LogNotificationEventObject(&analytics.NotificationEvent{Request: &analytics.EventRequest{Type: analytics.Imp}})
+
+	// the sixth event fills the buffer, which is sent right away
+	assert.Eventually(t, func() bool { return runner.count() == 6 }, time.Second, 10*time.Millisecond)
+
+	runner.mux.Lock()
+	defer runner.mux.Unlock()
+	assert.Equal(t, "request1", runner.auctions[0].RequestWrapper.ID)
+	assert.Equal(t, "request1", runner.amps[0].RequestWrapper.ID)
+	assert.Equal(t, "https://publisher.com", runner.amps[0].Origin)
+	assert.Equal(t, "request1", runner.videos[0].RequestWrapper.ID)
+	assert.Equal(t, "appnexus", runner.cookieSyncs[0].BidderStatus[0].BidderCode)
+	assert.Equal(t, "appnexus", runner.setUIDs[0].Bidder)
+	assert.Equal(t, analytics.Imp, runner.notifications[0].Request.Type)
+}
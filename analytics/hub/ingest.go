@@ -0,0 +1,106 @@
+package hub
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/prebid/prebid-server/v2/analytics"
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/logger"
+	"github.com/prebid/prebid-server/v2/util/httputil"
+	"github.com/prebid/prebid-server/v2/util/jsonutil"
+)
+
+type ingestResponse struct {
+	Logged  int `json:"logged"`
+	Skipped int `json:"skipped"`
+}
+
+type ingester struct {
+	runner       analytics.Runner
+	tokens       [][]byte
+	maxBatchSize int64
+}
+
+// NewIngestHandler returns the handler of the hub which logs the batches of events forwarded by the edge
+// instances with the runner, or nil if ingest is disabled. Requests must have one of the configured tokens
+// as their bearer token.
+//
+// A batch is the POST body, gzipped if its Content-Encoding says so, of newline delimited events. Events
+// which can't be decoded are skipped, so that one bad event doesn't cost the rest of the batch.
+func NewIngestHandler(cfg config.AnalyticsHubIngest, runner analytics.Runner) http.Handler {
+	if !cfg.Enabled {
+		return nil
+	}
+	tokens := make([][]byte, len(cfg.Tokens))
+	for i, token := range cfg.Tokens {
+		tokens[i] = []byte(token)
+	}
+	return &ingester{runner: runner, tokens: tokens, maxBatchSize: cfg.MaxBatchSize}
+}
+
+func (i *ingester) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if !httputil.HasBearerToken(req, i.tokens) {
+		w.Header().Set("WWW-Authenticate", "Bearer")
+		http.Error(w, "A valid bearer token is required", http.StatusUnauthorized)
+		return
+	}
+	if req.Method != http.MethodPost {
+		w.Header().Set("Allow", "POST")
+		http.Error(w, "Batches of events are sent with POST", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var body io.Reader = req.Body
+	if strings.EqualFold(req.Header.Get("Content-Encoding"), "gzip") {
+		gzipReader, err := gzip.NewReader(req.Body)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Invalid batch: %v", err), http.StatusBadRequest)
+			return
+		}
+		defer gzipReader.Close()
+		body = gzipReader
+	}
+	// one byte more than the limit tells a batch at the limit from one over it
+	batch, err := io.ReadAll(io.LimitReader(body, i.maxBatchSize+1))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid batch: %v", err), http.StatusBadRequest)
+		return
+	}
+	if int64(len(batch)) > i.maxBatchSize {
+		http.Error(w, fmt.Sprintf("The batch is larger than %d bytes", i.maxBatchSize), http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	response := i.logBatch(batch)
+	if response.Skipped > 0 {
+		logger.Warningf("[hub] Skipped %d of the %d events of a batch", response.Skipped, response.Logged+response.Skipped)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func (i *ingester) logBatch(batch []byte) ingestResponse {
+	var response ingestResponse
+	scanner := bufio.NewScanner(bytes.NewReader(batch))
+	scanner.Buffer(nil, len(batch)+1)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		var event Event
+		if err := jsonutil.UnmarshalValid(line, &event); err != nil || !event.log(i.runner) {
+			response.Skipped++
+			continue
+		}
+		response.Logged++
+	}
+	return response
+}
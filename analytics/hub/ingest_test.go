@@ -0,0 +1,191 @@
+package hub
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v2/analytics"
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/privacy"
+	"github.com/prebid/prebid-server/v2/util/jsonutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockRunner struct {
+	auctions      []*analytics.AuctionObject
+	amps          []*analytics.AmpObject
+	videos        []*analytics.VideoObject
+	cookieSyncs   []*analytics.CookieSyncObject
+	setUIDs       []*analytics.SetUIDObject
+	notifications []*analytics.NotificationEvent
+}
+
+func (r *mockRunner) LogAuctionObject(ao *analytics.AuctionObject, _ privacy.ActivityControl) {
+	r.auctions = append(r.auctions, ao)
+}
+
+func (r *mockRunner) LogVideoObject(vo *analytics.VideoObject, _ privacy.ActivityControl) {
+	r.videos = append(r.videos, vo)
+}
+
+func (r *mockRunner) LogCookieSyncObject(cso *analytics.CookieSyncObject) {
+	r.cookieSyncs = append(r.cookieSyncs, cso)
+}
+
+func (r *mockRunner) LogSetUIDObject(so *analytics.SetUIDObject) {
+	r.setUIDs = append(r.setUIDs, so)
+}
+
+func (r *mockRunner) LogAmpObject(ao *analytics.AmpObject, _ privacy.ActivityControl) {
+	r.amps = append(r.amps, ao)
+}
+
+func (r *mockRunner) LogNotificationEventObject(ne *analytics.NotificationEvent, _ privacy.ActivityControl) {
+	r.notifications = append(r.notifications, ne)
+}
+
+func newBatch(t *testing.T, events ...*Event) []byte {
+	var batch bytes.Buffer
+	for _, event := range events {
+		line, err := jsonutil.Marshal(event)
+		require.NoError(t, err)
+		batch.Write(line)
+		batch.WriteByte('\n')
+	}
+	return batch.Bytes()
+}
+
+func gzipped(t *testing.T, data []byte) []byte {
+	var compressed bytes.Buffer
+	writer := gzip.NewWriter(&compressed)
+	_, err := writer.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+	return compressed.Bytes()
+}
+
+func TestNewIngestHandlerDisabled(t *testing.T) {
+	assert.Nil(t, NewIngestHandler(config.AnalyticsHubIngest{Enabled: false}, &mockRunner{}))
+}
+
+func TestIngest(t *testing.T) {
+	startTime := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	auction := newAuctionEvent(&analytics.AuctionObject{
+		Status:         http.StatusOK,
+		Errors:         []error{errors.New("bidder timed out")},
+		Account:        &config.Account{ID: "account1"},
+		StartTime:      startTime,
+		RequestWrapper: requestWrapper(&openrtb2.BidRequest{ID: "request1"}),
+		Response:       &openrtb2.BidResponse{ID: "request1"},
+		Test:           true,
+	})
+	setUID := newSetUIDEvent(&analytics.SetUIDObject{Status: http.StatusOK, Bidder: "appnexus", UID: "uid1", Success: true})
+	notification := newNotificationEvent(&analytics.NotificationEvent{
+		Request: &analytics.EventRequest{Type: analytics.Win, BidID: "bid1"},
+		Account: &config.Account{ID: "account1"},
+	})
+	batch := newBatch(t, auction, setUID, notification)
+	batch = append(batch, []byte("{malformed\n{\"type\":\"unknown\"}\n\n")...)
+
+	runner := &mockRunner{}
+	handler := NewIngestHandler(config.AnalyticsHubIngest{Enabled: true, Tokens: []string{"token"}, MaxBatchSize: 1 << 20}, runner)
+
+	req := httptest.NewRequest(http.MethodPost, "/analytics/ingest", bytes.NewReader(gzipped(t, batch)))
+	req.Header.Set("Content-Encoding", "gzip")
+	req.Header.Set("Authorization", "Bearer token")
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, req)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.JSONEq(t, `{"logged":3,"skipped":2}`, recorder.Body.String())
+
+	require.Len(t, runner.auctions, 1)
+	assert.Equal(t, http.StatusOK, runner.auctions[0].Status)
+	assert.Equal(t, []error{errors.New("bidder timed out")}, runner.auctions[0].Errors)
+	assert.Equal(t, "account1", runner.auctions[0].Account.ID)
+	assert.True(t, startTime.Equal(runner.auctions[0].StartTime))
+	assert.Equal(t, "request1", runner.auctions[0].RequestWrapper.ID)
+	assert.Equal(t, "request1", runner.auctions[0].Response.ID)
+	assert.True(t, runner.auctions[0].Test)
+
+	require.Len(t, runner.setUIDs, 1)
+	assert.Equal(t, &analytics.SetUIDObject{Status: http.StatusOK, Bidder: "appnexus", UID: "uid1", Success: true}, runner.setUIDs[0])
+
+	require.Len(t, runner.notifications, 1)
+	assert.Equal(t, &analytics.EventRequest{Type: analytics.Win, BidID: "bid1"}, runner.notifications[0].Request)
+	assert.Equal(t, "account1", runner.notifications[0].Account.ID)
+}
+
+func TestIngestRejected(t *testing.T) {
+	testCases := []struct {
+		name           string
+		method         string
+		authorization  string
+		body           []byte
+		gzip           bool
+		expectedStatus int
+	}{
+		{
+			name:           "no-token",
+			method:         http.MethodPost,
+			body:           []byte(`{"type":"setuid"}`),
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "wrong-token",
+			method:         http.MethodPost,
+			authorization:  "Bearer wrong",
+			body:           []byte(`{"type":"setuid"}`),
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "get",
+			method:         http.MethodGet,
+			authorization:  "Bearer token",
+			expectedStatus: http.StatusMethodNotAllowed,
+		},
+		{
+			name:           "not-gzipped",
+			method:         http.MethodPost,
+			authorization:  "Bearer token",
+			body:           []byte(`{"type":"setuid"}`),
+			gzip:           true,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "too-large",
+			method:         http.MethodPost,
+			authorization:  "Bearer token",
+			body:           []byte(`{"type":"setuid","uid":"` + strings.Repeat("a", 100) + `"}`),
+			expectedStatus: http.StatusRequestEntityTooLarge,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			runner := &mockRunner{}
+			handler := NewIngestHandler(config.AnalyticsHubIngest{Enabled: true, Tokens: []string{"token"}, MaxBatchSize: 64}, runner)
+
+			req := httptest.NewRequest(test.method, "/analytics/ingest", bytes.NewReader(test.body))
+			if test.gzip {
+				req.Header.Set("Content-Encoding", "gzip")
+			}
+			if test.authorization != "" {
+				req.Header.Set("Authorization", test.authorization)
+			}
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, req)
+
+			assert.Equal(t, test.expectedStatus, recorder.Code)
+			assert.Empty(t, runner.setUIDs)
+		})
+	}
+}
@@ -7,7 +7,8 @@ import (
 	"time"
 
 	"github.com/benbjohnson/clock"
-	"github.com/golang/glog"
+
+	"github.com/prebid/prebid-server/v2/logger"
 )
 
 type Metrics struct {
@@ -66,7 +67,7 @@ func (c *EventChannel) buffer(event []byte) {
 
 	_, err := c.gz.Write(event)
 	if err != nil {
-		glog.Warning("[pubstack] fail to compress, skip the event")
+		logger.Warning("[pubstack] fail to compress, skip the event")
 		return
 	}
 
@@ -104,7 +105,7 @@ func (c *EventChannel) flush() {
 	// finish writing gzip header
 	err := c.gz.Close()
 	if err != nil {
-		glog.Warning("[pubstack] fail to close gzipped buffer")
+		logger.Warning("[pubstack] fail to close gzipped buffer")
 		return
 	}
 
@@ -112,7 +113,7 @@ func (c *EventChannel) flush() {
 	payload := make([]byte, c.buff.Len())
 	_, err = c.buff.Read(payload)
 	if err != nil {
-		glog.Warning("[pubstack] fail to copy the buffer")
+		logger.Warning("[pubstack] fail to copy the buffer")
 		return
 	}
 
@@ -7,7 +7,7 @@ import (
 	"net/url"
 	"path"
 
-	"github.com/golang/glog"
+	"github.com/prebid/prebid-server/v2/logger"
 )
 
 type Sender = func(payload []byte) error
@@ -16,7 +16,7 @@ func NewHttpSender(client *http.Client, endpoint string) Sender {
 	return func(payload []byte) error {
 		req, err := http.NewRequest(http.MethodPost, endpoint, bytes.NewReader(payload))
 		if err != nil {
-			glog.Error(err)
+			logger.Error(err)
 			return err
 		}
 
@@ -30,7 +30,7 @@ func NewHttpSender(client *http.Client, endpoint string) Sender {
 		resp.Body.Close()
 
 		if resp.StatusCode != http.StatusOK {
-			glog.Errorf("[pubstack] Wrong code received %d instead of %d", resp.StatusCode, http.StatusOK)
+			logger.Errorf("[pubstack] Wrong code received %d instead of %d", resp.StatusCode, http.StatusOK)
 			return fmt.Errorf("wrong code received %d instead of %d", resp.StatusCode, http.StatusOK)
 		}
 		return nil
@@ -40,7 +40,7 @@ func NewHttpSender(client *http.Client, endpoint string) Sender {
 func BuildEndpointSender(client *http.Client, baseUrl string, module string) Sender {
 	endpoint, err := url.Parse(baseUrl)
 	if err != nil {
-		glog.Error(err)
+		logger.Error(err)
 	}
 	endpoint.Path = path.Join(endpoint.Path, "intake", module)
 	return NewHttpSender(client, endpoint.String())
@@ -10,11 +10,11 @@ import (
 	"time"
 
 	"github.com/benbjohnson/clock"
-	"github.com/golang/glog"
 
 	"github.com/prebid/prebid-server/v2/analytics"
 	"github.com/prebid/prebid-server/v2/analytics/pubstack/eventchannel"
 	"github.com/prebid/prebid-server/v2/analytics/pubstack/helpers"
+	"github.com/prebid/prebid-server/v2/logger"
 )
 
 type Configuration struct {
@@ -64,7 +64,7 @@ func NewModule(client *http.Client, scope, endpoint, configRefreshDelay string,
 }
 
 func NewModuleWithConfigTask(client *http.Client, scope, endpoint string, maxEventCount int, maxByteSize, maxTime string, configTask ConfigUpdateTask, clock clock.Clock) (analytics.Module, error) {
-	glog.Infof("[pubstack] Initializing module scope=%s endpoint=%s\n", scope, endpoint)
+	logger.Infof("[pubstack] Initializing module scope=%s endpoint=%s\n", scope, endpoint)
 
 	// parse args
 	bufferCfg, err := newBufferConfig(maxEventCount, maxByteSize, maxTime)
@@ -103,7 +103,7 @@ func NewModuleWithConfigTask(client *http.Client, scope, endpoint string, maxEve
 	configChannel := configTask.Start(pb.stopCh)
 	go pb.start(configChannel)
 
-	glog.Info("[pubstack] Pubstack analytics configured and ready")
+	logger.Info("[pubstack] Pubstack analytics configured and ready")
 	return &pb, nil
 }
 
@@ -118,7 +118,7 @@ func (p *PubstackModule) LogAuctionObject(ao *analytics.AuctionObject) {
 	// serialize event
 	payload, err := helpers.JsonifyAuctionObject(ao, p.scope)
 	if err != nil {
-		glog.Warning("[pubstack] Cannot serialize auction")
+		logger.Warning("[pubstack] Cannot serialize auction")
 		return
 	}
 
@@ -139,7 +139,7 @@ func (p *PubstackModule) LogVideoObject(vo *analytics.VideoObject) {
 	// serialize event
 	payload, err := helpers.JsonifyVideoObject(vo, p.scope)
 	if err != nil {
-		glog.Warning("[pubstack] Cannot serialize video")
+		logger.Warning("[pubstack] Cannot serialize video")
 		return
 	}
 
@@ -157,7 +157,7 @@ func (p *PubstackModule) LogSetUIDObject(so *analytics.SetUIDObject) {
 	// serialize event
 	payload, err := helpers.JsonifySetUIDObject(so, p.scope)
 	if err != nil {
-		glog.Warning("[pubstack] Cannot serialize video")
+		logger.Warning("[pubstack] Cannot serialize video")
 		return
 	}
 
@@ -175,7 +175,7 @@ func (p *PubstackModule) LogCookieSyncObject(cso *analytics.CookieSyncObject) {
 	// serialize event
 	payload, err := helpers.JsonifyCookieSync(cso, p.scope)
 	if err != nil {
-		glog.Warning("[pubstack] Cannot serialize video")
+		logger.Warning("[pubstack] Cannot serialize video")
 		return
 	}
 
@@ -193,7 +193,7 @@ func (p *PubstackModule) LogAmpObject(ao *analytics.AmpObject) {
 	// serialize event
 	payload, err := helpers.JsonifyAmpObject(ao, p.scope)
 	if err != nil {
-		glog.Warning("[pubstack] Cannot serialize video")
+		logger.Warning("[pubstack] Cannot serialize video")
 		return
 	}
 
@@ -210,7 +210,7 @@ func (p *PubstackModule) start(c <-chan *Configuration) {
 			return
 		case config := <-c:
 			p.updateConfig(config)
-			glog.Infof("[pubstack] Updating config: %v", p.cfg)
+			logger.Infof("[pubstack] Updating config: %v", p.cfg)
 		}
 	}
 }
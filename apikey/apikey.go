@@ -0,0 +1,96 @@
+// Package apikey authenticates server to server requests by the API keys of their accounts, and limits the rate
+// at which each key may be used.
+package apikey
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/errortypes"
+	"github.com/prebid/prebid-server/v2/metrics"
+	"github.com/prebid/prebid-server/v2/ratelimit"
+)
+
+// Header is the request header callers present their API key in.
+const Header = "X-Prebid-Api-Key"
+
+// Authenticator checks the API keys of requests, and keeps track of how often each key is used.
+//
+// When the rate limiting counters are shared by the fleet, the rate limits are enforced with them over
+// one second windows, so that they hold however many instances are running. Otherwise each instance
+// keeps a token bucket for each key.
+type Authenticator struct {
+	mutex   sync.Mutex
+	buckets map[string]*bucket
+	now     func() time.Time
+	limiter *ratelimit.Limiter
+}
+
+// bucket holds the requests a key may still make. It's refilled at the key's rate limit, up to one second's worth.
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+func NewAuthenticator(limiter *ratelimit.Limiter) *Authenticator {
+	return &Authenticator{
+		buckets: make(map[string]*bucket),
+		now:     time.Now,
+		limiter: limiter,
+	}
+}
+
+// Authenticate checks the key presented with a request for the account. It returns the outcome for metrics,
+// and an error if the request must be rejected.
+func (a *Authenticator) Authenticate(ctx context.Context, accountID string, keys config.AccountAPIKeys, presented string) (metrics.APIKeyStatus, error) {
+	if presented == "" {
+		if keys.Required {
+			return metrics.APIKeyMissing, &errortypes.Unauthorized{Message: fmt.Sprintf("account %s requires an API key in the %s header", accountID, Header)}
+		}
+		return metrics.APIKeyMissing, nil
+	}
+
+	key, ok := keys.Find(presented)
+	if !ok {
+		return metrics.APIKeyInvalid, &errortypes.Unauthorized{Message: fmt.Sprintf("the API key is not valid for account %s", accountID)}
+	}
+	if key.Revoked {
+		return metrics.APIKeyRevoked, &errortypes.Unauthorized{Message: fmt.Sprintf("API key %s of account %s has been revoked", key.ID, accountID)}
+	}
+	if key.RateLimit > 0 && !a.allow(ctx, accountID+"/"+key.ID, key.RateLimit) {
+		return metrics.APIKeyRateLimited, &errortypes.RateLimited{Message: fmt.Sprintf("API key %s of account %s is over its rate limit of %d requests per second", key.ID, accountID, key.RateLimit)}
+	}
+	return metrics.APIKeyAccepted, nil
+}
+
+// allow takes a request from the key's bucket, if it has one left. A nil Authenticator doesn't limit keys.
+func (a *Authenticator) allow(ctx context.Context, key string, rate int) bool {
+	if a == nil {
+		return true
+	}
+	if a.limiter.Distributed() {
+		return a.limiter.Allow(ctx, metrics.RateLimitAPIKey, key, int64(rate), time.Second)
+	}
+	now := a.now()
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	b, ok := a.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(rate), last: now}
+		a.buckets[key] = b
+	} else {
+		b.tokens = math.Min(float64(rate), b.tokens+now.Sub(b.last).Seconds()*float64(rate))
+		b.last = now
+	}
+
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
@@ -0,0 +1,112 @@
+package apikey
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/errortypes"
+	"github.com/prebid/prebid-server/v2/metrics"
+	"github.com/stretchr/testify/assert"
+)
+
+// SHA-256 hashes of "secret" and "old-secret"
+const (
+	secretHash    = "2bb80d537b1da3e38bd30361aa855686bde0eacd7162fef6a25fe97bf527a25b"
+	oldSecretHash = "5d865deae06fbd34fe9ce848f3e5fc4368f2f612b18aef47f29f2164563a0140"
+)
+
+func TestAuthenticate(t *testing.T) {
+	keys := config.AccountAPIKeys{Keys: []config.AccountAPIKey{
+		{ID: "partner", SHA256: secretHash},
+		{ID: "old", SHA256: oldSecretHash, Revoked: true},
+	}}
+	required := keys
+	required.Required = true
+
+	testCases := []struct {
+		description    string
+		keys           config.AccountAPIKeys
+		presented      string
+		expectedStatus metrics.APIKeyStatus
+		expectedErr    error
+	}{
+		{
+			description:    "valid key",
+			keys:           keys,
+			presented:      "secret",
+			expectedStatus: metrics.APIKeyAccepted,
+		},
+		{
+			description:    "no key and none required",
+			keys:           keys,
+			expectedStatus: metrics.APIKeyMissing,
+		},
+		{
+			description:    "no key but one required",
+			keys:           required,
+			expectedStatus: metrics.APIKeyMissing,
+			expectedErr:    &errortypes.Unauthorized{Message: "account 1001 requires an API key in the X-Prebid-Api-Key header"},
+		},
+		{
+			description:    "unknown key",
+			keys:           keys,
+			presented:      "guess",
+			expectedStatus: metrics.APIKeyInvalid,
+			expectedErr:    &errortypes.Unauthorized{Message: "the API key is not valid for account 1001"},
+		},
+		{
+			description:    "revoked key",
+			keys:           keys,
+			presented:      "old-secret",
+			expectedStatus: metrics.APIKeyRevoked,
+			expectedErr:    &errortypes.Unauthorized{Message: "API key old of account 1001 has been revoked"},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			status, err := NewAuthenticator(nil).Authenticate(context.Background(), "1001", test.keys, test.presented)
+			assert.Equal(t, test.expectedStatus, status)
+			assert.Equal(t, test.expectedErr, err)
+		})
+	}
+}
+
+func TestAuthenticateRateLimit(t *testing.T) {
+	keys := config.AccountAPIKeys{Keys: []config.AccountAPIKey{{ID: "partner", SHA256: secretHash, RateLimit: 2}}}
+	now := time.Unix(1700000000, 0)
+	authenticator := NewAuthenticator(nil)
+	authenticator.now = func() time.Time { return now }
+
+	for i := 0; i < 2; i++ {
+		status, err := authenticator.Authenticate(context.Background(), "1001", keys, "secret")
+		assert.Equal(t, metrics.APIKeyAccepted, status)
+		assert.NoError(t, err)
+	}
+
+	status, err := authenticator.Authenticate(context.Background(), "1001", keys, "secret")
+	assert.Equal(t, metrics.APIKeyRateLimited, status)
+	assert.Equal(t, &errortypes.RateLimited{Message: "API key partner of account 1001 is over its rate limit of 2 requests per second"}, err)
+
+	// the same key of another account has its own limit
+	status, _ = authenticator.Authenticate(context.Background(), "1002", keys, "secret")
+	assert.Equal(t, metrics.APIKeyAccepted, status)
+
+	// half a second refills one request
+	now = now.Add(500 * time.Millisecond)
+	status, _ = authenticator.Authenticate(context.Background(), "1001", keys, "secret")
+	assert.Equal(t, metrics.APIKeyAccepted, status)
+	status, _ = authenticator.Authenticate(context.Background(), "1001", keys, "secret")
+	assert.Equal(t, metrics.APIKeyRateLimited, status)
+
+	// a long pause refills no more than a second's worth
+	now = now.Add(time.Minute)
+	for i := 0; i < 2; i++ {
+		status, _ = authenticator.Authenticate(context.Background(), "1001", keys, "secret")
+		assert.Equal(t, metrics.APIKeyAccepted, status)
+	}
+	status, _ = authenticator.Authenticate(context.Background(), "1001", keys, "secret")
+	assert.Equal(t, metrics.APIKeyRateLimited, status)
+}
@@ -0,0 +1,54 @@
+package auctionquality
+
+import (
+	"net/http"
+
+	accountService "github.com/prebid/prebid-server/v2/account"
+	"github.com/prebid/prebid-server/v2/apikey"
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/metrics"
+	"github.com/prebid/prebid-server/v2/stored_requests"
+	"github.com/prebid/prebid-server/v2/util/httputil"
+)
+
+// Handler returns a handler which responds to GET requests with the Report of the account in the query
+// string, or nil if the auction quality statistics are disabled.
+//
+// Requests must have one of the account's API keys, which isn't revoked, in the API key header. Accounts
+// without API keys can't fetch their reports.
+func (t *Tracker) Handler(cfg *config.Configuration, accounts stored_requests.AccountFetcher, me metrics.MetricsEngine) http.Handler {
+	if t == nil {
+		return nil
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodGet {
+			w.Header().Set("Allow", http.MethodGet)
+			http.Error(w, "The auction quality report must be fetched with GET", http.StatusMethodNotAllowed)
+			return
+		}
+		accountID := req.URL.Query().Get("account")
+		if accountID == "" {
+			http.Error(w, "The account is required", http.StatusBadRequest)
+			return
+		}
+
+		// the account is looked up before the key is checked, but a missing, disabled or unreadable account
+		// gets the same response as a wrong key, so that the response doesn't tell which accounts exist
+		account, _ := accountService.GetAccount(req.Context(), cfg, accounts, accountID, me)
+		if account == nil || !authorized(account.APIKeys, req.Header.Get(apikey.Header)) {
+			http.Error(w, "A valid API key of the account is required in the "+apikey.Header+" header", http.StatusUnauthorized)
+			return
+		}
+
+		w.Header().Set("Cache-Control", "no-store")
+		httputil.WriteJSON(w, t.Report(account.ID))
+	})
+}
+
+func authorized(keys config.AccountAPIKeys, presented string) bool {
+	if presented == "" {
+		return false
+	}
+	key, ok := keys.Find(presented)
+	return ok && !key.Revoked
+}
@@ -0,0 +1,140 @@
+package auctionquality
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prebid/prebid-server/v2/apikey"
+	"github.com/prebid/prebid-server/v2/config"
+	metricsConf "github.com/prebid/prebid-server/v2/metrics/config"
+	"github.com/prebid/prebid-server/v2/stored_requests"
+	"github.com/prebid/prebid-server/v2/util/timeutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockAccountFetcher map[string]json.RawMessage
+
+func (af mockAccountFetcher) FetchAccount(_ context.Context, _ json.RawMessage, accountID string) (json.RawMessage, []error) {
+	if account, ok := af[accountID]; ok {
+		return account, nil
+	}
+	return nil, []error{stored_requests.NotFoundError{ID: accountID, DataType: "Account"}}
+}
+
+func hashOf(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+func TestHandler(t *testing.T) {
+	clock := timeutil.NewFakeTime(time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC))
+	tracker := newTestTracker(clock)
+	tracker.Record(Auction{Account: "acct", Bidders: []Bidder{{Name: "appnexus", Called: true, TimedOut: true}}})
+
+	accounts := mockAccountFetcher{
+		"acct":     json.RawMessage(`{"api_keys":{"keys":[{"id":"current","sha256":"` + hashOf("key") + `"},{"id":"old","sha256":"` + hashOf("old-key") + `","revoked":true}]}}`),
+		"keyless":  json.RawMessage(`{}`),
+		"disabled": json.RawMessage(`{"disabled":true,"api_keys":{"keys":[{"id":"current","sha256":"` + hashOf("key") + `"}]}}`),
+	}
+	handler := tracker.Handler(&config.Configuration{}, accounts, &metricsConf.NilMetricsEngine{})
+
+	testCases := []struct {
+		name           string
+		method         string
+		target         string
+		key            string
+		expectedStatus int
+	}{
+		{
+			name:           "valid-key",
+			method:         http.MethodGet,
+			target:         "/auction_quality?account=acct",
+			key:            "key",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "no-key",
+			method:         http.MethodGet,
+			target:         "/auction_quality?account=acct",
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "wrong-key",
+			method:         http.MethodGet,
+			target:         "/auction_quality?account=acct",
+			key:            "other-key",
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "revoked-key",
+			method:         http.MethodGet,
+			target:         "/auction_quality?account=acct",
+			key:            "old-key",
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "account-without-keys",
+			method:         http.MethodGet,
+			target:         "/auction_quality?account=keyless",
+			key:            "key",
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "disabled-account",
+			method:         http.MethodGet,
+			target:         "/auction_quality?account=disabled",
+			key:            "key",
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "unknown-account",
+			method:         http.MethodGet,
+			target:         "/auction_quality?account=unknown",
+			key:            "key",
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "no-account",
+			method:         http.MethodGet,
+			target:         "/auction_quality",
+			key:            "key",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "post",
+			method:         http.MethodPost,
+			target:         "/auction_quality?account=acct",
+			key:            "key",
+			expectedStatus: http.StatusMethodNotAllowed,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			req := httptest.NewRequest(test.method, test.target, nil)
+			if test.key != "" {
+				req.Header.Set(apikey.Header, test.key)
+			}
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, req)
+
+			require.Equal(t, test.expectedStatus, recorder.Code, recorder.Body.String())
+			if test.expectedStatus != http.StatusOK {
+				return
+			}
+			assert.Equal(t, "application/json", recorder.Header().Get("Content-Type"))
+			var report Report
+			require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &report))
+			assert.Equal(t, "acct", report.Account)
+			assert.Equal(t, int64(1), report.Auctions)
+			assert.Equal(t, []BidderRow{{Bidder: "appnexus", Requests: 1, Timeouts: 1, TimeoutRate: 1}}, report.Bidders)
+		})
+	}
+}
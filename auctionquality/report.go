@@ -0,0 +1,112 @@
+package auctionquality
+
+import (
+	"sort"
+	"time"
+)
+
+// Report has the statistics of an account's auctions over the window.
+type Report struct {
+	Account    string          `json:"account"`
+	From       time.Time       `json:"from"`
+	To         time.Time       `json:"to"`
+	Auctions   int64           `json:"auctions"`
+	Bidders    []BidderRow     `json:"bidders"`
+	Validation []ValidationRow `json:"validation"`
+}
+
+// BidderRow has the statistics of a bidder.
+type BidderRow struct {
+	Bidder   string `json:"bidder"`
+	Requests int64  `json:"requests"`
+	Timeouts int64  `json:"timeouts"`
+	// TimeoutRate is the share of the requests which timed out.
+	TimeoutRate     float64 `json:"timeout_rate"`
+	Failures        int64   `json:"failures"`
+	PrivacyBlocks   int64   `json:"privacy_blocks"`
+	FloorRejections int64   `json:"floor_rejections"`
+	RejectedBids    int64   `json:"rejected_bids"`
+}
+
+// ValidationRow counts the validation errors and warnings with a code.
+type ValidationRow struct {
+	Code  int   `json:"code"`
+	Count int64 `json:"count"`
+}
+
+// Report adds up the statistics of the account over the window. Bidders are sorted by requests, most
+// first, and validation codes by count, most first.
+func (t *Tracker) Report(account string) Report {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	now := t.clock.Now()
+	minute := now.Unix() / 60
+	oldest := minute - int64(len(t.slots)) + 1
+	total := newStats()
+	for _, s := range t.slots {
+		if s.minute < oldest || s.minute > minute {
+			continue
+		}
+		if accountStats, ok := s.accounts[account]; ok {
+			total.add(accountStats)
+		}
+	}
+
+	report := Report{
+		Account:    account,
+		From:       time.Unix(oldest*60, 0).UTC(),
+		To:         now.UTC(),
+		Auctions:   total.auctions,
+		Bidders:    make([]BidderRow, 0, len(total.bidders)),
+		Validation: make([]ValidationRow, 0, len(total.validation)),
+	}
+	for name, b := range total.bidders {
+		row := BidderRow{
+			Bidder:          name,
+			Requests:        b.requests,
+			Timeouts:        b.timeouts,
+			Failures:        b.failures,
+			PrivacyBlocks:   b.privacyBlocks,
+			FloorRejections: b.floorRejections,
+			RejectedBids:    b.rejectedBids,
+		}
+		if b.requests > 0 {
+			row.TimeoutRate = float64(b.timeouts) / float64(b.requests)
+		}
+		report.Bidders = append(report.Bidders, row)
+	}
+	sort.Slice(report.Bidders, func(i, j int) bool {
+		a, b := report.Bidders[i], report.Bidders[j]
+		if a.Requests != b.Requests {
+			return a.Requests > b.Requests
+		}
+		return a.Bidder < b.Bidder
+	})
+	for code, n := range total.validation {
+		report.Validation = append(report.Validation, ValidationRow{Code: code, Count: n})
+	}
+	sort.Slice(report.Validation, func(i, j int) bool {
+		a, b := report.Validation[i], report.Validation[j]
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		return a.Code < b.Code
+	})
+	return report
+}
+
+func (s *stats) add(other *stats) {
+	s.auctions += other.auctions
+	for name, o := range other.bidders {
+		b := s.bidder(name)
+		b.requests += o.requests
+		b.timeouts += o.timeouts
+		b.failures += o.failures
+		b.privacyBlocks += o.privacyBlocks
+		b.floorRejections += o.floorRejections
+		b.rejectedBids += o.rejectedBids
+	}
+	for code, n := range other.validation {
+		s.validation[code] += n
+	}
+}
@@ -0,0 +1,162 @@
+// Package auctionquality keeps statistics of the health of each account's recent auctions in memory: how
+// often its bidders time out or fail, the validation problems of its requests, and the bidders and bids
+// blocked by privacy policies and floors. Publishers fetch them with their account's API keys, so they can
+// diagnose their own setup without asking the host.
+package auctionquality
+
+import (
+	"sync"
+
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/util/timeutil"
+)
+
+// Auction is what's recorded of an auction.
+type Auction struct {
+	Account string
+	Bidders []Bidder
+	// Validation counts the validation errors and warnings of the auction by code
+	Validation map[int]int
+}
+
+// Bidder is how a bidder fared in an auction. Bidders which weren't called, such as those left out by
+// privacy policies, have Called unset.
+type Bidder struct {
+	Name     string
+	Called   bool
+	TimedOut bool
+	// Failed is set if the call ended with an error other than a timeout
+	Failed bool
+	// FloorRejections is the number of the bidder's bids which were below their floors
+	FloorRejections int
+	// RejectedBids is the number of the bidder's bids which were rejected as invalid
+	RejectedBids int
+}
+
+// Tracker keeps the statistics by the minute, in a ring of slots which covers the window. A nil *Tracker
+// is valid and records nothing.
+type Tracker struct {
+	maxAccounts int
+	clock       timeutil.Time
+
+	mutex sync.Mutex
+	slots []slot
+}
+
+// slot has the statistics of one minute.
+type slot struct {
+	minute   int64
+	accounts map[string]*stats
+}
+
+type stats struct {
+	auctions   int64
+	bidders    map[string]*bidderStats
+	validation map[int]int64
+}
+
+type bidderStats struct {
+	requests        int64
+	timeouts        int64
+	failures        int64
+	privacyBlocks   int64
+	floorRejections int64
+	rejectedBids    int64
+}
+
+// New builds a Tracker, or returns nil if the auction quality statistics are disabled.
+func New(cfg config.AuctionQuality) *Tracker {
+	if !cfg.Enabled {
+		return nil
+	}
+	return &Tracker{
+		maxAccounts: cfg.MaxAccounts,
+		clock:       &timeutil.RealTime{},
+		slots:       make([]slot, cfg.WindowMinutes),
+	}
+}
+
+// Record adds an auction to the statistics of the current minute. Once the minute has as many accounts as
+// the config allows, the auctions of new accounts are left out.
+func (t *Tracker) Record(auction Auction) {
+	if t == nil {
+		return
+	}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	s := t.statsOf(t.currentSlot(), auction.Account)
+	if s == nil {
+		return
+	}
+	s.auctions++
+	for _, bidder := range auction.Bidders {
+		b := s.bidder(bidder.Name)
+		if bidder.Called {
+			b.requests++
+		}
+		if bidder.TimedOut {
+			b.timeouts++
+		}
+		if bidder.Failed {
+			b.failures++
+		}
+		b.floorRejections += int64(bidder.FloorRejections)
+		b.rejectedBids += int64(bidder.RejectedBids)
+	}
+	for code, n := range auction.Validation {
+		s.validation[code] += int64(n)
+	}
+}
+
+// RecordPrivacyBlock counts a bidder which wasn't called for an auction of the account because a privacy
+// policy didn't allow it.
+func (t *Tracker) RecordPrivacyBlock(account, bidder string) {
+	if t == nil {
+		return
+	}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	if s := t.statsOf(t.currentSlot(), account); s != nil {
+		s.bidder(bidder).privacyBlocks++
+	}
+}
+
+// currentSlot returns the slot of the current minute, clearing it if it last held an earlier minute.
+func (t *Tracker) currentSlot() *slot {
+	minute := t.clock.Now().Unix() / 60
+	current := &t.slots[minute%int64(len(t.slots))]
+	if current.minute != minute || current.accounts == nil {
+		current.minute = minute
+		current.accounts = make(map[string]*stats)
+	}
+	return current
+}
+
+// statsOf returns the statistics of the account in the slot, or nil if the slot is full.
+func (t *Tracker) statsOf(current *slot, account string) *stats {
+	s, ok := current.accounts[account]
+	if ok {
+		return s
+	}
+	if len(current.accounts) >= t.maxAccounts {
+		return nil
+	}
+	s = newStats()
+	current.accounts[account] = s
+	return s
+}
+
+func newStats() *stats {
+	return &stats{bidders: make(map[string]*bidderStats), validation: make(map[int]int64)}
+}
+
+func (s *stats) bidder(name string) *bidderStats {
+	b, ok := s.bidders[name]
+	if !ok {
+		b = &bidderStats{}
+		s.bidders[name] = b
+	}
+	return b
+}
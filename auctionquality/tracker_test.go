@@ -0,0 +1,95 @@
+package auctionquality
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/util/timeutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestTracker(clock timeutil.Time) *Tracker {
+	t := New(config.AuctionQuality{Enabled: true, WindowMinutes: 3, MaxAccounts: 2})
+	t.clock = clock
+	return t
+}
+
+func TestRecordNil(t *testing.T) {
+	var tracker *Tracker
+	assert.NotPanics(t, func() {
+		tracker.Record(Auction{Account: "acct", Bidders: []Bidder{{Name: "appnexus", Called: true}}})
+		tracker.RecordPrivacyBlock("acct", "appnexus")
+	})
+}
+
+func TestReport(t *testing.T) {
+	clock := timeutil.NewFakeTime(time.Date(2024, 5, 1, 12, 0, 30, 0, time.UTC))
+	tracker := newTestTracker(clock)
+	tracker.Record(Auction{
+		Account: "acct",
+		Bidders: []Bidder{
+			{Name: "appnexus", Called: true, TimedOut: true},
+			{Name: "rubicon", Called: true, FloorRejections: 2, RejectedBids: 1},
+		},
+		Validation: map[int]int{10001: 1},
+	})
+	tracker.RecordPrivacyBlock("acct", "openx")
+	tracker.Record(Auction{Account: "other", Bidders: []Bidder{{Name: "appnexus", Called: true}}})
+
+	clock.Advance(time.Minute)
+	tracker.Record(Auction{
+		Account:    "acct",
+		Bidders:    []Bidder{{Name: "appnexus", Called: true, Failed: true}},
+		Validation: map[int]int{10001: 1, 10005: 3},
+	})
+
+	report := tracker.Report("acct")
+
+	assert.Equal(t, "acct", report.Account)
+	assert.Equal(t, time.Date(2024, 5, 1, 11, 59, 0, 0, time.UTC), report.From)
+	assert.Equal(t, clock.Now(), report.To)
+	assert.Equal(t, int64(2), report.Auctions)
+	assert.Equal(t, []BidderRow{
+		{Bidder: "appnexus", Requests: 2, Timeouts: 1, TimeoutRate: 0.5, Failures: 1},
+		{Bidder: "rubicon", Requests: 1, FloorRejections: 2, RejectedBids: 1},
+		{Bidder: "openx", PrivacyBlocks: 1},
+	}, report.Bidders)
+	assert.Equal(t, []ValidationRow{{Code: 10005, Count: 3}, {Code: 10001, Count: 2}}, report.Validation)
+}
+
+func TestReportWindow(t *testing.T) {
+	clock := timeutil.NewFakeTime(time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC))
+	tracker := newTestTracker(clock)
+	tracker.Record(Auction{Account: "acct", Bidders: []Bidder{{Name: "appnexus", Called: true}}})
+
+	clock.Advance(2 * time.Minute)
+	assert.Equal(t, int64(1), tracker.Report("acct").Auctions)
+
+	// the first minute's slot is reused once it falls out of the window
+	clock.Advance(time.Minute)
+	assert.Equal(t, int64(0), tracker.Report("acct").Auctions)
+	tracker.Record(Auction{Account: "acct"})
+	assert.Equal(t, int64(1), tracker.Report("acct").Auctions)
+	assert.Empty(t, tracker.Report("acct").Bidders)
+}
+
+func TestRecordMaxAccounts(t *testing.T) {
+	clock := timeutil.NewFakeTime(time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC))
+	tracker := newTestTracker(clock)
+	tracker.Record(Auction{Account: "one"})
+	tracker.Record(Auction{Account: "two"})
+	tracker.Record(Auction{Account: "three"})
+	tracker.RecordPrivacyBlock("three", "appnexus")
+	tracker.Record(Auction{Account: "one"})
+
+	assert.Equal(t, int64(2), tracker.Report("one").Auctions)
+	assert.Equal(t, int64(1), tracker.Report("two").Auctions)
+	assert.Equal(t, Report{
+		Account:    "three",
+		From:       time.Date(2024, 5, 1, 11, 58, 0, 0, time.UTC),
+		To:         clock.Now(),
+		Bidders:    []BidderRow{},
+		Validation: []ValidationRow{},
+	}, tracker.Report("three"))
+}
@@ -0,0 +1,76 @@
+package auctionrecording
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/prebid/prebid-server/v2/stored_requests"
+)
+
+// Fetcher returns a fetcher which records the stored requests and imps fetched for recorded auctions, and
+// serves replayed auctions the ones in their recording.
+func (r *Recorder) Fetcher(fetcher stored_requests.Fetcher) stored_requests.Fetcher {
+	if r == nil {
+		return fetcher
+	}
+	return &recordingFetcher{Fetcher: fetcher}
+}
+
+// AccountFetcher returns a fetcher which records the account fetched for recorded auctions, and serves
+// replayed auctions the one in their recording.
+func (r *Recorder) AccountFetcher(fetcher stored_requests.AccountFetcher) stored_requests.AccountFetcher {
+	if r == nil {
+		return fetcher
+	}
+	return &recordingAccountFetcher{AccountFetcher: fetcher}
+}
+
+type recordingFetcher struct {
+	stored_requests.Fetcher
+}
+
+func (f *recordingFetcher) FetchRequests(ctx context.Context, requestIDs []string, impIDs []string) (map[string]json.RawMessage, map[string]json.RawMessage, []error) {
+	session := FromContext(ctx)
+	if session == nil {
+		return f.Fetcher.FetchRequests(ctx, requestIDs, impIDs)
+	}
+
+	requestData, missingRequestIDs := session.replayedStoredData(StoredDataRequest, requestIDs)
+	impData, missingImpIDs := session.replayedStoredData(StoredDataImp, impIDs)
+	var errs []error
+	if len(missingRequestIDs) > 0 || len(missingImpIDs) > 0 {
+		var fetchedRequests, fetchedImps map[string]json.RawMessage
+		fetchedRequests, fetchedImps, errs = f.Fetcher.FetchRequests(ctx, missingRequestIDs, missingImpIDs)
+		requestData = merge(requestData, fetchedRequests)
+		impData = merge(impData, fetchedImps)
+	}
+
+	session.recordStoredData(StoredDataRequest, requestData)
+	session.recordStoredData(StoredDataImp, impData)
+	return requestData, impData, errs
+}
+
+func merge(data, more map[string]json.RawMessage) map[string]json.RawMessage {
+	if data == nil {
+		return more
+	}
+	for id, value := range more {
+		data[id] = value
+	}
+	return data
+}
+
+type recordingAccountFetcher struct {
+	stored_requests.AccountFetcher
+}
+
+func (f *recordingAccountFetcher) FetchAccount(ctx context.Context, accountDefaultJSON json.RawMessage, accountID string) (json.RawMessage, []error) {
+	session := FromContext(ctx)
+	account, ok := session.replayedAccount()
+	var errs []error
+	if !ok {
+		account, errs = f.AccountFetcher.FetchAccount(ctx, accountDefaultJSON, accountID)
+	}
+	session.recordAccount(accountID, account)
+	return account, errs
+}
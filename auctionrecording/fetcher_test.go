@@ -0,0 +1,36 @@
+package auctionrecording
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFetcherReplaysRecordedData(t *testing.T) {
+	fetcher := &fakeFetcher{imps: map[string]json.RawMessage{"imp2": json.RawMessage(`{"fetched":true}`)}}
+	recorded := newStoredData(StoredDataImp, "imp1", json.RawMessage(`{"recorded":true}`))
+	session := newReplaySession(&Recording{StoredData: []StoredData{recorded}})
+
+	_, imps, errs := (&Recorder{}).Fetcher(fetcher).FetchRequests(WithSession(context.Background(), session), nil, []string{"imp1", "imp2"})
+
+	assert.Empty(t, errs)
+	assert.Equal(t, map[string]json.RawMessage{
+		"imp1": json.RawMessage(`{"recorded":true}`),
+		"imp2": json.RawMessage(`{"fetched":true}`),
+	}, imps)
+	assert.Equal(t, 1, fetcher.calls, "only data which wasn't recorded should be fetched")
+	assert.Len(t, session.Recording().StoredData, 2)
+}
+
+func TestAccountFetcherReplaysRecordedAccount(t *testing.T) {
+	fetcher := &fakeFetcher{account: json.RawMessage(`{"id":"current"}`)}
+	accounts := (&Recorder{}).AccountFetcher(fetcher)
+
+	account, _ := accounts.FetchAccount(WithSession(context.Background(), newReplaySession(&Recording{Account: json.RawMessage(`{"id":"recorded"}`)})), nil, "1001")
+	assert.JSONEq(t, `{"id":"recorded"}`, string(account))
+
+	account, _ = accounts.FetchAccount(WithSession(context.Background(), newReplaySession(&Recording{})), nil, "1001")
+	assert.JSONEq(t, `{"id":"current"}`, string(account), "the current account should be used if none was recorded")
+}
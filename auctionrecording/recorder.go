@@ -0,0 +1,224 @@
+package auctionrecording
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"math/rand"
+	"net/http"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/logger"
+	"github.com/prebid/prebid-server/v2/util/uuidutil"
+)
+
+// Recorder records sampled requests to the endpoints it wraps, and replays recordings against them.
+// A nil Recorder leaves everything it's given as it is.
+type Recorder struct {
+	sampleRate    float64
+	accounts      map[string]struct{}
+	maxBodySize   int64
+	replayEnabled bool
+	endpoints     map[string]httprouter.Handle
+
+	sink  Sink
+	queue chan *Recording
+	done  chan struct{}
+
+	random func() float64
+	uuid   uuidutil.UUIDGenerator
+}
+
+// NewRecorder returns a recorder which writes to the configured sink, or nil if both recording and replay
+// are disabled. Request bodies over maxBodySize bytes aren't recorded.
+func NewRecorder(cfg config.AuctionRecording, maxBodySize int64) (*Recorder, error) {
+	if !cfg.Enabled && !cfg.ReplayEnabled {
+		return nil, nil
+	}
+
+	recorder := &Recorder{
+		maxBodySize:   maxBodySize,
+		replayEnabled: cfg.ReplayEnabled,
+		endpoints:     make(map[string]httprouter.Handle),
+		random:        rand.Float64,
+		uuid:          uuidutil.UUIDRandomGenerator{},
+	}
+	if cfg.Enabled {
+		sink, err := NewSink(cfg.Sink)
+		if err != nil {
+			return nil, err
+		}
+		recorder.sampleRate = cfg.SampleRate
+		recorder.accounts = make(map[string]struct{}, len(cfg.Accounts))
+		for _, account := range cfg.Accounts {
+			recorder.accounts[account] = struct{}{}
+		}
+		recorder.sink = sink
+		recorder.queue = make(chan *Recording, cfg.QueueSize)
+		recorder.done = make(chan struct{})
+		go recorder.writeRecordings()
+	}
+	return recorder, nil
+}
+
+// Close writes the recordings which are still queued, and closes the sink.
+func (r *Recorder) Close() {
+	if r == nil || r.sink == nil {
+		return
+	}
+	close(r.queue)
+	<-r.done
+	if err := r.sink.Close(); err != nil {
+		logger.Errorf("Failed to close the auction recording sink: %v", err)
+	}
+}
+
+// Record returns a handle which records a sample of the requests to the endpoint at path. It must be
+// called for an endpoint before its requests can be replayed.
+func (r *Recorder) Record(path string, handle httprouter.Handle) httprouter.Handle {
+	if r == nil {
+		return handle
+	}
+
+	recordingHandle := func(w http.ResponseWriter, req *http.Request, params httprouter.Params) {
+		session := FromContext(req.Context())
+		if session == nil {
+			if r.sink == nil || r.random() >= r.sampleRate {
+				handle(w, req, params)
+				return
+			}
+			session = newSession()
+			req = req.WithContext(WithSession(req.Context(), session))
+		}
+		r.startRecording(session, path, req)
+
+		recorder := &responseRecorder{ResponseWriter: w}
+		handle(recorder, req, params)
+
+		session.update(func(recording *Recording) {
+			recording.Status = recorder.status
+			if recording.Status == 0 {
+				recording.Status = http.StatusOK
+			}
+			recording.Response = rawOrString(recorder.body.Bytes())
+		})
+		if !session.isReplay() {
+			r.save(session)
+		}
+	}
+	r.endpoints[path] = recordingHandle
+	return recordingHandle
+}
+
+// startRecording records the request, restoring its body so it can be read again by the endpoint.
+func (r *Recorder) startRecording(session *Session, path string, req *http.Request) {
+	id, err := r.uuid.Generate()
+	if err != nil {
+		logger.Errorf("Failed to generate an auction recording ID: %v", err)
+	}
+
+	var body []byte
+	header := req.Header.Clone()
+	if req.Body != nil {
+		var reader io.Reader = req.Body
+		if r.maxBodySize > 0 {
+			reader = io.LimitReader(req.Body, r.maxBodySize+1)
+		}
+		read, _ := io.ReadAll(reader)
+		req.Body = readCloser{Reader: io.MultiReader(bytes.NewReader(read), req.Body), Closer: req.Body}
+		if r.maxBodySize <= 0 || int64(len(read)) <= r.maxBodySize {
+			body = read
+		}
+		// The endpoint would fail to decompress the body too, so it's recorded as it is if it's malformed
+		if header.Get("Content-Encoding") == "gzip" {
+			if decompressed, err := gunzip(body); err == nil {
+				body = decompressed
+				header.Del("Content-Encoding")
+			}
+		}
+	}
+
+	session.update(func(recording *Recording) {
+		recording.ID = id
+		recording.Timestamp = time.Now().UTC()
+		recording.Method = req.Method
+		recording.Endpoint = path
+		recording.Query = req.URL.RawQuery
+		recording.Header = header
+		recording.Body = rawOrString(body)
+		recording.Consent = readConsent(body, header)
+	})
+}
+
+// save queues the recording to be written, unless it's for an account which isn't being recorded.
+func (r *Recorder) save(session *Session) {
+	recording := session.Recording()
+	if _, ok := r.accounts[recording.AccountID]; len(r.accounts) > 0 && !ok {
+		return
+	}
+	select {
+	case r.queue <- &recording:
+	default:
+		logger.Warningf("Dropped auction recording %s because %d recordings are already waiting to be written", recording.ID, cap(r.queue))
+	}
+}
+
+func (r *Recorder) writeRecordings() {
+	defer close(r.done)
+	for recording := range r.queue {
+		if err := r.sink.Write(recording); err != nil {
+			logger.Errorf("Failed to write auction recording %s: %v", recording.ID, err)
+		}
+	}
+}
+
+// rawOrString returns data if it's JSON, or else data as a JSON string.
+func rawOrString(data []byte) json.RawMessage {
+	if len(data) == 0 {
+		return nil
+	}
+	if json.Valid(data) {
+		return data
+	}
+	quoted, _ := json.Marshal(string(data))
+	return quoted
+}
+
+func gunzip(data []byte) ([]byte, error) {
+	reader, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	return io.ReadAll(reader)
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// responseRecorder keeps a copy of the response written by an endpoint.
+type responseRecorder struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (w *responseRecorder) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *responseRecorder) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
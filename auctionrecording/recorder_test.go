@@ -0,0 +1,345 @@
+package auctionrecording
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/exchange/inprocess"
+	"github.com/prebid/prebid-server/v2/stored_requests"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeAuction stands in for the auction endpoint. It fetches the account and a stored imp, calls a
+// bidder, and responds with what it got.
+type fakeAuction struct {
+	fetcher  stored_requests.Fetcher
+	accounts stored_requests.AccountFetcher
+	client   *http.Client
+}
+
+func (a *fakeAuction) handle(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	// Like the auction endpoint, the stages run under contexts which aren't derived from the request's.
+	ctx := WithSession(context.Background(), FromContext(r.Context()))
+
+	account, _ := a.accounts.FetchAccount(ctx, json.RawMessage(`{}`), "1001")
+	_, imps, _ := a.fetcher.FetchRequests(ctx, nil, []string{"stored-imp"})
+
+	req, _ := http.NewRequestWithContext(ctx, http.MethodPost, "http://bidder.com/bid", bytes.NewReader(body))
+	resp, err := a.client.Do(req)
+	if err != nil {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		fmt.Fprintf(w, "Bidder failed: %v", err)
+		return
+	}
+	defer resp.Body.Close()
+	bid, _ := io.ReadAll(resp.Body)
+
+	fmt.Fprintf(w, `{"account":%s,"imp":%s,"bid":%s}`, account, imps["stored-imp"], bid)
+}
+
+type fakeFetcher struct {
+	stored_requests.Fetcher
+	imps    map[string]json.RawMessage
+	account json.RawMessage
+	calls   int
+}
+
+func (f *fakeFetcher) FetchRequests(ctx context.Context, requestIDs []string, impIDs []string) (map[string]json.RawMessage, map[string]json.RawMessage, []error) {
+	f.calls++
+	imps := make(map[string]json.RawMessage)
+	for _, id := range impIDs {
+		imps[id] = f.imps[id]
+	}
+	return nil, imps, nil
+}
+
+func (f *fakeFetcher) FetchAccount(ctx context.Context, accountDefaultJSON json.RawMessage, accountID string) (json.RawMessage, []error) {
+	f.calls++
+	return f.account, nil
+}
+
+// newFakeAuction returns an auction whose bidder bids price, and whose stored data is tagged with version.
+func newFakeAuction(recorder *Recorder, version string, price float64) (*fakeAuction, *fakeFetcher) {
+	fetcher := &fakeFetcher{
+		imps:    map[string]json.RawMessage{"stored-imp": json.RawMessage(fmt.Sprintf(`{"version":%q}`, version))},
+		account: json.RawMessage(fmt.Sprintf(`{"id":"1001","version":%q}`, version)),
+	}
+	transport := inprocess.NewTransport(nil)
+	transport.Handle("bidder.com", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"price":%g}`, price)
+	}))
+	return &fakeAuction{
+		fetcher:  recorder.Fetcher(fetcher),
+		accounts: recorder.AccountFetcher(fetcher),
+		client:   recorder.BidderClient(&http.Client{Transport: transport}),
+	}, fetcher
+}
+
+func newTestRecorder(t *testing.T, cfg config.AuctionRecording) (*Recorder, string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "recordings.jsonl")
+	cfg.QueueSize = 10
+	cfg.Sink = config.AuctionRecordingSink{Type: config.AuctionRecordingSinkFile, File: config.AuctionRecordingFileSink{Path: path}}
+	recorder, err := NewRecorder(cfg, 1024)
+	require.NoError(t, err)
+	return recorder, path
+}
+
+func readRecordings(t *testing.T, path string) []Recording {
+	t.Helper()
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	defer file.Close()
+
+	var recordings []Recording
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(nil, 1<<20)
+	for scanner.Scan() {
+		var recording Recording
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &recording))
+		recordings = append(recordings, recording)
+	}
+	require.NoError(t, scanner.Err())
+	return recordings
+}
+
+func TestRecordAndReplay(t *testing.T) {
+	recorder, path := newTestRecorder(t, config.AuctionRecording{Enabled: true, SampleRate: 1, ReplayEnabled: true})
+	auction, _ := newFakeAuction(recorder, "v1", 1.5)
+	handle := recorder.Record("/openrtb2/auction", auction.handle)
+
+	body := `{"id":"req","regs":{"gdpr":1},"user":{"consent":"CONSENT"}}`
+	req := httptest.NewRequest(http.MethodPost, "/openrtb2/auction?debug=1", strings.NewReader(body))
+	req.Header.Set("Sec-GPC", "1")
+	w := httptest.NewRecorder()
+	handle(w, req, nil)
+	expectedResponse := `{"account":{"id":"1001","version":"v1"},"imp":{"version":"v1"},"bid":{"price":1.5}}`
+	require.Equal(t, expectedResponse, w.Body.String())
+
+	recorder.Close()
+	recordings := readRecordings(t, path)
+	require.Len(t, recordings, 1)
+	recording := recordings[0]
+
+	assert.NotEmpty(t, recording.ID)
+	assert.Equal(t, http.MethodPost, recording.Method)
+	assert.Equal(t, "/openrtb2/auction", recording.Endpoint)
+	assert.Equal(t, "debug=1", recording.Query)
+	assert.JSONEq(t, body, string(recording.Body))
+	assert.Equal(t, Consent{GDPR: "1", TCF: "CONSENT", GPC: "1"}, recording.Consent)
+	assert.Equal(t, "1001", recording.AccountID)
+	assert.JSONEq(t, `{"id":"1001","version":"v1"}`, string(recording.Account))
+	require.Len(t, recording.StoredData, 1)
+	assert.Equal(t, StoredDataImp, recording.StoredData[0].Type)
+	assert.Equal(t, "stored-imp", recording.StoredData[0].ID)
+	assert.Len(t, recording.StoredData[0].Version, 16)
+	require.Len(t, recording.BidderCalls, 1)
+	assert.Equal(t, "http://bidder.com/bid", recording.BidderCalls[0].URI)
+	assert.JSONEq(t, body, recording.BidderCalls[0].RequestBody)
+	assert.Equal(t, http.StatusOK, recording.BidderCalls[0].Status)
+	assert.Equal(t, `{"price":1.5}`, recording.BidderCalls[0].ResponseBody)
+	assert.Equal(t, http.StatusOK, recording.Status)
+	assert.JSONEq(t, expectedResponse, string(recording.Response))
+
+	// The stored data and the bidder have changed since, but the replay should still get what the
+	// recorded auction did.
+	replayRecorder, _ := newTestRecorder(t, config.AuctionRecording{ReplayEnabled: true})
+	replayAuction, fetcher := newFakeAuction(replayRecorder, "v2", 3)
+	replayRecorder.Record("/openrtb2/auction", replayAuction.handle)
+
+	recordingJSON, err := json.Marshal(recording)
+	require.NoError(t, err)
+	w = httptest.NewRecorder()
+	replayRecorder.ReplayHandler().ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/auction_recording/replay", bytes.NewReader(recordingJSON)))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var result ReplayResult
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+	assert.Equal(t, recording.ID, result.Original.ID)
+	assert.JSONEq(t, expectedResponse, string(result.Replayed.Response))
+	assert.Equal(t, "debug=1", result.Replayed.Query)
+	assert.Equal(t, recording.StoredData, result.Replayed.StoredData)
+	assert.Equal(t, 0, fetcher.calls, "replays should not fetch data which was recorded")
+	assert.Equal(t, recording.BidderCalls[0].ResponseBody, result.Replayed.BidderCalls[0].ResponseBody)
+}
+
+func TestReplayUnmatchedBidderCall(t *testing.T) {
+	recorder, _ := newTestRecorder(t, config.AuctionRecording{ReplayEnabled: true})
+	auction, _ := newFakeAuction(recorder, "v1", 1)
+	recorder.Record("/openrtb2/auction", auction.handle)
+
+	recording := Recording{Method: http.MethodPost, Endpoint: "/openrtb2/auction", Body: json.RawMessage(`{"id":"req"}`)}
+	recordingJSON, err := json.Marshal(recording)
+	require.NoError(t, err)
+	w := httptest.NewRecorder()
+	recorder.ReplayHandler().ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(recordingJSON)))
+
+	var result ReplayResult
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+	assert.Equal(t, http.StatusServiceUnavailable, result.Replayed.Status)
+	require.Len(t, result.Replayed.BidderCalls, 1)
+	assert.Equal(t, "no recorded call to POST http://bidder.com/bid", result.Replayed.BidderCalls[0].Error)
+	assert.Equal(t, `"Bidder failed: Post \"http://bidder.com/bid\": no recorded call to POST http://bidder.com/bid"`, string(result.Replayed.Response))
+}
+
+func TestReplayHandlerErrors(t *testing.T) {
+	recorder, _ := newTestRecorder(t, config.AuctionRecording{ReplayEnabled: true})
+	recorder.Record("/openrtb2/auction", func(http.ResponseWriter, *http.Request, httprouter.Params) {})
+	handler := recorder.ReplayHandler()
+
+	testCases := []struct {
+		name         string
+		method       string
+		body         string
+		expectedCode int
+		expectedBody string
+	}{
+		{
+			name:         "get",
+			method:       http.MethodGet,
+			expectedCode: http.StatusMethodNotAllowed,
+			expectedBody: "Recordings must be POSTed to be replayed\n",
+		},
+		{
+			name:         "malformed",
+			method:       http.MethodPost,
+			body:         `{"id":`,
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "Invalid recording: unexpected EOF\n",
+		},
+		{
+			name:         "unknown-endpoint",
+			method:       http.MethodPost,
+			body:         `{"endpoint":"/openrtb2/video"}`,
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "Can't replay requests to /openrtb2/video\n",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, httptest.NewRequest(test.method, "/", strings.NewReader(test.body)))
+			assert.Equal(t, test.expectedCode, w.Code)
+			assert.Equal(t, test.expectedBody, w.Body.String())
+		})
+	}
+}
+
+func TestRecordSampling(t *testing.T) {
+	recorder, path := newTestRecorder(t, config.AuctionRecording{Enabled: true, SampleRate: 0.5, Accounts: []string{"1001"}})
+	samples := []float64{0.7, 0.2, 0.4}
+	recorder.random = func() float64 {
+		sample := samples[0]
+		samples = samples[1:]
+		return sample
+	}
+
+	accounts := []string{"1001", "1001", "other"}
+	var handled []string
+	handle := recorder.Record("/openrtb2/auction", func(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+		account := accounts[len(handled)]
+		handled = append(handled, account)
+		FromContext(r.Context()).recordAccount(account, nil)
+	})
+	for range accounts {
+		handle(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/openrtb2/auction", strings.NewReader(`{}`)), nil)
+	}
+	recorder.Close()
+
+	assert.Equal(t, accounts, handled, "requests should be handled whether they're recorded or not")
+	recordings := readRecordings(t, path)
+	require.Len(t, recordings, 1, "only sampled requests for the listed accounts should be recorded")
+	assert.Equal(t, "1001", recordings[0].AccountID)
+}
+
+func TestRecordRequestBody(t *testing.T) {
+	var gzipped bytes.Buffer
+	writer := gzip.NewWriter(&gzipped)
+	writer.Write([]byte(`{"id":"gzipped"}`))
+	writer.Close()
+
+	testCases := []struct {
+		name            string
+		body            []byte
+		contentEncoding string
+		expectedBody    string
+	}{
+		{
+			name:         "json",
+			body:         []byte(`{"id":"req"}`),
+			expectedBody: `{"id":"req"}`,
+		},
+		{
+			name:         "not-json",
+			body:         []byte(`id=req`),
+			expectedBody: `"id=req"`,
+		},
+		{
+			name:            "gzip",
+			body:            gzipped.Bytes(),
+			contentEncoding: "gzip",
+			expectedBody:    `{"id":"gzipped"}`,
+		},
+		{
+			name: "too-large",
+			body: bytes.Repeat([]byte(" "), 2000),
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			recorder, path := newTestRecorder(t, config.AuctionRecording{Enabled: true, SampleRate: 1})
+			var handledBody []byte
+			handle := recorder.Record("/openrtb2/auction", func(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+				handledBody, _ = io.ReadAll(r.Body)
+			})
+
+			req := httptest.NewRequest(http.MethodPost, "/openrtb2/auction", bytes.NewReader(test.body))
+			if test.contentEncoding != "" {
+				req.Header.Set("Content-Encoding", test.contentEncoding)
+			}
+			handle(httptest.NewRecorder(), req, nil)
+			recorder.Close()
+
+			assert.Equal(t, test.body, handledBody, "the endpoint should get the body as it was sent")
+			recordings := readRecordings(t, path)
+			require.Len(t, recordings, 1)
+			assert.Equal(t, test.expectedBody, string(recordings[0].Body))
+			assert.Empty(t, recordings[0].Header.Get("Content-Encoding"), "the body should be recorded decompressed")
+		})
+	}
+}
+
+func TestNilRecorder(t *testing.T) {
+	recorder, err := NewRecorder(config.AuctionRecording{Enabled: false, ReplayEnabled: false}, 0)
+	require.NoError(t, err)
+	require.Nil(t, recorder)
+
+	client := &http.Client{}
+	fetcher := &fakeFetcher{}
+	assert.Same(t, client, recorder.BidderClient(client))
+	assert.Same(t, fetcher, recorder.Fetcher(fetcher))
+	assert.Same(t, fetcher, recorder.AccountFetcher(fetcher))
+	assert.Nil(t, recorder.ReplayHandler())
+	recorder.Close()
+}
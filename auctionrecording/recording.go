@@ -0,0 +1,107 @@
+// Package auctionrecording captures sampled auctions, with the account, stored data and bidder responses
+// they used, and replays them against the current code. A replayed auction gets its inputs from the
+// recording rather than from the stores and bidders, so any difference in its outcome is down to the code.
+package auctionrecording
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/buger/jsonparser"
+	"github.com/prebid/prebid-server/v2/stored_requests"
+)
+
+// Recording is everything an auction depended on, along with the response it got. Endpoint, Query and
+// Body are named as in the captured request files of cmd/loadgen, so a file of recordings can be used
+// to replay load as well.
+type Recording struct {
+	ID        string    `json:"id"`
+	Timestamp time.Time `json:"timestamp"`
+
+	Method   string          `json:"method"`
+	Endpoint string          `json:"endpoint"`
+	Query    string          `json:"query,omitempty"`
+	Header   http.Header     `json:"header,omitempty"`
+	Body     json.RawMessage `json:"body,omitempty"`
+	Consent  Consent         `json:"consent"`
+
+	AccountID string          `json:"accountid,omitempty"`
+	Account   json.RawMessage `json:"account,omitempty"`
+	// StoredData holds the stored requests and imps which were merged into the request
+	StoredData  []StoredData `json:"storeddata,omitempty"`
+	BidderCalls []BidderCall `json:"biddercalls,omitempty"`
+
+	Status   int             `json:"status"`
+	Response json.RawMessage `json:"response,omitempty"`
+}
+
+// Stored data types
+const (
+	StoredDataRequest = "request"
+	StoredDataImp     = "imp"
+)
+
+// StoredData is a stored request or imp. Version is a hash of its content, so recordings which used the
+// same version can be found without comparing the data itself.
+type StoredData struct {
+	Type    string          `json:"type"`
+	ID      string          `json:"id"`
+	Version string          `json:"version"`
+	Data    json.RawMessage `json:"data"`
+}
+
+func newStoredData(dataType, id string, data json.RawMessage) StoredData {
+	return StoredData{
+		Type:    dataType,
+		ID:      id,
+		Version: stored_requests.ContentVersion(data),
+		Data:    data,
+	}
+}
+
+// BidderCall is an HTTP call made by a bidder adapter. Error is set instead of the response if the call
+// failed, as it does when the bidder times out.
+type BidderCall struct {
+	Method         string      `json:"method"`
+	URI            string      `json:"uri"`
+	RequestHeader  http.Header `json:"requestheader,omitempty"`
+	RequestBody    string      `json:"requestbody,omitempty"`
+	Status         int         `json:"status,omitempty"`
+	ResponseHeader http.Header `json:"responseheader,omitempty"`
+	ResponseBody   string      `json:"responsebody,omitempty"`
+	Error          string      `json:"error,omitempty"`
+}
+
+// Consent holds the privacy signals of the request, so recordings can be filtered by them. They're read
+// from the request as received, before any stored request was merged into it.
+type Consent struct {
+	GDPR      string `json:"gdpr,omitempty"`
+	TCF       string `json:"tcf,omitempty"`
+	USPrivacy string `json:"usprivacy,omitempty"`
+	GPP       string `json:"gpp,omitempty"`
+	GPPSID    string `json:"gppsid,omitempty"`
+	GPC       string `json:"gpc,omitempty"`
+}
+
+func readConsent(body []byte, header http.Header) Consent {
+	return Consent{
+		GDPR:      firstValue(body, []string{"regs", "gdpr"}, []string{"regs", "ext", "gdpr"}),
+		TCF:       firstValue(body, []string{"user", "consent"}, []string{"user", "ext", "consent"}),
+		USPrivacy: firstValue(body, []string{"regs", "us_privacy"}, []string{"regs", "ext", "us_privacy"}),
+		GPP:       firstValue(body, []string{"regs", "gpp"}),
+		GPPSID:    firstValue(body, []string{"regs", "gpp_sid"}),
+		GPC:       header.Get("Sec-GPC"),
+	}
+}
+
+// firstValue returns the value at the first of paths which the JSON has, as it appears in the JSON
+// without any quotes.
+func firstValue(data []byte, paths ...[]string) string {
+	for _, path := range paths {
+		if value, _, _, err := jsonparser.Get(data, path...); err == nil {
+			return string(value)
+		}
+	}
+	return ""
+}
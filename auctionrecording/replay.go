@@ -0,0 +1,103 @@
+package auctionrecording
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+// ReplayResult holds a recording along with the recording of its replay, so the two can be compared.
+type ReplayResult struct {
+	Original Recording `json:"original"`
+	Replayed Recording `json:"replayed"`
+}
+
+// ReplayHandler returns a handler which replays the recording in the body of POST requests, and responds
+// with a ReplayResult. It returns nil if replay is disabled.
+func (r *Recorder) ReplayHandler() http.Handler {
+	if r == nil || !r.replayEnabled {
+		return nil
+	}
+	return http.HandlerFunc(r.serveReplay)
+}
+
+func (r *Recorder) serveReplay(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "Recordings must be POSTed to be replayed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var recording Recording
+	if err := json.NewDecoder(req.Body).Decode(&recording); err != nil {
+		http.Error(w, fmt.Sprintf("Invalid recording: %v", err), http.StatusBadRequest)
+		return
+	}
+	handle, ok := r.endpoints[recording.Endpoint]
+	if !ok {
+		http.Error(w, fmt.Sprintf("Can't replay requests to %s", recording.Endpoint), http.StatusBadRequest)
+		return
+	}
+
+	replayReq, err := newReplayRequest(req.Context(), &recording)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid recording: %v", err), http.StatusBadRequest)
+		return
+	}
+	session := newReplaySession(&recording)
+	replayReq = replayReq.WithContext(WithSession(replayReq.Context(), session))
+	handle(&discardResponseWriter{header: make(http.Header)}, replayReq, nil)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(ReplayResult{Original: recording, Replayed: session.Recording()})
+}
+
+func newReplayRequest(ctx context.Context, recording *Recording) (*http.Request, error) {
+	method := recording.Method
+	if method == "" {
+		method = http.MethodPost
+	}
+	target := url.URL{Path: recording.Endpoint, RawQuery: recording.Query}
+
+	var body io.Reader
+	if len(recording.Body) > 0 {
+		body = bytes.NewReader(originalBody(recording.Body))
+	}
+	req, err := http.NewRequestWithContext(ctx, method, target.String(), body)
+	if err != nil {
+		return nil, err
+	}
+	req.RequestURI = target.RequestURI()
+	if recording.Header != nil {
+		req.Header = recording.Header.Clone()
+	}
+	return req, nil
+}
+
+// originalBody undoes rawOrString, returning the body as it was received.
+func originalBody(body json.RawMessage) []byte {
+	var text string
+	if body[0] == '"' && json.Unmarshal(body, &text) == nil {
+		return []byte(text)
+	}
+	return body
+}
+
+// discardResponseWriter is written the response of a replayed request, which the session records.
+type discardResponseWriter struct {
+	header http.Header
+}
+
+func (w *discardResponseWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *discardResponseWriter) Write(b []byte) (int, error) {
+	return len(b), nil
+}
+
+func (w *discardResponseWriter) WriteHeader(int) {}
@@ -0,0 +1,150 @@
+package auctionrecording
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// Session records what an auction does. If the auction is a replay, the session also serves the account,
+// stored data and bidder responses of the recording being replayed. A nil session does nothing.
+type Session struct {
+	lock      sync.Mutex
+	recording Recording
+
+	replay          *Recording
+	usedBidderCalls []bool
+}
+
+func newSession() *Session {
+	return &Session{}
+}
+
+func newReplaySession(replay *Recording) *Session {
+	return &Session{
+		replay:          replay,
+		usedBidderCalls: make([]bool, len(replay.BidderCalls)),
+	}
+}
+
+type sessionKey struct{}
+
+// WithSession returns a context which carries the session to the stages of the auction run under it.
+func WithSession(ctx context.Context, session *Session) context.Context {
+	if session == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, sessionKey{}, session)
+}
+
+// FromContext returns the session of the auction, or nil if it isn't being recorded or replayed.
+func FromContext(ctx context.Context) *Session {
+	session, _ := ctx.Value(sessionKey{}).(*Session)
+	return session
+}
+
+// Recording returns a copy of what has been recorded so far.
+func (s *Session) Recording() Recording {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	recording := s.recording
+	recording.StoredData = append([]StoredData(nil), s.recording.StoredData...)
+	recording.BidderCalls = append([]BidderCall(nil), s.recording.BidderCalls...)
+	return recording
+}
+
+func (s *Session) isReplay() bool {
+	return s != nil && s.replay != nil
+}
+
+func (s *Session) update(update func(recording *Recording)) {
+	if s == nil {
+		return
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	update(&s.recording)
+}
+
+func (s *Session) recordAccount(accountID string, account json.RawMessage) {
+	s.update(func(recording *Recording) {
+		recording.AccountID = accountID
+		recording.Account = account
+	})
+}
+
+func (s *Session) recordStoredData(dataType string, data map[string]json.RawMessage) {
+	s.update(func(recording *Recording) {
+		for id, value := range data {
+			if value == nil || hasStoredData(recording.StoredData, dataType, id) {
+				continue
+			}
+			recording.StoredData = append(recording.StoredData, newStoredData(dataType, id, value))
+		}
+	})
+}
+
+func (s *Session) recordBidderCall(call BidderCall) {
+	s.update(func(recording *Recording) {
+		recording.BidderCalls = append(recording.BidderCalls, call)
+	})
+}
+
+func hasStoredData(storedData []StoredData, dataType, id string) bool {
+	for _, data := range storedData {
+		if data.Type == dataType && data.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// replayedAccount returns the recorded account, if the session is a replay which has one.
+func (s *Session) replayedAccount() (json.RawMessage, bool) {
+	if !s.isReplay() || s.replay.Account == nil {
+		return nil, false
+	}
+	return s.replay.Account, true
+}
+
+// replayedStoredData returns the recorded data of the given IDs, along with the IDs which weren't recorded.
+func (s *Session) replayedStoredData(dataType string, ids []string) (data map[string]json.RawMessage, missing []string) {
+	if !s.isReplay() {
+		return nil, ids
+	}
+	data = make(map[string]json.RawMessage, len(ids))
+	for _, id := range ids {
+		found := false
+		for _, stored := range s.replay.StoredData {
+			if stored.Type == dataType && stored.ID == id {
+				data[id] = stored.Data
+				found = true
+				break
+			}
+		}
+		if !found {
+			missing = append(missing, id)
+		}
+	}
+	return data, missing
+}
+
+var errNoRecordedCall = errors.New("no recorded call")
+
+// replayedBidderCall returns the first recorded call to the same method and URI which hasn't been
+// replayed yet.
+func (s *Session) replayedBidderCall(method, uri string) (BidderCall, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	for i, call := range s.replay.BidderCalls {
+		if !s.usedBidderCalls[i] && call.Method == method && call.URI == uri {
+			s.usedBidderCalls[i] = true
+			return call, nil
+		}
+	}
+	return BidderCall{}, fmt.Errorf("%w to %s %s", errNoRecordedCall, method, uri)
+}
@@ -0,0 +1,59 @@
+package auctionrecording
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/prebid/prebid-server/v2/config"
+)
+
+// Sink stores recordings. Write is only called from one goroutine at a time.
+type Sink interface {
+	Write(recording *Recording) error
+	Close() error
+}
+
+// NewSink returns the configured sink.
+func NewSink(cfg config.AuctionRecordingSink) (Sink, error) {
+	switch cfg.Type {
+	case config.AuctionRecordingSinkFile:
+		return newFileSink(cfg.File.Path)
+	default:
+		return nil, fmt.Errorf("unknown auction recording sink type: %s", cfg.Type)
+	}
+}
+
+// fileSink appends recordings to a file as JSON, one per line.
+type fileSink struct {
+	lock sync.Mutex
+	file *os.File
+}
+
+func newFileSink(path string) (*fileSink, error) {
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, err
+	}
+	return &fileSink{file: file}, nil
+}
+
+func (s *fileSink) Write(recording *Recording) error {
+	line, err := json.Marshal(recording)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	_, err = s.file.Write(line)
+	return err
+}
+
+func (s *fileSink) Close() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.file.Close()
+}
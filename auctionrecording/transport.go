@@ -0,0 +1,106 @@
+package auctionrecording
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// BidderClient returns a client for the bidder adapters which records the calls made for recorded
+// auctions, and answers the calls made for replayed auctions with the responses in their recording.
+func (r *Recorder) BidderClient(client *http.Client) *http.Client {
+	if r == nil {
+		return client
+	}
+	next := client.Transport
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	recordingClient := *client
+	recordingClient.Transport = &transport{next: next}
+	return &recordingClient
+}
+
+type transport struct {
+	next http.RoundTripper
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	session := FromContext(req.Context())
+	if session == nil {
+		return t.next.RoundTrip(req)
+	}
+
+	call := BidderCall{
+		Method:        req.Method,
+		URI:           req.URL.String(),
+		RequestHeader: req.Header.Clone(),
+	}
+	if req.Body != nil {
+		body, err := io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		call.RequestBody = string(body)
+		req = req.Clone(req.Context())
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	if session.isReplay() {
+		return t.replay(session, call, req)
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if err == nil {
+		var body []byte
+		body, err = io.ReadAll(resp.Body)
+		resp.Body.Close()
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+		call.Status = resp.StatusCode
+		call.ResponseHeader = resp.Header.Clone()
+		call.ResponseBody = string(body)
+	}
+	if err != nil {
+		call.Error = err.Error()
+		resp = nil
+	}
+	session.recordBidderCall(call)
+	return resp, err
+}
+
+func (t *transport) replay(session *Session, call BidderCall, req *http.Request) (*http.Response, error) {
+	recorded, err := session.replayedBidderCall(call.Method, call.URI)
+	if err == nil && recorded.Error != "" {
+		err = errors.New(recorded.Error)
+	}
+	if err != nil {
+		call.Error = err.Error()
+		session.recordBidderCall(call)
+		return nil, err
+	}
+
+	call.Status = recorded.Status
+	call.ResponseHeader = recorded.ResponseHeader
+	call.ResponseBody = recorded.ResponseBody
+	session.recordBidderCall(call)
+
+	header := recorded.ResponseHeader.Clone()
+	if header == nil {
+		header = make(http.Header)
+	}
+	return &http.Response{
+		Status:        strconv.Itoa(recorded.Status) + " " + http.StatusText(recorded.Status),
+		StatusCode:    recorded.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(strings.NewReader(recorded.ResponseBody)),
+		ContentLength: int64(len(recorded.ResponseBody)),
+		Request:       req,
+	}, nil
+}
@@ -0,0 +1,47 @@
+package auctionrecording
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type failingTripper struct{}
+
+func (failingTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	return nil, errors.New("connection refused")
+}
+
+func TestTransportRecordsFailedCalls(t *testing.T) {
+	recorder := &Recorder{}
+	client := recorder.BidderClient(&http.Client{Transport: failingTripper{}})
+
+	session := newSession()
+	req, err := http.NewRequestWithContext(WithSession(context.Background(), session), http.MethodGet, "http://bidder.com/bid?id=1", nil)
+	require.NoError(t, err)
+	_, err = client.Do(req)
+	assert.Error(t, err)
+
+	calls := session.Recording().BidderCalls
+	require.Len(t, calls, 1)
+	assert.Equal(t, BidderCall{Method: http.MethodGet, URI: "http://bidder.com/bid?id=1", RequestHeader: http.Header{}, Error: "connection refused"}, calls[0])
+
+	// The failure should be replayed too
+	replay := newReplaySession(&Recording{BidderCalls: calls})
+	req, err = http.NewRequestWithContext(WithSession(context.Background(), replay), http.MethodGet, "http://bidder.com/bid?id=1", nil)
+	require.NoError(t, err)
+	_, err = recorder.BidderClient(&http.Client{}).Do(req)
+	assert.ErrorContains(t, err, "connection refused")
+}
+
+func TestTransportWithoutSession(t *testing.T) {
+	client := (&Recorder{}).BidderClient(&http.Client{Transport: failingTripper{}})
+	req, err := http.NewRequest(http.MethodGet, "http://bidder.com/bid", nil)
+	require.NoError(t, err)
+	_, err = client.Do(req)
+	assert.ErrorContains(t, err, "connection refused", "calls for auctions which aren't recorded should be sent as they are")
+}
@@ -0,0 +1,91 @@
+package biddermaintenance
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/prebid/prebid-server/v2/logger"
+	"github.com/prebid/prebid-server/v2/openrtb_ext"
+	"github.com/prebid/prebid-server/v2/util/httputil"
+	"github.com/prebid/prebid-server/v2/util/jsonutil"
+)
+
+// maxRequestSize limits the body of requests which turn a kill switch on.
+const maxRequestSize = 16 * 1024
+
+// turnOnRequest is the body of a PUT request, which turns a kill switch on.
+type turnOnRequest struct {
+	Bidder string `json:"bidder"`
+	Reason string `json:"reason"`
+}
+
+type turnOffResponse struct {
+	TurnedOff bool `json:"turned_off"`
+}
+
+// Handler returns a handler which turns the kill switches on and off, or nil if kill switches are disabled.
+// Requests must have one of the configured tokens as their bearer token.
+//
+// GET lists the kill switches which are on. PUT turns on the kill switch of the bidder in the body, and
+// DELETE turns off the kill switch of the bidder in the query string.
+func (m *Maintenance) Handler() http.Handler {
+	if m == nil || len(m.tokens) == 0 {
+		return nil
+	}
+	return http.HandlerFunc(m.serve)
+}
+
+func (m *Maintenance) serve(w http.ResponseWriter, req *http.Request) {
+	if !httputil.HasBearerToken(req, m.tokens) {
+		w.Header().Set("WWW-Authenticate", "Bearer")
+		http.Error(w, "A valid bearer token is required", http.StatusUnauthorized)
+		return
+	}
+
+	switch req.Method {
+	case http.MethodGet:
+		httputil.WriteJSON(w, m.KillSwitches())
+	case http.MethodPut:
+		m.serveTurnOn(w, req)
+	case http.MethodDelete:
+		bidder := req.URL.Query().Get("bidder")
+		if bidder == "" {
+			http.Error(w, "The bidder of the kill switch to turn off is required", http.StatusBadRequest)
+			return
+		}
+		turnedOff := m.TurnOff(bidder)
+		if turnedOff {
+			logger.Warningf("Kill switch of bidder %s turned off", bidder)
+		}
+		httputil.WriteJSON(w, turnOffResponse{TurnedOff: turnedOff})
+	default:
+		w.Header().Set("Allow", "GET, PUT, DELETE")
+		http.Error(w, "Kill switches are managed with GET, PUT and DELETE", http.StatusMethodNotAllowed)
+	}
+}
+
+func (m *Maintenance) serveTurnOn(w http.ResponseWriter, req *http.Request) {
+	body, err := io.ReadAll(io.LimitReader(req.Body, maxRequestSize))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid request: %v", err), http.StatusBadRequest)
+		return
+	}
+	var request turnOnRequest
+	if err := jsonutil.UnmarshalValid(body, &request); err != nil {
+		http.Error(w, fmt.Sprintf("Invalid request: %v", err), http.StatusBadRequest)
+		return
+	}
+	if request.Bidder == "" {
+		http.Error(w, "Invalid request: bidder is required", http.StatusBadRequest)
+		return
+	}
+	if _, ok := openrtb_ext.NormalizeBidderName(request.Bidder); !ok {
+		http.Error(w, fmt.Sprintf("Invalid request: unknown bidder %s", request.Bidder), http.StatusBadRequest)
+		return
+	}
+
+	killSwitch := m.TurnOn(request.Bidder, request.Reason)
+	logger.Warningf("Kill switch of bidder %s turned on: reason=%q", killSwitch.Bidder, killSwitch.Reason)
+	httputil.WriteJSON(w, killSwitch)
+}
@@ -0,0 +1,135 @@
+package biddermaintenance
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prebid/prebid-server/v2/util/timeutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler(t *testing.T) {
+	clock := timeutil.NewFakeTime(time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC))
+	handler := newTestMaintenance(clock).Handler()
+
+	serve := func(method, target, authorization, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		if authorization != "" {
+			req.Header.Set("Authorization", authorization)
+		}
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, req)
+		return recorder
+	}
+
+	tests := []struct {
+		description    string
+		method         string
+		target         string
+		authorization  string
+		body           string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			description:    "no-token",
+			method:         http.MethodGet,
+			target:         "/bidders/kill_switches",
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			description:    "wrong-token",
+			method:         http.MethodPut,
+			target:         "/bidders/kill_switches",
+			authorization:  "Bearer other",
+			body:           `{"bidder":"appnexus"}`,
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			description:    "turn-on",
+			method:         http.MethodPut,
+			target:         "/bidders/kill_switches",
+			authorization:  "Bearer token",
+			body:           `{"bidder":"appnexus","reason":"incident"}`,
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"bidder":"appnexus","reason":"incident","since":"2024-05-01T12:00:00Z"}`,
+		},
+		{
+			description:    "turn-on-unknown-bidder",
+			method:         http.MethodPut,
+			target:         "/bidders/kill_switches",
+			authorization:  "Bearer token",
+			body:           `{"bidder":"unknown"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			description:    "turn-on-without-bidder",
+			method:         http.MethodPut,
+			target:         "/bidders/kill_switches",
+			authorization:  "Bearer token",
+			body:           `{"reason":"incident"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			description:    "turn-on-malformed",
+			method:         http.MethodPut,
+			target:         "/bidders/kill_switches",
+			authorization:  "Bearer token",
+			body:           `{"bidder":`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			description:    "list",
+			method:         http.MethodGet,
+			target:         "/bidders/kill_switches",
+			authorization:  "Bearer token",
+			expectedStatus: http.StatusOK,
+			expectedBody:   `[{"bidder":"appnexus","reason":"incident","since":"2024-05-01T12:00:00Z"}]`,
+		},
+		{
+			description:    "turn-off-without-bidder",
+			method:         http.MethodDelete,
+			target:         "/bidders/kill_switches",
+			authorization:  "Bearer token",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			description:    "turn-off",
+			method:         http.MethodDelete,
+			target:         "/bidders/kill_switches?bidder=appnexus",
+			authorization:  "Bearer token",
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"turned_off":true}`,
+		},
+		{
+			description:    "list-after-turn-off",
+			method:         http.MethodGet,
+			target:         "/bidders/kill_switches",
+			authorization:  "Bearer token",
+			expectedStatus: http.StatusOK,
+			expectedBody:   `[]`,
+		},
+		{
+			description:    "wrong-method",
+			method:         http.MethodPost,
+			target:         "/bidders/kill_switches",
+			authorization:  "Bearer token",
+			expectedStatus: http.StatusMethodNotAllowed,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			recorder := serve(test.method, test.target, test.authorization, test.body)
+
+			require.Equal(t, test.expectedStatus, recorder.Code, recorder.Body.String())
+			if test.expectedBody != "" {
+				assert.JSONEq(t, test.expectedBody, recorder.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,125 @@
+// Package biddermaintenance leaves bidders out of auctions during their maintenance windows, which the host
+// and accounts configure, and while their kill switch is on. Kill switches are turned on and off on the admin
+// server, so that a bidder can be taken out of auctions at once during a partner's incident, without shipping
+// a config change. They're kept in memory, so they don't survive a restart.
+package biddermaintenance
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/metrics"
+	"github.com/prebid/prebid-server/v2/util/timeutil"
+)
+
+// KillSwitch leaves a bidder out of every auction until it's turned off.
+type KillSwitch struct {
+	Bidder string `json:"bidder"`
+	// Reason is a note of why the kill switch was turned on, such as the partner's incident ticket
+	Reason string    `json:"reason,omitempty"`
+	Since  time.Time `json:"since"`
+}
+
+// Maintenance holds the host's maintenance windows and the kill switches which are on. A nil *Maintenance
+// is valid. It has neither, but still applies the windows of accounts.
+type Maintenance struct {
+	tokens [][]byte
+	clock  timeutil.Time
+
+	mutex        sync.RWMutex
+	windows      []config.BidderMaintenanceWindow
+	killSwitches map[string]KillSwitch
+}
+
+// New builds the Maintenance, or returns nil if the host has no maintenance windows and kill switches are
+// disabled.
+func New(cfg config.BidderMaintenance) *Maintenance {
+	if len(cfg.Windows) == 0 && !cfg.KillSwitches.Enabled {
+		return nil
+	}
+	m := &Maintenance{
+		windows:      cfg.Windows,
+		clock:        &timeutil.RealTime{},
+		killSwitches: make(map[string]KillSwitch),
+	}
+	for _, token := range cfg.KillSwitches.Tokens {
+		m.tokens = append(m.tokens, []byte(token))
+	}
+	return m
+}
+
+// Check reports whether the bidder is in maintenance, because its kill switch is on or one of the host's or
+// the account's windows is in effect, and why. bidder may be an alias or the name of the bidder it's an
+// alias of, and matches either in any case.
+func (m *Maintenance) Check(bidder string, accountWindows []config.BidderMaintenanceWindow) (metrics.BidderMaintenance, bool) {
+	now := time.Now()
+	if m != nil {
+		now = m.clock.Now()
+		m.mutex.RLock()
+		_, killed := m.killSwitches[strings.ToLower(bidder)]
+		windows := m.windows
+		m.mutex.RUnlock()
+		if killed {
+			return metrics.BidderMaintenanceKillSwitch, true
+		}
+		if inWindow(bidder, windows, now) {
+			return metrics.BidderMaintenanceWindow, true
+		}
+	}
+	if inWindow(bidder, accountWindows, now) {
+		return metrics.BidderMaintenanceWindow, true
+	}
+	return "", false
+}
+
+func inWindow(bidder string, windows []config.BidderMaintenanceWindow, now time.Time) bool {
+	for _, window := range windows {
+		if strings.EqualFold(window.Bidder, bidder) && window.Active(now) {
+			return true
+		}
+	}
+	return false
+}
+
+// SetWindows replaces the host's maintenance windows, when the host config changes while the server runs.
+func (m *Maintenance) SetWindows(windows []config.BidderMaintenanceWindow) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.windows = windows
+}
+
+// TurnOn turns the bidder's kill switch on, in place of the one which is on already, if there is one.
+func (m *Maintenance) TurnOn(bidder, reason string) KillSwitch {
+	killSwitch := KillSwitch{Bidder: bidder, Reason: reason, Since: m.clock.Now()}
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.killSwitches[strings.ToLower(bidder)] = killSwitch
+	return killSwitch
+}
+
+// TurnOff turns the bidder's kill switch off. It reports whether it was on.
+func (m *Maintenance) TurnOff(bidder string) bool {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	key := strings.ToLower(bidder)
+	_, on := m.killSwitches[key]
+	delete(m.killSwitches, key)
+	return on
+}
+
+// KillSwitches returns the kill switches which are on, by bidder.
+func (m *Maintenance) KillSwitches() []KillSwitch {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	killSwitches := make([]KillSwitch, 0, len(m.killSwitches))
+	for _, killSwitch := range m.killSwitches {
+		killSwitches = append(killSwitches, killSwitch)
+	}
+	sort.Slice(killSwitches, func(i, j int) bool {
+		return strings.ToLower(killSwitches[i].Bidder) < strings.ToLower(killSwitches[j].Bidder)
+	})
+	return killSwitches
+}
@@ -0,0 +1,111 @@
+package biddermaintenance
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/metrics"
+	"github.com/prebid/prebid-server/v2/util/timeutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestMaintenance(clock timeutil.Time, windows ...config.BidderMaintenanceWindow) *Maintenance {
+	m := New(config.BidderMaintenance{Windows: windows, KillSwitches: config.BidderKillSwitches{Enabled: true, Tokens: []string{"token"}}})
+	m.clock = clock
+	return m
+}
+
+func TestCheck(t *testing.T) {
+	clock := timeutil.NewFakeTime(time.Date(2024, 5, 1, 3, 0, 0, 0, time.UTC))
+	m := newTestMaintenance(clock, config.BidderMaintenanceWindow{Bidder: "appnexus", Start: "2024-05-01T02:00:00Z", End: "2024-05-01T04:00:00Z"})
+	m.TurnOn("Rubicon", "incident")
+	accountWindows := []config.BidderMaintenanceWindow{
+		{Bidder: "openx", Start: "2024-05-01T02:30:00Z", End: "2024-05-01T03:30:00Z"},
+		{Bidder: "pubmatic", Start: "2024-05-01T04:00:00Z", End: "2024-05-01T05:00:00Z"},
+	}
+
+	testCases := []struct {
+		name           string
+		bidder         string
+		expectedReason metrics.BidderMaintenance
+		expectedOK     bool
+	}{
+		{
+			name:           "host-window",
+			bidder:         "AppNexus",
+			expectedReason: metrics.BidderMaintenanceWindow,
+			expectedOK:     true,
+		},
+		{
+			name:           "kill-switch",
+			bidder:         "rubicon",
+			expectedReason: metrics.BidderMaintenanceKillSwitch,
+			expectedOK:     true,
+		},
+		{
+			name:           "account-window",
+			bidder:         "openx",
+			expectedReason: metrics.BidderMaintenanceWindow,
+			expectedOK:     true,
+		},
+		{
+			name:   "account-window-not-started",
+			bidder: "pubmatic",
+		},
+		{
+			name:   "no-maintenance",
+			bidder: "ix",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			reason, ok := m.Check(test.bidder, accountWindows)
+			assert.Equal(t, test.expectedReason, reason)
+			assert.Equal(t, test.expectedOK, ok)
+		})
+	}
+}
+
+func TestCheckNil(t *testing.T) {
+	var m *Maintenance
+	window := config.BidderMaintenanceWindow{Bidder: "appnexus", Start: "2000-01-01T00:00:00Z", End: "9999-01-01T00:00:00Z"}
+
+	reason, ok := m.Check("appnexus", []config.BidderMaintenanceWindow{window})
+	assert.Equal(t, metrics.BidderMaintenanceWindow, reason)
+	assert.True(t, ok)
+
+	_, ok = m.Check("appnexus", nil)
+	assert.False(t, ok)
+}
+
+func TestKillSwitches(t *testing.T) {
+	clock := timeutil.NewFakeTime(time.Date(2024, 5, 1, 3, 0, 0, 0, time.UTC))
+	m := newTestMaintenance(clock)
+	m.TurnOn("rubicon", "")
+	m.TurnOn("appnexus", "incident")
+	m.TurnOn("AppNexus", "incident 2")
+
+	assert.Equal(t, []KillSwitch{
+		{Bidder: "AppNexus", Reason: "incident 2", Since: clock.Now()},
+		{Bidder: "rubicon", Since: clock.Now()},
+	}, m.KillSwitches())
+
+	assert.True(t, m.TurnOff("appnexus"))
+	assert.False(t, m.TurnOff("appnexus"))
+	assert.Equal(t, []KillSwitch{{Bidder: "rubicon", Since: clock.Now()}}, m.KillSwitches())
+}
+
+func TestSetWindows(t *testing.T) {
+	clock := timeutil.NewFakeTime(time.Date(2024, 5, 1, 3, 0, 0, 0, time.UTC))
+	m := newTestMaintenance(clock, config.BidderMaintenanceWindow{Bidder: "appnexus", Start: "2024-05-01T02:00:00Z", End: "2024-05-01T04:00:00Z"})
+
+	m.SetWindows([]config.BidderMaintenanceWindow{{Bidder: "rubicon", Start: "2024-05-01T02:00:00Z", End: "2024-05-01T04:00:00Z"}})
+
+	_, ok := m.Check("appnexus", nil)
+	assert.False(t, ok)
+	reason, ok := m.Check("rubicon", nil)
+	assert.Equal(t, metrics.BidderMaintenanceWindow, reason)
+	assert.True(t, ok)
+}
@@ -0,0 +1,66 @@
+package bidlandscape
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/prebid/prebid-server/v2/util/httputil"
+)
+
+// Handler returns a handler which responds to GET requests with a Report, or nil if the bid landscape is
+// disabled. Requests must have one of the configured tokens as their bearer token.
+//
+// The query string may filter the statistics by bidder, account and size, and group them with group_by,
+// a comma separated list of dimensions. They're grouped by bidder by default.
+func (l *Landscape) Handler() http.Handler {
+	if l == nil {
+		return nil
+	}
+	return http.HandlerFunc(l.serveReport)
+}
+
+func (l *Landscape) serveReport(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		http.Error(w, "The bid landscape must be fetched with GET", http.StatusMethodNotAllowed)
+		return
+	}
+	if !httputil.HasBearerToken(req, l.tokens) {
+		w.Header().Set("WWW-Authenticate", "Bearer")
+		http.Error(w, "A valid bearer token is required", http.StatusUnauthorized)
+		return
+	}
+
+	params := req.URL.Query()
+	query := Query{
+		Bidder:  params.Get("bidder"),
+		Account: params.Get("account"),
+		Size:    params.Get("size"),
+		GroupBy: []Dimension{DimensionBidder},
+	}
+	if groupBy := params.Get("group_by"); groupBy != "" {
+		dimensions, err := parseDimensions(groupBy)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		query.GroupBy = dimensions
+	}
+
+	httputil.WriteJSON(w, l.Report(query))
+}
+
+func parseDimensions(groupBy string) ([]Dimension, error) {
+	var dimensions []Dimension
+	for _, name := range strings.Split(groupBy, ",") {
+		dimension := Dimension(strings.TrimSpace(name))
+		switch dimension {
+		case DimensionBidder, DimensionAccount, DimensionSize:
+			dimensions = append(dimensions, dimension)
+		default:
+			return nil, fmt.Errorf("Can't group by %q. Reports may be grouped by bidder, account and size", name)
+		}
+	}
+	return dimensions, nil
+}
@@ -0,0 +1,109 @@
+package bidlandscape
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prebid/prebid-server/v2/util/timeutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler(t *testing.T) {
+	clock := timeutil.NewFakeTime(time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC))
+	l := newTestLandscape(clock)
+	l.tokens = append(l.tokens, []byte("other-token"))
+	l.Record(Auction{
+		Account:  "acct",
+		Requests: []Request{{Bidder: "appnexus", Sizes: []string{"300x250", "728x90"}}},
+		Bids:     []Bid{{Bidder: "appnexus", Size: "300x250", Price: 2, Won: true}},
+	})
+
+	tests := []struct {
+		description    string
+		method         string
+		target         string
+		authorization  string
+		expectedStatus int
+		expectedKeys   []Key
+	}{
+		{
+			description:    "grouped-by-bidder-by-default",
+			method:         http.MethodGet,
+			target:         "/bid_landscape",
+			authorization:  "Bearer token",
+			expectedStatus: http.StatusOK,
+			expectedKeys:   []Key{{Bidder: "appnexus"}},
+		},
+		{
+			description:    "filtered-and-grouped",
+			method:         http.MethodGet,
+			target:         "/bid_landscape?account=acct&group_by=account,size",
+			authorization:  "Bearer other-token",
+			expectedStatus: http.StatusOK,
+			expectedKeys:   []Key{{Account: "acct", Size: "300x250"}, {Account: "acct", Size: "728x90"}},
+		},
+		{
+			description:    "unknown-dimension",
+			method:         http.MethodGet,
+			target:         "/bid_landscape?group_by=bidder,deal",
+			authorization:  "Bearer token",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			description:    "no-token",
+			method:         http.MethodGet,
+			target:         "/bid_landscape",
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			description:    "wrong-token",
+			method:         http.MethodGet,
+			target:         "/bid_landscape",
+			authorization:  "Bearer tokens",
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			description:    "not-a-bearer-token",
+			method:         http.MethodGet,
+			target:         "/bid_landscape",
+			authorization:  "Basic token",
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			description:    "post",
+			method:         http.MethodPost,
+			target:         "/bid_landscape",
+			authorization:  "Bearer token",
+			expectedStatus: http.StatusMethodNotAllowed,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			req := httptest.NewRequest(test.method, test.target, nil)
+			if test.authorization != "" {
+				req.Header.Set("Authorization", test.authorization)
+			}
+			recorder := httptest.NewRecorder()
+			l.Handler().ServeHTTP(recorder, req)
+
+			require.Equal(t, test.expectedStatus, recorder.Code, recorder.Body.String())
+			if test.expectedStatus != http.StatusOK {
+				return
+			}
+			assert.Equal(t, "application/json", recorder.Header().Get("Content-Type"))
+			var report Report
+			require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &report))
+			keys := make([]Key, 0, len(report.Rows))
+			for _, row := range report.Rows {
+				keys = append(keys, row.Key)
+			}
+			assert.ElementsMatch(t, test.expectedKeys, keys)
+		})
+	}
+}
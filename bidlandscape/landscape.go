@@ -0,0 +1,151 @@
+// Package bidlandscape keeps statistics of the bids of the last hour or so in memory: how often each
+// bidder bids and wins, and at what prices, by account and size. Yield teams query them on the admin
+// server to tune floors and demand, rather than waiting for the analytics pipeline to catch up.
+package bidlandscape
+
+import (
+	"sync"
+
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/util/timeutil"
+)
+
+// Key is the bidder, account and size which the statistics are kept by. Dimensions which a report isn't
+// grouped by are left empty.
+type Key struct {
+	Bidder  string `json:"bidder,omitempty"`
+	Account string `json:"account,omitempty"`
+	Size    string `json:"size,omitempty"`
+}
+
+// Auction is what's recorded of an auction: the requests sent to each bidder, and the bids they returned.
+type Auction struct {
+	Account  string
+	Requests []Request
+	Bids     []Bid
+}
+
+// Request is an imp sent to a bidder. It counts as a request for each of the sizes the imp takes.
+type Request struct {
+	Bidder string
+	Sizes  []string
+}
+
+// Bid is a bid returned by a bidder, with its price in USD CPM.
+type Bid struct {
+	Bidder string
+	Size   string
+	Price  float64
+	// Won is set for the bid with the highest price of its imp
+	Won bool
+}
+
+// Landscape keeps the statistics by the minute, in a ring of slots which covers the window. A nil
+// *Landscape is valid and records nothing.
+type Landscape struct {
+	priceBuckets []float64
+	maxKeys      int
+	tokens       [][]byte
+	clock        timeutil.Time
+
+	mutex sync.Mutex
+	slots []slot
+}
+
+// slot has the statistics of one minute.
+type slot struct {
+	minute int64
+	stats  map[Key]*stats
+}
+
+type stats struct {
+	requests int64
+	bids     int64
+	wins     int64
+	priceSum float64
+	// prices counts the bids in each price bucket, with one more bucket for the prices above the last bound
+	prices []int64
+}
+
+// New builds a Landscape, or returns nil if the bid landscape is disabled.
+func New(cfg config.BidLandscape) *Landscape {
+	if !cfg.Enabled {
+		return nil
+	}
+	l := &Landscape{
+		priceBuckets: cfg.PriceBuckets,
+		maxKeys:      cfg.MaxKeys,
+		clock:        &timeutil.RealTime{},
+		slots:        make([]slot, cfg.WindowMinutes),
+	}
+	for _, token := range cfg.Tokens {
+		l.tokens = append(l.tokens, []byte(token))
+	}
+	return l
+}
+
+// Record adds an auction to the statistics of the current minute. Once the minute has as many keys as
+// the config allows, the requests and bids of new keys are left out.
+func (l *Landscape) Record(auction Auction) {
+	if l == nil || (len(auction.Requests) == 0 && len(auction.Bids) == 0) {
+		return
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	current := l.currentSlot()
+	for _, request := range auction.Requests {
+		for _, size := range request.Sizes {
+			if s := l.statsOf(current, Key{Bidder: request.Bidder, Account: auction.Account, Size: size}); s != nil {
+				s.requests++
+			}
+		}
+	}
+	for _, bid := range auction.Bids {
+		s := l.statsOf(current, Key{Bidder: bid.Bidder, Account: auction.Account, Size: bid.Size})
+		if s == nil {
+			continue
+		}
+		s.bids++
+		if bid.Won {
+			s.wins++
+		}
+		s.priceSum += bid.Price
+		s.prices[l.bucketOf(bid.Price)]++
+	}
+}
+
+// currentSlot returns the slot of the current minute, clearing it if it last held an earlier minute.
+func (l *Landscape) currentSlot() *slot {
+	minute := l.clock.Now().Unix() / 60
+	current := &l.slots[minute%int64(len(l.slots))]
+	if current.minute != minute || current.stats == nil {
+		current.minute = minute
+		current.stats = make(map[Key]*stats)
+	}
+	return current
+}
+
+// statsOf returns the statistics of the key in the slot, or nil if the slot is full.
+func (l *Landscape) statsOf(current *slot, key Key) *stats {
+	s, ok := current.stats[key]
+	if ok {
+		return s
+	}
+	if len(current.stats) >= l.maxKeys {
+		return nil
+	}
+	s = &stats{prices: make([]int64, len(l.priceBuckets)+1)}
+	current.stats[key] = s
+	return s
+}
+
+// bucketOf returns the index of the price bucket which the price falls in.
+func (l *Landscape) bucketOf(price float64) int {
+	for i, bound := range l.priceBuckets {
+		if price <= bound {
+			return i
+		}
+	}
+	return len(l.priceBuckets)
+}
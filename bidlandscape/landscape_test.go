@@ -0,0 +1,185 @@
+package bidlandscape
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/util/timeutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestLandscape(clock timeutil.Time) *Landscape {
+	l := New(config.BidLandscape{
+		Enabled:       true,
+		WindowMinutes: 3,
+		PriceBuckets:  []float64{1, 5},
+		MaxKeys:       10,
+		Tokens:        []string{"token"},
+	})
+	l.clock = clock
+	return l
+}
+
+func TestRecordNil(t *testing.T) {
+	var l *Landscape
+	assert.NotPanics(t, func() { l.Record(Auction{Account: "acct", Requests: []Request{{Bidder: "appnexus", Sizes: []string{"300x250"}}}}) })
+}
+
+func TestReport(t *testing.T) {
+	clock := timeutil.NewFakeTime(time.Date(2024, 5, 1, 12, 0, 30, 0, time.UTC))
+	l := newTestLandscape(clock)
+	l.Record(Auction{
+		Account: "acct",
+		Requests: []Request{
+			{Bidder: "appnexus", Sizes: []string{"300x250", "728x90"}},
+			{Bidder: "rubicon", Sizes: []string{"300x250"}},
+		},
+		Bids: []Bid{
+			{Bidder: "appnexus", Size: "300x250", Price: 0.5},
+			{Bidder: "appnexus", Size: "728x90", Price: 2},
+			{Bidder: "rubicon", Size: "300x250", Price: 6, Won: true},
+		},
+	})
+	l.Record(Auction{
+		Account:  "other",
+		Requests: []Request{{Bidder: "appnexus", Sizes: []string{"300x250"}}},
+		Bids:     []Bid{{Bidder: "appnexus", Size: "300x250", Price: 3, Won: true}},
+	})
+
+	report := l.Report(Query{GroupBy: []Dimension{DimensionBidder}})
+
+	one, five := 1.0, 5.0
+	assert.Equal(t, time.Date(2024, 5, 1, 11, 58, 0, 0, time.UTC), report.From)
+	assert.Equal(t, clock.Now(), report.To)
+	assert.Equal(t, []Row{
+		{
+			Key:      Key{Bidder: "appnexus"},
+			Requests: 3,
+			Bids:     3,
+			Wins:     1,
+			BidRate:  1,
+			WinRate:  1.0 / 3,
+			AvgPrice: 5.5 / 3,
+			Prices:   []PriceBucket{{Min: 0, Max: &one, Bids: 1}, {Min: 1, Max: &five, Bids: 2}, {Min: 5, Bids: 0}},
+		},
+		{
+			Key:      Key{Bidder: "rubicon"},
+			Requests: 1,
+			Bids:     1,
+			Wins:     1,
+			BidRate:  1,
+			WinRate:  1,
+			AvgPrice: 6,
+			Prices:   []PriceBucket{{Min: 0, Max: &one, Bids: 0}, {Min: 1, Max: &five, Bids: 0}, {Min: 5, Bids: 1}},
+		},
+	}, report.Rows)
+}
+
+func TestReportFiltersAndGroups(t *testing.T) {
+	clock := timeutil.NewFakeTime(time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC))
+	l := newTestLandscape(clock)
+	l.Record(Auction{
+		Account: "acct",
+		Requests: []Request{
+			{Bidder: "appnexus", Sizes: []string{"300x250", "728x90"}},
+			{Bidder: "rubicon", Sizes: []string{"300x250"}},
+		},
+	})
+	l.Record(Auction{
+		Account:  "other",
+		Requests: []Request{{Bidder: "appnexus", Sizes: []string{"300x250"}}},
+	})
+
+	tests := []struct {
+		description  string
+		query        Query
+		expectedRows map[Key]int64
+	}{
+		{
+			description:  "everything",
+			query:        Query{},
+			expectedRows: map[Key]int64{{}: 4},
+		},
+		{
+			description:  "by-size",
+			query:        Query{GroupBy: []Dimension{DimensionSize}},
+			expectedRows: map[Key]int64{{Size: "300x250"}: 3, {Size: "728x90"}: 1},
+		},
+		{
+			description:  "by-bidder-and-account",
+			query:        Query{GroupBy: []Dimension{DimensionBidder, DimensionAccount}},
+			expectedRows: map[Key]int64{{Bidder: "appnexus", Account: "acct"}: 2, {Bidder: "rubicon", Account: "acct"}: 1, {Bidder: "appnexus", Account: "other"}: 1},
+		},
+		{
+			description:  "filtered",
+			query:        Query{Bidder: "appnexus", Size: "300x250", GroupBy: []Dimension{DimensionAccount}},
+			expectedRows: map[Key]int64{{Account: "acct"}: 1, {Account: "other"}: 1},
+		},
+		{
+			description:  "nothing-matches",
+			query:        Query{Account: "unknown"},
+			expectedRows: map[Key]int64{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			rows := make(map[Key]int64)
+			for _, row := range l.Report(test.query).Rows {
+				rows[row.Key] = row.Requests
+			}
+			assert.Equal(t, test.expectedRows, rows)
+		})
+	}
+}
+
+func TestReportRollsTheWindow(t *testing.T) {
+	clock := timeutil.NewFakeTime(time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC))
+	l := newTestLandscape(clock)
+	record := func() {
+		l.Record(Auction{Account: "acct", Requests: []Request{{Bidder: "appnexus", Sizes: []string{"300x250"}}}})
+	}
+	requests := func() int64 {
+		rows := l.Report(Query{}).Rows
+		if len(rows) == 0 {
+			return 0
+		}
+		return rows[0].Requests
+	}
+
+	record()
+	clock.Advance(time.Minute)
+	record()
+	record()
+	assert.Equal(t, int64(3), requests())
+
+	clock.Advance(2 * time.Minute)
+	assert.Equal(t, int64(2), requests(), "the first minute has left the window")
+
+	clock.Advance(time.Minute)
+	record()
+	assert.Equal(t, int64(1), requests(), "the slot of the second minute is reused")
+
+	clock.Advance(time.Hour)
+	assert.Equal(t, int64(0), requests())
+}
+
+func TestRecordMaxKeys(t *testing.T) {
+	clock := timeutil.NewFakeTime(time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC))
+	l := newTestLandscape(clock)
+	l.maxKeys = 2
+	l.Record(Auction{
+		Account:  "acct",
+		Requests: []Request{{Bidder: "appnexus", Sizes: []string{"300x250", "728x90", "160x600"}}},
+		Bids:     []Bid{{Bidder: "appnexus", Size: "300x250", Price: 1}, {Bidder: "appnexus", Size: "160x600", Price: 1}},
+	})
+
+	rows := make(map[Key]Row)
+	for _, row := range l.Report(Query{GroupBy: []Dimension{DimensionSize}}).Rows {
+		rows[row.Key] = row
+	}
+	assert.Len(t, rows, 2)
+	assert.Equal(t, int64(1), rows[Key{Size: "300x250"}].Bids)
+	assert.Equal(t, int64(1), rows[Key{Size: "728x90"}].Requests)
+}
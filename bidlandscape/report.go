@@ -0,0 +1,165 @@
+package bidlandscape
+
+import (
+	"sort"
+	"time"
+)
+
+// Dimension is a dimension which reports may be grouped by.
+type Dimension string
+
+const (
+	DimensionBidder  Dimension = "bidder"
+	DimensionAccount Dimension = "account"
+	DimensionSize    Dimension = "size"
+)
+
+// Query picks the statistics of a report. Empty filters match everything.
+type Query struct {
+	Bidder  string
+	Account string
+	Size    string
+	GroupBy []Dimension
+}
+
+// Report has the statistics of the window, a row for each group.
+type Report struct {
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+	Rows []Row     `json:"rows"`
+}
+
+// Row has the statistics of a group.
+type Row struct {
+	Key
+	Requests int64 `json:"requests"`
+	Bids     int64 `json:"bids"`
+	Wins     int64 `json:"wins"`
+	// BidRate is the bids per request. It may be above 1 for bidders which return several bids per imp.
+	BidRate float64 `json:"bid_rate"`
+	// WinRate is the share of the bids which won their imp's auction.
+	WinRate  float64       `json:"win_rate"`
+	AvgPrice float64       `json:"avg_price"`
+	Prices   []PriceBucket `json:"prices"`
+}
+
+// PriceBucket counts the bids with prices above Min and up to Max, in USD CPM. The last bucket has no Max.
+type PriceBucket struct {
+	Min  float64  `json:"min"`
+	Max  *float64 `json:"max,omitempty"`
+	Bids int64    `json:"bids"`
+}
+
+// Report adds up the statistics of the window which match the query. The rows are sorted by requests,
+// most first.
+func (l *Landscape) Report(query Query) Report {
+	grouped := make(map[Dimension]bool, len(query.GroupBy))
+	for _, dimension := range query.GroupBy {
+		grouped[dimension] = true
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	now := l.clock.Now()
+	minute := now.Unix() / 60
+	oldest := minute - int64(len(l.slots)) + 1
+	totals := make(map[Key]*stats)
+	for _, s := range l.slots {
+		if s.minute < oldest || s.minute > minute {
+			continue
+		}
+		for key, keyStats := range s.stats {
+			if !query.matches(key) {
+				continue
+			}
+			groupKey := key.groupedBy(grouped)
+			total, ok := totals[groupKey]
+			if !ok {
+				total = &stats{prices: make([]int64, len(l.priceBuckets)+1)}
+				totals[groupKey] = total
+			}
+			total.add(keyStats)
+		}
+	}
+
+	report := Report{
+		From: time.Unix(oldest*60, 0).UTC(),
+		To:   now.UTC(),
+		Rows: make([]Row, 0, len(totals)),
+	}
+	for key, total := range totals {
+		report.Rows = append(report.Rows, l.row(key, total))
+	}
+	sort.Slice(report.Rows, func(i, j int) bool {
+		a, b := report.Rows[i], report.Rows[j]
+		if a.Requests != b.Requests {
+			return a.Requests > b.Requests
+		}
+		if a.Bidder != b.Bidder {
+			return a.Bidder < b.Bidder
+		}
+		if a.Account != b.Account {
+			return a.Account < b.Account
+		}
+		return a.Size < b.Size
+	})
+	return report
+}
+
+func (q Query) matches(key Key) bool {
+	return (q.Bidder == "" || q.Bidder == key.Bidder) &&
+		(q.Account == "" || q.Account == key.Account) &&
+		(q.Size == "" || q.Size == key.Size)
+}
+
+func (k Key) groupedBy(grouped map[Dimension]bool) Key {
+	if !grouped[DimensionBidder] {
+		k.Bidder = ""
+	}
+	if !grouped[DimensionAccount] {
+		k.Account = ""
+	}
+	if !grouped[DimensionSize] {
+		k.Size = ""
+	}
+	return k
+}
+
+func (s *stats) add(other *stats) {
+	s.requests += other.requests
+	s.bids += other.bids
+	s.wins += other.wins
+	s.priceSum += other.priceSum
+	for i, n := range other.prices {
+		s.prices[i] += n
+	}
+}
+
+func (l *Landscape) row(key Key, total *stats) Row {
+	row := Row{
+		Key:      key,
+		Requests: total.requests,
+		Bids:     total.bids,
+		Wins:     total.wins,
+		Prices:   make([]PriceBucket, len(total.prices)),
+	}
+	if total.requests > 0 {
+		row.BidRate = float64(total.bids) / float64(total.requests)
+	}
+	if total.bids > 0 {
+		row.WinRate = float64(total.wins) / float64(total.bids)
+		row.AvgPrice = total.priceSum / float64(total.bids)
+	}
+	for i, n := range total.prices {
+		bucket := PriceBucket{Bids: n}
+		if i > 0 {
+			bucket.Min = l.priceBuckets[i-1]
+		}
+		if i < len(l.priceBuckets) {
+			max := l.priceBuckets[i]
+			bucket.Max = &max
+		}
+		row.Prices[i] = bucket
+	}
+	return row
+}
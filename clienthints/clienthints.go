@@ -0,0 +1,174 @@
+// Package clienthints reads the User-Agent Client Hints (Sec-CH-UA* headers) browsers send in place of the
+// details they've stopped putting in their User-Agent strings, and turns them into an OpenRTB structured user
+// agent (device.sua).
+package clienthints
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/prebid/openrtb/v20/adcom1"
+	"github.com/prebid/openrtb/v20/openrtb2"
+)
+
+const (
+	headerUA              = "Sec-CH-UA"
+	headerFullVersionList = "Sec-CH-UA-Full-Version-List"
+	headerMobile          = "Sec-CH-UA-Mobile"
+	headerPlatform        = "Sec-CH-UA-Platform"
+	headerPlatformVersion = "Sec-CH-UA-Platform-Version"
+	headerModel           = "Sec-CH-UA-Model"
+	headerArch            = "Sec-CH-UA-Arch"
+	headerBitness         = "Sec-CH-UA-Bitness"
+)
+
+// highEntropyHeaders are only sent by browsers if the site asks for them.
+var highEntropyHeaders = []string{headerFullVersionList, headerPlatformVersion, headerModel, headerArch, headerBitness}
+
+// Parse returns the structured user agent described by the request's client hints, or nil if it has none.
+func Parse(header http.Header) *openrtb2.UserAgent {
+	browsers := parseBrandList(header.Get(headerFullVersionList))
+	if len(browsers) == 0 {
+		browsers = parseBrandList(header.Get(headerUA))
+	}
+	platform := parseString(header.Get(headerPlatform))
+	if len(browsers) == 0 && platform == "" {
+		return nil
+	}
+
+	sua := &openrtb2.UserAgent{
+		Browsers:     browsers,
+		Architecture: parseString(header.Get(headerArch)),
+		Bitness:      parseString(header.Get(headerBitness)),
+		Model:        parseString(header.Get(headerModel)),
+		Source:       adcom1.UASourceLowEntropy,
+	}
+	if platform != "" {
+		sua.Platform = &openrtb2.BrandVersion{Brand: platform, Version: splitVersion(parseString(header.Get(headerPlatformVersion)))}
+	}
+	switch strings.TrimSpace(header.Get(headerMobile)) {
+	case "?1":
+		mobile := int8(1)
+		sua.Mobile = &mobile
+	case "?0":
+		mobile := int8(0)
+		sua.Mobile = &mobile
+	}
+	for _, name := range highEntropyHeaders {
+		if header.Get(name) != "" {
+			sua.Source = adcom1.UASourceHighEntropy
+			break
+		}
+	}
+	return sua
+}
+
+// EnrichDevice sets device.sua, and the device's os, osv and model where they're missing, from a structured
+// user agent. A structured user agent the device already has is kept as it is.
+func EnrichDevice(device *openrtb2.Device, sua *openrtb2.UserAgent) {
+	if device.SUA != nil || sua == nil {
+		return
+	}
+	device.SUA = sua
+
+	if sua.Platform != nil && sua.Platform.Brand != "Unknown" {
+		if device.OS == "" {
+			device.OS = sua.Platform.Brand
+		}
+		if device.OSV == "" {
+			device.OSV = osVersion(sua.Platform)
+		}
+	}
+	if device.Model == "" {
+		device.Model = sua.Model
+	}
+}
+
+// osVersion returns the version of the platform as it's known to users. Windows is the exception: its client
+// hint is the version of the Universal API Contract, whose major version is 13 or more on Windows 11, and from
+// 1 to 10 on Windows 10. Earlier versions of Windows can't be told apart.
+func osVersion(platform *openrtb2.BrandVersion) string {
+	if len(platform.Version) == 0 {
+		return ""
+	}
+	if platform.Brand != "Windows" {
+		return strings.Join(platform.Version, ".")
+	}
+	major, err := strconv.Atoi(platform.Version[0])
+	switch {
+	case err != nil || major == 0:
+		return ""
+	case major >= 13:
+		return "11"
+	default:
+		return "10"
+	}
+}
+
+// parseBrandList parses a structured header list of brands and their versions, such as
+// "Chromium";v="118", "Google Chrome";v="118.0.5993.70", "Not=A?Brand";v="99".
+func parseBrandList(value string) []openrtb2.BrandVersion {
+	var brands []openrtb2.BrandVersion
+	for _, item := range splitOutsideQuotes(value, ',') {
+		params := splitOutsideQuotes(item, ';')
+		brand := parseString(params[0])
+		if brand == "" {
+			continue
+		}
+		brandVersion := openrtb2.BrandVersion{Brand: brand}
+		for _, param := range params[1:] {
+			if name, version, ok := strings.Cut(strings.TrimSpace(param), "="); ok && name == "v" {
+				brandVersion.Version = splitVersion(parseString(version))
+			}
+		}
+		brands = append(brands, brandVersion)
+	}
+	return brands
+}
+
+// splitOutsideQuotes splits a structured header value by a separator which isn't in a quoted string.
+func splitOutsideQuotes(value string, separator byte) []string {
+	var parts []string
+	inQuotes := false
+	start := 0
+	for i := 0; i < len(value); i++ {
+		switch {
+		case value[i] == '\\' && inQuotes:
+			i++
+		case value[i] == '"':
+			inQuotes = !inQuotes
+		case value[i] == separator && !inQuotes:
+			parts = append(parts, value[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, value[start:])
+}
+
+// parseString returns the contents of a structured header string, such as "Windows".
+func parseString(value string) string {
+	value = strings.TrimSpace(value)
+	if len(value) < 2 || value[0] != '"' || value[len(value)-1] != '"' {
+		return ""
+	}
+	value = value[1 : len(value)-1]
+	if !strings.Contains(value, `\`) {
+		return value
+	}
+	var unescaped strings.Builder
+	for i := 0; i < len(value); i++ {
+		if value[i] == '\\' && i+1 < len(value) {
+			i++
+		}
+		unescaped.WriteByte(value[i])
+	}
+	return unescaped.String()
+}
+
+func splitVersion(version string) []string {
+	if version == "" {
+		return nil
+	}
+	return strings.Split(version, ".")
+}
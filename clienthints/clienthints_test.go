@@ -0,0 +1,171 @@
+package clienthints
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/prebid/openrtb/v20/adcom1"
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	mobile := int8(1)
+	desktop := int8(0)
+
+	testCases := []struct {
+		description string
+		headers     map[string]string
+		expected    *openrtb2.UserAgent
+	}{
+		{
+			description: "no client hints",
+			headers:     map[string]string{"User-Agent": "Mozilla/5.0"},
+		},
+		{
+			description: "low entropy",
+			headers: map[string]string{
+				"Sec-CH-UA":          `"Chromium";v="118", "Google Chrome";v="118", "Not=A?Brand";v="99"`,
+				"Sec-CH-UA-Mobile":   "?0",
+				"Sec-CH-UA-Platform": `"Windows"`,
+			},
+			expected: &openrtb2.UserAgent{
+				Browsers: []openrtb2.BrandVersion{
+					{Brand: "Chromium", Version: []string{"118"}},
+					{Brand: "Google Chrome", Version: []string{"118"}},
+					{Brand: "Not=A?Brand", Version: []string{"99"}},
+				},
+				Platform: &openrtb2.BrandVersion{Brand: "Windows"},
+				Mobile:   &desktop,
+				Source:   adcom1.UASourceLowEntropy,
+			},
+		},
+		{
+			description: "high entropy",
+			headers: map[string]string{
+				"Sec-CH-UA":                   `"Chromium";v="118", "Google Chrome";v="118"`,
+				"Sec-CH-UA-Full-Version-List": `"Chromium";v="118.0.5993.70", "Google Chrome";v="118.0.5993.70"`,
+				"Sec-CH-UA-Mobile":            "?1",
+				"Sec-CH-UA-Platform":          `"Android"`,
+				"Sec-CH-UA-Platform-Version":  `"13.0.0"`,
+				"Sec-CH-UA-Model":             `"Pixel 7"`,
+				"Sec-CH-UA-Arch":              `""`,
+				"Sec-CH-UA-Bitness":           `"64"`,
+			},
+			expected: &openrtb2.UserAgent{
+				Browsers: []openrtb2.BrandVersion{
+					{Brand: "Chromium", Version: []string{"118", "0", "5993", "70"}},
+					{Brand: "Google Chrome", Version: []string{"118", "0", "5993", "70"}},
+				},
+				Platform: &openrtb2.BrandVersion{Brand: "Android", Version: []string{"13", "0", "0"}},
+				Mobile:   &mobile,
+				Bitness:  "64",
+				Model:    "Pixel 7",
+				Source:   adcom1.UASourceHighEntropy,
+			},
+		},
+		{
+			description: "quoted separators and escapes in brands",
+			headers: map[string]string{
+				"Sec-CH-UA": `"Brand, \"Quoted\"; Inc";v="1.2", invalid, "Other"`,
+			},
+			expected: &openrtb2.UserAgent{
+				Browsers: []openrtb2.BrandVersion{
+					{Brand: `Brand, "Quoted"; Inc`, Version: []string{"1", "2"}},
+					{Brand: "Other"},
+				},
+				Source: adcom1.UASourceLowEntropy,
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			header := http.Header{}
+			for name, value := range test.headers {
+				header.Set(name, value)
+			}
+			assert.Equal(t, test.expected, Parse(header))
+		})
+	}
+}
+
+func TestEnrichDevice(t *testing.T) {
+	windows := func(version ...string) *openrtb2.UserAgent {
+		return &openrtb2.UserAgent{Platform: &openrtb2.BrandVersion{Brand: "Windows", Version: version}}
+	}
+
+	testCases := []struct {
+		description    string
+		device         openrtb2.Device
+		sua            *openrtb2.UserAgent
+		expectedOS     string
+		expectedOSV    string
+		expectedModel  string
+		expectedSUASet bool
+	}{
+		{
+			description:    "missing fields are filled in",
+			sua:            &openrtb2.UserAgent{Platform: &openrtb2.BrandVersion{Brand: "Android", Version: []string{"13", "0", "0"}}, Model: "Pixel 7"},
+			expectedOS:     "Android",
+			expectedOSV:    "13.0.0",
+			expectedModel:  "Pixel 7",
+			expectedSUASet: true,
+		},
+		{
+			description:    "fields already set are kept",
+			device:         openrtb2.Device{OS: "android", OSV: "13", Model: "Pixel"},
+			sua:            &openrtb2.UserAgent{Platform: &openrtb2.BrandVersion{Brand: "Android", Version: []string{"13", "0", "0"}}, Model: "Pixel 7"},
+			expectedOS:     "android",
+			expectedOSV:    "13",
+			expectedModel:  "Pixel",
+			expectedSUASet: true,
+		},
+		{
+			description:    "Windows 11",
+			sua:            windows("15", "0", "0"),
+			expectedOS:     "Windows",
+			expectedOSV:    "11",
+			expectedSUASet: true,
+		},
+		{
+			description:    "Windows 10",
+			sua:            windows("10", "0", "0"),
+			expectedOS:     "Windows",
+			expectedOSV:    "10",
+			expectedSUASet: true,
+		},
+		{
+			description:    "earlier Windows",
+			sua:            windows("0", "3", "0"),
+			expectedOS:     "Windows",
+			expectedSUASet: true,
+		},
+		{
+			description:    "unknown platform",
+			sua:            &openrtb2.UserAgent{Platform: &openrtb2.BrandVersion{Brand: "Unknown"}},
+			expectedSUASet: true,
+		},
+		{
+			description:   "device with sua is left alone",
+			device:        openrtb2.Device{SUA: &openrtb2.UserAgent{Model: "Given"}},
+			sua:           &openrtb2.UserAgent{Model: "Pixel 7"},
+			expectedModel: "",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			device := test.device
+			EnrichDevice(&device, test.sua)
+			assert.Equal(t, test.expectedOS, device.OS)
+			assert.Equal(t, test.expectedOSV, device.OSV)
+			assert.Equal(t, test.expectedModel, device.Model)
+			if test.expectedSUASet {
+				assert.Same(t, test.sua, device.SUA)
+			} else {
+				assert.NotSame(t, test.sua, device.SUA)
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+// Command debugtoken makes a signed token which turns on debug output for an account's requests to
+// /openrtb2/auction, for as long as the token is valid. The key is read from the PBS_DEBUG_TOKEN_KEY
+// environment variable, so it doesn't end up in the shell history, and must be one of the account's
+// debug_token.keys.
+//
+// For example, to make a token which is valid for 15 minutes and turns on verbose hook tracing:
+//
+//	PBS_DEBUG_TOKEN_KEY=... go run ./cmd/debugtoken -account 1001 -ttl 15m -trace verbose
+//
+// The token is sent in the X-Pbs-Debug-Token header, or in ext.prebid.debugtoken.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/prebid/prebid-server/v2/debugtoken"
+)
+
+const keyVariable = "PBS_DEBUG_TOKEN_KEY"
+
+func main() {
+	account := flag.String("account", "", "ID of the account the token is for.")
+	ttl := flag.Duration("ttl", 15*time.Minute, "How long the token is valid for. It can't be longer than the account's debug_token.max_ttl_seconds.")
+	trace := flag.String("trace", "", "Level of hook tracing to turn on: basic or verbose. Tracing is off if it's empty.")
+	flag.Parse()
+
+	token, err := makeToken(*account, *ttl, *trace, os.Getenv(keyVariable), time.Now())
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	fmt.Println(token)
+}
+
+func makeToken(account string, ttl time.Duration, trace, key string, now time.Time) (string, error) {
+	if account == "" {
+		return "", errors.New("-account is required")
+	}
+	if ttl <= 0 {
+		return "", errors.New("-ttl must be > 0")
+	}
+	if key == "" {
+		return "", fmt.Errorf("%s must be set to the key to sign the token with", keyVariable)
+	}
+	claims := debugtoken.Claims{
+		Account: account,
+		Expires: now.Add(ttl).Unix(),
+		Trace:   trace,
+	}
+	return debugtoken.Sign(claims, key)
+}
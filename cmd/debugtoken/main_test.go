@@ -0,0 +1,70 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prebid/prebid-server/v2/debugtoken"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMakeToken(t *testing.T) {
+	const key = "0123456789abcdef0123456789abcdef"
+	now := time.Unix(1700000000, 0)
+
+	token, err := makeToken("1001", 15*time.Minute, "basic", key, now)
+	require.NoError(t, err)
+
+	claims, err := debugtoken.Verify(token, "1001", []string{key}, time.Hour, now)
+	require.NoError(t, err)
+	assert.Equal(t, debugtoken.Claims{Account: "1001", Expires: now.Unix() + 900, Trace: "basic"}, claims)
+}
+
+func TestMakeTokenInvalid(t *testing.T) {
+	const key = "0123456789abcdef0123456789abcdef"
+	now := time.Unix(1700000000, 0)
+
+	testCases := []struct {
+		description   string
+		account       string
+		ttl           time.Duration
+		trace         string
+		key           string
+		expectedError string
+	}{
+		{
+			description:   "no-account",
+			ttl:           time.Minute,
+			key:           key,
+			expectedError: "-account is required",
+		},
+		{
+			description:   "no-ttl",
+			account:       "1001",
+			key:           key,
+			expectedError: "-ttl must be > 0",
+		},
+		{
+			description:   "no-key",
+			account:       "1001",
+			ttl:           time.Minute,
+			expectedError: "PBS_DEBUG_TOKEN_KEY must be set to the key to sign the token with",
+		},
+		{
+			description:   "invalid-trace",
+			account:       "1001",
+			ttl:           time.Minute,
+			trace:         "all",
+			key:           key,
+			expectedError: `the trace level "all" is invalid`,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			_, err := makeToken(test.account, test.ttl, test.trace, test.key, now)
+			assert.EqualError(t, err, test.expectedError)
+		})
+	}
+}
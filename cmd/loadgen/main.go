@@ -0,0 +1,211 @@
+// Command loadgen sends OpenRTB traffic to a Prebid Server instance at a steady rate, and reports the
+// latency percentiles and error rates it sees. It's meant for validating capacity, and for catching
+// performance regressions, before a rollout.
+//
+// Traffic is either synthetic, a mix of banner, video and AMP requests, or replayed from a file of
+// captured requests. Replay files hold one request per line, either as a bare OpenRTB request for
+// /openrtb2/auction, or as an object giving the endpoint, and the query string or body to send it:
+//
+//	{"endpoint":"/openrtb2/amp","query":"tag_id=some-stored-request&w=300&h=250"}
+//
+// For example, to send 200 requests a second for ten minutes, reporting every 10 seconds:
+//
+//	go run ./cmd/loadgen -target http://localhost:8000 -qps 200 -duration 10m \
+//	  -mix banner=70,video=20,amp=10 -amp_tag_id some-stored-request \
+//	  -imp_ext '{"prebid":{"bidder":{"appnexus":{"placementId":12883451}}}}'
+package main
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"time"
+)
+
+type options struct {
+	target         string
+	qps            float64
+	duration       time.Duration
+	timeout        time.Duration
+	maxInFlight    int
+	reportInterval time.Duration
+}
+
+func main() {
+	target := flag.String("target", "http://localhost:8000", "Base URL of the Prebid Server instance to send traffic to.")
+	qps := flag.Float64("qps", 100, "Requests to send per second.")
+	duration := flag.Duration("duration", time.Minute, "How long to send traffic for.")
+	timeout := flag.Duration("timeout", 2*time.Second, "How long to wait for each response before counting the request as failed.")
+	maxInFlight := flag.Int("max_in_flight", 1000, "Most requests to have in flight at once. Requests due beyond that are dropped and counted as errors.")
+	reportInterval := flag.Duration("report_interval", 10*time.Second, "How often to report on the latest traffic. Use 0 to only report at the end.")
+	mix := flag.String("mix", "banner=100", "Weights of the kinds of synthetic traffic to send, such as banner=70,video=20,amp=10.")
+	impExt := flag.String("imp_ext", "", "JSON to use as imp.ext in synthetic banner and video requests. It says which bidders to call.")
+	ampTagID := flag.String("amp_tag_id", "", "ID of the stored request to use in synthetic AMP requests.")
+	account := flag.String("account", "", "Account ID to use in synthetic requests.")
+	tmax := flag.Int64("tmax", 500, "tmax of synthetic requests, in milliseconds.")
+	replay := flag.String("replay", "", "File of captured requests to replay instead of sending synthetic traffic.")
+	maxErrorRate := flag.Float64("max_error_rate", -1, "Exit with status 1 if the share of requests which fail is over this, from 0 to 1. Use a negative value to never fail.")
+	flag.Parse()
+
+	if *qps <= 0 || *duration <= 0 || *timeout <= 0 || *maxInFlight <= 0 || *reportInterval < 0 {
+		fmt.Fprintln(os.Stderr, "-qps, -duration, -timeout and -max_in_flight must be > 0, and -report_interval must be >= 0")
+		os.Exit(2)
+	}
+
+	source, err := newTrafficSource(*replay, *mix, *impExt, *account, *ampTagID, *tmax)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	opts := options{
+		target:         strings.TrimSuffix(*target, "/"),
+		qps:            *qps,
+		duration:       *duration,
+		timeout:        *timeout,
+		maxInFlight:    *maxInFlight,
+		reportInterval: *reportInterval,
+	}
+	client := &http.Client{
+		Timeout: *timeout,
+		Transport: &http.Transport{
+			Proxy:               http.ProxyFromEnvironment,
+			MaxIdleConns:        *maxInFlight,
+			MaxIdleConnsPerHost: *maxInFlight,
+			IdleConnTimeout:     90 * time.Second,
+		},
+	}
+	all := run(ctx, opts, source, client, os.Stdout)
+
+	if *maxErrorRate >= 0 && all.errorRate() > *maxErrorRate {
+		fmt.Fprintf(os.Stderr, "error rate %.2f%% is over the maximum of %.2f%%\n", all.errorRate()*100, *maxErrorRate*100)
+		os.Exit(1)
+	}
+}
+
+func newTrafficSource(replay, mix, impExt, account, ampTagID string, tmax int64) (trafficSource, error) {
+	if replay != "" {
+		return newReplayTraffic(replay)
+	}
+	entries, err := parseMix(mix)
+	if err != nil {
+		return nil, err
+	}
+	return newSyntheticTraffic(entries, []byte(impExt), account, ampTagID, tmax)
+}
+
+// run sends traffic until the duration is up or ctx is done, writing a report every report interval and
+// once at the end. It returns the combined stats of the whole run.
+func run(ctx context.Context, opts options, source trafficSource, client *http.Client, out io.Writer) *kindStats {
+	rec := newRecorder()
+	rnd := rand.New(rand.NewSource(time.Now().UnixNano()))
+	inFlight := make(chan struct{}, opts.maxInFlight)
+	var requests sync.WaitGroup
+
+	start := time.Now()
+	end := start.Add(opts.duration)
+
+	reportsDone := make(chan struct{})
+	stopReports := make(chan struct{})
+	go func() {
+		defer close(reportsDone)
+		if opts.reportInterval == 0 {
+			return
+		}
+		ticker := time.NewTicker(opts.reportInterval)
+		defer ticker.Stop()
+		last := start
+		for {
+			select {
+			case now := <-ticker.C:
+				writeReport(out, fmt.Sprintf("--- %s", now.Sub(start).Round(time.Second)), rec.takeInterval(), now.Sub(last))
+				last = now
+			case <-stopReports:
+				return
+			}
+		}
+	}()
+
+	timer := time.NewTimer(time.Hour)
+	timer.Stop()
+	for i := 0; ctx.Err() == nil; i++ {
+		// Requests are scheduled against the start time, rather than the previous request, so the rate
+		// doesn't drift down as the server slows.
+		scheduled := start.Add(time.Duration(float64(i) * float64(time.Second) / opts.qps))
+		if !scheduled.Before(end) {
+			break
+		}
+		if wait := time.Until(scheduled); wait > 0 {
+			timer.Reset(wait)
+			select {
+			case <-timer.C:
+			case <-ctx.Done():
+				timer.Stop()
+				continue
+			}
+		}
+
+		request, err := source.next(rnd)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to build request: %v\n", err)
+			continue
+		}
+
+		select {
+		case inFlight <- struct{}{}:
+		default:
+			rec.recordDropped(request.kind)
+			continue
+		}
+		requests.Add(1)
+		go func() {
+			defer func() {
+				<-inFlight
+				requests.Done()
+			}()
+			status, err := send(ctx, client, opts.target, request)
+			rec.record(request.kind, time.Since(scheduled), status, err)
+		}()
+	}
+	requests.Wait()
+	close(stopReports)
+	<-reportsDone
+
+	return writeReport(out, "=== total", rec.takeTotal(), time.Since(start))
+}
+
+// send sends a request, and returns the status of the response once it has been read in full.
+func send(ctx context.Context, client *http.Client, target string, request loadRequest) (int, error) {
+	method := http.MethodGet
+	var body io.Reader
+	if request.body != nil {
+		method = http.MethodPost
+		body = bytes.NewReader(request.body)
+	}
+	httpRequest, err := http.NewRequestWithContext(ctx, method, target+request.path, body)
+	if err != nil {
+		return 0, err
+	}
+	if request.body != nil {
+		httpRequest.Header.Set("Content-Type", "application/json")
+	}
+
+	response, err := client.Do(httpRequest)
+	if err != nil {
+		return 0, err
+	}
+	defer response.Body.Close()
+	_, err = io.Copy(io.Discard, response.Body)
+	return response.StatusCode, err
+}
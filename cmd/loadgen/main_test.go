@@ -0,0 +1,104 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRun(t *testing.T) {
+	var (
+		lock     sync.Mutex
+		requests = make(map[string]int)
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		lock.Lock()
+		requests[r.Method+" "+r.URL.Path]++
+		lock.Unlock()
+
+		if r.URL.Path == "/openrtb2/amp" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		assert.True(t, json.Valid(body))
+		w.Write([]byte(`{"id":"some-id"}`))
+	}))
+	defer server.Close()
+
+	mix := []mixEntry{{kind: trafficBanner, weight: 1}, {kind: trafficAmp, weight: 1}}
+	source, err := newSyntheticTraffic(mix, json.RawMessage(`{"prebid":{}}`), "", "1", 500)
+	require.NoError(t, err)
+
+	var out bytes.Buffer
+	opts := options{
+		target:         server.URL,
+		qps:            200,
+		duration:       250 * time.Millisecond,
+		timeout:        time.Second,
+		maxInFlight:    10,
+		reportInterval: 100 * time.Millisecond,
+	}
+	all := run(context.Background(), opts, source, server.Client(), &out)
+
+	assert.Equal(t, 50, all.requests())
+	lock.Lock()
+	assert.Equal(t, all.requests(), requests["POST /openrtb2/auction"]+requests["GET /openrtb2/amp"])
+	assert.Equal(t, requests["GET /openrtb2/amp"], all.failed, "amp requests fail")
+	lock.Unlock()
+
+	assert.Equal(t, 2, strings.Count(out.String(), "--- "), "should report every interval")
+	assert.Contains(t, out.String(), "=== total")
+}
+
+func TestRunStopsWhenCanceled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	source, err := newSyntheticTraffic([]mixEntry{{kind: trafficBanner, weight: 1}}, json.RawMessage(`{}`), "", "", 500)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	opts := options{target: server.URL, qps: 100, duration: time.Hour, timeout: time.Second, maxInFlight: 10}
+
+	done := make(chan struct{})
+	go func() {
+		run(ctx, opts, source, server.Client(), io.Discard)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("run should stop once its context is done")
+	}
+}
+
+func TestRunDropsRequestsOverMaxInFlight(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+
+	source, err := newSyntheticTraffic([]mixEntry{{kind: trafficBanner, weight: 1}}, json.RawMessage(`{}`), "", "", 500)
+	require.NoError(t, err)
+
+	opts := options{target: server.URL, qps: 100, duration: 100 * time.Millisecond, timeout: 200 * time.Millisecond, maxInFlight: 2}
+	all := run(context.Background(), opts, source, &http.Client{Timeout: opts.timeout}, io.Discard)
+
+	assert.Equal(t, 10, all.requests())
+	assert.Equal(t, 8, all.dropped)
+	assert.Equal(t, 2, all.failed, "requests in flight should time out")
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"sync"
+	"text/tabwriter"
+	"time"
+)
+
+// kindStats holds the outcomes of the requests of one kind of traffic.
+type kindStats struct {
+	latencies []time.Duration
+	// failed counts requests which got no response, or a response with a status other than 2xx.
+	failed int
+	// dropped counts requests which weren't sent because too many were already in flight.
+	dropped int
+}
+
+func (s *kindStats) requests() int {
+	return len(s.latencies) + s.dropped
+}
+
+func (s *kindStats) errorRate() float64 {
+	if s.requests() == 0 {
+		return 0
+	}
+	return float64(s.failed+s.dropped) / float64(s.requests())
+}
+
+// percentile returns the latency which p percent of the requests took no longer than. The latencies must
+// be sorted.
+func (s *kindStats) percentile(p float64) time.Duration {
+	if len(s.latencies) == 0 {
+		return 0
+	}
+	// The tolerance keeps float error, as in 99.9/100*1000, from pushing the rank up by one.
+	rank := int(math.Ceil(p/100*float64(len(s.latencies))-1e-9)) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	return s.latencies[rank]
+}
+
+func (s *kindStats) merge(other *kindStats) {
+	s.latencies = append(s.latencies, other.latencies...)
+	s.failed += other.failed
+	s.dropped += other.dropped
+}
+
+// recorder collects the outcomes of requests, both for the whole run and for the latest report interval.
+type recorder struct {
+	lock     sync.Mutex
+	total    map[string]*kindStats
+	interval map[string]*kindStats
+}
+
+func newRecorder() *recorder {
+	return &recorder{
+		total:    make(map[string]*kindStats),
+		interval: make(map[string]*kindStats),
+	}
+}
+
+// record adds the outcome of a request which was sent. The latency is measured from the time the request
+// was meant to be sent, so a server which falls behind is charged for the time requests spent waiting to
+// go out, rather than being sent fewer of them.
+func (r *recorder) record(kind string, latency time.Duration, status int, err error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	for _, stats := range []*kindStats{statsFor(r.total, kind), statsFor(r.interval, kind)} {
+		stats.latencies = append(stats.latencies, latency)
+		if err != nil || status < http.StatusOK || status >= http.StatusMultipleChoices {
+			stats.failed++
+		}
+	}
+}
+
+// recordDropped adds a request which wasn't sent.
+func (r *recorder) recordDropped(kind string) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	statsFor(r.total, kind).dropped++
+	statsFor(r.interval, kind).dropped++
+}
+
+// takeInterval returns the outcomes since the last call, and starts a new interval.
+func (r *recorder) takeInterval() map[string]*kindStats {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	interval := r.interval
+	r.interval = make(map[string]*kindStats)
+	return interval
+}
+
+// takeTotal returns the outcomes of the whole run.
+func (r *recorder) takeTotal() map[string]*kindStats {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	total := r.total
+	r.total = make(map[string]*kindStats)
+	return total
+}
+
+func statsFor(stats map[string]*kindStats, kind string) *kindStats {
+	s, ok := stats[kind]
+	if !ok {
+		s = &kindStats{}
+		stats[kind] = s
+	}
+	return s
+}
+
+// summarize sorts the latencies of each kind of traffic, and returns the kinds in order along with their
+// combined stats.
+func summarize(stats map[string]*kindStats) (kinds []string, all *kindStats) {
+	all = &kindStats{}
+	for kind, s := range stats {
+		kinds = append(kinds, kind)
+		all.merge(s)
+		sort.Slice(s.latencies, func(i, j int) bool { return s.latencies[i] < s.latencies[j] })
+	}
+	sort.Strings(kinds)
+	sort.Slice(all.latencies, func(i, j int) bool { return all.latencies[i] < all.latencies[j] })
+	return kinds, all
+}
+
+// writeReport writes a table of the request rate, error rate and latency percentiles of each kind of
+// traffic. It returns the combined stats.
+func writeReport(w io.Writer, title string, stats map[string]*kindStats, elapsed time.Duration) *kindStats {
+	kinds, all := summarize(stats)
+
+	fmt.Fprintf(w, "%s\n", title)
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(table, "kind\trequests\tqps\terrors\tdropped\terror %\tp50\tp90\tp99\tp99.9\tmax\t")
+	writeRow := func(kind string, s *kindStats) {
+		qps := 0.0
+		if elapsed > 0 {
+			qps = float64(s.requests()) / elapsed.Seconds()
+		}
+		fmt.Fprintf(table, "%s\t%d\t%.1f\t%d\t%d\t%.2f\t%s\t%s\t%s\t%s\t%s\t\n",
+			kind, s.requests(), qps, s.failed, s.dropped, s.errorRate()*100,
+			formatLatency(s.percentile(50)), formatLatency(s.percentile(90)), formatLatency(s.percentile(99)),
+			formatLatency(s.percentile(99.9)), formatLatency(s.percentile(100)))
+	}
+	for _, kind := range kinds {
+		writeRow(kind, stats[kind])
+	}
+	if len(kinds) != 1 {
+		writeRow("all", all)
+	}
+	table.Flush()
+	return all
+}
+
+func formatLatency(d time.Duration) string {
+	return d.Round(100 * time.Microsecond).String()
+}
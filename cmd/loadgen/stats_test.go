@@ -0,0 +1,68 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKindStatsPercentile(t *testing.T) {
+	stats := &kindStats{}
+	assert.Equal(t, time.Duration(0), stats.percentile(50))
+
+	for i := 1; i <= 1000; i++ {
+		stats.latencies = append(stats.latencies, time.Duration(i)*time.Millisecond)
+	}
+	assert.Equal(t, time.Millisecond, stats.percentile(0))
+	assert.Equal(t, 500*time.Millisecond, stats.percentile(50))
+	assert.Equal(t, 990*time.Millisecond, stats.percentile(99))
+	assert.Equal(t, 999*time.Millisecond, stats.percentile(99.9))
+	assert.Equal(t, time.Second, stats.percentile(100))
+}
+
+func TestRecorder(t *testing.T) {
+	rec := newRecorder()
+	rec.record(trafficBanner, 10*time.Millisecond, http.StatusOK, nil)
+	rec.record(trafficBanner, 20*time.Millisecond, http.StatusNoContent, nil)
+	rec.record(trafficBanner, 30*time.Millisecond, http.StatusServiceUnavailable, nil)
+	rec.record(trafficVideo, 2*time.Second, 0, errors.New("timeout"))
+	rec.recordDropped(trafficVideo)
+
+	interval := rec.takeInterval()
+	assert.Equal(t, 3, interval[trafficBanner].requests())
+	assert.Equal(t, 1, interval[trafficBanner].failed)
+	assert.Equal(t, 2, interval[trafficVideo].requests())
+	assert.Equal(t, 1, interval[trafficVideo].failed)
+	assert.Equal(t, 1, interval[trafficVideo].dropped)
+	assert.Equal(t, 1.0, interval[trafficVideo].errorRate())
+	assert.Empty(t, rec.takeInterval(), "taking the interval should start a new one")
+
+	rec.record(trafficAmp, time.Millisecond, http.StatusOK, nil)
+	total := rec.takeTotal()
+	assert.Len(t, total, 3, "the total should cover every interval")
+	assert.Equal(t, 1, total[trafficAmp].requests())
+}
+
+func TestWriteReport(t *testing.T) {
+	stats := map[string]*kindStats{
+		trafficVideo:  {latencies: []time.Duration{40 * time.Millisecond, 30 * time.Millisecond}, failed: 1},
+		trafficBanner: {latencies: []time.Duration{20 * time.Millisecond, 10 * time.Millisecond}, dropped: 2},
+	}
+
+	var out bytes.Buffer
+	all := writeReport(&out, "=== total", stats, 2*time.Second)
+
+	assert.Equal(t, 6, all.requests())
+	assert.Equal(t, 0.5, all.errorRate())
+	expected := `=== total
+    kind  requests  qps  errors  dropped  error %   p50   p90   p99  p99.9   max
+  banner         4  2.0       0        2    50.00  10ms  20ms  20ms   20ms  20ms
+   video         2  1.0       1        0    50.00  30ms  40ms  40ms   40ms  40ms
+     all         6  3.0       1        2    50.00  20ms  40ms  40ms   40ms  40ms
+`
+	assert.Equal(t, expected, out.String())
+}
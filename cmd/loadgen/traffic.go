@@ -0,0 +1,233 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/prebid/openrtb/v20/adcom1"
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v2/util/ptrutil"
+	"github.com/prebid/prebid-server/v2/util/uuidutil"
+)
+
+const (
+	trafficBanner = "banner"
+	trafficVideo  = "video"
+	trafficAmp    = "amp"
+)
+
+// loadRequest is a single request to send to Prebid Server. Requests with a body are POSTed, and the
+// rest are sent as GETs.
+type loadRequest struct {
+	kind string
+	path string
+	body []byte
+}
+
+// trafficSource produces the requests to send.
+type trafficSource interface {
+	next(rnd *rand.Rand) (loadRequest, error)
+}
+
+// mixEntry is the weight given to a kind of synthetic traffic.
+type mixEntry struct {
+	kind   string
+	weight int
+}
+
+// parseMix parses a traffic mix such as "banner=70,video=20,amp=10".
+func parseMix(mix string) ([]mixEntry, error) {
+	var entries []mixEntry
+	for _, part := range strings.Split(mix, ",") {
+		kind, weightString, found := strings.Cut(strings.TrimSpace(part), "=")
+		if !found {
+			return nil, fmt.Errorf("mix entry %q must look like kind=weight", part)
+		}
+		if kind != trafficBanner && kind != trafficVideo && kind != trafficAmp {
+			return nil, fmt.Errorf("mix entry %q has unknown kind. Must be one of %s, %s or %s", part, trafficBanner, trafficVideo, trafficAmp)
+		}
+		weight, err := strconv.Atoi(weightString)
+		if err != nil || weight < 0 {
+			return nil, fmt.Errorf("mix entry %q must have a weight >= 0", part)
+		}
+		if weight > 0 {
+			entries = append(entries, mixEntry{kind: kind, weight: weight})
+		}
+	}
+	if len(entries) == 0 {
+		return nil, errors.New("mix must give some traffic a weight > 0")
+	}
+	return entries, nil
+}
+
+// syntheticTraffic generates requests of each kind in proportion to their weights in the mix.
+type syntheticTraffic struct {
+	mix         []mixEntry
+	totalWeight int
+	impExt      json.RawMessage
+	account     string
+	ampTagID    string
+	tmax        int64
+	uuids       uuidutil.UUIDGenerator
+}
+
+func newSyntheticTraffic(mix []mixEntry, impExt json.RawMessage, account, ampTagID string, tmax int64) (*syntheticTraffic, error) {
+	traffic := &syntheticTraffic{
+		mix:      mix,
+		impExt:   impExt,
+		account:  account,
+		ampTagID: ampTagID,
+		tmax:     tmax,
+		uuids:    uuidutil.UUIDRandomGenerator{},
+	}
+	for _, entry := range mix {
+		traffic.totalWeight += entry.weight
+		switch entry.kind {
+		case trafficBanner, trafficVideo:
+			if len(impExt) == 0 {
+				return nil, fmt.Errorf("synthetic %s traffic needs -imp_ext, to say which bidders to call", entry.kind)
+			}
+		case trafficAmp:
+			if ampTagID == "" {
+				return nil, errors.New("synthetic amp traffic needs -amp_tag_id, the ID of a stored AMP request")
+			}
+		}
+	}
+	if len(impExt) > 0 && !json.Valid(impExt) {
+		return nil, errors.New("-imp_ext must be valid JSON")
+	}
+	return traffic, nil
+}
+
+func (t *syntheticTraffic) next(rnd *rand.Rand) (loadRequest, error) {
+	pick := rnd.Intn(t.totalWeight)
+	kind := t.mix[len(t.mix)-1].kind
+	for _, entry := range t.mix {
+		if pick < entry.weight {
+			kind = entry.kind
+			break
+		}
+		pick -= entry.weight
+	}
+
+	if kind == trafficAmp {
+		query := url.Values{}
+		query.Set("tag_id", t.ampTagID)
+		query.Set("w", "300")
+		query.Set("h", "250")
+		query.Set("account", t.account)
+		query.Set("timeout", strconv.FormatInt(t.tmax, 10))
+		return loadRequest{kind: kind, path: "/openrtb2/amp?" + query.Encode()}, nil
+	}
+
+	id, err := t.uuids.Generate()
+	if err != nil {
+		return loadRequest{}, err
+	}
+	imp := openrtb2.Imp{ID: "1", Ext: t.impExt}
+	if kind == trafficVideo {
+		imp.Video = &openrtb2.Video{
+			MIMEs:       []string{"video/mp4"},
+			Protocols:   []adcom1.MediaCreativeSubtype{adcom1.CreativeVAST20, adcom1.CreativeVAST30, adcom1.CreativeVAST20Wrapper, adcom1.CreativeVAST30Wrapper},
+			W:           ptrutil.ToPtr[int64](640),
+			H:           ptrutil.ToPtr[int64](480),
+			MinDuration: 5,
+			MaxDuration: 30,
+		}
+	} else {
+		imp.Banner = &openrtb2.Banner{Format: []openrtb2.Format{{W: 300, H: 250}, {W: 300, H: 600}}}
+	}
+	request := openrtb2.BidRequest{
+		ID:   id,
+		Imp:  []openrtb2.Imp{imp},
+		Site: &openrtb2.Site{Page: "https://loadgen.prebid.org", Publisher: &openrtb2.Publisher{ID: t.account}},
+		TMax: t.tmax,
+	}
+	body, err := json.Marshal(request)
+	if err != nil {
+		return loadRequest{}, err
+	}
+	return loadRequest{kind: kind, path: "/openrtb2/auction", body: body}, nil
+}
+
+// capturedRequest is a line of a replay file. A line may also hold a bare OpenRTB request, which is sent
+// to /openrtb2/auction.
+type capturedRequest struct {
+	Endpoint string          `json:"endpoint"`
+	Query    string          `json:"query"`
+	Body     json.RawMessage `json:"body"`
+}
+
+// replayTraffic sends captured requests in the order they were captured, starting over once they run out.
+type replayTraffic struct {
+	requests []loadRequest
+	index    int
+}
+
+func newReplayTraffic(path string) (*replayTraffic, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	traffic := &replayTraffic{}
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		request, err := parseCapturedRequest([]byte(text))
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %v", path, line, err)
+		}
+		traffic.requests = append(traffic.requests, request)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(traffic.requests) == 0 {
+		return nil, fmt.Errorf("%s has no requests to replay", path)
+	}
+	return traffic, nil
+}
+
+func parseCapturedRequest(line []byte) (loadRequest, error) {
+	var captured capturedRequest
+	if err := json.Unmarshal(line, &captured); err != nil {
+		return loadRequest{}, err
+	}
+	if captured.Endpoint == "" {
+		return loadRequest{kind: "auction", path: "/openrtb2/auction", body: line}, nil
+	}
+	if !strings.HasPrefix(captured.Endpoint, "/") {
+		return loadRequest{}, fmt.Errorf("endpoint %q must be a path", captured.Endpoint)
+	}
+
+	request := loadRequest{
+		kind: strings.TrimPrefix(captured.Endpoint[strings.LastIndex(captured.Endpoint, "/"):], "/"),
+		path: captured.Endpoint,
+	}
+	if captured.Query != "" {
+		request.path += "?" + captured.Query
+	}
+	if len(captured.Body) > 0 {
+		request.body = captured.Body
+	}
+	return request, nil
+}
+
+func (t *replayTraffic) next(rnd *rand.Rand) (loadRequest, error) {
+	request := t.requests[t.index]
+	t.index = (t.index + 1) % len(t.requests)
+	return request, nil
+}
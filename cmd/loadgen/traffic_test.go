@@ -0,0 +1,211 @@
+package main
+
+import (
+	"encoding/json"
+	"math/rand"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMix(t *testing.T) {
+	testCases := []struct {
+		name          string
+		mix           string
+		expected      []mixEntry
+		expectedError string
+	}{
+		{
+			name:     "one-kind",
+			mix:      "banner=100",
+			expected: []mixEntry{{kind: trafficBanner, weight: 100}},
+		},
+		{
+			name:     "all-kinds-with-spaces",
+			mix:      "banner=70, video=20, amp=10",
+			expected: []mixEntry{{kind: trafficBanner, weight: 70}, {kind: trafficVideo, weight: 20}, {kind: trafficAmp, weight: 10}},
+		},
+		{
+			name:     "zero-weight-left-out",
+			mix:      "banner=1,video=0",
+			expected: []mixEntry{{kind: trafficBanner, weight: 1}},
+		},
+		{
+			name:          "no-weight",
+			mix:           "banner",
+			expectedError: `mix entry "banner" must look like kind=weight`,
+		},
+		{
+			name:          "unknown-kind",
+			mix:           "native=10",
+			expectedError: `mix entry "native=10" has unknown kind. Must be one of banner, video or amp`,
+		},
+		{
+			name:          "negative-weight",
+			mix:           "banner=-1",
+			expectedError: `mix entry "banner=-1" must have a weight >= 0`,
+		},
+		{
+			name:          "all-zero",
+			mix:           "banner=0",
+			expectedError: "mix must give some traffic a weight > 0",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			mix, err := parseMix(test.mix)
+			if test.expectedError != "" {
+				assert.EqualError(t, err, test.expectedError)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, test.expected, mix)
+			}
+		})
+	}
+}
+
+func TestNewSyntheticTrafficErrors(t *testing.T) {
+	_, err := newSyntheticTraffic([]mixEntry{{kind: trafficVideo, weight: 1}}, nil, "", "", 500)
+	assert.EqualError(t, err, "synthetic video traffic needs -imp_ext, to say which bidders to call")
+
+	_, err = newSyntheticTraffic([]mixEntry{{kind: trafficAmp, weight: 1}}, nil, "", "", 500)
+	assert.EqualError(t, err, "synthetic amp traffic needs -amp_tag_id, the ID of a stored AMP request")
+
+	_, err = newSyntheticTraffic([]mixEntry{{kind: trafficBanner, weight: 1}}, json.RawMessage(`{`), "", "", 500)
+	assert.EqualError(t, err, "-imp_ext must be valid JSON")
+}
+
+func TestSyntheticTraffic(t *testing.T) {
+	impExt := json.RawMessage(`{"prebid":{"bidder":{"appnexus":{"placementId":1}}}}`)
+	mix := []mixEntry{{kind: trafficBanner, weight: 2}, {kind: trafficVideo, weight: 1}, {kind: trafficAmp, weight: 1}}
+	traffic, err := newSyntheticTraffic(mix, impExt, "1001", "amp-stored-request", 300)
+	require.NoError(t, err)
+
+	rnd := rand.New(rand.NewSource(1))
+	counts := make(map[string]int)
+	ids := make(map[string]bool)
+	for i := 0; i < 4000; i++ {
+		request, err := traffic.next(rnd)
+		require.NoError(t, err)
+		counts[request.kind]++
+
+		switch request.kind {
+		case trafficAmp:
+			assert.Nil(t, request.body)
+			path, rawQuery, _ := strings.Cut(request.path, "?")
+			assert.Equal(t, "/openrtb2/amp", path)
+			query, err := url.ParseQuery(rawQuery)
+			require.NoError(t, err)
+			assert.Equal(t, "amp-stored-request", query.Get("tag_id"))
+			assert.Equal(t, "1001", query.Get("account"))
+			assert.Equal(t, "300", query.Get("timeout"))
+		default:
+			assert.Equal(t, "/openrtb2/auction", request.path)
+			var bidRequest openrtb2.BidRequest
+			require.NoError(t, json.Unmarshal(request.body, &bidRequest))
+			assert.False(t, ids[bidRequest.ID], "each request should have a new ID")
+			ids[bidRequest.ID] = true
+			assert.Equal(t, int64(300), bidRequest.TMax)
+			assert.Equal(t, "1001", bidRequest.Site.Publisher.ID)
+			require.Len(t, bidRequest.Imp, 1)
+			assert.JSONEq(t, string(impExt), string(bidRequest.Imp[0].Ext))
+			assert.Equal(t, request.kind == trafficVideo, bidRequest.Imp[0].Video != nil)
+			assert.Equal(t, request.kind == trafficBanner, bidRequest.Imp[0].Banner != nil)
+		}
+	}
+
+	assert.InDelta(t, 2000, counts[trafficBanner], 150)
+	assert.InDelta(t, 1000, counts[trafficVideo], 150)
+	assert.InDelta(t, 1000, counts[trafficAmp], 150)
+}
+
+func TestParseCapturedRequest(t *testing.T) {
+	testCases := []struct {
+		name          string
+		line          string
+		expected      loadRequest
+		expectedError bool
+	}{
+		{
+			name:     "bare-openrtb-request",
+			line:     `{"id":"req1","imp":[{"id":"imp1"}]}`,
+			expected: loadRequest{kind: "auction", path: "/openrtb2/auction", body: []byte(`{"id":"req1","imp":[{"id":"imp1"}]}`)},
+		},
+		{
+			name:     "amp",
+			line:     `{"endpoint":"/openrtb2/amp","query":"tag_id=1&w=300"}`,
+			expected: loadRequest{kind: "amp", path: "/openrtb2/amp?tag_id=1&w=300"},
+		},
+		{
+			name:     "video-with-body",
+			line:     `{"endpoint":"/openrtb2/video","body":{"storedrequestid":"1"}}`,
+			expected: loadRequest{kind: "video", path: "/openrtb2/video", body: []byte(`{"storedrequestid":"1"}`)},
+		},
+		{
+			name:          "endpoint-not-a-path",
+			line:          `{"endpoint":"http://localhost/openrtb2/auction"}`,
+			expectedError: true,
+		},
+		{
+			name:          "malformed",
+			line:          `{"id":`,
+			expectedError: true,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			request, err := parseCapturedRequest([]byte(test.line))
+			if test.expectedError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, test.expected, request)
+			}
+		})
+	}
+}
+
+func TestReplayTraffic(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "captured.jsonl")
+	captured := `{"id":"req1","imp":[{"id":"imp1"}]}
+
+{"endpoint":"/openrtb2/amp","query":"tag_id=1"}
+`
+	require.NoError(t, os.WriteFile(path, []byte(captured), 0644))
+
+	traffic, err := newReplayTraffic(path)
+	require.NoError(t, err)
+
+	var paths []string
+	for i := 0; i < 3; i++ {
+		request, err := traffic.next(nil)
+		require.NoError(t, err)
+		paths = append(paths, request.path)
+	}
+	assert.Equal(t, []string{"/openrtb2/auction", "/openrtb2/amp?tag_id=1", "/openrtb2/auction"}, paths, "replay should start over once it runs out")
+}
+
+func TestReplayTrafficErrors(t *testing.T) {
+	dir := t.TempDir()
+
+	empty := filepath.Join(dir, "empty.jsonl")
+	require.NoError(t, os.WriteFile(empty, []byte("\n"), 0644))
+	_, err := newReplayTraffic(empty)
+	assert.EqualError(t, err, empty+" has no requests to replay")
+
+	malformed := filepath.Join(dir, "malformed.jsonl")
+	require.NoError(t, os.WriteFile(malformed, []byte("{\"id\":\"1\"}\n{\n"), 0644))
+	_, err = newReplayTraffic(malformed)
+	assert.ErrorContains(t, err, malformed+":2:")
+
+	_, err = newReplayTraffic(filepath.Join(dir, "missing.jsonl"))
+	assert.Error(t, err)
+}
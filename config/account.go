@@ -1,14 +1,24 @@
 package config
 
 import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"math"
+	"net"
+	"net/url"
+	"regexp"
 	"strings"
+	"time"
 
 	"github.com/prebid/go-gdpr/consentconstants"
 	"github.com/prebid/prebid-server/v2/openrtb_ext"
 	"github.com/prebid/prebid-server/v2/util/iputil"
+	"golang.org/x/text/currency"
 )
 
 // ChannelType enumerates the values of integrations Prebid Server can configure for an account
@@ -26,11 +36,14 @@ const (
 // Account represents a publisher account configuration
 type Account struct {
 	ID                      string                                      `mapstructure:"id" json:"id"`
+	Parent                  string                                      `mapstructure:"parent" json:"parent"`
 	Disabled                bool                                        `mapstructure:"disabled" json:"disabled"`
 	CacheTTL                DefaultTTLs                                 `mapstructure:"cache_ttl" json:"cache_ttl"`
 	CCPA                    AccountCCPA                                 `mapstructure:"ccpa" json:"ccpa"`
 	GDPR                    AccountGDPR                                 `mapstructure:"gdpr" json:"gdpr"`
 	DebugAllow              bool                                        `mapstructure:"debug_allow" json:"debug_allow"`
+	DebugToken              AccountDebugToken                           `mapstructure:"debug_token" json:"debug_token"`
+	DebugAccess             AccountDebugAccess                          `mapstructure:"debug_access" json:"debug_access"`
 	DefaultIntegration      string                                      `mapstructure:"default_integration" json:"default_integration"`
 	CookieSync              CookieSync                                  `mapstructure:"cookie_sync" json:"cookie_sync"`
 	Events                  Events                                      `mapstructure:"events" json:"events"` // Don't enable this feature. It is still under developmment - https://github.com/prebid/prebid-server/issues/1725
@@ -42,6 +55,867 @@ type Account struct {
 	DefaultBidLimit         int                                         `mapstructure:"default_bid_limit" json:"default_bid_limit"`
 	BidAdjustments          *openrtb_ext.ExtRequestPrebidBidAdjustments `mapstructure:"bidadjustments" json:"bidadjustments"`
 	Privacy                 AccountPrivacy                              `mapstructure:"privacy" json:"privacy"`
+	// BidderCanaries overrides the share of requests, from 0 to 100, sent with each bidder's canary
+	// configuration, by bidder name.
+	BidderCanaries   map[string]float64      `mapstructure:"bidder_canaries" json:"bidder_canaries"`
+	AdsCert          AccountAdsCert          `mapstructure:"adscert" json:"adscert"`
+	SChain           AccountSChain           `mapstructure:"schain" json:"schain"`
+	Origin           AccountOrigin           `mapstructure:"origin" json:"origin"`
+	RequestLimits    AccountRequestLimits    `mapstructure:"request_limits" json:"request_limits"`
+	APIKeys          AccountAPIKeys          `mapstructure:"api_keys" json:"api_keys"`
+	ResponseSigning  AccountResponseSigning  `mapstructure:"response_signing" json:"response_signing"`
+	ClientHints      AccountClientHints      `mapstructure:"client_hints" json:"client_hints"`
+	DealPacing       AccountDealPacing       `mapstructure:"deal_pacing" json:"deal_pacing"`
+	CreativeTrackers AccountCreativeTrackers `mapstructure:"creative_trackers" json:"creative_trackers"`
+	NativeTrackers   AccountNativeTrackers   `mapstructure:"native_trackers" json:"native_trackers"`
+	FirstPartyData   AccountFirstPartyData   `mapstructure:"first_party_data" json:"first_party_data"`
+	DefaultRequest   AccountDefaultRequest   `mapstructure:"default_request" json:"default_request"`
+	SizeResolution   AccountSizeResolution   `mapstructure:"size_resolution" json:"size_resolution"`
+	DynamicTmax      AccountDynamicTmax      `mapstructure:"dynamic_tmax" json:"dynamic_tmax"`
+	EventWebhook     AccountEventWebhook     `mapstructure:"event_webhook" json:"event_webhook"`
+	IVT              AccountIVT              `mapstructure:"ivt" json:"ivt"`
+	Multiformat      AccountMultiformat      `mapstructure:"multiformat" json:"multiformat"`
+	BidderFields     AccountBidderFields     `mapstructure:"bidder_fields" json:"bidder_fields"`
+	LineItems        AccountLineItems        `mapstructure:"line_items" json:"line_items"`
+	PriceEncryption  AccountPriceEncryption  `mapstructure:"price_encryption" json:"price_encryption"`
+	UsageQuota       AccountUsageQuota       `mapstructure:"usage_quota" json:"usage_quota"`
+	Currency         AccountCurrency         `mapstructure:"currency" json:"currency"`
+	// BidderMaintenance are windows in which bidders are left out of the account's auctions, as well as those
+	// of the host. Windows whose times can't be parsed are ignored.
+	BidderMaintenance []BidderMaintenanceWindow `mapstructure:"bidder_maintenance" json:"bidder_maintenance"`
+	// AdaptiveBidderTmax gives the bidders of the account's auctions no more time than they usually take, if
+	// the host tracks their response times.
+	AdaptiveBidderTmax AccountAdaptiveBidderTmax `mapstructure:"adaptive_bidder_tmax" json:"adaptive_bidder_tmax"`
+	// ContentClassification has the content of the account's pages and apps classified, if the host has a
+	// classifier.
+	ContentClassification AccountContentClassification `mapstructure:"content_classification" json:"content_classification"`
+	// ResponseExt leaves the sections of the response ext the account's integrations don't read out of its
+	// auction responses.
+	ResponseExt AccountResponseExt `mapstructure:"response_ext" json:"response_ext"`
+	// StoredBidResponses answers imps of the account's requests with stored bid responses for some bidders,
+	// so QA can force deterministic bids in production-like traffic without changing the requests.
+	StoredBidResponses AccountStoredBidResponses `mapstructure:"stored_bid_responses" json:"stored_bid_responses"`
+}
+
+// Sections of the response ext which accounts may leave out of their auction responses.
+const (
+	ResponseExtDebug              = "debug"
+	ResponseExtErrors             = "errors"
+	ResponseExtWarnings           = "warnings"
+	ResponseExtResponseTimeMillis = "responsetimemillis"
+	ResponseExtUsersync           = "usersync"
+	ResponseExtSeatNonBid         = "seatnonbid"
+	ResponseExtFledge             = "fledge"
+	ResponseExtModules            = "modules"
+)
+
+// ResponseExtSections returns the sections of the response ext which accounts may leave out.
+func ResponseExtSections() []string {
+	return []string{
+		ResponseExtDebug,
+		ResponseExtErrors,
+		ResponseExtWarnings,
+		ResponseExtResponseTimeMillis,
+		ResponseExtUsersync,
+		ResponseExtSeatNonBid,
+		ResponseExtFledge,
+		ResponseExtModules,
+	}
+}
+
+// AccountResponseExt limits the response ext of the account's auction responses to the sections its
+// integrations read, for bandwidth sensitive integrations such as mobile apps. The auction timestamp,
+// passthrough, targeting and tmaxrequest are always kept, as are members of the ext the sections don't cover.
+type AccountResponseExt struct {
+	Enabled bool `mapstructure:"enabled" json:"enabled"`
+	// Include lists the sections kept: debug, errors, warnings, responsetimemillis, usersync, and seatnonbid,
+	// fledge and modules of ext.prebid. The modules section holds the hook traces and the analytics tags of
+	// modules.
+	Include []string `mapstructure:"include" json:"include"`
+}
+
+// Includes returns true if the section is kept in the account's responses.
+func (re *AccountResponseExt) Includes(section string) bool {
+	if !re.Enabled {
+		return true
+	}
+	for _, included := range re.Include {
+		if included == section {
+			return true
+		}
+	}
+	return false
+}
+
+func (re *AccountResponseExt) validate(errs []error) []error {
+	sections := ResponseExtSections()
+	for _, section := range re.Include {
+		known := false
+		for _, s := range sections {
+			known = known || s == section
+		}
+		if !known {
+			errs = append(errs, fmt.Errorf("account_defaults.response_ext.include must only hold %s. Got %s", strings.Join(sections, ", "), section))
+		}
+	}
+	return errs
+}
+
+// AccountStoredBidResponses maps imps and bidders of the account's requests to stored bid responses. The
+// bidders aren't called for the imps mapped, and answer them with the responses instead, while the other
+// bidders of the imps are called as usual. Stored bid responses set by the request itself win.
+type AccountStoredBidResponses struct {
+	Enabled bool `mapstructure:"enabled" json:"enabled"`
+	// DebugTokenOnly limits the mappings to requests with a valid debug token for the account, so that only
+	// the requests of QA are answered with them
+	DebugTokenOnly bool                       `mapstructure:"debug_token_only" json:"debug_token_only"`
+	Mappings       []AccountStoredBidResponse `mapstructure:"mappings" json:"mappings"`
+}
+
+// AccountStoredBidResponse maps an imp and bidder to a stored bid response.
+type AccountStoredBidResponse struct {
+	// ImpID is the ID of the imp, or * for every imp. A mapping of the imp's own ID wins over one of *.
+	ImpID  string `mapstructure:"imp_id" json:"imp_id"`
+	Bidder string `mapstructure:"bidder" json:"bidder"`
+	// ID is the ID of the stored bid response, in the bidder's own format
+	ID string `mapstructure:"id" json:"id"`
+	// ReplaceImpID replaces the imp IDs of the stored response's bids with the ID of the imp. It defaults to true.
+	ReplaceImpID *bool `mapstructure:"replace_imp_id" json:"replace_imp_id"`
+}
+
+// AccountContentClassification opts the account in to content classification, which the host configures with
+// content_classification.
+type AccountContentClassification struct {
+	Enabled bool `mapstructure:"enabled" json:"enabled"`
+}
+
+// AccountAdaptiveBidderTmax opts the account in to adaptive bidder tmax, which the host configures with
+// adaptive_bidder_tmax.
+type AccountAdaptiveBidderTmax struct {
+	Enabled bool `mapstructure:"enabled" json:"enabled"`
+}
+
+// AccountDebugToken configures the signed tokens which turn on debug output for the account's requests,
+// even if debug_allow is off.
+type AccountDebugToken struct {
+	// Keys are the HMAC-SHA256 keys tokens may be signed with. More than one may be given while keys are rotated.
+	Keys []string `mapstructure:"keys" json:"keys"`
+	// MaxTTLSeconds is the longest a token may be valid for.
+	MaxTTLSeconds int `mapstructure:"max_ttl_seconds" json:"max_ttl_seconds"`
+}
+
+func (dt *AccountDebugToken) validate(errs []error) []error {
+	for _, key := range dt.Keys {
+		if len(key) < 32 {
+			errs = append(errs, fmt.Errorf("account_defaults.debug_token.keys must be at least 32 characters long"))
+			break
+		}
+	}
+	if len(dt.Keys) > 0 && dt.MaxTTLSeconds <= 0 {
+		errs = append(errs, fmt.Errorf("account_defaults.debug_token.max_ttl_seconds must be positive. Got %d", dt.MaxTTLSeconds))
+	}
+	return errs
+}
+
+// Modes of checking the ads.cert Call Signs of an account's auction requests.
+const (
+	AdsCertVerifyOff     = "off"
+	AdsCertVerifyObserve = "observe"
+	AdsCertVerifyEnforce = "enforce"
+)
+
+// AccountAdsCert configures ads.cert Call Signs for the account's auctions. Signing and verification need
+// experiment.adscert to be set up.
+type AccountAdsCert struct {
+	// Sign adds Call Signs to the requests sent to bidders which support them, even if the auction request
+	// doesn't ask for them.
+	Sign bool `mapstructure:"sign" json:"sign"`
+	// Verify checks the Call Signs of the account's auction requests. Requests with a missing or invalid
+	// Call Sign are logged if it's observe, and rejected if it's enforce.
+	Verify string `mapstructure:"verify" json:"verify"`
+}
+
+// VerifyEnabled returns true if the Call Signs of the account's auction requests are checked.
+func (ac *AccountAdsCert) VerifyEnabled() bool {
+	return ac.Verify == AdsCertVerifyObserve || ac.Verify == AdsCertVerifyEnforce
+}
+
+func (ac *AccountAdsCert) validate(errs []error) []error {
+	if ac.Verify != "" && ac.Verify != AdsCertVerifyOff && !ac.VerifyEnabled() {
+		errs = append(errs, fmt.Errorf("account_defaults.adscert.verify must be %s, %s or %s. Got %s", AdsCertVerifyOff, AdsCertVerifyObserve, AdsCertVerifyEnforce, ac.Verify))
+	}
+	return errs
+}
+
+// AccountSChain sets the node this server appends to the supply chains of the account's bidder requests. The
+// asi and sid given replace those of host_schain_node, and the node is appended even if there's no host node.
+type AccountSChain struct {
+	ASI string `mapstructure:"asi" json:"asi"`
+	SID string `mapstructure:"sid" json:"sid"`
+	// Bidders replaces the asi and sid of the node for the requests sent to the given bidders.
+	Bidders map[string]AccountSChainNode `mapstructure:"bidders" json:"bidders"`
+}
+
+// AccountSChainNode is the asi and sid of the node appended to the supply chain of a bidder's requests.
+type AccountSChainNode struct {
+	ASI string `mapstructure:"asi" json:"asi"`
+	SID string `mapstructure:"sid" json:"sid"`
+}
+
+// Node returns the asi and sid of the node appended to the supply chain of the bidder's requests, and false
+// if the account doesn't set them.
+func (as *AccountSChain) Node(bidder string) (AccountSChainNode, bool) {
+	if node, ok := as.Bidders[bidder]; ok {
+		return node, true
+	}
+	if as.ASI != "" {
+		return AccountSChainNode{ASI: as.ASI, SID: as.SID}, true
+	}
+	return AccountSChainNode{}, false
+}
+
+func (as *AccountSChain) validate(errs []error) []error {
+	if (as.ASI == "") != (as.SID == "") {
+		errs = append(errs, errors.New("account_defaults.schain must set both asi and sid, or neither"))
+	}
+	for bidder, node := range as.Bidders {
+		if node.ASI == "" || node.SID == "" {
+			errs = append(errs, fmt.Errorf("account_defaults.schain.bidders.%s must set both asi and sid", bidder))
+		}
+	}
+	return errs
+}
+
+const (
+	OriginVerifyOff     = "off"
+	OriginVerifyObserve = "observe"
+	OriginVerifyEnforce = "enforce"
+)
+
+// AccountOrigin restricts the sites the account's requests may come from, so the account ID can't be used by
+// requests spoofing another domain.
+type AccountOrigin struct {
+	// Verify is off, observe, which logs requests from other sites, or enforce, which rejects them.
+	Verify string `mapstructure:"verify" json:"verify"`
+	// AllowedDomains are the domains the account's requests may come from. Their subdomains are allowed too.
+	AllowedDomains []string `mapstructure:"allowed_domains" json:"allowed_domains"`
+}
+
+// VerifyEnabled returns true if the origins of the account's requests are checked.
+func (ao *AccountOrigin) VerifyEnabled() bool {
+	return ao.Verify == OriginVerifyObserve || ao.Verify == OriginVerifyEnforce
+}
+
+// IsAllowed returns true if the domain is one of AllowedDomains, or a subdomain of one.
+func (ao *AccountOrigin) IsAllowed(domain string) bool {
+	domain = strings.TrimSuffix(strings.ToLower(domain), ".")
+	if domain == "" {
+		return false
+	}
+	for _, allowed := range ao.AllowedDomains {
+		allowed = strings.TrimSuffix(strings.ToLower(allowed), ".")
+		if domain == allowed || strings.HasSuffix(domain, "."+allowed) {
+			return true
+		}
+	}
+	return false
+}
+
+func (ao *AccountOrigin) validate(errs []error) []error {
+	if ao.Verify != "" && ao.Verify != OriginVerifyOff && !ao.VerifyEnabled() {
+		errs = append(errs, fmt.Errorf("account_defaults.origin.verify must be %s, %s or %s. Got %s", OriginVerifyOff, OriginVerifyObserve, OriginVerifyEnforce, ao.Verify))
+	}
+	for _, domain := range ao.AllowedDomains {
+		if domain == "" || strings.ContainsAny(domain, "/:* ") {
+			errs = append(errs, fmt.Errorf("account_defaults.origin.allowed_domains has an invalid domain: %q", domain))
+		}
+	}
+	return errs
+}
+
+// AccountRequestLimits caps the size and complexity of the account's requests, so that one integration's
+// oversized requests can't slow down the auctions of every other account. A limit of 0 means there's none.
+type AccountRequestLimits struct {
+	MaxImps         int   `mapstructure:"max_imps" json:"max_imps"`
+	MaxEIDs         int   `mapstructure:"max_eids" json:"max_eids"`
+	MaxDataSegments int   `mapstructure:"max_data_segments" json:"max_data_segments"`
+	MaxRequestSize  int64 `mapstructure:"max_request_size" json:"max_request_size"`
+}
+
+func (rl *AccountRequestLimits) validate(errs []error) []error {
+	if rl.MaxImps < 0 {
+		errs = append(errs, fmt.Errorf("account_defaults.request_limits.max_imps must be 0 or more. Got %d", rl.MaxImps))
+	}
+	if rl.MaxEIDs < 0 {
+		errs = append(errs, fmt.Errorf("account_defaults.request_limits.max_eids must be 0 or more. Got %d", rl.MaxEIDs))
+	}
+	if rl.MaxDataSegments < 0 {
+		errs = append(errs, fmt.Errorf("account_defaults.request_limits.max_data_segments must be 0 or more. Got %d", rl.MaxDataSegments))
+	}
+	if rl.MaxRequestSize < 0 {
+		errs = append(errs, fmt.Errorf("account_defaults.request_limits.max_request_size must be 0 or more. Got %d", rl.MaxRequestSize))
+	}
+	return errs
+}
+
+// AccountAPIKeys are the keys server to server callers present to make requests for the account. Only the
+// SHA-256 hashes of the keys are kept, so the account store doesn't hold the keys themselves.
+type AccountAPIKeys struct {
+	// Required rejects the account's requests which don't have a key. Requests with an unknown or revoked key
+	// are rejected either way.
+	Required bool            `mapstructure:"required" json:"required"`
+	Keys     []AccountAPIKey `mapstructure:"keys" json:"keys"`
+}
+
+// AccountAPIKey is a key of an account, which may be revoked by updating the account.
+type AccountAPIKey struct {
+	// ID names the key in metrics and logs, without giving it away.
+	ID string `mapstructure:"id" json:"id"`
+	// SHA256 is the hex encoded SHA-256 hash of the key.
+	SHA256 string `mapstructure:"sha256" json:"sha256"`
+	// RateLimit is the most requests per second the key may be used for, or 0 for no limit.
+	RateLimit int  `mapstructure:"rate_limit" json:"rate_limit"`
+	Revoked   bool `mapstructure:"revoked" json:"revoked"`
+}
+
+// Enabled returns true if the account's requests are checked for keys.
+func (ak *AccountAPIKeys) Enabled() bool {
+	return ak.Required || len(ak.Keys) > 0
+}
+
+// Find returns the key whose hash matches the key presented with a request.
+func (ak *AccountAPIKeys) Find(key string) (AccountAPIKey, bool) {
+	sum := sha256.Sum256([]byte(key))
+	for _, k := range ak.Keys {
+		hash, err := hex.DecodeString(k.SHA256)
+		if err == nil && subtle.ConstantTimeCompare(hash, sum[:]) == 1 {
+			return k, true
+		}
+	}
+	return AccountAPIKey{}, false
+}
+
+func (ak *AccountAPIKeys) validate(errs []error) []error {
+	ids := make(map[string]struct{}, len(ak.Keys))
+	for i, key := range ak.Keys {
+		if key.ID == "" {
+			errs = append(errs, fmt.Errorf("account_defaults.api_keys.keys[%d].id is required", i))
+		} else if _, ok := ids[key.ID]; ok {
+			errs = append(errs, fmt.Errorf("account_defaults.api_keys.keys[%d].id %s is not unique", i, key.ID))
+		}
+		ids[key.ID] = struct{}{}
+		if hash, err := hex.DecodeString(key.SHA256); err != nil || len(hash) != sha256.Size {
+			errs = append(errs, fmt.Errorf("account_defaults.api_keys.keys[%d].sha256 must be a hex encoded SHA-256 hash", i))
+		}
+		if key.RateLimit < 0 {
+			errs = append(errs, fmt.Errorf("account_defaults.api_keys.keys[%d].rate_limit must be 0 or more. Got %d", i, key.RateLimit))
+		}
+	}
+	return errs
+}
+
+// AccountDealPacing spreads the bids for the account's deals over time, so that a deal's budget isn't spent
+// early in the day. Once a deal has had its most bids in a window, it's taken out of the account's requests
+// until the next window. Bids are counted as they're returned in auction responses, since Prebid Server
+// doesn't see which of them serve.
+type AccountDealPacing struct {
+	// WindowSeconds is how long each pacing window is
+	WindowSeconds int `mapstructure:"window_seconds" json:"window_seconds"`
+	// Deals holds the most bids in a window, by deal ID
+	Deals map[string]int64 `mapstructure:"deals" json:"deals"`
+}
+
+func (dp *AccountDealPacing) validate(errs []error) []error {
+	if len(dp.Deals) == 0 {
+		return errs
+	}
+	if dp.WindowSeconds <= 0 {
+		errs = append(errs, fmt.Errorf("account_defaults.deal_pacing.window_seconds must be positive. Got %d", dp.WindowSeconds))
+	}
+	for deal, bids := range dp.Deals {
+		if bids <= 0 {
+			errs = append(errs, fmt.Errorf("account_defaults.deal_pacing.deals.%s must be positive. Got %d", deal, bids))
+		}
+	}
+	return errs
+}
+
+// AccountCreativeTrackers adds tracking pixels to the markup of the account's banner bids, and event trackers
+// to its native bids, so that their impressions are counted when they render, as they are for video.
+type AccountCreativeTrackers struct {
+	Enabled bool `mapstructure:"enabled" json:"enabled"`
+	// Events are the Prebid Server events, win and imp, whose URLs are added. They're only added to auctions
+	// which have events enabled.
+	Events []string `mapstructure:"events" json:"events"`
+	// URLs are more trackers to add, such as those of a measurement vendor. They may have macros, such as
+	// ##PBS-BIDID## and ##PBS-BIDDER##.
+	URLs []string `mapstructure:"urls" json:"urls"`
+}
+
+func (ct *AccountCreativeTrackers) validate(errs []error) []error {
+	if !ct.Enabled {
+		return errs
+	}
+	for i, event := range ct.Events {
+		if event != "win" && event != "imp" {
+			errs = append(errs, fmt.Errorf("account_defaults.creative_trackers.events[%d] must be win or imp. Got %s", i, event))
+		}
+	}
+	for i, url := range ct.URLs {
+		if !isValidURL(url) {
+			errs = append(errs, fmt.Errorf("account_defaults.creative_trackers.urls[%d] must be a valid URL. Got %s", i, url))
+		}
+	}
+	return errs
+}
+
+// AccountNativeTrackers adds the publisher's own impression and click trackers to the markup of the account's
+// native bids, so that its measurement endpoints see them as well as the bidder's.
+type AccountNativeTrackers struct {
+	Enabled bool `mapstructure:"enabled" json:"enabled"`
+	// ImpTrackers are fired on impression. They may have macros, such as ##PBS-BIDID## and ##PBS-BIDDER##.
+	ImpTrackers []string `mapstructure:"imp_trackers" json:"imp_trackers"`
+	// ClickTrackers are fired on click of the ad's link. They may have the same macros.
+	ClickTrackers []string `mapstructure:"click_trackers" json:"click_trackers"`
+}
+
+func (nt *AccountNativeTrackers) validate(errs []error) []error {
+	if !nt.Enabled {
+		return errs
+	}
+	for i, url := range nt.ImpTrackers {
+		if !isValidURL(url) {
+			errs = append(errs, fmt.Errorf("account_defaults.native_trackers.imp_trackers[%d] must be a valid URL. Got %s", i, url))
+		}
+	}
+	for i, url := range nt.ClickTrackers {
+		if !isValidURL(url) {
+			errs = append(errs, fmt.Errorf("account_defaults.native_trackers.click_trackers[%d] must be a valid URL. Got %s", i, url))
+		}
+	}
+	return errs
+}
+
+// AccountFirstPartyData governs how the first party data of the request, stored requests and modules is
+// merged at the paths of its rules. Data at other paths is merged as it always has been: the request
+// overrides stored requests, merging objects and replacing arrays, and modules override both.
+type AccountFirstPartyData struct {
+	Rules []AccountFPDRule `mapstructure:"rules" json:"rules"`
+}
+
+// AccountFPDRule is how the first party data at a path is merged.
+type AccountFPDRule struct {
+	// Path is the dot separated path of the data in the request, such as user.ext.data or site.content.data.
+	// Paths starting with imp apply to each imp, such as imp.ext.data.
+	Path string `mapstructure:"path" json:"path"`
+	// Precedence lists the sources, request, stored and module, from the one which wins conflicts to the one
+	// which loses them. Sources left out lose to the ones listed, in the default order of module, request and
+	// stored.
+	Precedence []string `mapstructure:"precedence" json:"precedence"`
+	// Arrays is replace, where the array of the winning source replaces the others, or merge, where the
+	// arrays of every source are concatenated in order of precedence, without duplicates.
+	Arrays string `mapstructure:"arrays" json:"arrays"`
+	// MaxBytes caps the size of the merged data. Data over the cap is left out of the request, with a warning.
+	MaxBytes int `mapstructure:"max_bytes" json:"max_bytes"`
+}
+
+var fpdRulePathRoots = map[string]bool{"site": true, "app": true, "dooh": true, "user": true, "imp": true}
+
+var fpdSources = map[string]bool{"request": true, "stored": true, "module": true}
+
+func (fpd *AccountFirstPartyData) validate(errs []error) []error {
+	for i, rule := range fpd.Rules {
+		path := strings.Split(rule.Path, ".")
+		if len(path) < 2 || !fpdRulePathRoots[path[0]] {
+			errs = append(errs, fmt.Errorf("account_defaults.first_party_data.rules[%d].path must be a path within site, app, dooh, user or imp. Got %s", i, rule.Path))
+		}
+		seen := make(map[string]bool, len(rule.Precedence))
+		for _, source := range rule.Precedence {
+			if !fpdSources[source] || seen[source] {
+				errs = append(errs, fmt.Errorf("account_defaults.first_party_data.rules[%d].precedence must list request, stored and module at most once each. Got %v", i, rule.Precedence))
+				break
+			}
+			seen[source] = true
+		}
+		if rule.Arrays != "" && rule.Arrays != "replace" && rule.Arrays != "merge" {
+			errs = append(errs, fmt.Errorf("account_defaults.first_party_data.rules[%d].arrays must be replace or merge. Got %s", i, rule.Arrays))
+		}
+		if rule.MaxBytes < 0 {
+			errs = append(errs, fmt.Errorf("account_defaults.first_party_data.rules[%d].max_bytes must be >= 0. Got %d", i, rule.MaxBytes))
+		}
+	}
+	return errs
+}
+
+// AccountDefaultRequest is the account's layer of default requests. They're merged over the host's
+// default_request, and under stored requests, as the host's default_request.merge says.
+type AccountDefaultRequest struct {
+	// Request is merged into all of the account's auction requests.
+	Request json.RawMessage `mapstructure:"request" json:"request,omitempty"`
+	// Channels are merged into the account's auction requests of each integration type, web, app or dooh,
+	// over Request.
+	Channels map[string]json.RawMessage `mapstructure:"channels" json:"channels,omitempty"`
+}
+
+// Empty returns true if the account has no default requests.
+func (dr *AccountDefaultRequest) Empty() bool {
+	return len(dr.Request) == 0 && len(dr.Channels) == 0
+}
+
+func (dr *AccountDefaultRequest) validate(errs []error) []error {
+	if len(dr.Request) > 0 && !isJSONObject(dr.Request) {
+		errs = append(errs, errors.New("account_defaults.default_request.request must be a JSON object"))
+	}
+	for channel, request := range dr.Channels {
+		if channel != string(ChannelWeb) && channel != string(ChannelApp) && channel != string(ChannelDOOH) {
+			errs = append(errs, fmt.Errorf("account_defaults.default_request.channels must be web, app or dooh. Got %s", channel))
+		} else if !isJSONObject(request) {
+			errs = append(errs, fmt.Errorf("account_defaults.default_request.channels.%s must be a JSON object", channel))
+		}
+	}
+	return errs
+}
+
+func isJSONObject(data json.RawMessage) bool {
+	var object map[string]json.RawMessage
+	return json.Unmarshal(data, &object) == nil && object != nil
+}
+
+// AccountSizeResolution governs how the formats of banner imps are resolved from the device's screen.
+// Interstitials which ask for it in device.ext.prebid.interstitial are always resolved.
+type AccountSizeResolution struct {
+	// Enabled also resolves banner imps which have no size, or only the 1x1 format, to the sizes which fit
+	// the device's screen.
+	Enabled bool `mapstructure:"enabled" json:"enabled"`
+	// Catalog lists the sizes which may be resolved, in order of preference. The host's interstitial sizes
+	// are used if it's empty.
+	Catalog []AccountSize `mapstructure:"catalog" json:"catalog"`
+	// Adaptive lists fluid sizes, which are added to the resolved formats if they fit the screen.
+	Adaptive []AccountAdaptiveSize `mapstructure:"adaptive" json:"adaptive"`
+	// MaxFormats caps the number of catalog sizes resolved for an imp. Defaults to 10.
+	MaxFormats int `mapstructure:"max_formats" json:"max_formats"`
+}
+
+// AccountSize is a size of the catalog.
+type AccountSize struct {
+	W int64 `mapstructure:"w" json:"w"`
+	H int64 `mapstructure:"h" json:"h"`
+	// Orientation limits the size to screens in portrait or landscape orientation.
+	Orientation string `mapstructure:"orientation" json:"orientation"`
+}
+
+// AccountAdaptiveSize is a fluid size, whose width and height scale with the ratio of WRatio to HRatio, and
+// whose width is at least WMin.
+type AccountAdaptiveSize struct {
+	WRatio      int64  `mapstructure:"wratio" json:"wratio"`
+	HRatio      int64  `mapstructure:"hratio" json:"hratio"`
+	WMin        int64  `mapstructure:"wmin" json:"wmin"`
+	Orientation string `mapstructure:"orientation" json:"orientation"`
+}
+
+func (sr *AccountSizeResolution) validate(errs []error) []error {
+	for i, size := range sr.Catalog {
+		if size.W <= 0 || size.H <= 0 {
+			errs = append(errs, fmt.Errorf("account_defaults.size_resolution.catalog[%d] must have a positive w and h. Got %dx%d", i, size.W, size.H))
+		}
+		if !isValidOrientation(size.Orientation) {
+			errs = append(errs, fmt.Errorf("account_defaults.size_resolution.catalog[%d].orientation must be portrait or landscape. Got %s", i, size.Orientation))
+		}
+	}
+	for i, size := range sr.Adaptive {
+		if size.WRatio <= 0 || size.HRatio <= 0 {
+			errs = append(errs, fmt.Errorf("account_defaults.size_resolution.adaptive[%d] must have a positive wratio and hratio. Got %d:%d", i, size.WRatio, size.HRatio))
+		}
+		if size.WMin < 0 {
+			errs = append(errs, fmt.Errorf("account_defaults.size_resolution.adaptive[%d].wmin must be >= 0. Got %d", i, size.WMin))
+		}
+		if !isValidOrientation(size.Orientation) {
+			errs = append(errs, fmt.Errorf("account_defaults.size_resolution.adaptive[%d].orientation must be portrait or landscape. Got %s", i, size.Orientation))
+		}
+	}
+	if sr.MaxFormats < 0 {
+		errs = append(errs, fmt.Errorf("account_defaults.size_resolution.max_formats must be >= 0. Got %d", sr.MaxFormats))
+	}
+	return errs
+}
+
+func isValidOrientation(orientation string) bool {
+	return orientation == "" || orientation == "portrait" || orientation == "landscape"
+}
+
+// AccountDynamicTmax adjusts the tmax of the account's requests by their channel and the connection type of
+// their device, since a tmax which suits app users on 5G leaves mobile web users on 3G without bids.
+type AccountDynamicTmax struct {
+	Enabled bool `mapstructure:"enabled" json:"enabled"`
+	// MinMS and MaxMS bound the adjusted tmax. Use 0 for no bound.
+	MinMS int64 `mapstructure:"min_ms" json:"min_ms"`
+	MaxMS int64 `mapstructure:"max_ms" json:"max_ms"`
+	// ChannelMS is added to the tmax of requests from each channel: web, app, amp or dooh.
+	ChannelMS map[string]int64 `mapstructure:"channel_ms" json:"channel_ms"`
+	// ConnectionTypeMS is added to the tmax of requests whose device has each connection type: ethernet,
+	// wifi, cellular, 2g, 3g, 4g or 5g. Cellular is for cellular connections of an unknown generation.
+	ConnectionTypeMS map[string]int64 `mapstructure:"connection_type_ms" json:"connection_type_ms"`
+}
+
+var dynamicTmaxChannels = map[string]bool{string(ChannelWeb): true, string(ChannelApp): true, string(ChannelAMP): true, string(ChannelDOOH): true}
+
+var dynamicTmaxConnectionTypes = map[string]bool{"ethernet": true, "wifi": true, "cellular": true, "2g": true, "3g": true, "4g": true, "5g": true}
+
+func (dt *AccountDynamicTmax) validate(errs []error) []error {
+	if dt.MinMS < 0 {
+		errs = append(errs, fmt.Errorf("account_defaults.dynamic_tmax.min_ms must be >= 0. Got %d", dt.MinMS))
+	}
+	if dt.MaxMS < 0 {
+		errs = append(errs, fmt.Errorf("account_defaults.dynamic_tmax.max_ms must be >= 0. Got %d", dt.MaxMS))
+	}
+	if dt.MaxMS > 0 && dt.MaxMS < dt.MinMS {
+		errs = append(errs, fmt.Errorf("account_defaults.dynamic_tmax.max_ms cannot be less than min_ms. max_ms=%d, min_ms=%d", dt.MaxMS, dt.MinMS))
+	}
+	for channel := range dt.ChannelMS {
+		if !dynamicTmaxChannels[channel] {
+			errs = append(errs, fmt.Errorf("account_defaults.dynamic_tmax.channel_ms has an unknown channel %s. It must be web, app, amp or dooh", channel))
+		}
+	}
+	for connectionType := range dt.ConnectionTypeMS {
+		if !dynamicTmaxConnectionTypes[connectionType] {
+			errs = append(errs, fmt.Errorf("account_defaults.dynamic_tmax.connection_type_ms has an unknown connection type %s. It must be ethernet, wifi, cellular, 2g, 3g, 4g or 5g", connectionType))
+		}
+	}
+	return errs
+}
+
+// AccountEventWebhook sends the win, loss and render events of the account's bids, as received by /event,
+// to the publisher's endpoint, so they can reconcile revenue without building an analytics adapter.
+type AccountEventWebhook struct {
+	Enabled bool   `mapstructure:"enabled" json:"enabled"`
+	URL     string `mapstructure:"url" json:"url"`
+	// Secret is the key the HMAC-SHA256 signatures of the batches are made with
+	Secret string `mapstructure:"secret" json:"secret"`
+	// Events are the types of events sent: win, loss, imp or vast. Every type is sent if none are given.
+	Events []string `mapstructure:"events" json:"events"`
+}
+
+var eventWebhookTypes = map[string]bool{"win": true, "loss": true, "imp": true, "vast": true}
+
+// Sends reports whether events of the type are sent to the webhook.
+func (ew *AccountEventWebhook) Sends(eventType string) bool {
+	if !ew.Enabled {
+		return false
+	}
+	if len(ew.Events) == 0 {
+		return true
+	}
+	for _, t := range ew.Events {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+func (ew *AccountEventWebhook) validate(errs []error) []error {
+	if !ew.Enabled {
+		return errs
+	}
+	if webhookURL, err := url.Parse(ew.URL); err != nil || (webhookURL.Scheme != "http" && webhookURL.Scheme != "https") || webhookURL.Host == "" {
+		errs = append(errs, fmt.Errorf("account_defaults.event_webhook.url must be an http or https URL. Got %s", ew.URL))
+	}
+	if ew.Secret == "" {
+		errs = append(errs, errors.New("account_defaults.event_webhook.secret is required"))
+	}
+	for _, eventType := range ew.Events {
+		if !eventWebhookTypes[eventType] {
+			errs = append(errs, fmt.Errorf("account_defaults.event_webhook.events has an unknown event type %s. It must be win, loss, imp or vast", eventType))
+		}
+	}
+	return errs
+}
+
+// AccountIVT sets the account's policy for the invalid traffic found by the host's ivt screening.
+type AccountIVT struct {
+	// Action is block or tag, and overrides ivt.action for the account's requests. The host's action is taken
+	// if it's empty.
+	Action string `mapstructure:"action" json:"action"`
+}
+
+func (ivt *AccountIVT) validate(errs []error) []error {
+	if ivt.Action != "" && ivt.Action != IVTActionBlock && ivt.Action != IVTActionTag {
+		errs = append(errs, fmt.Errorf("account_defaults.ivt.action must be %s or %s. Got %s", IVTActionBlock, IVTActionTag, ivt.Action))
+	}
+	return errs
+}
+
+// AccountMultiformat is the account's policy for imps which offer more than one media type, so that it doesn't
+// depend on how each bidder's adapter picks among them.
+type AccountMultiformat struct {
+	// PreferredMediaTypes orders the media types from most to least preferred. It picks the media type sent
+	// to bidders which take one per imp, and the bid which wins when bids for an imp have the same price.
+	// Media types it leaves out come after those it lists, in the order banner, video, native and audio.
+	PreferredMediaTypes []string `mapstructure:"preferred_media_types" json:"preferred_media_types"`
+	// Bidders overrides the policy for bidders, by bidder name.
+	Bidders map[string]AccountMultiformatBidder `mapstructure:"bidders" json:"bidders"`
+}
+
+// AccountMultiformatBidder is the policy for the multiformat imps sent to a bidder.
+type AccountMultiformatBidder struct {
+	// PreferredMediaTypes overrides the account's order for the bidder, if it isn't empty.
+	PreferredMediaTypes []string `mapstructure:"preferred_media_types" json:"preferred_media_types"`
+	// SingleFormat sends the bidder only the preferred media type of each multiformat imp, as is done for
+	// bidders whose info says they don't support multiformat imps.
+	SingleFormat bool `mapstructure:"single_format" json:"single_format"`
+}
+
+func (mf *AccountMultiformat) validate(errs []error) []error {
+	errs = validateMediaTypes("account_defaults.multiformat.preferred_media_types", mf.PreferredMediaTypes, errs)
+	for bidder, policy := range mf.Bidders {
+		errs = validateMediaTypes(fmt.Sprintf("account_defaults.multiformat.bidders.%s.preferred_media_types", bidder), policy.PreferredMediaTypes, errs)
+	}
+	return errs
+}
+
+func validateMediaTypes(field string, mediaTypes []string, errs []error) []error {
+	for _, mediaType := range mediaTypes {
+		switch openrtb_ext.BidType(mediaType) {
+		case openrtb_ext.BidTypeBanner, openrtb_ext.BidTypeVideo, openrtb_ext.BidTypeNative, openrtb_ext.BidTypeAudio:
+		default:
+			errs = append(errs, fmt.Errorf("%s must only hold banner, video, native or audio. Got %s", field, mediaType))
+		}
+	}
+	return errs
+}
+
+// AccountBidderFields withholds fields of the requests sent to bidders, beyond what the privacy policies and
+// activities remove, such as device.ifa from partners who mustn't get it.
+type AccountBidderFields struct {
+	// Bidders maps bidder names to the fields withheld from them. The policy of "*" applies to bidders
+	// without one of their own.
+	Bidders map[string]AccountBidderFieldPolicy `mapstructure:"bidders" json:"bidders"`
+}
+
+// AccountBidderFieldPolicy lists fields by their dotted path in the request, such as device.ifa or
+// user.ext.eids. A * in a path stands for every member of an object or element of a list, as in
+// imp.*.ext.data.
+type AccountBidderFieldPolicy struct {
+	// Allow limits each object holding one of the paths to the members it lists. Objects without a listed
+	// path keep all of their members.
+	Allow []string `mapstructure:"allow" json:"allow"`
+	// Deny removes the paths.
+	Deny []string `mapstructure:"deny" json:"deny"`
+}
+
+// Policy returns the policy for the bidder, and whether there's one.
+func (bf *AccountBidderFields) Policy(bidder string) (AccountBidderFieldPolicy, bool) {
+	if policy, ok := bf.Bidders[bidder]; ok {
+		return policy, true
+	}
+	policy, ok := bf.Bidders["*"]
+	return policy, ok
+}
+
+func (bf *AccountBidderFields) validate(errs []error) []error {
+	for bidder, policy := range bf.Bidders {
+		for _, path := range policy.Allow {
+			if !strings.Contains(path, ".") || hasEmptySegment(path) {
+				errs = append(errs, fmt.Errorf("account_defaults.bidder_fields.bidders.%s.allow must hold paths of members within objects of the request, such as device.ifa. Got %s", bidder, path))
+			}
+		}
+		for _, path := range policy.Deny {
+			if hasEmptySegment(path) {
+				errs = append(errs, fmt.Errorf("account_defaults.bidder_fields.bidders.%s.deny must hold dotted paths, such as device.ifa. Got %s", bidder, path))
+			}
+		}
+	}
+	return errs
+}
+
+func hasEmptySegment(path string) bool {
+	return strings.Contains("."+path+".", "..")
+}
+
+// AccountResponseSigning signs the account's auction responses, so that wrappers and SDKs can verify they
+// weren't changed on the way.
+type AccountResponseSigning struct {
+	Enabled bool `mapstructure:"enabled" json:"enabled"`
+	// KeyID is the ID of the host's response_signing key the responses are signed with.
+	KeyID string `mapstructure:"key_id" json:"key_id"`
+}
+
+func (rs *AccountResponseSigning) validate(errs []error) []error {
+	if rs.Enabled && rs.KeyID == "" {
+		errs = append(errs, errors.New("account_defaults.response_signing.key_id is required to sign responses"))
+	}
+	return errs
+}
+
+// AccountClientHints fills in device.sua, and the device's os, osv and model where they're missing, from the
+// User-Agent Client Hints of the account's requests.
+type AccountClientHints struct {
+	Enabled bool `mapstructure:"enabled" json:"enabled"`
+}
+
+// AccountDebugAccess restricts which of the account's requests may get debug output, and samples requests
+// whose debug output is logged to analytics.
+type AccountDebugAccess struct {
+	// AllowedIPs are the IP addresses and CIDR blocks requests may get debug output from. If neither
+	// AllowedIPs nor AllowedTokens are given, any request may.
+	AllowedIPs []string `mapstructure:"allowed_ips" json:"allowed_ips"`
+	// AllowedTokens are the values of the X-Pbs-Debug-Override header which let requests from other
+	// addresses get debug output.
+	AllowedTokens []string `mapstructure:"allowed_tokens" json:"allowed_tokens"`
+	// AnalyticsSampleRate is the share of requests which are auctioned with debug output on, so it can
+	// be logged to analytics. It's removed from the response unless the request asked for it.
+	AnalyticsSampleRate float64 `mapstructure:"analytics_sample_rate" json:"analytics_sample_rate"`
+}
+
+// IsRestricted returns true if only some requests may get debug output.
+func (da *AccountDebugAccess) IsRestricted() bool {
+	return len(da.AllowedIPs) > 0 || len(da.AllowedTokens) > 0
+}
+
+// IsAllowed returns true if a request from ip, with the given X-Pbs-Debug-Override header, may get debug
+// output. Entries in AllowedIPs which aren't IP addresses or CIDR blocks match nothing.
+func (da *AccountDebugAccess) IsAllowed(ip net.IP, token string) bool {
+	if !da.IsRestricted() {
+		return true
+	}
+	if token != "" {
+		for _, allowed := range da.AllowedTokens {
+			if subtle.ConstantTimeCompare([]byte(token), []byte(allowed)) == 1 {
+				return true
+			}
+		}
+	}
+	if ip == nil {
+		return false
+	}
+	for _, allowed := range da.AllowedIPs {
+		if _, block, err := net.ParseCIDR(allowed); err == nil {
+			if block.Contains(ip) {
+				return true
+			}
+		} else if ip.Equal(net.ParseIP(allowed)) {
+			return true
+		}
+	}
+	return false
+}
+
+func (da *AccountDebugAccess) validate(errs []error) []error {
+	for _, allowed := range da.AllowedIPs {
+		if _, _, err := net.ParseCIDR(allowed); err != nil && net.ParseIP(allowed) == nil {
+			errs = append(errs, fmt.Errorf("account_defaults.debug_access.allowed_ips must be IP addresses or CIDR blocks. Got %s", allowed))
+		}
+	}
+	for _, token := range da.AllowedTokens {
+		if len(token) < 32 {
+			errs = append(errs, fmt.Errorf("account_defaults.debug_access.allowed_tokens must be at least 32 characters long"))
+			break
+		}
+	}
+	if da.AnalyticsSampleRate < 0 || da.AnalyticsSampleRate > 1 {
+		errs = append(errs, fmt.Errorf("account_defaults.debug_access.analytics_sample_rate must be in the range [0, 1]. Got %f", da.AnalyticsSampleRate))
+	}
+	return errs
 }
 
 // CookieSync represents the account-level defaults for the cookie sync endpoint.
@@ -254,6 +1128,35 @@ func (a *AccountGDPR) PurposeOneTreatmentAccessAllowed() (value, exists bool) {
 	return *a.PurposeOneTreatment.AccessAllowed, true
 }
 
+// PurposeOneTreatmentEnabledForCountry gets the purpose one treatment enabled setting of the account for consent
+// strings of publishers in the country, falling back to the account level setting. It returns the value and
+// whether or not it is set.
+func (a *AccountGDPR) PurposeOneTreatmentEnabledForCountry(country string) (value, exists bool) {
+	if treatment, ok := a.PurposeOneTreatment.Countries[country]; ok && treatment.Enabled != nil {
+		return *treatment.Enabled, true
+	}
+	return a.PurposeOneTreatmentEnabled()
+}
+
+// PurposeOneTreatmentAccessAllowedForCountry gets the purpose one treatment access allowed setting of the account
+// for consent strings of publishers in the country, falling back to the account level setting. It returns the
+// value and whether or not it is set.
+func (a *AccountGDPR) PurposeOneTreatmentAccessAllowedForCountry(country string) (value, exists bool) {
+	if treatment, ok := a.PurposeOneTreatment.Countries[country]; ok && treatment.AccessAllowed != nil {
+		return *treatment.AccessAllowed, true
+	}
+	return a.PurposeOneTreatmentAccessAllowed()
+}
+
+// PurposeRestrictedVendors returns the vendors the account restricts from the specified purpose, or a nil map if
+// there are none.
+func (a *AccountGDPR) PurposeRestrictedVendors(purpose consentconstants.Purpose) map[string]struct{} {
+	if c, exists := a.PurposeConfigs[purpose]; exists {
+		return c.RestrictedVendorMap
+	}
+	return nil
+}
+
 // AccountGDPRPurpose represents account-specific GDPR purpose configuration
 type AccountGDPRPurpose struct {
 	EnforceAlgo string `mapstructure:"enforce_algo" json:"enforce_algo,omitempty"`
@@ -264,6 +1167,11 @@ type AccountGDPRPurpose struct {
 	// Array of vendor exceptions that is used to create the hash table VendorExceptionMap so vendor names can be instantly accessed
 	VendorExceptions   []string `mapstructure:"vendor_exceptions" json:"vendor_exceptions"`
 	VendorExceptionMap map[string]struct{}
+	// Array of vendors the publisher restricts from the purpose, whatever the consent string allows them, that is
+	// used to create the hash table RestrictedVendorMap. These act as publisher restrictions of type "not allowed"
+	// which the publisher's CMP doesn't encode.
+	RestrictedVendors   []string `mapstructure:"restricted_vendors" json:"restricted_vendors"`
+	RestrictedVendorMap map[string]struct{}
 }
 
 // AccountGDPRSpecialFeature represents account-specific GDPR special feature configuration
@@ -276,8 +1184,17 @@ type AccountGDPRSpecialFeature struct {
 
 // AccountGDPRPurposeOneTreatment represents account-specific GDPR purpose one treatment configuration
 type AccountGDPRPurposeOneTreatment struct {
-	Enabled       *bool `mapstructure:"enabled"`
-	AccessAllowed *bool `mapstructure:"access_allowed"`
+	Enabled       *bool `mapstructure:"enabled" json:"enabled,omitempty"`
+	AccessAllowed *bool `mapstructure:"access_allowed" json:"access_allowed,omitempty"`
+	// Countries override the purpose one treatment for consent strings of publishers in a country, keyed by the
+	// upper case ISO 3166-1 alpha-2 code the consent string has as its publisher country.
+	Countries map[string]AccountGDPRPurposeOneTreatmentCountry `mapstructure:"countries" json:"countries,omitempty"`
+}
+
+// AccountGDPRPurposeOneTreatmentCountry represents the purpose one treatment of an account for a publisher country
+type AccountGDPRPurposeOneTreatmentCountry struct {
+	Enabled       *bool `mapstructure:"enabled" json:"enabled,omitempty"`
+	AccessAllowed *bool `mapstructure:"access_allowed" json:"access_allowed,omitempty"`
 }
 
 // AccountChannel indicates whether a particular privacy policy (GDPR, CCPA) is enabled for each channel type
@@ -313,6 +1230,8 @@ func (a *AccountChannel) GetByChannelType(channelType ChannelType) *bool {
 type AccountHooks struct {
 	Modules       AccountModules    `mapstructure:"modules" json:"modules"`
 	ExecutionPlan HookExecutionPlan `mapstructure:"execution_plan" json:"execution_plan"`
+	// Canaries replace the host's module canaries for the same modules.
+	Canaries []ModuleCanary `mapstructure:"canaries" json:"canaries"`
 }
 
 // AccountModules mapping provides account-level module configuration
@@ -372,3 +1291,210 @@ func (ip *IPv4) Validate(errs []error) []error {
 	}
 	return errs
 }
+
+// AccountLineItems matches the account's bids against its direct-sold line items. Bids which match one are
+// tagged with it, and win the auction over the bids of lower priority line items and bids which match none,
+// whatever their price, so direct-sold demand can be prioritized without an ad server making the decision.
+type AccountLineItems struct {
+	Enabled bool              `mapstructure:"enabled" json:"enabled"`
+	Items   []AccountLineItem `mapstructure:"items" json:"items"`
+}
+
+// AccountLineItem is a line item and the bids it targets. A bid matches it if it matches each of its targeting
+// lists which isn't empty.
+type AccountLineItem struct {
+	ID   string `mapstructure:"id" json:"id"`
+	Name string `mapstructure:"name" json:"name"`
+	// Priority ranks the line item against the others, from 1 for the highest priority
+	Priority int `mapstructure:"priority" json:"priority"`
+	// Bidders are the bidders whose bids it takes
+	Bidders []string `mapstructure:"bidders" json:"bidders"`
+	// DealIDs are the deals whose bids it takes
+	DealIDs []string `mapstructure:"deal_ids" json:"deal_ids"`
+	// Sizes are the sizes of the bids it takes, such as 300x250
+	Sizes []string `mapstructure:"sizes" json:"sizes"`
+	// Countries are the countries of the devices whose bids it takes, as ISO 3166-1 alpha-3 codes
+	Countries []string `mapstructure:"countries" json:"countries"`
+}
+
+// lineItemSizePattern matches a size such as 300x250.
+var lineItemSizePattern = regexp.MustCompile(`^[1-9][0-9]*x[1-9][0-9]*$`)
+
+func (li *AccountLineItems) validate(errs []error) []error {
+	if !li.Enabled {
+		return errs
+	}
+	ids := make(map[string]bool, len(li.Items))
+	for i, item := range li.Items {
+		if item.ID == "" {
+			errs = append(errs, fmt.Errorf("account_defaults.line_items.items[%d].id must be set", i))
+		} else if ids[item.ID] {
+			errs = append(errs, fmt.Errorf("account_defaults.line_items.items[%d].id must be unique. Got %s", i, item.ID))
+		}
+		ids[item.ID] = true
+		if item.Priority < 1 {
+			errs = append(errs, fmt.Errorf("account_defaults.line_items.items[%d].priority must be >= 1. Got %d", i, item.Priority))
+		}
+		for _, size := range item.Sizes {
+			if !lineItemSizePattern.MatchString(size) {
+				errs = append(errs, fmt.Errorf("account_defaults.line_items.items[%d].sizes must be sizes such as 300x250. Got %s", i, size))
+			}
+		}
+	}
+	return errs
+}
+
+// AccountPriceEncryption sends the price of each bid to the ad server encrypted, in the hb_pb_enc targeting key,
+// so it can bill the exact price without the price being readable on the page.
+type AccountPriceEncryption struct {
+	Enabled bool `mapstructure:"enabled" json:"enabled"`
+	// EncryptionKey and IntegrityKey are the keys the ad server decrypts prices with, base64 or base64url
+	// encoded, as ad servers give them out.
+	EncryptionKey string `mapstructure:"encryption_key" json:"encryption_key"`
+	IntegrityKey  string `mapstructure:"integrity_key" json:"integrity_key"`
+}
+
+// minPriceEncryptionKeySize is the fewest bytes a price encryption key may have.
+const minPriceEncryptionKeySize = 16
+
+// Keys returns the decoded encryption and integrity keys.
+func (pe *AccountPriceEncryption) Keys() (encryptionKey []byte, integrityKey []byte, err error) {
+	if encryptionKey, err = decodePriceEncryptionKey("encryption_key", pe.EncryptionKey); err != nil {
+		return nil, nil, err
+	}
+	if integrityKey, err = decodePriceEncryptionKey("integrity_key", pe.IntegrityKey); err != nil {
+		return nil, nil, err
+	}
+	return encryptionKey, integrityKey, nil
+}
+
+func decodePriceEncryptionKey(name, key string) ([]byte, error) {
+	key = strings.NewReplacer("+", "-", "/", "_").Replace(strings.TrimRight(key, "="))
+	decoded, err := base64.RawURLEncoding.DecodeString(key)
+	if err != nil || len(decoded) < minPriceEncryptionKeySize {
+		return nil, fmt.Errorf("account_defaults.price_encryption.%s must be a base64 encoded key of at least %d bytes", name, minPriceEncryptionKeySize)
+	}
+	return decoded, nil
+}
+
+func (pe *AccountPriceEncryption) validate(errs []error) []error {
+	if !pe.Enabled {
+		return errs
+	}
+	if _, err := decodePriceEncryptionKey("encryption_key", pe.EncryptionKey); err != nil {
+		errs = append(errs, err)
+	}
+	if _, err := decodePriceEncryptionKey("integrity_key", pe.IntegrityKey); err != nil {
+		errs = append(errs, err)
+	}
+	return errs
+}
+
+// Periods usage quotas may be set for. They start on the hour, or at midnight UTC.
+const (
+	UsageQuotaPeriodHour = "hour"
+	UsageQuotaPeriodDay  = "day"
+)
+
+// AccountUsageQuota caps the account's billable usage in each period. Once the account has used up any of
+// its quotas, its auctions are rejected until the next period starts. Quotas which are 0 aren't enforced.
+type AccountUsageQuota struct {
+	// Period is hour or day
+	Period         string `mapstructure:"period" json:"period"`
+	MaxAuctions    int64  `mapstructure:"max_auctions" json:"max_auctions"`
+	MaxImps        int64  `mapstructure:"max_imps" json:"max_imps"`
+	MaxBidderCalls int64  `mapstructure:"max_bidder_calls" json:"max_bidder_calls"`
+}
+
+// Enabled returns true if any of the quotas is enforced.
+func (uq *AccountUsageQuota) Enabled() bool {
+	return uq.MaxAuctions > 0 || uq.MaxImps > 0 || uq.MaxBidderCalls > 0
+}
+
+// Window returns the length of the period, or 0 if the period isn't known.
+func (uq *AccountUsageQuota) Window() time.Duration {
+	switch uq.Period {
+	case UsageQuotaPeriodHour:
+		return time.Hour
+	case UsageQuotaPeriodDay:
+		return 24 * time.Hour
+	}
+	return 0
+}
+
+func (uq *AccountUsageQuota) validate(errs []error) []error {
+	if uq.MaxAuctions < 0 {
+		errs = append(errs, fmt.Errorf("account_defaults.usage_quota.max_auctions must be >= 0. Got %d", uq.MaxAuctions))
+	}
+	if uq.MaxImps < 0 {
+		errs = append(errs, fmt.Errorf("account_defaults.usage_quota.max_imps must be >= 0. Got %d", uq.MaxImps))
+	}
+	if uq.MaxBidderCalls < 0 {
+		errs = append(errs, fmt.Errorf("account_defaults.usage_quota.max_bidder_calls must be >= 0. Got %d", uq.MaxBidderCalls))
+	}
+	if uq.Enabled() && uq.Window() == 0 {
+		errs = append(errs, fmt.Errorf("account_defaults.usage_quota.period must be %s or %s. Got %s", UsageQuotaPeriodHour, UsageQuotaPeriodDay, uq.Period))
+	}
+	return errs
+}
+
+// AccountCurrency overrides the rates of the currency converter for the account's auctions, for publishers
+// who've agreed fixed exchange rates with their buyers.
+type AccountCurrency struct {
+	// Rates maps each currency to the rates of the currencies it converts to, the way the currency file does.
+	// They take priority over the rates of the converter, but not over the rates of the auction request.
+	Rates map[string]map[string]float64 `mapstructure:"rates" json:"rates"`
+	// Selection is how the currency of the bid response is chosen from the currencies of the auction request.
+	// If it's most_bids, the bids are converted to the currency most of them were made in, rather than to the
+	// first currency of the request, and the rate applied to each bid is given in seatbid.ext.
+	Selection string `mapstructure:"selection" json:"selection"`
+}
+
+// Ways of choosing the currency of the bid response from the currencies of the auction request.
+const (
+	CurrencySelectionFirst    = "first"
+	CurrencySelectionMostBids = "most_bids"
+)
+
+// SelectsMostBids returns true if the bids are converted to the currency most of them were made in.
+func (ac *AccountCurrency) SelectsMostBids() bool {
+	return ac.Selection == CurrencySelectionMostBids
+}
+
+// NormalizeRates upper cases the currency codes of the rates, since the config keys are lower cased.
+func (ac *AccountCurrency) NormalizeRates() {
+	if len(ac.Rates) == 0 {
+		return
+	}
+	normalized := make(map[string]map[string]float64, len(ac.Rates))
+	for from, rates := range ac.Rates {
+		from = strings.ToUpper(from)
+		if normalized[from] == nil {
+			normalized[from] = make(map[string]float64, len(rates))
+		}
+		for to, rate := range rates {
+			normalized[from][strings.ToUpper(to)] = rate
+		}
+	}
+	ac.Rates = normalized
+}
+
+func (ac *AccountCurrency) validate(errs []error) []error {
+	if ac.Selection != "" && ac.Selection != CurrencySelectionFirst && !ac.SelectsMostBids() {
+		errs = append(errs, fmt.Errorf("account_defaults.currency.selection must be %s or %s. Got %s", CurrencySelectionFirst, CurrencySelectionMostBids, ac.Selection))
+	}
+	for from, rates := range ac.Rates {
+		if _, err := currency.ParseISO(from); err != nil {
+			errs = append(errs, fmt.Errorf("account_defaults.currency.rates has an unknown currency %s", from))
+		}
+		for to, rate := range rates {
+			if _, err := currency.ParseISO(to); err != nil {
+				errs = append(errs, fmt.Errorf("account_defaults.currency.rates.%s has an unknown currency %s", from, to))
+			}
+			if rate <= 0 {
+				errs = append(errs, fmt.Errorf("account_defaults.currency.rates.%s.%s must be > 0. Got %g", from, to, rate))
+			}
+		}
+	}
+	return errs
+}
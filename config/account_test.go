@@ -3,7 +3,9 @@ package config
 import (
 	"encoding/json"
 	"errors"
+	"net"
 	"testing"
+	"time"
 
 	"github.com/prebid/go-gdpr/consentconstants"
 	"github.com/prebid/prebid-server/v2/openrtb_ext"
@@ -969,6 +971,1074 @@ func TestAccountPriceFloorsValidate(t *testing.T) {
 	}
 }
 
+func TestAccountDebugTokenValidate(t *testing.T) {
+	tests := []struct {
+		description string
+		dt          *AccountDebugToken
+		want        []error
+	}{
+		{
+			description: "valid configuration",
+			dt: &AccountDebugToken{
+				Keys:          []string{"0123456789abcdef0123456789abcdef"},
+				MaxTTLSeconds: 3600,
+			},
+		},
+		{
+			description: "valid configuration without keys",
+			dt:          &AccountDebugToken{},
+		},
+		{
+			description: "Invalid configuration: short key",
+			dt: &AccountDebugToken{
+				Keys:          []string{"0123456789abcdef0123456789abcdef", "short", "also short"},
+				MaxTTLSeconds: 3600,
+			},
+			want: []error{errors.New("account_defaults.debug_token.keys must be at least 32 characters long")},
+		},
+		{
+			description: "Invalid configuration: MaxTTLSeconds:0",
+			dt: &AccountDebugToken{
+				Keys: []string{"0123456789abcdef0123456789abcdef"},
+			},
+			want: []error{errors.New("account_defaults.debug_token.max_ttl_seconds must be positive. Got 0")},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			var errs []error
+			got := tt.dt.validate(errs)
+			assert.ElementsMatch(t, got, tt.want)
+		})
+	}
+}
+
+func TestAccountDebugAccessValidate(t *testing.T) {
+	tests := []struct {
+		description string
+		da          *AccountDebugAccess
+		want        []error
+	}{
+		{
+			description: "valid configuration",
+			da: &AccountDebugAccess{
+				AllowedIPs:          []string{"203.0.113.7", "198.51.100.0/24", "2001:db8::/32"},
+				AllowedTokens:       []string{"0123456789abcdef0123456789abcdef"},
+				AnalyticsSampleRate: 0.01,
+			},
+		},
+		{
+			description: "valid configuration without restrictions",
+			da:          &AccountDebugAccess{},
+		},
+		{
+			description: "Invalid configuration: malformed IP",
+			da: &AccountDebugAccess{
+				AllowedIPs: []string{"203.0.113.7", "203.0.113", "198.51.100.0/33"},
+			},
+			want: []error{
+				errors.New("account_defaults.debug_access.allowed_ips must be IP addresses or CIDR blocks. Got 203.0.113"),
+				errors.New("account_defaults.debug_access.allowed_ips must be IP addresses or CIDR blocks. Got 198.51.100.0/33"),
+			},
+		},
+		{
+			description: "Invalid configuration: short token",
+			da: &AccountDebugAccess{
+				AllowedTokens: []string{"short", "also short"},
+			},
+			want: []error{errors.New("account_defaults.debug_access.allowed_tokens must be at least 32 characters long")},
+		},
+		{
+			description: "Invalid configuration: AnalyticsSampleRate:1.5",
+			da: &AccountDebugAccess{
+				AnalyticsSampleRate: 1.5,
+			},
+			want: []error{errors.New("account_defaults.debug_access.analytics_sample_rate must be in the range [0, 1]. Got 1.500000")},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			var errs []error
+			got := tt.da.validate(errs)
+			assert.ElementsMatch(t, got, tt.want)
+		})
+	}
+}
+
+func TestAccountAdsCertValidate(t *testing.T) {
+	tests := []struct {
+		description string
+		ac          *AccountAdsCert
+		want        []error
+	}{
+		{
+			description: "valid configuration",
+			ac:          &AccountAdsCert{Sign: true, Verify: AdsCertVerifyEnforce},
+		},
+		{
+			description: "valid empty configuration",
+			ac:          &AccountAdsCert{},
+		},
+		{
+			description: "Invalid configuration: unknown verify mode",
+			ac:          &AccountAdsCert{Verify: "strict"},
+			want:        []error{errors.New("account_defaults.adscert.verify must be off, observe or enforce. Got strict")},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			var errs []error
+			got := tt.ac.validate(errs)
+			assert.ElementsMatch(t, got, tt.want)
+		})
+	}
+}
+
+func TestAccountAdsCertVerifyEnabled(t *testing.T) {
+	assert.False(t, (&AccountAdsCert{}).VerifyEnabled())
+	assert.False(t, (&AccountAdsCert{Verify: AdsCertVerifyOff}).VerifyEnabled())
+	assert.True(t, (&AccountAdsCert{Verify: AdsCertVerifyObserve}).VerifyEnabled())
+	assert.True(t, (&AccountAdsCert{Verify: AdsCertVerifyEnforce}).VerifyEnabled())
+}
+
+func TestAccountSChainValidate(t *testing.T) {
+	tests := []struct {
+		description string
+		as          *AccountSChain
+		want        []error
+	}{
+		{
+			description: "valid configuration",
+			as:          &AccountSChain{ASI: "publisher.com", SID: "1", Bidders: map[string]AccountSChainNode{"appnexus": {ASI: "publisher.com", SID: "2"}}},
+		},
+		{
+			description: "valid empty configuration",
+			as:          &AccountSChain{},
+		},
+		{
+			description: "Invalid configuration: asi without sid",
+			as:          &AccountSChain{ASI: "publisher.com"},
+			want:        []error{errors.New("account_defaults.schain must set both asi and sid, or neither")},
+		},
+		{
+			description: "Invalid configuration: bidder without asi",
+			as:          &AccountSChain{Bidders: map[string]AccountSChainNode{"appnexus": {SID: "2"}}},
+			want:        []error{errors.New("account_defaults.schain.bidders.appnexus must set both asi and sid")},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			var errs []error
+			got := tt.as.validate(errs)
+			assert.ElementsMatch(t, got, tt.want)
+		})
+	}
+}
+
+func TestAccountSChainNode(t *testing.T) {
+	as := AccountSChain{ASI: "publisher.com", SID: "1", Bidders: map[string]AccountSChainNode{"appnexus": {ASI: "publisher.com", SID: "2"}}}
+
+	node, ok := as.Node("appnexus")
+	assert.True(t, ok)
+	assert.Equal(t, AccountSChainNode{ASI: "publisher.com", SID: "2"}, node)
+
+	node, ok = as.Node("rubicon")
+	assert.True(t, ok)
+	assert.Equal(t, AccountSChainNode{ASI: "publisher.com", SID: "1"}, node)
+
+	_, ok = (&AccountSChain{}).Node("rubicon")
+	assert.False(t, ok)
+}
+
+func TestAccountOriginValidate(t *testing.T) {
+	tests := []struct {
+		description string
+		ao          *AccountOrigin
+		want        []error
+	}{
+		{
+			description: "valid configuration",
+			ao:          &AccountOrigin{Verify: OriginVerifyEnforce, AllowedDomains: []string{"publisher.com"}},
+		},
+		{
+			description: "valid empty configuration",
+			ao:          &AccountOrigin{},
+		},
+		{
+			description: "Invalid configuration: unknown verify mode",
+			ao:          &AccountOrigin{Verify: "strict"},
+			want:        []error{errors.New("account_defaults.origin.verify must be off, observe or enforce. Got strict")},
+		},
+		{
+			description: "Invalid configuration: URL and wildcard domains",
+			ao:          &AccountOrigin{AllowedDomains: []string{"https://publisher.com", "*.publisher.com"}},
+			want: []error{
+				errors.New(`account_defaults.origin.allowed_domains has an invalid domain: "https://publisher.com"`),
+				errors.New(`account_defaults.origin.allowed_domains has an invalid domain: "*.publisher.com"`),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			var errs []error
+			got := tt.ao.validate(errs)
+			assert.ElementsMatch(t, got, tt.want)
+		})
+	}
+}
+
+func TestAccountOriginIsAllowed(t *testing.T) {
+	ao := AccountOrigin{AllowedDomains: []string{"publisher.com", "Partner.org."}}
+
+	assert.True(t, ao.IsAllowed("publisher.com"))
+	assert.True(t, ao.IsAllowed("www.Publisher.com"))
+	assert.True(t, ao.IsAllowed("partner.org"))
+	assert.False(t, ao.IsAllowed("notpublisher.com"))
+	assert.False(t, ao.IsAllowed("publisher.com.spoofer.com"))
+	assert.False(t, ao.IsAllowed(""))
+}
+
+func TestAccountRequestLimitsValidate(t *testing.T) {
+	tests := []struct {
+		description string
+		rl          *AccountRequestLimits
+		want        []error
+	}{
+		{
+			description: "valid configuration",
+			rl:          &AccountRequestLimits{MaxImps: 20, MaxEIDs: 50, MaxDataSegments: 100, MaxRequestSize: 64000},
+		},
+		{
+			description: "valid empty configuration",
+			rl:          &AccountRequestLimits{},
+		},
+		{
+			description: "Invalid configuration: negative limits",
+			rl:          &AccountRequestLimits{MaxImps: -1, MaxEIDs: -2, MaxDataSegments: -3, MaxRequestSize: -4},
+			want: []error{
+				errors.New("account_defaults.request_limits.max_imps must be 0 or more. Got -1"),
+				errors.New("account_defaults.request_limits.max_eids must be 0 or more. Got -2"),
+				errors.New("account_defaults.request_limits.max_data_segments must be 0 or more. Got -3"),
+				errors.New("account_defaults.request_limits.max_request_size must be 0 or more. Got -4"),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			var errs []error
+			got := tt.rl.validate(errs)
+			assert.ElementsMatch(t, got, tt.want)
+		})
+	}
+}
+
+func TestAccountAPIKeysValidate(t *testing.T) {
+	// SHA-256 of "secret"
+	const hash = "2bb80d537b1da3e38bd30361aa855686bde0eacd7162fef6a25fe97bf527a25b"
+
+	tests := []struct {
+		description string
+		ak          *AccountAPIKeys
+		want        []error
+	}{
+		{
+			description: "valid configuration",
+			ak:          &AccountAPIKeys{Required: true, Keys: []AccountAPIKey{{ID: "partner", SHA256: hash, RateLimit: 100}, {ID: "old", SHA256: hash, Revoked: true}}},
+		},
+		{
+			description: "valid empty configuration",
+			ak:          &AccountAPIKeys{},
+		},
+		{
+			description: "Invalid configuration: missing and repeated ids",
+			ak:          &AccountAPIKeys{Keys: []AccountAPIKey{{SHA256: hash}, {ID: "partner", SHA256: hash}, {ID: "partner", SHA256: hash}}},
+			want: []error{
+				errors.New("account_defaults.api_keys.keys[0].id is required"),
+				errors.New("account_defaults.api_keys.keys[2].id partner is not unique"),
+			},
+		},
+		{
+			description: "Invalid configuration: bad hash and negative rate limit",
+			ak:          &AccountAPIKeys{Keys: []AccountAPIKey{{ID: "partner", SHA256: "secret", RateLimit: -1}}},
+			want: []error{
+				errors.New("account_defaults.api_keys.keys[0].sha256 must be a hex encoded SHA-256 hash"),
+				errors.New("account_defaults.api_keys.keys[0].rate_limit must be 0 or more. Got -1"),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			var errs []error
+			got := tt.ak.validate(errs)
+			assert.ElementsMatch(t, got, tt.want)
+		})
+	}
+}
+
+func TestAccountAPIKeysFind(t *testing.T) {
+	ak := AccountAPIKeys{Keys: []AccountAPIKey{
+		{ID: "other", SHA256: "invalid"},
+		{ID: "partner", SHA256: "2BB80D537B1DA3E38BD30361AA855686BDE0EACD7162FEF6A25FE97BF527A25B", RateLimit: 10},
+	}}
+
+	key, ok := ak.Find("secret")
+	assert.True(t, ok)
+	assert.Equal(t, "partner", key.ID)
+
+	_, ok = ak.Find("Secret")
+	assert.False(t, ok)
+	_, ok = ak.Find("")
+	assert.False(t, ok)
+
+	assert.True(t, ak.Enabled())
+	assert.True(t, (&AccountAPIKeys{Required: true}).Enabled())
+	assert.False(t, (&AccountAPIKeys{}).Enabled())
+}
+
+func TestAccountResponseSigningValidate(t *testing.T) {
+	tests := []struct {
+		description string
+		rs          *AccountResponseSigning
+		want        []error
+	}{
+		{
+			description: "valid configuration",
+			rs:          &AccountResponseSigning{Enabled: true, KeyID: "2024"},
+		},
+		{
+			description: "valid disabled configuration",
+			rs:          &AccountResponseSigning{},
+		},
+		{
+			description: "Invalid configuration: enabled without a key",
+			rs:          &AccountResponseSigning{Enabled: true},
+			want:        []error{errors.New("account_defaults.response_signing.key_id is required to sign responses")},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			var errs []error
+			got := tt.rs.validate(errs)
+			assert.ElementsMatch(t, got, tt.want)
+		})
+	}
+}
+
+func TestAccountResponseExtValidate(t *testing.T) {
+	tests := []struct {
+		description string
+		re          *AccountResponseExt
+		want        []error
+	}{
+		{
+			description: "valid configuration",
+			re:          &AccountResponseExt{Enabled: true, Include: []string{"debug", "seatnonbid", "modules"}},
+		},
+		{
+			description: "nothing included",
+			re:          &AccountResponseExt{Enabled: true},
+		},
+		{
+			description: "unknown section",
+			re:          &AccountResponseExt{Enabled: true, Include: []string{"fledge", "bids"}},
+			want:        []error{errors.New("account_defaults.response_ext.include must only hold debug, errors, warnings, responsetimemillis, usersync, seatnonbid, fledge, modules. Got bids")},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			var errs []error
+			got := tt.re.validate(errs)
+			assert.ElementsMatch(t, got, tt.want)
+		})
+	}
+}
+
+func TestAccountResponseExtIncludes(t *testing.T) {
+	disabled := AccountResponseExt{Enabled: false}
+	enabled := AccountResponseExt{Enabled: true, Include: []string{ResponseExtDebug}}
+
+	assert.True(t, disabled.Includes(ResponseExtFledge))
+	assert.True(t, enabled.Includes(ResponseExtDebug))
+	assert.False(t, enabled.Includes(ResponseExtFledge))
+}
+
+func TestAccountDealPacingValidate(t *testing.T) {
+	tests := []struct {
+		description string
+		dp          *AccountDealPacing
+		want        []error
+	}{
+		{
+			description: "valid configuration",
+			dp:          &AccountDealPacing{WindowSeconds: 3600, Deals: map[string]int64{"deal-1": 1000}},
+		},
+		{
+			description: "no deals",
+			dp:          &AccountDealPacing{},
+		},
+		{
+			description: "Invalid configuration",
+			dp:          &AccountDealPacing{Deals: map[string]int64{"deal-1": 0}},
+			want: []error{
+				errors.New("account_defaults.deal_pacing.window_seconds must be positive. Got 0"),
+				errors.New("account_defaults.deal_pacing.deals.deal-1 must be positive. Got 0"),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			var errs []error
+			got := tt.dp.validate(errs)
+			assert.ElementsMatch(t, got, tt.want)
+		})
+	}
+}
+
+func TestAccountCreativeTrackersValidate(t *testing.T) {
+	tests := []struct {
+		description string
+		ct          *AccountCreativeTrackers
+		want        []error
+	}{
+		{
+			description: "valid configuration",
+			ct:          &AccountCreativeTrackers{Enabled: true, Events: []string{"win", "imp"}, URLs: []string{"https://tracker.example.com/imp?bid=##PBS-BIDID##"}},
+		},
+		{
+			description: "disabled",
+			ct:          &AccountCreativeTrackers{Events: []string{"click"}, URLs: []string{"not a url"}},
+		},
+		{
+			description: "Invalid configuration",
+			ct:          &AccountCreativeTrackers{Enabled: true, Events: []string{"imp", "click"}, URLs: []string{"not a url"}},
+			want: []error{
+				errors.New("account_defaults.creative_trackers.events[1] must be win or imp. Got click"),
+				errors.New("account_defaults.creative_trackers.urls[0] must be a valid URL. Got not a url"),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			var errs []error
+			got := tt.ct.validate(errs)
+			assert.ElementsMatch(t, got, tt.want)
+		})
+	}
+}
+
+func TestAccountNativeTrackersValidate(t *testing.T) {
+	tests := []struct {
+		description string
+		nt          *AccountNativeTrackers
+		want        []error
+	}{
+		{
+			description: "valid configuration",
+			nt:          &AccountNativeTrackers{Enabled: true, ImpTrackers: []string{"https://measure.example.com/imp?bid=##PBS-BIDID##"}, ClickTrackers: []string{"https://measure.example.com/click?bid=##PBS-BIDID##"}},
+		},
+		{
+			description: "disabled",
+			nt:          &AccountNativeTrackers{ImpTrackers: []string{"not a url"}},
+		},
+		{
+			description: "Invalid configuration",
+			nt:          &AccountNativeTrackers{Enabled: true, ImpTrackers: []string{"https://measure.example.com/imp", "not a url"}, ClickTrackers: []string{"/click"}},
+			want: []error{
+				errors.New("account_defaults.native_trackers.imp_trackers[1] must be a valid URL. Got not a url"),
+				errors.New("account_defaults.native_trackers.click_trackers[0] must be a valid URL. Got /click"),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			var errs []error
+			got := tt.nt.validate(errs)
+			assert.ElementsMatch(t, got, tt.want)
+		})
+	}
+}
+
+func TestAccountFirstPartyDataValidate(t *testing.T) {
+	tests := []struct {
+		description string
+		fpd         *AccountFirstPartyData
+		want        []error
+	}{
+		{
+			description: "valid configuration",
+			fpd: &AccountFirstPartyData{Rules: []AccountFPDRule{
+				{Path: "user.ext.data", Precedence: []string{"stored", "request"}, Arrays: "merge", MaxBytes: 1024},
+				{Path: "imp.ext.data"},
+			}},
+		},
+		{
+			description: "no rules",
+			fpd:         &AccountFirstPartyData{},
+		},
+		{
+			description: "Invalid configuration",
+			fpd: &AccountFirstPartyData{Rules: []AccountFPDRule{
+				{Path: "user", Precedence: []string{"request", "request"}},
+				{Path: "device.ext.data", Precedence: []string{"rtd"}, Arrays: "append", MaxBytes: -1},
+			}},
+			want: []error{
+				errors.New("account_defaults.first_party_data.rules[0].path must be a path within site, app, dooh, user or imp. Got user"),
+				errors.New("account_defaults.first_party_data.rules[0].precedence must list request, stored and module at most once each. Got [request request]"),
+				errors.New("account_defaults.first_party_data.rules[1].path must be a path within site, app, dooh, user or imp. Got device.ext.data"),
+				errors.New("account_defaults.first_party_data.rules[1].precedence must list request, stored and module at most once each. Got [rtd]"),
+				errors.New("account_defaults.first_party_data.rules[1].arrays must be replace or merge. Got append"),
+				errors.New("account_defaults.first_party_data.rules[1].max_bytes must be >= 0. Got -1"),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			var errs []error
+			got := tt.fpd.validate(errs)
+			assert.ElementsMatch(t, got, tt.want)
+		})
+	}
+}
+
+func TestAccountDefaultRequestValidate(t *testing.T) {
+	tests := []struct {
+		description    string
+		defaultRequest *AccountDefaultRequest
+		want           []error
+	}{
+		{
+			description: "valid configuration",
+			defaultRequest: &AccountDefaultRequest{
+				Request:  json.RawMessage(`{"regs":{"coppa":1}}`),
+				Channels: map[string]json.RawMessage{"app": json.RawMessage(`{"app":{"publisher":{"id":"1"}}}`)},
+			},
+		},
+		{
+			description:    "no default requests",
+			defaultRequest: &AccountDefaultRequest{},
+		},
+		{
+			description: "Invalid configuration",
+			defaultRequest: &AccountDefaultRequest{
+				Request:  json.RawMessage(`["regs"]`),
+				Channels: map[string]json.RawMessage{"amp": json.RawMessage(`{}`), "web": json.RawMessage(`null`)},
+			},
+			want: []error{
+				errors.New("account_defaults.default_request.request must be a JSON object"),
+				errors.New("account_defaults.default_request.channels must be web, app or dooh. Got amp"),
+				errors.New("account_defaults.default_request.channels.web must be a JSON object"),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			var errs []error
+			got := tt.defaultRequest.validate(errs)
+			assert.ElementsMatch(t, got, tt.want)
+		})
+	}
+}
+
+func TestAccountSizeResolutionValidate(t *testing.T) {
+	tests := []struct {
+		description    string
+		sizeResolution *AccountSizeResolution
+		want           []error
+	}{
+		{
+			description: "valid configuration",
+			sizeResolution: &AccountSizeResolution{
+				Enabled:    true,
+				Catalog:    []AccountSize{{W: 320, H: 480, Orientation: "portrait"}, {W: 300, H: 250}},
+				Adaptive:   []AccountAdaptiveSize{{WRatio: 32, HRatio: 5, WMin: 300, Orientation: "landscape"}},
+				MaxFormats: 5,
+			},
+		},
+		{
+			description:    "no configuration",
+			sizeResolution: &AccountSizeResolution{},
+		},
+		{
+			description: "Invalid configuration",
+			sizeResolution: &AccountSizeResolution{
+				Catalog:    []AccountSize{{W: 0, H: 250}, {W: 300, H: 250, Orientation: "square"}},
+				Adaptive:   []AccountAdaptiveSize{{WRatio: 16, WMin: -1}},
+				MaxFormats: -1,
+			},
+			want: []error{
+				errors.New("account_defaults.size_resolution.catalog[0] must have a positive w and h. Got 0x250"),
+				errors.New("account_defaults.size_resolution.catalog[1].orientation must be portrait or landscape. Got square"),
+				errors.New("account_defaults.size_resolution.adaptive[0] must have a positive wratio and hratio. Got 16:0"),
+				errors.New("account_defaults.size_resolution.adaptive[0].wmin must be >= 0. Got -1"),
+				errors.New("account_defaults.size_resolution.max_formats must be >= 0. Got -1"),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			var errs []error
+			got := tt.sizeResolution.validate(errs)
+			assert.ElementsMatch(t, got, tt.want)
+		})
+	}
+}
+
+func TestAccountDynamicTmaxValidate(t *testing.T) {
+	tests := []struct {
+		description string
+		dynamicTmax *AccountDynamicTmax
+		want        []error
+	}{
+		{
+			description: "valid configuration",
+			dynamicTmax: &AccountDynamicTmax{
+				Enabled:          true,
+				MinMS:            300,
+				MaxMS:            2000,
+				ChannelMS:        map[string]int64{"app": -100, "amp": 200},
+				ConnectionTypeMS: map[string]int64{"3g": 400, "5g": -100},
+			},
+		},
+		{
+			description: "no configuration",
+			dynamicTmax: &AccountDynamicTmax{},
+		},
+		{
+			description: "Invalid configuration",
+			dynamicTmax: &AccountDynamicTmax{
+				MinMS:            -1,
+				ChannelMS:        map[string]int64{"video": 100},
+				ConnectionTypeMS: map[string]int64{"6g": -100},
+			},
+			want: []error{
+				errors.New("account_defaults.dynamic_tmax.min_ms must be >= 0. Got -1"),
+				errors.New("account_defaults.dynamic_tmax.channel_ms has an unknown channel video. It must be web, app, amp or dooh"),
+				errors.New("account_defaults.dynamic_tmax.connection_type_ms has an unknown connection type 6g. It must be ethernet, wifi, cellular, 2g, 3g, 4g or 5g"),
+			},
+		},
+		{
+			description: "Inverted bounds",
+			dynamicTmax: &AccountDynamicTmax{MinMS: 500, MaxMS: 400},
+			want: []error{
+				errors.New("account_defaults.dynamic_tmax.max_ms cannot be less than min_ms. max_ms=400, min_ms=500"),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			var errs []error
+			got := tt.dynamicTmax.validate(errs)
+			assert.ElementsMatch(t, got, tt.want)
+		})
+	}
+}
+
+func TestAccountEventWebhookValidate(t *testing.T) {
+	tests := []struct {
+		description  string
+		eventWebhook *AccountEventWebhook
+		want         []error
+	}{
+		{
+			description:  "valid configuration",
+			eventWebhook: &AccountEventWebhook{Enabled: true, URL: "https://publisher.example.com/events", Secret: "secret", Events: []string{"win", "loss"}},
+		},
+		{
+			description:  "disabled",
+			eventWebhook: &AccountEventWebhook{URL: "publisher.example.com"},
+		},
+		{
+			description:  "Invalid configuration",
+			eventWebhook: &AccountEventWebhook{Enabled: true, URL: "publisher.example.com", Events: []string{"click"}},
+			want: []error{
+				errors.New("account_defaults.event_webhook.url must be an http or https URL. Got publisher.example.com"),
+				errors.New("account_defaults.event_webhook.secret is required"),
+				errors.New("account_defaults.event_webhook.events has an unknown event type click. It must be win, loss, imp or vast"),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			var errs []error
+			got := tt.eventWebhook.validate(errs)
+			assert.ElementsMatch(t, got, tt.want)
+		})
+	}
+}
+
+func TestAccountEventWebhookSends(t *testing.T) {
+	all := AccountEventWebhook{Enabled: true}
+	assert.True(t, all.Sends("win"))
+	assert.True(t, all.Sends("vast"))
+
+	some := AccountEventWebhook{Enabled: true, Events: []string{"win", "loss"}}
+	assert.True(t, some.Sends("loss"))
+	assert.False(t, some.Sends("imp"))
+
+	disabled := AccountEventWebhook{Events: []string{"win"}}
+	assert.False(t, disabled.Sends("win"))
+}
+
+func TestAccountIVTValidate(t *testing.T) {
+	tests := []struct {
+		description string
+		ivt         *AccountIVT
+		want        []error
+	}{
+		{
+			description: "host action",
+			ivt:         &AccountIVT{},
+		},
+		{
+			description: "block",
+			ivt:         &AccountIVT{Action: IVTActionBlock},
+		},
+		{
+			description: "Invalid action",
+			ivt:         &AccountIVT{Action: "drop"},
+			want: []error{
+				errors.New("account_defaults.ivt.action must be block or tag. Got drop"),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			var errs []error
+			got := tt.ivt.validate(errs)
+			assert.ElementsMatch(t, got, tt.want)
+		})
+	}
+}
+
+func TestAccountLineItemsValidate(t *testing.T) {
+	tests := []struct {
+		description string
+		li          *AccountLineItems
+		want        []error
+	}{
+		{
+			description: "valid configuration",
+			li: &AccountLineItems{Enabled: true, Items: []AccountLineItem{
+				{ID: "li-1", Priority: 1, DealIDs: []string{"deal-1"}, Sizes: []string{"300x250", "728x90"}},
+				{ID: "li-2", Priority: 2, Bidders: []string{"appnexus"}, Countries: []string{"USA"}},
+			}},
+		},
+		{
+			description: "disabled",
+			li:          &AccountLineItems{Items: []AccountLineItem{{Sizes: []string{"big"}}}},
+		},
+		{
+			description: "Invalid configuration",
+			li: &AccountLineItems{Enabled: true, Items: []AccountLineItem{
+				{ID: "li-1", Priority: 1},
+				{ID: "li-1", Priority: 0, Sizes: []string{"300x250", "300X0"}},
+				{Priority: 3},
+			}},
+			want: []error{
+				errors.New("account_defaults.line_items.items[1].id must be unique. Got li-1"),
+				errors.New("account_defaults.line_items.items[1].priority must be >= 1. Got 0"),
+				errors.New("account_defaults.line_items.items[1].sizes must be sizes such as 300x250. Got 300X0"),
+				errors.New("account_defaults.line_items.items[2].id must be set"),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			var errs []error
+			got := tt.li.validate(errs)
+			assert.ElementsMatch(t, got, tt.want)
+		})
+	}
+}
+
+func TestAccountPriceEncryptionValidate(t *testing.T) {
+	tests := []struct {
+		description string
+		pe          *AccountPriceEncryption
+		want        []error
+	}{
+		{
+			description: "base64url keys",
+			pe:          &AccountPriceEncryption{Enabled: true, EncryptionKey: "skU7Ax_NL5pPAFyKdkfZjZz2-VhIN8bjj1rVFOaJ_5o=", IntegrityKey: "arO23ykdNqUQ5LEoQ0FVmPkBd7xB5CO89PDZlSjpFxo"},
+		},
+		{
+			description: "base64 keys",
+			pe:          &AccountPriceEncryption{Enabled: true, EncryptionKey: "skU7Ax/NL5pPAFyKdkfZjZz2+VhIN8bjj1rVFOaJ/5o=", IntegrityKey: "arO23ykdNqUQ5LEoQ0FVmPkBd7xB5CO89PDZlSjpFxo="},
+		},
+		{
+			description: "disabled",
+			pe:          &AccountPriceEncryption{EncryptionKey: "not a key"},
+		},
+		{
+			description: "Invalid configuration",
+			pe:          &AccountPriceEncryption{Enabled: true, EncryptionKey: "c2hvcnQ=", IntegrityKey: "not a key"},
+			want: []error{
+				errors.New("account_defaults.price_encryption.encryption_key must be a base64 encoded key of at least 16 bytes"),
+				errors.New("account_defaults.price_encryption.integrity_key must be a base64 encoded key of at least 16 bytes"),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			var errs []error
+			got := tt.pe.validate(errs)
+			assert.ElementsMatch(t, got, tt.want)
+		})
+	}
+}
+
+func TestAccountUsageQuotaValidate(t *testing.T) {
+	tests := []struct {
+		description string
+		uq          *AccountUsageQuota
+		want        []error
+	}{
+		{
+			description: "valid configuration",
+			uq:          &AccountUsageQuota{Period: UsageQuotaPeriodHour, MaxAuctions: 1000, MaxImps: 5000, MaxBidderCalls: 20000},
+		},
+		{
+			description: "no quotas",
+			uq:          &AccountUsageQuota{Period: "week"},
+		},
+		{
+			description: "Invalid configuration",
+			uq:          &AccountUsageQuota{Period: "week", MaxAuctions: 1000, MaxImps: -1, MaxBidderCalls: -2},
+			want: []error{
+				errors.New("account_defaults.usage_quota.max_imps must be >= 0. Got -1"),
+				errors.New("account_defaults.usage_quota.max_bidder_calls must be >= 0. Got -2"),
+				errors.New("account_defaults.usage_quota.period must be hour or day. Got week"),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			var errs []error
+			got := tt.uq.validate(errs)
+			assert.ElementsMatch(t, got, tt.want)
+		})
+	}
+}
+
+func TestAccountUsageQuotaWindow(t *testing.T) {
+	assert.Equal(t, time.Hour, (&AccountUsageQuota{Period: UsageQuotaPeriodHour}).Window())
+	assert.Equal(t, 24*time.Hour, (&AccountUsageQuota{Period: UsageQuotaPeriodDay}).Window())
+	assert.Equal(t, time.Duration(0), (&AccountUsageQuota{Period: "month"}).Window())
+}
+
+func TestAccountCurrencyValidate(t *testing.T) {
+	tests := []struct {
+		description string
+		ac          *AccountCurrency
+		want        []error
+	}{
+		{
+			description: "valid configuration",
+			ac:          &AccountCurrency{Rates: map[string]map[string]float64{"USD": {"EUR": 0.9, "gbp": 0.8}}},
+		},
+		{
+			description: "most_bids selection",
+			ac:          &AccountCurrency{Selection: CurrencySelectionMostBids},
+		},
+		{
+			description: "unknown selection",
+			ac:          &AccountCurrency{Selection: "last"},
+			want:        []error{errors.New("account_defaults.currency.selection must be first or most_bids. Got last")},
+		},
+		{
+			description: "no rates",
+			ac:          &AccountCurrency{},
+		},
+		{
+			description: "Invalid configuration",
+			ac:          &AccountCurrency{Rates: map[string]map[string]float64{"US": {"EUR": 0.9}, "USD": {"ZZZ": 1.1, "JPY": 0}}},
+			want: []error{
+				errors.New("account_defaults.currency.rates has an unknown currency US"),
+				errors.New("account_defaults.currency.rates.USD has an unknown currency ZZZ"),
+				errors.New("account_defaults.currency.rates.USD.JPY must be > 0. Got 0"),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			var errs []error
+			got := tt.ac.validate(errs)
+			assert.ElementsMatch(t, got, tt.want)
+		})
+	}
+}
+
+func TestAccountCurrencyNormalizeRates(t *testing.T) {
+	ac := AccountCurrency{Rates: map[string]map[string]float64{"usd": {"eur": 0.9}, "USD": {"GBP": 0.8}}}
+	ac.NormalizeRates()
+	assert.Equal(t, map[string]map[string]float64{"USD": {"EUR": 0.9, "GBP": 0.8}}, ac.Rates)
+
+	empty := AccountCurrency{}
+	empty.NormalizeRates()
+	assert.Nil(t, empty.Rates)
+}
+
+func TestAccountMultiformatValidate(t *testing.T) {
+	tests := []struct {
+		description string
+		multiformat *AccountMultiformat
+		want        []error
+	}{
+		{
+			description: "no preference",
+			multiformat: &AccountMultiformat{},
+		},
+		{
+			description: "valid preference",
+			multiformat: &AccountMultiformat{
+				PreferredMediaTypes: []string{"video", "banner"},
+				Bidders:             map[string]AccountMultiformatBidder{"appnexus": {PreferredMediaTypes: []string{"native"}, SingleFormat: true}},
+			},
+		},
+		{
+			description: "Invalid media types",
+			multiformat: &AccountMultiformat{
+				PreferredMediaTypes: []string{"video", "display"},
+				Bidders:             map[string]AccountMultiformatBidder{"appnexus": {PreferredMediaTypes: []string{"Banner"}}},
+			},
+			want: []error{
+				errors.New("account_defaults.multiformat.preferred_media_types must only hold banner, video, native or audio. Got display"),
+				errors.New("account_defaults.multiformat.bidders.appnexus.preferred_media_types must only hold banner, video, native or audio. Got Banner"),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			var errs []error
+			got := tt.multiformat.validate(errs)
+			assert.ElementsMatch(t, got, tt.want)
+		})
+	}
+}
+
+func TestAccountBidderFieldsValidate(t *testing.T) {
+	tests := []struct {
+		description  string
+		bidderFields *AccountBidderFields
+		want         []error
+	}{
+		{
+			description:  "no policies",
+			bidderFields: &AccountBidderFields{},
+		},
+		{
+			description: "valid policies",
+			bidderFields: &AccountBidderFields{Bidders: map[string]AccountBidderFieldPolicy{
+				"appnexus": {Allow: []string{"device.ua", "imp.*.ext.data"}, Deny: []string{"device.ifa", "bcat"}},
+			}},
+		},
+		{
+			description: "Invalid paths",
+			bidderFields: &AccountBidderFields{Bidders: map[string]AccountBidderFieldPolicy{
+				"appnexus": {Allow: []string{"device"}, Deny: []string{"user..ext"}},
+			}},
+			want: []error{
+				errors.New("account_defaults.bidder_fields.bidders.appnexus.allow must hold paths of members within objects of the request, such as device.ifa. Got device"),
+				errors.New("account_defaults.bidder_fields.bidders.appnexus.deny must hold dotted paths, such as device.ifa. Got user..ext"),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			var errs []error
+			got := tt.bidderFields.validate(errs)
+			assert.ElementsMatch(t, got, tt.want)
+		})
+	}
+}
+
+func TestAccountBidderFieldsPolicy(t *testing.T) {
+	bidderFields := AccountBidderFields{Bidders: map[string]AccountBidderFieldPolicy{
+		"appnexus": {Deny: []string{"device.ifa"}},
+		"*":        {Deny: []string{"user.ext.eids"}},
+	}}
+
+	policy, ok := bidderFields.Policy("appnexus")
+	assert.True(t, ok)
+	assert.Equal(t, []string{"device.ifa"}, policy.Deny)
+
+	policy, ok = bidderFields.Policy("rubicon")
+	assert.True(t, ok)
+	assert.Equal(t, []string{"user.ext.eids"}, policy.Deny)
+
+	_, ok = (&AccountBidderFields{}).Policy("rubicon")
+	assert.False(t, ok)
+}
+
+func TestAccountDebugAccessIsAllowed(t *testing.T) {
+	restricted := AccountDebugAccess{
+		AllowedIPs:    []string{"203.0.113.7", "198.51.100.0/24", "2001:db8::/32", "malformed"},
+		AllowedTokens: []string{"0123456789abcdef0123456789abcdef"},
+	}
+	tests := []struct {
+		description string
+		da          AccountDebugAccess
+		ip          net.IP
+		token       string
+		want        bool
+	}{
+		{
+			description: "unrestricted",
+			da:          AccountDebugAccess{},
+			ip:          net.ParseIP("192.0.2.1"),
+			want:        true,
+		},
+		{
+			description: "allowed IP",
+			da:          restricted,
+			ip:          net.ParseIP("203.0.113.7"),
+			want:        true,
+		},
+		{
+			description: "IP in allowed IPv4 block",
+			da:          restricted,
+			ip:          net.ParseIP("198.51.100.200"),
+			want:        true,
+		},
+		{
+			description: "IP in allowed IPv6 block",
+			da:          restricted,
+			ip:          net.ParseIP("2001:db8::1"),
+			want:        true,
+		},
+		{
+			description: "IP not allowed",
+			da:          restricted,
+			ip:          net.ParseIP("192.0.2.1"),
+			want:        false,
+		},
+		{
+			description: "no IP",
+			da:          restricted,
+			want:        false,
+		},
+		{
+			description: "allowed token",
+			da:          restricted,
+			ip:          net.ParseIP("192.0.2.1"),
+			token:       "0123456789abcdef0123456789abcdef",
+			want:        true,
+		},
+		{
+			description: "token not allowed",
+			da:          restricted,
+			ip:          net.ParseIP("192.0.2.1"),
+			token:       "fedcba9876543210fedcba9876543210",
+			want:        false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.da.IsAllowed(tt.ip, tt.token))
+		})
+	}
+}
+
 func TestIPMaskingValidate(t *testing.T) {
 	tests := []struct {
 		name    string
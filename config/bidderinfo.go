@@ -1,6 +1,7 @@
 package config
 
 import (
+	"crypto/tls"
 	"errors"
 	"fmt"
 	"log"
@@ -13,6 +14,7 @@ import (
 	"github.com/prebid/prebid-server/v2/openrtb_ext"
 
 	validator "github.com/asaskevich/govalidator"
+	"golang.org/x/text/currency"
 	"gopkg.in/yaml.v3"
 )
 
@@ -45,6 +47,85 @@ type BidderInfo struct {
 	// EndpointCompression determines, if set, the type of compression the bid request will undergo before being sent to the corresponding bid server
 	EndpointCompression string       `yaml:"endpointCompression" mapstructure:"endpointCompression"`
 	OpenRTB             *OpenRTBInfo `yaml:"openrtb" mapstructure:"openrtb"`
+	// MaxResponseSize is the largest bid response body, in bytes, read from the bidder. If set, it overrides the
+	// host's max_bidder_response_size.
+	MaxResponseSize int64 `yaml:"maxResponseSize" mapstructure:"maxResponseSize"`
+	// Canary is a second configuration of the adapter, which a share of the requests to the bidder are
+	// sent with, so a change to it can be ramped up.
+	Canary *BidderCanary `yaml:"canary" mapstructure:"canary"`
+	// TimeoutNotificationURL is called when a request to the bidder times out, if
+	// bidder_timeout_notifications is enabled and the adapter doesn't make its own notifications.
+	TimeoutNotificationURL string `yaml:"timeoutNotificationUrl" mapstructure:"timeoutNotificationUrl"`
+	// TLS configures the connections to the bidder's endpoint, for endpoints whose certificates are issued by
+	// a private CA or which require a client certificate.
+	TLS *BidderTLS `yaml:"tls" mapstructure:"tls"`
+	// Transport tunes the connection pool to the bidder's endpoint, for bidders whose traffic doesn't suit the
+	// host's http_client settings.
+	Transport *BidderTransport `yaml:"transport" mapstructure:"transport"`
+	// Hedging sends a request to the bidder a second time if it's slower to answer than most, and takes
+	// whichever answer comes first, for bidders whose endpoints are sometimes slow for no reason.
+	Hedging *BidderHedging `yaml:"hedging" mapstructure:"hedging"`
+	// AcceptsTestTraffic is true if the bidder takes requests with test=1. If test_traffic is enabled, test
+	// requests are only sent to the bidders which accept them.
+	AcceptsTestTraffic bool `yaml:"acceptsTestTraffic" mapstructure:"acceptsTestTraffic"`
+}
+
+// BidderCanary configures the canary version of an adapter. Fields which aren't set are the same as in
+// the current configuration.
+type BidderCanary struct {
+	Endpoint         string `yaml:"endpoint" mapstructure:"endpoint"`
+	ExtraAdapterInfo string `yaml:"extra_info" mapstructure:"extra_info"`
+	// Percent is the share of requests, from 0 to 100, sent with the canary configuration. Accounts may
+	// override it.
+	Percent float64 `yaml:"percent" mapstructure:"percent"`
+}
+
+// BidderTLS configures the TLS connections to a bidder's endpoint. It applies to every request sent to the
+// endpoint's host.
+type BidderTLS struct {
+	// RootCert is a PEM file of CA certificates trusted for the endpoint, in addition to the host's.
+	RootCert string `yaml:"rootCert" mapstructure:"rootCert"`
+	// ClientCert and ClientKey are PEM files of the certificate, and its key, presented to the endpoint.
+	ClientCert string `yaml:"clientCert" mapstructure:"clientCert"`
+	ClientKey  string `yaml:"clientKey" mapstructure:"clientKey"`
+	// MinVersion is the lowest TLS version accepted, from "1.0" to "1.3". It's Go's default if not set.
+	MinVersion string `yaml:"minVersion" mapstructure:"minVersion"`
+}
+
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// MinTLSVersion returns the tls package's constant for MinVersion, or 0 if it isn't set.
+func (t *BidderTLS) MinTLSVersion() uint16 {
+	return tlsVersions[t.MinVersion]
+}
+
+// BidderTransport configures the connection pool to a bidder's endpoint. Like BidderTLS, it applies to every
+// request sent to the endpoint's host. Fields which aren't set are the same as in the host's http_client.
+type BidderTransport struct {
+	MaxConnsPerHost     int `yaml:"maxConnsPerHost" mapstructure:"maxConnsPerHost"`
+	MaxIdleConnsPerHost int `yaml:"maxIdleConnsPerHost" mapstructure:"maxIdleConnsPerHost"`
+	// IdleConnTimeoutSeconds is how long an idle connection is kept open.
+	IdleConnTimeoutSeconds int `yaml:"idleConnTimeoutSeconds" mapstructure:"idleConnTimeoutSeconds"`
+	// HTTP2 negotiates HTTP/2 with https endpoints, so that the requests share few connections.
+	HTTP2 bool `yaml:"http2" mapstructure:"http2"`
+	// TLSSessionCacheSize is the number of TLS sessions kept to be resumed, which saves a full handshake
+	// when a new connection is made. Sessions aren't resumed if it's 0.
+	TLSSessionCacheSize int `yaml:"tlsSessionCacheSize" mapstructure:"tlsSessionCacheSize"`
+}
+
+// BidderHedging configures the hedged requests to a bidder. A request which hasn't been answered within the
+// percentile of the bidder's recent response times is sent again, to the same endpoint.
+type BidderHedging struct {
+	// Percentile of the recent response times after which a request is sent again, above 0 and below 100.
+	Percentile float64 `yaml:"percentile" mapstructure:"percentile"`
+	// MinDelayMs is the least time to wait before sending a request again, so that a bidder which answers
+	// quickly isn't sent every request twice.
+	MinDelayMs int `yaml:"minDelayMs" mapstructure:"minDelayMs"`
 }
 
 type aliasNillableFields struct {
@@ -95,9 +176,15 @@ type AdapterXAPI struct {
 // OpenRTBInfo specifies the versions/aspects of openRTB that a bidder supports
 // Version is not yet actively supported
 // GPPSupported is not yet actively supported
+// MultiformatSupported is false for bidders which take one media type per imp. They're sent only the media
+// type the account prefers of each multiformat imp. It's supported if it isn't set.
+// Currency is the currency the bidder takes floors in. If price_floors.currency_conversion is on, the floors
+// of imps in other currencies are converted to it before the bidder is called.
 type OpenRTBInfo struct {
-	Version      string `yaml:"version" mapstructure:"version"`
-	GPPSupported bool   `yaml:"gpp-supported" mapstructure:"gpp-supported"`
+	Version              string `yaml:"version" mapstructure:"version"`
+	GPPSupported         bool   `yaml:"gpp-supported" mapstructure:"gpp-supported"`
+	MultiformatSupported *bool  `yaml:"multiformat-supported" mapstructure:"multiformat-supported"`
+	Currency             string `yaml:"currency" mapstructure:"currency"`
 }
 
 // Syncer specifies the user sync settings for a bidder. This struct is shared by the account config,
@@ -332,9 +419,15 @@ func processBidderAliases(aliasNillableFieldsByBidder map[string]aliasNillableFi
 		if aliasBidderInfo.OpenRTB == nil {
 			aliasBidderInfo.OpenRTB = parentBidderInfo.OpenRTB
 		}
+		if aliasBidderInfo.MaxResponseSize == 0 {
+			aliasBidderInfo.MaxResponseSize = parentBidderInfo.MaxResponseSize
+		}
 		if aliasBidderInfo.PlatformID == "" {
 			aliasBidderInfo.PlatformID = parentBidderInfo.PlatformID
 		}
+		if !aliasBidderInfo.AcceptsTestTraffic {
+			aliasBidderInfo.AcceptsTestTraffic = parentBidderInfo.AcceptsTestTraffic
+		}
 		if aliasBidderInfo.Syncer == nil && parentBidderInfo.Syncer != nil {
 			syncerKey := aliasBidderInfo.AliasOf
 			if parentBidderInfo.Syncer.Key != "" {
@@ -385,11 +478,92 @@ func (infos BidderInfos) validate(errs []error) []error {
 			if err := validateSyncer(bidder); err != nil {
 				errs = append(errs, err)
 			}
+
+			if bidder.Canary != nil {
+				errs = validateCanary(bidder.Canary, bidderName, errs)
+			}
+
+			if bidder.TimeoutNotificationURL != "" {
+				errs = validateTimeoutNotificationURL(bidder.TimeoutNotificationURL, bidderName, errs)
+			}
+
+			if bidder.TLS != nil {
+				errs = validateBidderTLS(bidder.TLS, bidder.Endpoint, bidderName, errs)
+			}
+
+			if bidder.Transport != nil {
+				errs = validateBidderTransport(bidder.Transport, bidder.Endpoint, bidderName, errs)
+			}
+
+			if bidder.Hedging != nil {
+				errs = validateBidderHedging(bidder.Hedging, bidderName, errs)
+			}
 		}
 	}
 	return errs
 }
 
+func validateCanary(canary *BidderCanary, bidderName string, errs []error) []error {
+	if canary.Endpoint != "" {
+		errs = validateAdapterEndpoint(canary.Endpoint, bidderName, errs)
+	}
+	if canary.Percent < 0 || canary.Percent > 100 {
+		errs = append(errs, fmt.Errorf("canary.percent must be in the range [0, 100] for adapter: %s. Got %f", bidderName, canary.Percent))
+	}
+	return errs
+}
+
+func validateBidderTLS(bidderTLS *BidderTLS, endpoint string, bidderName string, errs []error) []error {
+	if (bidderTLS.ClientCert == "") != (bidderTLS.ClientKey == "") {
+		errs = append(errs, fmt.Errorf("tls.clientCert and tls.clientKey must be set together for adapter: %s", bidderName))
+	}
+	if bidderTLS.MinVersion != "" && bidderTLS.MinTLSVersion() == 0 {
+		errs = append(errs, fmt.Errorf("tls.minVersion must be one of 1.0, 1.1, 1.2 or 1.3 for adapter: %s. Got %s", bidderName, bidderTLS.MinVersion))
+	}
+	if !strings.HasPrefix(endpoint, "https://") {
+		errs = append(errs, fmt.Errorf("tls requires an https endpoint for adapter: %s. Got %s", bidderName, endpoint))
+	} else {
+		errs = validateFixedHost("tls", endpoint, bidderName, errs)
+	}
+	return errs
+}
+
+func validateBidderTransport(transport *BidderTransport, endpoint string, bidderName string, errs []error) []error {
+	if transport.MaxConnsPerHost < 0 {
+		errs = append(errs, fmt.Errorf("transport.maxConnsPerHost must be >= 0 for adapter: %s. Got %d", bidderName, transport.MaxConnsPerHost))
+	}
+	if transport.MaxIdleConnsPerHost < 0 {
+		errs = append(errs, fmt.Errorf("transport.maxIdleConnsPerHost must be >= 0 for adapter: %s. Got %d", bidderName, transport.MaxIdleConnsPerHost))
+	}
+	if transport.IdleConnTimeoutSeconds < 0 {
+		errs = append(errs, fmt.Errorf("transport.idleConnTimeoutSeconds must be >= 0 for adapter: %s. Got %d", bidderName, transport.IdleConnTimeoutSeconds))
+	}
+	if transport.TLSSessionCacheSize < 0 {
+		errs = append(errs, fmt.Errorf("transport.tlsSessionCacheSize must be >= 0 for adapter: %s. Got %d", bidderName, transport.TLSSessionCacheSize))
+	}
+	return validateFixedHost("transport", endpoint, bidderName, errs)
+}
+
+func validateBidderHedging(hedging *BidderHedging, bidderName string, errs []error) []error {
+	if hedging.Percentile <= 0 || hedging.Percentile >= 100 {
+		errs = append(errs, fmt.Errorf("hedging.percentile must be in the range (0, 100) for adapter: %s. Got %f", bidderName, hedging.Percentile))
+	}
+	if hedging.MinDelayMs < 0 {
+		errs = append(errs, fmt.Errorf("hedging.minDelayMs must be >= 0 for adapter: %s. Got %d", bidderName, hedging.MinDelayMs))
+	}
+	return errs
+}
+
+// validateFixedHost checks that the host of the endpoint has no macros. The bidder's tls and transport are
+// chosen by the host of each request, so it must be known up front.
+func validateFixedHost(field string, endpoint string, bidderName string, errs []error) []error {
+	_, rest, _ := strings.Cut(endpoint, "://")
+	if host, _, _ := strings.Cut(rest, "/"); strings.Contains(host, "{{") {
+		errs = append(errs, fmt.Errorf("%s requires an endpoint without macros in its host for adapter: %s. Got %s", field, bidderName, endpoint))
+	}
+	return errs
+}
+
 func validateAliases(aliasBidderInfo BidderInfo, infos BidderInfos, bidderName string) error {
 	if len(aliasBidderInfo.AliasOf) > 0 {
 		if parentBidder, ok := infos[aliasBidderInfo.AliasOf]; ok {
@@ -445,6 +619,28 @@ func validateAdapterEndpoint(endpoint string, bidderName string, errs []error) [
 	return errs
 }
 
+var testTimeoutNotificationTemplateParams = macros.TimeoutNotificationTemplateParams{
+	Bidder:    "anyBidder",
+	RequestID: "anyRequestID",
+	ImpIDs:    "anyImpID",
+	Timeout:   "100",
+}
+
+func validateTimeoutNotificationURL(notificationURL string, bidderName string, errs []error) []error {
+	notificationTemplate, err := template.New("timeoutNotificationTemplate").Parse(notificationURL)
+	if err != nil {
+		return append(errs, fmt.Errorf("Invalid timeoutNotificationUrl template: %s for adapter: %s. %v", notificationURL, bidderName, err))
+	}
+	resolvedURL, err := macros.ResolveMacros(notificationTemplate, testTimeoutNotificationTemplateParams)
+	if err != nil {
+		return append(errs, fmt.Errorf("Unable to resolve timeoutNotificationUrl: %s for adapter: %s. %v", notificationURL, bidderName, err))
+	}
+	if !validator.IsURL(resolvedURL) || !validator.IsRequestURL(resolvedURL) {
+		errs = append(errs, fmt.Errorf("The timeoutNotificationUrl: %s for %s is not a valid URL", resolvedURL, bidderName))
+	}
+	return errs
+}
+
 func validateInfo(bidder BidderInfo, infos BidderInfos, bidderName string) error {
 	if err := validateMaintainer(bidder.Maintainer, bidderName); err != nil {
 		return err
@@ -452,6 +648,14 @@ func validateInfo(bidder BidderInfo, infos BidderInfos, bidderName string) error
 	if err := validateCapabilities(bidder.Capabilities, bidderName); err != nil {
 		return err
 	}
+	if bidder.MaxResponseSize < 0 {
+		return fmt.Errorf("maxResponseSize must be >= 0 for adapter: %s. Got %d", bidderName, bidder.MaxResponseSize)
+	}
+	if bidder.OpenRTB != nil && bidder.OpenRTB.Currency != "" {
+		if _, err := currency.ParseISO(bidder.OpenRTB.Currency); err != nil {
+			return fmt.Errorf("openrtb.currency must be an ISO 4217 currency code for adapter: %s. Got %s", bidderName, bidder.OpenRTB.Currency)
+		}
+	}
 	if len(bidder.AliasOf) > 0 {
 		if err := validateAliasCapabilities(bidder, infos, bidderName); err != nil {
 			return err
@@ -646,6 +850,27 @@ func applyBidderInfoConfigOverrides(configBidderInfos nillableFieldBidderInfos,
 		if configBidderInfo.bidderInfo.OpenRTB != nil {
 			mergedBidderInfo.OpenRTB = configBidderInfo.bidderInfo.OpenRTB
 		}
+		if configBidderInfo.bidderInfo.MaxResponseSize > 0 {
+			mergedBidderInfo.MaxResponseSize = configBidderInfo.bidderInfo.MaxResponseSize
+		}
+		if configBidderInfo.bidderInfo.Canary != nil {
+			mergedBidderInfo.Canary = configBidderInfo.bidderInfo.Canary
+		}
+		if configBidderInfo.bidderInfo.TimeoutNotificationURL != "" {
+			mergedBidderInfo.TimeoutNotificationURL = configBidderInfo.bidderInfo.TimeoutNotificationURL
+		}
+		if configBidderInfo.bidderInfo.TLS != nil {
+			mergedBidderInfo.TLS = configBidderInfo.bidderInfo.TLS
+		}
+		if configBidderInfo.bidderInfo.Transport != nil {
+			mergedBidderInfo.Transport = configBidderInfo.bidderInfo.Transport
+		}
+		if configBidderInfo.bidderInfo.Hedging != nil {
+			mergedBidderInfo.Hedging = configBidderInfo.bidderInfo.Hedging
+		}
+		if configBidderInfo.bidderInfo.AcceptsTestTraffic {
+			mergedBidderInfo.AcceptsTestTraffic = true
+		}
 
 		mergedBidderInfos[string(normalizedBidderName)] = mergedBidderInfo
 	}
@@ -310,6 +310,7 @@ func TestProcessAliasBidderInfo(t *testing.T) {
 		Maintainer: &MaintainerInfo{
 			Email: "some-email@domain.com",
 		},
+		MaxResponseSize:         1000,
 		ModifyingVastXmlAllowed: true,
 		OpenRTB: &OpenRTBInfo{
 			GPPSupported: true,
@@ -357,6 +358,7 @@ func TestProcessAliasBidderInfo(t *testing.T) {
 		Maintainer: &MaintainerInfo{
 			Email: "alias-email@domain.com",
 		},
+		MaxResponseSize:         2000,
 		ModifyingVastXmlAllowed: false,
 		OpenRTB: &OpenRTBInfo{
 			GPPSupported: false,
@@ -736,6 +738,76 @@ func TestBidderInfoValidationNegative(t *testing.T) {
 				errors.New("There's no default endpoint available for bidderA. Calls to this bidder/exchange will fail. Please set adapters.bidderA.endpoint in your app config"),
 			},
 		},
+		{
+			"One bidder invalid canary",
+			BidderInfos{
+				"bidderA": BidderInfo{
+					Endpoint: "http://bidderA.com/openrtb2",
+					Maintainer: &MaintainerInfo{
+						Email: "maintainer@bidderA.com",
+					},
+					Capabilities: &CapabilitiesInfo{
+						App: &PlatformInfo{
+							MediaTypes: []openrtb_ext.BidType{
+								openrtb_ext.BidTypeVideo,
+							},
+						},
+					},
+					Canary: &BidderCanary{
+						Endpoint: "incorrect",
+						Percent:  101,
+					},
+				},
+			},
+			[]error{
+				errors.New("The endpoint: incorrect for bidderA is not a valid URL"),
+				errors.New("canary.percent must be in the range [0, 100] for adapter: bidderA. Got 101.000000"),
+			},
+		},
+		{
+			"One bidder negative max response size",
+			BidderInfos{
+				"bidderA": BidderInfo{
+					Endpoint: "http://bidderA.com/openrtb2",
+					Maintainer: &MaintainerInfo{
+						Email: "maintainer@bidderA.com",
+					},
+					Capabilities: &CapabilitiesInfo{
+						App: &PlatformInfo{
+							MediaTypes: []openrtb_ext.BidType{
+								openrtb_ext.BidTypeVideo,
+							},
+						},
+					},
+					MaxResponseSize: -1,
+				},
+			},
+			[]error{
+				errors.New("maxResponseSize must be >= 0 for adapter: bidderA. Got -1"),
+			},
+		},
+		{
+			"One bidder invalid currency",
+			BidderInfos{
+				"bidderA": BidderInfo{
+					Endpoint: "http://bidderA.com/openrtb2",
+					Maintainer: &MaintainerInfo{
+						Email: "maintainer@bidderA.com",
+					},
+					Capabilities: &CapabilitiesInfo{
+						App: &PlatformInfo{
+							MediaTypes: []openrtb_ext.BidType{
+								openrtb_ext.BidTypeVideo,
+							},
+						},
+					},
+					OpenRTB: &OpenRTBInfo{Currency: "XYZ"},
+				},
+			},
+			[]error{
+				errors.New("openrtb.currency must be an ISO 4217 currency code for adapter: bidderA. Got XYZ"),
+			},
+		},
 		{
 			"One bidder incorrect url template",
 			BidderInfos{
@@ -778,6 +850,123 @@ func TestBidderInfoValidationNegative(t *testing.T) {
 				errors.New("Invalid endpoint template: http://bidderA.com/openrtb2/getuid?r=[{{.]RedirectURL}} for adapter: bidderA. template: endpointTemplate:1: bad character U+005D ']'"),
 			},
 		},
+		{
+			"One bidder incorrect timeout notification url",
+			BidderInfos{
+				"bidderA": BidderInfo{
+					Endpoint:               "http://bidderA.com/openrtb2",
+					TimeoutNotificationURL: "http://bidderA.com/timeout?id={{.Unknown}}",
+					Maintainer: &MaintainerInfo{
+						Email: "maintainer@bidderA.com",
+					},
+					Capabilities: &CapabilitiesInfo{
+						App: &PlatformInfo{
+							MediaTypes: []openrtb_ext.BidType{
+								openrtb_ext.BidTypeVideo,
+							},
+						},
+					},
+				},
+			},
+			[]error{
+				errors.New("Unable to resolve timeoutNotificationUrl: http://bidderA.com/timeout?id={{.Unknown}} for adapter: bidderA. template: timeoutNotificationTemplate:1:32: executing \"timeoutNotificationTemplate\" at <.Unknown>: can't evaluate field Unknown in type macros.TimeoutNotificationTemplateParams"),
+			},
+		},
+		{
+			"One bidder invalid tls",
+			BidderInfos{
+				"bidderA": BidderInfo{
+					Endpoint: "http://bidderA.com/openrtb2",
+					TLS:      &BidderTLS{ClientCert: "client.pem", MinVersion: "1.4"},
+					Maintainer: &MaintainerInfo{
+						Email: "maintainer@bidderA.com",
+					},
+					Capabilities: &CapabilitiesInfo{
+						App: &PlatformInfo{
+							MediaTypes: []openrtb_ext.BidType{
+								openrtb_ext.BidTypeVideo,
+							},
+						},
+					},
+				},
+			},
+			[]error{
+				errors.New("tls.clientCert and tls.clientKey must be set together for adapter: bidderA"),
+				errors.New("tls.minVersion must be one of 1.0, 1.1, 1.2 or 1.3 for adapter: bidderA. Got 1.4"),
+				errors.New("tls requires an https endpoint for adapter: bidderA. Got http://bidderA.com/openrtb2"),
+			},
+		},
+		{
+			"One bidder tls with macro in endpoint host",
+			BidderInfos{
+				"bidderA": BidderInfo{
+					Endpoint: "https://{{.Host}}.bidderA.com/openrtb2",
+					TLS:      &BidderTLS{RootCert: "ca.pem"},
+					Maintainer: &MaintainerInfo{
+						Email: "maintainer@bidderA.com",
+					},
+					Capabilities: &CapabilitiesInfo{
+						App: &PlatformInfo{
+							MediaTypes: []openrtb_ext.BidType{
+								openrtb_ext.BidTypeVideo,
+							},
+						},
+					},
+				},
+			},
+			[]error{
+				errors.New("tls requires an endpoint without macros in its host for adapter: bidderA. Got https://{{.Host}}.bidderA.com/openrtb2"),
+			},
+		},
+		{
+			"One bidder invalid transport",
+			BidderInfos{
+				"bidderA": BidderInfo{
+					Endpoint:  "http://{{.Host}}.bidderA.com/openrtb2",
+					Transport: &BidderTransport{MaxConnsPerHost: -1, MaxIdleConnsPerHost: -2, IdleConnTimeoutSeconds: -3, TLSSessionCacheSize: -4},
+					Maintainer: &MaintainerInfo{
+						Email: "maintainer@bidderA.com",
+					},
+					Capabilities: &CapabilitiesInfo{
+						App: &PlatformInfo{
+							MediaTypes: []openrtb_ext.BidType{
+								openrtb_ext.BidTypeVideo,
+							},
+						},
+					},
+				},
+			},
+			[]error{
+				errors.New("transport.maxConnsPerHost must be >= 0 for adapter: bidderA. Got -1"),
+				errors.New("transport.maxIdleConnsPerHost must be >= 0 for adapter: bidderA. Got -2"),
+				errors.New("transport.idleConnTimeoutSeconds must be >= 0 for adapter: bidderA. Got -3"),
+				errors.New("transport.tlsSessionCacheSize must be >= 0 for adapter: bidderA. Got -4"),
+				errors.New("transport requires an endpoint without macros in its host for adapter: bidderA. Got http://{{.Host}}.bidderA.com/openrtb2"),
+			},
+		},
+		{
+			"One bidder invalid hedging",
+			BidderInfos{
+				"bidderA": BidderInfo{
+					Endpoint: "http://bidderA.com/openrtb2",
+					Hedging:  &BidderHedging{Percentile: 100, MinDelayMs: -1},
+					Maintainer: &MaintainerInfo{
+						Email: "maintainer@bidderA.com",
+					},
+					Capabilities: &CapabilitiesInfo{
+						App: &PlatformInfo{
+							MediaTypes: []openrtb_ext.BidType{
+								openrtb_ext.BidTypeVideo,
+							},
+						},
+					},
+				},
+			},
+			[]error{
+				errors.New("hedging.percentile must be in the range (0, 100) for adapter: bidderA. Got 100.000000"),
+				errors.New("hedging.minDelayMs must be >= 0 for adapter: bidderA. Got -1"),
+			},
+		},
 		{
 			"One bidder no maintainer",
 			BidderInfos{
@@ -1690,6 +1879,36 @@ func TestApplyBidderInfoConfigOverrides(t *testing.T) {
 			givenConfigBidderInfos: nillableFieldBidderInfos{"a": {bidderInfo: BidderInfo{EndpointCompression: "LZ77", Syncer: &Syncer{Key: "override"}}}},
 			expectedBidderInfos:    BidderInfos{"a": {EndpointCompression: "LZ77", Syncer: &Syncer{Key: "override"}}},
 		},
+		{
+			description:            "Override TimeoutNotificationURL",
+			givenFsBidderInfos:     BidderInfos{"a": {TimeoutNotificationURL: "https://a.com/timeout"}},
+			givenConfigBidderInfos: nillableFieldBidderInfos{"a": {bidderInfo: BidderInfo{TimeoutNotificationURL: "https://b.com/timeout", Syncer: &Syncer{Key: "override"}}}},
+			expectedBidderInfos:    BidderInfos{"a": {TimeoutNotificationURL: "https://b.com/timeout", Syncer: &Syncer{Key: "override"}}},
+		},
+		{
+			description:            "Override TLS",
+			givenFsBidderInfos:     BidderInfos{"a": {TLS: &BidderTLS{RootCert: "a.pem"}}},
+			givenConfigBidderInfos: nillableFieldBidderInfos{"a": {bidderInfo: BidderInfo{TLS: &BidderTLS{RootCert: "b.pem"}, Syncer: &Syncer{Key: "override"}}}},
+			expectedBidderInfos:    BidderInfos{"a": {TLS: &BidderTLS{RootCert: "b.pem"}, Syncer: &Syncer{Key: "override"}}},
+		},
+		{
+			description:            "Override Transport",
+			givenFsBidderInfos:     BidderInfos{"a": {Transport: &BidderTransport{MaxIdleConnsPerHost: 10}}},
+			givenConfigBidderInfos: nillableFieldBidderInfos{"a": {bidderInfo: BidderInfo{Transport: &BidderTransport{HTTP2: true}, Syncer: &Syncer{Key: "override"}}}},
+			expectedBidderInfos:    BidderInfos{"a": {Transport: &BidderTransport{HTTP2: true}, Syncer: &Syncer{Key: "override"}}},
+		},
+		{
+			description:            "Override Hedging",
+			givenFsBidderInfos:     BidderInfos{"a": {Hedging: &BidderHedging{Percentile: 95}}},
+			givenConfigBidderInfos: nillableFieldBidderInfos{"a": {bidderInfo: BidderInfo{Hedging: &BidderHedging{Percentile: 99, MinDelayMs: 50}, Syncer: &Syncer{Key: "override"}}}},
+			expectedBidderInfos:    BidderInfos{"a": {Hedging: &BidderHedging{Percentile: 99, MinDelayMs: 50}, Syncer: &Syncer{Key: "override"}}},
+		},
+		{
+			description:            "Override AcceptsTestTraffic",
+			givenFsBidderInfos:     BidderInfos{"a": {AcceptsTestTraffic: false}},
+			givenConfigBidderInfos: nillableFieldBidderInfos{"a": {bidderInfo: BidderInfo{AcceptsTestTraffic: true, Syncer: &Syncer{Key: "override"}}}},
+			expectedBidderInfos:    BidderInfos{"a": {AcceptsTestTraffic: true, Syncer: &Syncer{Key: "override"}}},
+		},
 		{
 			description:            "Don't override Disabled",
 			givenFsBidderInfos:     BidderInfos{"a": {Disabled: true}},
@@ -1726,6 +1945,18 @@ func TestApplyBidderInfoConfigOverrides(t *testing.T) {
 			givenConfigBidderInfos: nillableFieldBidderInfos{"a": {bidderInfo: BidderInfo{OpenRTB: &OpenRTBInfo{Version: "2"}, Syncer: &Syncer{Key: "override"}}}},
 			expectedBidderInfos:    BidderInfos{"a": {OpenRTB: &OpenRTBInfo{Version: "2"}, Syncer: &Syncer{Key: "override"}}},
 		},
+		{
+			description:            "Don't override MaxResponseSize",
+			givenFsBidderInfos:     BidderInfos{"a": {MaxResponseSize: 1000}},
+			givenConfigBidderInfos: nillableFieldBidderInfos{"a": {bidderInfo: BidderInfo{Syncer: &Syncer{Key: "override"}}}},
+			expectedBidderInfos:    BidderInfos{"a": {MaxResponseSize: 1000, Syncer: &Syncer{Key: "override"}}},
+		},
+		{
+			description:            "Override MaxResponseSize",
+			givenFsBidderInfos:     BidderInfos{"a": {MaxResponseSize: 1000}},
+			givenConfigBidderInfos: nillableFieldBidderInfos{"a": {bidderInfo: BidderInfo{MaxResponseSize: 2000, Syncer: &Syncer{Key: "override"}}}},
+			expectedBidderInfos:    BidderInfos{"a": {MaxResponseSize: 2000, Syncer: &Syncer{Key: "override"}}},
+		},
 		{
 			description:            "Don't override AliasOf",
 			givenFsBidderInfos:     BidderInfos{"a": {AliasOf: "Alias1"}},
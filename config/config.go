@@ -4,17 +4,22 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
+	"net"
 	"net/url"
 	"reflect"
+	"regexp"
+	"runtime/debug"
 	"strings"
 	"time"
 
-	"github.com/golang/glog"
 	"github.com/prebid/go-gdpr/consentconstants"
 	"github.com/prebid/openrtb/v20/openrtb2"
 	"github.com/prebid/prebid-server/v2/errortypes"
+	"github.com/prebid/prebid-server/v2/logger"
 	"github.com/prebid/prebid-server/v2/openrtb_ext"
 	"github.com/prebid/prebid-server/v2/util/jsonutil"
+	"github.com/prebid/prebid-server/v2/util/versionutil"
 	"github.com/spf13/viper"
 )
 
@@ -101,6 +106,92 @@ type Configuration struct {
 	Hooks       Hooks       `mapstructure:"hooks"`
 	Validations Validations `mapstructure:"validations"`
 	PriceFloors PriceFloors `mapstructure:"price_floors"`
+	// BidderRequestPool bounds the number of concurrent bidder HTTP requests across all auctions
+	BidderRequestPool BidderRequestPool `mapstructure:"bidder_request_pool"`
+	// BidderConnectionWarmup opens connections to bidder endpoints at startup and keeps them alive while idle
+	BidderConnectionWarmup BidderConnectionWarmup `mapstructure:"bidder_connection_warmup"`
+	// InProcessBidders answers the calls to the listed bidders with a mock bidder inside the server, for load tests
+	InProcessBidders InProcessBidders `mapstructure:"in_process_bidders"`
+	// AuctionSimulation serves /openrtb2/simulate, which holds auctions with the bidder responses it's given
+	AuctionSimulation AuctionSimulation `mapstructure:"auction_simulation"`
+	// TestTraffic keeps requests with test=1 apart from production traffic
+	TestTraffic TestTraffic `mapstructure:"test_traffic"`
+	// RequestValidationEndpoint serves /openrtb2/validate, which reports the errors and warnings of requests without auctioning them
+	RequestValidationEndpoint RequestValidationEndpoint `mapstructure:"request_validation_endpoint"`
+	// OpenRTB3 serves /openrtb3/auction, which takes OpenRTB 3.0 requests and holds their auctions as /openrtb2/auction
+	OpenRTB3 OpenRTB3 `mapstructure:"openrtb3"`
+	// DeterministicIDs makes the IDs the server generates the same on every run, for golden file tests
+	DeterministicIDs DeterministicIDs `mapstructure:"deterministic_ids"`
+	// MaxBidderResponseSize is the largest bid response body, in bytes, read from a bidder. Use 0 for no limit.
+	MaxBidderResponseSize int64 `mapstructure:"max_bidder_response_size"`
+	// ParsedStoredRequestCache caches stored requests and imps after they've been merged or unmarshaled
+	ParsedStoredRequestCache ParsedStoredRequestCache `mapstructure:"parsed_stored_request_cache"`
+	// BidderResponseCache reuses the responses of the listed bidders to anonymous requests for the same context
+	BidderResponseCache BidderResponseCache `mapstructure:"bidder_response_cache"`
+	// LoadShedding rejects requests to the auction endpoints, or skips optional work for them, when the server is overloaded
+	LoadShedding LoadShedding `mapstructure:"load_shedding"`
+	// LatencyBudget divides the tmax of requests to /openrtb2/auction between the stages of the auction
+	LatencyBudget LatencyBudget `mapstructure:"latency_budget"`
+	// AuctionRecording captures sampled auctions, with everything needed to replay them against the current code
+	AuctionRecording AuctionRecording `mapstructure:"auction_recording"`
+	// TrafficShadowing copies sampled requests to /openrtb2/auction to a shadow host, to try out release candidates on real traffic
+	TrafficShadowing TrafficShadowing `mapstructure:"traffic_shadowing"`
+	// Webhooks notifies external systems of changes to accounts and stored data
+	Webhooks Webhooks `mapstructure:"webhooks"`
+	// AuctionEventWebhooks sends the win, loss and render events of accounts to the webhooks they configure
+	AuctionEventWebhooks AuctionEventWebhooks `mapstructure:"auction_event_webhooks"`
+	// BidLandscape keeps statistics of recent bids in memory, and serves them on the admin server
+	BidLandscape BidLandscape `mapstructure:"bid_landscape"`
+	// AuctionQuality keeps rolling statistics of the health of each account's auctions, which publishers fetch with their API keys
+	AuctionQuality AuctionQuality `mapstructure:"auction_quality"`
+	// Metering counts the billable usage of each account, and exports it to the host's billing sink
+	Metering Metering `mapstructure:"metering"`
+	// ResponseOverrides pins a bidder's responses for an account to a stored bid response for a while, through the admin server
+	ResponseOverrides ResponseOverrides `mapstructure:"response_overrides"`
+	// StoredRequestVersions keeps the last versions of stored requests and imps, and pins accounts to one of them through the admin server
+	StoredRequestVersions StoredRequestVersions `mapstructure:"stored_request_versions"`
+	// BidderMaintenance leaves bidders out of auctions during their maintenance windows, or while their kill switch is on
+	BidderMaintenance BidderMaintenance `mapstructure:"bidder_maintenance"`
+	// AdaptiveBidderTmax tracks each bidder's recent response times, and gives bidders no more time than they usually take in the auctions of accounts which opt in
+	AdaptiveBidderTmax AdaptiveBidderTmax `mapstructure:"adaptive_bidder_tmax"`
+	// FaultInjection adds latency, errors and malformed responses to calls to bidders, stored data and the cache,
+	// to test how the server copes with them in staging. It must never be enabled in production.
+	FaultInjection FaultInjection `mapstructure:"fault_injection"`
+	// Logging configures the format of the logs, and the levels they're written at
+	Logging Logging `mapstructure:"logging"`
+	// JSON selects the engine requests and responses are unmarshaled and marshaled with
+	JSON JSON `mapstructure:"json"`
+	// BidderTimeoutNotifications sends bidders the timeout notifications their info asks for, within a rate limit
+	BidderTimeoutNotifications BidderTimeoutNotifications `mapstructure:"bidder_timeout_notifications"`
+	// RequestDecoding configures how the body of /openrtb2/auction requests is read
+	RequestDecoding RequestDecoding `mapstructure:"request_decoding"`
+	// RequestNormalization fixes well known mistakes in /openrtb2/auction requests before they're validated
+	RequestNormalization RequestNormalization `mapstructure:"request_normalization"`
+	// IVT screens requests to the auction endpoints for invalid traffic before they're auctioned
+	IVT IVT `mapstructure:"ivt"`
+	// ResponseSigning holds the keys accounts may have their auction responses signed with
+	ResponseSigning ResponseSigning `mapstructure:"response_signing"`
+	// GeoLocation fills in device.geo from the device's IP address for requests which don't say where it is
+	GeoLocation GeoLocation `mapstructure:"geolocation"`
+	// ContentClassification fills in the categories and segments of the content of a request's page or app from a classifier
+	ContentClassification ContentClassification `mapstructure:"content_classification"`
+	// PIIScanner looks for personal data in the requests sent to bidders which the privacy policies should have kept out
+	PIIScanner PIIScanner `mapstructure:"pii_scanner"`
+	// MobileSDK fills in and normalizes the signals of requests from the Prebid Mobile SDK
+	MobileSDK MobileSDK `mapstructure:"mobile_sdk"`
+	// RateLimiting keeps the counters behind the API key rate limits, bidder QPS caps and deal pacing
+	RateLimiting RateLimiting `mapstructure:"rate_limiting"`
+	// StoredDataEncryption configures decryption of encrypted values in stored data and account configs
+	StoredDataEncryption StoredDataEncryption `mapstructure:"stored_data_encryption"`
+	// StoredDataBus propagates stored data saves and invalidations to every instance of the fleet
+	StoredDataBus StoredDataBus `mapstructure:"stored_data_bus"`
+	// ConfigWatch applies changes to the host config files to the settings which may change without a restart
+	ConfigWatch ConfigWatch `mapstructure:"config_watch"`
+	// StrictDeprecatedSettings fails config validation if any deprecated setting is used instead of migrating
+	// it to its replacement with a warning.
+	StrictDeprecatedSettings bool `mapstructure:"strict_deprecated_settings"`
+	// deprecatedSettingsInUse lists the deprecated settings found in the host config
+	deprecatedSettingsInUse []DeprecatedSetting
 }
 
 type Admin struct {
@@ -109,6 +200,9 @@ type Admin struct {
 type PriceFloors struct {
 	Enabled bool              `mapstructure:"enabled"`
 	Fetcher PriceFloorFetcher `mapstructure:"fetcher"`
+	// CurrencyConversion converts the floors of imps to the currency of bidders whose info names one, so
+	// that bidders which ignore imp.bidfloorcur don't read a floor in another currency as one in theirs.
+	CurrencyConversion bool `mapstructure:"currency_conversion"`
 }
 
 type PriceFloorFetcher struct {
@@ -121,6 +215,1290 @@ type PriceFloorFetcher struct {
 
 const MIN_COOKIE_SIZE_BYTES = 500
 
+// BidderRequestPool configures a shared pool of workers which make the HTTP requests to bidders. Requests
+// wait in a bounded queue for a free worker and are shed when the queue is full, so a traffic spike degrades
+// into dropped bidder requests rather than unbounded goroutine growth.
+type BidderRequestPool struct {
+	Enabled   bool `mapstructure:"enabled"`
+	Workers   int  `mapstructure:"workers"`
+	QueueSize int  `mapstructure:"queue_size"`
+}
+
+func (cfg *BidderRequestPool) validate(errs []error) []error {
+	if !cfg.Enabled {
+		return errs
+	}
+	if cfg.Workers <= 0 {
+		errs = append(errs, fmt.Errorf("bidder_request_pool.workers must be > 0. Got %d", cfg.Workers))
+	}
+	if cfg.QueueSize < 0 {
+		errs = append(errs, fmt.Errorf("bidder_request_pool.queue_size must be >= 0. Got %d", cfg.QueueSize))
+	}
+	return errs
+}
+
+// BidderConnectionWarmup configures requests made to the endpoints of the listed bidders so their connections
+// are already open when auctions need them. The warmup runs once before the server starts accepting requests
+// and then every KeepAliveIntervalSeconds, which should be shorter than the idle connection timeout of the
+// http_client.
+type BidderConnectionWarmup struct {
+	Enabled                  bool     `mapstructure:"enabled"`
+	Bidders                  []string `mapstructure:"bidders"`
+	Connections              int      `mapstructure:"connections"`
+	KeepAliveIntervalSeconds int      `mapstructure:"keep_alive_interval_seconds"`
+	TimeoutMs                int      `mapstructure:"timeout_ms"`
+}
+
+func (cfg *BidderConnectionWarmup) validate(bidderInfos BidderInfos, client HTTPClient, errs []error) []error {
+	if !cfg.Enabled {
+		return errs
+	}
+	for _, bidder := range cfg.Bidders {
+		if _, ok := bidderInfos[bidder]; !ok {
+			errs = append(errs, fmt.Errorf("bidder_connection_warmup.bidders contains unknown bidder: %s", bidder))
+		}
+	}
+	if cfg.Connections <= 0 {
+		errs = append(errs, fmt.Errorf("bidder_connection_warmup.connections must be > 0. Got %d", cfg.Connections))
+	}
+	if cfg.KeepAliveIntervalSeconds < 0 {
+		errs = append(errs, fmt.Errorf("bidder_connection_warmup.keep_alive_interval_seconds must be >= 0. Got %d", cfg.KeepAliveIntervalSeconds))
+	}
+	if cfg.TimeoutMs <= 0 {
+		errs = append(errs, fmt.Errorf("bidder_connection_warmup.timeout_ms must be > 0. Got %d", cfg.TimeoutMs))
+	}
+	if cfg.KeepAliveIntervalSeconds > 0 && client.IdleConnTimeout > 0 && cfg.KeepAliveIntervalSeconds >= client.IdleConnTimeout {
+		logger.Warningf("bidder_connection_warmup.keep_alive_interval_seconds (%d) is not shorter than http_client.idle_connection_timeout_seconds (%d). Idle connections will be closed between keep-alive requests.", cfg.KeepAliveIntervalSeconds, client.IdleConnTimeout)
+	}
+	return errs
+}
+
+// InProcessBidders configures a mock bidder which answers the calls to the listed bidders without them leaving
+// the server. Every imp gets a bid at BidPrice. It's meant for load tests and benchmarks, where the latency
+// and availability of real bidders would make the results hard to reproduce, and must never be enabled in
+// production.
+type InProcessBidders struct {
+	Enabled  bool     `mapstructure:"enabled"`
+	Bidders  []string `mapstructure:"bidders"`
+	BidPrice float64  `mapstructure:"bid_price"`
+}
+
+func (cfg *InProcessBidders) validate(bidderInfos BidderInfos, errs []error) []error {
+	if !cfg.Enabled {
+		return errs
+	}
+	errCount := len(errs)
+	if len(cfg.Bidders) == 0 {
+		errs = append(errs, errors.New("in_process_bidders.bidders must not be empty"))
+	}
+	for _, bidder := range cfg.Bidders {
+		if _, ok := bidderInfos[bidder]; !ok {
+			errs = append(errs, fmt.Errorf("in_process_bidders.bidders contains unknown bidder: %s", bidder))
+		}
+	}
+	if cfg.BidPrice <= 0 {
+		errs = append(errs, fmt.Errorf("in_process_bidders.bid_price must be > 0. Got %g", cfg.BidPrice))
+	}
+	if len(errs) == errCount {
+		logger.Warningf("in_process_bidders is enabled. Calls to %s will be answered by a mock bidder.", strings.Join(cfg.Bidders, ", "))
+	}
+	return errs
+}
+
+// AuctionSimulation configures /openrtb2/simulate, which holds auctions with the bidder responses given
+// along with the request rather than calling the bidders, so publishers can try out config changes without
+// live demand.
+type AuctionSimulation struct {
+	Enabled bool `mapstructure:"enabled"`
+}
+
+// TestTraffic keeps requests with test=1 apart from production traffic, so QA traffic doesn't pollute the
+// production metrics and the stats partners are judged by. Test requests are only sent to the bidders which
+// accept test traffic. Their bids are never cached or given event URLs, and they aren't metered or counted
+// in the request metrics, bid landscape or auction quality statistics.
+type TestTraffic struct {
+	Enabled bool `mapstructure:"enabled"`
+	// SynthesizeBids answers for the bidders which don't accept test traffic with a bid on every imp at
+	// SynthesizedBidPrice, rather than leaving them out of the auction.
+	SynthesizeBids      bool    `mapstructure:"synthesize_bids"`
+	SynthesizedBidPrice float64 `mapstructure:"synthesized_bid_price"`
+}
+
+func (cfg *TestTraffic) validate(errs []error) []error {
+	if cfg.Enabled && cfg.SynthesizedBidPrice < 0 {
+		errs = append(errs, fmt.Errorf("test_traffic.synthesized_bid_price must be >= 0. Got %f", cfg.SynthesizedBidPrice))
+	}
+	return errs
+}
+
+// RequestValidationEndpoint configures /openrtb2/validate, which checks requests as /openrtb2/auction would and
+// responds with every error and warning found instead of holding an auction, so publishers can lint tag
+// changes in their CI.
+type RequestValidationEndpoint struct {
+	Enabled bool `mapstructure:"enabled"`
+}
+
+// OpenRTB3 configures /openrtb3/auction, which maps OpenRTB 3.0 requests and the AdCOM objects in them to
+// OpenRTB 2.x, holds the auction as /openrtb2/auction does, and maps the response back to OpenRTB 3.0.
+type OpenRTB3 struct {
+	Enabled bool `mapstructure:"enabled"`
+}
+
+// DeterministicIDs makes the request IDs, source.tid and imp.ext.tid values, bid IDs and cache keys the
+// server generates come from a generator seeded with Seed, rather than being random. Responses to the same
+// requests, sent one at a time, then have the same IDs on every run. It's meant for end-to-end tests which
+// compare responses with golden files, and for comparing replayed auctions, and must never be enabled in
+// production, since the IDs are only as unique as the seed.
+type DeterministicIDs struct {
+	Enabled bool  `mapstructure:"enabled"`
+	Seed    int64 `mapstructure:"seed"`
+}
+
+// ParsedStoredRequestCache configures the cache of stored requests in their merged and unmarshaled form.
+// Entries are keyed by content, so they never need to be invalidated when stored requests change.
+type ParsedStoredRequestCache struct {
+	Enabled    bool `mapstructure:"enabled"`
+	MaxEntries int  `mapstructure:"max_entries"`
+}
+
+func (cfg *ParsedStoredRequestCache) validate(errs []error) []error {
+	if cfg.Enabled && cfg.MaxEntries <= 0 {
+		errs = append(errs, fmt.Errorf("parsed_stored_request_cache.max_entries must be > 0. Got %d", cfg.MaxEntries))
+	}
+	return errs
+}
+
+// BidderResponseCache configures a short lived cache of bidder responses to anonymous requests, keyed by a
+// fingerprint of the imps, sizes, floors, bidder params, site or app and device type of the request. Bidders
+// answer requests without user IDs or consent with the same bids anyway, so reusing their responses for a few
+// seconds cuts the requests sent to them.
+type BidderResponseCache struct {
+	Enabled bool `mapstructure:"enabled"`
+	// TTLMS is how long a response is reused for, in milliseconds. It's at most a minute, since bids go stale.
+	TTLMS      int `mapstructure:"ttl_ms"`
+	MaxEntries int `mapstructure:"max_entries"`
+	// Bidders opts bidders into the cache. The responses of other bidders are never cached.
+	Bidders []string `mapstructure:"bidders"`
+}
+
+const maxBidderResponseCacheTTLMS = 60000
+
+func (cfg *BidderResponseCache) validate(errs []error) []error {
+	if !cfg.Enabled {
+		return errs
+	}
+	if cfg.TTLMS <= 0 || cfg.TTLMS > maxBidderResponseCacheTTLMS {
+		errs = append(errs, fmt.Errorf("bidder_response_cache.ttl_ms must be between 1 and %d. Got %d", maxBidderResponseCacheTTLMS, cfg.TTLMS))
+	}
+	if cfg.MaxEntries <= 0 {
+		errs = append(errs, fmt.Errorf("bidder_response_cache.max_entries must be > 0. Got %d", cfg.MaxEntries))
+	}
+	if len(cfg.Bidders) == 0 {
+		errs = append(errs, errors.New("bidder_response_cache.bidders must list at least one bidder"))
+	}
+	return errs
+}
+
+// LoadShedding configures how the auction endpoints protect the server when it's overloaded.
+type LoadShedding struct {
+	Memory      MemoryLoadShedding      `mapstructure:"memory"`
+	Concurrency ConcurrencyLoadShedding `mapstructure:"concurrency"`
+}
+
+// MemoryLoadShedding configures load shedding based on the memory used by the process. The thresholds are
+// fractions of the memory limit. Past the degrade threshold, optional work such as debug output and the optional
+// modules is skipped. Past the shed threshold, requests are rejected with a 503.
+type MemoryLoadShedding struct {
+	Enabled bool `mapstructure:"enabled"`
+	// LimitBytes is the memory limit. Use 0 for the limit set with GOMEMLIMIT.
+	LimitBytes        int64   `mapstructure:"limit_bytes"`
+	DegradeThreshold  float64 `mapstructure:"degrade_threshold"`
+	ShedThreshold     float64 `mapstructure:"shed_threshold"`
+	CheckIntervalMs   int     `mapstructure:"check_interval_ms"`
+	RetryAfterSeconds int     `mapstructure:"retry_after_seconds"`
+	// OptionalModules are the codes of the modules which aren't run past the degrade threshold.
+	OptionalModules []string `mapstructure:"optional_modules"`
+}
+
+// ConcurrencyLoadShedding configures an adaptive limit on the number of requests processed at once. Requests
+// over the limit are queued, and the limit is lowered while they wait longer than the target queue delay.
+type ConcurrencyLoadShedding struct {
+	Enabled            bool    `mapstructure:"enabled"`
+	InitialLimit       int     `mapstructure:"initial_limit"`
+	MinLimit           int     `mapstructure:"min_limit"`
+	MaxLimit           int     `mapstructure:"max_limit"`
+	BackoffRatio       float64 `mapstructure:"backoff_ratio"`
+	TargetQueueDelayMs int     `mapstructure:"target_queue_delay_ms"`
+	MaxQueueWaitMs     int     `mapstructure:"max_queue_wait_ms"`
+	MaxQueueSize       int     `mapstructure:"max_queue_size"`
+	RetryAfterSeconds  int     `mapstructure:"retry_after_seconds"`
+}
+
+func (cfg *LoadShedding) validate(errs []error) []error {
+	errs = cfg.Memory.validate(errs)
+	return cfg.Concurrency.validate(errs)
+}
+
+func (cfg *ConcurrencyLoadShedding) validate(errs []error) []error {
+	if !cfg.Enabled {
+		return errs
+	}
+	if cfg.MinLimit <= 0 {
+		errs = append(errs, fmt.Errorf("load_shedding.concurrency.min_limit must be > 0. Got %d", cfg.MinLimit))
+	}
+	if cfg.MaxLimit < cfg.MinLimit {
+		errs = append(errs, fmt.Errorf("load_shedding.concurrency.max_limit must be >= load_shedding.concurrency.min_limit. Got %d and %d", cfg.MaxLimit, cfg.MinLimit))
+	}
+	if cfg.InitialLimit < cfg.MinLimit || cfg.InitialLimit > cfg.MaxLimit {
+		errs = append(errs, fmt.Errorf("load_shedding.concurrency.initial_limit must be between min_limit and max_limit. Got %d", cfg.InitialLimit))
+	}
+	if cfg.BackoffRatio <= 0 || cfg.BackoffRatio >= 1 {
+		errs = append(errs, fmt.Errorf("load_shedding.concurrency.backoff_ratio must be in the range (0, 1). Got %g", cfg.BackoffRatio))
+	}
+	if cfg.TargetQueueDelayMs <= 0 {
+		errs = append(errs, fmt.Errorf("load_shedding.concurrency.target_queue_delay_ms must be > 0. Got %d", cfg.TargetQueueDelayMs))
+	}
+	if cfg.MaxQueueWaitMs < cfg.TargetQueueDelayMs {
+		errs = append(errs, fmt.Errorf("load_shedding.concurrency.max_queue_wait_ms must be >= load_shedding.concurrency.target_queue_delay_ms. Got %d and %d", cfg.MaxQueueWaitMs, cfg.TargetQueueDelayMs))
+	}
+	if cfg.MaxQueueSize < 0 {
+		errs = append(errs, fmt.Errorf("load_shedding.concurrency.max_queue_size must be >= 0. Got %d", cfg.MaxQueueSize))
+	}
+	if cfg.RetryAfterSeconds < 0 {
+		errs = append(errs, fmt.Errorf("load_shedding.concurrency.retry_after_seconds must be >= 0. Got %d", cfg.RetryAfterSeconds))
+	}
+	return errs
+}
+
+func (cfg *MemoryLoadShedding) validate(errs []error) []error {
+	if !cfg.Enabled {
+		return errs
+	}
+	if cfg.LimitBytes < 0 {
+		errs = append(errs, fmt.Errorf("load_shedding.memory.limit_bytes must be >= 0. Got %d", cfg.LimitBytes))
+	}
+	if cfg.LimitBytes == 0 && debug.SetMemoryLimit(-1) == math.MaxInt64 {
+		errs = append(errs, errors.New("load_shedding.memory.limit_bytes must be set when GOMEMLIMIT is not"))
+	}
+	if cfg.DegradeThreshold <= 0 || cfg.DegradeThreshold > 1 {
+		errs = append(errs, fmt.Errorf("load_shedding.memory.degrade_threshold must be in the range (0, 1]. Got %g", cfg.DegradeThreshold))
+	}
+	if cfg.ShedThreshold <= 0 || cfg.ShedThreshold > 1 {
+		errs = append(errs, fmt.Errorf("load_shedding.memory.shed_threshold must be in the range (0, 1]. Got %g", cfg.ShedThreshold))
+	}
+	if cfg.DegradeThreshold > cfg.ShedThreshold {
+		errs = append(errs, fmt.Errorf("load_shedding.memory.degrade_threshold must not be greater than load_shedding.memory.shed_threshold. Got %g and %g", cfg.DegradeThreshold, cfg.ShedThreshold))
+	}
+	if cfg.CheckIntervalMs <= 0 {
+		errs = append(errs, fmt.Errorf("load_shedding.memory.check_interval_ms must be > 0. Got %d", cfg.CheckIntervalMs))
+	}
+	if cfg.RetryAfterSeconds < 0 {
+		errs = append(errs, fmt.Errorf("load_shedding.memory.retry_after_seconds must be >= 0. Got %d", cfg.RetryAfterSeconds))
+	}
+	return errs
+}
+
+// LatencyBudget configures how an auction's tmax is divided between its stages. Each value is a share of tmax.
+// StoredRequests caps the time spent fetching the stored requests and account. Hooks and CacheWrite are held
+// back from the bidders for the hooks which run on their responses and for writing bids to Prebid Cache.
+// The bidders have whatever is left.
+type LatencyBudget struct {
+	Enabled        bool    `mapstructure:"enabled"`
+	StoredRequests float64 `mapstructure:"stored_requests"`
+	Hooks          float64 `mapstructure:"hooks"`
+	CacheWrite     float64 `mapstructure:"cache_write"`
+}
+
+func (cfg *LatencyBudget) validate(errs []error) []error {
+	if !cfg.Enabled {
+		return errs
+	}
+	if cfg.StoredRequests <= 0 || cfg.StoredRequests >= 1 {
+		errs = append(errs, fmt.Errorf("latency_budget.stored_requests must be in the range (0, 1). Got %g", cfg.StoredRequests))
+	}
+	if cfg.Hooks < 0 || cfg.Hooks >= 1 {
+		errs = append(errs, fmt.Errorf("latency_budget.hooks must be in the range [0, 1). Got %g", cfg.Hooks))
+	}
+	if cfg.CacheWrite < 0 || cfg.CacheWrite >= 1 {
+		errs = append(errs, fmt.Errorf("latency_budget.cache_write must be in the range [0, 1). Got %g", cfg.CacheWrite))
+	}
+	if cfg.StoredRequests+cfg.Hooks+cfg.CacheWrite >= 1 {
+		errs = append(errs, fmt.Errorf("latency_budget.stored_requests, latency_budget.hooks and latency_budget.cache_write must leave some of tmax to the bidders. Got %g in total", cfg.StoredRequests+cfg.Hooks+cfg.CacheWrite))
+	}
+	return errs
+}
+
+// AuctionRecording configures the capture of sampled requests to /openrtb2/auction, along with the account,
+// stored data and bidder calls they used, and the replay of captured auctions. Replay may be enabled on its
+// own, to replay auctions captured by other hosts.
+type AuctionRecording struct {
+	Enabled    bool    `mapstructure:"enabled"`
+	SampleRate float64 `mapstructure:"sample_rate"`
+	// Accounts limits recording to the listed accounts. All accounts are recorded if it's empty.
+	Accounts []string `mapstructure:"accounts"`
+	// QueueSize is the number of recordings which may wait to be written before new ones are dropped
+	QueueSize     int                  `mapstructure:"queue_size"`
+	Sink          AuctionRecordingSink `mapstructure:"sink"`
+	ReplayEnabled bool                 `mapstructure:"replay_enabled"`
+}
+
+// AuctionRecordingSink configures where recordings are written
+type AuctionRecordingSink struct {
+	Type string                   `mapstructure:"type"`
+	File AuctionRecordingFileSink `mapstructure:"file"`
+}
+
+// AuctionRecordingFileSink appends recordings to a file, one per line
+type AuctionRecordingFileSink struct {
+	Path string `mapstructure:"path"`
+}
+
+// AuctionRecordingSinkFile is the only supported sink type
+const AuctionRecordingSinkFile = "file"
+
+func (cfg *AuctionRecording) validate(errs []error) []error {
+	if !cfg.Enabled {
+		return errs
+	}
+	if cfg.SampleRate <= 0 || cfg.SampleRate > 1 {
+		errs = append(errs, fmt.Errorf("auction_recording.sample_rate must be in the range (0, 1]. Got %g", cfg.SampleRate))
+	}
+	if cfg.QueueSize <= 0 {
+		errs = append(errs, fmt.Errorf("auction_recording.queue_size must be > 0. Got %d", cfg.QueueSize))
+	}
+	switch cfg.Sink.Type {
+	case AuctionRecordingSinkFile:
+		if cfg.Sink.File.Path == "" {
+			errs = append(errs, errors.New("auction_recording.sink.file.path must be set when auction_recording.sink.type is file"))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("auction_recording.sink.type must be %s. Got %s", AuctionRecordingSinkFile, cfg.Sink.Type))
+	}
+	return errs
+}
+
+// TrafficShadowing configures copies of sampled requests to /openrtb2/auction, which are sent to the shadow
+// host at URL after the personal data in them has been scrubbed. The copies are sent by a pool of Workers,
+// in the background, and the shadow host's responses are ignored.
+type TrafficShadowing struct {
+	Enabled    bool    `mapstructure:"enabled"`
+	URL        string  `mapstructure:"url"`
+	SampleRate float64 `mapstructure:"sample_rate"`
+	TimeoutMs  int     `mapstructure:"timeout_ms"`
+	Workers    int     `mapstructure:"workers"`
+	// QueueSize is the number of copies which may wait for a worker before new ones are dropped
+	QueueSize int `mapstructure:"queue_size"`
+}
+
+func (cfg *TrafficShadowing) validate(errs []error) []error {
+	if !cfg.Enabled {
+		return errs
+	}
+	if shadowURL, err := url.Parse(cfg.URL); err != nil || (shadowURL.Scheme != "http" && shadowURL.Scheme != "https") || shadowURL.Host == "" {
+		errs = append(errs, fmt.Errorf("traffic_shadowing.url must be an http or https URL. Got %s", cfg.URL))
+	}
+	if cfg.SampleRate <= 0 || cfg.SampleRate > 1 {
+		errs = append(errs, fmt.Errorf("traffic_shadowing.sample_rate must be in the range (0, 1]. Got %g", cfg.SampleRate))
+	}
+	if cfg.TimeoutMs <= 0 {
+		errs = append(errs, fmt.Errorf("traffic_shadowing.timeout_ms must be > 0. Got %d", cfg.TimeoutMs))
+	}
+	if cfg.Workers <= 0 {
+		errs = append(errs, fmt.Errorf("traffic_shadowing.workers must be > 0. Got %d", cfg.Workers))
+	}
+	if cfg.QueueSize < 0 {
+		errs = append(errs, fmt.Errorf("traffic_shadowing.queue_size must be >= 0. Got %d", cfg.QueueSize))
+	}
+	return errs
+}
+
+// Webhooks configures the endpoints which are sent an event when accounts or stored data change. Events are
+// sent in the background, in the order they happened, and are signed with the endpoint's secret.
+type Webhooks struct {
+	Enabled   bool              `mapstructure:"enabled"`
+	Endpoints []WebhookEndpoint `mapstructure:"endpoints"`
+	TimeoutMs int               `mapstructure:"timeout_ms"`
+	// MaxAttempts is how many times an event is sent to an endpoint before it's given up on
+	MaxAttempts int `mapstructure:"max_attempts"`
+	// RetryDelayMs is how long to wait before the second attempt. The wait doubles with each attempt after it.
+	RetryDelayMs int `mapstructure:"retry_delay_ms"`
+	// QueueSize is the number of events which may wait to be sent to each endpoint before new ones are dropped
+	QueueSize int `mapstructure:"queue_size"`
+}
+
+type WebhookEndpoint struct {
+	URL string `mapstructure:"url"`
+	// Secret is the key the HMAC-SHA256 signatures of the events are made with
+	Secret string `mapstructure:"secret"`
+	// Events are the types of events sent to the endpoint. Every type is sent if none are given.
+	Events []string `mapstructure:"events"`
+}
+
+// WebhookEventTypes returns the types of events sent when stored data changes, which are named by the
+// section of the stored data and whether it was saved or invalidated.
+func WebhookEventTypes() []string {
+	var types []string
+	for _, dataType := range []DataType{RequestDataType, AMPRequestDataType, VideoDataType, CategoryDataType, AccountDataType, ResponseDataType} {
+		types = append(types, dataType.Section()+".saved", dataType.Section()+".invalidated")
+	}
+	return types
+}
+
+func (cfg *Webhooks) validate(errs []error) []error {
+	if !cfg.Enabled {
+		return errs
+	}
+	if len(cfg.Endpoints) == 0 {
+		errs = append(errs, errors.New("webhooks.endpoints must have at least one endpoint"))
+	}
+	eventTypes := make(map[string]bool)
+	for _, eventType := range WebhookEventTypes() {
+		eventTypes[eventType] = true
+	}
+	for i, endpoint := range cfg.Endpoints {
+		if endpointURL, err := url.Parse(endpoint.URL); err != nil || (endpointURL.Scheme != "http" && endpointURL.Scheme != "https") || endpointURL.Host == "" {
+			errs = append(errs, fmt.Errorf("webhooks.endpoints[%d].url must be an http or https URL. Got %s", i, endpoint.URL))
+		}
+		if endpoint.Secret == "" {
+			errs = append(errs, fmt.Errorf("webhooks.endpoints[%d].secret is required", i))
+		}
+		for _, eventType := range endpoint.Events {
+			if !eventTypes[eventType] {
+				errs = append(errs, fmt.Errorf("webhooks.endpoints[%d].events has an unknown event type %s", i, eventType))
+			}
+		}
+	}
+	if cfg.TimeoutMs <= 0 {
+		errs = append(errs, fmt.Errorf("webhooks.timeout_ms must be > 0. Got %d", cfg.TimeoutMs))
+	}
+	if cfg.MaxAttempts <= 0 {
+		errs = append(errs, fmt.Errorf("webhooks.max_attempts must be > 0. Got %d", cfg.MaxAttempts))
+	}
+	if cfg.RetryDelayMs < 0 {
+		errs = append(errs, fmt.Errorf("webhooks.retry_delay_ms must be >= 0. Got %d", cfg.RetryDelayMs))
+	}
+	if cfg.QueueSize < 0 {
+		errs = append(errs, fmt.Errorf("webhooks.queue_size must be >= 0. Got %d", cfg.QueueSize))
+	}
+	return errs
+}
+
+// AuctionEventWebhooks configures how the events received by /event are sent to the webhooks of the
+// accounts which have one. Events are sent in batches, in the background, and are signed with the secret
+// of the account's webhook.
+type AuctionEventWebhooks struct {
+	Enabled   bool `mapstructure:"enabled"`
+	TimeoutMs int  `mapstructure:"timeout_ms"`
+	// MaxAttempts is how many times a batch is sent before it's given up on
+	MaxAttempts int `mapstructure:"max_attempts"`
+	// RetryDelayMs is how long to wait before the second attempt. The wait doubles with each attempt after it.
+	RetryDelayMs int `mapstructure:"retry_delay_ms"`
+	// BatchSize is the most events sent in one request
+	BatchSize int `mapstructure:"batch_size"`
+	// BatchIntervalMs is the longest an event waits for its batch to fill up before the batch is sent anyway
+	BatchIntervalMs int `mapstructure:"batch_interval_ms"`
+	// QueueSize is the number of events which may wait to be batched before new ones are dropped
+	QueueSize int `mapstructure:"queue_size"`
+	// Workers is the number of batches sent at the same time
+	Workers int `mapstructure:"workers"`
+}
+
+func (cfg *AuctionEventWebhooks) validate(errs []error) []error {
+	if !cfg.Enabled {
+		return errs
+	}
+	if cfg.TimeoutMs <= 0 {
+		errs = append(errs, fmt.Errorf("auction_event_webhooks.timeout_ms must be > 0. Got %d", cfg.TimeoutMs))
+	}
+	if cfg.MaxAttempts <= 0 {
+		errs = append(errs, fmt.Errorf("auction_event_webhooks.max_attempts must be > 0. Got %d", cfg.MaxAttempts))
+	}
+	if cfg.RetryDelayMs < 0 {
+		errs = append(errs, fmt.Errorf("auction_event_webhooks.retry_delay_ms must be >= 0. Got %d", cfg.RetryDelayMs))
+	}
+	if cfg.BatchSize <= 0 {
+		errs = append(errs, fmt.Errorf("auction_event_webhooks.batch_size must be > 0. Got %d", cfg.BatchSize))
+	}
+	if cfg.BatchIntervalMs <= 0 {
+		errs = append(errs, fmt.Errorf("auction_event_webhooks.batch_interval_ms must be > 0. Got %d", cfg.BatchIntervalMs))
+	}
+	if cfg.QueueSize < 0 {
+		errs = append(errs, fmt.Errorf("auction_event_webhooks.queue_size must be >= 0. Got %d", cfg.QueueSize))
+	}
+	if cfg.Workers <= 0 {
+		errs = append(errs, fmt.Errorf("auction_event_webhooks.workers must be > 0. Got %d", cfg.Workers))
+	}
+	return errs
+}
+
+// BidLandscape configures the statistics of recent bids by bidder, account and size, which yield teams can
+// query on the admin server without waiting for the analytics pipeline.
+type BidLandscape struct {
+	Enabled bool `mapstructure:"enabled"`
+	// WindowMinutes is how far back the statistics go. They're kept by the minute, so the window rolls
+	// forward a minute at a time.
+	WindowMinutes int `mapstructure:"window_minutes"`
+	// PriceBuckets are the upper bounds of the buckets of the price distribution, in USD CPM
+	PriceBuckets []float64 `mapstructure:"price_buckets"`
+	// MaxKeys bounds the number of bidder, account and size combinations counted each minute, so that the
+	// memory used stays bounded however many sizes and accounts are seen
+	MaxKeys int `mapstructure:"max_keys"`
+	// Tokens are the bearer tokens which may query the statistics
+	Tokens []string `mapstructure:"tokens"`
+}
+
+// Metering configures the counting of each account's billable usage: its auctions, the imps in them and the
+// requests made to bidders for them. Each instance exports the usage it counted since its last export to the
+// sink, so the sink adds up the reports of every instance.
+type Metering struct {
+	Enabled bool `mapstructure:"enabled"`
+	// ExportIntervalSeconds is how often the usage is exported
+	ExportIntervalSeconds int `mapstructure:"export_interval_seconds"`
+	// SinkURL is where the usage reports are POSTed, as JSON
+	SinkURL string `mapstructure:"sink_url"`
+	// SinkTimeoutMS bounds each export. Reports which fail are sent again at the next export.
+	SinkTimeoutMS int `mapstructure:"sink_timeout_ms"`
+}
+
+func (cfg *Metering) validate(errs []error) []error {
+	if !cfg.Enabled {
+		return errs
+	}
+	if cfg.ExportIntervalSeconds <= 0 {
+		errs = append(errs, fmt.Errorf("metering.export_interval_seconds must be > 0. Got %d", cfg.ExportIntervalSeconds))
+	}
+	if u, err := url.Parse(cfg.SinkURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		errs = append(errs, fmt.Errorf("metering.sink_url must be an http or https URL. Got %s", cfg.SinkURL))
+	}
+	if cfg.SinkTimeoutMS <= 0 {
+		errs = append(errs, fmt.Errorf("metering.sink_timeout_ms must be > 0. Got %d", cfg.SinkTimeoutMS))
+	}
+	return errs
+}
+
+func (cfg *BidLandscape) validate(errs []error) []error {
+	if !cfg.Enabled {
+		return errs
+	}
+	if cfg.WindowMinutes <= 0 {
+		errs = append(errs, fmt.Errorf("bid_landscape.window_minutes must be > 0. Got %d", cfg.WindowMinutes))
+	}
+	for i, bound := range cfg.PriceBuckets {
+		if bound <= 0 || (i > 0 && bound <= cfg.PriceBuckets[i-1]) {
+			errs = append(errs, errors.New("bid_landscape.price_buckets must be positive and in increasing order"))
+			break
+		}
+	}
+	if cfg.MaxKeys <= 0 {
+		errs = append(errs, fmt.Errorf("bid_landscape.max_keys must be > 0. Got %d", cfg.MaxKeys))
+	}
+	if len(cfg.Tokens) == 0 {
+		errs = append(errs, errors.New("bid_landscape.tokens must have at least one token"))
+	}
+	for i, token := range cfg.Tokens {
+		if token == "" {
+			errs = append(errs, fmt.Errorf("bid_landscape.tokens[%d] must not be empty", i))
+		}
+	}
+	return errs
+}
+
+// AuctionQuality configures the statistics of the health of each account's recent auctions: how often its
+// bidders time out, which validation problems its requests have, and how many bidders and bids were blocked
+// by privacy policies and floors. Publishers fetch them with one of their account's API keys.
+type AuctionQuality struct {
+	Enabled bool `mapstructure:"enabled"`
+	// WindowMinutes is how far back the statistics go. They're kept by the minute, so the window rolls
+	// forward a minute at a time.
+	WindowMinutes int `mapstructure:"window_minutes"`
+	// MaxAccounts bounds the number of accounts counted each minute, so that the memory used stays bounded
+	MaxAccounts int `mapstructure:"max_accounts"`
+}
+
+func (cfg *AuctionQuality) validate(errs []error) []error {
+	if !cfg.Enabled {
+		return errs
+	}
+	if cfg.WindowMinutes <= 0 {
+		errs = append(errs, fmt.Errorf("auction_quality.window_minutes must be > 0. Got %d", cfg.WindowMinutes))
+	}
+	if cfg.MaxAccounts <= 0 {
+		errs = append(errs, fmt.Errorf("auction_quality.max_accounts must be > 0. Got %d", cfg.MaxAccounts))
+	}
+	return errs
+}
+
+// ResponseOverrides configures the overrides which pin a bidder's responses for an account to a stored bid
+// response, so that a misbehaving bidder can be isolated, or one of its responses replayed, in production.
+// Overrides are set on the admin server, kept in memory, and always expire.
+type ResponseOverrides struct {
+	Enabled bool `mapstructure:"enabled"`
+	// MaxTTLSeconds is the longest an override may last, so that one which is forgotten doesn't pin a
+	// bidder for good
+	MaxTTLSeconds int `mapstructure:"max_ttl_seconds"`
+	// MaxOverrides bounds the number of overrides in effect at once
+	MaxOverrides int `mapstructure:"max_overrides"`
+	// Tokens are the bearer tokens which may manage the overrides
+	Tokens []string `mapstructure:"tokens"`
+}
+
+func (cfg *ResponseOverrides) validate(errs []error) []error {
+	if !cfg.Enabled {
+		return errs
+	}
+	if cfg.MaxTTLSeconds <= 0 {
+		errs = append(errs, fmt.Errorf("response_overrides.max_ttl_seconds must be > 0. Got %d", cfg.MaxTTLSeconds))
+	}
+	if cfg.MaxOverrides <= 0 {
+		errs = append(errs, fmt.Errorf("response_overrides.max_overrides must be > 0. Got %d", cfg.MaxOverrides))
+	}
+	if len(cfg.Tokens) == 0 {
+		errs = append(errs, errors.New("response_overrides.tokens must have at least one token"))
+	}
+	for i, token := range cfg.Tokens {
+		if token == "" {
+			errs = append(errs, fmt.Errorf("response_overrides.tokens[%d] must not be empty", i))
+		}
+	}
+	return errs
+}
+
+// StoredRequestVersions configures the history of the stored requests and imps served to auctions, and the
+// pins which serve an account one of their previous versions, so that a broken stored request edit can be
+// rolled back for an account at once. The history and pins are kept in memory, and pins are set on the admin
+// server.
+type StoredRequestVersions struct {
+	Enabled bool `mapstructure:"enabled"`
+	// MaxVersions is the number of versions kept of each stored request and imp, the current one included
+	MaxVersions int `mapstructure:"max_versions"`
+	// MaxPins bounds the number of pins in effect at once
+	MaxPins int `mapstructure:"max_pins"`
+	// Tokens are the bearer tokens which may manage the pins
+	Tokens []string `mapstructure:"tokens"`
+}
+
+func (cfg *StoredRequestVersions) validate(errs []error) []error {
+	if !cfg.Enabled {
+		return errs
+	}
+	if cfg.MaxVersions < 2 {
+		errs = append(errs, fmt.Errorf("stored_request_versions.max_versions must be >= 2. Got %d", cfg.MaxVersions))
+	}
+	if cfg.MaxPins <= 0 {
+		errs = append(errs, fmt.Errorf("stored_request_versions.max_pins must be > 0. Got %d", cfg.MaxPins))
+	}
+	if len(cfg.Tokens) == 0 {
+		errs = append(errs, errors.New("stored_request_versions.tokens must have at least one token"))
+	}
+	for i, token := range cfg.Tokens {
+		if token == "" {
+			errs = append(errs, fmt.Errorf("stored_request_versions.tokens[%d] must not be empty", i))
+		}
+	}
+	return errs
+}
+
+// BidderMaintenance configures the windows in which bidders are left out of every account's auctions, and
+// the kill switches which leave them out at once, so that a partner's incident or planned downtime doesn't
+// need a config change to be shipped.
+type BidderMaintenance struct {
+	Windows []BidderMaintenanceWindow `mapstructure:"windows"`
+	// KillSwitches are turned on and off on the admin server
+	KillSwitches BidderKillSwitches `mapstructure:"kill_switches"`
+}
+
+// BidderMaintenanceWindow is a time range in which a bidder is left out of auctions.
+type BidderMaintenanceWindow struct {
+	Bidder string `mapstructure:"bidder" json:"bidder"`
+	// Start and End are RFC 3339 times. The window includes its start, but not its end.
+	Start string `mapstructure:"start" json:"start"`
+	End   string `mapstructure:"end" json:"end"`
+}
+
+// Active reports whether the window is in effect at the time. A window whose times can't be parsed never is.
+func (w BidderMaintenanceWindow) Active(now time.Time) bool {
+	start, end, err := w.parse()
+	return err == nil && !now.Before(start) && now.Before(end)
+}
+
+func (w BidderMaintenanceWindow) parse() (start time.Time, end time.Time, err error) {
+	if start, err = time.Parse(time.RFC3339, w.Start); err != nil {
+		return
+	}
+	end, err = time.Parse(time.RFC3339, w.End)
+	return
+}
+
+// BidderKillSwitches configures the admin endpoint which turns bidders' kill switches on and off. Kill
+// switches are kept in memory, so they're turned off by a restart.
+type BidderKillSwitches struct {
+	Enabled bool `mapstructure:"enabled"`
+	// Tokens are the bearer tokens which may turn the kill switches on and off
+	Tokens []string `mapstructure:"tokens"`
+}
+
+func (cfg *BidderMaintenance) validate(errs []error) []error {
+	errs = validateBidderMaintenanceWindows("bidder_maintenance.windows", cfg.Windows, errs)
+	if !cfg.KillSwitches.Enabled {
+		return errs
+	}
+	if len(cfg.KillSwitches.Tokens) == 0 {
+		errs = append(errs, errors.New("bidder_maintenance.kill_switches.tokens must have at least one token"))
+	}
+	for i, token := range cfg.KillSwitches.Tokens {
+		if token == "" {
+			errs = append(errs, fmt.Errorf("bidder_maintenance.kill_switches.tokens[%d] must not be empty", i))
+		}
+	}
+	return errs
+}
+
+func validateBidderMaintenanceWindows(field string, windows []BidderMaintenanceWindow, errs []error) []error {
+	for i, window := range windows {
+		if window.Bidder == "" {
+			errs = append(errs, fmt.Errorf("%s[%d].bidder is required", field, i))
+		}
+		start, end, err := window.parse()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s[%d] must have RFC 3339 start and end times: %v", field, i, err))
+		} else if !end.After(start) {
+			errs = append(errs, fmt.Errorf("%s[%d].end must be after its start. Got %s", field, i, window.End))
+		}
+	}
+	return errs
+}
+
+// AdaptiveBidderTmax configures how each bidder's response times are tracked, and how much time bidders are
+// given in the auctions of accounts with adaptive_bidder_tmax enabled. A bidder's tmax is cut to a percentile
+// of its recent response times, plus headroom, if that's less than the auction leaves it, so that the
+// auction isn't held up waiting for the tail of a slow bidder's responses.
+type AdaptiveBidderTmax struct {
+	// Enabled turns the tracking of response times on. Accounts must also opt in for tmax to be adapted.
+	Enabled bool `mapstructure:"enabled"`
+	// Percentile of the recent response times a bidder's tmax is cut to, above 0 and below 100
+	Percentile float64 `mapstructure:"percentile"`
+	// Samples is the number of each bidder's recent response times which are kept
+	Samples int `mapstructure:"samples"`
+	// MinSamples is the number of response times a bidder must have before its tmax is adapted. The
+	// percentile is updated each time this many more have been observed.
+	MinSamples int `mapstructure:"min_samples"`
+	// HeadroomPercent is added to the percentile, so that a bidder has a little more time than it usually takes
+	HeadroomPercent int `mapstructure:"headroom_percent"`
+	// MinTmaxMS is the least tmax a bidder is given
+	MinTmaxMS int64 `mapstructure:"min_tmax_ms"`
+	// MeasurePercent is the share of requests, from 0 to 100, for which bidders keep the time the auction
+	// leaves them, so that their response times keep being measured without being cut short
+	MeasurePercent int `mapstructure:"measure_percent"`
+}
+
+func (cfg *AdaptiveBidderTmax) validate(errs []error) []error {
+	if !cfg.Enabled {
+		return errs
+	}
+	if cfg.Percentile <= 0 || cfg.Percentile >= 100 {
+		errs = append(errs, fmt.Errorf("adaptive_bidder_tmax.percentile must be in the range (0, 100). Got %f", cfg.Percentile))
+	}
+	if cfg.Samples <= 0 {
+		errs = append(errs, fmt.Errorf("adaptive_bidder_tmax.samples must be > 0. Got %d", cfg.Samples))
+	}
+	if cfg.MinSamples <= 0 || cfg.MinSamples > cfg.Samples {
+		errs = append(errs, fmt.Errorf("adaptive_bidder_tmax.min_samples must be > 0 and no more than samples. Got %d", cfg.MinSamples))
+	}
+	if cfg.HeadroomPercent < 0 {
+		errs = append(errs, fmt.Errorf("adaptive_bidder_tmax.headroom_percent must be >= 0. Got %d", cfg.HeadroomPercent))
+	}
+	if cfg.MinTmaxMS <= 0 {
+		errs = append(errs, fmt.Errorf("adaptive_bidder_tmax.min_tmax_ms must be > 0. Got %d", cfg.MinTmaxMS))
+	}
+	if cfg.MeasurePercent < 0 || cfg.MeasurePercent > 100 {
+		errs = append(errs, fmt.Errorf("adaptive_bidder_tmax.measure_percent must be between 0 and 100. Got %d", cfg.MeasurePercent))
+	}
+	return errs
+}
+
+// FaultInjection configures the faults injected into the calls to each kind of dependency.
+type FaultInjection struct {
+	Enabled        bool   `mapstructure:"enabled"`
+	Bidders        Faults `mapstructure:"bidders"`
+	StoredRequests Faults `mapstructure:"stored_requests"`
+	Cache          Faults `mapstructure:"cache"`
+}
+
+// Faults configures the share of calls which are delayed, fail, or get a malformed response. A call may
+// be delayed and then fail or get a malformed response.
+type Faults struct {
+	LatencyRate   float64 `mapstructure:"latency_rate"`
+	LatencyMs     int     `mapstructure:"latency_ms"`
+	ErrorRate     float64 `mapstructure:"error_rate"`
+	MalformedRate float64 `mapstructure:"malformed_rate"`
+}
+
+// IsEmpty returns true if no faults are injected.
+func (cfg Faults) IsEmpty() bool {
+	return (cfg.LatencyRate <= 0 || cfg.LatencyMs <= 0) && cfg.ErrorRate <= 0 && cfg.MalformedRate <= 0
+}
+
+func (cfg *FaultInjection) validate(errs []error) []error {
+	if !cfg.Enabled {
+		return errs
+	}
+	errs = cfg.Bidders.validate("fault_injection.bidders", errs)
+	errs = cfg.StoredRequests.validate("fault_injection.stored_requests", errs)
+	errs = cfg.Cache.validate("fault_injection.cache", errs)
+	return errs
+}
+
+func (cfg *Faults) validate(path string, errs []error) []error {
+	if cfg.LatencyRate < 0 || cfg.LatencyRate > 1 {
+		errs = append(errs, fmt.Errorf("%s.latency_rate must be in the range [0, 1]. Got %g", path, cfg.LatencyRate))
+	}
+	if cfg.LatencyMs < 0 {
+		errs = append(errs, fmt.Errorf("%s.latency_ms must be >= 0. Got %d", path, cfg.LatencyMs))
+	}
+	if cfg.ErrorRate < 0 || cfg.ErrorRate > 1 {
+		errs = append(errs, fmt.Errorf("%s.error_rate must be in the range [0, 1]. Got %g", path, cfg.ErrorRate))
+	}
+	if cfg.MalformedRate < 0 || cfg.MalformedRate > 1 {
+		errs = append(errs, fmt.Errorf("%s.malformed_rate must be in the range [0, 1]. Got %g", path, cfg.MalformedRate))
+	}
+	if cfg.ErrorRate+cfg.MalformedRate > 1 {
+		errs = append(errs, fmt.Errorf("%s.error_rate and %s.malformed_rate must add up to at most 1. Got %g", path, path, cfg.ErrorRate+cfg.MalformedRate))
+	}
+	return errs
+}
+
+// Logging configures the server's logs. The levels may be changed at runtime through the admin endpoint
+// /logging/levels.
+type Logging struct {
+	// Format is either json or glog. It's json if empty.
+	Format string `mapstructure:"format"`
+	// Level is the default level. It's info if empty.
+	Level string `mapstructure:"level"`
+	// ComponentLevels overrides the level for packages, by their path within the module, such as exchange
+	ComponentLevels map[string]string `mapstructure:"component_levels"`
+	// AccountLevels overrides the level for the requests of accounts, by account ID
+	AccountLevels map[string]string `mapstructure:"account_levels"`
+	// Sampling is the share of the lines of high volume classes, such as bidder_errors, which are logged
+	Sampling map[string]float64 `mapstructure:"sampling"`
+	// RedactFields are the names of the JSON members and query parameters whose values are redacted
+	RedactFields []string `mapstructure:"redact_fields"`
+	// RedactPatterns are regular expressions whose matches are redacted
+	RedactPatterns []string `mapstructure:"redact_patterns"`
+}
+
+// Levels returns the levels the logs are written at.
+func (cfg *Logging) Levels() logger.Levels {
+	return logger.Levels{Level: cfg.Level, Components: cfg.ComponentLevels, Accounts: cfg.AccountLevels}
+}
+
+// Policy returns the rules applied to lines before they're written.
+func (cfg *Logging) Policy() logger.Policy {
+	policy := logger.Policy{RedactFields: cfg.RedactFields, RedactPatterns: cfg.RedactPatterns}
+	if len(cfg.Sampling) > 0 {
+		policy.Sampling = make(map[logger.Class]float64, len(cfg.Sampling))
+		for class, rate := range cfg.Sampling {
+			policy.Sampling[logger.Class(class)] = rate
+		}
+	}
+	return policy
+}
+
+func (cfg *Logging) validate(errs []error) []error {
+	if cfg.Format != "" && cfg.Format != logger.FormatJSON && cfg.Format != logger.FormatGlog {
+		errs = append(errs, fmt.Errorf("logging.format must be %s or %s. Got %s", logger.FormatJSON, logger.FormatGlog, cfg.Format))
+	}
+	if _, err := logger.ParseLevel(cfg.Level); cfg.Level != "" && err != nil {
+		errs = append(errs, fmt.Errorf("logging.level: %v", err))
+	}
+	for component, level := range cfg.ComponentLevels {
+		if _, err := logger.ParseLevel(level); err != nil {
+			errs = append(errs, fmt.Errorf("logging.component_levels.%s: %v", component, err))
+		}
+	}
+	for account, level := range cfg.AccountLevels {
+		if _, err := logger.ParseLevel(level); err != nil {
+			errs = append(errs, fmt.Errorf("logging.account_levels.%s: %v", account, err))
+		}
+	}
+	for _, err := range logger.ValidatePolicy(cfg.Policy()) {
+		errs = append(errs, fmt.Errorf("logging: %v", err))
+	}
+	return errs
+}
+
+// JSON selects the engine requests and responses are unmarshaled and marshaled with.
+type JSON struct {
+	// Engine is jsoniter, stdlib or sonic. It's jsoniter if empty. sonic is only built on amd64, with the
+	// versions of Go it supports, and jsoniter is used in its place elsewhere.
+	Engine string `mapstructure:"engine"`
+}
+
+func (cfg *JSON) validate(errs []error) []error {
+	if cfg.Engine != "" && !jsonutil.IsEngine(cfg.Engine) {
+		errs = append(errs, fmt.Errorf("json.engine must be %s, %s or %s. Got %s", jsonutil.EngineJsoniter, jsonutil.EngineStdlib, jsonutil.EngineSonic, cfg.Engine))
+	}
+	return errs
+}
+
+// BidderTimeoutNotifications configures the timeout notifications sent to bidders whose requests time out,
+// both those adapters make themselves and those to the timeoutNotificationUrl of a bidder's info.
+type BidderTimeoutNotifications struct {
+	// Enabled sends notifications to the timeoutNotificationUrl of bidders, and applies the rate limit to all
+	// of them. Adapters which make their own notifications send them regardless.
+	Enabled bool `mapstructure:"enabled"`
+	// MaxPerSecond is the most notifications sent to a bidder each second. It isn't limited if 0.
+	MaxPerSecond int `mapstructure:"max_per_second"`
+	// TimeoutMs is how long a notification may take.
+	TimeoutMs int `mapstructure:"timeout_ms"`
+}
+
+func (cfg *BidderTimeoutNotifications) validate(errs []error) []error {
+	if !cfg.Enabled {
+		return errs
+	}
+	if cfg.MaxPerSecond < 0 {
+		errs = append(errs, fmt.Errorf("bidder_timeout_notifications.max_per_second must be 0 or more. Got %d", cfg.MaxPerSecond))
+	}
+	if cfg.TimeoutMs <= 0 {
+		errs = append(errs, fmt.Errorf("bidder_timeout_notifications.timeout_ms must be positive. Got %d", cfg.TimeoutMs))
+	}
+	return errs
+}
+
+type RequestDecoding struct {
+	// ImpPrecheck counts the imps of /openrtb2/auction requests as their body is read, so that requests with
+	// too many of them are rejected before the rest of the body is read. The body is still buffered and
+	// unmarshaled whole.
+	ImpPrecheck bool `mapstructure:"imp_precheck"`
+	// MaxImps rejects prechecked requests with more imps than this, without reading the rest of them. It
+	// isn't limited if 0.
+	MaxImps int `mapstructure:"max_imps"`
+}
+
+func (cfg *RequestDecoding) validate(errs []error) []error {
+	if cfg.MaxImps < 0 {
+		errs = append(errs, fmt.Errorf("request_decoding.max_imps must be 0 or more. Got %d", cfg.MaxImps))
+	}
+	return errs
+}
+
+// RequestNormalization selects the fixes applied to requests, once stored requests have been merged into
+// them, so that requests with well known mistakes can be auctioned instead of rejected.
+type RequestNormalization struct {
+	Enabled bool `mapstructure:"enabled"`
+	// StringifiedNumbers turns strings holding numbers into numbers, in the fields which must be numbers
+	// and in the bidder params whose schema says they're numbers.
+	StringifiedNumbers bool `mapstructure:"stringified_numbers"`
+	// EnumCase fixes the case of currency codes and price granularities.
+	EnumCase bool `mapstructure:"enum_case"`
+	// ConsentLocation moves consent strings and privacy signals sent in the wrong object to the right one.
+	ConsentLocation bool `mapstructure:"consent_location"`
+}
+
+const (
+	IVTActionBlock = "block"
+	IVTActionTag   = "tag"
+)
+
+// IVT configures the screening of requests for invalid traffic: devices whose user agent is on the spiders and
+// bots list, requests from blocked or datacenter IP addresses, and blocked advertising IDs. Lists in files have
+// one entry per line, and lines starting with # are skipped.
+type IVT struct {
+	Enabled bool `mapstructure:"enabled"`
+	// Action is block, which rejects invalid traffic with a 503, or tag, which auctions it with device.ext.ivt set
+	Action string  `mapstructure:"action"`
+	Bots   IVTBots `mapstructure:"bots"`
+	// BlockedIPs are the IP addresses and CIDR blocks whose requests are invalid traffic
+	BlockedIPs     []string `mapstructure:"blocked_ips"`
+	BlockedIPsFile string   `mapstructure:"blocked_ips_file"`
+	// DatacenterIPsFile lists the CIDR blocks of datacenters and hosting providers
+	DatacenterIPsFile string `mapstructure:"datacenter_ips_file"`
+	// DatacenterIPsURL is where more CIDR blocks of datacenters are downloaded from, in the same format
+	DatacenterIPsURL string `mapstructure:"datacenter_ips_url"`
+	// DatacenterIPsRefreshMinutes is how often the datacenter file and URL are reloaded. Use 0 to load them once.
+	DatacenterIPsRefreshMinutes int `mapstructure:"datacenter_ips_refresh_minutes"`
+	// BlockedIFAs are the advertising IDs whose requests are invalid traffic
+	BlockedIFAs     []string `mapstructure:"blocked_ifas"`
+	BlockedIFAsFile string   `mapstructure:"blocked_ifas_file"`
+}
+
+// IVTBots configures the user agents of spiders and bots. The files are in the format of the IAB/ABC
+// International Spiders and Bots List, with a pattern, an active flag and, for the list, a flag to match the
+// pattern only at the start of the user agent, separated by |.
+type IVTBots struct {
+	// Builtin matches the user agents of well known crawlers and headless browsers
+	Builtin     bool   `mapstructure:"builtin"`
+	ListFile    string `mapstructure:"list_file"`
+	ExcludeFile string `mapstructure:"exclude_file"`
+}
+
+func (cfg *IVT) validate(errs []error) []error {
+	if !cfg.Enabled {
+		return errs
+	}
+	if cfg.Action != IVTActionBlock && cfg.Action != IVTActionTag {
+		errs = append(errs, fmt.Errorf("ivt.action must be %s or %s. Got %s", IVTActionBlock, IVTActionTag, cfg.Action))
+	}
+	for _, ip := range cfg.BlockedIPs {
+		if _, _, err := net.ParseCIDR(ip); err != nil && net.ParseIP(ip) == nil {
+			errs = append(errs, fmt.Errorf("ivt.blocked_ips has an invalid IP address or CIDR block: %s", ip))
+		}
+	}
+	if cfg.DatacenterIPsURL != "" {
+		if datacenterURL, err := url.Parse(cfg.DatacenterIPsURL); err != nil || (datacenterURL.Scheme != "http" && datacenterURL.Scheme != "https") || datacenterURL.Host == "" {
+			errs = append(errs, fmt.Errorf("ivt.datacenter_ips_url must be an http or https URL. Got %s", cfg.DatacenterIPsURL))
+		}
+	}
+	if cfg.DatacenterIPsRefreshMinutes < 0 {
+		errs = append(errs, fmt.Errorf("ivt.datacenter_ips_refresh_minutes must be >= 0. Got %d", cfg.DatacenterIPsRefreshMinutes))
+	}
+	return errs
+}
+
+// ResponseSigning holds the ECDSA P-256 private keys auction responses are signed with, by key ID. Each account
+// which signs its responses picks one of them, so keys can be rotated by adding a new one and moving accounts
+// over to it before the old one is removed.
+type ResponseSigning struct {
+	// Keys are the paths of PEM files with the private keys, by key ID
+	Keys map[string]string `mapstructure:"keys"`
+}
+
+func (cfg *ResponseSigning) validate(errs []error) []error {
+	for id, path := range cfg.Keys {
+		if path == "" {
+			errs = append(errs, fmt.Errorf("response_signing.keys.%s must be the path of a PEM file", id))
+		}
+	}
+	return errs
+}
+
+// GeoLocationProviderMaxMind reads locations from a MaxMind DB file
+const GeoLocationProviderMaxMind = "maxmind"
+
+// GeoLocation configures looking up where devices are from their IP addresses, to fill in device.geo for
+// requests which don't have it before GDPR scope, price floors and bidders use it.
+type GeoLocation struct {
+	Enabled  bool               `mapstructure:"enabled"`
+	Provider string             `mapstructure:"provider"`
+	MaxMind  GeoLocationMaxMind `mapstructure:"maxmind"`
+}
+
+// GeoLocationMaxMind configures the MaxMind DB file locations are read from, such as GeoIP2 or GeoLite2 City.
+type GeoLocationMaxMind struct {
+	DatabasePath string `mapstructure:"database_path"`
+	// ReloadIntervalSeconds is how often the file is checked for a new database. Use 0 to never reload it.
+	ReloadIntervalSeconds int `mapstructure:"reload_interval_seconds"`
+}
+
+func (cfg *GeoLocation) validate(errs []error) []error {
+	if !cfg.Enabled {
+		return errs
+	}
+	if cfg.Provider != GeoLocationProviderMaxMind {
+		errs = append(errs, fmt.Errorf("geolocation.provider must be %s. Got %s", GeoLocationProviderMaxMind, cfg.Provider))
+		return errs
+	}
+	if cfg.MaxMind.DatabasePath == "" {
+		errs = append(errs, errors.New("geolocation.maxmind.database_path must be the path of a MaxMind DB file"))
+	}
+	if cfg.MaxMind.ReloadIntervalSeconds < 0 {
+		errs = append(errs, fmt.Errorf("geolocation.maxmind.reload_interval_seconds must be 0 or more. Got %d", cfg.MaxMind.ReloadIntervalSeconds))
+	}
+	return errs
+}
+
+// ContentClassificationProviderHTTP classifies content with a service at an HTTP endpoint
+const ContentClassificationProviderHTTP = "http"
+
+// ContentClassification configures the classifier of the content of the pages and apps of auctions, in accounts
+// with content_classification enabled. The classifier is only called if the auction has enough time left, so
+// the classifications of content seen before, which are cached by URL, are the only ones most auctions use.
+type ContentClassification struct {
+	Enabled  bool                      `mapstructure:"enabled"`
+	Provider string                    `mapstructure:"provider"`
+	HTTP     ContentClassificationHTTP `mapstructure:"http"`
+	// TimeoutMS bounds each call to the classifier
+	TimeoutMS int `mapstructure:"timeout_ms"`
+	// MinRemainingMS is the time the auction must still have once the classifier's timeout is over, for the
+	// classifier to be called
+	MinRemainingMS int `mapstructure:"min_remaining_ms"`
+	// CacheSize is the number of URLs whose classification is cached
+	CacheSize int `mapstructure:"cache_size"`
+	// CacheTTLSeconds is how long a URL's classification is cached
+	CacheTTLSeconds int `mapstructure:"cache_ttl_seconds"`
+	// DataName is the name of the data object of site.content or app.content the segments are added in
+	DataName string `mapstructure:"data_name"`
+}
+
+// ContentClassificationHTTP configures the service content is classified by.
+type ContentClassificationHTTP struct {
+	Endpoint string `mapstructure:"endpoint"`
+}
+
+func (cfg *ContentClassification) validate(errs []error) []error {
+	if !cfg.Enabled {
+		return errs
+	}
+	if cfg.Provider != ContentClassificationProviderHTTP {
+		errs = append(errs, fmt.Errorf("content_classification.provider must be %s. Got %s", ContentClassificationProviderHTTP, cfg.Provider))
+	} else if _, err := url.ParseRequestURI(cfg.HTTP.Endpoint); err != nil {
+		errs = append(errs, fmt.Errorf("content_classification.http.endpoint must be a URL. Got %s", cfg.HTTP.Endpoint))
+	}
+	if cfg.TimeoutMS <= 0 {
+		errs = append(errs, fmt.Errorf("content_classification.timeout_ms must be > 0. Got %d", cfg.TimeoutMS))
+	}
+	if cfg.MinRemainingMS < 0 {
+		errs = append(errs, fmt.Errorf("content_classification.min_remaining_ms must be >= 0. Got %d", cfg.MinRemainingMS))
+	}
+	if cfg.CacheSize <= 0 {
+		errs = append(errs, fmt.Errorf("content_classification.cache_size must be > 0. Got %d", cfg.CacheSize))
+	}
+	if cfg.CacheTTLSeconds <= 0 {
+		errs = append(errs, fmt.Errorf("content_classification.cache_ttl_seconds must be > 0. Got %d", cfg.CacheTTLSeconds))
+	}
+	if cfg.DataName == "" {
+		errs = append(errs, errors.New("content_classification.data_name must not be empty"))
+	}
+	return errs
+}
+
+const (
+	// PIIScannerModeOff doesn't scan requests
+	PIIScannerModeOff = "off"
+	// PIIScannerModeReport scans a sample of the requests and reports the personal data found in metrics and
+	// the debug output
+	PIIScannerModeReport = "report"
+	// PIIScannerModeEnforce scans every request, reports what's found and doesn't send the requests with any
+	PIIScannerModeEnforce = "enforce"
+)
+
+// PIIScanner configures scanning the HTTP requests sent to bidders for personal data. It's a backstop for
+// adapters and modules which copy data from places the privacy policies don't clean.
+type PIIScanner struct {
+	Mode string `mapstructure:"mode"`
+	// SampleRate is the share of bidder requests scanned in report mode, from 0 to 1
+	SampleRate float64 `mapstructure:"sample_rate"`
+	// Emails finds email addresses
+	Emails bool `mapstructure:"emails"`
+	// PreciseGeo finds latitudes and longitudes more precise than the privacy policies allow the bidder
+	PreciseGeo bool `mapstructure:"precise_geo"`
+	// UserIDs finds the IDs of the user and device which the privacy policies removed from the bidder's request
+	UserIDs bool `mapstructure:"user_ids"`
+	// Patterns are regular expressions of other personal data to find, by name
+	Patterns map[string]string `mapstructure:"patterns"`
+}
+
+// MobileSDK configures how requests from the Prebid Mobile SDK are handled. Each feature can be limited to the
+// SDK versions which send what it expects.
+type MobileSDK struct {
+	// Interstitial sizes the interstitial banners which don't have a size from the device's screen
+	Interstitial MobileSDKInterstitial `mapstructure:"interstitial"`
+	// ATT applies the iOS App Tracking Transparency status to the device's advertising ID and LMT flag
+	ATT MobileSDKFeature `mapstructure:"att"`
+	// Multiformat fills in the banner and video objects of impressions which can serve either from each other
+	Multiformat MobileSDKFeature `mapstructure:"multiformat"`
+}
+
+// MobileSDKFeature turns a feature on for requests from the Prebid Mobile SDK.
+type MobileSDKFeature struct {
+	Enabled bool `mapstructure:"enabled"`
+	// MinVersion is the oldest SDK version the feature applies to, such as 2.1.0. Empty applies it to all versions.
+	MinVersion string `mapstructure:"min_version"`
+}
+
+type MobileSDKInterstitial struct {
+	MobileSDKFeature `mapstructure:",squash"`
+	// MinSizePerc is the smallest ad size allowed, as a percentage of the screen's width and height
+	MinSizePerc int64 `mapstructure:"min_size_perc"`
+}
+
+const (
+	RateLimitingBackendLocal = "local"
+	RateLimitingBackendRedis = "redis"
+)
+
+// RateLimiting configures the counters the API key rate limits, bidder QPS caps and deal pacing are enforced
+// with. Local counters limit each instance on its own, so a limit is multiplied by the number of instances
+// running. Redis counters are shared by every instance which uses the same Redis server.
+type RateLimiting struct {
+	// Backend is local or redis
+	Backend string            `mapstructure:"backend"`
+	Redis   RateLimitingRedis `mapstructure:"redis"`
+	// BidderQPS caps the requests per second sent to each bidder, by bidder name. Bidders over their cap are
+	// left out of auctions until the next second.
+	BidderQPS map[string]int `mapstructure:"bidder_qps"`
+}
+
+// RateLimitingRedis configures the Redis server or cluster the counters are kept in
+type RateLimitingRedis struct {
+	RedisConnection `mapstructure:",squash"`
+	// KeyPrefix is put in front of the keys of the counters, so the Redis server can be shared with other uses
+	KeyPrefix string `mapstructure:"key_prefix"`
+	// TimeoutMS bounds each call to the Redis server. Limits aren't enforced for calls which fail or time out.
+	TimeoutMS int `mapstructure:"timeout_ms"`
+}
+
+func (cfg *RateLimiting) validate(errs []error) []error {
+	switch cfg.Backend {
+	case "", RateLimitingBackendLocal:
+	case RateLimitingBackendRedis:
+		if len(cfg.Redis.Addrs) == 0 {
+			errs = append(errs, errors.New("rate_limiting.redis.addrs is required for the redis backend"))
+		}
+		if cfg.Redis.TimeoutMS <= 0 {
+			errs = append(errs, fmt.Errorf("rate_limiting.redis.timeout_ms must be > 0. Got %d", cfg.Redis.TimeoutMS))
+		}
+		errs = cfg.Redis.RedisConnection.validate("rate_limiting.redis", errs)
+	default:
+		errs = append(errs, fmt.Errorf("rate_limiting.backend must be %s or %s. Got %s", RateLimitingBackendLocal, RateLimitingBackendRedis, cfg.Backend))
+	}
+	for bidder, qps := range cfg.BidderQPS {
+		if qps <= 0 {
+			errs = append(errs, fmt.Errorf("rate_limiting.bidder_qps.%s must be > 0. Got %d", bidder, qps))
+		}
+	}
+	return errs
+}
+
+func (cfg *MobileSDK) validate(errs []error) []error {
+	errs = cfg.Interstitial.MobileSDKFeature.validate("mobile_sdk.interstitial", errs)
+	if cfg.Interstitial.Enabled && (cfg.Interstitial.MinSizePerc < 0 || cfg.Interstitial.MinSizePerc > 100) {
+		errs = append(errs, fmt.Errorf("mobile_sdk.interstitial.min_size_perc must be between 0 and 100. Got %d", cfg.Interstitial.MinSizePerc))
+	}
+	errs = cfg.ATT.validate("mobile_sdk.att", errs)
+	errs = cfg.Multiformat.validate("mobile_sdk.multiformat", errs)
+	return errs
+}
+
+func (cfg *MobileSDKFeature) validate(name string, errs []error) []error {
+	if !cfg.Enabled || cfg.MinVersion == "" {
+		return errs
+	}
+	if _, err := versionutil.Parse(cfg.MinVersion); err != nil {
+		errs = append(errs, fmt.Errorf("%s.min_version %s: %v", name, cfg.MinVersion, err))
+	}
+	return errs
+}
+
+func (cfg *PIIScanner) validate(errs []error) []error {
+	switch cfg.Mode {
+	case "", PIIScannerModeOff:
+		return errs
+	case PIIScannerModeReport, PIIScannerModeEnforce:
+	default:
+		errs = append(errs, fmt.Errorf("pii_scanner.mode must be one of %s, %s or %s. Got %s", PIIScannerModeOff, PIIScannerModeReport, PIIScannerModeEnforce, cfg.Mode))
+	}
+	if cfg.SampleRate < 0 || cfg.SampleRate > 1 {
+		errs = append(errs, fmt.Errorf("pii_scanner.sample_rate must be between 0 and 1. Got %g", cfg.SampleRate))
+	}
+	for name, pattern := range cfg.Patterns {
+		if _, err := regexp.Compile(pattern); err != nil {
+			errs = append(errs, fmt.Errorf("pii_scanner.patterns.%s is not a valid regular expression: %v", name, err))
+		}
+	}
+	return errs
+}
+
 type HTTPClient struct {
 	MaxConnsPerHost     int `mapstructure:"max_connections_per_host"`
 	MaxIdleConns        int `mapstructure:"max_idle_connections"`
@@ -133,24 +1511,98 @@ func (cfg *Configuration) validate(v *viper.Viper) []error {
 	errs = cfg.AuctionTimeouts.validate(errs)
 	errs = cfg.StoredRequests.validate(errs)
 	errs = cfg.StoredRequestsAMP.validate(errs)
+	errs = cfg.DefReqConfig.validate(errs)
 	errs = cfg.Accounts.validate(errs)
 	errs = cfg.CategoryMapping.validate(errs)
 	errs = cfg.StoredVideo.validate(errs)
+	errs = cfg.StoredDataEncryption.validate(errs)
+	errs = cfg.StoredDataBus.validate(errs)
+	errs = cfg.ConfigWatch.validate(errs)
+	errs = cfg.BidderRequestPool.validate(errs)
+	errs = cfg.BidderConnectionWarmup.validate(cfg.BidderInfos, cfg.Client, errs)
+	errs = cfg.InProcessBidders.validate(cfg.BidderInfos, errs)
+	errs = cfg.ParsedStoredRequestCache.validate(errs)
+	errs = cfg.BidderResponseCache.validate(errs)
+	errs = cfg.LoadShedding.validate(errs)
+	errs = cfg.LatencyBudget.validate(errs)
+	errs = cfg.AuctionRecording.validate(errs)
+	errs = cfg.TrafficShadowing.validate(errs)
+	errs = cfg.Webhooks.validate(errs)
+	errs = cfg.AuctionEventWebhooks.validate(errs)
+	errs = cfg.BidLandscape.validate(errs)
+	errs = cfg.AuctionQuality.validate(errs)
+	errs = cfg.Metering.validate(errs)
+	errs = cfg.ResponseOverrides.validate(errs)
+	errs = cfg.StoredRequestVersions.validate(errs)
+	errs = cfg.BidderMaintenance.validate(errs)
+	errs = cfg.AdaptiveBidderTmax.validate(errs)
+	errs = cfg.Analytics.Hub.validate(errs)
+	errs = cfg.FaultInjection.validate(errs)
+	errs = cfg.Logging.validate(errs)
+	errs = cfg.JSON.validate(errs)
+	errs = cfg.RequestDecoding.validate(errs)
+	errs = cfg.BidderTimeoutNotifications.validate(errs)
+	errs = cfg.IVT.validate(errs)
+	errs = cfg.ResponseSigning.validate(errs)
+	errs = cfg.GeoLocation.validate(errs)
+	errs = cfg.ContentClassification.validate(errs)
+	errs = cfg.TestTraffic.validate(errs)
+	errs = cfg.PIIScanner.validate(errs)
+	errs = cfg.MobileSDK.validate(errs)
+	errs = cfg.RateLimiting.validate(errs)
 	errs = cfg.Metrics.validate(errs)
 	if cfg.MaxRequestSize < 0 {
 		errs = append(errs, fmt.Errorf("cfg.max_request_size must be >= 0. Got %d", cfg.MaxRequestSize))
 	}
+	if cfg.MaxBidderResponseSize < 0 {
+		errs = append(errs, fmt.Errorf("cfg.max_bidder_response_size must be >= 0. Got %d", cfg.MaxBidderResponseSize))
+	}
 	errs = cfg.GDPR.validate(v, errs)
 	errs = cfg.CurrencyConverter.validate(errs)
 	errs = cfg.Debug.validate(errs)
+	errs = cfg.Hooks.validate(errs)
 	errs = cfg.ExtCacheURL.validate(errs)
 	errs = cfg.AccountDefaults.PriceFloors.validate(errs)
+	errs = cfg.AccountDefaults.DebugToken.validate(errs)
+	errs = cfg.AccountDefaults.DebugAccess.validate(errs)
+	errs = cfg.AccountDefaults.AdsCert.validate(errs)
+	if (cfg.AccountDefaults.AdsCert.Sign || cfg.AccountDefaults.AdsCert.VerifyEnabled()) && cfg.Experiment.AdCerts.Mode != AdCertsSignerModeInprocess && cfg.Experiment.AdCerts.Mode != AdCertsSignerModeRemote {
+		errs = append(errs, errors.New("account_defaults.adscert needs experiment.adscert.mode to be inprocess or remote"))
+	}
+	errs = cfg.AccountDefaults.SChain.validate(errs)
+	errs = cfg.AccountDefaults.Origin.validate(errs)
+	errs = cfg.AccountDefaults.RequestLimits.validate(errs)
+	errs = cfg.AccountDefaults.APIKeys.validate(errs)
+	errs = cfg.AccountDefaults.ResponseSigning.validate(errs)
+	errs = cfg.AccountDefaults.DealPacing.validate(errs)
+	errs = cfg.AccountDefaults.CreativeTrackers.validate(errs)
+	errs = cfg.AccountDefaults.NativeTrackers.validate(errs)
+	errs = cfg.AccountDefaults.FirstPartyData.validate(errs)
+	errs = cfg.AccountDefaults.DefaultRequest.validate(errs)
+	errs = cfg.AccountDefaults.SizeResolution.validate(errs)
+	errs = cfg.AccountDefaults.DynamicTmax.validate(errs)
+	errs = cfg.AccountDefaults.EventWebhook.validate(errs)
+	errs = cfg.AccountDefaults.IVT.validate(errs)
+	errs = cfg.AccountDefaults.Multiformat.validate(errs)
+	errs = cfg.AccountDefaults.BidderFields.validate(errs)
+	errs = cfg.AccountDefaults.LineItems.validate(errs)
+	errs = cfg.AccountDefaults.PriceEncryption.validate(errs)
+	errs = cfg.AccountDefaults.UsageQuota.validate(errs)
+	errs = cfg.AccountDefaults.Currency.validate(errs)
+	errs = cfg.AccountDefaults.ResponseExt.validate(errs)
+	errs = validateBidderMaintenanceWindows("account_defaults.bidder_maintenance", cfg.AccountDefaults.BidderMaintenance, errs)
+	if _, ok := cfg.ResponseSigning.Keys[cfg.AccountDefaults.ResponseSigning.KeyID]; cfg.AccountDefaults.ResponseSigning.Enabled && !ok {
+		errs = append(errs, fmt.Errorf("account_defaults.response_signing.key_id %s is not one of response_signing.keys", cfg.AccountDefaults.ResponseSigning.KeyID))
+	}
+	if cfg.AccountDefaults.Parent != "" {
+		errs = append(errs, errors.New("account_defaults.parent cannot be set. Parent accounts are only supported on host-defined accounts"))
+	}
 	if cfg.AccountDefaults.Disabled {
-		glog.Warning(`With account_defaults.disabled=true, host-defined accounts must exist and have "disabled":false. All other requests will be rejected.`)
+		logger.Warning(`With account_defaults.disabled=true, host-defined accounts must exist and have "disabled":false. All other requests will be rejected.`)
 	}
 
 	if cfg.AccountDefaults.Events.Enabled {
-		glog.Warning(`account_defaults.events has no effect as the feature is under development.`)
+		logger.Warning(`account_defaults.events has no effect as the feature is under development.`)
 	}
 
 	errs = cfg.Experiment.validate(errs)
@@ -158,6 +1610,10 @@ func (cfg *Configuration) validate(v *viper.Viper) []error {
 	errs = cfg.AccountDefaults.Privacy.IPv6Config.Validate(errs)
 	errs = cfg.AccountDefaults.Privacy.IPv4Config.Validate(errs)
 
+	if cfg.StrictDeprecatedSettings {
+		errs = append(errs, deprecatedSettingsErrors(cfg.deprecatedSettingsInUse)...)
+	}
+
 	return errs
 }
 
@@ -262,7 +1718,7 @@ func (cfg *GDPR) validate(v *viper.Viper, errs []error) []error {
 		errs = append(errs, fmt.Errorf("gdpr.host_vendor_id must be in the range [0, %d]. Got %d", 0xffff, cfg.HostVendorID))
 	}
 	if cfg.HostVendorID == 0 {
-		glog.Warning("gdpr.host_vendor_id was not specified. Host company GDPR checks will be skipped.")
+		logger.Warning("gdpr.host_vendor_id was not specified. Host company GDPR checks will be skipped.")
 	}
 	if cfg.AMPException == true {
 		errs = append(errs, fmt.Errorf("gdpr.amp_exception has been discontinued and must be removed from your config. If you need to disable GDPR for AMP, you may do so per-account (gdpr.integration_enabled.amp) or at the host level for the default account (account_defaults.gdpr.integration_enabled.amp)"))
@@ -449,18 +1905,117 @@ type Analytics struct {
 	File     FileLogs      `mapstructure:"file"`
 	Agma     AgmaAnalytics `mapstructure:"agma"`
 	Pubstack Pubstack      `mapstructure:"pubstack"`
+	Hub      AnalyticsHub  `mapstructure:"hub"`
+}
+
+// AnalyticsHub lets edge instances forward their analytics to a hub instance, which logs them with its own
+// analytics modules, so that only the hub needs the modules' credentials.
+type AnalyticsHub struct {
+	// Forward sends the analytics of this instance to the hub, in gzipped batches.
+	Forward AnalyticsHubForward `mapstructure:"forward"`
+	// Ingest accepts the batches of the edge instances on the admin server.
+	Ingest AnalyticsHubIngest `mapstructure:"ingest"`
+}
+
+type AnalyticsHubForward struct {
+	Enabled bool `mapstructure:"enabled"`
+	// URL is the ingest endpoint of the hub's admin server
+	URL string `mapstructure:"url"`
+	// Token is the bearer token sent to the hub
+	Token   string             `mapstructure:"token"`
+	Buffers AnalyticsHubBuffer `mapstructure:"buffers"`
+}
+
+type AnalyticsHubBuffer struct {
+	BufferSize string `mapstructure:"size"`
+	EventCount int    `mapstructure:"count"`
+	Timeout    string `mapstructure:"timeout"`
+}
+
+type AnalyticsHubIngest struct {
+	Enabled bool `mapstructure:"enabled"`
+	// Tokens are the bearer tokens which may send batches
+	Tokens []string `mapstructure:"tokens"`
+	// MaxBatchSize bounds the size of a batch once it's decompressed
+	MaxBatchSize int64 `mapstructure:"max_batch_size"`
+}
+
+func (cfg *AnalyticsHub) validate(errs []error) []error {
+	if cfg.Forward.Enabled {
+		if _, err := url.ParseRequestURI(cfg.Forward.URL); err != nil {
+			errs = append(errs, fmt.Errorf("analytics.hub.forward.url must be a valid URL. Got %q", cfg.Forward.URL))
+		}
+		if cfg.Forward.Buffers.EventCount <= 0 {
+			errs = append(errs, fmt.Errorf("analytics.hub.forward.buffers.count must be > 0. Got %d", cfg.Forward.Buffers.EventCount))
+		}
+	}
+	if cfg.Ingest.Enabled {
+		// the hub would forward the batches back to itself
+		if cfg.Forward.Enabled {
+			errs = append(errs, errors.New("analytics.hub.forward and analytics.hub.ingest cannot both be enabled"))
+		}
+		if cfg.Ingest.MaxBatchSize <= 0 {
+			errs = append(errs, fmt.Errorf("analytics.hub.ingest.max_batch_size must be > 0. Got %d", cfg.Ingest.MaxBatchSize))
+		}
+		if len(cfg.Ingest.Tokens) == 0 {
+			errs = append(errs, errors.New("analytics.hub.ingest.tokens must have at least one token"))
+		}
+		for i, token := range cfg.Ingest.Tokens {
+			if token == "" {
+				errs = append(errs, fmt.Errorf("analytics.hub.ingest.tokens[%d] must not be empty", i))
+			}
+		}
+	}
+	return errs
 }
 
 type CurrencyConverter struct {
 	FetchURL             string `mapstructure:"fetch_url"`
 	FetchIntervalSeconds int    `mapstructure:"fetch_interval_seconds"`
 	StaleRatesSeconds    int    `mapstructure:"stale_rates_seconds"`
+	// RatesSources are fetched in order of priority, failing over to the next source when one fails. The
+	// FetchURL is the only source if there are none.
+	RatesSources []CurrencyRatesSource `mapstructure:"rates_sources"`
+	// SourceMaxFailures is how many updates in a row a source may fail before it's skipped as unhealthy
+	SourceMaxFailures int `mapstructure:"source_max_failures"`
+	// SourceRetrySeconds is how long an unhealthy source is skipped for before it's tried again
+	SourceRetrySeconds int `mapstructure:"source_retry_seconds"`
+	// StaleAlarmSeconds is how long the rates may go without an update before the currency_rates_stale
+	// metric is raised. The alarm is off if it's 0.
+	StaleAlarmSeconds int `mapstructure:"stale_alarm_seconds"`
+}
+
+// CurrencyRatesSource is a URL serving currency rates in the format of the Prebid currency file.
+type CurrencyRatesSource struct {
+	// Name identifies the source in the logs. It defaults to the URL.
+	Name string `mapstructure:"name"`
+	URL  string `mapstructure:"url"`
+	// Priority orders the sources, from the lowest. Sources of the same priority are tried in the order given.
+	Priority  int `mapstructure:"priority"`
+	TimeoutMS int `mapstructure:"timeout_ms"`
 }
 
 func (cfg *CurrencyConverter) validate(errs []error) []error {
 	if cfg.FetchIntervalSeconds < 0 {
 		errs = append(errs, fmt.Errorf("currency_converter.fetch_interval_seconds must be in the range [0, %d]. Got %d", 0xffff, cfg.FetchIntervalSeconds))
 	}
+	for i, source := range cfg.RatesSources {
+		if sourceURL, err := url.Parse(source.URL); err != nil || (sourceURL.Scheme != "http" && sourceURL.Scheme != "https") || sourceURL.Host == "" {
+			errs = append(errs, fmt.Errorf("currency_converter.rates_sources[%d].url must be an http or https URL. Got %s", i, source.URL))
+		}
+		if source.TimeoutMS < 0 {
+			errs = append(errs, fmt.Errorf("currency_converter.rates_sources[%d].timeout_ms must be >= 0. Got %d", i, source.TimeoutMS))
+		}
+	}
+	if len(cfg.RatesSources) > 0 && cfg.SourceMaxFailures <= 0 {
+		errs = append(errs, fmt.Errorf("currency_converter.source_max_failures must be > 0. Got %d", cfg.SourceMaxFailures))
+	}
+	if cfg.SourceRetrySeconds < 0 {
+		errs = append(errs, fmt.Errorf("currency_converter.source_retry_seconds must be >= 0. Got %d", cfg.SourceRetrySeconds))
+	}
+	if cfg.StaleAlarmSeconds < 0 {
+		errs = append(errs, fmt.Errorf("currency_converter.stale_alarm_seconds must be >= 0. Got %d", cfg.StaleAlarmSeconds))
+	}
 	return errs
 }
 
@@ -645,6 +2200,48 @@ type DefReqConfig struct {
 	Type       string      `mapstructure:"type"`
 	FileSystem DefReqFiles `mapstructure:"file"`
 	AliasInfo  bool        `mapstructure:"alias_info"`
+	// Channels are the default requests of each integration type, web, app or dooh, which are layered over
+	// the default request for the auction requests of that type.
+	Channels map[string]DefReqFiles `mapstructure:"channels"`
+	// Merge is how the default requests are merged with the requests layered over them.
+	Merge DefReqMerge `mapstructure:"merge"`
+}
+
+// DefReqMerge is how default requests are merged with the requests layered over them. Objects are merged
+// key by key, and a request's other values replace those of its defaults.
+type DefReqMerge struct {
+	// Arrays is replace, where a request's arrays replace those of its defaults, or merge, where the items
+	// of the defaults are kept and the request's are added after them. The imps are always replaced.
+	Arrays string `mapstructure:"arrays"`
+	// Ext is merge, where ext objects are merged key by key like the rest of the request, or replace, where
+	// a request's ext objects replace those of its defaults.
+	Ext string `mapstructure:"ext"`
+	// Enforce are the dot separated paths, such as source.schain or regs, where the host's default requests
+	// override the account's default requests, stored requests and the request itself.
+	Enforce []string `mapstructure:"enforce"`
+}
+
+func (cfg *DefReqConfig) validate(errs []error) []error {
+	for channel, files := range cfg.Channels {
+		if channel != string(ChannelWeb) && channel != string(ChannelApp) && channel != string(ChannelDOOH) {
+			errs = append(errs, fmt.Errorf("default_request.channels must be web, app or dooh. Got %s", channel))
+		}
+		if cfg.Type != "file" && len(files.FileName) > 0 {
+			errs = append(errs, fmt.Errorf("default_request.channels.%s needs default_request.type to be file", channel))
+		}
+	}
+	if cfg.Merge.Arrays != "" && cfg.Merge.Arrays != "replace" && cfg.Merge.Arrays != "merge" {
+		errs = append(errs, fmt.Errorf("default_request.merge.arrays must be replace or merge. Got %s", cfg.Merge.Arrays))
+	}
+	if cfg.Merge.Ext != "" && cfg.Merge.Ext != "merge" && cfg.Merge.Ext != "replace" {
+		errs = append(errs, fmt.Errorf("default_request.merge.ext must be merge or replace. Got %s", cfg.Merge.Ext))
+	}
+	for i, path := range cfg.Merge.Enforce {
+		if len(path) == 0 || strings.HasPrefix(path, ".") || strings.HasSuffix(path, ".") || strings.Contains(path, "..") {
+			errs = append(errs, fmt.Errorf("default_request.merge.enforce[%d] must be a dot separated path. Got %s", i, path))
+		}
+	}
+	return errs
 }
 
 type DefReqFiles struct {
@@ -707,22 +2304,26 @@ func (cfg *TimeoutNotification) validate(errs []error) []error {
 
 // New uses viper to get our server configurations.
 func New(v *viper.Viper, bidderInfos BidderInfos, normalizeBidderName func(string) (openrtb_ext.BidderName, bool)) (*Configuration, error) {
+	deprecatedSettingsInUse := migrateDeprecatedSettings(v, deprecatedSettings)
+
 	var c Configuration
 	if err := v.Unmarshal(&c); err != nil {
 		return nil, fmt.Errorf("viper failed to unmarshal app config: %v", err)
 	}
+	c.deprecatedSettingsInUse = deprecatedSettingsInUse
 
 	if err := c.RequestValidation.Parse(); err != nil {
 		return nil, err
 	}
 
 	if err := isValidCookieSize(c.HostCookie.MaxCookieSizeBytes); err != nil {
-		glog.Fatal(fmt.Printf("Max cookie size %d cannot be less than %d \n", c.HostCookie.MaxCookieSizeBytes, MIN_COOKIE_SIZE_BYTES))
+		logger.Fatal(fmt.Printf("Max cookie size %d cannot be less than %d \n", c.HostCookie.MaxCookieSizeBytes, MIN_COOKIE_SIZE_BYTES))
 		return nil, err
 	}
 
 	// Update account defaults and generate base json for patch
 	c.AccountDefaults.CacheTTL = c.CacheURL.DefaultTTLs // comment this out to set explicitly in config
+	c.AccountDefaults.Currency.NormalizeRates()
 
 	if err := c.MarshalAccountDefaults(); err != nil {
 		return nil, err
@@ -803,7 +2404,7 @@ func New(v *viper.Viper, bidderInfos BidderInfos, normalizeBidderName func(strin
 	}
 	c.BidderInfos = mergedBidderInfos
 
-	glog.Info("Logging the resolved configuration:")
+	logger.Info("Logging the resolved configuration:")
 	logGeneral(reflect.ValueOf(c), "  \t")
 	if errs := c.validate(v); len(errs) > 0 {
 		return &c, errortypes.NewAggregateError("validation errors", errs)
@@ -846,7 +2447,7 @@ func setConfigBidderInfoNillableFields(v *viper.Viper, bidderInfos BidderInfos)
 func (cfg *Configuration) MarshalAccountDefaults() error {
 	var err error
 	if cfg.accountDefaultsJSON, err = jsonutil.Marshal(cfg.AccountDefaults); err != nil {
-		glog.Warningf("converting %+v to json: %v", cfg.AccountDefaults, err)
+		logger.Warningf("converting %+v to json: %v", cfg.AccountDefaults, err)
 	}
 	return err
 }
@@ -928,6 +2529,14 @@ func SetupViper(v *viper.Viper, filename string, bidderInfos BidderInfos) {
 	v.SetDefault("http_client_cache.max_idle_connections", 10)
 	v.SetDefault("http_client_cache.max_idle_connections_per_host", 2)
 	v.SetDefault("http_client_cache.idle_connection_timeout_seconds", 60)
+	v.SetDefault("bidder_request_pool.enabled", false)
+	v.SetDefault("bidder_request_pool.workers", 1000)
+	v.SetDefault("bidder_request_pool.queue_size", 10000)
+	v.SetDefault("bidder_connection_warmup.enabled", false)
+	v.SetDefault("bidder_connection_warmup.bidders", []string{})
+	v.SetDefault("bidder_connection_warmup.connections", 2)
+	v.SetDefault("bidder_connection_warmup.keep_alive_interval_seconds", 30)
+	v.SetDefault("bidder_connection_warmup.timeout_ms", 1000)
 	// no metrics configured by default (metrics{host|database|username|password})
 	v.SetDefault("metrics.disabled_metrics.account_adapter_details", false)
 	v.SetDefault("metrics.disabled_metrics.account_debug", true)
@@ -947,6 +2556,7 @@ func SetupViper(v *viper.Viper, filename string, bidderInfos BidderInfos) {
 	v.SetDefault("metrics.prometheus.timeout_ms", 10000)
 	v.SetDefault("category_mapping.filesystem.enabled", true)
 	v.SetDefault("category_mapping.filesystem.directorypath", "./static/category-mapping")
+	v.SetDefault("category_mapping.filesystem.watch_interval_seconds", 0)
 	v.SetDefault("category_mapping.http.endpoint", "")
 	v.SetDefault("stored_requests.database.connection.driver", "")
 	v.SetDefault("stored_requests.database.connection.dbname", "")
@@ -967,11 +2577,38 @@ func SetupViper(v *viper.Viper, filename string, bidderInfos BidderInfos) {
 	v.SetDefault("stored_requests.database.poll_for_updates.timeout_ms", 0)
 	v.SetDefault("stored_requests.database.poll_for_updates.query", "")
 	v.SetDefault("stored_requests.database.poll_for_updates.amp_query", "")
+	v.SetDefault("stored_requests.database.listen_notify.channel", "")
+	v.SetDefault("stored_requests.database.listen_notify.min_reconnect_interval_ms", 1000)
+	v.SetDefault("stored_requests.database.listen_notify.max_reconnect_interval_ms", 60000)
 	v.SetDefault("stored_requests.filesystem.enabled", false)
 	v.SetDefault("stored_requests.filesystem.directorypath", "./stored_requests/data/by_id")
+	v.SetDefault("stored_requests.filesystem.watch_interval_seconds", 0)
 	v.SetDefault("stored_requests.directorypath", "./stored_requests/data/by_id")
 	v.SetDefault("stored_requests.http.endpoint", "")
 	v.SetDefault("stored_requests.http.amp_endpoint", "")
+	v.SetDefault("stored_requests.redis.addrs", []string{})
+	v.SetDefault("stored_requests.redis.cluster", false)
+	v.SetDefault("stored_requests.redis.username", "")
+	v.SetDefault("stored_requests.redis.password", "")
+	v.SetDefault("stored_requests.redis.db", 0)
+	v.SetDefault("stored_requests.redis.key_prefix", "")
+	v.SetDefault("stored_requests.redis.timeout_ms", 100)
+	v.SetDefault("stored_requests.redis.pool_size", 0)
+	v.SetDefault("stored_requests.redis.tls.enabled", false)
+	v.SetDefault("stored_requests.redis.tls.root_cert", "")
+	v.SetDefault("stored_requests.redis.tls.client_cert", "")
+	v.SetDefault("stored_requests.redis.tls.client_key", "")
+	v.SetDefault("stored_requests.bucket.provider", "s3")
+	v.SetDefault("stored_requests.bucket.name", "")
+	v.SetDefault("stored_requests.bucket.prefix", "")
+	v.SetDefault("stored_requests.bucket.region", "")
+	v.SetDefault("stored_requests.bucket.endpoint", "")
+	v.SetDefault("stored_requests.bucket.access_key_id", "")
+	v.SetDefault("stored_requests.bucket.secret_access_key", "")
+	v.SetDefault("stored_requests.bucket.session_token", "")
+	v.SetDefault("stored_requests.bucket.sync_interval_seconds", 60)
+	v.SetDefault("stored_requests.bucket.timeout_ms", 10000)
+	v.SetDefault("stored_requests.bucket.refresh_endpoint", "")
 	v.SetDefault("stored_requests.in_memory_cache.type", "none")
 	v.SetDefault("stored_requests.in_memory_cache.ttl_seconds", 0)
 	v.SetDefault("stored_requests.in_memory_cache.request_cache_size_bytes", 0)
@@ -1003,9 +2640,36 @@ func SetupViper(v *viper.Viper, filename string, bidderInfos BidderInfos) {
 	v.SetDefault("stored_video_req.database.poll_for_updates.timeout_ms", 0)
 	v.SetDefault("stored_video_req.database.poll_for_updates.query", "")
 	v.SetDefault("stored_video_req.database.poll_for_updates.amp_query", "")
+	v.SetDefault("stored_video_req.database.listen_notify.channel", "")
+	v.SetDefault("stored_video_req.database.listen_notify.min_reconnect_interval_ms", 1000)
+	v.SetDefault("stored_video_req.database.listen_notify.max_reconnect_interval_ms", 60000)
 	v.SetDefault("stored_video_req.filesystem.enabled", false)
 	v.SetDefault("stored_video_req.filesystem.directorypath", "")
+	v.SetDefault("stored_video_req.filesystem.watch_interval_seconds", 0)
 	v.SetDefault("stored_video_req.http.endpoint", "")
+	v.SetDefault("stored_video_req.redis.addrs", []string{})
+	v.SetDefault("stored_video_req.redis.cluster", false)
+	v.SetDefault("stored_video_req.redis.username", "")
+	v.SetDefault("stored_video_req.redis.password", "")
+	v.SetDefault("stored_video_req.redis.db", 0)
+	v.SetDefault("stored_video_req.redis.key_prefix", "")
+	v.SetDefault("stored_video_req.redis.timeout_ms", 100)
+	v.SetDefault("stored_video_req.redis.pool_size", 0)
+	v.SetDefault("stored_video_req.redis.tls.enabled", false)
+	v.SetDefault("stored_video_req.redis.tls.root_cert", "")
+	v.SetDefault("stored_video_req.redis.tls.client_cert", "")
+	v.SetDefault("stored_video_req.redis.tls.client_key", "")
+	v.SetDefault("stored_video_req.bucket.provider", "s3")
+	v.SetDefault("stored_video_req.bucket.name", "")
+	v.SetDefault("stored_video_req.bucket.prefix", "")
+	v.SetDefault("stored_video_req.bucket.region", "")
+	v.SetDefault("stored_video_req.bucket.endpoint", "")
+	v.SetDefault("stored_video_req.bucket.access_key_id", "")
+	v.SetDefault("stored_video_req.bucket.secret_access_key", "")
+	v.SetDefault("stored_video_req.bucket.session_token", "")
+	v.SetDefault("stored_video_req.bucket.sync_interval_seconds", 60)
+	v.SetDefault("stored_video_req.bucket.timeout_ms", 10000)
+	v.SetDefault("stored_video_req.bucket.refresh_endpoint", "")
 	v.SetDefault("stored_video_req.in_memory_cache.type", "none")
 	v.SetDefault("stored_video_req.in_memory_cache.ttl_seconds", 0)
 	v.SetDefault("stored_video_req.in_memory_cache.request_cache_size_bytes", 0)
@@ -1035,9 +2699,36 @@ func SetupViper(v *viper.Viper, filename string, bidderInfos BidderInfos) {
 	v.SetDefault("stored_responses.database.poll_for_updates.timeout_ms", 0)
 	v.SetDefault("stored_responses.database.poll_for_updates.query", "")
 	v.SetDefault("stored_responses.database.poll_for_updates.amp_query", "")
+	v.SetDefault("stored_responses.database.listen_notify.channel", "")
+	v.SetDefault("stored_responses.database.listen_notify.min_reconnect_interval_ms", 1000)
+	v.SetDefault("stored_responses.database.listen_notify.max_reconnect_interval_ms", 60000)
 	v.SetDefault("stored_responses.filesystem.enabled", false)
 	v.SetDefault("stored_responses.filesystem.directorypath", "")
+	v.SetDefault("stored_responses.filesystem.watch_interval_seconds", 0)
 	v.SetDefault("stored_responses.http.endpoint", "")
+	v.SetDefault("stored_responses.redis.addrs", []string{})
+	v.SetDefault("stored_responses.redis.cluster", false)
+	v.SetDefault("stored_responses.redis.username", "")
+	v.SetDefault("stored_responses.redis.password", "")
+	v.SetDefault("stored_responses.redis.db", 0)
+	v.SetDefault("stored_responses.redis.key_prefix", "")
+	v.SetDefault("stored_responses.redis.timeout_ms", 100)
+	v.SetDefault("stored_responses.redis.pool_size", 0)
+	v.SetDefault("stored_responses.redis.tls.enabled", false)
+	v.SetDefault("stored_responses.redis.tls.root_cert", "")
+	v.SetDefault("stored_responses.redis.tls.client_cert", "")
+	v.SetDefault("stored_responses.redis.tls.client_key", "")
+	v.SetDefault("stored_responses.bucket.provider", "s3")
+	v.SetDefault("stored_responses.bucket.name", "")
+	v.SetDefault("stored_responses.bucket.prefix", "")
+	v.SetDefault("stored_responses.bucket.region", "")
+	v.SetDefault("stored_responses.bucket.endpoint", "")
+	v.SetDefault("stored_responses.bucket.access_key_id", "")
+	v.SetDefault("stored_responses.bucket.secret_access_key", "")
+	v.SetDefault("stored_responses.bucket.session_token", "")
+	v.SetDefault("stored_responses.bucket.sync_interval_seconds", 60)
+	v.SetDefault("stored_responses.bucket.timeout_ms", 10000)
+	v.SetDefault("stored_responses.bucket.refresh_endpoint", "")
 	v.SetDefault("stored_responses.in_memory_cache.type", "none")
 	v.SetDefault("stored_responses.in_memory_cache.ttl_seconds", 0)
 	v.SetDefault("stored_responses.in_memory_cache.request_cache_size_bytes", 0)
@@ -1059,7 +2750,54 @@ func SetupViper(v *viper.Viper, filename string, bidderInfos BidderInfos) {
 
 	v.SetDefault("accounts.filesystem.enabled", false)
 	v.SetDefault("accounts.filesystem.directorypath", "./stored_requests/data/by_id")
+	v.SetDefault("accounts.filesystem.watch_interval_seconds", 0)
+	v.SetDefault("accounts.redis.addrs", []string{})
+	v.SetDefault("accounts.redis.cluster", false)
+	v.SetDefault("accounts.redis.username", "")
+	v.SetDefault("accounts.redis.password", "")
+	v.SetDefault("accounts.redis.db", 0)
+	v.SetDefault("accounts.redis.key_prefix", "")
+	v.SetDefault("accounts.redis.timeout_ms", 100)
+	v.SetDefault("accounts.redis.pool_size", 0)
+	v.SetDefault("accounts.redis.tls.enabled", false)
+	v.SetDefault("accounts.redis.tls.root_cert", "")
+	v.SetDefault("accounts.redis.tls.client_cert", "")
+	v.SetDefault("accounts.redis.tls.client_key", "")
+	v.SetDefault("accounts.bucket.provider", "s3")
+	v.SetDefault("accounts.bucket.name", "")
+	v.SetDefault("accounts.bucket.prefix", "")
+	v.SetDefault("accounts.bucket.region", "")
+	v.SetDefault("accounts.bucket.endpoint", "")
+	v.SetDefault("accounts.bucket.access_key_id", "")
+	v.SetDefault("accounts.bucket.secret_access_key", "")
+	v.SetDefault("accounts.bucket.session_token", "")
+	v.SetDefault("accounts.bucket.sync_interval_seconds", 60)
+	v.SetDefault("accounts.bucket.timeout_ms", 10000)
+	v.SetDefault("accounts.bucket.refresh_endpoint", "")
 	v.SetDefault("accounts.in_memory_cache.type", "none")
+	v.SetDefault("accounts.database.listen_notify.channel", "")
+	v.SetDefault("accounts.database.listen_notify.min_reconnect_interval_ms", 1000)
+	v.SetDefault("accounts.database.listen_notify.max_reconnect_interval_ms", 60000)
+	v.SetDefault("stored_data_encryption.enabled", false)
+	v.SetDefault("stored_data_encryption.key_provider", "local")
+	v.SetDefault("stored_data_encryption.master_key", "")
+	v.SetDefault("stored_data_encryption.kms.endpoint", "")
+	v.SetDefault("stored_data_encryption.kms.timeout_ms", 500)
+	v.SetDefault("stored_data_bus.enabled", false)
+	v.SetDefault("stored_data_bus.channel", "prebid-server-stored-data")
+	v.SetDefault("stored_data_bus.timeout_ms", 500)
+	v.SetDefault("stored_data_bus.redis.addrs", []string{})
+	v.SetDefault("stored_data_bus.redis.cluster", false)
+	v.SetDefault("stored_data_bus.redis.username", "")
+	v.SetDefault("stored_data_bus.redis.password", "")
+	v.SetDefault("stored_data_bus.redis.db", 0)
+	v.SetDefault("stored_data_bus.redis.pool_size", 0)
+	v.SetDefault("stored_data_bus.redis.tls.enabled", false)
+	v.SetDefault("stored_data_bus.redis.tls.root_cert", "")
+	v.SetDefault("stored_data_bus.redis.tls.client_cert", "")
+	v.SetDefault("stored_data_bus.redis.tls.client_key", "")
+	v.SetDefault("config_watch.enabled", false)
+	v.SetDefault("config_watch.interval_seconds", 30)
 
 	v.BindEnv("user_sync.external_url")
 	v.BindEnv("user_sync.coop_sync.default")
@@ -1069,6 +2807,176 @@ func SetupViper(v *viper.Viper, filename string, bidderInfos BidderInfos) {
 	v.SetDefault("user_sync.redirect_url", "{{.ExternalURL}}/setuid?bidder={{.SyncerKey}}&gdpr={{.GDPR}}&gdpr_consent={{.GDPRConsent}}&gpp={{.GPP}}&gpp_sid={{.GPPSID}}&f={{.SyncType}}&uid={{.UserMacro}}")
 
 	v.SetDefault("max_request_size", 1024*256)
+	v.SetDefault("max_bidder_response_size", 0)
+	v.SetDefault("parsed_stored_request_cache.enabled", false)
+	v.SetDefault("parsed_stored_request_cache.max_entries", 10000)
+	v.SetDefault("bidder_response_cache.enabled", false)
+	v.SetDefault("bidder_response_cache.ttl_ms", 5000)
+	v.SetDefault("bidder_response_cache.max_entries", 10000)
+	v.SetDefault("bidder_response_cache.bidders", []string{})
+	v.SetDefault("load_shedding.memory.enabled", false)
+	v.SetDefault("load_shedding.memory.limit_bytes", 0)
+	v.SetDefault("load_shedding.memory.degrade_threshold", 0.8)
+	v.SetDefault("load_shedding.memory.shed_threshold", 0.9)
+	v.SetDefault("load_shedding.memory.check_interval_ms", 100)
+	v.SetDefault("load_shedding.memory.retry_after_seconds", 1)
+	v.SetDefault("load_shedding.memory.optional_modules", []string{})
+	v.SetDefault("load_shedding.concurrency.enabled", false)
+	v.SetDefault("load_shedding.concurrency.initial_limit", 200)
+	v.SetDefault("load_shedding.concurrency.min_limit", 10)
+	v.SetDefault("load_shedding.concurrency.max_limit", 1000)
+	v.SetDefault("load_shedding.concurrency.backoff_ratio", 0.9)
+	v.SetDefault("load_shedding.concurrency.target_queue_delay_ms", 10)
+	v.SetDefault("load_shedding.concurrency.max_queue_wait_ms", 50)
+	v.SetDefault("load_shedding.concurrency.max_queue_size", 1000)
+	v.SetDefault("load_shedding.concurrency.retry_after_seconds", 1)
+	v.SetDefault("ivt.enabled", false)
+	v.SetDefault("ivt.action", IVTActionTag)
+	v.SetDefault("ivt.bots.builtin", true)
+	v.SetDefault("ivt.bots.list_file", "")
+	v.SetDefault("ivt.bots.exclude_file", "")
+	v.SetDefault("ivt.blocked_ips", []string{})
+	v.SetDefault("ivt.blocked_ips_file", "")
+	v.SetDefault("ivt.datacenter_ips_file", "")
+	v.SetDefault("ivt.datacenter_ips_url", "")
+	v.SetDefault("ivt.datacenter_ips_refresh_minutes", 60)
+	v.SetDefault("ivt.blocked_ifas", []string{})
+	v.SetDefault("ivt.blocked_ifas_file", "")
+	v.SetDefault("response_signing.keys", map[string]string{})
+	v.SetDefault("geolocation.enabled", false)
+	v.SetDefault("geolocation.provider", GeoLocationProviderMaxMind)
+	v.SetDefault("geolocation.maxmind.database_path", "")
+	v.SetDefault("geolocation.maxmind.reload_interval_seconds", 300)
+	v.SetDefault("content_classification.enabled", false)
+	v.SetDefault("content_classification.provider", ContentClassificationProviderHTTP)
+	v.SetDefault("content_classification.http.endpoint", "")
+	v.SetDefault("content_classification.timeout_ms", 50)
+	v.SetDefault("content_classification.min_remaining_ms", 300)
+	v.SetDefault("content_classification.cache_size", 100000)
+	v.SetDefault("content_classification.cache_ttl_seconds", 3600)
+	v.SetDefault("content_classification.data_name", "prebid-server")
+	v.SetDefault("pii_scanner.mode", PIIScannerModeOff)
+	v.SetDefault("pii_scanner.sample_rate", 0.01)
+	v.SetDefault("pii_scanner.emails", true)
+	v.SetDefault("pii_scanner.precise_geo", true)
+	v.SetDefault("pii_scanner.user_ids", true)
+	v.SetDefault("mobile_sdk.interstitial.enabled", false)
+	v.SetDefault("mobile_sdk.interstitial.min_version", "")
+	v.SetDefault("mobile_sdk.interstitial.min_size_perc", 50)
+	v.SetDefault("mobile_sdk.att.enabled", false)
+	v.SetDefault("mobile_sdk.att.min_version", "")
+	v.SetDefault("mobile_sdk.multiformat.enabled", false)
+	v.SetDefault("mobile_sdk.multiformat.min_version", "")
+	v.SetDefault("rate_limiting.backend", RateLimitingBackendLocal)
+	v.SetDefault("rate_limiting.redis.addrs", []string{})
+	v.SetDefault("rate_limiting.redis.cluster", false)
+	v.SetDefault("rate_limiting.redis.username", "")
+	v.SetDefault("rate_limiting.redis.password", "")
+	v.SetDefault("rate_limiting.redis.db", 0)
+	v.SetDefault("rate_limiting.redis.pool_size", 0)
+	v.SetDefault("rate_limiting.redis.tls.enabled", false)
+	v.SetDefault("rate_limiting.redis.tls.root_cert", "")
+	v.SetDefault("rate_limiting.redis.tls.client_cert", "")
+	v.SetDefault("rate_limiting.redis.tls.client_key", "")
+	v.SetDefault("rate_limiting.redis.key_prefix", "pbs:")
+	v.SetDefault("rate_limiting.redis.timeout_ms", 20)
+	v.SetDefault("in_process_bidders.enabled", false)
+	v.SetDefault("in_process_bidders.bidders", []string{})
+	v.SetDefault("in_process_bidders.bid_price", 1.0)
+	v.SetDefault("auction_simulation.enabled", false)
+	v.SetDefault("test_traffic.enabled", false)
+	v.SetDefault("test_traffic.synthesize_bids", false)
+	v.SetDefault("test_traffic.synthesized_bid_price", 1.0)
+	v.SetDefault("request_validation_endpoint.enabled", false)
+	v.SetDefault("openrtb3.enabled", false)
+	v.SetDefault("deterministic_ids.enabled", false)
+	v.SetDefault("deterministic_ids.seed", 0)
+	v.SetDefault("latency_budget.enabled", false)
+	v.SetDefault("latency_budget.stored_requests", 0.1)
+	v.SetDefault("latency_budget.hooks", 0.05)
+	v.SetDefault("latency_budget.cache_write", 0.1)
+	v.SetDefault("auction_recording.enabled", false)
+	v.SetDefault("auction_recording.sample_rate", 0.001)
+	v.SetDefault("auction_recording.accounts", []string{})
+	v.SetDefault("auction_recording.queue_size", 100)
+	v.SetDefault("auction_recording.sink.type", "file")
+	v.SetDefault("auction_recording.sink.file.path", "")
+	v.SetDefault("auction_recording.replay_enabled", false)
+	v.SetDefault("traffic_shadowing.enabled", false)
+	v.SetDefault("traffic_shadowing.url", "")
+	v.SetDefault("traffic_shadowing.sample_rate", 0.01)
+	v.SetDefault("traffic_shadowing.timeout_ms", 1000)
+	v.SetDefault("traffic_shadowing.workers", 10)
+	v.SetDefault("traffic_shadowing.queue_size", 100)
+	v.SetDefault("webhooks.enabled", false)
+	v.SetDefault("webhooks.timeout_ms", 2000)
+	v.SetDefault("webhooks.max_attempts", 5)
+	v.SetDefault("webhooks.retry_delay_ms", 1000)
+	v.SetDefault("webhooks.queue_size", 1000)
+	v.SetDefault("auction_event_webhooks.enabled", false)
+	v.SetDefault("auction_event_webhooks.timeout_ms", 2000)
+	v.SetDefault("auction_event_webhooks.max_attempts", 5)
+	v.SetDefault("auction_event_webhooks.retry_delay_ms", 1000)
+	v.SetDefault("auction_event_webhooks.batch_size", 100)
+	v.SetDefault("auction_event_webhooks.batch_interval_ms", 1000)
+	v.SetDefault("auction_event_webhooks.queue_size", 10000)
+	v.SetDefault("auction_event_webhooks.workers", 4)
+	v.SetDefault("bid_landscape.enabled", false)
+	v.SetDefault("bid_landscape.window_minutes", 60)
+	v.SetDefault("bid_landscape.price_buckets", []float64{0.1, 0.5, 1, 2, 5, 10, 20})
+	v.SetDefault("bid_landscape.max_keys", 10000)
+	v.SetDefault("bid_landscape.tokens", []string{})
+	v.SetDefault("auction_quality.enabled", false)
+	v.SetDefault("auction_quality.window_minutes", 60)
+	v.SetDefault("auction_quality.max_accounts", 10000)
+	v.SetDefault("metering.enabled", false)
+	v.SetDefault("metering.export_interval_seconds", 60)
+	v.SetDefault("metering.sink_url", "")
+	v.SetDefault("metering.sink_timeout_ms", 5000)
+	v.SetDefault("response_overrides.enabled", false)
+	v.SetDefault("response_overrides.max_ttl_seconds", 3600)
+	v.SetDefault("response_overrides.max_overrides", 100)
+	v.SetDefault("response_overrides.tokens", []string{})
+	v.SetDefault("stored_request_versions.enabled", false)
+	v.SetDefault("stored_request_versions.max_versions", 5)
+	v.SetDefault("stored_request_versions.max_pins", 100)
+	v.SetDefault("stored_request_versions.tokens", []string{})
+	v.SetDefault("bidder_maintenance.windows", []BidderMaintenanceWindow{})
+	v.SetDefault("bidder_maintenance.kill_switches.enabled", false)
+	v.SetDefault("bidder_maintenance.kill_switches.tokens", []string{})
+	v.SetDefault("adaptive_bidder_tmax.enabled", false)
+	v.SetDefault("adaptive_bidder_tmax.percentile", 95)
+	v.SetDefault("adaptive_bidder_tmax.samples", 1000)
+	v.SetDefault("adaptive_bidder_tmax.min_samples", 100)
+	v.SetDefault("adaptive_bidder_tmax.headroom_percent", 20)
+	v.SetDefault("adaptive_bidder_tmax.min_tmax_ms", 100)
+	v.SetDefault("adaptive_bidder_tmax.measure_percent", 5)
+	v.SetDefault("logging.format", "json")
+	v.SetDefault("logging.level", "info")
+	v.SetDefault("logging.redact_fields", []string{"ip", "ipv6", "ifa", "consent", "gdpr_consent", "gpp"})
+	v.SetDefault("json.engine", jsonutil.EngineJsoniter)
+	v.SetDefault("bidder_timeout_notifications.enabled", false)
+	v.SetDefault("bidder_timeout_notifications.max_per_second", 100)
+	v.SetDefault("bidder_timeout_notifications.timeout_ms", 200)
+	v.SetDefault("request_decoding.imp_precheck", false)
+	v.SetDefault("request_decoding.max_imps", 0)
+	v.SetDefault("request_normalization.enabled", false)
+	v.SetDefault("request_normalization.stringified_numbers", true)
+	v.SetDefault("request_normalization.enum_case", true)
+	v.SetDefault("request_normalization.consent_location", true)
+	v.SetDefault("fault_injection.enabled", false)
+	v.SetDefault("fault_injection.bidders.latency_rate", 0)
+	v.SetDefault("fault_injection.bidders.latency_ms", 0)
+	v.SetDefault("fault_injection.bidders.error_rate", 0)
+	v.SetDefault("fault_injection.bidders.malformed_rate", 0)
+	v.SetDefault("fault_injection.stored_requests.latency_rate", 0)
+	v.SetDefault("fault_injection.stored_requests.latency_ms", 0)
+	v.SetDefault("fault_injection.stored_requests.error_rate", 0)
+	v.SetDefault("fault_injection.stored_requests.malformed_rate", 0)
+	v.SetDefault("fault_injection.cache.latency_rate", 0)
+	v.SetDefault("fault_injection.cache.latency_ms", 0)
+	v.SetDefault("fault_injection.cache.error_rate", 0)
+	v.SetDefault("fault_injection.cache.malformed_rate", 0)
 	v.SetDefault("analytics.file.filename", "")
 	v.SetDefault("analytics.pubstack.endpoint", "https://s2s.pbstck.com/v1")
 	v.SetDefault("analytics.pubstack.scopeid", "change-me")
@@ -1085,6 +2993,15 @@ func SetupViper(v *viper.Viper, filename string, bidderInfos BidderInfos) {
 	v.SetDefault("analytics.agma.buffers.count", 100)
 	v.SetDefault("analytics.agma.buffers.timeout", "15m")
 	v.SetDefault("analytics.agma.accounts", []AgmaAnalyticsAccount{})
+	v.SetDefault("analytics.hub.forward.enabled", false)
+	v.SetDefault("analytics.hub.forward.url", "")
+	v.SetDefault("analytics.hub.forward.token", "")
+	v.SetDefault("analytics.hub.forward.buffers.size", "2MB")
+	v.SetDefault("analytics.hub.forward.buffers.count", 100)
+	v.SetDefault("analytics.hub.forward.buffers.timeout", "10s")
+	v.SetDefault("analytics.hub.ingest.enabled", false)
+	v.SetDefault("analytics.hub.ingest.tokens", []string{})
+	v.SetDefault("analytics.hub.ingest.max_batch_size", 64<<20)
 	v.SetDefault("amp_timeout_adjustment_ms", 0)
 	v.BindEnv("gdpr.default_value")
 	v.SetDefault("gdpr.enabled", true)
@@ -1123,14 +3040,71 @@ func SetupViper(v *viper.Viper, filename string, bidderInfos BidderInfos) {
 	v.SetDefault("currency_converter.fetch_url", "https://cdn.jsdelivr.net/gh/prebid/currency-file@1/latest.json")
 	v.SetDefault("currency_converter.fetch_interval_seconds", 1800) // fetch currency rates every 30 minutes
 	v.SetDefault("currency_converter.stale_rates_seconds", 0)
+	v.SetDefault("currency_converter.source_max_failures", 3)
+	v.SetDefault("currency_converter.source_retry_seconds", 300)
+	v.SetDefault("currency_converter.stale_alarm_seconds", 0)
 	v.SetDefault("default_request.type", "")
 	v.SetDefault("default_request.file.name", "")
 	v.SetDefault("default_request.alias_info", false)
+	v.SetDefault("default_request.merge.arrays", "replace")
+	v.SetDefault("default_request.merge.ext", "merge")
+	v.SetDefault("default_request.merge.enforce", []string{})
 	v.SetDefault("blacklisted_apps", []string{""})
 	v.SetDefault("blacklisted_accts", []string{""})
 	v.SetDefault("account_required", false)
 	v.SetDefault("account_defaults.disabled", false)
 	v.SetDefault("account_defaults.debug_allow", true)
+	v.SetDefault("account_defaults.debug_token.keys", []string{})
+	v.SetDefault("account_defaults.debug_token.max_ttl_seconds", 3600)
+	v.SetDefault("account_defaults.debug_access.allowed_ips", []string{})
+	v.SetDefault("account_defaults.debug_access.allowed_tokens", []string{})
+	v.SetDefault("account_defaults.debug_access.analytics_sample_rate", 0.0)
+	v.SetDefault("account_defaults.adscert.sign", false)
+	v.SetDefault("account_defaults.adscert.verify", "off")
+	v.SetDefault("account_defaults.schain.asi", "")
+	v.SetDefault("account_defaults.schain.sid", "")
+	v.SetDefault("account_defaults.origin.verify", "off")
+	v.SetDefault("account_defaults.origin.allowed_domains", []string{})
+	v.SetDefault("account_defaults.request_limits.max_imps", 0)
+	v.SetDefault("account_defaults.request_limits.max_eids", 0)
+	v.SetDefault("account_defaults.request_limits.max_data_segments", 0)
+	v.SetDefault("account_defaults.request_limits.max_request_size", 0)
+	v.SetDefault("account_defaults.api_keys.required", false)
+	v.SetDefault("account_defaults.response_signing.enabled", false)
+	v.SetDefault("account_defaults.response_signing.key_id", "")
+	v.SetDefault("account_defaults.deal_pacing.window_seconds", 3600)
+	v.SetDefault("account_defaults.creative_trackers.enabled", false)
+	v.SetDefault("account_defaults.creative_trackers.events", []string{"imp"})
+	v.SetDefault("account_defaults.creative_trackers.urls", []string{})
+	v.SetDefault("account_defaults.native_trackers.enabled", false)
+	v.SetDefault("account_defaults.native_trackers.imp_trackers", []string{})
+	v.SetDefault("account_defaults.native_trackers.click_trackers", []string{})
+	v.SetDefault("account_defaults.line_items.enabled", false)
+	v.SetDefault("account_defaults.price_encryption.enabled", false)
+	v.SetDefault("account_defaults.price_encryption.encryption_key", "")
+	v.SetDefault("account_defaults.price_encryption.integrity_key", "")
+	v.SetDefault("account_defaults.usage_quota.period", UsageQuotaPeriodDay)
+	v.SetDefault("account_defaults.usage_quota.max_auctions", 0)
+	v.SetDefault("account_defaults.usage_quota.max_imps", 0)
+	v.SetDefault("account_defaults.usage_quota.max_bidder_calls", 0)
+	v.SetDefault("account_defaults.client_hints.enabled", false)
+	v.SetDefault("account_defaults.size_resolution.enabled", false)
+	v.SetDefault("account_defaults.size_resolution.max_formats", 10)
+	v.SetDefault("account_defaults.dynamic_tmax.enabled", false)
+	v.SetDefault("account_defaults.content_classification.enabled", false)
+	v.SetDefault("account_defaults.response_ext.enabled", false)
+	v.SetDefault("account_defaults.response_ext.include", []string{})
+	v.SetDefault("account_defaults.stored_bid_responses.enabled", false)
+	v.SetDefault("account_defaults.stored_bid_responses.debug_token_only", false)
+	v.SetDefault("account_defaults.dynamic_tmax.min_ms", 0)
+	v.SetDefault("account_defaults.dynamic_tmax.max_ms", 0)
+	v.SetDefault("account_defaults.currency.selection", CurrencySelectionFirst)
+	v.SetDefault("account_defaults.event_webhook.enabled", false)
+	v.SetDefault("account_defaults.event_webhook.url", "")
+	v.SetDefault("account_defaults.event_webhook.secret", "")
+	v.SetDefault("account_defaults.event_webhook.events", []string{})
+	v.SetDefault("account_defaults.ivt.action", "")
+	v.SetDefault("account_defaults.multiformat.preferred_media_types", []string{})
 	v.SetDefault("account_defaults.price_floors.enabled", false)
 	v.SetDefault("account_defaults.price_floors.enforce_floors_rate", 100)
 	v.SetDefault("account_defaults.price_floors.adjust_for_bid_adjustment", true)
@@ -1235,6 +3209,7 @@ func SetupViper(v *viper.Viper, filename string, bidderInfos BidderInfos) {
 	v.SetDefault("gdpr.tcf2.special_feature1.enforce", true)
 	v.SetDefault("gdpr.tcf2.special_feature1.vendor_exceptions", []openrtb_ext.BidderName{})
 	v.SetDefault("price_floors.enabled", false)
+	v.SetDefault("price_floors.currency_conversion", false)
 
 	// Defaults for account_defaults.events.default_url
 	v.SetDefault("account_defaults.events.default_url", "https://PBS_HOST/event?t=##PBS-EVENTTYPE##&vtype=##PBS-VASTEVENT##&b=##PBS-BIDID##&f=i&a=##PBS-ACCOUNTID##&ts=##PBS-TIMESTAMP##&bidder=##PBS-BIDDER##&int=##PBS-INTEGRATION##&mt=##PBS-MEDIATYPE##&ch=##PBS-CHANNEL##&aid=##PBS-AUCTIONID##&l=##PBS-LINEID##")
@@ -1249,6 +3224,7 @@ func SetupViper(v *viper.Viper, filename string, bidderInfos BidderInfos) {
 	v.SetDefault("experiment.adscert.remote.signing_timeout_ms", 5)
 
 	v.SetDefault("hooks.enabled", false)
+	v.SetDefault("strict_deprecated_settings", false)
 
 	for bidderName := range bidderInfos {
 		setBidderDefaults(v, strings.ToLower(bidderName))
@@ -1284,6 +3260,7 @@ func setBidderDefaults(v *viper.Viper, bidder string) {
 	v.BindEnv(adapterCfgPrefix + ".endpointCompression")
 	v.BindEnv(adapterCfgPrefix + ".openrtb.version")
 	v.BindEnv(adapterCfgPrefix + ".openrtb.gpp-supported")
+	v.BindEnv(adapterCfgPrefix + ".maxResponseSize")
 
 	v.BindEnv(adapterCfgPrefix + ".usersync.key")
 	v.BindEnv(adapterCfgPrefix + ".usersync.default")
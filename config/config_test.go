@@ -151,6 +151,9 @@ func TestDefaults(t *testing.T) {
 	cmpInts(t, "host_cookie.max_cookie_size_bytes", 0, cfg.HostCookie.MaxCookieSizeBytes)
 	cmpInts(t, "currency_converter.fetch_interval_seconds", 1800, cfg.CurrencyConverter.FetchIntervalSeconds)
 	cmpStrings(t, "currency_converter.fetch_url", "https://cdn.jsdelivr.net/gh/prebid/currency-file@1/latest.json", cfg.CurrencyConverter.FetchURL)
+	cmpInts(t, "currency_converter.source_max_failures", 3, cfg.CurrencyConverter.SourceMaxFailures)
+	cmpInts(t, "currency_converter.source_retry_seconds", 300, cfg.CurrencyConverter.SourceRetrySeconds)
+	cmpInts(t, "currency_converter.stale_alarm_seconds", 0, cfg.CurrencyConverter.StaleAlarmSeconds)
 	cmpBools(t, "account_required", false, cfg.AccountRequired)
 	cmpInts(t, "metrics.influxdb.collection_rate_seconds", 20, cfg.Metrics.Influxdb.MetricSendInterval)
 	cmpBools(t, "account_adapter_details", false, cfg.Metrics.Disabled.AccountAdapterDetails)
@@ -161,6 +164,8 @@ func TestDefaults(t *testing.T) {
 	cmpStrings(t, "certificates_file", "", cfg.PemCertsFile)
 	cmpBools(t, "stored_requests.filesystem.enabled", false, cfg.StoredRequests.Files.Enabled)
 	cmpStrings(t, "stored_requests.filesystem.directorypath", "./stored_requests/data/by_id", cfg.StoredRequests.Files.Path)
+	cmpBools(t, "mobile_sdk.interstitial.enabled", false, cfg.MobileSDK.Interstitial.Enabled)
+	cmpInts(t, "mobile_sdk.interstitial.min_size_perc", 50, int(cfg.MobileSDK.Interstitial.MinSizePerc))
 	cmpBools(t, "auto_gen_source_tid", true, cfg.AutoGenSourceTID)
 	cmpBools(t, "generate_bid_id", false, cfg.GenerateBidID)
 	cmpStrings(t, "experiment.adscert.mode", "off", cfg.Experiment.AdCerts.Mode)
@@ -207,6 +212,7 @@ func TestDefaults(t *testing.T) {
 	cmpInts(t, "account_defaults.privacy.privacysandbox.cookiedeprecation.ttl_sec", 604800, cfg.AccountDefaults.Privacy.PrivacySandbox.CookieDeprecation.TTLSec)
 
 	cmpBools(t, "account_defaults.events.enabled", false, cfg.AccountDefaults.Events.Enabled)
+	cmpStrings(t, "account_defaults.currency.selection", "first", cfg.AccountDefaults.Currency.Selection)
 
 	cmpBools(t, "hooks.enabled", false, cfg.Hooks.Enabled)
 	cmpStrings(t, "validations.banner_creative_max_size", "skip", cfg.Validations.BannerCreativeMaxSize)
@@ -341,6 +347,11 @@ func TestDefaults(t *testing.T) {
 
 // When adding a new field, make sure the indentations are spaces not tabs otherwise read config may fail to parse the new field value.
 var fullConfig = []byte(`
+bidder_maintenance:
+  windows:
+    - bidder: appnexus
+      start: "2024-05-01T02:00:00Z"
+      end: "2024-05-01T04:00:00Z"
 gdpr:
   host_vendor_id: 15
   default_value: "1"
@@ -428,6 +439,15 @@ http_client_cache:
 currency_converter:
   fetch_url: https://currency.prebid.org
   fetch_interval_seconds: 1800
+  rates_sources:
+    - name: primary
+      url: https://rates.prebid.org/latest.json
+      priority: 1
+      timeout_ms: 2000
+    - url: https://currency.prebid.org
+      priority: 2
+  source_max_failures: 5
+  stale_alarm_seconds: 7200
 recaptcha_secret: asdfasdfasdfasdf
 metrics:
   influxdb:
@@ -491,6 +511,11 @@ price_floors:
 account_defaults:
     events:
         enabled: true
+    currency:
+        rates:
+            USD:
+                EUR: 0.9
+        selection: most_bids
     price_floors:
         enabled: true
         enforce_floors_rate: 50
@@ -611,6 +636,7 @@ func TestFullConfig(t *testing.T) {
 	cmpStrings(t, "external_cache.host", "www.externalprebidcache.net", cfg.ExtCacheURL.Host)
 	cmpStrings(t, "external_cache.path", "/endpoints/cache", cfg.ExtCacheURL.Path)
 	cmpInts(t, "http_client.max_connections_per_host", 10, cfg.Client.MaxConnsPerHost)
+	assert.Equal(t, []BidderMaintenanceWindow{{Bidder: "appnexus", Start: "2024-05-01T02:00:00Z", End: "2024-05-01T04:00:00Z"}}, cfg.BidderMaintenance.Windows, "bidder_maintenance.windows")
 	cmpInts(t, "http_client.max_idle_connections", 500, cfg.Client.MaxIdleConns)
 	cmpInts(t, "http_client.max_idle_connections_per_host", 20, cfg.Client.MaxIdleConnsPerHost)
 	cmpInts(t, "http_client.idle_connection_timeout_seconds", 30, cfg.Client.IdleConnTimeout)
@@ -814,9 +840,18 @@ func TestFullConfig(t *testing.T) {
 	}
 	assert.Equal(t, expectedTCF2, cfg.GDPR.TCF2, "gdpr.tcf2")
 	assert.Equal(t, expectedBidAdjustments, cfg.AccountDefaults.BidAdjustments)
+	assert.Equal(t, map[string]map[string]float64{"USD": {"EUR": 0.9}}, cfg.AccountDefaults.Currency.Rates, "account_defaults.currency.rates")
+	cmpStrings(t, "account_defaults.currency.selection", "most_bids", cfg.AccountDefaults.Currency.Selection)
 
 	cmpStrings(t, "currency_converter.fetch_url", "https://currency.prebid.org", cfg.CurrencyConverter.FetchURL)
 	cmpInts(t, "currency_converter.fetch_interval_seconds", 1800, cfg.CurrencyConverter.FetchIntervalSeconds)
+	assert.Equal(t, []CurrencyRatesSource{
+		{Name: "primary", URL: "https://rates.prebid.org/latest.json", Priority: 1, TimeoutMS: 2000},
+		{URL: "https://currency.prebid.org", Priority: 2},
+	}, cfg.CurrencyConverter.RatesSources, "currency_converter.rates_sources")
+	cmpInts(t, "currency_converter.source_max_failures", 5, cfg.CurrencyConverter.SourceMaxFailures)
+	cmpInts(t, "currency_converter.source_retry_seconds", 300, cfg.CurrencyConverter.SourceRetrySeconds)
+	cmpInts(t, "currency_converter.stale_alarm_seconds", 7200, cfg.CurrencyConverter.StaleAlarmSeconds)
 	cmpStrings(t, "recaptcha_secret", "asdfasdfasdfasdf", cfg.RecaptchaSecret)
 	cmpStrings(t, "metrics.influxdb.host", "upstream:8232", cfg.Metrics.Influxdb.Host)
 	cmpStrings(t, "metrics.influxdb.database", "metricsdb", cfg.Metrics.Influxdb.Database)
@@ -1151,6 +1186,1215 @@ func TestNegativeRequestSize(t *testing.T) {
 	assertOneError(t, cfg.validate(v), "cfg.max_request_size must be >= 0. Got -1")
 }
 
+func TestAccountDefaultsParent(t *testing.T) {
+	cfg, v := newDefaultConfig(t)
+	cfg.AccountDefaults.Parent = "parent"
+	assertOneError(t, cfg.validate(v), "account_defaults.parent cannot be set. Parent accounts are only supported on host-defined accounts")
+}
+
+func TestBidderRequestPoolValidate(t *testing.T) {
+	cfg, v := newDefaultConfig(t)
+	cfg.BidderRequestPool = BidderRequestPool{Enabled: true, Workers: 0, QueueSize: -1}
+	errs := cfg.validate(v)
+	assert.ElementsMatch(t, []error{
+		errors.New("bidder_request_pool.workers must be > 0. Got 0"),
+		errors.New("bidder_request_pool.queue_size must be >= 0. Got -1"),
+	}, errs)
+}
+
+func TestBidderConnectionWarmupValidate(t *testing.T) {
+	cfg := BidderConnectionWarmup{Enabled: true, Bidders: []string{"bidder1", "unknown"}, Connections: 0, KeepAliveIntervalSeconds: -1, TimeoutMs: 0}
+	errs := cfg.validate(BidderInfos{"bidder1": BidderInfo{}}, HTTPClient{IdleConnTimeout: 60}, nil)
+	assert.ElementsMatch(t, []error{
+		errors.New("bidder_connection_warmup.bidders contains unknown bidder: unknown"),
+		errors.New("bidder_connection_warmup.connections must be > 0. Got 0"),
+		errors.New("bidder_connection_warmup.keep_alive_interval_seconds must be >= 0. Got -1"),
+		errors.New("bidder_connection_warmup.timeout_ms must be > 0. Got 0"),
+	}, errs)
+
+	cfg.Enabled = false
+	assert.Empty(t, cfg.validate(BidderInfos{}, HTTPClient{}, nil))
+}
+
+func TestInProcessBiddersValidate(t *testing.T) {
+	cfg := InProcessBidders{Enabled: true, Bidders: []string{"bidder1", "unknown"}, BidPrice: 0}
+	errs := cfg.validate(BidderInfos{"bidder1": BidderInfo{}}, nil)
+	assert.ElementsMatch(t, []error{
+		errors.New("in_process_bidders.bidders contains unknown bidder: unknown"),
+		errors.New("in_process_bidders.bid_price must be > 0. Got 0"),
+	}, errs)
+
+	cfg = InProcessBidders{Enabled: true, BidPrice: 1}
+	assert.Equal(t, []error{errors.New("in_process_bidders.bidders must not be empty")}, cfg.validate(BidderInfos{}, nil))
+
+	cfg = InProcessBidders{Enabled: true, Bidders: []string{"bidder1"}, BidPrice: 1}
+	assert.Empty(t, cfg.validate(BidderInfos{"bidder1": BidderInfo{}}, nil))
+
+	cfg.Enabled = false
+	cfg.BidPrice = -1
+	assert.Empty(t, cfg.validate(BidderInfos{}, nil))
+}
+
+func TestAuctionRecordingValidate(t *testing.T) {
+	testCases := []struct {
+		name         string
+		cfg          AuctionRecording
+		expectedErrs []error
+	}{
+		{
+			name: "disabled",
+			cfg:  AuctionRecording{Enabled: false, SampleRate: 2, ReplayEnabled: true},
+		},
+		{
+			name: "valid",
+			cfg:  AuctionRecording{Enabled: true, SampleRate: 1, QueueSize: 10, Sink: AuctionRecordingSink{Type: "file", File: AuctionRecordingFileSink{Path: "recordings.jsonl"}}},
+		},
+		{
+			name: "invalid",
+			cfg:  AuctionRecording{Enabled: true, SampleRate: 0, QueueSize: 0, Sink: AuctionRecordingSink{Type: "file"}},
+			expectedErrs: []error{
+				errors.New("auction_recording.sample_rate must be in the range (0, 1]. Got 0"),
+				errors.New("auction_recording.queue_size must be > 0. Got 0"),
+				errors.New("auction_recording.sink.file.path must be set when auction_recording.sink.type is file"),
+			},
+		},
+		{
+			name: "unknown-sink",
+			cfg:  AuctionRecording{Enabled: true, SampleRate: 0.5, QueueSize: 10, Sink: AuctionRecordingSink{Type: "kafka"}},
+			expectedErrs: []error{
+				errors.New("auction_recording.sink.type must be file. Got kafka"),
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			assert.ElementsMatch(t, test.expectedErrs, test.cfg.validate(nil))
+		})
+	}
+}
+
+func TestTrafficShadowingValidate(t *testing.T) {
+	testCases := []struct {
+		name         string
+		cfg          TrafficShadowing
+		expectedErrs []error
+	}{
+		{
+			name: "disabled",
+			cfg:  TrafficShadowing{Enabled: false, URL: "not a url"},
+		},
+		{
+			name: "valid",
+			cfg:  TrafficShadowing{Enabled: true, URL: "http://shadow.prebid.org:8000", SampleRate: 0.1, TimeoutMs: 500, Workers: 2, QueueSize: 0},
+		},
+		{
+			name: "invalid",
+			cfg:  TrafficShadowing{Enabled: true, URL: "shadow.prebid.org", SampleRate: 1.5, TimeoutMs: 0, Workers: 0, QueueSize: -1},
+			expectedErrs: []error{
+				errors.New("traffic_shadowing.url must be an http or https URL. Got shadow.prebid.org"),
+				errors.New("traffic_shadowing.sample_rate must be in the range (0, 1]. Got 1.5"),
+				errors.New("traffic_shadowing.timeout_ms must be > 0. Got 0"),
+				errors.New("traffic_shadowing.workers must be > 0. Got 0"),
+				errors.New("traffic_shadowing.queue_size must be >= 0. Got -1"),
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			assert.ElementsMatch(t, test.expectedErrs, test.cfg.validate(nil))
+		})
+	}
+}
+
+func TestWebhooksValidate(t *testing.T) {
+	testCases := []struct {
+		name         string
+		cfg          Webhooks
+		expectedErrs []error
+	}{
+		{
+			name: "disabled",
+			cfg:  Webhooks{Enabled: false},
+		},
+		{
+			name: "valid",
+			cfg: Webhooks{
+				Enabled:      true,
+				Endpoints:    []WebhookEndpoint{{URL: "https://hooks.prebid.org/pbs", Secret: "secret", Events: []string{"accounts.saved", "stored_requests.invalidated"}}},
+				TimeoutMs:    2000,
+				MaxAttempts:  5,
+				RetryDelayMs: 1000,
+				QueueSize:    1000,
+			},
+		},
+		{
+			name: "no endpoints",
+			cfg:  Webhooks{Enabled: true, TimeoutMs: 2000, MaxAttempts: 1},
+			expectedErrs: []error{
+				errors.New("webhooks.endpoints must have at least one endpoint"),
+			},
+		},
+		{
+			name: "invalid",
+			cfg: Webhooks{
+				Enabled:      true,
+				Endpoints:    []WebhookEndpoint{{URL: "hooks.prebid.org", Events: []string{"accounts.deleted"}}},
+				RetryDelayMs: -1,
+				QueueSize:    -1,
+			},
+			expectedErrs: []error{
+				errors.New("webhooks.endpoints[0].url must be an http or https URL. Got hooks.prebid.org"),
+				errors.New("webhooks.endpoints[0].secret is required"),
+				errors.New("webhooks.endpoints[0].events has an unknown event type accounts.deleted"),
+				errors.New("webhooks.timeout_ms must be > 0. Got 0"),
+				errors.New("webhooks.max_attempts must be > 0. Got 0"),
+				errors.New("webhooks.retry_delay_ms must be >= 0. Got -1"),
+				errors.New("webhooks.queue_size must be >= 0. Got -1"),
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			assert.ElementsMatch(t, test.expectedErrs, test.cfg.validate(nil))
+		})
+	}
+}
+
+func TestAuctionEventWebhooksValidate(t *testing.T) {
+	testCases := []struct {
+		name         string
+		cfg          AuctionEventWebhooks
+		expectedErrs []error
+	}{
+		{
+			name: "disabled",
+			cfg:  AuctionEventWebhooks{Enabled: false},
+		},
+		{
+			name: "valid",
+			cfg: AuctionEventWebhooks{
+				Enabled:         true,
+				TimeoutMs:       2000,
+				MaxAttempts:     5,
+				RetryDelayMs:    1000,
+				BatchSize:       100,
+				BatchIntervalMs: 1000,
+				QueueSize:       10000,
+				Workers:         4,
+			},
+		},
+		{
+			name: "invalid",
+			cfg: AuctionEventWebhooks{
+				Enabled:      true,
+				RetryDelayMs: -1,
+				QueueSize:    -1,
+			},
+			expectedErrs: []error{
+				errors.New("auction_event_webhooks.timeout_ms must be > 0. Got 0"),
+				errors.New("auction_event_webhooks.max_attempts must be > 0. Got 0"),
+				errors.New("auction_event_webhooks.retry_delay_ms must be >= 0. Got -1"),
+				errors.New("auction_event_webhooks.batch_size must be > 0. Got 0"),
+				errors.New("auction_event_webhooks.batch_interval_ms must be > 0. Got 0"),
+				errors.New("auction_event_webhooks.queue_size must be >= 0. Got -1"),
+				errors.New("auction_event_webhooks.workers must be > 0. Got 0"),
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			assert.ElementsMatch(t, test.expectedErrs, test.cfg.validate(nil))
+		})
+	}
+}
+
+func TestBidLandscapeValidate(t *testing.T) {
+	testCases := []struct {
+		name         string
+		cfg          BidLandscape
+		expectedErrs []error
+	}{
+		{
+			name: "disabled",
+			cfg:  BidLandscape{Enabled: false},
+		},
+		{
+			name: "valid",
+			cfg:  BidLandscape{Enabled: true, WindowMinutes: 60, PriceBuckets: []float64{0.5, 1, 5}, MaxKeys: 100, Tokens: []string{"token"}},
+		},
+		{
+			name: "invalid",
+			cfg:  BidLandscape{Enabled: true, PriceBuckets: []float64{1, 0.5}},
+			expectedErrs: []error{
+				errors.New("bid_landscape.window_minutes must be > 0. Got 0"),
+				errors.New("bid_landscape.price_buckets must be positive and in increasing order"),
+				errors.New("bid_landscape.max_keys must be > 0. Got 0"),
+				errors.New("bid_landscape.tokens must have at least one token"),
+			},
+		},
+		{
+			name: "empty token",
+			cfg:  BidLandscape{Enabled: true, WindowMinutes: 60, MaxKeys: 100, Tokens: []string{""}},
+			expectedErrs: []error{
+				errors.New("bid_landscape.tokens[0] must not be empty"),
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			assert.ElementsMatch(t, test.expectedErrs, test.cfg.validate(nil))
+		})
+	}
+}
+
+func TestAuctionQualityValidate(t *testing.T) {
+	testCases := []struct {
+		name         string
+		cfg          AuctionQuality
+		expectedErrs []error
+	}{
+		{
+			name: "disabled",
+			cfg:  AuctionQuality{Enabled: false},
+		},
+		{
+			name: "valid",
+			cfg:  AuctionQuality{Enabled: true, WindowMinutes: 60, MaxAccounts: 100},
+		},
+		{
+			name: "invalid",
+			cfg:  AuctionQuality{Enabled: true, WindowMinutes: -1},
+			expectedErrs: []error{
+				errors.New("auction_quality.window_minutes must be > 0. Got -1"),
+				errors.New("auction_quality.max_accounts must be > 0. Got 0"),
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			assert.ElementsMatch(t, test.expectedErrs, test.cfg.validate(nil))
+		})
+	}
+}
+
+func TestMeteringValidate(t *testing.T) {
+	testCases := []struct {
+		name         string
+		cfg          Metering
+		expectedErrs []error
+	}{
+		{
+			name: "disabled",
+			cfg:  Metering{Enabled: false, SinkURL: "invalid"},
+		},
+		{
+			name: "valid",
+			cfg:  Metering{Enabled: true, ExportIntervalSeconds: 60, SinkURL: "https://billing.prebid.org/usage", SinkTimeoutMS: 5000},
+		},
+		{
+			name: "invalid",
+			cfg:  Metering{Enabled: true, SinkURL: "billing.prebid.org/usage", SinkTimeoutMS: -1},
+			expectedErrs: []error{
+				errors.New("metering.export_interval_seconds must be > 0. Got 0"),
+				errors.New("metering.sink_url must be an http or https URL. Got billing.prebid.org/usage"),
+				errors.New("metering.sink_timeout_ms must be > 0. Got -1"),
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			assert.ElementsMatch(t, test.expectedErrs, test.cfg.validate(nil))
+		})
+	}
+}
+
+func TestResponseOverridesValidate(t *testing.T) {
+	testCases := []struct {
+		name         string
+		cfg          ResponseOverrides
+		expectedErrs []error
+	}{
+		{
+			name: "disabled",
+			cfg:  ResponseOverrides{Enabled: false},
+		},
+		{
+			name: "valid",
+			cfg:  ResponseOverrides{Enabled: true, MaxTTLSeconds: 3600, MaxOverrides: 100, Tokens: []string{"token"}},
+		},
+		{
+			name: "invalid",
+			cfg:  ResponseOverrides{Enabled: true, MaxTTLSeconds: -1, Tokens: []string{""}},
+			expectedErrs: []error{
+				errors.New("response_overrides.max_ttl_seconds must be > 0. Got -1"),
+				errors.New("response_overrides.max_overrides must be > 0. Got 0"),
+				errors.New("response_overrides.tokens[0] must not be empty"),
+			},
+		},
+		{
+			name: "no tokens",
+			cfg:  ResponseOverrides{Enabled: true, MaxTTLSeconds: 3600, MaxOverrides: 100},
+			expectedErrs: []error{
+				errors.New("response_overrides.tokens must have at least one token"),
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			assert.ElementsMatch(t, test.expectedErrs, test.cfg.validate(nil))
+		})
+	}
+}
+
+func TestStoredRequestVersionsValidate(t *testing.T) {
+	testCases := []struct {
+		name         string
+		cfg          StoredRequestVersions
+		expectedErrs []error
+	}{
+		{
+			name: "disabled",
+			cfg:  StoredRequestVersions{Enabled: false},
+		},
+		{
+			name: "valid",
+			cfg:  StoredRequestVersions{Enabled: true, MaxVersions: 5, MaxPins: 100, Tokens: []string{"token"}},
+		},
+		{
+			name: "invalid",
+			cfg:  StoredRequestVersions{Enabled: true, MaxVersions: 1, Tokens: []string{""}},
+			expectedErrs: []error{
+				errors.New("stored_request_versions.max_versions must be >= 2. Got 1"),
+				errors.New("stored_request_versions.max_pins must be > 0. Got 0"),
+				errors.New("stored_request_versions.tokens[0] must not be empty"),
+			},
+		},
+		{
+			name: "no tokens",
+			cfg:  StoredRequestVersions{Enabled: true, MaxVersions: 5, MaxPins: 100},
+			expectedErrs: []error{
+				errors.New("stored_request_versions.tokens must have at least one token"),
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			assert.ElementsMatch(t, test.expectedErrs, test.cfg.validate(nil))
+		})
+	}
+}
+
+func TestAdaptiveBidderTmaxValidate(t *testing.T) {
+	testCases := []struct {
+		name         string
+		cfg          AdaptiveBidderTmax
+		expectedErrs []error
+	}{
+		{
+			name: "disabled",
+			cfg:  AdaptiveBidderTmax{Enabled: false, Percentile: -1},
+		},
+		{
+			name: "valid",
+			cfg:  AdaptiveBidderTmax{Enabled: true, Percentile: 95, Samples: 1000, MinSamples: 100, HeadroomPercent: 20, MinTmaxMS: 100, MeasurePercent: 5},
+		},
+		{
+			name: "invalid",
+			cfg:  AdaptiveBidderTmax{Enabled: true, Percentile: 100, Samples: 10, MinSamples: 20, HeadroomPercent: -1, MeasurePercent: 101},
+			expectedErrs: []error{
+				errors.New("adaptive_bidder_tmax.percentile must be in the range (0, 100). Got 100.000000"),
+				errors.New("adaptive_bidder_tmax.min_samples must be > 0 and no more than samples. Got 20"),
+				errors.New("adaptive_bidder_tmax.headroom_percent must be >= 0. Got -1"),
+				errors.New("adaptive_bidder_tmax.min_tmax_ms must be > 0. Got 0"),
+				errors.New("adaptive_bidder_tmax.measure_percent must be between 0 and 100. Got 101"),
+			},
+		},
+		{
+			name: "no samples",
+			cfg:  AdaptiveBidderTmax{Enabled: true, Percentile: 95, MinSamples: 0, MinTmaxMS: 100},
+			expectedErrs: []error{
+				errors.New("adaptive_bidder_tmax.samples must be > 0. Got 0"),
+				errors.New("adaptive_bidder_tmax.min_samples must be > 0 and no more than samples. Got 0"),
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			assert.ElementsMatch(t, test.expectedErrs, test.cfg.validate(nil))
+		})
+	}
+}
+
+func TestBidderMaintenanceValidate(t *testing.T) {
+	window := BidderMaintenanceWindow{Bidder: "appnexus", Start: "2024-05-01T02:00:00Z", End: "2024-05-01T04:00:00Z"}
+
+	testCases := []struct {
+		name         string
+		cfg          BidderMaintenance
+		expectedErrs []error
+	}{
+		{
+			name: "empty",
+			cfg:  BidderMaintenance{},
+		},
+		{
+			name: "valid",
+			cfg:  BidderMaintenance{Windows: []BidderMaintenanceWindow{window}, KillSwitches: BidderKillSwitches{Enabled: true, Tokens: []string{"token"}}},
+		},
+		{
+			name: "kill switches disabled without tokens",
+			cfg:  BidderMaintenance{KillSwitches: BidderKillSwitches{Enabled: false}},
+		},
+		{
+			name: "invalid windows",
+			cfg: BidderMaintenance{Windows: []BidderMaintenanceWindow{
+				{Start: window.Start, End: window.End},
+				{Bidder: "appnexus", Start: "2024-05-01", End: window.End},
+				{Bidder: "appnexus", Start: window.End, End: window.Start},
+			}},
+			expectedErrs: []error{
+				errors.New("bidder_maintenance.windows[0].bidder is required"),
+				errors.New(`bidder_maintenance.windows[1] must have RFC 3339 start and end times: parsing time "2024-05-01" as "2006-01-02T15:04:05Z07:00": cannot parse "" as "T"`),
+				errors.New("bidder_maintenance.windows[2].end must be after its start. Got 2024-05-01T02:00:00Z"),
+			},
+		},
+		{
+			name: "invalid kill switches",
+			cfg:  BidderMaintenance{KillSwitches: BidderKillSwitches{Enabled: true, Tokens: []string{""}}},
+			expectedErrs: []error{
+				errors.New("bidder_maintenance.kill_switches.tokens[0] must not be empty"),
+			},
+		},
+		{
+			name: "kill switches without tokens",
+			cfg:  BidderMaintenance{KillSwitches: BidderKillSwitches{Enabled: true}},
+			expectedErrs: []error{
+				errors.New("bidder_maintenance.kill_switches.tokens must have at least one token"),
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			assert.ElementsMatch(t, test.expectedErrs, test.cfg.validate(nil))
+		})
+	}
+}
+
+func TestBidderMaintenanceWindowActive(t *testing.T) {
+	window := BidderMaintenanceWindow{Bidder: "appnexus", Start: "2024-05-01T02:00:00Z", End: "2024-05-01T06:00:00+02:00"}
+
+	testCases := []struct {
+		name     string
+		window   BidderMaintenanceWindow
+		now      time.Time
+		expected bool
+	}{
+		{
+			name:     "before",
+			window:   window,
+			now:      time.Date(2024, 5, 1, 1, 59, 59, 0, time.UTC),
+			expected: false,
+		},
+		{
+			name:     "start",
+			window:   window,
+			now:      time.Date(2024, 5, 1, 2, 0, 0, 0, time.UTC),
+			expected: true,
+		},
+		{
+			name:     "before end in another zone",
+			window:   window,
+			now:      time.Date(2024, 5, 1, 3, 59, 59, 0, time.UTC),
+			expected: true,
+		},
+		{
+			name:     "end",
+			window:   window,
+			now:      time.Date(2024, 5, 1, 4, 0, 0, 0, time.UTC),
+			expected: false,
+		},
+		{
+			name:     "unparseable",
+			window:   BidderMaintenanceWindow{Bidder: "appnexus", Start: "invalid", End: window.End},
+			now:      time.Date(2024, 5, 1, 3, 0, 0, 0, time.UTC),
+			expected: false,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, test.window.Active(test.now))
+		})
+	}
+}
+
+func TestAnalyticsHubValidate(t *testing.T) {
+	validForward := AnalyticsHubForward{Enabled: true, URL: "http://hub.internal:6060/analytics/ingest", Buffers: AnalyticsHubBuffer{EventCount: 100}}
+	validIngest := AnalyticsHubIngest{Enabled: true, Tokens: []string{"token"}, MaxBatchSize: 1024}
+
+	testCases := []struct {
+		name         string
+		cfg          AnalyticsHub
+		expectedErrs []error
+	}{
+		{
+			name: "disabled",
+			cfg:  AnalyticsHub{Forward: AnalyticsHubForward{URL: "invalid"}},
+		},
+		{
+			name: "valid-forward",
+			cfg:  AnalyticsHub{Forward: validForward},
+		},
+		{
+			name: "valid-ingest",
+			cfg:  AnalyticsHub{Ingest: validIngest},
+		},
+		{
+			name: "invalid-forward",
+			cfg:  AnalyticsHub{Forward: AnalyticsHubForward{Enabled: true, URL: "hub"}},
+			expectedErrs: []error{
+				errors.New(`analytics.hub.forward.url must be a valid URL. Got "hub"`),
+				errors.New("analytics.hub.forward.buffers.count must be > 0. Got 0"),
+			},
+		},
+		{
+			name: "invalid-ingest",
+			cfg:  AnalyticsHub{Ingest: AnalyticsHubIngest{Enabled: true, Tokens: []string{""}}},
+			expectedErrs: []error{
+				errors.New("analytics.hub.ingest.max_batch_size must be > 0. Got 0"),
+				errors.New("analytics.hub.ingest.tokens[0] must not be empty"),
+			},
+		},
+		{
+			name: "no-tokens",
+			cfg:  AnalyticsHub{Ingest: AnalyticsHubIngest{Enabled: true, MaxBatchSize: 1024}},
+			expectedErrs: []error{
+				errors.New("analytics.hub.ingest.tokens must have at least one token"),
+			},
+		},
+		{
+			name: "forward-and-ingest",
+			cfg:  AnalyticsHub{Forward: validForward, Ingest: validIngest},
+			expectedErrs: []error{
+				errors.New("analytics.hub.forward and analytics.hub.ingest cannot both be enabled"),
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			assert.ElementsMatch(t, test.expectedErrs, test.cfg.validate(nil))
+		})
+	}
+}
+
+func TestDefReqConfigValidate(t *testing.T) {
+	testCases := []struct {
+		name         string
+		cfg          DefReqConfig
+		expectedErrs []error
+	}{
+		{
+			name: "empty",
+			cfg:  DefReqConfig{},
+		},
+		{
+			name: "valid",
+			cfg: DefReqConfig{
+				Type:       "file",
+				FileSystem: DefReqFiles{FileName: "default.json"},
+				Channels:   map[string]DefReqFiles{"app": {FileName: "app.json"}, "web": {FileName: "web.json"}},
+				Merge:      DefReqMerge{Arrays: "merge", Ext: "replace", Enforce: []string{"source.schain", "regs"}},
+			},
+		},
+		{
+			name: "invalid",
+			cfg: DefReqConfig{
+				Channels: map[string]DefReqFiles{"amp": {}, "app": {FileName: "app.json"}},
+				Merge:    DefReqMerge{Arrays: "append", Ext: "drop", Enforce: []string{"regs.", ""}},
+			},
+			expectedErrs: []error{
+				errors.New("default_request.channels must be web, app or dooh. Got amp"),
+				errors.New("default_request.channels.app needs default_request.type to be file"),
+				errors.New("default_request.merge.arrays must be replace or merge. Got append"),
+				errors.New("default_request.merge.ext must be merge or replace. Got drop"),
+				errors.New("default_request.merge.enforce[0] must be a dot separated path. Got regs."),
+				errors.New("default_request.merge.enforce[1] must be a dot separated path. Got "),
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			assert.ElementsMatch(t, test.expectedErrs, test.cfg.validate(nil))
+		})
+	}
+}
+
+func TestFaultInjectionValidate(t *testing.T) {
+	testCases := []struct {
+		name         string
+		cfg          FaultInjection
+		expectedErrs []error
+	}{
+		{
+			name: "disabled",
+			cfg:  FaultInjection{Enabled: false, Bidders: Faults{ErrorRate: 2}},
+		},
+		{
+			name: "valid",
+			cfg: FaultInjection{
+				Enabled:        true,
+				Bidders:        Faults{LatencyRate: 0.1, LatencyMs: 200, ErrorRate: 0.05, MalformedRate: 0.05},
+				StoredRequests: Faults{ErrorRate: 1},
+			},
+		},
+		{
+			name: "invalid",
+			cfg: FaultInjection{
+				Enabled:        true,
+				Bidders:        Faults{LatencyRate: 1.5, LatencyMs: -1},
+				StoredRequests: Faults{ErrorRate: -0.5, MalformedRate: 2},
+				Cache:          Faults{ErrorRate: 0.6, MalformedRate: 0.6},
+			},
+			expectedErrs: []error{
+				errors.New("fault_injection.bidders.latency_rate must be in the range [0, 1]. Got 1.5"),
+				errors.New("fault_injection.bidders.latency_ms must be >= 0. Got -1"),
+				errors.New("fault_injection.stored_requests.error_rate must be in the range [0, 1]. Got -0.5"),
+				errors.New("fault_injection.stored_requests.malformed_rate must be in the range [0, 1]. Got 2"),
+				errors.New("fault_injection.stored_requests.error_rate and fault_injection.stored_requests.malformed_rate must add up to at most 1. Got 1.5"),
+				errors.New("fault_injection.cache.error_rate and fault_injection.cache.malformed_rate must add up to at most 1. Got 1.2"),
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			assert.ElementsMatch(t, test.expectedErrs, test.cfg.validate(nil))
+		})
+	}
+}
+
+func TestLoggingValidate(t *testing.T) {
+	testCases := []struct {
+		name         string
+		cfg          Logging
+		expectedErrs []error
+	}{
+		{
+			name: "valid",
+			cfg:  Logging{Format: "json", Level: "info", ComponentLevels: map[string]string{"exchange": "debug"}, AccountLevels: map[string]string{"1001": "error"}},
+		},
+		{
+			name: "invalid",
+			cfg:  Logging{Format: "xml", Level: "loud", ComponentLevels: map[string]string{"exchange": "quiet"}, AccountLevels: map[string]string{"1001": "silent"}},
+			expectedErrs: []error{
+				errors.New("logging.format must be json or glog. Got xml"),
+				errors.New("logging.level: unknown log level: loud"),
+				errors.New("logging.component_levels.exchange: unknown log level: quiet"),
+				errors.New("logging.account_levels.1001: unknown log level: silent"),
+			},
+		},
+		{
+			name: "valid-policy",
+			cfg:  Logging{Sampling: map[string]float64{"bidder_errors": 0.01}, RedactFields: []string{"ip"}, RedactPatterns: []string{`\d{16}`}},
+		},
+		{
+			name: "invalid-policy",
+			cfg:  Logging{Sampling: map[string]float64{"bidder_errors": 2, "everything": 0.5}, RedactFields: []string{""}, RedactPatterns: []string{"("}},
+			expectedErrs: []error{
+				errors.New("logging: sampling rate of bidder_errors must be in the range [0, 1]. Got 2.000000"),
+				errors.New("logging: unknown log class: everything"),
+				errors.New("logging: redacted field names must not be empty"),
+				errors.New("logging: invalid redaction pattern (: error parsing regexp: missing closing ): `(`"),
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			assert.ElementsMatch(t, test.expectedErrs, test.cfg.validate(nil))
+		})
+	}
+}
+
+func TestJSONValidate(t *testing.T) {
+	for _, engine := range []string{"jsoniter", "stdlib", "sonic"} {
+		assert.Empty(t, (&JSON{Engine: engine}).validate(nil), engine)
+	}
+	assert.Equal(t, []error{errors.New("json.engine must be jsoniter, stdlib or sonic. Got gojay")}, (&JSON{Engine: "gojay"}).validate(nil))
+}
+
+func TestRequestDecodingValidate(t *testing.T) {
+	assert.Empty(t, (&RequestDecoding{ImpPrecheck: true, MaxImps: 100}).validate(nil))
+	assert.Equal(t, []error{errors.New("request_decoding.max_imps must be 0 or more. Got -1")}, (&RequestDecoding{MaxImps: -1}).validate(nil))
+}
+
+func TestBidderTimeoutNotificationsValidate(t *testing.T) {
+	assert.Empty(t, (&BidderTimeoutNotifications{MaxPerSecond: -1}).validate(nil), "disabled")
+	assert.Empty(t, (&BidderTimeoutNotifications{Enabled: true, MaxPerSecond: 100, TimeoutMs: 200}).validate(nil))
+	assert.Equal(t, []error{
+		errors.New("bidder_timeout_notifications.max_per_second must be 0 or more. Got -1"),
+		errors.New("bidder_timeout_notifications.timeout_ms must be positive. Got 0"),
+	}, (&BidderTimeoutNotifications{Enabled: true, MaxPerSecond: -1}).validate(nil))
+}
+
+func TestHooksValidate(t *testing.T) {
+	testCases := []struct {
+		name         string
+		cfg          Hooks
+		expectedErrs []error
+	}{
+		{
+			name: "no-canaries",
+			cfg:  Hooks{},
+		},
+		{
+			name: "valid",
+			cfg:  Hooks{Canaries: []ModuleCanary{{ModuleCode: "acme.foo", CanaryModuleCode: "acme.foo_v2", Percent: 5}}},
+		},
+		{
+			name: "invalid",
+			cfg: Hooks{Canaries: []ModuleCanary{
+				{ModuleCode: "acme.foo", Percent: 5},
+				{ModuleCode: "acme.bar", CanaryModuleCode: "acme.bar_v2", Percent: 150},
+			}},
+			expectedErrs: []error{
+				errors.New("hooks.canaries must have a module_code and a canary_module_code"),
+				errors.New("hooks.canaries.percent must be in the range [0, 100]. Got 150.000000 for acme.bar"),
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			assert.ElementsMatch(t, test.expectedErrs, test.cfg.validate(nil))
+		})
+	}
+}
+
+func TestIVTValidate(t *testing.T) {
+	testCases := []struct {
+		name         string
+		cfg          IVT
+		expectedErrs []error
+	}{
+		{
+			name: "disabled",
+			cfg:  IVT{Enabled: false, Action: "drop", BlockedIPs: []string{"invalid"}},
+		},
+		{
+			name: "valid",
+			cfg:  IVT{Enabled: true, Action: IVTActionBlock, BlockedIPs: []string{"203.0.113.7", "198.51.100.0/24", "2001:db8::/32"}},
+		},
+		{
+			name: "valid datacenter url",
+			cfg:  IVT{Enabled: true, Action: IVTActionTag, DatacenterIPsURL: "https://lists.prebid.org/datacenters.csv", DatacenterIPsRefreshMinutes: 60},
+		},
+		{
+			name: "invalid",
+			cfg:  IVT{Enabled: true, Action: "drop", BlockedIPs: []string{"203.0.113", "198.51.100.0/33"}, DatacenterIPsURL: "lists.prebid.org/datacenters.csv", DatacenterIPsRefreshMinutes: -1},
+			expectedErrs: []error{
+				errors.New("ivt.action must be block or tag. Got drop"),
+				errors.New("ivt.blocked_ips has an invalid IP address or CIDR block: 203.0.113"),
+				errors.New("ivt.blocked_ips has an invalid IP address or CIDR block: 198.51.100.0/33"),
+				errors.New("ivt.datacenter_ips_url must be an http or https URL. Got lists.prebid.org/datacenters.csv"),
+				errors.New("ivt.datacenter_ips_refresh_minutes must be >= 0. Got -1"),
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			assert.ElementsMatch(t, test.expectedErrs, test.cfg.validate(nil))
+		})
+	}
+}
+
+func TestResponseSigningValidate(t *testing.T) {
+	cfg := ResponseSigning{Keys: map[string]string{"2024": "/etc/prebid/2024.pem"}}
+	assert.Empty(t, cfg.validate(nil))
+
+	cfg.Keys["2023"] = ""
+	assert.Equal(t, []error{errors.New("response_signing.keys.2023 must be the path of a PEM file")}, cfg.validate(nil))
+}
+
+func TestGeoLocationValidate(t *testing.T) {
+	testCases := []struct {
+		name         string
+		cfg          GeoLocation
+		expectedErrs []error
+	}{
+		{
+			name: "disabled",
+			cfg:  GeoLocation{Enabled: false, Provider: "unknown"},
+		},
+		{
+			name: "valid",
+			cfg:  GeoLocation{Enabled: true, Provider: GeoLocationProviderMaxMind, MaxMind: GeoLocationMaxMind{DatabasePath: "/var/lib/GeoLite2-City.mmdb", ReloadIntervalSeconds: 300}},
+		},
+		{
+			name:         "unknown provider",
+			cfg:          GeoLocation{Enabled: true, Provider: "unknown"},
+			expectedErrs: []error{errors.New("geolocation.provider must be maxmind. Got unknown")},
+		},
+		{
+			name: "invalid maxmind",
+			cfg:  GeoLocation{Enabled: true, Provider: GeoLocationProviderMaxMind, MaxMind: GeoLocationMaxMind{ReloadIntervalSeconds: -1}},
+			expectedErrs: []error{
+				errors.New("geolocation.maxmind.database_path must be the path of a MaxMind DB file"),
+				errors.New("geolocation.maxmind.reload_interval_seconds must be 0 or more. Got -1"),
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			assert.ElementsMatch(t, test.expectedErrs, test.cfg.validate(nil))
+		})
+	}
+}
+
+func TestContentClassificationValidate(t *testing.T) {
+	valid := ContentClassification{
+		Enabled:         true,
+		Provider:        ContentClassificationProviderHTTP,
+		HTTP:            ContentClassificationHTTP{Endpoint: "https://classifier.example.com/classify"},
+		TimeoutMS:       50,
+		MinRemainingMS:  300,
+		CacheSize:       1000,
+		CacheTTLSeconds: 3600,
+		DataName:        "prebid-server",
+	}
+	testCases := []struct {
+		name         string
+		cfg          ContentClassification
+		expectedErrs []error
+	}{
+		{
+			name: "disabled",
+			cfg:  ContentClassification{Enabled: false, Provider: "unknown"},
+		},
+		{
+			name: "valid",
+			cfg:  valid,
+		},
+		{
+			name: "unknown provider",
+			cfg: func() ContentClassification {
+				cfg := valid
+				cfg.Provider = "unknown"
+				return cfg
+			}(),
+			expectedErrs: []error{errors.New("content_classification.provider must be http. Got unknown")},
+		},
+		{
+			name: "invalid",
+			cfg:  ContentClassification{Enabled: true, Provider: ContentClassificationProviderHTTP, MinRemainingMS: -1},
+			expectedErrs: []error{
+				errors.New("content_classification.http.endpoint must be a URL. Got "),
+				errors.New("content_classification.timeout_ms must be > 0. Got 0"),
+				errors.New("content_classification.min_remaining_ms must be >= 0. Got -1"),
+				errors.New("content_classification.cache_size must be > 0. Got 0"),
+				errors.New("content_classification.cache_ttl_seconds must be > 0. Got 0"),
+				errors.New("content_classification.data_name must not be empty"),
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			assert.ElementsMatch(t, test.expectedErrs, test.cfg.validate(nil))
+		})
+	}
+}
+
+func TestTestTrafficValidate(t *testing.T) {
+	testCases := []struct {
+		name         string
+		cfg          TestTraffic
+		expectedErrs []error
+	}{
+		{
+			name: "disabled",
+			cfg:  TestTraffic{Enabled: false, SynthesizedBidPrice: -1},
+		},
+		{
+			name: "valid",
+			cfg:  TestTraffic{Enabled: true, SynthesizeBids: true, SynthesizedBidPrice: 1},
+		},
+		{
+			name:         "negative-price",
+			cfg:          TestTraffic{Enabled: true, SynthesizedBidPrice: -1},
+			expectedErrs: []error{errors.New("test_traffic.synthesized_bid_price must be >= 0. Got -1.000000")},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			assert.ElementsMatch(t, test.expectedErrs, test.cfg.validate(nil))
+		})
+	}
+}
+
+func TestPIIScannerValidate(t *testing.T) {
+	testCases := []struct {
+		name         string
+		cfg          PIIScanner
+		expectedErrs []error
+	}{
+		{
+			name: "off",
+			cfg:  PIIScanner{Mode: PIIScannerModeOff, SampleRate: 2, Patterns: map[string]string{"phone": "("}},
+		},
+		{
+			name: "valid",
+			cfg:  PIIScanner{Mode: PIIScannerModeReport, SampleRate: 0.01, Emails: true, Patterns: map[string]string{"phone": `\+1\d{10}`}},
+		},
+		{
+			name:         "unknown mode",
+			cfg:          PIIScanner{Mode: "block", SampleRate: 1},
+			expectedErrs: []error{errors.New("pii_scanner.mode must be one of off, report or enforce. Got block")},
+		},
+		{
+			name: "invalid sample rate and pattern",
+			cfg:  PIIScanner{Mode: PIIScannerModeEnforce, SampleRate: 1.5, Patterns: map[string]string{"phone": "("}},
+			expectedErrs: []error{
+				errors.New("pii_scanner.sample_rate must be between 0 and 1. Got 1.5"),
+				errors.New("pii_scanner.patterns.phone is not a valid regular expression: error parsing regexp: missing closing ): `(`"),
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			assert.ElementsMatch(t, test.expectedErrs, test.cfg.validate(nil))
+		})
+	}
+}
+
+func TestMobileSDKValidate(t *testing.T) {
+	testCases := []struct {
+		name         string
+		cfg          MobileSDK
+		expectedErrs []error
+	}{
+		{
+			name: "disabled",
+			cfg: MobileSDK{
+				Interstitial: MobileSDKInterstitial{MobileSDKFeature: MobileSDKFeature{MinVersion: "latest"}, MinSizePerc: 200},
+				ATT:          MobileSDKFeature{MinVersion: "latest"},
+			},
+		},
+		{
+			name: "valid",
+			cfg: MobileSDK{
+				Interstitial: MobileSDKInterstitial{MobileSDKFeature: MobileSDKFeature{Enabled: true}, MinSizePerc: 50},
+				ATT:          MobileSDKFeature{Enabled: true, MinVersion: "2.0"},
+				Multiformat:  MobileSDKFeature{Enabled: true, MinVersion: "2.1.0"},
+			},
+		},
+		{
+			name: "invalid",
+			cfg: MobileSDK{
+				Interstitial: MobileSDKInterstitial{MobileSDKFeature: MobileSDKFeature{Enabled: true, MinVersion: "2.x"}, MinSizePerc: 101},
+				Multiformat:  MobileSDKFeature{Enabled: true, MinVersion: "latest"},
+			},
+			expectedErrs: []error{
+				errors.New("mobile_sdk.interstitial.min_version 2.x: expected major.minor.patch format"),
+				errors.New("mobile_sdk.interstitial.min_size_perc must be between 0 and 100. Got 101"),
+				errors.New("mobile_sdk.multiformat.min_version latest: expected major.minor.patch format"),
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			assert.ElementsMatch(t, test.expectedErrs, test.cfg.validate(nil))
+		})
+	}
+}
+
+func TestRateLimitingValidate(t *testing.T) {
+	testCases := []struct {
+		name         string
+		cfg          RateLimiting
+		expectedErrs []error
+	}{
+		{
+			name: "local",
+			cfg:  RateLimiting{Backend: RateLimitingBackendLocal, BidderQPS: map[string]int{"appnexus": 100}},
+		},
+		{
+			name: "unset",
+			cfg:  RateLimiting{},
+		},
+		{
+			name: "redis",
+			cfg:  RateLimiting{Backend: RateLimitingBackendRedis, Redis: RateLimitingRedis{RedisConnection: RedisConnection{Addrs: []string{"localhost:6379"}}, TimeoutMS: 20}},
+		},
+		{
+			name: "invalid redis",
+			cfg:  RateLimiting{Backend: RateLimitingBackendRedis, Redis: RateLimitingRedis{RedisConnection: RedisConnection{PoolSize: -1}}, BidderQPS: map[string]int{"appnexus": 0}},
+			expectedErrs: []error{
+				errors.New("rate_limiting.redis.addrs is required for the redis backend"),
+				errors.New("rate_limiting.redis.timeout_ms must be > 0. Got 0"),
+				errors.New("rate_limiting.redis.pool_size must be >= 0. Got -1"),
+				errors.New("rate_limiting.bidder_qps.appnexus must be > 0. Got 0"),
+			},
+		},
+		{
+			name:         "unknown backend",
+			cfg:          RateLimiting{Backend: "memcached"},
+			expectedErrs: []error{errors.New("rate_limiting.backend must be local or redis. Got memcached")},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			assert.ElementsMatch(t, test.expectedErrs, test.cfg.validate(nil))
+		})
+	}
+}
+
+func TestParsedStoredRequestCacheValidate(t *testing.T) {
+	cfg := ParsedStoredRequestCache{Enabled: true, MaxEntries: 0}
+	assert.Equal(t, []error{errors.New("parsed_stored_request_cache.max_entries must be > 0. Got 0")}, cfg.validate(nil))
+
+	cfg.Enabled = false
+	assert.Empty(t, cfg.validate(nil))
+}
+
+func TestBidderResponseCacheValidate(t *testing.T) {
+	testCases := []struct {
+		name         string
+		cfg          BidderResponseCache
+		expectedErrs []error
+	}{
+		{
+			name: "disabled",
+			cfg:  BidderResponseCache{Enabled: false},
+		},
+		{
+			name: "valid",
+			cfg:  BidderResponseCache{Enabled: true, TTLMS: 5000, MaxEntries: 10000, Bidders: []string{"appnexus"}},
+		},
+		{
+			name: "invalid",
+			cfg:  BidderResponseCache{Enabled: true, TTLMS: 120000},
+			expectedErrs: []error{
+				errors.New("bidder_response_cache.ttl_ms must be between 1 and 60000. Got 120000"),
+				errors.New("bidder_response_cache.max_entries must be > 0. Got 0"),
+				errors.New("bidder_response_cache.bidders must list at least one bidder"),
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			assert.ElementsMatch(t, test.expectedErrs, test.cfg.validate(nil))
+		})
+	}
+}
+
+func TestMemoryLoadSheddingValidate(t *testing.T) {
+	testCases := []struct {
+		name         string
+		cfg          MemoryLoadShedding
+		expectedErrs []error
+	}{
+		{
+			name: "disabled",
+			cfg:  MemoryLoadShedding{Enabled: false, LimitBytes: -1},
+		},
+		{
+			name: "valid",
+			cfg:  MemoryLoadShedding{Enabled: true, LimitBytes: 1000, DegradeThreshold: 0.8, ShedThreshold: 0.9, CheckIntervalMs: 100, RetryAfterSeconds: 1},
+		},
+		{
+			name: "no-limit",
+			cfg:  MemoryLoadShedding{Enabled: true, LimitBytes: 0, DegradeThreshold: 0.8, ShedThreshold: 0.9, CheckIntervalMs: 100},
+			expectedErrs: []error{
+				errors.New("load_shedding.memory.limit_bytes must be set when GOMEMLIMIT is not"),
+			},
+		},
+		{
+			name: "invalid",
+			cfg:  MemoryLoadShedding{Enabled: true, LimitBytes: -1, DegradeThreshold: 0.95, ShedThreshold: 1.5, CheckIntervalMs: 0, RetryAfterSeconds: -1},
+			expectedErrs: []error{
+				errors.New("load_shedding.memory.limit_bytes must be >= 0. Got -1"),
+				errors.New("load_shedding.memory.shed_threshold must be in the range (0, 1]. Got 1.5"),
+				errors.New("load_shedding.memory.check_interval_ms must be > 0. Got 0"),
+				errors.New("load_shedding.memory.retry_after_seconds must be >= 0. Got -1"),
+			},
+		},
+		{
+			name: "degrade-after-shed",
+			cfg:  MemoryLoadShedding{Enabled: true, LimitBytes: 1000, DegradeThreshold: 0.9, ShedThreshold: 0.8, CheckIntervalMs: 100},
+			expectedErrs: []error{
+				errors.New("load_shedding.memory.degrade_threshold must not be greater than load_shedding.memory.shed_threshold. Got 0.9 and 0.8"),
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			assert.ElementsMatch(t, test.expectedErrs, test.cfg.validate(nil))
+		})
+	}
+}
+
+func TestConcurrencyLoadSheddingValidate(t *testing.T) {
+	valid := ConcurrencyLoadShedding{Enabled: true, InitialLimit: 200, MinLimit: 10, MaxLimit: 1000, BackoffRatio: 0.9, TargetQueueDelayMs: 10, MaxQueueWaitMs: 50, MaxQueueSize: 1000, RetryAfterSeconds: 1}
+	assert.Empty(t, valid.validate(nil))
+
+	invalid := ConcurrencyLoadShedding{Enabled: true, InitialLimit: 5, MinLimit: 0, MaxLimit: -1, BackoffRatio: 1, TargetQueueDelayMs: 0, MaxQueueWaitMs: -1, MaxQueueSize: -1, RetryAfterSeconds: -1}
+	assert.ElementsMatch(t, []error{
+		errors.New("load_shedding.concurrency.min_limit must be > 0. Got 0"),
+		errors.New("load_shedding.concurrency.max_limit must be >= load_shedding.concurrency.min_limit. Got -1 and 0"),
+		errors.New("load_shedding.concurrency.initial_limit must be between min_limit and max_limit. Got 5"),
+		errors.New("load_shedding.concurrency.backoff_ratio must be in the range (0, 1). Got 1"),
+		errors.New("load_shedding.concurrency.target_queue_delay_ms must be > 0. Got 0"),
+		errors.New("load_shedding.concurrency.max_queue_wait_ms must be >= load_shedding.concurrency.target_queue_delay_ms. Got -1 and 0"),
+		errors.New("load_shedding.concurrency.max_queue_size must be >= 0. Got -1"),
+		errors.New("load_shedding.concurrency.retry_after_seconds must be >= 0. Got -1"),
+	}, invalid.validate(nil))
+
+	invalid.Enabled = false
+	assert.Empty(t, invalid.validate(nil))
+}
+
+func TestLatencyBudgetValidate(t *testing.T) {
+	valid := LatencyBudget{Enabled: true, StoredRequests: 0.1, Hooks: 0, CacheWrite: 0.1}
+	assert.Empty(t, valid.validate(nil))
+
+	invalid := LatencyBudget{Enabled: true, StoredRequests: 0, Hooks: -0.1, CacheWrite: 1}
+	assert.ElementsMatch(t, []error{
+		errors.New("latency_budget.stored_requests must be in the range (0, 1). Got 0"),
+		errors.New("latency_budget.hooks must be in the range [0, 1). Got -0.1"),
+		errors.New("latency_budget.cache_write must be in the range [0, 1). Got 1"),
+	}, invalid.validate(nil))
+
+	overcommitted := LatencyBudget{Enabled: true, StoredRequests: 0.5, Hooks: 0.25, CacheWrite: 0.25}
+	assert.ElementsMatch(t, []error{
+		errors.New("latency_budget.stored_requests, latency_budget.hooks and latency_budget.cache_write must leave some of tmax to the bidders. Got 1 in total"),
+	}, overcommitted.validate(nil))
+
+	invalid.Enabled = false
+	assert.Empty(t, invalid.validate(nil))
+}
+
 func TestNegativePrometheusTimeout(t *testing.T) {
 	cfg, v := newDefaultConfig(t)
 	cfg.Metrics.Prometheus.Port = 8001
@@ -1192,6 +2436,22 @@ func TestInvalidAMPException(t *testing.T) {
 	assertOneError(t, cfg.validate(v), "gdpr.amp_exception has been discontinued and must be removed from your config. If you need to disable GDPR for AMP, you may do so per-account (gdpr.integration_enabled.amp) or at the host level for the default account (account_defaults.gdpr.integration_enabled.amp)")
 }
 
+func TestInvalidAccountAdsCertWithoutSigner(t *testing.T) {
+	cfg, v := newDefaultConfig(t)
+	cfg.AccountDefaults.AdsCert.Verify = AdsCertVerifyObserve
+	assertOneError(t, cfg.validate(v), "account_defaults.adscert needs experiment.adscert.mode to be inprocess or remote")
+}
+
+func TestInvalidAccountResponseSigningKey(t *testing.T) {
+	cfg, v := newDefaultConfig(t)
+	cfg.ResponseSigning.Keys = map[string]string{"2024": "/etc/prebid/2024.pem"}
+	cfg.AccountDefaults.ResponseSigning = AccountResponseSigning{Enabled: true, KeyID: "2023"}
+	assertOneError(t, cfg.validate(v), "account_defaults.response_signing.key_id 2023 is not one of response_signing.keys")
+
+	cfg.AccountDefaults.ResponseSigning.KeyID = "2024"
+	assert.Empty(t, cfg.validate(v))
+}
+
 func TestInvalidGDPRDefaultValue(t *testing.T) {
 	cfg, v := newDefaultConfig(t)
 	cfg.GDPR.DefaultValue = "2"
@@ -1255,6 +2515,54 @@ func TestOverflowedCurrencyConverterFetchInterval(t *testing.T) {
 	assert.NotNil(t, err, "cfg.currency_converter.fetch_interval_seconds prevent values over %d, but it doesn't", 0xffff)
 }
 
+func TestCurrencyConverterValidate(t *testing.T) {
+	tests := []struct {
+		name         string
+		cfg          CurrencyConverter
+		expectedErrs []error
+	}{
+		{
+			name: "fetch-url-only",
+			cfg:  CurrencyConverter{FetchURL: "https://currency.prebid.org", FetchIntervalSeconds: 1800},
+		},
+		{
+			name: "valid-sources",
+			cfg: CurrencyConverter{
+				RatesSources: []CurrencyRatesSource{
+					{Name: "primary", URL: "https://rates.prebid.org", Priority: 1, TimeoutMS: 1000},
+					{URL: "http://currency.prebid.org", Priority: 2},
+				},
+				SourceMaxFailures:  3,
+				SourceRetrySeconds: 300,
+				StaleAlarmSeconds:  3600,
+			},
+		},
+		{
+			name: "invalid-sources",
+			cfg: CurrencyConverter{
+				RatesSources: []CurrencyRatesSource{
+					{URL: "ftp://rates.prebid.org"},
+					{URL: "https://currency.prebid.org", TimeoutMS: -1},
+				},
+				SourceRetrySeconds: -1,
+				StaleAlarmSeconds:  -1,
+			},
+			expectedErrs: []error{
+				errors.New("currency_converter.rates_sources[0].url must be an http or https URL. Got ftp://rates.prebid.org"),
+				errors.New("currency_converter.rates_sources[1].timeout_ms must be >= 0. Got -1"),
+				errors.New("currency_converter.source_max_failures must be > 0. Got 0"),
+				errors.New("currency_converter.source_retry_seconds must be >= 0. Got -1"),
+				errors.New("currency_converter.stale_alarm_seconds must be >= 0. Got -1"),
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.ElementsMatch(t, test.expectedErrs, test.cfg.validate(nil))
+		})
+	}
+}
+
 func TestLimitTimeout(t *testing.T) {
 	doTimeoutTest(t, 10, 15, 10, 0)
 	doTimeoutTest(t, 10, 0, 10, 0)
@@ -1345,6 +2653,22 @@ func TestValidateAccountsConfigRestrictions(t *testing.T) {
 	assert.Contains(t, errs, errors.New("accounts.database: retrieving accounts via database not available, use accounts.files"))
 }
 
+func TestValidateAccountsListenNotify(t *testing.T) {
+	cfg, v := newDefaultConfig(t)
+	cfg.Accounts.Files.Enabled = true
+	cfg.Accounts.InMemoryCache.Type = "unbounded"
+	cfg.Accounts.Database.ConnectionInfo.Driver = "postgres"
+	cfg.Accounts.Database.ConnectionInfo.Database = "accounts"
+	cfg.Accounts.Database.ListenNotify.Channel = "accounts"
+
+	errs := cfg.validate(v)
+	assert.Empty(t, errs)
+
+	cfg.Accounts.Database.PollUpdates.Query = "SELECT id, config, 'account' AS type FROM accounts WHERE last_updated > $LAST_UPDATED"
+	errs = cfg.validate(v)
+	assert.Contains(t, errs, errors.New("accounts.database: retrieving accounts via database not available, use accounts.files"))
+}
+
 func newDefaultConfig(t *testing.T) (*Configuration, *viper.Viper) {
 	v := viper.New()
 	SetupViper(v, "", bidderInfos)
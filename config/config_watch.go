@@ -0,0 +1,26 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// ConfigWatch re-reads the host config files while the server runs, such as those mounted from Kubernetes
+// ConfigMaps and Secrets, and applies the changes to the settings which may change without a restart.
+type ConfigWatch struct {
+	Enabled bool `mapstructure:"enabled"`
+	// Interval is how often, in seconds, the files are checked for changes.
+	Interval int `mapstructure:"interval_seconds"`
+}
+
+// IntervalDuration returns the check interval as a time.Duration
+func (cfg *ConfigWatch) IntervalDuration() time.Duration {
+	return time.Duration(cfg.Interval) * time.Second
+}
+
+func (cfg *ConfigWatch) validate(errs []error) []error {
+	if cfg.Enabled && cfg.Interval <= 0 {
+		errs = append(errs, fmt.Errorf("config_watch.interval_seconds must be > 0. Got %d", cfg.Interval))
+	}
+	return errs
+}
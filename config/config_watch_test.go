@@ -0,0 +1,39 @@
+package config
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigWatchValidate(t *testing.T) {
+	tests := []struct {
+		description  string
+		cfg          ConfigWatch
+		expectedErrs []error
+	}{
+		{
+			description: "disabled",
+			cfg:         ConfigWatch{Enabled: false},
+		},
+		{
+			description: "valid",
+			cfg:         ConfigWatch{Enabled: true, Interval: 30},
+		},
+		{
+			description: "no interval",
+			cfg:         ConfigWatch{Enabled: true},
+			expectedErrs: []error{
+				errors.New("config_watch.interval_seconds must be > 0. Got 0"),
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			errs := test.cfg.validate(nil)
+			assert.Equal(t, test.expectedErrs, errs)
+		})
+	}
+}
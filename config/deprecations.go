@@ -0,0 +1,84 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/viper"
+
+	"github.com/prebid/prebid-server/v2/logger"
+)
+
+// DeprecatedSetting describes a config key which is no longer supported along with the key which replaces it.
+// Settings without a replacement have been removed and are ignored.
+type DeprecatedSetting struct {
+	Key         string `json:"key"`
+	Replacement string `json:"replacement,omitempty"`
+	Message     string `json:"message,omitempty"`
+}
+
+// deprecatedSettings lists every deprecated config key known to Prebid Server. When a key is moved, add an
+// entry here so existing host configs continue to work and operators are told to update them.
+var deprecatedSettings = []DeprecatedSetting{
+	{Key: "enable_gzip", Replacement: "compression.response.enable_gzip"},
+	{Key: "stored_requests.directorypath", Replacement: "stored_requests.filesystem.directorypath"},
+	{Key: "stored_requests.postgres", Replacement: "stored_requests.database"},
+	{Key: "stored_video_req.postgres", Replacement: "stored_video_req.database"},
+	{Key: "stored_responses.postgres", Replacement: "stored_responses.database"},
+	{Key: "account_defaults.events_enabled", Replacement: "account_defaults.events.enabled"},
+	{Key: "gdpr.tcf2.purpose_one_treatement", Replacement: "gdpr.tcf2.purpose_one_treatment"},
+	{Key: "blacklisted_accts", Message: "blocked accounts must be configured with \"disabled\": true in the account config"},
+}
+
+// migrateDeprecatedSettings copies the value of each deprecated key explicitly set by the host, in a config file
+// or environment variable, to its replacement and returns the deprecated settings found. A replacement key which
+// is also explicitly set takes precedence over the deprecated key.
+func migrateDeprecatedSettings(v *viper.Viper, settings []DeprecatedSetting) []DeprecatedSetting {
+	var inUse []DeprecatedSetting
+	for _, setting := range settings {
+		if !isExplicitlySet(v, setting.Key) {
+			continue
+		}
+		inUse = append(inUse, setting)
+
+		if setting.Replacement == "" {
+			logger.Warningf("config: %s is no longer supported and is ignored. %s", setting.Key, setting.Message)
+			continue
+		}
+		if isExplicitlySet(v, setting.Replacement) {
+			logger.Warningf("config: %s is deprecated and is ignored because %s is also set", setting.Key, setting.Replacement)
+			continue
+		}
+		logger.Warningf("config: %s is deprecated and will be removed in a future version. Use %s instead", setting.Key, setting.Replacement)
+		v.Set(setting.Replacement, v.Get(setting.Key))
+	}
+	return inUse
+}
+
+// isExplicitlySet checks whether a key was provided in a config file or environment variable, as opposed to only
+// having a default value.
+func isExplicitlySet(v *viper.Viper, key string) bool {
+	if v.InConfig(key) {
+		return true
+	}
+	_, ok := os.LookupEnv("PBS_" + strings.ToUpper(strings.ReplaceAll(key, ".", "_")))
+	return ok
+}
+
+func deprecatedSettingsErrors(inUse []DeprecatedSetting) []error {
+	errs := make([]error, 0, len(inUse))
+	for _, setting := range inUse {
+		if setting.Replacement == "" {
+			errs = append(errs, fmt.Errorf("%s is no longer supported and must be removed from your config", setting.Key))
+		} else {
+			errs = append(errs, fmt.Errorf("%s is deprecated and must be replaced with %s", setting.Key, setting.Replacement))
+		}
+	}
+	return errs
+}
+
+// DeprecatedSettingsInUse returns the deprecated settings found in the host config when it was loaded.
+func (cfg *Configuration) DeprecatedSettingsInUse() []DeprecatedSetting {
+	return cfg.deprecatedSettingsInUse
+}
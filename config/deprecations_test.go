@@ -0,0 +1,122 @@
+package config
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMigrateDeprecatedSettings(t *testing.T) {
+	settings := []DeprecatedSetting{
+		{Key: "old.flag", Replacement: "new.flag"},
+		{Key: "old.section", Replacement: "new.section"},
+		{Key: "removed", Message: "no longer used"},
+	}
+
+	testCases := []struct {
+		description   string
+		yaml          string
+		env           map[string]string
+		expectedInUse []DeprecatedSetting
+		expectedFlag  bool
+		expectedPort  int
+	}{
+		{
+			description:   "none",
+			yaml:          "new:\n  flag: true\n",
+			expectedInUse: nil,
+			expectedFlag:  true,
+		},
+		{
+			description:   "deprecated-leaf-migrated",
+			yaml:          "old:\n  flag: true\n",
+			expectedInUse: []DeprecatedSetting{settings[0]},
+			expectedFlag:  true,
+		},
+		{
+			description:   "replacement-takes-precedence",
+			yaml:          "old:\n  flag: true\nnew:\n  flag: false\n",
+			expectedInUse: []DeprecatedSetting{settings[0]},
+			expectedFlag:  false,
+		},
+		{
+			description:   "deprecated-section-migrated",
+			yaml:          "old:\n  section:\n    port: 1234\n",
+			expectedInUse: []DeprecatedSetting{settings[1]},
+			expectedPort:  1234,
+		},
+		{
+			description:   "deprecated-env-migrated",
+			env:           map[string]string{"PBS_OLD_FLAG": "true"},
+			expectedInUse: []DeprecatedSetting{settings[0]},
+			expectedFlag:  true,
+		},
+		{
+			description:   "removed",
+			yaml:          "removed: 1\n",
+			expectedInUse: []DeprecatedSetting{settings[2]},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			for k, val := range test.env {
+				t.Setenv(k, val)
+			}
+			v := viper.New()
+			v.SetConfigType("yaml")
+			v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+			v.SetEnvPrefix("PBS")
+			v.AutomaticEnv()
+			v.SetDefault("new.flag", false)
+			assert.NoError(t, v.ReadConfig(bytes.NewBufferString(test.yaml)))
+
+			inUse := migrateDeprecatedSettings(v, settings)
+
+			assert.Equal(t, test.expectedInUse, inUse)
+			assert.Equal(t, test.expectedFlag, v.GetBool("new.flag"))
+			assert.Equal(t, test.expectedPort, v.GetInt("new.section.port"))
+		})
+	}
+}
+
+func TestNewWithDeprecatedSettings(t *testing.T) {
+	testCases := []struct {
+		description   string
+		yaml          string
+		expectedError string
+	}{
+		{
+			description: "migrated",
+			yaml:        "enable_gzip: true\n",
+		},
+		{
+			description:   "strict",
+			yaml:          "enable_gzip: true\nstrict_deprecated_settings: true\n",
+			expectedError: "enable_gzip is deprecated and must be replaced with compression.response.enable_gzip",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			v := viper.New()
+			SetupViper(v, "", bidderInfos)
+			v.Set("gdpr.default_value", "0")
+			v.SetConfigType("yaml")
+			assert.NoError(t, v.ReadConfig(bytes.NewBufferString(test.yaml)))
+
+			cfg, err := New(v, bidderInfos, mockNormalizeBidderName)
+
+			assert.Equal(t, []DeprecatedSetting{{Key: "enable_gzip", Replacement: "compression.response.enable_gzip"}}, cfg.DeprecatedSettingsInUse())
+			assert.True(t, cfg.Compression.Response.GZIP)
+			if test.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, test.expectedError)
+			}
+		})
+	}
+}
@@ -1,5 +1,7 @@
 package config
 
+import "fmt"
+
 type Hooks struct {
 	Enabled bool    `mapstructure:"enabled"`
 	Modules Modules `mapstructure:"modules"`
@@ -7,6 +9,31 @@ type Hooks struct {
 	HostExecutionPlan HookExecutionPlan `mapstructure:"host_execution_plan"`
 	// DefaultAccountExecutionPlan can be replaced by the account-specific hook execution plan
 	DefaultAccountExecutionPlan HookExecutionPlan `mapstructure:"default_account_execution_plan"`
+	// Canaries run a canary version of a module instead of the module for a share of the requests
+	Canaries []ModuleCanary `mapstructure:"canaries"`
+}
+
+// ModuleCanary names a module and its canary version, which is another module run in its place for a
+// share of the requests. Both must be registered, and the canary must have the same hooks.
+type ModuleCanary struct {
+	// ModuleCode is the module in the execution plan, in the format: {vendor_name}.{module_name}
+	ModuleCode string `mapstructure:"module_code" json:"module_code"`
+	// CanaryModuleCode is the module run in its place, in the same format
+	CanaryModuleCode string `mapstructure:"canary_module_code" json:"canary_module_code"`
+	// Percent is the share of requests, from 0 to 100, which run the canary
+	Percent float64 `mapstructure:"percent" json:"percent"`
+}
+
+func (cfg *Hooks) validate(errs []error) []error {
+	for _, canary := range cfg.Canaries {
+		if canary.ModuleCode == "" || canary.CanaryModuleCode == "" {
+			errs = append(errs, fmt.Errorf("hooks.canaries must have a module_code and a canary_module_code"))
+		}
+		if canary.Percent < 0 || canary.Percent > 100 {
+			errs = append(errs, fmt.Errorf("hooks.canaries.percent must be in the range [0, 100]. Got %f for %s", canary.Percent, canary.ModuleCode))
+		}
+	}
+	return errs
 }
 
 // Modules mapping provides module specific configuration, format: map[vendor_name]map[module_name]interface{}
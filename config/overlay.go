@@ -0,0 +1,54 @@
+package config
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// MergeOverlays merges additional config files on top of the configuration already read by SetupViper.
+// Files are applied in the order given, so a setting in a later file overrides the same setting in an
+// earlier file or in the base pbs.yaml/pbs.json. Environment variables still take precedence over every
+// file. The format of each overlay is determined by its file extension.
+//
+// The resulting precedence, from highest to lowest, is:
+//
+//  1. Environment variables (PBS_*)
+//  2. Overlay files, last to first
+//  3. The base config file (pbs.yaml, pbs.json, ...)
+//  4. Defaults defined in SetupViper
+func MergeOverlays(v *viper.Viper, files []string) error {
+	for _, file := range files {
+		file = strings.TrimSpace(file)
+		if file == "" {
+			continue
+		}
+		v.SetConfigFile(file)
+		if err := v.MergeInConfig(); err != nil {
+			return fmt.Errorf("failed to merge config overlay %s: %v", file, err)
+		}
+	}
+	return nil
+}
+
+// DumpEffectiveConfig writes every resolved setting known to viper as a sorted list of "key: value" lines.
+// It reflects the merged result of defaults, config files, overlays and environment variables. Values of
+// sensitive keys are redacted the same way as in the startup configuration log.
+func DumpEffectiveConfig(v *viper.Viper, w io.Writer) error {
+	keys := v.AllKeys()
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		value := fmt.Sprintf("%v", v.Get(key))
+		if !allowedName(key) {
+			value = "<REDACTED>"
+		}
+		if _, err := fmt.Fprintf(w, "%s: %s\n", key, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package config
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergeOverlays(t *testing.T) {
+	dir := t.TempDir()
+	writeFile := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+		return path
+	}
+
+	region := writeFile("region.yaml", "datacenter: us-east\nport: 9000\nhost_cookie:\n  domain: region.com\n")
+	env := writeFile("env.json", `{"port": 9100}`)
+	local := writeFile("local.yaml", "host_cookie:\n  family: local\n")
+
+	v := viper.New()
+	v.SetConfigType("yaml")
+	require.NoError(t, v.ReadConfig(strings.NewReader("datacenter: base\nport: 8000\nexternal_url: http://base.com\n")))
+
+	err := MergeOverlays(v, []string{region, " ", env, local})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "http://base.com", v.GetString("external_url"), "base value preserved")
+	assert.Equal(t, "us-east", v.GetString("datacenter"), "overlay overrides base")
+	assert.Equal(t, 9100, v.GetInt("port"), "later overlay overrides earlier overlay")
+	assert.Equal(t, "region.com", v.GetString("host_cookie.domain"), "nested value preserved")
+	assert.Equal(t, "local", v.GetString("host_cookie.family"), "nested values merged")
+}
+
+func TestMergeOverlaysMissingFile(t *testing.T) {
+	v := viper.New()
+	err := MergeOverlays(v, []string{filepath.Join(t.TempDir(), "missing.yaml")})
+	assert.ErrorContains(t, err, "failed to merge config overlay")
+}
+
+func TestDumpEffectiveConfig(t *testing.T) {
+	v := viper.New()
+	v.SetDefault("port", 8000)
+	v.Set("stored_requests.database.connection.password", "secret")
+	v.Set("datacenter", "us-east")
+
+	var out bytes.Buffer
+	err := DumpEffectiveConfig(v, &out)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "datacenter: us-east\nport: 8000\nstored_requests.database.connection.password: <REDACTED>\n", out.String())
+}
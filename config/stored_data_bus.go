@@ -0,0 +1,41 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// StoredDataBus configures the channel which propagates the saves and invalidations made through the
+// cache events APIs of one instance to the caches of every other instance of the fleet, so they don't
+// wait for their copies of the data to expire.
+type StoredDataBus struct {
+	Enabled bool `mapstructure:"enabled"`
+	// Channel is the Redis pub/sub channel the events are published on. Every instance of a fleet must use
+	// the same one.
+	Channel string `mapstructure:"channel"`
+	// Timeout is how long, in milliseconds, publishing an event may take.
+	Timeout int             `mapstructure:"timeout_ms"`
+	Redis   RedisConnection `mapstructure:"redis"`
+}
+
+// TimeoutDuration returns the publish timeout as a time.Duration
+func (cfg *StoredDataBus) TimeoutDuration() time.Duration {
+	return time.Duration(cfg.Timeout) * time.Millisecond
+}
+
+func (cfg *StoredDataBus) validate(errs []error) []error {
+	if !cfg.Enabled {
+		return errs
+	}
+	if cfg.Channel == "" {
+		errs = append(errs, errors.New("stored_data_bus.channel is required"))
+	}
+	if cfg.Timeout <= 0 {
+		errs = append(errs, fmt.Errorf("stored_data_bus.timeout_ms must be > 0. Got %d", cfg.Timeout))
+	}
+	if len(cfg.Redis.Addrs) == 0 {
+		errs = append(errs, errors.New("stored_data_bus.redis.addrs is required"))
+	}
+	return cfg.Redis.validate("stored_data_bus.redis", errs)
+}
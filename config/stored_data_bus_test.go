@@ -0,0 +1,48 @@
+package config
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStoredDataBusValidate(t *testing.T) {
+	tests := []struct {
+		description  string
+		cfg          StoredDataBus
+		expectedErrs []error
+	}{
+		{
+			description: "disabled",
+			cfg:         StoredDataBus{Enabled: false},
+		},
+		{
+			description: "valid",
+			cfg:         StoredDataBus{Enabled: true, Channel: "pbs-stored-data", Timeout: 500, Redis: RedisConnection{Addrs: []string{"redis:6379"}}},
+		},
+		{
+			description: "missing channel, timeout and addrs",
+			cfg:         StoredDataBus{Enabled: true},
+			expectedErrs: []error{
+				errors.New("stored_data_bus.channel is required"),
+				errors.New("stored_data_bus.timeout_ms must be > 0. Got 0"),
+				errors.New("stored_data_bus.redis.addrs is required"),
+			},
+		},
+		{
+			description: "invalid connection",
+			cfg:         StoredDataBus{Enabled: true, Channel: "pbs-stored-data", Timeout: 500, Redis: RedisConnection{Addrs: []string{"redis:6379"}, Cluster: true, DB: 1}},
+			expectedErrs: []error{
+				errors.New("stored_data_bus.redis.db must be 0 for a cluster. Got 1"),
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			errs := test.cfg.validate(nil)
+			assert.Equal(t, test.expectedErrs, errs)
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package config
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// Key providers supported for decrypting envelope-encrypted stored data
+const (
+	StoredDataKeyProviderLocal = "local"
+	StoredDataKeyProviderKMS   = "kms"
+)
+
+// StoredDataEncryption configures decryption of envelope-encrypted values found in stored requests,
+// stored responses and account configs. Each value carries its own data key, which is unwrapped by
+// the configured key provider.
+type StoredDataEncryption struct {
+	Enabled bool `mapstructure:"enabled"`
+	// KeyProvider is either "local", which unwraps data keys with MasterKey, or "kms", which asks
+	// the KMS endpoint to unwrap them.
+	KeyProvider string `mapstructure:"key_provider"`
+	// MasterKey is the base64 encoded 256 bit AES key used by the local key provider.
+	MasterKey string         `mapstructure:"master_key"`
+	KMS       KMSKeyProvider `mapstructure:"kms"`
+}
+
+// KMSKeyProvider configures the HTTP endpoint which unwraps data keys
+type KMSKeyProvider struct {
+	Endpoint  string `mapstructure:"endpoint"`
+	TimeoutMs int    `mapstructure:"timeout_ms"`
+}
+
+// TimeoutDuration returns the KMS request timeout
+func (cfg *KMSKeyProvider) TimeoutDuration() time.Duration {
+	return time.Duration(cfg.TimeoutMs) * time.Millisecond
+}
+
+func (cfg *StoredDataEncryption) validate(errs []error) []error {
+	if !cfg.Enabled {
+		return errs
+	}
+	switch cfg.KeyProvider {
+	case StoredDataKeyProviderLocal:
+		if key, err := base64.StdEncoding.DecodeString(cfg.MasterKey); err != nil || len(key) != 32 {
+			errs = append(errs, fmt.Errorf("stored_data_encryption.master_key must be a base64 encoded 32 byte key"))
+		}
+	case StoredDataKeyProviderKMS:
+		if _, err := url.ParseRequestURI(cfg.KMS.Endpoint); err != nil {
+			errs = append(errs, fmt.Errorf("stored_data_encryption.kms.endpoint must be a valid URL. Got %q", cfg.KMS.Endpoint))
+		}
+		if cfg.KMS.TimeoutMs <= 0 {
+			errs = append(errs, fmt.Errorf("stored_data_encryption.kms.timeout_ms must be > 0. Got %d", cfg.KMS.TimeoutMs))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("stored_data_encryption.key_provider must be one of %q or %q. Got %q", StoredDataKeyProviderLocal, StoredDataKeyProviderKMS, cfg.KeyProvider))
+	}
+	return errs
+}
@@ -0,0 +1,53 @@
+package config
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStoredDataEncryptionValidate(t *testing.T) {
+	tests := []struct {
+		description  string
+		cfg          StoredDataEncryption
+		expectedErrs []error
+	}{
+		{
+			description: "disabled",
+			cfg:         StoredDataEncryption{Enabled: false, KeyProvider: "invalid"},
+		},
+		{
+			description: "valid local",
+			cfg:         StoredDataEncryption{Enabled: true, KeyProvider: "local", MasterKey: "MDEyMzQ1Njc4OWFiY2RlZjAxMjM0NTY3ODlhYmNkZWY="},
+		},
+		{
+			description:  "local with short key",
+			cfg:          StoredDataEncryption{Enabled: true, KeyProvider: "local", MasterKey: "c2hvcnQ="},
+			expectedErrs: []error{errors.New("stored_data_encryption.master_key must be a base64 encoded 32 byte key")},
+		},
+		{
+			description: "valid kms",
+			cfg:         StoredDataEncryption{Enabled: true, KeyProvider: "kms", KMS: KMSKeyProvider{Endpoint: "http://kms.local/decrypt", TimeoutMs: 100}},
+		},
+		{
+			description: "kms without endpoint or timeout",
+			cfg:         StoredDataEncryption{Enabled: true, KeyProvider: "kms"},
+			expectedErrs: []error{
+				errors.New(`stored_data_encryption.kms.endpoint must be a valid URL. Got ""`),
+				errors.New("stored_data_encryption.kms.timeout_ms must be > 0. Got 0"),
+			},
+		},
+		{
+			description:  "unknown key provider",
+			cfg:          StoredDataEncryption{Enabled: true, KeyProvider: "vault"},
+			expectedErrs: []error{errors.New(`stored_data_encryption.key_provider must be one of "local" or "kms". Got "vault"`)},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			assert.Equal(t, test.expectedErrs, test.cfg.validate(nil))
+		})
+	}
+}
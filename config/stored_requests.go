@@ -2,10 +2,11 @@ package config
 
 import (
 	"fmt"
+	"net/url"
 	"strings"
 	"time"
 
-	"github.com/golang/glog"
+	"github.com/prebid/prebid-server/v2/logger"
 )
 
 // DataType constants
@@ -62,6 +63,12 @@ type StoredRequests struct {
 	// HTTP configures an instance of stored_requests/backends/http/http_fetcher.go.
 	// If non-nil, Stored Requests will be fetched from the endpoint described there.
 	HTTP HTTPFetcherConfig `mapstructure:"http"`
+	// Redis configures an instance of stored_requests/backends/redis_fetcher/fetcher.go.
+	// If it has addresses, Stored Requests will be fetched from the Redis server or cluster there.
+	Redis RedisFetcherConfig `mapstructure:"redis"`
+	// Bucket configures an instance of stored_requests/backends/bucket_fetcher/fetcher.go.
+	// If it has a name, Stored Requests will be synced from the S3 or GCS bucket there.
+	Bucket BucketFetcherConfig `mapstructure:"bucket"`
 	// InMemoryCache configures an instance of stored_requests/caches/memory/cache.go.
 	// If non-nil, Stored Requests will be saved in an in-memory cache.
 	InMemoryCache InMemoryCache `mapstructure:"in_memory_cache"`
@@ -103,6 +110,14 @@ type FileFetcherConfig struct {
 	Enabled bool `mapstructure:"enabled"`
 	// Path to the directory this file fetcher gets data from.
 	Path string `mapstructure:"directorypath"`
+	// WatchInterval is how often, in seconds, the directory is re-read so that changes are applied without a
+	// restart. This supports directories mounted from Kubernetes ConfigMaps or Secrets. 0 disables watching.
+	WatchInterval int `mapstructure:"watch_interval_seconds"`
+}
+
+// WatchIntervalDuration returns the directory watch interval as a time.Duration
+func (cfg FileFetcherConfig) WatchIntervalDuration() time.Duration {
+	return time.Duration(cfg.WatchInterval) * time.Second
 }
 
 // HTTPFetcherConfig configures a stored_requests/backends/http_fetcher/fetcher.go
@@ -111,6 +126,142 @@ type HTTPFetcherConfig struct {
 	AmpEndpoint string `mapstructure:"amp_endpoint"`
 }
 
+// RedisFetcherConfig configures a stored_requests/backends/redis_fetcher/fetcher.go
+type RedisFetcherConfig struct {
+	RedisConnection `mapstructure:",squash"`
+	// KeyPrefix is put in front of the keys of the data, like {key_prefix}request:{id}.
+	KeyPrefix string `mapstructure:"key_prefix"`
+	// Timeout is how long, in milliseconds, each fetch may take.
+	Timeout int `mapstructure:"timeout_ms"`
+}
+
+// TimeoutDuration returns the fetch timeout as a time.Duration
+func (cfg RedisFetcherConfig) TimeoutDuration() time.Duration {
+	return time.Duration(cfg.Timeout) * time.Millisecond
+}
+
+func (cfg *RedisFetcherConfig) validate(section string, errs []error) []error {
+	if len(cfg.Addrs) == 0 {
+		return errs
+	}
+	if cfg.Timeout <= 0 {
+		errs = append(errs, fmt.Errorf("%s.redis.timeout_ms must be > 0. Got %d", section, cfg.Timeout))
+	}
+	return cfg.RedisConnection.validate(section+".redis", errs)
+}
+
+// Object storage providers the bucket fetcher supports
+const (
+	BucketProviderS3  = "s3"
+	BucketProviderGCS = "gcs"
+)
+
+// BucketFetcherConfig configures a stored_requests/backends/bucket_fetcher/fetcher.go
+type BucketFetcherConfig struct {
+	// Provider is either "s3" or "gcs".
+	Provider string `mapstructure:"provider"`
+	// Name is the name of the bucket.
+	Name string `mapstructure:"name"`
+	// Prefix is the "directory" of the bucket the data is in, like {prefix}/stored_requests/{id}.json.
+	Prefix string `mapstructure:"prefix"`
+	// Region is the region of an S3 bucket.
+	Region string `mapstructure:"region"`
+	// Endpoint replaces the provider's endpoint, for S3 compatible storage like MinIO. The bucket is then
+	// addressed in the path.
+	Endpoint string `mapstructure:"endpoint"`
+	// AccessKeyID and SecretAccessKey are the credentials requests are signed with, which for GCS are HMAC
+	// keys. Requests are anonymous without them.
+	AccessKeyID     string `mapstructure:"access_key_id"`
+	SecretAccessKey string `mapstructure:"secret_access_key"`
+	// SessionToken is sent along with temporary credentials.
+	SessionToken string `mapstructure:"session_token"`
+	// SyncInterval is how often, in seconds, the bucket is synced. 0 only syncs it on startup and when
+	// the refresh endpoint is called.
+	SyncInterval int `mapstructure:"sync_interval_seconds"`
+	// Timeout is how long, in milliseconds, each sync may take.
+	Timeout int `mapstructure:"timeout_ms"`
+	// RefreshEndpoint is the url path which syncs the bucket when POSTed to. Empty doesn't expose one.
+	RefreshEndpoint string `mapstructure:"refresh_endpoint"`
+}
+
+// SyncIntervalDuration returns the sync interval as a time.Duration
+func (cfg BucketFetcherConfig) SyncIntervalDuration() time.Duration {
+	return time.Duration(cfg.SyncInterval) * time.Second
+}
+
+// TimeoutDuration returns the sync timeout as a time.Duration
+func (cfg BucketFetcherConfig) TimeoutDuration() time.Duration {
+	return time.Duration(cfg.Timeout) * time.Millisecond
+}
+
+func (cfg *BucketFetcherConfig) validate(section string, errs []error) []error {
+	if cfg.Name == "" {
+		return errs
+	}
+	switch cfg.Provider {
+	case BucketProviderS3:
+		if cfg.Region == "" {
+			errs = append(errs, fmt.Errorf("%s.bucket.region is required for s3", section))
+		}
+	case BucketProviderGCS:
+	default:
+		errs = append(errs, fmt.Errorf("%s.bucket.provider must be one of %q or %q. Got %q", section, BucketProviderS3, BucketProviderGCS, cfg.Provider))
+	}
+	if cfg.Endpoint != "" {
+		if _, err := url.ParseRequestURI(cfg.Endpoint); err != nil {
+			errs = append(errs, fmt.Errorf("%s.bucket.endpoint must be a valid URL. Got %q", section, cfg.Endpoint))
+		}
+	}
+	if (cfg.AccessKeyID == "") != (cfg.SecretAccessKey == "") {
+		errs = append(errs, fmt.Errorf("%s.bucket.access_key_id and %s.bucket.secret_access_key must be set together", section, section))
+	}
+	if cfg.SyncInterval < 0 {
+		errs = append(errs, fmt.Errorf("%s.bucket.sync_interval_seconds must be >= 0. Got %d", section, cfg.SyncInterval))
+	}
+	if cfg.Timeout <= 0 {
+		errs = append(errs, fmt.Errorf("%s.bucket.timeout_ms must be > 0. Got %d", section, cfg.Timeout))
+	}
+	return errs
+}
+
+// RedisConnection configures the connections to a Redis server or cluster
+type RedisConnection struct {
+	// Addrs are the host:port addresses of the server, or of some of the nodes of a cluster.
+	Addrs []string `mapstructure:"addrs"`
+	// Cluster connects to a Redis Cluster, whose other nodes are discovered from the addresses.
+	Cluster  bool   `mapstructure:"cluster"`
+	Username string `mapstructure:"username"`
+	Password string `mapstructure:"password"`
+	// DB is the database of the server the data is in. Clusters only have database 0.
+	DB int `mapstructure:"db"`
+	// PoolSize is the most connections kept to each node. 0 uses the client's default.
+	PoolSize int      `mapstructure:"pool_size"`
+	TLS      RedisTLS `mapstructure:"tls"`
+}
+
+func (cfg *RedisConnection) validate(section string, errs []error) []error {
+	if cfg.PoolSize < 0 {
+		errs = append(errs, fmt.Errorf("%s.pool_size must be >= 0. Got %d", section, cfg.PoolSize))
+	}
+	if cfg.Cluster && cfg.DB != 0 {
+		errs = append(errs, fmt.Errorf("%s.db must be 0 for a cluster. Got %d", section, cfg.DB))
+	}
+	if (cfg.TLS.ClientCert == "") != (cfg.TLS.ClientKey == "") {
+		errs = append(errs, fmt.Errorf("%s.tls.client_cert and %s.tls.client_key must be set together", section, section))
+	}
+	return errs
+}
+
+// RedisTLS configures TLS for the connections to Redis
+type RedisTLS struct {
+	Enabled bool `mapstructure:"enabled"`
+	// RootCert is the CA certificate file the server certificate is verified with, rather than the system's.
+	RootCert string `mapstructure:"root_cert"`
+	// ClientCert and ClientKey are the certificate and key files of the client, for servers which verify it.
+	ClientCert string `mapstructure:"client_cert"`
+	ClientKey  string `mapstructure:"client_key"`
+}
+
 // Migrate combined stored_requests+amp configuration to separate simple config sections
 func resolvedStoredRequestsConfig(cfg *Configuration) {
 	sr := &cfg.StoredRequests
@@ -137,7 +288,11 @@ func resolvedStoredRequestsConfig(cfg *Configuration) {
 }
 
 func (cfg *StoredRequests) validate(errs []error) []error {
-	if cfg.DataType() == AccountDataType && cfg.Database.ConnectionInfo.Database != "" {
+	if cfg.Files.WatchInterval < 0 {
+		errs = append(errs, fmt.Errorf("%s.filesystem.watch_interval_seconds must be >= 0. Got %d", cfg.Section(), cfg.Files.WatchInterval))
+	}
+	// accounts may only listen to the database for changes to the accounts fetched from elsewhere
+	if cfg.DataType() == AccountDataType && cfg.Database.ConnectionInfo.Database != "" && (cfg.Database.retrievesData() || cfg.Database.ListenNotify.Channel == "") {
 		errs = append(errs, fmt.Errorf("%s.database: retrieving accounts via database not available, use accounts.files", cfg.Section()))
 	} else {
 		errs = cfg.Database.validate(cfg.DataType(), errs)
@@ -145,8 +300,16 @@ func (cfg *StoredRequests) validate(errs []error) []error {
 
 	// Categories do not use cache so none of the following checks apply
 	if cfg.DataType() == CategoryDataType {
+		if len(cfg.Redis.Addrs) > 0 {
+			errs = append(errs, fmt.Errorf("%s.redis: retrieving categories via redis not available, use categories.filesystem or categories.http", cfg.Section()))
+		}
+		if cfg.Bucket.Name != "" {
+			errs = append(errs, fmt.Errorf("%s.bucket: retrieving categories via bucket not available, use categories.filesystem or categories.http", cfg.Section()))
+		}
 		return errs
 	}
+	errs = cfg.Redis.validate(cfg.Section(), errs)
+	errs = cfg.Bucket.validate(cfg.Section(), errs)
 
 	if cfg.InMemoryCache.Type == "none" {
 		if cfg.CacheEvents.Enabled {
@@ -163,6 +326,9 @@ func (cfg *StoredRequests) validate(errs []error) []error {
 		if cfg.Database.CacheInitialization.Query != "" {
 			errs = append(errs, fmt.Errorf("%s: database.initialize_caches.query must be empty if in_memory_cache=none", cfg.Section()))
 		}
+		if cfg.Database.ListenNotify.Channel != "" {
+			errs = append(errs, fmt.Errorf("%s: database.listen_notify.channel must be empty if in_memory_cache=none", cfg.Section()))
+		}
 	}
 	errs = cfg.InMemoryCache.validate(cfg.DataType(), errs)
 	return errs
@@ -175,6 +341,7 @@ type DatabaseConfig struct {
 	FetcherQueries      DatabaseFetcherQueries   `mapstructure:"fetcher"`
 	CacheInitialization DatabaseCacheInitializer `mapstructure:"initialize_caches"`
 	PollUpdates         DatabaseUpdatePolling    `mapstructure:"poll_for_updates"`
+	ListenNotify        DatabaseListenNotify     `mapstructure:"listen_notify"`
 }
 
 func (cfg *DatabaseConfig) validate(dataType DataType, errs []error) []error {
@@ -184,9 +351,15 @@ func (cfg *DatabaseConfig) validate(dataType DataType, errs []error) []error {
 
 	errs = cfg.CacheInitialization.validate(dataType, errs)
 	errs = cfg.PollUpdates.validate(dataType, errs)
+	errs = cfg.ListenNotify.validate(dataType, cfg.ConnectionInfo.Driver, errs)
 	return errs
 }
 
+// retrievesData returns true if data is fetched from the database, rather than changes to it only listened for.
+func (cfg *DatabaseConfig) retrievesData() bool {
+	return cfg.FetcherQueries.QueryTemplate != "" || cfg.CacheInitialization.Query != "" || cfg.PollUpdates.Query != ""
+}
+
 // DatabaseConnection has options which put types to the Database Connection string. See:
 // https://godoc.org/github.com/lib/pq#hdr-Connection_String_Parameters
 type DatabaseConnection struct {
@@ -308,6 +481,48 @@ func (cfg *DatabaseUpdatePolling) validate(dataType DataType, errs []error) []er
 	return errs
 }
 
+// DatabaseListenNotify configures stored_requests/events/postgres, which drops Stored Data from the cache as soon as
+// a Postgres database announces its change with NOTIFY, rather than on the next poll.
+type DatabaseListenNotify struct {
+	// Channel is the channel LISTENed on. The payload of its notifications names the changed data, like:
+	//
+	// {"requests": ["request1"], "imps": ["imp1"], "responses": ["resp1"], "accounts": ["acc1"]}
+	//
+	// An empty channel doesn't listen.
+	Channel string `mapstructure:"channel"`
+	// MinReconnectInterval is how long to wait before reconnecting after the connection is lost. It doubles
+	// after each failed attempt, up to MaxReconnectInterval.
+	MinReconnectInterval int `mapstructure:"min_reconnect_interval_ms"`
+	MaxReconnectInterval int `mapstructure:"max_reconnect_interval_ms"`
+}
+
+func (cfg *DatabaseListenNotify) validate(dataType DataType, driver string, errs []error) []error {
+	section := dataType.Section()
+	if cfg.Channel == "" {
+		return errs
+	}
+	if driver != "postgres" {
+		errs = append(errs, fmt.Errorf("%s: database.listen_notify is only supported by the postgres driver. Got %s", section, driver))
+	}
+	if cfg.MinReconnectInterval <= 0 {
+		errs = append(errs, fmt.Errorf("%s: database.listen_notify.min_reconnect_interval_ms must be > 0. Got %d", section, cfg.MinReconnectInterval))
+	}
+	if cfg.MaxReconnectInterval < cfg.MinReconnectInterval {
+		errs = append(errs, fmt.Errorf("%s: database.listen_notify.max_reconnect_interval_ms must be >= min_reconnect_interval_ms. Got %d", section, cfg.MaxReconnectInterval))
+	}
+	return errs
+}
+
+// MinReconnectIntervalDuration returns the minimum reconnect interval as a time.Duration
+func (cfg DatabaseListenNotify) MinReconnectIntervalDuration() time.Duration {
+	return time.Duration(cfg.MinReconnectInterval) * time.Millisecond
+}
+
+// MaxReconnectIntervalDuration returns the maximum reconnect interval as a time.Duration
+func (cfg DatabaseListenNotify) MaxReconnectIntervalDuration() time.Duration {
+	return time.Duration(cfg.MaxReconnectInterval) * time.Millisecond
+}
+
 type InMemoryCache struct {
 	// Identify the type of memory cache. "none", "unbounded", "lru"
 	Type string `mapstructure:"type"`
@@ -357,7 +572,7 @@ func (cfg *InMemoryCache) validate(dataType DataType, errs []error) []error {
 				errs = append(errs, fmt.Errorf("%s: in_memory_cache.size_bytes must be >= 0 when in_memory_cache.type=lru. Got %d", section, cfg.Size))
 			}
 			if cfg.RequestCacheSize > 0 || cfg.ImpCacheSize > 0 || cfg.RespCacheSize > 0 {
-				glog.Warningf("%s: in_memory_cache.request_cache_size_bytes, imp_cache_size_bytes and resp_cache_size_bytes do not apply to this section and will be ignored", section)
+				logger.Warningf("%s: in_memory_cache.request_cache_size_bytes, imp_cache_size_bytes and resp_cache_size_bytes do not apply to this section and will be ignored", section)
 			}
 		} else {
 			// dual (request and imp) caches
@@ -371,7 +586,7 @@ func (cfg *InMemoryCache) validate(dataType DataType, errs []error) []error {
 				errs = append(errs, fmt.Errorf("%s: in_memory_cache.resp_cache_size_bytes must be >= 0 when in_memory_cache.type=lru. Got %d", section, cfg.RespCacheSize))
 			}
 			if cfg.Size > 0 {
-				glog.Warningf("%s: in_memory_cache.size_bytes does not apply in this section and will be ignored", section)
+				logger.Warningf("%s: in_memory_cache.size_bytes does not apply in this section and will be ignored", section)
 			}
 		}
 	default:
@@ -205,6 +205,144 @@ func TestDatabaseConfigValidation(t *testing.T) {
 	}
 }
 
+func TestDatabaseListenNotifyValidation(t *testing.T) {
+	tests := []struct {
+		description  string
+		driver       string
+		listenNotify DatabaseListenNotify
+		wantErrors   []error
+	}{
+		{
+			description:  "Not listening",
+			driver:       "mysql",
+			listenNotify: DatabaseListenNotify{},
+		},
+		{
+			description:  "Valid",
+			driver:       "postgres",
+			listenNotify: DatabaseListenNotify{Channel: "stored_data", MinReconnectInterval: 1000, MaxReconnectInterval: 60000},
+		},
+		{
+			description:  "Not postgres",
+			driver:       "mysql",
+			listenNotify: DatabaseListenNotify{Channel: "stored_data", MinReconnectInterval: 1000, MaxReconnectInterval: 60000},
+			wantErrors:   []error{errors.New("stored_requests: database.listen_notify is only supported by the postgres driver. Got mysql")},
+		},
+		{
+			description:  "Invalid reconnect intervals",
+			driver:       "postgres",
+			listenNotify: DatabaseListenNotify{Channel: "stored_data", MinReconnectInterval: 0, MaxReconnectInterval: -1},
+			wantErrors: []error{
+				errors.New("stored_requests: database.listen_notify.min_reconnect_interval_ms must be > 0. Got 0"),
+				errors.New("stored_requests: database.listen_notify.max_reconnect_interval_ms must be >= min_reconnect_interval_ms. Got -1"),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		errs := tt.listenNotify.validate(RequestDataType, tt.driver, nil)
+		assert.Equal(t, tt.wantErrors, errs, tt.description)
+	}
+}
+
+func TestRedisFetcherValidation(t *testing.T) {
+	tests := []struct {
+		description string
+		dataType    DataType
+		redis       RedisFetcherConfig
+		wantErrors  []error
+	}{
+		{
+			description: "Not configured",
+			dataType:    RequestDataType,
+			redis:       RedisFetcherConfig{Timeout: 0},
+		},
+		{
+			description: "Valid",
+			dataType:    AccountDataType,
+			redis:       RedisFetcherConfig{RedisConnection: RedisConnection{Addrs: []string{"redis-1:6379", "redis-2:6379"}, Cluster: true, TLS: RedisTLS{Enabled: true, ClientCert: "client.pem", ClientKey: "client.key"}}, Timeout: 100},
+		},
+		{
+			description: "Invalid",
+			dataType:    RequestDataType,
+			redis:       RedisFetcherConfig{RedisConnection: RedisConnection{Addrs: []string{"redis-1:6379"}, Cluster: true, DB: 2, PoolSize: -1, TLS: RedisTLS{Enabled: true, ClientCert: "client.pem"}}, Timeout: 0},
+			wantErrors: []error{
+				errors.New("stored_requests.redis.timeout_ms must be > 0. Got 0"),
+				errors.New("stored_requests.redis.pool_size must be >= 0. Got -1"),
+				errors.New("stored_requests.redis.db must be 0 for a cluster. Got 2"),
+				errors.New("stored_requests.redis.tls.client_cert and stored_requests.redis.tls.client_key must be set together"),
+			},
+		},
+		{
+			description: "Categories",
+			dataType:    CategoryDataType,
+			redis:       RedisFetcherConfig{RedisConnection: RedisConnection{Addrs: []string{"redis-1:6379"}}, Timeout: 100},
+			wantErrors:  []error{errors.New("categories.redis: retrieving categories via redis not available, use categories.filesystem or categories.http")},
+		},
+	}
+
+	for _, tt := range tests {
+		cfg := StoredRequests{dataType: tt.dataType, Redis: tt.redis, InMemoryCache: InMemoryCache{Type: "none"}}
+		errs := cfg.validate(nil)
+		assert.Equal(t, tt.wantErrors, errs, tt.description)
+	}
+}
+
+func TestBucketFetcherValidation(t *testing.T) {
+	tests := []struct {
+		description string
+		dataType    DataType
+		bucket      BucketFetcherConfig
+		wantErrors  []error
+	}{
+		{
+			description: "Not configured",
+			dataType:    RequestDataType,
+			bucket:      BucketFetcherConfig{Provider: "invalid"},
+		},
+		{
+			description: "Valid s3",
+			dataType:    AccountDataType,
+			bucket:      BucketFetcherConfig{Provider: "s3", Name: "pbs-data", Region: "us-east-1", AccessKeyID: "id", SecretAccessKey: "secret", SyncInterval: 60, Timeout: 10000},
+		},
+		{
+			description: "Valid gcs",
+			dataType:    RequestDataType,
+			bucket:      BucketFetcherConfig{Provider: "gcs", Name: "pbs-data", Endpoint: "https://storage.googleapis.com", Timeout: 10000},
+		},
+		{
+			description: "S3 without region",
+			dataType:    RequestDataType,
+			bucket:      BucketFetcherConfig{Provider: "s3", Name: "pbs-data", Timeout: 10000},
+			wantErrors:  []error{errors.New("stored_requests.bucket.region is required for s3")},
+		},
+		{
+			description: "Invalid",
+			dataType:    ResponseDataType,
+			bucket:      BucketFetcherConfig{Provider: "azure", Name: "pbs-data", Endpoint: "minio", AccessKeyID: "id", SyncInterval: -1},
+			wantErrors: []error{
+				errors.New(`stored_responses.bucket.provider must be one of "s3" or "gcs". Got "azure"`),
+				errors.New(`stored_responses.bucket.endpoint must be a valid URL. Got "minio"`),
+				errors.New("stored_responses.bucket.access_key_id and stored_responses.bucket.secret_access_key must be set together"),
+				errors.New("stored_responses.bucket.sync_interval_seconds must be >= 0. Got -1"),
+				errors.New("stored_responses.bucket.timeout_ms must be > 0. Got 0"),
+			},
+		},
+		{
+			description: "Categories",
+			dataType:    CategoryDataType,
+			bucket:      BucketFetcherConfig{Provider: "gcs", Name: "pbs-data", Timeout: 10000},
+			wantErrors:  []error{errors.New("categories.bucket: retrieving categories via bucket not available, use categories.filesystem or categories.http")},
+		},
+	}
+
+	for _, tt := range tests {
+		cfg := StoredRequests{dataType: tt.dataType, Bucket: tt.bucket, InMemoryCache: InMemoryCache{Type: "none"}}
+		errs := cfg.validate(nil)
+		assert.Equal(t, tt.wantErrors, errs, tt.description)
+	}
+}
+
 func assertErrsExist(t *testing.T, err []error) {
 	t.Helper()
 	if len(err) == 0 {
@@ -6,7 +6,7 @@ import (
 	"regexp"
 	"strings"
 
-	"github.com/golang/glog"
+	"github.com/prebid/prebid-server/v2/logger"
 )
 
 type logMsg func(string, ...interface{})
@@ -14,63 +14,64 @@ type logMsg func(string, ...interface{})
 var mapregex = regexp.MustCompile(`mapstructure:"([^"]+)"`)
 var blocklistregexp = []*regexp.Regexp{
 	regexp.MustCompile("password"),
+	regexp.MustCompile("master_key"),
 }
 
 // LogGeneral will log nearly any sort of value, but requires the name of the root object to be in the
 // prefix if you want that name to be logged. Structs will append .<fieldname> recursively to the prefix
 // to document deeper structure.
 func logGeneral(v reflect.Value, prefix string) {
-	logGeneralWithLogger(v, prefix, glog.Infof)
+	logGeneralWithLogger(v, prefix, logger.Infof)
 }
 
-func logGeneralWithLogger(v reflect.Value, prefix string, logger logMsg) {
+func logGeneralWithLogger(v reflect.Value, prefix string, logFn logMsg) {
 	switch v.Kind() {
 	case reflect.Struct:
-		logStructWithLogger(v, prefix, logger)
+		logStructWithLogger(v, prefix, logFn)
 	case reflect.Map:
-		logMapWithLogger(v, prefix, logger)
+		logMapWithLogger(v, prefix, logFn)
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-		logger("%s: %d", prefix, v.Int())
+		logFn("%s: %d", prefix, v.Int())
 	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-		logger("%s: %d", prefix, v.Uint())
+		logFn("%s: %d", prefix, v.Uint())
 	case reflect.Float32, reflect.Float64:
-		logger("%s: %f", prefix, v.Float())
+		logFn("%s: %f", prefix, v.Float())
 	case reflect.Bool:
-		logger("%s: %t", prefix, v.Bool())
+		logFn("%s: %t", prefix, v.Bool())
 	default:
 		// logString, by using v.String(), will not fail, and indicate what additional cases we need to handle
-		logger("%s: %s", prefix, v.String())
+		logFn("%s: %s", prefix, v.String())
 	}
 }
 
-func logStructWithLogger(v reflect.Value, prefix string, logger logMsg) {
+func logStructWithLogger(v reflect.Value, prefix string, logFn logMsg) {
 	if v.Kind() != reflect.Struct {
-		glog.Fatalf("LogStruct called on type %s, whuch is not a struct!", v.Type().String())
+		logger.Fatalf("LogStruct called on type %s, whuch is not a struct!", v.Type().String())
 	}
 	t := v.Type()
 	for i := 0; i < t.NumField(); i++ {
 		fieldname := fieldNameByTag(t.Field(i))
 		if allowedName(fieldname) {
-			logGeneralWithLogger(v.Field(i), extendPrefix(prefix, fieldname), logger)
+			logGeneralWithLogger(v.Field(i), extendPrefix(prefix, fieldname), logFn)
 		} else {
-			logger("%s.%s: <REDACTED>", prefix, fieldname)
+			logFn("%s.%s: <REDACTED>", prefix, fieldname)
 		}
 	}
 }
 
-func logMapWithLogger(v reflect.Value, prefix string, logger logMsg) {
+func logMapWithLogger(v reflect.Value, prefix string, logFn logMsg) {
 	if v.Kind() != reflect.Map {
-		glog.Fatalf("LogMap called on type %s, whuch is not a map!", v.Type().String())
+		logger.Fatalf("LogMap called on type %s, whuch is not a map!", v.Type().String())
 	}
 	for _, k := range v.MapKeys() {
 		if k.Kind() == reflect.String && !allowedName(k.String()) {
-			logger("%s: <REDACTED>", extendMapPrefix(prefix, k.String()))
+			logFn("%s: <REDACTED>", extendMapPrefix(prefix, k.String()))
 		} else {
 			// Use Sprintf("%v", k.Interface) to handle non-string keys. Should not be possible to have a key
 			// too complex to represent by %v.
 			// NOTE: This will break if we have an unexported map in the object. If so we will have to switch
 			// on k.Kind() rather than rely on fmt.Sprintf("%v") doing that work.
-			logGeneralWithLogger(v.MapIndex(k), extendMapPrefix(prefix, fmt.Sprintf("%v", k.Interface())), logger)
+			logGeneralWithLogger(v.MapIndex(k), extendMapPrefix(prefix, fmt.Sprintf("%v", k.Interface())), logFn)
 		}
 	}
 }
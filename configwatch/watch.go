@@ -0,0 +1,182 @@
+// Package configwatch re-reads the host config files while the server runs, and applies the changes to the
+// settings which may change without a restart. It's meant for files mounted from Kubernetes ConfigMaps and
+// Secrets, which the kubelet updates in place. Changes to any other setting are logged, and only take effect
+// once the server is restarted.
+package configwatch
+
+import (
+	"crypto/sha256"
+	"errors"
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/prebid/prebid-server/v2/biddermaintenance"
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/logger"
+)
+
+// setting is a group of settings which may change without a restart.
+type setting struct {
+	// name lists the config keys of the settings, for the logs
+	name string
+	// get returns the settings of the config, to tell whether they changed
+	get func(cfg *config.Configuration) interface{}
+	// clear zeroes the settings in a copy of the config, so that the changes to other settings can be found
+	clear func(cfg *config.Configuration)
+	// apply puts the settings of the config into effect
+	apply func(w *Watcher, cfg *config.Configuration) error
+}
+
+// settings are the settings which may change without a restart. Any other change needs one.
+var settings = []setting{
+	{
+		name: "logging.level, logging.component_levels and logging.account_levels",
+		get:  func(cfg *config.Configuration) interface{} { return cfg.Logging.Levels() },
+		clear: func(cfg *config.Configuration) {
+			cfg.Logging.Level = ""
+			cfg.Logging.ComponentLevels = nil
+			cfg.Logging.AccountLevels = nil
+		},
+		apply: func(w *Watcher, cfg *config.Configuration) error {
+			return logger.SetLevels(cfg.Logging.Levels())
+		},
+	},
+	{
+		name: "logging.sampling, logging.redact_fields and logging.redact_patterns",
+		get:  func(cfg *config.Configuration) interface{} { return cfg.Logging.Policy() },
+		clear: func(cfg *config.Configuration) {
+			cfg.Logging.Sampling = nil
+			cfg.Logging.RedactFields = nil
+			cfg.Logging.RedactPatterns = nil
+		},
+		apply: func(w *Watcher, cfg *config.Configuration) error {
+			return logger.SetPolicy(cfg.Logging.Policy())
+		},
+	},
+	{
+		name: "bidder_maintenance.windows",
+		get:  func(cfg *config.Configuration) interface{} { return cfg.BidderMaintenance.Windows },
+		clear: func(cfg *config.Configuration) {
+			cfg.BidderMaintenance.Windows = nil
+		},
+		apply: func(w *Watcher, cfg *config.Configuration) error {
+			if w.maintenance == nil {
+				return errors.New("the server started without maintenance windows or kill switches, so it has none to change")
+			}
+			w.maintenance.SetWindows(cfg.BidderMaintenance.Windows)
+			return nil
+		},
+	},
+}
+
+// Watcher applies the changes to the config files each time it's run. It's meant to be run by a
+// task.TickerTask, and isn't safe for concurrent use.
+type Watcher struct {
+	files       []string
+	load        func() (*config.Configuration, error)
+	maintenance *biddermaintenance.Maintenance
+
+	cfg    *config.Configuration
+	digest [sha256.Size]byte
+}
+
+// NewWatcher builds a Watcher of the files the config was loaded from, which loads the config again from
+// them with load when they change. maintenance may be nil.
+func NewWatcher(cfg *config.Configuration, files []string, load func() (*config.Configuration, error), maintenance *biddermaintenance.Maintenance) *Watcher {
+	w := &Watcher{
+		files:       files,
+		load:        load,
+		maintenance: maintenance,
+		cfg:         cfg,
+	}
+	if digest, err := digestFiles(files); err == nil {
+		w.digest = digest
+	}
+	return w
+}
+
+// Run loads the config again if any of the files changed, and applies the changes to the settings which may
+// change without a restart. A config which can't be loaded, or is invalid, isn't applied at all.
+func (w *Watcher) Run() error {
+	digest, err := digestFiles(w.files)
+	if err != nil {
+		logger.Warningf("Config files couldn't be read to check them for changes: %v", err)
+		return err
+	}
+	if digest == w.digest {
+		return nil
+	}
+	// the digest is kept even if the config can't be loaded, so that the errors are only logged once
+	w.digest = digest
+
+	cfg, err := w.load()
+	if err != nil {
+		logger.Errorf("The config files changed, but weren't applied since they couldn't be loaded: %v", err)
+		return err
+	}
+	w.apply(cfg)
+	return nil
+}
+
+func (w *Watcher) apply(cfg *config.Configuration) {
+	for _, s := range settings {
+		if reflect.DeepEqual(s.get(w.cfg), s.get(cfg)) {
+			continue
+		}
+		if err := s.apply(w, cfg); err != nil {
+			logger.Errorf("Config %s changed, but couldn't be applied: %v", s.name, err)
+			continue
+		}
+		logger.Infof("Applied the changes to config %s", s.name)
+	}
+	if sections := restartSections(w.cfg, cfg); len(sections) > 0 {
+		logger.Warningf("Config sections %s changed, and only take effect once the server is restarted", strings.Join(sections, ", "))
+	}
+	w.cfg = cfg
+}
+
+// restartSections returns the top level config sections which changed, other than in the settings which may
+// change without a restart.
+func restartSections(before, after *config.Configuration) []string {
+	b, a := *before, *after
+	for _, s := range settings {
+		s.clear(&b)
+		s.clear(&a)
+	}
+
+	var sections []string
+	beforeValue, afterValue := reflect.ValueOf(b), reflect.ValueOf(a)
+	for i := 0; i < beforeValue.NumField(); i++ {
+		field := beforeValue.Type().Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		if reflect.DeepEqual(beforeValue.Field(i).Interface(), afterValue.Field(i).Interface()) {
+			continue
+		}
+		name, _, _ := strings.Cut(field.Tag.Get("mapstructure"), ",")
+		if name == "" {
+			name = field.Name
+		}
+		sections = append(sections, name)
+	}
+	return sections
+}
+
+// digestFiles hashes the contents of the files. Files are read through their links, which Kubernetes swaps
+// when it updates a mounted volume.
+func digestFiles(files []string) ([sha256.Size]byte, error) {
+	hash := sha256.New()
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return [sha256.Size]byte{}, err
+		}
+		hash.Write([]byte(file))
+		hash.Write(data)
+	}
+	var digest [sha256.Size]byte
+	copy(digest[:], hash.Sum(nil))
+	return digest, nil
+}
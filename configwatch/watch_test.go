@@ -0,0 +1,137 @@
+package configwatch
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/prebid/prebid-server/v2/biddermaintenance"
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/logger"
+	"github.com/prebid/prebid-server/v2/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRun(t *testing.T) {
+	defer logger.SetLevels(logger.GetLevels())
+
+	file := filepath.Join(t.TempDir(), "pbs.yaml")
+	require.NoError(t, os.WriteFile(file, []byte("logging:\n  level: info\n"), 0644))
+
+	window := config.BidderMaintenanceWindow{Bidder: "appnexus", Start: "2000-01-01T00:00:00Z", End: "9999-01-01T00:00:00Z"}
+	maintenance := biddermaintenance.New(config.BidderMaintenance{KillSwitches: config.BidderKillSwitches{Enabled: true, Tokens: []string{"token"}}})
+	initial := &config.Configuration{Logging: config.Logging{Level: "info"}}
+
+	loads := 0
+	next := &config.Configuration{
+		Port:              8001,
+		Logging:           config.Logging{Level: "debug", AccountLevels: map[string]string{"account1": "error"}},
+		BidderMaintenance: config.BidderMaintenance{Windows: []config.BidderMaintenanceWindow{window}},
+	}
+	w := NewWatcher(initial, []string{file}, func() (*config.Configuration, error) {
+		loads++
+		return next, nil
+	}, maintenance)
+
+	// unchanged files aren't loaded again
+	require.NoError(t, w.Run())
+	assert.Equal(t, 0, loads)
+
+	require.NoError(t, os.WriteFile(file, []byte("logging:\n  level: debug\n"), 0644))
+	require.NoError(t, w.Run())
+	assert.Equal(t, 1, loads)
+	assert.Equal(t, logger.Levels{Level: "debug", Accounts: map[string]string{"account1": "error"}}, logger.GetLevels())
+	reason, ok := maintenance.Check("appnexus", nil)
+	assert.True(t, ok)
+	assert.Equal(t, metrics.BidderMaintenanceWindow, reason)
+	assert.Same(t, next, w.cfg)
+
+	require.NoError(t, w.Run())
+	assert.Equal(t, 1, loads)
+}
+
+func TestRunInvalidConfig(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "pbs.yaml")
+	require.NoError(t, os.WriteFile(file, []byte("port: 8000\n"), 0644))
+
+	initial := &config.Configuration{Port: 8000}
+	loads := 0
+	w := NewWatcher(initial, []string{file}, func() (*config.Configuration, error) {
+		loads++
+		return &config.Configuration{Port: -1}, errors.New("validation errors")
+	}, nil)
+
+	require.NoError(t, os.WriteFile(file, []byte("port: -1\n"), 0644))
+	assert.Error(t, w.Run())
+	assert.Same(t, initial, w.cfg)
+
+	// the same invalid files aren't loaded again
+	assert.NoError(t, w.Run())
+	assert.Equal(t, 1, loads)
+}
+
+func TestRunMissingFile(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "pbs.yaml")
+	w := NewWatcher(&config.Configuration{}, []string{file}, func() (*config.Configuration, error) {
+		t.Fatal("the config mustn't be loaded")
+		return nil, nil
+	}, nil)
+
+	assert.Error(t, w.Run())
+}
+
+func TestApplyWithoutMaintenance(t *testing.T) {
+	initial := &config.Configuration{}
+	next := &config.Configuration{
+		BidderMaintenance: config.BidderMaintenance{Windows: []config.BidderMaintenanceWindow{{Bidder: "appnexus", Start: "2000-01-01T00:00:00Z", End: "9999-01-01T00:00:00Z"}}},
+	}
+	w := NewWatcher(initial, nil, nil, nil)
+
+	// the windows can't be applied, but the other settings still are
+	w.apply(next)
+	assert.Same(t, next, w.cfg)
+}
+
+func TestRestartSections(t *testing.T) {
+	testCases := []struct {
+		description      string
+		before           config.Configuration
+		after            config.Configuration
+		expectedSections []string
+	}{
+		{
+			description: "unchanged",
+			before:      config.Configuration{Port: 8000},
+			after:       config.Configuration{Port: 8000},
+		},
+		{
+			description: "reloadable-only",
+			before:      config.Configuration{Logging: config.Logging{Level: "info", RedactFields: []string{"ip"}}},
+			after:       config.Configuration{Logging: config.Logging{Level: "debug", Sampling: map[string]float64{"bidder_errors": 0.1}}},
+		},
+		{
+			description:      "logging-format",
+			before:           config.Configuration{Logging: config.Logging{Format: "json", Level: "info"}},
+			after:            config.Configuration{Logging: config.Logging{Format: "glog", Level: "debug"}},
+			expectedSections: []string{"logging"},
+		},
+		{
+			description: "restart",
+			before:      config.Configuration{Port: 8000, BidderMaintenance: config.BidderMaintenance{KillSwitches: config.BidderKillSwitches{Enabled: true}}},
+			after: config.Configuration{
+				Port:              8001,
+				BidderMaintenance: config.BidderMaintenance{Windows: []config.BidderMaintenanceWindow{{Bidder: "appnexus"}}},
+				StoredRequests:    config.StoredRequests{Files: config.FileFetcherConfig{Enabled: true}},
+			},
+			expectedSections: []string{"port", "stored_requests", "bidder_maintenance"},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			assert.ElementsMatch(t, test.expectedSections, restartSections(&test.before, &test.after))
+		})
+	}
+}
@@ -0,0 +1,102 @@
+// Package contentclassification fills in the categories of the content of a request's page or app, and the
+// contextual segments it's in, from a classifier, so that bidders can target content which publishers don't
+// categorize themselves. Classifications are cached by URL, and the classifier is only called if the auction
+// has time to spare, so the classifier's latency is only paid for content which hasn't been seen lately.
+package contentclassification
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/prebid/openrtb/v20/adcom1"
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/util/jsonutil"
+)
+
+// maxResponseSize limits the responses of the classification service.
+const maxResponseSize = 64 * 1024
+
+const (
+	channelSite = "site"
+	channelApp  = "app"
+)
+
+// Content is the content to classify.
+type Content struct {
+	URL string `json:"url"`
+	// Channel is site for the page of a site, or app for the content of an app
+	Channel string `json:"channel"`
+}
+
+// Classification is what a classifier found content to be about.
+type Classification struct {
+	// Categories are the categories of the content, in the taxonomy CatTax
+	Categories []string                `json:"cat,omitempty"`
+	CatTax     adcom1.CategoryTaxonomy `json:"cattax,omitempty"`
+	// Segments are the contextual segments the content is in, in the taxonomy SegTax
+	Segments []openrtb2.Segment `json:"segment,omitempty"`
+	SegTax   int                `json:"segtax,omitempty"`
+}
+
+// Classifier classifies content. Hosts with a classifier of their own, such as a local model, can implement it.
+type Classifier interface {
+	// Classify returns what the content is about. It must return once ctx is done.
+	Classify(ctx context.Context, content Content) (*Classification, error)
+}
+
+// NewClassifier returns the configured classifier, or nil if content classification is off.
+func NewClassifier(cfg config.ContentClassification, client *http.Client) Classifier {
+	if !cfg.Enabled {
+		return nil
+	}
+	switch cfg.Provider {
+	case config.ContentClassificationProviderHTTP:
+		return &httpClassifier{endpoint: cfg.HTTP.Endpoint, client: client}
+	default:
+		return nil
+	}
+}
+
+// httpClassifier classifies content with a service which is sent the content as JSON, and answers with the
+// classification as JSON.
+type httpClassifier struct {
+	endpoint string
+	client   *http.Client
+}
+
+func (c *httpClassifier) Classify(ctx context.Context, content Content) (*Classification, error) {
+	body, err := jsonutil.Marshal(content)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNoContent {
+		return &Classification{}, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		io.Copy(io.Discard, resp.Body)
+		return nil, fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+	respBody, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return nil, err
+	}
+	var classification Classification
+	if err := jsonutil.UnmarshalValid(respBody, &classification); err != nil {
+		return nil, err
+	}
+	return &classification, nil
+}
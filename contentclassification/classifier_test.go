@@ -0,0 +1,108 @@
+package contentclassification
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prebid/openrtb/v20/adcom1"
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewClassifier(t *testing.T) {
+	testCases := []struct {
+		name     string
+		cfg      config.ContentClassification
+		expected Classifier
+	}{
+		{
+			name:     "disabled",
+			cfg:      config.ContentClassification{Enabled: false, Provider: config.ContentClassificationProviderHTTP},
+			expected: nil,
+		},
+		{
+			name:     "http",
+			cfg:      config.ContentClassification{Enabled: true, Provider: config.ContentClassificationProviderHTTP, HTTP: config.ContentClassificationHTTP{Endpoint: "https://classifier.example.com"}},
+			expected: &httpClassifier{endpoint: "https://classifier.example.com", client: http.DefaultClient},
+		},
+		{
+			name:     "unknown-provider",
+			cfg:      config.ContentClassification{Enabled: true, Provider: "unknown"},
+			expected: nil,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, NewClassifier(test.cfg, http.DefaultClient))
+		})
+	}
+}
+
+func TestHTTPClassifierClassify(t *testing.T) {
+	testCases := []struct {
+		name                   string
+		status                 int
+		response               string
+		expectedClassification *Classification
+		expectedErr            bool
+	}{
+		{
+			name:     "classified",
+			status:   http.StatusOK,
+			response: `{"cat":["IAB17"],"cattax":1,"segment":[{"id":"483"}],"segtax":7}`,
+			expectedClassification: &Classification{
+				Categories: []string{"IAB17"},
+				CatTax:     adcom1.CatTaxIABContent10,
+				Segments:   []openrtb2.Segment{{ID: "483"}},
+				SegTax:     7,
+			},
+		},
+		{
+			name:                   "no-content",
+			status:                 http.StatusNoContent,
+			expectedClassification: &Classification{},
+		},
+		{
+			name:        "error-status",
+			status:      http.StatusInternalServerError,
+			response:    "failed",
+			expectedErr: true,
+		},
+		{
+			name:        "invalid-response",
+			status:      http.StatusOK,
+			response:    `{"cat":`,
+			expectedErr: true,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			var requestBody string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				requestBody = string(body)
+				w.WriteHeader(test.status)
+				w.Write([]byte(test.response))
+			}))
+			defer server.Close()
+
+			classifier := &httpClassifier{endpoint: server.URL, client: server.Client()}
+			classification, err := classifier.Classify(context.Background(), Content{URL: "https://news.example.com/sports", Channel: channelSite})
+
+			assert.JSONEq(t, `{"url":"https://news.example.com/sports","channel":"site"}`, requestBody)
+			if test.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expectedClassification, classification)
+		})
+	}
+}
@@ -0,0 +1,208 @@
+package contentclassification
+
+import (
+	"container/list"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/metrics"
+)
+
+// Enricher fills in the classification of the content of requests. A nil *Enricher is valid and leaves
+// requests as they are.
+type Enricher struct {
+	classifier    Classifier
+	timeout       time.Duration
+	minRemaining  time.Duration
+	dataName      string
+	metricsEngine metrics.MetricsEngine
+	now           func() time.Time
+
+	cache *classificationCache
+}
+
+// NewEnricher returns an enricher for the classifier, or nil if there's no classifier.
+func NewEnricher(cfg config.ContentClassification, classifier Classifier, metricsEngine metrics.MetricsEngine) *Enricher {
+	if classifier == nil {
+		return nil
+	}
+	return &Enricher{
+		classifier:    classifier,
+		timeout:       time.Duration(cfg.TimeoutMS) * time.Millisecond,
+		minRemaining:  time.Duration(cfg.MinRemainingMS) * time.Millisecond,
+		dataName:      cfg.DataName,
+		metricsEngine: metricsEngine,
+		now:           time.Now,
+		cache:         newClassificationCache(cfg.CacheSize, time.Duration(cfg.CacheTTLSeconds)*time.Second),
+	}
+}
+
+// Enrich classifies the content of the request's site.page, or app.content.url, and fills in the categories of
+// site.content or app.content if it has none, and adds the segments in a data object named by data_name.
+// Content which is already categorized and has the data object isn't classified. The site or app and its
+// content are copied before they're changed.
+//
+// The classification is taken from the cache if it's there. Otherwise the classifier is only called if ctx
+// leaves time for its timeout and min_remaining_ms after it, and the request is left as it is if it fails.
+func (e *Enricher) Enrich(ctx context.Context, req *openrtb2.BidRequest) {
+	if e == nil || req == nil {
+		return
+	}
+
+	var toClassify Content
+	var content *openrtb2.Content
+	switch {
+	case req.Site != nil:
+		content = req.Site.Content
+		toClassify = Content{URL: req.Site.Page, Channel: channelSite}
+	case req.App != nil && req.App.Content != nil:
+		content = req.App.Content
+		toClassify = Content{URL: req.App.Content.URL, Channel: channelApp}
+	}
+	toClassify.URL, _, _ = strings.Cut(toClassify.URL, "#")
+	if toClassify.URL == "" || (content != nil && len(content.Cat) > 0 && hasData(content, e.dataName)) {
+		return
+	}
+
+	classification, ok := e.classify(ctx, toClassify)
+	if !ok {
+		return
+	}
+	enriched := e.apply(content, classification)
+	if enriched == content {
+		return
+	}
+	if toClassify.Channel == channelSite {
+		site := *req.Site
+		site.Content = enriched
+		req.Site = &site
+	} else {
+		app := *req.App
+		app.Content = enriched
+		req.App = &app
+	}
+}
+
+func (e *Enricher) classify(ctx context.Context, content Content) (*Classification, bool) {
+	start := e.now()
+	if classification, ok := e.cache.get(content.URL, start); ok {
+		e.metricsEngine.RecordContentClassification(metrics.ContentClassificationCached, e.now().Sub(start))
+		return classification, true
+	}
+	if deadline, ok := ctx.Deadline(); ok && deadline.Sub(start) < e.timeout+e.minRemaining {
+		e.metricsEngine.RecordContentClassification(metrics.ContentClassificationSkipped, 0)
+		return nil, false
+	}
+
+	classifyCtx, cancel := context.WithTimeout(ctx, e.timeout)
+	defer cancel()
+	classification, err := e.classifier.Classify(classifyCtx, content)
+	if err != nil {
+		e.metricsEngine.RecordContentClassification(metrics.ContentClassificationError, e.now().Sub(start))
+		return nil, false
+	}
+	if classification == nil {
+		classification = &Classification{}
+	}
+	e.metricsEngine.RecordContentClassification(metrics.ContentClassificationClassified, e.now().Sub(start))
+	e.cache.put(content.URL, classification, e.now())
+	return classification, true
+}
+
+// apply returns a copy of the content with the classification filled in, or the content itself if the
+// classification adds nothing to it.
+func (e *Enricher) apply(content *openrtb2.Content, classification *Classification) *openrtb2.Content {
+	setCategories := len(classification.Categories) > 0 && (content == nil || len(content.Cat) == 0)
+	addSegments := len(classification.Segments) > 0 && (content == nil || !hasData(content, e.dataName))
+	if !setCategories && !addSegments {
+		return content
+	}
+
+	enriched := &openrtb2.Content{}
+	if content != nil {
+		*enriched = *content
+	}
+	if setCategories {
+		enriched.Cat = append([]string(nil), classification.Categories...)
+		enriched.CatTax = classification.CatTax
+	}
+	if addSegments {
+		data := openrtb2.Data{Name: e.dataName, Segment: append([]openrtb2.Segment(nil), classification.Segments...)}
+		if classification.SegTax > 0 {
+			data.Ext = json.RawMessage(fmt.Sprintf(`{"segtax":%d}`, classification.SegTax))
+		}
+		enriched.Data = append(enriched.Data[:len(enriched.Data):len(enriched.Data)], data)
+	}
+	return enriched
+}
+
+func hasData(content *openrtb2.Content, name string) bool {
+	for _, data := range content.Data {
+		if data.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// classificationCache keeps the classifications of the most recently classified URLs for a while.
+type classificationCache struct {
+	size int
+	ttl  time.Duration
+
+	lock    sync.Mutex
+	entries map[string]*list.Element
+	lru     *list.List
+}
+
+type classificationCacheEntry struct {
+	url            string
+	classification *Classification
+	expires        time.Time
+}
+
+func newClassificationCache(size int, ttl time.Duration) *classificationCache {
+	return &classificationCache{
+		size:    size,
+		ttl:     ttl,
+		entries: make(map[string]*list.Element),
+		lru:     list.New(),
+	}
+}
+
+func (c *classificationCache) get(url string, now time.Time) (*Classification, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	element, ok := c.entries[url]
+	if !ok {
+		return nil, false
+	}
+	entry := element.Value.(*classificationCacheEntry)
+	if !now.Before(entry.expires) {
+		c.lru.Remove(element)
+		delete(c.entries, url)
+		return nil, false
+	}
+	c.lru.MoveToFront(element)
+	return entry.classification, true
+}
+
+func (c *classificationCache) put(url string, classification *Classification, now time.Time) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if element, ok := c.entries[url]; ok {
+		c.lru.Remove(element)
+	}
+	c.entries[url] = c.lru.PushFront(&classificationCacheEntry{url: url, classification: classification, expires: now.Add(c.ttl)})
+	for c.lru.Len() > c.size {
+		oldest := c.lru.Back()
+		c.lru.Remove(oldest)
+		delete(c.entries, oldest.Value.(*classificationCacheEntry).url)
+	}
+}
@@ -0,0 +1,200 @@
+package contentclassification
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prebid/openrtb/v20/adcom1"
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type fakeClassifier struct {
+	classification *Classification
+	err            error
+	classified     []Content
+}
+
+func (c *fakeClassifier) Classify(ctx context.Context, content Content) (*Classification, error) {
+	c.classified = append(c.classified, content)
+	return c.classification, c.err
+}
+
+var sportsClassification = &Classification{
+	Categories: []string{"IAB17"},
+	CatTax:     adcom1.CatTaxIABContent10,
+	Segments:   []openrtb2.Segment{{ID: "483"}},
+	SegTax:     7,
+}
+
+func newTestEnricher(classifier Classifier, me metrics.MetricsEngine) *Enricher {
+	return NewEnricher(config.ContentClassification{
+		TimeoutMS:       50,
+		MinRemainingMS:  300,
+		CacheSize:       2,
+		CacheTTLSeconds: 60,
+		DataName:        "prebid-server",
+	}, classifier, me)
+}
+
+func TestNewEnricherWithoutClassifier(t *testing.T) {
+	assert.Nil(t, NewEnricher(config.ContentClassification{}, nil, &metrics.MetricsEngineMock{}))
+}
+
+func TestEnrich(t *testing.T) {
+	segments := []openrtb2.Data{{Name: "prebid-server", Segment: []openrtb2.Segment{{ID: "483"}}, Ext: json.RawMessage(`{"segtax":7}`)}}
+	testCases := []struct {
+		name               string
+		req                *openrtb2.BidRequest
+		classification     *Classification
+		expectedReq        *openrtb2.BidRequest
+		expectedClassified []Content
+	}{
+		{
+			name:               "site",
+			req:                &openrtb2.BidRequest{Site: &openrtb2.Site{Page: "https://news.example.com/sports#top"}},
+			classification:     sportsClassification,
+			expectedReq:        &openrtb2.BidRequest{Site: &openrtb2.Site{Page: "https://news.example.com/sports#top", Content: &openrtb2.Content{Cat: []string{"IAB17"}, CatTax: adcom1.CatTaxIABContent10, Data: segments}}},
+			expectedClassified: []Content{{URL: "https://news.example.com/sports", Channel: "site"}},
+		},
+		{
+			name:               "app",
+			req:                &openrtb2.BidRequest{App: &openrtb2.App{Bundle: "com.example", Content: &openrtb2.Content{URL: "https://videos.example.com/1"}}},
+			classification:     sportsClassification,
+			expectedReq:        &openrtb2.BidRequest{App: &openrtb2.App{Bundle: "com.example", Content: &openrtb2.Content{URL: "https://videos.example.com/1", Cat: []string{"IAB17"}, CatTax: adcom1.CatTaxIABContent10, Data: segments}}},
+			expectedClassified: []Content{{URL: "https://videos.example.com/1", Channel: "app"}},
+		},
+		{
+			name:               "publisher-categories-kept",
+			req:                &openrtb2.BidRequest{Site: &openrtb2.Site{Page: "https://news.example.com/sports", Content: &openrtb2.Content{Cat: []string{"IAB19"}}}},
+			classification:     sportsClassification,
+			expectedReq:        &openrtb2.BidRequest{Site: &openrtb2.Site{Page: "https://news.example.com/sports", Content: &openrtb2.Content{Cat: []string{"IAB19"}, Data: segments}}},
+			expectedClassified: []Content{{URL: "https://news.example.com/sports", Channel: "site"}},
+		},
+		{
+			name:        "already-classified",
+			req:         &openrtb2.BidRequest{Site: &openrtb2.Site{Page: "https://news.example.com/sports", Content: &openrtb2.Content{Cat: []string{"IAB19"}, Data: segments}}},
+			expectedReq: &openrtb2.BidRequest{Site: &openrtb2.Site{Page: "https://news.example.com/sports", Content: &openrtb2.Content{Cat: []string{"IAB19"}, Data: segments}}},
+		},
+		{
+			name:        "no-url",
+			req:         &openrtb2.BidRequest{App: &openrtb2.App{Bundle: "com.example"}},
+			expectedReq: &openrtb2.BidRequest{App: &openrtb2.App{Bundle: "com.example"}},
+		},
+		{
+			name:               "nothing-found",
+			req:                &openrtb2.BidRequest{Site: &openrtb2.Site{Page: "https://news.example.com/"}},
+			classification:     nil,
+			expectedReq:        &openrtb2.BidRequest{Site: &openrtb2.Site{Page: "https://news.example.com/"}},
+			expectedClassified: []Content{{URL: "https://news.example.com/", Channel: "site"}},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			me := &metrics.MetricsEngineMock{}
+			me.On("RecordContentClassification", metrics.ContentClassificationClassified, mock.Anything).Maybe()
+			classifier := &fakeClassifier{classification: test.classification}
+			site := test.req.Site
+
+			newTestEnricher(classifier, me).Enrich(context.Background(), test.req)
+
+			assert.Equal(t, test.expectedReq, test.req)
+			assert.Equal(t, test.expectedClassified, classifier.classified)
+			if site != nil {
+				assert.Equal(t, test.expectedReq.Site.Page, site.Page)
+				if test.req.Site != site {
+					assert.NotEqual(t, test.req.Site.Content, site.Content, "the original site mustn't be changed")
+				}
+			}
+		})
+	}
+}
+
+func TestEnrichCached(t *testing.T) {
+	me := &metrics.MetricsEngineMock{}
+	me.On("RecordContentClassification", metrics.ContentClassificationClassified, mock.Anything).Once()
+	me.On("RecordContentClassification", metrics.ContentClassificationCached, mock.Anything).Once()
+	classifier := &fakeClassifier{classification: sportsClassification}
+	enricher := newTestEnricher(classifier, me)
+
+	for i := 0; i < 2; i++ {
+		req := &openrtb2.BidRequest{Site: &openrtb2.Site{Page: "https://news.example.com/sports"}}
+		enricher.Enrich(context.Background(), req)
+		assert.Equal(t, []string{"IAB17"}, req.Site.Content.Cat)
+	}
+	assert.Len(t, classifier.classified, 1)
+	me.AssertExpectations(t)
+}
+
+func TestEnrichFailures(t *testing.T) {
+	testCases := []struct {
+		name           string
+		classifierErr  error
+		timeout        time.Duration
+		expectedStatus metrics.ContentClassificationStatus
+		expectedCalls  int
+	}{
+		{
+			name:           "classifier-error",
+			classifierErr:  errors.New("failed"),
+			timeout:        time.Second,
+			expectedStatus: metrics.ContentClassificationError,
+			expectedCalls:  1,
+		},
+		{
+			name:           "too-little-time-left",
+			timeout:        200 * time.Millisecond,
+			expectedStatus: metrics.ContentClassificationSkipped,
+			expectedCalls:  0,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			me := &metrics.MetricsEngineMock{}
+			me.On("RecordContentClassification", test.expectedStatus, mock.Anything).Once()
+			classifier := &fakeClassifier{classification: sportsClassification, err: test.classifierErr}
+			ctx, cancel := context.WithTimeout(context.Background(), test.timeout)
+			defer cancel()
+			req := &openrtb2.BidRequest{Site: &openrtb2.Site{Page: "https://news.example.com/sports"}}
+
+			newTestEnricher(classifier, me).Enrich(ctx, req)
+
+			assert.Nil(t, req.Site.Content)
+			assert.Len(t, classifier.classified, test.expectedCalls)
+			me.AssertExpectations(t)
+		})
+	}
+}
+
+func TestNilEnricher(t *testing.T) {
+	var enricher *Enricher
+	req := &openrtb2.BidRequest{Site: &openrtb2.Site{Page: "https://news.example.com/sports"}}
+	enricher.Enrich(context.Background(), req)
+	assert.Nil(t, req.Site.Content)
+}
+
+func TestClassificationCache(t *testing.T) {
+	now := time.Now()
+	cache := newClassificationCache(2, time.Minute)
+	cache.put("a", &Classification{Categories: []string{"a"}}, now)
+	cache.put("b", &Classification{Categories: []string{"b"}}, now)
+
+	_, ok := cache.get("a", now)
+	assert.True(t, ok)
+	cache.put("c", &Classification{Categories: []string{"c"}}, now)
+
+	_, ok = cache.get("b", now)
+	assert.False(t, ok, "the least recently used URL should have been evicted")
+	_, ok = cache.get("a", now)
+	assert.True(t, ok)
+	_, ok = cache.get("a", now.Add(time.Minute))
+	assert.False(t, ok, "the classification should have expired")
+}
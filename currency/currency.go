@@ -4,18 +4,22 @@ import (
 	"github.com/prebid/prebid-server/v2/openrtb_ext"
 )
 
-func GetAuctionCurrencyRates(currencyConverter *RateConverter, requestRates *openrtb_ext.ExtRequestCurrency) Conversions {
-	if currencyConverter == nil && requestRates == nil {
+// GetAuctionCurrencyRates returns the rates of the auction. The rates of the account, if it has any, are
+// merged over those of the currencyConverter to make up the PBS rates, which the rates of the request are
+// then merged over, unless the request opts out of using them.
+func GetAuctionCurrencyRates(currencyConverter *RateConverter, accountRates map[string]map[string]float64, requestRates *openrtb_ext.ExtRequestCurrency) Conversions {
+	pbsRates := getPBSRates(currencyConverter, accountRates)
+	if pbsRates == nil && requestRates == nil {
 		return nil
 	}
 
 	if requestRates == nil {
 		// No bidRequest.ext.currency field was found, use PBS rates as usual
-		return currencyConverter.Rates()
+		return pbsRates
 	}
 
 	// currencyConverter will never be nil, refer main.serve(), adding this check for future usecases
-	if currencyConverter == nil {
+	if pbsRates == nil {
 		return NewRates(requestRates.ConversionRates)
 	}
 
@@ -33,10 +37,25 @@ func GetAuctionCurrencyRates(currencyConverter *RateConverter, requestRates *ope
 	// Both PBS and custom rates can be used, check if ConversionRates is not empty
 	if len(requestRates.ConversionRates) == 0 {
 		// Custom rates map is empty, use PBS rates only
-		return currencyConverter.Rates()
+		return pbsRates
 	}
 
 	// Return an AggregateConversions object that includes both custom and PBS currency rates but will
 	// prioritize custom rates over PBS rates whenever a currency rate is found in both
-	return NewAggregateConversions(NewRates(requestRates.ConversionRates), currencyConverter.Rates())
+	return NewAggregateConversions(NewRates(requestRates.ConversionRates), pbsRates)
+}
+
+// getPBSRates returns the rates of the currencyConverter, with the rates of the account taking priority
+// over them, or nil if there are neither.
+func getPBSRates(currencyConverter *RateConverter, accountRates map[string]map[string]float64) Conversions {
+	if len(accountRates) == 0 {
+		if currencyConverter == nil {
+			return nil
+		}
+		return currencyConverter.Rates()
+	}
+	if currencyConverter == nil {
+		return NewRates(accountRates)
+	}
+	return NewAggregateConversions(NewRates(accountRates), currencyConverter.Rates())
 }
@@ -178,7 +178,7 @@ func TestGetAuctionCurrencyRates(t *testing.T) {
 			if tt.args.currencyConverter != nil {
 				tt.args.currencyConverter.Run()
 			}
-			auctionRates := GetAuctionCurrencyRates(tt.args.currencyConverter, tt.args.requestRates)
+			auctionRates := GetAuctionCurrencyRates(tt.args.currencyConverter, nil, tt.args.requestRates)
 			if tt.args.currencyConverter == nil && tt.args.requestRates == nil && tt.assertRates == nil {
 				assert.Nil(t, auctionRates)
 			} else if tt.assertRates == nil {
@@ -197,3 +197,76 @@ func TestGetAuctionCurrencyRates(t *testing.T) {
 		})
 	}
 }
+
+func TestGetAuctionCurrencyRatesWithAccountRates(t *testing.T) {
+	pbsRates := map[string]map[string]float64{
+		"USD": {"EUR": 0.92, "GBP": 0.79},
+	}
+	accountRates := map[string]map[string]float64{
+		"USD": {"EUR": 0.90}, // contractual rate, different than in pbsRates
+		"JPY": {"USD": 0.0067},
+	}
+	requestRates := map[string]map[string]float64{
+		"USD": {"EUR": 0.95},
+	}
+
+	currencyConverter := NewRateConverter(&MockCurrencyRatesHttpClient{ResponseBody: `{"conversions":{"USD":{"EUR":0.92,"GBP":0.79}}}`}, "currency.fake.com", 24*time.Hour)
+	currencyConverter.Run()
+
+	tests := []struct {
+		name              string
+		currencyConverter *RateConverter
+		requestRates      *openrtb_ext.ExtRequestCurrency
+		expectedRates     map[string]map[string]float64
+		expectedMissing   [][2]string
+	}{
+		{
+			name:              "account-over-pbs",
+			currencyConverter: currencyConverter,
+			expectedRates: map[string]map[string]float64{
+				"USD": {"EUR": 0.90, "GBP": 0.79},
+				"EUR": {"USD": 1 / 0.90},
+				"JPY": {"USD": 0.0067},
+			},
+			expectedMissing: [][2]string{{"JPY", "GBP"}},
+		},
+		{
+			name:              "request-over-account",
+			currencyConverter: currencyConverter,
+			requestRates:      &openrtb_ext.ExtRequestCurrency{ConversionRates: requestRates},
+			expectedRates: map[string]map[string]float64{
+				"USD": {"EUR": 0.95, "GBP": 0.79},
+				"JPY": {"USD": 0.0067},
+			},
+		},
+		{
+			name:              "request-opts-out-of-pbs-rates",
+			currencyConverter: currencyConverter,
+			requestRates:      &openrtb_ext.ExtRequestCurrency{ConversionRates: requestRates, UsePBSRates: ptrutil.ToPtr(false)},
+			expectedRates:     map[string]map[string]float64{"USD": {"EUR": 0.95}},
+			expectedMissing:   [][2]string{{"JPY", "USD"}, {"USD", "GBP"}},
+		},
+		{
+			name:            "no-converter",
+			expectedRates:   accountRates,
+			expectedMissing: [][2]string{{"USD", "GBP"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			auctionRates := GetAuctionCurrencyRates(tt.currencyConverter, accountRates, tt.requestRates)
+			for from, rates := range tt.expectedRates {
+				for to, expectedRate := range rates {
+					rate, err := auctionRates.GetRate(from, to)
+					assert.NoError(t, err)
+					assert.Equal(t, expectedRate, rate, from+" to "+to)
+				}
+			}
+			for _, pair := range tt.expectedMissing {
+				_, err := auctionRates.GetRate(pair[0], pair[1])
+				assert.IsType(t, ConversionNotFoundError{}, err, pair[0]+" to "+pair[1])
+			}
+		})
+	}
+	assert.Equal(t, pbsRates, *currencyConverter.Rates().GetRates(), "the rates of the converter shouldn't change")
+}
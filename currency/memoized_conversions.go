@@ -0,0 +1,69 @@
+package currency
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// MemoizedConversions remembers the rates looked up in the Conversions it wraps, so that the many
+// conversions between the same currencies made in an auction, by the floors, the bid adjustments and the
+// exchange, only parse the currency codes and look the rate up once. The rates of an auction don't change
+// while it runs, so errors are remembered as well. It's safe for concurrent use by the bidders of the auction,
+// and counts its hits and misses so they can be recorded once the auction ends.
+// It implements the Conversions interface.
+type MemoizedConversions struct {
+	conversions Conversions
+
+	lock   sync.RWMutex
+	rates  map[currencyPair]memoizedRate
+	hits   atomic.Int64
+	misses atomic.Int64
+}
+
+type currencyPair struct {
+	from, to string
+}
+
+type memoizedRate struct {
+	rate float64
+	err  error
+}
+
+// NewMemoizedConversions expects conversions to not be nil
+func NewMemoizedConversions(conversions Conversions) *MemoizedConversions {
+	return &MemoizedConversions{
+		conversions: conversions,
+		rates:       make(map[currencyPair]memoizedRate),
+	}
+}
+
+// GetRate returns the conversion rate between two currencies, looking it up in the wrapped Conversions the
+// first time it's asked for.
+func (mc *MemoizedConversions) GetRate(from string, to string) (float64, error) {
+	pair := currencyPair{from: from, to: to}
+
+	mc.lock.RLock()
+	memoized, ok := mc.rates[pair]
+	mc.lock.RUnlock()
+	if ok {
+		mc.hits.Add(1)
+		return memoized.rate, memoized.err
+	}
+
+	mc.misses.Add(1)
+	rate, err := mc.conversions.GetRate(from, to)
+	mc.lock.Lock()
+	mc.rates[pair] = memoizedRate{rate: rate, err: err}
+	mc.lock.Unlock()
+	return rate, err
+}
+
+// GetRates returns the rates of the wrapped Conversions
+func (mc *MemoizedConversions) GetRates() *map[string]map[string]float64 {
+	return mc.conversions.GetRates()
+}
+
+// CacheResults returns how many of the rates looked up so far were memoized already, and how many weren't.
+func (mc *MemoizedConversions) CacheResults() (hits int, misses int) {
+	return int(mc.hits.Load()), int(mc.misses.Load())
+}
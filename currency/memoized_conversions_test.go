@@ -0,0 +1,76 @@
+package currency
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// countingConversions counts the lookups made in the Conversions it wraps
+type countingConversions struct {
+	Conversions
+	lock    sync.Mutex
+	lookups int
+}
+
+func (c *countingConversions) GetRate(from string, to string) (float64, error) {
+	c.lock.Lock()
+	c.lookups++
+	c.lock.Unlock()
+	return c.Conversions.GetRate(from, to)
+}
+
+func TestMemoizedGetRate(t *testing.T) {
+	rates := &countingConversions{Conversions: NewRates(map[string]map[string]float64{
+		"USD": {"GBP": 0.8, "EUR": 0.9},
+	})}
+	memoized := NewMemoizedConversions(rates)
+
+	tests := []struct {
+		description  string
+		from         string
+		to           string
+		expectedRate float64
+		expectedErr  error
+	}{
+		{description: "direct", from: "USD", to: "GBP", expectedRate: 0.8},
+		{description: "direct-again", from: "USD", to: "GBP", expectedRate: 0.8},
+		{description: "reciprocal", from: "EUR", to: "USD", expectedRate: 1 / 0.9},
+		{description: "not-found", from: "GBP", to: "JPY", expectedErr: ConversionNotFoundError{FromCur: "GBP", ToCur: "JPY"}},
+		{description: "not-found-again", from: "GBP", to: "JPY", expectedErr: ConversionNotFoundError{FromCur: "GBP", ToCur: "JPY"}},
+		{description: "reciprocal-again", from: "EUR", to: "USD", expectedRate: 1 / 0.9},
+	}
+	for _, test := range tests {
+		rate, err := memoized.GetRate(test.from, test.to)
+		assert.Equal(t, test.expectedRate, rate, test.description)
+		assert.Equal(t, test.expectedErr, err, test.description)
+	}
+
+	assert.Equal(t, 3, rates.lookups, "each pair should only be looked up once")
+	hits, misses := memoized.CacheResults()
+	assert.Equal(t, 3, hits)
+	assert.Equal(t, 3, misses)
+	assert.Equal(t, rates.GetRates(), memoized.GetRates())
+}
+
+func TestMemoizedGetRateConcurrently(t *testing.T) {
+	memoized := NewMemoizedConversions(NewRates(map[string]map[string]float64{"USD": {"GBP": 0.8}}))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				rate, err := memoized.GetRate("GBP", "USD")
+				assert.NoError(t, err)
+				assert.Equal(t, 1/0.8, rate)
+			}
+		}()
+	}
+	wg.Wait()
+
+	hits, misses := memoized.CacheResults()
+	assert.Equal(t, 1000, hits+misses)
+}
@@ -1,14 +1,20 @@
 package currency
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"sort"
+	"sync"
 	"sync/atomic"
 	"time"
 
-	"github.com/golang/glog"
+	"github.com/prebid/prebid-server/v2/config"
 	"github.com/prebid/prebid-server/v2/errortypes"
+	"github.com/prebid/prebid-server/v2/logger"
+	"github.com/prebid/prebid-server/v2/metrics"
 	"github.com/prebid/prebid-server/v2/util/jsonutil"
 	"github.com/prebid/prebid-server/v2/util/timeutil"
 )
@@ -17,13 +23,41 @@ import (
 type RateConverter struct {
 	httpClient          httpClient
 	staleRatesThreshold time.Duration
-	syncSourceURL       string
+	staleAlarmThreshold time.Duration
+	sources             []*ratesSource // in order of priority
+	maxFailures         int
+	retryInterval       time.Duration
+	me                  metrics.MetricsEngine
+	updateLock          sync.Mutex   // serializes the updates, which change the health of the sources
+	started             time.Time    // when the rates were first updated, whether or not they were fetched
 	rates               atomic.Value // Should only hold Rates struct
 	lastUpdated         atomic.Value // Should only hold time.Time
+	source              atomic.Value // Should only hold string, the URL the rates were fetched from
 	constantRates       Conversions
 	time                timeutil.Time
 }
 
+// ratesSource is one of the URLs a RateConverter fetches the rates from, along with its health. A source
+// which fails maxFailures updates in a row is unhealthy, and is skipped until retryAt.
+type ratesSource struct {
+	name      string
+	url       string
+	timeout   time.Duration
+	failures  int
+	lastError error
+	retryAt   time.Time
+}
+
+// RatesSourceHealth is the health of one of the sources of a RateConverter, which /currency/rates reports
+// in its additionalInfo when there are several.
+type RatesSourceHealth struct {
+	Name                string `json:"name"`
+	URL                 string `json:"url"`
+	Healthy             bool   `json:"healthy"`
+	ConsecutiveFailures int    `json:"consecutiveFailures"`
+	LastError           string `json:"lastError,omitempty"`
+}
+
 // NewRateConverter returns a new RateConverter
 func NewRateConverter(
 	httpClient httpClient,
@@ -33,7 +67,7 @@ func NewRateConverter(
 	return &RateConverter{
 		httpClient:          httpClient,
 		staleRatesThreshold: staleRatesThreshold,
-		syncSourceURL:       syncSourceURL,
+		sources:             []*ratesSource{{name: syncSourceURL, url: syncSourceURL}},
 		rates:               atomic.Value{},
 		lastUpdated:         atomic.Value{},
 		constantRates:       NewConstantRates(),
@@ -41,9 +75,53 @@ func NewRateConverter(
 	}
 }
 
-// fetch allows to retrieve the currencies rates from the syncSourceURL provided
-func (rc *RateConverter) fetch() (*Rates, error) {
-	request, err := http.NewRequest("GET", rc.syncSourceURL, nil)
+// NewRateConverterFromConfig returns a RateConverter which fetches the rates from the
+// currency_converter.rates_sources in order of priority, failing over to the next source when one fails,
+// or from the currency_converter.fetch_url if there are none.
+func NewRateConverterFromConfig(httpClient httpClient, cfg config.CurrencyConverter) *RateConverter {
+	rc := NewRateConverter(httpClient, cfg.FetchURL, time.Duration(cfg.StaleRatesSeconds)*time.Second)
+	rc.staleAlarmThreshold = time.Duration(cfg.StaleAlarmSeconds) * time.Second
+	if len(cfg.RatesSources) == 0 {
+		return rc
+	}
+
+	configured := make([]config.CurrencyRatesSource, len(cfg.RatesSources))
+	copy(configured, cfg.RatesSources)
+	sort.SliceStable(configured, func(i, j int) bool {
+		return configured[i].Priority < configured[j].Priority
+	})
+	rc.sources = make([]*ratesSource, 0, len(configured))
+	for _, source := range configured {
+		name := source.Name
+		if name == "" {
+			name = source.URL
+		}
+		rc.sources = append(rc.sources, &ratesSource{
+			name:    name,
+			url:     source.URL,
+			timeout: time.Duration(source.TimeoutMS) * time.Millisecond,
+		})
+	}
+	rc.maxFailures = cfg.SourceMaxFailures
+	rc.retryInterval = time.Duration(cfg.SourceRetrySeconds) * time.Second
+	return rc
+}
+
+// SetMetricsEngine sets the engine the updates and the staleness of the rates are recorded in. It must be
+// called before the converter is first run.
+func (rc *RateConverter) SetMetricsEngine(me metrics.MetricsEngine) {
+	rc.me = me
+}
+
+// fetch allows to retrieve the currencies rates from the source provided
+func (rc *RateConverter) fetch(source *ratesSource) (*Rates, error) {
+	ctx := context.Background()
+	if source.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, source.timeout)
+		defer cancel()
+	}
+	request, err := http.NewRequestWithContext(ctx, "GET", source.url, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -74,21 +152,82 @@ func (rc *RateConverter) fetch() (*Rates, error) {
 	return updatedRates, err
 }
 
+// fetchFromSources fetches the rates from the first of the sources, in order of priority, which serves them.
+// Unhealthy sources are only tried once they're due to be retried, unless every source is unhealthy.
+func (rc *RateConverter) fetchFromSources(now time.Time) (*Rates, *ratesSource, error) {
+	candidates := make([]*ratesSource, 0, len(rc.sources))
+	for _, source := range rc.sources {
+		if rc.healthy(source) || !now.Before(source.retryAt) {
+			candidates = append(candidates, source)
+		}
+	}
+	if len(candidates) == 0 {
+		candidates = rc.sources
+	}
+
+	var errs []error
+	for _, source := range candidates {
+		rates, err := rc.fetch(source)
+		if err == nil {
+			if !rc.healthy(source) {
+				logger.Infof("The currency rates source %s is healthy again", source.name)
+			}
+			source.failures = 0
+			source.lastError = nil
+			return rates, source, nil
+		}
+		source.failures++
+		source.lastError = err
+		if !rc.healthy(source) {
+			if source.failures == rc.maxFailures {
+				logger.Warningf("The currency rates source %s failed %d times in a row, skipping it for %v", source.name, source.failures, rc.retryInterval)
+			}
+			source.retryAt = now.Add(rc.retryInterval)
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", source.name, err))
+	}
+	return nil, nil, errors.Join(errs...)
+}
+
+// healthy reports whether the source has failed fewer than maxFailures updates in a row. Sources are
+// always healthy if maxFailures isn't set, as with the single fetch_url.
+func (rc *RateConverter) healthy(source *ratesSource) bool {
+	return rc.maxFailures <= 0 || source.failures < rc.maxFailures
+}
+
 // Update updates the internal currencies rates from remote sources
 func (rc *RateConverter) update() error {
-	rates, err := rc.fetch()
+	rc.updateLock.Lock()
+	defer rc.updateLock.Unlock()
+
+	now := rc.time.Now()
+	if rc.started.IsZero() {
+		rc.started = now
+	}
+
+	rates, source, err := rc.fetchFromSources(now)
+	status := metrics.CurrencyRatesFailed
 	if err == nil {
 		rc.rates.Store(rates)
-		rc.lastUpdated.Store(rc.time.Now())
+		rc.lastUpdated.Store(now)
+		rc.source.Store(source.url)
+		status = metrics.CurrencyRatesFetched
+		if source != rc.sources[0] {
+			status = metrics.CurrencyRatesFailover
+		}
 	} else {
 		if rc.checkStaleRates() {
 			rc.clearRates()
-			glog.Errorf("Error updating conversion rates, falling back to constant rates: %v", err)
+			logger.Errorf("Error updating conversion rates, falling back to constant rates: %v", err)
 		} else {
-			glog.Errorf("Error updating conversion rates: %v", err)
+			logger.Errorf("Error updating conversion rates: %v", err)
 		}
 	}
 
+	if rc.me != nil {
+		rc.me.RecordCurrencyRatesFetch(status)
+		rc.me.RecordCurrencyRatesStale(rc.alarmStale(now))
+	}
 	return err
 }
 
@@ -136,15 +275,58 @@ func (rc *RateConverter) checkStaleRates() bool {
 	return false
 }
 
-// GetInfo returns setup information about the converter
+// alarmStale reports whether the rates have gone longer than the stale alarm threshold without an update,
+// counting from the first update if they've never been fetched.
+func (rc *RateConverter) alarmStale(now time.Time) bool {
+	if rc.staleAlarmThreshold <= 0 {
+		return false
+	}
+	since := rc.started
+	if lastUpdated := rc.LastUpdated(); !lastUpdated.IsZero() {
+		since = lastUpdated
+	}
+	return now.Sub(since) > rc.staleAlarmThreshold
+}
+
+// SourcesHealth returns the health of the sources, in order of priority
+func (rc *RateConverter) SourcesHealth() []RatesSourceHealth {
+	rc.updateLock.Lock()
+	defer rc.updateLock.Unlock()
+
+	health := make([]RatesSourceHealth, 0, len(rc.sources))
+	for _, source := range rc.sources {
+		sourceHealth := RatesSourceHealth{
+			Name:                source.name,
+			URL:                 source.url,
+			Healthy:             rc.healthy(source),
+			ConsecutiveFailures: source.failures,
+		}
+		if source.lastError != nil {
+			sourceHealth.LastError = source.lastError.Error()
+		}
+		health = append(health, sourceHealth)
+	}
+	return health
+}
+
+// GetInfo returns setup information about the converter. The source is the URL the rates were last
+// fetched from, or the one with the highest priority if they haven't been yet.
 func (rc *RateConverter) GetInfo() ConverterInfo {
 	var rates *map[string]map[string]float64
 	rates = rc.Rates().GetRates()
-	return converterInfo{
-		source:      rc.syncSourceURL,
+	source := rc.sources[0].url
+	if fetchedFrom := rc.source.Load(); fetchedFrom != nil {
+		source = fetchedFrom.(string)
+	}
+	info := converterInfo{
+		source:      source,
 		lastUpdated: rc.LastUpdated(),
 		rates:       rates,
 	}
+	if len(rc.sources) > 1 {
+		info.additionalInfo = rc.SourcesHealth()
+	}
+	return info
 }
 
 type httpClient interface {
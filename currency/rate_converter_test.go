@@ -9,8 +9,11 @@ import (
 	"testing"
 	"time"
 
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/metrics"
 	"github.com/prebid/prebid-server/v2/util/task"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 func getMockRates() []byte {
@@ -377,3 +380,140 @@ func (m *mockHttpClient) Do(req *http.Request) (*http.Response, error) {
 		Body:       io.NopCloser(strings.NewReader(m.responseBody)),
 	}, nil
 }
+
+func newRatesSourceServer(t *testing.T, statuses ...int) (*httptest.Server, *int) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		status := http.StatusOK
+		if calls < len(statuses) {
+			status = statuses[calls]
+		}
+		calls++
+		rw.WriteHeader(status)
+		if status == http.StatusOK {
+			rw.Write(getMockRates())
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server, &calls
+}
+
+func TestRatesSourcesFailover(t *testing.T) {
+	primary, primaryCalls := newRatesSourceServer(t, http.StatusInternalServerError, http.StatusInternalServerError, http.StatusInternalServerError, http.StatusInternalServerError)
+	secondary, secondaryCalls := newRatesSourceServer(t)
+	fakeTime := &FakeTime{time: time.Date(2024, time.May, 1, 12, 0, 0, 0, time.UTC)}
+	me := &metrics.MetricsEngineMock{}
+	me.On("RecordCurrencyRatesFetch", mock.Anything).Return()
+	me.On("RecordCurrencyRatesStale", false).Return()
+
+	currencyConverter := NewRateConverterFromConfig(&http.Client{}, config.CurrencyConverter{
+		FetchURL: "http://unused.prebid.org",
+		RatesSources: []config.CurrencyRatesSource{
+			{Name: "secondary", URL: secondary.URL, Priority: 2},
+			{Name: "primary", URL: primary.URL, Priority: 1, TimeoutMS: 1000},
+		},
+		SourceMaxFailures:  2,
+		SourceRetrySeconds: 60,
+	})
+	currencyConverter.time = fakeTime
+	currencyConverter.SetMetricsEngine(me)
+
+	// the primary source fails twice and is then skipped as unhealthy
+	for i := 0; i < 3; i++ {
+		assert.NoError(t, currencyConverter.Run())
+	}
+	assert.Equal(t, 2, *primaryCalls)
+	assert.Equal(t, 3, *secondaryCalls)
+	assert.Equal(t, secondary.URL, currencyConverter.GetInfo().Source())
+	rate, err := currencyConverter.Rates().GetRate("USD", "GBP")
+	assert.NoError(t, err)
+	assert.Equal(t, 0.77208, rate)
+	me.AssertNumberOfCalls(t, "RecordCurrencyRatesFetch", 3)
+	me.AssertCalled(t, "RecordCurrencyRatesFetch", metrics.CurrencyRatesFailover)
+
+	health := currencyConverter.SourcesHealth()
+	assert.Len(t, health, 2)
+	assert.Equal(t, "primary", health[0].Name)
+	assert.False(t, health[0].Healthy)
+	assert.Equal(t, 2, health[0].ConsecutiveFailures)
+	assert.Contains(t, health[0].LastError, "status code 500")
+	assert.Equal(t, RatesSourceHealth{Name: "secondary", URL: secondary.URL, Healthy: true}, health[1])
+	assert.Equal(t, health, currencyConverter.GetInfo().AdditionalInfo())
+
+	// once its retry interval has passed, the primary source is tried again, and is healthy once it recovers
+	fakeTime.time = fakeTime.time.Add(time.Minute)
+	assert.NoError(t, currencyConverter.Run())
+	assert.Equal(t, 3, *primaryCalls)
+	assert.Equal(t, 4, *secondaryCalls)
+
+	fakeTime.time = fakeTime.time.Add(time.Minute)
+	assert.NoError(t, currencyConverter.Run())
+	assert.Equal(t, 4, *primaryCalls)
+	assert.Equal(t, 5, *secondaryCalls)
+
+	fakeTime.time = fakeTime.time.Add(time.Minute)
+	assert.NoError(t, currencyConverter.Run())
+	assert.Equal(t, 5, *primaryCalls)
+	assert.Equal(t, 5, *secondaryCalls)
+	assert.Equal(t, primary.URL, currencyConverter.GetInfo().Source())
+	assert.True(t, currencyConverter.SourcesHealth()[0].Healthy)
+	me.AssertCalled(t, "RecordCurrencyRatesFetch", metrics.CurrencyRatesFetched)
+}
+
+func TestRatesSourcesAllUnhealthy(t *testing.T) {
+	primary, primaryCalls := newRatesSourceServer(t, http.StatusNotFound, http.StatusNotFound)
+	secondary, secondaryCalls := newRatesSourceServer(t, http.StatusNotFound)
+
+	currencyConverter := NewRateConverterFromConfig(&http.Client{}, config.CurrencyConverter{
+		RatesSources: []config.CurrencyRatesSource{
+			{Name: "primary", URL: primary.URL},
+			{Name: "secondary", URL: secondary.URL},
+		},
+		SourceMaxFailures:  1,
+		SourceRetrySeconds: 3600,
+	})
+
+	err := currencyConverter.Run()
+	assert.ErrorContains(t, err, "primary: The currency rates request failed with status code 404")
+	assert.ErrorContains(t, err, "secondary: The currency rates request failed with status code 404")
+	assert.Equal(t, &ConstantRates{}, currencyConverter.Rates())
+
+	// every source is unhealthy, so they're all tried rather than none
+	assert.NoError(t, currencyConverter.Run())
+	assert.Equal(t, 2, *primaryCalls)
+	assert.Equal(t, 2, *secondaryCalls)
+	assert.Equal(t, secondary.URL, currencyConverter.GetInfo().Source())
+}
+
+func TestRatesStaleAlarm(t *testing.T) {
+	server, _ := newRatesSourceServer(t, http.StatusNotFound, http.StatusOK, http.StatusNotFound, http.StatusNotFound)
+	fakeTime := &FakeTime{time: time.Date(2024, time.May, 1, 12, 0, 0, 0, time.UTC)}
+	me := &metrics.MetricsEngineMock{}
+	me.On("RecordCurrencyRatesFetch", mock.Anything).Return()
+	me.On("RecordCurrencyRatesStale", mock.Anything).Return()
+
+	currencyConverter := NewRateConverterFromConfig(&http.Client{}, config.CurrencyConverter{
+		FetchURL:          server.URL,
+		StaleAlarmSeconds: 60,
+	})
+	currencyConverter.time = fakeTime
+	currencyConverter.SetMetricsEngine(me)
+
+	tests := []struct {
+		description   string
+		advance       time.Duration
+		expectedFetch metrics.CurrencyRatesFetchStatus
+		expectedStale bool
+	}{
+		{description: "never-fetched", expectedFetch: metrics.CurrencyRatesFailed, expectedStale: false},
+		{description: "fetched", advance: 2 * time.Minute, expectedFetch: metrics.CurrencyRatesFetched, expectedStale: false},
+		{description: "failed-within-threshold", advance: time.Minute, expectedFetch: metrics.CurrencyRatesFailed, expectedStale: false},
+		{description: "failed-past-threshold", advance: time.Second, expectedFetch: metrics.CurrencyRatesFailed, expectedStale: true},
+	}
+	for i, test := range tests {
+		fakeTime.time = fakeTime.time.Add(test.advance)
+		currencyConverter.Run()
+		assert.Equal(t, test.expectedFetch, me.Calls[2*i].Arguments.Get(0), test.description)
+		assert.Equal(t, test.expectedStale, me.Calls[2*i+1].Arguments.Get(0), test.description)
+	}
+}
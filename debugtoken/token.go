@@ -0,0 +1,117 @@
+// Package debugtoken signs and verifies the tokens which turn on debug output for an auction. Unlike the
+// debug flag of a request, which anyone can set, a token can only be made by someone holding one of the
+// account's keys, and it expires, so detailed debug output can be turned on safely in production for a
+// single troubleshooting session.
+//
+// A token is the base64url encoded JSON of its claims, followed by a dot and the base64url encoded
+// HMAC-SHA256 of the encoded claims.
+package debugtoken
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Trace levels a token may turn on, as for ext.prebid.trace.
+const (
+	TraceBasic   = "basic"
+	TraceVerbose = "verbose"
+)
+
+// Claims say what a token is good for.
+type Claims struct {
+	// Account is the ID of the account whose requests the token may be used for.
+	Account string `json:"account"`
+	// Expires is when the token stops being valid, in seconds since the Unix epoch.
+	Expires int64 `json:"exp"`
+	// Trace is the level of hook tracing the token turns on, if any.
+	Trace string `json:"trace,omitempty"`
+}
+
+var encoding = base64.RawURLEncoding
+
+// Sign returns a token for the claims, signed with key.
+func Sign(claims Claims, key string) (string, error) {
+	if key == "" {
+		return "", errors.New("the signing key is empty")
+	}
+	if err := validateTrace(claims.Trace); err != nil {
+		return "", err
+	}
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+	encoded := encoding.EncodeToString(payload)
+	return encoded + "." + encoding.EncodeToString(sign(encoded, key)), nil
+}
+
+// Verify returns the claims of the token, if it was signed with one of the keys, is for the account, and
+// hasn't expired. Tokens which would stay valid for longer than maxTTL are rejected, so a leaked token
+// can't be used for long.
+func Verify(token, accountID string, keys []string, maxTTL time.Duration, now time.Time) (Claims, error) {
+	encoded, signature, found := strings.Cut(token, ".")
+	if !found {
+		return Claims{}, errors.New("the token is malformed")
+	}
+	decodedSignature, err := encoding.DecodeString(signature)
+	if err != nil {
+		return Claims{}, errors.New("the token's signature is malformed")
+	}
+	if !signedWithAny(encoded, decodedSignature, keys) {
+		return Claims{}, errors.New("the token's signature doesn't match any of the account's keys")
+	}
+
+	payload, err := encoding.DecodeString(encoded)
+	if err != nil {
+		return Claims{}, errors.New("the token's claims are malformed")
+	}
+	var claims Claims
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return Claims{}, fmt.Errorf("the token's claims are malformed: %v", err)
+	}
+
+	if claims.Account != accountID {
+		return Claims{}, fmt.Errorf("the token is for account %q", claims.Account)
+	}
+	expires := time.Unix(claims.Expires, 0)
+	if !now.Before(expires) {
+		return Claims{}, fmt.Errorf("the token expired at %s", expires.UTC().Format(time.RFC3339))
+	}
+	if expires.Sub(now) > maxTTL {
+		return Claims{}, fmt.Errorf("the token is valid for longer than the %s allowed", maxTTL)
+	}
+	if err := validateTrace(claims.Trace); err != nil {
+		return Claims{}, err
+	}
+	return claims, nil
+}
+
+func signedWithAny(encoded string, signature []byte, keys []string) bool {
+	for _, key := range keys {
+		if key != "" && hmac.Equal(signature, sign(encoded, key)) {
+			return true
+		}
+	}
+	return false
+}
+
+func sign(encoded, key string) []byte {
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write([]byte(encoded))
+	return mac.Sum(nil)
+}
+
+func validateTrace(trace string) error {
+	switch trace {
+	case "", TraceBasic, TraceVerbose:
+		return nil
+	}
+	return fmt.Errorf("the trace level %q is invalid", trace)
+}
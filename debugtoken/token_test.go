@@ -0,0 +1,157 @@
+package debugtoken
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testKey      = "0123456789abcdef0123456789abcdef"
+	otherTestKey = "fedcba9876543210fedcba9876543210"
+)
+
+func TestSignAndVerify(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+
+	testCases := []struct {
+		description    string
+		claims         Claims
+		signingKey     string
+		keys           []string
+		accountID      string
+		expectedClaims Claims
+		expectedError  string
+	}{
+		{
+			description:    "valid",
+			claims:         Claims{Account: "1001", Expires: now.Unix() + 600},
+			signingKey:     testKey,
+			keys:           []string{testKey},
+			accountID:      "1001",
+			expectedClaims: Claims{Account: "1001", Expires: now.Unix() + 600},
+		},
+		{
+			description:    "valid-with-trace",
+			claims:         Claims{Account: "1001", Expires: now.Unix() + 600, Trace: TraceVerbose},
+			signingKey:     testKey,
+			keys:           []string{testKey},
+			accountID:      "1001",
+			expectedClaims: Claims{Account: "1001", Expires: now.Unix() + 600, Trace: TraceVerbose},
+		},
+		{
+			description:    "signed-with-second-key",
+			claims:         Claims{Account: "1001", Expires: now.Unix() + 600},
+			signingKey:     otherTestKey,
+			keys:           []string{testKey, otherTestKey},
+			accountID:      "1001",
+			expectedClaims: Claims{Account: "1001", Expires: now.Unix() + 600},
+		},
+		{
+			description:   "signed-with-unknown-key",
+			claims:        Claims{Account: "1001", Expires: now.Unix() + 600},
+			signingKey:    otherTestKey,
+			keys:          []string{testKey},
+			accountID:     "1001",
+			expectedError: "the token's signature doesn't match any of the account's keys",
+		},
+		{
+			description:   "no-keys",
+			claims:        Claims{Account: "1001", Expires: now.Unix() + 600},
+			signingKey:    testKey,
+			accountID:     "1001",
+			expectedError: "the token's signature doesn't match any of the account's keys",
+		},
+		{
+			description:   "other-account",
+			claims:        Claims{Account: "1002", Expires: now.Unix() + 600},
+			signingKey:    testKey,
+			keys:          []string{testKey},
+			accountID:     "1001",
+			expectedError: `the token is for account "1002"`,
+		},
+		{
+			description:   "expired",
+			claims:        Claims{Account: "1001", Expires: now.Unix()},
+			signingKey:    testKey,
+			keys:          []string{testKey},
+			accountID:     "1001",
+			expectedError: "the token expired at 2023-11-14T22:13:20Z",
+		},
+		{
+			description:   "valid-for-too-long",
+			claims:        Claims{Account: "1001", Expires: now.Unix() + 3601},
+			signingKey:    testKey,
+			keys:          []string{testKey},
+			accountID:     "1001",
+			expectedError: "the token is valid for longer than the 1h0m0s allowed",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			token, err := Sign(test.claims, test.signingKey)
+			require.NoError(t, err)
+
+			claims, err := Verify(token, test.accountID, test.keys, time.Hour, now)
+			if test.expectedError != "" {
+				assert.EqualError(t, err, test.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.expectedClaims, claims)
+		})
+	}
+}
+
+func TestVerifyMalformed(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	token, err := Sign(Claims{Account: "1001", Expires: now.Unix() + 600}, testKey)
+	require.NoError(t, err)
+	encoded, _, _ := strings.Cut(token, ".")
+
+	testCases := []struct {
+		description   string
+		token         string
+		expectedError string
+	}{
+		{
+			description:   "no-signature",
+			token:         encoded,
+			expectedError: "the token is malformed",
+		},
+		{
+			description:   "signature-not-base64",
+			token:         encoded + ".!!!",
+			expectedError: "the token's signature is malformed",
+		},
+		{
+			description:   "tampered-claims",
+			token:         "e30" + token[3:],
+			expectedError: "the token's signature doesn't match any of the account's keys",
+		},
+		{
+			description:   "claims-not-json",
+			token:         "bm90LWpzb24." + encoding.EncodeToString(sign("bm90LWpzb24", testKey)),
+			expectedError: "the token's claims are malformed: invalid character 'o' in literal null (expecting 'u')",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			_, err := Verify(test.token, "1001", []string{testKey}, time.Hour, now)
+			assert.EqualError(t, err, test.expectedError)
+		})
+	}
+}
+
+func TestSignInvalid(t *testing.T) {
+	_, err := Sign(Claims{Account: "1001", Expires: 1}, "")
+	assert.EqualError(t, err, "the signing key is empty")
+
+	_, err = Sign(Claims{Account: "1001", Expires: 1, Trace: "everything"}, testKey)
+	assert.EqualError(t, err, `the trace level "everything" is invalid`)
+}
@@ -0,0 +1,203 @@
+// Package defaultrequest layers default requests under auction requests: the host's default request, the
+// host's default request for the request's integration type, and the account's default requests. Hosts
+// can choose how arrays and ext objects are merged, and enforce fields such as the supply chain or regs
+// across all of their accounts.
+package defaultrequest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/buger/jsonparser"
+	jsonpatch "gopkg.in/evanphx/json-patch.v4"
+
+	"github.com/prebid/prebid-server/v2/config"
+)
+
+// Defaults are the host's default requests, and how default requests are merged with the requests layered
+// over them. The zero value has no host default requests.
+type Defaults struct {
+	host json.RawMessage
+	// channels are the host default request merged with the default request of each integration type
+	channels    map[config.ChannelType]json.RawMessage
+	mergeArrays bool
+	replaceExt  bool
+	enforce     [][]string
+}
+
+// New returns the host's default requests, given the host default request which was read from
+// default_request.file.name. The default requests of the integration types are read from their files.
+func New(hostDefault []byte, cfg config.DefReqConfig) (*Defaults, error) {
+	d := &Defaults{
+		host:        hostDefault,
+		channels:    make(map[config.ChannelType]json.RawMessage, len(cfg.Channels)),
+		mergeArrays: cfg.Merge.Arrays == "merge",
+		replaceExt:  cfg.Merge.Ext == "replace",
+	}
+	for _, path := range cfg.Merge.Enforce {
+		d.enforce = append(d.enforce, strings.Split(path, "."))
+	}
+
+	for channel, files := range cfg.Channels {
+		if len(files.FileName) == 0 {
+			continue
+		}
+		channelDefault, err := os.ReadFile(files.FileName)
+		if err != nil {
+			return nil, fmt.Errorf("error reading the default request of %s from file %s: %v", channel, files.FileName, err)
+		}
+		merged, err := d.merge(hostDefault, channelDefault)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing the default request of %s in file %s: %v", channel, files.FileName, err)
+		}
+		d.channels[config.ChannelType(channel)] = merged
+	}
+	return d, nil
+}
+
+// Request returns the default request of the account's requests of the integration type, with each layer
+// merged over the ones under it. It's empty if there are none.
+func (d *Defaults) Request(account *config.Account, channel config.ChannelType) (json.RawMessage, error) {
+	request := d.hostRequest(channel)
+	if account == nil {
+		return request, nil
+	}
+
+	var err error
+	for _, layer := range []json.RawMessage{account.DefaultRequest.Request, account.DefaultRequest.Channels[string(channel)]} {
+		if len(layer) == 0 {
+			continue
+		}
+		if request, err = d.merge(request, layer); err != nil {
+			return nil, err
+		}
+	}
+	return request, nil
+}
+
+// Apply merges the request over the default request of the account's requests of its integration type,
+// and then sets the enforced fields to the values of the host's default requests.
+func (d *Defaults) Apply(request []byte, account *config.Account, channel config.ChannelType) ([]byte, error) {
+	defaultRequest, err := d.Request(account, channel)
+	if err != nil || len(defaultRequest) == 0 {
+		return request, err
+	}
+
+	merged, err := d.merge(defaultRequest, request)
+	if err != nil {
+		return nil, err
+	}
+
+	hostRequest := d.hostRequest(channel)
+	for _, keys := range d.enforce {
+		value, dataType, _, err := jsonparser.Get(hostRequest, keys...)
+		if err != nil || dataType == jsonparser.NotExist {
+			continue
+		}
+		if dataType == jsonparser.String {
+			value = []byte(`"` + string(value) + `"`)
+		}
+		if merged, err = jsonparser.Set(merged, value, keys...); err != nil {
+			return nil, err
+		}
+	}
+	return merged, nil
+}
+
+// Enabled returns true if the account's requests of the integration type have default requests.
+func (d *Defaults) Enabled(account *config.Account, channel config.ChannelType) bool {
+	return len(d.hostRequest(channel)) > 0 || (account != nil && !account.DefaultRequest.Empty())
+}
+
+func (d *Defaults) hostRequest(channel config.ChannelType) json.RawMessage {
+	if d == nil {
+		return nil
+	}
+	if request, ok := d.channels[channel]; ok {
+		return request
+	}
+	return d.host
+}
+
+// merge merges the overlay over the base. It's a JSON Merge Patch, unless the host merges arrays or
+// replaces ext objects.
+func (d *Defaults) merge(base, overlay []byte) (json.RawMessage, error) {
+	if len(base) == 0 {
+		base = []byte(`{}`)
+	}
+	if d == nil || (!d.mergeArrays && !d.replaceExt) {
+		return jsonpatch.MergePatch(base, overlay)
+	}
+
+	baseValue, err := unmarshal(base)
+	if err != nil {
+		return nil, err
+	}
+	overlayValue, err := unmarshal(overlay)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(d.mergeValues(baseValue, overlayValue, ""))
+}
+
+// unmarshal unmarshals the JSON keeping its numbers as they are, so that large IDs aren't rounded.
+func unmarshal(data []byte) (interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// mergeValues merges the overlay over the base value at the key. Keys set to null are removed, as they are
+// by a JSON Merge Patch.
+func (d *Defaults) mergeValues(base, overlay interface{}, key string) interface{} {
+	switch overlayValue := overlay.(type) {
+	case map[string]interface{}:
+		baseObject, ok := base.(map[string]interface{})
+		if !ok || (key == "ext" && d.replaceExt) {
+			baseObject = map[string]interface{}{}
+		}
+		merged := make(map[string]interface{}, len(baseObject)+len(overlayValue))
+		for k, v := range baseObject {
+			merged[k] = v
+		}
+		for k, v := range overlayValue {
+			if v == nil {
+				delete(merged, k)
+				continue
+			}
+			merged[k] = d.mergeValues(merged[k], v, k)
+		}
+		return merged
+	case []interface{}:
+		baseArray, ok := base.([]interface{})
+		if !ok || !d.mergeArrays || key == "imp" {
+			return overlayValue
+		}
+		merged := append([]interface{}{}, baseArray...)
+		for _, item := range overlayValue {
+			if !containsValue(baseArray, item) {
+				merged = append(merged, item)
+			}
+		}
+		return merged
+	default:
+		return overlay
+	}
+}
+
+func containsValue(values []interface{}, value interface{}) bool {
+	for _, v := range values {
+		if reflect.DeepEqual(v, value) {
+			return true
+		}
+	}
+	return false
+}
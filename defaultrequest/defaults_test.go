@@ -0,0 +1,179 @@
+package defaultrequest
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew(t *testing.T) {
+	dir := t.TempDir()
+	appFile := filepath.Join(dir, "app.json")
+	require.NoError(t, os.WriteFile(appFile, []byte(`{"regs":{"coppa":1},"tmax":300}`), 0644))
+	invalidFile := filepath.Join(dir, "invalid.json")
+	require.NoError(t, os.WriteFile(invalidFile, []byte(`{"regs":`), 0644))
+
+	defaults, err := New([]byte(`{"tmax":500,"regs":{"gdpr":0}}`), config.DefReqConfig{
+		Type:     "file",
+		Channels: map[string]config.DefReqFiles{"app": {FileName: appFile}, "dooh": {}},
+	})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"tmax":300,"regs":{"gdpr":0,"coppa":1}}`, string(defaults.channels[config.ChannelApp]))
+	assert.NotContains(t, defaults.channels, config.ChannelDOOH)
+
+	_, err = New(nil, config.DefReqConfig{Channels: map[string]config.DefReqFiles{"web": {FileName: filepath.Join(dir, "missing.json")}}})
+	assert.Error(t, err)
+
+	_, err = New(nil, config.DefReqConfig{Channels: map[string]config.DefReqFiles{"web": {FileName: invalidFile}}})
+	assert.Error(t, err)
+}
+
+func TestRequest(t *testing.T) {
+	defaults := &Defaults{
+		host:     json.RawMessage(`{"tmax":500,"regs":{"gdpr":0}}`),
+		channels: map[config.ChannelType]json.RawMessage{config.ChannelApp: json.RawMessage(`{"tmax":300,"regs":{"gdpr":0}}`)},
+	}
+	account := &config.Account{DefaultRequest: config.AccountDefaultRequest{
+		Request:  json.RawMessage(`{"cur":["EUR"]}`),
+		Channels: map[string]json.RawMessage{"web": json.RawMessage(`{"tmax":800}`)},
+	}}
+
+	testCases := []struct {
+		description     string
+		defaults        *Defaults
+		account         *config.Account
+		channel         config.ChannelType
+		expectedRequest string
+	}{
+		{
+			description:     "host",
+			defaults:        defaults,
+			channel:         config.ChannelWeb,
+			expectedRequest: `{"tmax":500,"regs":{"gdpr":0}}`,
+		},
+		{
+			description:     "host-channel",
+			defaults:        defaults,
+			channel:         config.ChannelApp,
+			expectedRequest: `{"tmax":300,"regs":{"gdpr":0}}`,
+		},
+		{
+			description:     "account",
+			defaults:        defaults,
+			account:         account,
+			channel:         config.ChannelApp,
+			expectedRequest: `{"tmax":300,"regs":{"gdpr":0},"cur":["EUR"]}`,
+		},
+		{
+			description:     "account-channel",
+			defaults:        defaults,
+			account:         account,
+			channel:         config.ChannelWeb,
+			expectedRequest: `{"tmax":800,"regs":{"gdpr":0},"cur":["EUR"]}`,
+		},
+		{
+			description:     "account-without-host",
+			defaults:        nil,
+			account:         account,
+			channel:         config.ChannelDOOH,
+			expectedRequest: `{"cur":["EUR"]}`,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			request, err := test.defaults.Request(test.account, test.channel)
+			require.NoError(t, err)
+			assert.JSONEq(t, test.expectedRequest, string(request))
+		})
+	}
+}
+
+func TestApply(t *testing.T) {
+	host := json.RawMessage(`{"source":{"schain":{"ver":"1.0","complete":1,"nodes":[{"asi":"host.com","sid":"1","hp":1}]}},"regs":{"gdpr":1},"badv":["host.com"],"ext":{"prebid":{"targeting":{},"debug":true}}}`)
+
+	testCases := []struct {
+		description     string
+		merge           config.DefReqMerge
+		request         string
+		expectedRequest string
+	}{
+		{
+			description:     "merge-patch",
+			request:         `{"id":"req","imp":[{"id":"1"}],"badv":["request.com"],"regs":{"gdpr":null,"coppa":1},"ext":{"prebid":{"debug":false}}}`,
+			expectedRequest: `{"id":"req","imp":[{"id":"1"}],"source":{"schain":{"ver":"1.0","complete":1,"nodes":[{"asi":"host.com","sid":"1","hp":1}]}},"regs":{"coppa":1},"badv":["request.com"],"ext":{"prebid":{"targeting":{},"debug":false}}}`,
+		},
+		{
+			description:     "merge-arrays",
+			merge:           config.DefReqMerge{Arrays: "merge"},
+			request:         `{"id":"req","imp":[{"id":"1"}],"badv":["request.com","host.com"],"regs":{"gdpr":null}}`,
+			expectedRequest: `{"id":"req","imp":[{"id":"1"}],"source":{"schain":{"ver":"1.0","complete":1,"nodes":[{"asi":"host.com","sid":"1","hp":1}]}},"regs":{},"badv":["host.com","request.com"],"ext":{"prebid":{"targeting":{},"debug":true}}}`,
+		},
+		{
+			description:     "replace-ext",
+			merge:           config.DefReqMerge{Ext: "replace"},
+			request:         `{"id":"req","ext":{"prebid":{"debug":false}}}`,
+			expectedRequest: `{"id":"req","source":{"schain":{"ver":"1.0","complete":1,"nodes":[{"asi":"host.com","sid":"1","hp":1}]}},"regs":{"gdpr":1},"badv":["host.com"],"ext":{"prebid":{"debug":false}}}`,
+		},
+		{
+			description:     "enforce",
+			merge:           config.DefReqMerge{Enforce: []string{"source.schain", "regs.gdpr", "user.consent"}},
+			request:         `{"id":"req","source":{"tid":"t","schain":{"ver":"1.0","complete":0,"nodes":[]}},"regs":{"gdpr":0,"coppa":1}}`,
+			expectedRequest: `{"id":"req","source":{"tid":"t","schain":{"ver":"1.0","complete":1,"nodes":[{"asi":"host.com","sid":"1","hp":1}]}},"regs":{"gdpr":1,"coppa":1},"badv":["host.com"],"ext":{"prebid":{"targeting":{},"debug":true}}}`,
+		},
+		{
+			description:     "large-numbers",
+			merge:           config.DefReqMerge{Arrays: "merge"},
+			request:         `{"id":"req","bcat":[],"user":{"yob":1990,"ext":{"id":12345678901234567890}}}`,
+			expectedRequest: `{"id":"req","bcat":[],"user":{"yob":1990,"ext":{"id":12345678901234567890}},"source":{"schain":{"ver":"1.0","complete":1,"nodes":[{"asi":"host.com","sid":"1","hp":1}]}},"regs":{"gdpr":1},"badv":["host.com"],"ext":{"prebid":{"targeting":{},"debug":true}}}`,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			defaults, err := New(host, config.DefReqConfig{Merge: test.merge})
+			require.NoError(t, err)
+
+			request, err := defaults.Apply([]byte(test.request), nil, config.ChannelWeb)
+
+			require.NoError(t, err)
+			assert.JSONEq(t, test.expectedRequest, string(request))
+		})
+	}
+}
+
+func TestApplyEnforcesHostOverAccount(t *testing.T) {
+	defaults, err := New([]byte(`{"regs":{"coppa":1}}`), config.DefReqConfig{Merge: config.DefReqMerge{Enforce: []string{"regs"}}})
+	require.NoError(t, err)
+	account := &config.Account{DefaultRequest: config.AccountDefaultRequest{Request: json.RawMessage(`{"regs":{"coppa":0},"tmax":500}`)}}
+
+	request, err := defaults.Apply([]byte(`{"id":"req","regs":{"gdpr":1}}`), account, config.ChannelApp)
+
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"id":"req","regs":{"coppa":1},"tmax":500}`, string(request))
+}
+
+func TestApplyWithoutDefaults(t *testing.T) {
+	var defaults *Defaults
+	request := []byte(`{"id":"req"}`)
+
+	assert.False(t, defaults.Enabled(nil, config.ChannelWeb))
+	applied, err := defaults.Apply(request, &config.Account{}, config.ChannelWeb)
+
+	require.NoError(t, err)
+	assert.Equal(t, request, applied)
+}
+
+func TestApplyInvalidRequest(t *testing.T) {
+	defaults, err := New([]byte(`{"tmax":500}`), config.DefReqConfig{Merge: config.DefReqMerge{Arrays: "merge"}})
+	require.NoError(t, err)
+
+	_, err = defaults.Apply([]byte(`{"id":`), nil, config.ChannelWeb)
+
+	assert.Error(t, err)
+}
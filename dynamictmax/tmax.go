@@ -0,0 +1,93 @@
+// Package dynamictmax adjusts the tmax of requests by their channel and the connection type of their device,
+// within the bounds set by the account, since a single tmax is too long for app users on fast networks and
+// too short for mobile web users on slow ones.
+package dynamictmax
+
+import (
+	"github.com/prebid/openrtb/v20/adcom1"
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/openrtb_ext"
+)
+
+const (
+	boundMin     = "min"
+	boundMax     = "max"
+	boundHostMax = "host_max"
+)
+
+var connectionTypes = map[adcom1.ConnectionType]string{
+	adcom1.ConnectionEthernet: "ethernet",
+	adcom1.ConnectionWIFI:     "wifi",
+	adcom1.ConnectionCellular: "cellular",
+	adcom1.Connection2G:       "2g",
+	adcom1.Connection3G:       "3g",
+	adcom1.Connection4G:       "4g",
+	adcom1.Connection5G:       "5g",
+}
+
+// Adjustment is how the tmax of a request was adjusted.
+type Adjustment struct {
+	requested      int64
+	applied        int64
+	channel        string
+	connectionType string
+	bound          string
+}
+
+// Adjust adds the account's adjustments for the channel and for the device's connection type to the tmax of
+// the request, or to the default of timeouts if the request has none, and bounds it by the account's bounds
+// and the max of timeouts. It returns nil, and leaves the tmax as it is, if the account doesn't enable
+// dynamic tmax or there's no tmax to adjust.
+func Adjust(req *openrtb_ext.RequestWrapper, channel config.ChannelType, cfg config.AccountDynamicTmax, timeouts config.AuctionTimeouts) *Adjustment {
+	if !cfg.Enabled {
+		return nil
+	}
+	base := req.TMax
+	if base <= 0 {
+		base = int64(timeouts.Default)
+	}
+	if base <= 0 {
+		return nil
+	}
+
+	a := &Adjustment{requested: req.TMax, channel: string(channel)}
+	applied := base + cfg.ChannelMS[a.channel]
+	if req.Device != nil && req.Device.ConnectionType != nil {
+		a.connectionType = connectionTypes[*req.Device.ConnectionType]
+		if a.connectionType != "" {
+			applied += cfg.ConnectionTypeMS[a.connectionType]
+		}
+	}
+
+	if cfg.MinMS > 0 && applied < cfg.MinMS {
+		applied, a.bound = cfg.MinMS, boundMin
+	}
+	if cfg.MaxMS > 0 && applied > cfg.MaxMS {
+		applied, a.bound = cfg.MaxMS, boundMax
+	}
+	if timeouts.Max > 0 && applied > int64(timeouts.Max) {
+		applied, a.bound = int64(timeouts.Max), boundHostMax
+	}
+	if applied <= 0 {
+		// adjustments which leave no time at all are a misconfiguration, so the tmax is left as it was
+		applied, a.bound = base, ""
+	}
+
+	a.applied = applied
+	req.TMax = applied
+	return a
+}
+
+// Debug returns the adjustment for bidresponse.ext.debug.dynamictmax, or nil if the tmax wasn't adjusted.
+func (a *Adjustment) Debug() *openrtb_ext.ExtResponseDynamicTmax {
+	if a == nil {
+		return nil
+	}
+	return &openrtb_ext.ExtResponseDynamicTmax{
+		RequestedMS:    a.requested,
+		AppliedMS:      a.applied,
+		Channel:        a.channel,
+		ConnectionType: a.connectionType,
+		Bound:          a.bound,
+	}
+}
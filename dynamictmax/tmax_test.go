@@ -0,0 +1,135 @@
+package dynamictmax
+
+import (
+	"testing"
+
+	"github.com/prebid/openrtb/v20/adcom1"
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/openrtb_ext"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdjust(t *testing.T) {
+	cfg := config.AccountDynamicTmax{
+		Enabled:          true,
+		MinMS:            300,
+		MaxMS:            2000,
+		ChannelMS:        map[string]int64{"app": -200, "amp": 100},
+		ConnectionTypeMS: map[string]int64{"3g": 600, "5g": -300, "2g": 1500},
+	}
+
+	tests := []struct {
+		description    string
+		channel        config.ChannelType
+		tmax           int64
+		connectionType *adcom1.ConnectionType
+		cfg            config.AccountDynamicTmax
+		timeouts       config.AuctionTimeouts
+		expectedTmax   int64
+		expectedDebug  *openrtb_ext.ExtResponseDynamicTmax
+	}{
+		{
+			description:   "disabled",
+			channel:       config.ChannelWeb,
+			tmax:          1000,
+			cfg:           config.AccountDynamicTmax{ChannelMS: map[string]int64{"web": 500}},
+			expectedTmax:  1000,
+			expectedDebug: nil,
+		},
+		{
+			description:    "web-on-3g",
+			channel:        config.ChannelWeb,
+			tmax:           1000,
+			connectionType: adcom1.Connection3G.Ptr(),
+			cfg:            cfg,
+			expectedTmax:   1600,
+			expectedDebug:  &openrtb_ext.ExtResponseDynamicTmax{RequestedMS: 1000, AppliedMS: 1600, Channel: "web", ConnectionType: "3g"},
+		},
+		{
+			description:    "app-on-5g",
+			channel:        config.ChannelApp,
+			tmax:           1000,
+			connectionType: adcom1.Connection5G.Ptr(),
+			cfg:            cfg,
+			expectedTmax:   500,
+			expectedDebug:  &openrtb_ext.ExtResponseDynamicTmax{RequestedMS: 1000, AppliedMS: 500, Channel: "app", ConnectionType: "5g"},
+		},
+		{
+			description:    "unknown-connection-type",
+			channel:        config.ChannelAMP,
+			tmax:           1000,
+			connectionType: adcom1.ConnectionUnknown.Ptr(),
+			cfg:            cfg,
+			expectedTmax:   1100,
+			expectedDebug:  &openrtb_ext.ExtResponseDynamicTmax{RequestedMS: 1000, AppliedMS: 1100, Channel: "amp"},
+		},
+		{
+			description:    "held-to-min",
+			channel:        config.ChannelApp,
+			tmax:           600,
+			connectionType: adcom1.Connection5G.Ptr(),
+			cfg:            cfg,
+			expectedTmax:   300,
+			expectedDebug:  &openrtb_ext.ExtResponseDynamicTmax{RequestedMS: 600, AppliedMS: 300, Channel: "app", ConnectionType: "5g", Bound: "min"},
+		},
+		{
+			description:    "held-to-max",
+			channel:        config.ChannelWeb,
+			tmax:           1000,
+			connectionType: adcom1.Connection2G.Ptr(),
+			cfg:            cfg,
+			expectedTmax:   2000,
+			expectedDebug:  &openrtb_ext.ExtResponseDynamicTmax{RequestedMS: 1000, AppliedMS: 2000, Channel: "web", ConnectionType: "2g", Bound: "max"},
+		},
+		{
+			description:    "held-to-host-max",
+			channel:        config.ChannelWeb,
+			tmax:           1000,
+			connectionType: adcom1.Connection3G.Ptr(),
+			cfg:            cfg,
+			timeouts:       config.AuctionTimeouts{Max: 1200},
+			expectedTmax:   1200,
+			expectedDebug:  &openrtb_ext.ExtResponseDynamicTmax{RequestedMS: 1000, AppliedMS: 1200, Channel: "web", ConnectionType: "3g", Bound: "host_max"},
+		},
+		{
+			description:    "host-default",
+			channel:        config.ChannelWeb,
+			connectionType: adcom1.Connection3G.Ptr(),
+			cfg:            cfg,
+			timeouts:       config.AuctionTimeouts{Default: 800},
+			expectedTmax:   1400,
+			expectedDebug:  &openrtb_ext.ExtResponseDynamicTmax{RequestedMS: 0, AppliedMS: 1400, Channel: "web", ConnectionType: "3g"},
+		},
+		{
+			description:    "no-tmax",
+			channel:        config.ChannelWeb,
+			connectionType: adcom1.Connection3G.Ptr(),
+			cfg:            cfg,
+			expectedTmax:   0,
+			expectedDebug:  nil,
+		},
+		{
+			description:    "no-time-left",
+			channel:        config.ChannelApp,
+			tmax:           400,
+			connectionType: adcom1.Connection5G.Ptr(),
+			cfg:            config.AccountDynamicTmax{Enabled: true, ChannelMS: cfg.ChannelMS, ConnectionTypeMS: cfg.ConnectionTypeMS},
+			expectedTmax:   400,
+			expectedDebug:  &openrtb_ext.ExtResponseDynamicTmax{RequestedMS: 400, AppliedMS: 400, Channel: "app", ConnectionType: "5g"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			req := &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{TMax: test.tmax}}
+			if test.connectionType != nil {
+				req.Device = &openrtb2.Device{ConnectionType: test.connectionType}
+			}
+
+			adjustment := Adjust(req, test.channel, test.cfg, test.timeouts)
+
+			assert.Equal(t, test.expectedTmax, req.TMax)
+			assert.Equal(t, test.expectedDebug, adjustment.Debug())
+		})
+	}
+}
@@ -0,0 +1,26 @@
+package endpoints
+
+import (
+	"net/http"
+
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/logger"
+	"github.com/prebid/prebid-server/v2/util/jsonutil"
+)
+
+// NewDeprecatedSettingsEndpoint returns the deprecated config settings the server was started with along with
+// their replacements, so operators can find settings which must be updated before upgrading.
+func NewDeprecatedSettingsEndpoint(settings []config.DeprecatedSetting) http.HandlerFunc {
+	if settings == nil {
+		settings = []config.DeprecatedSetting{}
+	}
+	jsonOutput, err := jsonutil.Marshal(settings)
+	if err != nil {
+		logger.Fatalf("error creating /config/deprecated endpoint response: %v", err)
+	}
+
+	return func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(jsonOutput)
+	}
+}
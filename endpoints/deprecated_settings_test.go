@@ -0,0 +1,45 @@
+package endpoints
+
+import (
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeprecatedSettings(t *testing.T) {
+	var testCases = []struct {
+		description string
+		settings    []config.DeprecatedSetting
+		expected    string
+	}{
+		{
+			description: "None",
+			settings:    nil,
+			expected:    `[]`,
+		},
+		{
+			description: "Replaced and removed",
+			settings: []config.DeprecatedSetting{
+				{Key: "enable_gzip", Replacement: "compression.response.enable_gzip"},
+				{Key: "blacklisted_accts", Message: "use account config"},
+			},
+			expected: `[{"key":"enable_gzip","replacement":"compression.response.enable_gzip"},{"key":"blacklisted_accts","message":"use account config"}]`,
+		},
+	}
+
+	for _, test := range testCases {
+		handler := NewDeprecatedSettingsEndpoint(test.settings)
+		w := httptest.NewRecorder()
+
+		handler(w, nil)
+
+		response, err := io.ReadAll(w.Result().Body)
+		if assert.NoError(t, err, test.description+":read") {
+			assert.JSONEq(t, test.expected, string(response), test.description+":response")
+			assert.Equal(t, "application/json", w.Header().Get("Content-Type"), test.description+":content-type")
+		}
+	}
+}
@@ -21,6 +21,7 @@ import (
 	"github.com/prebid/prebid-server/v2/privacy"
 	"github.com/prebid/prebid-server/v2/stored_requests"
 	"github.com/prebid/prebid-server/v2/util/httputil"
+	"github.com/prebid/prebid-server/v2/webhooks"
 )
 
 const (
@@ -47,15 +48,17 @@ type eventEndpoint struct {
 	Cfg           *config.Configuration
 	TrackingPixel *httputil.Pixel
 	MetricsEngine metrics.MetricsEngine
+	EventWebhooks *webhooks.AuctionEventNotifier
 }
 
-func NewEventEndpoint(cfg *config.Configuration, accounts stored_requests.AccountFetcher, analytics analytics.Runner, me metrics.MetricsEngine) httprouter.Handle {
+func NewEventEndpoint(cfg *config.Configuration, accounts stored_requests.AccountFetcher, analytics analytics.Runner, me metrics.MetricsEngine, eventWebhooks *webhooks.AuctionEventNotifier) httprouter.Handle {
 	ee := &eventEndpoint{
 		Accounts:      accounts,
 		Analytics:     analytics,
 		Cfg:           cfg,
 		TrackingPixel: &httputil.Pixel1x1PNG,
 		MetricsEngine: me,
+		EventWebhooks: eventWebhooks,
 	}
 
 	return ee.Handle
@@ -125,6 +128,9 @@ func (e *eventEndpoint) Handle(w http.ResponseWriter, r *http.Request, _ httprou
 		Account: account,
 	}, activities)
 
+	// send the event to the account's webhook, if it has one
+	e.EventWebhooks.Notify(account, eventRequest)
+
 	// Add tracking pixel if format == image
 	if eventRequest.Format == analytics.Image {
 		w.WriteHeader(http.StatusOK)
@@ -287,6 +293,9 @@ func readType(er *analytics.EventRequest, httpRequest *http.Request) error {
 	case string(analytics.Win):
 		er.Type = analytics.Win
 		return nil
+	case string(analytics.Loss):
+		er.Type = analytics.Loss
+		return nil
 	case string(analytics.Vast):
 		er.Type = analytics.Vast
 		return nil
@@ -5,9 +5,9 @@ import (
 	"sort"
 	"strings"
 
-	"github.com/golang/glog"
 	"github.com/julienschmidt/httprouter"
 	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/logger"
 	"github.com/prebid/prebid-server/v2/util/jsonutil"
 )
 
@@ -18,22 +18,22 @@ var invalidBaseAdaptersOnlyMsg = []byte(`Invalid value for 'baseadaptersonly' qu
 func NewBiddersEndpoint(bidders config.BidderInfos, aliases map[string]string) httprouter.Handle {
 	responseAll, err := prepareBiddersResponseAll(bidders, aliases)
 	if err != nil {
-		glog.Fatalf("error creating /info/bidders endpoint all bidders response: %v", err)
+		logger.Fatalf("error creating /info/bidders endpoint all bidders response: %v", err)
 	}
 
 	responseAllBaseOnly, err := prepareBiddersResponseAllBaseOnly(bidders)
 	if err != nil {
-		glog.Fatalf("error creating /info/bidders endpoint all bidders (base adapters only) response: %v", err)
+		logger.Fatalf("error creating /info/bidders endpoint all bidders (base adapters only) response: %v", err)
 	}
 
 	responseEnabledOnly, err := prepareBiddersResponseEnabledOnly(bidders, aliases)
 	if err != nil {
-		glog.Fatalf("error creating /info/bidders endpoint enabled only response: %v", err)
+		logger.Fatalf("error creating /info/bidders endpoint enabled only response: %v", err)
 	}
 
 	responseEnabledOnlyBaseOnly, err := prepareBiddersResponseEnabledOnlyBaseOnly(bidders)
 	if err != nil {
-		glog.Fatalf("error creating /info/bidders endpoint enabled only (base adapters only) response: %v", err)
+		logger.Fatalf("error creating /info/bidders endpoint enabled only (base adapters only) response: %v", err)
 	}
 
 	return func(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
@@ -163,6 +163,6 @@ func writeResponse(w http.ResponseWriter, data []byte) {
 
 func writeWithErrorHandling(w http.ResponseWriter, data []byte) {
 	if _, err := w.Write(data); err != nil {
-		glog.Errorf("error writing response to /info/bidders: %v", err)
+		logger.Errorf("error writing response to /info/bidders: %v", err)
 	}
 }
@@ -0,0 +1,55 @@
+package endpoints
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/prebid/prebid-server/v2/logger"
+	"github.com/prebid/prebid-server/v2/util/jsonutil"
+)
+
+// maxLogLevelsSize limits the body of requests which change the log levels.
+const maxLogLevelsSize = 64 * 1024
+
+// NewLogLevelsEndpoint returns the levels the server logs at for GET requests, and replaces them with the
+// levels in the body of PUT requests, so logging can be turned up for a component or account while an
+// issue is investigated. Changes last until the server restarts.
+func NewLogLevelsEndpoint() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+		case http.MethodPut:
+			body, err := io.ReadAll(io.LimitReader(r.Body, maxLogLevelsSize))
+			if err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				fmt.Fprintf(w, "Invalid request: %v\n", err)
+				return
+			}
+			var levels logger.Levels
+			if err := jsonutil.UnmarshalValid(body, &levels); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				fmt.Fprintf(w, "Invalid request: %v\n", err)
+				return
+			}
+			if err := logger.SetLevels(levels); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				fmt.Fprintf(w, "Invalid request: %v\n", err)
+				return
+			}
+			logger.Warningf("Log levels changed to %s", body)
+		default:
+			w.Header().Set("Allow", "GET, PUT")
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		levels, err := jsonutil.Marshal(logger.GetLevels())
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(levels)
+	}
+}
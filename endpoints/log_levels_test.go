@@ -0,0 +1,74 @@
+package endpoints
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/prebid/prebid-server/v2/logger"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogLevelsEndpoint(t *testing.T) {
+	previous := logger.GetLevels()
+	defer logger.SetLevels(previous)
+
+	testCases := []struct {
+		description    string
+		method         string
+		body           string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			description:    "Get",
+			method:         http.MethodGet,
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"level":"info"}`,
+		},
+		{
+			description:    "Put",
+			method:         http.MethodPut,
+			body:           `{"level":"warning","components":{"exchange":"debug"},"accounts":{"1001":"debug"}}`,
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"level":"warning","components":{"exchange":"debug"},"accounts":{"1001":"debug"}}`,
+		},
+		{
+			description:    "Put unknown level",
+			method:         http.MethodPut,
+			body:           `{"level":"loud"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "Invalid request: unknown log level: loud\n",
+		},
+		{
+			description:    "Put malformed",
+			method:         http.MethodPut,
+			body:           `{"level":`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			description:    "Post",
+			method:         http.MethodPost,
+			expectedStatus: http.StatusMethodNotAllowed,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			logger.SetLevels(logger.Levels{Level: "info"})
+
+			req := httptest.NewRequest(test.method, "/logging/levels", strings.NewReader(test.body))
+			w := httptest.NewRecorder()
+			NewLogLevelsEndpoint()(w, req)
+
+			assert.Equal(t, test.expectedStatus, w.Code)
+			if test.expectedBody != "" {
+				assert.Equal(t, test.expectedBody, w.Body.String())
+			}
+			if test.expectedStatus == http.StatusMethodNotAllowed {
+				assert.Equal(t, "GET, PUT", w.Header().Get("Allow"))
+			}
+		})
+	}
+}
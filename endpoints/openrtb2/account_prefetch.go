@@ -0,0 +1,43 @@
+package openrtb2
+
+import (
+	"context"
+
+	accountService "github.com/prebid/prebid-server/v2/account"
+	"github.com/prebid/prebid-server/v2/config"
+)
+
+// accountPrefetch is a fetch of the account a request names, started before its stored requests are in,
+// so the two lookups take as long as the slower of them rather than the sum of both.
+type accountPrefetch struct {
+	accountID string
+	done      chan struct{}
+	account   *config.Account
+	errs      []error
+}
+
+// prefetchAccount starts fetching the account the request names. It returns nil if the request doesn't
+// name an account, since it would only be known once its stored request is in.
+func (deps *endpointDeps) prefetchAccount(ctx context.Context, requestJson []byte) *accountPrefetch {
+	accountID, _, _, err := searchAccountId(requestJson)
+	if err != nil || accountID == "" {
+		return nil
+	}
+
+	prefetch := &accountPrefetch{accountID: accountID, done: make(chan struct{})}
+	go func() {
+		defer close(prefetch.done)
+		prefetch.account, prefetch.errs = accountService.GetAccount(ctx, deps.cfg, deps.accounts, accountID, deps.metricsEngine)
+	}()
+	return prefetch
+}
+
+// getAccount returns the account, waiting for the prefetched one if it's the same account. A stored request
+// may name another account than the request itself, which is then fetched on its own.
+func (deps *endpointDeps) getAccount(ctx context.Context, prefetch *accountPrefetch, accountID string) (*config.Account, []error) {
+	if prefetch != nil && prefetch.accountID == accountID {
+		<-prefetch.done
+		return prefetch.account, prefetch.errs
+	}
+	return accountService.GetAccount(ctx, deps.cfg, deps.accounts, accountID, deps.metricsEngine)
+}
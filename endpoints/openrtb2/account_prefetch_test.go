@@ -0,0 +1,129 @@
+package openrtb2
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/prebid/prebid-server/v2/config"
+	metricsConfig "github.com/prebid/prebid-server/v2/metrics/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingAccountFetcher counts the fetches of each account, and signals each fetch on fetched.
+type countingAccountFetcher struct {
+	mockAccountFetcher
+	lock    sync.Mutex
+	fetches map[string]int
+	fetched chan string
+}
+
+func (af *countingAccountFetcher) FetchAccount(ctx context.Context, defaultAccountJSON json.RawMessage, accountID string) (json.RawMessage, []error) {
+	af.lock.Lock()
+	af.fetches[accountID]++
+	af.lock.Unlock()
+	if af.fetched != nil {
+		af.fetched <- accountID
+	}
+	return af.mockAccountFetcher.FetchAccount(ctx, defaultAccountJSON, accountID)
+}
+
+func newPrefetchTestDeps(fetcher *countingAccountFetcher) *endpointDeps {
+	return &endpointDeps{
+		cfg:           &config.Configuration{},
+		accounts:      fetcher,
+		metricsEngine: &metricsConfig.NilMetricsEngine{},
+	}
+}
+
+func TestPrefetchAccount(t *testing.T) {
+	testCases := []struct {
+		name            string
+		request         string
+		accountID       string
+		expectedFetches map[string]int
+	}{
+		{
+			name:            "same-account",
+			request:         `{"site":{"publisher":{"id":"account1"}}}`,
+			accountID:       "account1",
+			expectedFetches: map[string]int{"account1": 1},
+		},
+		{
+			name:            "stored-request-names-another-account",
+			request:         `{"app":{"publisher":{"id":"account1"}}}`,
+			accountID:       "account2",
+			expectedFetches: map[string]int{"account1": 1, "account2": 1},
+		},
+		{
+			name:            "no-account",
+			request:         `{"ext":{"prebid":{"storedrequest":{"id":"1"}}}}`,
+			accountID:       "account2",
+			expectedFetches: map[string]int{"account2": 1},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			fetcher := &countingAccountFetcher{
+				mockAccountFetcher: mockAccountFetcher{data: map[string]json.RawMessage{
+					"account1": json.RawMessage(`{"id":"account1"}`),
+					"account2": json.RawMessage(`{"id":"account2"}`),
+				}},
+				fetches: make(map[string]int),
+			}
+			deps := newPrefetchTestDeps(fetcher)
+
+			prefetch := deps.prefetchAccount(context.Background(), []byte(test.request))
+			account, errs := deps.getAccount(context.Background(), prefetch, test.accountID)
+
+			assert.Empty(t, errs)
+			require.NotNil(t, account)
+			assert.Equal(t, test.accountID, account.ID)
+			if prefetch != nil {
+				<-prefetch.done
+			}
+			assert.Equal(t, test.expectedFetches, fetcher.fetches)
+		})
+	}
+}
+
+func TestPrefetchAccountRunsAlongWithStoredRequests(t *testing.T) {
+	fetcher := &countingAccountFetcher{
+		mockAccountFetcher: mockAccountFetcher{data: map[string]json.RawMessage{"account1": json.RawMessage(`{"id":"account1"}`)}},
+		fetches:            make(map[string]int),
+		fetched:            make(chan string, 1),
+	}
+	deps := newPrefetchTestDeps(fetcher)
+
+	prefetch := deps.prefetchAccount(context.Background(), []byte(`{"site":{"publisher":{"id":"account1"}}}`))
+
+	// the account is fetched before getAccount is called, as the stored requests would be
+	select {
+	case accountID := <-fetcher.fetched:
+		assert.Equal(t, "account1", accountID)
+	case <-time.After(time.Second):
+		t.Fatal("the account wasn't prefetched")
+	}
+	account, errs := deps.getAccount(context.Background(), prefetch, "account1")
+	assert.Empty(t, errs)
+	assert.Equal(t, "account1", account.ID)
+}
+
+func TestPrefetchAccountErrors(t *testing.T) {
+	fetcher := &countingAccountFetcher{
+		mockAccountFetcher: mockAccountFetcher{data: map[string]json.RawMessage{"disabled": json.RawMessage(`{"id":"disabled","disabled":true}`)}},
+		fetches:            make(map[string]int),
+	}
+	deps := newPrefetchTestDeps(fetcher)
+
+	prefetch := deps.prefetchAccount(context.Background(), []byte(`{"site":{"publisher":{"id":"disabled"}}}`))
+	account, errs := deps.getAccount(context.Background(), prefetch, "disabled")
+
+	assert.Nil(t, account)
+	assert.Len(t, errs, 1)
+	assert.Equal(t, map[string]int{"disabled": 1}, fetcher.fetches)
+}
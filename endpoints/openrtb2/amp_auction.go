@@ -12,11 +12,11 @@ import (
 	"time"
 
 	"github.com/buger/jsonparser"
-	"github.com/golang/glog"
 	"github.com/julienschmidt/httprouter"
 	"github.com/prebid/openrtb/v20/openrtb2"
 	"github.com/prebid/openrtb/v20/openrtb3"
 	"github.com/prebid/prebid-server/v2/hooks/hookexecution"
+	"github.com/prebid/prebid-server/v2/logger"
 	"github.com/prebid/prebid-server/v2/ortb"
 	"github.com/prebid/prebid-server/v2/util/uuidutil"
 	jsonpatch "gopkg.in/evanphx/json-patch.v4"
@@ -25,16 +25,21 @@ import (
 	"github.com/prebid/prebid-server/v2/amp"
 	"github.com/prebid/prebid-server/v2/analytics"
 	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/dynamictmax"
 	"github.com/prebid/prebid-server/v2/errortypes"
 	"github.com/prebid/prebid-server/v2/exchange"
 	"github.com/prebid/prebid-server/v2/gdpr"
+	"github.com/prebid/prebid-server/v2/geolocation"
 	"github.com/prebid/prebid-server/v2/hooks"
+	"github.com/prebid/prebid-server/v2/ivt"
+	"github.com/prebid/prebid-server/v2/loadshedding"
 	"github.com/prebid/prebid-server/v2/metrics"
 	"github.com/prebid/prebid-server/v2/openrtb_ext"
 	"github.com/prebid/prebid-server/v2/privacy"
 	"github.com/prebid/prebid-server/v2/stored_requests"
 	"github.com/prebid/prebid-server/v2/stored_requests/backends/empty_fetcher"
 	"github.com/prebid/prebid-server/v2/stored_responses"
+	"github.com/prebid/prebid-server/v2/storedversions"
 	"github.com/prebid/prebid-server/v2/usersync"
 	"github.com/prebid/prebid-server/v2/util/iputil"
 	"github.com/prebid/prebid-server/v2/util/jsonutil"
@@ -71,6 +76,9 @@ func NewAmpEndpoint(
 	storedRespFetcher stored_requests.Fetcher,
 	hookExecutionPlanBuilder hooks.ExecutionPlanBuilder,
 	tmaxAdjustments *exchange.TmaxAdjustmentsPreprocessed,
+	geoEnricher *geolocation.Enricher,
+	ivtFilter *ivt.Filter,
+	storedRequestVersions *storedversions.Versions,
 ) (httprouter.Handle, error) {
 
 	if ex == nil || validator == nil || requestsById == nil || accounts == nil || cfg == nil || metricsEngine == nil {
@@ -105,6 +113,16 @@ func NewAmpEndpoint(
 		hookExecutionPlanBuilder,
 		tmaxAdjustments,
 		openrtb_ext.NormalizeBidderName,
+		newParsedRequestCache(cfg.ParsedStoredRequestCache),
+		nil,
+		ivtFilter,
+		nil,
+		nil,
+		geoEnricher,
+		nil,
+		nil,
+		nil,
+		storedRequestVersions,
 	}).AmpAuction), nil
 
 }
@@ -119,6 +137,7 @@ func (deps *endpointDeps) AmpAuction(w http.ResponseWriter, r *http.Request, _ h
 	start := time.Now()
 
 	hookExecutor := hookexecution.NewHookExecutor(deps.hookExecutionPlanBuilder, hookexecution.EndpointAmp, deps.metricsEngine)
+	loadshedding.SkipOptionalModules(r.Context(), hookExecutor, deps.cfg.LoadShedding.Memory.OptionalModules)
 
 	ao := analytics.AmpObject{
 		Status:    http.StatusOK,
@@ -138,8 +157,8 @@ func (deps *endpointDeps) AmpAuction(w http.ResponseWriter, r *http.Request, _ h
 	activityControl := privacy.ActivityControl{}
 
 	defer func() {
-		deps.metricsEngine.RecordRequest(labels)
-		deps.metricsEngine.RecordRequestTime(labels, time.Since(start))
+		ao.Test = isTestTraffic(deps.cfg.TestTraffic, ao.RequestWrapper)
+		recordRequestMetrics(deps.metricsEngine, labels, ao.Test, start)
 		deps.analytics.LogAmpObject(&ao, activityControl)
 	}()
 
@@ -164,7 +183,7 @@ func (deps *endpointDeps) AmpAuction(w http.ResponseWriter, r *http.Request, _ h
 	// Process reject after parsing amp request, so we can use reqWrapper.
 	// There is no body for AMP requests, so we pass a nil body and ignore the return value.
 	if rejectErr != nil {
-		labels, ao = rejectAmpRequest(*rejectErr, w, hookExecutor, reqWrapper, nil, labels, ao, nil)
+		labels, ao = rejectAmpRequest(r.Context(), *rejectErr, w, hookExecutor, reqWrapper, nil, labels, ao, nil)
 		return
 	}
 
@@ -230,6 +249,22 @@ func (deps *endpointDeps) AmpAuction(w http.ResponseWriter, r *http.Request, _ h
 		return
 	}
 
+	setClientHintsImplicitly(r, reqWrapper, account)
+	deps.geoEnricher.Enrich(reqWrapper.Device)
+
+	if err := deps.screenInvalidTraffic(reqWrapper, account); err != nil {
+		httpStatus := http.StatusBadRequest
+		labels.RequestStatus = metrics.RequestStatusBadInput
+		if errortypes.ReadCode(err) == errortypes.InvalidTrafficErrorCode {
+			httpStatus = http.StatusServiceUnavailable
+			labels.RequestStatus = metrics.RequestStatusBlacklisted
+		}
+		w.WriteHeader(httpStatus)
+		fmt.Fprintf(w, "Invalid request: %s\n", err.Error())
+		ao.Errors = append(ao.Errors, err)
+		return
+	}
+
 	hasStoredResponses := len(storedAuctionResponses) > 0
 	errs := deps.validateRequest(account, r, reqWrapper, true, hasStoredResponses, storedBidResponses, false)
 	errL = append(errL, errs...)
@@ -243,6 +278,11 @@ func (deps *endpointDeps) AmpAuction(w http.ResponseWriter, r *http.Request, _ h
 		return
 	}
 
+	// AMP requests can't carry debug tokens, so mappings limited to them don't apply
+	accountStoredBidResponses, accountBidderImpReplaceImpID, accountStoredErrs := stored_responses.ProcessAccountStoredBidResponses(ctx, reqWrapper, account.StoredBidResponses, false, deps.storedRespFetcher)
+	errL = append(errL, accountStoredErrs...)
+	ao.Errors = append(ao.Errors, accountStoredErrs...)
+
 	tcf2Config := gdpr.NewTCF2Config(deps.cfg.GDPR.TCF2, account.GDPR)
 
 	activityControl = privacy.NewActivityControl(&account.Privacy)
@@ -252,25 +292,37 @@ func (deps *endpointDeps) AmpAuction(w http.ResponseWriter, r *http.Request, _ h
 
 	secGPC := r.Header.Get("Sec-GPC")
 
-	auctionRequest := &exchange.AuctionRequest{
-		BidRequestWrapper:          reqWrapper,
-		Account:                    *account,
-		UserSyncs:                  usersyncs,
-		RequestType:                labels.RType,
-		StartTime:                  start,
-		LegacyLabels:               labels,
-		GlobalPrivacyControlHeader: secGPC,
-		StoredAuctionResponses:     storedAuctionResponses,
-		StoredBidResponses:         storedBidResponses,
-		BidderImpReplaceImpID:      bidderImpReplaceImp,
-		PubID:                      labels.PubID,
-		HookExecutor:               hookExecutor,
-		QueryParams:                r.URL.Query(),
-		TCF2Config:                 tcf2Config,
-		Activities:                 activityControl,
-		TmaxAdjustments:            deps.tmaxAdjustments,
+	// the deadline is moved if the account adjusts the tmax, which can only be known once the account is looked up
+	tmaxAdjustment := dynamictmax.Adjust(reqWrapper, config.ChannelAMP, account.DynamicTmax, config.AuctionTimeouts{Default: defaultAmpRequestTimeoutMillis})
+	if tmaxAdjustment != nil {
+		cancel()
+		ctx, cancel = context.WithDeadline(context.Background(), start.Add(time.Duration(reqWrapper.TMax)*time.Millisecond))
+		defer cancel()
 	}
 
+	auctionRequest := &exchange.AuctionRequest{
+		BidRequestWrapper:            reqWrapper,
+		Account:                      *account,
+		UserSyncs:                    usersyncs,
+		RequestType:                  labels.RType,
+		StartTime:                    start,
+		LegacyLabels:                 labels,
+		GlobalPrivacyControlHeader:   secGPC,
+		StoredAuctionResponses:       storedAuctionResponses,
+		StoredBidResponses:           storedBidResponses,
+		BidderImpReplaceImpID:        bidderImpReplaceImp,
+		PubID:                        labels.PubID,
+		HookExecutor:                 hookExecutor,
+		QueryParams:                  r.URL.Query(),
+		TCF2Config:                   tcf2Config,
+		Activities:                   activityControl,
+		TmaxAdjustments:              deps.tmaxAdjustments,
+		DynamicTmax:                  tmaxAdjustment,
+		AccountStoredBidResponses:    accountStoredBidResponses,
+		AccountBidderImpReplaceImpID: accountBidderImpReplaceImpID,
+	}
+	loadshedding.SkipDebug(r.Context(), &auctionRequest.Account)
+
 	auctionResponse, err := deps.ex.HoldAuction(ctx, auctionRequest, nil)
 	defer func() {
 		if !auctionRequest.BidderResponseStartTime.IsZero() {
@@ -284,10 +336,17 @@ func (deps *endpointDeps) AmpAuction(w http.ResponseWriter, r *http.Request, _ h
 	ao.SeatNonBid = auctionResponse.GetSeatNonBid()
 	ao.AuctionResponse = response
 	rejectErr, isRejectErr := hookexecution.CastRejectErr(err)
+	if errortypes.ReadCode(err) == errortypes.RateLimitedErrorCode {
+		w.WriteHeader(http.StatusTooManyRequests)
+		fmt.Fprintf(w, "Invalid request: %s\n", err.Error())
+		ao.Status = http.StatusTooManyRequests
+		ao.Errors = append(ao.Errors, err)
+		return
+	}
 	if err != nil && !isRejectErr {
 		w.WriteHeader(http.StatusInternalServerError)
 		fmt.Fprintf(w, "Critical error while running the auction: %v", err)
-		glog.Errorf("/openrtb2/amp Critical error: %v", err)
+		logger.Ctx(r.Context()).Errorf("/openrtb2/amp Critical error: %v", err)
 		ao.Status = http.StatusInternalServerError
 		ao.Errors = append(ao.Errors, err)
 		return
@@ -298,21 +357,22 @@ func (deps *endpointDeps) AmpAuction(w http.ResponseWriter, r *http.Request, _ h
 	if err := reqWrapper.RebuildRequest(); err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
 		fmt.Fprintf(w, "Critical error while running the auction: %v", err)
-		glog.Errorf("/openrtb2/amp Critical error: %v", err)
+		logger.Ctx(r.Context()).Errorf("/openrtb2/amp Critical error: %v", err)
 		ao.Status = http.StatusInternalServerError
 		ao.Errors = append(ao.Errors, err)
 		return
 	}
 
 	if isRejectErr {
-		labels, ao = rejectAmpRequest(*rejectErr, w, hookExecutor, reqWrapper, account, labels, ao, errL)
+		labels, ao = rejectAmpRequest(r.Context(), *rejectErr, w, hookExecutor, reqWrapper, account, labels, ao, errL)
 		return
 	}
 
-	labels, ao = sendAmpResponse(w, hookExecutor, auctionResponse, reqWrapper, account, labels, ao, errL)
+	labels, ao = sendAmpResponse(r.Context(), w, hookExecutor, auctionResponse, reqWrapper, account, labels, ao, errL)
 }
 
 func rejectAmpRequest(
+	ctx context.Context,
 	rejectErr hookexecution.RejectError,
 	w http.ResponseWriter,
 	hookExecutor hookexecution.HookStageExecutor,
@@ -326,10 +386,11 @@ func rejectAmpRequest(
 	ao.AuctionResponse = response
 	ao.Errors = append(ao.Errors, rejectErr)
 
-	return sendAmpResponse(w, hookExecutor, &exchange.AuctionResponse{BidResponse: response}, reqWrapper, account, labels, ao, errs)
+	return sendAmpResponse(ctx, w, hookExecutor, &exchange.AuctionResponse{BidResponse: response}, reqWrapper, account, labels, ao, errs)
 }
 
 func sendAmpResponse(
+	ctx context.Context,
 	w http.ResponseWriter,
 	hookExecutor hookexecution.HookStageExecutor,
 	auctionResponse *exchange.AuctionResponse,
@@ -362,7 +423,7 @@ func sendAmpResponse(
 					if err != nil {
 						w.WriteHeader(http.StatusInternalServerError)
 						fmt.Fprintf(w, "Critical error while unpacking AMP targets: %v", err)
-						glog.Errorf("/openrtb2/amp Critical error unpacking targets: %v", err)
+						logger.Ctx(ctx).Errorf("/openrtb2/amp Critical error unpacking targets: %v", err)
 						ao.Errors = append(ao.Errors, fmt.Errorf("Critical error while unpacking AMP targets: %v", err))
 						ao.Status = http.StatusInternalServerError
 						return labels, ao
@@ -393,7 +454,7 @@ func sendAmpResponse(
 	}
 	// Now JSONify the targets for the AMP response.
 	ampResponse := AmpResponse{Targeting: targets}
-	ao, ampResponse.ORTB2.Ext = getExtBidResponse(hookExecutor, auctionResponse, reqWrapper, account, ao, errs)
+	ao, ampResponse.ORTB2.Ext = getExtBidResponse(ctx, hookExecutor, auctionResponse, reqWrapper, account, ao, errs)
 
 	ao.AmpTargetingValues = targets
 
@@ -418,6 +479,7 @@ func sendAmpResponse(
 }
 
 func getExtBidResponse(
+	ctx context.Context,
 	hookExecutor hookexecution.HookStageExecutor,
 	auctionResponse *exchange.AuctionResponse,
 	reqWrapper *openrtb_ext.RequestWrapper,
@@ -441,10 +503,7 @@ func getExtBidResponse(
 		warnings = make(map[openrtb_ext.BidderName][]openrtb_ext.ExtBidderMessage)
 	}
 	for _, v := range errortypes.WarningOnly(errs) {
-		bidderErr := openrtb_ext.ExtBidderMessage{
-			Code:    errortypes.ReadCode(v),
-			Message: v.Error(),
-		}
+		bidderErr := openrtb_ext.NewExtBidderMessage(v)
 		warnings[openrtb_ext.BidderReservedGeneral] = append(warnings[openrtb_ext.BidderReservedGeneral], bidderErr)
 	}
 
@@ -459,7 +518,7 @@ func getExtBidResponse(
 			if extResponse.Debug != nil {
 				extBidResponse.Debug = extResponse.Debug
 			} else {
-				glog.Errorf("Test set on request but debug not present in response.")
+				logger.Ctx(ctx).Errorf("Test set on request but debug not present in response.")
 				ao.Errors = append(ao.Errors, fmt.Errorf("test set on request but debug not present in response"))
 			}
 		}
@@ -469,7 +528,7 @@ func getExtBidResponse(
 		modules, warns, err := hookexecution.GetModulesJSON(stageOutcomes, reqWrapper.BidRequest, account)
 		if err != nil {
 			err := fmt.Errorf("Failed to get modules outcome: %s", err)
-			glog.Errorf(err.Error())
+			logger.Ctx(ctx).Errorf(err.Error())
 			ao.Errors = append(ao.Errors, err)
 		} else if modules != nil {
 			extBidResponse.Prebid = &openrtb_ext.ExtResponsePrebid{Modules: modules}
@@ -540,9 +599,12 @@ func (deps *endpointDeps) loadRequestJSONForAmp(httpRequest *http.Request) (req
 		return
 	}
 
+	// Serve the account the version of the stored request it's pinned to, if a stored request edit broke it
+	storedRequests, _ = deps.storedRequestVersions.Pinned(config.AMPRequestDataType, ampAccountID(storedRequests[ampParams.StoredRequestID], ampParams.Account), storedRequests, nil)
+
 	// The fetched config becomes the entire OpenRTB request
 	requestJSON := storedRequests[ampParams.StoredRequestID]
-	if err := jsonutil.UnmarshalValid(requestJSON, req); err != nil {
+	if req, err = deps.parsedCache.unmarshalRequest(requestJSON); err != nil {
 		errs = []error{err}
 		return
 	}
@@ -813,6 +875,22 @@ func setAmpExtDirect(site *openrtb2.Site, value string) {
 }
 
 // Sets the effective publisher ID for amp request
+// ampAccountID returns the ID of the account of an AMP request whose stored request is requestJSON, as it's
+// resolved once the request is built: the publisher of the stored request, or else the account parameter.
+func ampAccountID(requestJSON []byte, account string) string {
+	if id, err := jsonparser.GetString(requestJSON, "site", "publisher", "id"); err == nil && id != "" {
+		return id
+	}
+	if id, err := jsonparser.GetString(requestJSON, "app", "publisher", "id"); err == nil && id != "" {
+		return id
+	}
+	// ACCOUNT_ID is the unresolved macro name and should be ignored.
+	if account == "ACCOUNT_ID" {
+		return ""
+	}
+	return account
+}
+
 func setEffectiveAmpPubID(req *openrtb2.BidRequest, account string) {
 	// ACCOUNT_ID is the unresolved macro name and should be ignored.
 	if account == "" || account == "ACCOUNT_ID" {
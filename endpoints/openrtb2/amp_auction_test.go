@@ -27,6 +27,7 @@ import (
 	"github.com/prebid/prebid-server/v2/hooks"
 	"github.com/prebid/prebid-server/v2/hooks/hookexecution"
 	"github.com/prebid/prebid-server/v2/hooks/hookstage"
+	"github.com/prebid/prebid-server/v2/loadshedding"
 	"github.com/prebid/prebid-server/v2/metrics"
 	metricsConfig "github.com/prebid/prebid-server/v2/metrics/config"
 	"github.com/prebid/prebid-server/v2/openrtb_ext"
@@ -213,6 +214,9 @@ func TestAMPPageInfo(t *testing.T) {
 		empty_fetcher.EmptyFetcher{},
 		hooks.EmptyPlanBuilder{},
 		nil,
+		nil,
+		nil,
+		nil,
 	)
 	request := httptest.NewRequest("GET", fmt.Sprintf("/openrtb2/auction/amp?tag_id=1&curl=%s", url.QueryEscape(page)), nil)
 	recorder := httptest.NewRecorder()
@@ -317,6 +321,9 @@ func TestGDPRConsent(t *testing.T) {
 			empty_fetcher.EmptyFetcher{},
 			hooks.EmptyPlanBuilder{},
 			nil,
+			nil,
+			nil,
+			nil,
 		)
 
 		// Invoke Endpoint
@@ -741,6 +748,9 @@ func TestCCPAConsent(t *testing.T) {
 			empty_fetcher.EmptyFetcher{},
 			hooks.EmptyPlanBuilder{},
 			nil,
+			nil,
+			nil,
+			nil,
 		)
 
 		// Invoke Endpoint
@@ -789,12 +799,14 @@ func TestConsentWarnings(t *testing.T) {
 		Message: "debug turned off for bidder",
 	}
 	invalidCCPAWarning := openrtb_ext.ExtBidderMessage{
-		Code:    10001,
-		Message: "Consent string '" + invalidConsent + "' is not a valid CCPA consent string.",
+		Code:     10001,
+		Category: errortypes.CategoryPrivacy,
+		Message:  "Consent string '" + invalidConsent + "' is not a valid CCPA consent string.",
 	}
 	invalidConsentWarning := openrtb_ext.ExtBidderMessage{
-		Code:    10001,
-		Message: "CCPA consent is invalid and will be ignored. (request.regs.ext.us_privacy must contain 4 characters)",
+		Code:     10001,
+		Category: errortypes.CategoryPrivacy,
+		Message:  "CCPA consent is invalid and will be ignored. (request.regs.ext.us_privacy must contain 4 characters)",
 	}
 
 	testData := []inputTest{
@@ -855,6 +867,9 @@ func TestConsentWarnings(t *testing.T) {
 			empty_fetcher.EmptyFetcher{},
 			hooks.EmptyPlanBuilder{},
 			nil,
+			nil,
+			nil,
+			nil,
 		)
 
 		// Invoke Endpoint
@@ -954,6 +969,9 @@ func TestNewAndLegacyConsentBothProvided(t *testing.T) {
 			empty_fetcher.EmptyFetcher{},
 			hooks.EmptyPlanBuilder{},
 			nil,
+			nil,
+			nil,
+			nil,
 		)
 
 		// Invoke Endpoint
@@ -1009,6 +1027,9 @@ func TestAMPSiteExt(t *testing.T) {
 		empty_fetcher.EmptyFetcher{},
 		hooks.EmptyPlanBuilder{},
 		nil,
+		nil,
+		nil,
+		nil,
 	)
 	request, err := http.NewRequest("GET", "/openrtb2/auction/amp?tag_id=1", nil)
 	if !assert.NoError(t, err) {
@@ -1052,6 +1073,9 @@ func TestAmpBadRequests(t *testing.T) {
 		empty_fetcher.EmptyFetcher{},
 		hooks.EmptyPlanBuilder{},
 		nil,
+		nil,
+		nil,
+		nil,
 	)
 	for requestID := range badRequests {
 		request := httptest.NewRequest("GET", fmt.Sprintf("/openrtb2/auction/amp?tag_id=%s", requestID), nil)
@@ -1086,6 +1110,9 @@ func TestAmpDebug(t *testing.T) {
 		empty_fetcher.EmptyFetcher{},
 		hooks.EmptyPlanBuilder{},
 		nil,
+		nil,
+		nil,
+		nil,
 	)
 
 	for requestID := range requests {
@@ -1222,6 +1249,9 @@ func TestQueryParamOverrides(t *testing.T) {
 		empty_fetcher.EmptyFetcher{},
 		hooks.EmptyPlanBuilder{},
 		nil,
+		nil,
+		nil,
+		nil,
 	)
 
 	requestID := "1"
@@ -1380,6 +1410,9 @@ func (s formatOverrideSpec) execute(t *testing.T) {
 		empty_fetcher.EmptyFetcher{},
 		hooks.EmptyPlanBuilder{},
 		nil,
+		nil,
+		nil,
+		nil,
 	)
 
 	url := fmt.Sprintf("/openrtb2/auction/amp?tag_id=1&debug=1&w=%d&h=%d&ow=%d&oh=%d&ms=%s&account=%s", s.width, s.height, s.overrideWidth, s.overrideHeight, s.multisize, s.account)
@@ -1413,8 +1446,9 @@ func (s formatOverrideSpec) execute(t *testing.T) {
 }
 
 type mockAmpExchange struct {
-	lastRequest *openrtb2.BidRequest
-	requestExt  json.RawMessage
+	lastRequest        *openrtb2.BidRequest
+	lastAuctionRequest *exchange.AuctionRequest
+	requestExt         json.RawMessage
 }
 
 var expectedErrorsFromHoldAuction map[openrtb_ext.BidderName][]openrtb_ext.ExtBidderMessage = map[openrtb_ext.BidderName][]openrtb_ext.ExtBidderMessage{
@@ -1429,6 +1463,7 @@ var expectedErrorsFromHoldAuction map[openrtb_ext.BidderName][]openrtb_ext.ExtBi
 func (m *mockAmpExchange) HoldAuction(ctx context.Context, auctionRequest *exchange.AuctionRequest, debugLog *exchange.DebugLog) (*exchange.AuctionResponse, error) {
 	r := auctionRequest.BidRequestWrapper
 	m.lastRequest = r.BidRequest
+	m.lastAuctionRequest = auctionRequest
 
 	response := &openrtb2.BidResponse{
 		SeatBid: []openrtb2.SeatBid{{
@@ -1921,6 +1956,9 @@ func ampObjectTestSetup(t *testing.T, inTagId string, inStoredRequest json.RawMe
 		empty_fetcher.EmptyFetcher{},
 		hooks.EmptyPlanBuilder{},
 		nil,
+		nil,
+		nil,
+		nil,
 	)
 	return &actualAmpObject, endpoint
 }
@@ -1974,6 +2012,9 @@ func TestAmpAuctionResponseHeaders(t *testing.T) {
 		empty_fetcher.EmptyFetcher{},
 		hooks.EmptyPlanBuilder{},
 		nil,
+		nil,
+		nil,
+		nil,
 	)
 
 	for _, test := range testCases {
@@ -2010,6 +2051,9 @@ func TestRequestWithTargeting(t *testing.T) {
 		empty_fetcher.EmptyFetcher{},
 		hooks.EmptyPlanBuilder{},
 		nil,
+		nil,
+		nil,
+		nil,
 	)
 	url, err := url.Parse("/openrtb2/auction/amp")
 	assert.NoError(t, err, "unexpected error received while parsing url")
@@ -2324,7 +2368,7 @@ func TestSendAmpResponse_LogsErrors(t *testing.T) {
 			account := &config.Account{DebugAllow: true}
 			reqWrapper := openrtb_ext.RequestWrapper{BidRequest: test.request}
 
-			_, ao = sendAmpResponse(test.writer, test.hookExecutor, &exchange.AuctionResponse{BidResponse: test.response}, &reqWrapper, account, labels, ao, nil)
+			_, ao = sendAmpResponse(context.Background(), test.writer, test.hookExecutor, &exchange.AuctionResponse{BidResponse: test.response}, &reqWrapper, account, labels, ao, nil)
 
 			assert.Equal(t, test.expectedErrors, ao.Errors, "Invalid errors.")
 			assert.Equal(t, test.expectedStatus, ao.Status, "Invalid HTTP response status.")
@@ -2404,3 +2448,61 @@ func TestSetSeatNonBid(t *testing.T) {
 		})
 	}
 }
+
+func TestAmpDegradedDisablesDebug(t *testing.T) {
+	stored := map[string]json.RawMessage{
+		"1": json.RawMessage(validRequest(t, "site.json")),
+	}
+
+	testCases := []struct {
+		name               string
+		degraded           bool
+		expectedDebugAllow bool
+	}{
+		{
+			name:               "normal",
+			degraded:           false,
+			expectedDebugAllow: true,
+		},
+		{
+			name:               "degraded",
+			degraded:           true,
+			expectedDebugAllow: false,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			exchange := &mockAmpExchange{}
+			endpoint, _ := NewAmpEndpoint(
+				fakeUUIDGenerator{},
+				exchange,
+				newParamsValidator(t),
+				&mockAmpStoredReqFetcher{stored},
+				empty_fetcher.EmptyFetcher{},
+				&config.Configuration{MaxRequestSize: maxSize, AccountDefaults: config.Account{DebugAllow: true}},
+				&metricsConfig.NilMetricsEngine{},
+				analyticsBuild.New(&config.Analytics{}),
+				map[string]string{},
+				[]byte{},
+				openrtb_ext.BuildBidderMap(),
+				empty_fetcher.EmptyFetcher{},
+				hooks.EmptyPlanBuilder{},
+				nil,
+				nil,
+				nil,
+				nil,
+			)
+
+			request := httptest.NewRequest("GET", "/openrtb2/auction/amp?tag_id=1&debug=1", nil)
+			if test.degraded {
+				request = request.WithContext(loadshedding.WithDegraded(request.Context()))
+			}
+			recorder := httptest.NewRecorder()
+			endpoint(recorder, request, nil)
+
+			require.NotNil(t, exchange.lastAuctionRequest, "Endpoint responded with %d: %s", recorder.Code, recorder.Body.String())
+			assert.Equal(t, test.expectedDebugAllow, exchange.lastAuctionRequest.Account.DebugAllow)
+		})
+	}
+}
@@ -0,0 +1,23 @@
+package openrtb2
+
+import (
+	"net/http"
+
+	"github.com/prebid/prebid-server/v2/apikey"
+	"github.com/prebid/prebid-server/v2/config"
+)
+
+// authenticateAPIKey checks the API key of a server to server request against the keys of its account, if the
+// account has any or requires one. Requests with an unknown or revoked key, or without a key when one is
+// required, are rejected, as are those whose key is over its rate limit.
+func (deps *endpointDeps) authenticateAPIKey(httpRequest *http.Request, account *config.Account) []error {
+	if account == nil || !account.APIKeys.Enabled() {
+		return nil
+	}
+	status, err := deps.apiKeyAuthenticator.Authenticate(httpRequest.Context(), account.ID, account.APIKeys, httpRequest.Header.Get(apikey.Header))
+	deps.metricsEngine.RecordAPIKey(status)
+	if err != nil {
+		return []error{err}
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package openrtb2
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prebid/prebid-server/v2/apikey"
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/errortypes"
+	"github.com/prebid/prebid-server/v2/metrics"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuthenticateAPIKey(t *testing.T) {
+	// SHA-256 of "secret"
+	keys := config.AccountAPIKeys{Keys: []config.AccountAPIKey{{ID: "partner", SHA256: "2bb80d537b1da3e38bd30361aa855686bde0eacd7162fef6a25fe97bf527a25b"}}}
+
+	testCases := []struct {
+		description    string
+		account        *config.Account
+		key            string
+		expectedErrs   []error
+		expectedStatus metrics.APIKeyStatus
+	}{
+		{
+			description: "no account",
+		},
+		{
+			description: "account without keys",
+			account:     &config.Account{ID: "1001"},
+			key:         "anything",
+		},
+		{
+			description:    "valid key",
+			account:        &config.Account{ID: "1001", APIKeys: keys},
+			key:            "secret",
+			expectedStatus: metrics.APIKeyAccepted,
+		},
+		{
+			description:    "invalid key",
+			account:        &config.Account{ID: "1001", APIKeys: keys},
+			key:            "guess",
+			expectedErrs:   []error{&errortypes.Unauthorized{Message: "the API key is not valid for account 1001"}},
+			expectedStatus: metrics.APIKeyInvalid,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			metricsEngine := &metrics.MetricsEngineMock{}
+			if test.expectedStatus != "" {
+				metricsEngine.On("RecordAPIKey", test.expectedStatus).Once()
+			}
+			deps := &endpointDeps{cfg: &config.Configuration{}, metricsEngine: metricsEngine, apiKeyAuthenticator: apikey.NewAuthenticator(nil)}
+
+			httpReq := httptest.NewRequest("POST", "/openrtb2/auction", nil)
+			if test.key != "" {
+				httpReq.Header.Set(apikey.Header, test.key)
+			}
+
+			assert.Equal(t, test.expectedErrs, deps.authenticateAPIKey(httpReq, test.account))
+			metricsEngine.AssertExpectations(t)
+		})
+	}
+}
+
+func TestWriteErrorAPIKey(t *testing.T) {
+	testCases := []struct {
+		description  string
+		err          error
+		expectedCode int
+	}{
+		{
+			description:  "unauthorized",
+			err:          &errortypes.Unauthorized{Message: "the API key is not valid for account 1001"},
+			expectedCode: 401,
+		},
+		{
+			description:  "rate limited",
+			err:          &errortypes.RateLimited{Message: "API key partner of account 1001 is over its rate limit of 2 requests per second"},
+			expectedCode: 429,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			labels := metrics.Labels{}
+			assert.True(t, writeError([]error{test.err}, recorder, &labels))
+			assert.Equal(t, test.expectedCode, recorder.Code)
+			assert.Equal(t, metrics.RequestStatusBadInput, labels.RequestStatus)
+		})
+	}
+}
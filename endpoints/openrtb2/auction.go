@@ -7,6 +7,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"math/rand"
 	"net/http"
 	"net/url"
 	"regexp"
@@ -15,8 +16,6 @@ import (
 	"time"
 
 	"github.com/buger/jsonparser"
-	"github.com/gofrs/uuid"
-	"github.com/golang/glog"
 	"github.com/julienschmidt/httprouter"
 	gpplib "github.com/prebid/go-gpp"
 	"github.com/prebid/go-gpp/constants"
@@ -27,28 +26,42 @@ import (
 	"github.com/prebid/openrtb/v20/openrtb3"
 	"github.com/prebid/prebid-server/v2/bidadjustment"
 	"github.com/prebid/prebid-server/v2/hooks"
+	"github.com/prebid/prebid-server/v2/ivt"
+	"github.com/prebid/prebid-server/v2/loadshedding"
+	"github.com/prebid/prebid-server/v2/logger"
 	"github.com/prebid/prebid-server/v2/ortb"
 	"github.com/prebid/prebid-server/v2/privacy"
+	"github.com/prebid/prebid-server/v2/requestnormalization"
+	"github.com/prebid/prebid-server/v2/responseoverride"
+	"github.com/prebid/prebid-server/v2/responsesigning"
 	"golang.org/x/net/publicsuffix"
-	jsonpatch "gopkg.in/evanphx/json-patch.v4"
 
-	accountService "github.com/prebid/prebid-server/v2/account"
 	"github.com/prebid/prebid-server/v2/analytics"
+	"github.com/prebid/prebid-server/v2/apikey"
+	"github.com/prebid/prebid-server/v2/auctionrecording"
 	"github.com/prebid/prebid-server/v2/config"
 	"github.com/prebid/prebid-server/v2/currency"
+	"github.com/prebid/prebid-server/v2/defaultrequest"
+	"github.com/prebid/prebid-server/v2/dynamictmax"
 	"github.com/prebid/prebid-server/v2/errortypes"
 	"github.com/prebid/prebid-server/v2/exchange"
+	"github.com/prebid/prebid-server/v2/experiment/adscert"
+	"github.com/prebid/prebid-server/v2/firstpartydata"
 	"github.com/prebid/prebid-server/v2/gdpr"
+	"github.com/prebid/prebid-server/v2/geolocation"
 	"github.com/prebid/prebid-server/v2/hooks/hookexecution"
+	"github.com/prebid/prebid-server/v2/latencybudget"
 	"github.com/prebid/prebid-server/v2/metrics"
 	"github.com/prebid/prebid-server/v2/openrtb_ext"
 	"github.com/prebid/prebid-server/v2/prebid_cache_client"
 	"github.com/prebid/prebid-server/v2/privacy/ccpa"
 	"github.com/prebid/prebid-server/v2/privacy/lmt"
 	"github.com/prebid/prebid-server/v2/schain"
+	"github.com/prebid/prebid-server/v2/sizeresolution"
 	"github.com/prebid/prebid-server/v2/stored_requests"
 	"github.com/prebid/prebid-server/v2/stored_requests/backends/empty_fetcher"
 	"github.com/prebid/prebid-server/v2/stored_responses"
+	"github.com/prebid/prebid-server/v2/storedversions"
 	"github.com/prebid/prebid-server/v2/usersync"
 	"github.com/prebid/prebid-server/v2/util/httputil"
 	"github.com/prebid/prebid-server/v2/util/iputil"
@@ -97,11 +110,48 @@ func NewEndpoint(
 	storedRespFetcher stored_requests.Fetcher,
 	hookExecutionPlanBuilder hooks.ExecutionPlanBuilder,
 	tmaxAdjustments *exchange.TmaxAdjustmentsPreprocessed,
+	adsCertVerifier adscert.Verifier,
+	apiKeyAuthenticator *apikey.Authenticator,
+	responseSigner *responsesigning.Signer,
+	geoEnricher *geolocation.Enricher,
+	responseOverrides *responseoverride.Overrides,
+	ivtFilter *ivt.Filter,
+	storedRequestVersions *storedversions.Versions,
 ) (httprouter.Handle, error) {
 	if ex == nil || validator == nil || requestsById == nil || accounts == nil || cfg == nil || metricsEngine == nil {
 		return nil, errors.New("NewEndpoint requires non-nil arguments.")
 	}
 
+	deps, err := newEndpointDeps(uuidGenerator, ex, validator, requestsById, accounts, cfg, metricsEngine, analyticsRunner, disabledBidders, defReqJSON, bidderMap, storedRespFetcher, hookExecutionPlanBuilder, tmaxAdjustments, adsCertVerifier, apiKeyAuthenticator, responseSigner, geoEnricher, responseOverrides, ivtFilter, storedRequestVersions)
+	if err != nil {
+		return nil, err
+	}
+	return httprouter.Handle(deps.Auction), nil
+}
+
+func newEndpointDeps(
+	uuidGenerator uuidutil.UUIDGenerator,
+	ex exchange.Exchange,
+	validator openrtb_ext.BidderParamValidator,
+	requestsById stored_requests.Fetcher,
+	accounts stored_requests.AccountFetcher,
+	cfg *config.Configuration,
+	metricsEngine metrics.MetricsEngine,
+	analyticsRunner analytics.Runner,
+	disabledBidders map[string]string,
+	defReqJSON []byte,
+	bidderMap map[string]openrtb_ext.BidderName,
+	storedRespFetcher stored_requests.Fetcher,
+	hookExecutionPlanBuilder hooks.ExecutionPlanBuilder,
+	tmaxAdjustments *exchange.TmaxAdjustmentsPreprocessed,
+	adsCertVerifier adscert.Verifier,
+	apiKeyAuthenticator *apikey.Authenticator,
+	responseSigner *responsesigning.Signer,
+	geoEnricher *geolocation.Enricher,
+	responseOverrides *responseoverride.Overrides,
+	ivtFilter *ivt.Filter,
+	storedRequestVersions *storedversions.Versions,
+) (*endpointDeps, error) {
 	defRequest := len(defReqJSON) > 0
 
 	ipValidator := iputil.PublicNetworkIPValidator{
@@ -109,7 +159,12 @@ func NewEndpoint(
 		IPv6PrivateNetworks: cfg.RequestValidation.IPv6PrivateNetworksParsed,
 	}
 
-	return httprouter.Handle((&endpointDeps{
+	defaultRequests, err := defaultrequest.New(defReqJSON, cfg.DefReqConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	return &endpointDeps{
 		uuidGenerator,
 		ex,
 		validator,
@@ -129,7 +184,17 @@ func NewEndpoint(
 		storedRespFetcher,
 		hookExecutionPlanBuilder,
 		tmaxAdjustments,
-		openrtb_ext.NormalizeBidderName}).Auction), nil
+		openrtb_ext.NormalizeBidderName,
+		newParsedRequestCache(cfg.ParsedStoredRequestCache),
+		adsCertVerifier,
+		ivtFilter,
+		apiKeyAuthenticator,
+		responseSigner,
+		geoEnricher,
+		defaultRequests,
+		responseOverrides,
+		requestnormalization.NewNormalizer(cfg.RequestNormalization, validator, metricsEngine),
+		storedRequestVersions}, nil
 }
 
 type normalizeBidderName func(name string) (openrtb_ext.BidderName, bool)
@@ -155,6 +220,16 @@ type endpointDeps struct {
 	hookExecutionPlanBuilder  hooks.ExecutionPlanBuilder
 	tmaxAdjustments           *exchange.TmaxAdjustmentsPreprocessed
 	normalizeBidderName       normalizeBidderName
+	parsedCache               *parsedRequestCache
+	adsCertVerifier           adscert.Verifier
+	ivtFilter                 *ivt.Filter
+	apiKeyAuthenticator       *apikey.Authenticator
+	responseSigner            *responsesigning.Signer
+	geoEnricher               *geolocation.Enricher
+	defaultRequests           *defaultrequest.Defaults
+	responseOverrides         *responseoverride.Overrides
+	normalizer                *requestnormalization.Normalizer
+	storedRequestVersions     *storedversions.Versions
 }
 
 func (deps *endpointDeps) Auction(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
@@ -166,7 +241,13 @@ func (deps *endpointDeps) Auction(w http.ResponseWriter, r *http.Request, _ http
 	// to compute the auction timeout.
 	start := time.Now()
 
+	budget := latencybudget.New(deps.cfg.LatencyBudget, start)
+	if budget != nil {
+		r = r.WithContext(latencybudget.WithBudget(r.Context(), budget))
+	}
+
 	hookExecutor := hookexecution.NewHookExecutor(deps.hookExecutionPlanBuilder, hookexecution.EndpointAuction, deps.metricsEngine)
+	loadshedding.SkipOptionalModules(r.Context(), hookExecutor, deps.cfg.LoadShedding.Memory.OptionalModules)
 
 	ao := analytics.AuctionObject{
 		Status:    http.StatusOK,
@@ -184,21 +265,22 @@ func (deps *endpointDeps) Auction(w http.ResponseWriter, r *http.Request, _ http
 
 	activityControl := privacy.ActivityControl{}
 	defer func() {
-		deps.metricsEngine.RecordRequest(labels)
-		deps.metricsEngine.RecordRequestTime(labels, time.Since(start))
+		ao.Test = isTestTraffic(deps.cfg.TestTraffic, ao.RequestWrapper)
+		recordRequestMetrics(deps.metricsEngine, labels, ao.Test, start)
+		budget.RecordMetrics(deps.metricsEngine)
 		deps.analytics.LogAuctionObject(&ao, activityControl)
 	}()
 
 	w.Header().Set("X-Prebid", version.BuildXPrebidHeader(version.Ver))
 
-	req, impExtInfoMap, storedAuctionResponses, storedBidResponses, bidderImpReplaceImp, account, errL := deps.parseRequest(r, &labels, hookExecutor)
+	req, impExtInfoMap, storedAuctionResponses, storedBidResponses, bidderImpReplaceImp, fpdResolution, sizeResolution, account, errL := deps.parseRequest(r, &labels, hookExecutor)
 	if errortypes.ContainsFatalError(errL) && writeError(errL, w, &labels) {
 		return
 	}
 
 	if rejectErr := hookexecution.FindFirstRejectOrNil(errL); rejectErr != nil {
 		ao.RequestWrapper = req
-		labels, ao = rejectAuctionRequest(*rejectErr, w, hookExecutor, req.BidRequest, account, labels, ao)
+		labels, ao = rejectAuctionRequest(r.Context(), *rejectErr, w, hookExecutor, req.BidRequest, account, labels, ao, deps.responseSigner)
 		return
 	}
 
@@ -209,14 +291,17 @@ func (deps *endpointDeps) Auction(w http.ResponseWriter, r *http.Request, _ http
 	hookExecutor.SetActivityControl(activityControl)
 	hookExecutor.SetAccount(account)
 
-	ctx := context.Background()
+	ctx := latencybudget.WithBudget(context.Background(), budget)
+	ctx = auctionrecording.WithSession(ctx, auctionrecording.FromContext(r.Context()))
 
+	tmaxAdjustment := dynamictmax.Adjust(req, requestChannel(req.App != nil, req.DOOH != nil), account.DynamicTmax, deps.cfg.AuctionTimeouts)
 	timeout := deps.cfg.AuctionTimeouts.LimitAuctionTimeout(time.Duration(req.TMax) * time.Millisecond)
 	if timeout > 0 {
 		var cancel context.CancelFunc
 		ctx, cancel = context.WithDeadline(ctx, start.Add(timeout))
 		defer cancel()
 	}
+	budget.SetTmax(timeout)
 
 	// Read Usersyncs/Cookie
 	decoder := usersync.Base64Decoder{}
@@ -240,28 +325,56 @@ func (deps *endpointDeps) Auction(w http.ResponseWriter, r *http.Request, _ http
 	}
 	secGPC := r.Header.Get("Sec-GPC")
 
+	debugLog, err := applyDebugToken(r, req, account, start)
+	if err != nil {
+		errL = append(errL, err)
+	}
+	analyticsDebug, err := applyDebugAccess(r, req, account, debugLog, deps.privateNetworkIPValidator, rand.Float64)
+	if err != nil {
+		errL = append(errL, err)
+	}
+	pinnedResponses, pinWarnings := deps.responseOverrides.Pinned(ctx, account.ID, debugLog != nil)
+	errL = append(errL, pinWarnings...)
+	accountStoredBidResponses, accountBidderImpReplaceImpID, accountStoredErrs := stored_responses.ProcessAccountStoredBidResponses(ctx, req, account.StoredBidResponses, debugLog != nil, deps.storedRespFetcher)
+	errL = append(errL, accountStoredErrs...)
+
 	warnings := errortypes.WarningOnly(errL)
+	if len(warnings) > 0 {
+		logger.Ctx(r.Context()).WithClass(logger.ClassValidationWarnings).Debugf("/openrtb2/auction request warnings: %v", warnings)
+	}
 
 	auctionRequest := &exchange.AuctionRequest{
-		BidRequestWrapper:          req,
-		Account:                    *account,
-		UserSyncs:                  usersyncs,
-		RequestType:                labels.RType,
-		StartTime:                  start,
-		LegacyLabels:               labels,
-		Warnings:                   warnings,
-		GlobalPrivacyControlHeader: secGPC,
-		ImpExtInfoMap:              impExtInfoMap,
-		StoredAuctionResponses:     storedAuctionResponses,
-		StoredBidResponses:         storedBidResponses,
-		BidderImpReplaceImpID:      bidderImpReplaceImp,
-		PubID:                      labels.PubID,
-		HookExecutor:               hookExecutor,
-		TCF2Config:                 tcf2Config,
-		Activities:                 activityControl,
-		TmaxAdjustments:            deps.tmaxAdjustments,
-	}
-	auctionResponse, err := deps.ex.HoldAuction(ctx, auctionRequest, nil)
+		BidRequestWrapper:            req,
+		Account:                      *account,
+		UserSyncs:                    usersyncs,
+		RequestType:                  labels.RType,
+		StartTime:                    start,
+		LegacyLabels:                 labels,
+		Warnings:                     warnings,
+		GlobalPrivacyControlHeader:   secGPC,
+		ImpExtInfoMap:                impExtInfoMap,
+		StoredAuctionResponses:       storedAuctionResponses,
+		StoredBidResponses:           storedBidResponses,
+		BidderImpReplaceImpID:        bidderImpReplaceImp,
+		PubID:                        labels.PubID,
+		HookExecutor:                 hookExecutor,
+		TCF2Config:                   tcf2Config,
+		Activities:                   activityControl,
+		TmaxAdjustments:              deps.tmaxAdjustments,
+		SimulatedResponses:           simulatedResponsesFromContext(r.Context()),
+		AnalyticsDebug:               analyticsDebug,
+		FirstPartyDataResolution:     fpdResolution,
+		SizeResolution:               sizeResolution,
+		DynamicTmax:                  tmaxAdjustment,
+		PinnedResponses:              pinnedResponses,
+		AccountStoredBidResponses:    accountStoredBidResponses,
+		AccountBidderImpReplaceImpID: accountBidderImpReplaceImpID,
+	}
+	if loadshedding.SkipDebug(r.Context(), &auctionRequest.Account) {
+		auctionRequest.AnalyticsDebug = false
+		debugLog = nil
+	}
+	auctionResponse, err := deps.ex.HoldAuction(ctx, auctionRequest, debugLog)
 	defer func() {
 		if !auctionRequest.BidderResponseStartTime.IsZero() {
 			deps.metricsEngine.RecordOverheadTime(metrics.MakeAuctionResponse, time.Since(auctionRequest.BidderResponseStartTime))
@@ -277,27 +390,69 @@ func (deps *endpointDeps) Auction(w http.ResponseWriter, r *http.Request, _ http
 	ao.SeatNonBid = auctionResponse.GetSeatNonBid()
 	rejectErr, isRejectErr := hookexecution.CastRejectErr(err)
 	if err != nil && !isRejectErr {
-		if errortypes.ReadCode(err) == errortypes.BadInputErrorCode {
+		if code := errortypes.ReadCode(err); code == errortypes.BadInputErrorCode || code == errortypes.RateLimitedErrorCode {
 			writeError([]error{err}, w, &labels)
 			return
 		}
 		labels.RequestStatus = metrics.RequestStatusErr
 		w.WriteHeader(http.StatusInternalServerError)
 		fmt.Fprintf(w, "Critical error while running the auction: %v", err)
-		glog.Errorf("/openrtb2/auction Critical error: %v", err)
+		logger.Ctx(r.Context()).Errorf("/openrtb2/auction Critical error: %v", err)
 		ao.Status = http.StatusInternalServerError
 		ao.Errors = append(ao.Errors, err)
 		return
 	} else if isRejectErr {
-		labels, ao = rejectAuctionRequest(*rejectErr, w, hookExecutor, req.BidRequest, account, labels, ao)
+		labels, ao = rejectAuctionRequest(r.Context(), *rejectErr, w, hookExecutor, req.BidRequest, account, labels, ao, deps.responseSigner)
 		return
 	}
 
 	err = setSeatNonBidRaw(req, auctionResponse)
 	if err != nil {
-		glog.Errorf("Error setting seat non-bid: %v", err)
+		logger.Ctx(r.Context()).Errorf("Error setting seat non-bid: %v", err)
+	}
+
+	budget.Record(metrics.LatencyBudgetHooks, hooksExecutionTime(hookExecutor.GetOutcomes()))
+	if err := setLatencyBudgetDebug(response, budget); err != nil {
+		logger.Ctx(r.Context()).Errorf("Error setting latency budget debug info: %v", err)
+	}
+	if auctionRequest.AnalyticsDebug {
+		// The debug output was only for analytics, which still get the response as it is
+		if response, err = withoutDebugOutput(response); err != nil {
+			logger.Ctx(r.Context()).Errorf("Error removing debug output sampled for analytics: %v", err)
+		}
 	}
-	labels, ao = sendAuctionResponse(w, hookExecutor, response, req.BidRequest, account, labels, ao)
+	labels, ao = sendAuctionResponse(r.Context(), w, hookExecutor, response, req.BidRequest, account, labels, ao, deps.responseSigner)
+}
+
+// hooksExecutionTime returns the time spent running hooks in the stages which have run so far.
+func hooksExecutionTime(outcomes []hookexecution.StageOutcome) time.Duration {
+	var total time.Duration
+	for _, outcome := range outcomes {
+		total += outcome.ExecutionTimeMillis
+	}
+	return total
+}
+
+// setLatencyBudgetDebug adds the use of the latency budget to bidResponse.ext.debug, if the response has
+// debug output.
+func setLatencyBudgetDebug(response *openrtb2.BidResponse, budget *latencybudget.Budget) error {
+	report := budget.Report()
+	if response == nil || report == nil {
+		return nil
+	}
+	if _, _, _, err := jsonparser.Get(response.Ext, "debug"); err != nil {
+		return nil
+	}
+	reportJSON, err := jsonutil.Marshal(report)
+	if err != nil {
+		return err
+	}
+	ext, err := jsonparser.Set(response.Ext, reportJSON, "debug", "latencybudget")
+	if err != nil {
+		return err
+	}
+	response.Ext = ext
+	return nil
 }
 
 // setSeatNonBidRaw is transitional function for setting SeatNonBid inside bidResponse.Ext
@@ -328,6 +483,7 @@ func setSeatNonBidRaw(request *openrtb_ext.RequestWrapper, auctionResponse *exch
 }
 
 func rejectAuctionRequest(
+	ctx context.Context,
 	rejectErr hookexecution.RejectError,
 	w http.ResponseWriter,
 	hookExecutor hookexecution.HookStageExecutor,
@@ -335,6 +491,7 @@ func rejectAuctionRequest(
 	account *config.Account,
 	labels metrics.Labels,
 	ao analytics.AuctionObject,
+	signer *responsesigning.Signer,
 ) (metrics.Labels, analytics.AuctionObject) {
 	response := &openrtb2.BidResponse{NBR: openrtb3.NoBidReason(rejectErr.NBR).Ptr()}
 	if request != nil {
@@ -344,10 +501,11 @@ func rejectAuctionRequest(
 	ao.Response = response
 	ao.Errors = append(ao.Errors, rejectErr)
 
-	return sendAuctionResponse(w, hookExecutor, response, request, account, labels, ao)
+	return sendAuctionResponse(ctx, w, hookExecutor, response, request, account, labels, ao, signer)
 }
 
 func sendAuctionResponse(
+	ctx context.Context,
 	w http.ResponseWriter,
 	hookExecutor hookexecution.HookStageExecutor,
 	response *openrtb2.BidResponse,
@@ -355,6 +513,7 @@ func sendAuctionResponse(
 	account *config.Account,
 	labels metrics.Labels,
 	ao analytics.AuctionObject,
+	signer *responsesigning.Signer,
 ) (metrics.Labels, analytics.AuctionObject) {
 	hookExecutor.ExecuteAuctionResponseStage(response)
 
@@ -365,7 +524,7 @@ func sendAuctionResponse(
 		ext, warns, err := hookexecution.EnrichExtBidResponse(response.Ext, stageOutcomes, request, account)
 		if err != nil {
 			err = fmt.Errorf("Failed to enrich Bid Response with hook debug information: %s", err)
-			glog.Errorf(err.Error())
+			logger.Ctx(ctx).Errorf(err.Error())
 			ao.Errors = append(ao.Errors, err)
 		} else {
 			response.Ext = ext
@@ -374,18 +533,36 @@ func sendAuctionResponse(
 		if len(warns) > 0 {
 			ao.Errors = append(ao.Errors, warns...)
 		}
-	}
 
-	// Fixes #231
-	enc := json.NewEncoder(w)
-	enc.SetEscapeHTML(false)
+		if account != nil {
+			if ext, err := filterResponseExt(response.Ext, account.ResponseExt); err != nil {
+				ao.Errors = append(ao.Errors, fmt.Errorf("/openrtb2/auction Failed to filter the response ext: %v", err))
+			} else {
+				response.Ext = ext
+			}
+		}
+	}
 
 	w.Header().Set("Content-Type", "application/json")
 
+	if signer != nil && account != nil && account.ResponseSigning.Enabled {
+		body, signature, err := signBidResponse(response, signer, account.ResponseSigning.KeyID)
+		if err == nil {
+			w.Header().Set(responsesigning.Header, signature)
+			if _, err := w.Write(body); err != nil {
+				labels.RequestStatus = metrics.RequestStatusNetworkErr
+				ao.Errors = append(ao.Errors, fmt.Errorf("/openrtb2/auction Failed to send response: %v", err))
+			}
+			return labels, ao
+		}
+		// the response is still sent, and verifiers treat it as unsigned
+		ao.Errors = append(ao.Errors, fmt.Errorf("/openrtb2/auction Failed to sign response: %v", err))
+	}
+
 	// If an error happens when encoding the response, there isn't much we can do.
 	// If we've sent _any_ bytes, then Go would have sent the 200 status code first.
 	// That status code can't be un-sent... so the best we can do is log the error.
-	if err := enc.Encode(response); err != nil {
+	if err := writeBidResponse(w, response); err != nil {
 		labels.RequestStatus = metrics.RequestStatusNetworkErr
 		ao.Errors = append(ao.Errors, fmt.Errorf("/openrtb2/auction Failed to send response: %v", err))
 	}
@@ -403,7 +580,7 @@ func sendAuctionResponse(
 // possible, it will return errors with messages that suggest improvements.
 //
 // If the errors list has at least one element, then no guarantees are made about the returned request.
-func (deps *endpointDeps) parseRequest(httpRequest *http.Request, labels *metrics.Labels, hookExecutor hookexecution.HookStageExecutor) (req *openrtb_ext.RequestWrapper, impExtInfoMap map[string]exchange.ImpExtInfo, storedAuctionResponses stored_responses.ImpsWithBidResponses, storedBidResponses stored_responses.ImpBidderStoredResp, bidderImpReplaceImpId stored_responses.BidderImpReplaceImpID, account *config.Account, errs []error) {
+func (deps *endpointDeps) parseRequest(httpRequest *http.Request, labels *metrics.Labels, hookExecutor hookexecution.HookStageExecutor) (req *openrtb_ext.RequestWrapper, impExtInfoMap map[string]exchange.ImpExtInfo, storedAuctionResponses stored_responses.ImpsWithBidResponses, storedBidResponses stored_responses.ImpBidderStoredResp, bidderImpReplaceImpId stored_responses.BidderImpReplaceImpID, fpdResolution *firstpartydata.Resolution, sizeResolution *sizeresolution.Resolution, account *config.Account, errs []error) {
 	errs = nil
 	var err error
 	var r io.ReadCloser = httpRequest.Body
@@ -421,51 +598,49 @@ func (deps *endpointDeps) parseRequest(httpRequest *http.Request, labels *metric
 		}
 	}
 	defer r.Close()
-	limitedReqReader := &io.LimitedReader{
-		R: r,
-		N: deps.cfg.MaxRequestSize,
-	}
 
-	requestJson, err := io.ReadAll(limitedReqReader)
-	if err != nil {
-		errs = []error{err}
+	requestJson, errs := deps.readRequestBody(r)
+	if len(errs) > 0 {
+		// Discard the rest of the request body so that the connection can be reused.
+		io.Copy(io.Discard, httpRequest.Body)
 		return
 	}
 
-	if limitedReqReader.N <= 0 {
-		// Limited Reader returns 0 if the request was exactly at the max size or over the limit.
-		// This is because it only reads up to N bytes. To check if the request was too large,
-		//  we need to look at the next byte of its underlying reader, limitedReader.R.
-		if _, err := limitedReqReader.R.Read(make([]byte, 1)); err != io.EOF {
-			// Discard the rest of the request body so that the connection can be reused.
-			io.Copy(io.Discard, httpRequest.Body)
-			errs = []error{fmt.Errorf("request size exceeded max size of %d bytes.", deps.cfg.MaxRequestSize)}
-			return
-		}
-	}
-
 	req = &openrtb_ext.RequestWrapper{}
 	req.BidRequest = &openrtb2.BidRequest{}
 
+	// Call Signs are made over the body as it was sent, before hooks may change it.
+	signedRequestJson := requestJson
 	requestJson, rejectErr := hookExecutor.ExecuteEntrypointStage(httpRequest, requestJson)
 	if rejectErr != nil {
 		errs = []error{rejectErr}
 		if err = jsonutil.UnmarshalValid(requestJson, req.BidRequest); err != nil {
-			glog.Errorf("Failed to unmarshal BidRequest during entrypoint rejection: %s", err)
+			logger.Ctx(httpRequest.Context()).Errorf("Failed to unmarshal BidRequest during entrypoint rejection: %s", err)
 		}
 		return
 	}
 
 	timeout := parseTimeout(requestJson, time.Duration(storedRequestTimeoutMillis)*time.Millisecond)
-	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	sessionCtx := auctionrecording.WithSession(context.Background(), auctionrecording.FromContext(httpRequest.Context()))
+	ctx, cancel := context.WithTimeout(sessionCtx, timeout)
 	defer cancel()
 
+	// The tmax of the request is final only once stored requests have been merged into it, but the
+	// stored requests must be fetched within a share of it all the same.
+	budget := latencybudget.FromContext(httpRequest.Context())
+	budget.SetTmax(deps.cfg.AuctionTimeouts.LimitAuctionTimeout(parseTimeout(requestJson, 0)))
+	storedRequestCtx, endStoredRequestStage := budget.Start(ctx, metrics.LatencyBudgetStoredRequests)
+	defer endStoredRequestStage()
+
 	impInfo, errs := parseImpInfo(requestJson)
 	if len(errs) > 0 {
-		return nil, nil, nil, nil, nil, nil, errs
+		return nil, nil, nil, nil, nil, nil, nil, nil, errs
 	}
 
-	storedBidRequestId, hasStoredBidRequest, storedRequests, storedImps, errs := deps.getStoredRequests(ctx, requestJson, impInfo)
+	// The account is looked up along with the stored requests and imps, under the same deadline
+	accountPrefetch := deps.prefetchAccount(storedRequestCtx, requestJson)
+
+	storedBidRequestId, hasStoredBidRequest, storedRequests, storedImps, errs := deps.getStoredRequests(storedRequestCtx, requestJson, impInfo)
 	if len(errs) > 0 {
 		return
 	}
@@ -489,17 +664,34 @@ func (deps *endpointDeps) parseRequest(httpRequest *http.Request, labels *metric
 	}
 
 	// Look up account
-	account, errs = accountService.GetAccount(ctx, deps.cfg, deps.accounts, accountId, deps.metricsEngine)
+	account, errs = deps.getAccount(storedRequestCtx, accountPrefetch, accountId)
+	endStoredRequestStage()
 	if len(errs) > 0 {
 		return
 	}
 
+	if errs = deps.authenticateAPIKey(httpRequest, account); len(errs) > 0 {
+		return
+	}
+
+	if errs = deps.checkRequestSize(len(signedRequestJson), account); len(errs) > 0 {
+		return
+	}
+
+	if errs = deps.checkImpCount(len(impInfo), account); len(errs) > 0 {
+		return
+	}
+
+	if errs = deps.verifyCallSign(httpRequest, signedRequestJson, account); len(errs) > 0 {
+		return
+	}
+
 	hookExecutor.SetAccount(account)
 	requestJson, rejectErr = hookExecutor.ExecuteRawAuctionStage(requestJson)
 	if rejectErr != nil {
 		errs = []error{rejectErr}
 		if err = jsonutil.UnmarshalValid(requestJson, req.BidRequest); err != nil {
-			glog.Errorf("Failed to unmarshal BidRequest during raw auction stage rejection: %s", err)
+			logger.Ctx(httpRequest.Context()).Errorf("Failed to unmarshal BidRequest during raw auction stage rejection: %s", err)
 		}
 		return
 	}
@@ -508,7 +700,7 @@ func (deps *endpointDeps) parseRequest(httpRequest *http.Request, labels *metric
 	if hasPayloadUpdatesAt(hooks.StageRawAuctionRequest.String(), hookExecutor.GetOutcomes()) {
 		impInfo, errs = parseImpInfo(requestJson)
 		if len(errs) > 0 {
-			return nil, nil, nil, nil, nil, nil, errs
+			return nil, nil, nil, nil, nil, nil, nil, nil, errs
 		}
 		storedBidRequestId, hasStoredBidRequest, storedRequests, storedImps, errs = deps.getStoredRequests(ctx, requestJson, impInfo)
 		if len(errs) > 0 {
@@ -516,11 +708,22 @@ func (deps *endpointDeps) parseRequest(httpRequest *http.Request, labels *metric
 		}
 	}
 
+	// Serve the account the versions of stored requests and imps it's pinned to, if a stored request edit broke it
+	storedRequests, storedImps = deps.storedRequestVersions.Pinned(config.RequestDataType, account.ID, storedRequests, storedImps)
+
 	// Fetch the Stored Request data and merge it into the HTTP request.
-	if requestJson, impExtInfoMap, errs = deps.processStoredRequests(requestJson, impInfo, storedRequests, storedImps, storedBidRequestId, hasStoredBidRequest); len(errs) > 0 {
+	incomingRequestJson := requestJson
+	channel := requestChannel(isAppReq, isDOOHReq)
+	if requestJson, impExtInfoMap, errs = deps.processStoredRequests(requestJson, impInfo, storedRequests, storedImps, storedBidRequestId, hasStoredBidRequest, account, channel); len(errs) > 0 {
 		return
 	}
 
+	var fpdWarnings []error
+	requestJson, fpdResolution, fpdWarnings = deps.resolveFirstPartyData(account, channel, requestJson, incomingRequestJson, impInfo, storedRequests, storedImps, storedBidRequestId, hasStoredBidRequest)
+
+	var normalizationWarnings []error
+	requestJson, normalizationWarnings = deps.normalizer.Normalize(requestJson)
+
 	if err := jsonutil.UnmarshalValid(requestJson, req.BidRequest); err != nil {
 		errs = []error{err}
 		return
@@ -533,13 +736,29 @@ func (deps *endpointDeps) parseRequest(httpRequest *http.Request, labels *metric
 
 	// Populate any "missing" OpenRTB fields with info from other sources, (e.g. HTTP request headers).
 	deps.setFieldsImplicitly(httpRequest, req)
+	setClientHintsImplicitly(httpRequest, req, account)
+	deps.geoEnricher.Enrich(req.Device)
+
+	if errs = verifyOrigin(httpRequest, req, account); len(errs) > 0 {
+		return
+	}
+
+	if err := deps.screenInvalidTraffic(req, account); err != nil {
+		errs = []error{err}
+		return
+	}
 
 	if err := ortb.SetDefaults(req); err != nil {
 		errs = []error{err}
 		return
 	}
 
-	if err := processInterstitials(req); err != nil {
+	if err := processMobileSDK(req, deps.cfg.MobileSDK); err != nil {
+		errs = []error{err}
+		return
+	}
+
+	if sizeResolution, err = sizeresolution.Resolve(req, account.SizeResolution); err != nil {
 		errs = []error{err}
 		return
 	}
@@ -549,7 +768,7 @@ func (deps *endpointDeps) parseRequest(httpRequest *http.Request, labels *metric
 	//Stored auction responses should be processed after stored requests due to possible impression modification
 	storedAuctionResponses, storedBidResponses, bidderImpReplaceImpId, errs = stored_responses.ProcessStoredResponses(ctx, req, deps.storedRespFetcher)
 	if len(errs) > 0 {
-		return nil, nil, nil, nil, nil, nil, errs
+		return nil, nil, nil, nil, nil, nil, nil, nil, errs
 	}
 
 	hasStoredResponses := len(storedAuctionResponses) > 0
@@ -557,6 +776,8 @@ func (deps *endpointDeps) parseRequest(httpRequest *http.Request, labels *metric
 	if len(errL) > 0 {
 		errs = append(errs, errL...)
 	}
+	errs = append(errs, fpdWarnings...)
+	errs = append(errs, normalizationWarnings...)
 
 	return
 }
@@ -761,15 +982,22 @@ func (deps *endpointDeps) validateRequest(account *config.Account, httpReq *http
 		return []error{errors.New("request.imp must contain at least one element.")}
 	}
 
+	if errs := deps.checkRequestLimits(req, account); len(errs) > 0 {
+		return errs
+	}
+
 	if len(req.Cur) > 1 {
 		req.Cur = req.Cur[0:1]
-		errL = append(errL, &errortypes.Warning{Message: fmt.Sprintf("A prebid request can only process one currency. Taking the first currency in the list, %s, as the active currency", req.Cur[0])})
+		errL = append(errL, &errortypes.Warning{
+			Message:     fmt.Sprintf("A prebid request can only process one currency. Taking the first currency in the list, %s, as the active currency", req.Cur[0]),
+			WarningCode: errortypes.MultipleCurrenciesWarningCode,
+		})
 	}
 
 	// If automatically filling source TID is enabled then validate that
 	// source.TID exists and If it doesn't, fill it with a randomly generated UUID
 	if deps.cfg.AutoGenSourceTID {
-		if err := validateAndFillSourceTID(req, deps.cfg.GenerateRequestID, hasStoredBidRequest, isAmp); err != nil {
+		if err := validateAndFillSourceTID(req, deps.uuidGenerator, deps.cfg.GenerateRequestID, hasStoredBidRequest, isAmp); err != nil {
 			return []error{err}
 		}
 	}
@@ -817,6 +1045,10 @@ func (deps *endpointDeps) validateRequest(account *config.Account, httpReq *http
 		return []error{err}
 	}
 
+	if err := validateRequestSChain(req); err != nil {
+		return []error{err}
+	}
+
 	if err := validateOrFillChannel(req, isAmp); err != nil {
 		return []error{err}
 	}
@@ -946,27 +1178,27 @@ func mapSChains(req *openrtb_ext.RequestWrapper) error {
 	return nil
 }
 
-func validateAndFillSourceTID(req *openrtb_ext.RequestWrapper, generateRequestID bool, hasStoredBidRequest bool, isAmp bool) error {
+func validateAndFillSourceTID(req *openrtb_ext.RequestWrapper, uuidGenerator uuidutil.UUIDGenerator, generateRequestID bool, hasStoredBidRequest bool, isAmp bool) error {
 	if req.Source == nil {
 		req.Source = &openrtb2.Source{}
 	}
 
 	if req.Source.TID == "" || req.Source.TID == "{{UUID}}" || (generateRequestID && (isAmp || hasStoredBidRequest)) {
-		rawUUID, err := uuid.NewV4()
+		tid, err := uuidGenerator.Generate()
 		if err != nil {
 			return errors.New("error creating a random UUID for source.tid")
 		}
-		req.Source.TID = rawUUID.String()
+		req.Source.TID = tid
 	}
 
 	for _, impWrapper := range req.GetImp() {
 		ie, _ := impWrapper.GetImpExt()
 		if ie.GetTid() == "" || ie.GetTid() == "{{UUID}}" || (generateRequestID && (isAmp || hasStoredBidRequest)) {
-			rawUUID, err := uuid.NewV4()
+			tid, err := uuidGenerator.Generate()
 			if err != nil {
 				return errors.New("imp.ext.tid missing in the imp and error creating a random UID")
 			}
-			ie.SetTid(rawUUID.String())
+			ie.SetTid(tid)
 			impWrapper.RebuildImp()
 		}
 	}
@@ -1003,8 +1235,30 @@ func (deps *endpointDeps) validateBidAdjustmentFactors(adjustmentFactors map[str
 }
 
 func validateSChains(sChains []*openrtb_ext.ExtRequestPrebidSChain) error {
-	_, err := schain.BidderToPrebidSChains(sChains)
-	return err
+	if _, err := schain.BidderToPrebidSChains(sChains); err != nil {
+		return err
+	}
+	for i, sChain := range sChains {
+		if err := schain.Validate(&sChain.SChain, fmt.Sprintf("request.ext.prebid.schains[%d].schain", i)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateRequestSChain checks the schain of the request itself, in either the ORTB 2.5 location
+// (req.source.ext.schain) or the ORTB 2.6 location (req.source.schain).
+func validateRequestSChain(req *openrtb_ext.RequestWrapper) error {
+	if req.Source != nil {
+		if err := schain.Validate(req.Source.SChain, "request.source.schain"); err != nil {
+			return err
+		}
+	}
+	sourceExt, err := req.GetSourceExt()
+	if err != nil {
+		return fmt.Errorf("source.ext is invalid: %v", err)
+	}
+	return schain.Validate(sourceExt.GetSChain(), "request.source.ext.schain")
 }
 
 func (deps *endpointDeps) validateEidPermissions(prebid *openrtb_ext.ExtRequestPrebidData, requestAliases map[string]string) error {
@@ -2156,7 +2410,7 @@ func (deps *endpointDeps) getStoredRequests(ctx context.Context, requestJson []b
 	return storedBidRequestId, hasStoredBidRequest, storedRequests, storedImps, errs
 }
 
-func (deps *endpointDeps) processStoredRequests(requestJson []byte, impInfo []ImpExtPrebidData, storedRequests map[string]json.RawMessage, storedImps map[string]json.RawMessage, storedBidRequestId string, hasStoredBidRequest bool) ([]byte, map[string]exchange.ImpExtInfo, []error) {
+func (deps *endpointDeps) processStoredRequests(requestJson []byte, impInfo []ImpExtPrebidData, storedRequests map[string]json.RawMessage, storedImps map[string]json.RawMessage, storedBidRequestId string, hasStoredBidRequest bool, account *config.Account, channel config.ChannelType) ([]byte, map[string]exchange.ImpExtInfo, []error) {
 	bidRequestID, err := getBidRequestID(storedRequests[storedBidRequestId])
 	if err != nil {
 		return nil, nil, []error{err}
@@ -2175,18 +2429,18 @@ func (deps *endpointDeps) processStoredRequests(requestJson []byte, impInfo []Im
 			if err != nil {
 				return nil, nil, []error{err}
 			}
-			uuidPatch, err = jsonpatch.MergePatch(storedRequests[storedBidRequestId], uuidPatch)
+			uuidPatch, err = jsonutil.MergeClone(storedRequests[storedBidRequestId], uuidPatch)
 			if err != nil {
 				errL := storedRequestErrorChecker(requestJson, storedRequests, storedBidRequestId)
 				return nil, nil, errL
 			}
-			resolvedRequest, err = jsonpatch.MergePatch(requestJson, uuidPatch)
+			resolvedRequest, err = jsonutil.MergeClone(requestJson, uuidPatch)
 			if err != nil {
 				errL := storedRequestErrorChecker(requestJson, storedRequests, storedBidRequestId)
 				return nil, nil, errL
 			}
 		} else {
-			resolvedRequest, err = jsonpatch.MergePatch(storedRequests[storedBidRequestId], requestJson)
+			resolvedRequest, err = jsonutil.MergeClone(storedRequests[storedBidRequestId], requestJson)
 			if err != nil {
 				errL := storedRequestErrorChecker(requestJson, storedRequests, storedBidRequestId)
 				return nil, nil, errL
@@ -2194,9 +2448,9 @@ func (deps *endpointDeps) processStoredRequests(requestJson []byte, impInfo []Im
 		}
 	}
 
-	// Apply default aliases, if they are provided
-	if deps.defaultRequest {
-		aliasedRequest, err := jsonpatch.MergePatch(deps.defReqJSON, resolvedRequest)
+	// Apply the default requests of the host and account, if they are provided
+	if deps.defaultRequests.Enabled(account, channel) {
+		aliasedRequest, err := deps.defaultRequests.Apply(resolvedRequest, account, channel)
 		if err != nil {
 			hasErr, Err := getJsonSyntaxError(resolvedRequest)
 			if hasErr {
@@ -2219,7 +2473,7 @@ func (deps *endpointDeps) processStoredRequests(requestJson []byte, impInfo []Im
 	resolvedImps := make([]json.RawMessage, 0, len(impInfo))
 	for i, impData := range impInfo {
 		if impData.ImpExtPrebid.StoredRequest != nil && len(impData.ImpExtPrebid.StoredRequest.ID) > 0 {
-			resolvedImp, err := jsonpatch.MergePatch(storedImps[impData.ImpExtPrebid.StoredRequest.ID], impData.Imp)
+			resolvedImp, err := deps.parsedCache.mergePatch(storedImps[impData.ImpExtPrebid.StoredRequest.ID], impData.Imp)
 
 			if err != nil {
 				hasErr, errMessage := getJsonSyntaxError(impData.Imp)
@@ -2277,6 +2531,48 @@ func (deps *endpointDeps) processStoredRequests(requestJson []byte, impInfo []Im
 	return resolvedRequest, impExtInfoMap, nil
 }
 
+// resolveFirstPartyData merges the first party data of the HTTP request and its stored data by the account's
+// merge rules, into the request they've been merged into by processStoredRequests. The default requests count
+// as stored data, under any stored request.
+func (deps *endpointDeps) resolveFirstPartyData(account *config.Account, channel config.ChannelType, requestJson, incomingRequestJson []byte, impInfo []ImpExtPrebidData, storedRequests map[string]json.RawMessage, storedImps map[string]json.RawMessage, storedBidRequestId string, hasStoredBidRequest bool) ([]byte, *firstpartydata.Resolution, []error) {
+	if len(account.FirstPartyData.Rules) == 0 {
+		return requestJson, nil, nil
+	}
+
+	var storedRequest json.RawMessage
+	if hasStoredBidRequest {
+		storedRequest = storedRequests[storedBidRequestId]
+	}
+	if deps.defaultRequests.Enabled(account, channel) {
+		if len(storedRequest) == 0 {
+			storedRequest, _ = deps.defaultRequests.Request(account, channel)
+		} else if defaulted, err := deps.defaultRequests.Apply(storedRequest, account, channel); err == nil {
+			storedRequest = defaulted
+		}
+	}
+
+	imps := make([]firstpartydata.ImpSources, len(impInfo))
+	for i, impData := range impInfo {
+		imps[i].Request = impData.Imp
+		if impData.ImpExtPrebid.StoredRequest != nil && len(impData.ImpExtPrebid.StoredRequest.ID) > 0 {
+			imps[i].Stored = storedImps[impData.ImpExtPrebid.StoredRequest.ID]
+		}
+	}
+
+	return firstpartydata.ResolveStored(account.FirstPartyData, requestJson, incomingRequestJson, storedRequest, imps)
+}
+
+// requestChannel returns the integration type of an auction request, for its default requests.
+func requestChannel(isAppReq, isDOOHReq bool) config.ChannelType {
+	if isAppReq {
+		return config.ChannelApp
+	}
+	if isDOOHReq {
+		return config.ChannelDOOH
+	}
+	return config.ChannelWeb
+}
+
 // parseImpInfo parses the request JSON and returns impression and unmarshalled imp.ext.prebid
 func parseImpInfo(requestJson []byte) (impData []ImpExtPrebidData, errs []error) {
 	if impArray, dataType, _, err := jsonparser.Get(requestJson, "imp"); err == nil && dataType == jsonparser.Array {
@@ -2388,7 +2684,7 @@ func writeError(errs []error, w http.ResponseWriter, labels *metrics.Labels) boo
 		metricsStatus := metrics.RequestStatusBadInput
 		for _, err := range errs {
 			erVal := errortypes.ReadCode(err)
-			if erVal == errortypes.BlacklistedAppErrorCode || erVal == errortypes.AccountDisabledErrorCode {
+			if erVal == errortypes.BlacklistedAppErrorCode || erVal == errortypes.AccountDisabledErrorCode || erVal == errortypes.InvalidTrafficErrorCode {
 				httpStatus = http.StatusServiceUnavailable
 				metricsStatus = metrics.RequestStatusBlacklisted
 				break
@@ -2396,6 +2692,15 @@ func writeError(errs []error, w http.ResponseWriter, labels *metrics.Labels) boo
 				httpStatus = http.StatusInternalServerError
 				metricsStatus = metrics.RequestStatusAccountConfigErr
 				break
+			} else if erVal == errortypes.UnauthorizedErrorCode {
+				httpStatus = http.StatusUnauthorized
+				break
+			} else if erVal == errortypes.RateLimitedErrorCode {
+				httpStatus = http.StatusTooManyRequests
+				break
+			} else if erVal == errortypes.RequestTooLargeErrorCode {
+				httpStatus = http.StatusRequestEntityTooLarge
+				break
 			}
 		}
 		w.WriteHeader(httpStatus)
@@ -2408,6 +2713,94 @@ func writeError(errs []error, w http.ResponseWriter, labels *metrics.Labels) boo
 	return rc
 }
 
+// verifyCallSign checks the ads.cert Call Sign of the request if the account asks for it. Requests without a
+// valid Call Sign are rejected if the account enforces them, and only logged otherwise.
+func (deps *endpointDeps) verifyCallSign(httpRequest *http.Request, requestJson []byte, account *config.Account) []error {
+	if deps.adsCertVerifier == nil || !account.AdsCert.VerifyEnabled() {
+		return nil
+	}
+	err := deps.adsCertVerifier.Verify(requestURL(httpRequest), requestJson, httpRequest.Header.Values(adscert.SignHeader))
+	if err == nil {
+		return nil
+	}
+	if account.AdsCert.Verify == config.AdsCertVerifyEnforce {
+		return []error{&errortypes.BadInput{Message: fmt.Sprintf("ads.cert Call Sign verification failed: %v", err)}}
+	}
+	logger.Ctx(httpRequest.Context()).WithClass(logger.ClassValidationWarnings).Warningf("ads.cert Call Sign verification failed: %v", err)
+	return nil
+}
+
+// requestURL returns the URL the request was sent to. The scheme is taken from X-Forwarded-Proto if TLS
+// was terminated before the request reached the server.
+func requestURL(httpRequest *http.Request) string {
+	scheme := "http"
+	if httpRequest.TLS != nil || strings.EqualFold(httpRequest.Header.Get("X-Forwarded-Proto"), "https") {
+		scheme = "https"
+	}
+	return scheme + "://" + httpRequest.Host + httpRequest.URL.RequestURI()
+}
+
+// verifyOrigin checks the site a request comes from against the domains the account allows, if it asks for
+// it. Requests which aren't for a site, and accounts without allowed domains, aren't checked.
+func verifyOrigin(httpRequest *http.Request, req *openrtb_ext.RequestWrapper, account *config.Account) []error {
+	if !account.Origin.VerifyEnabled() || len(account.Origin.AllowedDomains) == 0 || req.Site == nil {
+		return nil
+	}
+	err := checkOrigin(httpRequest, req.Site, &account.Origin)
+	if err == nil {
+		return nil
+	}
+	if account.Origin.Verify == config.OriginVerifyEnforce {
+		return []error{&errortypes.BadInput{Message: fmt.Sprintf("request origin verification failed for account %s: %v", account.ID, err)}}
+	}
+	logger.Ctx(httpRequest.Context()).WithClass(logger.ClassValidationWarnings).Warningf("request origin verification failed for account %s: %v", account.ID, err)
+	return nil
+}
+
+// checkOrigin returns an error unless the site's domain and page, and the Origin and Referer headers, are all
+// allowed by the account. Those which are missing are skipped, but at least one must be given.
+func checkOrigin(httpRequest *http.Request, site *openrtb2.Site, origin *config.AccountOrigin) error {
+	claims := []struct {
+		source string
+		domain string
+	}{
+		{source: "site.domain", domain: hostOf(site.Domain)},
+		{source: "site.page", domain: hostOf(site.Page)},
+		{source: "Origin header", domain: hostOf(httpRequest.Header.Get("Origin"))},
+		{source: "Referer header", domain: hostOf(httpRequest.Referer())},
+	}
+
+	checked := false
+	for _, claim := range claims {
+		if claim.domain == "" {
+			continue
+		}
+		if !origin.IsAllowed(claim.domain) {
+			return fmt.Errorf("%s %s is not an allowed domain", claim.source, claim.domain)
+		}
+		checked = true
+	}
+	if !checked {
+		return errors.New("the request has no site.domain, site.page, Origin or Referer to check")
+	}
+	return nil
+}
+
+// hostOf returns the host name of the URL, which may leave out the scheme, or an empty string if it has none.
+func hostOf(rawURL string) string {
+	if rawURL == "" || rawURL == "null" {
+		return ""
+	}
+	if !strings.Contains(rawURL, "://") {
+		rawURL = "http://" + rawURL
+	}
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	return parsed.Hostname()
+}
+
 // Returns the account ID for the request
 func getAccountID(pub *openrtb2.Publisher) string {
 	if pub != nil {
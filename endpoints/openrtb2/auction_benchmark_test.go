@@ -14,6 +14,7 @@ import (
 	"github.com/prebid/prebid-server/v2/config"
 	"github.com/prebid/prebid-server/v2/currency"
 	"github.com/prebid/prebid-server/v2/exchange"
+	"github.com/prebid/prebid-server/v2/exchange/inprocess"
 	"github.com/prebid/prebid-server/v2/experiment/adscert"
 	"github.com/prebid/prebid-server/v2/hooks"
 	"github.com/prebid/prebid-server/v2/macros"
@@ -54,6 +55,9 @@ func benchmarkBuildTestRequest() *http.Request {
       }
     }
   ],
+  "site": {
+    "page": "prebid.org"
+  },
   "test": 1,
   "tmax": 500
 }`))
@@ -61,12 +65,13 @@ func benchmarkBuildTestRequest() *http.Request {
 }
 
 // BenchmarkOpenrtbEndpoint measures the performance of the endpoint, mocking out the external server dependency.
+// The bidder is called in process, so the results don't depend on the network stack.
 func BenchmarkOpenrtbEndpoint(b *testing.B) {
-	server := httptest.NewServer(http.HandlerFunc(benchmarkTestServer))
-	defer server.Close()
+	transport := inprocess.NewTransport(nil)
+	transport.Handle("ib.adnxs.com", http.HandlerFunc(benchmarkTestServer))
 
 	var infos = make(config.BidderInfos, 0)
-	infos["appnexus"] = config.BidderInfo{Capabilities: &config.CapabilitiesInfo{Site: &config.PlatformInfo{MediaTypes: []openrtb_ext.BidType{openrtb_ext.BidTypeBanner}}}}
+	infos["appnexus"] = config.BidderInfo{Endpoint: "http://ib.adnxs.com/openrtb2", Capabilities: &config.CapabilitiesInfo{Site: &config.PlatformInfo{MediaTypes: []openrtb_ext.BidType{openrtb_ext.BidTypeBanner}}}}
 	paramValidator, err := openrtb_ext.NewBidderParamsValidator("../../static/bidder-params")
 	if err != nil {
 		return
@@ -74,7 +79,7 @@ func BenchmarkOpenrtbEndpoint(b *testing.B) {
 
 	nilMetrics := &metricsConfig.NilMetricsEngine{}
 
-	adapters, adaptersErr := exchange.BuildAdapters(server.Client(), &config.Configuration{}, infos, nilMetrics)
+	adapters, adaptersErr := exchange.BuildAdapters(&http.Client{Transport: transport}, &config.Configuration{}, infos, nilMetrics)
 	if adaptersErr != nil {
 		b.Fatal("unable to build adapters")
 	}
@@ -96,6 +101,7 @@ func BenchmarkOpenrtbEndpoint(b *testing.B) {
 		&adscert.NilSigner{},
 		macros.NewStringIndexBasedReplacer(),
 		nil,
+		exchange.Dependencies{},
 	)
 
 	endpoint, _ := NewEndpoint(
@@ -109,12 +115,25 @@ func BenchmarkOpenrtbEndpoint(b *testing.B) {
 		analyticsBuild.New(&config.Analytics{}),
 		map[string]string{},
 		[]byte{},
-		nil,
+		map[string]openrtb_ext.BidderName{"appnexus": openrtb_ext.BidderAppnexus},
 		empty_fetcher.EmptyFetcher{},
 		hooks.EmptyPlanBuilder{},
 		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
 	)
 
+	recorder := httptest.NewRecorder()
+	endpoint(recorder, benchmarkBuildTestRequest(), nil)
+	if recorder.Code != http.StatusOK || !strings.Contains(recorder.Body.String(), `"seatbid"`) {
+		b.Fatalf("expected a bid from the test server. Got %d: %s", recorder.Code, recorder.Body.String())
+	}
+
 	b.ResetTimer()
 	for n := 0; n < b.N; n++ {
 		endpoint(httptest.NewRecorder(), benchmarkBuildTestRequest(), nil)
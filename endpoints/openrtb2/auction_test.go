@@ -4,7 +4,12 @@ import (
 	"bytes"
 	"compress/gzip"
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
 	"encoding/json"
+	"encoding/pem"
 	"errors"
 	"io"
 	"io/fs"
@@ -26,20 +31,28 @@ import (
 	"github.com/prebid/prebid-server/v2/analytics"
 	analyticsBuild "github.com/prebid/prebid-server/v2/analytics/build"
 	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/defaultrequest"
 	"github.com/prebid/prebid-server/v2/errortypes"
 	"github.com/prebid/prebid-server/v2/exchange"
+	"github.com/prebid/prebid-server/v2/experiment/adscert"
+	"github.com/prebid/prebid-server/v2/geolocation"
 	"github.com/prebid/prebid-server/v2/hooks"
 	"github.com/prebid/prebid-server/v2/hooks/hookexecution"
 	"github.com/prebid/prebid-server/v2/hooks/hookstage"
+	"github.com/prebid/prebid-server/v2/latencybudget"
 	"github.com/prebid/prebid-server/v2/metrics"
 	metricsConfig "github.com/prebid/prebid-server/v2/metrics/config"
 	"github.com/prebid/prebid-server/v2/openrtb_ext"
+	"github.com/prebid/prebid-server/v2/requestnormalization"
+	"github.com/prebid/prebid-server/v2/responsesigning"
 	"github.com/prebid/prebid-server/v2/stored_requests/backends/empty_fetcher"
 	"github.com/prebid/prebid-server/v2/stored_responses"
 	"github.com/prebid/prebid-server/v2/util/iputil"
 	"github.com/prebid/prebid-server/v2/util/jsonutil"
 	"github.com/prebid/prebid-server/v2/util/ptrutil"
+	"github.com/prebid/prebid-server/v2/util/uuidutil"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 const jsonFileExtension string = ".json"
@@ -461,6 +474,13 @@ func TestExplicitUserId(t *testing.T) {
 		empty_fetcher.EmptyFetcher{},
 		hooks.EmptyPlanBuilder{},
 		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
 	)
 
 	endpoint(httptest.NewRecorder(), request, nil)
@@ -478,6 +498,105 @@ func TestExplicitUserId(t *testing.T) {
 	}
 }
 
+type fakeGeoProvider map[string]*geolocation.Location
+
+func (p fakeGeoProvider) Lookup(ip net.IP) (*geolocation.Location, error) {
+	return p[ip.String()], nil
+}
+
+// TestGeoLocation makes sure the device's location is filled in from its IP address before the auction.
+func TestGeoLocation(t *testing.T) {
+	ex := &mockExchange{}
+	provider := fakeGeoProvider{"216.160.83.56": {Country: "USA", Region: "WA", Metro: "819"}}
+
+	request := httptest.NewRequest("POST", "/openrtb2/auction", strings.NewReader(`{
+		"id": "some-request-id",
+		"site": {"page": "test.somepage.com"},
+		"device": {"ip": "216.160.83.56", "geo": {"region": "OR"}},
+		"imp": [{"id": "my-imp-id", "banner": {"format": [{"w": 300, "h": 600}]}, "ext": {"appnexus": {"placementId": 12883451}}}]
+	}`))
+
+	endpoint, _ := NewEndpoint(
+		fakeUUIDGenerator{},
+		ex,
+		mockBidderParamValidator{},
+		empty_fetcher.EmptyFetcher{},
+		empty_fetcher.EmptyFetcher{},
+		&config.Configuration{MaxRequestSize: maxSize},
+		&metricsConfig.NilMetricsEngine{},
+		analyticsBuild.New(&config.Analytics{}),
+		map[string]string{},
+		[]byte{},
+		openrtb_ext.BuildBidderMap(),
+		empty_fetcher.EmptyFetcher{},
+		hooks.EmptyPlanBuilder{},
+		nil,
+		nil,
+		nil,
+		nil,
+		geolocation.NewEnricher(provider, &metricsConfig.NilMetricsEngine{}),
+		nil,
+		nil,
+		nil,
+	)
+
+	endpoint(httptest.NewRecorder(), request, nil)
+
+	require.NotNil(t, ex.lastRequest, "The request never made it into the Exchange.")
+	require.NotNil(t, ex.lastRequest.Device)
+	assert.Equal(t, &openrtb2.Geo{Country: "USA", Region: "OR", Metro: "819"}, ex.lastRequest.Device.Geo)
+}
+
+func TestMobileSDK(t *testing.T) {
+	ex := &mockExchange{}
+
+	request := httptest.NewRequest("POST", "/openrtb2/auction", strings.NewReader(`{
+		"id": "some-request-id",
+		"app": {"bundle": "com.example.app", "ext": {"prebid": {"source": "prebid-mobile", "version": "2.1.0"}}},
+		"device": {"os": "iOS", "w": 320, "h": 480, "ifa": "00000000-0000-0000-0000-000000000000", "ext": {"atts": 2}},
+		"imp": [{"id": "my-imp-id", "instl": 1, "banner": {"format": [{"w": 1, "h": 1}]}, "ext": {"appnexus": {"placementId": 12883451}}}]
+	}`))
+
+	cfg := &config.Configuration{
+		MaxRequestSize: maxSize,
+		MobileSDK: config.MobileSDK{
+			Interstitial: config.MobileSDKInterstitial{MobileSDKFeature: config.MobileSDKFeature{Enabled: true}, MinSizePerc: 90},
+			ATT:          config.MobileSDKFeature{Enabled: true, MinVersion: "2.0"},
+		},
+	}
+	endpoint, _ := NewEndpoint(
+		fakeUUIDGenerator{},
+		ex,
+		mockBidderParamValidator{},
+		empty_fetcher.EmptyFetcher{},
+		empty_fetcher.EmptyFetcher{},
+		cfg,
+		&metricsConfig.NilMetricsEngine{},
+		analyticsBuild.New(&config.Analytics{}),
+		map[string]string{},
+		[]byte{},
+		openrtb_ext.BuildBidderMap(),
+		empty_fetcher.EmptyFetcher{},
+		hooks.EmptyPlanBuilder{},
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+	)
+
+	endpoint(httptest.NewRecorder(), request, nil)
+
+	require.NotNil(t, ex.lastRequest, "The request never made it into the Exchange.")
+	assert.Contains(t, ex.lastRequest.Imp[0].Banner.Format, openrtb2.Format{W: 320, H: 480})
+	assert.NotContains(t, ex.lastRequest.Imp[0].Banner.Format, openrtb2.Format{W: 1, H: 1})
+	assert.Empty(t, ex.lastRequest.Device.IFA)
+	assert.Equal(t, ptrutil.ToPtr[int8](1), ex.lastRequest.Device.Lmt)
+}
+
 // TestBadAliasRequests() reuses two requests that would fail anyway.  Here, we
 // take advantage of our knowledge that processStoredRequests() in auction.go
 // processes aliases before it processes stored imps.  Changing that order
@@ -519,6 +638,13 @@ func doBadAliasRequest(t *testing.T, filename string, expectMsg string) {
 		empty_fetcher.EmptyFetcher{},
 		hooks.EmptyPlanBuilder{},
 		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
 	)
 
 	request := httptest.NewRequest("POST", "/openrtb2/auction", bytes.NewReader(testBidRequest))
@@ -573,6 +699,13 @@ func TestNilExchange(t *testing.T) {
 		empty_fetcher.EmptyFetcher{},
 		hooks.EmptyPlanBuilder{},
 		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
 	)
 
 	if err == nil {
@@ -599,6 +732,13 @@ func TestNilValidator(t *testing.T) {
 		empty_fetcher.EmptyFetcher{},
 		hooks.EmptyPlanBuilder{},
 		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
 	)
 
 	if err == nil {
@@ -625,6 +765,13 @@ func TestExchangeError(t *testing.T) {
 		empty_fetcher.EmptyFetcher{},
 		hooks.EmptyPlanBuilder{},
 		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
 	)
 
 	request := httptest.NewRequest("POST", "/openrtb2/auction", strings.NewReader(validRequest(t, "site.json")))
@@ -752,6 +899,13 @@ func TestImplicitIPsEndToEnd(t *testing.T) {
 			empty_fetcher.EmptyFetcher{},
 			hooks.EmptyPlanBuilder{},
 			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
 		)
 
 		httpReq := httptest.NewRequest("POST", "/openrtb2/auction", strings.NewReader(validRequest(t, test.reqJSONFile)))
@@ -952,6 +1106,13 @@ func TestImplicitDNTEndToEnd(t *testing.T) {
 			empty_fetcher.EmptyFetcher{},
 			hooks.EmptyPlanBuilder{},
 			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
 		)
 
 		httpReq := httptest.NewRequest("POST", "/openrtb2/auction", strings.NewReader(validRequest(t, test.reqJSONFile)))
@@ -1194,6 +1355,16 @@ func TestStoredRequests(t *testing.T) {
 		hooks.EmptyPlanBuilder{},
 		nil,
 		openrtb_ext.NormalizeBidderName,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
 	}
 
 	testStoreVideoAttr := []bool{true, true, false, false, false}
@@ -1203,7 +1374,7 @@ func TestStoredRequests(t *testing.T) {
 		assert.Len(t, errs, 0, "No errors should be returned")
 		storedBidRequestId, hasStoredBidRequest, storedRequests, storedImps, errs := deps.getStoredRequests(context.Background(), json.RawMessage(requestData), impInfo)
 		assert.Len(t, errs, 0, "No errors should be returned")
-		newRequest, impExtInfoMap, errList := deps.processStoredRequests(json.RawMessage(requestData), impInfo, storedRequests, storedImps, storedBidRequestId, hasStoredBidRequest)
+		newRequest, impExtInfoMap, errList := deps.processStoredRequests(json.RawMessage(requestData), impInfo, storedRequests, storedImps, storedBidRequestId, hasStoredBidRequest, nil, config.ChannelWeb)
 		if len(errList) != 0 {
 			for _, err := range errList {
 				if err != nil {
@@ -1225,6 +1396,39 @@ func TestStoredRequests(t *testing.T) {
 	}
 }
 
+func TestResolveFirstPartyData(t *testing.T) {
+	defaultRequests, err := defaultrequest.New([]byte(`{"site":{"ext":{"data":{"c":"default"}}}}`), config.DefReqConfig{})
+	require.NoError(t, err)
+	deps := &endpointDeps{defaultRequests: defaultRequests}
+	account := &config.Account{FirstPartyData: config.AccountFirstPartyData{Rules: []config.AccountFPDRule{
+		{Path: "site.ext.data", Precedence: []string{"stored", "request"}},
+		{Path: "imp.ext.data", Arrays: "merge"},
+	}}}
+	incomingRequest := []byte(`{"id":"req","site":{"ext":{"data":{"a":"request"}}},"imp":[{"id":"imp-1","ext":{"data":{"s":["request"]},"prebid":{"storedrequest":{"id":"stored-imp"}}}}],"ext":{"prebid":{"storedrequest":{"id":"stored-req"}}}}`)
+	impInfo, errs := parseImpInfo(incomingRequest)
+	require.Empty(t, errs)
+	storedRequests := map[string]json.RawMessage{"stored-req": json.RawMessage(`{"site":{"ext":{"data":{"a":"stored","b":"stored"}}}}`)}
+	storedImps := map[string]json.RawMessage{"stored-imp": json.RawMessage(`{"ext":{"data":{"s":["stored"]}}}`)}
+	mergedRequest := []byte(`{"id":"req","site":{"ext":{"data":{"a":"request","b":"stored","c":"default"}}},"imp":[{"id":"imp-1","ext":{"data":{"s":["request"]},"prebid":{"storedrequest":{"id":"stored-imp"}}}}],"ext":{"prebid":{"storedrequest":{"id":"stored-req"}}}}`)
+
+	resolvedRequest, resolution, errs := deps.resolveFirstPartyData(account, config.ChannelWeb, mergedRequest, incomingRequest, impInfo, storedRequests, storedImps, "stored-req", true)
+
+	assert.Empty(t, errs)
+	assert.NotNil(t, resolution)
+	assert.JSONEq(t, `{"id":"req","site":{"ext":{"data":{"a":"stored","b":"stored","c":"default"}}},"imp":[{"id":"imp-1","ext":{"data":{"s":["request","stored"]},"prebid":{"storedrequest":{"id":"stored-imp"}}}}],"ext":{"prebid":{"storedrequest":{"id":"stored-req"}}}}`, string(resolvedRequest))
+}
+
+func TestResolveFirstPartyDataWithoutRules(t *testing.T) {
+	deps := &endpointDeps{}
+	request := []byte(`{"id":"req","site":{"ext":{"data":{"a":"request"}}}}`)
+
+	resolvedRequest, resolution, errs := deps.resolveFirstPartyData(&config.Account{}, config.ChannelWeb, request, request, nil, nil, nil, "", false)
+
+	assert.Empty(t, errs)
+	assert.Nil(t, resolution)
+	assert.Equal(t, request, resolvedRequest)
+}
+
 func TestMergeBidderParams(t *testing.T) {
 	testCases := []struct {
 		description         string
@@ -1644,6 +1848,16 @@ func TestValidateRequest(t *testing.T) {
 		hooks.EmptyPlanBuilder{},
 		nil,
 		openrtb_ext.NormalizeBidderName,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
 	}
 
 	testCases := []struct {
@@ -2355,6 +2569,16 @@ func TestSetIntegrationType(t *testing.T) {
 		hooks.EmptyPlanBuilder{},
 		nil,
 		openrtb_ext.NormalizeBidderName,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
 	}
 
 	testCases := []struct {
@@ -2422,6 +2646,16 @@ func TestStoredRequestGenerateUuid(t *testing.T) {
 		hooks.EmptyPlanBuilder{},
 		nil,
 		openrtb_ext.NormalizeBidderName,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
 	}
 
 	req := &openrtb2.BidRequest{}
@@ -2490,7 +2724,7 @@ func TestStoredRequestGenerateUuid(t *testing.T) {
 		assert.Empty(t, errs, test.description)
 		storedBidRequestId, hasStoredBidRequest, storedRequests, storedImps, errs := deps.getStoredRequests(context.Background(), json.RawMessage(test.givenRawData), impInfo)
 		assert.Empty(t, errs, test.description)
-		newRequest, _, errList := deps.processStoredRequests(json.RawMessage(test.givenRawData), impInfo, storedRequests, storedImps, storedBidRequestId, hasStoredBidRequest)
+		newRequest, _, errList := deps.processStoredRequests(json.RawMessage(test.givenRawData), impInfo, storedRequests, storedImps, storedBidRequestId, hasStoredBidRequest, nil, config.ChannelWeb)
 		assert.Empty(t, errList, test.description)
 
 		if err := jsonutil.UnmarshalValid(newRequest, req); err != nil {
@@ -2527,6 +2761,16 @@ func TestOversizedRequest(t *testing.T) {
 		hooks.EmptyPlanBuilder{},
 		nil,
 		openrtb_ext.NormalizeBidderName,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
 	}
 
 	req := httptest.NewRequest("POST", "/openrtb2/auction", strings.NewReader(reqBody))
@@ -2567,6 +2811,16 @@ func TestRequestSizeEdgeCase(t *testing.T) {
 		hooks.EmptyPlanBuilder{},
 		nil,
 		openrtb_ext.NormalizeBidderName,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
 	}
 
 	req := httptest.NewRequest("POST", "/openrtb2/auction", strings.NewReader(reqBody))
@@ -2600,6 +2854,13 @@ func TestNoEncoding(t *testing.T) {
 		empty_fetcher.EmptyFetcher{},
 		hooks.EmptyPlanBuilder{},
 		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
 	)
 	request := httptest.NewRequest("POST", "/openrtb2/auction", strings.NewReader(validRequest(t, "site.json")))
 	recorder := httptest.NewRecorder()
@@ -2685,6 +2946,13 @@ func TestContentType(t *testing.T) {
 		empty_fetcher.EmptyFetcher{},
 		hooks.EmptyPlanBuilder{},
 		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
 	)
 	request := httptest.NewRequest("POST", "/openrtb2/auction", strings.NewReader(validRequest(t, "site.json")))
 	recorder := httptest.NewRecorder()
@@ -2907,6 +3175,16 @@ func TestValidateImpExt(t *testing.T) {
 		hooks.EmptyPlanBuilder{},
 		nil,
 		openrtb_ext.NormalizeBidderName,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
 	}
 
 	for _, group := range testGroups {
@@ -2963,6 +3241,16 @@ func TestCurrencyTrunc(t *testing.T) {
 		hooks.EmptyPlanBuilder{},
 		nil,
 		openrtb_ext.NormalizeBidderName,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
 	}
 
 	ui := int64(1)
@@ -2986,7 +3274,10 @@ func TestCurrencyTrunc(t *testing.T) {
 
 	errL := deps.validateRequest(nil, nil, &openrtb_ext.RequestWrapper{BidRequest: &req}, false, false, nil, false)
 
-	expectedError := errortypes.Warning{Message: "A prebid request can only process one currency. Taking the first currency in the list, USD, as the active currency"}
+	expectedError := errortypes.Warning{
+		Message:     "A prebid request can only process one currency. Taking the first currency in the list, USD, as the active currency",
+		WarningCode: errortypes.MultipleCurrenciesWarningCode,
+	}
 	assert.ElementsMatch(t, errL, []error{&expectedError})
 }
 
@@ -3012,6 +3303,16 @@ func TestCCPAInvalid(t *testing.T) {
 		hooks.EmptyPlanBuilder{},
 		nil,
 		openrtb_ext.NormalizeBidderName,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
 	}
 
 	ui := int64(1)
@@ -3065,6 +3366,16 @@ func TestNoSaleInvalid(t *testing.T) {
 		hooks.EmptyPlanBuilder{},
 		nil,
 		openrtb_ext.NormalizeBidderName,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
 	}
 
 	ui := int64(1)
@@ -3101,7 +3412,7 @@ func TestValidateSourceTID(t *testing.T) {
 	}
 
 	deps := &endpointDeps{
-		fakeUUIDGenerator{},
+		uuidutil.UUIDRandomGenerator{},
 		&nobidExchange{},
 		mockBidderParamValidator{},
 		&mockStoredReqFetcher{},
@@ -3121,6 +3432,16 @@ func TestValidateSourceTID(t *testing.T) {
 		hooks.EmptyPlanBuilder{},
 		nil,
 		openrtb_ext.NormalizeBidderName,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
 	}
 
 	ui := int64(1)
@@ -3167,6 +3488,16 @@ func TestSChainInvalid(t *testing.T) {
 		hooks.EmptyPlanBuilder{},
 		nil,
 		openrtb_ext.NormalizeBidderName,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
 	}
 
 	ui := int64(1)
@@ -3182,16 +3513,64 @@ func TestSChainInvalid(t *testing.T) {
 				Ext: json.RawMessage(`{"appnexus": {"placementId": 5667}}`),
 			},
 		},
-		Site: &openrtb2.Site{
-			ID: "anySiteID",
+		Site: &openrtb2.Site{
+			ID: "anySiteID",
+		},
+		Ext: json.RawMessage(`{"prebid":{"schains":[{"bidders":["appnexus"],"schain":{"complete":1,"nodes":[{"asi":"directseller1.com","sid":"00001","rid":"BidRequest1","hp":1}],"ver":"1.0"}}, {"bidders":["appnexus"],"schain":{"complete":1,"nodes":[{"asi":"directseller2.com","sid":"00002","rid":"BidRequest2","hp":1}],"ver":"1.0"}}]}}`),
+	}
+
+	errL := deps.validateRequest(nil, nil, &openrtb_ext.RequestWrapper{BidRequest: &req}, false, false, nil, false)
+
+	expectedError := errors.New("request.ext.prebid.schains contains multiple schains for bidder appnexus; it must contain no more than one per bidder.")
+	assert.ElementsMatch(t, errL, []error{expectedError})
+}
+
+func TestValidateSChainsStructure(t *testing.T) {
+	sChains := []*openrtb_ext.ExtRequestPrebidSChain{
+		{Bidders: []string{"appnexus"}, SChain: openrtb2.SupplyChain{Ver: "1.0", Nodes: []openrtb2.SupplyChainNode{{ASI: "directseller1.com", SID: "00001"}}}},
+		{Bidders: []string{"rubicon"}, SChain: openrtb2.SupplyChain{Ver: "1.0", Nodes: []openrtb2.SupplyChainNode{{ASI: "directseller1.com"}}}},
+	}
+
+	err := validateSChains(sChains)
+	assert.EqualError(t, err, "request.ext.prebid.schains[1].schain.nodes[0].sid is required")
+}
+
+func TestValidateRequestSChain(t *testing.T) {
+	testCases := []struct {
+		description string
+		source      *openrtb2.Source
+		expectedErr string
+	}{
+		{
+			description: "no source",
+		},
+		{
+			description: "valid source.ext.schain",
+			source:      &openrtb2.Source{Ext: json.RawMessage(`{"schain":{"complete":1,"nodes":[{"asi":"directseller1.com","sid":"00001","hp":1}],"ver":"1.0"}}`)},
+		},
+		{
+			description: "looping source.ext.schain",
+			source:      &openrtb2.Source{Ext: json.RawMessage(`{"schain":{"complete":1,"nodes":[{"asi":"directseller1.com","sid":"00001"},{"asi":"directseller1.com","sid":"00001"}],"ver":"1.0"}}`)},
+			expectedErr: "request.source.ext.schain.nodes[1] repeats nodes[0] (asi directseller1.com, sid 00001); the supply chain loops",
+		},
+		{
+			description: "invalid source.schain",
+			source:      &openrtb2.Source{SChain: &openrtb2.SupplyChain{Ver: "1.0", Complete: 3}},
+			expectedErr: "request.source.schain.complete must be 0 or 1. Got 3",
 		},
-		Ext: json.RawMessage(`{"prebid":{"schains":[{"bidders":["appnexus"],"schain":{"complete":1,"nodes":[{"asi":"directseller1.com","sid":"00001","rid":"BidRequest1","hp":1}],"ver":"1.0"}}, {"bidders":["appnexus"],"schain":{"complete":1,"nodes":[{"asi":"directseller2.com","sid":"00002","rid":"BidRequest2","hp":1}],"ver":"1.0"}}]}}`),
 	}
 
-	errL := deps.validateRequest(nil, nil, &openrtb_ext.RequestWrapper{BidRequest: &req}, false, false, nil, false)
-
-	expectedError := errors.New("request.ext.prebid.schains contains multiple schains for bidder appnexus; it must contain no more than one per bidder.")
-	assert.ElementsMatch(t, errL, []error{expectedError})
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			req := &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{Source: test.source}}
+			err := validateRequestSChain(req)
+			if test.expectedErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, test.expectedErr)
+			}
+		})
+	}
 }
 
 func TestMapSChains(t *testing.T) {
@@ -3696,7 +4075,7 @@ func TestValidateAndFillSourceTID(t *testing.T) {
 	}
 
 	for _, test := range testCases {
-		_ = validateAndFillSourceTID(test.req, test.generateRequestID, test.hasStoredBidRequest, test.isAmp)
+		_ = validateAndFillSourceTID(test.req, uuidutil.UUIDRandomGenerator{}, test.generateRequestID, test.hasStoredBidRequest, test.isAmp)
 		impWrapper := &openrtb_ext.ImpWrapper{}
 		impWrapper.Imp = &test.req.Imp[0]
 		ie, _ := impWrapper.GetImpExt()
@@ -3736,6 +4115,16 @@ func TestEidPermissionsInvalid(t *testing.T) {
 		hooks.EmptyPlanBuilder{},
 		nil,
 		openrtb_ext.NormalizeBidderName,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
 	}
 
 	ui := int64(1)
@@ -4008,6 +4397,13 @@ func TestIOS14EndToEnd(t *testing.T) {
 		empty_fetcher.EmptyFetcher{},
 		hooks.EmptyPlanBuilder{},
 		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
 	)
 
 	httpReq := httptest.NewRequest("POST", "/openrtb2/auction", strings.NewReader(validRequest(t, "app-ios140-no-ifa.json")))
@@ -4076,6 +4472,16 @@ func TestAuctionWarnings(t *testing.T) {
 		hooks.EmptyPlanBuilder{},
 		nil,
 		openrtb_ext.NormalizeBidderName,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
 	}
 	for _, test := range testCases {
 		t.Run(test.name, func(t *testing.T) {
@@ -4123,13 +4529,23 @@ func TestParseRequestParseImpInfoError(t *testing.T) {
 		hooks.EmptyPlanBuilder{},
 		nil,
 		openrtb_ext.NormalizeBidderName,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
 	}
 
 	hookExecutor := hookexecution.NewHookExecutor(deps.hookExecutionPlanBuilder, hookexecution.EndpointAuction, deps.metricsEngine)
 
 	req := httptest.NewRequest("POST", "/openrtb2/auction", strings.NewReader(reqBody))
 
-	resReq, impExtInfoMap, _, _, _, _, errL := deps.parseRequest(req, &metrics.Labels{}, hookExecutor)
+	resReq, impExtInfoMap, _, _, _, _, _, _, errL := deps.parseRequest(req, &metrics.Labels{}, hookExecutor)
 
 	assert.Nil(t, resReq, "Result request should be nil due to incorrect imp")
 	assert.Nil(t, impExtInfoMap, "Impression info map should be nil due to incorrect imp")
@@ -4204,6 +4620,16 @@ func TestParseGzipedRequest(t *testing.T) {
 		hooks.EmptyPlanBuilder{},
 		nil,
 		openrtb_ext.NormalizeBidderName,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
 	}
 
 	hookExecutor := hookexecution.NewHookExecutor(deps.hookExecutionPlanBuilder, hookexecution.EndpointAuction, deps.metricsEngine)
@@ -4223,7 +4649,7 @@ func TestParseGzipedRequest(t *testing.T) {
 		} else {
 			req = httptest.NewRequest("POST", "/openrtb2/auction", bytes.NewReader(reqBody))
 		}
-		resReq, impExtInfoMap, _, _, _, _, errL := deps.parseRequest(req, &metrics.Labels{}, hookExecutor)
+		resReq, impExtInfoMap, _, _, _, _, _, _, errL := deps.parseRequest(req, &metrics.Labels{}, hookExecutor)
 
 		if test.expectedErr == "" {
 			assert.Nil(t, errL, "Error list should be nil", test.desc)
@@ -4699,6 +5125,13 @@ func TestAuctionResponseHeaders(t *testing.T) {
 		empty_fetcher.EmptyFetcher{},
 		hooks.EmptyPlanBuilder{},
 		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
 	)
 
 	for _, test := range testCases {
@@ -4805,13 +5238,23 @@ func TestParseRequestMergeBidderParams(t *testing.T) {
 				hooks.EmptyPlanBuilder{},
 				nil,
 				openrtb_ext.NormalizeBidderName,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
 			}
 
 			hookExecutor := hookexecution.NewHookExecutor(deps.hookExecutionPlanBuilder, hookexecution.EndpointAuction, deps.metricsEngine)
 
 			req := httptest.NewRequest("POST", "/openrtb2/auction", strings.NewReader(test.givenRequestBody))
 
-			resReq, _, _, _, _, _, errL := deps.parseRequest(req, &metrics.Labels{}, hookExecutor)
+			resReq, _, _, _, _, _, _, _, errL := deps.parseRequest(req, &metrics.Labels{}, hookExecutor)
 
 			assert.NoError(t, resReq.RebuildRequest())
 
@@ -4839,6 +5282,45 @@ func TestParseRequestMergeBidderParams(t *testing.T) {
 	}
 }
 
+func TestParseRequestNormalization(t *testing.T) {
+	body := `{"id":"req","tmax":"500","cur":["usd"],"site":{"page":"prebid.org"},"imp":[{"id":"imp","banner":{"format":[{"w":300,"h":250}]},"ext":{"appnexus":{"placementId":12883451}}}],"regs":{"ext":{"consent":"BO"}}}`
+	cfg := &config.Configuration{
+		MaxRequestSize:       int64(len(body)),
+		RequestNormalization: config.RequestNormalization{Enabled: true, StringifiedNumbers: true, EnumCase: true, ConsentLocation: true},
+	}
+	deps := &endpointDeps{
+		uuidGenerator:             fakeUUIDGenerator{},
+		paramsValidator:           mockBidderParamValidator{},
+		storedReqFetcher:          &mockStoredReqFetcher{},
+		videoFetcher:              empty_fetcher.EmptyFetcher{},
+		accounts:                  empty_fetcher.EmptyFetcher{},
+		cfg:                       cfg,
+		metricsEngine:             &metricsConfig.NilMetricsEngine{},
+		bidderMap:                 openrtb_ext.BuildBidderMap(),
+		privateNetworkIPValidator: hardcodedResponseIPValidator{response: true},
+		storedRespFetcher:         empty_fetcher.EmptyFetcher{},
+		hookExecutionPlanBuilder:  hooks.EmptyPlanBuilder{},
+		normalizeBidderName:       openrtb_ext.NormalizeBidderName,
+		normalizer:                requestnormalization.NewNormalizer(cfg.RequestNormalization, mockBidderParamValidator{}, &metricsConfig.NilMetricsEngine{}),
+	}
+	hookExecutor := hookexecution.NewHookExecutor(deps.hookExecutionPlanBuilder, hookexecution.EndpointAuction, deps.metricsEngine)
+	req := httptest.NewRequest("POST", "/openrtb2/auction", strings.NewReader(body))
+
+	resReq, _, _, _, _, _, _, _, errL := deps.parseRequest(req, &metrics.Labels{}, hookExecutor)
+
+	assert.Empty(t, errortypes.FatalOnly(errL))
+	require.NotNil(t, resReq)
+	assert.Equal(t, int64(500), resReq.TMax)
+	assert.Equal(t, []string{"USD"}, resReq.Cur)
+	require.NotNil(t, resReq.User)
+	assert.Equal(t, "BO", resReq.User.Consent)
+	assert.Equal(t, []error{
+		&errortypes.Warning{Message: "request.regs.ext.consent was moved to request.user.consent", WarningCode: errortypes.RequestNormalizedWarningCode},
+		&errortypes.Warning{Message: `request.tmax was the string "500", and was changed to the number 500`, WarningCode: errortypes.RequestNormalizedWarningCode},
+		&errortypes.Warning{Message: `request.cur[0] was "usd", and was changed to "USD"`, WarningCode: errortypes.RequestNormalizedWarningCode},
+	}, errortypes.WarningOnly(errL))
+}
+
 func TestParseRequestStoredResponses(t *testing.T) {
 	mockStoredResponses := map[string]json.RawMessage{
 		"6d718149": json.RawMessage(`[{"bid": [{"id": "bid_id1"],"seat": "appnexus"}]`),
@@ -4909,13 +5391,23 @@ func TestParseRequestStoredResponses(t *testing.T) {
 				hooks.EmptyPlanBuilder{},
 				nil,
 				openrtb_ext.NormalizeBidderName,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
 			}
 
 			hookExecutor := hookexecution.NewHookExecutor(deps.hookExecutionPlanBuilder, hookexecution.EndpointAuction, deps.metricsEngine)
 
 			req := httptest.NewRequest("POST", "/openrtb2/auction", strings.NewReader(test.givenRequestBody))
 
-			_, _, storedResponses, _, _, _, errL := deps.parseRequest(req, &metrics.Labels{}, hookExecutor)
+			_, _, storedResponses, _, _, _, _, _, errL := deps.parseRequest(req, &metrics.Labels{}, hookExecutor)
 
 			if test.expectedErrorCount == 0 {
 				assert.Equal(t, test.expectedStoredResponses, storedResponses, "stored responses should match")
@@ -5022,12 +5514,22 @@ func TestParseRequestStoredBidResponses(t *testing.T) {
 				hooks.EmptyPlanBuilder{},
 				nil,
 				openrtb_ext.NormalizeBidderName,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
 			}
 
 			hookExecutor := hookexecution.NewHookExecutor(deps.hookExecutionPlanBuilder, hookexecution.EndpointAuction, deps.metricsEngine)
 
 			req := httptest.NewRequest("POST", "/openrtb2/auction", strings.NewReader(test.givenRequestBody))
-			_, _, _, storedBidResponses, _, _, errL := deps.parseRequest(req, &metrics.Labels{}, hookExecutor)
+			_, _, _, storedBidResponses, _, _, _, _, errL := deps.parseRequest(req, &metrics.Labels{}, hookExecutor)
 			if test.expectedErrorCount == 0 {
 				assert.Empty(t, errL)
 				assert.Equal(t, test.expectedStoredBidResponses, storedBidResponses, "stored responses should match")
@@ -5060,6 +5562,16 @@ func TestValidateStoredResp(t *testing.T) {
 		hooks.EmptyPlanBuilder{},
 		nil,
 		openrtb_ext.NormalizeBidderName,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
 	}
 
 	testCases := []struct {
@@ -5800,7 +6312,7 @@ func TestSendAuctionResponse_LogsErrors(t *testing.T) {
 			ao := analytics.AuctionObject{}
 			account := &config.Account{DebugAllow: true}
 
-			_, ao = sendAuctionResponse(writer, test.hookExecutor, test.response, test.request, account, labels, ao)
+			_, ao = sendAuctionResponse(context.Background(), writer, test.hookExecutor, test.response, test.request, account, labels, ao, nil)
 
 			assert.Equal(t, ao.Errors, test.expectedErrors, "Invalid errors.")
 			assert.Equal(t, test.expectedStatus, ao.Status, "Invalid HTTP response status.")
@@ -5808,6 +6320,61 @@ func TestSendAuctionResponse_LogsErrors(t *testing.T) {
 	}
 }
 
+func TestSendAuctionResponseSigned(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+	keyFile := filepath.Join(t.TempDir(), "2024.pem")
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}), 0600))
+	signer, err := responsesigning.NewSigner(config.ResponseSigning{Keys: map[string]string{"2024": keyFile}})
+	require.NoError(t, err)
+
+	testCases := []struct {
+		description     string
+		signing         config.AccountResponseSigning
+		expectSignature bool
+		expectedErrors  []error
+	}{
+		{
+			description:     "signed",
+			signing:         config.AccountResponseSigning{Enabled: true, KeyID: "2024"},
+			expectSignature: true,
+		},
+		{
+			description: "not enabled for the account",
+			signing:     config.AccountResponseSigning{KeyID: "2024"},
+		},
+		{
+			description:    "unknown key is sent unsigned",
+			signing:        config.AccountResponseSigning{Enabled: true, KeyID: "2023"},
+			expectedErrors: []error{errors.New("/openrtb2/auction Failed to sign response: unknown response signing key 2023")},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			writer := httptest.NewRecorder()
+			response := &openrtb2.BidResponse{ID: "some-id", SeatBid: []openrtb2.SeatBid{{Seat: "appnexus", Bid: []openrtb2.Bid{{ID: "1", ImpID: "1", Price: 1.5}}}}}
+			account := &config.Account{ResponseSigning: test.signing}
+
+			_, ao := sendAuctionResponse(context.Background(), writer, &hookexecution.EmptyHookExecutor{}, response, &openrtb2.BidRequest{ID: "some-id"}, account, metrics.Labels{}, analytics.AuctionObject{}, signer)
+
+			assert.Equal(t, test.expectedErrors, ao.Errors)
+			var expectedBody bytes.Buffer
+			require.NoError(t, writeBidResponse(&expectedBody, response))
+			assert.Equal(t, expectedBody.String(), writer.Body.String())
+
+			signature := writer.Header().Get(responsesigning.Header)
+			if test.expectSignature {
+				assert.Regexp(t, `^[\w-]+\.\.[\w-]+$`, signature)
+			} else {
+				assert.Empty(t, signature)
+			}
+		})
+	}
+}
+
 func TestParseRequestMultiBid(t *testing.T) {
 	tests := []struct {
 		name             string
@@ -5878,13 +6445,23 @@ func TestParseRequestMultiBid(t *testing.T) {
 				hooks.EmptyPlanBuilder{},
 				nil,
 				openrtb_ext.NormalizeBidderName,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
 			}
 
 			hookExecutor := hookexecution.NewHookExecutor(deps.hookExecutionPlanBuilder, hookexecution.EndpointAuction, deps.metricsEngine)
 
 			req := httptest.NewRequest("POST", "/openrtb2/auction", strings.NewReader(test.givenRequestBody))
 
-			resReq, _, _, _, _, _, errL := deps.parseRequest(req, &metrics.Labels{}, hookExecutor)
+			resReq, _, _, _, _, _, _, _, errL := deps.parseRequest(req, &metrics.Labels{}, hookExecutor)
 
 			assert.NoError(t, resReq.RebuildRequest())
 
@@ -6274,7 +6851,8 @@ func TestValidateOrFillCDep(t *testing.T) {
 			},
 			wantDeviceExt: json.RawMessage(`{`),
 			wantErr: &errortypes.FailedToUnmarshal{
-				Message: "expects \" or n, but found \x00",
+				Message:  "expects \" or n, but found \x00",
+				Position: &errortypes.JSONPosition{Offset: 1},
 			},
 		},
 	}
@@ -6425,6 +7003,16 @@ func TestValidateRequestCookieDeprecation(t *testing.T) {
 		hooks.EmptyPlanBuilder{},
 		nil,
 		openrtb_ext.NormalizeBidderName,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
 	}
 
 	for _, test := range testCases {
@@ -6436,3 +7024,237 @@ func TestValidateRequestCookieDeprecation(t *testing.T) {
 		assert.Equal(t, test.wantCDep, deviceExt.GetCDep())
 	}
 }
+
+func TestSetLatencyBudgetDebug(t *testing.T) {
+	budget := latencybudget.New(config.LatencyBudget{Enabled: true, StoredRequests: 0.1, Hooks: 0.05, CacheWrite: 0.1}, time.Now())
+	budget.SetTmax(time.Second)
+	budget.Record(metrics.LatencyBudgetHooks, 3*time.Millisecond)
+
+	testCases := []struct {
+		name        string
+		budget      *latencybudget.Budget
+		ext         json.RawMessage
+		expectedExt string
+	}{
+		{
+			name:        "no-budget",
+			budget:      nil,
+			ext:         json.RawMessage(`{"debug":{}}`),
+			expectedExt: `{"debug":{}}`,
+		},
+		{
+			name:        "no-debug",
+			budget:      budget,
+			ext:         json.RawMessage(`{"responsetimemillis":{}}`),
+			expectedExt: `{"responsetimemillis":{}}`,
+		},
+		{
+			name:        "debug",
+			budget:      budget,
+			ext:         json.RawMessage(`{"debug":{"resolvedrequest":{}}}`),
+			expectedExt: `{"debug":{"resolvedrequest":{},"latencybudget":{"tmaxms":1000,"stages":{"hooks":{"allocatedms":50,"usedms":3}}}}}`,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			response := &openrtb2.BidResponse{ID: "some-id", Ext: test.ext}
+			assert.NoError(t, setLatencyBudgetDebug(response, test.budget))
+			assert.JSONEq(t, test.expectedExt, string(response.Ext))
+		})
+	}
+
+	assert.NoError(t, setLatencyBudgetDebug(nil, budget))
+}
+
+func TestHooksExecutionTime(t *testing.T) {
+	outcomes := []hookexecution.StageOutcome{
+		{ExecutionTime: hookexecution.ExecutionTime{ExecutionTimeMillis: 2 * time.Millisecond}},
+		{ExecutionTime: hookexecution.ExecutionTime{ExecutionTimeMillis: 5 * time.Millisecond}},
+	}
+	assert.Equal(t, 7*time.Millisecond, hooksExecutionTime(outcomes))
+	assert.Equal(t, time.Duration(0), hooksExecutionTime(nil))
+}
+
+type mockAdsCertVerifier struct {
+	err        error
+	requestURL string
+	body       []byte
+	signatures []string
+}
+
+func (v *mockAdsCertVerifier) Verify(requestURL string, body []byte, signatures []string) error {
+	v.requestURL = requestURL
+	v.body = body
+	v.signatures = signatures
+	return v.err
+}
+
+func TestVerifyCallSign(t *testing.T) {
+	testCases := []struct {
+		description  string
+		verify       string
+		verifyErr    error
+		expectedErrs []error
+		expectCalled bool
+	}{
+		{
+			description: "Verification off",
+			verify:      config.AdsCertVerifyOff,
+			verifyErr:   errors.New("invalid signature"),
+		},
+		{
+			description:  "Valid signature enforced",
+			verify:       config.AdsCertVerifyEnforce,
+			expectCalled: true,
+		},
+		{
+			description:  "Invalid signature observed",
+			verify:       config.AdsCertVerifyObserve,
+			verifyErr:    errors.New("invalid signature"),
+			expectCalled: true,
+		},
+		{
+			description:  "Invalid signature enforced",
+			verify:       config.AdsCertVerifyEnforce,
+			verifyErr:    errors.New("invalid signature"),
+			expectedErrs: []error{&errortypes.BadInput{Message: "ads.cert Call Sign verification failed: invalid signature"}},
+			expectCalled: true,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			verifier := &mockAdsCertVerifier{err: test.verifyErr}
+			deps := &endpointDeps{adsCertVerifier: verifier}
+
+			httpReq := httptest.NewRequest(http.MethodPost, "http://pbs.com/openrtb2/auction?debug=1", nil)
+			httpReq.Header.Add(adscert.SignHeader, "from=ssp.com&invoking=pbs.com")
+			account := &config.Account{AdsCert: config.AccountAdsCert{Verify: test.verify}}
+
+			errs := deps.verifyCallSign(httpReq, []byte(`{"id":"1"}`), account)
+			assert.Equal(t, test.expectedErrs, errs)
+			if test.expectCalled {
+				assert.Equal(t, "http://pbs.com/openrtb2/auction?debug=1", verifier.requestURL)
+				assert.Equal(t, []byte(`{"id":"1"}`), verifier.body)
+				assert.Equal(t, []string{"from=ssp.com&invoking=pbs.com"}, verifier.signatures)
+			} else {
+				assert.Empty(t, verifier.requestURL)
+			}
+		})
+	}
+}
+
+func TestVerifyCallSignWithoutVerifier(t *testing.T) {
+	deps := &endpointDeps{}
+	httpReq := httptest.NewRequest(http.MethodPost, "/openrtb2/auction", nil)
+	account := &config.Account{AdsCert: config.AccountAdsCert{Verify: config.AdsCertVerifyEnforce}}
+
+	assert.Empty(t, deps.verifyCallSign(httpReq, nil, account))
+}
+
+func TestRequestURL(t *testing.T) {
+	httpReq := httptest.NewRequest(http.MethodPost, "http://pbs.com/openrtb2/auction?a=b", nil)
+	assert.Equal(t, "http://pbs.com/openrtb2/auction?a=b", requestURL(httpReq))
+
+	httpReq.Header.Set("X-Forwarded-Proto", "https")
+	assert.Equal(t, "https://pbs.com/openrtb2/auction?a=b", requestURL(httpReq))
+
+	tlsReq := httptest.NewRequest(http.MethodPost, "https://pbs.com/openrtb2/auction", nil)
+	assert.Equal(t, "https://pbs.com/openrtb2/auction", requestURL(tlsReq))
+}
+
+func TestVerifyOrigin(t *testing.T) {
+	allowed := []string{"publisher.com", "partner.org"}
+	testCases := []struct {
+		description  string
+		origin       config.AccountOrigin
+		site         *openrtb2.Site
+		headers      map[string]string
+		expectedErrs []error
+	}{
+		{
+			description: "Verification off",
+			origin:      config.AccountOrigin{Verify: config.OriginVerifyOff, AllowedDomains: allowed},
+			site:        &openrtb2.Site{Domain: "spoofer.com"},
+		},
+		{
+			description: "No allowed domains",
+			origin:      config.AccountOrigin{Verify: config.OriginVerifyEnforce},
+			site:        &openrtb2.Site{Domain: "spoofer.com"},
+		},
+		{
+			description: "Not a site request",
+			origin:      config.AccountOrigin{Verify: config.OriginVerifyEnforce, AllowedDomains: allowed},
+		},
+		{
+			description: "Allowed domain, page and headers",
+			origin:      config.AccountOrigin{Verify: config.OriginVerifyEnforce, AllowedDomains: allowed},
+			site:        &openrtb2.Site{Domain: "www.publisher.com", Page: "https://www.publisher.com/news"},
+			headers:     map[string]string{"Origin": "https://publisher.com", "Referer": "https://news.publisher.com/a"},
+		},
+		{
+			description: "Allowed Origin header only",
+			origin:      config.AccountOrigin{Verify: config.OriginVerifyEnforce, AllowedDomains: allowed},
+			site:        &openrtb2.Site{},
+			headers:     map[string]string{"Origin": "https://partner.org"},
+		},
+		{
+			description: "Spoofed domain observed",
+			origin:      config.AccountOrigin{Verify: config.OriginVerifyObserve, AllowedDomains: allowed},
+			site:        &openrtb2.Site{Domain: "publisher.com"},
+			headers:     map[string]string{"Origin": "https://spoofer.com"},
+		},
+		{
+			description:  "Spoofed domain enforced",
+			origin:       config.AccountOrigin{Verify: config.OriginVerifyEnforce, AllowedDomains: allowed},
+			site:         &openrtb2.Site{Domain: "publisher.com"},
+			headers:      map[string]string{"Origin": "https://spoofer.com"},
+			expectedErrs: []error{&errortypes.BadInput{Message: "request origin verification failed for account 1001: Origin header spoofer.com is not an allowed domain"}},
+		},
+		{
+			description:  "Page on a lookalike domain enforced",
+			origin:       config.AccountOrigin{Verify: config.OriginVerifyEnforce, AllowedDomains: allowed},
+			site:         &openrtb2.Site{Page: "https://notpublisher.com/news"},
+			expectedErrs: []error{&errortypes.BadInput{Message: "request origin verification failed for account 1001: site.page notpublisher.com is not an allowed domain"}},
+		},
+		{
+			description:  "Nothing to check enforced",
+			origin:       config.AccountOrigin{Verify: config.OriginVerifyEnforce, AllowedDomains: allowed},
+			site:         &openrtb2.Site{},
+			expectedErrs: []error{&errortypes.BadInput{Message: "request origin verification failed for account 1001: the request has no site.domain, site.page, Origin or Referer to check"}},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			httpReq := httptest.NewRequest(http.MethodPost, "/openrtb2/auction", nil)
+			for name, value := range test.headers {
+				httpReq.Header.Set(name, value)
+			}
+			req := &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{Site: test.site}}
+			account := &config.Account{ID: "1001", Origin: test.origin}
+
+			errs := verifyOrigin(httpReq, req, account)
+			assert.Equal(t, test.expectedErrs, errs)
+		})
+	}
+}
+
+func TestHostOf(t *testing.T) {
+	testCases := []struct {
+		rawURL   string
+		expected string
+	}{
+		{rawURL: "", expected: ""},
+		{rawURL: "null", expected: ""},
+		{rawURL: "https://www.publisher.com/news?a=b", expected: "www.publisher.com"},
+		{rawURL: "publisher.com", expected: "publisher.com"},
+		{rawURL: "publisher.com:8080/news", expected: "publisher.com"},
+		{rawURL: "http://%zz", expected: ""},
+	}
+
+	for _, test := range testCases {
+		assert.Equal(t, test.expected, hostOf(test.rawURL), test.rawURL)
+	}
+}
@@ -0,0 +1,30 @@
+package openrtb2
+
+import (
+	"net/http"
+
+	"github.com/prebid/prebid-server/v2/clienthints"
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/openrtb_ext"
+	"github.com/prebid/prebid-server/v2/privacy"
+)
+
+// clientHintsComponent names the enrichment from client hints in the account's activity controls.
+var clientHintsComponent = privacy.Component{Type: privacy.ComponentTypeGeneral, Name: "clienthints"}
+
+// setClientHintsImplicitly fills in the device from the request's User-Agent Client Hints, if the account turns
+// it on. The hints describe whatever sent the request, so they're only used if device.ua is its User-Agent too.
+// Nothing is added to the requests of children or of users who limit ad tracking, or if the account's enrichUfpd
+// activity control doesn't allow it.
+func setClientHintsImplicitly(httpReq *http.Request, req *openrtb_ext.RequestWrapper, account *config.Account) {
+	if account == nil || !account.ClientHints.Enabled || req.Device == nil || req.Device.UA != httpReq.UserAgent() {
+		return
+	}
+	if (req.Regs != nil && req.Regs.COPPA == 1) || (req.Device.Lmt != nil && *req.Device.Lmt == 1) {
+		return
+	}
+	if !privacy.NewActivityControl(&account.Privacy).Allow(privacy.ActivityEnrichUserFPD, clientHintsComponent, privacy.NewRequestFromBidRequest(*req)) {
+		return
+	}
+	clienthints.EnrichDevice(req.Device, clienthints.Parse(httpReq.Header))
+}
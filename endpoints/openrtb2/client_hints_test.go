@@ -0,0 +1,83 @@
+package openrtb2
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/openrtb_ext"
+	"github.com/prebid/prebid-server/v2/util/ptrutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetClientHintsImplicitly(t *testing.T) {
+	const ua = "Mozilla/5.0 (Linux; Android 10; K) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/118.0.0.0 Mobile Safari/537.36"
+	enabled := config.Account{ClientHints: config.AccountClientHints{Enabled: true}}
+	denied := enabled
+	denied.Privacy.AllowActivities = &config.AllowActivities{EnrichUserFPD: config.Activity{Default: ptrutil.ToPtr(false)}}
+
+	testCases := []struct {
+		description string
+		account     config.Account
+		request     openrtb2.BidRequest
+		expectSUA   bool
+	}{
+		{
+			description: "enabled",
+			account:     enabled,
+			request:     openrtb2.BidRequest{Device: &openrtb2.Device{UA: ua}},
+			expectSUA:   true,
+		},
+		{
+			description: "not enabled for the account",
+			request:     openrtb2.BidRequest{Device: &openrtb2.Device{UA: ua}},
+		},
+		{
+			description: "device.ua is another client's",
+			account:     enabled,
+			request:     openrtb2.BidRequest{Device: &openrtb2.Device{UA: "Mozilla/5.0 (iPhone)"}},
+		},
+		{
+			description: "COPPA",
+			account:     enabled,
+			request:     openrtb2.BidRequest{Device: &openrtb2.Device{UA: ua}, Regs: &openrtb2.Regs{COPPA: 1}},
+		},
+		{
+			description: "limited ad tracking",
+			account:     enabled,
+			request:     openrtb2.BidRequest{Device: &openrtb2.Device{UA: ua, Lmt: ptrutil.ToPtr[int8](1)}},
+		},
+		{
+			description: "enrichUfpd activity denied",
+			account:     denied,
+			request:     openrtb2.BidRequest{Device: &openrtb2.Device{UA: ua}},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			httpReq := httptest.NewRequest("POST", "/openrtb2/auction", nil)
+			httpReq.Header.Set("User-Agent", ua)
+			httpReq.Header.Set("Sec-CH-UA", `"Chromium";v="118", "Google Chrome";v="118"`)
+			httpReq.Header.Set("Sec-CH-UA-Platform", `"Android"`)
+			httpReq.Header.Set("Sec-CH-UA-Platform-Version", `"13.0.0"`)
+			httpReq.Header.Set("Sec-CH-UA-Model", `"Pixel 7"`)
+
+			request := test.request
+			device := *request.Device
+			request.Device = &device
+			setClientHintsImplicitly(httpReq, &openrtb_ext.RequestWrapper{BidRequest: &request}, &test.account)
+
+			if test.expectSUA {
+				assert.NotNil(t, request.Device.SUA)
+				assert.Equal(t, "Android", request.Device.OS)
+				assert.Equal(t, "13.0.0", request.Device.OSV)
+				assert.Equal(t, "Pixel 7", request.Device.Model)
+			} else {
+				assert.Nil(t, request.Device.SUA)
+				assert.Empty(t, request.Device.Model)
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package openrtb2
+
+import (
+	"net/http"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/errortypes"
+	"github.com/prebid/prebid-server/v2/exchange"
+	"github.com/prebid/prebid-server/v2/openrtb_ext"
+	"github.com/prebid/prebid-server/v2/util/httputil"
+	"github.com/prebid/prebid-server/v2/util/iputil"
+	"github.com/prebid/prebid-server/v2/util/jsonutil"
+)
+
+// applyDebugAccess turns debug output off for the request if the account only allows it from other
+// addresses, and returns true if the request is sampled to have its debug output logged to analytics.
+// Requests whose debug output was turned on with a debug token are left as they are.
+func applyDebugAccess(httpRequest *http.Request, req *openrtb_ext.RequestWrapper, account *config.Account, debugLog *exchange.DebugLog, ipValidator iputil.IPValidator, random func() float64) (bool, error) {
+	if debugLog != nil && debugLog.DebugOverride {
+		return false, nil
+	}
+
+	if account.DebugAllow && account.DebugAccess.IsRestricted() {
+		ip, _ := httputil.FindIP(httpRequest, ipValidator)
+		if !account.DebugAccess.IsAllowed(ip, httpRequest.Header.Get(exchange.DebugOverrideHeader)) {
+			account.DebugAllow = false
+		}
+	}
+
+	if account.DebugAccess.AnalyticsSampleRate <= 0 {
+		return false, nil
+	}
+	requestExt, err := req.GetRequestExt()
+	if err != nil {
+		return false, err
+	}
+	prebid := requestExt.GetPrebid()
+	requestsDebug := req.Test == 1 || (prebid != nil && prebid.Debug)
+	if requestsDebug && account.DebugAllow {
+		// the debug output is logged to analytics anyway
+		return false, nil
+	}
+	return random() < account.DebugAccess.AnalyticsSampleRate, nil
+}
+
+// withoutDebugOutput returns a copy of the response without the debug output, and the warnings about
+// bidders which don't allow it, which were only added for analytics. If the response ext can't be read,
+// the copy has none.
+func withoutDebugOutput(response *openrtb2.BidResponse) (*openrtb2.BidResponse, error) {
+	if response == nil || len(response.Ext) == 0 {
+		return response, nil
+	}
+	stripped := *response
+	stripped.Ext = nil
+
+	var responseExt openrtb_ext.ExtBidResponse
+	if err := jsonutil.Unmarshal(response.Ext, &responseExt); err != nil {
+		return &stripped, err
+	}
+	responseExt.Debug = nil
+	for bidder, warnings := range responseExt.Warnings {
+		kept := make([]openrtb_ext.ExtBidderMessage, 0, len(warnings))
+		for _, warning := range warnings {
+			if warning.Code != errortypes.BidderLevelDebugDisabledWarningCode {
+				kept = append(kept, warning)
+			}
+		}
+		if len(kept) == 0 {
+			delete(responseExt.Warnings, bidder)
+		} else {
+			responseExt.Warnings[bidder] = kept
+		}
+	}
+
+	ext, err := jsonutil.Marshal(responseExt)
+	if err != nil {
+		return &stripped, err
+	}
+	stripped.Ext = ext
+	return &stripped, nil
+}
@@ -0,0 +1,189 @@
+package openrtb2
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/exchange"
+	"github.com/prebid/prebid-server/v2/openrtb_ext"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyDebugAccess(t *testing.T) {
+	const token = "0123456789abcdef0123456789abcdef"
+	restricted := config.AccountDebugAccess{
+		AllowedIPs:    []string{"198.51.100.0/24"},
+		AllowedTokens: []string{token},
+	}
+	sampled := config.AccountDebugAccess{AnalyticsSampleRate: 0.1}
+
+	testCases := []struct {
+		description            string
+		remoteAddr             string
+		header                 string
+		test                   int8
+		requestExt             string
+		debugAllow             bool
+		debugAccess            config.AccountDebugAccess
+		debugLog               *exchange.DebugLog
+		random                 float64
+		expectedDebugAllow     bool
+		expectedAnalyticsDebug bool
+	}{
+		{
+			description:        "unrestricted",
+			remoteAddr:         "192.0.2.1:1234",
+			debugAllow:         true,
+			expectedDebugAllow: true,
+		},
+		{
+			description:        "allowed-ip",
+			remoteAddr:         "198.51.100.7:1234",
+			debugAllow:         true,
+			debugAccess:        restricted,
+			expectedDebugAllow: true,
+		},
+		{
+			description:        "allowed-token",
+			remoteAddr:         "192.0.2.1:1234",
+			header:             token,
+			debugAllow:         true,
+			debugAccess:        restricted,
+			expectedDebugAllow: true,
+		},
+		{
+			description:        "not-allowed",
+			remoteAddr:         "192.0.2.1:1234",
+			header:             "wrong",
+			debugAllow:         true,
+			debugAccess:        restricted,
+			expectedDebugAllow: false,
+		},
+		{
+			description:        "allowed-but-account-disallows-debug",
+			remoteAddr:         "198.51.100.7:1234",
+			debugAllow:         false,
+			debugAccess:        restricted,
+			expectedDebugAllow: false,
+		},
+		{
+			description:        "debug-token-overrides-restrictions",
+			remoteAddr:         "192.0.2.1:1234",
+			debugAllow:         true,
+			debugAccess:        restricted,
+			debugLog:           &exchange.DebugLog{DebugOverride: true, DebugEnabledOrOverridden: true},
+			expectedDebugAllow: true,
+		},
+		{
+			description:            "sampled",
+			remoteAddr:             "192.0.2.1:1234",
+			debugAllow:             true,
+			debugAccess:            sampled,
+			random:                 0.05,
+			expectedDebugAllow:     true,
+			expectedAnalyticsDebug: true,
+		},
+		{
+			description:        "not-sampled",
+			remoteAddr:         "192.0.2.1:1234",
+			debugAllow:         true,
+			debugAccess:        sampled,
+			random:             0.5,
+			expectedDebugAllow: true,
+		},
+		{
+			description:        "not-sampled-when-debug-requested",
+			remoteAddr:         "192.0.2.1:1234",
+			test:               1,
+			debugAllow:         true,
+			debugAccess:        sampled,
+			random:             0.05,
+			expectedDebugAllow: true,
+		},
+		{
+			description:            "sampled-when-requested-debug-not-allowed",
+			remoteAddr:             "192.0.2.1:1234",
+			requestExt:             `{"prebid":{"debug":true}}`,
+			debugAllow:             false,
+			debugAccess:            sampled,
+			random:                 0.05,
+			expectedDebugAllow:     false,
+			expectedAnalyticsDebug: true,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			httpRequest := httptest.NewRequest("POST", "/openrtb2/auction", nil)
+			httpRequest.RemoteAddr = test.remoteAddr
+			if test.header != "" {
+				httpRequest.Header.Set(exchange.DebugOverrideHeader, test.header)
+			}
+			req := &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{Test: test.test, Ext: json.RawMessage(test.requestExt)}}
+			account := &config.Account{ID: "1001", DebugAllow: test.debugAllow, DebugAccess: test.debugAccess}
+
+			analyticsDebug, err := applyDebugAccess(httpRequest, req, account, test.debugLog, hardcodedResponseIPValidator{response: true}, func() float64 { return test.random })
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.expectedAnalyticsDebug, analyticsDebug, "analytics debug")
+			assert.Equal(t, test.expectedDebugAllow, account.DebugAllow, "debug allow")
+		})
+	}
+}
+
+func TestWithoutDebugOutput(t *testing.T) {
+	testCases := []struct {
+		description string
+		response    *openrtb2.BidResponse
+		expectedExt string
+		expectedErr bool
+	}{
+		{
+			description: "nil",
+		},
+		{
+			description: "no-ext",
+			response:    &openrtb2.BidResponse{ID: "resp"},
+		},
+		{
+			description: "debug-and-bidder-debug-warnings",
+			response: &openrtb2.BidResponse{ID: "resp", Ext: json.RawMessage(`{` +
+				`"debug":{"resolvedrequest":{"id":"req"}},` +
+				`"warnings":{"appnexus":[{"code":10003,"message":"debug turned off for bidder"}],"rubicon":[{"code":10003,"message":"debug turned off for bidder"},{"code":10999,"message":"other"}]},` +
+				`"tmaxrequest":500}`)},
+			expectedExt: `{"warnings":{"rubicon":[{"code":10999,"message":"other"}]},"tmaxrequest":500}`,
+		},
+		{
+			description: "malformed",
+			response:    &openrtb2.BidResponse{ID: "resp", Ext: json.RawMessage(`{"debug":`)},
+			expectedErr: true,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			var originalExt string
+			if test.response != nil {
+				originalExt = string(test.response.Ext)
+			}
+
+			stripped, err := withoutDebugOutput(test.response)
+
+			assert.Equal(t, test.expectedErr, err != nil)
+			if test.response == nil {
+				assert.Nil(t, stripped)
+				return
+			}
+			assert.Equal(t, test.response.ID, stripped.ID)
+			if test.expectedExt == "" {
+				assert.Empty(t, stripped.Ext)
+			} else {
+				assert.JSONEq(t, test.expectedExt, string(stripped.Ext))
+			}
+			assert.Equal(t, originalExt, string(test.response.Ext), "The response logged to analytics shouldn't change")
+		})
+	}
+}
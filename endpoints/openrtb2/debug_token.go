@@ -0,0 +1,61 @@
+package openrtb2
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/debugtoken"
+	"github.com/prebid/prebid-server/v2/errortypes"
+	"github.com/prebid/prebid-server/v2/exchange"
+	"github.com/prebid/prebid-server/v2/openrtb_ext"
+)
+
+// debugTokenHeader carries a debug token, as an alternative to ext.prebid.debugtoken.
+const debugTokenHeader = "X-Pbs-Debug-Token"
+
+// applyDebugToken turns debug output, and the trace level the token asks for, on for the request if it
+// carries a valid debug token. It returns the debug log the auction should be held with, which overrides
+// the account's and bidders' debug settings, or a warning if the token isn't valid. The token is removed
+// from the request either way, so it isn't echoed back, logged to analytics, or passed on.
+func applyDebugToken(httpRequest *http.Request, req *openrtb_ext.RequestWrapper, account *config.Account, now time.Time) (*exchange.DebugLog, error) {
+	requestExt, err := req.GetRequestExt()
+	if err != nil {
+		return nil, err
+	}
+	prebid := requestExt.GetPrebid()
+
+	token := httpRequest.Header.Get(debugTokenHeader)
+	if prebid != nil && prebid.DebugToken != "" {
+		if token == "" {
+			token = prebid.DebugToken
+		}
+		prebid.DebugToken = ""
+		requestExt.SetPrebid(prebid)
+	}
+	if token == "" {
+		return nil, nil
+	}
+
+	maxTTL := time.Duration(account.DebugToken.MaxTTLSeconds) * time.Second
+	claims, err := debugtoken.Verify(token, account.ID, account.DebugToken.Keys, maxTTL, now)
+	if err != nil {
+		return nil, &errortypes.Warning{
+			Message:     fmt.Sprintf("debug token ignored: %v", err),
+			WarningCode: errortypes.InvalidDebugTokenWarningCode,
+		}
+	}
+
+	if prebid == nil {
+		prebid = &openrtb_ext.ExtRequestPrebid{}
+	}
+	prebid.Debug = true
+	if claims.Trace != "" {
+		prebid.Trace = claims.Trace
+	}
+	requestExt.SetPrebid(prebid)
+	account.DebugAllow = true
+
+	return &exchange.DebugLog{DebugOverride: true, DebugEnabledOrOverridden: true}, nil
+}
@@ -0,0 +1,117 @@
+package openrtb2
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/debugtoken"
+	"github.com/prebid/prebid-server/v2/errortypes"
+	"github.com/prebid/prebid-server/v2/exchange"
+	"github.com/prebid/prebid-server/v2/openrtb_ext"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyDebugToken(t *testing.T) {
+	const key = "0123456789abcdef0123456789abcdef"
+	now := time.Unix(1700000000, 0)
+	sign := func(claims debugtoken.Claims) string {
+		token, err := debugtoken.Sign(claims, key)
+		require.NoError(t, err)
+		return token
+	}
+	validToken := sign(debugtoken.Claims{Account: "1001", Expires: now.Unix() + 600})
+	traceToken := sign(debugtoken.Claims{Account: "1001", Expires: now.Unix() + 600, Trace: debugtoken.TraceVerbose})
+	expiredToken := sign(debugtoken.Claims{Account: "1001", Expires: now.Unix() - 1})
+	override := &exchange.DebugLog{DebugOverride: true, DebugEnabledOrOverridden: true}
+
+	testCases := []struct {
+		description        string
+		header             string
+		requestExt         string
+		keys               []string
+		expectedDebugLog   *exchange.DebugLog
+		expectedErr        error
+		expectedRequestExt string
+		expectedDebugAllow bool
+	}{
+		{
+			description:        "no-token",
+			requestExt:         `{"prebid":{"debug":true}}`,
+			keys:               []string{key},
+			expectedRequestExt: `{"prebid":{"debug":true}}`,
+		},
+		{
+			description:        "valid-token-in-header",
+			header:             validToken,
+			keys:               []string{key},
+			expectedDebugLog:   override,
+			expectedRequestExt: `{"prebid":{"debug":true}}`,
+			expectedDebugAllow: true,
+		},
+		{
+			description:        "valid-token-in-ext",
+			requestExt:         `{"prebid":{"debugtoken":"` + traceToken + `"}}`,
+			keys:               []string{key},
+			expectedDebugLog:   override,
+			expectedRequestExt: `{"prebid":{"debug":true,"trace":"verbose"}}`,
+			expectedDebugAllow: true,
+		},
+		{
+			description:        "header-takes-precedence-over-ext",
+			header:             validToken,
+			requestExt:         `{"prebid":{"debugtoken":"` + expiredToken + `"}}`,
+			keys:               []string{key},
+			expectedDebugLog:   override,
+			expectedRequestExt: `{"prebid":{"debug":true}}`,
+			expectedDebugAllow: true,
+		},
+		{
+			description: "expired-token",
+			requestExt:  `{"prebid":{"debugtoken":"` + expiredToken + `"}}`,
+			keys:        []string{key},
+			expectedErr: &errortypes.Warning{
+				Message:     "debug token ignored: the token expired at 2023-11-14T22:13:19Z",
+				WarningCode: errortypes.InvalidDebugTokenWarningCode,
+			},
+		},
+		{
+			description: "account-without-keys",
+			header:      validToken,
+			expectedErr: &errortypes.Warning{
+				Message:     "debug token ignored: the token's signature doesn't match any of the account's keys",
+				WarningCode: errortypes.InvalidDebugTokenWarningCode,
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			httpRequest := httptest.NewRequest("POST", "/openrtb2/auction", nil)
+			if test.header != "" {
+				httpRequest.Header.Set(debugTokenHeader, test.header)
+			}
+			req := &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{ID: "req", Ext: json.RawMessage(test.requestExt)}}
+			account := &config.Account{
+				ID:         "1001",
+				DebugToken: config.AccountDebugToken{Keys: test.keys, MaxTTLSeconds: 3600},
+			}
+
+			debugLog, err := applyDebugToken(httpRequest, req, account, now)
+			assert.Equal(t, test.expectedErr, err)
+			assert.Equal(t, test.expectedDebugLog, debugLog)
+			assert.Equal(t, test.expectedDebugAllow, account.DebugAllow)
+
+			require.NoError(t, req.RebuildRequest())
+			if test.expectedRequestExt == "" {
+				assert.Empty(t, req.Ext)
+			} else {
+				assert.JSONEq(t, test.expectedRequestExt, string(req.Ext))
+			}
+		})
+	}
+}
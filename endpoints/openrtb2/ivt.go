@@ -0,0 +1,64 @@
+package openrtb2
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/errortypes"
+	"github.com/prebid/prebid-server/v2/metrics"
+	"github.com/prebid/prebid-server/v2/openrtb_ext"
+	"github.com/prebid/prebid-server/v2/util/jsonutil"
+)
+
+// ivtExtKey is the member of device.ext which has the reasons a tagged request is invalid traffic.
+const ivtExtKey = "ivt"
+
+type ivtTag struct {
+	Reasons []metrics.IVTReason `json:"reasons"`
+	// Datacenter is the hosting provider of a request from a datacenter, if the datacenter list names it
+	Datacenter string `json:"datacenter,omitempty"`
+}
+
+// screenInvalidTraffic checks the request's device for invalid traffic. Requests found to be invalid traffic
+// are rejected, or tagged with the reasons in device.ext.ivt so bidders and analytics can tell them apart,
+// depending on the account's ivt.action, or on the host's if the account doesn't set one.
+func (deps *endpointDeps) screenInvalidTraffic(req *openrtb_ext.RequestWrapper, account *config.Account) error {
+	reasons := deps.ivtFilter.Check(req.Device)
+	if len(reasons) == 0 {
+		return nil
+	}
+
+	configuredAction := deps.cfg.IVT.Action
+	if account != nil && account.IVT.Action != "" {
+		configuredAction = account.IVT.Action
+	}
+	action := metrics.IVTTagged
+	if configuredAction == config.IVTActionBlock {
+		action = metrics.IVTBlocked
+	}
+	for _, reason := range reasons {
+		deps.metricsEngine.RecordIVT(reason, action)
+	}
+
+	if action == metrics.IVTBlocked {
+		names := make([]string, len(reasons))
+		for i, reason := range reasons {
+			names[i] = string(reason)
+		}
+		return &errortypes.InvalidTraffic{Message: fmt.Sprintf("Prebid-server does not process invalid traffic: %s", strings.Join(names, ", "))}
+	}
+
+	deviceExt, err := req.GetDeviceExt()
+	if err != nil {
+		return err
+	}
+	tag, err := jsonutil.Marshal(ivtTag{Reasons: reasons, Datacenter: deps.ivtFilter.Datacenter(req.Device)})
+	if err != nil {
+		return err
+	}
+	ext := deviceExt.GetExt()
+	ext[ivtExtKey] = tag
+	deviceExt.SetExt(ext)
+	return nil
+}
@@ -0,0 +1,113 @@
+package openrtb2
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/errortypes"
+	"github.com/prebid/prebid-server/v2/ivt"
+	"github.com/prebid/prebid-server/v2/metrics"
+	"github.com/prebid/prebid-server/v2/openrtb_ext"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScreenInvalidTraffic(t *testing.T) {
+	testCases := []struct {
+		description       string
+		action            string
+		accountAction     string
+		device            *openrtb2.Device
+		expectedErr       error
+		expectedDeviceExt json.RawMessage
+		expectedMetrics   []metrics.IVTReason
+	}{
+		{
+			description: "valid traffic",
+			action:      config.IVTActionBlock,
+			device:      &openrtb2.Device{UA: "Mozilla/5.0", IP: "192.0.2.1"},
+		},
+		{
+			description:     "blocked",
+			action:          config.IVTActionBlock,
+			device:          &openrtb2.Device{UA: "Googlebot/2.1", IP: "203.0.113.7"},
+			expectedErr:     &errortypes.InvalidTraffic{Message: "Prebid-server does not process invalid traffic: bot_ua, blocked_ip"},
+			expectedMetrics: []metrics.IVTReason{metrics.IVTBotUserAgent, metrics.IVTBlockedIP},
+		},
+		{
+			description:       "tagged",
+			action:            config.IVTActionTag,
+			device:            &openrtb2.Device{UA: "Mozilla/5.0", IP: "203.0.113.7", Ext: json.RawMessage(`{"atts":1}`)},
+			expectedDeviceExt: json.RawMessage(`{"atts":1,"ivt":{"reasons":["blocked_ip"]}}`),
+			expectedMetrics:   []metrics.IVTReason{metrics.IVTBlockedIP},
+		},
+		{
+			description:     "blocked by account",
+			action:          config.IVTActionTag,
+			accountAction:   config.IVTActionBlock,
+			device:          &openrtb2.Device{UA: "Mozilla/5.0", IP: "203.0.113.7"},
+			expectedErr:     &errortypes.InvalidTraffic{Message: "Prebid-server does not process invalid traffic: blocked_ip"},
+			expectedMetrics: []metrics.IVTReason{metrics.IVTBlockedIP},
+		},
+		{
+			description:       "tagged by account",
+			action:            config.IVTActionBlock,
+			accountAction:     config.IVTActionTag,
+			device:            &openrtb2.Device{UA: "Mozilla/5.0", IP: "203.0.113.7"},
+			expectedDeviceExt: json.RawMessage(`{"ivt":{"reasons":["blocked_ip"]}}`),
+			expectedMetrics:   []metrics.IVTReason{metrics.IVTBlockedIP},
+		},
+		{
+			description:       "datacenter tagged with its provider",
+			action:            config.IVTActionTag,
+			device:            &openrtb2.Device{UA: "Mozilla/5.0", IP: "198.51.100.20"},
+			expectedDeviceExt: json.RawMessage(`{"ivt":{"reasons":["datacenter_ip"],"datacenter":"example-cloud"}}`),
+			expectedMetrics:   []metrics.IVTReason{metrics.IVTDatacenterIP},
+		},
+	}
+
+	datacenterIPsFile := filepath.Join(t.TempDir(), "datacenters.csv")
+	require.NoError(t, os.WriteFile(datacenterIPsFile, []byte("198.51.100.0/24,example-cloud\n"), 0600))
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			cfg := &config.Configuration{IVT: config.IVT{Enabled: true, Action: test.action, Bots: config.IVTBots{Builtin: true}, BlockedIPs: []string{"203.0.113.7"}, DatacenterIPsFile: datacenterIPsFile}}
+			filter, err := ivt.NewFilter(cfg.IVT)
+			require.NoError(t, err)
+
+			metricsEngine := &metrics.MetricsEngineMock{}
+			account := &config.Account{IVT: config.AccountIVT{Action: test.accountAction}}
+			configuredAction := test.action
+			if test.accountAction != "" {
+				configuredAction = test.accountAction
+			}
+			expectedAction := metrics.IVTTagged
+			if configuredAction == config.IVTActionBlock {
+				expectedAction = metrics.IVTBlocked
+			}
+			for _, reason := range test.expectedMetrics {
+				metricsEngine.On("RecordIVT", reason, expectedAction).Once()
+			}
+			deps := &endpointDeps{cfg: cfg, metricsEngine: metricsEngine, ivtFilter: filter}
+
+			req := &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{Device: test.device}}
+			err = deps.screenInvalidTraffic(req, account)
+			assert.Equal(t, test.expectedErr, err)
+			require.NoError(t, req.RebuildRequest())
+			if test.expectedDeviceExt != nil {
+				assert.JSONEq(t, string(test.expectedDeviceExt), string(req.Device.Ext))
+			}
+			metricsEngine.AssertExpectations(t)
+		})
+	}
+}
+
+func TestScreenInvalidTrafficWithoutFilter(t *testing.T) {
+	deps := &endpointDeps{cfg: &config.Configuration{}, metricsEngine: &metrics.MetricsEngineMock{}}
+	req := &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{Device: &openrtb2.Device{UA: "Googlebot/2.1"}}}
+	assert.NoError(t, deps.screenInvalidTraffic(req, nil))
+}
@@ -0,0 +1,139 @@
+package openrtb2
+
+import (
+	"strings"
+
+	"github.com/prebid/openrtb/v20/adcom1"
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/openrtb_ext"
+	"github.com/prebid/prebid-server/v2/util/ptrutil"
+	"github.com/prebid/prebid-server/v2/util/versionutil"
+)
+
+// mobileSDKSource is app.ext.prebid.source in requests from the Prebid Mobile SDK
+const mobileSDKSource = "prebid-mobile"
+
+// zeroIFA is the advertising ID iOS gives apps the user hasn't authorized to track them
+const zeroIFA = "00000000-0000-0000-0000-000000000000"
+
+// processMobileSDK fills in and normalizes the signals of requests from the Prebid Mobile SDK, for the features
+// the host turns on for the SDK's version. It runs before interstitials are sized and the LMT flag is set for iOS,
+// which then see what it filled in.
+func processMobileSDK(req *openrtb_ext.RequestWrapper, cfg config.MobileSDK) error {
+	if req.App == nil || !(cfg.Interstitial.Enabled || cfg.ATT.Enabled || cfg.Multiformat.Enabled) {
+		return nil
+	}
+	appExt, err := req.GetAppExt()
+	if err != nil {
+		return err
+	}
+	prebid := appExt.GetPrebid()
+	if prebid == nil || !strings.EqualFold(prebid.Source, mobileSDKSource) {
+		return nil
+	}
+	version, versionErr := versionutil.Parse(prebid.Version)
+	enabled := func(feature config.MobileSDKFeature) bool {
+		if !feature.Enabled || feature.MinVersion == "" {
+			return feature.Enabled
+		}
+		minVersion, err := versionutil.Parse(feature.MinVersion)
+		return err == nil && versionErr == nil && version.AtLeast(minVersion)
+	}
+
+	if enabled(cfg.Multiformat) {
+		for _, imp := range req.GetImp() {
+			normalizeMultiformat(imp.Imp)
+		}
+	}
+	if enabled(cfg.Interstitial.MobileSDKFeature) {
+		if err := setInterstitialSizing(req, cfg.Interstitial.MinSizePerc); err != nil {
+			return err
+		}
+	}
+	if enabled(cfg.ATT) {
+		applyATT(req.Device)
+	}
+	return nil
+}
+
+// normalizeMultiformat fills in the banner and video of an impression which can serve either from each other, since
+// the SDK sends the size of the ad view in only one of them.
+func normalizeMultiformat(imp *openrtb2.Imp) {
+	if imp.Banner == nil || imp.Video == nil {
+		return
+	}
+	banner, video := imp.Banner, imp.Video
+	if len(banner.Format) == 0 && banner.W != nil && banner.H != nil {
+		banner.Format = []openrtb2.Format{{W: *banner.W, H: *banner.H}}
+	}
+	if (video.W == nil || *video.W == 0) && (video.H == nil || *video.H == 0) {
+		if len(banner.Format) > 0 {
+			video.W = ptrutil.ToPtr(banner.Format[0].W)
+			video.H = ptrutil.ToPtr(banner.Format[0].H)
+		}
+	} else if len(banner.Format) == 0 && banner.W == nil && banner.H == nil && video.W != nil && video.H != nil {
+		banner.Format = []openrtb2.Format{{W: *video.W, H: *video.H}}
+	}
+	if video.Plcmt == 0 {
+		// The video plays in the ad view without other content, which takes over the screen for interstitials.
+		video.Plcmt = adcom1.VideoPlcmtNoContent
+		if imp.Instl == 1 {
+			video.Plcmt = adcom1.VideoPlcmtInterstitial
+		}
+	}
+}
+
+// setInterstitialSizing asks for interstitials to be sized from the device's screen, if the request doesn't say
+// how itself and has interstitial banners without a size.
+func setInterstitialSizing(req *openrtb_ext.RequestWrapper, minSizePerc int64) error {
+	if req.Device == nil || req.Device.W == 0 || req.Device.H == 0 {
+		return nil
+	}
+	unsized := false
+	for _, imp := range req.GetImp() {
+		if imp.Instl == 1 && imp.Banner != nil && (len(imp.Banner.Format) == 0 || (imp.Banner.Format[0].W < 2 && imp.Banner.Format[0].H < 2)) {
+			unsized = true
+			break
+		}
+	}
+	if !unsized {
+		return nil
+	}
+
+	deviceExt, err := req.GetDeviceExt()
+	if err != nil {
+		return err
+	}
+	prebid := deviceExt.GetPrebid()
+	if prebid == nil {
+		prebid = &openrtb_ext.ExtDevicePrebid{}
+	} else if prebid.Interstitial != nil {
+		return nil
+	}
+	prebid.Interstitial = &openrtb_ext.ExtDeviceInt{MinWidthPerc: minSizePerc, MinHeightPerc: minSizePerc}
+	deviceExt.SetPrebid(prebid)
+	return nil
+}
+
+// applyATT applies the iOS App Tracking Transparency status in device.ext.atts. The advertising ID is only the
+// user's if they authorized tracking, so it's removed otherwise, and LMT is set from the status. Unlike
+// lmt.ModifyForIOS, this covers iPadOS and every OS version which has the status.
+func applyATT(device *openrtb2.Device) {
+	if device == nil || !(strings.EqualFold(device.OS, "ios") || strings.EqualFold(device.OS, "ipados")) {
+		return
+	}
+	if device.IFA == zeroIFA {
+		device.IFA = ""
+	}
+	atts, err := openrtb_ext.ParseDeviceExtATTS(device.Ext)
+	if err != nil || atts == nil {
+		return
+	}
+	if *atts == openrtb_ext.IOSAppTrackingStatusAuthorized {
+		device.Lmt = ptrutil.ToPtr[int8](0)
+		return
+	}
+	device.IFA = ""
+	device.Lmt = ptrutil.ToPtr[int8](1)
+}
@@ -0,0 +1,239 @@
+package openrtb2
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/prebid/openrtb/v20/adcom1"
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/openrtb_ext"
+	"github.com/prebid/prebid-server/v2/util/ptrutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProcessMobileSDK(t *testing.T) {
+	allEnabled := config.MobileSDK{
+		Interstitial: config.MobileSDKInterstitial{MobileSDKFeature: config.MobileSDKFeature{Enabled: true}, MinSizePerc: 50},
+		ATT:          config.MobileSDKFeature{Enabled: true},
+		Multiformat:  config.MobileSDKFeature{Enabled: true},
+	}
+
+	testCases := []struct {
+		description       string
+		cfg               config.MobileSDK
+		appExt            string
+		expectedProcessed bool
+	}{
+		{
+			description:       "SDK request",
+			cfg:               allEnabled,
+			appExt:            `{"prebid":{"source":"prebid-mobile","version":"2.1.0"}}`,
+			expectedProcessed: true,
+		},
+		{
+			description: "not from the SDK",
+			cfg:         allEnabled,
+			appExt:      `{"prebid":{"source":"other-sdk","version":"2.1.0"}}`,
+		},
+		{
+			description: "no app ext",
+			cfg:         allEnabled,
+		},
+		{
+			description: "features off",
+			appExt:      `{"prebid":{"source":"prebid-mobile","version":"2.1.0"}}`,
+		},
+		{
+			description: "SDK older than the features' min version",
+			cfg: config.MobileSDK{
+				Interstitial: config.MobileSDKInterstitial{MobileSDKFeature: config.MobileSDKFeature{Enabled: true, MinVersion: "2.2"}, MinSizePerc: 50},
+				ATT:          config.MobileSDKFeature{Enabled: true, MinVersion: "2.2"},
+				Multiformat:  config.MobileSDKFeature{Enabled: true, MinVersion: "2.2"},
+			},
+			appExt: `{"prebid":{"source":"prebid-mobile","version":"2.1.0"}}`,
+		},
+		{
+			description: "SDK version which can't be compared with the features' min version",
+			cfg: config.MobileSDK{
+				ATT:         config.MobileSDKFeature{Enabled: true, MinVersion: "2.0"},
+				Multiformat: config.MobileSDKFeature{Enabled: true, MinVersion: "2.0"},
+			},
+			appExt: `{"prebid":{"source":"prebid-mobile","version":"latest"}}`,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			req := &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{
+				App: &openrtb2.App{ID: "app"},
+				Imp: []openrtb2.Imp{
+					{ID: "multiformat", Banner: &openrtb2.Banner{Format: []openrtb2.Format{{W: 320, H: 50}}}, Video: &openrtb2.Video{MIMEs: []string{"video/mp4"}}},
+					{ID: "interstitial", Instl: 1, Banner: &openrtb2.Banner{}},
+				},
+				Device: &openrtb2.Device{OS: "iOS", W: 390, H: 844, IFA: zeroIFA, Ext: json.RawMessage(`{"atts":2}`)},
+			}}
+			if test.appExt != "" {
+				req.App.Ext = json.RawMessage(test.appExt)
+			}
+
+			require.NoError(t, processMobileSDK(req, test.cfg))
+			require.NoError(t, req.RebuildRequest())
+
+			multiformat := req.Imp[0]
+			if test.expectedProcessed {
+				assert.Equal(t, ptrutil.ToPtr[int64](320), multiformat.Video.W)
+				assert.Equal(t, adcom1.VideoPlcmtNoContent, multiformat.Video.Plcmt)
+				assert.JSONEq(t, `{"atts":2,"prebid":{"interstitial":{"minwidthperc":50,"minheightperc":50}}}`, string(req.Device.Ext))
+				assert.Empty(t, req.Device.IFA)
+				assert.Equal(t, ptrutil.ToPtr[int8](1), req.Device.Lmt)
+			} else {
+				assert.Nil(t, multiformat.Video.W)
+				assert.Zero(t, multiformat.Video.Plcmt)
+				assert.JSONEq(t, `{"atts":2}`, string(req.Device.Ext))
+				assert.Equal(t, zeroIFA, req.Device.IFA)
+				assert.Nil(t, req.Device.Lmt)
+			}
+		})
+	}
+}
+
+func TestNormalizeMultiformat(t *testing.T) {
+	testCases := []struct {
+		description string
+		imp         openrtb2.Imp
+		expected    openrtb2.Imp
+	}{
+		{
+			description: "video sized from the banner",
+			imp:         openrtb2.Imp{Banner: &openrtb2.Banner{W: ptrutil.ToPtr[int64](300), H: ptrutil.ToPtr[int64](250)}, Video: &openrtb2.Video{}},
+			expected: openrtb2.Imp{
+				Banner: &openrtb2.Banner{W: ptrutil.ToPtr[int64](300), H: ptrutil.ToPtr[int64](250), Format: []openrtb2.Format{{W: 300, H: 250}}},
+				Video:  &openrtb2.Video{W: ptrutil.ToPtr[int64](300), H: ptrutil.ToPtr[int64](250), Plcmt: adcom1.VideoPlcmtNoContent},
+			},
+		},
+		{
+			description: "banner sized from the video",
+			imp:         openrtb2.Imp{Instl: 1, Banner: &openrtb2.Banner{}, Video: &openrtb2.Video{W: ptrutil.ToPtr[int64](320), H: ptrutil.ToPtr[int64](480)}},
+			expected: openrtb2.Imp{
+				Instl:  1,
+				Banner: &openrtb2.Banner{Format: []openrtb2.Format{{W: 320, H: 480}}},
+				Video:  &openrtb2.Video{W: ptrutil.ToPtr[int64](320), H: ptrutil.ToPtr[int64](480), Plcmt: adcom1.VideoPlcmtInterstitial},
+			},
+		},
+		{
+			description: "sizes and placement kept",
+			imp: openrtb2.Imp{
+				Banner: &openrtb2.Banner{Format: []openrtb2.Format{{W: 300, H: 250}}},
+				Video:  &openrtb2.Video{W: ptrutil.ToPtr[int64](640), H: ptrutil.ToPtr[int64](360), Plcmt: adcom1.VideoPlcmtAccompanyingContent},
+			},
+			expected: openrtb2.Imp{
+				Banner: &openrtb2.Banner{Format: []openrtb2.Format{{W: 300, H: 250}}},
+				Video:  &openrtb2.Video{W: ptrutil.ToPtr[int64](640), H: ptrutil.ToPtr[int64](360), Plcmt: adcom1.VideoPlcmtAccompanyingContent},
+			},
+		},
+		{
+			description: "banner only",
+			imp:         openrtb2.Imp{Banner: &openrtb2.Banner{W: ptrutil.ToPtr[int64](300), H: ptrutil.ToPtr[int64](250)}},
+			expected:    openrtb2.Imp{Banner: &openrtb2.Banner{W: ptrutil.ToPtr[int64](300), H: ptrutil.ToPtr[int64](250)}},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			normalizeMultiformat(&test.imp)
+			assert.Equal(t, test.expected, test.imp)
+		})
+	}
+}
+
+func TestSetInterstitialSizing(t *testing.T) {
+	testCases := []struct {
+		description string
+		request     *openrtb2.BidRequest
+		expectedExt string
+	}{
+		{
+			description: "1x1 interstitial",
+			request: &openrtb2.BidRequest{
+				Imp:    []openrtb2.Imp{{Instl: 1, Banner: &openrtb2.Banner{Format: []openrtb2.Format{{W: 1, H: 1}}}}},
+				Device: &openrtb2.Device{W: 390, H: 844, Ext: json.RawMessage(`{"prebid":{}}`)},
+			},
+			expectedExt: `{"prebid":{"interstitial":{"minwidthperc":50,"minheightperc":50}}}`,
+		},
+		{
+			description: "sizing in the request kept",
+			request: &openrtb2.BidRequest{
+				Imp:    []openrtb2.Imp{{Instl: 1, Banner: &openrtb2.Banner{}}},
+				Device: &openrtb2.Device{W: 390, H: 844, Ext: json.RawMessage(`{"prebid":{"interstitial":{"minwidthperc":60,"minheightperc":70}}}`)},
+			},
+			expectedExt: `{"prebid":{"interstitial":{"minwidthperc":60,"minheightperc":70}}}`,
+		},
+		{
+			description: "sized interstitial",
+			request: &openrtb2.BidRequest{
+				Imp:    []openrtb2.Imp{{Instl: 1, Banner: &openrtb2.Banner{Format: []openrtb2.Format{{W: 320, H: 480}}}}},
+				Device: &openrtb2.Device{W: 390, H: 844},
+			},
+		},
+		{
+			description: "no screen size",
+			request: &openrtb2.BidRequest{
+				Imp:    []openrtb2.Imp{{Instl: 1, Banner: &openrtb2.Banner{}}},
+				Device: &openrtb2.Device{},
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			req := &openrtb_ext.RequestWrapper{BidRequest: test.request}
+			assert.NoError(t, setInterstitialSizing(req, 50))
+			assert.NoError(t, req.RebuildRequest())
+			if test.expectedExt == "" {
+				assert.Empty(t, req.Device.Ext)
+			} else {
+				assert.JSONEq(t, test.expectedExt, string(req.Device.Ext))
+			}
+		})
+	}
+}
+
+func TestApplyATT(t *testing.T) {
+	const ifa = "6D92078A-8246-4BA4-AE5B-76104861E7DC"
+
+	testCases := []struct {
+		description string
+		device      *openrtb2.Device
+		expected    *openrtb2.Device
+	}{
+		{
+			description: "authorized",
+			device:      &openrtb2.Device{OS: "iOS", IFA: ifa, Ext: json.RawMessage(`{"atts":3}`)},
+			expected:    &openrtb2.Device{OS: "iOS", IFA: ifa, Lmt: ptrutil.ToPtr[int8](0), Ext: json.RawMessage(`{"atts":3}`)},
+		},
+		{
+			description: "denied on iPadOS",
+			device:      &openrtb2.Device{OS: "iPadOS", IFA: ifa, Ext: json.RawMessage(`{"atts":2}`)},
+			expected:    &openrtb2.Device{OS: "iPadOS", Lmt: ptrutil.ToPtr[int8](1), Ext: json.RawMessage(`{"atts":2}`)},
+		},
+		{
+			description: "no status with a zero ID",
+			device:      &openrtb2.Device{OS: "ios", IFA: zeroIFA},
+			expected:    &openrtb2.Device{OS: "ios"},
+		},
+		{
+			description: "android",
+			device:      &openrtb2.Device{OS: "android", IFA: zeroIFA, Ext: json.RawMessage(`{"atts":2}`)},
+			expected:    &openrtb2.Device{OS: "android", IFA: zeroIFA, Ext: json.RawMessage(`{"atts":2}`)},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			applyATT(test.device)
+			assert.Equal(t, test.expected, test.device)
+		})
+	}
+}
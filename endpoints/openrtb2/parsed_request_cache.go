@@ -0,0 +1,163 @@
+package openrtb2
+
+import (
+	"bytes"
+	"container/list"
+	"encoding/binary"
+	"hash/maphash"
+	"sync"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/ortb"
+	"github.com/prebid/prebid-server/v2/util/jsonutil"
+)
+
+// parsedRequestCache holds stored requests in the form they take after they've been merged with the
+// incoming request or unmarshaled, so that repeated auctions for the same ad unit skip that work.
+//
+// Entries are keyed by the content of their inputs rather than by stored request ID, so an update to a
+// stored request can never be served stale: the new content simply misses the cache, and the old entry
+// ages out. A nil *parsedRequestCache is valid and caches nothing.
+type parsedRequestCache struct {
+	seed       maphash.Seed
+	maxEntries int
+
+	lock    sync.Mutex
+	entries map[uint64]*list.Element
+	lru     *list.List
+}
+
+type parsedRequestCacheEntry struct {
+	hash   uint64
+	inputs [][]byte
+	value  interface{}
+}
+
+// newParsedRequestCache returns a cache configured by cfg, or nil if the cache is disabled.
+func newParsedRequestCache(cfg config.ParsedStoredRequestCache) *parsedRequestCache {
+	if !cfg.Enabled {
+		return nil
+	}
+	return &parsedRequestCache{
+		seed:       maphash.MakeSeed(),
+		maxEntries: cfg.MaxEntries,
+		entries:    make(map[uint64]*list.Element, cfg.MaxEntries),
+		lru:        list.New(),
+	}
+}
+
+// mergePatch returns the result of jsonutil.MergeClone(doc, patch). The result may be shared with
+// other callers, so it must not be modified in place. Its capacity is capped at its length, so
+// appending to it, as jsonparser.Set does, always copies.
+func (c *parsedRequestCache) mergePatch(doc, patch []byte) ([]byte, error) {
+	if c == nil {
+		return jsonutil.MergeClone(doc, patch)
+	}
+
+	hash := c.hash(doc, patch)
+	if value, ok := c.get(hash, doc, patch); ok {
+		return value.([]byte), nil
+	}
+
+	merged, err := jsonutil.MergeClone(doc, patch)
+	if err != nil {
+		return nil, err
+	}
+	merged = merged[:len(merged):len(merged)]
+	c.put(hash, merged, doc, patch)
+	return merged, nil
+}
+
+// unmarshalRequest returns the bid request held by requestJSON. The caller owns the returned request
+// and is free to modify it.
+func (c *parsedRequestCache) unmarshalRequest(requestJSON []byte) (*openrtb2.BidRequest, error) {
+	if c == nil {
+		req := &openrtb2.BidRequest{}
+		if err := jsonutil.UnmarshalValid(requestJSON, req); err != nil {
+			return nil, err
+		}
+		return req, nil
+	}
+
+	hash := c.hash(requestJSON)
+	if value, ok := c.get(hash, requestJSON); ok {
+		return ortb.CloneBidRequest(value.(*openrtb2.BidRequest)), nil
+	}
+
+	req := &openrtb2.BidRequest{}
+	if err := jsonutil.UnmarshalValid(requestJSON, req); err != nil {
+		return nil, err
+	}
+	c.put(hash, ortb.CloneBidRequest(req), requestJSON)
+	return req, nil
+}
+
+func (c *parsedRequestCache) hash(inputs ...[]byte) uint64 {
+	var h maphash.Hash
+	h.SetSeed(c.seed)
+	for _, input := range inputs {
+		// The length prefix keeps ("ab", "c") and ("a", "bc") apart.
+		var length [8]byte
+		binary.LittleEndian.PutUint64(length[:], uint64(len(input)))
+		h.Write(length[:])
+		h.Write(input)
+	}
+	return h.Sum64()
+}
+
+func (c *parsedRequestCache) get(hash uint64, inputs ...[]byte) (interface{}, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	element, ok := c.entries[hash]
+	if !ok {
+		return nil, false
+	}
+	entry := element.Value.(*parsedRequestCacheEntry)
+	if !sameInputs(entry.inputs, inputs) {
+		return nil, false
+	}
+	c.lru.MoveToFront(element)
+	return entry.value, true
+}
+
+func (c *parsedRequestCache) put(hash uint64, value interface{}, inputs ...[]byte) {
+	// The inputs are kept to rule out hash collisions. They're copied because callers may reuse
+	// the buffers they were read into.
+	entry := &parsedRequestCacheEntry{
+		hash:   hash,
+		inputs: make([][]byte, len(inputs)),
+		value:  value,
+	}
+	for i, input := range inputs {
+		entry.inputs[i] = bytes.Clone(input)
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if element, ok := c.entries[hash]; ok {
+		element.Value = entry
+		c.lru.MoveToFront(element)
+		return
+	}
+	c.entries[hash] = c.lru.PushFront(entry)
+	for c.lru.Len() > c.maxEntries {
+		oldest := c.lru.Back()
+		c.lru.Remove(oldest)
+		delete(c.entries, oldest.Value.(*parsedRequestCacheEntry).hash)
+	}
+}
+
+func sameInputs(a, b [][]byte) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !bytes.Equal(a[i], b[i]) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,136 @@
+package openrtb2
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/buger/jsonparser"
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewParsedRequestCache(t *testing.T) {
+	assert.Nil(t, newParsedRequestCache(config.ParsedStoredRequestCache{Enabled: false, MaxEntries: 10}))
+	assert.NotNil(t, newParsedRequestCache(config.ParsedStoredRequestCache{Enabled: true, MaxEntries: 10}))
+}
+
+func TestParsedRequestCacheMergePatch(t *testing.T) {
+	testCases := []struct {
+		name  string
+		cache *parsedRequestCache
+	}{
+		{
+			name:  "disabled",
+			cache: nil,
+		},
+		{
+			name:  "enabled",
+			cache: newParsedRequestCache(config.ParsedStoredRequestCache{Enabled: true, MaxEntries: 10}),
+		},
+	}
+
+	storedImp := []byte(`{"id":"stored","banner":{"format":[{"w":300,"h":250}]}}`)
+	incomingImp := []byte(`{"id":"imp1","ext":{"prebid":{"storedrequest":{"id":"1"}}}}`)
+	expected := `{"id":"imp1","banner":{"format":[{"w":300,"h":250}]},"ext":{"prebid":{"storedrequest":{"id":"1"}}}}`
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			for i := 0; i < 2; i++ {
+				merged, err := test.cache.mergePatch(storedImp, incomingImp)
+				require.NoError(t, err)
+				assert.JSONEq(t, expected, string(merged))
+
+				// the caller's changes must not leak into the cached result
+				_, err = jsonparser.Set(merged, []byte(`"changed"`), "id")
+				require.NoError(t, err)
+			}
+
+			_, err := test.cache.mergePatch(storedImp, []byte(`{malformed`))
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestParsedRequestCacheMergePatchKeyedByContent(t *testing.T) {
+	cache := newParsedRequestCache(config.ParsedStoredRequestCache{Enabled: true, MaxEntries: 10})
+
+	merged, err := cache.mergePatch([]byte(`{"a":1}`), []byte(`{"b":2}`))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"a":1,"b":2}`, string(merged))
+
+	// an updated stored imp is merged again rather than served from the cache
+	merged, err = cache.mergePatch([]byte(`{"a":3}`), []byte(`{"b":2}`))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"a":3,"b":2}`, string(merged))
+
+	// reused input buffers don't change the cached entry
+	doc := []byte(`{"c":1}`)
+	_, err = cache.mergePatch(doc, []byte(`{}`))
+	require.NoError(t, err)
+	copy(doc, `{"c":2}`)
+	merged, err = cache.mergePatch(doc, []byte(`{}`))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"c":2}`, string(merged))
+}
+
+func TestParsedRequestCacheUnmarshalRequest(t *testing.T) {
+	cache := newParsedRequestCache(config.ParsedStoredRequestCache{Enabled: true, MaxEntries: 10})
+	requestJSON := []byte(`{"id":"req1","imp":[{"id":"imp1","secure":0,"banner":{"format":[{"w":300,"h":250}]}}],"site":{"page":"prebid.org"}}`)
+
+	first, err := cache.unmarshalRequest(requestJSON)
+	require.NoError(t, err)
+	*first.Imp[0].Secure = 1
+	first.Imp[0].Banner.Format[0].W = 728
+	first.Site.Page = "changed"
+
+	second, err := cache.unmarshalRequest(requestJSON)
+	require.NoError(t, err)
+	assert.Equal(t, "req1", second.ID)
+	assert.Equal(t, int8(0), *second.Imp[0].Secure)
+	assert.Equal(t, int64(300), second.Imp[0].Banner.Format[0].W)
+	assert.Equal(t, "prebid.org", second.Site.Page)
+
+	_, err = cache.unmarshalRequest([]byte(`{"id":`))
+	assert.Error(t, err)
+
+	var nilCache *parsedRequestCache
+	req, err := nilCache.unmarshalRequest(requestJSON)
+	require.NoError(t, err)
+	assert.Equal(t, "req1", req.ID)
+}
+
+func TestParsedRequestCacheEviction(t *testing.T) {
+	cache := newParsedRequestCache(config.ParsedStoredRequestCache{Enabled: true, MaxEntries: 2})
+
+	for _, doc := range []string{`{"a":1}`, `{"a":2}`, `{"a":3}`} {
+		_, err := cache.mergePatch([]byte(doc), []byte(`{}`))
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 2, cache.lru.Len())
+	assert.Len(t, cache.entries, 2)
+
+	_, ok := cache.get(cache.hash([]byte(`{"a":1}`), []byte(`{}`)), []byte(`{"a":1}`), []byte(`{}`))
+	assert.False(t, ok, "least recently used entry should be evicted")
+	_, ok = cache.get(cache.hash([]byte(`{"a":3}`), []byte(`{}`)), []byte(`{"a":3}`), []byte(`{}`))
+	assert.True(t, ok)
+}
+
+func BenchmarkParsedRequestCacheUnmarshalRequest(b *testing.B) {
+	requestJSON := json.RawMessage(`{"id":"{{UUID}}","imp":[{"id":"imp1","banner":{"format":[{"w":300,"h":250},{"w":300,"h":600}]},"ext":{"prebid":{"bidder":{"appnexus":{"placementId":12883451},"rubicon":{"accountId":1001,"siteId":113932,"zoneId":535510}}}}}],"site":{"page":"prebid.org","publisher":{"id":"1001"}},"tmax":500,"ext":{"prebid":{"targeting":{"pricegranularity":"medium"},"cache":{"bids":{}}}}}`)
+
+	for _, cache := range []*parsedRequestCache{nil, newParsedRequestCache(config.ParsedStoredRequestCache{Enabled: true, MaxEntries: 10})} {
+		name := "disabled"
+		if cache != nil {
+			name = "enabled"
+		}
+		b.Run(name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := cache.unmarshalRequest(requestJSON); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package openrtb2
+
+import (
+	"fmt"
+
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/errortypes"
+	"github.com/prebid/prebid-server/v2/metrics"
+	"github.com/prebid/prebid-server/v2/openrtb_ext"
+)
+
+// checkRequestSize rejects a request whose body is larger than its account allows, with a 413. The host's
+// max_request_size has already been applied while the body was read.
+func (deps *endpointDeps) checkRequestSize(size int, account *config.Account) []error {
+	if account == nil {
+		return nil
+	}
+	limit := account.RequestLimits.MaxRequestSize
+	if limit <= 0 || int64(size) <= limit {
+		return nil
+	}
+	deps.metricsEngine.RecordRequestLimitExceeded(metrics.RequestLimitRequestSize)
+	return []error{&errortypes.RequestTooLarge{
+		Message: fmt.Sprintf("request size of %d bytes exceeds the account's limit of %d bytes", size, limit),
+	}}
+}
+
+// checkImpCount rejects a request with more imps than its account allows. It's checked against the imps of
+// the incoming request before it's unmarshaled, so that the imps of an oversized request aren't decoded only
+// to be thrown away. Imps which come from a stored request are checked by checkRequestLimits.
+func (deps *endpointDeps) checkImpCount(imps int, account *config.Account) []error {
+	if account == nil {
+		return nil
+	}
+	limit := account.RequestLimits.MaxImps
+	if limit <= 0 || imps <= limit {
+		return nil
+	}
+	deps.metricsEngine.RecordRequestLimitExceeded(metrics.RequestLimitImps)
+	return []error{fmt.Errorf("request.imp has %d elements, more than the account's limit of %d", imps, limit)}
+}
+
+// checkRequestLimits rejects a request with more imps, eids or user.data segments than its account allows, so
+// that a few oversized requests can't slow down the auctions of every other account.
+func (deps *endpointDeps) checkRequestLimits(req *openrtb_ext.RequestWrapper, account *config.Account) []error {
+	if account == nil {
+		return nil
+	}
+	limits := account.RequestLimits
+
+	if errs := deps.checkImpCount(req.LenImp(), account); len(errs) > 0 {
+		return errs
+	}
+
+	if req.User == nil {
+		return nil
+	}
+
+	if limits.MaxEIDs > 0 {
+		eids := len(req.User.EIDs)
+		// user.ext is validated later on, so an invalid one is left for that to report
+		if userExt, err := req.GetUserExt(); err == nil && userExt.GetEid() != nil {
+			eids += len(*userExt.GetEid())
+		}
+		if eids > limits.MaxEIDs {
+			deps.metricsEngine.RecordRequestLimitExceeded(metrics.RequestLimitEIDs)
+			return []error{fmt.Errorf("request.user has %d eids, more than the account's limit of %d", eids, limits.MaxEIDs)}
+		}
+	}
+
+	if limits.MaxDataSegments > 0 {
+		segments := 0
+		for _, data := range req.User.Data {
+			segments += len(data.Segment)
+		}
+		if segments > limits.MaxDataSegments {
+			deps.metricsEngine.RecordRequestLimitExceeded(metrics.RequestLimitDataSegments)
+			return []error{fmt.Errorf("request.user.data has %d segments, more than the account's limit of %d", segments, limits.MaxDataSegments)}
+		}
+	}
+	return nil
+}
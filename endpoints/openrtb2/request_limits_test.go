@@ -0,0 +1,194 @@
+package openrtb2
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/errortypes"
+	"github.com/prebid/prebid-server/v2/metrics"
+	"github.com/prebid/prebid-server/v2/openrtb_ext"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckRequestSize(t *testing.T) {
+	testCases := []struct {
+		description    string
+		account        *config.Account
+		size           int
+		expectedErrs   []error
+		expectedMetric bool
+	}{
+		{
+			description: "no account",
+			size:        1000,
+		},
+		{
+			description: "no limit",
+			account:     &config.Account{},
+			size:        1000,
+		},
+		{
+			description: "at the limit",
+			account:     &config.Account{RequestLimits: config.AccountRequestLimits{MaxRequestSize: 1000}},
+			size:        1000,
+		},
+		{
+			description:    "over the limit",
+			account:        &config.Account{RequestLimits: config.AccountRequestLimits{MaxRequestSize: 1000}},
+			size:           1001,
+			expectedErrs:   []error{&errortypes.RequestTooLarge{Message: "request size of 1001 bytes exceeds the account's limit of 1000 bytes"}},
+			expectedMetric: true,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			metricsEngine := &metrics.MetricsEngineMock{}
+			if test.expectedMetric {
+				metricsEngine.On("RecordRequestLimitExceeded", metrics.RequestLimitRequestSize).Once()
+			}
+			deps := &endpointDeps{cfg: &config.Configuration{}, metricsEngine: metricsEngine}
+
+			assert.Equal(t, test.expectedErrs, deps.checkRequestSize(test.size, test.account))
+			metricsEngine.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCheckImpCount(t *testing.T) {
+	testCases := []struct {
+		description    string
+		account        *config.Account
+		imps           int
+		expectedErrs   []error
+		expectedMetric bool
+	}{
+		{
+			description: "no account",
+			imps:        3,
+		},
+		{
+			description: "no limit",
+			account:     &config.Account{},
+			imps:        3,
+		},
+		{
+			description: "at the limit",
+			account:     &config.Account{RequestLimits: config.AccountRequestLimits{MaxImps: 2}},
+			imps:        2,
+		},
+		{
+			description:    "over the limit",
+			account:        &config.Account{RequestLimits: config.AccountRequestLimits{MaxImps: 2}},
+			imps:           3,
+			expectedErrs:   []error{errors.New("request.imp has 3 elements, more than the account's limit of 2")},
+			expectedMetric: true,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			metricsEngine := &metrics.MetricsEngineMock{}
+			if test.expectedMetric {
+				metricsEngine.On("RecordRequestLimitExceeded", metrics.RequestLimitImps).Once()
+			}
+			deps := &endpointDeps{cfg: &config.Configuration{}, metricsEngine: metricsEngine}
+
+			assert.Equal(t, test.expectedErrs, deps.checkImpCount(test.imps, test.account))
+			metricsEngine.AssertExpectations(t)
+		})
+	}
+}
+
+func TestWriteErrorRequestTooLarge(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	labels := metrics.Labels{}
+
+	assert.True(t, writeError([]error{&errortypes.RequestTooLarge{Message: "too large"}}, recorder, &labels))
+	assert.Equal(t, http.StatusRequestEntityTooLarge, recorder.Code)
+	assert.Equal(t, metrics.RequestStatusBadInput, labels.RequestStatus)
+}
+
+func TestCheckRequestLimits(t *testing.T) {
+	limits := config.AccountRequestLimits{MaxImps: 2, MaxEIDs: 2, MaxDataSegments: 3}
+
+	testCases := []struct {
+		description    string
+		limits         config.AccountRequestLimits
+		request        *openrtb2.BidRequest
+		expectedErrs   []error
+		expectedMetric metrics.RequestLimit
+	}{
+		{
+			description: "within the limits",
+			limits:      limits,
+			request: &openrtb2.BidRequest{
+				Imp: []openrtb2.Imp{{ID: "1"}, {ID: "2"}},
+				User: &openrtb2.User{
+					EIDs: []openrtb2.EID{{Source: "a.com"}},
+					Ext:  json.RawMessage(`{"eids":[{"source":"b.com"}]}`),
+					Data: []openrtb2.Data{{Segment: []openrtb2.Segment{{ID: "1"}, {ID: "2"}}}, {Segment: []openrtb2.Segment{{ID: "3"}}}},
+				},
+			},
+		},
+		{
+			description: "no limits",
+			request: &openrtb2.BidRequest{
+				Imp:  []openrtb2.Imp{{ID: "1"}, {ID: "2"}, {ID: "3"}},
+				User: &openrtb2.User{EIDs: []openrtb2.EID{{Source: "a.com"}, {Source: "b.com"}, {Source: "c.com"}}},
+			},
+		},
+		{
+			description:    "too many imps",
+			limits:         limits,
+			request:        &openrtb2.BidRequest{Imp: []openrtb2.Imp{{ID: "1"}, {ID: "2"}, {ID: "3"}}},
+			expectedErrs:   []error{errors.New("request.imp has 3 elements, more than the account's limit of 2")},
+			expectedMetric: metrics.RequestLimitImps,
+		},
+		{
+			description: "too many eids across user.eids and user.ext.eids",
+			limits:      limits,
+			request: &openrtb2.BidRequest{
+				Imp: []openrtb2.Imp{{ID: "1"}},
+				User: &openrtb2.User{
+					EIDs: []openrtb2.EID{{Source: "a.com"}, {Source: "b.com"}},
+					Ext:  json.RawMessage(`{"eids":[{"source":"c.com"}]}`),
+				},
+			},
+			expectedErrs:   []error{errors.New("request.user has 3 eids, more than the account's limit of 2")},
+			expectedMetric: metrics.RequestLimitEIDs,
+		},
+		{
+			description: "too many data segments",
+			limits:      limits,
+			request: &openrtb2.BidRequest{
+				Imp: []openrtb2.Imp{{ID: "1"}},
+				User: &openrtb2.User{
+					Data: []openrtb2.Data{{Segment: []openrtb2.Segment{{ID: "1"}, {ID: "2"}}}, {Segment: []openrtb2.Segment{{ID: "3"}, {ID: "4"}}}},
+				},
+			},
+			expectedErrs:   []error{errors.New("request.user.data has 4 segments, more than the account's limit of 3")},
+			expectedMetric: metrics.RequestLimitDataSegments,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			metricsEngine := &metrics.MetricsEngineMock{}
+			if test.expectedMetric != "" {
+				metricsEngine.On("RecordRequestLimitExceeded", test.expectedMetric).Once()
+			}
+			deps := &endpointDeps{cfg: &config.Configuration{}, metricsEngine: metricsEngine}
+			account := &config.Account{RequestLimits: test.limits}
+
+			errs := deps.checkRequestLimits(&openrtb_ext.RequestWrapper{BidRequest: test.request}, account)
+			assert.Equal(t, test.expectedErrs, errs)
+			metricsEngine.AssertExpectations(t)
+		})
+	}
+}
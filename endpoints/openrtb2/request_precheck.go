@@ -0,0 +1,111 @@
+package openrtb2
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/prebid/prebid-server/v2/metrics"
+	"github.com/prebid/prebid-server/v2/util/bufferpool"
+)
+
+// requestPrecheckBufferSize is how much of the body the imp precheck reads at a time.
+const requestPrecheckBufferSize = 4096
+
+var errRequestTooLarge = errors.New("request body is larger than the max size")
+
+// requestImpsError is returned by precheckRequestImps for a request with more imps than allowed.
+type requestImpsError struct {
+	maxImps int
+}
+
+func (err *requestImpsError) Error() string {
+	return fmt.Sprintf("request.imp has more than %d elements, the host's limit", err.maxImps)
+}
+
+// readRequestBody reads the body of an auction request, within the host's max_request_size. Its imps are
+// counted as it's read if request_decoding.imp_precheck is on.
+func (deps *endpointDeps) readRequestBody(r io.Reader) ([]byte, []error) {
+	if deps.cfg.RequestDecoding.ImpPrecheck {
+		requestJson, err := precheckRequestImps(r, deps.cfg.MaxRequestSize, deps.cfg.RequestDecoding.MaxImps)
+		var impsErr *requestImpsError
+		switch {
+		case err == errRequestTooLarge:
+			return nil, []error{fmt.Errorf("request size exceeded max size of %d bytes.", deps.cfg.MaxRequestSize)}
+		case errors.As(err, &impsErr):
+			deps.metricsEngine.RecordRequestLimitExceeded(metrics.RequestLimitImps)
+			return nil, []error{err}
+		case err != nil:
+			return nil, []error{err}
+		}
+		return requestJson, nil
+	}
+
+	limitedReqReader := &io.LimitedReader{
+		R: r,
+		N: deps.cfg.MaxRequestSize,
+	}
+
+	requestJson, err := bufferpool.ReadAll(limitedReqReader)
+	if err != nil {
+		return nil, []error{err}
+	}
+
+	if limitedReqReader.N <= 0 {
+		// Limited Reader returns 0 if the request was exactly at the max size or over the limit.
+		// This is because it only reads up to N bytes. To check if the request was too large,
+		//  we need to look at the next byte of its underlying reader, limitedReader.R.
+		if _, err := limitedReqReader.R.Read(make([]byte, 1)); err != io.EOF {
+			return nil, []error{fmt.Errorf("request size exceeded max size of %d bytes.", deps.cfg.MaxRequestSize)}
+		}
+	}
+	return requestJson, nil
+}
+
+// precheckRequestImps reads the body of a request, counting its imps with jsoniter's iterator as the body
+// comes in. It stops reading as soon as the body has more than maxImps imps, so that a request with too many
+// imps is rejected before the rest of it is read or unmarshaled. A maxImps of 0 doesn't limit the imps.
+//
+// It isn't a streaming decoder: the body is buffered whole, and unmarshaled afterwards like any other, since
+// hooks, stored requests and Call Signs work on the raw JSON. The body is limited to maxSize the same way as
+// when it isn't prechecked. A body which isn't valid JSON is returned as it is, so that it's reported the
+// same way as one that wasn't prechecked.
+func precheckRequestImps(r io.Reader, maxSize int64, maxImps int) ([]byte, error) {
+	var buf bytes.Buffer
+
+	// everything the iterator reads is kept, and one byte past maxSize tells a body that's too large apart
+	// from one that's exactly the max size
+	body := io.TeeReader(io.LimitReader(r, maxSize+1), &buf)
+	iter := jsoniter.Parse(jsoniter.ConfigCompatibleWithStandardLibrary, body, requestPrecheckBufferSize)
+
+	imps := 0
+	iter.ReadObjectCB(func(iter *jsoniter.Iterator, field string) bool {
+		// the field names are matched the way the request is unmarshaled, which ignores their case
+		if !strings.EqualFold(field, "imp") || iter.WhatIsNext() != jsoniter.ArrayValue {
+			iter.Skip()
+			return true
+		}
+		return iter.ReadArrayCB(func(iter *jsoniter.Iterator) bool {
+			imps++
+			if maxImps > 0 && imps > maxImps {
+				return false
+			}
+			iter.Skip()
+			return true
+		})
+	})
+	if maxImps > 0 && imps > maxImps {
+		return nil, &requestImpsError{maxImps: maxImps}
+	}
+
+	if _, err := io.Copy(io.Discard, body); err != nil {
+		return nil, err
+	}
+	if int64(buf.Len()) > maxSize {
+		return nil, errRequestTooLarge
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,173 @@
+package openrtb2
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/metrics"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrecheckRequestImps(t *testing.T) {
+	testCases := []struct {
+		description string
+		body        string
+		maxSize     int64
+		maxImps     int
+		expectedErr error
+	}{
+		{
+			description: "within the limits",
+			body:        `{"id":"1","imp":[{"id":"a"},{"id":"b"}],"site":{"page":"p"}}`,
+			maxSize:     100,
+			maxImps:     2,
+		},
+		{
+			description: "exactly the max size",
+			body:        `{"imp":[{"id":"a"}]}`,
+			maxSize:     20,
+		},
+		{
+			description: "no imp limit",
+			body:        `{"imp":[{},{},{},{}]}`,
+			maxSize:     100,
+		},
+		{
+			description: "too many imps",
+			body:        `{"id":"1","imp":[{"id":"a"},{"id":"b"},{"id":"c"}]}`,
+			maxSize:     100,
+			maxImps:     2,
+			expectedErr: &requestImpsError{maxImps: 2},
+		},
+		{
+			description: "too many imps in a field of another case",
+			body:        `{"IMP":[{},{}]}`,
+			maxSize:     100,
+			maxImps:     1,
+			expectedErr: &requestImpsError{maxImps: 1},
+		},
+		{
+			description: "imps of nested objects are not counted",
+			body:        `{"ext":{"imp":[{},{}]},"imp":[{"ext":{"imp":[1,2,3]}}]}`,
+			maxSize:     100,
+			maxImps:     1,
+		},
+		{
+			description: "too large",
+			body:        `{"imp":[{"id":"a"}]}`,
+			maxSize:     19,
+			expectedErr: errRequestTooLarge,
+		},
+		{
+			description: "invalid JSON is returned as it is",
+			body:        `{"imp":[{"id":}`,
+			maxSize:     100,
+			maxImps:     1,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			requestJson, err := precheckRequestImps(strings.NewReader(test.body), test.maxSize, test.maxImps)
+
+			assert.Equal(t, test.expectedErr, err)
+			if test.expectedErr == nil {
+				assert.Equal(t, test.body, string(requestJson))
+			}
+		})
+	}
+}
+
+func TestPrecheckRequestImpsStopsReading(t *testing.T) {
+	imps := strings.Repeat(`{"id":"imp"},`, 10000)
+	reader := strings.NewReader(`{"imp":[` + imps + `{}]}`)
+
+	_, err := precheckRequestImps(reader, 1<<20, 10)
+
+	assert.Equal(t, &requestImpsError{maxImps: 10}, err)
+	assert.NotZero(t, reader.Len(), "the rest of the body shouldn't be read")
+}
+
+func TestReadRequestBody(t *testing.T) {
+	testCases := []struct {
+		description    string
+		impPrecheck    bool
+		body           string
+		expectedErrs   []error
+		expectedMetric bool
+	}{
+		{
+			description: "buffered",
+			body:        `{"imp":[{},{}]}`,
+		},
+		{
+			description:  "buffered too large",
+			body:         `{"imp":[{},{},{},{},{},{},{},{},{},{},{}]}`,
+			expectedErrs: []error{errors.New("request size exceeded max size of 40 bytes.")},
+		},
+		{
+			description: "prechecked",
+			impPrecheck: true,
+			body:        `{"imp":[{},{}]}`,
+		},
+		{
+			description:  "prechecked too large",
+			impPrecheck:  true,
+			body:         `{"id":"aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"}`,
+			expectedErrs: []error{errors.New("request size exceeded max size of 40 bytes.")},
+		},
+		{
+			description:    "prechecked too many imps",
+			impPrecheck:    true,
+			body:           `{"imp":[{},{},{}]}`,
+			expectedErrs:   []error{&requestImpsError{maxImps: 2}},
+			expectedMetric: true,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			metricsEngine := &metrics.MetricsEngineMock{}
+			if test.expectedMetric {
+				metricsEngine.On("RecordRequestLimitExceeded", metrics.RequestLimitImps).Once()
+			}
+			cfg := &config.Configuration{
+				MaxRequestSize:  40,
+				RequestDecoding: config.RequestDecoding{ImpPrecheck: test.impPrecheck, MaxImps: 2},
+			}
+			deps := &endpointDeps{cfg: cfg, metricsEngine: metricsEngine}
+
+			requestJson, errs := deps.readRequestBody(strings.NewReader(test.body))
+
+			assert.Equal(t, test.expectedErrs, errs)
+			if test.expectedErrs == nil {
+				assert.Equal(t, test.body, string(requestJson))
+			}
+			metricsEngine.AssertExpectations(t)
+		})
+	}
+}
+
+func BenchmarkReadRequestBody(b *testing.B) {
+	var imps []string
+	for i := 0; i < 60; i++ {
+		imps = append(imps, fmt.Sprintf(`{"id":"pod-%d","video":{"mimes":["video/mp4"],"minduration":15,"maxduration":30,"w":1920,"h":1080},"ext":{"prebid":{"bidder":{"appnexus":{"placementId":12883451}}}}}`, i))
+	}
+	body := `{"id":"ctv","imp":[` + strings.Join(imps, ",") + `],"app":{"bundle":"com.example.ctv"}}`
+
+	for _, impPrecheck := range []bool{false, true} {
+		deps := &endpointDeps{cfg: &config.Configuration{
+			MaxRequestSize:  1 << 20,
+			RequestDecoding: config.RequestDecoding{ImpPrecheck: impPrecheck, MaxImps: 100},
+		}}
+		b.Run(fmt.Sprintf("imp_precheck=%t", impPrecheck), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				deps.readRequestBody(strings.NewReader(body))
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package openrtb2
+
+import (
+	"encoding/json"
+
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/util/jsonutil"
+)
+
+// responseExtSections are the sections of the response ext accounts may leave out, keyed by their member of
+// the ext.
+var responseExtSections = []string{
+	config.ResponseExtDebug,
+	config.ResponseExtErrors,
+	config.ResponseExtWarnings,
+	config.ResponseExtResponseTimeMillis,
+	config.ResponseExtUsersync,
+}
+
+// responseExtPrebidSections are the sections of ext.prebid accounts may leave out, keyed by their member of
+// ext.prebid.
+var responseExtPrebidSections = []string{
+	config.ResponseExtSeatNonBid,
+	config.ResponseExtFledge,
+	config.ResponseExtModules,
+}
+
+// filterResponseExt leaves the sections of the response ext the account doesn't include out of it. The ext
+// is returned as is if the account doesn't limit it.
+func filterResponseExt(ext json.RawMessage, responseExt config.AccountResponseExt) (json.RawMessage, error) {
+	if !responseExt.Enabled || len(ext) == 0 {
+		return ext, nil
+	}
+
+	var members map[string]json.RawMessage
+	if err := jsonutil.Unmarshal(ext, &members); err != nil {
+		return nil, err
+	}
+
+	removed := removeSections(members, responseExtSections, responseExt)
+
+	if prebidJSON, ok := members["prebid"]; ok {
+		var prebid map[string]json.RawMessage
+		if err := jsonutil.Unmarshal(prebidJSON, &prebid); err != nil {
+			return nil, err
+		}
+		if removeSections(prebid, responseExtPrebidSections, responseExt) {
+			removed = true
+			if len(prebid) == 0 {
+				delete(members, "prebid")
+			} else {
+				prebidJSON, err := jsonutil.Marshal(prebid)
+				if err != nil {
+					return nil, err
+				}
+				members["prebid"] = prebidJSON
+			}
+		}
+	}
+
+	if !removed {
+		return ext, nil
+	}
+	if len(members) == 0 {
+		return nil, nil
+	}
+	return jsonutil.Marshal(members)
+}
+
+// removeSections removes the sections the account doesn't include from the members, and returns true if any
+// were present.
+func removeSections(members map[string]json.RawMessage, sections []string, responseExt config.AccountResponseExt) bool {
+	removed := false
+	for _, section := range sections {
+		if _, ok := members[section]; ok && !responseExt.Includes(section) {
+			delete(members, section)
+			removed = true
+		}
+	}
+	return removed
+}
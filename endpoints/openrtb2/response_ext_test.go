@@ -0,0 +1,73 @@
+package openrtb2
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilterResponseExt(t *testing.T) {
+	ext := json.RawMessage(`{"debug":{"httpcalls":{}},"errors":{"appnexus":[{"code":1,"message":"m"}]},"responsetimemillis":{"appnexus":12},"tmaxrequest":500,"prebid":{"auctiontimestamp":1,"seatnonbid":[{"seat":"appnexus"}],"modules":{"trace":{}}}}`)
+
+	testCases := []struct {
+		name        string
+		ext         json.RawMessage
+		responseExt config.AccountResponseExt
+		expected    string
+	}{
+		{
+			name:        "disabled",
+			ext:         ext,
+			responseExt: config.AccountResponseExt{Enabled: false},
+			expected:    string(ext),
+		},
+		{
+			name:        "nothing-included",
+			ext:         ext,
+			responseExt: config.AccountResponseExt{Enabled: true},
+			expected:    `{"tmaxrequest":500,"prebid":{"auctiontimestamp":1}}`,
+		},
+		{
+			name:        "some-included",
+			ext:         ext,
+			responseExt: config.AccountResponseExt{Enabled: true, Include: []string{"responsetimemillis", "seatnonbid"}},
+			expected:    `{"responsetimemillis":{"appnexus":12},"tmaxrequest":500,"prebid":{"auctiontimestamp":1,"seatnonbid":[{"seat":"appnexus"}]}}`,
+		},
+		{
+			name:        "prebid-emptied",
+			ext:         json.RawMessage(`{"responsetimemillis":{"appnexus":12},"prebid":{"modules":{"trace":{}}}}`),
+			responseExt: config.AccountResponseExt{Enabled: true, Include: []string{"responsetimemillis"}},
+			expected:    `{"responsetimemillis":{"appnexus":12}}`,
+		},
+		{
+			name:        "all-removed",
+			ext:         json.RawMessage(`{"debug":{}}`),
+			responseExt: config.AccountResponseExt{Enabled: true},
+		},
+		{
+			name:        "nothing-to-remove",
+			ext:         json.RawMessage(`{"prebid":{"auctiontimestamp":1}}`),
+			responseExt: config.AccountResponseExt{Enabled: true},
+			expected:    `{"prebid":{"auctiontimestamp":1}}`,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			filtered, err := filterResponseExt(test.ext, test.responseExt)
+			assert.NoError(t, err)
+			if test.expected == "" {
+				assert.Nil(t, filtered)
+			} else {
+				assert.JSONEq(t, test.expected, string(filtered))
+			}
+		})
+	}
+}
+
+func TestFilterResponseExtMalformed(t *testing.T) {
+	_, err := filterResponseExt(json.RawMessage(`{"prebid":malformed}`), config.AccountResponseExt{Enabled: true})
+	assert.Error(t, err)
+}
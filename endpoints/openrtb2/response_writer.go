@@ -0,0 +1,99 @@
+package openrtb2
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v2/responsesigning"
+)
+
+// bidResponseIDPrefix is how every encoded bid response with an empty ID starts. The "id" field
+// has no omitempty and comes first in openrtb2.BidResponse, so whatever follows it is the rest of
+// the response.
+var bidResponseIDPrefix = []byte(`{"id":""`)
+
+// writeBidResponse writes response to w as JSON, followed by a newline. The output is identical
+// to that of a json.Encoder with HTML escaping turned off, but the seatbids are encoded and
+// written one at a time. Responses with many or large bids, such as CTV pods, would otherwise
+// need an encoding buffer the size of the whole response for every request.
+func writeBidResponse(w io.Writer, response *openrtb2.BidResponse) error {
+	if response == nil || len(response.SeatBid) == 0 {
+		// Fixes #231
+		enc := json.NewEncoder(w)
+		enc.SetEscapeHTML(false)
+		return enc.Encode(response)
+	}
+
+	// The ID and the remaining fields are encoded up front so that an error in them, typically a
+	// malformed ext, is reported before anything has been written.
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(response.ID); err != nil {
+		return err
+	}
+	head := append([]byte(`{"id":`), bytes.TrimSuffix(buf.Bytes(), newline)...)
+	head = append(head, `,"seatbid":[`...)
+
+	buf.Reset()
+	rest := *response
+	rest.ID = ""
+	rest.SeatBid = nil
+	if err := enc.Encode(&rest); err != nil {
+		return err
+	}
+	tail := append([]byte("]"), bytes.TrimPrefix(buf.Bytes(), bidResponseIDPrefix)...)
+
+	if _, err := w.Write(head); err != nil {
+		return err
+	}
+	seatBidEnc := json.NewEncoder(valueWriter{w})
+	seatBidEnc.SetEscapeHTML(false)
+	for i := range response.SeatBid {
+		if i > 0 {
+			if _, err := w.Write(comma); err != nil {
+				return err
+			}
+		}
+		if err := seatBidEnc.Encode(&response.SeatBid[i]); err != nil {
+			return err
+		}
+	}
+	_, err := w.Write(tail)
+	return err
+}
+
+var (
+	newline = []byte("\n")
+	comma   = []byte(",")
+)
+
+// valueWriter drops the newline a json.Encoder writes after each value, so that values can be
+// written into the middle of a document.
+type valueWriter struct {
+	w io.Writer
+}
+
+func (vw valueWriter) Write(p []byte) (int, error) {
+	n, err := vw.w.Write(bytes.TrimSuffix(p, newline))
+	if err == nil {
+		n = len(p)
+	}
+	return n, err
+}
+
+// signBidResponse encodes the response as writeBidResponse does, and signs it with the key of the given ID.
+// The whole response is encoded before it's written, since its signature goes in a header.
+func signBidResponse(response *openrtb2.BidResponse, signer *responsesigning.Signer, keyID string) ([]byte, string, error) {
+	var buf bytes.Buffer
+	if err := writeBidResponse(&buf, response); err != nil {
+		return nil, "", err
+	}
+	signature, err := signer.Sign(keyID, buf.Bytes())
+	if err != nil {
+		return nil, "", err
+	}
+	return buf.Bytes(), signature, nil
+}
@@ -0,0 +1,138 @@
+package openrtb2
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/openrtb/v20/openrtb3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteBidResponse(t *testing.T) {
+	nbr := openrtb3.NoBidReason(2)
+
+	testCases := []struct {
+		name     string
+		response *openrtb2.BidResponse
+	}{
+		{
+			name:     "nil",
+			response: nil,
+		},
+		{
+			name:     "no-seatbid",
+			response: &openrtb2.BidResponse{ID: "some-id", NBR: &nbr, Ext: json.RawMessage(`{"debug": {}}`)},
+		},
+		{
+			name: "one-seatbid",
+			response: &openrtb2.BidResponse{
+				ID:      "some-id",
+				SeatBid: []openrtb2.SeatBid{{Seat: "appnexus", Bid: []openrtb2.Bid{{ID: "bid1", ImpID: "imp1", Price: 1.5, AdM: "<div>&ad</div>"}}}},
+			},
+		},
+		{
+			name: "many-seatbids-and-all-fields",
+			response: &openrtb2.BidResponse{
+				ID: "<id>",
+				SeatBid: []openrtb2.SeatBid{
+					{Seat: "appnexus", Bid: []openrtb2.Bid{{ID: "bid1", ImpID: "imp1", Price: 1.5}, {ID: "bid2", ImpID: "imp2", Price: 2}}},
+					{Seat: "rubicon", Bid: []openrtb2.Bid{{ID: "bid3", ImpID: "imp1", Price: 0.5, Ext: json.RawMessage(`{ "prebid": { "type": "video" } }`)}}},
+				},
+				BidID:      "bidid",
+				Cur:        "USD",
+				CustomData: "data",
+				NBR:        &nbr,
+				Ext:        json.RawMessage(`{"responsetimemillis": {"appnexus": 10}}`),
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			var expected bytes.Buffer
+			enc := json.NewEncoder(&expected)
+			enc.SetEscapeHTML(false)
+			assert.NoError(t, enc.Encode(test.response))
+
+			var actual bytes.Buffer
+			assert.NoError(t, writeBidResponse(&actual, test.response))
+			assert.Equal(t, expected.String(), actual.String())
+		})
+	}
+}
+
+func TestWriteBidResponseErrors(t *testing.T) {
+	seatBid := []openrtb2.SeatBid{{Seat: "appnexus", Bid: []openrtb2.Bid{{ID: "bid1", ImpID: "imp1"}}}}
+
+	t.Run("malformed-ext-writes-nothing", func(t *testing.T) {
+		var actual bytes.Buffer
+		err := writeBidResponse(&actual, &openrtb2.BidResponse{ID: "some-id", SeatBid: seatBid, Ext: json.RawMessage("...")})
+		assert.Error(t, err)
+		assert.Empty(t, actual.String())
+	})
+
+	t.Run("write-error", func(t *testing.T) {
+		err := writeBidResponse(failingWriter{}, &openrtb2.BidResponse{ID: "some-id", SeatBid: seatBid})
+		assert.EqualError(t, err, "connection closed")
+	})
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("connection closed")
+}
+
+// largestWriteWriter records the largest single write, which is the size of the buffer the json
+// encoder needed to produce it.
+type largestWriteWriter struct {
+	largest int
+}
+
+func (w *largestWriteWriter) Write(p []byte) (int, error) {
+	if len(p) > w.largest {
+		w.largest = len(p)
+	}
+	return len(p), nil
+}
+
+func BenchmarkWriteBidResponse(b *testing.B) {
+	// A CTV-sized response: a few seats, each with a pod's worth of video bids carrying inline VAST.
+	adm := `<VAST version="3.0"><Ad><InLine>` + string(bytes.Repeat([]byte(`<Impression><![CDATA[https://tracker.com/imp]]></Impression>`), 40)) + `</InLine></Ad></VAST>`
+	response := &openrtb2.BidResponse{ID: "some-id", Cur: "USD"}
+	for s := 0; s < 5; s++ {
+		seatBid := openrtb2.SeatBid{Seat: fmt.Sprintf("bidder%d", s)}
+		for i := 0; i < 20; i++ {
+			seatBid.Bid = append(seatBid.Bid, openrtb2.Bid{ID: fmt.Sprintf("bid%d", i), ImpID: fmt.Sprintf("imp%d", i), Price: 10, AdM: adm})
+		}
+		response.SeatBid = append(response.SeatBid, seatBid)
+	}
+
+	b.Run("encoder", func(b *testing.B) {
+		b.ReportAllocs()
+		w := &largestWriteWriter{}
+		for i := 0; i < b.N; i++ {
+			enc := json.NewEncoder(w)
+			enc.SetEscapeHTML(false)
+			if err := enc.Encode(response); err != nil {
+				b.Fatal(err)
+			}
+		}
+		b.ReportMetric(float64(w.largest), "buffer-bytes")
+	})
+
+	b.Run("streaming", func(b *testing.B) {
+		b.ReportAllocs()
+		w := &largestWriteWriter{}
+		for i := 0; i < b.N; i++ {
+			if err := writeBidResponse(w, response); err != nil {
+				b.Fatal(err)
+			}
+		}
+		b.ReportMetric(float64(w.largest), "buffer-bytes")
+	})
+}
@@ -0,0 +1,116 @@
+package openrtb2
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/julienschmidt/httprouter"
+	analyticsBuild "github.com/prebid/prebid-server/v2/analytics/build"
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/exchange"
+	"github.com/prebid/prebid-server/v2/hooks"
+	"github.com/prebid/prebid-server/v2/metrics"
+	"github.com/prebid/prebid-server/v2/openrtb_ext"
+	"github.com/prebid/prebid-server/v2/stored_requests"
+	"github.com/prebid/prebid-server/v2/util/jsonutil"
+	"github.com/prebid/prebid-server/v2/util/uuidutil"
+)
+
+// simulationRequest is the body of a request to /openrtb2/simulate.
+type simulationRequest struct {
+	// Request is the OpenRTB request to hold the auction for, as it would be sent to /openrtb2/auction.
+	Request json.RawMessage `json:"request"`
+	// Responses holds the OpenRTB response of each bidder, by bidder name.
+	Responses map[string]json.RawMessage `json:"responses"`
+}
+
+type simulationKey struct{}
+
+func withSimulatedResponses(ctx context.Context, responses map[string]json.RawMessage) context.Context {
+	return context.WithValue(ctx, simulationKey{}, responses)
+}
+
+// simulatedResponsesFromContext returns the bidder responses of a simulated auction, or nil if the
+// auction is real.
+func simulatedResponsesFromContext(ctx context.Context) map[string]json.RawMessage {
+	responses, _ := ctx.Value(simulationKey{}).(map[string]json.RawMessage)
+	return responses
+}
+
+// NewSimulationEndpoint returns an endpoint which holds auctions with the bidder responses given along
+// with the request, instead of calling the bidders, and responds as /openrtb2/auction would have. Bids
+// go through the same floors, privacy, validation and targeting as in real auctions, but aren't cached.
+// Simulated auctions aren't logged to analytics.
+func NewSimulationEndpoint(
+	uuidGenerator uuidutil.UUIDGenerator,
+	ex exchange.Exchange,
+	validator openrtb_ext.BidderParamValidator,
+	requestsById stored_requests.Fetcher,
+	accounts stored_requests.AccountFetcher,
+	cfg *config.Configuration,
+	metricsEngine metrics.MetricsEngine,
+	disabledBidders map[string]string,
+	defReqJSON []byte,
+	bidderMap map[string]openrtb_ext.BidderName,
+	storedRespFetcher stored_requests.Fetcher,
+	hookExecutionPlanBuilder hooks.ExecutionPlanBuilder,
+	tmaxAdjustments *exchange.TmaxAdjustmentsPreprocessed,
+) (httprouter.Handle, error) {
+	auction, err := NewEndpoint(uuidGenerator, ex, validator, requestsById, accounts, cfg, metricsEngine, analyticsBuild.New(&config.Analytics{}), disabledBidders, defReqJSON, bidderMap, storedRespFetcher, hookExecutionPlanBuilder, tmaxAdjustments, nil, nil, nil, nil, nil, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return simulate(auction, cfg.MaxRequestSize), nil
+}
+
+func simulate(auction httprouter.Handle, maxRequestSize int64) httprouter.Handle {
+	return func(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+		simulation, err := readSimulationRequest(r, maxRequestSize)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprintf(w, "Invalid request: %s\n", err.Error())
+			return
+		}
+
+		auctionRequest := r.Clone(withSimulatedResponses(r.Context(), simulation.Responses))
+		auctionRequest.Body = io.NopCloser(bytes.NewReader(simulation.Request))
+		auctionRequest.ContentLength = int64(len(simulation.Request))
+		auction(w, auctionRequest, params)
+	}
+}
+
+func readSimulationRequest(r *http.Request, maxRequestSize int64) (simulationRequest, error) {
+	if r.Header.Get("Content-Encoding") != "" {
+		return simulationRequest{}, errors.New("simulation requests can't be compressed")
+	}
+
+	reader := io.Reader(r.Body)
+	if maxRequestSize > 0 {
+		reader = io.LimitReader(r.Body, maxRequestSize+1)
+	}
+	body, err := io.ReadAll(reader)
+	if err != nil {
+		return simulationRequest{}, err
+	}
+	if maxRequestSize > 0 && int64(len(body)) > maxRequestSize {
+		return simulationRequest{}, fmt.Errorf("request size exceeded max size of %d bytes.", maxRequestSize)
+	}
+
+	var simulation simulationRequest
+	if err := jsonutil.UnmarshalValid(body, &simulation); err != nil {
+		return simulationRequest{}, err
+	}
+	if len(simulation.Request) == 0 {
+		return simulationRequest{}, errors.New("simulation requests must have a request")
+	}
+	if simulation.Responses == nil {
+		// bidders still mustn't be called if none of them are given a response
+		simulation.Responses = make(map[string]json.RawMessage)
+	}
+	return simulation, nil
+}
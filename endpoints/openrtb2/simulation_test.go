@@ -0,0 +1,95 @@
+package openrtb2
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSimulate(t *testing.T) {
+	testCases := []struct {
+		description       string
+		body              string
+		contentEncoding   string
+		expectedStatus    int
+		expectedBody      string
+		expectedRequest   string
+		expectedResponses map[string]json.RawMessage
+	}{
+		{
+			description:       "with-responses",
+			body:              `{"request":{"id":"req"},"responses":{"appnexus":{"id":"resp"}}}`,
+			expectedStatus:    http.StatusOK,
+			expectedRequest:   `{"id":"req"}`,
+			expectedResponses: map[string]json.RawMessage{"appnexus": json.RawMessage(`{"id":"resp"}`)},
+		},
+		{
+			description:       "without-responses",
+			body:              `{"request":{"id":"req"}}`,
+			expectedStatus:    http.StatusOK,
+			expectedRequest:   `{"id":"req"}`,
+			expectedResponses: map[string]json.RawMessage{},
+		},
+		{
+			description:    "without-request",
+			body:           `{"responses":{}}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "Invalid request: simulation requests must have a request\n",
+		},
+		{
+			description:    "too-large",
+			body:           `{"request":{"id":"a-request-which-is-much-too-long-to-be-accepted-here"}}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "Invalid request: request size exceeded max size of 70 bytes.\n",
+		},
+		{
+			description:     "compressed",
+			body:            `{"request":{"id":"req"}}`,
+			contentEncoding: "gzip",
+			expectedStatus:  http.StatusBadRequest,
+			expectedBody:    "Invalid request: simulation requests can't be compressed\n",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			var (
+				called            bool
+				receivedRequest   string
+				receivedResponses map[string]json.RawMessage
+			)
+			auction := func(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+				called = true
+				body, _ := io.ReadAll(r.Body)
+				receivedRequest = string(body)
+				receivedResponses = simulatedResponsesFromContext(r.Context())
+			}
+
+			request := httptest.NewRequest(http.MethodPost, "/openrtb2/simulate", strings.NewReader(test.body))
+			if test.contentEncoding != "" {
+				request.Header.Set("Content-Encoding", test.contentEncoding)
+			}
+			recorder := httptest.NewRecorder()
+			simulate(auction, 70)(recorder, request, nil)
+
+			assert.Equal(t, test.expectedStatus, recorder.Code)
+			assert.Equal(t, test.expectedBody, recorder.Body.String())
+			assert.Equal(t, test.expectedStatus == http.StatusOK, called)
+			if called {
+				assert.Equal(t, test.expectedRequest, receivedRequest)
+				assert.Equal(t, test.expectedResponses, receivedResponses)
+			}
+		})
+	}
+}
+
+func TestSimulatedResponsesFromContext(t *testing.T) {
+	request := httptest.NewRequest(http.MethodPost, "/openrtb2/auction", nil)
+	assert.Nil(t, simulatedResponsesFromContext(request.Context()), "Real auctions shouldn't be simulated")
+}
@@ -0,0 +1,26 @@
+package openrtb2
+
+import (
+	"time"
+
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/metrics"
+	"github.com/prebid/prebid-server/v2/openrtb_ext"
+)
+
+// isTestTraffic returns true if the request has test=1, and test traffic is kept apart from production
+// traffic.
+func isTestTraffic(cfg config.TestTraffic, req *openrtb_ext.RequestWrapper) bool {
+	return cfg.Enabled && req != nil && req.BidRequest != nil && req.Test == 1
+}
+
+// recordRequestMetrics records the request and its time in the request metrics, or only counts it as a test
+// request if it's test traffic.
+func recordRequestMetrics(me metrics.MetricsEngine, labels metrics.Labels, testTraffic bool, start time.Time) {
+	if testTraffic {
+		me.RecordTestRequest(labels.RType)
+		return
+	}
+	me.RecordRequest(labels)
+	me.RecordRequestTime(labels, time.Since(start))
+}
@@ -0,0 +1,74 @@
+package openrtb2
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/metrics"
+	"github.com/prebid/prebid-server/v2/openrtb_ext"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestIsTestTraffic(t *testing.T) {
+	testCases := []struct {
+		name     string
+		cfg      config.TestTraffic
+		req      *openrtb_ext.RequestWrapper
+		expected bool
+	}{
+		{
+			name:     "test",
+			cfg:      config.TestTraffic{Enabled: true},
+			req:      &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{Test: 1}},
+			expected: true,
+		},
+		{
+			name: "production",
+			cfg:  config.TestTraffic{Enabled: true},
+			req:  &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{}},
+		},
+		{
+			name: "disabled",
+			cfg:  config.TestTraffic{Enabled: false},
+			req:  &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{Test: 1}},
+		},
+		{
+			name: "unparsed",
+			cfg:  config.TestTraffic{Enabled: true},
+			req:  nil,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, isTestTraffic(test.cfg, test.req))
+		})
+	}
+}
+
+func TestRecordRequestMetrics(t *testing.T) {
+	labels := metrics.Labels{RType: metrics.ReqTypeORTB2Web, RequestStatus: metrics.RequestStatusOK}
+
+	t.Run("production", func(t *testing.T) {
+		me := &metrics.MetricsEngineMock{}
+		me.On("RecordRequest", labels).Once()
+		me.On("RecordRequestTime", labels, mock.Anything).Once()
+
+		recordRequestMetrics(me, labels, false, time.Now())
+
+		me.AssertExpectations(t)
+	})
+
+	t.Run("test", func(t *testing.T) {
+		me := &metrics.MetricsEngineMock{}
+		me.On("RecordTestRequest", metrics.ReqTypeORTB2Web).Once()
+
+		recordRequestMetrics(me, labels, true, time.Now())
+
+		me.AssertExpectations(t)
+		me.AssertNotCalled(t, "RecordRequest", mock.Anything)
+	})
+}
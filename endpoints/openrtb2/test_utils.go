@@ -1205,6 +1205,7 @@ func buildTestExchange(testCfg *testConfigValues, adapterMap map[openrtb_ext.Bid
 		&adscert.NilSigner{},
 		macros.NewStringIndexBasedReplacer(),
 		nil,
+		exchange.Dependencies{},
 	)
 
 	testExchange = &exchangeTestWrapper{
@@ -1281,9 +1282,13 @@ func buildTestEndpoint(test testCase, cfg *config.Configuration) (httprouter.Han
 
 	switch test.endpointType {
 	case AMP_ENDPOINT:
-		endpointBuilder = NewAmpEndpoint
+		endpointBuilder = func(uuidGenerator uuidutil.UUIDGenerator, ex exchange.Exchange, validator openrtb_ext.BidderParamValidator, requestsById stored_requests.Fetcher, accounts stored_requests.AccountFetcher, cfg *config.Configuration, metricsEngine metrics.MetricsEngine, analyticsRunner analytics.Runner, disabledBidders map[string]string, defReqJSON []byte, bidderMap map[string]openrtb_ext.BidderName, storedRespFetcher stored_requests.Fetcher, hookExecutionPlanBuilder hooks.ExecutionPlanBuilder, tmaxAdjustments *exchange.TmaxAdjustmentsPreprocessed) (httprouter.Handle, error) {
+			return NewAmpEndpoint(uuidGenerator, ex, validator, requestsById, accounts, cfg, metricsEngine, analyticsRunner, disabledBidders, defReqJSON, bidderMap, storedRespFetcher, hookExecutionPlanBuilder, tmaxAdjustments, nil, nil, nil)
+		}
 	default: //case OPENRTB_ENDPOINT:
-		endpointBuilder = NewEndpoint
+		endpointBuilder = func(uuidGenerator uuidutil.UUIDGenerator, ex exchange.Exchange, validator openrtb_ext.BidderParamValidator, requestsById stored_requests.Fetcher, accounts stored_requests.AccountFetcher, cfg *config.Configuration, metricsEngine metrics.MetricsEngine, analyticsRunner analytics.Runner, disabledBidders map[string]string, defReqJSON []byte, bidderMap map[string]openrtb_ext.BidderName, storedRespFetcher stored_requests.Fetcher, hookExecutionPlanBuilder hooks.ExecutionPlanBuilder, tmaxAdjustments *exchange.TmaxAdjustmentsPreprocessed) (httprouter.Handle, error) {
+			return NewEndpoint(uuidGenerator, ex, validator, requestsById, accounts, cfg, metricsEngine, analyticsRunner, disabledBidders, defReqJSON, bidderMap, storedRespFetcher, hookExecutionPlanBuilder, tmaxAdjustments, nil, nil, nil, nil, nil, nil, nil)
+		}
 	}
 
 	endpoint, err := endpointBuilder(
@@ -1412,6 +1417,7 @@ type mockPlanBuilder struct {
 	processedAuctionPlan         hooks.Plan[hookstage.ProcessedAuctionRequest]
 	bidderRequestPlan            hooks.Plan[hookstage.BidderRequest]
 	rawBidderResponsePlan        hooks.Plan[hookstage.RawBidderResponse]
+	rawBidderResponsePerSeatPlan hooks.Plan[hookstage.RawBidderResponsePerSeat]
 	allProcessedBidResponsesPlan hooks.Plan[hookstage.AllProcessedBidResponses]
 	auctionResponsePlan          hooks.Plan[hookstage.AuctionResponse]
 }
@@ -1436,6 +1442,10 @@ func (m mockPlanBuilder) PlanForRawBidderResponseStage(_ string, _ *config.Accou
 	return m.rawBidderResponsePlan
 }
 
+func (m mockPlanBuilder) PlanForRawBidderResponsePerSeatStage(_ string, _ *config.Account) hooks.Plan[hookstage.RawBidderResponsePerSeat] {
+	return m.rawBidderResponsePerSeatPlan
+}
+
 func (m mockPlanBuilder) PlanForAllProcessedBidResponsesStage(_ string, _ *config.Account) hooks.Plan[hookstage.AllProcessedBidResponses] {
 	return m.allProcessedBidResponsesPlan
 }
@@ -0,0 +1,93 @@
+package openrtb2
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/errortypes"
+	"github.com/prebid/prebid-server/v2/hooks/hookexecution"
+	"github.com/prebid/prebid-server/v2/metrics"
+	"github.com/prebid/prebid-server/v2/openrtb_ext"
+	"github.com/prebid/prebid-server/v2/stored_requests"
+	"github.com/prebid/prebid-server/v2/util/uuidutil"
+	"github.com/prebid/prebid-server/v2/version"
+)
+
+// validationResponse is the body of a response from /openrtb2/validate.
+type validationResponse struct {
+	Valid    bool                `json:"valid"`
+	Errors   []validationMessage `json:"errors"`
+	Warnings []validationMessage `json:"warnings"`
+}
+
+type validationMessage struct {
+	Code     int                             `json:"code"`
+	Category errortypes.Category             `json:"category"`
+	Message  string                          `json:"message"`
+	Position *openrtb_ext.ExtMessagePosition `json:"position,omitempty"`
+}
+
+// NewValidationEndpoint returns an endpoint which checks requests as /openrtb2/auction would, resolving
+// their stored requests and validating them against the OpenRTB rules and the bidder param schemas, and
+// responds with every error and warning found instead of holding an auction. Modules aren't run, and
+// requests to it aren't counted in the request metrics or logged to analytics.
+func NewValidationEndpoint(
+	uuidGenerator uuidutil.UUIDGenerator,
+	validator openrtb_ext.BidderParamValidator,
+	requestsById stored_requests.Fetcher,
+	accounts stored_requests.AccountFetcher,
+	cfg *config.Configuration,
+	metricsEngine metrics.MetricsEngine,
+	disabledBidders map[string]string,
+	defReqJSON []byte,
+	bidderMap map[string]openrtb_ext.BidderName,
+	storedRespFetcher stored_requests.Fetcher,
+) (httprouter.Handle, error) {
+	if validator == nil || requestsById == nil || accounts == nil || cfg == nil || metricsEngine == nil {
+		return nil, errors.New("NewValidationEndpoint requires non-nil arguments.")
+	}
+
+	deps, err := newEndpointDeps(uuidGenerator, nil, validator, requestsById, accounts, cfg, metricsEngine, nil, disabledBidders, defReqJSON, bidderMap, storedRespFetcher, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return deps.Validate, nil
+}
+
+// Validate responds with the errors and warnings of the request, with a 200 status if it would be
+// auctioned and a 400 status if it would be rejected.
+func (deps *endpointDeps) Validate(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	w.Header().Set("X-Prebid", version.BuildXPrebidHeader(version.Ver))
+
+	// the labels are filled in by parseRequest, but validated requests aren't recorded
+	labels := metrics.Labels{
+		Source: metrics.DemandUnknown,
+		RType:  metrics.ReqTypeORTB2Web,
+		PubID:  metrics.PublisherUnknown,
+	}
+	_, _, _, _, _, _, _, _, errs := deps.parseRequest(r, &labels, hookexecution.EmptyHookExecutor{})
+
+	response := validationResponse{
+		Valid:    !errortypes.ContainsFatalError(errs),
+		Errors:   toValidationMessages(errortypes.FatalOnly(errs)),
+		Warnings: toValidationMessages(errortypes.WarningOnly(errs)),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if !response.Valid {
+		w.WriteHeader(http.StatusBadRequest)
+	}
+	json.NewEncoder(w).Encode(response)
+}
+
+func toValidationMessages(errs []error) []validationMessage {
+	messages := make([]validationMessage, 0, len(errs))
+	for _, err := range errs {
+		message := openrtb_ext.NewExtBidderMessage(err)
+		messages = append(messages, validationMessage{Code: message.Code, Category: message.Category, Message: message.Message, Position: message.Position})
+	}
+	return messages
+}
@@ -0,0 +1,120 @@
+package openrtb2
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/errortypes"
+	metricsConfig "github.com/prebid/prebid-server/v2/metrics/config"
+	"github.com/prebid/prebid-server/v2/openrtb_ext"
+	"github.com/prebid/prebid-server/v2/stored_requests/backends/empty_fetcher"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidate(t *testing.T) {
+	testCases := []struct {
+		description      string
+		body             string
+		expectedStatus   int
+		expectedResponse validationResponse
+	}{
+		{
+			description:    "valid",
+			body:           `{"id":"req","site":{"page":"test.somepage.com"},"imp":[{"id":"imp-1","banner":{"format":[{"w":300,"h":250}]},"ext":{"prebid":{"bidder":{"appnexus":{"placementId":12883451}}}}}]}`,
+			expectedStatus: http.StatusOK,
+			expectedResponse: validationResponse{
+				Valid:    true,
+				Errors:   []validationMessage{},
+				Warnings: []validationMessage{},
+			},
+		},
+		{
+			description:    "with-warnings",
+			body:           `{"id":"req","site":{"page":"test.somepage.com"},"imp":[{"id":"imp-1","banner":{"format":[{"w":300,"h":250}]},"ext":{"prebid":{"bidder":{"appnexus":{"placementId":12883451}}}}}],"cur":["EUR","USD"]}`,
+			expectedStatus: http.StatusOK,
+			expectedResponse: validationResponse{
+				Valid:    true,
+				Errors:   []validationMessage{},
+				Warnings: []validationMessage{{Code: errortypes.MultipleCurrenciesWarningCode, Category: errortypes.CategoryCurrency, Message: "A prebid request can only process one currency. Taking the first currency in the list, EUR, as the active currency"}},
+			},
+		},
+		{
+			description:    "invalid-bidder-params",
+			body:           `{"id":"req","site":{"page":"test.somepage.com"},"imp":[{"id":"imp-1","banner":{"format":[{"w":300,"h":250}]},"ext":{"prebid":{"bidder":{"rubicon":{"accountId":"abc","siteId":1,"zoneId":2}}}}}]}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedResponse: validationResponse{
+				Valid:    false,
+				Errors:   []validationMessage{{Code: 999, Category: errortypes.CategoryUnknown, Message: "request.imp[0].ext.prebid.bidder.rubicon failed validation.\naccountId: Does not match pattern '^\\d+$'"}},
+				Warnings: []validationMessage{},
+			},
+		},
+		{
+			description:    "no-imps",
+			body:           `{"id":"req","site":{"page":"test.somepage.com"}}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedResponse: validationResponse{
+				Valid:    false,
+				Errors:   []validationMessage{{Code: 999, Category: errortypes.CategoryUnknown, Message: "request.imp must contain at least one element."}},
+				Warnings: []validationMessage{},
+			},
+		},
+		{
+			description:    "malformed-imp",
+			body:           `{"id":"req","site":{"page":"test.somepage.com"},"imp":[{"id":"imp-1","banner":{"format":{"w":300,"h":250}},"ext":{"prebid":{"bidder":{"appnexus":{"placementId":12883451}}}}}]}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedResponse: validationResponse{
+				Valid:    false,
+				Errors:   []validationMessage{{Code: errortypes.FailedToUnmarshalErrorCode, Category: errortypes.CategoryValidation, Message: "cannot unmarshal openrtb2.Banner.Format: decode slice: expect [ or n, but found {", Position: &openrtb_ext.ExtMessagePosition{Path: "imp[0].banner.format", Offset: 89, Expected: "array"}}},
+				Warnings: []validationMessage{},
+			},
+		},
+		{
+			description:    "unknown-stored-request",
+			body:           `{"id":"req","site":{"page":"test.somepage.com"},"imp":[{"id":"imp-1","ext":{"prebid":{"storedrequest":{"id":"missing"}}}}]}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedResponse: validationResponse{
+				Valid:    false,
+				Errors:   []validationMessage{{Code: 999, Category: errortypes.CategoryUnknown, Message: "Stored Imp with ID=\"missing\" not found."}},
+				Warnings: []validationMessage{},
+			},
+		},
+	}
+
+	validate, err := NewValidationEndpoint(
+		fakeUUIDGenerator{},
+		newParamsValidator(t),
+		empty_fetcher.EmptyFetcher{},
+		empty_fetcher.EmptyFetcher{},
+		&config.Configuration{MaxRequestSize: maxSize},
+		&metricsConfig.NilMetricsEngine{},
+		map[string]string{},
+		[]byte{},
+		openrtb_ext.BuildBidderMap(),
+		empty_fetcher.EmptyFetcher{},
+	)
+	require.NoError(t, err)
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			request := httptest.NewRequest(http.MethodPost, "/openrtb2/validate", strings.NewReader(test.body))
+			recorder := httptest.NewRecorder()
+			validate(recorder, request, nil)
+
+			assert.Equal(t, test.expectedStatus, recorder.Code)
+			assert.Equal(t, "application/json", recorder.Header().Get("Content-Type"))
+			var response validationResponse
+			require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+			assert.Equal(t, test.expectedResponse, response)
+		})
+	}
+}
+
+func TestNewValidationEndpointRequiresArguments(t *testing.T) {
+	_, err := NewValidationEndpoint(fakeUUIDGenerator{}, nil, empty_fetcher.EmptyFetcher{}, empty_fetcher.EmptyFetcher{}, &config.Configuration{}, &metricsConfig.NilMetricsEngine{}, nil, nil, nil, nil)
+	assert.EqualError(t, err, "NewValidationEndpoint requires non-nil arguments.")
+}
@@ -14,26 +14,31 @@ import (
 
 	"github.com/buger/jsonparser"
 	"github.com/gofrs/uuid"
-	"github.com/golang/glog"
 	"github.com/julienschmidt/httprouter"
 	"github.com/prebid/openrtb/v20/openrtb2"
 	"github.com/prebid/prebid-server/v2/hooks"
 	"github.com/prebid/prebid-server/v2/hooks/hookexecution"
+	"github.com/prebid/prebid-server/v2/ivt"
+	"github.com/prebid/prebid-server/v2/loadshedding"
+	"github.com/prebid/prebid-server/v2/logger"
 	"github.com/prebid/prebid-server/v2/ortb"
 	"github.com/prebid/prebid-server/v2/privacy"
 	jsonpatch "gopkg.in/evanphx/json-patch.v4"
 
 	accountService "github.com/prebid/prebid-server/v2/account"
 	"github.com/prebid/prebid-server/v2/analytics"
+	"github.com/prebid/prebid-server/v2/apikey"
 	"github.com/prebid/prebid-server/v2/config"
 	"github.com/prebid/prebid-server/v2/errortypes"
 	"github.com/prebid/prebid-server/v2/exchange"
+	"github.com/prebid/prebid-server/v2/geolocation"
 	"github.com/prebid/prebid-server/v2/metrics"
 	"github.com/prebid/prebid-server/v2/openrtb_ext"
 	"github.com/prebid/prebid-server/v2/prebid_cache_client"
 	"github.com/prebid/prebid-server/v2/stored_requests"
 	"github.com/prebid/prebid-server/v2/stored_requests/backends/empty_fetcher"
 	"github.com/prebid/prebid-server/v2/usersync"
+	"github.com/prebid/prebid-server/v2/util/bufferpool"
 	"github.com/prebid/prebid-server/v2/util/iputil"
 	"github.com/prebid/prebid-server/v2/util/jsonutil"
 	"github.com/prebid/prebid-server/v2/util/ptrutil"
@@ -58,6 +63,9 @@ func NewVideoEndpoint(
 	bidderMap map[string]openrtb_ext.BidderName,
 	cache prebid_cache_client.Client,
 	tmaxAdjustments *exchange.TmaxAdjustmentsPreprocessed,
+	apiKeyAuthenticator *apikey.Authenticator,
+	geoEnricher *geolocation.Enricher,
+	ivtFilter *ivt.Filter,
 ) (httprouter.Handle, error) {
 
 	if ex == nil || validator == nil || requestsById == nil || accounts == nil || cfg == nil || met == nil {
@@ -93,7 +101,17 @@ func NewVideoEndpoint(
 		empty_fetcher.EmptyFetcher{},
 		hooks.EmptyPlanBuilder{},
 		tmaxAdjustments,
-		openrtb_ext.NormalizeBidderName}).VideoAuctionEndpoint), nil
+		openrtb_ext.NormalizeBidderName,
+		nil,
+		nil,
+		ivtFilter,
+		apiKeyAuthenticator,
+		nil,
+		geoEnricher,
+		nil,
+		nil,
+		nil,
+		nil}).VideoAuctionEndpoint), nil
 }
 
 /*
@@ -170,9 +188,9 @@ func (deps *endpointDeps) VideoAuctionEndpoint(w http.ResponseWriter, r *http.Re
 		R: r.Body,
 		N: deps.cfg.MaxRequestSize,
 	}
-	requestJson, err := io.ReadAll(lr)
+	requestJson, err := bufferpool.ReadAll(lr)
 	if err != nil {
-		handleError(&labels, w, []error{err}, &vo, &debugLog)
+		handleError(r.Context(), &labels, w, []error{err}, &vo, &debugLog)
 		return
 	}
 
@@ -191,27 +209,27 @@ func (deps *endpointDeps) VideoAuctionEndpoint(w http.ResponseWriter, r *http.Re
 
 	if err != nil {
 		if deps.cfg.VideoStoredRequestRequired {
-			handleError(&labels, w, []error{err}, &vo, &debugLog)
+			handleError(r.Context(), &labels, w, []error{err}, &vo, &debugLog)
 			return
 		}
 	} else {
 		storedRequest, errs := deps.loadStoredVideoRequest(context.Background(), storedRequestId)
 		if len(errs) > 0 {
-			handleError(&labels, w, errs, &vo, &debugLog)
+			handleError(r.Context(), &labels, w, errs, &vo, &debugLog)
 			return
 		}
 
 		//merge incoming req with stored video req
 		resolvedRequest, err = jsonpatch.MergePatch(storedRequest, requestJson)
 		if err != nil {
-			handleError(&labels, w, []error{err}, &vo, &debugLog)
+			handleError(r.Context(), &labels, w, []error{err}, &vo, &debugLog)
 			return
 		}
 	}
 	//unmarshal and validate combined result
 	videoBidReq, errL, podErrors := deps.parseVideoRequest(resolvedRequest, r.Header)
 	if len(errL) > 0 {
-		handleError(&labels, w, errL, &vo, &debugLog)
+		handleError(r.Context(), &labels, w, errL, &vo, &debugLog)
 		return
 	}
 
@@ -221,7 +239,7 @@ func (deps *endpointDeps) VideoAuctionEndpoint(w http.ResponseWriter, r *http.Re
 	if deps.defaultRequest {
 		if err := jsonutil.UnmarshalValid(deps.defReqJSON, bidReq); err != nil {
 			err = fmt.Errorf("Invalid JSON in Default Request Settings: %s", err)
-			handleError(&labels, w, []error{err}, &vo, &debugLog)
+			handleError(r.Context(), &labels, w, []error{err}, &vo, &debugLog)
 			return
 		}
 	}
@@ -249,7 +267,7 @@ func (deps *endpointDeps) VideoAuctionEndpoint(w http.ResponseWriter, r *http.Re
 		}
 		err := fmt.Errorf("all pods are incorrect: %s", strings.Join(resPodErr, "; "))
 		errL = append(errL, err)
-		handleError(&labels, w, errL, &vo, &debugLog)
+		handleError(r.Context(), &labels, w, errL, &vo, &debugLog)
 		return
 	}
 
@@ -263,7 +281,7 @@ func (deps *endpointDeps) VideoAuctionEndpoint(w http.ResponseWriter, r *http.Re
 	deps.setFieldsImplicitly(r, bidReqWrapper)
 
 	if err := ortb.SetDefaults(bidReqWrapper); err != nil {
-		handleError(&labels, w, errL, &vo, &debugLog)
+		handleError(r.Context(), &labels, w, errL, &vo, &debugLog)
 		return
 	}
 
@@ -296,13 +314,32 @@ func (deps *endpointDeps) VideoAuctionEndpoint(w http.ResponseWriter, r *http.Re
 	// Look up account now that we have resolved the pubID value
 	account, acctIDErrs := accountService.GetAccount(ctx, deps.cfg, deps.accounts, labels.PubID, deps.metricsEngine)
 	if len(acctIDErrs) > 0 {
-		handleError(&labels, w, acctIDErrs, &vo, &debugLog)
+		handleError(r.Context(), &labels, w, acctIDErrs, &vo, &debugLog)
+		return
+	}
+
+	if errL = deps.authenticateAPIKey(r, account); len(errL) > 0 {
+		handleError(r.Context(), &labels, w, errL, &vo, &debugLog)
+		return
+	}
+
+	if errL = deps.checkRequestSize(len(requestJson), account); len(errL) > 0 {
+		handleError(r.Context(), &labels, w, errL, &vo, &debugLog)
+		return
+	}
+
+	setClientHintsImplicitly(r, bidReqWrapper, account)
+	deps.geoEnricher.Enrich(bidReqWrapper.Device)
+
+	if err := deps.screenInvalidTraffic(bidReqWrapper, account); err != nil {
+		errL = append(errL, err)
+		handleError(r.Context(), &labels, w, errL, &vo, &debugLog)
 		return
 	}
 
 	errL = deps.validateRequest(account, r, bidReqWrapper, false, false, nil, false)
 	if errortypes.ContainsFatalError(errL) {
-		handleError(&labels, w, errL, &vo, &debugLog)
+		handleError(r.Context(), &labels, w, errL, &vo, &debugLog)
 		return
 	}
 
@@ -322,6 +359,7 @@ func (deps *endpointDeps) VideoAuctionEndpoint(w http.ResponseWriter, r *http.Re
 		TmaxAdjustments:            deps.tmaxAdjustments,
 		Activities:                 activityControl,
 	}
+	loadshedding.SkipDebug(r.Context(), &auctionRequest.Account)
 
 	auctionResponse, err := deps.ex.HoldAuction(ctx, auctionRequest, &debugLog)
 	defer func() {
@@ -338,7 +376,7 @@ func (deps *endpointDeps) VideoAuctionEndpoint(w http.ResponseWriter, r *http.Re
 	vo.SeatNonBid = auctionResponse.GetSeatNonBid()
 	if err != nil {
 		errL := []error{err}
-		handleError(&labels, w, errL, &vo, &debugLog)
+		handleError(r.Context(), &labels, w, errL, &vo, &debugLog)
 		return
 	}
 
@@ -346,13 +384,13 @@ func (deps *endpointDeps) VideoAuctionEndpoint(w http.ResponseWriter, r *http.Re
 	bidResp, err := buildVideoResponse(response, podErrors)
 	if err != nil {
 		errL := []error{err}
-		handleError(&labels, w, errL, &vo, &debugLog)
+		handleError(r.Context(), &labels, w, errL, &vo, &debugLog)
 		return
 	}
 	if bidReq.Test == 1 {
 		err = setSeatNonBidRaw(bidReqWrapper, auctionResponse)
 		if err != nil {
-			glog.Errorf("Error setting seat non-bid: %v", err)
+			logger.Ctx(r.Context()).Errorf("Error setting seat non-bid: %v", err)
 		}
 		bidResp.Ext = response.Ext
 	}
@@ -377,7 +415,7 @@ func (deps *endpointDeps) VideoAuctionEndpoint(w http.ResponseWriter, r *http.Re
 	resp, err := jsonutil.Marshal(bidResp)
 	if err != nil {
 		errL := []error{err}
-		handleError(&labels, w, errL, &vo, &debugLog)
+		handleError(r.Context(), &labels, w, errL, &vo, &debugLog)
 		return
 	}
 
@@ -392,7 +430,7 @@ func cleanupVideoBidRequest(videoReq *openrtb_ext.BidRequestVideo, podErrors []P
 	return videoReq
 }
 
-func handleError(labels *metrics.Labels, w http.ResponseWriter, errL []error, vo *analytics.VideoObject, debugLog *exchange.DebugLog) {
+func handleError(ctx context.Context, labels *metrics.Labels, w http.ResponseWriter, errL []error, vo *analytics.VideoObject, debugLog *exchange.DebugLog) {
 	if debugLog != nil && debugLog.DebugEnabledOrOverridden {
 		if rawUUID, err := uuid.NewV4(); err == nil {
 			debugLog.CacheKey = rawUUID.String()
@@ -404,7 +442,7 @@ func handleError(labels *metrics.Labels, w http.ResponseWriter, errL []error, vo
 	var status int = http.StatusInternalServerError
 	for _, er := range errL {
 		erVal := errortypes.ReadCode(er)
-		if erVal == errortypes.BlacklistedAppErrorCode || erVal == errortypes.AccountDisabledErrorCode {
+		if erVal == errortypes.BlacklistedAppErrorCode || erVal == errortypes.AccountDisabledErrorCode || erVal == errortypes.InvalidTrafficErrorCode {
 			status = http.StatusServiceUnavailable
 			labels.RequestStatus = metrics.RequestStatusBlacklisted
 			break
@@ -416,13 +454,25 @@ func handleError(labels *metrics.Labels, w http.ResponseWriter, errL []error, vo
 			status = http.StatusInternalServerError
 			labels.RequestStatus = metrics.RequestStatusAccountConfigErr
 			break
+		} else if erVal == errortypes.UnauthorizedErrorCode {
+			status = http.StatusUnauthorized
+			labels.RequestStatus = metrics.RequestStatusBadInput
+			break
+		} else if erVal == errortypes.RateLimitedErrorCode {
+			status = http.StatusTooManyRequests
+			labels.RequestStatus = metrics.RequestStatusBadInput
+			break
+		} else if erVal == errortypes.RequestTooLargeErrorCode {
+			status = http.StatusRequestEntityTooLarge
+			labels.RequestStatus = metrics.RequestStatusBadInput
+			break
 		}
 		errors = fmt.Sprintf("%s %s", errors, er.Error())
 	}
 	w.WriteHeader(status)
 	vo.Status = status
 	fmt.Fprintf(w, "Critical error while running the video endpoint: %v", errors)
-	glog.Errorf("/openrtb2/video Critical error: %v", errors)
+	logger.Ctx(ctx).Errorf("/openrtb2/video Critical error: %v", errors)
 	vo.Errors = append(vo.Errors, errL...)
 }
 
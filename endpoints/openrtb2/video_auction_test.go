@@ -839,6 +839,30 @@ func TestHandleError(t *testing.T) {
 			wantCode:          500,
 			wantMetricsStatus: metrics.RequestStatusAccountConfigErr,
 		},
+		{
+			description: "Unauthorized error - return 401 with bad input metrics status",
+			giveErrors: []error{
+				&errortypes.Unauthorized{},
+			},
+			wantCode:          401,
+			wantMetricsStatus: metrics.RequestStatusBadInput,
+		},
+		{
+			description: "Rate limited error - return 429 with bad input metrics status",
+			giveErrors: []error{
+				&errortypes.RateLimited{},
+			},
+			wantCode:          429,
+			wantMetricsStatus: metrics.RequestStatusBadInput,
+		},
+		{
+			description: "Request too large error - return 413 with bad input metrics status",
+			giveErrors: []error{
+				&errortypes.RequestTooLarge{},
+			},
+			wantCode:          413,
+			wantMetricsStatus: metrics.RequestStatusBadInput,
+		},
 		{
 			description: "Multiple generic errors - return 500 with generic error metrics status",
 			giveErrors: []error{
@@ -865,7 +889,7 @@ func TestHandleError(t *testing.T) {
 		}
 
 		recorder := httptest.NewRecorder()
-		handleError(&labels, recorder, tt.giveErrors, &vo, nil)
+		handleError(context.Background(), &labels, recorder, tt.giveErrors, &vo, nil)
 
 		assert.Equal(t, tt.wantMetricsStatus, labels.RequestStatus, tt.description)
 		assert.Equal(t, tt.wantCode, recorder.Code, tt.description)
@@ -1014,7 +1038,7 @@ func TestHandleErrorDebugLog(t *testing.T) {
 		DebugOverride:            false,
 		DebugEnabledOrOverridden: true,
 	}
-	handleError(&labels, recorder, []error{err1, err2}, &vo, &debugLog)
+	handleError(context.Background(), &labels, recorder, []error{err1, err2}, &vo, &debugLog)
 
 	assert.Equal(t, metrics.RequestStatusErr, labels.RequestStatus, "labels.RequestStatus should indicate an error")
 	assert.Equal(t, 500, recorder.Code, "Error status should be written to writer")
@@ -1228,6 +1252,16 @@ func mockDepsWithMetrics(t *testing.T, ex *mockExchangeVideo) (*endpointDeps, *m
 		hooks.EmptyPlanBuilder{},
 		nil,
 		openrtb_ext.NormalizeBidderName,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
 	}
 	return deps, metrics, mockModule
 }
@@ -1277,6 +1311,16 @@ func mockDeps(t *testing.T, ex *mockExchangeVideo) *endpointDeps {
 		hooks.EmptyPlanBuilder{},
 		nil,
 		openrtb_ext.NormalizeBidderName,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
 	}
 }
 
@@ -1302,6 +1346,16 @@ func mockDepsAppendBidderNames(t *testing.T, ex *mockExchangeAppendBidderNames)
 		hooks.EmptyPlanBuilder{},
 		nil,
 		openrtb_ext.NormalizeBidderName,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
 	}
 
 	return deps
@@ -1329,6 +1383,16 @@ func mockDepsNoBids(t *testing.T, ex *mockExchangeVideoNoBids) *endpointDeps {
 		hooks.EmptyPlanBuilder{},
 		nil,
 		openrtb_ext.NormalizeBidderName,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
 	}
 
 	return edep
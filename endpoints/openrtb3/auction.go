@@ -0,0 +1,119 @@
+package openrtb3
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v2/responsesigning"
+	"github.com/prebid/prebid-server/v2/util/jsonutil"
+)
+
+// NewAuctionEndpoint returns an endpoint which takes OpenRTB 3.0 requests, maps them to OpenRTB 2.x, and
+// holds their auctions with the auction endpoint, whose OpenRTB 2.x responses it maps back to OpenRTB 3.0.
+// Responses which aren't bid responses, such as errors, are passed on as they are.
+func NewAuctionEndpoint(auction httprouter.Handle, maxRequestSize int64) httprouter.Handle {
+	return func(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+		bidRequest, err := readRequest(r, maxRequestSize)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprintf(w, "Invalid request: %s\n", err.Error())
+			return
+		}
+		body, err := jsonutil.Marshal(bidRequest)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			fmt.Fprintf(w, "Critical error while translating the request: %v", err)
+			return
+		}
+
+		auctionRequest := r.Clone(r.Context())
+		auctionRequest.Body = io.NopCloser(bytes.NewReader(body))
+		auctionRequest.ContentLength = int64(len(body))
+		recorder := &auctionResponse{header: make(http.Header)}
+		auction(recorder, auctionRequest, params)
+
+		for name, values := range recorder.header {
+			// the response is rewritten, so it no longer matches its length or signature
+			if name == "Content-Length" || name == responsesigning.Header {
+				continue
+			}
+			w.Header()[name] = values
+		}
+		if recorder.status != http.StatusOK {
+			w.WriteHeader(recorder.status)
+			w.Write(recorder.body.Bytes())
+			return
+		}
+
+		var bidResponse openrtb2.BidResponse
+		if err := jsonutil.Unmarshal(recorder.body.Bytes(), &bidResponse); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			fmt.Fprintf(w, "Critical error while translating the response: %v", err)
+			return
+		}
+		response, err := translateResponse(&bidResponse)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			fmt.Fprintf(w, "Critical error while translating the response: %v", err)
+			return
+		}
+		enc := json.NewEncoder(w)
+		enc.SetEscapeHTML(false)
+		enc.Encode(response)
+	}
+}
+
+// readRequest reads an OpenRTB 3.0 request, and maps it to OpenRTB 2.x.
+func readRequest(r *http.Request, maxRequestSize int64) (*openrtb2.BidRequest, error) {
+	if r.Header.Get("Content-Encoding") != "" {
+		return nil, errors.New("OpenRTB 3.0 requests can't be compressed")
+	}
+
+	reader := io.Reader(r.Body)
+	if maxRequestSize > 0 {
+		reader = io.LimitReader(r.Body, maxRequestSize+1)
+	}
+	body, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+	if maxRequestSize > 0 && int64(len(body)) > maxRequestSize {
+		return nil, fmt.Errorf("request size exceeded max size of %d bytes.", maxRequestSize)
+	}
+
+	request, err := parseRequest(body)
+	if err != nil {
+		return nil, err
+	}
+	return translateRequest(request)
+}
+
+// auctionResponse keeps the response written by the auction endpoint, so that it can be translated.
+type auctionResponse struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (w *auctionResponse) Header() http.Header {
+	return w.header
+}
+
+func (w *auctionResponse) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+}
+
+func (w *auctionResponse) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	return w.body.Write(b)
+}
@@ -0,0 +1,96 @@
+package openrtb3
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/prebid/prebid-server/v2/responsesigning"
+	"github.com/stretchr/testify/assert"
+)
+
+const testRequest = `{"openrtb":{"ver":"3.0","domainspec":"adcom","domainver":"1.0","request":{"id":"req","item":[{"id":"1","spec":{"placement":{"display":{"w":300,"h":250}}}}]}}}`
+
+func TestAuctionEndpoint(t *testing.T) {
+	testCases := []struct {
+		description     string
+		body            string
+		contentEncoding string
+		auctionStatus   int
+		auctionResponse string
+		expectedStatus  int
+		expectedBody    string
+		expectedAuction string
+	}{
+		{
+			description:     "bid",
+			body:            testRequest,
+			auctionStatus:   http.StatusOK,
+			auctionResponse: `{"id":"req","seatbid":[{"seat":"appnexus","bid":[{"id":"bid","impid":"1","price":1,"adm":"<div/>","mtype":1}]}],"cur":"USD"}`,
+			expectedStatus:  http.StatusOK,
+			expectedBody:    `{"openrtb":{"ver":"3.0","domainspec":"adcom","domainver":"1.0","response":{"id":"req","cur":"USD","seatbid":[{"seat":"appnexus","bid":[{"id":"bid","item":"1","price":1,"media":{"ad":{"id":"","display":{"adm":"<div/>"}}}}]}]}}}`,
+			expectedAuction: `{"id":"req","imp":[{"id":"1","banner":{"w":300,"h":250},"secure":0}]}`,
+		},
+		{
+			description:     "auction error",
+			body:            testRequest,
+			auctionStatus:   http.StatusBadRequest,
+			auctionResponse: "Invalid request: request.imp[0] has no bidders\n",
+			expectedStatus:  http.StatusBadRequest,
+			expectedBody:    "Invalid request: request.imp[0] has no bidders\n",
+		},
+		{
+			description:    "invalid request",
+			body:           `{"openrtb":{"ver":"2.6"}}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "Invalid request: openrtb.ver must be 3.x. Got \"2.6\"\n",
+		},
+		{
+			description:     "compressed",
+			body:            testRequest,
+			contentEncoding: "gzip",
+			expectedStatus:  http.StatusBadRequest,
+			expectedBody:    "Invalid request: OpenRTB 3.0 requests can't be compressed\n",
+		},
+		{
+			description:    "too large",
+			body:           testRequest + strings.Repeat(" ", 1000),
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "Invalid request: request size exceeded max size of 1000 bytes.\n",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			var auctionBody string
+			auction := func(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+				body, _ := io.ReadAll(r.Body)
+				auctionBody = string(body)
+				w.Header().Set("Content-Type", "application/json")
+				w.Header().Set(responsesigning.Header, "signature")
+				w.WriteHeader(test.auctionStatus)
+				io.WriteString(w, test.auctionResponse)
+			}
+
+			req := httptest.NewRequest("POST", "/openrtb3/auction", strings.NewReader(test.body))
+			if test.contentEncoding != "" {
+				req.Header.Set("Content-Encoding", test.contentEncoding)
+			}
+			recorder := httptest.NewRecorder()
+			NewAuctionEndpoint(auction, 1000)(recorder, req, nil)
+
+			assert.Equal(t, test.expectedStatus, recorder.Code)
+			if test.expectedStatus == http.StatusOK {
+				assert.JSONEq(t, test.expectedBody, recorder.Body.String())
+				assert.JSONEq(t, test.expectedAuction, auctionBody)
+				assert.Equal(t, "application/json", recorder.Header().Get("Content-Type"))
+				assert.Empty(t, recorder.Header().Get(responsesigning.Header))
+			} else {
+				assert.Equal(t, test.expectedBody, recorder.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,538 @@
+package openrtb3
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/prebid/openrtb/v20/adcom1"
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/openrtb/v20/openrtb3"
+	"github.com/prebid/prebid-server/v2/util/countryutil"
+	"github.com/prebid/prebid-server/v2/util/jsonutil"
+	"github.com/prebid/prebid-server/v2/util/ptrutil"
+)
+
+// requestContext is the AdCOM context of a request. AdCOM has no way to tell a flag which is 0 from one which
+// is missing, so the flags whose absence means something to the auction are decoded as pointers.
+type requestContext struct {
+	adcom1.RequestContext
+	Device *device `json:"device,omitempty"`
+	Regs   *regs   `json:"regs,omitempty"`
+}
+
+type device struct {
+	adcom1.Device
+	DNT *int8 `json:"dnt,omitempty"`
+	Lmt *int8 `json:"lmt,omitempty"`
+}
+
+type regs struct {
+	adcom1.Regs
+	GDPR *int8 `json:"gdpr,omitempty"`
+}
+
+// parseRequest parses the body of a request to /openrtb3/auction, and checks it has an OpenRTB 3.0 request
+// with AdCOM 1.x objects.
+func parseRequest(body []byte) (*openrtb3.Request, error) {
+	// Seats are the buyers allowed to bid unless the request says otherwise.
+	request := &openrtb3.Request{WSeat: 1}
+	parsed := openrtb3.Body{OpenRTB: openrtb3.OpenRTB{Request: request}}
+	if err := jsonutil.UnmarshalValid(body, &parsed); err != nil {
+		return nil, err
+	}
+	if !strings.HasPrefix(parsed.OpenRTB.Ver, "3.") {
+		return nil, fmt.Errorf("openrtb.ver must be 3.x. Got %q", parsed.OpenRTB.Ver)
+	}
+	if parsed.OpenRTB.DomainSpec != "" && !strings.EqualFold(parsed.OpenRTB.DomainSpec, "adcom") {
+		return nil, fmt.Errorf("openrtb.domainspec must be adcom. Got %q", parsed.OpenRTB.DomainSpec)
+	}
+	if parsed.OpenRTB.DomainVer != "" && !strings.HasPrefix(parsed.OpenRTB.DomainVer, "1.") {
+		return nil, fmt.Errorf("openrtb.domainver must be 1.x. Got %q", parsed.OpenRTB.DomainVer)
+	}
+	if parsed.OpenRTB.Request != request || request.ID == "" {
+		return nil, errors.New("openrtb.request.id is required")
+	}
+	if len(request.Item) == 0 {
+		return nil, errors.New("openrtb.request.item must have at least one item")
+	}
+	return request, nil
+}
+
+// translateRequest maps an OpenRTB 3.0 request to the OpenRTB 2.x request the auction takes. Native
+// placements and the DOOH and content objects aren't mapped, since AdCOM describes them differently enough
+// that they'd have to be rebuilt rather than translated.
+func translateRequest(request *openrtb3.Request) (*openrtb2.BidRequest, error) {
+	bidRequest := &openrtb2.BidRequest{
+		ID:   request.ID,
+		Test: request.Test,
+		TMax: request.TMax,
+		AT:   int64(request.AT),
+		Cur:  request.Cur,
+		Ext:  request.Ext,
+	}
+	if len(request.Seat) > 0 {
+		if request.WSeat == 1 {
+			bidRequest.WSeat = request.Seat
+		} else {
+			bidRequest.BSeat = request.Seat
+		}
+	}
+	if request.Source != nil {
+		bidRequest.Source = &openrtb2.Source{TID: request.Source.TID, PChain: request.Source.PChain, Ext: request.Source.Ext}
+	}
+
+	bidRequest.Imp = make([]openrtb2.Imp, 0, len(request.Item))
+	for i, item := range request.Item {
+		imp, err := translateItem(item)
+		if err != nil {
+			return nil, fmt.Errorf("openrtb.request.item[%d]: %v", i, err)
+		}
+		bidRequest.Imp = append(bidRequest.Imp, imp)
+	}
+
+	if len(request.Context) > 0 {
+		var context requestContext
+		if err := jsonutil.UnmarshalValid(request.Context, &context); err != nil {
+			return nil, fmt.Errorf("openrtb.request.context: %v", err)
+		}
+		translateContext(&context, bidRequest)
+	}
+	if request.CData != "" {
+		if bidRequest.User == nil {
+			bidRequest.User = &openrtb2.User{}
+		}
+		bidRequest.User.CustomData = request.CData
+	}
+	return bidRequest, nil
+}
+
+func translateItem(item openrtb3.Item) (openrtb2.Imp, error) {
+	imp := openrtb2.Imp{
+		ID:          item.ID,
+		BidFloor:    item.Flr,
+		BidFloorCur: item.FlrCur,
+		Exp:         item.Exp,
+		DT:          float64(item.DT),
+		Ext:         item.Ext,
+	}
+	if item.Qty > 0 {
+		imp.Qty = &openrtb2.Qty{Multiplier: float64(item.Qty)}
+	}
+	for _, metric := range item.Metric {
+		imp.Metric = append(imp.Metric, openrtb2.Metric{Type: metric.Type, Value: metric.Value, Vendor: metric.Vendor, Ext: metric.Ext})
+	}
+	if len(item.Deal) > 0 || item.Private == 1 {
+		imp.PMP = &openrtb2.PMP{PrivateAuction: item.Private}
+		for _, deal := range item.Deal {
+			imp.PMP.Deals = append(imp.PMP.Deals, openrtb2.Deal{
+				ID:          deal.ID,
+				BidFloor:    deal.Flr,
+				BidFloorCur: deal.FlrCur,
+				AT:          int64(deal.AT),
+				WSeat:       deal.WSeat,
+				WADomain:    deal.WADomain,
+				Ext:         deal.Ext,
+			})
+		}
+	}
+
+	if len(item.Spec) == 0 {
+		return imp, nil
+	}
+	var spec adcom1.ItemSpec
+	if err := jsonutil.UnmarshalValid(item.Spec, &spec); err != nil {
+		return openrtb2.Imp{}, fmt.Errorf("spec: %v", err)
+	}
+	if placement := spec.Placement; placement != nil {
+		imp.TagID = placement.TagID
+		imp.Secure = ptrutil.ToPtr(placement.Secure)
+		imp.Rwdd = placement.Reward
+		imp.SSAI = openrtb2.AdInsertion(placement.SSAI)
+		imp.DisplayManager = placement.SDK
+		imp.DisplayManagerVer = placement.SDKVer
+		if display := placement.Display; display != nil {
+			imp.Instl = display.Instl
+			imp.IframeBuster = display.IfrBust
+			imp.ClickBrowser = clickBrowser(display.ClkType)
+			imp.Banner = translateDisplay(display)
+		}
+		if video := placement.Video; video != nil {
+			if imp.ClickBrowser == nil {
+				imp.ClickBrowser = clickBrowser(video.ClkType)
+			}
+			imp.Video = translateVideo(video)
+		}
+		if placement.Audio != nil {
+			imp.Audio = translateAudio(placement.Audio)
+		}
+	}
+	return imp, nil
+}
+
+// clickBrowser maps the AdCOM click type to whether clicks open in the native browser.
+func clickBrowser(clkType adcom1.ClickType) *int8 {
+	switch clkType {
+	case adcom1.ClickEmbedded:
+		return ptrutil.ToPtr[int8](0)
+	case adcom1.ClickNative:
+		return ptrutil.ToPtr[int8](1)
+	}
+	return nil
+}
+
+// translateDisplay maps a display placement to a banner. The placement is either sized itself, or has the
+// formats it can take, which banner formats carry along with the directions they can expand in.
+func translateDisplay(display *adcom1.DisplayPlacement) *openrtb2.Banner {
+	if display.NativeFmt != nil && display.W == 0 && display.H == 0 && len(display.DisplayFmt) == 0 {
+		return nil
+	}
+	banner := &openrtb2.Banner{
+		MIMEs:    display.MIME,
+		TopFrame: display.TopFrame,
+		API:      display.API,
+		Ext:      display.Ext,
+	}
+	if display.W > 0 && display.H > 0 {
+		banner.W = ptrutil.ToPtr(display.W)
+		banner.H = ptrutil.ToPtr(display.H)
+	}
+	if display.Pos != 0 {
+		banner.Pos = ptrutil.ToPtr(display.Pos)
+	}
+	for _, format := range display.DisplayFmt {
+		banner.Format = append(banner.Format, openrtb2.Format{W: format.W, H: format.H, WRatio: int64(format.WRatio), HRatio: int64(format.HRatio), Ext: format.Ext})
+		for _, dir := range format.ExpDir {
+			if !containsExpDir(banner.ExpDir, dir) {
+				banner.ExpDir = append(banner.ExpDir, dir)
+			}
+		}
+	}
+	return banner
+}
+
+func containsExpDir(dirs []adcom1.ExpandableDirection, dir adcom1.ExpandableDirection) bool {
+	for _, d := range dirs {
+		if d == dir {
+			return true
+		}
+	}
+	return false
+}
+
+func translateVideo(placement *adcom1.VideoPlacement) *openrtb2.Video {
+	video := &openrtb2.Video{
+		MIMEs:         placement.MIME,
+		MinDuration:   placement.MinDur,
+		MaxDuration:   placement.MaxDur,
+		RqdDurs:       placement.RqdDurs,
+		Protocols:     placement.CType,
+		Placement:     placement.PType,
+		Linearity:     placement.Linear,
+		SkipMin:       placement.SkipMin,
+		SkipAfter:     placement.SkipAfter,
+		PlaybackEnd:   placement.PlayEnd,
+		Delivery:      placement.Delivery,
+		MaxSeq:        placement.MaxSeq,
+		PodDur:        placement.PodDur,
+		PodSeq:        placement.PodSeq,
+		SlotInPod:     placement.SlotInPod,
+		MinCPMPerSec:  placement.MinCPMPerSec,
+		MaxExtended:   placement.MaxExt,
+		MinBitRate:    placement.MinBitR,
+		MaxBitRate:    placement.MaxBitR,
+		API:           placement.API,
+		CompanionType: placement.CompType,
+		Ext:           placement.Ext,
+	}
+	if placement.W > 0 && placement.H > 0 {
+		video.W = ptrutil.ToPtr(placement.W)
+		video.H = ptrutil.ToPtr(placement.H)
+	}
+	if placement.Delay != 0 {
+		video.StartDelay = ptrutil.ToPtr(placement.Delay)
+	}
+	if placement.Skip == 1 {
+		video.Skip = ptrutil.ToPtr(placement.Skip)
+	}
+	if placement.PlayMethod != 0 {
+		video.PlaybackMethod = []adcom1.PlaybackMethod{placement.PlayMethod}
+	}
+	if placement.Pos != 0 {
+		video.Pos = ptrutil.ToPtr(placement.Pos)
+	}
+	if placement.PodID != 0 {
+		video.PodID = strconv.FormatInt(placement.PodID, 10)
+	}
+	return video
+}
+
+func translateAudio(placement *adcom1.AudioPlacement) *openrtb2.Audio {
+	audio := &openrtb2.Audio{
+		MIMEs:         placement.MIME,
+		MinDuration:   placement.MinDur,
+		MaxDuration:   placement.MaxDur,
+		RqdDurs:       placement.RqdDurs,
+		Protocols:     placement.CType,
+		Feed:          placement.Feed,
+		Delivery:      placement.Delivery,
+		MaxSeq:        placement.MaxSeq,
+		PodDur:        placement.PodDur,
+		PodSeq:        placement.PodSeq,
+		SlotInPod:     placement.SlotInPod,
+		MinCPMPerSec:  placement.MinCPMPerSec,
+		MaxExtended:   placement.MaxExt,
+		MinBitrate:    placement.MinBitR,
+		MaxBitrate:    placement.MaxBitR,
+		API:           placement.API,
+		CompanionType: placement.CompType,
+		Ext:           placement.Ext,
+	}
+	if placement.Delay != 0 {
+		audio.StartDelay = ptrutil.ToPtr(placement.Delay)
+	}
+	if placement.NVol != 0 {
+		audio.NVol = ptrutil.ToPtr(placement.NVol)
+	}
+	if placement.PodID != 0 {
+		audio.PodID = strconv.FormatInt(placement.PodID, 10)
+	}
+	return audio
+}
+
+func translateContext(context *requestContext, bidRequest *openrtb2.BidRequest) {
+	if site := context.Site; site != nil {
+		bidRequest.Site = &openrtb2.Site{
+			ID:            site.ID,
+			Name:          site.Name,
+			Domain:        site.Domain,
+			CatTax:        site.CatTax,
+			Cat:           site.Cat,
+			SectionCat:    site.SectCat,
+			PageCat:       site.PageCat,
+			Page:          site.Page,
+			Ref:           site.Ref,
+			Search:        site.Search,
+			Mobile:        flag(site.Mobile),
+			PrivacyPolicy: flag(site.PrivPolicy),
+			Publisher:     translatePublisher(site.Pub),
+			Keywords:      site.Keywords,
+			KwArray:       site.KwArray,
+			Ext:           site.Ext,
+		}
+	}
+	if app := context.App; app != nil {
+		bidRequest.App = &openrtb2.App{
+			ID:            app.ID,
+			Name:          app.Name,
+			Bundle:        app.Bundle,
+			Domain:        app.Domain,
+			StoreURL:      app.StoreURL,
+			CatTax:        app.CatTax,
+			Cat:           app.Cat,
+			SectionCat:    app.SectCat,
+			PageCat:       app.PageCat,
+			Ver:           app.Ver,
+			PrivacyPolicy: flag(app.PrivPolicy),
+			Paid:          flag(app.Paid),
+			Publisher:     translatePublisher(app.Pub),
+			Keywords:      app.Keywords,
+			KwArray:       app.KwArray,
+			Ext:           app.Ext,
+		}
+	}
+	if user := context.User; user != nil {
+		bidRequest.User = &openrtb2.User{
+			ID:       user.ID,
+			BuyerUID: user.BuyerUID,
+			Yob:      user.YOB,
+			Gender:   user.Gender,
+			Keywords: user.Keywords,
+			KwArray:  user.KwArray,
+			Geo:      translateGeo(user.Geo),
+			Consent:  user.Consent,
+			Ext:      user.Ext,
+		}
+		for _, data := range user.Data {
+			bidRequest.User.Data = append(bidRequest.User.Data, translateData(data))
+		}
+		for _, eid := range user.EIDs {
+			translated := openrtb2.EID{Source: eid.Source, Ext: eid.Ext}
+			for _, uid := range eid.UIDs {
+				translated.UIDs = append(translated.UIDs, openrtb2.UID{ID: uid.ID, AType: uid.AType, Ext: uid.Ext})
+			}
+			bidRequest.User.EIDs = append(bidRequest.User.EIDs, translated)
+		}
+	}
+	if context.Device != nil {
+		bidRequest.Device = translateDevice(context.Device)
+	}
+	if regs := context.Regs; regs != nil {
+		bidRequest.Regs = &openrtb2.Regs{COPPA: regs.COPPA, GDPR: regs.GDPR, Ext: regs.Ext}
+	}
+	if restrictions := context.Restrictions; restrictions != nil {
+		bidRequest.BCat = restrictions.BCat
+		bidRequest.CatTax = restrictions.CatTax
+		bidRequest.BAdv = restrictions.BAdv
+		bidRequest.BApp = restrictions.BApp
+		if len(restrictions.BAttr) > 0 {
+			for i := range bidRequest.Imp {
+				imp := &bidRequest.Imp[i]
+				if imp.Banner != nil {
+					imp.Banner.BAttr = restrictions.BAttr
+				}
+				if imp.Video != nil {
+					imp.Video.BAttr = restrictions.BAttr
+				}
+				if imp.Audio != nil {
+					imp.Audio.BAttr = restrictions.BAttr
+				}
+			}
+		}
+	}
+}
+
+// flag returns a pointer to an AdCOM flag which is set, or nil otherwise.
+func flag(v int8) *int8 {
+	if v == 0 {
+		return nil
+	}
+	return ptrutil.ToPtr(v)
+}
+
+func translatePublisher(pub *adcom1.Publisher) *openrtb2.Publisher {
+	if pub == nil {
+		return nil
+	}
+	return &openrtb2.Publisher{ID: pub.ID, Name: pub.Name, CatTax: pub.CatTax, Cat: pub.Cat, Domain: pub.Domain, Ext: pub.Ext}
+}
+
+func translateData(data adcom1.Data) openrtb2.Data {
+	translated := openrtb2.Data{ID: data.ID, Name: data.Name, Ext: data.Ext}
+	for _, segment := range data.Segment {
+		translated.Segment = append(translated.Segment, openrtb2.Segment{ID: segment.ID, Name: segment.Name, Value: segment.Value, Ext: segment.Ext})
+	}
+	return translated
+}
+
+func translateDevice(device *device) *openrtb2.Device {
+	translated := &openrtb2.Device{
+		Geo:        translateGeo(device.Geo),
+		DNT:        device.DNT,
+		Lmt:        device.Lmt,
+		UA:         device.UA,
+		IP:         device.IP,
+		IPv6:       device.IPv6,
+		DeviceType: device.Type,
+		Make:       device.Make,
+		Model:      device.Model,
+		OS:         osName(device.OS),
+		OSV:        device.OSV,
+		HWV:        device.HWV,
+		H:          device.H,
+		W:          device.W,
+		PPI:        device.PPI,
+		PxRatio:    device.PxRatio,
+		JS:         flag(device.JS),
+		GeoFetch:   flag(device.GeoFetch),
+		Language:   device.Lang,
+		LangB:      device.LangB,
+		Carrier:    device.Carrier,
+		MCCMNC:     device.MCCMNC,
+		IFA:        device.IFA,
+		Ext:        device.Ext,
+	}
+	if device.ConType != 0 {
+		translated.ConnectionType = ptrutil.ToPtr(device.ConType)
+	}
+	if sua := device.SUA; sua != nil {
+		translated.SUA = &openrtb2.UserAgent{
+			Platform:     translateBrandVersion(sua.Platform),
+			Mobile:       ptrutil.ToPtr(sua.Mobile),
+			Architecture: sua.Architecture,
+			Bitness:      sua.Bitness,
+			Model:        sua.Model,
+			Source:       sua.Source,
+			Ext:          sua.Ext,
+		}
+		for i := range sua.Browsers {
+			translated.SUA.Browsers = append(translated.SUA.Browsers, *translateBrandVersion(&sua.Browsers[i]))
+		}
+	}
+	return translated
+}
+
+func translateBrandVersion(bv *adcom1.BrandVersion) *openrtb2.BrandVersion {
+	if bv == nil {
+		return nil
+	}
+	return &openrtb2.BrandVersion{Brand: bv.Brand, Version: bv.Version, Ext: bv.Ext}
+}
+
+// translateGeo maps an AdCOM location, whose country has an alpha-2 code, to an OpenRTB 2.x one, whose country
+// has an alpha-3 code. Countries which don't have an alpha-2 code are passed on as they are.
+func translateGeo(geo *adcom1.Geo) *openrtb2.Geo {
+	if geo == nil {
+		return nil
+	}
+	translated := &openrtb2.Geo{
+		Type:      geo.Type,
+		Accuracy:  geo.Accur,
+		LastFix:   geo.LastFix,
+		IPService: geo.IPServ,
+		Country:   geo.Country,
+		Region:    geo.Region,
+		Metro:     geo.Metro,
+		City:      geo.City,
+		ZIP:       geo.ZIP,
+		UTCOffset: geo.UTCOffset,
+		Ext:       geo.Ext,
+	}
+	if geo.Lat != 0 || geo.Lon != 0 {
+		translated.Lat = ptrutil.ToPtr(geo.Lat)
+		translated.Lon = ptrutil.ToPtr(geo.Lon)
+	}
+	if alpha3, ok := countryutil.ToAlpha3(strings.ToUpper(geo.Country)); ok {
+		translated.Country = alpha3
+	}
+	return translated
+}
+
+// osNames are the names OpenRTB 2.x requests usually give the operating systems in the AdCOM list.
+var osNames = map[adcom1.OperatingSystem]string{
+	adcom1.OS3DS:         "3DS",
+	adcom1.OSAndroid:     "Android",
+	adcom1.OSAppleTV:     "tvOS",
+	adcom1.OSAsha:        "Asha",
+	adcom1.OSBada:        "Bada",
+	adcom1.OSBlackBerry:  "BlackBerry",
+	adcom1.OSBREW:        "BREW",
+	adcom1.OSChromeOS:    "ChromeOS",
+	adcom1.OSDarwin:      "Darwin",
+	adcom1.OSFireOS:      "FireOS",
+	adcom1.OSFirefoxOS:   "FirefoxOS",
+	adcom1.OSHelenOS:     "HelenOS",
+	adcom1.OSIOS:         "iOS",
+	adcom1.OSLinux:       "Linux",
+	adcom1.OSMacOS:       "macOS",
+	adcom1.OSMeeGo:       "MeeGo",
+	adcom1.OSMorphOS:     "MorphOS",
+	adcom1.OSNetBSD:      "NetBSD",
+	adcom1.OSNucleusPLUS: "NucleusPLUS",
+	adcom1.OSPSVita:      "PSVita",
+	adcom1.OSPS3:         "PS3",
+	adcom1.OSPS4:         "PS4",
+	adcom1.OSPSP:         "PSP",
+	adcom1.OSSymbian:     "Symbian",
+	adcom1.OSTizen:       "Tizen",
+	adcom1.OSWatchOS:     "watchOS",
+	adcom1.OSWebOS:       "webOS",
+	adcom1.OSWindows:     "Windows",
+}
+
+// osName returns the OpenRTB 2.x name of the operating system. Those which aren't listed, or have
+// vendor-specific codes, have no name.
+func osName(os adcom1.OperatingSystem) string {
+	return osNames[os]
+}
@@ -0,0 +1,200 @@
+package openrtb3
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/prebid/openrtb/v20/adcom1"
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v2/util/ptrutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRequest(t *testing.T) {
+	testCases := []struct {
+		description   string
+		body          string
+		expectedWSeat int8
+		expectedErr   string
+	}{
+		{
+			description:   "wseat defaults to 1",
+			body:          `{"openrtb":{"ver":"3.0","domainspec":"adcom","domainver":"1.0","request":{"id":"req","item":[{"id":"1"}]}}}`,
+			expectedWSeat: 1,
+		},
+		{
+			description: "wseat 0",
+			body:        `{"openrtb":{"ver":"3.0","request":{"id":"req","wseat":0,"item":[{"id":"1"}]}}}`,
+		},
+		{
+			description: "OpenRTB 2.x",
+			body:        `{"openrtb":{"ver":"2.6","request":{"id":"req","item":[{"id":"1"}]}}}`,
+			expectedErr: `openrtb.ver must be 3.x. Got "2.6"`,
+		},
+		{
+			description: "other domain spec",
+			body:        `{"openrtb":{"ver":"3.0","domainspec":"other","request":{"id":"req","item":[{"id":"1"}]}}}`,
+			expectedErr: `openrtb.domainspec must be adcom. Got "other"`,
+		},
+		{
+			description: "AdCOM 2.x",
+			body:        `{"openrtb":{"ver":"3.0","domainver":"2.0","request":{"id":"req","item":[{"id":"1"}]}}}`,
+			expectedErr: `openrtb.domainver must be 1.x. Got "2.0"`,
+		},
+		{
+			description: "no request",
+			body:        `{"openrtb":{"ver":"3.0"}}`,
+			expectedErr: "openrtb.request.id is required",
+		},
+		{
+			description: "no items",
+			body:        `{"openrtb":{"ver":"3.0","request":{"id":"req"}}}`,
+			expectedErr: "openrtb.request.item must have at least one item",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			request, err := parseRequest([]byte(test.body))
+			if test.expectedErr != "" {
+				assert.EqualError(t, err, test.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "req", request.ID)
+			assert.Equal(t, test.expectedWSeat, request.WSeat)
+		})
+	}
+}
+
+func TestTranslateRequest(t *testing.T) {
+	body := `{"openrtb":{"ver":"3.0","domainspec":"adcom","domainver":"1.0","request":{
+		"id":"req","tmax":500,"at":1,"cur":["USD"],"seat":["seat1"],"wseat":0,"cdata":"cookie",
+		"source":{"tid":"tid","pchain":"pchain","ds":"signature"},
+		"item":[
+			{"id":"display","flr":1.5,"flrcur":"USD","qty":2,"private":1,"deal":[{"id":"deal","flr":3,"at":3}],"ext":{"prebid":{"bidder":{"appnexus":{"placementId":1}}}},
+			 "spec":{"placement":{"tagid":"tag","secure":1,"sdk":"sdk","sdkver":"1.2","display":{"pos":1,"instl":1,"clktype":3,"mime":["image/png"],"displayfmt":[{"w":300,"h":250,"expdir":[1,2]},{"w":320,"h":50,"expdir":[2]}]}}}},
+			{"id":"video","spec":{"placement":{"reward":1,"video":{"ptype":1,"delay":-1,"skip":1,"playmethod":1,"mime":["video/mp4"],"ctype":[2,3],"w":640,"h":480,"mindur":5,"maxdur":30,"podid":7}}}},
+			{"id":"native","spec":{"placement":{"display":{"nativefmt":{}}}}}
+		],
+		"context":{
+			"site":{"id":"site","domain":"example.com","page":"https://example.com/page","mobile":1,"pub":{"id":"pub"}},
+			"user":{"id":"user","consent":"consent","eids":[{"source":"id.com","uids":[{"id":"uid","atype":1}]}]},
+			"device":{"type":4,"ua":"ua","os":13,"osv":"17.0","lmt":0,"w":390,"h":844,"ifa":"ifa","contype":2,"geo":{"lat":52.52,"lon":13.405,"country":"de"}},
+			"regs":{"gdpr":0,"coppa":1,"ext":{"us_privacy":"1YNN"}},
+			"restrictions":{"bcat":["IAB25"],"badv":["bad.com"],"battr":[1]}
+		}
+	}}}`
+
+	request, err := parseRequest([]byte(body))
+	require.NoError(t, err)
+	bidRequest, err := translateRequest(request)
+	require.NoError(t, err)
+
+	expected := &openrtb2.BidRequest{
+		ID:     "req",
+		TMax:   500,
+		AT:     1,
+		Cur:    []string{"USD"},
+		BSeat:  []string{"seat1"},
+		BCat:   []string{"IAB25"},
+		BAdv:   []string{"bad.com"},
+		Source: &openrtb2.Source{TID: "tid", PChain: "pchain"},
+		Imp: []openrtb2.Imp{
+			{
+				ID:                "display",
+				BidFloor:          1.5,
+				BidFloorCur:       "USD",
+				Qty:               &openrtb2.Qty{Multiplier: 2},
+				PMP:               &openrtb2.PMP{PrivateAuction: 1, Deals: []openrtb2.Deal{{ID: "deal", BidFloor: 3, AT: 3}}},
+				TagID:             "tag",
+				Secure:            ptrutil.ToPtr[int8](1),
+				DisplayManager:    "sdk",
+				DisplayManagerVer: "1.2",
+				Instl:             1,
+				ClickBrowser:      ptrutil.ToPtr[int8](1),
+				Banner: &openrtb2.Banner{
+					Format: []openrtb2.Format{{W: 300, H: 250}, {W: 320, H: 50}},
+					Pos:    ptrutil.ToPtr(adcom1.PositionAboveFold),
+					MIMEs:  []string{"image/png"},
+					ExpDir: []adcom1.ExpandableDirection{1, 2},
+					BAttr:  []adcom1.CreativeAttribute{1},
+				},
+				Ext: json.RawMessage(`{"prebid":{"bidder":{"appnexus":{"placementId":1}}}}`),
+			},
+			{
+				ID:     "video",
+				Secure: ptrutil.ToPtr[int8](0),
+				Rwdd:   1,
+				Video: &openrtb2.Video{
+					MIMEs:          []string{"video/mp4"},
+					MinDuration:    5,
+					MaxDuration:    30,
+					StartDelay:     ptrutil.ToPtr(adcom1.StartMidRoll),
+					Protocols:      []adcom1.MediaCreativeSubtype{2, 3},
+					W:              ptrutil.ToPtr[int64](640),
+					H:              ptrutil.ToPtr[int64](480),
+					PodID:          "7",
+					Placement:      adcom1.VideoPlacementInStream,
+					Skip:           ptrutil.ToPtr[int8](1),
+					PlaybackMethod: []adcom1.PlaybackMethod{1},
+					BAttr:          []adcom1.CreativeAttribute{1},
+				},
+			},
+			{
+				ID:     "native",
+				Secure: ptrutil.ToPtr[int8](0),
+			},
+		},
+		Site: &openrtb2.Site{
+			ID:        "site",
+			Domain:    "example.com",
+			Page:      "https://example.com/page",
+			Mobile:    ptrutil.ToPtr[int8](1),
+			Publisher: &openrtb2.Publisher{ID: "pub"},
+		},
+		User: &openrtb2.User{
+			ID:         "user",
+			Consent:    "consent",
+			CustomData: "cookie",
+			EIDs:       []openrtb2.EID{{Source: "id.com", UIDs: []openrtb2.UID{{ID: "uid", AType: 1}}}},
+		},
+		Device: &openrtb2.Device{
+			DeviceType:     4,
+			UA:             "ua",
+			OS:             "iOS",
+			OSV:            "17.0",
+			Lmt:            ptrutil.ToPtr[int8](0),
+			W:              390,
+			H:              844,
+			IFA:            "ifa",
+			ConnectionType: ptrutil.ToPtr(adcom1.ConnectionWIFI),
+			Geo:            &openrtb2.Geo{Lat: ptrutil.ToPtr(52.52), Lon: ptrutil.ToPtr(13.405), Country: "DEU"},
+		},
+		Regs: &openrtb2.Regs{COPPA: 1, GDPR: ptrutil.ToPtr[int8](0), Ext: json.RawMessage(`{"us_privacy":"1YNN"}`)},
+	}
+	assert.Equal(t, expected, bidRequest)
+}
+
+func TestTranslateRequestSeats(t *testing.T) {
+	request, err := parseRequest([]byte(`{"openrtb":{"ver":"3.0","request":{"id":"req","seat":["seat1"],"item":[{"id":"1"}]}}}`))
+	require.NoError(t, err)
+	bidRequest, err := translateRequest(request)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"seat1"}, bidRequest.WSeat)
+	assert.Empty(t, bidRequest.BSeat)
+}
+
+func TestTranslateRequestInvalidSpec(t *testing.T) {
+	request, err := parseRequest([]byte(`{"openrtb":{"ver":"3.0","request":{"id":"req","item":[{"id":"1","spec":{"placement":"banner"}}]}}}`))
+	require.NoError(t, err)
+	_, err = translateRequest(request)
+	assert.ErrorContains(t, err, "openrtb.request.item[0]: spec:")
+}
+
+func TestTranslateGeo(t *testing.T) {
+	assert.Nil(t, translateGeo(nil))
+	assert.Equal(t, &openrtb2.Geo{Country: "GBR"}, translateGeo(&adcom1.Geo{Country: "GB"}))
+	assert.Equal(t, &openrtb2.Geo{Country: "GBR"}, translateGeo(&adcom1.Geo{Country: "GBR"}))
+}
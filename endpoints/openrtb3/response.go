@@ -0,0 +1,110 @@
+package openrtb3
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/buger/jsonparser"
+	"github.com/prebid/openrtb/v20/adcom1"
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/openrtb/v20/openrtb3"
+	"github.com/prebid/prebid-server/v2/openrtb_ext"
+)
+
+// translateResponse maps the OpenRTB 2.x response of an auction to an OpenRTB 3.0 response, with the ads
+// of the bids as AdCOM objects.
+func translateResponse(bidResponse *openrtb2.BidResponse) (openrtb3.Body, error) {
+	response := &openrtb3.Response{
+		ID:    bidResponse.ID,
+		BidID: bidResponse.BidID,
+		Cur:   bidResponse.Cur,
+		CData: bidResponse.CustomData,
+		Ext:   bidResponse.Ext,
+	}
+	if bidResponse.NBR != nil {
+		response.NBR = *bidResponse.NBR
+	}
+	for _, seatBid := range bidResponse.SeatBid {
+		translated := openrtb3.SeatBid{Seat: seatBid.Seat, Package: seatBid.Group, Ext: seatBid.Ext}
+		for _, bid := range seatBid.Bid {
+			translatedBid, err := translateBid(bid)
+			if err != nil {
+				return openrtb3.Body{}, err
+			}
+			translated.Bid = append(translated.Bid, translatedBid)
+		}
+		response.SeatBid = append(response.SeatBid, translated)
+	}
+	return openrtb3.Body{OpenRTB: openrtb3.OpenRTB{Ver: "3.0", DomainSpec: "adcom", DomainVer: "1.0", Response: response}}, nil
+}
+
+func translateBid(bid openrtb2.Bid) (openrtb3.Bid, error) {
+	ad := &adcom1.Ad{
+		ID:      bid.CrID,
+		ADomain: bid.ADomain,
+		IURL:    bid.IURL,
+		Cat:     bid.Cat,
+		CatTax:  bid.CatTax,
+		Lang:    bid.Language,
+		Attr:    bid.Attr,
+		MRating: bid.QAGMediaRating,
+	}
+	if ad.ID == "" {
+		ad.ID = bid.AdID
+	}
+	if bid.Bundle != "" {
+		ad.Bundle = []string{bid.Bundle}
+	}
+	switch bidType(bid) {
+	case openrtb_ext.BidTypeVideo:
+		ad.Video = &adcom1.Video{AdM: bid.AdM, Dur: bid.Dur}
+	case openrtb_ext.BidTypeAudio:
+		ad.Audio = &adcom1.Audio{AdM: bid.AdM, Dur: bid.Dur}
+	default:
+		// banner and native ads are both display ads in AdCOM
+		ad.Display = &adcom1.Display{W: bid.W, H: bid.H, AdM: bid.AdM}
+	}
+	// the markup is left unescaped, as it is in the responses of /openrtb2/auction
+	var media bytes.Buffer
+	enc := json.NewEncoder(&media)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(adcom1.BidMedia{Ad: ad}); err != nil {
+		return openrtb3.Bid{}, fmt.Errorf("bid %s: %v", bid.ID, err)
+	}
+
+	return openrtb3.Bid{
+		ID:     bid.ID,
+		Item:   bid.ImpID,
+		Price:  bid.Price,
+		Deal:   bid.DealID,
+		CID:    bid.CID,
+		Tactic: bid.Tactic,
+		PURL:   bid.NURL,
+		BURL:   bid.BURL,
+		LURL:   bid.LURL,
+		Exp:    bid.Exp,
+		Media:  bytes.TrimSuffix(media.Bytes(), []byte("\n")),
+		Ext:    bid.Ext,
+	}, nil
+}
+
+// bidType returns the media type of a bid, from its mtype or the type the auction put in its ext.
+func bidType(bid openrtb2.Bid) openrtb_ext.BidType {
+	switch bid.MType {
+	case openrtb2.MarkupBanner:
+		return openrtb_ext.BidTypeBanner
+	case openrtb2.MarkupVideo:
+		return openrtb_ext.BidTypeVideo
+	case openrtb2.MarkupAudio:
+		return openrtb_ext.BidTypeAudio
+	case openrtb2.MarkupNative:
+		return openrtb_ext.BidTypeNative
+	}
+	if value, err := jsonparser.GetString(bid.Ext, "prebid", "type"); err == nil {
+		if bidType, err := openrtb_ext.ParseBidType(value); err == nil {
+			return bidType
+		}
+	}
+	return openrtb_ext.BidTypeBanner
+}
@@ -0,0 +1,81 @@
+package openrtb3
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/openrtb/v20/openrtb3"
+	"github.com/prebid/prebid-server/v2/openrtb_ext"
+	"github.com/prebid/prebid-server/v2/util/ptrutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTranslateResponse(t *testing.T) {
+	bidResponse := &openrtb2.BidResponse{
+		ID:         "req",
+		BidID:      "bidid",
+		Cur:        "USD",
+		CustomData: "cookie",
+		SeatBid: []openrtb2.SeatBid{{
+			Seat:  "appnexus",
+			Group: 1,
+			Bid: []openrtb2.Bid{
+				{ID: "banner", ImpID: "1", Price: 1.5, NURL: "https://win", BURL: "https://bill", DealID: "deal", CrID: "creative", ADomain: []string{"ad.com"}, Bundle: "com.ad", W: 300, H: 250, AdM: "<div/>", MType: openrtb2.MarkupBanner},
+				{ID: "video", ImpID: "2", Price: 2, AdID: "ad", AdM: "<VAST/>", Dur: 30, Ext: json.RawMessage(`{"prebid":{"type":"video"}}`)},
+			},
+		}},
+		Ext: json.RawMessage(`{"responsetimemillis":{"appnexus":5}}`),
+	}
+
+	body, err := translateResponse(bidResponse)
+	require.NoError(t, err)
+
+	expected := openrtb3.Body{OpenRTB: openrtb3.OpenRTB{
+		Ver:        "3.0",
+		DomainSpec: "adcom",
+		DomainVer:  "1.0",
+		Response: &openrtb3.Response{
+			ID:    "req",
+			BidID: "bidid",
+			Cur:   "USD",
+			CData: "cookie",
+			SeatBid: []openrtb3.SeatBid{{
+				Seat:    "appnexus",
+				Package: 1,
+				Bid: []openrtb3.Bid{
+					{ID: "banner", Item: "1", Price: 1.5, PURL: "https://win", BURL: "https://bill", Deal: "deal", Media: json.RawMessage(`{"ad":{"id":"creative","adomain":["ad.com"],"bundle":["com.ad"],"display":{"w":300,"h":250,"adm":"<div/>"}}}`)},
+					{ID: "video", Item: "2", Price: 2, Media: json.RawMessage(`{"ad":{"id":"ad","video":{"dur":30,"adm":"<VAST/>"}}}`), Ext: json.RawMessage(`{"prebid":{"type":"video"}}`)},
+				},
+			}},
+			Ext: json.RawMessage(`{"responsetimemillis":{"appnexus":5}}`),
+		},
+	}}
+	assert.Equal(t, expected, body)
+}
+
+func TestTranslateResponseNoBid(t *testing.T) {
+	body, err := translateResponse(&openrtb2.BidResponse{ID: "req", NBR: ptrutil.ToPtr(openrtb3.NoBidInvalidRequest)})
+	require.NoError(t, err)
+	assert.Equal(t, &openrtb3.Response{ID: "req", NBR: openrtb3.NoBidInvalidRequest}, body.OpenRTB.Response)
+}
+
+func TestBidType(t *testing.T) {
+	testCases := []struct {
+		description string
+		bid         openrtb2.Bid
+		expected    openrtb_ext.BidType
+	}{
+		{description: "mtype", bid: openrtb2.Bid{MType: openrtb2.MarkupAudio}, expected: openrtb_ext.BidTypeAudio},
+		{description: "ext", bid: openrtb2.Bid{Ext: json.RawMessage(`{"prebid":{"type":"native"}}`)}, expected: openrtb_ext.BidTypeNative},
+		{description: "mtype over ext", bid: openrtb2.Bid{MType: openrtb2.MarkupVideo, Ext: json.RawMessage(`{"prebid":{"type":"banner"}}`)}, expected: openrtb_ext.BidTypeVideo},
+		{description: "unknown", bid: openrtb2.Bid{Ext: json.RawMessage(`{"prebid":{"type":"other"}}`)}, expected: openrtb_ext.BidTypeBanner},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			assert.Equal(t, test.expected, bidType(test.bid))
+		})
+	}
+}
@@ -0,0 +1,17 @@
+package endpoints
+
+import (
+	"net/http"
+
+	"github.com/prebid/prebid-server/v2/responsesigning"
+)
+
+// NewResponseSigningKeysEndpoint returns the public keys auction responses are signed with, as a JSON Web Key Set,
+// so that wrappers and SDKs can fetch them to verify signatures by key ID.
+func NewResponseSigningKeysEndpoint(signer *responsesigning.Signer) http.HandlerFunc {
+	jwks := signer.JWKS()
+	return func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/jwk-set+json")
+		w.Write(jwks)
+	}
+}
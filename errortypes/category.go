@@ -0,0 +1,93 @@
+package errortypes
+
+// Category groups error and warning codes by what they're about, so clients can react to a kind of problem
+// without knowing every code. Categories, like codes, are part of the response contract and must not change.
+type Category string
+
+const (
+	// CategoryValidation is for requests which are invalid or were changed to be valid.
+	CategoryValidation Category = "validation"
+	// CategoryPrivacy is for consent strings and privacy signals, and data left out to respect them.
+	CategoryPrivacy Category = "privacy"
+	// CategoryFloors is for price floors and the bids rejected by them.
+	CategoryFloors Category = "floors"
+	// CategoryAdapter is for bidders, their requests and the bids they responded with.
+	CategoryAdapter Category = "adapter"
+	// CategoryCurrency is for currencies and their conversion.
+	CategoryCurrency Category = "currency"
+	// CategoryAccount is for the account, and whether it may use Prebid Server.
+	CategoryAccount Category = "account"
+	// CategoryTargeting is for the targeting keys sent to ad servers.
+	CategoryTargeting Category = "targeting"
+	// CategoryDebug is for debug output.
+	CategoryDebug Category = "debug"
+	// CategoryTraffic is for requests which were limited, shed or screened.
+	CategoryTraffic Category = "traffic"
+	// CategoryModule is for modules.
+	CategoryModule Category = "module"
+	// CategoryUnknown is for codes without a category, such as UnknownErrorCode and UnknownWarningCode.
+	CategoryUnknown Category = "unknown"
+)
+
+var codeCategories = map[int]Category{
+	TimeoutErrorCode:                   CategoryAdapter,
+	BadInputErrorCode:                  CategoryValidation,
+	BlacklistedAppErrorCode:            CategoryAccount,
+	BadServerResponseErrorCode:         CategoryAdapter,
+	FailedToRequestBidsErrorCode:       CategoryAdapter,
+	BidderTemporarilyDisabledErrorCode: CategoryAdapter,
+	AccountDisabledErrorCode:           CategoryAccount,
+	AcctRequiredErrorCode:              CategoryAccount,
+	NoConversionRateErrorCode:          CategoryCurrency,
+	MalformedAcctErrorCode:             CategoryAccount,
+	ModuleRejectionErrorCode:           CategoryModule,
+	TmaxTimeoutErrorCode:               CategoryAdapter,
+	FailedToMarshalErrorCode:           CategoryAdapter,
+	FailedToUnmarshalErrorCode:         CategoryValidation,
+	LoadShedErrorCode:                  CategoryTraffic,
+	InvalidTrafficErrorCode:            CategoryTraffic,
+	UnauthorizedErrorCode:              CategoryAccount,
+	RateLimitedErrorCode:               CategoryTraffic,
+	RequestTooLargeErrorCode:           CategoryValidation,
+
+	InvalidPrivacyConsentWarningCode:      CategoryPrivacy,
+	AccountLevelDebugDisabledWarningCode:  CategoryDebug,
+	BidderLevelDebugDisabledWarningCode:   CategoryDebug,
+	DisabledCurrencyConversionWarningCode: CategoryCurrency,
+	AlternateBidderCodeWarningCode:        CategoryAdapter,
+	MultiBidWarningCode:                   CategoryValidation,
+	AdServerTargetingWarningCode:          CategoryTargeting,
+	BidAdjustmentWarningCode:              CategoryValidation,
+	FloorBidRejectionWarningCode:          CategoryFloors,
+	InvalidBidResponseDSAWarningCode:      CategoryAdapter,
+	SecCookieDeprecationLenWarningCode:    CategoryPrivacy,
+	InvalidDebugTokenWarningCode:          CategoryDebug,
+	SChainLoopWarningCode:                 CategoryValidation,
+	PIIViolationWarningCode:               CategoryPrivacy,
+	BidderQPSCapWarningCode:               CategoryTraffic,
+	DealPacingWarningCode:                 CategoryTraffic,
+	FirstPartyDataCapWarningCode:          CategoryValidation,
+	ResponseOverrideWarningCode:           CategoryAdapter,
+	MultipleCurrenciesWarningCode:         CategoryCurrency,
+	AdsCertSigningWarningCode:             CategoryAdapter,
+	DebugResolutionWarningCode:            CategoryDebug,
+	FloorCurrencyConversionWarningCode:    CategoryFloors,
+	TargetingEncryptionWarningCode:        CategoryTargeting,
+	RequestNormalizedWarningCode:          CategoryValidation,
+	BidderMaintenanceWarningCode:          CategoryAdapter,
+	TestTrafficWarningCode:                CategoryTraffic,
+	AccountStoredBidResponseWarningCode:   CategoryAdapter,
+}
+
+// CategoryOf returns the category of an error or warning code, or CategoryUnknown if it doesn't have one.
+func CategoryOf(code int) Category {
+	if category, ok := codeCategories[code]; ok {
+		return category
+	}
+	return CategoryUnknown
+}
+
+// ReadCategory returns the category of the error or warning, or CategoryUnknown if it doesn't have one.
+func ReadCategory(err error) Category {
+	return CategoryOf(ReadCode(err))
+}
@@ -0,0 +1,80 @@
+package errortypes
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestCodes makes sure codes and their categories aren't changed, since clients depend on them. New codes
+// must be added at the end of their list, and here.
+func TestCodes(t *testing.T) {
+	testCases := []struct {
+		code             int
+		expectedCode     int
+		expectedCategory Category
+	}{
+		{code: UnknownErrorCode, expectedCode: 999, expectedCategory: CategoryUnknown},
+		{code: TimeoutErrorCode, expectedCode: 1, expectedCategory: CategoryAdapter},
+		{code: BadInputErrorCode, expectedCode: 2, expectedCategory: CategoryValidation},
+		{code: BlacklistedAppErrorCode, expectedCode: 3, expectedCategory: CategoryAccount},
+		{code: BadServerResponseErrorCode, expectedCode: 4, expectedCategory: CategoryAdapter},
+		{code: FailedToRequestBidsErrorCode, expectedCode: 5, expectedCategory: CategoryAdapter},
+		{code: BidderTemporarilyDisabledErrorCode, expectedCode: 6, expectedCategory: CategoryAdapter},
+		{code: AccountDisabledErrorCode, expectedCode: 7, expectedCategory: CategoryAccount},
+		{code: AcctRequiredErrorCode, expectedCode: 8, expectedCategory: CategoryAccount},
+		{code: NoConversionRateErrorCode, expectedCode: 9, expectedCategory: CategoryCurrency},
+		{code: MalformedAcctErrorCode, expectedCode: 10, expectedCategory: CategoryAccount},
+		{code: ModuleRejectionErrorCode, expectedCode: 11, expectedCategory: CategoryModule},
+		{code: TmaxTimeoutErrorCode, expectedCode: 12, expectedCategory: CategoryAdapter},
+		{code: FailedToMarshalErrorCode, expectedCode: 13, expectedCategory: CategoryAdapter},
+		{code: FailedToUnmarshalErrorCode, expectedCode: 14, expectedCategory: CategoryValidation},
+		{code: LoadShedErrorCode, expectedCode: 15, expectedCategory: CategoryTraffic},
+		{code: InvalidTrafficErrorCode, expectedCode: 16, expectedCategory: CategoryTraffic},
+		{code: UnauthorizedErrorCode, expectedCode: 17, expectedCategory: CategoryAccount},
+		{code: RateLimitedErrorCode, expectedCode: 18, expectedCategory: CategoryTraffic},
+		{code: RequestTooLargeErrorCode, expectedCode: 19, expectedCategory: CategoryValidation},
+		{code: UnknownWarningCode, expectedCode: 10999, expectedCategory: CategoryUnknown},
+		{code: InvalidPrivacyConsentWarningCode, expectedCode: 10001, expectedCategory: CategoryPrivacy},
+		{code: AccountLevelDebugDisabledWarningCode, expectedCode: 10002, expectedCategory: CategoryDebug},
+		{code: BidderLevelDebugDisabledWarningCode, expectedCode: 10003, expectedCategory: CategoryDebug},
+		{code: DisabledCurrencyConversionWarningCode, expectedCode: 10004, expectedCategory: CategoryCurrency},
+		{code: AlternateBidderCodeWarningCode, expectedCode: 10005, expectedCategory: CategoryAdapter},
+		{code: MultiBidWarningCode, expectedCode: 10006, expectedCategory: CategoryValidation},
+		{code: AdServerTargetingWarningCode, expectedCode: 10007, expectedCategory: CategoryTargeting},
+		{code: BidAdjustmentWarningCode, expectedCode: 10008, expectedCategory: CategoryValidation},
+		{code: FloorBidRejectionWarningCode, expectedCode: 10009, expectedCategory: CategoryFloors},
+		{code: InvalidBidResponseDSAWarningCode, expectedCode: 10010, expectedCategory: CategoryAdapter},
+		{code: SecCookieDeprecationLenWarningCode, expectedCode: 10011, expectedCategory: CategoryPrivacy},
+		{code: InvalidDebugTokenWarningCode, expectedCode: 10012, expectedCategory: CategoryDebug},
+		{code: SChainLoopWarningCode, expectedCode: 10013, expectedCategory: CategoryValidation},
+		{code: PIIViolationWarningCode, expectedCode: 10014, expectedCategory: CategoryPrivacy},
+		{code: BidderQPSCapWarningCode, expectedCode: 10015, expectedCategory: CategoryTraffic},
+		{code: DealPacingWarningCode, expectedCode: 10016, expectedCategory: CategoryTraffic},
+		{code: FirstPartyDataCapWarningCode, expectedCode: 10017, expectedCategory: CategoryValidation},
+		{code: ResponseOverrideWarningCode, expectedCode: 10018, expectedCategory: CategoryAdapter},
+		{code: MultipleCurrenciesWarningCode, expectedCode: 10019, expectedCategory: CategoryCurrency},
+		{code: AdsCertSigningWarningCode, expectedCode: 10020, expectedCategory: CategoryAdapter},
+		{code: DebugResolutionWarningCode, expectedCode: 10021, expectedCategory: CategoryDebug},
+		{code: FloorCurrencyConversionWarningCode, expectedCode: 10022, expectedCategory: CategoryFloors},
+		{code: TargetingEncryptionWarningCode, expectedCode: 10023, expectedCategory: CategoryTargeting},
+		{code: RequestNormalizedWarningCode, expectedCode: 10024, expectedCategory: CategoryValidation},
+		{code: BidderMaintenanceWarningCode, expectedCode: 10025, expectedCategory: CategoryAdapter},
+		{code: TestTrafficWarningCode, expectedCode: 10026, expectedCategory: CategoryTraffic},
+		{code: AccountStoredBidResponseWarningCode, expectedCode: 10027, expectedCategory: CategoryAdapter},
+	}
+
+	for _, test := range testCases {
+		assert.Equal(t, test.expectedCode, test.code)
+		assert.Equal(t, test.expectedCategory, CategoryOf(test.code), "category of %d", test.code)
+	}
+	assert.Len(t, codeCategories, len(testCases)-2, "every code but the unknown ones must have a category")
+}
+
+func TestReadCategory(t *testing.T) {
+	assert.Equal(t, CategoryPrivacy, ReadCategory(&Warning{Message: "warning", WarningCode: InvalidPrivacyConsentWarningCode}))
+	assert.Equal(t, CategoryValidation, ReadCategory(&BadInput{Message: "error"}))
+	assert.Equal(t, CategoryUnknown, ReadCategory(&Warning{Message: "warning"}))
+	assert.Equal(t, CategoryUnknown, ReadCategory(errors.New("error")))
+}
@@ -17,6 +17,11 @@ const (
 	TmaxTimeoutErrorCode
 	FailedToMarshalErrorCode
 	FailedToUnmarshalErrorCode
+	LoadShedErrorCode
+	InvalidTrafficErrorCode
+	UnauthorizedErrorCode
+	RateLimitedErrorCode
+	RequestTooLargeErrorCode
 )
 
 // Defines numeric codes for well-known warnings.
@@ -33,6 +38,22 @@ const (
 	FloorBidRejectionWarningCode
 	InvalidBidResponseDSAWarningCode
 	SecCookieDeprecationLenWarningCode
+	InvalidDebugTokenWarningCode
+	SChainLoopWarningCode
+	PIIViolationWarningCode
+	BidderQPSCapWarningCode
+	DealPacingWarningCode
+	FirstPartyDataCapWarningCode
+	ResponseOverrideWarningCode
+	MultipleCurrenciesWarningCode
+	AdsCertSigningWarningCode
+	DebugResolutionWarningCode
+	FloorCurrencyConversionWarningCode
+	TargetingEncryptionWarningCode
+	RequestNormalizedWarningCode
+	BidderMaintenanceWarningCode
+	TestTrafficWarningCode
+	AccountStoredBidResponseWarningCode
 )
 
 // Coder provides an error or warning code with severity.
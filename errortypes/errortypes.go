@@ -39,6 +39,25 @@ func (err *TmaxTimeout) Severity() Severity {
 	return SeverityFatal
 }
 
+// LoadShed should be used to flag a bidder request which was dropped because Prebid Server is overloaded
+//
+// LoadShed will not be written to the app log, since it's tracked by the bidder request pool metrics.
+type LoadShed struct {
+	Message string
+}
+
+func (err *LoadShed) Error() string {
+	return err.Message
+}
+
+func (err *LoadShed) Code() int {
+	return LoadShedErrorCode
+}
+
+func (err *LoadShed) Severity() Severity {
+	return SeverityFatal
+}
+
 // BadInput should be used when returning errors which are caused by bad input.
 // It should _not_ be used if the error is a server-side issue (e.g. failed to send the external request).
 //
@@ -79,6 +98,85 @@ func (err *BlacklistedApp) Severity() Severity {
 	return SeverityFatal
 }
 
+// InvalidTraffic should be used when a request is blocked because its device is a known bot, or comes from a
+// blocked or datacenter IP address, or has a blocked advertising ID.
+//
+// These errors will be written to  http.ResponseWriter before canceling execution
+type InvalidTraffic struct {
+	Message string
+}
+
+func (err *InvalidTraffic) Error() string {
+	return err.Message
+}
+
+func (err *InvalidTraffic) Code() int {
+	return InvalidTrafficErrorCode
+}
+
+func (err *InvalidTraffic) Severity() Severity {
+	return SeverityFatal
+}
+
+// Unauthorized should be used when a request doesn't have a valid API key for an account which requires one,
+// or has one which is unknown or revoked.
+//
+// These errors will be written to  http.ResponseWriter before canceling execution
+type Unauthorized struct {
+	Message string
+}
+
+func (err *Unauthorized) Error() string {
+	return err.Message
+}
+
+func (err *Unauthorized) Code() int {
+	return UnauthorizedErrorCode
+}
+
+func (err *Unauthorized) Severity() Severity {
+	return SeverityFatal
+}
+
+// RateLimited should be used when a request is rejected because its API key has gone over its rate limit, or
+// its account has used up its usage quota.
+//
+// These errors will be written to  http.ResponseWriter before canceling execution
+type RateLimited struct {
+	Message string
+}
+
+func (err *RateLimited) Error() string {
+	return err.Message
+}
+
+func (err *RateLimited) Code() int {
+	return RateLimitedErrorCode
+}
+
+func (err *RateLimited) Severity() Severity {
+	return SeverityFatal
+}
+
+// RequestTooLarge should be used when a request is rejected because its body is larger than its account allows.
+//
+// These errors will be written to  http.ResponseWriter before canceling execution
+type RequestTooLarge struct {
+	Message string
+}
+
+func (err *RequestTooLarge) Error() string {
+	return err.Message
+}
+
+func (err *RequestTooLarge) Code() int {
+	return RequestTooLargeErrorCode
+}
+
+func (err *RequestTooLarge) Severity() Severity {
+	return SeverityFatal
+}
+
 // AccountDisabled should be used when a request an account is specifically disabled in account config.
 type AccountDisabled struct {
 	Message string
@@ -221,6 +319,18 @@ func (err *Warning) Severity() Severity {
 // FailedToUnmarshal should be used to represent errors that occur when unmarshaling raw json.
 type FailedToUnmarshal struct {
 	Message string
+	// Position is where in the json the error was found, if it's known.
+	Position *JSONPosition
+}
+
+// JSONPosition is where in a json document an error was found.
+type JSONPosition struct {
+	// Path is the path of the value from the root of the document, such as imp[0].banner.format[1].w.
+	Path string
+	// Offset is the number of bytes of the document read before the error was found.
+	Offset int64
+	// Expected is the json type the value should have had, such as number or object, if it had another type.
+	Expected string
 }
 
 func (err *FailedToUnmarshal) Error() string {
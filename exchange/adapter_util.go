@@ -12,22 +12,61 @@ import (
 
 func BuildAdapters(client *http.Client, cfg *config.Configuration, infos config.BidderInfos, me metrics.MetricsEngine) (map[openrtb_ext.BidderName]AdaptedBidder, []error) {
 	server := config.Server{ExternalUrl: cfg.ExternalURL, GvlID: cfg.GDPR.HostVendorID, DataCenter: cfg.DataCenter}
-	bidders, errs := buildBidders(infos, newAdapterBuilders(), server)
+	builders := newAdapterBuilders()
+	bidders, errs := buildBidders(infos, builders, server)
+	canaries, canaryErrs := buildCanaryBidders(infos, bidders, builders, server)
+	errs = append(errs, canaryErrs...)
 
 	if len(errs) > 0 {
 		return nil, errs
 	}
 
+	requestPool := newBidderRequestPool(cfg.BidderRequestPool, me)
+	responseCache := newBidderResponseCache(cfg.BidderResponseCache, me)
+	timeoutNotifier, errs := newTimeoutNotifier(cfg.BidderTimeoutNotifications, infos, me)
+	if len(errs) > 0 {
+		return nil, errs
+	}
 	exchangeBidders := make(map[openrtb_ext.BidderName]AdaptedBidder, len(bidders))
 	for bidderName, bidder := range bidders {
 		info := infos[string(bidderName)]
-		exchangeBidder := AdaptBidder(bidder, client, cfg, me, bidderName, info.Debug, info.EndpointCompression)
+		exchangeBidder := adaptBidder(bidder, client, cfg, me, bidderName, info.Debug, info.EndpointCompression, info.MaxResponseSize, requestPool, responseCache, timeoutNotifier, newRequestHedger(info.Hedging))
 		exchangeBidder = addValidatedBidderMiddleware(exchangeBidder)
+		if canary, ok := canaries[bidderName]; ok {
+			adaptedCanary := adaptBidder(canary, client, cfg, me, bidderName, info.Debug, info.EndpointCompression, info.MaxResponseSize, requestPool, responseCache, timeoutNotifier, newRequestHedger(info.Hedging))
+			exchangeBidder = &canaryBidder{
+				name:    bidderName,
+				control: exchangeBidder,
+				canary:  addValidatedBidderMiddleware(adaptedCanary),
+				me:      me,
+			}
+		}
 		exchangeBidders[bidderName] = exchangeBidder
 	}
 	return exchangeBidders, nil
 }
 
+// buildCanaryBidders builds the bidders with a canary config a second time, with that config.
+func buildCanaryBidders(infos config.BidderInfos, bidders map[openrtb_ext.BidderName]adapters.Bidder, builders map[openrtb_ext.BidderName]adapters.Builder, server config.Server) (map[openrtb_ext.BidderName]adapters.Bidder, []error) {
+	canaries := make(map[openrtb_ext.BidderName]adapters.Bidder)
+	var errs []error
+
+	for bidderName := range bidders {
+		info := infos[string(bidderName)]
+		if info.Canary == nil {
+			continue
+		}
+
+		canaryInstance, builderErr := builders[bidderName](bidderName, canaryAdapterInfo(info), server)
+		if builderErr != nil {
+			errs = append(errs, fmt.Errorf("%v: canary: %v", bidderName, builderErr))
+			continue
+		}
+		canaries[bidderName] = adapters.BuildInfoAwareBidder(canaryInstance, info)
+	}
+	return canaries, errs
+}
+
 func buildBidders(infos config.BidderInfos, builders map[openrtb_ext.BidderName]adapters.Builder, server config.Server) (map[openrtb_ext.BidderName]adapters.Bidder, []error) {
 	bidders := make(map[openrtb_ext.BidderName]adapters.Bidder)
 	var errs []error
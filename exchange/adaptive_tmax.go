@@ -0,0 +1,101 @@
+package exchange
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/openrtb_ext"
+)
+
+// adaptiveTmax tracks each bidder's recent response times, and cuts the time a bidder is given to a percentile
+// of them, plus headroom, in the auctions of accounts which opt in. A bidder which is consistently slow then
+// can't hold up the auction for the whole of the time the auction leaves it.
+type adaptiveTmax struct {
+	percentile float64
+	samples    int
+	minSamples int
+	headroom   int64
+	minTmax    time.Duration
+	measure    float64
+	random     func() float64
+
+	mutex         sync.Mutex
+	responseTimes map[openrtb_ext.BidderName]*latencyWindow
+}
+
+// newAdaptiveTmax returns the tracker of the bidders' response times, or nil if they aren't tracked.
+func newAdaptiveTmax(cfg config.AdaptiveBidderTmax) *adaptiveTmax {
+	if !cfg.Enabled {
+		return nil
+	}
+	return &adaptiveTmax{
+		percentile:    cfg.Percentile,
+		samples:       cfg.Samples,
+		minSamples:    cfg.MinSamples,
+		headroom:      int64(cfg.HeadroomPercent),
+		minTmax:       time.Duration(cfg.MinTmaxMS) * time.Millisecond,
+		measure:       float64(cfg.MeasurePercent) / 100,
+		random:        rand.Float64,
+		responseTimes: make(map[openrtb_ext.BidderName]*latencyWindow),
+	}
+}
+
+// adapt returns the context the bidder is called with, which has an earlier deadline if the bidder's tmax
+// is cut, and whether it was. The bidder request is given a copy of the bid request with the shorter tmax,
+// so that the requests to the other bidders are left as they are. The cancel func must always be called.
+//
+// A bidder's tmax isn't cut if the account hasn't opted in, if the bidder's response times aren't known yet,
+// if the request is one of the share kept for measuring, or if the auction leaves the bidder less time anyway.
+func (a *adaptiveTmax) adapt(ctx context.Context, bidderRequest *BidderRequest, optedIn bool) (context.Context, context.CancelFunc, bool) {
+	if a == nil || !optedIn || !tracksResponseTime(bidderRequest) || a.random() < a.measure {
+		return ctx, func() {}, false
+	}
+	percentile, ok := a.window(bidderRequest.BidderCoreName).get()
+	if !ok {
+		return ctx, func() {}, false
+	}
+
+	tmax := percentile * time.Duration(100+a.headroom) / 100
+	if tmax < a.minTmax {
+		tmax = a.minTmax
+	}
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) <= tmax {
+		return ctx, func() {}, false
+	}
+
+	adaptedCtx, cancel := context.WithTimeout(ctx, tmax)
+	bidRequest := *bidderRequest.BidRequest
+	bidRequest.TMax = tmax.Milliseconds()
+	bidderRequest.BidRequest = &bidRequest
+	return adaptedCtx, cancel, true
+}
+
+// observe adds the time the bidder took to its recent response times. The times of bidders whose tmax was
+// cut aren't added, since they'd only ever fall within it, and nor are the times of requests which weren't
+// sent to the bidder's endpoint.
+func (a *adaptiveTmax) observe(bidderRequest BidderRequest, adapted bool, responseTime time.Duration) {
+	if a == nil || adapted || !tracksResponseTime(&bidderRequest) {
+		return
+	}
+	a.window(bidderRequest.BidderCoreName).observe(responseTime)
+}
+
+func (a *adaptiveTmax) window(bidder openrtb_ext.BidderName) *latencyWindow {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	window, ok := a.responseTimes[bidder]
+	if !ok {
+		window = newLatencyWindow(a.percentile, a.samples, a.minSamples)
+		a.responseTimes[bidder] = window
+	}
+	return window
+}
+
+// tracksResponseTime reports whether the request goes to the bidder's own endpoint. Simulated and stored
+// responses aren't, and nor are requests to a canary, which has endpoints of its own.
+func tracksResponseTime(bidderRequest *BidderRequest) bool {
+	return bidderRequest.SimulatedResponse == nil && len(bidderRequest.BidderStoredResponses) == 0 && !bidderRequest.Canary
+}
@@ -0,0 +1,187 @@
+package exchange
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/openrtb_ext"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestAdaptiveTmax(measure float64) *adaptiveTmax {
+	a := newAdaptiveTmax(config.AdaptiveBidderTmax{
+		Enabled:         true,
+		Percentile:      95,
+		Samples:         100,
+		MinSamples:      20,
+		HeadroomPercent: 20,
+		MinTmaxMS:       100,
+		MeasurePercent:  5,
+	})
+	a.random = func() float64 { return measure }
+	return a
+}
+
+func TestNewAdaptiveTmaxDisabled(t *testing.T) {
+	assert.Nil(t, newAdaptiveTmax(config.AdaptiveBidderTmax{Enabled: false}))
+}
+
+func TestAdaptiveTmaxAdapt(t *testing.T) {
+	testCases := []struct {
+		name          string
+		bidderRequest BidderRequest
+		optedIn       bool
+		measure       float64
+		responseTimes []time.Duration
+		timeout       time.Duration
+		expectedTmax  int64
+		expectedOK    bool
+	}{
+		{
+			name:          "adapted",
+			bidderRequest: BidderRequest{BidderCoreName: openrtb_ext.BidderAppnexus},
+			optedIn:       true,
+			measure:       0.5,
+			responseTimes: responseTimes(20, 10*time.Millisecond),
+			timeout:       time.Second,
+			expectedTmax:  216,
+			expectedOK:    true,
+		},
+		{
+			name:          "min-tmax",
+			bidderRequest: BidderRequest{BidderCoreName: openrtb_ext.BidderAppnexus},
+			optedIn:       true,
+			measure:       0.5,
+			responseTimes: responseTimes(20, time.Millisecond),
+			timeout:       time.Second,
+			expectedTmax:  100,
+			expectedOK:    true,
+		},
+		{
+			name:          "not-opted-in",
+			bidderRequest: BidderRequest{BidderCoreName: openrtb_ext.BidderAppnexus},
+			optedIn:       false,
+			measure:       0.5,
+			responseTimes: responseTimes(20, 10*time.Millisecond),
+			timeout:       time.Second,
+			expectedTmax:  1000,
+		},
+		{
+			name:          "measured",
+			bidderRequest: BidderRequest{BidderCoreName: openrtb_ext.BidderAppnexus},
+			optedIn:       true,
+			measure:       0.01,
+			responseTimes: responseTimes(20, 10*time.Millisecond),
+			timeout:       time.Second,
+			expectedTmax:  1000,
+		},
+		{
+			name:          "too-few-response-times",
+			bidderRequest: BidderRequest{BidderCoreName: openrtb_ext.BidderAppnexus},
+			optedIn:       true,
+			measure:       0.5,
+			responseTimes: responseTimes(19, 10*time.Millisecond),
+			timeout:       time.Second,
+			expectedTmax:  1000,
+		},
+		{
+			name:          "auction-leaves-less-time",
+			bidderRequest: BidderRequest{BidderCoreName: openrtb_ext.BidderAppnexus},
+			optedIn:       true,
+			measure:       0.5,
+			responseTimes: responseTimes(20, 10*time.Millisecond),
+			timeout:       200 * time.Millisecond,
+			expectedTmax:  1000,
+		},
+		{
+			name:          "simulated",
+			bidderRequest: BidderRequest{BidderCoreName: openrtb_ext.BidderAppnexus, SimulatedResponse: json.RawMessage(`{}`)},
+			optedIn:       true,
+			measure:       0.5,
+			responseTimes: responseTimes(20, 10*time.Millisecond),
+			timeout:       time.Second,
+			expectedTmax:  1000,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			a := newTestAdaptiveTmax(test.measure)
+			window := a.window(openrtb_ext.BidderAppnexus)
+			for _, responseTime := range test.responseTimes {
+				window.observe(responseTime)
+			}
+			bidRequest := &openrtb2.BidRequest{ID: "request", TMax: 1000}
+			test.bidderRequest.BidRequest = bidRequest
+			ctx, cancelAuction := context.WithTimeout(context.Background(), test.timeout)
+			defer cancelAuction()
+
+			adaptedCtx, cancel, ok := a.adapt(ctx, &test.bidderRequest, test.optedIn)
+			defer cancel()
+
+			assert.Equal(t, test.expectedOK, ok)
+			assert.Equal(t, test.expectedTmax, test.bidderRequest.BidRequest.TMax)
+			assert.Equal(t, int64(1000), bidRequest.TMax, "the auction's bid request mustn't be changed")
+			if test.expectedOK {
+				deadline, _ := adaptedCtx.Deadline()
+				assert.WithinDuration(t, time.Now().Add(time.Duration(test.expectedTmax)*time.Millisecond), deadline, 50*time.Millisecond)
+			} else {
+				assert.Equal(t, ctx, adaptedCtx)
+			}
+		})
+	}
+}
+
+func TestAdaptiveTmaxObserve(t *testing.T) {
+	testCases := []struct {
+		name             string
+		bidderRequest    BidderRequest
+		adapted          bool
+		expectedObserved int
+	}{
+		{
+			name:             "observed",
+			bidderRequest:    BidderRequest{BidderName: "alias", BidderCoreName: openrtb_ext.BidderAppnexus},
+			expectedObserved: 1,
+		},
+		{
+			name:          "adapted",
+			bidderRequest: BidderRequest{BidderCoreName: openrtb_ext.BidderAppnexus},
+			adapted:       true,
+		},
+		{
+			name:          "stored-responses",
+			bidderRequest: BidderRequest{BidderCoreName: openrtb_ext.BidderAppnexus, BidderStoredResponses: map[string]json.RawMessage{"imp": json.RawMessage(`{}`)}},
+		},
+		{
+			name:          "canary",
+			bidderRequest: BidderRequest{BidderCoreName: openrtb_ext.BidderAppnexus, Canary: true},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			a := newTestAdaptiveTmax(0.5)
+			a.observe(test.bidderRequest, test.adapted, 10*time.Millisecond)
+			assert.Equal(t, test.expectedObserved, a.window(openrtb_ext.BidderAppnexus).observed)
+		})
+	}
+}
+
+func TestAdaptiveTmaxNil(t *testing.T) {
+	var a *adaptiveTmax
+	bidderRequest := BidderRequest{BidRequest: &openrtb2.BidRequest{TMax: 1000}}
+	ctx := context.Background()
+
+	adaptedCtx, cancel, ok := a.adapt(ctx, &bidderRequest, true)
+	cancel()
+	a.observe(bidderRequest, false, time.Second)
+
+	assert.False(t, ok)
+	assert.Equal(t, ctx, adaptedCtx)
+	assert.Equal(t, int64(1000), bidderRequest.BidRequest.TMax)
+}
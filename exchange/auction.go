@@ -5,6 +5,7 @@ import (
 	"encoding/xml"
 	"errors"
 	"fmt"
+	"math"
 	"regexp"
 	"sort"
 	"strings"
@@ -17,6 +18,7 @@ import (
 	"github.com/prebid/prebid-server/v2/openrtb_ext"
 	"github.com/prebid/prebid-server/v2/prebid_cache_client"
 	"github.com/prebid/prebid-server/v2/util/jsonutil"
+	"github.com/prebid/prebid-server/v2/util/uuidutil"
 )
 
 const (
@@ -106,7 +108,7 @@ func (d *DebugLog) PutDebugLogError(cache prebid_cache_client.Client, timeout in
 	return nil
 }
 
-func newAuction(seatBids map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid, numImps int, preferDeals bool) *auction {
+func newAuction(seatBids map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid, numImps int, preferDeals bool, mediaTypes mediaTypePreference) *auction {
 	winningBids := make(map[string]*entities.PbsOrtbBid, numImps)
 	allBidsByBidder := make(map[string]map[openrtb_ext.BidderName][]*entities.PbsOrtbBid, numImps)
 
@@ -114,7 +116,7 @@ func newAuction(seatBids map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid, nu
 		if seatBid != nil {
 			for _, bid := range seatBid.Bids {
 				wbid, ok := winningBids[bid.Bid.ImpID]
-				if !ok || isNewWinningBid(bid.Bid, wbid.Bid, preferDeals) {
+				if !ok || outranks(bid, wbid, preferDeals) || winsTie(bid, wbid, preferDeals, mediaTypes) {
 					winningBids[bid.Bid.ImpID] = bid
 				}
 
@@ -132,6 +134,7 @@ func newAuction(seatBids map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid, nu
 	return &auction{
 		winningBids:     winningBids,
 		allBidsByBidder: allBidsByBidder,
+		mediaTypes:      mediaTypes,
 	}
 }
 
@@ -148,13 +151,36 @@ func isNewWinningBid(bid, wbid *openrtb2.Bid, preferDeals bool) bool {
 	return bid.Price > wbid.Price
 }
 
+// outranks calculates if the new bid will win against the current winning bid. Bids which matched one of the
+// account's line items win over bids which didn't, and the bids of higher priority line items over those of
+// lower priority ones. Other bids are ranked by isNewWinningBid.
+func outranks(bid, wbid *entities.PbsOrtbBid, preferDeals bool) bool {
+	if bidRank, wbidRank := lineItemRank(bid), lineItemRank(wbid); bidRank != wbidRank {
+		return bidRank < wbidRank
+	}
+	return isNewWinningBid(bid.Bid, wbid.Bid, preferDeals)
+}
+
+func lineItemRank(bid *entities.PbsOrtbBid) int {
+	if bid.LineItem == nil {
+		return math.MaxInt
+	}
+	return bid.LineItem.Priority
+}
+
+// winsTie returns whether the new bid (bid) ties with the current winning bid (wbid), and wins because the
+// account prefers its media type.
+func winsTie(bid, wbid *entities.PbsOrtbBid, preferDeals bool, mediaTypes mediaTypePreference) bool {
+	return !outranks(bid, wbid, preferDeals) && !outranks(wbid, bid, preferDeals) && mediaTypes.prefers(bid.BidType, wbid.BidType)
+}
+
 func (a *auction) validateAndUpdateMultiBid(adapterBids map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid, preferDeals bool, accountDefaultBidLimit int) {
 	bidsSnipped := false
 	// sort bids for multibid targeting
 	for _, topBidsPerBidder := range a.allBidsByBidder {
 		for bidder, topBids := range topBidsPerBidder {
 			sort.Slice(topBids, func(i, j int) bool {
-				return isNewWinningBid(topBids[i].Bid, topBids[j].Bid, preferDeals)
+				return outranks(topBids[i], topBids[j], preferDeals) || winsTie(topBids[i], topBids[j], preferDeals, a.mediaTypes)
 			})
 
 			// assert hard limit on bids count per imp, per adapter.
@@ -197,7 +223,7 @@ func (a *auction) setRoundedPrices(targetingData targetData) {
 	a.roundedPrices = roundedPrices
 }
 
-func (a *auction) doCache(ctx context.Context, cache prebid_cache_client.Client, targData *targetData, evTracking *eventTracking, bidRequest *openrtb2.BidRequest, ttlBuffer int64, defaultTTLs *config.DefaultTTLs, bidCategory map[string]string, debugLog *DebugLog) []error {
+func (a *auction) doCache(ctx context.Context, cache prebid_cache_client.Client, targData *targetData, evTracking *eventTracking, bidRequest *openrtb2.BidRequest, ttlBuffer int64, defaultTTLs *config.DefaultTTLs, bidCategory map[string]string, debugLog *DebugLog, cacheIDs uuidutil.UUIDGenerator) []error {
 	var bids, vast, includeBidderKeys, includeWinners bool = targData.includeCacheBids, targData.includeCacheVast, targData.includeBidderKeys, targData.includeWinners
 	if !((bids || vast) && (includeBidderKeys || includeWinners)) {
 		return nil
@@ -213,8 +239,8 @@ func (a *auction) doCache(ctx context.Context, cache prebid_cache_client.Client,
 	var hbCacheID string
 	if len(bidCategory) > 0 {
 		// assert:  category of winning bids never duplicated
-		if rawUuid, err := uuid.NewV4(); err == nil {
-			hbCacheID = rawUuid.String()
+		if cacheID, err := cacheIDs.Generate(); err == nil {
+			hbCacheID = cacheID
 			competitiveExclusion = true
 		} else {
 			errs = append(errs, errors.New("failed to create custom cache key"))
@@ -289,7 +315,8 @@ func (a *auction) doCache(ctx context.Context, cache prebid_cache_client.Client,
 		}
 	}
 
-	if len(toCache) > 0 && debugLog != nil && debugLog.DebugEnabledOrOverridden {
+	// only endpoints which give the debug log a cache type, like /openrtb2/video, have it cached
+	if len(toCache) > 0 && debugLog != nil && debugLog.DebugEnabledOrOverridden && debugLog.CacheType != "" {
 		debugLog.CacheKey = hbCacheID
 		debugLog.BuildCacheString()
 		if jsonBytes, err := jsonutil.Marshal(debugLog.CacheString); err == nil {
@@ -402,4 +429,6 @@ type auction struct {
 	cacheIds map[*openrtb2.Bid]string
 	// vastCacheIds stores UUIDS from Prebid cache for fetching the VAST markup to video bids.
 	vastCacheIds map[*openrtb2.Bid]string
+	// mediaTypes breaks ties between bids for the same imp, if the account has a preference.
+	mediaTypes mediaTypePreference
 }
@@ -0,0 +1,67 @@
+package exchange
+
+import (
+	"github.com/prebid/prebid-server/v2/auctionquality"
+	"github.com/prebid/prebid-server/v2/errortypes"
+	"github.com/prebid/prebid-server/v2/openrtb_ext"
+)
+
+// recordAuctionQuality adds how each bidder fared, and the validation errors and warnings of the response,
+// to the account's auction quality statistics. Bidders time out or fail by the codes of their errors, and
+// their bids are rejected by floors and by the response validations which report seat non bids.
+func recordAuctionQuality(tracker *auctionquality.Tracker, accountID string, bidderRequests []BidderRequest, adapterExtra map[openrtb_ext.BidderName]*seatResponseExtra, floorRejections map[string]int, seatNonBids nonBids, bidResponseExt *openrtb_ext.ExtBidResponse) {
+	if tracker == nil {
+		return
+	}
+
+	auction := auctionquality.Auction{Account: accountID, Validation: make(map[int]int)}
+	bidders := make(map[string]*auctionquality.Bidder, len(bidderRequests))
+	bidderOf := func(name string) *auctionquality.Bidder {
+		bidder, ok := bidders[name]
+		if !ok {
+			bidder = &auctionquality.Bidder{Name: name}
+			bidders[name] = bidder
+		}
+		return bidder
+	}
+
+	for _, bidderRequest := range bidderRequests {
+		bidder := bidderOf(bidderRequest.BidderName.String())
+		bidder.Called = true
+		extra := adapterExtra[bidderRequest.BidderName]
+		if extra == nil {
+			continue
+		}
+		for _, message := range extra.Errors {
+			switch {
+			case message.Code == errortypes.TimeoutErrorCode || message.Code == errortypes.TmaxTimeoutErrorCode:
+				bidder.TimedOut = true
+			case message.Category == errortypes.CategoryAdapter:
+				bidder.Failed = true
+			}
+		}
+	}
+	for seat, n := range floorRejections {
+		bidderOf(seat).FloorRejections += n
+	}
+	for seat, nonBids := range seatNonBids.seatNonBidsMap {
+		bidderOf(seat).RejectedBids += len(nonBids)
+	}
+
+	if bidResponseExt != nil {
+		for _, messages := range []map[openrtb_ext.BidderName][]openrtb_ext.ExtBidderMessage{bidResponseExt.Errors, bidResponseExt.Warnings} {
+			for _, bidderMessages := range messages {
+				for _, message := range bidderMessages {
+					if message.Category == errortypes.CategoryValidation {
+						auction.Validation[message.Code]++
+					}
+				}
+			}
+		}
+	}
+
+	for _, bidder := range bidders {
+		auction.Bidders = append(auction.Bidders, *bidder)
+	}
+	tracker.Record(auction)
+}
@@ -0,0 +1,64 @@
+package exchange
+
+import (
+	"testing"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v2/auctionquality"
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/errortypes"
+	"github.com/prebid/prebid-server/v2/exchange/entities"
+	"github.com/prebid/prebid-server/v2/openrtb_ext"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecordAuctionQuality(t *testing.T) {
+	tracker := auctionquality.New(config.AuctionQuality{Enabled: true, WindowMinutes: 60, MaxAccounts: 100})
+	bidderRequests := []BidderRequest{
+		{BidderName: "appnexus", BidRequest: &openrtb2.BidRequest{}},
+		{BidderName: "rubicon", BidRequest: &openrtb2.BidRequest{}},
+		{BidderName: "openx", BidRequest: &openrtb2.BidRequest{}},
+	}
+	adapterExtra := map[openrtb_ext.BidderName]*seatResponseExtra{
+		"appnexus": {Errors: []openrtb_ext.ExtBidderMessage{
+			openrtb_ext.NewExtBidderMessage(&errortypes.Timeout{Message: "timed out"}),
+		}},
+		"rubicon": {Errors: []openrtb_ext.ExtBidderMessage{
+			openrtb_ext.NewExtBidderMessage(&errortypes.BadServerResponse{Message: "bad response"}),
+		}},
+		"openx": {},
+	}
+	seatNonBids := nonBids{}
+	seatNonBids.addBid(&entities.PbsOrtbBid{Bid: &openrtb2.Bid{ImpID: "imp"}}, int(ResponseRejectedCreativeSizeNotAllowed), "openx")
+	bidResponseExt := &openrtb_ext.ExtBidResponse{
+		Errors: map[openrtb_ext.BidderName][]openrtb_ext.ExtBidderMessage{
+			openrtb_ext.BidderReservedGeneral: {openrtb_ext.NewExtBidderMessage(&errortypes.BadInput{Message: "invalid"})},
+		},
+		Warnings: map[openrtb_ext.BidderName][]openrtb_ext.ExtBidderMessage{
+			openrtb_ext.BidderReservedGeneral: {
+				openrtb_ext.NewExtBidderMessage(&errortypes.Warning{Message: "normalized", WarningCode: errortypes.RequestNormalizedWarningCode}),
+				openrtb_ext.NewExtBidderMessage(&errortypes.Warning{Message: "debug disabled", WarningCode: errortypes.AccountLevelDebugDisabledWarningCode}),
+			},
+		},
+	}
+
+	recordAuctionQuality(tracker, "acct", bidderRequests, adapterExtra, map[string]int{"rubicon": 2}, seatNonBids, bidResponseExt)
+
+	report := tracker.Report("acct")
+	assert.Equal(t, int64(1), report.Auctions)
+	assert.ElementsMatch(t, []auctionquality.BidderRow{
+		{Bidder: "appnexus", Requests: 1, Timeouts: 1, TimeoutRate: 1},
+		{Bidder: "rubicon", Requests: 1, Failures: 1, FloorRejections: 2},
+		{Bidder: "openx", Requests: 1, RejectedBids: 1},
+	}, report.Bidders)
+	assert.ElementsMatch(t, []auctionquality.ValidationRow{
+		{Code: errortypes.BadInputErrorCode, Count: 1},
+		{Code: errortypes.RequestNormalizedWarningCode, Count: 1},
+	}, report.Validation)
+}
+
+func TestRecordAuctionQualityDisabled(t *testing.T) {
+	assert.NotPanics(t, func() {
+		recordAuctionQuality(nil, "acct", []BidderRequest{{BidderName: "appnexus"}}, nil, nil, nonBids{}, nil)
+	})
+}
@@ -18,6 +18,7 @@ import (
 	"github.com/prebid/prebid-server/v2/prebid_cache_client"
 	"github.com/prebid/prebid-server/v2/util/jsonutil"
 	"github.com/prebid/prebid-server/v2/util/ptrutil"
+	"github.com/prebid/prebid-server/v2/util/uuidutil"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -274,7 +275,7 @@ func runCacheSpec(t *testing.T, fileDisplayName string, specData *cacheSpec) {
 		externalURL:        "http://localhost",
 		auctionTimestampMs: 1234567890,
 	}
-	_ = testAuction.doCache(ctx, cache, targData, evTracking, &specData.BidRequest, 60, &specData.DefaultTTLs, bidCategory, &specData.DebugLog)
+	_ = testAuction.doCache(ctx, cache, targData, evTracking, &specData.BidRequest, 60, &specData.DefaultTTLs, bidCategory, &specData.DebugLog, uuidutil.UUIDRandomGenerator{})
 
 	if len(specData.ExpectedCacheables) > len(cache.items) {
 		t.Errorf("%s:  [CACHE_ERROR] Less elements were cached than expected \n", fileDisplayName)
@@ -578,13 +579,69 @@ func TestNewAuction(t *testing.T) {
 	}
 
 	for _, test := range tests {
-		auc := newAuction(test.seatBids, test.numImps, test.preferDeals)
+		auc := newAuction(test.seatBids, test.numImps, test.preferDeals, nil)
 
 		assert.Equal(t, test.expectedAuction, *auc, test.description)
 	}
 
 }
 
+func TestNewAuctionWithLineItems(t *testing.T) {
+	bidP230 := entities.PbsOrtbBid{Bid: &openrtb2.Bid{ImpID: "imp1", Price: 2.30}}
+	bidP300Deal := entities.PbsOrtbBid{Bid: &openrtb2.Bid{ImpID: "imp1", Price: 3.00, DealID: "deal-3"}}
+	bidP050Priority2 := entities.PbsOrtbBid{
+		Bid:      &openrtb2.Bid{ImpID: "imp1", Price: 0.50, DealID: "deal-2"},
+		LineItem: &openrtb_ext.ExtBidPrebidLineItem{ID: "li-2", Priority: 2},
+	}
+	bidP077Priority1 := entities.PbsOrtbBid{
+		Bid:      &openrtb2.Bid{ImpID: "imp1", Price: 0.77},
+		LineItem: &openrtb_ext.ExtBidPrebidLineItem{ID: "li-1", Priority: 1},
+	}
+	bidP120Priority1 := entities.PbsOrtbBid{
+		Bid:      &openrtb2.Bid{ImpID: "imp1", Price: 1.20},
+		LineItem: &openrtb_ext.ExtBidPrebidLineItem{ID: "li-1", Priority: 1},
+	}
+
+	tests := []struct {
+		description        string
+		seatBids           map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid
+		preferDeals        bool
+		expectedWinningBid *entities.PbsOrtbBid
+	}{
+		{
+			description: "line item bid wins over higher priced bids",
+			seatBids: map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid{
+				"appnexus": {Bids: []*entities.PbsOrtbBid{&bidP230}},
+				"rubicon":  {Bids: []*entities.PbsOrtbBid{&bidP050Priority2}},
+				"pubmatic": {Bids: []*entities.PbsOrtbBid{&bidP300Deal}},
+			},
+			preferDeals:        true,
+			expectedWinningBid: &bidP050Priority2,
+		},
+		{
+			description: "higher priority line item wins",
+			seatBids: map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid{
+				"appnexus": {Bids: []*entities.PbsOrtbBid{&bidP050Priority2}},
+				"rubicon":  {Bids: []*entities.PbsOrtbBid{&bidP077Priority1}},
+			},
+			expectedWinningBid: &bidP077Priority1,
+		},
+		{
+			description: "price decides between bids of the same priority",
+			seatBids: map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid{
+				"appnexus": {Bids: []*entities.PbsOrtbBid{&bidP077Priority1}},
+				"rubicon":  {Bids: []*entities.PbsOrtbBid{&bidP120Priority1, &bidP230}},
+			},
+			expectedWinningBid: &bidP120Priority1,
+		},
+	}
+
+	for _, test := range tests {
+		auc := newAuction(test.seatBids, 1, test.preferDeals, nil)
+		assert.Equal(t, test.expectedWinningBid, auc.winningBids["imp1"], test.description)
+	}
+}
+
 func TestValidateAndUpdateMultiBid(t *testing.T) {
 	// create new bids for new test cases since the last one changes a few bids. Ex marks bid1p001.Bid = nil
 	bid1p001 := entities.PbsOrtbBid{
@@ -0,0 +1,98 @@
+package exchange
+
+import (
+	"fmt"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v2/bidlandscape"
+	"github.com/prebid/prebid-server/v2/currency"
+	"github.com/prebid/prebid-server/v2/exchange/entities"
+	"github.com/prebid/prebid-server/v2/openrtb_ext"
+)
+
+// recordBidLandscape adds the imps sent to each bidder and the bids they returned to the bid landscape.
+// Simulated bidders weren't really asked, so neither their requests nor their bids count. The winner of
+// each imp is the bid with the highest price, whether or not it has targeting.
+func recordBidLandscape(landscape *bidlandscape.Landscape, accountID string, bidderRequests []BidderRequest, adapterBids map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid, conversions currency.Conversions) {
+	if landscape == nil {
+		return
+	}
+
+	auction := bidlandscape.Auction{Account: accountID}
+	impSizes := make(map[string][]string)
+	simulated := make(map[openrtb_ext.BidderName]bool)
+	for _, bidderRequest := range bidderRequests {
+		if bidderRequest.SimulatedResponse != nil {
+			simulated[bidderRequest.BidderName] = true
+			continue
+		}
+		for i := range bidderRequest.BidRequest.Imp {
+			imp := &bidderRequest.BidRequest.Imp[i]
+			sizes, ok := impSizes[imp.ID]
+			if !ok {
+				sizes = landscapeSizes(imp)
+				impSizes[imp.ID] = sizes
+			}
+			auction.Requests = append(auction.Requests, bidlandscape.Request{Bidder: bidderRequest.BidderName.String(), Sizes: sizes})
+		}
+	}
+
+	winners := make(map[string]int)
+	for seat, seatBid := range adapterBids {
+		if seatBid == nil || simulated[seat] {
+			continue
+		}
+		rate, err := conversions.GetRate(seatBid.Currency, "USD")
+		if err != nil {
+			continue
+		}
+		for _, pbsBid := range seatBid.Bids {
+			if pbsBid == nil || pbsBid.Bid == nil {
+				continue
+			}
+			bid := bidlandscape.Bid{Bidder: seat.String(), Price: pbsBid.Bid.Price * rate}
+			if pbsBid.Bid.W > 0 && pbsBid.Bid.H > 0 {
+				bid.Size = fmt.Sprintf("%dx%d", pbsBid.Bid.W, pbsBid.Bid.H)
+			} else if sizes := impSizes[pbsBid.Bid.ImpID]; len(sizes) > 0 {
+				bid.Size = sizes[0]
+			}
+			if winner, ok := winners[pbsBid.Bid.ImpID]; !ok || bid.Price > auction.Bids[winner].Price {
+				winners[pbsBid.Bid.ImpID] = len(auction.Bids)
+			}
+			auction.Bids = append(auction.Bids, bid)
+		}
+	}
+	for _, winner := range winners {
+		auction.Bids[winner].Won = true
+	}
+
+	landscape.Record(auction)
+}
+
+// landscapeSizes returns the sizes which an imp takes: its banner formats, its video player size, or the
+// media type for imps without a size.
+func landscapeSizes(imp *openrtb2.Imp) []string {
+	var sizes []string
+	if imp.Banner != nil {
+		for _, format := range imp.Banner.Format {
+			sizes = append(sizes, fmt.Sprintf("%dx%d", format.W, format.H))
+		}
+		if len(sizes) == 0 && imp.Banner.W != nil && imp.Banner.H != nil {
+			sizes = append(sizes, fmt.Sprintf("%dx%d", *imp.Banner.W, *imp.Banner.H))
+		}
+	}
+	if imp.Video != nil {
+		if imp.Video.W != nil && imp.Video.H != nil && *imp.Video.W > 0 && *imp.Video.H > 0 {
+			sizes = append(sizes, fmt.Sprintf("%dx%d", *imp.Video.W, *imp.Video.H))
+		} else if len(sizes) == 0 {
+			sizes = append(sizes, string(openrtb_ext.BidTypeVideo))
+		}
+	}
+	if len(sizes) == 0 && imp.Native != nil {
+		sizes = append(sizes, string(openrtb_ext.BidTypeNative))
+	}
+	if len(sizes) == 0 && imp.Audio != nil {
+		sizes = append(sizes, string(openrtb_ext.BidTypeAudio))
+	}
+	return sizes
+}
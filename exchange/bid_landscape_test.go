@@ -0,0 +1,136 @@
+package exchange
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v2/bidlandscape"
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/currency"
+	"github.com/prebid/prebid-server/v2/exchange/entities"
+	"github.com/prebid/prebid-server/v2/openrtb_ext"
+	"github.com/prebid/prebid-server/v2/util/ptrutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecordBidLandscape(t *testing.T) {
+	landscape := bidlandscape.New(config.BidLandscape{
+		Enabled:       true,
+		WindowMinutes: 60,
+		PriceBuckets:  []float64{1},
+		MaxKeys:       100,
+		Tokens:        []string{"token"},
+	})
+	imps := []openrtb2.Imp{
+		{ID: "banner", Banner: &openrtb2.Banner{Format: []openrtb2.Format{{W: 300, H: 250}, {W: 300, H: 600}}}},
+		{ID: "video", Video: &openrtb2.Video{W: ptrutil.ToPtr[int64](640), H: ptrutil.ToPtr[int64](480)}},
+	}
+	bidderRequests := []BidderRequest{
+		{BidderName: "appnexus", BidRequest: &openrtb2.BidRequest{Imp: imps}},
+		{BidderName: "rubicon", BidRequest: &openrtb2.BidRequest{Imp: imps[:1]}},
+		{BidderName: "simulated", BidRequest: &openrtb2.BidRequest{Imp: imps}, SimulatedResponse: json.RawMessage(`{}`)},
+	}
+	adapterBids := map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid{
+		"appnexus": {
+			Currency: "EUR",
+			Bids: []*entities.PbsOrtbBid{
+				{Bid: &openrtb2.Bid{ImpID: "banner", Price: 1, W: 300, H: 600}},
+				{Bid: &openrtb2.Bid{ImpID: "video", Price: 4}},
+			},
+		},
+		"rubicon": {
+			Currency: "USD",
+			Bids:     []*entities.PbsOrtbBid{{Bid: &openrtb2.Bid{ImpID: "banner", Price: 1.5}}},
+		},
+		"simulated": {
+			Currency: "USD",
+			Bids:     []*entities.PbsOrtbBid{{Bid: &openrtb2.Bid{ImpID: "banner", Price: 100}}},
+		},
+	}
+	conversions := currency.NewRates(map[string]map[string]float64{"EUR": {"USD": 2}})
+
+	recordBidLandscape(landscape, "acct", bidderRequests, adapterBids, conversions)
+
+	rows := make(map[bidlandscape.Key]bidlandscape.Row)
+	report := landscape.Report(bidlandscape.Query{GroupBy: []bidlandscape.Dimension{bidlandscape.DimensionBidder, bidlandscape.DimensionAccount, bidlandscape.DimensionSize}})
+	for _, row := range report.Rows {
+		rows[row.Key] = row
+	}
+	assert.Len(t, rows, 5)
+
+	appnexusBanner := rows[bidlandscape.Key{Bidder: "appnexus", Account: "acct", Size: "300x600"}]
+	assert.Equal(t, int64(1), appnexusBanner.Requests)
+	assert.Equal(t, int64(1), appnexusBanner.Bids)
+	assert.Equal(t, int64(1), appnexusBanner.Wins, "2 USD beats rubicon's 1.5 USD")
+	assert.Equal(t, 2.0, appnexusBanner.AvgPrice)
+
+	appnexusVideo := rows[bidlandscape.Key{Bidder: "appnexus", Account: "acct", Size: "640x480"}]
+	assert.Equal(t, int64(1), appnexusVideo.Requests)
+	assert.Equal(t, int64(1), appnexusVideo.Wins)
+	assert.Equal(t, 8.0, appnexusVideo.AvgPrice)
+
+	rubiconBanner := rows[bidlandscape.Key{Bidder: "rubicon", Account: "acct", Size: "300x250"}]
+	assert.Equal(t, int64(1), rubiconBanner.Requests)
+	assert.Equal(t, int64(1), rubiconBanner.Bids, "bids without a size take the imp's first size")
+	assert.Equal(t, int64(0), rubiconBanner.Wins)
+
+	assert.Equal(t, int64(1), rows[bidlandscape.Key{Bidder: "appnexus", Account: "acct", Size: "300x250"}].Requests)
+	assert.Equal(t, int64(1), rows[bidlandscape.Key{Bidder: "rubicon", Account: "acct", Size: "300x600"}].Requests)
+}
+
+func TestRecordBidLandscapeDisabled(t *testing.T) {
+	assert.NotPanics(t, func() {
+		recordBidLandscape(nil, "acct", []BidderRequest{{BidderName: "appnexus", BidRequest: &openrtb2.BidRequest{}}}, nil, nil)
+	})
+}
+
+func TestLandscapeSizes(t *testing.T) {
+	tests := []struct {
+		description   string
+		imp           openrtb2.Imp
+		expectedSizes []string
+	}{
+		{
+			description:   "banner-formats",
+			imp:           openrtb2.Imp{Banner: &openrtb2.Banner{Format: []openrtb2.Format{{W: 300, H: 250}, {W: 728, H: 90}}}},
+			expectedSizes: []string{"300x250", "728x90"},
+		},
+		{
+			description:   "banner-size",
+			imp:           openrtb2.Imp{Banner: &openrtb2.Banner{W: ptrutil.ToPtr[int64](320), H: ptrutil.ToPtr[int64](50)}},
+			expectedSizes: []string{"320x50"},
+		},
+		{
+			description:   "video-without-size",
+			imp:           openrtb2.Imp{Video: &openrtb2.Video{}},
+			expectedSizes: []string{"video"},
+		},
+		{
+			description:   "banner-and-video-without-size",
+			imp:           openrtb2.Imp{Banner: &openrtb2.Banner{Format: []openrtb2.Format{{W: 300, H: 250}}}, Video: &openrtb2.Video{}},
+			expectedSizes: []string{"300x250"},
+		},
+		{
+			description:   "native",
+			imp:           openrtb2.Imp{Native: &openrtb2.Native{}},
+			expectedSizes: []string{"native"},
+		},
+		{
+			description:   "audio",
+			imp:           openrtb2.Imp{Audio: &openrtb2.Audio{}},
+			expectedSizes: []string{"audio"},
+		},
+		{
+			description:   "nothing",
+			imp:           openrtb2.Imp{},
+			expectedSizes: nil,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			assert.Equal(t, test.expectedSizes, landscapeSizes(&test.imp))
+		})
+	}
+}
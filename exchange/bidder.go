@@ -8,7 +8,6 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"io"
 	"net/http"
 	"net/http/httptrace"
 	"regexp"
@@ -16,13 +15,13 @@ import (
 	"sync"
 	"time"
 
-	"github.com/golang/glog"
 	"github.com/prebid/prebid-server/v2/bidadjustment"
 	"github.com/prebid/prebid-server/v2/config/util"
 	"github.com/prebid/prebid-server/v2/currency"
 	"github.com/prebid/prebid-server/v2/exchange/entities"
 	"github.com/prebid/prebid-server/v2/experiment/adscert"
 	"github.com/prebid/prebid-server/v2/hooks/hookexecution"
+	"github.com/prebid/prebid-server/v2/logger"
 	"github.com/prebid/prebid-server/v2/version"
 
 	"github.com/prebid/openrtb/v20/adcom1"
@@ -34,6 +33,7 @@ import (
 	"github.com/prebid/prebid-server/v2/errortypes"
 	"github.com/prebid/prebid-server/v2/metrics"
 	"github.com/prebid/prebid-server/v2/openrtb_ext"
+	"github.com/prebid/prebid-server/v2/util/bufferpool"
 	"github.com/prebid/prebid-server/v2/util/jsonutil"
 	"golang.org/x/net/context/ctxhttp"
 )
@@ -72,10 +72,13 @@ type bidRequestOptions struct {
 	tmaxAdjustments        *TmaxAdjustmentsPreprocessed
 	bidderRequestStartTime time.Time
 	responseDebugAllowed   bool
+	resolvedRequests       *resolvedBidderRequests
 }
 
 type extraBidderRespInfo struct {
 	respProcessingStartTime time.Time
+	// resolvedRequest is the request sent to the bidder, if it's asked for in the debug output
+	resolvedRequest json.RawMessage
 }
 
 type extraAuctionResponseInfo struct {
@@ -96,16 +99,28 @@ const (
 // The name refers to the "Adapter" architecture pattern, and should not be confused with a Prebid "Adapter"
 // (which is being phased out and replaced by Bidder for OpenRTB auctions)
 func AdaptBidder(bidder adapters.Bidder, client *http.Client, cfg *config.Configuration, me metrics.MetricsEngine, name openrtb_ext.BidderName, debugInfo *config.DebugInfo, endpointCompression string) AdaptedBidder {
+	return adaptBidder(bidder, client, cfg, me, name, debugInfo, endpointCompression, 0, nil, nil, nil, nil)
+}
+
+func adaptBidder(bidder adapters.Bidder, client *http.Client, cfg *config.Configuration, me metrics.MetricsEngine, name openrtb_ext.BidderName, debugInfo *config.DebugInfo, endpointCompression string, maxResponseSize int64, requestPool *bidderRequestPool, responseCache *bidderResponseCache, timeoutNotifier *timeoutNotifier, hedger *requestHedger) AdaptedBidder {
+	if maxResponseSize == 0 {
+		maxResponseSize = cfg.MaxBidderResponseSize
+	}
 	return &bidderAdapter{
-		Bidder:     bidder,
-		BidderName: name,
-		Client:     client,
-		me:         me,
+		Bidder:          bidder,
+		BidderName:      name,
+		Client:          client,
+		me:              me,
+		requestPool:     requestPool,
+		responseCache:   responseCache,
+		timeoutNotifier: timeoutNotifier,
+		hedger:          hedger,
 		config: bidderAdapterConfig{
 			Debug:               cfg.Debug,
 			DisableConnMetrics:  cfg.Metrics.Disabled.AdapterConnectionMetrics,
 			DebugInfo:           config.DebugInfo{Allow: parseDebugInfo(debugInfo)},
 			EndpointCompression: endpointCompression,
+			MaxResponseSize:     maxResponseSize,
 		},
 	}
 }
@@ -123,6 +138,14 @@ type bidderAdapter struct {
 	Client     *http.Client
 	me         metrics.MetricsEngine
 	config     bidderAdapterConfig
+	// requestPool runs the bidder's HTTP requests if bidder requests are bounded by a shared pool
+	requestPool *bidderRequestPool
+	// responseCache answers the bidder's requests with the responses to earlier requests for the same context
+	responseCache *bidderResponseCache
+	// timeoutNotifier sends the bidder's timeout notifications within its rate limit
+	timeoutNotifier *timeoutNotifier
+	// hedger sends the bidder's requests a second time if they're slow to be answered, if they're hedged
+	hedger *requestHedger
 }
 
 type bidderAdapterConfig struct {
@@ -130,10 +153,12 @@ type bidderAdapterConfig struct {
 	DisableConnMetrics  bool
 	DebugInfo           config.DebugInfo
 	EndpointCompression string
+	MaxResponseSize     int64
 }
 
 func (bidder *bidderAdapter) requestBid(ctx context.Context, bidderRequest BidderRequest, conversions currency.Conversions, reqInfo *adapters.ExtraRequestInfo, adsCertSigner adscert.Signer, bidRequestOptions bidRequestOptions, alternateBidderCodes openrtb_ext.ExtAlternateBidderCodes, hookExecutor hookexecution.StageExecutor, ruleToAdjustments openrtb_ext.AdjustmentsByDealID) ([]*entities.PbsOrtbSeatBid, extraBidderRespInfo, []error) {
 	request := openrtb_ext.RequestWrapper{BidRequest: bidderRequest.BidRequest}
+	piiScan := newPIIScan(bidderRequest.PIIPolicy, bidderRequest.BidRequest)
 	reject := hookExecutor.ExecuteBidderRequestStage(&request, string(bidderRequest.BidderName))
 	if reject != nil {
 		return nil, extraBidderRespInfo{}, []error{reject}
@@ -144,28 +169,40 @@ func (bidder *bidderAdapter) requestBid(ctx context.Context, bidderRequest Bidde
 		errs            []error
 		responseChannel chan *httpCallInfo
 		extraRespInfo   extraBidderRespInfo
+		// responseCacheKeys are the keys to cache the responses to the requests made under, if they're cached
+		responseCacheKeys map[*adapters.RequestData][]byte
 	)
 
 	// rebuild request after modules execution
 	request.RebuildRequest()
 	bidderRequest.BidRequest = request.BidRequest
+	piiScan.modulesRan(bidderRequest.BidRequest)
 
 	//check if real request exists for this bidder or it only has stored responses
 	dataLen := 0
-	if len(bidderRequest.BidRequest.Imp) > 0 {
+	if bidderRequest.SimulatedResponse != nil {
+		// the bidder isn't called in a simulated auction, since its response is given
+		extraRespInfo.resolvedRequest, errs = resolveBidderRequest(bidderRequest.BidRequest, bidRequestOptions.resolvedRequests, errs)
+		dataLen = 1 + len(bidderRequest.BidderStoredResponses)
+		responseChannel = make(chan *httpCallInfo, dataLen)
+		responseChannel <- prepareSimulatedResponse(bidderRequest.BidderName, bidderRequest.SimulatedResponse)
+	} else if len(bidderRequest.BidRequest.Imp) > 0 {
 		// Reducing the amount of time bidders have to compensate for the processing time used by PBS to fetch a stored request (if needed), validate the OpenRTB request and split it into multiple requests sanitized for each bidder
 		// As well as for the time needed by PBS to prepare the auction response
 		if bidRequestOptions.tmaxAdjustments != nil && bidRequestOptions.tmaxAdjustments.IsEnforced {
 			bidderRequest.BidRequest.TMax = getBidderTmax(&bidderTmaxCtx{ctx}, bidderRequest.BidRequest.TMax, *bidRequestOptions.tmaxAdjustments)
 		}
+		var resolveErrs []error
+		extraRespInfo.resolvedRequest, resolveErrs = resolveBidderRequest(bidderRequest.BidRequest, bidRequestOptions.resolvedRequests, nil)
 		reqData, errs = bidder.Bidder.MakeRequests(bidderRequest.BidRequest, reqInfo)
+		errs = append(errs, resolveErrs...)
 
 		if len(reqData) == 0 {
 			// If the adapter failed to generate both requests and errors, this is an error.
 			if len(errs) == 0 {
 				errs = append(errs, &errortypes.FailedToRequestBids{Message: "The adapter failed to generate any bid requests, but also failed to generate an error explaining why"})
 			}
-			return nil, extraBidderRespInfo{}, errs
+			return nil, extraRespInfo, errs
 		}
 		xPrebidHeader := version.BuildXPrebidHeaderForRequest(bidderRequest.BidRequest, version.Ver)
 
@@ -185,7 +222,10 @@ func (bidder *bidderAdapter) requestBid(ctx context.Context, bidderRequest Bidde
 				bidder.me.RecordAdsCertSignTime(time.Since(startSignRequestTime))
 				if err != nil {
 					bidder.me.RecordAdsCertReq(false)
-					errs = append(errs, &errortypes.Warning{Message: fmt.Sprintf("AdsCert signer is enabled but cannot sign the request: %s", err.Error())})
+					errs = append(errs, &errortypes.Warning{
+						Message:     fmt.Sprintf("AdsCert signer is enabled but cannot sign the request: %s", err.Error()),
+						WarningCode: errortypes.AdsCertSigningWarningCode,
+					})
 				}
 				if err == nil && len(signatureMessage) > 0 {
 					reqData[i].Headers.Add(adscert.SignHeader, signatureMessage)
@@ -194,11 +234,20 @@ func (bidder *bidderAdapter) requestBid(ctx context.Context, bidderRequest Bidde
 			}
 
 		}
+		var piiErrs []error
+		reqData, piiErrs = bidder.scanForPII(piiScan, bidderRequest.BidderLabels.Adapter, reqData, bidRequestOptions.responseDebugAllowed)
+		errs = append(errs, piiErrs...)
+
 		// Make any HTTP requests in parallel.
 		// If the bidder only needs to make one, save some cycles by just using the current one.
 		dataLen = len(reqData) + len(bidderRequest.BidderStoredResponses)
 		responseChannel = make(chan *httpCallInfo, dataLen)
-		if len(reqData) == 1 {
+		reqData, responseCacheKeys = bidder.responseCache.answer(bidderRequest, bidder.responseCache.fingerprint(bidderRequest), reqData, responseChannel)
+		if bidder.requestPool != nil {
+			for _, oneReqData := range reqData {
+				bidder.submitRequest(ctx, oneReqData, responseChannel, bidRequestOptions)
+			}
+		} else if len(reqData) == 1 {
 			responseChannel <- bidder.doRequest(ctx, reqData[0], bidRequestOptions.bidderRequestStartTime, bidRequestOptions.tmaxAdjustments)
 		} else {
 			for _, oneReqData := range reqData {
@@ -235,6 +284,7 @@ func (bidder *bidderAdapter) requestBid(ctx context.Context, bidderRequest Bidde
 	// even if the timeout occurs sometime halfway through.
 	for i := 0; i < dataLen; i++ {
 		httpInfo := <-responseChannel
+		bidder.responseCache.store(responseCacheKeys, httpInfo)
 		// If this is a test bid, capture debugging info from the requests.
 		// Write debug data to ext in case if:
 		// - headerDebugAllowed (debug override header specified correct) - it overrides all other debug restrictions
@@ -258,7 +308,13 @@ func (bidder *bidderAdapter) requestBid(ctx context.Context, bidderRequest Bidde
 
 		if httpInfo.err == nil {
 			extraRespInfo.respProcessingStartTime = time.Now()
-			bidResponse, moreErrs := bidder.Bidder.MakeBids(bidderRequest.BidRequest, httpInfo.request, httpInfo.response)
+			var bidResponse *adapters.BidderResponse
+			var moreErrs []error
+			if httpInfo.simulated {
+				bidResponse, moreErrs = makeSimulatedBids(httpInfo.response)
+			} else {
+				bidResponse, moreErrs = bidder.Bidder.MakeBids(bidderRequest.BidRequest, httpInfo.request, httpInfo.response)
+			}
 			errs = append(errs, moreErrs...)
 
 			if bidResponse != nil {
@@ -516,13 +572,44 @@ func makeExt(httpInfo *httpCallInfo) *openrtb_ext.ExtHttpCall {
 	return ext
 }
 
+// submitRequest makes the request on the shared bidder request pool. The request is shed if the pool's
+// queue is full, or if the auction has timed out by the time a worker picks it up.
+func (bidder *bidderAdapter) submitRequest(ctx context.Context, data *adapters.RequestData, responseChannel chan<- *httpCallInfo, bidRequestOptions bidRequestOptions) {
+	submitted := bidder.requestPool.trySubmit(func() {
+		defer func() {
+			if r := recover(); r != nil {
+				responseChannel <- &httpCallInfo{
+					request: data,
+					err:     fmt.Errorf("bidder request panicked: %v", r),
+				}
+				panic(r)
+			}
+		}()
+		if ctx.Err() != nil {
+			responseChannel <- &httpCallInfo{
+				request: data,
+				err:     &errortypes.Timeout{Message: "bidder request timed out waiting for a free worker"},
+			}
+			return
+		}
+		responseChannel <- bidder.doRequest(ctx, data, bidRequestOptions.bidderRequestStartTime, bidRequestOptions.tmaxAdjustments)
+	})
+	if !submitted {
+		bidder.me.RecordBidderRequestShed(bidder.BidderName)
+		responseChannel <- &httpCallInfo{
+			request: data,
+			err:     &errortypes.LoadShed{Message: "bidder request dropped because the bidder request queue is full"},
+		}
+	}
+}
+
 // doRequest makes a request, handles the response, and returns the data needed by the
 // Bidder interface.
 func (bidder *bidderAdapter) doRequest(ctx context.Context, req *adapters.RequestData, bidderRequestStartTime time.Time, tmaxAdjustments *TmaxAdjustmentsPreprocessed) *httpCallInfo {
-	return bidder.doRequestImpl(ctx, req, glog.Warningf, bidderRequestStartTime, tmaxAdjustments)
+	return bidder.doRequestImpl(ctx, req, logger.Ctx(ctx).WithClass(logger.ClassBidderErrors).Warningf, bidderRequestStartTime, tmaxAdjustments)
 }
 
-func (bidder *bidderAdapter) doRequestImpl(ctx context.Context, req *adapters.RequestData, logger util.LogMsg, bidderRequestStartTime time.Time, tmaxAdjustments *TmaxAdjustmentsPreprocessed) *httpCallInfo {
+func (bidder *bidderAdapter) doRequestImpl(ctx context.Context, req *adapters.RequestData, logFn util.LogMsg, bidderRequestStartTime time.Time, tmaxAdjustments *TmaxAdjustmentsPreprocessed) *httpCallInfo {
 	requestBody, err := getRequestBody(req, bidder.config.EndpointCompression)
 	if err != nil {
 		return &httpCallInfo{
@@ -539,11 +626,6 @@ func (bidder *bidderAdapter) doRequestImpl(ctx context.Context, req *adapters.Re
 	}
 	httpReq.Header = req.Headers
 
-	// If adapter connection metrics are not disabled, add the client trace
-	// to get complete connection info into our metrics
-	if !bidder.config.DisableConnMetrics {
-		ctx = bidder.addClientTrace(ctx)
-	}
 	bidder.me.RecordOverheadTime(metrics.PreBidder, time.Since(bidderRequestStartTime))
 
 	if tmaxAdjustments != nil && tmaxAdjustments.IsEnforced {
@@ -557,22 +639,16 @@ func (bidder *bidderAdapter) doRequestImpl(ctx context.Context, req *adapters.Re
 	}
 
 	httpCallStart := time.Now()
-	httpResp, err := ctxhttp.Do(ctx, bidder.Client, httpReq)
+	httpResp, err := bidder.sendRequest(ctx, httpReq)
 	if err != nil {
 		if err == context.DeadlineExceeded {
 			err = &errortypes.Timeout{Message: err.Error()}
-			var corebidder adapters.Bidder = bidder.Bidder
-			// The bidder adapter normally stores an info-aware bidder (a bidder wrapper)
-			// rather than the actual bidder. So we need to unpack that first.
-			if b, ok := corebidder.(*adapters.InfoAwareBidder); ok {
-				corebidder = b.Bidder
-			}
-			if tb, ok := corebidder.(adapters.TimeoutBidder); ok {
+			if notification := bidder.timeoutNotification(); notification != nil && bidder.timeoutNotifier.allow(bidder.BidderName) {
 				// Toss the timeout notification call into a go routine, as we are out of time'
 				// and cannot delay processing. We don't do anything result, as there is not much
 				// we can do about a timeout notification failure. We do not want to get stuck in
 				// a loop of trying to report timeouts to the timeout notifications.
-				go bidder.doTimeoutNotification(tb, req, logger)
+				go bidder.doTimeoutNotification(notification, req, logFn)
 			}
 
 		}
@@ -582,14 +658,26 @@ func (bidder *bidderAdapter) doRequestImpl(ctx context.Context, req *adapters.Re
 		}
 	}
 
-	respBody, err := io.ReadAll(httpResp.Body)
+	defer httpResp.Body.Close()
+	// oversized responses are rejected without reading the rest of the body. Closing the body unread
+	// discards the connection, which is cheaper than draining a response nobody will use.
+	maxResponseSize := bidder.config.MaxResponseSize
+	if maxResponseSize > 0 && httpResp.ContentLength > maxResponseSize {
+		return &httpCallInfo{
+			request: req,
+			err:     newResponseTooLargeError(maxResponseSize),
+		}
+	}
+	respBody, err := bufferpool.ReadAllLimit(httpResp.Body, maxResponseSize)
+	if err == bufferpool.ErrTooLarge {
+		err = newResponseTooLargeError(maxResponseSize)
+	}
 	if err != nil {
 		return &httpCallInfo{
 			request: req,
 			err:     err,
 		}
 	}
-	defer httpResp.Body.Close()
 
 	if httpResp.StatusCode < 200 || httpResp.StatusCode >= 400 {
 		err = &errortypes.BadServerResponse{
@@ -609,8 +697,50 @@ func (bidder *bidderAdapter) doRequestImpl(ctx context.Context, req *adapters.Re
 	}
 }
 
-func (bidder *bidderAdapter) doTimeoutNotification(timeoutBidder adapters.TimeoutBidder, req *adapters.RequestData, logger util.LogMsg) {
-	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+// sendRequest sends the request to the bidder, a second time as well if the bidder's requests are hedged.
+func (bidder *bidderAdapter) sendRequest(ctx context.Context, httpReq *http.Request) (*http.Response, error) {
+	if bidder.hedger == nil {
+		return ctxhttp.Do(bidder.withClientTrace(ctx), bidder.Client, httpReq)
+	}
+	httpResp, hedged, won, err := bidder.hedger.do(ctx, bidder.Client, httpReq, bidder.withClientTrace)
+	if hedged {
+		bidder.me.RecordAdapterHedgedRequest(bidder.BidderName, won)
+	}
+	return httpResp, err
+}
+
+// withClientTrace adds the client trace to the context, to get complete connection info into our metrics,
+// unless adapter connection metrics are disabled.
+func (bidder *bidderAdapter) withClientTrace(ctx context.Context) context.Context {
+	if bidder.config.DisableConnMetrics {
+		return ctx
+	}
+	return bidder.addClientTrace(ctx)
+}
+
+func newResponseTooLargeError(maxResponseSize int64) error {
+	return &errortypes.BadServerResponse{
+		Message: fmt.Sprintf("Server response exceeded the maximum size of %d bytes", maxResponseSize),
+	}
+}
+
+// timeoutNotification returns what makes the bidder's timeout notifications: its adapter, if it makes its
+// own, or else the notification URL of its info. It's nil if the bidder has neither.
+func (bidder *bidderAdapter) timeoutNotification() timeoutNotificationMaker {
+	var corebidder adapters.Bidder = bidder.Bidder
+	// The bidder adapter normally stores an info-aware bidder (a bidder wrapper)
+	// rather than the actual bidder. So we need to unpack that first.
+	if b, ok := corebidder.(*adapters.InfoAwareBidder); ok {
+		corebidder = b.Bidder
+	}
+	if tb, ok := corebidder.(adapters.TimeoutBidder); ok {
+		return tb
+	}
+	return bidder.timeoutNotifier.urlNotification(bidder.BidderName)
+}
+
+func (bidder *bidderAdapter) doTimeoutNotification(timeoutBidder timeoutNotificationMaker, req *adapters.RequestData, logFn util.LogMsg) {
+	ctx, cancel := context.WithTimeout(context.Background(), bidder.timeoutNotifier.notificationTimeout())
 	defer cancel()
 	toReq, errL := timeoutBidder.MakeTimeoutNotification(req)
 	if toReq != nil && len(errL) == 0 {
@@ -628,13 +758,13 @@ func (bidder *bidderAdapter) doTimeoutNotification(timeoutBidder adapters.Timeou
 					msg = fmt.Sprintf("TimeoutNotification: error:(%s) body:%s", err.Error(), string(toReq.Body))
 				}
 				// If logging is turned on, and logging is not disallowed via FailOnly
-				util.LogRandomSample(msg, logger, bidder.config.Debug.TimeoutNotification.SamplingRate)
+				util.LogRandomSample(msg, logFn, bidder.config.Debug.TimeoutNotification.SamplingRate)
 			}
 		} else {
 			bidder.me.RecordTimeoutNotice(false)
 			if bidder.config.Debug.TimeoutNotification.Log {
 				msg := fmt.Sprintf("TimeoutNotification: Failed to make timeout request: method(%s), uri(%s), error(%s)", toReq.Method, toReq.Uri, err.Error())
-				util.LogRandomSample(msg, logger, bidder.config.Debug.TimeoutNotification.SamplingRate)
+				util.LogRandomSample(msg, logFn, bidder.config.Debug.TimeoutNotification.SamplingRate)
 			}
 		}
 	} else if bidder.config.Debug.TimeoutNotification.Log {
@@ -645,7 +775,7 @@ func (bidder *bidderAdapter) doTimeoutNotification(timeoutBidder adapters.Timeou
 		} else {
 			msg = fmt.Sprintf("TimeoutNotification: Failed to generate timeout request: error(%s), bidder request marshal failed(%s)", errL[0].Error(), err.Error())
 		}
-		util.LogRandomSample(msg, logger, bidder.config.Debug.TimeoutNotification.SamplingRate)
+		util.LogRandomSample(msg, logFn, bidder.config.Debug.TimeoutNotification.SamplingRate)
 	}
 
 }
@@ -654,6 +784,8 @@ type httpCallInfo struct {
 	request  *adapters.RequestData
 	response *adapters.ResponseData
 	err      error
+	// simulated is true if the response was given in a simulated auction, rather than by the bidder
+	simulated bool
 }
 
 // This function adds an httptrace.ClientTrace object to the context so, if connection with the bidder
@@ -688,10 +820,13 @@ func (bidder *bidderAdapter) addClientTrace(ctx context.Context) context.Context
 			tlsStart = time.Now()
 		},
 
-		TLSHandshakeDone: func(tls.ConnectionState, error) {
+		TLSHandshakeDone: func(state tls.ConnectionState, err error) {
 			tlsHandshakeTime := time.Now().Sub(tlsStart)
 
 			bidder.me.RecordTLSHandshakeTime(tlsHandshakeTime)
+			if err == nil {
+				bidder.me.RecordAdapterTLSHandshake(bidder.BidderName, state.DidResume)
+			}
 		},
 	}
 	return httptrace.WithClientTrace(ctx, trace)
@@ -0,0 +1,132 @@
+package exchange
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/util/jsonutil"
+)
+
+// withholdBidderFields returns a copy of the bidder's request without the fields the policy withholds, and
+// how many were removed. The request is returned as it is if none were. The copy shares nothing with the
+// request, since it's unmarshaled from the JSON the fields were removed from.
+func withholdBidderFields(request *openrtb2.BidRequest, policy config.AccountBidderFieldPolicy) (*openrtb2.BidRequest, int, error) {
+	data, err := jsonutil.Marshal(request)
+	if err != nil {
+		return nil, 0, err
+	}
+	// numbers are kept as they're written, so that they aren't changed by a round trip through float64
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var root interface{}
+	if err := decoder.Decode(&root); err != nil {
+		return nil, 0, err
+	}
+
+	removed := 0
+	allowed := make(map[string]map[string]bool, len(policy.Allow))
+	for _, path := range policy.Allow {
+		parent, name := splitFieldPath(path)
+		if allowed[parent] == nil {
+			allowed[parent] = make(map[string]bool)
+		}
+		allowed[parent][name] = true
+	}
+	for parent, names := range allowed {
+		for _, node := range findFieldNodes(root, strings.Split(parent, ".")) {
+			if object, ok := node.(map[string]interface{}); ok {
+				for name := range object {
+					if !names[name] {
+						delete(object, name)
+						removed++
+					}
+				}
+			}
+		}
+	}
+	for _, path := range policy.Deny {
+		parent, name := splitFieldPath(path)
+		var parentSegments []string
+		if parent != "" {
+			parentSegments = strings.Split(parent, ".")
+		}
+		for _, node := range findFieldNodes(root, parentSegments) {
+			removed += removeField(node, name)
+		}
+	}
+
+	if removed == 0 {
+		return request, 0, nil
+	}
+	if data, err = json.Marshal(root); err != nil {
+		return nil, 0, err
+	}
+	withheld := &openrtb2.BidRequest{}
+	if err := jsonutil.Unmarshal(data, withheld); err != nil {
+		return nil, 0, err
+	}
+	return withheld, removed, nil
+}
+
+func splitFieldPath(path string) (string, string) {
+	if i := strings.LastIndexByte(path, '.'); i != -1 {
+		return path[:i], path[i+1:]
+	}
+	return "", path
+}
+
+// findFieldNodes returns the values at the path, where * stands for every member of an object or element of
+// a list, and a number for an element of a list.
+func findFieldNodes(node interface{}, segments []string) []interface{} {
+	if len(segments) == 0 {
+		return []interface{}{node}
+	}
+	var children []interface{}
+	switch value := node.(type) {
+	case map[string]interface{}:
+		if segments[0] == "*" {
+			for _, child := range value {
+				children = append(children, child)
+			}
+		} else if child, ok := value[segments[0]]; ok {
+			children = append(children, child)
+		}
+	case []interface{}:
+		if segments[0] == "*" {
+			children = value
+		} else if i, err := strconv.Atoi(segments[0]); err == nil && i >= 0 && i < len(value) {
+			children = append(children, value[i])
+		}
+	}
+
+	var nodes []interface{}
+	for _, child := range children {
+		nodes = append(nodes, findFieldNodes(child, segments[1:])...)
+	}
+	return nodes
+}
+
+// removeField removes the member of an object with the name, or all of its members for *, and returns how
+// many were removed.
+func removeField(node interface{}, name string) int {
+	object, ok := node.(map[string]interface{})
+	if !ok {
+		return 0
+	}
+	if name == "*" {
+		removed := len(object)
+		for member := range object {
+			delete(object, member)
+		}
+		return removed
+	}
+	if _, ok := object[name]; !ok {
+		return 0
+	}
+	delete(object, name)
+	return 1
+}
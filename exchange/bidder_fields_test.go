@@ -0,0 +1,96 @@
+package exchange
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithholdBidderFields(t *testing.T) {
+	newRequest := func() *openrtb2.BidRequest {
+		return &openrtb2.BidRequest{
+			ID: "req-1",
+			Imp: []openrtb2.Imp{
+				{ID: "imp-1", BidFloor: 0.1, Ext: json.RawMessage(`{"data":{"pbadslot":"a"},"gpid":"g1"}`)},
+				{ID: "imp-2", BidFloor: 1e-7, Ext: json.RawMessage(`{"data":{"pbadslot":"b"}}`)},
+			},
+			Device: &openrtb2.Device{UA: "ua", IFA: "ifa", IP: "203.0.113.1", Model: "model"},
+			User:   &openrtb2.User{ID: "user-1", Ext: json.RawMessage(`{"consent":"c","eids":[{"source":"s"}]}`)},
+		}
+	}
+
+	testCases := []struct {
+		description     string
+		policy          config.AccountBidderFieldPolicy
+		expectedFields  int
+		expectedRequest func() *openrtb2.BidRequest
+	}{
+		{
+			description:     "nothing_withheld",
+			policy:          config.AccountBidderFieldPolicy{Deny: []string{"site.page", "app"}},
+			expectedFields:  0,
+			expectedRequest: newRequest,
+		},
+		{
+			description:    "deny",
+			policy:         config.AccountBidderFieldPolicy{Deny: []string{"device.ifa", "user.ext.eids", "imp.*.ext.data"}},
+			expectedFields: 4,
+			expectedRequest: func() *openrtb2.BidRequest {
+				request := newRequest()
+				request.Device.IFA = ""
+				request.User.Ext = json.RawMessage(`{"consent":"c"}`)
+				request.Imp[0].Ext = json.RawMessage(`{"gpid":"g1"}`)
+				request.Imp[1].Ext = json.RawMessage(`{}`)
+				return request
+			},
+		},
+		{
+			description:    "deny_list_index_and_wildcard",
+			policy:         config.AccountBidderFieldPolicy{Deny: []string{"imp.1.ext", "user.*"}},
+			expectedFields: 3,
+			expectedRequest: func() *openrtb2.BidRequest {
+				request := newRequest()
+				request.Imp[1].Ext = nil
+				request.User = &openrtb2.User{}
+				return request
+			},
+		},
+		{
+			description:    "allow",
+			policy:         config.AccountBidderFieldPolicy{Allow: []string{"device.ua", "device.ip"}},
+			expectedFields: 2,
+			expectedRequest: func() *openrtb2.BidRequest {
+				request := newRequest()
+				request.Device = &openrtb2.Device{UA: "ua", IP: "203.0.113.1"}
+				return request
+			},
+		},
+		{
+			description:    "allow_and_deny",
+			policy:         config.AccountBidderFieldPolicy{Allow: []string{"device.ua", "device.ip"}, Deny: []string{"device.ip"}},
+			expectedFields: 3,
+			expectedRequest: func() *openrtb2.BidRequest {
+				request := newRequest()
+				request.Device = &openrtb2.Device{UA: "ua"}
+				return request
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			request := newRequest()
+
+			withheld, fields, err := withholdBidderFields(request, test.policy)
+
+			require.NoError(t, err)
+			assert.Equal(t, test.expectedFields, fields)
+			assert.Equal(t, test.expectedRequest(), withheld)
+			assert.Equal(t, newRequest(), request, "the request shouldn't be changed")
+		})
+	}
+}
@@ -0,0 +1,164 @@
+package exchange
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/prebid/prebid-server/v2/config"
+	"golang.org/x/net/context/ctxhttp"
+)
+
+// hedgeSamples is the number of a bidder's recent response times the hedging delay is taken from.
+const hedgeSamples = 1000
+
+// hedgeDelayInterval is how many response times are observed between updates of the hedging delay. Requests
+// aren't hedged until the first update, so the delay is never taken from fewer response times than this.
+const hedgeDelayInterval = 20
+
+// requestHedger sends a bidder's requests a second time, to the same endpoint, if they haven't been answered
+// within a percentile of the bidder's recent response times, and takes whichever answer comes first.
+type requestHedger struct {
+	minDelay      time.Duration
+	responseTimes *latencyWindow
+}
+
+// newRequestHedger returns the hedger of a bidder's requests, or nil if they aren't hedged.
+func newRequestHedger(cfg *config.BidderHedging) *requestHedger {
+	if cfg == nil {
+		return nil
+	}
+	return &requestHedger{
+		minDelay:      time.Duration(cfg.MinDelayMs) * time.Millisecond,
+		responseTimes: newLatencyWindow(cfg.Percentile, hedgeSamples, hedgeDelayInterval),
+	}
+}
+
+// delay returns how long a request waits before it's hedged, which is the percentile of the recent response
+// times but no less than the minimum delay, or false if it shouldn't be hedged yet.
+func (h *requestHedger) delay() (time.Duration, bool) {
+	delay, ok := h.responseTimes.get()
+	if !ok {
+		return 0, false
+	}
+	if delay < h.minDelay {
+		delay = h.minDelay
+	}
+	if delay <= 0 {
+		delay = time.Nanosecond
+	}
+	return delay, true
+}
+
+// observe adds the time a bidder took to answer a request to its recent response times.
+func (h *requestHedger) observe(responseTime time.Duration) {
+	h.responseTimes.observe(responseTime)
+}
+
+// hedgeAttempt is the outcome of one of the times a request was sent.
+type hedgeAttempt struct {
+	hedge        bool
+	resp         *http.Response
+	err          error
+	responseTime time.Duration
+}
+
+// do sends the request, and sends it again if it hasn't been answered once the hedging delay is over. The
+// first response is returned, and the other request is cancelled. An error is only returned once both
+// requests have failed. withTrace adds the client trace to the context of each request, since a trace can't
+// be shared by concurrent requests.
+//
+// hedged is set if the request was sent a second time, and won if the response is to the second request.
+func (h *requestHedger) do(ctx context.Context, client *http.Client, req *http.Request, withTrace func(context.Context) context.Context) (resp *http.Response, hedged bool, won bool, err error) {
+	results := make(chan hedgeAttempt, 2)
+	cancels := make([]context.CancelFunc, 0, 2)
+	send := func(req *http.Request, hedge bool) {
+		attemptCtx, cancel := context.WithCancel(ctx)
+		cancels = append(cancels, cancel)
+		go func() {
+			start := time.Now()
+			resp, err := ctxhttp.Do(withTrace(attemptCtx), client, req)
+			results <- hedgeAttempt{hedge: hedge, resp: resp, err: err, responseTime: time.Since(start)}
+		}()
+	}
+	send(req, false)
+
+	var hedgeTimer <-chan time.Time
+	if delay, ok := h.delay(); ok {
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
+		hedgeTimer = timer.C
+	}
+
+	pending := 1
+	for {
+		select {
+		case <-hedgeTimer:
+			hedgeTimer = nil
+			if ctx.Err() != nil || req.GetBody == nil {
+				continue
+			}
+			hedgeReq := req.Clone(ctx)
+			if hedgeReq.Body, err = req.GetBody(); err != nil {
+				err = nil
+				continue
+			}
+			send(hedgeReq, true)
+			hedged = true
+			pending++
+		case attempt := <-results:
+			pending--
+			if attempt.err != nil {
+				if err == nil {
+					err = attempt.err
+				}
+				if pending == 0 {
+					for _, cancel := range cancels {
+						cancel()
+					}
+					return nil, hedged, false, err
+				}
+				continue
+			}
+
+			h.observe(attempt.responseTime)
+			// the other request is cancelled, but not this one's, which must last until its body is read
+			winner := 0
+			if attempt.hedge {
+				winner = 1
+			}
+			for i, cancel := range cancels {
+				if i != winner {
+					cancel()
+				}
+			}
+			if pending > 0 {
+				go closeHedgeAttempts(results, pending)
+			}
+			attempt.resp.Body = &cancelOnClose{ReadCloser: attempt.resp.Body, cancel: cancels[winner]}
+			return attempt.resp, hedged, attempt.hedge, nil
+		}
+	}
+}
+
+// closeHedgeAttempts closes the responses to the requests which lost the race.
+func closeHedgeAttempts(results <-chan hedgeAttempt, pending int) {
+	for ; pending > 0; pending-- {
+		if attempt := <-results; attempt.resp != nil {
+			attempt.resp.Body.Close()
+		}
+	}
+}
+
+// cancelOnClose cancels the context of a request once its response body is closed.
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (c *cancelOnClose) Close() error {
+	err := c.ReadCloser.Close()
+	c.cancel()
+	return err
+}
@@ -0,0 +1,230 @@
+package exchange
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/metrics"
+	"github.com/prebid/prebid-server/v2/openrtb_ext"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func noTrace(ctx context.Context) context.Context {
+	return ctx
+}
+
+// newTestRequestHedger returns a hedger which hedges requests after the delay, or doesn't hedge them if it's 0.
+func newTestRequestHedger(delay time.Duration) *requestHedger {
+	hedger := newRequestHedger(&config.BidderHedging{Percentile: 90})
+	hedger.responseTimes.value = delay
+	hedger.responseTimes.known = delay > 0
+	return hedger
+}
+
+func TestNewRequestHedgerDisabled(t *testing.T) {
+	assert.Nil(t, newRequestHedger(nil))
+}
+
+func TestRequestHedgerDelay(t *testing.T) {
+	testCases := []struct {
+		name          string
+		cfg           config.BidderHedging
+		responseTimes []time.Duration
+		expectedDelay time.Duration
+		expectedOK    bool
+	}{
+		{
+			name:          "too-few-response-times",
+			cfg:           config.BidderHedging{Percentile: 90},
+			responseTimes: responseTimes(hedgeDelayInterval-1, time.Millisecond),
+			expectedOK:    false,
+		},
+		{
+			name:          "percentile",
+			cfg:           config.BidderHedging{Percentile: 90},
+			responseTimes: responseTimes(hedgeDelayInterval, time.Millisecond),
+			expectedDelay: 17 * time.Millisecond,
+			expectedOK:    true,
+		},
+		{
+			name:          "min-delay",
+			cfg:           config.BidderHedging{Percentile: 90, MinDelayMs: 50},
+			responseTimes: responseTimes(hedgeDelayInterval, time.Millisecond),
+			expectedDelay: 50 * time.Millisecond,
+			expectedOK:    true,
+		},
+		{
+			name:          "oldest-response-times-replaced",
+			cfg:           config.BidderHedging{Percentile: 50},
+			responseTimes: append(responseTimes(hedgeSamples, time.Second), responseTimes(hedgeSamples, time.Millisecond)...),
+			expectedDelay: 499 * time.Millisecond,
+			expectedOK:    true,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			hedger := newRequestHedger(&test.cfg)
+			for _, responseTime := range test.responseTimes {
+				hedger.observe(responseTime)
+			}
+			delay, ok := hedger.delay()
+			assert.Equal(t, test.expectedOK, ok)
+			assert.Equal(t, test.expectedDelay, delay)
+		})
+	}
+}
+
+// responseTimes returns n response times, of 0 to n-1 units.
+func responseTimes(n int, unit time.Duration) []time.Duration {
+	times := make([]time.Duration, n)
+	for i := range times {
+		times[i] = time.Duration(i) * unit
+	}
+	return times
+}
+
+func TestRequestHedgerDo(t *testing.T) {
+	testCases := []struct {
+		name           string
+		hedgeDelay     time.Duration
+		firstDelay     time.Duration
+		secondDelay    time.Duration
+		expectedHedged bool
+		expectedWon    bool
+		expectedBody   string
+		expectedCalls  int32
+	}{
+		{
+			name:          "answered-before-delay",
+			hedgeDelay:    time.Second,
+			expectedBody:  "1",
+			expectedCalls: 1,
+		},
+		{
+			name:          "not-enough-response-times",
+			firstDelay:    20 * time.Millisecond,
+			expectedBody:  "1",
+			expectedCalls: 1,
+		},
+		{
+			name:           "hedge-wins",
+			hedgeDelay:     10 * time.Millisecond,
+			firstDelay:     time.Second,
+			expectedHedged: true,
+			expectedWon:    true,
+			expectedBody:   "2",
+			expectedCalls:  2,
+		},
+		{
+			name:           "first-wins",
+			hedgeDelay:     10 * time.Millisecond,
+			firstDelay:     30 * time.Millisecond,
+			secondDelay:    time.Second,
+			expectedHedged: true,
+			expectedWon:    false,
+			expectedBody:   "1",
+			expectedCalls:  2,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			var calls int32
+			server := httptest.NewServer(delayedHandler(&calls, test.firstDelay, test.secondDelay))
+			defer server.Close()
+
+			hedger := newTestRequestHedger(test.hedgeDelay)
+			req, err := http.NewRequest(http.MethodPost, server.URL, strings.NewReader("request"))
+			require.NoError(t, err)
+
+			resp, hedged, won, err := hedger.do(context.Background(), server.Client(), req, noTrace)
+			require.NoError(t, err)
+			body, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+			resp.Body.Close()
+
+			assert.Equal(t, test.expectedBody, string(body))
+			assert.Equal(t, test.expectedHedged, hedged)
+			assert.Equal(t, test.expectedWon, won)
+			assert.Equal(t, test.expectedCalls, atomic.LoadInt32(&calls))
+			assert.Equal(t, 1, hedger.responseTimes.observed)
+		})
+	}
+}
+
+// delayedHandler answers the first request it gets with "1" after the first delay, and the second with "2"
+// after the second delay, unless they're cancelled first. Both requests must have the same body.
+func delayedHandler(calls *int32, firstDelay, secondDelay time.Duration) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if string(body) != "request" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		call := atomic.AddInt32(calls, 1)
+		delay := firstDelay
+		if call > 1 {
+			delay = secondDelay
+		}
+		select {
+		case <-time.After(delay):
+			w.Write([]byte(strconv.Itoa(int(call))))
+		case <-r.Context().Done():
+		}
+	})
+}
+
+func TestRequestHedgerDoErrors(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(delayedHandler(&calls, time.Second, time.Second))
+	defer server.Close()
+
+	hedger := newTestRequestHedger(10 * time.Millisecond)
+	req, err := http.NewRequest(http.MethodPost, server.URL, strings.NewReader("request"))
+	require.NoError(t, err)
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	resp, hedged, won, err := hedger.do(ctx, server.Client(), req, noTrace)
+	assert.Nil(t, resp)
+	assert.Equal(t, context.DeadlineExceeded, err)
+	assert.True(t, hedged)
+	assert.False(t, won)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+	assert.Equal(t, 0, hedger.responseTimes.observed)
+}
+
+func TestSendRequestHedged(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(delayedHandler(&calls, time.Second, 0))
+	defer server.Close()
+
+	me := &metrics.MetricsEngineMock{}
+	me.On("RecordAdapterHedgedRequest", openrtb_ext.BidderAppnexus, true).Once()
+	hedger := newTestRequestHedger(10 * time.Millisecond)
+	bidder := &bidderAdapter{
+		BidderName: openrtb_ext.BidderAppnexus,
+		Client:     server.Client(),
+		me:         me,
+		hedger:     hedger,
+		config:     bidderAdapterConfig{DisableConnMetrics: true},
+	}
+	req, err := http.NewRequest(http.MethodPost, server.URL, strings.NewReader("request"))
+	require.NoError(t, err)
+
+	resp, err := bidder.sendRequest(context.Background(), req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	me.AssertExpectations(t)
+}
@@ -0,0 +1,38 @@
+package exchange
+
+import (
+	"fmt"
+
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/errortypes"
+)
+
+// leaveOutBiddersInMaintenance leaves out the requests to bidders whose kill switch is on, or which have a
+// maintenance window of the host or the account in effect. Simulated requests aren't sent to the bidder, so
+// they're kept.
+func (e *exchange) leaveOutBiddersInMaintenance(bidderRequests []BidderRequest, accountWindows []config.BidderMaintenanceWindow) ([]BidderRequest, []error) {
+	if e.maintenance == nil && len(accountWindows) == 0 {
+		return bidderRequests, nil
+	}
+
+	var errs []error
+	allowed := bidderRequests[:0]
+	for _, bidderRequest := range bidderRequests {
+		if bidderRequest.SimulatedResponse == nil {
+			reason, inMaintenance := e.maintenance.Check(bidderRequest.BidderName.String(), accountWindows)
+			if !inMaintenance && bidderRequest.BidderCoreName != bidderRequest.BidderName {
+				reason, inMaintenance = e.maintenance.Check(bidderRequest.BidderCoreName.String(), accountWindows)
+			}
+			if inMaintenance {
+				e.me.RecordAdapterMaintenance(bidderRequest.BidderCoreName, reason)
+				errs = append(errs, &errortypes.Warning{
+					Message:     fmt.Sprintf("%s was left out of the auction for maintenance", bidderRequest.BidderName),
+					WarningCode: errortypes.BidderMaintenanceWarningCode,
+				})
+				continue
+			}
+		}
+		allowed = append(allowed, bidderRequest)
+	}
+	return allowed, errs
+}
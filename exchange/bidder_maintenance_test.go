@@ -0,0 +1,48 @@
+package exchange
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/prebid/prebid-server/v2/biddermaintenance"
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/errortypes"
+	"github.com/prebid/prebid-server/v2/metrics"
+	"github.com/prebid/prebid-server/v2/openrtb_ext"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLeaveOutBiddersInMaintenance(t *testing.T) {
+	maintenance := biddermaintenance.New(config.BidderMaintenance{KillSwitches: config.BidderKillSwitches{Enabled: true, Tokens: []string{"token"}}})
+	maintenance.TurnOn("rubicon", "incident")
+	me := &metrics.MetricsEngineMock{}
+	me.On("RecordAdapterMaintenance", openrtb_ext.BidderName("rubicon"), metrics.BidderMaintenanceKillSwitch).Once()
+	me.On("RecordAdapterMaintenance", openrtb_ext.BidderName("appnexus"), metrics.BidderMaintenanceWindow).Once()
+	e := &exchange{maintenance: maintenance, me: me}
+
+	accountWindows := []config.BidderMaintenanceWindow{{Bidder: "appnexus", Start: "2000-01-01T00:00:00Z", End: "9999-01-01T00:00:00Z"}}
+	bidderRequests := []BidderRequest{
+		{BidderName: "appnexusAlias", BidderCoreName: "appnexus"},
+		{BidderName: "rubicon", BidderCoreName: "rubicon"},
+		{BidderName: "openx", BidderCoreName: "openx"},
+		{BidderName: "simulated", BidderCoreName: "rubicon", SimulatedResponse: json.RawMessage(`{}`)},
+	}
+
+	allowed, errs := e.leaveOutBiddersInMaintenance(bidderRequests, accountWindows)
+	assert.Equal(t, []BidderRequest{
+		{BidderName: "openx", BidderCoreName: "openx"},
+		{BidderName: "simulated", BidderCoreName: "rubicon", SimulatedResponse: json.RawMessage(`{}`)},
+	}, allowed)
+	assert.Equal(t, []error{
+		&errortypes.Warning{Message: "appnexusAlias was left out of the auction for maintenance", WarningCode: errortypes.BidderMaintenanceWarningCode},
+		&errortypes.Warning{Message: "rubicon was left out of the auction for maintenance", WarningCode: errortypes.BidderMaintenanceWarningCode},
+	}, errs)
+	me.AssertExpectations(t)
+}
+
+func TestLeaveOutBiddersInMaintenanceNone(t *testing.T) {
+	bidderRequests := []BidderRequest{{BidderName: "appnexus", BidderCoreName: "appnexus"}}
+	allowed, errs := (&exchange{}).leaveOutBiddersInMaintenance(bidderRequests, nil)
+	assert.Equal(t, bidderRequests, allowed)
+	assert.Empty(t, errs)
+}
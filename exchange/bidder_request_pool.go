@@ -0,0 +1,38 @@
+package exchange
+
+import (
+	"github.com/alitto/pond"
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/logger"
+	"github.com/prebid/prebid-server/v2/metrics"
+)
+
+// bidderRequestPool bounds the number of concurrent HTTP requests made to bidders across all auctions.
+type bidderRequestPool struct {
+	pool *pond.WorkerPool
+	me   metrics.MetricsEngine
+}
+
+// newBidderRequestPool returns the shared bidder request pool, or nil if bidder requests should each
+// run on their own goroutine.
+func newBidderRequestPool(cfg config.BidderRequestPool, me metrics.MetricsEngine) *bidderRequestPool {
+	if !cfg.Enabled {
+		return nil
+	}
+	return &bidderRequestPool{
+		pool: pond.New(cfg.Workers, cfg.QueueSize, pond.PanicHandler(bidderRequestPanicHandler)),
+		me:   me,
+	}
+}
+
+func bidderRequestPanicHandler(p interface{}) {
+	logger.Errorf("bidder request worker panicked: %v", p)
+}
+
+// trySubmit queues the task for the next free worker. It returns false without queueing the task if
+// the queue is full, in which case the request should be shed.
+func (p *bidderRequestPool) trySubmit(task func()) bool {
+	submitted := p.pool.TrySubmit(task)
+	p.me.RecordBidderRequestPool(p.pool.RunningWorkers(), int(p.pool.WaitingTasks()))
+	return submitted
+}
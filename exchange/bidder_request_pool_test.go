@@ -0,0 +1,108 @@
+package exchange
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prebid/prebid-server/v2/adapters"
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/errortypes"
+	"github.com/prebid/prebid-server/v2/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestNewBidderRequestPoolDisabled(t *testing.T) {
+	assert.Nil(t, newBidderRequestPool(config.BidderRequestPool{Enabled: false}, &metrics.MetricsEngineMock{}))
+}
+
+func TestSubmitRequest(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	expiredCtx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tests := []struct {
+		description   string
+		ctx           context.Context
+		busyWorker    bool
+		expectedErr   error
+		expectedShed  bool
+		expectedReply int
+	}{
+		{
+			description:   "request made by a worker",
+			ctx:           context.Background(),
+			expectedReply: http.StatusNoContent,
+		},
+		{
+			description:  "request shed when the queue is full",
+			ctx:          context.Background(),
+			busyWorker:   true,
+			expectedErr:  &errortypes.LoadShed{Message: "bidder request dropped because the bidder request queue is full"},
+			expectedShed: true,
+		},
+		{
+			description: "request dropped when the auction timed out before a worker was free",
+			ctx:         expiredCtx,
+			expectedErr: &errortypes.Timeout{Message: "bidder request timed out waiting for a free worker"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			me := &metrics.MetricsEngineMock{}
+			me.On("RecordBidderRequestPool", mock.Anything, mock.Anything).Return()
+			me.On("RecordBidderRequestShed", mock.Anything).Return()
+			me.On("RecordOverheadTime", mock.Anything, mock.Anything).Return()
+			me.On("RecordBidderServerResponseTime", mock.Anything).Return()
+
+			requestPool := newBidderRequestPool(config.BidderRequestPool{Enabled: true, Workers: 1, QueueSize: 0}, me)
+			defer requestPool.pool.StopAndWait()
+			bidder := &bidderAdapter{
+				BidderName:  "test",
+				Client:      server.Client(),
+				me:          me,
+				config:      bidderAdapterConfig{DisableConnMetrics: true},
+				requestPool: requestPool,
+			}
+
+			release := make(chan struct{})
+			if test.busyWorker {
+				started := make(chan struct{})
+				assert.True(t, requestPool.trySubmit(func() {
+					close(started)
+					<-release
+				}))
+				<-started
+			}
+
+			responseChannel := make(chan *httpCallInfo, 1)
+			bidder.submitRequest(test.ctx, &adapters.RequestData{Method: http.MethodGet, Uri: server.URL, Headers: http.Header{}}, responseChannel, bidRequestOptions{bidderRequestStartTime: time.Now()})
+
+			var callInfo *httpCallInfo
+			select {
+			case callInfo = <-responseChannel:
+			case <-time.After(5 * time.Second):
+				t.Fatal("no response for the bidder request")
+			}
+			close(release)
+
+			assert.Equal(t, test.expectedErr, callInfo.err)
+			if test.expectedReply != 0 {
+				assert.Equal(t, test.expectedReply, callInfo.response.StatusCode)
+			}
+			if test.expectedShed {
+				me.AssertCalled(t, "RecordBidderRequestShed", mock.Anything)
+			} else {
+				me.AssertNotCalled(t, "RecordBidderRequestShed", mock.Anything)
+			}
+		})
+	}
+}
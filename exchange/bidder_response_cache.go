@@ -0,0 +1,291 @@
+package exchange
+
+import (
+	"bytes"
+	"container/list"
+	"encoding/binary"
+	"encoding/json"
+	"hash/maphash"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/buger/jsonparser"
+	"github.com/prebid/openrtb/v20/adcom1"
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v2/adapters"
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/metrics"
+)
+
+// bidderResponseCache reuses the responses of bidders to anonymous requests for a few seconds. Bidders
+// answer requests without user IDs or consent with the same bids for the same context anyway, so reusing
+// their responses cuts the requests sent to them without changing the auctions.
+//
+// Entries are keyed by a fingerprint of the parts of the request which bear on the bids, so requests which
+// differ only in their IDs, tmax and transaction IDs share them. Only successful responses are cached, and
+// each is still turned into bids by the adapter, against the request it's reused for. A nil
+// *bidderResponseCache is valid and caches nothing.
+type bidderResponseCache struct {
+	seed       maphash.Seed
+	ttl        time.Duration
+	maxEntries int
+	bidders    map[string]bool
+	me         metrics.MetricsEngine
+	now        func() time.Time
+
+	lock    sync.Mutex
+	entries map[uint64]*list.Element
+	lru     *list.List
+}
+
+type bidderResponseCacheEntry struct {
+	hash     uint64
+	key      []byte
+	response *adapters.ResponseData
+	expires  time.Time
+}
+
+// requestFingerprint holds the parts of a bidder request which bear on the bids.
+type requestFingerprint struct {
+	Imp    []impFingerprint      `json:"imp"`
+	Site   *openrtb2.Site        `json:"site,omitempty"`
+	App    *openrtb2.App         `json:"app,omitempty"`
+	DOOH   *openrtb2.DOOH        `json:"dooh,omitempty"`
+	Device deviceFingerprint     `json:"device"`
+	User   *openrtb2.User        `json:"user,omitempty"`
+	Regs   *openrtb2.Regs        `json:"regs,omitempty"`
+	SChain *openrtb2.SupplyChain `json:"schain,omitempty"`
+	Test   int8                  `json:"test,omitempty"`
+	Cur    []string              `json:"cur,omitempty"`
+	BCat   []string              `json:"bcat,omitempty"`
+	BAdv   []string              `json:"badv,omitempty"`
+	BApp   []string              `json:"bapp,omitempty"`
+	Ext    json.RawMessage       `json:"ext,omitempty"`
+}
+
+type impFingerprint struct {
+	ID          string           `json:"id"`
+	TagID       string           `json:"tagid,omitempty"`
+	Banner      *openrtb2.Banner `json:"banner,omitempty"`
+	Video       *openrtb2.Video  `json:"video,omitempty"`
+	Audio       *openrtb2.Audio  `json:"audio,omitempty"`
+	Native      *openrtb2.Native `json:"native,omitempty"`
+	PMP         *openrtb2.PMP    `json:"pmp,omitempty"`
+	Instl       int8             `json:"instl,omitempty"`
+	Secure      *int8            `json:"secure,omitempty"`
+	BidFloor    float64          `json:"bidfloor,omitempty"`
+	BidFloorCur string           `json:"bidfloorcur,omitempty"`
+	Ext         json.RawMessage  `json:"ext,omitempty"`
+}
+
+type deviceFingerprint struct {
+	DeviceType adcom1.DeviceType `json:"devicetype,omitempty"`
+	OS         string            `json:"os,omitempty"`
+	Language   string            `json:"language,omitempty"`
+	Country    string            `json:"country,omitempty"`
+}
+
+// newBidderResponseCache returns a cache configured by cfg, or nil if the cache is disabled.
+func newBidderResponseCache(cfg config.BidderResponseCache, me metrics.MetricsEngine) *bidderResponseCache {
+	if !cfg.Enabled {
+		return nil
+	}
+	bidders := make(map[string]bool, len(cfg.Bidders))
+	for _, bidder := range cfg.Bidders {
+		bidders[strings.ToLower(bidder)] = true
+	}
+	return &bidderResponseCache{
+		seed:       maphash.MakeSeed(),
+		ttl:        time.Duration(cfg.TTLMS) * time.Millisecond,
+		maxEntries: cfg.MaxEntries,
+		bidders:    bidders,
+		me:         me,
+		now:        time.Now,
+		entries:    make(map[uint64]*list.Element, cfg.MaxEntries),
+		lru:        list.New(),
+	}
+}
+
+// fingerprint returns the fingerprint of the bidder request, or nil if its responses mustn't be cached:
+// if the bidder isn't opted in, or the request identifies the user or carries their consent.
+func (c *bidderResponseCache) fingerprint(bidderRequest BidderRequest) []byte {
+	if c == nil || !c.bidders[strings.ToLower(bidderRequest.BidderName.String())] {
+		return nil
+	}
+	req := bidderRequest.BidRequest
+	if req == nil || !isAnonymous(req) {
+		return nil
+	}
+
+	fingerprint := requestFingerprint{
+		Imp:  make([]impFingerprint, 0, len(req.Imp)),
+		Site: req.Site,
+		App:  req.App,
+		DOOH: req.DOOH,
+		User: req.User,
+		Regs: req.Regs,
+		Test: req.Test,
+		Cur:  req.Cur,
+		BCat: req.BCat,
+		BAdv: req.BAdv,
+		BApp: req.BApp,
+		Ext:  req.Ext,
+	}
+	for _, imp := range req.Imp {
+		ext := imp.Ext
+		if len(ext) > 0 {
+			// the transaction ID is different for every auction
+			ext = jsonparser.Delete(bytes.Clone(ext), "tid")
+		}
+		fingerprint.Imp = append(fingerprint.Imp, impFingerprint{
+			ID:          imp.ID,
+			TagID:       imp.TagID,
+			Banner:      imp.Banner,
+			Video:       imp.Video,
+			Audio:       imp.Audio,
+			Native:      imp.Native,
+			PMP:         imp.PMP,
+			Instl:       imp.Instl,
+			Secure:      imp.Secure,
+			BidFloor:    imp.BidFloor,
+			BidFloorCur: imp.BidFloorCur,
+			Ext:         ext,
+		})
+	}
+	if req.Device != nil {
+		fingerprint.Device = deviceFingerprint{DeviceType: req.Device.DeviceType, OS: req.Device.OS, Language: req.Device.Language}
+		if req.Device.Geo != nil {
+			fingerprint.Device.Country = req.Device.Geo.Country
+		}
+	}
+	if req.Source != nil {
+		fingerprint.SChain = req.Source.SChain
+	}
+
+	data, err := json.Marshal(fingerprint)
+	if err != nil {
+		return nil
+	}
+	return data
+}
+
+// isAnonymous reports whether the request has no user or device IDs and no consent string.
+func isAnonymous(req *openrtb2.BidRequest) bool {
+	if req.User != nil && (req.User.ID != "" || req.User.BuyerUID != "" || len(req.User.EIDs) > 0 || req.User.Consent != "") {
+		return false
+	}
+	if req.Device != nil && req.Device.IFA != "" {
+		return false
+	}
+	if req.Regs != nil && req.Regs.GPP != "" {
+		return false
+	}
+	return true
+}
+
+// answer sends the cached response to each of the requests which has one to responseChannel. It returns the
+// requests which have to be made, and the keys to cache their responses under.
+func (c *bidderResponseCache) answer(bidderRequest BidderRequest, fingerprint []byte, reqData []*adapters.RequestData, responseChannel chan<- *httpCallInfo) ([]*adapters.RequestData, map[*adapters.RequestData][]byte) {
+	if c == nil || fingerprint == nil {
+		return reqData, nil
+	}
+	misses := make([]*adapters.RequestData, 0, len(reqData))
+	keys := make(map[*adapters.RequestData][]byte, len(reqData))
+	for i, data := range reqData {
+		key := responseCacheKey(bidderRequest, fingerprint, i, data)
+		if response, ok := c.get(key); ok {
+			c.me.RecordAdapterResponseCacheResult(bidderRequest.BidderName, metrics.CacheHit)
+			responseChannel <- &httpCallInfo{request: data, response: cloneResponse(response)}
+			continue
+		}
+		c.me.RecordAdapterResponseCacheResult(bidderRequest.BidderName, metrics.CacheMiss)
+		misses = append(misses, data)
+		keys[data] = key
+	}
+	return misses, keys
+}
+
+// store caches the response to one of the requests made, if it's to be cached and was successful.
+func (c *bidderResponseCache) store(keys map[*adapters.RequestData][]byte, httpInfo *httpCallInfo) {
+	key, ok := keys[httpInfo.request]
+	if !ok || httpInfo.err != nil || httpInfo.response == nil {
+		return
+	}
+	if httpInfo.response.StatusCode != http.StatusOK && httpInfo.response.StatusCode != http.StatusNoContent {
+		return
+	}
+	c.put(key, cloneResponse(httpInfo.response))
+}
+
+// cloneResponse copies a response going into or out of the cache, so that an adapter which changes the
+// body as it makes bids doesn't change the bids made from it in other auctions.
+func cloneResponse(response *adapters.ResponseData) *adapters.ResponseData {
+	return &adapters.ResponseData{
+		StatusCode: response.StatusCode,
+		Body:       bytes.Clone(response.Body),
+		Headers:    response.Headers.Clone(),
+	}
+}
+
+// responseCacheKey keys the response to the i-th request the adapter made for the bidder request. The
+// endpoint is part of the key, since the canary configuration of a bidder may call another one.
+func responseCacheKey(bidderRequest BidderRequest, fingerprint []byte, i int, data *adapters.RequestData) []byte {
+	key := make([]byte, 0, len(fingerprint)+len(data.Uri)+64)
+	key = append(key, bidderRequest.BidderName.String()...)
+	key = append(key, 0)
+	key = append(key, data.Method...)
+	key = append(key, 0)
+	key = append(key, data.Uri...)
+	key = binary.LittleEndian.AppendUint64(append(key, 0), uint64(i))
+	return append(key, fingerprint...)
+}
+
+func (c *bidderResponseCache) get(key []byte) (*adapters.ResponseData, bool) {
+	hash := maphash.Bytes(c.seed, key)
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	element, ok := c.entries[hash]
+	if !ok {
+		return nil, false
+	}
+	entry := element.Value.(*bidderResponseCacheEntry)
+	if !bytes.Equal(entry.key, key) {
+		return nil, false
+	}
+	if !c.now().Before(entry.expires) {
+		c.lru.Remove(element)
+		delete(c.entries, hash)
+		return nil, false
+	}
+	c.lru.MoveToFront(element)
+	return entry.response, true
+}
+
+func (c *bidderResponseCache) put(key []byte, response *adapters.ResponseData) {
+	hash := maphash.Bytes(c.seed, key)
+	entry := &bidderResponseCacheEntry{
+		hash:     hash,
+		key:      key,
+		response: response,
+		expires:  c.now().Add(c.ttl),
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if element, ok := c.entries[hash]; ok {
+		element.Value = entry
+		c.lru.MoveToFront(element)
+		return
+	}
+	c.entries[hash] = c.lru.PushFront(entry)
+	for c.lru.Len() > c.maxEntries {
+		oldest := c.lru.Back()
+		c.lru.Remove(oldest)
+		delete(c.entries, oldest.Value.(*bidderResponseCacheEntry).hash)
+	}
+}
@@ -0,0 +1,242 @@
+package exchange
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v2/adapters"
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/currency"
+	"github.com/prebid/prebid-server/v2/experiment/adscert"
+	"github.com/prebid/prebid-server/v2/hooks/hookexecution"
+	"github.com/prebid/prebid-server/v2/metrics"
+	metricsConfig "github.com/prebid/prebid-server/v2/metrics/config"
+	"github.com/prebid/prebid-server/v2/openrtb_ext"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestBidderResponseCache(me metrics.MetricsEngine, maxEntries int) *bidderResponseCache {
+	return newBidderResponseCache(config.BidderResponseCache{Enabled: true, TTLMS: 1000, MaxEntries: maxEntries, Bidders: []string{"AppNexus"}}, me)
+}
+
+func anonymousBidderRequest(id string) BidderRequest {
+	return BidderRequest{
+		BidderName: openrtb_ext.BidderAppnexus,
+		BidRequest: &openrtb2.BidRequest{
+			ID:   id,
+			TMax: 500,
+			Imp: []openrtb2.Imp{{
+				ID:       "imp-1",
+				Banner:   &openrtb2.Banner{Format: []openrtb2.Format{{W: 300, H: 250}}},
+				BidFloor: 1.5,
+				Ext:      []byte(`{"bidder":{"placementId":1},"tid":"` + id + `"}`),
+			}},
+			Site:   &openrtb2.Site{Page: "https://example.com/page"},
+			Device: &openrtb2.Device{UA: "ua-" + id, IP: "1.2.3.4", OS: "iOS", Geo: &openrtb2.Geo{Country: "USA", City: id}},
+		},
+	}
+}
+
+func TestBidderResponseCacheFingerprint(t *testing.T) {
+	cache := newTestBidderResponseCache(&metricsConfig.NilMetricsEngine{}, 10)
+	base := cache.fingerprint(anonymousBidderRequest("a"))
+	assert.NotNil(t, base)
+
+	tests := []struct {
+		description string
+		cache       *bidderResponseCache
+		modify      func(*BidderRequest)
+		expectSame  bool
+		expectNil   bool
+	}{
+		{
+			description: "different-ids-tmax-and-device-details",
+			cache:       cache,
+			modify:      func(r *BidderRequest) { r.BidRequest.TMax = 900 },
+			expectSame:  true,
+		},
+		{
+			description: "different-floor",
+			cache:       cache,
+			modify:      func(r *BidderRequest) { r.BidRequest.Imp[0].BidFloor = 2 },
+		},
+		{
+			description: "different-country",
+			cache:       cache,
+			modify:      func(r *BidderRequest) { r.BidRequest.Device.Geo.Country = "CAN" },
+		},
+		{
+			description: "user-id",
+			cache:       cache,
+			modify:      func(r *BidderRequest) { r.BidRequest.User = &openrtb2.User{ID: "user"} },
+			expectNil:   true,
+		},
+		{
+			description: "consent",
+			cache:       cache,
+			modify:      func(r *BidderRequest) { r.BidRequest.User = &openrtb2.User{Consent: "consent"} },
+			expectNil:   true,
+		},
+		{
+			description: "device-ifa",
+			cache:       cache,
+			modify:      func(r *BidderRequest) { r.BidRequest.Device.IFA = "ifa" },
+			expectNil:   true,
+		},
+		{
+			description: "bidder-not-opted-in",
+			cache:       cache,
+			modify:      func(r *BidderRequest) { r.BidderName = openrtb_ext.BidderRubicon },
+			expectNil:   true,
+		},
+		{
+			description: "disabled",
+			cache:       nil,
+			modify:      func(r *BidderRequest) {},
+			expectNil:   true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			bidderRequest := anonymousBidderRequest("b")
+			test.modify(&bidderRequest)
+
+			fingerprint := test.cache.fingerprint(bidderRequest)
+
+			if test.expectNil {
+				assert.Nil(t, fingerprint)
+				return
+			}
+			if test.expectSame {
+				assert.Equal(t, string(base), string(fingerprint))
+			} else {
+				assert.NotEqual(t, string(base), string(fingerprint))
+			}
+		})
+	}
+}
+
+func TestBidderResponseCacheAnswerAndStore(t *testing.T) {
+	me := &metrics.MetricsEngineMock{}
+	me.On("RecordAdapterResponseCacheResult", openrtb_ext.BidderAppnexus, metrics.CacheMiss).Return().Twice()
+	me.On("RecordAdapterResponseCacheResult", openrtb_ext.BidderAppnexus, metrics.CacheHit).Return().Once()
+	cache := newTestBidderResponseCache(me, 10)
+	now := time.Now()
+	cache.now = func() time.Time { return now }
+
+	bidderRequest := anonymousBidderRequest("a")
+	fingerprint := cache.fingerprint(bidderRequest)
+	reqData := []*adapters.RequestData{{Method: http.MethodPost, Uri: "https://bidder.example.com"}}
+	responseChannel := make(chan *httpCallInfo, 1)
+
+	misses, keys := cache.answer(bidderRequest, fingerprint, reqData, responseChannel)
+	assert.Equal(t, reqData, misses)
+	cache.store(keys, &httpCallInfo{request: reqData[0], response: &adapters.ResponseData{StatusCode: http.StatusOK, Body: []byte(`{"id":"a"}`)}})
+
+	misses, _ = cache.answer(bidderRequest, fingerprint, reqData, responseChannel)
+	assert.Empty(t, misses)
+	httpInfo := <-responseChannel
+	assert.Equal(t, reqData[0], httpInfo.request)
+	assert.Equal(t, `{"id":"a"}`, string(httpInfo.response.Body))
+
+	now = now.Add(time.Second)
+	misses, _ = cache.answer(bidderRequest, fingerprint, reqData, responseChannel)
+	assert.Equal(t, reqData, misses, "expired responses must not be reused")
+
+	me.AssertExpectations(t)
+}
+
+func TestBidderResponseCacheStoresOnlySuccessfulResponses(t *testing.T) {
+	tests := []struct {
+		description string
+		httpInfo    *httpCallInfo
+		expectHit   bool
+	}{
+		{
+			description: "ok",
+			httpInfo:    &httpCallInfo{response: &adapters.ResponseData{StatusCode: http.StatusOK}},
+			expectHit:   true,
+		},
+		{
+			description: "no-content",
+			httpInfo:    &httpCallInfo{response: &adapters.ResponseData{StatusCode: http.StatusNoContent}},
+			expectHit:   true,
+		},
+		{
+			description: "server-error",
+			httpInfo:    &httpCallInfo{response: &adapters.ResponseData{StatusCode: http.StatusInternalServerError}},
+		},
+		{
+			description: "timeout",
+			httpInfo:    &httpCallInfo{err: context.DeadlineExceeded},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			cache := newTestBidderResponseCache(&metricsConfig.NilMetricsEngine{}, 10)
+			bidderRequest := anonymousBidderRequest("a")
+			fingerprint := cache.fingerprint(bidderRequest)
+			reqData := []*adapters.RequestData{{Method: http.MethodPost, Uri: "https://bidder.example.com"}}
+			responseChannel := make(chan *httpCallInfo, 1)
+
+			_, keys := cache.answer(bidderRequest, fingerprint, reqData, responseChannel)
+			test.httpInfo.request = reqData[0]
+			cache.store(keys, test.httpInfo)
+
+			misses, _ := cache.answer(bidderRequest, fingerprint, reqData, responseChannel)
+			assert.Equal(t, test.expectHit, len(misses) == 0)
+		})
+	}
+}
+
+func TestBidderResponseCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	cache := newTestBidderResponseCache(&metricsConfig.NilMetricsEngine{}, 2)
+	response := &adapters.ResponseData{StatusCode: http.StatusOK}
+
+	cache.put([]byte("a"), response)
+	cache.put([]byte("b"), response)
+	_, ok := cache.get([]byte("a"))
+	assert.True(t, ok)
+	cache.put([]byte("c"), response)
+
+	_, ok = cache.get([]byte("a"))
+	assert.True(t, ok)
+	_, ok = cache.get([]byte("b"))
+	assert.False(t, ok, "the least recently used response should have been evicted")
+	_, ok = cache.get([]byte("c"))
+	assert.True(t, ok)
+}
+
+func TestRequestBidReusesCachedResponses(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.Write([]byte(`{"id":"response"}`))
+	}))
+	defer server.Close()
+
+	bidderImpl := &goodSingleBidder{
+		httpRequest: &adapters.RequestData{
+			Method:  http.MethodPost,
+			Uri:     server.URL,
+			Body:    []byte(`{"key":"val"}`),
+			Headers: http.Header{},
+		},
+		bidResponse: &adapters.BidderResponse{},
+	}
+	cache := newTestBidderResponseCache(&metricsConfig.NilMetricsEngine{}, 10)
+	bidder := adaptBidder(bidderImpl, server.Client(), &config.Configuration{}, &metricsConfig.NilMetricsEngine{}, openrtb_ext.BidderAppnexus, nil, "", 0, nil, cache, nil, nil)
+	currencyConverter := currency.NewRateConverter(&http.Client{}, "", time.Duration(0))
+
+	for _, id := range []string{"a", "b"} {
+		_, _, errs := bidder.requestBid(context.Background(), anonymousBidderRequest(id), currencyConverter.Rates(), &adapters.ExtraRequestInfo{}, &adscert.NilSigner{}, bidRequestOptions{}, openrtb_ext.ExtAlternateBidderCodes{}, &hookexecution.EmptyHookExecutor{}, nil)
+		assert.Empty(t, errs)
+		assert.Equal(t, `{"id":"response"}`, string(bidderImpl.httpResponse.Body))
+	}
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+}
@@ -17,7 +17,6 @@ import (
 	"testing"
 	"time"
 
-	"github.com/golang/glog"
 	"github.com/prebid/openrtb/v20/adcom1"
 	nativeRequests "github.com/prebid/openrtb/v20/native1/request"
 	nativeResponse "github.com/prebid/openrtb/v20/native1/response"
@@ -29,6 +28,7 @@ import (
 	"github.com/prebid/prebid-server/v2/exchange/entities"
 	"github.com/prebid/prebid-server/v2/experiment/adscert"
 	"github.com/prebid/prebid-server/v2/hooks/hookexecution"
+	"github.com/prebid/prebid-server/v2/logger"
 	"github.com/prebid/prebid-server/v2/metrics"
 	metricsConfig "github.com/prebid/prebid-server/v2/metrics/config"
 	"github.com/prebid/prebid-server/v2/openrtb_ext"
@@ -611,6 +611,87 @@ func TestConnectionClose(t *testing.T) {
 	}
 }
 
+func TestDoRequestMaxResponseSize(t *testing.T) {
+	body := strings.Repeat("a", 100)
+	testCases := []struct {
+		description     string
+		maxResponseSize int64
+		streamed        bool
+		expectedBody    []byte
+		expectedError   error
+	}{
+		{
+			description:     "under-limit",
+			maxResponseSize: 100,
+			expectedBody:    []byte(body),
+		},
+		{
+			description:     "no-limit",
+			maxResponseSize: 0,
+			expectedBody:    []byte(body),
+		},
+		{
+			description:     "content-length-over-limit",
+			maxResponseSize: 99,
+			expectedError:   &errortypes.BadServerResponse{Message: "Server response exceeded the maximum size of 99 bytes"},
+		},
+		{
+			description:     "streamed-over-limit",
+			maxResponseSize: 99,
+			streamed:        true,
+			expectedError:   &errortypes.BadServerResponse{Message: "Server response exceeded the maximum size of 99 bytes"},
+		},
+		{
+			description:     "streamed-under-limit",
+			maxResponseSize: 100,
+			streamed:        true,
+			expectedBody:    []byte(body),
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if test.streamed {
+					// flushing before writing the body sends the response without a Content-Length
+					w.(http.Flusher).Flush()
+				}
+				w.Write([]byte(body))
+			}))
+			defer server.Close()
+
+			bidder := &bidderAdapter{
+				Bidder:     &mixedMultiBidder{},
+				Client:     server.Client(),
+				BidderName: openrtb_ext.BidderAppnexus,
+				me:         &metricsConfig.NilMetricsEngine{},
+				config:     bidderAdapterConfig{MaxResponseSize: test.maxResponseSize},
+			}
+			callInfo := bidder.doRequest(context.Background(), &adapters.RequestData{
+				Method: "POST",
+				Uri:    server.URL,
+			}, time.Now(), &TmaxAdjustmentsPreprocessed{})
+
+			assert.Equal(t, test.expectedError, callInfo.err)
+			if test.expectedError == nil {
+				assert.Equal(t, test.expectedBody, callInfo.response.Body)
+			} else {
+				assert.Nil(t, callInfo.response)
+			}
+		})
+	}
+}
+
+func TestAdaptBidderMaxResponseSize(t *testing.T) {
+	cfg := &config.Configuration{MaxBidderResponseSize: 1000}
+
+	hostDefault := adaptBidder(&mixedMultiBidder{}, http.DefaultClient, cfg, &metricsConfig.NilMetricsEngine{}, openrtb_ext.BidderAppnexus, nil, "", 0, nil, nil, nil, nil)
+	assert.Equal(t, int64(1000), hostDefault.(*bidderAdapter).config.MaxResponseSize)
+
+	bidderOverride := adaptBidder(&mixedMultiBidder{}, http.DefaultClient, cfg, &metricsConfig.NilMetricsEngine{}, openrtb_ext.BidderAppnexus, nil, "", 500, nil, nil, nil, nil)
+	assert.Equal(t, int64(500), bidderOverride.(*bidderAdapter).config.MaxResponseSize)
+}
+
 type bid struct {
 	currency       string
 	price          float64
@@ -2163,6 +2244,7 @@ func TestCallRecordTLSHandshakeTime(t *testing.T) {
 	// setup a mock metrics engine and its expectation
 	metricsMock := &metrics.MetricsEngineMock{}
 	metricsMock.Mock.On("RecordTLSHandshakeTime", mock.Anything).Return()
+	metricsMock.Mock.On("RecordAdapterTLSHandshake", mock.Anything, false).Return()
 	metricsMock.On("RecordOverheadTime", metrics.PreBidder, mock.Anything).Once()
 	metricsMock.On("RecordBidderServerResponseTime", mock.Anything).Once()
 
@@ -2206,7 +2288,7 @@ func TestTimeoutNotificationOff(t *testing.T) {
 	if tb, ok := bidder.Bidder.(adapters.TimeoutBidder); !ok {
 		t.Error("Failed to cast bidder to a TimeoutBidder")
 	} else {
-		bidder.doTimeoutNotification(tb, &adapters.RequestData{}, glog.Warningf)
+		bidder.doTimeoutNotification(tb, &adapters.RequestData{}, logger.Warningf)
 	}
 }
 
@@ -3423,3 +3505,30 @@ func BenchmarkCompressToGZIPOptimized(b *testing.B) {
 		getRequestBody(req, "GZIP")
 	}
 }
+
+func BenchmarkDoRequest(b *testing.B) {
+	for _, size := range []int{1 << 10, 64 << 10} {
+		respBody := `{"seatbid":[{"bid":[{"adm":"` + strings.Repeat("a", size) + `"}]}]}`
+		server := httptest.NewServer(mockHandler(http.StatusOK, "getBody", respBody))
+
+		bidder := &bidderAdapter{
+			Client: server.Client(),
+			me:     &metricsConfig.NilMetricsEngine{},
+			config: bidderAdapterConfig{DisableConnMetrics: true},
+		}
+		req := &adapters.RequestData{
+			Method:  http.MethodPost,
+			Uri:     server.URL,
+			Body:    []byte(`{"key":"val"}`),
+			Headers: http.Header{},
+		}
+
+		b.Run(fmt.Sprintf("%dKB", size>>10), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				bidder.doRequest(context.Background(), req, time.Now(), nil)
+			}
+		})
+		server.Close()
+	}
+}
@@ -0,0 +1,160 @@
+package exchange
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/prebid/prebid-server/v2/config"
+)
+
+// NewBidderTransportClient returns a client for the bidder adapters which sends the requests to the endpoints
+// of bidders with their own TLS or transport config with a transport of their own, and the rest with the
+// given client. The bidders' transports are copies of the given client's, so the settings they don't
+// override, and its root CAs, stay the same. It returns the given client if no bidder has its own config.
+func NewBidderTransportClient(client *http.Client, bidderInfos config.BidderInfos) (*http.Client, []error) {
+	var errs []error
+	transports := make(map[string]http.RoundTripper)
+	hostBidders := make(map[string]string)
+	for bidder, info := range bidderInfos {
+		if (info.TLS == nil && info.Transport == nil) || !info.IsEnabled() {
+			continue
+		}
+		origin, err := endpointOrigin(info.Endpoint)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: transport: %v", bidder, err))
+			continue
+		}
+		endpoint, _ := url.Parse(origin)
+		if other, ok := hostBidders[endpoint.Host]; ok {
+			errs = append(errs, fmt.Errorf("%s: transport: bidder %s has its own transport config for the same host %s", bidder, other, endpoint.Host))
+			continue
+		}
+		transport, err := bidderTransport(client.Transport, info)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: transport: %v", bidder, err))
+			continue
+		}
+		hostBidders[endpoint.Host] = bidder
+		transports[endpoint.Host] = transport
+	}
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	if len(transports) == 0 {
+		return client, nil
+	}
+
+	fallback := client.Transport
+	if fallback == nil {
+		fallback = http.DefaultTransport
+	}
+	transportClient := *client
+	transportClient.Transport = &hostTransport{transports: transports, fallback: fallback}
+	return &transportClient, nil
+}
+
+// bidderTransport returns a copy of the base transport with the bidder's TLS and transport config.
+func bidderTransport(base http.RoundTripper, info config.BidderInfo) (*http.Transport, error) {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	baseTransport, ok := base.(*http.Transport)
+	if !ok {
+		return nil, fmt.Errorf("the bidder client's transport is a %T, not an *http.Transport", base)
+	}
+
+	transport := baseTransport.Clone()
+	if transport.TLSClientConfig == nil {
+		transport.TLSClientConfig = &tls.Config{}
+	}
+	if info.TLS != nil {
+		if err := applyBidderTLS(transport.TLSClientConfig, info.TLS); err != nil {
+			return nil, err
+		}
+	}
+	if info.Transport != nil {
+		applyBidderTransport(transport, info.Transport)
+	}
+	return transport, nil
+}
+
+func applyBidderTLS(tlsConfig *tls.Config, bidderTLS *config.BidderTLS) error {
+	if bidderTLS.RootCert != "" {
+		rootCAs, err := baseRootCAs(tlsConfig)
+		if err != nil {
+			return err
+		}
+		pem, err := os.ReadFile(bidderTLS.RootCert)
+		if err != nil {
+			return err
+		}
+		if !rootCAs.AppendCertsFromPEM(pem) {
+			return fmt.Errorf("no certificates found in %s", bidderTLS.RootCert)
+		}
+		tlsConfig.RootCAs = rootCAs
+	}
+
+	if bidderTLS.ClientCert != "" {
+		cert, err := tls.LoadX509KeyPair(bidderTLS.ClientCert, bidderTLS.ClientKey)
+		if err != nil {
+			return err
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	if version := bidderTLS.MinTLSVersion(); version != 0 {
+		tlsConfig.MinVersion = version
+	}
+	return nil
+}
+
+func applyBidderTransport(transport *http.Transport, bidderTransport *config.BidderTransport) {
+	if bidderTransport.MaxConnsPerHost > 0 {
+		transport.MaxConnsPerHost = bidderTransport.MaxConnsPerHost
+	}
+	if bidderTransport.MaxIdleConnsPerHost > 0 {
+		transport.MaxIdleConnsPerHost = bidderTransport.MaxIdleConnsPerHost
+		// the pool of every host is bounded by MaxIdleConns too
+		if transport.MaxIdleConns > 0 && transport.MaxIdleConns < bidderTransport.MaxIdleConnsPerHost {
+			transport.MaxIdleConns = bidderTransport.MaxIdleConnsPerHost
+		}
+	}
+	if bidderTransport.IdleConnTimeoutSeconds > 0 {
+		transport.IdleConnTimeout = time.Duration(bidderTransport.IdleConnTimeoutSeconds) * time.Second
+	}
+	// a transport with its own TLS config only negotiates HTTP/2 if it's forced to
+	if bidderTransport.HTTP2 {
+		transport.ForceAttemptHTTP2 = true
+	}
+	if bidderTransport.TLSSessionCacheSize > 0 {
+		transport.TLSClientConfig.ClientSessionCache = tls.NewLRUClientSessionCache(bidderTransport.TLSSessionCacheSize)
+	}
+}
+
+// baseRootCAs returns a copy of the CAs the base config trusts, which are the system's if it doesn't set
+// any, so the bidder's CAs can be added to them.
+func baseRootCAs(tlsConfig *tls.Config) (*x509.CertPool, error) {
+	if tlsConfig.RootCAs != nil {
+		return tlsConfig.RootCAs.Clone(), nil
+	}
+	return x509.SystemCertPool()
+}
+
+// hostTransport sends requests with the transport for their host, or the fallback if there isn't one.
+type hostTransport struct {
+	transports map[string]http.RoundTripper
+	fallback   http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *hostTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if transport, ok := t.transports[req.URL.Host]; ok {
+		return transport.RoundTrip(req)
+	}
+	return t.fallback.RoundTrip(req)
+}
@@ -0,0 +1,206 @@
+package exchange
+
+import (
+	"crypto/tls"
+	"encoding/pem"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewBidderTransportClientTLS(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	// the handshake with the client which doesn't trust the bidder's CA is expected to fail
+	server.Config.ErrorLog = log.New(io.Discard, "", 0)
+	server.StartTLS()
+	defer server.Close()
+
+	rootCert := filepath.Join(t.TempDir(), "ca.pem")
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	require.NoError(t, os.WriteFile(rootCert, certPEM, 0600))
+
+	bidderInfos := config.BidderInfos{
+		"bidderA": config.BidderInfo{
+			Endpoint: server.URL + "/openrtb2",
+			TLS:      &config.BidderTLS{RootCert: rootCert, MinVersion: "1.2"},
+		},
+		"bidderB": config.BidderInfo{
+			Endpoint: "https://bidderB.com/openrtb2",
+		},
+	}
+
+	client, errs := NewBidderTransportClient(&http.Client{}, bidderInfos)
+	require.Empty(t, errs)
+
+	resp, err := client.Get(server.URL + "/openrtb2")
+	require.NoError(t, err, "the bidder's CA should be trusted")
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+	transport := client.Transport.(*hostTransport)
+	bidderTransport := transport.transports[server.Listener.Addr().String()].(*http.Transport)
+	assert.Equal(t, uint16(tls.VersionTLS12), bidderTransport.TLSClientConfig.MinVersion)
+	assert.Equal(t, http.DefaultTransport, transport.fallback)
+
+	_, err = http.DefaultClient.Get(server.URL + "/openrtb2")
+	assert.Error(t, err, "the bidder's CA shouldn't be trusted by other clients")
+}
+
+func TestNewBidderTransportClientTransport(t *testing.T) {
+	base := &http.Client{
+		Transport: &http.Transport{
+			MaxIdleConns:        10,
+			MaxIdleConnsPerHost: 2,
+			IdleConnTimeout:     30 * time.Second,
+			TLSClientConfig:     &tls.Config{},
+		},
+	}
+	bidderInfos := config.BidderInfos{
+		"bidderA": config.BidderInfo{
+			Endpoint: "https://bidderA.com/openrtb2",
+			Transport: &config.BidderTransport{
+				MaxConnsPerHost:        100,
+				MaxIdleConnsPerHost:    50,
+				IdleConnTimeoutSeconds: 90,
+				HTTP2:                  true,
+				TLSSessionCacheSize:    64,
+			},
+		},
+		"bidderB": config.BidderInfo{
+			Endpoint:  "http://bidderB.com:8080/openrtb2",
+			Transport: &config.BidderTransport{IdleConnTimeoutSeconds: 5},
+		},
+	}
+
+	client, errs := NewBidderTransportClient(base, bidderInfos)
+	require.Empty(t, errs)
+
+	transport := client.Transport.(*hostTransport)
+	assert.Same(t, base.Transport, transport.fallback)
+
+	bidderA := transport.transports["bidderA.com"].(*http.Transport)
+	assert.Equal(t, 100, bidderA.MaxConnsPerHost)
+	assert.Equal(t, 50, bidderA.MaxIdleConnsPerHost)
+	assert.Equal(t, 50, bidderA.MaxIdleConns, "the pool of the host should fit its idle connections")
+	assert.Equal(t, 90*time.Second, bidderA.IdleConnTimeout)
+	assert.True(t, bidderA.ForceAttemptHTTP2)
+	assert.NotNil(t, bidderA.TLSClientConfig.ClientSessionCache)
+
+	bidderB := transport.transports["bidderB.com:8080"].(*http.Transport)
+	assert.Equal(t, 0, bidderB.MaxConnsPerHost)
+	assert.Equal(t, 2, bidderB.MaxIdleConnsPerHost)
+	assert.Equal(t, 10, bidderB.MaxIdleConns)
+	assert.Equal(t, 5*time.Second, bidderB.IdleConnTimeout)
+	assert.False(t, bidderB.ForceAttemptHTTP2)
+	assert.Nil(t, bidderB.TLSClientConfig.ClientSessionCache)
+
+	assert.Nil(t, base.Transport.(*http.Transport).TLSClientConfig.ClientSessionCache, "the base transport shouldn't change")
+}
+
+func TestNewBidderTransportClientNoBidderConfig(t *testing.T) {
+	bidderInfos := config.BidderInfos{
+		"bidderA": config.BidderInfo{
+			Endpoint: "https://bidderA.com/openrtb2",
+		},
+		"bidderB": config.BidderInfo{
+			Disabled: true,
+			Endpoint: "https://bidderB.com/openrtb2",
+			TLS:      &config.BidderTLS{RootCert: "missing.pem"},
+		},
+	}
+
+	given := &http.Client{}
+	client, errs := NewBidderTransportClient(given, bidderInfos)
+	assert.Empty(t, errs)
+	assert.Same(t, given, client)
+}
+
+func TestNewBidderTransportClientErrors(t *testing.T) {
+	rootCert := filepath.Join(t.TempDir(), "ca.pem")
+	require.NoError(t, os.WriteFile(rootCert, []byte("not a certificate"), 0600))
+
+	testCases := []struct {
+		name          string
+		client        *http.Client
+		bidderInfos   config.BidderInfos
+		expectedError string
+	}{
+		{
+			name:   "missing-root-cert",
+			client: &http.Client{},
+			bidderInfos: config.BidderInfos{
+				"bidderA": config.BidderInfo{Endpoint: "https://bidderA.com/bid", TLS: &config.BidderTLS{RootCert: "missing.pem"}},
+			},
+			expectedError: "bidderA: transport: open missing.pem: no such file or directory",
+		},
+		{
+			name:   "no-certificates",
+			client: &http.Client{},
+			bidderInfos: config.BidderInfos{
+				"bidderA": config.BidderInfo{Endpoint: "https://bidderA.com/bid", TLS: &config.BidderTLS{RootCert: rootCert}},
+			},
+			expectedError: "bidderA: transport: no certificates found in " + rootCert,
+		},
+		{
+			name:   "missing-client-cert",
+			client: &http.Client{},
+			bidderInfos: config.BidderInfos{
+				"bidderA": config.BidderInfo{Endpoint: "https://bidderA.com/bid", TLS: &config.BidderTLS{ClientCert: "missing.pem", ClientKey: "missing-key.pem"}},
+			},
+			expectedError: "bidderA: transport: open missing.pem: no such file or directory",
+		},
+		{
+			name:   "macro-in-host",
+			client: &http.Client{},
+			bidderInfos: config.BidderInfos{
+				"bidderA": config.BidderInfo{Endpoint: "https://{{.Host}}.bidderA.com/bid", TLS: &config.BidderTLS{MinVersion: "1.2"}},
+			},
+			expectedError: "bidderA: transport: endpoint https://{{.Host}}.bidderA.com/bid has no fixed host",
+		},
+		{
+			name:   "not-an-http-transport",
+			client: &http.Client{Transport: roundTripperFunc(func(*http.Request) (*http.Response, error) { return nil, nil })},
+			bidderInfos: config.BidderInfos{
+				"bidderA": config.BidderInfo{Endpoint: "https://bidderA.com/bid", TLS: &config.BidderTLS{MinVersion: "1.2"}},
+			},
+			expectedError: "bidderA: transport: the bidder client's transport is a exchange.roundTripperFunc, not an *http.Transport",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			client, errs := NewBidderTransportClient(test.client, test.bidderInfos)
+			assert.Nil(t, client)
+			require.Len(t, errs, 1)
+			assert.EqualError(t, errs[0], test.expectedError)
+		})
+	}
+}
+
+func TestNewBidderTransportClientSameHost(t *testing.T) {
+	bidderInfos := config.BidderInfos{
+		"bidderA": config.BidderInfo{Endpoint: "https://bidder.com/a", TLS: &config.BidderTLS{MinVersion: "1.2"}},
+		"bidderB": config.BidderInfo{Endpoint: "https://bidder.com/b", Transport: &config.BidderTransport{HTTP2: true}},
+	}
+
+	_, errs := NewBidderTransportClient(&http.Client{}, bidderInfos)
+	require.Len(t, errs, 1)
+	assert.Contains(t, errs[0].Error(), "has its own transport config for the same host bidder.com")
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
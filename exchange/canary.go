@@ -0,0 +1,81 @@
+package exchange
+
+import (
+	"context"
+	"time"
+
+	"github.com/prebid/prebid-server/v2/adapters"
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/currency"
+	"github.com/prebid/prebid-server/v2/errortypes"
+	"github.com/prebid/prebid-server/v2/exchange/entities"
+	"github.com/prebid/prebid-server/v2/experiment/adscert"
+	"github.com/prebid/prebid-server/v2/hooks/hookexecution"
+	"github.com/prebid/prebid-server/v2/metrics"
+	"github.com/prebid/prebid-server/v2/openrtb_ext"
+)
+
+// canaryBidder sends the requests assigned to the canary to the bidder built with the canary config,
+// and the others to the bidder built with the regular config, recording the same metrics for both so
+// they can be compared.
+type canaryBidder struct {
+	name    openrtb_ext.BidderName
+	control AdaptedBidder
+	canary  AdaptedBidder
+	me      metrics.MetricsEngine
+}
+
+func (b *canaryBidder) requestBid(ctx context.Context, bidderRequest BidderRequest, conversions currency.Conversions, reqInfo *adapters.ExtraRequestInfo, adsCertSigner adscert.Signer, bidRequestOptions bidRequestOptions, alternateBidderCodes openrtb_ext.ExtAlternateBidderCodes, hookExecutor hookexecution.StageExecutor, ruleToAdjustments openrtb_ext.AdjustmentsByDealID) ([]*entities.PbsOrtbSeatBid, extraBidderRespInfo, []error) {
+	bidder, version := b.control, metrics.CanaryControl
+	if bidderRequest.Canary {
+		bidder, version = b.canary, metrics.CanaryCanary
+	}
+
+	start := time.Now()
+	seatBids, extraRespInfo, errs := bidder.requestBid(ctx, bidderRequest, conversions, reqInfo, adsCertSigner, bidRequestOptions, alternateBidderCodes, hookExecutor, ruleToAdjustments)
+
+	bids := 0
+	for _, seatBid := range seatBids {
+		if seatBid != nil {
+			bids += len(seatBid.Bids)
+		}
+	}
+	labels := metrics.AdapterCanaryLabels{
+		Adapter: b.name,
+		Version: version,
+		Success: len(errortypes.FatalOnly(errs)) == 0,
+	}
+	b.me.RecordAdapterCanary(labels, bids, time.Since(start))
+
+	return seatBids, extraRespInfo, errs
+}
+
+// assignCanaries decides which bidder requests go to the canary config of their bidder. The account's
+// share for a bidder replaces the one set in the bidder's canary config.
+func assignCanaries(bidderRequests []BidderRequest, accountCanaries map[string]float64, infos config.BidderInfos, random func() float64) {
+	for i := range bidderRequests {
+		coreBidder := bidderRequests[i].BidderCoreName.String()
+		info, ok := infos[coreBidder]
+		if !ok || info.Canary == nil {
+			continue
+		}
+
+		percent := info.Canary.Percent
+		if accountPercent, ok := accountCanaries[coreBidder]; ok {
+			percent = accountPercent
+		}
+		bidderRequests[i].Canary = percent > 0 && random()*100 < percent
+	}
+}
+
+// canaryAdapterInfo returns the adapter config the canary of the bidder is built with.
+func canaryAdapterInfo(info config.BidderInfo) config.Adapter {
+	adapter := buildAdapterInfo(info)
+	if info.Canary.Endpoint != "" {
+		adapter.Endpoint = info.Canary.Endpoint
+	}
+	if info.Canary.ExtraAdapterInfo != "" {
+		adapter.ExtraAdapterInfo = info.Canary.ExtraAdapterInfo
+	}
+	return adapter
+}
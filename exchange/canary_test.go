@@ -0,0 +1,207 @@
+package exchange
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v2/adapters"
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/currency"
+	"github.com/prebid/prebid-server/v2/errortypes"
+	"github.com/prebid/prebid-server/v2/exchange/entities"
+	"github.com/prebid/prebid-server/v2/metrics"
+	"github.com/prebid/prebid-server/v2/openrtb_ext"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCanaryBidderRequestBid(t *testing.T) {
+	controlSeatBids := []*entities.PbsOrtbSeatBid{{Bids: []*entities.PbsOrtbBid{{Bid: &openrtb2.Bid{ID: "control"}}}}}
+	canarySeatBids := []*entities.PbsOrtbSeatBid{{Bids: []*entities.PbsOrtbBid{{Bid: &openrtb2.Bid{ID: "canary-1"}}, {Bid: &openrtb2.Bid{ID: "canary-2"}}}}}
+
+	testCases := []struct {
+		description      string
+		canary           bool
+		canaryErrs       []error
+		expectedSeatBids []*entities.PbsOrtbSeatBid
+		expectedLabels   metrics.AdapterCanaryLabels
+		expectedBids     int
+	}{
+		{
+			description:      "Control",
+			canary:           false,
+			expectedSeatBids: controlSeatBids,
+			expectedLabels:   metrics.AdapterCanaryLabels{Adapter: openrtb_ext.BidderAppnexus, Version: metrics.CanaryControl, Success: true},
+			expectedBids:     1,
+		},
+		{
+			description:      "Canary",
+			canary:           true,
+			expectedSeatBids: canarySeatBids,
+			expectedLabels:   metrics.AdapterCanaryLabels{Adapter: openrtb_ext.BidderAppnexus, Version: metrics.CanaryCanary, Success: true},
+			expectedBids:     2,
+		},
+		{
+			description:      "Canary with warnings",
+			canary:           true,
+			canaryErrs:       []error{&errortypes.Warning{Message: "warning"}},
+			expectedSeatBids: canarySeatBids,
+			expectedLabels:   metrics.AdapterCanaryLabels{Adapter: openrtb_ext.BidderAppnexus, Version: metrics.CanaryCanary, Success: true},
+			expectedBids:     2,
+		},
+		{
+			description:      "Canary with errors",
+			canary:           true,
+			canaryErrs:       []error{errors.New("failed")},
+			expectedSeatBids: canarySeatBids,
+			expectedLabels:   metrics.AdapterCanaryLabels{Adapter: openrtb_ext.BidderAppnexus, Version: metrics.CanaryCanary, Success: false},
+			expectedBids:     2,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			me := &metrics.MetricsEngineMock{}
+			me.On("RecordAdapterCanary", test.expectedLabels, test.expectedBids, mock.Anything).Return()
+
+			bidder := &canaryBidder{
+				name:    openrtb_ext.BidderAppnexus,
+				control: &mockAdaptedBidder{bidResponse: controlSeatBids},
+				canary:  &mockAdaptedBidder{bidResponse: canarySeatBids, errorResponse: test.canaryErrs},
+				me:      me,
+			}
+			bidderRequest := BidderRequest{BidderName: openrtb_ext.BidderAppnexus, BidderCoreName: openrtb_ext.BidderAppnexus, Canary: test.canary}
+
+			seatBids, _, errs := bidder.requestBid(context.Background(), bidderRequest, currency.NewConstantRates(), &adapters.ExtraRequestInfo{}, nil, bidRequestOptions{}, openrtb_ext.ExtAlternateBidderCodes{}, nil, nil)
+			assert.Equal(t, test.expectedSeatBids, seatBids)
+			assert.Equal(t, test.canaryErrs, errs)
+			me.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAssignCanaries(t *testing.T) {
+	infos := config.BidderInfos{
+		"appnexus": {Canary: &config.BidderCanary{Endpoint: "http://canary.com", Percent: 10}},
+		"rubicon":  {},
+	}
+
+	testCases := []struct {
+		description     string
+		accountCanaries map[string]float64
+		random          float64
+		expectedCanary  map[openrtb_ext.BidderName]bool
+	}{
+		{
+			description:    "Drawn for canary",
+			random:         0.05,
+			expectedCanary: map[openrtb_ext.BidderName]bool{"appnexus": true, "appnexusAlias": true, "rubicon": false},
+		},
+		{
+			description:    "Drawn for control",
+			random:         0.1,
+			expectedCanary: map[openrtb_ext.BidderName]bool{"appnexus": false, "appnexusAlias": false, "rubicon": false},
+		},
+		{
+			description:     "Account share replaces host share",
+			accountCanaries: map[string]float64{"appnexus": 50, "rubicon": 100},
+			random:          0.3,
+			expectedCanary:  map[openrtb_ext.BidderName]bool{"appnexus": true, "appnexusAlias": true, "rubicon": false},
+		},
+		{
+			description:     "Account turns canary off",
+			accountCanaries: map[string]float64{"appnexus": 0},
+			random:          0,
+			expectedCanary:  map[openrtb_ext.BidderName]bool{"appnexus": false, "appnexusAlias": false, "rubicon": false},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			bidderRequests := []BidderRequest{
+				{BidderName: "appnexus", BidderCoreName: "appnexus"},
+				{BidderName: "appnexusAlias", BidderCoreName: "appnexus"},
+				{BidderName: "rubicon", BidderCoreName: "rubicon"},
+			}
+
+			assignCanaries(bidderRequests, test.accountCanaries, infos, func() float64 { return test.random })
+
+			canary := make(map[openrtb_ext.BidderName]bool, len(bidderRequests))
+			for _, bidderRequest := range bidderRequests {
+				canary[bidderRequest.BidderName] = bidderRequest.Canary
+			}
+			assert.Equal(t, test.expectedCanary, canary)
+		})
+	}
+}
+
+func TestBuildCanaryBidders(t *testing.T) {
+	server := config.Server{ExternalUrl: "http://hosturl.com"}
+
+	var builtWith []config.Adapter
+	builder := func(name openrtb_ext.BidderName, cfg config.Adapter, server config.Server) (adapters.Bidder, error) {
+		builtWith = append(builtWith, cfg)
+		return fakeBidder{name.String()}, nil
+	}
+	failingBuilder := fakeBuilder{nil, errors.New("anyError")}.Builder
+
+	testCases := []struct {
+		description       string
+		bidderInfos       config.BidderInfos
+		builders          map[openrtb_ext.BidderName]adapters.Builder
+		expectedCanaries  []openrtb_ext.BidderName
+		expectedBuiltWith []config.Adapter
+		expectedErrors    []error
+	}{
+		{
+			description:      "No canary",
+			bidderInfos:      config.BidderInfos{"appnexus": {Endpoint: "http://control.com"}},
+			builders:         map[openrtb_ext.BidderName]adapters.Builder{openrtb_ext.BidderAppnexus: builder},
+			expectedCanaries: []openrtb_ext.BidderName{},
+		},
+		{
+			description: "Canary endpoint",
+			bidderInfos: config.BidderInfos{"appnexus": {Endpoint: "http://control.com", ExtraAdapterInfo: "extra", Canary: &config.BidderCanary{Endpoint: "http://canary.com"}}},
+			builders:    map[openrtb_ext.BidderName]adapters.Builder{openrtb_ext.BidderAppnexus: builder},
+			expectedCanaries: []openrtb_ext.BidderName{
+				openrtb_ext.BidderAppnexus,
+			},
+			expectedBuiltWith: []config.Adapter{{Endpoint: "http://canary.com", ExtraAdapterInfo: "extra"}},
+		},
+		{
+			description: "Canary extra info",
+			bidderInfos: config.BidderInfos{"appnexus": {Endpoint: "http://control.com", ExtraAdapterInfo: "extra", Canary: &config.BidderCanary{ExtraAdapterInfo: "canary"}}},
+			builders:    map[openrtb_ext.BidderName]adapters.Builder{openrtb_ext.BidderAppnexus: builder},
+			expectedCanaries: []openrtb_ext.BidderName{
+				openrtb_ext.BidderAppnexus,
+			},
+			expectedBuiltWith: []config.Adapter{{Endpoint: "http://control.com", ExtraAdapterInfo: "canary"}},
+		},
+		{
+			description:      "Builder error",
+			bidderInfos:      config.BidderInfos{"appnexus": {Canary: &config.BidderCanary{Endpoint: "http://canary.com"}}},
+			builders:         map[openrtb_ext.BidderName]adapters.Builder{openrtb_ext.BidderAppnexus: failingBuilder},
+			expectedCanaries: []openrtb_ext.BidderName{},
+			expectedErrors:   []error{errors.New("appnexus: canary: anyError")},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			builtWith = nil
+			bidders := map[openrtb_ext.BidderName]adapters.Bidder{openrtb_ext.BidderAppnexus: fakeBidder{"appnexus"}}
+
+			canaries, errs := buildCanaryBidders(test.bidderInfos, bidders, test.builders, server)
+
+			names := make([]openrtb_ext.BidderName, 0, len(canaries))
+			for name := range canaries {
+				names = append(names, name)
+			}
+			assert.ElementsMatch(t, test.expectedCanaries, names)
+			assert.Equal(t, test.expectedBuiltWith, builtWith)
+			assert.Equal(t, test.expectedErrors, errs)
+		})
+	}
+}
@@ -0,0 +1,133 @@
+package exchange
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptrace"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/logger"
+	"github.com/prebid/prebid-server/v2/metrics"
+	"github.com/prebid/prebid-server/v2/openrtb_ext"
+	"github.com/prebid/prebid-server/v2/util/task"
+)
+
+// connectionWarmer makes HEAD requests to the origins of bidder endpoints through the client used for
+// bidder requests. This leaves open connections in the client's idle pool, so auctions don't pay for
+// the TCP and TLS handshakes after a deploy or after a quiet period.
+type connectionWarmer struct {
+	client      *http.Client
+	me          metrics.MetricsEngine
+	origins     map[openrtb_ext.BidderName]string
+	connections int
+	timeout     time.Duration
+}
+
+// NewConnectionWarmup returns a task which warms up connections to the endpoints of the configured
+// bidders when started, and then keeps them alive at the configured interval. It returns nil if the
+// warmup is disabled or none of the bidders have an endpoint which can be warmed up.
+func NewConnectionWarmup(client *http.Client, cfg config.BidderConnectionWarmup, bidderInfos config.BidderInfos, me metrics.MetricsEngine) *task.TickerTask {
+	if !cfg.Enabled {
+		return nil
+	}
+
+	origins := make(map[openrtb_ext.BidderName]string, len(cfg.Bidders))
+	for _, bidder := range cfg.Bidders {
+		info, ok := bidderInfos[bidder]
+		if !ok || !info.IsEnabled() {
+			continue
+		}
+		origin, err := endpointOrigin(info.Endpoint)
+		if err != nil {
+			logger.Warningf("Not warming up connections to bidder %s: %v", bidder, err)
+			continue
+		}
+		origins[openrtb_ext.BidderName(bidder)] = origin
+	}
+	if len(origins) == 0 {
+		return nil
+	}
+
+	warmer := &connectionWarmer{
+		client:      client,
+		me:          me,
+		origins:     origins,
+		connections: cfg.Connections,
+		timeout:     time.Duration(cfg.TimeoutMs) * time.Millisecond,
+	}
+	return task.NewTickerTask(time.Duration(cfg.KeepAliveIntervalSeconds)*time.Second, warmer)
+}
+
+// endpointOrigin returns the scheme and host of a bidder endpoint. Endpoints with macros in the host
+// are resolved per request, so they have no fixed origin to warm up.
+func endpointOrigin(endpoint string) (string, error) {
+	scheme, rest, found := strings.Cut(endpoint, "://")
+	if !found {
+		return "", fmt.Errorf("endpoint %s has no scheme", endpoint)
+	}
+	host := rest
+	if i := strings.IndexAny(rest, "/?#"); i >= 0 {
+		host = rest[:i]
+	}
+	if host == "" || strings.Contains(host, "{{") {
+		return "", fmt.Errorf("endpoint %s has no fixed host", endpoint)
+	}
+	origin := scheme + "://" + host + "/"
+	if _, err := url.Parse(origin); err != nil {
+		return "", err
+	}
+	return origin, nil
+}
+
+// Run implements task.Runner. Requests are made concurrently so that each bidder ends up with up to
+// the configured number of idle connections.
+func (w *connectionWarmer) Run() error {
+	var wg sync.WaitGroup
+	for bidder, origin := range w.origins {
+		for i := 0; i < w.connections; i++ {
+			wg.Add(1)
+			go func(bidder openrtb_ext.BidderName, origin string) {
+				defer wg.Done()
+				w.me.RecordAdapterConnectionWarmup(bidder, w.warm(origin))
+			}(bidder, origin)
+		}
+	}
+	wg.Wait()
+	return nil
+}
+
+func (w *connectionWarmer) warm(origin string) metrics.ConnectionWarmupResult {
+	ctx, cancel := context.WithTimeout(context.Background(), w.timeout)
+	defer cancel()
+
+	connReused := false
+	ctx = httptrace.WithClientTrace(ctx, &httptrace.ClientTrace{
+		GotConn: func(info httptrace.GotConnInfo) {
+			connReused = info.Reused
+		},
+	})
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, origin, nil)
+	if err != nil {
+		return metrics.ConnectionWarmupFailed
+	}
+	resp, err := w.client.Do(req)
+	if err != nil {
+		logger.Debugf("Connection warmup request to %s failed: %v", origin, err)
+		return metrics.ConnectionWarmupFailed
+	}
+	// the body must be read to the end for the connection to be returned to the idle pool
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+
+	if connReused {
+		return metrics.ConnectionWarmupReused
+	}
+	return metrics.ConnectionWarmupCreated
+}
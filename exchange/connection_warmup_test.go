@@ -0,0 +1,154 @@
+package exchange
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/metrics"
+	"github.com/prebid/prebid-server/v2/openrtb_ext"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEndpointOrigin(t *testing.T) {
+	testCases := []struct {
+		name           string
+		endpoint       string
+		expectedOrigin string
+		expectedError  bool
+	}{
+		{
+			name:           "path-and-query",
+			endpoint:       "https://bidder.com/openrtb2?src=prebid",
+			expectedOrigin: "https://bidder.com/",
+		},
+		{
+			name:           "port",
+			endpoint:       "http://bidder.com:8080/bid",
+			expectedOrigin: "http://bidder.com:8080/",
+		},
+		{
+			name:           "no-path",
+			endpoint:       "https://bidder.com",
+			expectedOrigin: "https://bidder.com/",
+		},
+		{
+			name:           "macro-in-path",
+			endpoint:       "https://bidder.com/{{.AccountID}}/bid?pub={{.PublisherID}}",
+			expectedOrigin: "https://bidder.com/",
+		},
+		{
+			name:          "macro-in-host",
+			endpoint:      "https://{{.Host}}.bidder.com/bid",
+			expectedError: true,
+		},
+		{
+			name:          "no-scheme",
+			endpoint:      "bidder.com/bid",
+			expectedError: true,
+		},
+		{
+			name:          "no-host",
+			endpoint:      "https:///bid",
+			expectedError: true,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			origin, err := endpointOrigin(test.endpoint)
+			if test.expectedError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, test.expectedOrigin, origin)
+			}
+		})
+	}
+}
+
+func TestNewConnectionWarmup(t *testing.T) {
+	bidderInfos := config.BidderInfos{
+		"appnexus": config.BidderInfo{Disabled: false, Endpoint: "https://appnexus.com/bid"},
+		"disabled": config.BidderInfo{Disabled: true, Endpoint: "https://disabled.com/bid"},
+		"macro":    config.BidderInfo{Disabled: false, Endpoint: "https://{{.Host}}.macro.com/bid"},
+	}
+
+	testCases := []struct {
+		name        string
+		cfg         config.BidderConnectionWarmup
+		expectedNil bool
+	}{
+		{
+			name:        "disabled",
+			cfg:         config.BidderConnectionWarmup{Enabled: false, Bidders: []string{"appnexus"}, Connections: 1},
+			expectedNil: true,
+		},
+		{
+			name:        "no-bidders-with-fixed-origin",
+			cfg:         config.BidderConnectionWarmup{Enabled: true, Bidders: []string{"disabled", "macro", "unknown"}, Connections: 1},
+			expectedNil: true,
+		},
+		{
+			name:        "enabled",
+			cfg:         config.BidderConnectionWarmup{Enabled: true, Bidders: []string{"appnexus", "macro"}, Connections: 1},
+			expectedNil: false,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			warmup := NewConnectionWarmup(http.DefaultClient, test.cfg, bidderInfos, &metrics.MetricsEngineMock{})
+			assert.Equal(t, test.expectedNil, warmup == nil)
+		})
+	}
+}
+
+func TestConnectionWarmerRun(t *testing.T) {
+	var (
+		methods []string
+		lock    sync.Mutex
+	)
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		defer lock.Unlock()
+		methods = append(methods, r.Method+" "+r.URL.Path)
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	closedServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	closedServer.Close()
+
+	me := &metrics.MetricsEngineMock{}
+	warmer := &connectionWarmer{
+		client: server.Client(),
+		me:     me,
+		origins: map[openrtb_ext.BidderName]string{
+			"appnexus": server.URL + "/",
+			"rubicon":  closedServer.URL + "/",
+		},
+		connections: 1,
+		timeout:     5 * time.Second,
+	}
+
+	// first run opens the connection
+	me.On("RecordAdapterConnectionWarmup", openrtb_ext.BidderName("appnexus"), metrics.ConnectionWarmupCreated).Once()
+	me.On("RecordAdapterConnectionWarmup", openrtb_ext.BidderName("rubicon"), metrics.ConnectionWarmupFailed).Once()
+	require.NoError(t, warmer.Run())
+	me.AssertExpectations(t)
+
+	// second run keeps it alive
+	me.On("RecordAdapterConnectionWarmup", openrtb_ext.BidderName("appnexus"), metrics.ConnectionWarmupReused).Once()
+	me.On("RecordAdapterConnectionWarmup", openrtb_ext.BidderName("rubicon"), metrics.ConnectionWarmupFailed).Once()
+	require.NoError(t, warmer.Run())
+	me.AssertExpectations(t)
+
+	lock.Lock()
+	defer lock.Unlock()
+	assert.Equal(t, []string{"HEAD /", "HEAD /"}, methods)
+}
@@ -0,0 +1,218 @@
+package exchange
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"net/url"
+
+	"github.com/prebid/openrtb/v20/native1"
+	nativeResponse "github.com/prebid/openrtb/v20/native1/response"
+	"github.com/prebid/prebid-server/v2/analytics"
+	"github.com/prebid/prebid-server/v2/exchange/entities"
+	"github.com/prebid/prebid-server/v2/macros"
+	"github.com/prebid/prebid-server/v2/openrtb_ext"
+	"github.com/prebid/prebid-server/v2/util/jsonutil"
+)
+
+// creativeTrackerURLs returns a function which gives the tracker URLs to add to a bid's markup, or nil if
+// the account doesn't add trackers to this auction's creatives. The Prebid Server event URLs are only
+// added if events are enabled for the auction, since the event endpoint won't take them otherwise.
+func (ev *eventTracking) creativeTrackerURLs() func(pbsBid *entities.PbsOrtbBid, bidderName openrtb_ext.BidderName) []string {
+	if !ev.creativeTrackers.Enabled {
+		return nil
+	}
+	var eventTypes []analytics.EventType
+	if ev.isEventAllowed() {
+		for _, eventType := range ev.creativeTrackers.Events {
+			eventTypes = append(eventTypes, analytics.EventType(eventType))
+		}
+	}
+	if len(eventTypes) == 0 && len(ev.creativeTrackers.URLs) == 0 {
+		return nil
+	}
+
+	replaceMacros := ev.trackerMacroReplacer(len(ev.creativeTrackers.URLs) > 0)
+
+	return func(pbsBid *entities.PbsOrtbBid, bidderName openrtb_ext.BidderName) []string {
+		urls := make([]string, 0, len(eventTypes)+len(ev.creativeTrackers.URLs))
+		for _, eventType := range eventTypes {
+			urls = append(urls, ev.makeEventURL(eventType, pbsBid, bidderName))
+		}
+		for _, url := range ev.creativeTrackers.URLs {
+			if replaced, ok := replaceMacros(url, pbsBid, bidderName); ok {
+				urls = append(urls, replaced)
+			}
+		}
+		return urls
+	}
+}
+
+// trackerMacroReplacer returns a function which replaces the macros of the request and bid in a tracker URL.
+// URLs are left as they are if there's no macro replacer, or if withMacros is false because none of the
+// trackers are configured URLs. The function reports whether the URL could be replaced.
+func (ev *eventTracking) trackerMacroReplacer(withMacros bool) func(url string, pbsBid *entities.PbsOrtbBid, bidderName openrtb_ext.BidderName) (string, bool) {
+	if ev.macroReplacer == nil || ev.request == nil || !withMacros {
+		return func(url string, pbsBid *entities.PbsOrtbBid, bidderName openrtb_ext.BidderName) (string, bool) {
+			return url, true
+		}
+	}
+	macroProvider := macros.NewProvider(ev.request)
+	return func(url string, pbsBid *entities.PbsOrtbBid, bidderName openrtb_ext.BidderName) (string, bool) {
+		macroProvider.PopulateBidMacros(pbsBid, bidderName.String())
+		replaced, err := ev.macroReplacer.Replace(url, macroProvider)
+		return replaced, err == nil
+	}
+}
+
+// nativeTrackerAdder returns a function which adds the account's impression and click trackers to the markup
+// of a native bid, or nil if the account doesn't add any. Trackers whose macros can't be replaced, or which
+// aren't URLs once they are, are left out.
+func (ev *eventTracking) nativeTrackerAdder() func(pbsBid *entities.PbsOrtbBid, bidderName openrtb_ext.BidderName) {
+	if !ev.nativeTrackers.Enabled || len(ev.nativeTrackers.ImpTrackers) == 0 && len(ev.nativeTrackers.ClickTrackers) == 0 {
+		return nil
+	}
+	replaceMacros := ev.trackerMacroReplacer(true)
+	trackerURLs := func(urls []string, pbsBid *entities.PbsOrtbBid, bidderName openrtb_ext.BidderName) []string {
+		replacedURLs := make([]string, 0, len(urls))
+		for _, url := range urls {
+			if replaced, ok := replaceMacros(url, pbsBid, bidderName); ok && isTrackerURL(replaced) {
+				replacedURLs = append(replacedURLs, replaced)
+			}
+		}
+		return replacedURLs
+	}
+
+	return func(pbsBid *entities.PbsOrtbBid, bidderName openrtb_ext.BidderName) {
+		bid := pbsBid.Bid
+		if pbsBid.BidType != openrtb_ext.BidTypeNative || len(bid.AdM) == 0 {
+			return
+		}
+		impTrackers := trackerURLs(ev.nativeTrackers.ImpTrackers, pbsBid, bidderName)
+		clickTrackers := trackerURLs(ev.nativeTrackers.ClickTrackers, pbsBid, bidderName)
+		if adm, err := addNativeImpAndClickTrackers(bid.AdM, impTrackers, clickTrackers); err == nil {
+			bid.AdM = adm
+		}
+	}
+}
+
+// isTrackerURL reports whether the tracker is an absolute http or https URL.
+func isTrackerURL(tracker string) bool {
+	parsed, err := url.Parse(tracker)
+	return err == nil && (parsed.Scheme == "http" || parsed.Scheme == "https") && parsed.Host != ""
+}
+
+// addCreativeTrackers adds the tracker URLs to the markup of banner and native bids: as hidden pixels after
+// banner markup, and as impression trackers of native markup. Markup which is fetched with the nurl can't
+// be changed, and native markup which can't be parsed is left as it is.
+func addCreativeTrackers(pbsBid *entities.PbsOrtbBid, urls []string) {
+	bid := pbsBid.Bid
+	if len(urls) == 0 || len(bid.AdM) == 0 {
+		return
+	}
+	switch pbsBid.BidType {
+	case openrtb_ext.BidTypeBanner:
+		bid.AdM = addBannerTrackers(bid.AdM, urls)
+	case openrtb_ext.BidTypeNative:
+		if adm, err := addNativeTrackers(bid.AdM, urls); err == nil {
+			bid.AdM = adm
+		}
+	}
+}
+
+// addBannerTrackers appends a hidden pixel for each URL to the markup, the way Prebid.js adds its own.
+func addBannerTrackers(adm string, urls []string) string {
+	for _, url := range urls {
+		adm += `<div style="position:absolute;left:0px;top:0px;visibility:hidden;"><img src="` + html.EscapeString(url) + `"></div>`
+	}
+	return adm
+}
+
+// addNativeTrackers adds the URLs to the native markup as image pixels fired on impression.
+func addNativeTrackers(adm string, urls []string) (string, error) {
+	return modifyNativeMarkup(adm, func(markup *nativeResponse.Response) {
+		addNativeImpTrackers(markup, urls)
+	})
+}
+
+// addNativeImpAndClickTrackers adds the impression trackers to the native markup as image pixels, and the
+// click trackers to the clicktrackers of its link.
+func addNativeImpAndClickTrackers(adm string, impTrackers []string, clickTrackers []string) (string, error) {
+	return modifyNativeMarkup(adm, func(markup *nativeResponse.Response) {
+		addNativeImpTrackers(markup, impTrackers)
+		markup.Link.ClickTrackers = append(markup.Link.ClickTrackers, clickTrackers...)
+	})
+}
+
+// addNativeImpTrackers adds the URLs to the markup as image pixels fired on impression. Markup of native 1.0
+// or 1.1, and markup which only has imptrackers, gets them there, and any other markup gets them as
+// eventtrackers.
+func addNativeImpTrackers(markup *nativeResponse.Response, urls []string) {
+	if len(urls) == 0 {
+		return
+	}
+	if markup.Ver == "1.0" || markup.Ver == "1.1" || len(markup.ImpTrackers) > 0 && len(markup.EventTrackers) == 0 {
+		markup.ImpTrackers = append(markup.ImpTrackers, urls...)
+		return
+	}
+	for _, url := range urls {
+		markup.EventTrackers = append(markup.EventTrackers, nativeResponse.EventTracker{
+			Event:  native1.EventTypeImpression,
+			Method: native1.EventTrackingMethodImage,
+			URL:    url,
+		})
+	}
+}
+
+// modifyNativeMarkup parses the native markup, modifies it and checks that it still follows the native
+// spec. Markup which can't be parsed, or which doesn't follow the spec once modified, is returned as it
+// was, with the error.
+func modifyNativeMarkup(adm string, modify func(markup *nativeResponse.Response)) (string, error) {
+	var markup nativeResponse.Response
+	if err := jsonutil.UnmarshalValid(json.RawMessage(adm), &markup); err != nil {
+		return adm, err
+	}
+
+	modify(&markup)
+	if err := validateNativeMarkup(&markup); err != nil {
+		return adm, err
+	}
+
+	markupJSON, err := jsonutil.Marshal(markup)
+	if err != nil {
+		return adm, err
+	}
+	return string(markupJSON), nil
+}
+
+// validateNativeMarkup checks the parts of the native markup which trackers are added to, and which the spec
+// requires: the link and its URL, the assets, or where to fetch them from, and the event, method and URL of
+// each event tracker which fires an image or script.
+func validateNativeMarkup(markup *nativeResponse.Response) error {
+	if markup.Link.URL == "" {
+		return errors.New("native markup has no link.url")
+	}
+	if len(markup.Assets) == 0 && markup.AssetsURL == "" && markup.DCOURL == "" {
+		return errors.New("native markup has no assets, assetsurl or dcourl")
+	}
+	for i, url := range markup.ImpTrackers {
+		if url == "" {
+			return fmt.Errorf("native markup has an empty imptrackers[%d]", i)
+		}
+	}
+	for i, url := range markup.Link.ClickTrackers {
+		if url == "" {
+			return fmt.Errorf("native markup has an empty link.clicktrackers[%d]", i)
+		}
+	}
+	for i, tracker := range markup.EventTrackers {
+		if tracker.Event == 0 || tracker.Method == 0 {
+			return fmt.Errorf("native markup has no event or method in eventtrackers[%d]", i)
+		}
+		if tracker.URL == "" && (tracker.Method == native1.EventTrackingMethodImage || tracker.Method == native1.EventTrackingMethodJS) {
+			return fmt.Errorf("native markup has no url in eventtrackers[%d]", i)
+		}
+	}
+	return nil
+}
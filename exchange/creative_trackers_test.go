@@ -0,0 +1,363 @@
+package exchange
+
+import (
+	"testing"
+
+	"github.com/prebid/openrtb/v20/native1"
+	nativeResponse "github.com/prebid/openrtb/v20/native1/response"
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/exchange/entities"
+	"github.com/prebid/prebid-server/v2/macros"
+	"github.com/prebid/prebid-server/v2/openrtb_ext"
+	"github.com/prebid/prebid-server/v2/util/ptrutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreativeTrackerURLs(t *testing.T) {
+	request := &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{
+		ID:   "auction-1",
+		Site: &openrtb2.Site{Domain: "example.com", Publisher: &openrtb2.Publisher{ID: "123456"}},
+	}}
+	tests := []struct {
+		description       string
+		creativeTrackers  config.AccountCreativeTrackers
+		enabledForAccount bool
+		expectedURLs      []string
+	}{
+		{
+			description:       "events-and-urls",
+			creativeTrackers:  config.AccountCreativeTrackers{Enabled: true, Events: []string{"win", "imp"}, URLs: []string{"https://tracker.example.com/imp?bid=##PBS-BIDID##&bidder=##PBS-BIDDER##&domain=##PBS-DOMAIN##"}},
+			enabledForAccount: true,
+			expectedURLs: []string{
+				"http://localhost/event?t=win&b=BID-1&a=123456&bidder=openx&ts=1234567890",
+				"http://localhost/event?t=imp&b=BID-1&a=123456&bidder=openx&ts=1234567890",
+				"https://tracker.example.com/imp?bid=BID-1&bidder=openx&domain=example.com",
+			},
+		},
+		{
+			description:       "events-disabled-for-auction",
+			creativeTrackers:  config.AccountCreativeTrackers{Enabled: true, Events: []string{"imp"}, URLs: []string{"https://tracker.example.com/imp?bid=##PBS-BIDID##"}},
+			enabledForAccount: false,
+			expectedURLs:      []string{"https://tracker.example.com/imp?bid=BID-1"},
+		},
+		{
+			description:       "only-events-and-events-disabled-for-auction",
+			creativeTrackers:  config.AccountCreativeTrackers{Enabled: true, Events: []string{"imp"}},
+			enabledForAccount: false,
+			expectedURLs:      nil,
+		},
+		{
+			description:       "disabled",
+			creativeTrackers:  config.AccountCreativeTrackers{Enabled: false, Events: []string{"imp"}},
+			enabledForAccount: true,
+			expectedURLs:      nil,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			ev := &eventTracking{
+				enabledForAccount:  test.enabledForAccount,
+				accountID:          "123456",
+				auctionTimestampMs: 1234567890,
+				externalURL:        "http://localhost",
+				creativeTrackers:   test.creativeTrackers,
+				request:            request,
+				macroReplacer:      macros.NewStringIndexBasedReplacer(),
+			}
+			creativeTrackerURLs := ev.creativeTrackerURLs()
+			if test.expectedURLs == nil {
+				assert.Nil(t, creativeTrackerURLs)
+				return
+			}
+			bid := &entities.PbsOrtbBid{Bid: &openrtb2.Bid{ID: "BID-1"}, BidType: openrtb_ext.BidTypeBanner}
+			assert.Equal(t, test.expectedURLs, creativeTrackerURLs(bid, openrtb_ext.BidderOpenx))
+		})
+	}
+}
+
+func TestAddCreativeTrackers(t *testing.T) {
+	urls := []string{"https://tracker.example.com/imp?a=1&b=2"}
+	tests := []struct {
+		description string
+		bidType     openrtb_ext.BidType
+		adm         string
+		expectedAdM string
+	}{
+		{
+			description: "banner",
+			bidType:     openrtb_ext.BidTypeBanner,
+			adm:         `<div>ad</div>`,
+			expectedAdM: `<div>ad</div><div style="position:absolute;left:0px;top:0px;visibility:hidden;"><img src="https://tracker.example.com/imp?a=1&amp;b=2"></div>`,
+		},
+		{
+			description: "banner-without-adm",
+			bidType:     openrtb_ext.BidTypeBanner,
+			adm:         ``,
+			expectedAdM: ``,
+		},
+		{
+			description: "native-eventtrackers",
+			bidType:     openrtb_ext.BidTypeNative,
+			adm:         `{"ver":"1.2","assets":[{"id":1,"title":{"text":"Title"}}],"link":{"url":"https://example.com"},"eventtrackers":[{"event":1,"method":1,"url":"https://bidder.example.com/imp"}]}`,
+			expectedAdM: `{"ver":"1.2","assets":[{"id":1,"title":{"text":"Title"}}],"link":{"url":"https://example.com"},"eventtrackers":[{"event":1,"method":1,"url":"https://bidder.example.com/imp"},{"event":1,"method":1,"url":"https://tracker.example.com/imp?a=1&b=2"}]}`,
+		},
+		{
+			description: "native-imptrackers",
+			bidType:     openrtb_ext.BidTypeNative,
+			adm:         `{"assets":[{"id":1,"title":{"text":"Title"}}],"link":{"url":"https://example.com"},"imptrackers":["https://bidder.example.com/imp"]}`,
+			expectedAdM: `{"assets":[{"id":1,"title":{"text":"Title"}}],"link":{"url":"https://example.com"},"imptrackers":["https://bidder.example.com/imp","https://tracker.example.com/imp?a=1&b=2"]}`,
+		},
+		{
+			description: "native-without-trackers",
+			bidType:     openrtb_ext.BidTypeNative,
+			adm:         `{"assets":[{"id":1,"title":{"text":"Title"}}],"link":{"url":"https://example.com"}}`,
+			expectedAdM: `{"assets":[{"id":1,"title":{"text":"Title"}}],"link":{"url":"https://example.com"},"eventtrackers":[{"event":1,"method":1,"url":"https://tracker.example.com/imp?a=1&b=2"}]}`,
+		},
+		{
+			description: "native-1.1-without-trackers",
+			bidType:     openrtb_ext.BidTypeNative,
+			adm:         `{"ver":"1.1","assets":[{"id":1,"title":{"text":"Title"}}],"link":{"url":"https://example.com"}}`,
+			expectedAdM: `{"ver":"1.1","assets":[{"id":1,"title":{"text":"Title"}}],"link":{"url":"https://example.com"},"imptrackers":["https://tracker.example.com/imp?a=1&b=2"]}`,
+		},
+		{
+			description: "native-without-link",
+			bidType:     openrtb_ext.BidTypeNative,
+			adm:         `{"native":{"assets":[{"id":1,"title":{"text":"Title"}}],"link":{"url":"https://example.com"}}}`,
+			expectedAdM: `{"native":{"assets":[{"id":1,"title":{"text":"Title"}}],"link":{"url":"https://example.com"}}}`,
+		},
+		{
+			description: "native-malformed",
+			bidType:     openrtb_ext.BidTypeNative,
+			adm:         `not json`,
+			expectedAdM: `not json`,
+		},
+		{
+			description: "video",
+			bidType:     openrtb_ext.BidTypeVideo,
+			adm:         `<VAST version="3.0"></VAST>`,
+			expectedAdM: `<VAST version="3.0"></VAST>`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			bid := &entities.PbsOrtbBid{Bid: &openrtb2.Bid{ID: "BID-1", AdM: test.adm}, BidType: test.bidType}
+			addCreativeTrackers(bid, urls)
+			if test.bidType == openrtb_ext.BidTypeNative && test.adm != test.expectedAdM {
+				assert.JSONEq(t, test.expectedAdM, bid.Bid.AdM)
+				return
+			}
+			assert.Equal(t, test.expectedAdM, bid.Bid.AdM)
+		})
+	}
+}
+
+func TestModifyBidsForEventsAddsCreativeTrackers(t *testing.T) {
+	ev := &eventTracking{
+		enabledForRequest:  true,
+		accountID:          "123456",
+		auctionTimestampMs: 1234567890,
+		externalURL:        "http://localhost",
+		creativeTrackers:   config.AccountCreativeTrackers{Enabled: true, Events: []string{"imp"}},
+	}
+	seatBids := map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid{
+		openrtb_ext.BidderOpenx: {Bids: []*entities.PbsOrtbBid{
+			{Bid: &openrtb2.Bid{ID: "BID-1", AdM: "<div>ad</div>"}, BidType: openrtb_ext.BidTypeBanner, GeneratedBidID: "generated"},
+		}},
+	}
+
+	ev.modifyBidsForEvents(seatBids)
+
+	bid := seatBids[openrtb_ext.BidderOpenx].Bids[0]
+	assert.Equal(t, `<div>ad</div><div style="position:absolute;left:0px;top:0px;visibility:hidden;"><img src="http://localhost/event?t=imp&amp;b=generated&amp;a=123456&amp;bidder=openx&amp;ts=1234567890"></div>`, bid.Bid.AdM)
+	assert.NotNil(t, bid.BidEvents)
+}
+
+func TestNativeTrackerAdder(t *testing.T) {
+	request := &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{
+		ID:   "auction-1",
+		Site: &openrtb2.Site{Domain: "example.com", Publisher: &openrtb2.Publisher{ID: "123456"}},
+	}}
+	adm := `{"ver":"1.2","assets":[{"id":1,"title":{"text":"Title"}}],"link":{"url":"https://example.com","clicktrackers":["https://bidder.example.com/click"]},"eventtrackers":[{"event":1,"method":1,"url":"https://bidder.example.com/imp"}]}`
+	tests := []struct {
+		description    string
+		nativeTrackers config.AccountNativeTrackers
+		bidType        openrtb_ext.BidType
+		expectedAdM    string
+	}{
+		{
+			description: "imp-and-click-trackers",
+			nativeTrackers: config.AccountNativeTrackers{
+				Enabled:       true,
+				ImpTrackers:   []string{"https://measure.example.com/imp?bid=##PBS-BIDID##&bidder=##PBS-BIDDER##&domain=##PBS-DOMAIN##"},
+				ClickTrackers: []string{"https://measure.example.com/click?bid=##PBS-BIDID##"},
+			},
+			bidType:     openrtb_ext.BidTypeNative,
+			expectedAdM: `{"ver":"1.2","assets":[{"id":1,"title":{"text":"Title"}}],"link":{"url":"https://example.com","clicktrackers":["https://bidder.example.com/click","https://measure.example.com/click?bid=BID-1"]},"eventtrackers":[{"event":1,"method":1,"url":"https://bidder.example.com/imp"},{"event":1,"method":1,"url":"https://measure.example.com/imp?bid=BID-1&bidder=openx&domain=example.com"}]}`,
+		},
+		{
+			description: "only-click-trackers",
+			nativeTrackers: config.AccountNativeTrackers{
+				Enabled:       true,
+				ClickTrackers: []string{"https://measure.example.com/click?bid=##PBS-BIDID##"},
+			},
+			bidType:     openrtb_ext.BidTypeNative,
+			expectedAdM: `{"ver":"1.2","assets":[{"id":1,"title":{"text":"Title"}}],"link":{"url":"https://example.com","clicktrackers":["https://bidder.example.com/click","https://measure.example.com/click?bid=BID-1"]},"eventtrackers":[{"event":1,"method":1,"url":"https://bidder.example.com/imp"}]}`,
+		},
+		{
+			description: "not-a-url-once-replaced",
+			nativeTrackers: config.AccountNativeTrackers{
+				Enabled:       true,
+				ImpTrackers:   []string{"##PBS-MACRO-tracker##"},
+				ClickTrackers: []string{"https://measure.example.com/click"},
+			},
+			bidType:     openrtb_ext.BidTypeNative,
+			expectedAdM: `{"ver":"1.2","assets":[{"id":1,"title":{"text":"Title"}}],"link":{"url":"https://example.com","clicktrackers":["https://bidder.example.com/click","https://measure.example.com/click"]},"eventtrackers":[{"event":1,"method":1,"url":"https://bidder.example.com/imp"}]}`,
+		},
+		{
+			description:    "banner",
+			nativeTrackers: config.AccountNativeTrackers{Enabled: true, ImpTrackers: []string{"https://measure.example.com/imp"}},
+			bidType:        openrtb_ext.BidTypeBanner,
+			expectedAdM:    adm,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			ev := &eventTracking{
+				nativeTrackers: test.nativeTrackers,
+				request:        request,
+				macroReplacer:  macros.NewStringIndexBasedReplacer(),
+			}
+			addNativeTrackers := ev.nativeTrackerAdder()
+			bid := &entities.PbsOrtbBid{Bid: &openrtb2.Bid{ID: "BID-1", AdM: adm}, BidType: test.bidType}
+			addNativeTrackers(bid, openrtb_ext.BidderOpenx)
+			assert.JSONEq(t, test.expectedAdM, bid.Bid.AdM)
+		})
+	}
+}
+
+func TestNativeTrackerAdderDisabled(t *testing.T) {
+	tests := []struct {
+		description    string
+		nativeTrackers config.AccountNativeTrackers
+	}{
+		{
+			description:    "disabled",
+			nativeTrackers: config.AccountNativeTrackers{ImpTrackers: []string{"https://measure.example.com/imp"}},
+		},
+		{
+			description:    "no-trackers",
+			nativeTrackers: config.AccountNativeTrackers{Enabled: true},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			ev := &eventTracking{nativeTrackers: test.nativeTrackers}
+			assert.Nil(t, ev.nativeTrackerAdder())
+		})
+	}
+}
+
+func TestAddNativeImpAndClickTrackers(t *testing.T) {
+	tests := []struct {
+		description string
+		adm         string
+		expectedAdM string
+		expectedErr string
+	}{
+		{
+			description: "imptrackers",
+			adm:         `{"assets":[{"id":1,"title":{"text":"Title"}}],"link":{"url":"https://example.com"},"imptrackers":["https://bidder.example.com/imp"]}`,
+			expectedAdM: `{"assets":[{"id":1,"title":{"text":"Title"}}],"link":{"url":"https://example.com","clicktrackers":["https://measure.example.com/click"]},"imptrackers":["https://bidder.example.com/imp","https://measure.example.com/imp"]}`,
+		},
+		{
+			description: "assetsurl",
+			adm:         `{"assetsurl":"https://bidder.example.com/assets","link":{"url":"https://example.com"}}`,
+			expectedAdM: `{"assetsurl":"https://bidder.example.com/assets","link":{"url":"https://example.com","clicktrackers":["https://measure.example.com/click"]},"eventtrackers":[{"event":1,"method":1,"url":"https://measure.example.com/imp"}]}`,
+		},
+		{
+			description: "no-link",
+			adm:         `{"assets":[{"id":1,"title":{"text":"Title"}}]}`,
+			expectedAdM: `{"assets":[{"id":1,"title":{"text":"Title"}}]}`,
+			expectedErr: "native markup has no link.url",
+		},
+		{
+			description: "no-assets",
+			adm:         `{"link":{"url":"https://example.com"}}`,
+			expectedAdM: `{"link":{"url":"https://example.com"}}`,
+			expectedErr: "native markup has no assets, assetsurl or dcourl",
+		},
+		{
+			description: "invalid-eventtracker",
+			adm:         `{"assets":[{"id":1,"title":{"text":"Title"}}],"link":{"url":"https://example.com"},"eventtrackers":[{"event":1,"method":2}]}`,
+			expectedAdM: `{"assets":[{"id":1,"title":{"text":"Title"}}],"link":{"url":"https://example.com"},"eventtrackers":[{"event":1,"method":2}]}`,
+			expectedErr: "native markup has no url in eventtrackers[0]",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			adm, err := addNativeImpAndClickTrackers(test.adm, []string{"https://measure.example.com/imp"}, []string{"https://measure.example.com/click"})
+			if test.expectedErr != "" {
+				assert.EqualError(t, err, test.expectedErr)
+				assert.Equal(t, test.expectedAdM, adm)
+				return
+			}
+			assert.NoError(t, err)
+			assert.JSONEq(t, test.expectedAdM, adm)
+		})
+	}
+}
+
+func TestValidateNativeMarkup(t *testing.T) {
+	link := nativeResponse.Link{URL: "https://example.com"}
+	assets := []nativeResponse.Asset{{ID: ptrutil.ToPtr[int64](1)}}
+	tests := []struct {
+		description string
+		markup      nativeResponse.Response
+		expectedErr string
+	}{
+		{
+			description: "valid",
+			markup:      nativeResponse.Response{Assets: assets, Link: link, ImpTrackers: []string{"https://bidder.example.com/imp"}},
+		},
+		{
+			description: "dcourl",
+			markup:      nativeResponse.Response{DCOURL: "https://bidder.example.com/dco", Link: link},
+		},
+		{
+			description: "empty-imptracker",
+			markup:      nativeResponse.Response{Assets: assets, Link: link, ImpTrackers: []string{""}},
+			expectedErr: "native markup has an empty imptrackers[0]",
+		},
+		{
+			description: "empty-clicktracker",
+			markup:      nativeResponse.Response{Assets: assets, Link: nativeResponse.Link{URL: "https://example.com", ClickTrackers: []string{""}}},
+			expectedErr: "native markup has an empty link.clicktrackers[0]",
+		},
+		{
+			description: "eventtracker-without-method",
+			markup:      nativeResponse.Response{Assets: assets, Link: link, EventTrackers: []nativeResponse.EventTracker{{Event: native1.EventTypeImpression, URL: "https://bidder.example.com/imp"}}},
+			expectedErr: "native markup has no event or method in eventtrackers[0]",
+		},
+		{
+			description: "custom-eventtracker-without-url",
+			markup:      nativeResponse.Response{Assets: assets, Link: link, EventTrackers: []nativeResponse.EventTracker{{Event: native1.EventTypeImpression, Method: 500}}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			err := validateNativeMarkup(&test.markup)
+			if test.expectedErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, test.expectedErr)
+		})
+	}
+}
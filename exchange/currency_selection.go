@@ -0,0 +1,125 @@
+package exchange
+
+import (
+	"sort"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v2/currency"
+	"github.com/prebid/prebid-server/v2/exchange/entities"
+	"github.com/prebid/prebid-server/v2/openrtb_ext"
+)
+
+// selectMostBidsCurrency converts the bids to the currency of the request most of them were made in, so that
+// as few bids as possible are converted. Ties go to the currency which comes first in the request. If some bid
+// can't be converted to that currency, the next one is tried, and the bids are left in the currencies the
+// bidders converted them to if none can be used, or if there are no conversions. Each bid is then given the rate
+// it was converted from the currency of the bidder with.
+func selectMostBidsCurrency(requestCur []string, adapterBids map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid, conversions currency.Conversions) {
+	if conversions == nil {
+		return
+	}
+	if len(requestCur) == 0 {
+		// the bidders convert to USD if the request has no currencies
+		requestCur = []string{"USD"}
+	}
+
+	bidsByCur := make(map[string]int)
+	for _, seatBid := range adapterBids {
+		if seatBid == nil {
+			continue
+		}
+		for _, pbsBid := range seatBid.Bids {
+			bidsByCur[pbsBid.OriginalBidCur]++
+		}
+	}
+	candidates := make([]string, len(requestCur))
+	copy(candidates, requestCur)
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return bidsByCur[candidates[i]] > bidsByCur[candidates[j]]
+	})
+
+	for _, cur := range candidates {
+		if conversionsTo(cur, adapterBids, conversions) {
+			for _, seatBid := range adapterBids {
+				if seatBid == nil || seatBid.Currency == cur {
+					continue
+				}
+				for _, pbsBid := range seatBid.Bids {
+					convertBid(pbsBid, seatBid.Currency, cur, conversions)
+				}
+				seatBid.Currency = cur
+			}
+			break
+		}
+	}
+
+	for _, seatBid := range adapterBids {
+		if seatBid == nil {
+			continue
+		}
+		for _, pbsBid := range seatBid.Bids {
+			if rate, err := conversions.GetRate(pbsBid.OriginalBidCur, seatBid.Currency); err == nil {
+				pbsBid.CurrencyRate = rate
+			}
+		}
+	}
+}
+
+// conversionsTo reports whether every bid which isn't in the currency already can be converted to it, from the
+// currency of the bidder and from the currency of its seat.
+func conversionsTo(cur string, adapterBids map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid, conversions currency.Conversions) bool {
+	for _, seatBid := range adapterBids {
+		if seatBid == nil || seatBid.Currency == cur {
+			continue
+		}
+		for _, pbsBid := range seatBid.Bids {
+			if _, err := conversions.GetRate(pbsBid.OriginalBidCur, cur); err != nil {
+				return false
+			}
+			if _, err := conversions.GetRate(pbsBid.OriginalBidCur, seatBid.Currency); err != nil {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// convertBid converts the price of the bid from seatCur, the currency the bidder converted it to, to cur. The
+// price is converted from OriginalBidCPM, with the rate from the currency of the bidder to cur, rather than
+// converted a second time, with the rate between seatCur and cur. The bid adjustments made to the price in
+// seatCur are kept in proportion.
+func convertBid(pbsBid *entities.PbsOrtbBid, seatCur, cur string, conversions currency.Conversions) {
+	if pbsBid.Bid == nil {
+		return
+	}
+	rate, _ := conversions.GetRate(pbsBid.OriginalBidCur, cur)
+	seatRate, _ := conversions.GetRate(pbsBid.OriginalBidCur, seatCur)
+	if unadjusted := pbsBid.OriginalBidCPM * seatRate; unadjusted != 0 {
+		pbsBid.Bid.Price = pbsBid.OriginalBidCPM * rate * (pbsBid.Bid.Price / unadjusted)
+	} else {
+		// the bid was free before it was adjusted
+		pbsBid.Bid.Price = pbsBid.Bid.Price / seatRate * rate
+	}
+}
+
+// makeSeatBidCurrencyRates returns the rates the bids of the seat which made it into the response were
+// converted with, if the currency of the response was chosen by the exchange.
+func makeSeatBidCurrencyRates(pbsBids []*entities.PbsOrtbBid, bids []openrtb2.Bid) []openrtb_ext.ExtSeatBidCurrencyRate {
+	inResponse := make(map[string]bool, len(bids))
+	for i := range bids {
+		inResponse[bids[i].ID] = true
+	}
+
+	var rates []openrtb_ext.ExtSeatBidCurrencyRate
+	for _, pbsBid := range pbsBids {
+		if pbsBid.CurrencyRate == 0 || pbsBid.Bid == nil || !inResponse[pbsBid.Bid.ID] {
+			continue
+		}
+		rates = append(rates, openrtb_ext.ExtSeatBidCurrencyRate{
+			BidID: pbsBid.Bid.ID,
+			Cur:   pbsBid.OriginalBidCur,
+			Rate:  pbsBid.CurrencyRate,
+		})
+	}
+	return rates
+}
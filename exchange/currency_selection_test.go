@@ -0,0 +1,184 @@
+package exchange
+
+import (
+	"testing"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v2/currency"
+	"github.com/prebid/prebid-server/v2/exchange/entities"
+	"github.com/prebid/prebid-server/v2/openrtb_ext"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSelectMostBidsCurrency(t *testing.T) {
+	// the rate from CAD to EUR isn't the one through USD, so bids converted twice get the wrong price
+	conversions := currency.NewRates(map[string]map[string]float64{
+		"USD": {"EUR": 0.5, "GBP": 0.8, "CAD": 1.25},
+		"CAD": {"EUR": 0.7},
+	})
+
+	type testBid struct {
+		id        string
+		price     float64
+		origPrice float64
+		origCur   string
+	}
+	type expectedBid struct {
+		price float64
+		rate  float64
+	}
+	tests := []struct {
+		description  string
+		requestCur   []string
+		seats        map[openrtb_ext.BidderName]string
+		bids         map[openrtb_ext.BidderName][]testBid
+		expectedCur  map[openrtb_ext.BidderName]string
+		expectedBids map[string]expectedBid
+	}{
+		{
+			description: "most bids in the second currency",
+			requestCur:  []string{"USD", "EUR"},
+			seats:       map[openrtb_ext.BidderName]string{"appnexus": "USD", "rubicon": "USD"},
+			bids: map[openrtb_ext.BidderName][]testBid{
+				"appnexus": {{id: "a1", price: 4, origPrice: 2, origCur: "EUR"}, {id: "a2", price: 1, origPrice: 1, origCur: "USD"}},
+				"rubicon":  {{id: "r1", price: 6, origPrice: 3, origCur: "EUR"}},
+			},
+			expectedCur: map[openrtb_ext.BidderName]string{"appnexus": "EUR", "rubicon": "EUR"},
+			expectedBids: map[string]expectedBid{
+				"a1": {price: 2, rate: 1},
+				"a2": {price: 0.5, rate: 0.5},
+				"r1": {price: 3, rate: 1},
+			},
+		},
+		{
+			description: "tie goes to the first currency",
+			requestCur:  []string{"USD", "EUR"},
+			seats:       map[openrtb_ext.BidderName]string{"appnexus": "USD"},
+			bids: map[openrtb_ext.BidderName][]testBid{
+				"appnexus": {{id: "a1", price: 4, origPrice: 2, origCur: "EUR"}, {id: "a2", price: 1, origPrice: 1, origCur: "USD"}},
+			},
+			expectedCur: map[openrtb_ext.BidderName]string{"appnexus": "USD"},
+			expectedBids: map[string]expectedBid{
+				"a1": {price: 4, rate: 2},
+				"a2": {price: 1, rate: 1},
+			},
+		},
+		{
+			description: "currency some seat can't be converted to is skipped",
+			requestCur:  []string{"USD", "GBP"},
+			seats:       map[openrtb_ext.BidderName]string{"appnexus": "USD", "rubicon": "EUR"},
+			bids: map[openrtb_ext.BidderName][]testBid{
+				"appnexus": {{id: "a1", price: 1.25, origPrice: 1, origCur: "GBP"}, {id: "a2", price: 1.25, origPrice: 1, origCur: "GBP"}},
+				"rubicon":  {{id: "r1", price: 2, origPrice: 2, origCur: "EUR"}},
+			},
+			expectedCur: map[openrtb_ext.BidderName]string{"appnexus": "USD", "rubicon": "USD"},
+			expectedBids: map[string]expectedBid{
+				"a1": {price: 1.25, rate: 1.25},
+				"a2": {price: 1.25, rate: 1.25},
+				"r1": {price: 4, rate: 2},
+			},
+		},
+		{
+			description: "no currency all seats can be converted to",
+			requestCur:  []string{"GBP"},
+			seats:       map[openrtb_ext.BidderName]string{"appnexus": "GBP", "rubicon": "EUR"},
+			bids: map[openrtb_ext.BidderName][]testBid{
+				"appnexus": {{id: "a1", price: 1, origPrice: 1, origCur: "GBP"}},
+				"rubicon":  {{id: "r1", price: 2, origPrice: 2, origCur: "EUR"}},
+			},
+			expectedCur: map[openrtb_ext.BidderName]string{"appnexus": "GBP", "rubicon": "EUR"},
+			expectedBids: map[string]expectedBid{
+				"a1": {price: 1, rate: 1},
+				"r1": {price: 2, rate: 1},
+			},
+		},
+		{
+			description: "converted from the currency of the bidder",
+			requestCur:  []string{"EUR", "USD"},
+			seats:       map[openrtb_ext.BidderName]string{"appnexus": "USD", "rubicon": "EUR"},
+			bids: map[openrtb_ext.BidderName][]testBid{
+				"appnexus": {{id: "a1", price: 0.8, origPrice: 1, origCur: "CAD"}},
+				"rubicon":  {{id: "r1", price: 2, origPrice: 2, origCur: "EUR"}},
+			},
+			expectedCur: map[openrtb_ext.BidderName]string{"appnexus": "EUR", "rubicon": "EUR"},
+			expectedBids: map[string]expectedBid{
+				"a1": {price: 0.7, rate: 0.7},
+				"r1": {price: 2, rate: 1},
+			},
+		},
+		{
+			description: "bid adjustments are kept",
+			requestCur:  []string{"EUR"},
+			seats:       map[openrtb_ext.BidderName]string{"appnexus": "USD"},
+			bids: map[openrtb_ext.BidderName][]testBid{
+				"appnexus": {{id: "a1", price: 0.4, origPrice: 1, origCur: "CAD"}, {id: "a2", price: 1, origPrice: 1, origCur: "EUR"}},
+			},
+			expectedCur: map[openrtb_ext.BidderName]string{"appnexus": "EUR"},
+			expectedBids: map[string]expectedBid{
+				"a1": {price: 0.35, rate: 0.7},
+				"a2": {price: 0.5, rate: 1},
+			},
+		},
+		{
+			description: "no request currencies",
+			seats:       map[openrtb_ext.BidderName]string{"appnexus": "USD"},
+			bids: map[openrtb_ext.BidderName][]testBid{
+				"appnexus": {{id: "a1", price: 1, origPrice: 0.5, origCur: "EUR"}},
+			},
+			expectedCur: map[openrtb_ext.BidderName]string{"appnexus": "USD"},
+			expectedBids: map[string]expectedBid{
+				"a1": {price: 1, rate: 2},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			adapterBids := make(map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid)
+			pbsBids := make(map[string]*entities.PbsOrtbBid)
+			for bidderName, cur := range test.seats {
+				seatBid := &entities.PbsOrtbSeatBid{Currency: cur}
+				for _, bid := range test.bids[bidderName] {
+					pbsBid := &entities.PbsOrtbBid{Bid: &openrtb2.Bid{ID: bid.id, Price: bid.price}, OriginalBidCPM: bid.origPrice, OriginalBidCur: bid.origCur}
+					seatBid.Bids = append(seatBid.Bids, pbsBid)
+					pbsBids[bid.id] = pbsBid
+				}
+				adapterBids[bidderName] = seatBid
+			}
+
+			selectMostBidsCurrency(test.requestCur, adapterBids, conversions)
+
+			for bidderName, cur := range test.expectedCur {
+				assert.Equal(t, cur, adapterBids[bidderName].Currency, string(bidderName))
+			}
+			for id, expected := range test.expectedBids {
+				assert.InDelta(t, expected.price, pbsBids[id].Bid.Price, 0.0001, id)
+				assert.InDelta(t, expected.rate, pbsBids[id].CurrencyRate, 0.0001, id)
+			}
+		})
+	}
+}
+
+func TestSelectMostBidsCurrencyNoConversions(t *testing.T) {
+	pbsBid := &entities.PbsOrtbBid{Bid: &openrtb2.Bid{ID: "a1", Price: 4}, OriginalBidCPM: 2, OriginalBidCur: "EUR"}
+	adapterBids := map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid{
+		"appnexus": {Currency: "USD", Bids: []*entities.PbsOrtbBid{pbsBid}},
+	}
+
+	assert.NotPanics(t, func() { selectMostBidsCurrency([]string{"USD", "EUR"}, adapterBids, nil) })
+	assert.Equal(t, "USD", adapterBids["appnexus"].Currency)
+	assert.Equal(t, 4.0, pbsBid.Bid.Price)
+	assert.Zero(t, pbsBid.CurrencyRate)
+}
+
+func TestMakeSeatBidCurrencyRates(t *testing.T) {
+	pbsBids := []*entities.PbsOrtbBid{
+		{Bid: &openrtb2.Bid{ID: "bid-1"}, OriginalBidCur: "EUR", CurrencyRate: 0.5},
+		{Bid: &openrtb2.Bid{ID: "bid-2"}, OriginalBidCur: "USD"},
+		{Bid: &openrtb2.Bid{ID: "bid-3"}, OriginalBidCur: "GBP", CurrencyRate: 0.8},
+	}
+	bids := []openrtb2.Bid{{ID: "bid-1"}, {ID: "bid-2"}}
+
+	assert.Equal(t, []openrtb_ext.ExtSeatBidCurrencyRate{{BidID: "bid-1", Cur: "EUR", Rate: 0.5}}, makeSeatBidCurrencyRates(pbsBids, bids))
+	assert.Empty(t, makeSeatBidCurrencyRates(pbsBids[1:2], bids))
+}
@@ -29,7 +29,7 @@ type PbsOrtbSeatBid struct {
 // PbsOrtbBid.Bid.Ext will become "response.seatbid[i].Bid.ext.bidder" in the final OpenRTB response.
 // PbsOrtbBid.BidMeta will become "response.seatbid[i].Bid.ext.prebid.meta" in the final OpenRTB response.
 // PbsOrtbBid.BidType will become "response.seatbid[i].Bid.ext.prebid.type" in the final OpenRTB response.
-// PbsOrtbBid.BidTargets does not need to be filled out by the Bidder. It will be set later by the exchange.
+// PbsOrtbBid.BidTargets does not need to be filled out by the Bidder. It will be set later by the exchange, which keeps the keys hooks added.
 // PbsOrtbBid.BidVideo is optional but should be filled out by the Bidder if BidType is video.
 // PbsOrtbBid.BidEvents is set by exchange when event tracking is enabled
 // PbsOrtbBid.BidFloors is set by exchange when floors is enabled
@@ -50,4 +50,9 @@ type PbsOrtbBid struct {
 	OriginalBidCPM    float64
 	OriginalBidCur    string
 	TargetBidderCode  string
+	// LineItem is the account's line item the bid matched, if any
+	LineItem *openrtb_ext.ExtBidPrebidLineItem
+	// CurrencyRate is the rate the bid was converted from OriginalBidCur with, if the account has the exchange
+	// choose the currency of the response
+	CurrencyRate float64
 }
@@ -9,6 +9,7 @@ import (
 	"github.com/prebid/prebid-server/v2/analytics"
 	"github.com/prebid/prebid-server/v2/config"
 	"github.com/prebid/prebid-server/v2/endpoints/events"
+	"github.com/prebid/prebid-server/v2/macros"
 	"github.com/prebid/prebid-server/v2/openrtb_ext"
 	"github.com/prebid/prebid-server/v2/util/jsonutil"
 )
@@ -22,10 +23,14 @@ type eventTracking struct {
 	integrationType    string
 	bidderInfos        config.BidderInfos
 	externalURL        string
+	creativeTrackers   config.AccountCreativeTrackers
+	nativeTrackers     config.AccountNativeTrackers
+	request            *openrtb_ext.RequestWrapper
+	macroReplacer      macros.Replacer
 }
 
 // getEventTracking creates an eventTracking object from the different configuration sources
-func getEventTracking(requestExtPrebid *openrtb_ext.ExtRequestPrebid, ts time.Time, account *config.Account, bidderInfos config.BidderInfos, externalURL string) *eventTracking {
+func getEventTracking(requestExtPrebid *openrtb_ext.ExtRequestPrebid, ts time.Time, account *config.Account, bidderInfos config.BidderInfos, externalURL string, request *openrtb_ext.RequestWrapper, macroReplacer macros.Replacer) *eventTracking {
 	return &eventTracking{
 		accountID:          account.ID,
 		enabledForAccount:  account.Events.Enabled,
@@ -34,6 +39,10 @@ func getEventTracking(requestExtPrebid *openrtb_ext.ExtRequestPrebid, ts time.Ti
 		integrationType:    getIntegrationType(requestExtPrebid),
 		bidderInfos:        bidderInfos,
 		externalURL:        externalURL,
+		creativeTrackers:   account.CreativeTrackers,
+		nativeTrackers:     account.NativeTrackers,
+		request:            request,
+		macroReplacer:      macroReplacer,
 	}
 }
 
@@ -44,14 +53,22 @@ func getIntegrationType(requestExtPrebid *openrtb_ext.ExtRequestPrebid) string {
 	return ""
 }
 
-// modifyBidsForEvents adds bidEvents and modifies VAST AdM if necessary.
+// modifyBidsForEvents adds bidEvents, and modifies VAST AdM and the AdM of banner and native bids if necessary.
 func (ev *eventTracking) modifyBidsForEvents(seatBids map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid) map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid {
+	creativeTrackerURLs := ev.creativeTrackerURLs()
+	addNativeTrackers := ev.nativeTrackerAdder()
 	for bidderName, seatBid := range seatBids {
 		modifyingVastXMLAllowed := ev.isModifyingVASTXMLAllowed(bidderName.String())
 		for _, pbsBid := range seatBid.Bids {
 			if modifyingVastXMLAllowed {
 				ev.modifyBidVAST(pbsBid, bidderName)
 			}
+			if creativeTrackerURLs != nil {
+				addCreativeTrackers(pbsBid, creativeTrackerURLs(pbsBid, bidderName))
+			}
+			if addNativeTrackers != nil {
+				addNativeTrackers(pbsBid, bidderName)
+			}
 			pbsBid.BidEvents = ev.makeBidExtEvents(pbsBid, bidderName)
 		}
 	}
@@ -14,14 +14,21 @@ import (
 	"strings"
 	"time"
 
+	"github.com/prebid/prebid-server/v2/logger"
 	"github.com/prebid/prebid-server/v2/privacy"
+	"github.com/prebid/prebid-server/v2/privacy/piiscan"
 
 	"github.com/prebid/prebid-server/v2/adapters"
 	"github.com/prebid/prebid-server/v2/adservertargeting"
+	"github.com/prebid/prebid-server/v2/auctionquality"
 	"github.com/prebid/prebid-server/v2/bidadjustment"
+	"github.com/prebid/prebid-server/v2/biddermaintenance"
+	"github.com/prebid/prebid-server/v2/bidlandscape"
 	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/contentclassification"
 	"github.com/prebid/prebid-server/v2/currency"
 	"github.com/prebid/prebid-server/v2/dsa"
+	"github.com/prebid/prebid-server/v2/dynamictmax"
 	"github.com/prebid/prebid-server/v2/errortypes"
 	"github.com/prebid/prebid-server/v2/exchange/entities"
 	"github.com/prebid/prebid-server/v2/experiment/adscert"
@@ -29,19 +36,24 @@ import (
 	"github.com/prebid/prebid-server/v2/floors"
 	"github.com/prebid/prebid-server/v2/gdpr"
 	"github.com/prebid/prebid-server/v2/hooks/hookexecution"
+	"github.com/prebid/prebid-server/v2/latencybudget"
 	"github.com/prebid/prebid-server/v2/macros"
+	"github.com/prebid/prebid-server/v2/metering"
 	"github.com/prebid/prebid-server/v2/metrics"
 	"github.com/prebid/prebid-server/v2/openrtb_ext"
 	"github.com/prebid/prebid-server/v2/prebid_cache_client"
+	"github.com/prebid/prebid-server/v2/priceencryption"
+	"github.com/prebid/prebid-server/v2/ratelimit"
+	"github.com/prebid/prebid-server/v2/responseoverride"
+	"github.com/prebid/prebid-server/v2/sizeresolution"
 	"github.com/prebid/prebid-server/v2/stored_requests"
 	"github.com/prebid/prebid-server/v2/stored_responses"
 	"github.com/prebid/prebid-server/v2/usersync"
 	"github.com/prebid/prebid-server/v2/util/jsonutil"
 	"github.com/prebid/prebid-server/v2/util/maputil"
+	"github.com/prebid/prebid-server/v2/util/uuidutil"
 
 	"github.com/buger/jsonparser"
-	"github.com/gofrs/uuid"
-	"github.com/golang/glog"
 	"github.com/prebid/openrtb/v20/openrtb2"
 	"github.com/prebid/openrtb/v20/openrtb3"
 )
@@ -76,6 +88,7 @@ type exchange struct {
 	privacyConfig            config.Privacy
 	categoriesFetcher        stored_requests.CategoryFetcher
 	bidIDGenerator           BidIDGenerator
+	deterministicIDs         *uuidutil.DeterministicGenerator
 	hostSChainNode           *openrtb2.SupplyChainNode
 	adsCertSigner            adscert.Signer
 	server                   config.Server
@@ -84,6 +97,17 @@ type exchange struct {
 	macroReplacer            macros.Replacer
 	priceFloorEnabled        bool
 	priceFloorFetcher        floors.FloorFetcher
+	floorCurrencyConversion  bool
+	piiScanner               *piiscan.Scanner
+	rateLimiter              *ratelimit.Limiter
+	bidderQPS                map[string]int
+	bidLandscape             *bidlandscape.Landscape
+	auctionQuality           *auctionquality.Tracker
+	meter                    *metering.Meter
+	maintenance              *biddermaintenance.Maintenance
+	adaptiveTmax             *adaptiveTmax
+	contentClassifier        *contentclassification.Enricher
+	testTraffic              config.TestTraffic
 }
 
 // Container to pass out response ext data from the GetAllBids goroutines back into the main thread
@@ -94,6 +118,9 @@ type seatResponseExtra struct {
 	// httpCalls is the list of debugging info. It should only be populated if the request.test == 1.
 	// This will become response.ext.debug.httpcalls.{bidder} on the final Response.
 	HttpCalls []*openrtb_ext.ExtHttpCall
+	// ResolvedRequest is the request sent to the bidder, if it's asked for in the debug output.
+	// This will become response.ext.debug.resolvedbidderrequests.{bidder} on the final Response.
+	ResolvedRequest json.RawMessage
 }
 
 type bidResponseWrapper struct {
@@ -110,7 +137,8 @@ type BidIDGenerator interface {
 }
 
 type bidIDGenerator struct {
-	enabled bool
+	enabled          bool
+	deterministicIDs *uuidutil.DeterministicGenerator
 }
 
 func (big *bidIDGenerator) Enabled() bool {
@@ -118,8 +146,8 @@ func (big *bidIDGenerator) Enabled() bool {
 }
 
 func (big *bidIDGenerator) New(bidder string) (string, error) {
-	rawUuid, err := uuid.NewV4()
-	return rawUuid.String(), err
+	// each bidder has its own sequence, since the bidders are given IDs in no particular order
+	return big.deterministicIDs.Sequence("bidid." + bidder).Generate()
 }
 
 type deduplicateChanceGenerator interface {
@@ -132,7 +160,19 @@ func (randomDeduplicateBidBooleanGenerator) Generate() bool {
 	return rand.Intn(100) < 50
 }
 
-func NewExchange(adapters map[openrtb_ext.BidderName]AdaptedBidder, cache prebid_cache_client.Client, cfg *config.Configuration, syncersByBidder map[string]usersync.Syncer, metricsEngine metrics.MetricsEngine, infos config.BidderInfos, gdprPermsBuilder gdpr.PermissionsBuilder, currencyConverter *currency.RateConverter, categoriesFetcher stored_requests.CategoryFetcher, adsCertSigner adscert.Signer, macroReplacer macros.Replacer, priceFloorFetcher floors.FloorFetcher) Exchange {
+// Dependencies are the optional collaborators of the exchange. Any of them may be left nil, which turns off
+// what it's there for.
+type Dependencies struct {
+	RateLimiter    *ratelimit.Limiter
+	BidLandscape   *bidlandscape.Landscape
+	AuctionQuality *auctionquality.Tracker
+	Meter          *metering.Meter
+	Maintenance    *biddermaintenance.Maintenance
+	// ContentClassifier classifies the content of requests, if content_classification is enabled
+	ContentClassifier contentclassification.Classifier
+}
+
+func NewExchange(adapters map[openrtb_ext.BidderName]AdaptedBidder, cache prebid_cache_client.Client, cfg *config.Configuration, syncersByBidder map[string]usersync.Syncer, metricsEngine metrics.MetricsEngine, infos config.BidderInfos, gdprPermsBuilder gdpr.PermissionsBuilder, currencyConverter *currency.RateConverter, categoriesFetcher stored_requests.CategoryFetcher, adsCertSigner adscert.Signer, macroReplacer macros.Replacer, priceFloorFetcher floors.FloorFetcher, deps Dependencies) Exchange {
 	bidderToSyncerKey := map[string]string{}
 	for bidder, syncer := range syncersByBidder {
 		bidderToSyncerKey[bidder] = syncer.Key()
@@ -155,6 +195,12 @@ func NewExchange(adapters map[openrtb_ext.BidderName]AdaptedBidder, cache prebid
 		gdprPermsBuilder:  gdprPermsBuilder,
 		hostSChainNode:    cfg.HostSChainNode,
 		bidderInfo:        infos,
+		auctionQuality:    deps.AuctionQuality,
+	}
+
+	var deterministicIDs *uuidutil.DeterministicGenerator
+	if cfg.DeterministicIDs.Enabled {
+		deterministicIDs = uuidutil.NewDeterministicGenerator(cfg.DeterministicIDs.Seed)
 	}
 
 	return &exchange{
@@ -170,7 +216,8 @@ func NewExchange(adapters map[openrtb_ext.BidderName]AdaptedBidder, cache prebid
 		me:                       metricsEngine,
 		gdprDefaultValue:         gdprDefaultValue,
 		privacyConfig:            privacyConfig,
-		bidIDGenerator:           &bidIDGenerator{cfg.GenerateBidID},
+		bidIDGenerator:           &bidIDGenerator{enabled: cfg.GenerateBidID, deterministicIDs: deterministicIDs},
+		deterministicIDs:         deterministicIDs,
 		hostSChainNode:           cfg.HostSChainNode,
 		adsCertSigner:            adsCertSigner,
 		server:                   config.Server{ExternalUrl: cfg.ExternalURL, GvlID: cfg.GDPR.HostVendorID, DataCenter: cfg.DataCenter},
@@ -179,9 +226,29 @@ func NewExchange(adapters map[openrtb_ext.BidderName]AdaptedBidder, cache prebid
 		macroReplacer:            macroReplacer,
 		priceFloorEnabled:        cfg.PriceFloors.Enabled,
 		priceFloorFetcher:        priceFloorFetcher,
+		floorCurrencyConversion:  cfg.PriceFloors.CurrencyConversion,
+		piiScanner:               piiscan.NewScanner(cfg.PIIScanner),
+		rateLimiter:              deps.RateLimiter,
+		bidderQPS:                cfg.RateLimiting.BidderQPS,
+		bidLandscape:             deps.BidLandscape,
+		auctionQuality:           deps.AuctionQuality,
+		meter:                    deps.Meter,
+		maintenance:              deps.Maintenance,
+		adaptiveTmax:             newAdaptiveTmax(cfg.AdaptiveBidderTmax),
+		contentClassifier:        newContentClassifier(cfg.ContentClassification, deps.ContentClassifier, metricsEngine),
+		testTraffic:              cfg.TestTraffic,
 	}
 }
 
+// newContentClassifier returns the enricher of the content of requests, or nil if content classification is off
+// or the host has no classifier.
+func newContentClassifier(cfg config.ContentClassification, classifier contentclassification.Classifier, metricsEngine metrics.MetricsEngine) *contentclassification.Enricher {
+	if !cfg.Enabled {
+		return nil
+	}
+	return contentclassification.NewEnricher(cfg, classifier, metricsEngine)
+}
+
 type ImpExtInfo struct {
 	EchoVideoAttrs bool
 	StoredImp      []byte
@@ -217,6 +284,27 @@ type AuctionRequest struct {
 	QueryParams             url.Values
 	BidderResponseStartTime time.Time
 	TmaxAdjustments         *TmaxAdjustmentsPreprocessed
+	// SimulatedResponses holds the OpenRTB response of each bidder, by bidder name, if the auction is a
+	// simulation. No bidders are called in a simulation, and bidders without a response don't bid.
+	SimulatedResponses map[string]json.RawMessage
+	// AnalyticsDebug turns debug output on for a request which didn't ask for it, so it can be logged to
+	// analytics. The endpoint removes it from the response.
+	AnalyticsDebug bool
+	// FirstPartyDataResolution is how the account's merge rules resolved the request's first party data.
+	// The auction resolves it from the request itself if the endpoint didn't.
+	FirstPartyDataResolution *firstpartydata.Resolution
+	// SizeResolution is the formats the endpoint resolved for banner imps from the device's screen.
+	SizeResolution *sizeresolution.Resolution
+	// DynamicTmax is how the endpoint adjusted the request's tmax, or nil if it wasn't adjusted.
+	DynamicTmax *dynamictmax.Adjustment
+	// PinnedResponses are the stored bid responses which bidders' responses are pinned to by response
+	// overrides, by lower case bidder name. Those bidders aren't called.
+	PinnedResponses map[string]responseoverride.Pinned
+	// AccountStoredBidResponses are the stored bid responses the account maps imps and bidders to, by lower
+	// case bidder name and imp ID, and AccountBidderImpReplaceImpID whether the imp IDs of their bids are
+	// replaced. Those bidders aren't called for those imps.
+	AccountStoredBidResponses    stored_responses.BidderImpsWithBidResponses
+	AccountBidderImpReplaceImpID stored_responses.BidderImpReplaceImpID
 }
 
 // BidderRequest holds the bidder specific request and all other
@@ -229,6 +317,12 @@ type BidderRequest struct {
 	BidderStoredResponses map[string]json.RawMessage
 	IsRequestAlias        bool
 	ImpReplaceImpId       map[string]bool
+	// SimulatedResponse is the OpenRTB response the bidder gives in a simulated auction, instead of being called
+	SimulatedResponse json.RawMessage
+	// Canary is true if the request goes to the bidder built with its canary config
+	Canary bool
+	// PIIPolicy is what the HTTP requests to the bidder mustn't have, if they're scanned for personal data
+	PIIPolicy *piiscan.Policy
 }
 
 func (e *exchange) HoldAuction(ctx context.Context, r *AuctionRequest, debugLog *DebugLog) (*AuctionResponse, error) {
@@ -236,11 +330,32 @@ func (e *exchange) HoldAuction(ctx context.Context, r *AuctionRequest, debugLog
 		return nil, nil
 	}
 
+	if r.SimulatedResponses == nil {
+		if err := e.meter.CheckQuota(ctx, &r.Account); err != nil {
+			return nil, err
+		}
+	}
+
+	var fpdErrs []error
+	if r.FirstPartyDataResolution == nil {
+		r.FirstPartyDataResolution, fpdErrs = firstpartydata.ResolveRequest(r.Account.FirstPartyData, r.BidRequestWrapper)
+	}
+
 	err := r.HookExecutor.ExecuteProcessedAuctionStage(r.BidRequestWrapper)
 	if err != nil {
 		return nil, err
 	}
 
+	// modules may have changed the first party data which the account's rules merge
+	fpdErrs = append(fpdErrs, r.FirstPartyDataResolution.ApplyModules(r.BidRequestWrapper)...)
+	if errortypes.ContainsFatalError(fpdErrs) {
+		return nil, errortypes.FatalOnly(fpdErrs)[0]
+	}
+
+	if r.Account.ContentClassification.Enabled {
+		e.contentClassifier.Enrich(ctx, r.BidRequestWrapper.BidRequest)
+	}
+
 	requestExt, err := r.BidRequestWrapper.GetRequestExt()
 	if err != nil {
 		return nil, err
@@ -260,15 +375,30 @@ func (e *exchange) HoldAuction(ctx context.Context, r *AuctionRequest, debugLog
 		requestExt.SetPrebid(requestExtPrebid)
 	}
 
+	testTraffic := e.isTestTraffic(r)
 	cacheInstructions := getExtCacheInstructions(requestExtPrebid)
+	if r.SimulatedResponses != nil || testTraffic {
+		// simulated and test bids aren't cached, since they could then be served
+		cacheInstructions = extCacheInstructions{returnCreative: true}
+	}
 
 	targData := getExtTargetData(requestExtPrebid, cacheInstructions)
 	if targData != nil {
 		_, targData.cacheHost, targData.cachePath = e.cache.GetExtCacheData()
 	}
 
-	// Get currency rates conversions for the auction
-	conversions := currency.GetAuctionCurrencyRates(e.currencyConverter, requestExtPrebid.CurrencyConversions)
+	// Get currency rates conversions for the auction, memoizing the rates the floors, bid adjustments and
+	// bidders look up as it runs
+	conversions := currency.GetAuctionCurrencyRates(e.currencyConverter, r.Account.Currency.Rates, requestExtPrebid.CurrencyConversions)
+	if conversions != nil {
+		memoizedConversions := currency.NewMemoizedConversions(conversions)
+		defer func() {
+			hits, misses := memoizedConversions.CacheResults()
+			e.me.RecordCurrencyConversionCacheResult(metrics.CacheHit, hits)
+			e.me.RecordCurrencyConversionCacheResult(metrics.CacheMiss, misses)
+		}()
+		conversions = memoizedConversions
+	}
 
 	var floorErrs []error
 	if e.priceFloorEnabled {
@@ -276,6 +406,9 @@ func (e *exchange) HoldAuction(ctx context.Context, r *AuctionRequest, debugLog
 	}
 
 	responseDebugAllow, accountDebugAllow, debugLog := getDebugInfo(r.BidRequestWrapper.Test, requestExtPrebid, r.Account.DebugAllow, debugLog)
+	if r.AnalyticsDebug {
+		responseDebugAllow, accountDebugAllow = true, true
+	}
 
 	// save incoming request with stored requests (if applicable) to return in debug logs
 	if responseDebugAllow || len(requestExtPrebid.AdServerTargeting) > 0 {
@@ -314,6 +447,8 @@ func (e *exchange) HoldAuction(ctx context.Context, r *AuctionRequest, debugLog
 	// Make our best guess if GDPR applies
 	gdprDefaultValue := e.parseGDPRDefaultValue(r.BidRequestWrapper)
 
+	pacingErrs := paceDeals(ctx, r.BidRequestWrapper, r.Account.ID, r.Account.DealPacing, e.rateLimiter)
+
 	// rebuild/resync the request in the request wrapper.
 	if err := r.BidRequestWrapper.RebuildRequest(); err != nil {
 		return nil, err
@@ -325,7 +460,14 @@ func (e *exchange) HoldAuction(ctx context.Context, r *AuctionRequest, debugLog
 		SChain: requestExt.GetSChain(),
 	}
 	bidderRequests, privacyLabels, errs := e.requestSplitter.cleanOpenRTBRequests(ctx, *r, requestExtLegacy, gdprDefaultValue, bidAdjustmentFactors)
+	pinBidderResponses(bidderRequests, r.PinnedResponses)
+	pinBidderImpResponses(bidderRequests, r.AccountStoredBidResponses, r.AccountBidderImpReplaceImpID)
+	setSimulatedResponses(bidderRequests, r.SimulatedResponses)
+	assignCanaries(bidderRequests, r.Account.BidderCanaries, e.bidderInfo, rand.Float64)
+	assignPIIPolicies(bidderRequests, r.BidRequestWrapper.BidRequest, e.piiScanner, rand.Float64)
 	errs = append(errs, floorErrs...)
+	errs = append(errs, pacingErrs...)
+	errs = append(errs, fpdErrs...)
 
 	mergedBidAdj, err := bidadjustment.Merge(r.BidRequestWrapper, r.Account.BidAdjustments)
 	if err != nil {
@@ -338,7 +480,7 @@ func (e *exchange) HoldAuction(ctx context.Context, r *AuctionRequest, debugLog
 
 	e.me.RecordRequestPrivacy(privacyLabels)
 
-	if len(r.StoredAuctionResponses) > 0 || len(r.StoredBidResponses) > 0 {
+	if len(r.StoredAuctionResponses) > 0 || len(r.StoredBidResponses) > 0 || len(r.AccountStoredBidResponses) > 0 {
 		e.me.RecordStoredResponse(r.PubID)
 	}
 
@@ -364,6 +506,16 @@ func (e *exchange) HoldAuction(ctx context.Context, r *AuctionRequest, debugLog
 		anyBidsReturned = true
 
 	} else {
+		var testTrafficErrs, maintenanceErrs, qpsErrs []error
+		if testTraffic {
+			bidderRequests, testTrafficErrs = e.routeTestTraffic(bidderRequests)
+			errs = append(errs, testTrafficErrs...)
+		}
+		bidderRequests, maintenanceErrs = e.leaveOutBiddersInMaintenance(bidderRequests, r.Account.BidderMaintenance)
+		errs = append(errs, maintenanceErrs...)
+		bidderRequests, qpsErrs = capBidderQPS(ctx, bidderRequests, e.bidderQPS, e.rateLimiter)
+		errs = append(errs, qpsErrs...)
+
 		// List of bidders we have requests for.
 		liveAdapters = listBiddersWithRequests(bidderRequests)
 
@@ -374,8 +526,12 @@ func (e *exchange) HoldAuction(ctx context.Context, r *AuctionRequest, debugLog
 		} else if r.Account.AlternateBidderCodes != nil {
 			alternateBidderCodes = *r.Account.AlternateBidderCodes
 		}
+		resolvedRequests := newResolvedBidderRequests(requestExtPrebid.ResolvedBidderRequests, responseDebugAllow, r.ResolvedBidRequest)
+		experiment := accountAdsCertExperiment(requestExtLegacy.Prebid.Experiment, r.Account.AdsCert)
 		var extraRespInfo extraAuctionResponseInfo
-		adapterBids, adapterExtra, extraRespInfo = e.getAllBids(auctionCtx, bidderRequests, bidAdjustmentFactors, conversions, accountDebugAllow, r.GlobalPrivacyControlHeader, debugLog.DebugOverride, alternateBidderCodes, requestExtLegacy.Prebid.Experiment, r.HookExecutor, r.StartTime, bidAdjustmentRules, r.TmaxAdjustments, responseDebugAllow)
+		bidderCtx, endBidderStage := latencybudget.FromContext(ctx).Start(auctionCtx, metrics.LatencyBudgetBidders)
+		adapterBids, adapterExtra, extraRespInfo = e.getAllBids(bidderCtx, bidderRequests, bidAdjustmentFactors, conversions, accountDebugAllow, r.GlobalPrivacyControlHeader, debugLog.DebugOverride, alternateBidderCodes, experiment, r.HookExecutor, r.StartTime, bidAdjustmentRules, r.TmaxAdjustments, responseDebugAllow, resolvedRequests, r.Account.AdaptiveBidderTmax.Enabled)
+		endBidderStage()
 		fledge = extraRespInfo.fledge
 		anyBidsReturned = extraRespInfo.bidsFound
 		r.BidderResponseStartTime = extraRespInfo.bidderResponseStartTime
@@ -386,9 +542,21 @@ func (e *exchange) HoldAuction(ctx context.Context, r *AuctionRequest, debugLog
 		cacheErrs      []error
 		bidResponseExt *openrtb_ext.ExtBidResponse
 		seatNonBids    = nonBids{}
+		// floorRejections counts the bids of each seat which were below their floors
+		floorRejections = make(map[string]int)
 	)
 
 	if anyBidsReturned {
+		var rejectErr *hookexecution.RejectError
+		adapterBids, rejectErr = r.HookExecutor.ExecuteRawBidderResponsePerSeatStage(r.BidRequestWrapper, adapterBids)
+		if rejectErr != nil {
+			errs = append(errs, rejectErr)
+		}
+
+		if r.Account.Currency.SelectsMostBids() {
+			selectMostBidsCurrency(r.BidRequestWrapper.Cur, adapterBids, conversions)
+		}
+
 		if e.priceFloorEnabled {
 			var rejectedBids []*entities.PbsOrtbSeatBid
 			var enforceErrs []error
@@ -396,12 +564,16 @@ func (e *exchange) HoldAuction(ctx context.Context, r *AuctionRequest, debugLog
 			adapterBids, enforceErrs, rejectedBids = floors.Enforce(r.BidRequestWrapper, adapterBids, r.Account, conversions)
 			errs = append(errs, enforceErrs...)
 			for _, rejectedBid := range rejectedBids {
+				floorRejections[rejectedBid.Seat]++
 				errs = append(errs, &errortypes.Warning{
 					Message:     fmt.Sprintf("%s bid id %s rejected - bid price %.4f %s is less than bid floor %.4f %s for imp %s", rejectedBid.Seat, rejectedBid.Bids[0].Bid.ID, rejectedBid.Bids[0].Bid.Price, rejectedBid.Currency, rejectedBid.Bids[0].BidFloors.FloorValue, rejectedBid.Bids[0].BidFloors.FloorCurrency, rejectedBid.Bids[0].Bid.ImpID),
 					WarningCode: errortypes.FloorBidRejectionWarningCode})
 			}
 		}
 
+		countPacedDeals(ctx, adapterBids, r.Account.ID, r.Account.DealPacing, e.rateLimiter)
+		matchLineItems(r.BidRequestWrapper.BidRequest, adapterBids, r.Account.LineItems)
+
 		var bidCategory map[string]string
 		//If includebrandcategory is present in ext then CE feature is on.
 		if requestExtPrebid.Targeting != nil && requestExtPrebid.Targeting.IncludeBrandCategory != nil {
@@ -427,8 +599,11 @@ func (e *exchange) HoldAuction(ctx context.Context, r *AuctionRequest, debugLog
 			}
 		}
 
-		evTracking := getEventTracking(requestExtPrebid, r.StartTime, &r.Account, e.bidderInfo, e.externalURL)
-		adapterBids = evTracking.modifyBidsForEvents(adapterBids)
+		evTracking := getEventTracking(requestExtPrebid, r.StartTime, &r.Account, e.bidderInfo, e.externalURL, r.BidRequestWrapper, e.macroReplacer)
+		if !testTraffic {
+			// test bids get no event URLs or trackers, so they're never billed
+			adapterBids = evTracking.modifyBidsForEvents(adapterBids)
+		}
 
 		r.HookExecutor.ExecuteAllProcessedBidResponsesStage(adapterBids)
 
@@ -436,7 +611,7 @@ func (e *exchange) HoldAuction(ctx context.Context, r *AuctionRequest, debugLog
 			multiBidMap := buildMultiBidMap(requestExtPrebid)
 
 			// A non-nil auction is only needed if targeting is active. (It is used below this block to extract cache keys)
-			auc = newAuction(adapterBids, len(r.BidRequestWrapper.Imp), targData.preferDeals)
+			auc = newAuction(adapterBids, len(r.BidRequestWrapper.Imp), targData.preferDeals, auctionMediaTypePreference(r.Account.Multiformat))
 			auc.validateAndUpdateMultiBid(adapterBids, targData.preferDeals, r.Account.DefaultBidLimit)
 			auc.setRoundedPrices(*targData)
 
@@ -455,12 +630,22 @@ func (e *exchange) HoldAuction(ctx context.Context, r *AuctionRequest, debugLog
 				}
 			}
 
-			cacheErrs = auc.doCache(ctx, e.cache, targData, evTracking, r.BidRequestWrapper.BidRequest, 60, &r.Account.CacheTTL, bidCategory, debugLog)
+			cacheCtx, endCacheWriteStage := latencybudget.FromContext(ctx).Start(ctx, metrics.LatencyBudgetCacheWrite)
+			cacheErrs = auc.doCache(cacheCtx, e.cache, targData, evTracking, r.BidRequestWrapper.BidRequest, 60, &r.Account.CacheTTL, bidCategory, debugLog, e.deterministicIDs.Sequence("cacheid"))
+			endCacheWriteStage()
 			if len(cacheErrs) > 0 {
 				errs = append(errs, cacheErrs...)
 			}
 
 			if targData.includeWinners || targData.includeBidderKeys || targData.includeFormat {
+				if r.Account.PriceEncryption.Enabled {
+					if targData.priceEncrypter, err = priceencryption.NewEncrypter(r.Account.PriceEncryption); err != nil {
+						errs = append(errs, &errortypes.Warning{
+							Message:     fmt.Sprintf("%s was left out of targeting: %v", openrtb_ext.HbPbEncKey, err),
+							WarningCode: errortypes.TargetingEncryptionWarningCode,
+						})
+					}
+				}
 				targData.setTargeting(auc, r.BidRequestWrapper.BidRequest.App != nil, bidCategory, r.Account.TruncateTargetAttribute, multiBidMap)
 			}
 		}
@@ -481,20 +666,25 @@ func (e *exchange) HoldAuction(ctx context.Context, r *AuctionRequest, debugLog
 
 	if !accountDebugAllow && !debugLog.DebugOverride {
 		accountDebugDisabledWarning := openrtb_ext.ExtBidderMessage{
-			Code:    errortypes.AccountLevelDebugDisabledWarningCode,
-			Message: "debug turned off for account",
+			Code:     errortypes.AccountLevelDebugDisabledWarningCode,
+			Category: errortypes.CategoryOf(errortypes.AccountLevelDebugDisabledWarningCode),
+			Message:  "debug turned off for account",
 		}
 		bidResponseExt.Warnings[openrtb_ext.BidderReservedGeneral] = append(bidResponseExt.Warnings[openrtb_ext.BidderReservedGeneral], accountDebugDisabledWarning)
 	}
 
 	for _, warning := range r.Warnings {
-		generalWarning := openrtb_ext.ExtBidderMessage{
-			Code:    errortypes.ReadCode(warning),
-			Message: warning.Error(),
-		}
+		generalWarning := openrtb_ext.NewExtBidderMessage(warning)
 		bidResponseExt.Warnings[openrtb_ext.BidderReservedGeneral] = append(bidResponseExt.Warnings[openrtb_ext.BidderReservedGeneral], generalWarning)
 	}
 
+	if !testTraffic {
+		if len(r.StoredAuctionResponses) == 0 {
+			recordBidLandscape(e.bidLandscape, r.Account.ID, bidderRequests, adapterBids, conversions)
+		}
+		meterUsage(ctx, e.meter, r, bidderRequests)
+	}
+
 	e.bidValidationEnforcement.SetBannerCreativeMaxSize(r.Account.Validations)
 
 	// Build the response
@@ -506,6 +696,9 @@ func (e *exchange) HoldAuction(ctx context.Context, r *AuctionRequest, debugLog
 		return nil, err
 	}
 	bidResponseExt = setSeatNonBid(bidResponseExt, seatNonBids)
+	if r.SimulatedResponses == nil && !testTraffic {
+		recordAuctionQuality(e.auctionQuality, r.Account.ID, bidderRequests, adapterExtra, floorRejections, seatNonBids, bidResponseExt)
+	}
 
 	return &AuctionResponse{
 		BidResponse:    bidResponse,
@@ -677,7 +870,9 @@ func (e *exchange) getAllBids(
 	pbsRequestStartTime time.Time,
 	bidAdjustmentRules map[string][]openrtb_ext.Adjustment,
 	tmaxAdjustments *TmaxAdjustmentsPreprocessed,
-	responseDebugAllowed bool) (
+	responseDebugAllowed bool,
+	resolvedRequests *resolvedBidderRequests,
+	adaptTmax bool) (
 	map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid,
 	map[openrtb_ext.BidderName]*seatResponseExtra,
 	extraAuctionResponseInfo) {
@@ -691,10 +886,10 @@ func (e *exchange) getAllBids(
 
 	for _, bidder := range bidderRequests {
 		// Here we actually call the adapters and collect the bids.
-		bidderRunner := e.recoverSafely(bidderRequests, func(bidderRequest BidderRequest, conversions currency.Conversions) {
+		bidderRunner := e.recoverSafely(ctx, bidderRequests, func(bidderRequest BidderRequest, conversions currency.Conversions) {
 			// Passing in aName so a doesn't change out from under the go routine
 			if bidderRequest.BidderLabels.Adapter == "" {
-				glog.Errorf("Exchange: bidlables for %s (%s) missing adapter string", bidderRequest.BidderName, bidderRequest.BidderCoreName)
+				logger.Ctx(ctx).Errorf("Exchange: bidlables for %s (%s) missing adapter string", bidderRequest.BidderName, bidderRequest.BidderCoreName)
 				bidderRequest.BidderLabels.Adapter = bidderRequest.BidderCoreName
 			}
 			brw := new(bidResponseWrapper)
@@ -704,6 +899,8 @@ func (e *exchange) getAllBids(
 			defer func() {
 				e.me.RecordAdapterRequest(bidderRequest.BidderLabels)
 			}()
+			bidderCtx, cancel, tmaxAdapted := e.adaptiveTmax.adapt(ctx, &bidderRequest, adaptTmax)
+			defer cancel()
 			start := time.Now()
 
 			reqInfo := adapters.NewExtraRequestInfo(conversions)
@@ -718,12 +915,21 @@ func (e *exchange) getAllBids(
 				tmaxAdjustments:        tmaxAdjustments,
 				bidderRequestStartTime: start,
 				responseDebugAllowed:   responseDebugAllowed,
+				resolvedRequests:       resolvedRequests,
+			}
+			var floorWarnings []error
+			if e.floorCurrencyConversion {
+				if bidderCurrency := floorCurrency(e.bidderInfo, bidderRequest); bidderCurrency != "" {
+					floorWarnings = convertBidderFloors(bidderRequest, bidderCurrency, conversions, e.me)
+				}
 			}
-			seatBids, extraBidderRespInfo, err := e.adapterMap[bidderRequest.BidderCoreName].requestBid(ctx, bidderRequest, conversions, &reqInfo, e.adsCertSigner, bidReqOptions, alternateBidderCodes, hookExecutor, bidAdjustmentRules)
+			seatBids, extraBidderRespInfo, err := e.adapterMap[bidderRequest.BidderCoreName].requestBid(bidderCtx, bidderRequest, conversions, &reqInfo, e.adsCertSigner, bidReqOptions, alternateBidderCodes, hookExecutor, bidAdjustmentRules)
+			err = append(err, floorWarnings...)
 			brw.bidderResponseStartTime = extraBidderRespInfo.respProcessingStartTime
 
 			// Add in time reporting
 			elapsed := time.Since(start)
+			e.adaptiveTmax.observe(bidderRequest, tmaxAdapted, elapsed)
 			brw.adapterSeatBids = seatBids
 			// Structure to record extra tracking data generated during bidding
 			ae := new(seatResponseExtra)
@@ -731,6 +937,7 @@ func (e *exchange) getAllBids(
 			if len(seatBids) != 0 {
 				ae.HttpCalls = seatBids[0].HttpCalls
 			}
+			ae.ResolvedRequest = extraBidderRespInfo.resolvedRequest
 			// Timing statistics
 			e.me.RecordAdapterTime(bidderRequest.BidderLabels, elapsed)
 			bidderRequest.BidderLabels.AdapterBids = bidsToMetric(brw.adapterSeatBids)
@@ -801,7 +1008,7 @@ func collectFledgeFromSeatBid(fledge *openrtb_ext.Fledge, bidderName openrtb_ext
 	return fledge
 }
 
-func (e *exchange) recoverSafely(bidderRequests []BidderRequest,
+func (e *exchange) recoverSafely(ctx context.Context, bidderRequests []BidderRequest,
 	inner func(BidderRequest, currency.Conversions),
 	chBids chan *bidResponseWrapper) func(BidderRequest, currency.Conversions) {
 	return func(bidderRequest BidderRequest, conversions currency.Conversions) {
@@ -818,7 +1025,7 @@ func (e *exchange) recoverSafely(bidderRequests []BidderRequest,
 					allBidders = sb.String()[:sb.Len()-1]
 				}
 
-				glog.Errorf("OpenRTB auction recovered panic from Bidder %s: %v. "+
+				logger.Ctx(ctx).Errorf("OpenRTB auction recovered panic from Bidder %s: %v. "+
 					"Account id: %s, All Bidders: %s, Stack trace is: %v",
 					bidderRequest.BidderCoreName, r, bidderRequest.BidderLabels.PubID, allBidders, string(debug.Stack()))
 				e.me.RecordAdapterPanic(bidderRequest.BidderLabels)
@@ -871,11 +1078,7 @@ func errorsToMetric(errs []error) map[metrics.AdapterError]struct{} {
 func errsToBidderErrors(errs []error) []openrtb_ext.ExtBidderMessage {
 	sErr := make([]openrtb_ext.ExtBidderMessage, 0)
 	for _, err := range errortypes.FatalOnly(errs) {
-		newErr := openrtb_ext.ExtBidderMessage{
-			Code:    errortypes.ReadCode(err),
-			Message: err.Error(),
-		}
-		sErr = append(sErr, newErr)
+		sErr = append(sErr, openrtb_ext.NewExtBidderMessage(err))
 	}
 
 	return sErr
@@ -884,11 +1087,7 @@ func errsToBidderErrors(errs []error) []openrtb_ext.ExtBidderMessage {
 func errsToBidderWarnings(errs []error) []openrtb_ext.ExtBidderMessage {
 	sWarn := make([]openrtb_ext.ExtBidderMessage, 0)
 	for _, warn := range errortypes.WarningOnly(errs) {
-		newErr := openrtb_ext.ExtBidderMessage{
-			Code:    errortypes.ReadCode(warn),
-			Message: warn.Error(),
-		}
-		sWarn = append(sWarn, newErr)
+		sWarn = append(sWarn, openrtb_ext.NewExtBidderMessage(warn))
 	}
 	return sWarn
 }
@@ -1182,6 +1381,9 @@ func (e *exchange) makeExtBidResponse(adapterBids map[openrtb_ext.BidderName]*en
 		bidResponseExt.Debug = &openrtb_ext.ExtResponseDebug{
 			HttpCalls:       make(map[openrtb_ext.BidderName][]*openrtb_ext.ExtHttpCall),
 			ResolvedRequest: r.ResolvedBidRequest,
+			ResolvedFPD:     r.FirstPartyDataResolution.Debug(),
+			ResolvedSizes:   r.SizeResolution.Debug(),
+			DynamicTmax:     r.DynamicTmax.Debug(),
 		}
 	}
 
@@ -1205,6 +1407,12 @@ func (e *exchange) makeExtBidResponse(adapterBids map[openrtb_ext.BidderName]*en
 		if debugInfo && len(responseExtra.HttpCalls) > 0 {
 			bidResponseExt.Debug.HttpCalls[bidderName] = responseExtra.HttpCalls
 		}
+		if debugInfo && len(responseExtra.ResolvedRequest) > 0 {
+			if bidResponseExt.Debug.ResolvedBidderRequests == nil {
+				bidResponseExt.Debug.ResolvedBidderRequests = make(map[openrtb_ext.BidderName]json.RawMessage, len(adapterExtra))
+			}
+			bidResponseExt.Debug.ResolvedBidderRequests[bidderName] = responseExtra.ResolvedRequest
+		}
 		if len(responseExtra.Warnings) > 0 {
 			bidResponseExt.Warnings[bidderName] = responseExtra.Warnings
 		}
@@ -1239,6 +1447,11 @@ func (e *exchange) makeSeatBid(adapterBid *entities.PbsOrtbSeatBid, adapter open
 	if len(errList) > 0 {
 		adapterExtra[adapter].Errors = append(adapterExtra[adapter].Errors, errsToBidderErrors(errList)...)
 	}
+	if rates := makeSeatBidCurrencyRates(adapterBid.Bids, seatBid.Bid); len(rates) > 0 {
+		if ext, err := jsonutil.Marshal(openrtb_ext.ExtSeatBid{Prebid: &openrtb_ext.ExtSeatBidPrebid{CurrencyRates: rates}}); err == nil {
+			seatBid.Ext = ext
+		}
+	}
 
 	return seatBid
 }
@@ -1250,8 +1463,9 @@ func (e *exchange) makeBid(bids []*entities.PbsOrtbBid, auc *auction, returnCrea
 	for _, bid := range bids {
 		if err := dsa.Validate(bidRequest, bid); err != nil {
 			dsaMessage := openrtb_ext.ExtBidderMessage{
-				Code:    errortypes.InvalidBidResponseDSAWarningCode,
-				Message: fmt.Sprintf("bid rejected: %s", err.Error()),
+				Code:     errortypes.InvalidBidResponseDSAWarningCode,
+				Category: errortypes.CategoryOf(errortypes.InvalidBidResponseDSAWarningCode),
+				Message:  fmt.Sprintf("bid rejected: %s", err.Error()),
 			}
 			bidResponseExt.Warnings[adapter] = append(bidResponseExt.Warnings[adapter], dsaMessage)
 
@@ -1288,6 +1502,7 @@ func (e *exchange) makeBid(bids []*entities.PbsOrtbBid, auc *auction, returnCrea
 			Video:             bid.BidVideo,
 			BidId:             bid.GeneratedBidID,
 			TargetBidderCode:  bid.TargetBidderCode,
+			LineItem:          bid.LineItem,
 		}
 
 		if cacheInfo, found := e.getBidCacheInfo(bid, auc); found {
@@ -1505,6 +1720,14 @@ func buildStoredAuctionResponse(storedAuctionResponses map[string]json.RawMessag
 	return adapterBids, fledge, liveAdapters, nil
 }
 
+// accountAdsCertExperiment turns Call Signs on for the auction if the account signs all of its requests.
+func accountAdsCertExperiment(experiment *openrtb_ext.Experiment, adsCert config.AccountAdsCert) *openrtb_ext.Experiment {
+	if !adsCert.Sign {
+		return experiment
+	}
+	return &openrtb_ext.Experiment{AdsCert: &openrtb_ext.AdsCert{Enabled: true}}
+}
+
 func isAdsCertEnabled(experiment *openrtb_ext.Experiment, info config.BidderInfo) bool {
 	requestAdsCertEnabled := experiment != nil && experiment.AdsCert != nil && experiment.AdsCert.Enabled
 	bidderAdsCertEnabled := info.Experiment.AdsCert.Enabled
@@ -1516,8 +1739,9 @@ func (e exchange) validateBannerCreativeSize(bid *entities.PbsOrtbBid, bidRespon
 		// Add error to debug array
 		errorMessage := setErrorMessageCreativeSize(validationType)
 		bidCreativeMaxSizeError := openrtb_ext.ExtBidderMessage{
-			Code:    errortypes.BadServerResponseErrorCode,
-			Message: errorMessage,
+			Code:     errortypes.BadServerResponseErrorCode,
+			Category: errortypes.CategoryOf(errortypes.BadServerResponseErrorCode),
+			Message:  errorMessage,
 		}
 		bidResponseExt.Errors[adapter] = append(bidResponseExt.Errors[adapter], bidCreativeMaxSizeError)
 
@@ -1537,8 +1761,9 @@ func (e exchange) validateBidAdM(bid *entities.PbsOrtbBid, bidResponseExt *openr
 		// Add error to debug array
 		errorMessage := setErrorMessageSecureMarkup(validationType)
 		bidSecureMarkupError := openrtb_ext.ExtBidderMessage{
-			Code:    errortypes.BadServerResponseErrorCode,
-			Message: errorMessage,
+			Code:     errortypes.BadServerResponseErrorCode,
+			Category: errortypes.CategoryOf(errortypes.BadServerResponseErrorCode),
+			Message:  errorMessage,
 		}
 		bidResponseExt.Errors[adapter] = append(bidResponseExt.Errors[adapter], bidSecureMarkupError)
 
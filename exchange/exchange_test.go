@@ -22,6 +22,7 @@ import (
 	"github.com/prebid/openrtb/v20/openrtb2"
 	"github.com/prebid/prebid-server/v2/adapters"
 	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/contentclassification"
 	"github.com/prebid/prebid-server/v2/currency"
 	"github.com/prebid/prebid-server/v2/errortypes"
 	"github.com/prebid/prebid-server/v2/exchange/entities"
@@ -42,6 +43,7 @@ import (
 	"github.com/prebid/prebid-server/v2/usersync"
 	"github.com/prebid/prebid-server/v2/util/jsonutil"
 	"github.com/prebid/prebid-server/v2/util/ptrutil"
+	"github.com/prebid/prebid-server/v2/util/uuidutil"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	jsonpatch "gopkg.in/evanphx/json-patch.v4"
@@ -82,7 +84,7 @@ func TestNewExchange(t *testing.T) {
 		},
 	}.Builder
 
-	e := NewExchange(adapters, nil, cfg, map[string]usersync.Syncer{}, &metricsConf.NilMetricsEngine{}, biddersInfo, gdprPermsBuilder, currencyConverter, nilCategoryFetcher{}, &adscert.NilSigner{}, macros.NewStringIndexBasedReplacer(), nil).(*exchange)
+	e := NewExchange(adapters, nil, cfg, map[string]usersync.Syncer{}, &metricsConf.NilMetricsEngine{}, biddersInfo, gdprPermsBuilder, currencyConverter, nilCategoryFetcher{}, &adscert.NilSigner{}, macros.NewStringIndexBasedReplacer(), nil, Dependencies{}).(*exchange)
 	for _, bidderName := range knownAdapters {
 		if _, ok := e.adapterMap[bidderName]; !ok {
 			if biddersInfo[string(bidderName)].IsEnabled() {
@@ -105,6 +107,44 @@ func TestNewExchange(t *testing.T) {
 //     sample request as specified in https://github.com/prebid/prebid-server/issues/465
 //  4. Build a BidResponse struct using exchange.buildBidResponse(ctx.Background(), liveA... )
 //  5. Assert we have no '&' characters in the response that exchange.buildBidResponse returns
+type fakeContentClassifier struct{}
+
+func (fakeContentClassifier) Classify(_ context.Context, _ contentclassification.Content) (*contentclassification.Classification, error) {
+	return &contentclassification.Classification{}, nil
+}
+
+func TestNewContentClassifier(t *testing.T) {
+	testCases := []struct {
+		description string
+		enabled     bool
+		classifier  contentclassification.Classifier
+		expectedNil bool
+	}{
+		{
+			description: "host-classifier",
+			enabled:     true,
+			classifier:  fakeContentClassifier{},
+		},
+		{
+			description: "disabled",
+			classifier:  fakeContentClassifier{},
+			expectedNil: true,
+		},
+		{
+			description: "no-classifier",
+			enabled:     true,
+			expectedNil: true,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			enricher := newContentClassifier(config.ContentClassification{Enabled: test.enabled}, test.classifier, &metricsConf.NilMetricsEngine{})
+			assert.Equal(t, test.expectedNil, enricher == nil)
+		})
+	}
+}
+
 func TestCharacterEscape(t *testing.T) {
 
 	// 1) Adapter with a '& char in its endpoint property
@@ -132,7 +172,7 @@ func TestCharacterEscape(t *testing.T) {
 		},
 	}.Builder
 
-	e := NewExchange(adapters, nil, cfg, map[string]usersync.Syncer{}, &metricsConf.NilMetricsEngine{}, biddersInfo, gdprPermsBuilder, currencyConverter, nilCategoryFetcher{}, &adscert.NilSigner{}, macros.NewStringIndexBasedReplacer(), nil).(*exchange)
+	e := NewExchange(adapters, nil, cfg, map[string]usersync.Syncer{}, &metricsConf.NilMetricsEngine{}, biddersInfo, gdprPermsBuilder, currencyConverter, nilCategoryFetcher{}, &adscert.NilSigner{}, macros.NewStringIndexBasedReplacer(), nil, Dependencies{}).(*exchange)
 
 	// 	3) Build all the parameters e.buildBidResponse(ctx.Background(), liveA... ) needs
 	//liveAdapters []openrtb_ext.BidderName,
@@ -806,7 +846,7 @@ func TestAdapterCurrency(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, "some-request-id", response.ID, "Response ID")
 	assert.Empty(t, response.SeatBid, "Response Bids")
-	assert.Contains(t, string(response.Ext), `"errors":{"appnexus":[{"code":5,"message":"The adapter failed to generate any bid requests, but also failed to generate an error explaining why"}]}`, "Response Ext")
+	assert.Contains(t, string(response.Ext), `"errors":{"appnexus":[{"code":5,"category":"adapter","message":"The adapter failed to generate any bid requests, but also failed to generate an error explaining why"}]}`, "Response Ext")
 
 	// Test Currency Converter Properly Passed To Adapter
 	if assert.NotNil(t, mockBidder.lastExtraRequestInfo, "Currency Conversion Argument") {
@@ -1234,7 +1274,7 @@ func TestGetBidCacheInfoEndToEnd(t *testing.T) {
 		},
 	}.Builder
 
-	e := NewExchange(adapters, pbc, cfg, map[string]usersync.Syncer{}, &metricsConf.NilMetricsEngine{}, biddersInfo, gdprPermsBuilder, currencyConverter, nilCategoryFetcher{}, &adscert.NilSigner{}, macros.NewStringIndexBasedReplacer(), nil).(*exchange)
+	e := NewExchange(adapters, pbc, cfg, map[string]usersync.Syncer{}, &metricsConf.NilMetricsEngine{}, biddersInfo, gdprPermsBuilder, currencyConverter, nilCategoryFetcher{}, &adscert.NilSigner{}, macros.NewStringIndexBasedReplacer(), nil, Dependencies{}).(*exchange)
 	// 	3) Build all the parameters e.buildBidResponse(ctx.Background(), liveA... ) needs
 	liveAdapters := []openrtb_ext.BidderName{bidderName}
 
@@ -1593,7 +1633,7 @@ func TestBidResponseCurrency(t *testing.T) {
 		},
 	}.Builder
 
-	e := NewExchange(adapters, nil, cfg, map[string]usersync.Syncer{}, &metricsConf.NilMetricsEngine{}, biddersInfo, gdprPermsBuilder, currencyConverter, nilCategoryFetcher{}, &adscert.NilSigner{}, macros.NewStringIndexBasedReplacer(), nil).(*exchange)
+	e := NewExchange(adapters, nil, cfg, map[string]usersync.Syncer{}, &metricsConf.NilMetricsEngine{}, biddersInfo, gdprPermsBuilder, currencyConverter, nilCategoryFetcher{}, &adscert.NilSigner{}, macros.NewStringIndexBasedReplacer(), nil, Dependencies{}).(*exchange)
 
 	liveAdapters := make([]openrtb_ext.BidderName, 1)
 	liveAdapters[0] = "appnexus"
@@ -1741,7 +1781,7 @@ func TestBidResponseImpExtInfo(t *testing.T) {
 		t.Fatalf("Error intializing adapters: %v", adaptersErr)
 	}
 
-	e := NewExchange(adapters, nil, cfg, map[string]usersync.Syncer{}, &metricsConf.NilMetricsEngine{}, nil, gdprPermsBuilder, nil, nilCategoryFetcher{}, &adscert.NilSigner{}, macros.NewStringIndexBasedReplacer(), nil).(*exchange)
+	e := NewExchange(adapters, nil, cfg, map[string]usersync.Syncer{}, &metricsConf.NilMetricsEngine{}, nil, gdprPermsBuilder, nil, nilCategoryFetcher{}, &adscert.NilSigner{}, macros.NewStringIndexBasedReplacer(), nil, Dependencies{}).(*exchange)
 
 	liveAdapters := make([]openrtb_ext.BidderName, 1)
 	liveAdapters[0] = "appnexus"
@@ -1835,7 +1875,7 @@ func TestRaceIntegration(t *testing.T) {
 		},
 	}.Builder
 
-	ex := NewExchange(adapters, &wellBehavedCache{}, cfg, map[string]usersync.Syncer{}, &metricsConf.NilMetricsEngine{}, biddersInfo, gdprPermsBuilder, currencyConverter, &nilCategoryFetcher{}, &adscert.NilSigner{}, macros.NewStringIndexBasedReplacer(), nil).(*exchange)
+	ex := NewExchange(adapters, &wellBehavedCache{}, cfg, map[string]usersync.Syncer{}, &metricsConf.NilMetricsEngine{}, biddersInfo, gdprPermsBuilder, currencyConverter, &nilCategoryFetcher{}, &adscert.NilSigner{}, macros.NewStringIndexBasedReplacer(), nil, Dependencies{}).(*exchange)
 	_, err = ex.HoldAuction(context.Background(), auctionRequest, &debugLog)
 	if err != nil {
 		t.Errorf("HoldAuction returned unexpected error: %v", err)
@@ -1933,7 +1973,7 @@ func TestPanicRecovery(t *testing.T) {
 		},
 	}.Builder
 
-	e := NewExchange(adapters, nil, cfg, map[string]usersync.Syncer{}, &metricsConf.NilMetricsEngine{}, biddersInfo, gdprPermsBuilder, currencyConverter, nilCategoryFetcher{}, &adscert.NilSigner{}, macros.NewStringIndexBasedReplacer(), nil).(*exchange)
+	e := NewExchange(adapters, nil, cfg, map[string]usersync.Syncer{}, &metricsConf.NilMetricsEngine{}, biddersInfo, gdprPermsBuilder, currencyConverter, nilCategoryFetcher{}, &adscert.NilSigner{}, macros.NewStringIndexBasedReplacer(), nil, Dependencies{}).(*exchange)
 
 	chBids := make(chan *bidResponseWrapper, 1)
 	panicker := func(bidderRequest BidderRequest, conversions currency.Conversions) {
@@ -1967,7 +2007,7 @@ func TestPanicRecovery(t *testing.T) {
 		},
 	}
 
-	recovered := e.recoverSafely(bidderRequests, panicker, chBids)
+	recovered := e.recoverSafely(context.Background(), bidderRequests, panicker, chBids)
 	recovered(bidderRequests[0], nil)
 }
 
@@ -2003,7 +2043,7 @@ func TestPanicRecoveryHighLevel(t *testing.T) {
 			allowAllBidders: true,
 		},
 	}.Builder
-	e := NewExchange(adapters, &mockCache{}, cfg, map[string]usersync.Syncer{}, &metricsConf.NilMetricsEngine{}, biddersInfo, gdprPermsBuilder, currencyConverter, categoriesFetcher, &adscert.NilSigner{}, macros.NewStringIndexBasedReplacer(), nil).(*exchange)
+	e := NewExchange(adapters, &mockCache{}, cfg, map[string]usersync.Syncer{}, &metricsConf.NilMetricsEngine{}, biddersInfo, gdprPermsBuilder, currencyConverter, categoriesFetcher, &adscert.NilSigner{}, macros.NewStringIndexBasedReplacer(), nil, Dependencies{}).(*exchange)
 
 	e.adapterMap[openrtb_ext.BidderBeachfront] = panicingAdapter{}
 	e.adapterMap[openrtb_ext.BidderAppnexus] = panicingAdapter{}
@@ -2457,6 +2497,24 @@ func newExchangeForTests(t *testing.T, filename string, expectations map[string]
 	}
 }
 
+func TestBidIDGeneratorDeterministicIDs(t *testing.T) {
+	newIDs := func(bidders ...string) map[string][]string {
+		generator := &bidIDGenerator{enabled: true, deterministicIDs: uuidutil.NewDeterministicGenerator(7)}
+		ids := make(map[string][]string)
+		for _, bidder := range bidders {
+			id, err := generator.New(bidder)
+			assert.NoError(t, err)
+			ids[bidder] = append(ids[bidder], id)
+		}
+		return ids
+	}
+
+	ids := newIDs("appnexus", "appnexus", "rubicon")
+	assert.Equal(t, ids, newIDs("rubicon", "appnexus", "appnexus"), "The IDs shouldn't depend on the order the bidders are given IDs in")
+	assert.NotEqual(t, ids["appnexus"][0], ids["appnexus"][1])
+	assert.NotEqual(t, ids["appnexus"][0], ids["rubicon"][0])
+}
+
 type fakeBidIDGenerator struct {
 	GenerateBidID bool `json:"generateBidID"`
 	ReturnError   bool `json:"returnError"`
@@ -4557,7 +4615,7 @@ func TestPassExperimentConfigsToHoldAuction(t *testing.T) {
 		},
 	}.Builder
 
-	e := NewExchange(adapters, nil, cfg, map[string]usersync.Syncer{}, &metricsConf.NilMetricsEngine{}, biddersInfo, gdprPermsBuilder, currencyConverter, nilCategoryFetcher{}, &signer, macros.NewStringIndexBasedReplacer(), nil).(*exchange)
+	e := NewExchange(adapters, nil, cfg, map[string]usersync.Syncer{}, &metricsConf.NilMetricsEngine{}, biddersInfo, gdprPermsBuilder, currencyConverter, nilCategoryFetcher{}, &signer, macros.NewStringIndexBasedReplacer(), nil, Dependencies{}).(*exchange)
 
 	// Define mock incoming bid requeset
 	mockBidRequest := &openrtb2.BidRequest{
@@ -4640,6 +4698,15 @@ func TestCallSignHeader(t *testing.T) {
 
 }
 
+func TestAccountAdsCertExperiment(t *testing.T) {
+	requestExperiment := &openrtb_ext.Experiment{AdsCert: &openrtb_ext.AdsCert{Enabled: false}}
+
+	assert.Same(t, requestExperiment, accountAdsCertExperiment(requestExperiment, config.AccountAdsCert{}))
+	assert.Nil(t, accountAdsCertExperiment(nil, config.AccountAdsCert{Verify: config.AdsCertVerifyEnforce}))
+	assert.Equal(t, &openrtb_ext.Experiment{AdsCert: &openrtb_ext.AdsCert{Enabled: true}}, accountAdsCertExperiment(requestExperiment, config.AccountAdsCert{Sign: true}))
+	assert.Equal(t, &openrtb_ext.Experiment{AdsCert: &openrtb_ext.AdsCert{Enabled: true}}, accountAdsCertExperiment(nil, config.AccountAdsCert{Sign: true}))
+}
+
 func TestValidateBannerCreativeSize(t *testing.T) {
 	exchange := exchange{bidValidationEnforcement: config.Validations{MaxCreativeWidth: 100, MaxCreativeHeight: 100},
 		me: metricsConf.NewMetricsEngine(&config.Configuration{}, openrtb_ext.CoreBidderNames(), nil, nil),
@@ -5209,8 +5276,9 @@ func TestGetAllBids(t *testing.T) {
 					"pubmatic": {
 						Warnings: []openrtb_ext.ExtBidderMessage{
 							{
-								Code:    errortypes.AlternateBidderCodeWarningCode,
-								Message: `alternateBidderCodes disabled for "pubmatic", rejecting bids for "groupm"`,
+								Code:     errortypes.AlternateBidderCodeWarningCode,
+								Category: errortypes.CategoryAdapter,
+								Message:  `alternateBidderCodes disabled for "pubmatic", rejecting bids for "groupm"`,
 							},
 						},
 					},
@@ -5445,7 +5513,7 @@ func TestGetAllBids(t *testing.T) {
 
 			adapterBids, adapterExtra, extraRespInfo := e.getAllBids(context.Background(), test.in.bidderRequests, test.in.bidAdjustments,
 				test.in.conversions, test.in.accountDebugAllowed, test.in.globalPrivacyControlHeader, test.in.headerDebugAllowed, test.in.alternateBidderCodes, test.in.experiment,
-				test.in.hookExecutor, test.in.pbsRequestStartTime, test.in.bidAdjustmentRules, test.in.tmaxAdjustments, false)
+				test.in.hookExecutor, test.in.pbsRequestStartTime, test.in.bidAdjustmentRules, test.in.tmaxAdjustments, false, nil, false)
 
 			assert.Equalf(t, test.expected.extraRespInfo.bidsFound, extraRespInfo.bidsFound, "extraRespInfo.bidsFound mismatch")
 			assert.Equalf(t, test.expected.adapterBids, adapterBids, "adapterBids mismatch")
@@ -6301,3 +6369,29 @@ func TestBidsToUpdate(t *testing.T) {
 		})
 	}
 }
+
+func TestErrsToBidderMessagesWithPosition(t *testing.T) {
+	position := &errortypes.JSONPosition{Path: "seatbid[0].bid[0].price", Offset: 57, Expected: "number"}
+	errs := []error{
+		&errortypes.FailedToUnmarshal{Message: "cannot unmarshal openrtb2.Bid.Price: unexpected character", Position: position},
+		&errortypes.FailedToUnmarshal{Message: "unknown position"},
+		&errortypes.Warning{Message: "warning", WarningCode: errortypes.UnknownWarningCode},
+	}
+
+	assert.Equal(t, []openrtb_ext.ExtBidderMessage{
+		{
+			Code:     errortypes.FailedToUnmarshalErrorCode,
+			Category: errortypes.CategoryValidation,
+			Message:  "cannot unmarshal openrtb2.Bid.Price: unexpected character",
+			Position: &openrtb_ext.ExtMessagePosition{Path: "seatbid[0].bid[0].price", Offset: 57, Expected: "number"},
+		},
+		{
+			Code:     errortypes.FailedToUnmarshalErrorCode,
+			Category: errortypes.CategoryValidation,
+			Message:  "unknown position",
+		},
+	}, errsToBidderErrors(errs))
+	assert.Equal(t, []openrtb_ext.ExtBidderMessage{
+		{Code: errortypes.UnknownWarningCode, Category: errortypes.CategoryUnknown, Message: "warning"},
+	}, errsToBidderWarnings(errs))
+}
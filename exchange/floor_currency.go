@@ -0,0 +1,61 @@
+package exchange
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/currency"
+	"github.com/prebid/prebid-server/v2/errortypes"
+	"github.com/prebid/prebid-server/v2/metrics"
+)
+
+// defaultFloorCurrency is the currency of an imp's floor if it doesn't have a bidfloorcur, as OpenRTB defines it.
+const defaultFloorCurrency = "USD"
+
+// floorCurrency returns the currency the bidder takes floors in, or "" if its info doesn't name one. An alias
+// without a currency of its own takes floors in the currency of the bidder it's an alias of.
+func floorCurrency(infos config.BidderInfos, bidderRequest BidderRequest) string {
+	for _, name := range []string{bidderRequest.BidderName.String(), bidderRequest.BidderCoreName.String()} {
+		if info, ok := infos[name]; ok && info.OpenRTB != nil && info.OpenRTB.Currency != "" {
+			return info.OpenRTB.Currency
+		}
+	}
+	return ""
+}
+
+// convertBidderFloors converts the floors of the imps sent to a bidder to the currency it takes them in. The
+// imps are the bidder's own copies, so the floors the bids are enforced against aren't changed. A floor which
+// can't be converted is sent as it is, with a warning, rather than dropped.
+func convertBidderFloors(bidderRequest BidderRequest, bidderCurrency string, conversions currency.Conversions, me metrics.MetricsEngine) []error {
+	var warnings []error
+	for i := range bidderRequest.BidRequest.Imp {
+		imp := &bidderRequest.BidRequest.Imp[i]
+		if imp.BidFloor <= 0 {
+			continue
+		}
+		from := imp.BidFloorCur
+		if from == "" {
+			from = defaultFloorCurrency
+		}
+		if strings.EqualFold(from, bidderCurrency) {
+			continue
+		}
+
+		rate, err := conversions.GetRate(from, bidderCurrency)
+		if err != nil {
+			me.RecordAdapterFloorConversion(bidderRequest.BidderName, false)
+			warnings = append(warnings, &errortypes.Warning{
+				Message:     fmt.Sprintf("imp %s: unable to convert the floor from %s to %s: %v", imp.ID, from, bidderCurrency, err),
+				WarningCode: errortypes.FloorCurrencyConversionWarningCode,
+			})
+			continue
+		}
+		me.RecordAdapterFloorConversion(bidderRequest.BidderName, true)
+		// floors are rounded to four decimals, as the floors module rounds them
+		imp.BidFloor = math.Round(imp.BidFloor*rate*10000) / 10000
+		imp.BidFloorCur = bidderCurrency
+	}
+	return warnings
+}
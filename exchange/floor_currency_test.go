@@ -0,0 +1,75 @@
+package exchange
+
+import (
+	"testing"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/currency"
+	"github.com/prebid/prebid-server/v2/errortypes"
+	"github.com/prebid/prebid-server/v2/metrics"
+	"github.com/prebid/prebid-server/v2/openrtb_ext"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFloorCurrency(t *testing.T) {
+	infos := config.BidderInfos{
+		"appnexus": {OpenRTB: &config.OpenRTBInfo{Currency: "EUR"}},
+		"alias":    {OpenRTB: &config.OpenRTBInfo{Currency: "GBP"}},
+		"rubicon":  {OpenRTB: &config.OpenRTBInfo{}},
+	}
+
+	testCases := []struct {
+		description string
+		bidder      openrtb_ext.BidderName
+		coreBidder  openrtb_ext.BidderName
+		expected    string
+	}{
+		{description: "bidder", bidder: "appnexus", coreBidder: "appnexus", expected: "EUR"},
+		{description: "alias_with_currency", bidder: "alias", coreBidder: "appnexus", expected: "GBP"},
+		{description: "alias_without_currency", bidder: "other", coreBidder: "appnexus", expected: "EUR"},
+		{description: "no_currency", bidder: "rubicon", coreBidder: "rubicon", expected: ""},
+		{description: "no_info", bidder: "unknown", coreBidder: "unknown", expected: ""},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			bidderRequest := BidderRequest{BidderName: test.bidder, BidderCoreName: test.coreBidder}
+			assert.Equal(t, test.expected, floorCurrency(infos, bidderRequest))
+		})
+	}
+}
+
+func TestConvertBidderFloors(t *testing.T) {
+	conversions := currency.NewRates(map[string]map[string]float64{"USD": {"EUR": 0.9}, "GBP": {"EUR": 1.17}})
+	bidderRequest := BidderRequest{
+		BidderName: openrtb_ext.BidderAppnexus,
+		BidRequest: &openrtb2.BidRequest{Imp: []openrtb2.Imp{
+			{ID: "usd", BidFloor: 1.5, BidFloorCur: "USD"},
+			{ID: "default", BidFloor: 2},
+			{ID: "gbp", BidFloor: 1, BidFloorCur: "GBP"},
+			{ID: "eur", BidFloor: 1, BidFloorCur: "eur"},
+			{ID: "none", BidFloorCur: "USD"},
+			{ID: "jpy", BidFloor: 100, BidFloorCur: "JPY"},
+		}},
+	}
+	metricsEngine := &metrics.MetricsEngineMock{}
+	metricsEngine.On("RecordAdapterFloorConversion", openrtb_ext.BidderAppnexus, true).Times(3)
+	metricsEngine.On("RecordAdapterFloorConversion", openrtb_ext.BidderAppnexus, false).Once()
+
+	warnings := convertBidderFloors(bidderRequest, "EUR", conversions, metricsEngine)
+
+	assert.Equal(t, []openrtb2.Imp{
+		{ID: "usd", BidFloor: 1.35, BidFloorCur: "EUR"},
+		{ID: "default", BidFloor: 1.8, BidFloorCur: "EUR"},
+		{ID: "gbp", BidFloor: 1.17, BidFloorCur: "EUR"},
+		{ID: "eur", BidFloor: 1, BidFloorCur: "eur"},
+		{ID: "none", BidFloorCur: "USD"},
+		{ID: "jpy", BidFloor: 100, BidFloorCur: "JPY"},
+	}, bidderRequest.BidRequest.Imp)
+	assert.Equal(t, []error{&errortypes.Warning{
+		Message:     "imp jpy: unable to convert the floor from JPY to EUR: Currency conversion rate not found: 'JPY' => 'EUR'",
+		WarningCode: errortypes.FloorCurrencyConversionWarningCode,
+	}}, warnings)
+	metricsEngine.AssertExpectations(t)
+}
@@ -0,0 +1,43 @@
+package exchange
+
+import (
+	"net/http"
+	"net/url"
+
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/exchange/inprocess"
+	"github.com/prebid/prebid-server/v2/logger"
+)
+
+// NewInProcessBidderClient returns a client for the bidder adapters which answers the calls to the
+// configured bidders with an in-process mock bidder, and sends the rest with the given client. It returns
+// the given client if in-process bidders are disabled.
+func NewInProcessBidderClient(client *http.Client, cfg config.InProcessBidders, bidderInfos config.BidderInfos) *http.Client {
+	if !cfg.Enabled {
+		return client
+	}
+
+	fallback := client.Transport
+	if fallback == nil {
+		fallback = http.DefaultTransport
+	}
+	transport := inprocess.NewTransport(fallback)
+	mock := inprocess.MockBidder{Price: cfg.BidPrice}
+	for _, bidder := range cfg.Bidders {
+		info, ok := bidderInfos[bidder]
+		if !ok || !info.IsEnabled() {
+			continue
+		}
+		origin, err := endpointOrigin(info.Endpoint)
+		if err != nil {
+			logger.Warningf("Not answering calls to bidder %s in process: %v", bidder, err)
+			continue
+		}
+		endpoint, _ := url.Parse(origin)
+		transport.Handle(endpoint.Host, mock)
+	}
+
+	inProcessClient := *client
+	inProcessClient.Transport = transport
+	return &inProcessClient
+}
@@ -0,0 +1,61 @@
+package exchange
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/util/jsonutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewInProcessBidderClient(t *testing.T) {
+	network := &http.Client{Transport: networkTripper{}}
+
+	bidderInfos := config.BidderInfos{
+		"mocked":   config.BidderInfo{Endpoint: "https://mocked.com/openrtb2?src=prebid"},
+		"macro":    config.BidderInfo{Endpoint: "https://{{.Host}}/openrtb2"},
+		"disabled": config.BidderInfo{Endpoint: "https://disabled.com/openrtb2", Disabled: true},
+	}
+	cfg := config.InProcessBidders{Enabled: true, Bidders: []string{"mocked", "macro", "disabled"}, BidPrice: 2.5}
+	client := NewInProcessBidderClient(network, cfg, bidderInfos)
+	require.NotSame(t, network, client)
+
+	resp, err := client.Post("https://mocked.com/openrtb2?src=prebid", "application/json", strings.NewReader(`{"id":"req","imp":[{"id":"imp1","banner":{"format":[{"w":300,"h":250}]}}]}`))
+	require.NoError(t, err)
+	var bidResponse openrtb2.BidResponse
+	require.NoError(t, jsonutil.UnmarshalValid(readAll(t, resp), &bidResponse))
+	require.Len(t, bidResponse.SeatBid, 1)
+	require.Len(t, bidResponse.SeatBid[0].Bid, 1)
+	assert.Equal(t, 2.5, bidResponse.SeatBid[0].Bid[0].Price)
+
+	for _, endpoint := range []string{"https://disabled.com/openrtb2", "https://other.com/openrtb2"} {
+		resp, err = client.Get(endpoint)
+		require.NoError(t, err)
+		assert.Equal(t, "from the network", string(readAll(t, resp)), "%s should be called over the network", endpoint)
+	}
+}
+
+func TestNewInProcessBidderClientDisabled(t *testing.T) {
+	client := &http.Client{}
+	assert.Same(t, client, NewInProcessBidderClient(client, config.InProcessBidders{Enabled: false}, config.BidderInfos{}))
+}
+
+func readAll(t *testing.T, resp *http.Response) []byte {
+	t.Helper()
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return body
+}
+
+// networkTripper stands in for calls made over the network.
+type networkTripper struct{}
+
+func (networkTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader("from the network")), Request: req}, nil
+}
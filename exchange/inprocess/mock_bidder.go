@@ -0,0 +1,88 @@
+package inprocess
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+)
+
+// MockBidder is an OpenRTB bidder which bids on every imp of the requests it gets. Its responses depend on
+// nothing but the request, so auctions run against it are reproducible.
+type MockBidder struct {
+	// Price is the price of every bid.
+	Price float64
+}
+
+// ServeHTTP implements http.Handler. It responds as Respond does, or with 204 if the request has no imps.
+func (b MockBidder) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var request openrtb2.BidRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	response := b.Respond(&request)
+	if response == nil {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// Respond returns the response to the request, with one bid per imp, sized to the first banner format of
+// the imp if it has one. It returns nil if the request has no imps.
+func (b MockBidder) Respond(request *openrtb2.BidRequest) *openrtb2.BidResponse {
+	if len(request.Imp) == 0 {
+		return nil
+	}
+
+	bids := make([]openrtb2.Bid, 0, len(request.Imp))
+	for i, imp := range request.Imp {
+		bids = append(bids, b.bid(imp, i))
+	}
+	return &openrtb2.BidResponse{
+		ID:      request.ID,
+		SeatBid: []openrtb2.SeatBid{{Bid: bids}},
+		Cur:     "USD",
+	}
+}
+
+func (b MockBidder) bid(imp openrtb2.Imp, index int) openrtb2.Bid {
+	bid := openrtb2.Bid{
+		ID:      "mock-bid-" + strconv.Itoa(index),
+		ImpID:   imp.ID,
+		Price:   b.Price,
+		CrID:    "mock-creative",
+		ADomain: []string{"mock-advertiser.com"},
+	}
+
+	switch {
+	case imp.Banner != nil:
+		bid.MType = openrtb2.MarkupBanner
+		bid.AdM = "<div>mock ad</div>"
+		if len(imp.Banner.Format) > 0 {
+			bid.W = imp.Banner.Format[0].W
+			bid.H = imp.Banner.Format[0].H
+		} else if imp.Banner.W != nil && imp.Banner.H != nil {
+			bid.W = *imp.Banner.W
+			bid.H = *imp.Banner.H
+		}
+	case imp.Video != nil:
+		bid.MType = openrtb2.MarkupVideo
+		bid.AdM = `<VAST version="3.0"></VAST>`
+		if imp.Video.W != nil && imp.Video.H != nil {
+			bid.W = *imp.Video.W
+			bid.H = *imp.Video.H
+		}
+	case imp.Native != nil:
+		bid.MType = openrtb2.MarkupNative
+		bid.AdM = `{"assets":[]}`
+	case imp.Audio != nil:
+		bid.MType = openrtb2.MarkupAudio
+		bid.AdM = `<VAST version="3.0"></VAST>`
+	}
+	return bid
+}
@@ -0,0 +1,78 @@
+package inprocess
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v2/util/ptrutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMockBidder(t *testing.T) {
+	request := openrtb2.BidRequest{
+		ID: "req",
+		Imp: []openrtb2.Imp{
+			{ID: "banner", Banner: &openrtb2.Banner{Format: []openrtb2.Format{{W: 300, H: 250}, {W: 728, H: 90}}}},
+			{ID: "video", Video: &openrtb2.Video{W: ptrutil.ToPtr[int64](640), H: ptrutil.ToPtr[int64](480)}},
+			{ID: "native", Native: &openrtb2.Native{Request: "{}"}},
+		},
+	}
+	body, err := json.Marshal(request)
+	require.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	MockBidder{Price: 1.5}.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(string(body))))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var response openrtb2.BidResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "req", response.ID)
+	assert.Equal(t, "USD", response.Cur)
+	require.Len(t, response.SeatBid, 1)
+	bids := response.SeatBid[0].Bid
+	require.Len(t, bids, 3)
+
+	for i, imp := range request.Imp {
+		assert.Equal(t, imp.ID, bids[i].ImpID)
+		assert.Equal(t, 1.5, bids[i].Price)
+		assert.NotEmpty(t, bids[i].AdM)
+		assert.NotEmpty(t, bids[i].CrID)
+	}
+	assert.Equal(t, openrtb2.MarkupBanner, bids[0].MType)
+	assert.Equal(t, [2]int64{300, 250}, [2]int64{bids[0].W, bids[0].H})
+	assert.Equal(t, openrtb2.MarkupVideo, bids[1].MType)
+	assert.Equal(t, [2]int64{640, 480}, [2]int64{bids[1].W, bids[1].H})
+	assert.Equal(t, openrtb2.MarkupNative, bids[2].MType)
+}
+
+func TestMockBidderNoBid(t *testing.T) {
+	testCases := []struct {
+		name         string
+		body         string
+		expectedCode int
+	}{
+		{
+			name:         "no-imps",
+			body:         `{"id":"req"}`,
+			expectedCode: http.StatusNoContent,
+		},
+		{
+			name:         "malformed",
+			body:         `{"id":`,
+			expectedCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			MockBidder{Price: 1}.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(test.body)))
+			assert.Equal(t, test.expectedCode, w.Code)
+		})
+	}
+}
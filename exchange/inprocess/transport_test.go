@@ -0,0 +1,82 @@
+package inprocess
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransportRoundTrip(t *testing.T) {
+	transport := NewTransport(nil)
+	transport.Handle("bidder.com", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		assert.Equal(t, "/openrtb2?src=prebid", r.RequestURI)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		w.Header().Set("X-Echo", "yes")
+		w.WriteHeader(http.StatusCreated)
+		w.Write(body)
+	}))
+	client := &http.Client{Transport: transport}
+
+	resp, err := client.Post("https://bidder.com/openrtb2?src=prebid", "application/json", strings.NewReader(`{"id":"1"}`))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+	assert.Equal(t, "201 Created", resp.Status)
+	assert.Equal(t, "yes", resp.Header.Get("X-Echo"))
+	assert.Equal(t, int64(10), resp.ContentLength)
+	assert.Equal(t, `{"id":"1"}`, string(body))
+}
+
+func TestTransportDefaultsToOK(t *testing.T) {
+	transport := NewTransport(nil)
+	transport.Handle("bidder.com:8080", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	resp, err := transport.RoundTrip(newRequest(t, context.Background(), "http://bidder.com:8080/"))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, int64(0), resp.ContentLength)
+}
+
+func TestTransportUnknownHost(t *testing.T) {
+	_, err := NewTransport(nil).RoundTrip(newRequest(t, context.Background(), "https://unknown.com/"))
+	assert.EqualError(t, err, "no in-process handler for host unknown.com")
+
+	fallback := NewTransport(nil)
+	fallback.Handle("unknown.com", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	resp, err := NewTransport(fallback).RoundTrip(newRequest(t, context.Background(), "https://unknown.com/"))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode, "unknown hosts should go to the fallback")
+}
+
+func TestTransportContextDone(t *testing.T) {
+	called := false
+	transport := NewTransport(nil)
+	transport.Handle("bidder.com", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := transport.RoundTrip(newRequest(t, ctx, "https://bidder.com/"))
+	assert.True(t, errors.Is(err, context.Canceled))
+	assert.False(t, called, "the handler should not be called once the request is canceled")
+}
+
+func newRequest(t *testing.T, ctx context.Context, url string) *http.Request {
+	t.Helper()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	require.NoError(t, err)
+	return req
+}
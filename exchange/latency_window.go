@@ -0,0 +1,66 @@
+package exchange
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+// latencyWindow keeps a bidder's recent response times, and a percentile of them which is updated every
+// interval observations. The percentile isn't known until the first update, so it's never taken from fewer
+// response times than the interval.
+type latencyWindow struct {
+	percentile float64
+	size       int
+	interval   int
+
+	mutex    sync.Mutex
+	samples  []time.Duration
+	observed int
+	value    time.Duration
+	known    bool
+}
+
+func newLatencyWindow(percentile float64, size, interval int) *latencyWindow {
+	return &latencyWindow{
+		percentile: percentile,
+		size:       size,
+		interval:   interval,
+		samples:    make([]time.Duration, 0, size),
+	}
+}
+
+// observe adds a response time, in place of the oldest once the window is full.
+func (w *latencyWindow) observe(responseTime time.Duration) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	if len(w.samples) < w.size {
+		w.samples = append(w.samples, responseTime)
+	} else {
+		w.samples[w.observed%w.size] = responseTime
+	}
+	w.observed++
+	if w.observed%w.interval == 0 {
+		w.value = w.compute()
+		w.known = true
+	}
+}
+
+// get returns the percentile of the response times, or false if it isn't known yet.
+func (w *latencyWindow) get() (time.Duration, bool) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	return w.value, w.known
+}
+
+func (w *latencyWindow) compute() time.Duration {
+	sorted := make([]time.Duration, len(w.samples))
+	copy(sorted, w.samples)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	index := int(math.Ceil(w.percentile/100*float64(len(sorted)))) - 1
+	if index < 0 {
+		index = 0
+	}
+	return sorted[index]
+}
@@ -0,0 +1,75 @@
+package exchange
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/exchange/entities"
+	"github.com/prebid/prebid-server/v2/openrtb_ext"
+)
+
+// matchLineItems tags each bid with the first of the account's line items, in order of priority, whose
+// targeting it matches. Line items of the same priority are tried in the order they're configured.
+func matchLineItems(req *openrtb2.BidRequest, adapterBids map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid, lineItems config.AccountLineItems) {
+	if !lineItems.Enabled || len(lineItems.Items) == 0 {
+		return
+	}
+	items := make([]config.AccountLineItem, len(lineItems.Items))
+	copy(items, lineItems.Items)
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].Priority < items[j].Priority
+	})
+
+	var country string
+	if req.Device != nil && req.Device.Geo != nil {
+		country = req.Device.Geo.Country
+	}
+
+	for bidderName, seatBid := range adapterBids {
+		if seatBid == nil {
+			continue
+		}
+		for _, pbsBid := range seatBid.Bids {
+			for i := range items {
+				if matchesLineItem(&items[i], pbsBid.Bid, bidderName, country) {
+					pbsBid.LineItem = &openrtb_ext.ExtBidPrebidLineItem{
+						ID:       items[i].ID,
+						Name:     items[i].Name,
+						Priority: items[i].Priority,
+					}
+					break
+				}
+			}
+		}
+	}
+}
+
+// matchesLineItem reports whether the bid matches each of the line item's targeting lists which isn't empty.
+func matchesLineItem(item *config.AccountLineItem, bid *openrtb2.Bid, bidderName openrtb_ext.BidderName, country string) bool {
+	return listHas(item.Bidders, bidderName.String(), strings.EqualFold) &&
+		listHas(item.DealIDs, bid.DealID, equalStrings) &&
+		listHas(item.Sizes, makeHbSize(bid), equalStrings) &&
+		listHas(item.Countries, country, strings.EqualFold)
+}
+
+// listHas reports whether the list is empty, so targets everything, or has the value.
+func listHas(list []string, value string, equal func(a, b string) bool) bool {
+	if len(list) == 0 {
+		return true
+	}
+	if value == "" {
+		return false
+	}
+	for _, item := range list {
+		if equal(item, value) {
+			return true
+		}
+	}
+	return false
+}
+
+func equalStrings(a, b string) bool {
+	return a == b
+}
@@ -0,0 +1,103 @@
+package exchange
+
+import (
+	"testing"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/exchange/entities"
+	"github.com/prebid/prebid-server/v2/openrtb_ext"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchLineItems(t *testing.T) {
+	lineItems := config.AccountLineItems{
+		Enabled: true,
+		Items: []config.AccountLineItem{
+			{ID: "li-house", Name: "House", Priority: 10, Bidders: []string{"appnexus", "rubicon"}},
+			{ID: "li-sponsorship", Name: "Sponsorship", Priority: 1, DealIDs: []string{"deal-1"}, Sizes: []string{"300x250"}, Countries: []string{"USA"}},
+			{ID: "li-standard", Priority: 5, Bidders: []string{"AppNexus"}, Sizes: []string{"728x90"}},
+			{ID: "li-standard-2", Priority: 5, Bidders: []string{"appnexus"}},
+		},
+	}
+	usaRequest := &openrtb2.BidRequest{Device: &openrtb2.Device{Geo: &openrtb2.Geo{Country: "USA"}}}
+
+	tests := []struct {
+		description      string
+		req              *openrtb2.BidRequest
+		lineItems        config.AccountLineItems
+		bidder           openrtb_ext.BidderName
+		bid              *openrtb2.Bid
+		expectedLineItem *openrtb_ext.ExtBidPrebidLineItem
+	}{
+		{
+			description:      "highest priority match",
+			req:              usaRequest,
+			lineItems:        lineItems,
+			bidder:           openrtb_ext.BidderRubicon,
+			bid:              &openrtb2.Bid{DealID: "deal-1", W: 300, H: 250},
+			expectedLineItem: &openrtb_ext.ExtBidPrebidLineItem{ID: "li-sponsorship", Name: "Sponsorship", Priority: 1},
+		},
+		{
+			description:      "other country",
+			req:              &openrtb2.BidRequest{Device: &openrtb2.Device{Geo: &openrtb2.Geo{Country: "CAN"}}},
+			lineItems:        lineItems,
+			bidder:           openrtb_ext.BidderRubicon,
+			bid:              &openrtb2.Bid{DealID: "deal-1", W: 300, H: 250},
+			expectedLineItem: &openrtb_ext.ExtBidPrebidLineItem{ID: "li-house", Name: "House", Priority: 10},
+		},
+		{
+			description:      "no device",
+			req:              &openrtb2.BidRequest{},
+			lineItems:        lineItems,
+			bidder:           openrtb_ext.BidderRubicon,
+			bid:              &openrtb2.Bid{DealID: "deal-1", W: 300, H: 250},
+			expectedLineItem: &openrtb_ext.ExtBidPrebidLineItem{ID: "li-house", Name: "House", Priority: 10},
+		},
+		{
+			description:      "bidder matches case insensitively",
+			req:              usaRequest,
+			lineItems:        lineItems,
+			bidder:           openrtb_ext.BidderAppnexus,
+			bid:              &openrtb2.Bid{W: 728, H: 90},
+			expectedLineItem: &openrtb_ext.ExtBidPrebidLineItem{ID: "li-standard", Priority: 5},
+		},
+		{
+			description:      "same priority in configured order",
+			req:              usaRequest,
+			lineItems:        lineItems,
+			bidder:           openrtb_ext.BidderAppnexus,
+			bid:              &openrtb2.Bid{},
+			expectedLineItem: &openrtb_ext.ExtBidPrebidLineItem{ID: "li-standard-2", Priority: 5},
+		},
+		{
+			description: "no match",
+			req:         usaRequest,
+			lineItems:   lineItems,
+			bidder:      openrtb_ext.BidderPubmatic,
+			bid:         &openrtb2.Bid{DealID: "deal-2", W: 300, H: 250},
+		},
+		{
+			description: "disabled",
+			req:         usaRequest,
+			lineItems:   config.AccountLineItems{Items: lineItems.Items},
+			bidder:      openrtb_ext.BidderRubicon,
+			bid:         &openrtb2.Bid{DealID: "deal-1", W: 300, H: 250},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			pbsBid := &entities.PbsOrtbBid{Bid: test.bid}
+			adapterBids := map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid{
+				test.bidder: {Bids: []*entities.PbsOrtbBid{pbsBid}},
+				"empty":     nil,
+			}
+
+			matchLineItems(test.req, adapterBids, test.lineItems)
+
+			assert.Equal(t, test.expectedLineItem, pbsBid.LineItem)
+		})
+	}
+	assert.Equal(t, "li-house", lineItems.Items[0].ID, "the account's line items keep their order")
+}
@@ -0,0 +1,24 @@
+package exchange
+
+import (
+	"context"
+
+	"github.com/prebid/prebid-server/v2/metering"
+)
+
+// meterUsage counts the auction, its imps and the requests made to bidders for it, for the account's billing
+// and quotas. Simulated auctions aren't counted, and auctions answered with stored auction responses don't
+// call any bidders.
+func meterUsage(ctx context.Context, meter *metering.Meter, r *AuctionRequest, bidderRequests []BidderRequest) {
+	if meter == nil || r.SimulatedResponses != nil {
+		return
+	}
+	usage := metering.Usage{
+		Auctions: 1,
+		Imps:     int64(len(r.BidRequestWrapper.Imp)),
+	}
+	if len(r.StoredAuctionResponses) == 0 {
+		usage.BidderCalls = int64(len(bidderRequests))
+	}
+	meter.Record(ctx, &r.Account, usage)
+}
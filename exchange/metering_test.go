@@ -0,0 +1,73 @@
+package exchange
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/errortypes"
+	"github.com/prebid/prebid-server/v2/metering"
+	"github.com/prebid/prebid-server/v2/metrics"
+	"github.com/prebid/prebid-server/v2/openrtb_ext"
+	"github.com/prebid/prebid-server/v2/ratelimit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestMeterUsage(t *testing.T) {
+	quota := config.AccountUsageQuota{Period: config.UsageQuotaPeriodDay, MaxBidderCalls: 2}
+	bidderRequests := []BidderRequest{{BidderName: "appnexus"}, {BidderName: "rubicon"}}
+
+	tests := []struct {
+		description      string
+		request          AuctionRequest
+		expectedQuotaHit bool
+	}{
+		{
+			description:      "auction",
+			request:          AuctionRequest{},
+			expectedQuotaHit: true,
+		},
+		{
+			description: "simulated-auction",
+			request:     AuctionRequest{SimulatedResponses: map[string]json.RawMessage{}},
+		},
+		{
+			description: "stored-auction-responses",
+			request:     AuctionRequest{StoredAuctionResponses: map[string]json.RawMessage{"imp1": json.RawMessage(`[]`)}},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			me := &metrics.MetricsEngineMock{}
+			me.On("RecordRateLimit", metrics.RateLimitUsageQuota, mock.Anything).Return()
+			meter := metering.NewMeter(config.Metering{}, ratelimit.NewLocalLimiter(me), http.DefaultClient)
+			r := test.request
+			r.Account = config.Account{ID: "1001", UsageQuota: quota}
+			r.BidRequestWrapper = &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{Imp: []openrtb2.Imp{{ID: "imp1"}}}}
+
+			meterUsage(context.Background(), meter, &r, bidderRequests)
+
+			err := meter.CheckQuota(context.Background(), &r.Account)
+			assert.Equal(t, test.expectedQuotaHit, err != nil)
+		})
+	}
+}
+
+func TestHoldAuctionOverUsageQuota(t *testing.T) {
+	me := &metrics.MetricsEngineMock{}
+	me.On("RecordRateLimit", metrics.RateLimitUsageQuota, mock.Anything).Return()
+	meter := metering.NewMeter(config.Metering{}, ratelimit.NewLocalLimiter(me), http.DefaultClient)
+	account := config.Account{ID: "1001", UsageQuota: config.AccountUsageQuota{Period: config.UsageQuotaPeriodHour, MaxAuctions: 1}}
+	meter.Record(context.Background(), &account, metering.Usage{Auctions: 1})
+	e := &exchange{meter: meter}
+
+	response, err := e.HoldAuction(context.Background(), &AuctionRequest{Account: account}, nil)
+
+	assert.Nil(t, response)
+	assert.EqualError(t, err, "Account 1001 has used up its quota of 1 auctions for the hour")
+	assert.Equal(t, errortypes.RateLimitedErrorCode, errortypes.ReadCode(err))
+}
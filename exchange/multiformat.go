@@ -0,0 +1,156 @@
+package exchange
+
+import (
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/openrtb_ext"
+)
+
+// defaultMediaTypes is the order of the media types an account's preference leaves out.
+var defaultMediaTypes = []openrtb_ext.BidType{
+	openrtb_ext.BidTypeBanner,
+	openrtb_ext.BidTypeVideo,
+	openrtb_ext.BidTypeNative,
+	openrtb_ext.BidTypeAudio,
+}
+
+// mediaTypePreference ranks the media types, from 0 for the most preferred.
+type mediaTypePreference map[openrtb_ext.BidType]int
+
+func newMediaTypePreference(preferred []string) mediaTypePreference {
+	preference := make(mediaTypePreference, len(defaultMediaTypes))
+	add := func(mediaType openrtb_ext.BidType) {
+		if _, ok := preference[mediaType]; !ok {
+			preference[mediaType] = len(preference)
+		}
+	}
+	for _, mediaType := range preferred {
+		add(openrtb_ext.BidType(mediaType))
+	}
+	for _, mediaType := range defaultMediaTypes {
+		add(mediaType)
+	}
+	return preference
+}
+
+// prefers returns whether a is preferred to b. It's false for a nil preference.
+func (p mediaTypePreference) prefers(a, b openrtb_ext.BidType) bool {
+	rankA, okA := p[a]
+	rankB, okB := p[b]
+	return okA && (!okB || rankA < rankB)
+}
+
+// auctionMediaTypePreference returns the preference which breaks ties between bids for the same imp, or nil
+// if the account hasn't set one.
+func auctionMediaTypePreference(account config.AccountMultiformat) mediaTypePreference {
+	if len(account.PreferredMediaTypes) == 0 {
+		return nil
+	}
+	return newMediaTypePreference(account.PreferredMediaTypes)
+}
+
+// applyMultiformatPolicy sends bidders which take one media type per imp only one media type of each of
+// their multiformat imps. It's the one the account prefers among those the bidder supports for the channel,
+// so that InfoAwareBidder doesn't drop the imp for offering only what the bidder can't bid on.
+func (rs *requestSplitter) applyMultiformatPolicy(bidderRequests []BidderRequest, account config.AccountMultiformat) {
+	for _, bidderRequest := range bidderRequests {
+		policy := account.Bidders[bidderRequest.BidderName.String()]
+		info := rs.bidderInfo[bidderRequest.BidderCoreName.String()]
+		if !policy.SingleFormat && multiformatSupported(info) {
+			continue
+		}
+
+		preferred := account.PreferredMediaTypes
+		if len(policy.PreferredMediaTypes) != 0 {
+			preferred = policy.PreferredMediaTypes
+		}
+		preference := newMediaTypePreference(preferred)
+		supported := supportedMediaTypes(info, bidderRequest.BidRequest)
+
+		for i := range bidderRequest.BidRequest.Imp {
+			keepPreferredMediaType(&bidderRequest.BidRequest.Imp[i], preference, supported)
+		}
+	}
+}
+
+func multiformatSupported(info config.BidderInfo) bool {
+	return info.OpenRTB == nil || info.OpenRTB.MultiformatSupported == nil || *info.OpenRTB.MultiformatSupported
+}
+
+// supportedMediaTypes returns the media types the bidder's capabilities allow for the request's channel, or
+// nil if it has none for it.
+func supportedMediaTypes(info config.BidderInfo, request *openrtb2.BidRequest) map[openrtb_ext.BidType]bool {
+	if info.Capabilities == nil {
+		return nil
+	}
+	// the channels are checked in the order InfoAwareBidder gives them precedence
+	var platform *config.PlatformInfo
+	switch {
+	case request.DOOH != nil:
+		platform = info.Capabilities.DOOH
+	case request.App != nil:
+		platform = info.Capabilities.App
+	case request.Site != nil:
+		platform = info.Capabilities.Site
+	}
+	if platform == nil {
+		return nil
+	}
+	supported := make(map[openrtb_ext.BidType]bool, len(platform.MediaTypes))
+	for _, mediaType := range platform.MediaTypes {
+		supported[mediaType] = true
+	}
+	return supported
+}
+
+// keepPreferredMediaType removes all but the most preferred media type of a multiformat imp. Media types the
+// bidder supports are preferred to those it doesn't, unless it supports none of them.
+func keepPreferredMediaType(imp *openrtb2.Imp, preference mediaTypePreference, supported map[openrtb_ext.BidType]bool) {
+	offered := impMediaTypes(imp)
+	if len(offered) < 2 {
+		return
+	}
+
+	keep := offered[0]
+	for _, mediaType := range offered[1:] {
+		if supported[mediaType] != supported[keep] {
+			if supported[mediaType] {
+				keep = mediaType
+			}
+			continue
+		}
+		if preference.prefers(mediaType, keep) {
+			keep = mediaType
+		}
+	}
+
+	if keep != openrtb_ext.BidTypeBanner {
+		imp.Banner = nil
+	}
+	if keep != openrtb_ext.BidTypeVideo {
+		imp.Video = nil
+	}
+	if keep != openrtb_ext.BidTypeNative {
+		imp.Native = nil
+	}
+	if keep != openrtb_ext.BidTypeAudio {
+		imp.Audio = nil
+	}
+}
+
+func impMediaTypes(imp *openrtb2.Imp) []openrtb_ext.BidType {
+	mediaTypes := make([]openrtb_ext.BidType, 0, len(defaultMediaTypes))
+	if imp.Banner != nil {
+		mediaTypes = append(mediaTypes, openrtb_ext.BidTypeBanner)
+	}
+	if imp.Video != nil {
+		mediaTypes = append(mediaTypes, openrtb_ext.BidTypeVideo)
+	}
+	if imp.Native != nil {
+		mediaTypes = append(mediaTypes, openrtb_ext.BidTypeNative)
+	}
+	if imp.Audio != nil {
+		mediaTypes = append(mediaTypes, openrtb_ext.BidTypeAudio)
+	}
+	return mediaTypes
+}
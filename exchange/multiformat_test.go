@@ -0,0 +1,120 @@
+package exchange
+
+import (
+	"testing"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/exchange/entities"
+	"github.com/prebid/prebid-server/v2/openrtb_ext"
+	"github.com/prebid/prebid-server/v2/util/ptrutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyMultiformatPolicy(t *testing.T) {
+	multiformatImp := func() openrtb2.Imp {
+		return openrtb2.Imp{ID: "1", Banner: &openrtb2.Banner{}, Video: &openrtb2.Video{}, Native: &openrtb2.Native{}}
+	}
+	siteCapabilities := func(mediaTypes ...openrtb_ext.BidType) *config.CapabilitiesInfo {
+		return &config.CapabilitiesInfo{Site: &config.PlatformInfo{MediaTypes: mediaTypes}}
+	}
+
+	testCases := []struct {
+		description string
+		info        config.BidderInfo
+		account     config.AccountMultiformat
+		expected    []openrtb_ext.BidType
+	}{
+		{
+			description: "multiformat_supported",
+			info:        config.BidderInfo{},
+			account:     config.AccountMultiformat{PreferredMediaTypes: []string{"video"}},
+			expected:    []openrtb_ext.BidType{openrtb_ext.BidTypeBanner, openrtb_ext.BidTypeVideo, openrtb_ext.BidTypeNative},
+		},
+		{
+			description: "multiformat_unsupported_default_order",
+			info:        config.BidderInfo{OpenRTB: &config.OpenRTBInfo{MultiformatSupported: ptrutil.ToPtr(false)}},
+			expected:    []openrtb_ext.BidType{openrtb_ext.BidTypeBanner},
+		},
+		{
+			description: "multiformat_unsupported_account_order",
+			info:        config.BidderInfo{OpenRTB: &config.OpenRTBInfo{MultiformatSupported: ptrutil.ToPtr(false)}},
+			account:     config.AccountMultiformat{PreferredMediaTypes: []string{"native", "video"}},
+			expected:    []openrtb_ext.BidType{openrtb_ext.BidTypeNative},
+		},
+		{
+			description: "single_format_bidder_order",
+			info:        config.BidderInfo{},
+			account: config.AccountMultiformat{
+				PreferredMediaTypes: []string{"native"},
+				Bidders:             map[string]config.AccountMultiformatBidder{"appnexus": {PreferredMediaTypes: []string{"video"}, SingleFormat: true}},
+			},
+			expected: []openrtb_ext.BidType{openrtb_ext.BidTypeVideo},
+		},
+		{
+			description: "preferred_unsupported_by_bidder",
+			info: config.BidderInfo{
+				OpenRTB:      &config.OpenRTBInfo{MultiformatSupported: ptrutil.ToPtr(false)},
+				Capabilities: siteCapabilities(openrtb_ext.BidTypeBanner, openrtb_ext.BidTypeVideo),
+			},
+			account:  config.AccountMultiformat{PreferredMediaTypes: []string{"native", "video"}},
+			expected: []openrtb_ext.BidType{openrtb_ext.BidTypeVideo},
+		},
+		{
+			description: "none_supported_by_bidder",
+			info: config.BidderInfo{
+				OpenRTB:      &config.OpenRTBInfo{MultiformatSupported: ptrutil.ToPtr(false)},
+				Capabilities: siteCapabilities(openrtb_ext.BidTypeAudio),
+			},
+			account:  config.AccountMultiformat{PreferredMediaTypes: []string{"native"}},
+			expected: []openrtb_ext.BidType{openrtb_ext.BidTypeNative},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			rs := requestSplitter{bidderInfo: config.BidderInfos{"appnexus": test.info}}
+			imp := multiformatImp()
+			bidderRequests := []BidderRequest{{
+				BidderName:     openrtb_ext.BidderAppnexus,
+				BidderCoreName: openrtb_ext.BidderAppnexus,
+				BidRequest:     &openrtb2.BidRequest{Site: &openrtb2.Site{}, Imp: []openrtb2.Imp{imp}},
+			}}
+
+			rs.applyMultiformatPolicy(bidderRequests, test.account)
+
+			assert.Equal(t, test.expected, impMediaTypes(&bidderRequests[0].BidRequest.Imp[0]))
+			assert.Equal(t, multiformatImp(), imp, "the original imp shouldn't be changed")
+		})
+	}
+}
+
+func TestNewMediaTypePreference(t *testing.T) {
+	preference := newMediaTypePreference([]string{"video", "audio", "video"})
+
+	assert.Equal(t, mediaTypePreference{
+		openrtb_ext.BidTypeVideo:  0,
+		openrtb_ext.BidTypeAudio:  1,
+		openrtb_ext.BidTypeBanner: 2,
+		openrtb_ext.BidTypeNative: 3,
+	}, preference)
+	assert.True(t, preference.prefers(openrtb_ext.BidTypeAudio, openrtb_ext.BidTypeBanner))
+	assert.False(t, preference.prefers(openrtb_ext.BidTypeBanner, openrtb_ext.BidTypeBanner))
+	assert.False(t, mediaTypePreference(nil).prefers(openrtb_ext.BidTypeVideo, openrtb_ext.BidTypeBanner))
+}
+
+func TestNewAuctionMediaTypeTies(t *testing.T) {
+	banner := &entities.PbsOrtbBid{Bid: &openrtb2.Bid{ID: "banner", ImpID: "1", Price: 2}, BidType: openrtb_ext.BidTypeBanner}
+	video := &entities.PbsOrtbBid{Bid: &openrtb2.Bid{ID: "video", ImpID: "1", Price: 2}, BidType: openrtb_ext.BidTypeVideo}
+	cheaperVideo := &entities.PbsOrtbBid{Bid: &openrtb2.Bid{ID: "cheaper-video", ImpID: "2", Price: 1}, BidType: openrtb_ext.BidTypeVideo}
+	dearerBanner := &entities.PbsOrtbBid{Bid: &openrtb2.Bid{ID: "dearer-banner", ImpID: "2", Price: 3}, BidType: openrtb_ext.BidTypeBanner}
+	seatBids := map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid{
+		"appnexus": {Bids: []*entities.PbsOrtbBid{banner, cheaperVideo}},
+		"rubicon":  {Bids: []*entities.PbsOrtbBid{video, dearerBanner}},
+	}
+
+	auc := newAuction(seatBids, 2, false, auctionMediaTypePreference(config.AccountMultiformat{PreferredMediaTypes: []string{"video"}}))
+
+	assert.Equal(t, video, auc.winningBids["1"], "the preferred media type should win the tie")
+	assert.Equal(t, dearerBanner, auc.winningBids["2"], "a higher price should win over the preferred media type")
+}
@@ -0,0 +1,125 @@
+package exchange
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v2/adapters"
+	"github.com/prebid/prebid-server/v2/errortypes"
+	"github.com/prebid/prebid-server/v2/metrics"
+	"github.com/prebid/prebid-server/v2/openrtb_ext"
+	"github.com/prebid/prebid-server/v2/privacy/piiscan"
+	"github.com/prebid/prebid-server/v2/util/jsonutil"
+)
+
+// assignPIIPolicies decides which bidder requests are scanned for personal data, and what the HTTP requests to
+// each bidder mustn't have given how the privacy policies cleaned its request.
+func assignPIIPolicies(bidderRequests []BidderRequest, original *openrtb2.BidRequest, scanner *piiscan.Scanner, random func() float64) {
+	for i := range bidderRequests {
+		if scanner.Sample(random) {
+			bidderRequests[i].PIIPolicy = scanner.NewPolicy(original, bidderRequests[i].BidRequest)
+		}
+	}
+}
+
+// piiScan finds personal data in the HTTP requests to a bidder, and what put it there. The bidder's request is
+// scanned before and after modules run, so what's found in the HTTP requests can be blamed on the privacy
+// policies, the modules or the adapter. A nil *piiScan scans nothing.
+type piiScan struct {
+	policy       *piiscan.Policy
+	inRequest    []piiscan.Violation
+	afterModules []piiscan.Violation
+}
+
+func newPIIScan(policy *piiscan.Policy, bidRequest *openrtb2.BidRequest) *piiScan {
+	if policy == nil {
+		return nil
+	}
+	return &piiScan{policy: policy, inRequest: scanBidRequest(policy, bidRequest)}
+}
+
+// modulesRan scans the bidder's request as the modules left it.
+func (s *piiScan) modulesRan(bidRequest *openrtb2.BidRequest) {
+	if s == nil {
+		return
+	}
+	s.afterModules = scanBidRequest(s.policy, bidRequest)
+}
+
+func (s *piiScan) source(violation piiscan.Violation) metrics.PIIViolationSource {
+	switch {
+	case containsViolation(s.inRequest, violation):
+		return metrics.PIIViolationSourceRequest
+	case containsViolation(s.afterModules, violation):
+		return metrics.PIIViolationSourceModule
+	default:
+		return metrics.PIIViolationSourceAdapter
+	}
+}
+
+func scanBidRequest(policy *piiscan.Policy, bidRequest *openrtb2.BidRequest) []piiscan.Violation {
+	body, err := jsonutil.Marshal(bidRequest)
+	if err != nil {
+		return nil
+	}
+	return policy.Scan(body)
+}
+
+func containsViolation(violations []piiscan.Violation, violation piiscan.Violation) bool {
+	for _, v := range violations {
+		if v == violation {
+			return true
+		}
+	}
+	return false
+}
+
+// scanForPII scans the HTTP requests to the bidder for personal data and records what's found. The requests with
+// any are reported in the debug output, or aren't sent if the policy is enforced.
+func (bidder *bidderAdapter) scanForPII(scan *piiScan, adapter openrtb_ext.BidderName, reqData []*adapters.RequestData, debugAllowed bool) ([]*adapters.RequestData, []error) {
+	if scan == nil {
+		return reqData, nil
+	}
+
+	var errs []error
+	sent := make([]*adapters.RequestData, 0, len(reqData))
+	for _, data := range reqData {
+		scanned := [][]byte{[]byte(data.Uri), data.Body}
+		if uri, err := url.QueryUnescape(data.Uri); err == nil && uri != data.Uri {
+			scanned = append(scanned, []byte(uri))
+		}
+		violations := scan.policy.Scan(scanned...)
+		if len(violations) == 0 {
+			sent = append(sent, data)
+			continue
+		}
+
+		found := make([]string, 0, len(violations))
+		for _, violation := range violations {
+			source := scan.source(violation)
+			bidder.me.RecordAdapterPIIViolation(metrics.AdapterPIIViolationLabels{Adapter: adapter, Source: source, Type: violation.Type})
+			found = append(found, fmt.Sprintf("%s from the %s", violation, source))
+		}
+		// The URI isn't in the messages, since it may have the personal data.
+		host := "the bidder"
+		if u, err := url.Parse(data.Uri); err == nil && u.Host != "" {
+			host = u.Host
+		}
+		if scan.policy.Enforce() {
+			errs = append(errs, &errortypes.FailedToRequestBids{
+				Message: fmt.Sprintf("The request to %s wasn't sent because it has personal data: %s", host, strings.Join(found, ", ")),
+			})
+			continue
+		}
+		sent = append(sent, data)
+		if debugAllowed {
+			errs = append(errs, &errortypes.Warning{
+				Message:     fmt.Sprintf("The request to %s has personal data: %s", host, strings.Join(found, ", ")),
+				WarningCode: errortypes.PIIViolationWarningCode,
+			})
+		}
+	}
+	return sent, errs
+}
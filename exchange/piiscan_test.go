@@ -0,0 +1,176 @@
+package exchange
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v2/adapters"
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/currency"
+	"github.com/prebid/prebid-server/v2/errortypes"
+	"github.com/prebid/prebid-server/v2/experiment/adscert"
+	"github.com/prebid/prebid-server/v2/hooks/hookexecution"
+	"github.com/prebid/prebid-server/v2/metrics"
+	metricsConfig "github.com/prebid/prebid-server/v2/metrics/config"
+	"github.com/prebid/prebid-server/v2/openrtb_ext"
+	"github.com/prebid/prebid-server/v2/privacy/piiscan"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAssignPIIPolicies(t *testing.T) {
+	original := &openrtb2.BidRequest{ID: "request"}
+
+	testCases := []struct {
+		description    string
+		scanner        *piiscan.Scanner
+		random         float64
+		expectedPolicy bool
+	}{
+		{
+			description: "Scanner off",
+			random:      0,
+		},
+		{
+			description:    "Sampled",
+			scanner:        piiscan.NewScanner(config.PIIScanner{Mode: config.PIIScannerModeReport, SampleRate: 0.5}),
+			random:         0.3,
+			expectedPolicy: true,
+		},
+		{
+			description: "Not sampled",
+			scanner:     piiscan.NewScanner(config.PIIScanner{Mode: config.PIIScannerModeReport, SampleRate: 0.5}),
+			random:      0.7,
+		},
+		{
+			description:    "Enforced",
+			scanner:        piiscan.NewScanner(config.PIIScanner{Mode: config.PIIScannerModeEnforce}),
+			random:         0.7,
+			expectedPolicy: true,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			bidderRequests := []BidderRequest{
+				{BidderName: "appnexus", BidRequest: &openrtb2.BidRequest{ID: "request"}},
+				{BidderName: "rubicon", BidRequest: &openrtb2.BidRequest{ID: "request"}},
+			}
+
+			assignPIIPolicies(bidderRequests, original, test.scanner, func() float64 { return test.random })
+
+			for _, bidderRequest := range bidderRequests {
+				assert.Equal(t, test.expectedPolicy, bidderRequest.PIIPolicy != nil, string(bidderRequest.BidderName))
+			}
+		})
+	}
+}
+
+func TestPIIScanSource(t *testing.T) {
+	scanner := piiscan.NewScanner(config.PIIScanner{Mode: config.PIIScannerModeReport, Emails: true, Patterns: map[string]string{"phone": `\+1\d{10}`}})
+	bidRequest := &openrtb2.BidRequest{Site: &openrtb2.Site{Keywords: "someone@example.com"}}
+	policy := scanner.NewPolicy(bidRequest, bidRequest)
+
+	scan := newPIIScan(policy, bidRequest)
+	bidRequest.Site.Keywords += ",+12025550123"
+	scan.modulesRan(bidRequest)
+
+	assert.Equal(t, metrics.PIIViolationSourceRequest, scan.source(piiscan.Violation{Type: metrics.PIIViolationEmail}))
+	assert.Equal(t, metrics.PIIViolationSourceModule, scan.source(piiscan.Violation{Type: metrics.PIIViolationPattern, Pattern: "phone"}))
+	assert.Equal(t, metrics.PIIViolationSourceAdapter, scan.source(piiscan.Violation{Type: metrics.PIIViolationUserID}))
+
+	assert.Nil(t, newPIIScan(nil, bidRequest))
+}
+
+func TestScanForPII(t *testing.T) {
+	original := &openrtb2.BidRequest{User: &openrtb2.User{ID: "user-12345"}}
+	bidderRequest := &openrtb2.BidRequest{User: &openrtb2.User{}}
+	clean := &adapters.RequestData{Uri: "https://bidder.com/bid", Body: []byte(`{"user":{}}`)}
+	leaky := &adapters.RequestData{Uri: "https://bidder.com/bid?uid=user-12345", Body: []byte(`{"user":{"ext":{"email":"someone@example.com"}}}`)}
+
+	testCases := []struct {
+		description  string
+		mode         string
+		debugAllowed bool
+		expectedSent []*adapters.RequestData
+		expectedErrs []error
+	}{
+		{
+			description:  "Report with debug",
+			mode:         config.PIIScannerModeReport,
+			debugAllowed: true,
+			expectedSent: []*adapters.RequestData{clean, leaky},
+			expectedErrs: []error{&errortypes.Warning{
+				Message:     "The request to bidder.com has personal data: email from the adapter, user_id from the adapter",
+				WarningCode: errortypes.PIIViolationWarningCode,
+			}},
+		},
+		{
+			description:  "Report without debug",
+			mode:         config.PIIScannerModeReport,
+			expectedSent: []*adapters.RequestData{clean, leaky},
+		},
+		{
+			description:  "Enforce",
+			mode:         config.PIIScannerModeEnforce,
+			expectedSent: []*adapters.RequestData{clean},
+			expectedErrs: []error{&errortypes.FailedToRequestBids{
+				Message: "The request to bidder.com wasn't sent because it has personal data: email from the adapter, user_id from the adapter",
+			}},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			me := &metrics.MetricsEngineMock{}
+			me.On("RecordAdapterPIIViolation", metrics.AdapterPIIViolationLabels{Adapter: openrtb_ext.BidderAppnexus, Source: metrics.PIIViolationSourceAdapter, Type: metrics.PIIViolationEmail}).Once()
+			me.On("RecordAdapterPIIViolation", metrics.AdapterPIIViolationLabels{Adapter: openrtb_ext.BidderAppnexus, Source: metrics.PIIViolationSourceAdapter, Type: metrics.PIIViolationUserID}).Once()
+			bidder := &bidderAdapter{me: me}
+
+			scanner := piiscan.NewScanner(config.PIIScanner{Mode: test.mode, Emails: true, UserIDs: true})
+			scan := newPIIScan(scanner.NewPolicy(original, bidderRequest), bidderRequest)
+			scan.modulesRan(bidderRequest)
+
+			sent, errs := bidder.scanForPII(scan, openrtb_ext.BidderAppnexus, []*adapters.RequestData{clean, leaky}, test.debugAllowed)
+			assert.Equal(t, test.expectedSent, sent)
+			assert.Equal(t, test.expectedErrs, errs)
+			me.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRequestBidPIIEnforced(t *testing.T) {
+	server := httptest.NewServer(mockHandler(200, "getBody", `{"bid":false}`))
+	defer server.Close()
+
+	bidderImpl := &goodSingleBidder{
+		httpRequest: &adapters.RequestData{
+			Method:  "POST",
+			Uri:     server.URL,
+			Body:    []byte(`{"user":{"ext":{"email":"someone@example.com"}}}`),
+			Headers: http.Header{},
+		},
+		bidResponse: &adapters.BidderResponse{},
+	}
+	bidder := AdaptBidder(bidderImpl, server.Client(), &config.Configuration{}, &metricsConfig.NilMetricsEngine{}, openrtb_ext.BidderAppnexus, nil, "")
+
+	bidRequest := &openrtb2.BidRequest{Imp: []openrtb2.Imp{{ID: "impId"}}}
+	scanner := piiscan.NewScanner(config.PIIScanner{Mode: config.PIIScannerModeEnforce, Emails: true})
+	bidderReq := BidderRequest{
+		BidRequest:   bidRequest,
+		BidderName:   openrtb_ext.BidderAppnexus,
+		BidderLabels: metrics.AdapterLabels{Adapter: openrtb_ext.BidderAppnexus},
+		PIIPolicy:    scanner.NewPolicy(bidRequest, bidRequest),
+	}
+	seatBids, _, errs := bidder.requestBid(context.Background(), bidderReq, currency.NewConstantRates(), &adapters.ExtraRequestInfo{}, &adscert.NilSigner{}, bidRequestOptions{}, openrtb_ext.ExtAlternateBidderCodes{}, &hookexecution.EmptyHookExecutor{}, nil)
+
+	assert.Nil(t, bidderImpl.httpResponse, "the bidder shouldn't be called")
+	if assert.Len(t, seatBids, 1) {
+		assert.Empty(t, seatBids[0].Bids)
+	}
+	assert.Equal(t, []error{&errortypes.FailedToRequestBids{
+		Message: "The request to " + server.Listener.Addr().String() + " wasn't sent because it has personal data: email from the adapter",
+	}}, errs)
+}
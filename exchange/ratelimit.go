@@ -0,0 +1,120 @@
+package exchange
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/errortypes"
+	"github.com/prebid/prebid-server/v2/exchange/entities"
+	"github.com/prebid/prebid-server/v2/metrics"
+	"github.com/prebid/prebid-server/v2/openrtb_ext"
+	"github.com/prebid/prebid-server/v2/ratelimit"
+)
+
+// capBidderQPS leaves out the requests to bidders which have been sent as many requests as their QPS cap
+// allows in the current second. Simulated requests aren't sent to the bidder, so they don't count.
+func capBidderQPS(ctx context.Context, bidderRequests []BidderRequest, caps map[string]int, limiter *ratelimit.Limiter) ([]BidderRequest, []error) {
+	if len(caps) == 0 {
+		return bidderRequests, nil
+	}
+
+	var errs []error
+	allowed := bidderRequests[:0]
+	for _, bidderRequest := range bidderRequests {
+		coreBidder := bidderRequest.BidderCoreName.String()
+		qps, ok := caps[coreBidder]
+		if ok && bidderRequest.SimulatedResponse == nil && !limiter.Allow(ctx, metrics.RateLimitBidderQPS, "bidder_qps:"+coreBidder, int64(qps), time.Second) {
+			errs = append(errs, &errortypes.Warning{
+				Message:     fmt.Sprintf("%s was left out of the auction because it's over its cap of %d requests per second", bidderRequest.BidderName, qps),
+				WarningCode: errortypes.BidderQPSCapWarningCode,
+			})
+			continue
+		}
+		allowed = append(allowed, bidderRequest)
+	}
+	return allowed, errs
+}
+
+// paceDeals takes the account's deals which have had their most bids in the current pacing window out of
+// the imps of the request, so that bidders don't bid on them again until the next window.
+func paceDeals(ctx context.Context, req *openrtb_ext.RequestWrapper, accountID string, pacing config.AccountDealPacing, limiter *ratelimit.Limiter) []error {
+	if len(pacing.Deals) == 0 {
+		return nil
+	}
+
+	var errs []error
+	exhausted := make(map[string]bool)
+	window := time.Duration(pacing.WindowSeconds) * time.Second
+	for _, imp := range req.GetImp() {
+		if imp.PMP == nil || len(imp.PMP.Deals) == 0 {
+			continue
+		}
+
+		paced := false
+		for _, deal := range imp.PMP.Deals {
+			max, ok := pacing.Deals[deal.ID]
+			if !ok {
+				continue
+			}
+			isExhausted, checked := exhausted[deal.ID]
+			if !checked {
+				isExhausted = limiter.Exhausted(ctx, metrics.RateLimitDealPacing, dealPacingKey(accountID, deal.ID), max, window)
+				exhausted[deal.ID] = isExhausted
+				if isExhausted {
+					errs = append(errs, &errortypes.Warning{
+						Message:     fmt.Sprintf("deal %s was left out of the auction because it has had its %d bids for the pacing window", deal.ID, max),
+						WarningCode: errortypes.DealPacingWarningCode,
+					})
+				}
+			}
+			paced = paced || isExhausted
+		}
+		if !paced {
+			continue
+		}
+
+		// the imp may be shared with a stored request, so its deals are copied rather than changed in place
+		pmp := *imp.PMP
+		pmp.Deals = nil
+		for _, deal := range imp.PMP.Deals {
+			if !exhausted[deal.ID] {
+				pmp.Deals = append(pmp.Deals, deal)
+			}
+		}
+		imp.PMP = &pmp
+	}
+	return errs
+}
+
+// countPacedDeals counts the bids returned for the account's paced deals towards their pacing windows.
+func countPacedDeals(ctx context.Context, adapterBids map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid, accountID string, pacing config.AccountDealPacing, limiter *ratelimit.Limiter) {
+	if len(pacing.Deals) == 0 {
+		return
+	}
+
+	bids := make(map[string]int64)
+	for _, seatBid := range adapterBids {
+		if seatBid == nil {
+			continue
+		}
+		for _, bid := range seatBid.Bids {
+			if bid == nil || bid.Bid == nil {
+				continue
+			}
+			if _, ok := pacing.Deals[bid.Bid.DealID]; ok {
+				bids[bid.Bid.DealID]++
+			}
+		}
+	}
+
+	window := time.Duration(pacing.WindowSeconds) * time.Second
+	for deal, n := range bids {
+		limiter.Count(ctx, metrics.RateLimitDealPacing, dealPacingKey(accountID, deal), n, window)
+	}
+}
+
+func dealPacingKey(accountID, dealID string) string {
+	return "deal_pacing:" + accountID + ":" + dealID
+}
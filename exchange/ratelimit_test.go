@@ -0,0 +1,104 @@
+package exchange
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/errortypes"
+	"github.com/prebid/prebid-server/v2/exchange/entities"
+	"github.com/prebid/prebid-server/v2/metrics"
+	metricsConf "github.com/prebid/prebid-server/v2/metrics/config"
+	"github.com/prebid/prebid-server/v2/openrtb_ext"
+	"github.com/prebid/prebid-server/v2/ratelimit"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestRateLimiter() *ratelimit.Limiter {
+	return ratelimit.NewLocalLimiter(&metricsConf.NilMetricsEngine{})
+}
+
+func TestCapBidderQPS(t *testing.T) {
+	limiter := newTestRateLimiter()
+	caps := map[string]int{"appnexus": 1}
+	bidderRequests := func() []BidderRequest {
+		return []BidderRequest{
+			{BidderName: "appnexus", BidderCoreName: "appnexus"},
+			{BidderName: "rubicon", BidderCoreName: "rubicon"},
+			{BidderName: "simulated", BidderCoreName: "appnexus", SimulatedResponse: json.RawMessage(`{}`)},
+		}
+	}
+
+	allowed, errs := capBidderQPS(context.Background(), bidderRequests(), caps, limiter)
+	assert.Len(t, allowed, 3)
+	assert.Empty(t, errs)
+
+	// the second request to appnexus in the same second is over its cap
+	allowed, errs = capBidderQPS(context.Background(), bidderRequests(), caps, limiter)
+	assert.Equal(t, []BidderRequest{
+		{BidderName: "rubicon", BidderCoreName: "rubicon"},
+		{BidderName: "simulated", BidderCoreName: "appnexus", SimulatedResponse: json.RawMessage(`{}`)},
+	}, allowed)
+	assert.Equal(t, []error{&errortypes.Warning{
+		Message:     "appnexus was left out of the auction because it's over its cap of 1 requests per second",
+		WarningCode: errortypes.BidderQPSCapWarningCode,
+	}}, errs)
+}
+
+func TestCapBidderQPSNoCaps(t *testing.T) {
+	bidderRequests := []BidderRequest{{BidderName: "appnexus", BidderCoreName: "appnexus"}}
+	allowed, errs := capBidderQPS(context.Background(), bidderRequests, nil, newTestRateLimiter())
+	assert.Equal(t, bidderRequests, allowed)
+	assert.Empty(t, errs)
+}
+
+func TestDealPacing(t *testing.T) {
+	limiter := newTestRateLimiter()
+	pacing := config.AccountDealPacing{WindowSeconds: 3600, Deals: map[string]int64{"paced": 2}}
+	sharedPMP := &openrtb2.PMP{PrivateAuction: 1, Deals: []openrtb2.Deal{{ID: "paced"}, {ID: "other"}}}
+	newRequest := func() *openrtb_ext.RequestWrapper {
+		return &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{Imp: []openrtb2.Imp{
+			{ID: "1", PMP: sharedPMP},
+			{ID: "2"},
+		}}}
+	}
+
+	// the deal has bids left
+	req := newRequest()
+	assert.Empty(t, paceDeals(context.Background(), req, "1001", pacing, limiter))
+	assert.Equal(t, sharedPMP, req.GetImp()[0].PMP)
+
+	adapterBids := map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid{
+		"appnexus": {Bids: []*entities.PbsOrtbBid{{Bid: &openrtb2.Bid{DealID: "paced"}}, {Bid: &openrtb2.Bid{DealID: "other"}}}},
+		"rubicon":  {Bids: []*entities.PbsOrtbBid{{Bid: &openrtb2.Bid{DealID: "paced"}}}},
+	}
+	countPacedDeals(context.Background(), adapterBids, "1001", pacing, limiter)
+
+	// the deal has had its bids for the window
+	req = newRequest()
+	errs := paceDeals(context.Background(), req, "1001", pacing, limiter)
+	assert.Equal(t, []error{&errortypes.Warning{
+		Message:     "deal paced was left out of the auction because it has had its 2 bids for the pacing window",
+		WarningCode: errortypes.DealPacingWarningCode,
+	}}, errs)
+	assert.Equal(t, &openrtb2.PMP{PrivateAuction: 1, Deals: []openrtb2.Deal{{ID: "other"}}}, req.GetImp()[0].PMP)
+	assert.Len(t, sharedPMP.Deals, 2, "the deals of the original imp shouldn't change")
+
+	// other accounts have their own windows
+	req = newRequest()
+	assert.Empty(t, paceDeals(context.Background(), req, "1002", pacing, limiter))
+}
+
+func TestDealPacingNotConfigured(t *testing.T) {
+	me := &metrics.MetricsEngineMock{}
+	limiter := ratelimit.NewLocalLimiter(me)
+	req := &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{Imp: []openrtb2.Imp{{ID: "1", PMP: &openrtb2.PMP{Deals: []openrtb2.Deal{{ID: "deal"}}}}}}}
+
+	assert.Empty(t, paceDeals(context.Background(), req, "1001", config.AccountDealPacing{}, limiter))
+	countPacedDeals(context.Background(), map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid{
+		"appnexus": {Bids: []*entities.PbsOrtbBid{{Bid: &openrtb2.Bid{DealID: "deal"}}}},
+	}, "1001", config.AccountDealPacing{}, limiter)
+	me.AssertNotCalled(t, "RecordRateLimit")
+}
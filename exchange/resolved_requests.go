@@ -0,0 +1,65 @@
+package exchange
+
+import (
+	"encoding/json"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v2/errortypes"
+	"github.com/prebid/prebid-server/v2/openrtb_ext"
+	"github.com/prebid/prebid-server/v2/util/jsonutil"
+	jsonpatch "gopkg.in/evanphx/json-patch.v4"
+)
+
+// resolvedBidderRequests makes the copies of the bidder requests which are returned in
+// ext.debug.resolvedbidderrequests. A nil resolvedBidderRequests makes none.
+type resolvedBidderRequests struct {
+	// resolvedRequest is the request the copies are diffed against, or nil if they're returned in full
+	resolvedRequest json.RawMessage
+}
+
+// newResolvedBidderRequests returns nil unless ext.prebid.resolvedbidderrequests asks for the bidder
+// requests, and debug output is allowed.
+func newResolvedBidderRequests(option string, responseDebugAllow bool, resolvedRequest json.RawMessage) *resolvedBidderRequests {
+	if !responseDebugAllow {
+		return nil
+	}
+	switch option {
+	case openrtb_ext.ResolvedBidderRequestsFull:
+		return &resolvedBidderRequests{}
+	case openrtb_ext.ResolvedBidderRequestsDiff:
+		if resolvedRequest == nil {
+			return &resolvedBidderRequests{}
+		}
+		return &resolvedBidderRequests{resolvedRequest: resolvedRequest}
+	}
+	return nil
+}
+
+// resolve returns the request as it's sent to the bidder, or the JSON merge patch which turns the
+// resolved request into it.
+func (r *resolvedBidderRequests) resolve(request *openrtb2.BidRequest) (json.RawMessage, error) {
+	if r == nil {
+		return nil, nil
+	}
+	requestJSON, err := jsonutil.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+	if r.resolvedRequest == nil {
+		return requestJSON, nil
+	}
+	return jsonpatch.CreateMergePatch(r.resolvedRequest, requestJSON)
+}
+
+// resolveBidderRequest returns the request sent to the bidder for the debug output, adding a warning to
+// errs if it can't be.
+func resolveBidderRequest(request *openrtb2.BidRequest, resolvedRequests *resolvedBidderRequests, errs []error) (json.RawMessage, []error) {
+	resolved, err := resolvedRequests.resolve(request)
+	if err != nil {
+		errs = append(errs, &errortypes.Warning{
+			Message:     "Failed to resolve the bidder request for the debug output: " + err.Error(),
+			WarningCode: errortypes.DebugResolutionWarningCode,
+		})
+	}
+	return resolved, errs
+}
@@ -0,0 +1,162 @@
+package exchange
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v2/adapters"
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/currency"
+	"github.com/prebid/prebid-server/v2/experiment/adscert"
+	"github.com/prebid/prebid-server/v2/hooks/hookexecution"
+	metricsConfig "github.com/prebid/prebid-server/v2/metrics/config"
+	"github.com/prebid/prebid-server/v2/openrtb_ext"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewResolvedBidderRequests(t *testing.T) {
+	resolvedRequest := json.RawMessage(`{"id":"req"}`)
+	testCases := []struct {
+		description        string
+		option             string
+		responseDebugAllow bool
+		resolvedRequest    json.RawMessage
+		expected           *resolvedBidderRequests
+	}{
+		{
+			description:        "full",
+			option:             "full",
+			responseDebugAllow: true,
+			resolvedRequest:    resolvedRequest,
+			expected:           &resolvedBidderRequests{},
+		},
+		{
+			description:        "diff",
+			option:             "diff",
+			responseDebugAllow: true,
+			resolvedRequest:    resolvedRequest,
+			expected:           &resolvedBidderRequests{resolvedRequest: resolvedRequest},
+		},
+		{
+			description:        "diff-without-resolved-request",
+			option:             "diff",
+			responseDebugAllow: true,
+			expected:           &resolvedBidderRequests{},
+		},
+		{
+			description:        "debug-not-allowed",
+			option:             "full",
+			responseDebugAllow: false,
+			resolvedRequest:    resolvedRequest,
+		},
+		{
+			description:        "not-asked-for",
+			responseDebugAllow: true,
+			resolvedRequest:    resolvedRequest,
+		},
+		{
+			description:        "unknown-option",
+			option:             "verbose",
+			responseDebugAllow: true,
+			resolvedRequest:    resolvedRequest,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			assert.Equal(t, test.expected, newResolvedBidderRequests(test.option, test.responseDebugAllow, test.resolvedRequest))
+		})
+	}
+}
+
+func TestResolvedBidderRequestsResolve(t *testing.T) {
+	request := &openrtb2.BidRequest{ID: "req", Imp: []openrtb2.Imp{{ID: "imp-1"}}, TMax: 400}
+	testCases := []struct {
+		description      string
+		resolvedRequests *resolvedBidderRequests
+		expected         string
+	}{
+		{
+			description: "nil",
+		},
+		{
+			description:      "full",
+			resolvedRequests: &resolvedBidderRequests{},
+			expected:         `{"id":"req","imp":[{"id":"imp-1"}],"tmax":400}`,
+		},
+		{
+			description:      "diff",
+			resolvedRequests: &resolvedBidderRequests{resolvedRequest: json.RawMessage(`{"id":"req","imp":[{"id":"imp-1"}],"tmax":500,"user":{"id":"user"}}`)},
+			expected:         `{"tmax":400,"user":null}`,
+		},
+		{
+			description:      "no-difference",
+			resolvedRequests: &resolvedBidderRequests{resolvedRequest: json.RawMessage(`{"id":"req","imp":[{"id":"imp-1"}],"tmax":400}`)},
+			expected:         `{}`,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			resolved, err := test.resolvedRequests.resolve(request)
+			assert.NoError(t, err)
+			if test.expected == "" {
+				assert.Nil(t, resolved)
+			} else {
+				assert.JSONEq(t, test.expected, string(resolved))
+			}
+		})
+	}
+}
+
+func TestRequestBidResolvedRequest(t *testing.T) {
+	bidder := AdaptBidder(noRequestsBidder{}, &http.Client{}, &config.Configuration{}, &metricsConfig.NilMetricsEngine{}, openrtb_ext.BidderAppnexus, nil, "")
+	currencyConverter := currency.NewRateConverter(&http.Client{}, "", time.Duration(0))
+	bidderRequest := BidderRequest{
+		BidRequest: &openrtb2.BidRequest{ID: "req", Imp: []openrtb2.Imp{{ID: "imp-1"}}},
+		BidderName: openrtb_ext.BidderAppnexus,
+	}
+
+	_, extraRespInfo, errs := bidder.requestBid(
+		context.Background(),
+		bidderRequest,
+		currencyConverter.Rates(),
+		&adapters.ExtraRequestInfo{},
+		&adscert.NilSigner{},
+		bidRequestOptions{
+			resolvedRequests: &resolvedBidderRequests{resolvedRequest: json.RawMessage(`{"id":"req","imp":[{"id":"imp-1"}]}`)},
+		},
+		openrtb_ext.ExtAlternateBidderCodes{},
+		userSettingHookExecutor{},
+		nil,
+	)
+
+	assert.Equal(t, []error{errors.New("no requests")}, errs)
+	assert.JSONEq(t, `{"user":{"id":"set-by-hook"}}`, string(extraRespInfo.resolvedRequest), "The request should be resolved after the hooks have run")
+}
+
+// noRequestsBidder makes no requests to its bidder.
+type noRequestsBidder struct{}
+
+func (noRequestsBidder) MakeRequests(request *openrtb2.BidRequest, reqInfo *adapters.ExtraRequestInfo) ([]*adapters.RequestData, []error) {
+	return nil, []error{errors.New("no requests")}
+}
+
+func (noRequestsBidder) MakeBids(internalRequest *openrtb2.BidRequest, externalRequest *adapters.RequestData, response *adapters.ResponseData) (*adapters.BidderResponse, []error) {
+	return nil, nil
+}
+
+// userSettingHookExecutor sets the user ID in bidder requests, as a bidder request hook might.
+type userSettingHookExecutor struct {
+	hookexecution.EmptyHookExecutor
+}
+
+func (userSettingHookExecutor) ExecuteBidderRequestStage(req *openrtb_ext.RequestWrapper, bidder string) *hookexecution.RejectError {
+	req.User = &openrtb2.User{ID: "set-by-hook"}
+	return nil
+}
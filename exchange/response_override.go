@@ -0,0 +1,98 @@
+package exchange
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v2/openrtb_ext"
+	"github.com/prebid/prebid-server/v2/responseoverride"
+	"github.com/prebid/prebid-server/v2/stored_responses"
+)
+
+// pinBidderResponses answers each imp of the bidders whose responses are pinned by a response override with
+// the stored bid response, instead of calling them. Other bidders are called as usual.
+func pinBidderResponses(bidderRequests []BidderRequest, pinned map[string]responseoverride.Pinned) {
+	if len(pinned) == 0 {
+		return
+	}
+	for i := range bidderRequests {
+		bidderRequest := &bidderRequests[i]
+		p, ok := pinned[strings.ToLower(bidderRequest.BidderName.String())]
+		if !ok || bidderRequest.BidRequest == nil || len(bidderRequest.BidRequest.Imp) == 0 {
+			continue
+		}
+
+		storedResponses := make(map[string]json.RawMessage, len(bidderRequest.BidderStoredResponses)+len(bidderRequest.BidRequest.Imp))
+		for impID, response := range bidderRequest.BidderStoredResponses {
+			storedResponses[impID] = response
+		}
+		replaceImpID := make(map[string]bool, len(bidderRequest.ImpReplaceImpId)+len(bidderRequest.BidRequest.Imp))
+		for impID, replace := range bidderRequest.ImpReplaceImpId {
+			replaceImpID[impID] = replace
+		}
+		for _, imp := range bidderRequest.BidRequest.Imp {
+			storedResponses[imp.ID] = p.Response
+			replaceImpID[imp.ID] = p.ReplaceImpID
+		}
+
+		// the bidder request is shallow copied, so that requests shared with other bidders keep their imps
+		request := *bidderRequest.BidRequest
+		request.Imp = nil
+		bidderRequest.BidRequest = &request
+		bidderRequest.BidderStoredResponses = storedResponses
+		bidderRequest.ImpReplaceImpId = replaceImpID
+	}
+}
+
+// pinBidderImpResponses answers the imps the account maps to stored bid responses for a bidder with the
+// responses, instead of calling the bidder for them. The bidder is still called for its other imps, and the
+// imps it was already answering with stored responses keep them.
+func pinBidderImpResponses(bidderRequests []BidderRequest, responses stored_responses.BidderImpsWithBidResponses, replaceImpIDs stored_responses.BidderImpReplaceImpID) {
+	if len(responses) == 0 {
+		return
+	}
+	for i := range bidderRequests {
+		bidderRequest := &bidderRequests[i]
+		bidder := strings.ToLower(bidderRequest.BidderName.String())
+		impResponses, ok := responses[openrtb_ext.BidderName(bidder)]
+		if !ok || bidderRequest.BidRequest == nil || len(bidderRequest.BidRequest.Imp) == 0 {
+			continue
+		}
+
+		imps := make([]openrtb2.Imp, 0, len(bidderRequest.BidRequest.Imp))
+		for _, imp := range bidderRequest.BidRequest.Imp {
+			if _, ok := impResponses[imp.ID]; !ok {
+				imps = append(imps, imp)
+			}
+		}
+		if len(imps) == len(bidderRequest.BidRequest.Imp) {
+			continue
+		}
+
+		storedResponses := make(map[string]json.RawMessage, len(bidderRequest.BidderStoredResponses)+len(impResponses))
+		for impID, response := range bidderRequest.BidderStoredResponses {
+			storedResponses[impID] = response
+		}
+		replaceImpID := make(map[string]bool, len(bidderRequest.ImpReplaceImpId)+len(impResponses))
+		for impID, replace := range bidderRequest.ImpReplaceImpId {
+			replaceImpID[impID] = replace
+		}
+		for _, imp := range bidderRequest.BidRequest.Imp {
+			if response, ok := impResponses[imp.ID]; ok {
+				storedResponses[imp.ID] = response
+				replaceImpID[imp.ID] = replaceImpIDs[bidder][imp.ID]
+			}
+		}
+
+		// the bidder request is shallow copied, so that requests shared with other bidders keep their imps
+		request := *bidderRequest.BidRequest
+		request.Imp = imps
+		if len(imps) == 0 {
+			request.Imp = nil
+		}
+		bidderRequest.BidRequest = &request
+		bidderRequest.BidderStoredResponses = storedResponses
+		bidderRequest.ImpReplaceImpId = replaceImpID
+	}
+}
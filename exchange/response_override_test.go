@@ -0,0 +1,88 @@
+package exchange
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v2/openrtb_ext"
+	"github.com/prebid/prebid-server/v2/responseoverride"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPinBidderResponses(t *testing.T) {
+	shared := &openrtb2.BidRequest{ID: "req", Imp: []openrtb2.Imp{{ID: "imp-1"}, {ID: "imp-2"}}}
+	response := json.RawMessage(`{"seatbid":[]}`)
+	bidderRequests := []BidderRequest{
+		{BidderName: "AppNexus", BidRequest: shared},
+		{BidderName: "rubicon", BidRequest: shared},
+		{
+			BidderName:            "openx",
+			BidRequest:            &openrtb2.BidRequest{ID: "req"},
+			BidderStoredResponses: map[string]json.RawMessage{"imp-3": json.RawMessage(`{}`)},
+		},
+	}
+
+	pinBidderResponses(bidderRequests, map[string]responseoverride.Pinned{
+		"appnexus": {Response: response, ReplaceImpID: true},
+		"openx":    {Response: response},
+	})
+
+	assert.Empty(t, bidderRequests[0].BidRequest.Imp, "pinned bidders aren't called")
+	assert.Equal(t, "req", bidderRequests[0].BidRequest.ID)
+	assert.Equal(t, map[string]json.RawMessage{"imp-1": response, "imp-2": response}, bidderRequests[0].BidderStoredResponses)
+	assert.Equal(t, map[string]bool{"imp-1": true, "imp-2": true}, bidderRequests[0].ImpReplaceImpId)
+
+	assert.Len(t, bidderRequests[1].BidRequest.Imp, 2, "other bidders are called as usual")
+	assert.Len(t, shared.Imp, 2, "requests shared with other bidders keep their imps")
+	assert.Nil(t, bidderRequests[1].BidderStoredResponses)
+
+	assert.Equal(t, map[string]json.RawMessage{"imp-3": json.RawMessage(`{}`)}, bidderRequests[2].BidderStoredResponses, "bidders which only have stored responses keep them")
+}
+
+func TestPinBidderResponsesWithoutOverrides(t *testing.T) {
+	request := &openrtb2.BidRequest{ID: "req", Imp: []openrtb2.Imp{{ID: "imp-1"}}}
+	bidderRequests := []BidderRequest{{BidderName: openrtb_ext.BidderName("appnexus"), BidRequest: request}}
+
+	pinBidderResponses(bidderRequests, nil)
+
+	assert.Same(t, request, bidderRequests[0].BidRequest)
+	assert.Nil(t, bidderRequests[0].BidderStoredResponses)
+}
+
+func TestPinBidderImpResponses(t *testing.T) {
+	shared := &openrtb2.BidRequest{ID: "req", Imp: []openrtb2.Imp{{ID: "imp-1"}, {ID: "imp-2"}}}
+	response := json.RawMessage(`{"seatbid":[]}`)
+	bidderRequests := []BidderRequest{
+		{
+			BidderName:            "AppNexus",
+			BidRequest:            shared,
+			BidderStoredResponses: map[string]json.RawMessage{"imp-3": json.RawMessage(`{}`)},
+			ImpReplaceImpId:       map[string]bool{"imp-3": true},
+		},
+		{BidderName: "rubicon", BidRequest: shared},
+		{BidderName: "openx", BidRequest: shared},
+	}
+
+	pinBidderImpResponses(bidderRequests,
+		map[openrtb_ext.BidderName]map[string]json.RawMessage{
+			"appnexus": {"imp-1": response},
+			"openx":    {"imp-1": response, "imp-2": response},
+		},
+		map[string]map[string]bool{
+			"appnexus": {"imp-1": false},
+			"openx":    {"imp-1": true, "imp-2": true},
+		},
+	)
+
+	assert.Equal(t, []openrtb2.Imp{{ID: "imp-2"}}, bidderRequests[0].BidRequest.Imp, "the bidder is called for its other imps")
+	assert.Equal(t, map[string]json.RawMessage{"imp-1": response, "imp-3": json.RawMessage(`{}`)}, bidderRequests[0].BidderStoredResponses)
+	assert.Equal(t, map[string]bool{"imp-1": false, "imp-3": true}, bidderRequests[0].ImpReplaceImpId)
+
+	assert.Len(t, bidderRequests[1].BidRequest.Imp, 2, "other bidders are called as usual")
+	assert.Len(t, shared.Imp, 2, "requests shared with other bidders keep their imps")
+	assert.Nil(t, bidderRequests[1].BidderStoredResponses)
+
+	assert.Empty(t, bidderRequests[2].BidRequest.Imp, "bidders with every imp mapped aren't called")
+	assert.Equal(t, map[string]json.RawMessage{"imp-1": response, "imp-2": response}, bidderRequests[2].BidderStoredResponses)
+}
@@ -0,0 +1,74 @@
+package exchange
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v2/adapters"
+	"github.com/prebid/prebid-server/v2/errortypes"
+	"github.com/prebid/prebid-server/v2/openrtb_ext"
+	"github.com/prebid/prebid-server/v2/util/jsonutil"
+)
+
+// noSimulatedResponse is the response of bidders which aren't given one in a simulated auction, which
+// don't bid.
+var noSimulatedResponse = json.RawMessage(`{}`)
+
+// setSimulatedResponses gives each bidder its response from the simulated auction, so no bidder is
+// called. A nil simulation leaves the bidder requests as they are.
+func setSimulatedResponses(bidderRequests []BidderRequest, simulation map[string]json.RawMessage) {
+	if simulation == nil {
+		return
+	}
+	for i := range bidderRequests {
+		response, ok := simulation[bidderRequests[i].BidderName.String()]
+		if !ok {
+			response = noSimulatedResponse
+		}
+		bidderRequests[i].SimulatedResponse = response
+	}
+}
+
+// prepareSimulatedResponse returns the call the bidder seems to have answered with its simulated
+// response, as it's shown in debug output.
+func prepareSimulatedResponse(bidder openrtb_ext.BidderName, response json.RawMessage) *httpCallInfo {
+	return &httpCallInfo{
+		request: &adapters.RequestData{
+			Method: http.MethodPost,
+			Uri:    "simulated://" + bidder.String(),
+		},
+		response: &adapters.ResponseData{
+			StatusCode: http.StatusOK,
+			Body:       response,
+		},
+		simulated: true,
+	}
+}
+
+// makeSimulatedBids reads the bids from a simulated OpenRTB response. Each bid must give its media type
+// in mtype or ext.prebid.type.
+func makeSimulatedBids(response *adapters.ResponseData) (*adapters.BidderResponse, []error) {
+	var bidResponse openrtb2.BidResponse
+	if err := jsonutil.Unmarshal(response.Body, &bidResponse); err != nil {
+		return nil, []error{&errortypes.BadInput{Message: "The simulated response is malformed: " + err.Error()}}
+	}
+
+	var errs []error
+	bidderResponse := adapters.NewBidderResponse()
+	bidderResponse.Currency = bidResponse.Cur
+	for _, seatBid := range bidResponse.SeatBid {
+		for i := range seatBid.Bid {
+			bidType, err := getMediaTypeForBid(seatBid.Bid[i])
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			bidderResponse.Bids = append(bidderResponse.Bids, &adapters.TypedBid{
+				Bid:     &seatBid.Bid[i],
+				BidType: bidType,
+			})
+		}
+	}
+	return bidderResponse, errs
+}
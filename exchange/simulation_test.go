@@ -0,0 +1,132 @@
+package exchange
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v2/adapters"
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/currency"
+	"github.com/prebid/prebid-server/v2/errortypes"
+	"github.com/prebid/prebid-server/v2/experiment/adscert"
+	"github.com/prebid/prebid-server/v2/hooks/hookexecution"
+	metricsConfig "github.com/prebid/prebid-server/v2/metrics/config"
+	"github.com/prebid/prebid-server/v2/openrtb_ext"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// uncalledBidder fails the test if the exchange asks it to make requests or read responses.
+type uncalledBidder struct {
+	t *testing.T
+}
+
+func (b uncalledBidder) MakeRequests(request *openrtb2.BidRequest, reqInfo *adapters.ExtraRequestInfo) ([]*adapters.RequestData, []error) {
+	b.t.Error("MakeRequests shouldn't be called in a simulated auction")
+	return nil, nil
+}
+
+func (b uncalledBidder) MakeBids(internalRequest *openrtb2.BidRequest, externalRequest *adapters.RequestData, response *adapters.ResponseData) (*adapters.BidderResponse, []error) {
+	b.t.Error("MakeBids shouldn't be called in a simulated auction")
+	return nil, nil
+}
+
+func TestRequestBidSimulated(t *testing.T) {
+	response := json.RawMessage(`{"id":"resp","cur":"USD","seatbid":[{"bid":[` +
+		`{"id":"bid-1","impid":"imp-1","price":1.5,"mtype":1},` +
+		`{"id":"bid-2","impid":"imp-1","price":2,"ext":{"prebid":{"type":"video"}}},` +
+		`{"id":"bid-3","impid":"imp-1","price":3}]}]}`)
+
+	bidder := AdaptBidder(uncalledBidder{t}, &http.Client{}, &config.Configuration{}, &metricsConfig.NilMetricsEngine{}, openrtb_ext.BidderAppnexus, nil, "")
+	currencyConverter := currency.NewRateConverter(&http.Client{}, "", time.Duration(0))
+	bidderRequest := BidderRequest{
+		BidRequest:        &openrtb2.BidRequest{ID: "req", Imp: []openrtb2.Imp{{ID: "imp-1", Banner: &openrtb2.Banner{}}}},
+		BidderName:        openrtb_ext.BidderAppnexus,
+		SimulatedResponse: response,
+	}
+
+	seatBids, _, errs := bidder.requestBid(
+		context.Background(),
+		bidderRequest,
+		currencyConverter.Rates(),
+		&adapters.ExtraRequestInfo{},
+		&adscert.NilSigner{},
+		bidRequestOptions{
+			headerDebugAllowed: true,
+			bidAdjustments:     map[string]float64{string(openrtb_ext.BidderAppnexus): 2.0},
+		},
+		openrtb_ext.ExtAlternateBidderCodes{},
+		&hookexecution.EmptyHookExecutor{},
+		nil,
+	)
+
+	assert.Equal(t, []error{&errortypes.BadServerResponse{Message: `Failed to parse bid mediatype for impression "imp-1"`}}, errs)
+	require.Len(t, seatBids, 1)
+	require.Len(t, seatBids[0].Bids, 2)
+	assert.Equal(t, "bid-1", seatBids[0].Bids[0].Bid.ID)
+	assert.Equal(t, openrtb_ext.BidTypeBanner, seatBids[0].Bids[0].BidType)
+	assert.Equal(t, 3.0, seatBids[0].Bids[0].Bid.Price, "The bid adjustment should be applied")
+	assert.Equal(t, "bid-2", seatBids[0].Bids[1].Bid.ID)
+	assert.Equal(t, openrtb_ext.BidTypeVideo, seatBids[0].Bids[1].BidType)
+
+	require.Len(t, seatBids[0].HttpCalls, 1)
+	assert.Equal(t, "simulated://appnexus", seatBids[0].HttpCalls[0].Uri)
+	assert.Equal(t, string(response), seatBids[0].HttpCalls[0].ResponseBody)
+}
+
+func TestMakeSimulatedBids(t *testing.T) {
+	testCases := []struct {
+		description      string
+		body             string
+		expectedResponse *adapters.BidderResponse
+		expectedErrs     []error
+	}{
+		{
+			description: "no-bids",
+			body:        `{}`,
+			expectedResponse: &adapters.BidderResponse{
+				Bids: make([]*adapters.TypedBid, 0),
+			},
+		},
+		{
+			description: "bids-in-several-seats",
+			body:        `{"cur":"EUR","seatbid":[{"bid":[{"id":"a","mtype":2}]},{"bid":[{"id":"b","mtype":4}]}]}`,
+			expectedResponse: &adapters.BidderResponse{
+				Currency: "EUR",
+				Bids: []*adapters.TypedBid{
+					{Bid: &openrtb2.Bid{ID: "a", MType: openrtb2.MarkupVideo}, BidType: openrtb_ext.BidTypeVideo},
+					{Bid: &openrtb2.Bid{ID: "b", MType: openrtb2.MarkupNative}, BidType: openrtb_ext.BidTypeNative},
+				},
+			},
+		},
+		{
+			description:  "malformed",
+			body:         `{"seatbid":{}}`,
+			expectedErrs: []error{&errortypes.BadInput{Message: "The simulated response is malformed: cannot unmarshal openrtb2.BidResponse.SeatBid: decode slice: expect [ or n, but found {"}},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			response, errs := makeSimulatedBids(&adapters.ResponseData{StatusCode: http.StatusOK, Body: []byte(test.body)})
+			assert.Equal(t, test.expectedErrs, errs)
+			assert.Equal(t, test.expectedResponse, response)
+		})
+	}
+}
+
+func TestSetSimulatedResponses(t *testing.T) {
+	bidderRequests := []BidderRequest{{BidderName: "appnexus"}, {BidderName: "rubicon"}}
+	setSimulatedResponses(bidderRequests, nil)
+	assert.Nil(t, bidderRequests[0].SimulatedResponse)
+	assert.Nil(t, bidderRequests[1].SimulatedResponse)
+
+	response := json.RawMessage(`{"id":"resp"}`)
+	setSimulatedResponses(bidderRequests, map[string]json.RawMessage{"appnexus": response})
+	assert.Equal(t, response, bidderRequests[0].SimulatedResponse)
+	assert.Equal(t, noSimulatedResponse, bidderRequests[1].SimulatedResponse, "Bidders without a response shouldn't be called")
+}
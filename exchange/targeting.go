@@ -6,6 +6,7 @@ import (
 
 	"github.com/prebid/openrtb/v20/openrtb2"
 	"github.com/prebid/prebid-server/v2/openrtb_ext"
+	"github.com/prebid/prebid-server/v2/priceencryption"
 )
 
 const MaxKeyLength = 20
@@ -30,6 +31,8 @@ type targetData struct {
 	// cacheHost and cachePath exist to supply cache host and path as targeting parameters
 	cacheHost string
 	cachePath string
+	// priceEncrypter encrypts the price of each bid for the hb_pb_enc key, if the account has price encryption on
+	priceEncrypter *priceencryption.Encrypter
 }
 
 // setTargeting writes all the targeting params into the bids.
@@ -68,6 +71,11 @@ func (targData *targetData) setTargeting(auc *auction, isApp bool, categoryMappi
 				if cpm, ok := auc.roundedPrices[topBid]; ok {
 					targData.addKeys(targets, openrtb_ext.HbpbConstantKey, cpm, targetingBidderCode, isOverallWinner, truncateTargetAttr, bidHasDeal)
 				}
+				if targData.priceEncrypter != nil {
+					if encryptedPrice, err := targData.priceEncrypter.Encrypt(topBid.Bid.Price); err == nil {
+						targData.addKeys(targets, openrtb_ext.HbPbEncKey, encryptedPrice, targetingBidderCode, isOverallWinner, truncateTargetAttr, bidHasDeal)
+					}
+				}
 				targData.addKeys(targets, openrtb_ext.HbBidderConstantKey, string(targetingBidderCode), targetingBidderCode, isOverallWinner, truncateTargetAttr, bidHasDeal)
 				if hbSize := makeHbSize(topBid.Bid); hbSize != "" {
 					targData.addKeys(targets, openrtb_ext.HbSizeConstantKey, hbSize, targetingBidderCode, isOverallWinner, truncateTargetAttr, bidHasDeal)
@@ -93,12 +101,22 @@ func (targData *targetData) setTargeting(auc *auction, isApp bool, categoryMappi
 					targData.addKeys(targets, openrtb_ext.HbDealIDConstantKey, topBid.Bid.DealID, targetingBidderCode, isOverallWinner, truncateTargetAttr, bidHasDeal)
 				}
 
+				if topBid.LineItem != nil {
+					targData.addKeys(targets, openrtb_ext.HbLineItemKey, topBid.LineItem.ID, targetingBidderCode, isOverallWinner, truncateTargetAttr, bidHasDeal)
+				}
+
 				if isApp {
 					targData.addKeys(targets, openrtb_ext.HbEnvKey, openrtb_ext.HbEnvKeyApp, targetingBidderCode, isOverallWinner, truncateTargetAttr, bidHasDeal)
 				}
 				if len(categoryMapping) > 0 {
 					targData.addKeys(targets, openrtb_ext.HbCategoryDurationKey, categoryMapping[topBid.Bid.ID], targetingBidderCode, isOverallWinner, truncateTargetAttr, bidHasDeal)
 				}
+				// keys hooks added to the bid are kept, unless the exchange sets them
+				for key, value := range topBid.BidTargets {
+					if _, ok := targets[key]; !ok {
+						targets[key] = value
+					}
+				}
 				topBid.BidTargets = targets
 			}
 		}
@@ -16,11 +16,13 @@ import (
 	"github.com/prebid/prebid-server/v2/hooks/hookexecution"
 	metricsConfig "github.com/prebid/prebid-server/v2/metrics/config"
 	"github.com/prebid/prebid-server/v2/openrtb_ext"
+	"github.com/prebid/prebid-server/v2/priceencryption"
 	"github.com/prebid/prebid-server/v2/util/jsonutil"
 	"github.com/prebid/prebid-server/v2/util/ptrutil"
 
 	"github.com/prebid/openrtb/v20/openrtb2"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // Using this set of bids in more than one test
@@ -1003,3 +1005,95 @@ func TestSetTargeting(t *testing.T) {
 		}
 	}
 }
+
+func TestSetTargetingLineItem(t *testing.T) {
+	lineItemBid := &entities.PbsOrtbBid{
+		Bid:      &openrtb2.Bid{ID: "bid-1", ImpID: "imp1", Price: 0.5, W: 300, H: 250, DealID: "deal-1"},
+		LineItem: &openrtb_ext.ExtBidPrebidLineItem{ID: "li-1", Priority: 1},
+	}
+	otherBid := &entities.PbsOrtbBid{Bid: &openrtb2.Bid{ID: "bid-2", ImpID: "imp1", Price: 2, W: 300, H: 250}}
+	auc := &auction{
+		winningBids: map[string]*entities.PbsOrtbBid{"imp1": lineItemBid},
+		allBidsByBidder: map[string]map[openrtb_ext.BidderName][]*entities.PbsOrtbBid{
+			"imp1": {
+				openrtb_ext.BidderAppnexus: {lineItemBid},
+				openrtb_ext.BidderRubicon:  {otherBid},
+			},
+		},
+	}
+	targData := &targetData{includeWinners: true, includeBidderKeys: true}
+
+	targData.setTargeting(auc, false, nil, nil, nil)
+
+	assert.Equal(t, map[string]string{
+		"hb_bidder":          "appnexus",
+		"hb_bidder_appnexus": "appnexus",
+		"hb_size":            "300x250",
+		"hb_size_appnexus":   "300x250",
+		"hb_deal":            "deal-1",
+		"hb_deal_appnexus":   "deal-1",
+		"hb_li":              "li-1",
+		"hb_li_appnexus":     "li-1",
+	}, lineItemBid.BidTargets)
+	assert.NotContains(t, otherBid.BidTargets, "hb_li_rubicon")
+}
+
+func TestSetTargetingPriceEncryption(t *testing.T) {
+	encrypter, err := priceencryption.NewEncrypter(config.AccountPriceEncryption{
+		Enabled:       true,
+		EncryptionKey: "skU7Ax_NL5pPAFyKdkfZjZz2-VhIN8bjj1rVFOaJ_5o=",
+		IntegrityKey:  "arO23ykdNqUQ5LEoQ0FVmPkBd7xB5CO89PDZlSjpFxo=",
+	})
+	require.NoError(t, err)
+	winningBid := &entities.PbsOrtbBid{Bid: &openrtb2.Bid{ID: "bid-1", ImpID: "imp1", Price: 2.57}}
+	otherBid := &entities.PbsOrtbBid{Bid: &openrtb2.Bid{ID: "bid-2", ImpID: "imp1", Price: 1.12}}
+	auc := &auction{
+		winningBids: map[string]*entities.PbsOrtbBid{"imp1": winningBid},
+		allBidsByBidder: map[string]map[openrtb_ext.BidderName][]*entities.PbsOrtbBid{
+			"imp1": {
+				openrtb_ext.BidderAppnexus: {winningBid},
+				openrtb_ext.BidderRubicon:  {otherBid},
+			},
+		},
+	}
+	targData := &targetData{includeWinners: true, includeBidderKeys: true, priceEncrypter: encrypter}
+
+	targData.setTargeting(auc, false, nil, nil, nil)
+
+	for _, test := range []struct {
+		bid           *entities.PbsOrtbBid
+		key           string
+		expectedPrice float64
+	}{
+		{bid: winningBid, key: "hb_pb_enc", expectedPrice: 2.57},
+		{bid: winningBid, key: "hb_pb_enc_appnexus", expectedPrice: 2.57},
+		{bid: otherBid, key: "hb_pb_enc_rubicon", expectedPrice: 1.12},
+	} {
+		require.Contains(t, test.bid.BidTargets, test.key)
+		price, err := encrypter.Decrypt(test.bid.BidTargets[test.key])
+		assert.NoError(t, err, test.key)
+		assert.Equal(t, test.expectedPrice, price, test.key)
+	}
+	assert.NotContains(t, otherBid.BidTargets, "hb_pb_enc")
+}
+
+func TestSetTargetingKeepsHookKeys(t *testing.T) {
+	winningBid := &entities.PbsOrtbBid{
+		Bid:        &openrtb2.Bid{ID: "bid-1", ImpID: "imp1", Price: 1.23},
+		BidTargets: map[string]string{"hb_flag": "1", "hb_bidder": "hook"},
+	}
+	auc := &auction{
+		winningBids: map[string]*entities.PbsOrtbBid{"imp1": winningBid},
+		allBidsByBidder: map[string]map[openrtb_ext.BidderName][]*entities.PbsOrtbBid{
+			"imp1": {openrtb_ext.BidderAppnexus: {winningBid}},
+		},
+	}
+	targData := &targetData{includeWinners: true}
+
+	targData.setTargeting(auc, false, nil, nil, nil)
+
+	assert.Equal(t, map[string]string{
+		"hb_flag":   "1",
+		"hb_bidder": "appnexus",
+	}, winningBid.BidTargets)
+}
@@ -0,0 +1,66 @@
+package exchange
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v2/errortypes"
+	"github.com/prebid/prebid-server/v2/exchange/inprocess"
+	"github.com/prebid/prebid-server/v2/util/jsonutil"
+)
+
+// isTestTraffic returns true if the auction is for a request with test=1, and test traffic is kept apart
+// from production traffic.
+func (e *exchange) isTestTraffic(r *AuctionRequest) bool {
+	return e.testTraffic.Enabled && r.BidRequestWrapper.Test == 1
+}
+
+// routeTestTraffic leaves out the requests of a test auction to bidders which don't accept test traffic, or
+// gives them a synthesized response instead if test_traffic.synthesize_bids is on. Simulated requests
+// aren't sent to the bidder, so they're kept.
+func (e *exchange) routeTestTraffic(bidderRequests []BidderRequest) ([]BidderRequest, []error) {
+	var errs []error
+	routed := bidderRequests[:0]
+	for _, bidderRequest := range bidderRequests {
+		if bidderRequest.SimulatedResponse == nil && !e.acceptsTestTraffic(bidderRequest) {
+			if !e.testTraffic.SynthesizeBids {
+				errs = append(errs, &errortypes.Warning{
+					Message:     fmt.Sprintf("%s was left out of the test auction, since it doesn't accept test traffic", bidderRequest.BidderName),
+					WarningCode: errortypes.TestTrafficWarningCode,
+				})
+				continue
+			}
+			response, err := synthesizeResponse(bidderRequest.BidRequest, e.testTraffic.SynthesizedBidPrice)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			bidderRequest.SimulatedResponse = response
+			errs = append(errs, &errortypes.Warning{
+				Message:     fmt.Sprintf("%s doesn't accept test traffic, and was answered with synthesized bids", bidderRequest.BidderName),
+				WarningCode: errortypes.TestTrafficWarningCode,
+			})
+		}
+		routed = append(routed, bidderRequest)
+	}
+	return routed, errs
+}
+
+// acceptsTestTraffic returns true if the bidder, or the bidder a request alias is for, accepts test traffic.
+func (e *exchange) acceptsTestTraffic(bidderRequest BidderRequest) bool {
+	if info, ok := e.bidderInfo[bidderRequest.BidderName.String()]; ok {
+		return info.AcceptsTestTraffic
+	}
+	return e.bidderInfo[bidderRequest.BidderCoreName.String()].AcceptsTestTraffic
+}
+
+// synthesizeResponse returns a response to the request with a bid at the price on every imp, made up by the
+// in-process mock bidder.
+func synthesizeResponse(request *openrtb2.BidRequest, price float64) (json.RawMessage, error) {
+	response := inprocess.MockBidder{Price: price}.Respond(request)
+	if response == nil {
+		return noSimulatedResponse, nil
+	}
+	return jsonutil.Marshal(response)
+}
@@ -0,0 +1,101 @@
+package exchange
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/errortypes"
+	"github.com/prebid/prebid-server/v2/openrtb_ext"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsTestTraffic(t *testing.T) {
+	testCases := []struct {
+		name     string
+		cfg      config.TestTraffic
+		test     int8
+		expected bool
+	}{
+		{
+			name:     "test",
+			cfg:      config.TestTraffic{Enabled: true},
+			test:     1,
+			expected: true,
+		},
+		{
+			name: "production",
+			cfg:  config.TestTraffic{Enabled: true},
+			test: 0,
+		},
+		{
+			name: "disabled",
+			cfg:  config.TestTraffic{Enabled: false},
+			test: 1,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			e := &exchange{testTraffic: test.cfg}
+			r := &AuctionRequest{BidRequestWrapper: &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{Test: test.test}}}
+			assert.Equal(t, test.expected, e.isTestTraffic(r))
+		})
+	}
+}
+
+func TestRouteTestTraffic(t *testing.T) {
+	bidRequest := &openrtb2.BidRequest{ID: "request", Imp: []openrtb2.Imp{{ID: "imp", Banner: &openrtb2.Banner{Format: []openrtb2.Format{{W: 300, H: 250}}}}}}
+	simulated := json.RawMessage(`{"seatbid":[]}`)
+	bidderInfos := config.BidderInfos{
+		"appnexus": {AcceptsTestTraffic: true},
+		"rubicon":  {AcceptsTestTraffic: false},
+	}
+	bidderRequests := func() []BidderRequest {
+		return []BidderRequest{
+			{BidderName: "appnexus", BidderCoreName: "appnexus", BidRequest: bidRequest},
+			{BidderName: "rubicon", BidderCoreName: "rubicon", BidRequest: bidRequest},
+			{BidderName: "requestAlias", BidderCoreName: "appnexus", BidRequest: bidRequest},
+			{BidderName: "pubmatic", BidderCoreName: "pubmatic", BidRequest: bidRequest, SimulatedResponse: simulated},
+		}
+	}
+
+	t.Run("left-out", func(t *testing.T) {
+		e := &exchange{bidderInfo: bidderInfos, testTraffic: config.TestTraffic{Enabled: true}}
+
+		routed, errs := e.routeTestTraffic(bidderRequests())
+
+		require.Len(t, routed, 3)
+		assert.Equal(t, openrtb_ext.BidderName("appnexus"), routed[0].BidderName)
+		assert.Equal(t, openrtb_ext.BidderName("requestAlias"), routed[1].BidderName)
+		assert.Equal(t, openrtb_ext.BidderName("pubmatic"), routed[2].BidderName)
+		assert.Nil(t, routed[0].SimulatedResponse)
+		assert.Equal(t, simulated, routed[2].SimulatedResponse)
+		require.Len(t, errs, 1)
+		assert.Equal(t, errortypes.TestTrafficWarningCode, errortypes.ReadCode(errs[0]))
+		assert.Equal(t, "rubicon was left out of the test auction, since it doesn't accept test traffic", errs[0].Error())
+	})
+
+	t.Run("synthesized", func(t *testing.T) {
+		e := &exchange{bidderInfo: bidderInfos, testTraffic: config.TestTraffic{Enabled: true, SynthesizeBids: true, SynthesizedBidPrice: 2.5}}
+
+		routed, errs := e.routeTestTraffic(bidderRequests())
+
+		require.Len(t, routed, 4)
+		assert.Nil(t, routed[0].SimulatedResponse)
+		assert.JSONEq(t, `{"id":"request","seatbid":[{"bid":[{"id":"mock-bid-0","impid":"imp","price":2.5,"adm":"<div>mock ad</div>","adomain":["mock-advertiser.com"],"crid":"mock-creative","w":300,"h":250,"mtype":1}]}],"cur":"USD"}`, string(routed[1].SimulatedResponse))
+		assert.Nil(t, routed[2].SimulatedResponse)
+		assert.Equal(t, simulated, routed[3].SimulatedResponse)
+		require.Len(t, errs, 1)
+		assert.Equal(t, errortypes.TestTrafficWarningCode, errortypes.ReadCode(errs[0]))
+		assert.Equal(t, "rubicon doesn't accept test traffic, and was answered with synthesized bids", errs[0].Error())
+	})
+}
+
+func TestSynthesizeResponseWithoutImps(t *testing.T) {
+	response, err := synthesizeResponse(&openrtb2.BidRequest{ID: "request"}, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, noSimulatedResponse, response)
+}
@@ -0,0 +1,131 @@
+package exchange
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/buger/jsonparser"
+	"github.com/prebid/prebid-server/v2/adapters"
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/macros"
+	"github.com/prebid/prebid-server/v2/metrics"
+	"github.com/prebid/prebid-server/v2/openrtb_ext"
+	"github.com/prebid/prebid-server/v2/ratelimit"
+)
+
+// defaultTimeoutNotificationTimeout is how long a timeout notification may take if bidder timeout
+// notifications aren't enabled.
+const defaultTimeoutNotificationTimeout = 200 * time.Millisecond
+
+// timeoutNotificationMaker makes the notification for a bidder request which timed out.
+type timeoutNotificationMaker interface {
+	MakeTimeoutNotification(req *adapters.RequestData) (*adapters.RequestData, []error)
+}
+
+// timeoutNotifier sends the timeout notifications of all bidders, whether their adapters make them or their
+// info has a notification URL, and keeps each bidder within a rate limit so a bidder which is timing out
+// isn't flooded with notifications as well.
+//
+// A nil *timeoutNotifier is valid. It sends only the notifications adapters make, without a limit.
+type timeoutNotifier struct {
+	limiter      *ratelimit.Limiter
+	maxPerSecond int64
+	timeout      time.Duration
+	urls         map[openrtb_ext.BidderName]*template.Template
+}
+
+// newTimeoutNotifier returns the shared timeout notifier, or nil if bidder timeout notifications aren't
+// enabled.
+func newTimeoutNotifier(cfg config.BidderTimeoutNotifications, infos config.BidderInfos, me metrics.MetricsEngine) (*timeoutNotifier, []error) {
+	if !cfg.Enabled {
+		return nil, nil
+	}
+
+	var errs []error
+	urls := make(map[openrtb_ext.BidderName]*template.Template)
+	for bidderName, info := range infos {
+		if info.TimeoutNotificationURL == "" || !info.IsEnabled() {
+			continue
+		}
+		urlTemplate, err := template.New("timeoutNotificationTemplate").Parse(info.TimeoutNotificationURL)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: unable to parse the timeout notification url: %v", bidderName, err))
+			continue
+		}
+		urls[openrtb_ext.BidderName(bidderName)] = urlTemplate
+	}
+
+	return &timeoutNotifier{
+		// the limit is kept by each instance, since it's there to protect bidders from bursts rather than
+		// to be exact
+		limiter:      ratelimit.NewLocalLimiter(me),
+		maxPerSecond: int64(cfg.MaxPerSecond),
+		timeout:      time.Duration(cfg.TimeoutMs) * time.Millisecond,
+		urls:         urls,
+	}, errs
+}
+
+// urlNotification returns what makes the notifications to the bidder's notification URL, or nil if it
+// doesn't have one.
+func (n *timeoutNotifier) urlNotification(bidderName openrtb_ext.BidderName) timeoutNotificationMaker {
+	if n == nil {
+		return nil
+	}
+	urlTemplate, ok := n.urls[bidderName]
+	if !ok {
+		return nil
+	}
+	return &urlTimeoutNotification{bidderName: bidderName, urlTemplate: urlTemplate}
+}
+
+// allow counts a notification to the bidder, and reports whether it's within the bidder's rate limit.
+func (n *timeoutNotifier) allow(bidderName openrtb_ext.BidderName) bool {
+	if n == nil || n.maxPerSecond == 0 {
+		return true
+	}
+	return n.limiter.Allow(context.Background(), metrics.RateLimitTimeoutNotification, bidderName.String(), n.maxPerSecond, time.Second)
+}
+
+// notificationTimeout returns how long a notification may take.
+func (n *timeoutNotifier) notificationTimeout() time.Duration {
+	if n == nil {
+		return defaultTimeoutNotificationTimeout
+	}
+	return n.timeout
+}
+
+// urlTimeoutNotification makes notifications to the URL of a bidder's info, with the macros of the
+// request which timed out resolved.
+type urlTimeoutNotification struct {
+	bidderName  openrtb_ext.BidderName
+	urlTemplate *template.Template
+}
+
+func (n *urlTimeoutNotification) MakeTimeoutNotification(req *adapters.RequestData) (*adapters.RequestData, []error) {
+	params := macros.TimeoutNotificationTemplateParams{Bidder: url.QueryEscape(n.bidderName.String())}
+	if id, err := jsonparser.GetString(req.Body, "id"); err == nil {
+		params.RequestID = url.QueryEscape(id)
+	}
+	var impIDs []string
+	jsonparser.ArrayEach(req.Body, func(imp []byte, _ jsonparser.ValueType, _ int, _ error) {
+		if id, err := jsonparser.GetString(imp, "id"); err == nil {
+			impIDs = append(impIDs, id)
+		}
+	}, "imp")
+	params.ImpIDs = url.QueryEscape(strings.Join(impIDs, ","))
+	if tmax, err := jsonparser.GetInt(req.Body, "tmax"); err == nil {
+		params.Timeout = strconv.FormatInt(tmax, 10)
+	}
+
+	uri, err := macros.ResolveMacros(n.urlTemplate, params)
+	if err != nil {
+		return nil, []error{err}
+	}
+	return &adapters.RequestData{Method: http.MethodGet, Uri: uri, Headers: http.Header{}}, nil
+}
@@ -0,0 +1,116 @@
+package exchange
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prebid/prebid-server/v2/adapters"
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/logger"
+	"github.com/prebid/prebid-server/v2/metrics"
+	metricsConfig "github.com/prebid/prebid-server/v2/metrics/config"
+	"github.com/prebid/prebid-server/v2/openrtb_ext"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewTimeoutNotifier(t *testing.T) {
+	infos := config.BidderInfos{
+		"appnexus": {TimeoutNotificationURL: "https://appnexus.example.com/timeout?id={{.RequestID}}"},
+		"rubicon":  {TimeoutNotificationURL: "https://rubicon.example.com/timeout", Disabled: true},
+		"openx":    {},
+	}
+	enabled := config.BidderTimeoutNotifications{Enabled: true, MaxPerSecond: 10, TimeoutMs: 50}
+
+	notifier, errs := newTimeoutNotifier(config.BidderTimeoutNotifications{}, infos, &metricsConfig.NilMetricsEngine{})
+	assert.Nil(t, notifier, "disabled")
+	assert.Empty(t, errs)
+
+	notifier, errs = newTimeoutNotifier(enabled, infos, &metricsConfig.NilMetricsEngine{})
+	require.NotNil(t, notifier)
+	assert.Empty(t, errs)
+	assert.Equal(t, int64(10), notifier.maxPerSecond)
+	assert.Equal(t, 50*time.Millisecond, notifier.notificationTimeout())
+	assert.NotNil(t, notifier.urlNotification(openrtb_ext.BidderAppnexus))
+	assert.Nil(t, notifier.urlNotification(openrtb_ext.BidderRubicon), "disabled bidder")
+	assert.Nil(t, notifier.urlNotification(openrtb_ext.BidderOpenx), "bidder without a url")
+
+	_, errs = newTimeoutNotifier(enabled, config.BidderInfos{"appnexus": {TimeoutNotificationURL: "{{.RequestID"}}, &metricsConfig.NilMetricsEngine{})
+	assert.Len(t, errs, 1)
+}
+
+func TestNilTimeoutNotifier(t *testing.T) {
+	var notifier *timeoutNotifier
+
+	assert.True(t, notifier.allow(openrtb_ext.BidderAppnexus))
+	assert.Nil(t, notifier.urlNotification(openrtb_ext.BidderAppnexus))
+	assert.Equal(t, defaultTimeoutNotificationTimeout, notifier.notificationTimeout())
+}
+
+func TestTimeoutNotifierAllow(t *testing.T) {
+	me := &metrics.MetricsEngineMock{}
+	me.On("RecordRateLimit", metrics.RateLimitTimeoutNotification, metrics.RateLimitAllowed).Times(3)
+	me.On("RecordRateLimit", metrics.RateLimitTimeoutNotification, metrics.RateLimitLimited).Once()
+	notifier, _ := newTimeoutNotifier(config.BidderTimeoutNotifications{Enabled: true, MaxPerSecond: 2, TimeoutMs: 200}, nil, me)
+
+	assert.True(t, notifier.allow(openrtb_ext.BidderAppnexus))
+	assert.True(t, notifier.allow(openrtb_ext.BidderAppnexus))
+	assert.True(t, notifier.allow(openrtb_ext.BidderRubicon), "each bidder has its own limit")
+	assert.False(t, notifier.allow(openrtb_ext.BidderAppnexus))
+	me.AssertExpectations(t)
+
+	unlimited, _ := newTimeoutNotifier(config.BidderTimeoutNotifications{Enabled: true, TimeoutMs: 200}, nil, me)
+	assert.True(t, unlimited.allow(openrtb_ext.BidderAppnexus))
+}
+
+func TestURLTimeoutNotification(t *testing.T) {
+	infos := config.BidderInfos{
+		"appnexus": {TimeoutNotificationURL: "https://appnexus.example.com/timeout?bidder={{.Bidder}}&id={{.RequestID}}&imps={{.ImpIDs}}&tmax={{.Timeout}}"},
+	}
+	notifier, _ := newTimeoutNotifier(config.BidderTimeoutNotifications{Enabled: true, TimeoutMs: 200}, infos, &metricsConfig.NilMetricsEngine{})
+
+	notification, errs := notifier.urlNotification(openrtb_ext.BidderAppnexus).MakeTimeoutNotification(&adapters.RequestData{
+		Body: []byte(`{"id":"req 1","imp":[{"id":"a"},{"id":"b&c"}],"tmax":500}`),
+	})
+
+	assert.Empty(t, errs)
+	assert.Equal(t, http.MethodGet, notification.Method)
+	assert.Equal(t, "https://appnexus.example.com/timeout?bidder=appnexus&id=req+1&imps=a%2Cb%26c&tmax=500", notification.Uri)
+}
+
+func TestBidderTimeoutNotification(t *testing.T) {
+	notifier, _ := newTimeoutNotifier(config.BidderTimeoutNotifications{Enabled: true, TimeoutMs: 200}, config.BidderInfos{
+		"appnexus": {TimeoutNotificationURL: "https://appnexus.example.com/timeout"},
+	}, &metricsConfig.NilMetricsEngine{})
+
+	adapterNotifications := &bidderAdapter{Bidder: wrapWithBidderInfo(&notifyingBidder{}), BidderName: openrtb_ext.BidderAppnexus, timeoutNotifier: notifier}
+	assert.IsType(t, &notifyingBidder{}, adapterNotifications.timeoutNotification(), "the adapter's own notifications come first")
+
+	urlNotifications := &bidderAdapter{Bidder: &mixedMultiBidder{}, BidderName: openrtb_ext.BidderAppnexus, timeoutNotifier: notifier}
+	assert.IsType(t, &urlTimeoutNotification{}, urlNotifications.timeoutNotification())
+
+	noNotifications := &bidderAdapter{Bidder: &mixedMultiBidder{}, BidderName: openrtb_ext.BidderRubicon, timeoutNotifier: notifier}
+	assert.Nil(t, noNotifications.timeoutNotification())
+}
+
+func TestURLTimeoutNotificationSent(t *testing.T) {
+	received := make(chan string, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received <- r.URL.RequestURI()
+	}))
+	defer server.Close()
+
+	notifier, _ := newTimeoutNotifier(config.BidderTimeoutNotifications{Enabled: true, TimeoutMs: 1000}, config.BidderInfos{
+		"appnexus": {TimeoutNotificationURL: server.URL + "/timeout?id={{.RequestID}}"},
+	}, &metricsConfig.NilMetricsEngine{})
+	me := &metrics.MetricsEngineMock{}
+	me.On("RecordTimeoutNotice", true).Once()
+	bidder := &bidderAdapter{Bidder: &mixedMultiBidder{}, BidderName: openrtb_ext.BidderAppnexus, Client: server.Client(), me: me, timeoutNotifier: notifier}
+
+	bidder.doTimeoutNotification(bidder.timeoutNotification(), &adapters.RequestData{Body: []byte(`{"id":"1"}`)}, logger.Warningf)
+
+	assert.Equal(t, "/timeout?id=1", <-received)
+	me.AssertExpectations(t)
+}
@@ -9,14 +9,13 @@ import (
 	"math/rand"
 	"strings"
 
-	"github.com/prebid/prebid-server/v2/ortb"
-
 	"github.com/buger/jsonparser"
 	"github.com/prebid/go-gdpr/vendorconsent"
 	gpplib "github.com/prebid/go-gpp"
 	gppConstants "github.com/prebid/go-gpp/constants"
 	"github.com/prebid/openrtb/v20/openrtb2"
 
+	"github.com/prebid/prebid-server/v2/auctionquality"
 	"github.com/prebid/prebid-server/v2/config"
 	"github.com/prebid/prebid-server/v2/errortypes"
 	"github.com/prebid/prebid-server/v2/firstpartydata"
@@ -49,6 +48,7 @@ type requestSplitter struct {
 	gdprPermsBuilder  gdpr.PermissionsBuilder
 	hostSChainNode    *openrtb2.SupplyChainNode
 	bidderInfo        config.BidderInfos
+	auctionQuality    *auctionquality.Tracker
 }
 
 // cleanOpenRTBRequests splits the input request into requests which are sanitized for each bidder. Intended behavior is:
@@ -88,6 +88,7 @@ func (rs *requestSplitter) cleanOpenRTBRequests(ctx context.Context,
 	bidderNameToBidderReq := buildBidResponseRequest(req.BidRequest, bidderImpWithBidResp, aliases, auctionReq.BidderImpReplaceImpID)
 	//this function should be executed after getAuctionBidderRequests
 	allBidderRequests = mergeBidderRequests(allBidderRequests, bidderNameToBidderReq)
+	rs.applyMultiformatPolicy(allBidderRequests, auctionReq.Account.Multiformat)
 
 	var gpp gpplib.GppContainer
 	if req.BidRequest.Regs != nil && len(req.BidRequest.Regs.GPP) > 0 {
@@ -162,6 +163,7 @@ func (rs *requestSplitter) cleanOpenRTBRequests(ctx context.Context,
 		if !fetchBidsActivityAllowed {
 			// skip the call to a bidder if fetchBids activity is not allowed
 			// do not add this bidder to allowedBidderRequests
+			rs.auctionQuality.RecordPrivacyBlock(auctionReq.Account.ID, bidderRequest.BidderName.String())
 			continue
 		}
 
@@ -174,6 +176,7 @@ func (rs *requestSplitter) cleanOpenRTBRequests(ctx context.Context,
 				// auction request is not permitted by GDPR
 				// do not add this bidder to allowedBidderRequests
 				rs.me.RecordAdapterGDPRRequestBlocked(bidderRequest.BidderCoreName)
+				rs.auctionQuality.RecordPrivacyBlock(auctionReq.Account.ID, bidderRequest.BidderName.String())
 				continue
 			}
 		}
@@ -183,8 +186,11 @@ func (rs *requestSplitter) cleanOpenRTBRequests(ctx context.Context,
 		// FPD should be applied before policies, otherwise it overrides policies and activities restricted data
 		applyFPD(auctionReq.FirstPartyData, bidderRequest)
 
+		// the privacy scrubbers are copy-on-write, so a shallow copy is enough to keep bidders from
+		// seeing each other's changes while sharing the device, user and source objects they leave alone
+		reqCopy := *bidderRequest.BidRequest
 		reqWrapper := &openrtb_ext.RequestWrapper{
-			BidRequest: ortb.CloneBidRequestPartial(bidderRequest.BidRequest),
+			BidRequest: &reqCopy,
 		}
 
 		passIDActivityAllowed := auctionReq.Activities.Allow(privacy.ActivityTransmitUserFPD, scopedName, privacy.NewRequestFromBidRequest(*req))
@@ -230,6 +236,19 @@ func (rs *requestSplitter) cleanOpenRTBRequests(ctx context.Context,
 		reqWrapper.RebuildRequest()
 		bidderRequest.BidRequest = reqWrapper.BidRequest
 
+		if policy, ok := auctionReq.Account.BidderFields.Policy(bidderRequest.BidderName.String()); ok {
+			withheld, fields, err := withholdBidderFields(bidderRequest.BidRequest, policy)
+			if err != nil {
+				// the bidder isn't called with fields the account withholds from it
+				errs = append(errs, fmt.Errorf("unable to withhold fields from %s: %v", bidderRequest.BidderName, err))
+				continue
+			}
+			if fields > 0 {
+				rs.me.RecordAdapterFieldsWithheld(bidderRequest.BidderName, fields)
+			}
+			bidderRequest.BidRequest = withheld
+		}
+
 		allowedBidderRequests = append(allowedBidderRequests, bidderRequest)
 
 		// GPP downgrade: always downgrade unless we can confirm GPP is supported
@@ -331,7 +350,7 @@ func getAuctionBidderRequests(auctionRequest AuctionRequest,
 		return nil, []error{err}
 	}
 
-	sChainWriter, err := schain.NewSChainWriter(requestExt, hostSChainNode)
+	sChainWriter, err := schain.NewSChainWriter(requestExt, hostSChainNode, auctionRequest.Account.SChain)
 	if err != nil {
 		return nil, []error{err}
 	}
@@ -349,7 +368,12 @@ func getAuctionBidderRequests(auctionRequest AuctionRequest,
 		reqCopy := *req.BidRequest
 		reqCopy.Imp = imps
 
-		sChainWriter.Write(&reqCopy, bidder)
+		if err := sChainWriter.Write(&reqCopy, bidder); err != nil {
+			errs = append(errs, err)
+			if !errortypes.IsWarning(err) {
+				continue
+			}
+		}
 
 		reqCopy.Ext, err = buildRequestExtForBidder(bidder, req.BidRequest.Ext, requestExt, bidderParamsInReqExt, auctionRequest.Account.AlternateBidderCodes)
 		if err != nil {
@@ -13,6 +13,7 @@ import (
 	gpplib "github.com/prebid/go-gpp"
 	"github.com/prebid/go-gpp/constants"
 	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v2/auctionquality"
 	"github.com/prebid/prebid-server/v2/config"
 	"github.com/prebid/prebid-server/v2/errortypes"
 	"github.com/prebid/prebid-server/v2/firstpartydata"
@@ -1184,6 +1185,7 @@ func TestCleanOpenRTBRequestsSChain(t *testing.T) {
 		description   string
 		inExt         json.RawMessage
 		inSourceExt   json.RawMessage
+		inAccount     config.AccountSChain
 		outRequestExt json.RawMessage
 		outSourceExt  json.RawMessage
 		hasError      bool
@@ -1209,6 +1211,14 @@ func TestCleanOpenRTBRequestsSChain(t *testing.T) {
 			outRequestExt: nil,
 			outSourceExt:  json.RawMessage(`{` + seller1SChain + `}`),
 		},
+		{
+			description:   "Account node appended to source.ext.schain",
+			inExt:         nil,
+			inSourceExt:   json.RawMessage(`{` + seller1SChain + `}`),
+			inAccount:     config.AccountSChain{ASI: "publisher.com", SID: "pub-1"},
+			outRequestExt: nil,
+			outSourceExt:  json.RawMessage(`{"schain":{"complete":1,"nodes":[{"asi":"directseller1.com","sid":"00001","rid":"BidRequest1","hp":1},{"asi":"publisher.com","sid":"pub-1","hp":1}],"ver":"1.0"}}`),
+		},
 		{
 			description:   "schainwriter instantation error -- multiple bidder schains in ext.prebid.schains.",
 			inExt:         json.RawMessage(`{"prebid":{"schains":[{"bidders":["appnexus"],` + seller1SChain + `},{"bidders":["appnexus"],` + seller2SChain + `}]}}`),
@@ -1237,6 +1247,7 @@ func TestCleanOpenRTBRequestsSChain(t *testing.T) {
 			BidRequestWrapper: &openrtb_ext.RequestWrapper{BidRequest: req},
 			UserSyncs:         &emptyUsersync{},
 			TCF2Config:        gdpr.NewTCF2Config(config.TCF2{}, config.AccountGDPR{}),
+			Account:           config.Account{SChain: test.inAccount},
 		}
 
 		gdprPermissionsBuilder := fakePermissionsBuilder{
@@ -1932,6 +1943,69 @@ func TestCleanOpenRTBRequestsLMT(t *testing.T) {
 	}
 }
 
+func TestCleanOpenRTBRequestsCopyOnWrite(t *testing.T) {
+	testCases := []struct {
+		description      string
+		enforceLMT       bool
+		expectSharedData bool
+	}{
+		{
+			description:      "Not Scrubbed - Device And Source Shared",
+			enforceLMT:       false,
+			expectSharedData: true,
+		},
+		{
+			description:      "Scrubbed - Original Request Unchanged",
+			enforceLMT:       true,
+			expectSharedData: false,
+		},
+	}
+
+	for _, test := range testCases {
+		req := newBidRequest(t)
+		req.Device.Lmt = ptrutil.ToPtr[int8](1)
+		req.Imp[0].Ext = json.RawMessage(`{"prebid":{"tid":"1234567", "bidder":{"appnexus": {"placementId": 1}, "rubicon": {}}}}`)
+
+		auctionReq := AuctionRequest{
+			BidRequestWrapper: &openrtb_ext.RequestWrapper{BidRequest: req},
+			UserSyncs:         &emptyUsersync{},
+			TCF2Config:        gdpr.NewTCF2Config(config.TCF2{}, config.AccountGDPR{}),
+		}
+
+		gdprPermissionsBuilder := fakePermissionsBuilder{
+			permissions: &permissionsMock{
+				allowAllBidders: true,
+			},
+		}.Builder
+
+		reqSplitter := &requestSplitter{
+			bidderToSyncerKey: map[string]string{},
+			me:                &metrics.MetricsEngineMock{},
+			privacyConfig:     config.Privacy{LMT: config.LMT{Enforce: test.enforceLMT}},
+			gdprPermsBuilder:  gdprPermissionsBuilder,
+			hostSChainNode:    nil,
+			bidderInfo:        config.BidderInfos{},
+		}
+
+		results, _, errs := reqSplitter.cleanOpenRTBRequests(context.Background(), auctionReq, nil, gdpr.SignalNo, map[string]float64{})
+
+		assert.Nil(t, errs, test.description)
+		assert.Len(t, results, 2, test.description)
+		for _, result := range results {
+			if test.expectSharedData {
+				assert.Same(t, req.Device, result.BidRequest.Device, test.description+":Device")
+				assert.Same(t, req.Source, result.BidRequest.Source, test.description+":Source")
+			} else {
+				assert.NotSame(t, req.Device, result.BidRequest.Device, test.description+":Device")
+				assert.Empty(t, result.BidRequest.Device.IFA, test.description+":Device.IFA")
+			}
+		}
+		assert.Equal(t, newBidRequest(t).Device.IFA, req.Device.IFA, test.description+":Original Device.IFA")
+		assert.Equal(t, newBidRequest(t).Device.Geo, req.Device.Geo, test.description+":Original Device.Geo")
+		assert.Equal(t, newBidRequest(t).User, req.User, test.description+":Original User")
+	}
+}
+
 func TestCleanOpenRTBRequestsGDPR(t *testing.T) {
 	tcf2Consent := "COzTVhaOzTVhaGvAAAENAiCIAP_AAH_AAAAAAEEUACCKAAA"
 	trueValue, falseValue := true, false
@@ -4525,6 +4599,8 @@ func TestCleanOpenRTBRequestsActivities(t *testing.T) {
 		expectedDevice    openrtb2.Device
 		expectedSource    openrtb2.Source
 		expectedImpExt    json.RawMessage
+		// expectedPrivacyBlocks are the bidders counted as blocked in the auction quality statistics
+		expectedPrivacyBlocks []auctionquality.BidderRow
 	}{
 		{
 			name:              "fetch_bids_request_with_one_bidder_allowed",
@@ -4543,6 +4619,9 @@ func TestCleanOpenRTBRequestsActivities(t *testing.T) {
 			expectedUser:      expectedUserDefault,
 			expectedDevice:    expectedDeviceDefault,
 			expectedSource:    expectedSourceDefault,
+			expectedPrivacyBlocks: []auctionquality.BidderRow{
+				{Bidder: "appnexus", PrivacyBlocks: 1},
+			},
 		},
 		{
 			name:              "transmit_ufpd_allowed",
@@ -4669,16 +4748,19 @@ func TestCleanOpenRTBRequestsActivities(t *testing.T) {
 			}
 
 			bidderToSyncerKey := map[string]string{}
+			tracker := auctionquality.New(config.AuctionQuality{Enabled: true, WindowMinutes: 1, MaxAccounts: 1})
 			reqSplitter := &requestSplitter{
 				bidderToSyncerKey: bidderToSyncerKey,
 				me:                &metrics.MetricsEngineMock{},
 				hostSChainNode:    nil,
 				bidderInfo:        config.BidderInfos{},
+				auctionQuality:    tracker,
 			}
 
 			bidderRequests, _, errs := reqSplitter.cleanOpenRTBRequests(context.Background(), auctionReq, nil, gdpr.SignalNo, map[string]float64{})
 			assert.Empty(t, errs)
 			assert.Len(t, bidderRequests, test.expectedReqNumber)
+			assert.ElementsMatch(t, test.expectedPrivacyBlocks, tracker.Report("").Bidders)
 
 			if test.expectedReqNumber == 1 {
 				assert.Equal(t, &test.expectedUser, bidderRequests[0].BidRequest.User)
@@ -5098,3 +5180,35 @@ func TestBuildBidResponseRequestBidderName(t *testing.T) {
 	assert.Equal(t, resultAppneXUS.ImpReplaceImpId, map[string]bool{"impId3": true, "impId4": false})
 
 }
+
+func BenchmarkCleanOpenRTBRequests(b *testing.B) {
+	bidders := []string{"appnexus", "rubicon", "openx", "pubmatic", "ix", "triplelift", "sovrn", "sharethrough"}
+	impExt := map[string]interface{}{}
+	for _, bidder := range bidders {
+		impExt[bidder] = map[string]interface{}{}
+	}
+	impExtJSON, _ := json.Marshal(map[string]interface{}{"prebid": map[string]interface{}{"bidder": impExt}})
+
+	reqSplitter := &requestSplitter{
+		bidderToSyncerKey: map[string]string{},
+		me:                &metrics.MetricsEngineMock{},
+		privacyConfig:     config.Privacy{},
+		gdprPermsBuilder:  fakePermissionsBuilder{permissions: &permissionsMock{allowAllBidders: true}}.Builder,
+		bidderInfo:        config.BidderInfos{},
+	}
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		req := newBidRequest(nil)
+		req.Imp[0].Ext = impExtJSON
+		auctionReq := AuctionRequest{
+			BidRequestWrapper: &openrtb_ext.RequestWrapper{BidRequest: req},
+			UserSyncs:         &emptyUsersync{},
+			TCF2Config:        gdpr.NewTCF2Config(config.TCF2{}, config.AccountGDPR{}),
+		}
+		b.StartTimer()
+
+		reqSplitter.cleanOpenRTBRequests(context.Background(), auctionReq, nil, gdpr.SignalNo, map[string]float64{})
+	}
+}
@@ -2,7 +2,8 @@ package adscert
 
 import (
 	"fmt"
-	"github.com/golang/glog"
+
+	"github.com/prebid/prebid-server/v2/logger"
 )
 
 type SignerLogger struct {
@@ -10,27 +11,27 @@ type SignerLogger struct {
 
 func (sl *SignerLogger) Debugf(format string, args ...interface{}) {
 	//there is no Debug level in glog
-	glog.Infof(format, args...)
+	logger.Infof(format, args...)
 }
 
 func (sl *SignerLogger) Infof(format string, args ...interface{}) {
-	glog.Infof(format, args...)
+	logger.Infof(format, args...)
 }
 
 func (sl *SignerLogger) Info(format string) {
-	glog.Info(format)
+	logger.Info(format)
 }
 
 func (sl *SignerLogger) Warningf(format string, args ...interface{}) {
-	glog.Warningf(format, args...)
+	logger.Warningf(format, args...)
 }
 
 func (sl *SignerLogger) Errorf(format string, args ...interface{}) {
-	glog.Errorf(format, args...)
+	logger.Errorf(format, args...)
 }
 
 func (sl *SignerLogger) Fatalf(format string, args ...interface{}) {
-	glog.Fatalf(format, args...)
+	logger.Fatalf(format, args...)
 }
 
 func (sl *SignerLogger) Panicf(format string, args ...interface{}) {
@@ -28,6 +28,11 @@ func (ips *inProcessSigner) Sign(destinationURL string, body []byte) (string, er
 	return getSignatureMessage(signatureResponse)
 }
 
+// Verify checks the adsCert header of received requests using in process go library
+func (ips *inProcessSigner) Verify(requestURL string, body []byte, signatures []string) error {
+	return verify(ips.signatory, requestURL, body, signatures)
+}
+
 func newInProcessSigner(inProcessSignerConfig config.AdsCertInProcess) (*inProcessSigner, error) {
 	return &inProcessSigner{
 		signatory: signatory.NewLocalAuthenticatedConnectionsSignatory(
@@ -28,6 +28,11 @@ func (rs *remoteSigner) Sign(destinationURL string, body []byte) (string, error)
 	return getSignatureMessage(signatureResponse)
 }
 
+// Verify checks the adsCert header of received requests using remote signing server
+func (rs *remoteSigner) Verify(requestURL string, body []byte, signatures []string) error {
+	return verify(rs.signatory, requestURL, body, signatures)
+}
+
 func newRemoteSigner(remoteSignerConfig config.AdsCertRemote) (*remoteSigner, error) {
 	// Establish the gRPC connection that the client will use to connect to the
 	// signatory server.  Secure connections are not implemented at this time.
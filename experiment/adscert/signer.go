@@ -1,6 +1,7 @@
 package adscert
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/IABTechLab/adscert/pkg/adscert/api"
@@ -16,6 +17,21 @@ type Signer interface {
 	Sign(destinationURL string, body []byte) (string, error)
 }
 
+// Verifier represents interface to check the Ads Cert signatures of received requests
+type Verifier interface {
+	// Verify returns an error unless one of the signatures is valid for the body of a request sent to requestURL
+	Verify(requestURL string, body []byte, signatures []string) error
+}
+
+// Signatory signs requests and checks the signatures of received requests with the same keys
+type Signatory interface {
+	Signer
+	Verifier
+}
+
+// ErrSignatureMissing is returned by Verify for requests without a signature
+var ErrSignatureMissing = errors.New("request has no " + SignHeader + " header")
+
 type NilSigner struct {
 }
 
@@ -23,7 +39,11 @@ func (ns *NilSigner) Sign(destinationURL string, body []byte) (string, error) {
 	return "", nil
 }
 
-func NewAdCertsSigner(experimentAdCertsConfig config.ExperimentAdsCert) (Signer, error) {
+func (ns *NilSigner) Verify(requestURL string, body []byte, signatures []string) error {
+	return errors.New("ads.cert signer is off")
+}
+
+func NewAdCertsSigner(experimentAdCertsConfig config.ExperimentAdsCert) (Signatory, error) {
 	logger.SetLoggerImpl(&SignerLogger{})
 	if experimentAdCertsConfig.Mode == config.AdCertsSignerModeInprocess {
 		return newInProcessSigner(experimentAdCertsConfig.InProcess)
@@ -50,3 +70,37 @@ func getSignatureMessage(signatureResponse *api.AuthenticatedConnectionSignature
 	}
 	return "", fmt.Errorf("error signing request: %s", signatureResponse.GetSignatureOperationStatus())
 }
+
+func verify(s signatory.AuthenticatedConnectionsSignatory, requestURL string, body []byte, signatures []string) error {
+	if len(signatures) == 0 {
+		return ErrSignatureMissing
+	}
+	reqInfo := createRequestInfo(requestURL, body)
+	signatory.SetRequestSignatures(reqInfo, signatures)
+	verificationResponse, err := s.VerifyAuthenticatedConnection(&api.AuthenticatedConnectionVerificationRequest{
+		RequestInfo: []*api.RequestInfo{reqInfo},
+	})
+	if err != nil {
+		return err
+	}
+	return getVerificationResult(verificationResponse)
+}
+
+// getVerificationResult accepts signatures whose body is valid, even if the URL isn't, since the URL the
+// request was sent to may have been rewritten by proxies on the way.
+func getVerificationResult(verificationResponse *api.AuthenticatedConnectionVerificationResponse) error {
+	if verificationResponse.GetVerificationOperationStatus() != api.VerificationOperationStatus_VERIFICATION_OPERATION_STATUS_OK {
+		return fmt.Errorf("error verifying request: %s", verificationResponse.GetVerificationOperationStatus())
+	}
+	status := api.SignatureDecodeStatus_SIGNATURE_DECODE_STATUS_SIGNATURE_NOT_PRESENT
+	for _, verificationInfo := range verificationResponse.GetVerificationInfo() {
+		for _, decodeStatus := range verificationInfo.GetSignatureDecodeStatus() {
+			if decodeStatus == api.SignatureDecodeStatus_SIGNATURE_DECODE_STATUS_BODY_AND_URL_VALID ||
+				decodeStatus == api.SignatureDecodeStatus_SIGNATURE_DECODE_STATUS_BODY_VALID {
+				return nil
+			}
+			status = decodeStatus
+		}
+	}
+	return fmt.Errorf("invalid signature: %s", status)
+}
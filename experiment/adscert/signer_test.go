@@ -30,6 +30,8 @@ func TestNilSignerForAdsCertDisabled(t *testing.T) {
 type MockLocalAuthenticatedConnectionsSignatory struct {
 	returnError       bool
 	operationStatusOk bool
+	decodeStatus      []api.SignatureDecodeStatus
+	verifyRequest     *api.AuthenticatedConnectionVerificationRequest
 }
 
 func (ips *MockLocalAuthenticatedConnectionsSignatory) SignAuthenticatedConnection(request *api.AuthenticatedConnectionSignatureRequest) (*api.AuthenticatedConnectionSignatureResponse, error) {
@@ -51,5 +53,89 @@ func (ips *MockLocalAuthenticatedConnectionsSignatory) SignAuthenticatedConnecti
 	return response, nil
 }
 func (ips *MockLocalAuthenticatedConnectionsSignatory) VerifyAuthenticatedConnection(request *api.AuthenticatedConnectionVerificationRequest) (*api.AuthenticatedConnectionVerificationResponse, error) {
-	return nil, nil
+	ips.verifyRequest = request
+	if ips.returnError {
+		return nil, errors.New("Test error")
+	}
+	response := &api.AuthenticatedConnectionVerificationResponse{
+		VerificationInfo: []*api.RequestVerificationInfo{{SignatureDecodeStatus: ips.decodeStatus}},
+	}
+	if ips.operationStatusOk {
+		response.VerificationOperationStatus = api.VerificationOperationStatus_VERIFICATION_OPERATION_STATUS_OK
+	}
+	return response, nil
+}
+
+func TestNilSignerVerify(t *testing.T) {
+	signer := &NilSigner{}
+	assert.EqualError(t, signer.Verify("https://pbs.com/openrtb2/auction", nil, []string{"sig"}), "ads.cert signer is off")
+}
+
+func TestVerify(t *testing.T) {
+	testCases := []struct {
+		desc              string
+		signatures        []string
+		generateError     bool
+		operationStatusOk bool
+		decodeStatus      []api.SignatureDecodeStatus
+		expectedErr       string
+	}{
+		{
+			desc:        "no signature",
+			expectedErr: "request has no X-Ads-Cert-Auth header",
+		},
+		{
+			desc:          "verifier error",
+			signatures:    []string{"sig"},
+			generateError: true,
+			expectedErr:   "Test error",
+		},
+		{
+			desc:        "verification operation error",
+			signatures:  []string{"sig"},
+			expectedErr: "error verifying request: VERIFICATION_OPERATION_STATUS_UNDEFINED",
+		},
+		{
+			desc:              "body and url valid",
+			signatures:        []string{"sig"},
+			operationStatusOk: true,
+			decodeStatus:      []api.SignatureDecodeStatus{api.SignatureDecodeStatus_SIGNATURE_DECODE_STATUS_BODY_AND_URL_VALID},
+		},
+		{
+			desc:              "body valid after an invalid signature",
+			signatures:        []string{"bad", "sig"},
+			operationStatusOk: true,
+			decodeStatus:      []api.SignatureDecodeStatus{api.SignatureDecodeStatus_SIGNATURE_DECODE_STATUS_INVALID_SIGNATURE, api.SignatureDecodeStatus_SIGNATURE_DECODE_STATUS_BODY_VALID},
+		},
+		{
+			desc:              "invalid signature",
+			signatures:        []string{"sig"},
+			operationStatusOk: true,
+			decodeStatus:      []api.SignatureDecodeStatus{api.SignatureDecodeStatus_SIGNATURE_DECODE_STATUS_COUNTERPARTY_LOOKUP_ERROR},
+			expectedErr:       "invalid signature: SIGNATURE_DECODE_STATUS_COUNTERPARTY_LOOKUP_ERROR",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			signatory := &MockLocalAuthenticatedConnectionsSignatory{
+				returnError:       test.generateError,
+				operationStatusOk: test.operationStatusOk,
+				decodeStatus:      test.decodeStatus,
+			}
+			err := verify(signatory, "https://pbs.com/openrtb2/auction", []byte(`{}`), test.signatures)
+			if test.expectedErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, test.expectedErr)
+			}
+			if len(test.signatures) > 0 {
+				if assert.Len(t, signatory.verifyRequest.RequestInfo, 1) {
+					requestInfo := signatory.verifyRequest.RequestInfo[0]
+					assert.Equal(t, "pbs.com", requestInfo.InvokingDomain)
+					assert.Len(t, requestInfo.SignatureInfo, len(test.signatures))
+				}
+			}
+		})
+	}
 }
@@ -0,0 +1,60 @@
+// Package faultinjection adds artificial latency, errors and malformed responses to the calls the server
+// makes to bidders, stored data backends and the cache, so timeouts, fallbacks and error handling can be
+// tested in staging. It must never be enabled in production.
+package faultinjection
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"time"
+
+	"github.com/prebid/prebid-server/v2/config"
+)
+
+// ErrInjected is the error returned by calls which were made to fail.
+var ErrInjected = errors.New("fault injected")
+
+// malformedJSON is returned in place of the data of calls which were given a malformed response.
+var malformedJSON = []byte(`{"malformed":`)
+
+type fault int
+
+const (
+	faultNone fault = iota
+	faultError
+	faultMalformed
+)
+
+// injector decides which faults are injected into a call.
+type injector struct {
+	cfg    config.Faults
+	random func() float64
+}
+
+func newInjector(cfg config.Faults) *injector {
+	return &injector{cfg: cfg, random: rand.Float64}
+}
+
+// inject delays the call if it's drawn for latency, and returns the fault it's drawn for. It returns
+// the context's error if the context is done while the call is delayed.
+func (i *injector) inject(ctx context.Context) (fault, error) {
+	if i.cfg.LatencyMs > 0 && i.random() < i.cfg.LatencyRate {
+		timer := time.NewTimer(time.Duration(i.cfg.LatencyMs) * time.Millisecond)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			return faultNone, ctx.Err()
+		}
+	}
+
+	draw := i.random()
+	if draw < i.cfg.ErrorRate {
+		return faultError, nil
+	}
+	if draw < i.cfg.ErrorRate+i.cfg.MalformedRate {
+		return faultMalformed, nil
+	}
+	return faultNone, nil
+}
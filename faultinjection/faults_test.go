@@ -0,0 +1,75 @@
+package faultinjection
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/stretchr/testify/assert"
+)
+
+// sequence returns the draws in order, repeating the last one.
+func sequence(draws ...float64) func() float64 {
+	return func() float64 {
+		draw := draws[0]
+		if len(draws) > 1 {
+			draws = draws[1:]
+		}
+		return draw
+	}
+}
+
+func TestInject(t *testing.T) {
+	cfg := config.Faults{LatencyRate: 0.5, LatencyMs: 10, ErrorRate: 0.1, MalformedRate: 0.2}
+
+	testCases := []struct {
+		description     string
+		draws           []float64
+		expectedFault   fault
+		expectedDelayed bool
+	}{
+		{
+			description:   "No fault",
+			draws:         []float64{0.9, 0.5},
+			expectedFault: faultNone,
+		},
+		{
+			description:   "Error",
+			draws:         []float64{0.9, 0.05},
+			expectedFault: faultError,
+		},
+		{
+			description:   "Malformed",
+			draws:         []float64{0.9, 0.25},
+			expectedFault: faultMalformed,
+		},
+		{
+			description:     "Delayed error",
+			draws:           []float64{0.1, 0.05},
+			expectedFault:   faultError,
+			expectedDelayed: true,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			i := &injector{cfg: cfg, random: sequence(test.draws...)}
+
+			start := time.Now()
+			fault, err := i.inject(context.Background())
+			assert.NoError(t, err)
+			assert.Equal(t, test.expectedFault, fault)
+			assert.Equal(t, test.expectedDelayed, time.Since(start) >= 10*time.Millisecond)
+		})
+	}
+}
+
+func TestInjectCancelled(t *testing.T) {
+	i := &injector{cfg: config.Faults{LatencyRate: 1, LatencyMs: 10000}, random: sequence(0)}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := i.inject(ctx)
+	assert.Equal(t, context.Canceled, err)
+}
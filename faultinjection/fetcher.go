@@ -0,0 +1,101 @@
+package faultinjection
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/stored_requests"
+)
+
+// WithFetcherFaults returns a Fetcher which injects the faults into the calls to the given Fetcher. It
+// returns the Fetcher if fault injection is disabled, or there are no faults to inject.
+func WithFetcherFaults(fetcher stored_requests.AllFetcher, enabled bool, cfg config.Faults) stored_requests.AllFetcher {
+	if !enabled || cfg.IsEmpty() {
+		return fetcher
+	}
+	return &faultyFetcher{fetcher: fetcher, injector: newInjector(cfg)}
+}
+
+// faultyFetcher injects faults into the calls to the Fetcher. Calls which fail aren't passed on. Calls
+// which get a malformed response are, and have the data they return replaced with malformed JSON.
+type faultyFetcher struct {
+	fetcher  stored_requests.AllFetcher
+	injector *injector
+}
+
+func (f *faultyFetcher) FetchRequests(ctx context.Context, requestIDs []string, impIDs []string) (requestData map[string]json.RawMessage, impData map[string]json.RawMessage, errs []error) {
+	fault, err := f.injector.inject(ctx)
+	if err != nil {
+		return nil, nil, []error{err}
+	}
+	if fault == faultError {
+		return nil, nil, []error{ErrInjected}
+	}
+
+	requestData, impData, errs = f.fetcher.FetchRequests(ctx, requestIDs, impIDs)
+	if fault == faultMalformed {
+		requestData = malformAll(requestData)
+		impData = malformAll(impData)
+	}
+	return
+}
+
+func (f *faultyFetcher) FetchRequestVersions(ctx context.Context, requestIDs []string, impIDs []string) (map[string]string, map[string]string) {
+	return stored_requests.FetchRequestVersions(ctx, f.fetcher, requestIDs, impIDs)
+}
+
+func (f *faultyFetcher) FetchResponses(ctx context.Context, ids []string) (data map[string]json.RawMessage, errs []error) {
+	fault, err := f.injector.inject(ctx)
+	if err != nil {
+		return nil, []error{err}
+	}
+	if fault == faultError {
+		return nil, []error{ErrInjected}
+	}
+
+	data, errs = f.fetcher.FetchResponses(ctx, ids)
+	if fault == faultMalformed {
+		data = malformAll(data)
+	}
+	return
+}
+
+func (f *faultyFetcher) FetchAccount(ctx context.Context, accountDefaultsJSON json.RawMessage, accountID string) (json.RawMessage, []error) {
+	fault, err := f.injector.inject(ctx)
+	if err != nil {
+		return nil, []error{err}
+	}
+	if fault == faultError {
+		return nil, []error{ErrInjected}
+	}
+
+	account, errs := f.fetcher.FetchAccount(ctx, accountDefaultsJSON, accountID)
+	if fault == faultMalformed && len(errs) == 0 {
+		account = malformedJSON
+	}
+	return account, errs
+}
+
+// FetchCategories may be delayed or fail, but categories can't be malformed.
+func (f *faultyFetcher) FetchCategories(ctx context.Context, primaryAdServer, publisherId, iabCategory string) (string, error) {
+	fault, err := f.injector.inject(ctx)
+	if err != nil {
+		return "", err
+	}
+	if fault == faultError {
+		return "", ErrInjected
+	}
+	return f.fetcher.FetchCategories(ctx, primaryAdServer, publisherId, iabCategory)
+}
+
+func malformAll(data map[string]json.RawMessage) map[string]json.RawMessage {
+	if len(data) == 0 {
+		return data
+	}
+	malformed := make(map[string]json.RawMessage, len(data))
+	for id := range data {
+		malformed[id] = malformedJSON
+	}
+	return malformed
+}
@@ -0,0 +1,93 @@
+package faultinjection
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/stored_requests/backends/empty_fetcher"
+	"github.com/stretchr/testify/assert"
+)
+
+type staticFetcher struct {
+	empty_fetcher.EmptyFetcher
+	data map[string]json.RawMessage
+}
+
+func (f *staticFetcher) FetchRequests(ctx context.Context, requestIDs []string, impIDs []string) (map[string]json.RawMessage, map[string]json.RawMessage, []error) {
+	requestData := make(map[string]json.RawMessage)
+	for _, id := range requestIDs {
+		requestData[id] = f.data[id]
+	}
+	impData := make(map[string]json.RawMessage)
+	for _, id := range impIDs {
+		impData[id] = f.data[id]
+	}
+	return requestData, impData, nil
+}
+
+func (f *staticFetcher) FetchAccount(ctx context.Context, accountDefaultsJSON json.RawMessage, accountID string) (json.RawMessage, []error) {
+	return f.data[accountID], nil
+}
+
+func TestWithFetcherFaults(t *testing.T) {
+	fetcher := &staticFetcher{}
+
+	assert.Same(t, fetcher, WithFetcherFaults(fetcher, false, config.Faults{ErrorRate: 1}), "disabled")
+	assert.Same(t, fetcher, WithFetcherFaults(fetcher, true, config.Faults{}), "no faults")
+	assert.NotSame(t, fetcher, WithFetcherFaults(fetcher, true, config.Faults{ErrorRate: 1}), "faults")
+}
+
+func TestFaultyFetcher(t *testing.T) {
+	data := map[string]json.RawMessage{
+		"req": json.RawMessage(`{"id":"req"}`),
+		"imp": json.RawMessage(`{"id":"imp"}`),
+		"acc": json.RawMessage(`{"id":"acc"}`),
+	}
+
+	testCases := []struct {
+		description      string
+		draw             float64
+		expectedRequests map[string]json.RawMessage
+		expectedImps     map[string]json.RawMessage
+		expectedAccount  json.RawMessage
+		expectedErrs     []error
+	}{
+		{
+			description:      "No fault",
+			draw:             0.9,
+			expectedRequests: map[string]json.RawMessage{"req": data["req"]},
+			expectedImps:     map[string]json.RawMessage{"imp": data["imp"]},
+			expectedAccount:  data["acc"],
+		},
+		{
+			description:  "Error",
+			draw:         0.05,
+			expectedErrs: []error{ErrInjected},
+		},
+		{
+			description:      "Malformed",
+			draw:             0.15,
+			expectedRequests: map[string]json.RawMessage{"req": malformedJSON},
+			expectedImps:     map[string]json.RawMessage{"imp": malformedJSON},
+			expectedAccount:  malformedJSON,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			fetcher := WithFetcherFaults(&staticFetcher{data: data}, true, config.Faults{ErrorRate: 0.1, MalformedRate: 0.1})
+			fetcher.(*faultyFetcher).injector.random = sequence(test.draw)
+
+			requests, imps, errs := fetcher.FetchRequests(context.Background(), []string{"req"}, []string{"imp"})
+			assert.Equal(t, test.expectedRequests, requests)
+			assert.Equal(t, test.expectedImps, imps)
+			assert.Equal(t, test.expectedErrs, errs)
+
+			account, errs := fetcher.FetchAccount(context.Background(), nil, "acc")
+			assert.Equal(t, test.expectedAccount, account)
+			assert.Equal(t, test.expectedErrs, errs)
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package faultinjection
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+
+	"github.com/prebid/prebid-server/v2/config"
+)
+
+// WithFaults returns a copy of the client which injects the faults into its calls. It returns the client
+// if fault injection is disabled, or there are no faults to inject.
+func WithFaults(client *http.Client, enabled bool, cfg config.Faults) *http.Client {
+	if !enabled || cfg.IsEmpty() {
+		return client
+	}
+
+	next := client.Transport
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	faultyClient := *client
+	faultyClient.Transport = &transport{next: next, injector: newInjector(cfg)}
+	return &faultyClient
+}
+
+// transport injects faults into the calls sent with the next transport. Calls which fail, or get a
+// malformed response, are never sent.
+type transport struct {
+	next     http.RoundTripper
+	injector *injector
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	fault, err := t.injector.inject(req.Context())
+	if err != nil {
+		return nil, err
+	}
+
+	switch fault {
+	case faultError:
+		return nil, ErrInjected
+	case faultMalformed:
+		return &http.Response{
+			Status:        "200 OK",
+			StatusCode:    http.StatusOK,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        http.Header{"Content-Type": []string{"application/json"}},
+			Body:          io.NopCloser(bytes.NewReader(malformedJSON)),
+			ContentLength: int64(len(malformedJSON)),
+			Request:       req,
+		}, nil
+	}
+	return t.next.RoundTrip(req)
+}
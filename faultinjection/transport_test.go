@@ -0,0 +1,75 @@
+package faultinjection
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithFaults(t *testing.T) {
+	client := &http.Client{}
+
+	assert.Same(t, client, WithFaults(client, false, config.Faults{ErrorRate: 1}), "disabled")
+	assert.Same(t, client, WithFaults(client, true, config.Faults{LatencyRate: 1}), "no faults")
+	assert.NotSame(t, client, WithFaults(client, true, config.Faults{ErrorRate: 1}), "faults")
+	assert.Nil(t, client.Transport, "the given client must not be changed")
+}
+
+func TestTransport(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Write([]byte(`{"id":"real"}`))
+	}))
+	defer server.Close()
+
+	testCases := []struct {
+		description   string
+		draw          float64
+		expectedErr   error
+		expectedBody  string
+		expectedCalls int
+	}{
+		{
+			description:   "No fault",
+			draw:          0.9,
+			expectedBody:  `{"id":"real"}`,
+			expectedCalls: 1,
+		},
+		{
+			description: "Error",
+			draw:        0.05,
+			expectedErr: ErrInjected,
+		},
+		{
+			description:  "Malformed",
+			draw:         0.15,
+			expectedBody: string(malformedJSON),
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			calls = 0
+			client := WithFaults(server.Client(), true, config.Faults{ErrorRate: 0.1, MalformedRate: 0.1})
+			client.Transport.(*transport).injector.random = sequence(test.draw)
+
+			resp, err := client.Get(server.URL)
+			if test.expectedErr != nil {
+				assert.ErrorIs(t, err, test.expectedErr)
+			} else {
+				require.NoError(t, err)
+				body, _ := io.ReadAll(resp.Body)
+				resp.Body.Close()
+				assert.Equal(t, http.StatusOK, resp.StatusCode)
+				assert.Equal(t, test.expectedBody, string(body))
+			}
+			assert.Equal(t, test.expectedCalls, calls)
+		})
+	}
+}
@@ -0,0 +1,423 @@
+package firstpartydata
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/buger/jsonparser"
+	"github.com/prebid/openrtb/v20/openrtb2"
+
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/errortypes"
+	"github.com/prebid/prebid-server/v2/openrtb_ext"
+	"github.com/prebid/prebid-server/v2/util/jsonutil"
+)
+
+// Source is where first party data comes from.
+type Source string
+
+const (
+	// SourceRequest is the HTTP request
+	SourceRequest Source = "request"
+	// SourceStored is the stored request, merged over the default request, or the stored imp
+	SourceStored Source = "stored"
+	// SourceModule is the modules, such as real time data modules, which change the request once it's been
+	// resolved
+	SourceModule Source = "module"
+)
+
+// defaultPrecedence is the order in which data has always been merged: the request overrides stored
+// requests, and modules, which run last, override both.
+var defaultPrecedence = []Source{SourceModule, SourceRequest, SourceStored}
+
+// mergeRule is an account's rule for the first party data at a path.
+type mergeRule struct {
+	path string
+	// keys are the keys of the path in the request, or in each imp for imp rules
+	keys        []string
+	imp         bool
+	precedence  []Source
+	mergeArrays bool
+	maxBytes    int
+}
+
+func newMergeRules(cfg config.AccountFirstPartyData) []mergeRule {
+	var rules []mergeRule
+	for _, ruleCfg := range cfg.Rules {
+		keys := strings.Split(ruleCfg.Path, ".")
+		if len(keys) < 2 {
+			continue
+		}
+		rule := mergeRule{
+			path:        ruleCfg.Path,
+			keys:        keys,
+			mergeArrays: ruleCfg.Arrays == "merge",
+			maxBytes:    ruleCfg.MaxBytes,
+		}
+		if keys[0] == "imp" {
+			rule.keys = keys[1:]
+			rule.imp = true
+		}
+
+		listed := make(map[Source]bool, len(defaultPrecedence))
+		for _, name := range ruleCfg.Precedence {
+			source := Source(name)
+			if !listed[source] && (source == SourceRequest || source == SourceStored || source == SourceModule) {
+				rule.precedence = append(rule.precedence, source)
+				listed[source] = true
+			}
+		}
+		for _, source := range defaultPrecedence {
+			if !listed[source] {
+				rule.precedence = append(rule.precedence, source)
+			}
+		}
+		rules = append(rules, rule)
+	}
+	return rules
+}
+
+// ImpSources holds the sources of an imp's first party data: the imp of the HTTP request, and its stored
+// imp if it has one.
+type ImpSources struct {
+	Request json.RawMessage
+	Stored  json.RawMessage
+}
+
+// Resolution is how the first party data at the paths of an account's merge rules was resolved. It keeps
+// the data each source had, so that it can be merged again once modules have changed the request. A nil
+// *Resolution governs no paths.
+type Resolution struct {
+	rules []mergeRule
+	paths []*resolvedPath
+}
+
+type resolvedPath struct {
+	rule *mergeRule
+	// impID is the ID of the imp, for imp rules
+	impID   string
+	sources map[Source]json.RawMessage
+	value   json.RawMessage
+	capped  bool
+}
+
+// ResolveStored merges the first party data of the request and its stored request and imps at the paths of
+// the account's rules, into the request they've already been merged into. The imps of the merged request
+// must be in the same order as their sources. Data over its rule's size cap is left out, with a warning.
+func ResolveStored(cfg config.AccountFirstPartyData, merged, request, stored json.RawMessage, imps []ImpSources) (json.RawMessage, *Resolution, []error) {
+	rules := newMergeRules(cfg)
+	if len(rules) == 0 {
+		return merged, nil, nil
+	}
+
+	// the merged request is changed in place, and may share its bytes with the sources
+	merged = append(json.RawMessage(nil), merged...)
+	resolution := &Resolution{rules: rules}
+	var errs []error
+	for i := range resolution.rules {
+		rule := &resolution.rules[i]
+		if !rule.imp {
+			sources := collectSources(map[Source]json.RawMessage{
+				SourceRequest: getJSON(request, rule.keys...),
+				SourceStored:  getJSON(stored, rule.keys...),
+			})
+			if len(sources) == 0 {
+				continue
+			}
+			path := resolution.add(rule, "", sources)
+			errs = append(errs, path.warnings()...)
+			merged = setJSON(merged, path.value, rule.keys...)
+			continue
+		}
+
+		for j, imp := range imps {
+			sources := collectSources(map[Source]json.RawMessage{
+				SourceRequest: getJSON(imp.Request, rule.keys...),
+				SourceStored:  getJSON(imp.Stored, rule.keys...),
+			})
+			if len(sources) == 0 {
+				continue
+			}
+			impKeys := []string{"imp", fmt.Sprintf("[%d]", j)}
+			impID, _ := jsonparser.GetString(merged, append(impKeys, "id")...)
+			path := resolution.add(rule, impID, sources)
+			errs = append(errs, path.warnings()...)
+			merged = setJSON(merged, path.value, append(impKeys, rule.keys...)...)
+		}
+	}
+	return merged, resolution, errs
+}
+
+// ResolveRequest resolves the first party data of a request which wasn't merged with stored requests by
+// ResolveStored, with the request as its only source. It caps the data in the request.
+func ResolveRequest(cfg config.AccountFirstPartyData, req *openrtb_ext.RequestWrapper) (*Resolution, []error) {
+	rules := newMergeRules(cfg)
+	if len(rules) == 0 {
+		return nil, nil
+	}
+	if err := req.RebuildRequest(); err != nil {
+		return nil, []error{err}
+	}
+	requestJSON, err := jsonutil.Marshal(req.BidRequest)
+	if err != nil {
+		return nil, []error{err}
+	}
+
+	var imps []ImpSources
+	for _, imp := range req.Imp {
+		impJSON, err := jsonutil.Marshal(imp)
+		if err != nil {
+			return nil, []error{err}
+		}
+		imps = append(imps, ImpSources{Request: impJSON})
+	}
+	resolvedJSON, resolution, errs := ResolveStored(cfg, requestJSON, requestJSON, nil, imps)
+	if len(errs) > 0 {
+		if err := replaceRequest(req, resolvedJSON); err != nil {
+			return nil, []error{err}
+		}
+	}
+	return resolution, errs
+}
+
+// ApplyModules merges the first party data which modules have added or changed at the paths of the rules
+// with the data of the other sources, as the rules say. Data which a module removed stays removed.
+func (r *Resolution) ApplyModules(req *openrtb_ext.RequestWrapper) []error {
+	if r == nil || len(r.rules) == 0 {
+		return nil
+	}
+	if err := req.RebuildRequest(); err != nil {
+		return []error{err}
+	}
+	requestJSON, err := jsonutil.Marshal(req.BidRequest)
+	if err != nil {
+		return []error{err}
+	}
+
+	var errs []error
+	changed := false
+	apply := func(rule *mergeRule, impID string, keys []string) {
+		current := getJSON(requestJSON, keys...)
+		path := r.find(rule, impID)
+		if current == nil || (path != nil && equalJSON(current, path.value)) {
+			return
+		}
+		if path == nil {
+			path = r.add(rule, impID, map[Source]json.RawMessage{SourceModule: current})
+		} else {
+			path.sources[SourceModule] = current
+			path.resolve()
+		}
+		errs = append(errs, path.warnings()...)
+		requestJSON = setJSON(requestJSON, path.value, keys...)
+		changed = true
+	}
+	for i := range r.rules {
+		rule := &r.rules[i]
+		if !rule.imp {
+			apply(rule, "", rule.keys)
+			continue
+		}
+		for j, imp := range req.Imp {
+			apply(rule, imp.ID, append([]string{"imp", fmt.Sprintf("[%d]", j)}, rule.keys...))
+		}
+	}
+
+	if changed {
+		if err := replaceRequest(req, requestJSON); err != nil {
+			return append(errs, err)
+		}
+	}
+	return errs
+}
+
+// Debug returns the resolved first party data for the debug output of the response.
+func (r *Resolution) Debug() []openrtb_ext.ExtResponseResolvedFPD {
+	if r == nil || len(r.paths) == 0 {
+		return nil
+	}
+	debug := make([]openrtb_ext.ExtResponseResolvedFPD, 0, len(r.paths))
+	for _, path := range r.paths {
+		sources := make(map[string]json.RawMessage, len(path.sources))
+		for source, value := range path.sources {
+			sources[string(source)] = value
+		}
+		debug = append(debug, openrtb_ext.ExtResponseResolvedFPD{
+			Path:    path.name(),
+			Sources: sources,
+			Value:   path.value,
+			Capped:  path.capped,
+		})
+	}
+	return debug
+}
+
+func (r *Resolution) add(rule *mergeRule, impID string, sources map[Source]json.RawMessage) *resolvedPath {
+	path := &resolvedPath{rule: rule, impID: impID, sources: sources}
+	path.resolve()
+	r.paths = append(r.paths, path)
+	return path
+}
+
+func (r *Resolution) find(rule *mergeRule, impID string) *resolvedPath {
+	for _, path := range r.paths {
+		if path.rule == rule && path.impID == impID {
+			return path
+		}
+	}
+	return nil
+}
+
+// resolve merges the data of the sources, from the one which loses conflicts to the one which wins them.
+func (p *resolvedPath) resolve() {
+	var value json.RawMessage
+	for i := len(p.rule.precedence) - 1; i >= 0; i-- {
+		sourceValue, ok := p.sources[p.rule.precedence[i]]
+		if !ok {
+			continue
+		}
+		if value == nil {
+			value = sourceValue
+		} else {
+			value = mergeValues(value, sourceValue, p.rule.mergeArrays)
+		}
+	}
+
+	p.value = value
+	p.capped = p.rule.maxBytes > 0 && len(value) > p.rule.maxBytes
+	if p.capped {
+		p.value = nil
+	}
+}
+
+func (p *resolvedPath) name() string {
+	if p.rule.imp {
+		return fmt.Sprintf("imp[%s].%s", p.impID, strings.Join(p.rule.keys, "."))
+	}
+	return p.rule.path
+}
+
+func (p *resolvedPath) warnings() []error {
+	if !p.capped {
+		return nil
+	}
+	return []error{&errortypes.Warning{
+		Message:     fmt.Sprintf("first party data at %s was left out of the request because it's over the account's cap of %d bytes", p.name(), p.rule.maxBytes),
+		WarningCode: errortypes.FirstPartyDataCapWarningCode,
+	}}
+}
+
+// mergeValues merges higher over lower. Objects are merged key by key, and arrays are concatenated if
+// mergeArrays is set. Otherwise higher replaces lower.
+func mergeValues(lower, higher json.RawMessage, mergeArrays bool) json.RawMessage {
+	lowerType, higherType := jsonType(lower), jsonType(higher)
+	switch {
+	case lowerType == '{' && higherType == '{':
+		var lowerObject, higherObject map[string]json.RawMessage
+		if jsonutil.Unmarshal(lower, &lowerObject) != nil || jsonutil.Unmarshal(higher, &higherObject) != nil {
+			return higher
+		}
+		for key, value := range higherObject {
+			if lowerValue, ok := lowerObject[key]; ok {
+				value = mergeValues(lowerValue, value, mergeArrays)
+			}
+			lowerObject[key] = value
+		}
+		merged, err := jsonutil.Marshal(lowerObject)
+		if err != nil {
+			return higher
+		}
+		return merged
+	case lowerType == '[' && higherType == '[' && mergeArrays:
+		var lowerArray, higherArray []json.RawMessage
+		if jsonutil.Unmarshal(lower, &lowerArray) != nil || jsonutil.Unmarshal(higher, &higherArray) != nil {
+			return higher
+		}
+		merged := higherArray
+		for _, item := range lowerArray {
+			duplicate := false
+			for _, existing := range merged {
+				if equalJSON(item, existing) {
+					duplicate = true
+					break
+				}
+			}
+			if !duplicate {
+				merged = append(merged, item)
+			}
+		}
+		mergedJSON, err := jsonutil.Marshal(merged)
+		if err != nil {
+			return higher
+		}
+		return mergedJSON
+	default:
+		return higher
+	}
+}
+
+func collectSources(values map[Source]json.RawMessage) map[Source]json.RawMessage {
+	for source, value := range values {
+		if value == nil {
+			delete(values, source)
+		}
+	}
+	return values
+}
+
+// getJSON returns the JSON value at the keys, or nil if there's none.
+func getJSON(data []byte, keys ...string) json.RawMessage {
+	if len(data) == 0 {
+		return nil
+	}
+	value, dataType, offset, err := jsonparser.Get(data, keys...)
+	if err != nil || dataType == jsonparser.NotExist {
+		return nil
+	}
+	if dataType == jsonparser.String {
+		// strings are returned without their quotes, which end at the offset
+		value = data[offset-len(value)-2 : offset]
+	}
+	return append(json.RawMessage(nil), value...)
+}
+
+// setJSON sets the JSON value at the keys, or deletes it if the value is nil.
+func setJSON(data []byte, value json.RawMessage, keys ...string) []byte {
+	if value == nil {
+		return jsonparser.Delete(data, keys...)
+	}
+	if updated, err := jsonparser.Set(data, value, keys...); err == nil {
+		return updated
+	}
+	return data
+}
+
+func jsonType(value json.RawMessage) byte {
+	trimmed := bytes.TrimSpace(value)
+	if len(trimmed) == 0 {
+		return 0
+	}
+	return trimmed[0]
+}
+
+// equalJSON reports whether two values are the same, whatever the order of their keys.
+func equalJSON(a, b json.RawMessage) bool {
+	var valueA, valueB interface{}
+	if json.Unmarshal(a, &valueA) != nil || json.Unmarshal(b, &valueB) != nil {
+		return bytes.Equal(a, b)
+	}
+	return reflect.DeepEqual(valueA, valueB)
+}
+
+// replaceRequest replaces the request in the wrapper with the JSON, and drops the wrapper's parsed exts.
+func replaceRequest(req *openrtb_ext.RequestWrapper, requestJSON []byte) error {
+	bidRequest := &openrtb2.BidRequest{}
+	if err := jsonutil.Unmarshal(requestJSON, bidRequest); err != nil {
+		return err
+	}
+	*req = openrtb_ext.RequestWrapper{BidRequest: bidRequest}
+	return nil
+}
@@ -0,0 +1,250 @@
+package firstpartydata
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/errortypes"
+	"github.com/prebid/prebid-server/v2/openrtb_ext"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveStored(t *testing.T) {
+	testCases := []struct {
+		description      string
+		rules            []config.AccountFPDRule
+		merged           string
+		request          string
+		stored           string
+		imps             []ImpSources
+		expectedMerged   string
+		expectedWarnings []string
+	}{
+		{
+			description:    "no-rules",
+			merged:         `{"site":{"ext":{"data":{"a":"request"}}}}`,
+			request:        `{"site":{"ext":{"data":{"a":"request"}}}}`,
+			stored:         `{"site":{"ext":{"data":{"a":"stored"}}}}`,
+			expectedMerged: `{"site":{"ext":{"data":{"a":"request"}}}}`,
+		},
+		{
+			description:    "default-precedence",
+			rules:          []config.AccountFPDRule{{Path: "site.ext.data"}},
+			merged:         `{"id":"req","site":{"ext":{"data":{"a":"request"}}}}`,
+			request:        `{"site":{"ext":{"data":{"a":"request"}}}}`,
+			stored:         `{"site":{"ext":{"data":{"a":"stored","b":"stored"}}}}`,
+			expectedMerged: `{"id":"req","site":{"ext":{"data":{"a":"request","b":"stored"}}}}`,
+		},
+		{
+			description:    "stored-wins",
+			rules:          []config.AccountFPDRule{{Path: "site.ext.data", Precedence: []string{"stored", "request"}}},
+			merged:         `{"site":{"ext":{"data":{"a":"request"}}}}`,
+			request:        `{"site":{"ext":{"data":{"a":"request"}}}}`,
+			stored:         `{"site":{"ext":{"data":{"a":"stored","b":"stored"}}}}`,
+			expectedMerged: `{"site":{"ext":{"data":{"a":"stored","b":"stored"}}}}`,
+		},
+		{
+			description:    "arrays-replaced",
+			rules:          []config.AccountFPDRule{{Path: "site.keywords"}, {Path: "user.data"}},
+			merged:         `{"site":{"keywords":"request"},"user":{"data":[{"id":"request"}]}}`,
+			request:        `{"site":{"keywords":"request"},"user":{"data":[{"id":"request"}]}}`,
+			stored:         `{"site":{"keywords":"stored"},"user":{"data":[{"id":"stored"}]}}`,
+			expectedMerged: `{"site":{"keywords":"request"},"user":{"data":[{"id":"request"}]}}`,
+		},
+		{
+			description:    "arrays-merged",
+			rules:          []config.AccountFPDRule{{Path: "user.data", Arrays: "merge"}},
+			merged:         `{"user":{"data":[{"id":"request"},{"id":"both"}]}}`,
+			request:        `{"user":{"data":[{"id":"request"},{"id":"both"}]}}`,
+			stored:         `{"user":{"data":[{"id":"stored"},{"id":"both"}]}}`,
+			expectedMerged: `{"user":{"data":[{"id":"request"},{"id":"both"},{"id":"stored"}]}}`,
+		},
+		{
+			description:    "stored-only",
+			rules:          []config.AccountFPDRule{{Path: "app.content"}},
+			merged:         `{"app":{"content":{"id":"stored"}}}`,
+			request:        `{}`,
+			stored:         `{"app":{"content":{"id":"stored"}}}`,
+			expectedMerged: `{"app":{"content":{"id":"stored"}}}`,
+		},
+		{
+			description:      "capped",
+			rules:            []config.AccountFPDRule{{Path: "site.ext.data", MaxBytes: 10}},
+			merged:           `{"id":"req","site":{"page":"p","ext":{"data":{"a":"request"}}}}`,
+			request:          `{"site":{"ext":{"data":{"a":"request"}}}}`,
+			stored:           `{}`,
+			expectedMerged:   `{"id":"req","site":{"page":"p","ext":{}}}`,
+			expectedWarnings: []string{"first party data at site.ext.data was left out of the request because it's over the account's cap of 10 bytes"},
+		},
+		{
+			description: "imps",
+			rules:       []config.AccountFPDRule{{Path: "imp.ext.data", Precedence: []string{"stored"}}},
+			merged:      `{"imp":[{"id":"1","ext":{"data":{"a":"request"}}},{"id":"2","ext":{"data":{"a":"request"}}}]}`,
+			request:     `{"imp":[{"id":"1","ext":{"data":{"a":"request"}}},{"id":"2","ext":{"data":{"a":"request"}}}]}`,
+			imps: []ImpSources{
+				{Request: json.RawMessage(`{"id":"1","ext":{"data":{"a":"request"}}}`), Stored: json.RawMessage(`{"ext":{"data":{"a":"stored"}}}`)},
+				{Request: json.RawMessage(`{"id":"2","ext":{"data":{"a":"request"}}}`)},
+			},
+			expectedMerged: `{"imp":[{"id":"1","ext":{"data":{"a":"stored"}}},{"id":"2","ext":{"data":{"a":"request"}}}]}`,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			cfg := config.AccountFirstPartyData{Rules: test.rules}
+			merged, resolution, errs := ResolveStored(cfg, json.RawMessage(test.merged), json.RawMessage(test.request), json.RawMessage(test.stored), test.imps)
+
+			assert.JSONEq(t, test.expectedMerged, string(merged))
+			if len(test.rules) == 0 {
+				assert.Nil(t, resolution)
+			} else {
+				assert.NotNil(t, resolution)
+			}
+			var warnings []string
+			for _, err := range errs {
+				assert.Equal(t, errortypes.FirstPartyDataCapWarningCode, errortypes.ReadCode(err))
+				warnings = append(warnings, err.Error())
+			}
+			assert.Equal(t, test.expectedWarnings, warnings)
+		})
+	}
+}
+
+func TestResolveStoredLeavesSourcesUnchanged(t *testing.T) {
+	cfg := config.AccountFirstPartyData{Rules: []config.AccountFPDRule{{Path: "site.ext.data", Precedence: []string{"stored"}}}}
+	request := json.RawMessage(`{"site":{"ext":{"data":{"a":"request"}}}}`)
+
+	merged, _, _ := ResolveStored(cfg, request, request, json.RawMessage(`{"site":{"ext":{"data":{"a":"stored"}}}}`), nil)
+
+	assert.JSONEq(t, `{"site":{"ext":{"data":{"a":"stored"}}}}`, string(merged))
+	assert.JSONEq(t, `{"site":{"ext":{"data":{"a":"request"}}}}`, string(request))
+}
+
+func TestResolveRequest(t *testing.T) {
+	cfg := config.AccountFirstPartyData{Rules: []config.AccountFPDRule{{Path: "user.ext.data", MaxBytes: 10}, {Path: "site.ext.data"}}}
+	req := &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{
+		ID:   "req",
+		Site: &openrtb2.Site{Page: "p", Ext: json.RawMessage(`{"data":{"a":"request"}}`)},
+		User: &openrtb2.User{ID: "u", Ext: json.RawMessage(`{"data":{"segments":["1","2","3"]}}`)},
+	}}
+
+	resolution, errs := ResolveRequest(cfg, req)
+
+	require.NotNil(t, resolution)
+	require.Len(t, errs, 1)
+	assert.Equal(t, errortypes.FirstPartyDataCapWarningCode, errortypes.ReadCode(errs[0]))
+	assert.Equal(t, "req", req.ID)
+	assert.JSONEq(t, `{}`, string(req.User.Ext))
+	assert.JSONEq(t, `{"data":{"a":"request"}}`, string(req.Site.Ext))
+}
+
+func TestResolveRequestNoRules(t *testing.T) {
+	req := &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{ID: "req"}}
+
+	resolution, errs := ResolveRequest(config.AccountFirstPartyData{}, req)
+
+	assert.Nil(t, resolution)
+	assert.Empty(t, errs)
+}
+
+func TestApplyModules(t *testing.T) {
+	testCases := []struct {
+		description     string
+		rules           []config.AccountFPDRule
+		moduleSite      *openrtb2.Site
+		moduleImpExt    json.RawMessage
+		expectedSiteExt string
+		expectedImpExt  string
+	}{
+		{
+			description:     "module-wins-by-default",
+			rules:           []config.AccountFPDRule{{Path: "site.ext.data"}, {Path: "imp.ext.data"}},
+			moduleSite:      &openrtb2.Site{Ext: json.RawMessage(`{"data":{"a":"module","c":"module"}}`)},
+			expectedSiteExt: `{"data":{"a":"module","b":"stored","c":"module"}}`,
+			expectedImpExt:  `{"data":{"a":"request"}}`,
+		},
+		{
+			description:     "request-wins",
+			rules:           []config.AccountFPDRule{{Path: "site.ext.data", Precedence: []string{"request", "stored", "module"}}},
+			moduleSite:      &openrtb2.Site{Ext: json.RawMessage(`{"data":{"a":"module","c":"module"}}`)},
+			expectedSiteExt: `{"data":{"a":"request","b":"stored","c":"module"}}`,
+			expectedImpExt:  `{"data":{"a":"request"}}`,
+		},
+		{
+			description:     "module-adds-imp-data",
+			rules:           []config.AccountFPDRule{{Path: "imp.ext.data", Precedence: []string{"request"}}},
+			moduleSite:      &openrtb2.Site{Ext: json.RawMessage(`{"data":{"a":"request","b":"stored"}}`)},
+			moduleImpExt:    json.RawMessage(`{"data":{"a":"module","m":"module"}}`),
+			expectedSiteExt: `{"data":{"a":"request","b":"stored"}}`,
+			expectedImpExt:  `{"data":{"a":"request","m":"module"}}`,
+		},
+		{
+			description:     "module-removes-data",
+			rules:           []config.AccountFPDRule{{Path: "site.ext.data"}},
+			moduleSite:      &openrtb2.Site{},
+			expectedSiteExt: ``,
+			expectedImpExt:  `{"data":{"a":"request"}}`,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			cfg := config.AccountFirstPartyData{Rules: test.rules}
+			request := json.RawMessage(`{"site":{"ext":{"data":{"a":"request"}}},"imp":[{"id":"1","ext":{"data":{"a":"request"}}}]}`)
+			stored := json.RawMessage(`{"site":{"ext":{"data":{"a":"stored","b":"stored"}}}}`)
+			imps := []ImpSources{{Request: json.RawMessage(`{"id":"1","ext":{"data":{"a":"request"}}}`)}}
+			merged := json.RawMessage(`{"site":{"ext":{"data":{"a":"request","b":"stored"}}},"imp":[{"id":"1","ext":{"data":{"a":"request"}}}]}`)
+			_, resolution, _ := ResolveStored(cfg, merged, request, stored, imps)
+
+			req := &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{
+				Site: test.moduleSite,
+				Imp:  []openrtb2.Imp{{ID: "1", Ext: json.RawMessage(`{"data":{"a":"request"}}`)}},
+			}}
+			if test.moduleImpExt != nil {
+				req.Imp[0].Ext = test.moduleImpExt
+			}
+
+			errs := resolution.ApplyModules(req)
+
+			assert.Empty(t, errs)
+			if test.expectedSiteExt == "" {
+				assert.Empty(t, req.Site.Ext)
+			} else {
+				assert.JSONEq(t, test.expectedSiteExt, string(req.Site.Ext))
+			}
+			assert.JSONEq(t, test.expectedImpExt, string(req.Imp[0].Ext))
+		})
+	}
+}
+
+func TestApplyModulesNilResolution(t *testing.T) {
+	var resolution *Resolution
+	req := &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{ID: "req"}}
+
+	assert.Empty(t, resolution.ApplyModules(req))
+	assert.Nil(t, resolution.Debug())
+}
+
+func TestResolutionDebug(t *testing.T) {
+	cfg := config.AccountFirstPartyData{Rules: []config.AccountFPDRule{{Path: "site.ext.data"}, {Path: "imp.ext.data", MaxBytes: 5}}}
+	request := json.RawMessage(`{"site":{"ext":{"data":{"a":"request"}}},"imp":[{"id":"imp-1","ext":{"data":{"a":"request"}}}]}`)
+	stored := json.RawMessage(`{"site":{"ext":{"data":{"b":"stored"}}}}`)
+	imps := []ImpSources{{Request: json.RawMessage(`{"id":"imp-1","ext":{"data":{"a":"request"}}}`)}}
+
+	_, resolution, _ := ResolveStored(cfg, request, request, stored, imps)
+	debug := resolution.Debug()
+
+	require.Len(t, debug, 2)
+	assert.Equal(t, "site.ext.data", debug[0].Path)
+	assert.JSONEq(t, `{"a":"request"}`, string(debug[0].Sources["request"]))
+	assert.JSONEq(t, `{"b":"stored"}`, string(debug[0].Sources["stored"]))
+	assert.JSONEq(t, `{"a":"request","b":"stored"}`, string(debug[0].Value))
+	assert.False(t, debug[0].Capped)
+
+	assert.Equal(t, "imp[imp-1].ext.data", debug[1].Path)
+	assert.Nil(t, debug[1].Value)
+	assert.True(t, debug[1].Capped)
+}
@@ -7,9 +7,9 @@ import (
 	"sort"
 	"strings"
 
-	"github.com/golang/glog"
 	"github.com/prebid/openrtb/v20/openrtb2"
 	"github.com/prebid/prebid-server/v2/currency"
+	"github.com/prebid/prebid-server/v2/logger"
 	"github.com/prebid/prebid-server/v2/openrtb_ext"
 	"github.com/prebid/prebid-server/v2/util/ptrutil"
 )
@@ -74,7 +74,7 @@ func getMinFloorValue(floorExt *openrtb_ext.PriceFloorRules, imp *openrtb_ext.Im
 		floorCur = getFloorCurrency(floorExt)
 		if floorMin > 0.0 && floorMinCur != "" {
 			if floorExt.FloorMinCur != "" && impFloorCur != "" && floorExt.FloorMinCur != impFloorCur {
-				glog.Warning("FloorMinCur are different in floorExt and ImpExt")
+				logger.Warning("FloorMinCur are different in floorExt and ImpExt")
 			}
 			if floorCur != "" && floorMinCur != floorCur {
 				rate, err = conversions.GetRate(floorMinCur, floorCur)
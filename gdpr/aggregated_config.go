@@ -18,8 +18,9 @@ type TCF2ConfigReader interface {
 	PurposeEnforcementAlgo(consentconstants.Purpose) config.TCF2EnforcementAlgo
 	PurposeEnforcingVendors(consentconstants.Purpose) bool
 	PurposeVendorExceptions(consentconstants.Purpose) map[string]struct{}
-	PurposeOneTreatmentEnabled() bool
-	PurposeOneTreatmentAccessAllowed() bool
+	PurposeRestrictedVendors(consentconstants.Purpose) map[string]struct{}
+	PurposeOneTreatmentEnabled(publisherCountry string) bool
+	PurposeOneTreatmentAccessAllowed(publisherCountry string) bool
 }
 
 type TCF2ConfigBuilder func(hostConfig config.TCF2, accountConfig config.AccountGDPR) TCF2ConfigReader
@@ -116,20 +117,29 @@ func (tc *tcf2Config) FeatureOneVendorException(bidder openrtb_ext.BidderName) b
 	return value
 }
 
-// PurposeOneTreatmentEnabled checks if purpose one treatment is enabled by first looking at the account settings, and
-// if not set there, defaulting to the host configuration.
-func (tc *tcf2Config) PurposeOneTreatmentEnabled() bool {
-	if value, exists := tc.AccountConfig.PurposeOneTreatmentEnabled(); exists {
+// PurposeRestrictedVendors returns the vendors the account restricts from the specified purpose, or a nil map if
+// there are none. The host has no restricted vendors of its own. A restricted vendor doesn't have legal basis for the
+// purpose whatever the consent string says, nor as a vendor exception.
+func (tc *tcf2Config) PurposeRestrictedVendors(purpose consentconstants.Purpose) map[string]struct{} {
+	return tc.AccountConfig.PurposeRestrictedVendors(purpose)
+}
+
+// PurposeOneTreatmentEnabled checks if purpose one treatment is enabled for consent strings of publishers in the
+// specified country by first looking at the account settings for the country and then the account, and if not set
+// there, defaulting to the host configuration.
+func (tc *tcf2Config) PurposeOneTreatmentEnabled(publisherCountry string) bool {
+	if value, exists := tc.AccountConfig.PurposeOneTreatmentEnabledForCountry(publisherCountry); exists {
 		return value
 	}
 	value := tc.HostConfig.PurposeOneTreatmentEnabled()
 	return value
 }
 
-// PurposeOneTreatmentAccessAllowed checks if purpose one treatment access is allowed by first looking at the account
-// settings, and if not set there, defaulting to the host configuration.
-func (tc *tcf2Config) PurposeOneTreatmentAccessAllowed() bool {
-	if value, exists := tc.AccountConfig.PurposeOneTreatmentAccessAllowed(); exists {
+// PurposeOneTreatmentAccessAllowed checks if purpose one treatment access is allowed for consent strings of
+// publishers in the specified country by first looking at the account settings for the country and then the
+// account, and if not set there, defaulting to the host configuration.
+func (tc *tcf2Config) PurposeOneTreatmentAccessAllowed(publisherCountry string) bool {
+	if value, exists := tc.AccountConfig.PurposeOneTreatmentAccessAllowedForCountry(publisherCountry); exists {
 		return value
 	}
 	value := tc.HostConfig.PurposeOneTreatmentAccessAllowed()
@@ -519,6 +519,7 @@ func TestPurposeOneTreatmentEnabled(t *testing.T) {
 		description        string
 		giveHostEnabled    bool
 		giveAccountEnabled *bool
+		giveCountries      map[string]config.AccountGDPRPurposeOneTreatmentCountry
 		wantEnabled        bool
 	}{
 		{
@@ -545,13 +546,34 @@ func TestPurposeOneTreatmentEnabled(t *testing.T) {
 			giveAccountEnabled: nil,
 			wantEnabled:        true,
 		},
+		{
+			description:        "Purpose 1 treatment enabled set for publisher country - use country setting",
+			giveHostEnabled:    true,
+			giveAccountEnabled: &[]bool{true}[0],
+			giveCountries:      map[string]config.AccountGDPRPurposeOneTreatmentCountry{"DE": {Enabled: &[]bool{false}[0]}},
+			wantEnabled:        false,
+		},
+		{
+			description:        "Purpose 1 treatment enabled set for another country - use account setting",
+			giveHostEnabled:    false,
+			giveAccountEnabled: &[]bool{true}[0],
+			giveCountries:      map[string]config.AccountGDPRPurposeOneTreatmentCountry{"FR": {Enabled: &[]bool{false}[0]}},
+			wantEnabled:        true,
+		},
+		{
+			description:     "Purpose 1 treatment enabled not set for publisher country - use host setting",
+			giveHostEnabled: true,
+			giveCountries:   map[string]config.AccountGDPRPurposeOneTreatmentCountry{"DE": {AccessAllowed: &[]bool{false}[0]}},
+			wantEnabled:     true,
+		},
 	}
 
 	for _, tt := range tests {
 		cfg := tcf2Config{
 			AccountConfig: config.AccountGDPR{
 				PurposeOneTreatment: config.AccountGDPRPurposeOneTreatment{
-					Enabled: tt.giveAccountEnabled,
+					Enabled:   tt.giveAccountEnabled,
+					Countries: tt.giveCountries,
 				},
 			},
 			HostConfig: config.TCF2{
@@ -561,7 +583,7 @@ func TestPurposeOneTreatmentEnabled(t *testing.T) {
 			},
 		}
 
-		result := cfg.PurposeOneTreatmentEnabled()
+		result := cfg.PurposeOneTreatmentEnabled("DE")
 
 		assert.Equal(t, tt.wantEnabled, result, tt.description)
 	}
@@ -572,6 +594,7 @@ func TestPurposeOneTreatmentAllowed(t *testing.T) {
 		description              string
 		giveHostAccessAllowed    bool
 		giveAccountAccessAllowed *bool
+		giveCountries            map[string]config.AccountGDPRPurposeOneTreatmentCountry
 		wantAccessAllowed        bool
 	}{
 		{
@@ -598,6 +621,20 @@ func TestPurposeOneTreatmentAllowed(t *testing.T) {
 			giveAccountAccessAllowed: nil,
 			wantAccessAllowed:        true,
 		},
+		{
+			description:              "Purpose 1 treatment access allowed set for publisher country - use country setting",
+			giveHostAccessAllowed:    false,
+			giveAccountAccessAllowed: &[]bool{false}[0],
+			giveCountries:            map[string]config.AccountGDPRPurposeOneTreatmentCountry{"DE": {AccessAllowed: &[]bool{true}[0]}},
+			wantAccessAllowed:        true,
+		},
+		{
+			description:              "Purpose 1 treatment access allowed set for another country - use account setting",
+			giveHostAccessAllowed:    true,
+			giveAccountAccessAllowed: &[]bool{false}[0],
+			giveCountries:            map[string]config.AccountGDPRPurposeOneTreatmentCountry{"FR": {AccessAllowed: &[]bool{true}[0]}},
+			wantAccessAllowed:        false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -605,6 +642,7 @@ func TestPurposeOneTreatmentAllowed(t *testing.T) {
 			AccountConfig: config.AccountGDPR{
 				PurposeOneTreatment: config.AccountGDPRPurposeOneTreatment{
 					AccessAllowed: tt.giveAccountAccessAllowed,
+					Countries:     tt.giveCountries,
 				},
 			},
 			HostConfig: config.TCF2{
@@ -614,7 +652,7 @@ func TestPurposeOneTreatmentAllowed(t *testing.T) {
 			},
 		}
 
-		result := cfg.PurposeOneTreatmentAccessAllowed()
+		result := cfg.PurposeOneTreatmentAccessAllowed("DE")
 
 		assert.Equal(t, tt.wantAccessAllowed, result, tt.description)
 	}
@@ -18,6 +18,9 @@ type BasicEnforcement struct {
 func (be *BasicEnforcement) LegalBasis(vendorInfo VendorInfo, name string, consent tcf2.ConsentMetadata, overrides Overrides) bool {
 	enforcePurpose, enforceVendors := be.applyEnforceOverrides(overrides)
 
+	if be.cfg.restrictedVendor(name) {
+		return false
+	}
 	if !enforcePurpose && !enforceVendors {
 		return true
 	}
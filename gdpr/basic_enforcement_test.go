@@ -220,6 +220,27 @@ func TestBasicLegalBasis(t *testing.T) {
 			},
 			wantResult: true,
 		},
+		{
+			description: "enforce purpose & vendors are on, purpose consent Y, vendor consent Y, bidder is restricted by the publisher",
+			consent:     purpose2AndVendor32Consent,
+			config: purposeConfig{
+				PurposeID:           consentconstants.Purpose(2),
+				EnforcePurpose:      true,
+				EnforceVendors:      true,
+				RestrictedVendorMap: map[string]struct{}{appnexus: {}},
+			},
+			wantResult: false,
+		},
+		{
+			description: "enforce purpose & vendors are off, bidder is a vendor exception restricted by the publisher",
+			consent:     noConsents,
+			config: purposeConfig{
+				PurposeID:           consentconstants.Purpose(2),
+				VendorExceptionMap:  map[string]struct{}{appnexus: {}},
+				RestrictedVendorMap: map[string]struct{}{appnexus: {}},
+			},
+			wantResult: false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -1,8 +1,10 @@
 package gdpr
 
 import (
+	"encoding/base64"
 	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/prebid/go-gdpr/api"
 	"github.com/prebid/go-gdpr/vendorconsent"
@@ -16,6 +18,8 @@ type parsedConsent struct {
 	listVersion     uint16
 	specVersion     uint16
 	consentMeta     tcf2.ConsentMetadata
+	// publisherCountry is the country whose rules the publisher operates under, such as for purpose one treatment
+	publisherCountry string
 }
 
 // parseConsent parses and validates the specified consent string returning an instance of parsedConsent
@@ -39,13 +43,32 @@ func parseConsent(consent string) (*parsedConsent, error) {
 		return nil, err
 	}
 	return &parsedConsent{
-		encodingVersion: pc.Version(),
-		listVersion:     pc.VendorListVersion(),
-		specVersion:     getSpecVersion(pc.TCFPolicyVersion()),
-		consentMeta:     cm,
+		encodingVersion:  pc.Version(),
+		listVersion:      pc.VendorListVersion(),
+		specVersion:      getSpecVersion(pc.TCFPolicyVersion()),
+		consentMeta:      cm,
+		publisherCountry: parsePublisherCountry(consent),
 	}, nil
 }
 
+// parsePublisherCountry returns the two letter publisher country code stored in bits 201 to 212 (zero-indexed) of
+// the core segment of a TCF2 consent string, which the consent metadata doesn't expose. An empty string is returned
+// if the consent string is too short to hold it.
+func parsePublisherCountry(consent string) string {
+	if index := strings.IndexByte(consent, '.'); index != -1 {
+		consent = consent[:index]
+	}
+	data, err := base64.RawURLEncoding.DecodeString(consent)
+	if err != nil || len(data) < 27 {
+		return ""
+	}
+	// Stored in bits 201-212 (zero-indexed), which is [0xxxxxxx xxxxx000] starting at the 26th byte.
+	// Each letter is stored as 6 bits, with A=0 and Z=25
+	leftChar := (data[25] >> 1) & 0x3f
+	rightChar := ((data[25] & 0x01) << 5) | data[26]>>3
+	return string([]byte{leftChar + 65, rightChar + 65}) // Unicode A-Z is 65-90
+}
+
 // validateVersions ensures that certain version fields in the consent string contain valid values.
 // An error is returned if at least one of them is invalid
 func validateVersions(pc api.VendorConsents) (err error) {
@@ -1,6 +1,7 @@
 package gdpr
 
 import (
+	"encoding/base64"
 	"errors"
 	"testing"
 	"time"
@@ -68,6 +69,65 @@ func TestParseConsent(t *testing.T) {
 	}
 }
 
+func TestParsePublisherCountry(t *testing.T) {
+	tests := []struct {
+		name     string
+		consent  string
+		expected string
+	}{
+		{
+			name:     "country",
+			consent:  withPublisherCountry(t, "CPuKGCPPuKGCPNEAAAENCZCAAAAAAAAAAAAAAAAAAAAA", "DE"),
+			expected: "DE",
+		},
+		{
+			name:     "country-with-segments",
+			consent:  withPublisherCountry(t, "CPuKGCPPuKGCPNEAAAENCZCAAAAAAAAAAAAAAAAAAAAA", "FR") + ".YAAAAAAAAAAA",
+			expected: "FR",
+		},
+		{
+			name:     "unset",
+			consent:  "CPuKGCPPuKGCPNEAAAENCZCAAAAAAAAAAAAAAAAAAAAA",
+			expected: "AA",
+		},
+		{
+			name:     "too-short",
+			consent:  "CPuKGCPPuKGCPNEAAAENCZCAAAAAAAAA",
+			expected: "",
+		},
+		{
+			name:     "malformed",
+			consent:  "not*base64",
+			expected: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, parsePublisherCountry(tt.consent))
+		})
+	}
+}
+
+// withPublisherCountry sets the publisher country of a TCF2 consent string, in bits 201 to 212 (zero-indexed) of its core segment.
+func withPublisherCountry(t *testing.T, consent string, country string) string {
+	data, err := base64.RawURLEncoding.DecodeString(consent)
+	if err != nil {
+		t.Fatalf("Failed to decode consent %s", consent)
+	}
+	for i := 0; i < 12; i++ {
+		letter := country[i/6] - 65
+		bit := uint(201 + i)
+		mask := byte(1) << (7 - bit%8)
+		if letter&(1<<(5-i%6)) != 0 {
+			data[bit/8] |= mask
+		} else {
+			data[bit/8] &^= mask
+		}
+	}
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
 func TestValidateVersions(t *testing.T) {
 	tests := []struct {
 		name          string
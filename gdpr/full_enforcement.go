@@ -27,6 +27,9 @@ func (fe *FullEnforcement) LegalBasis(vendorInfo VendorInfo, name string, consen
 	if consent.CheckPubRestriction(uint8(fe.cfg.PurposeID), pubRestrictNotAllowed, vendorInfo.vendorID) {
 		return false
 	}
+	if fe.cfg.restrictedVendor(name) {
+		return false
+	}
 	if !enforcePurpose && !enforceVendors {
 		return true
 	}
@@ -353,6 +353,31 @@ func TestLegalBasisWithNoPubRestrictionsAndWithPubRestrictionAllowAll(t *testing
 			wantLIPurposeResult:       false,
 			wantFlexPurposeResult:     false,
 		},
+		{
+			description: "enforce purpose & vendors on, purpose consent Y, vendor consent Y, bidder is restricted by the publisher",
+			config: purposeConfig{
+				EnforcePurpose:      true,
+				EnforceVendors:      true,
+				RestrictedVendorMap: map[string]struct{}{appnexus: {}},
+			},
+			consentNoPubRestriction:   P1P2P3PurposeConsentAndV32VendorConsent,
+			consentWithPubRestriction: P1P2P3PurposeConsentAndV32VendorConsentWithP1P2P3V32RestrictionAllowAll,
+			wantConsentPurposeResult:  false,
+			wantLIPurposeResult:       false,
+			wantFlexPurposeResult:     false,
+		},
+		{
+			description: "enforce purpose & vendors off, bidder is a vendor exception restricted by the publisher",
+			config: purposeConfig{
+				VendorExceptionMap:  map[string]struct{}{appnexus: {}},
+				RestrictedVendorMap: map[string]struct{}{appnexus: {}},
+			},
+			consentNoPubRestriction:   NoConsents,
+			consentWithPubRestriction: NoConsentsWithP1P2P3V32RestrictionAllowAll,
+			wantConsentPurposeResult:  false,
+			wantLIPurposeResult:       false,
+			wantFlexPurposeResult:     false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -133,8 +133,8 @@ func (p *permissionsImpl) allowSync(ctx context.Context, vendorID uint16, bidder
 		return true, nil
 	}
 
-	if p.cfg.PurposeOneTreatmentEnabled() && pc.consentMeta.PurposeOneTreatment() {
-		return p.cfg.PurposeOneTreatmentAccessAllowed(), nil
+	if p.cfg.PurposeOneTreatmentEnabled(pc.publisherCountry) && pc.consentMeta.PurposeOneTreatment() {
+		return p.cfg.PurposeOneTreatmentAccessAllowed(pc.publisherCountry), nil
 	}
 
 	purpose := consentconstants.Purpose(1)
@@ -2,6 +2,7 @@ package gdpr
 
 import (
 	"context"
+	"encoding/base64"
 	"errors"
 	"fmt"
 	"testing"
@@ -807,6 +808,64 @@ func TestAllowSync(t *testing.T) {
 	assert.EqualValuesf(t, true, allowSync, "BidderSyncAllowed failure")
 }
 
+func TestAllowSyncPurposeOneTreatmentByPublisherCountry(t *testing.T) {
+	const fullConsentToPurposesAndVendorsTwoSixEight = "COzTVhaOzTVhaGvAAAENAiCIAP_AAH_AAAAAAEEUACCKAAA"
+
+	vendorListData := MarshalVendorList(buildVendorList34())
+
+	tests := []struct {
+		description      string
+		publisherCountry string
+		wantAllowSync    bool
+	}{
+		{
+			description:      "Purpose one treatment of the publisher country disallows access",
+			publisherCountry: "DE",
+			wantAllowSync:    false,
+		},
+		{
+			description:      "Publisher country without purpose one treatment - consent is enforced",
+			publisherCountry: "FR",
+			wantAllowSync:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		tcf2AggConfig := allPurposesEnabledTCF2Config()
+		tcf2AggConfig.AccountConfig.PurposeOneTreatment = config.AccountGDPRPurposeOneTreatment{
+			Countries: map[string]config.AccountGDPRPurposeOneTreatmentCountry{
+				"DE": {Enabled: &[]bool{true}[0], AccessAllowed: &[]bool{false}[0]},
+			},
+		}
+
+		// sets the purpose one treatment flag, in bit 200 of the core segment
+		consent := withPublisherCountry(t, fullConsentToPurposesAndVendorsTwoSixEight, tt.publisherCountry)
+		data, _ := base64.RawURLEncoding.DecodeString(consent)
+		data[25] |= 0x80
+		consent = base64.RawURLEncoding.EncodeToString(data)
+
+		perms := permissionsImpl{
+			cfg:          &tcf2AggConfig,
+			hostVendorID: 2,
+			vendorIDs: map[openrtb_ext.BidderName]uint16{
+				openrtb_ext.BidderRubicon: 8,
+			},
+			fetchVendorList: listFetcher(map[uint16]map[uint16]vendorlist.VendorList{
+				2: {
+					34: parseVendorListDataV2(t, vendorListData),
+				},
+			}),
+			purposeEnforcerBuilder: NewPurposeEnforcerBuilder(&tcf2AggConfig),
+			gdprSignal:             SignalYes,
+			consent:                consent,
+		}
+
+		allowSync, err := perms.BidderSyncAllowed(context.Background(), openrtb_ext.BidderRubicon)
+		assert.NoError(t, err, tt.description)
+		assert.Equal(t, tt.wantAllowSync, allowSync, tt.description)
+	}
+}
+
 func TestProhibitedPurposeSync(t *testing.T) {
 	const fullConsentToPurposesAndVendorsTwoSixEight = "COzTVhaOzTVhaGvAAAENAiCIAP_AAH_AAAAAAEEUACCKAAA"
 
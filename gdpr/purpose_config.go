@@ -13,6 +13,7 @@ type purposeConfig struct {
 	EnforcePurpose             bool
 	EnforceVendors             bool
 	VendorExceptionMap         map[string]struct{}
+	RestrictedVendorMap        map[string]struct{}
 	BasicEnforcementVendorsMap map[string]struct{}
 }
 
@@ -35,3 +36,12 @@ func (pc *purposeConfig) vendorException(name string) bool {
 	_, found := pc.VendorExceptionMap[name]
 	return found
 }
+
+// restrictedVendor returns true if a given bidder/analytics adapter is restricted from the purpose by the publisher
+func (pc *purposeConfig) restrictedVendor(name string) bool {
+	if pc.RestrictedVendorMap == nil {
+		return false
+	}
+	_, found := pc.RestrictedVendorMap[name]
+	return found
+}
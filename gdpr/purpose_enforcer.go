@@ -70,6 +70,7 @@ func NewPurposeEnforcerBuilder(cfg TCF2ConfigReader) PurposeEnforcerBuilder {
 				EnforcePurpose:             cfg.PurposeEnforced(purpose),
 				EnforceVendors:             cfg.PurposeEnforcingVendors(purpose),
 				VendorExceptionMap:         cfg.PurposeVendorExceptions(purpose),
+				RestrictedVendorMap:        cfg.PurposeRestrictedVendors(purpose),
 				BasicEnforcementVendorsMap: cfg.BasicEnforcementVendors(),
 			}
 
@@ -89,6 +90,7 @@ func NewPurposeEnforcerBuilder(cfg TCF2ConfigReader) PurposeEnforcerBuilder {
 				EnforcePurpose:             cfg.PurposeEnforced(purpose),
 				EnforceVendors:             cfg.PurposeEnforcingVendors(purpose),
 				VendorExceptionMap:         cfg.PurposeVendorExceptions(purpose),
+				RestrictedVendorMap:        cfg.PurposeRestrictedVendors(purpose),
 				BasicEnforcementVendorsMap: cfg.BasicEnforcementVendors(),
 			}
 
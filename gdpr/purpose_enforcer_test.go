@@ -201,6 +201,7 @@ type fakeTCF2ConfigReader struct {
 	enforcePurpose             bool
 	enforceVendors             bool
 	vendorExceptionMap         map[string]struct{}
+	restrictedVendorMap        map[string]struct{}
 	basicEnforcementVendorsMap map[string]struct{}
 }
 
@@ -231,9 +232,12 @@ func (fcr *fakeTCF2ConfigReader) PurposeEnforcingVendors(purpose consentconstant
 func (fcr *fakeTCF2ConfigReader) PurposeVendorExceptions(purpose consentconstants.Purpose) map[string]struct{} {
 	return fcr.vendorExceptionMap
 }
-func (fcr *fakeTCF2ConfigReader) PurposeOneTreatmentEnabled() bool {
+func (fcr *fakeTCF2ConfigReader) PurposeRestrictedVendors(purpose consentconstants.Purpose) map[string]struct{} {
+	return fcr.restrictedVendorMap
+}
+func (fcr *fakeTCF2ConfigReader) PurposeOneTreatmentEnabled(string) bool {
 	return false
 }
-func (fcr *fakeTCF2ConfigReader) PurposeOneTreatmentAccessAllowed() bool {
+func (fcr *fakeTCF2ConfigReader) PurposeOneTreatmentAccessAllowed(string) bool {
 	return false
 }
@@ -0,0 +1,122 @@
+// Package geolocation fills in where a device is from its IP address, for requests which don't say. GDPR scope,
+// price floors and bidders all read the country, region and metro of device.geo, and many requests, especially
+// from browsers, have none.
+package geolocation
+
+import (
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/metrics"
+	"github.com/prebid/prebid-server/v2/util/task"
+)
+
+// Location is where an IP address is, in the form of OpenRTB's geo object.
+type Location struct {
+	// Country is the ISO 3166-1 alpha-3 code of the country
+	Country string
+	// Region is the ISO 3166-2 code of the country's subdivision, such as CA for California
+	Region string
+	// Metro is the Nielsen DMA code of the US metro area
+	Metro string
+}
+
+// Provider looks up where IP addresses are. Hosts with another source of locations can implement it.
+type Provider interface {
+	// Lookup returns where the IP address is, or nil if it isn't known.
+	Lookup(ip net.IP) (*Location, error)
+}
+
+// NewProvider returns the configured provider, or nil if geolocation is off.
+func NewProvider(cfg config.GeoLocation) (Provider, error) {
+	if !cfg.Enabled {
+		return nil, nil
+	}
+	switch cfg.Provider {
+	case config.GeoLocationProviderMaxMind:
+		maxMind, err := NewMaxMind(cfg.MaxMind.DatabasePath)
+		if err != nil {
+			return nil, err
+		}
+		return maxMind, nil
+	default:
+		return nil, fmt.Errorf("unknown geolocation provider %s", cfg.Provider)
+	}
+}
+
+// NewReloadTask returns a task which reloads the provider's database when it changes, or nil if the provider
+// isn't reloaded.
+func NewReloadTask(provider Provider, cfg config.GeoLocation) *task.TickerTask {
+	runner, ok := provider.(task.Runner)
+	if !ok || cfg.MaxMind.ReloadIntervalSeconds <= 0 {
+		return nil
+	}
+	return task.NewTickerTask(time.Duration(cfg.MaxMind.ReloadIntervalSeconds)*time.Second, runner)
+}
+
+// Enricher fills in the location of devices from a provider. A nil *Enricher is valid and leaves devices as
+// they are.
+type Enricher struct {
+	provider      Provider
+	metricsEngine metrics.MetricsEngine
+}
+
+// NewEnricher returns an enricher for the provider, or nil if there's no provider.
+func NewEnricher(provider Provider, metricsEngine metrics.MetricsEngine) *Enricher {
+	if provider == nil {
+		return nil
+	}
+	return &Enricher{provider: provider, metricsEngine: metricsEngine}
+}
+
+// Enrich sets the country, region and metro of device.geo where they're missing. A location in a different
+// country than the device's is ignored, since its region and metro wouldn't be in the device's country.
+func (e *Enricher) Enrich(device *openrtb2.Device) {
+	if e == nil || device == nil {
+		return
+	}
+	geo := device.Geo
+	if geo != nil && geo.Country != "" && geo.Region != "" && geo.Metro != "" {
+		return
+	}
+	ip := net.ParseIP(device.IP)
+	if ip == nil {
+		ip = net.ParseIP(device.IPv6)
+	}
+	if ip == nil {
+		return
+	}
+
+	start := time.Now()
+	location, err := e.provider.Lookup(ip)
+	status := metrics.GeoLookupFound
+	if err != nil {
+		status = metrics.GeoLookupError
+	} else if location == nil {
+		status = metrics.GeoLookupNotFound
+	}
+	e.metricsEngine.RecordGeoLookup(status, time.Since(start))
+	if status != metrics.GeoLookupFound || location.Country == "" {
+		return
+	}
+
+	if geo == nil {
+		geo = &openrtb2.Geo{}
+	} else if geo.Country != "" && !strings.EqualFold(geo.Country, location.Country) {
+		return
+	}
+	if geo.Country == "" {
+		geo.Country = location.Country
+	}
+	if geo.Region == "" {
+		geo.Region = location.Region
+	}
+	if geo.Metro == "" {
+		geo.Metro = location.Metro
+	}
+	device.Geo = geo
+}
@@ -0,0 +1,143 @@
+package geolocation
+
+import (
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/metrics"
+	"github.com/prebid/prebid-server/v2/util/ptrutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeProvider struct {
+	locations map[string]*Location
+	err       error
+	lookups   int
+}
+
+func (p *fakeProvider) Lookup(ip net.IP) (*Location, error) {
+	p.lookups++
+	return p.locations[ip.String()], p.err
+}
+
+func TestEnricherEnrich(t *testing.T) {
+	locations := map[string]*Location{
+		"216.160.83.56": {Country: "USA", Region: "WA", Metro: "819"},
+		"2001:db8::1":   {Country: "DEU"},
+	}
+
+	testCases := []struct {
+		description    string
+		device         *openrtb2.Device
+		err            error
+		expectedDevice *openrtb2.Device
+		expectedStatus metrics.GeoLookupStatus
+	}{
+		{
+			description:    "no geo",
+			device:         &openrtb2.Device{IP: "216.160.83.56"},
+			expectedDevice: &openrtb2.Device{IP: "216.160.83.56", Geo: &openrtb2.Geo{Country: "USA", Region: "WA", Metro: "819"}},
+			expectedStatus: metrics.GeoLookupFound,
+		},
+		{
+			description:    "ipv6",
+			device:         &openrtb2.Device{IPv6: "2001:db8::1"},
+			expectedDevice: &openrtb2.Device{IPv6: "2001:db8::1", Geo: &openrtb2.Geo{Country: "DEU"}},
+			expectedStatus: metrics.GeoLookupFound,
+		},
+		{
+			description:    "geo in the same country is kept and filled in",
+			device:         &openrtb2.Device{IP: "216.160.83.56", Geo: &openrtb2.Geo{Country: "usa", Region: "OR", Lat: ptrutil.ToPtr(45.5)}},
+			expectedDevice: &openrtb2.Device{IP: "216.160.83.56", Geo: &openrtb2.Geo{Country: "usa", Region: "OR", Metro: "819", Lat: ptrutil.ToPtr(45.5)}},
+			expectedStatus: metrics.GeoLookupFound,
+		},
+		{
+			description:    "geo in another country",
+			device:         &openrtb2.Device{IP: "216.160.83.56", Geo: &openrtb2.Geo{Country: "CAN"}},
+			expectedDevice: &openrtb2.Device{IP: "216.160.83.56", Geo: &openrtb2.Geo{Country: "CAN"}},
+			expectedStatus: metrics.GeoLookupFound,
+		},
+		{
+			description:    "complete geo isn't looked up",
+			device:         &openrtb2.Device{IP: "216.160.83.56", Geo: &openrtb2.Geo{Country: "USA", Region: "OR", Metro: "820"}},
+			expectedDevice: &openrtb2.Device{IP: "216.160.83.56", Geo: &openrtb2.Geo{Country: "USA", Region: "OR", Metro: "820"}},
+		},
+		{
+			description:    "no ip",
+			device:         &openrtb2.Device{UA: "Mozilla/5.0"},
+			expectedDevice: &openrtb2.Device{UA: "Mozilla/5.0"},
+		},
+		{
+			description:    "not found",
+			device:         &openrtb2.Device{IP: "10.0.0.1"},
+			expectedDevice: &openrtb2.Device{IP: "10.0.0.1"},
+			expectedStatus: metrics.GeoLookupNotFound,
+		},
+		{
+			description:    "error",
+			device:         &openrtb2.Device{IP: "216.160.83.56"},
+			err:            errors.New("invalid MaxMind DB data"),
+			expectedDevice: &openrtb2.Device{IP: "216.160.83.56"},
+			expectedStatus: metrics.GeoLookupError,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			metricsEngine := &metrics.MetricsEngineMock{}
+			if test.expectedStatus != "" {
+				metricsEngine.On("RecordGeoLookup", test.expectedStatus, mock.Anything).Once()
+			}
+			provider := &fakeProvider{locations: locations, err: test.err}
+
+			NewEnricher(provider, metricsEngine).Enrich(test.device)
+			assert.Equal(t, test.expectedDevice, test.device)
+			metricsEngine.AssertExpectations(t)
+			if test.expectedStatus == "" {
+				assert.Zero(t, provider.lookups)
+			}
+		})
+	}
+}
+
+func TestNilEnricher(t *testing.T) {
+	enricher := NewEnricher(nil, &metrics.MetricsEngineMock{})
+	assert.Nil(t, enricher)
+
+	device := &openrtb2.Device{IP: "216.160.83.56"}
+	enricher.Enrich(device)
+	assert.Nil(t, device.Geo)
+}
+
+func TestNewProvider(t *testing.T) {
+	provider, err := NewProvider(config.GeoLocation{Enabled: false, Provider: config.GeoLocationProviderMaxMind})
+	assert.NoError(t, err)
+	assert.Nil(t, provider)
+
+	_, err = NewProvider(config.GeoLocation{Enabled: true, Provider: "unknown"})
+	assert.EqualError(t, err, "unknown geolocation provider unknown")
+
+	path := filepath.Join(t.TempDir(), "GeoIP2-City-Test.mmdb")
+	require.NoError(t, os.WriteFile(path, buildTestDatabase(t, 6, 24, testNetworks), 0644))
+	provider, err = NewProvider(config.GeoLocation{Enabled: true, Provider: config.GeoLocationProviderMaxMind, MaxMind: config.GeoLocationMaxMind{DatabasePath: path}})
+	assert.NoError(t, err)
+	assert.IsType(t, &MaxMind{}, provider)
+}
+
+func TestNewReloadTask(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "GeoIP2-City-Test.mmdb")
+	require.NoError(t, os.WriteFile(path, buildTestDatabase(t, 6, 24, testNetworks), 0644))
+	maxMind, err := NewMaxMind(path)
+	require.NoError(t, err)
+
+	assert.NotNil(t, NewReloadTask(maxMind, config.GeoLocation{MaxMind: config.GeoLocationMaxMind{ReloadIntervalSeconds: 300}}))
+	assert.Nil(t, NewReloadTask(maxMind, config.GeoLocation{}), "no reload interval")
+	assert.Nil(t, NewReloadTask(&fakeProvider{}, config.GeoLocation{MaxMind: config.GeoLocationMaxMind{ReloadIntervalSeconds: 300}}), "provider which isn't reloaded")
+}
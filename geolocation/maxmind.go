@@ -0,0 +1,115 @@
+package geolocation
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/oschwald/maxminddb-golang"
+
+	"github.com/prebid/prebid-server/v2/logger"
+	"github.com/prebid/prebid-server/v2/util/countryutil"
+)
+
+// MaxMind looks up locations in a MaxMind DB file, such as GeoIP2 or GeoLite2 City. The file is reloaded when it
+// changes, so the database can be updated without restarting the server.
+//
+// The file is read with MaxMind's reader, which checks the sizes and pointers of the data against the file, and
+// bounds how deeply values may be nested, so a corrupt file fails lookups rather than the server.
+type MaxMind struct {
+	path string
+	db   atomic.Pointer[maxminddb.Reader]
+	// modTime and size identify the file the database was loaded from. They're only used by Run.
+	modTime time.Time
+	size    int64
+}
+
+// NewMaxMind loads the database from the file at path.
+func NewMaxMind(path string) (*MaxMind, error) {
+	m := &MaxMind{path: path}
+	if err := m.load(); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (m *MaxMind) load() error {
+	info, err := os.Stat(m.path)
+	if err != nil {
+		return err
+	}
+	buffer, err := os.ReadFile(m.path)
+	if err != nil {
+		return err
+	}
+	db, err := maxminddb.FromBytes(buffer)
+	if err != nil {
+		return fmt.Errorf("%s: %v", m.path, err)
+	}
+	m.db.Store(db)
+	m.modTime = info.ModTime()
+	m.size = info.Size()
+	return nil
+}
+
+// Run implements task.Runner. It reloads the database if the file has changed since it was loaded. If the new
+// file can't be loaded, the database already loaded is kept.
+func (m *MaxMind) Run() error {
+	info, err := os.Stat(m.path)
+	if err != nil {
+		logger.Errorf("Failed to check the MaxMind database for changes: %v", err)
+		return err
+	}
+	if info.ModTime().Equal(m.modTime) && info.Size() == m.size {
+		return nil
+	}
+	if err := m.load(); err != nil {
+		logger.Errorf("Failed to reload the MaxMind database: %v", err)
+		return err
+	}
+	logger.Infof("Reloaded the MaxMind database %s (%s)", m.path, m.db.Load().Metadata.DatabaseType)
+	return nil
+}
+
+// maxMindRecord is the part of a GeoIP2 or GeoLite2 City record a Location is made of.
+type maxMindRecord struct {
+	Country struct {
+		ISOCode string `maxminddb:"iso_code"`
+	} `maxminddb:"country"`
+	Subdivisions []struct {
+		ISOCode string `maxminddb:"iso_code"`
+	} `maxminddb:"subdivisions"`
+	Location struct {
+		MetroCode uint `maxminddb:"metro_code"`
+	} `maxminddb:"location"`
+}
+
+// Lookup implements Provider.
+func (m *MaxMind) Lookup(ip net.IP) (*Location, error) {
+	db := m.db.Load()
+	if ip.To4() == nil && db.Metadata.IPVersion == 4 {
+		// an IPv4 database has no IPv6 addresses
+		return nil, nil
+	}
+
+	var record maxMindRecord
+	_, found, err := db.LookupNetwork(ip, &record)
+	if err != nil || !found {
+		return nil, err
+	}
+
+	location := &Location{}
+	if record.Country.ISOCode != "" {
+		location.Country, _ = countryutil.ToAlpha3(record.Country.ISOCode)
+	}
+	if len(record.Subdivisions) > 0 {
+		location.Region = record.Subdivisions[0].ISOCode
+	}
+	if record.Location.MetroCode != 0 {
+		location.Metro = strconv.FormatUint(uint64(record.Location.MetroCode), 10)
+	}
+	return location, nil
+}
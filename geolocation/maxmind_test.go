@@ -0,0 +1,108 @@
+package geolocation
+
+import (
+	"bytes"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMaxMindLookup(t *testing.T) {
+	for _, ipVersion := range []int{4, 6} {
+		for _, recordSize := range []int{24, 28, 32} {
+			path := filepath.Join(t.TempDir(), "GeoIP2-City-Test.mmdb")
+			require.NoError(t, os.WriteFile(path, buildTestDatabase(t, ipVersion, recordSize, testNetworks), 0644))
+			provider, err := NewMaxMind(path)
+			require.NoError(t, err)
+
+			testCases := []struct {
+				ip       string
+				expected *Location
+			}{
+				{ip: "81.2.69.142", expected: &Location{Country: "GBR", Region: "ENG"}},
+				{ip: "216.160.83.56", expected: &Location{Country: "USA", Region: "WA", Metro: "819"}},
+				{ip: "81.2.70.1"},
+				{ip: "10.0.0.1"},
+				{ip: "2001:db8:1::1", expected: &Location{Country: "DEU"}},
+				{ip: "2001:db9::1"},
+			}
+
+			for _, test := range testCases {
+				if ipVersion == 4 && net.ParseIP(test.ip).To4() == nil {
+					test.expected = nil
+				}
+				location, err := provider.Lookup(net.ParseIP(test.ip))
+				assert.NoError(t, err, "IPv%d, %d bit records, %s", ipVersion, recordSize, test.ip)
+				assert.Equal(t, test.expected, location, "IPv%d, %d bit records, %s", ipVersion, recordSize, test.ip)
+			}
+		}
+	}
+}
+
+func TestMaxMindLookupCorrupt(t *testing.T) {
+	// Claim the country code "GB" is a string far longer than the file, which must fail the lookup rather than
+	// read or allocate past the end of the data.
+	buffer := buildTestDatabase(t, 6, 24, testNetworks)
+	code := bytes.Index(buffer, []byte("\x42GB"))
+	require.NotEqual(t, -1, code)
+	copy(buffer[code:], "\x5E\xFF\xFF")
+
+	path := filepath.Join(t.TempDir(), "corrupt.mmdb")
+	require.NoError(t, os.WriteFile(path, buffer, 0644))
+	provider, err := NewMaxMind(path)
+	require.NoError(t, err)
+
+	_, err = provider.Lookup(net.ParseIP("81.2.69.142"))
+	assert.Error(t, err)
+}
+
+func TestMaxMindReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "GeoIP2-City-Test.mmdb")
+	require.NoError(t, os.WriteFile(path, buildTestDatabase(t, 6, 24, testNetworks), 0644))
+	provider, err := NewMaxMind(path)
+	require.NoError(t, err)
+
+	assert.NoError(t, provider.Run(), "unchanged")
+	location, err := provider.Lookup(net.ParseIP("81.2.69.142"))
+	require.NoError(t, err)
+	assert.Equal(t, "GBR", location.Country)
+
+	moved := map[string]map[string]interface{}{
+		"81.2.69.0/24": {"country": map[string]interface{}{"iso_code": "IE"}},
+	}
+	require.NoError(t, os.WriteFile(path, buildTestDatabase(t, 6, 24, moved), 0644))
+	later := time.Now().Add(time.Minute)
+	require.NoError(t, os.Chtimes(path, later, later))
+	assert.NoError(t, provider.Run(), "changed")
+	location, err = provider.Lookup(net.ParseIP("81.2.69.142"))
+	require.NoError(t, err)
+	assert.Equal(t, "IRL", location.Country)
+
+	require.NoError(t, os.WriteFile(path, []byte("truncated"), 0644))
+	assert.Error(t, provider.Run(), "invalid")
+	location, err = provider.Lookup(net.ParseIP("81.2.69.142"))
+	require.NoError(t, err)
+	assert.Equal(t, "IRL", location.Country, "the last database loaded should be kept")
+}
+
+func TestNewMaxMindErrors(t *testing.T) {
+	dir := t.TempDir()
+	_, err := NewMaxMind(filepath.Join(dir, "missing.mmdb"))
+	assert.Error(t, err)
+
+	path := filepath.Join(dir, "invalid.mmdb")
+	require.NoError(t, os.WriteFile(path, []byte("not a database"), 0644))
+	_, err = NewMaxMind(path)
+	assert.ErrorContains(t, err, path+": ")
+
+	// The metadata claims a search tree larger than what's left of the file.
+	buffer := buildTestDatabase(t, 6, 24, testNetworks)
+	require.NoError(t, os.WriteFile(path, buffer[bytes.Index(buffer, metadataStart)-8:], 0644))
+	_, err = NewMaxMind(path)
+	assert.ErrorContains(t, err, path+": ")
+}
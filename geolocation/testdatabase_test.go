@@ -0,0 +1,247 @@
+package geolocation
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"net"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// The MaxMind DB format is a binary search tree over the bits of IP addresses, followed by a data section
+// whose values are encoded much like MessagePack. Its specification is at
+// https://maxmind.github.io/MaxMind-DB/. The tests write small databases in it rather than keeping binary
+// fixtures in the repository.
+
+// metadataStart marks the start of the metadata, which is at the end of the file.
+var metadataStart = []byte("\xAB\xCD\xEFMaxMind.com")
+
+// dataSectionSeparatorSize is the size of the zeros between the search tree and the data section.
+const dataSectionSeparatorSize = 16
+
+// The types of values in the data section.
+const (
+	typeExtended = 0
+	typePointer  = 1
+	typeString   = 2
+	typeDouble   = 3
+	typeBytes    = 4
+	typeUint16   = 5
+	typeUint32   = 6
+	typeMap      = 7
+	typeInt32    = 8
+	typeUint64   = 9
+	typeArray    = 11
+	typeBool     = 14
+	typeFloat    = 15
+)
+
+var testNetworks = map[string]map[string]interface{}{
+	"81.2.69.0/24": {
+		"country":      map[string]interface{}{"iso_code": "GB", "names": map[string]interface{}{"en": "United Kingdom"}},
+		"subdivisions": []interface{}{map[string]interface{}{"iso_code": "ENG"}},
+	},
+	"216.160.83.0/24": {
+		"city":         map[string]interface{}{"names": map[string]interface{}{"en": "Milton"}},
+		"country":      map[string]interface{}{"iso_code": "US", "names": map[string]interface{}{"en": "United States"}},
+		"location":     map[string]interface{}{"latitude": 47.2513, "longitude": -122.3149, "metro_code": uint16(819)},
+		"subdivisions": []interface{}{map[string]interface{}{"iso_code": "WA"}},
+	},
+	"2001:db8::/32": {
+		"country": map[string]interface{}{"iso_code": "DE", "names": map[string]interface{}{"en": "Germany"}},
+	},
+}
+
+// buildTestDatabase writes a MaxMind DB with a record for each network.
+func buildTestDatabase(t *testing.T, ipVersion, recordSize int, networks map[string]map[string]interface{}) []byte {
+	type record struct {
+		node int
+		data int
+	}
+	type node struct {
+		records [2]record
+	}
+	const empty = -1
+	nodes := []node{{records: [2]record{{node: empty, data: empty}, {node: empty, data: empty}}}}
+
+	cidrs := make([]string, 0, len(networks))
+	for cidr := range networks {
+		cidrs = append(cidrs, cidr)
+	}
+	sort.Strings(cidrs)
+
+	data := newTestEncoder()
+	for _, cidr := range cidrs {
+		ip, network, err := net.ParseCIDR(cidr)
+		require.NoError(t, err)
+		prefix, _ := network.Mask.Size()
+		if ip.To4() != nil {
+			ip = ip.To4()
+			if ipVersion == 6 {
+				ip = append(make(net.IP, 12), ip...)
+				prefix += 96
+			}
+		} else if ipVersion == 4 {
+			continue
+		}
+
+		dataOffset := data.buffer.Len()
+		data.encode(networks[cidr])
+
+		current := 0
+		for i := 0; i < prefix; i++ {
+			bit := (ip[i/8] >> (7 - i%8)) & 1
+			if i == prefix-1 {
+				nodes[current].records[bit] = record{node: empty, data: dataOffset}
+				break
+			}
+			if nodes[current].records[bit].node == empty {
+				nodes = append(nodes, node{records: [2]record{{node: empty, data: empty}, {node: empty, data: empty}}})
+				nodes[current].records[bit] = record{node: len(nodes) - 1, data: empty}
+			}
+			current = nodes[current].records[bit].node
+		}
+	}
+
+	var out bytes.Buffer
+	nodeCount := uint32(len(nodes))
+	value := func(r record) uint32 {
+		switch {
+		case r.node != empty:
+			return uint32(r.node)
+		case r.data != empty:
+			return nodeCount + dataSectionSeparatorSize + uint32(r.data)
+		default:
+			return nodeCount
+		}
+	}
+	for _, n := range nodes {
+		left, right := value(n.records[0]), value(n.records[1])
+		switch recordSize {
+		case 28:
+			out.Write([]byte{byte(left >> 16), byte(left >> 8), byte(left), byte(left>>20)&0xF0 | byte(right>>24)&0x0F, byte(right >> 16), byte(right >> 8), byte(right)})
+		case 32:
+			binary.Write(&out, binary.BigEndian, [2]uint32{left, right})
+		default:
+			out.Write([]byte{byte(left >> 16), byte(left >> 8), byte(left), byte(right >> 16), byte(right >> 8), byte(right)})
+		}
+	}
+	out.Write(make([]byte, dataSectionSeparatorSize))
+	out.Write(data.buffer.Bytes())
+
+	out.Write(metadataStart)
+	metadata := newTestEncoder()
+	metadata.encode(map[string]interface{}{
+		"binary_format_major_version": uint16(2),
+		"binary_format_minor_version": uint16(0),
+		"database_type":               "GeoIP2-City-Test",
+		"ip_version":                  uint16(ipVersion),
+		"languages":                   []interface{}{"en"},
+		"node_count":                  nodeCount,
+		"record_size":                 uint16(recordSize),
+	})
+	out.Write(metadata.buffer.Bytes())
+	return out.Bytes()
+}
+
+// testEncoder writes values in the format of a MaxMind DB data section. Strings after the first are written as
+// pointers to the first, as they are in MaxMind's databases.
+type testEncoder struct {
+	buffer  bytes.Buffer
+	strings map[string]int
+}
+
+func newTestEncoder() *testEncoder {
+	return &testEncoder{strings: make(map[string]int)}
+}
+
+func (e *testEncoder) encode(value interface{}) {
+	switch v := value.(type) {
+	case string:
+		if offset, ok := e.strings[v]; ok {
+			if offset < 2048 {
+				e.buffer.Write([]byte{typePointer<<5 | byte(offset>>8), byte(offset)})
+			} else {
+				offset -= 2048
+				e.buffer.Write([]byte{typePointer<<5 | 1<<3 | byte(offset>>16), byte(offset >> 8), byte(offset)})
+			}
+			return
+		}
+		e.strings[v] = e.buffer.Len()
+		e.writeControl(typeString, len(v))
+		e.buffer.WriteString(v)
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		e.writeControl(typeMap, len(v))
+		for _, key := range keys {
+			e.encode(key)
+			e.encode(v[key])
+		}
+	case []interface{}:
+		e.writeControl(typeArray, len(v))
+		for _, element := range v {
+			e.encode(element)
+		}
+	case []byte:
+		e.writeControl(typeBytes, len(v))
+		e.buffer.Write(v)
+	case float64:
+		e.writeControl(typeDouble, 8)
+		binary.Write(&e.buffer, binary.BigEndian, math.Float64bits(v))
+	case float32:
+		e.writeControl(typeFloat, 4)
+		binary.Write(&e.buffer, binary.BigEndian, math.Float32bits(v))
+	case int32:
+		e.writeControl(typeInt32, 4)
+		binary.Write(&e.buffer, binary.BigEndian, v)
+	case uint16:
+		e.writeUint(typeUint16, uint64(v))
+	case uint32:
+		e.writeUint(typeUint32, uint64(v))
+	case uint64:
+		e.writeUint(typeUint64, v)
+	case bool:
+		size := 0
+		if v {
+			size = 1
+		}
+		e.writeControl(typeBool, size)
+	}
+}
+
+func (e *testEncoder) writeUint(typeNum int, value uint64) {
+	var b []byte
+	for ; value > 0; value >>= 8 {
+		b = append([]byte{byte(value)}, b...)
+	}
+	e.writeControl(typeNum, len(b))
+	e.buffer.Write(b)
+}
+
+func (e *testEncoder) writeControl(typeNum, size int) {
+	var extended []byte
+	if typeNum > typeMap {
+		extended = []byte{byte(typeNum - 7)}
+		typeNum = typeExtended
+	}
+	var sizeBytes []byte
+	switch {
+	case size < 29:
+	case size < 285:
+		sizeBytes = []byte{byte(size - 29)}
+		size = 29
+	default:
+		sizeBytes = []byte{byte((size - 285) >> 8), byte(size - 285)}
+		size = 30
+	}
+	e.buffer.WriteByte(byte(typeNum<<5 | size))
+	e.buffer.Write(extended)
+	e.buffer.Write(sizeBytes)
+}
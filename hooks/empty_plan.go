@@ -29,6 +29,10 @@ func (e EmptyPlanBuilder) PlanForRawBidderResponseStage(endpoint string, account
 	return nil
 }
 
+func (e EmptyPlanBuilder) PlanForRawBidderResponsePerSeatStage(endpoint string, account *config.Account) Plan[hookstage.RawBidderResponsePerSeat] {
+	return nil
+}
+
 func (e EmptyPlanBuilder) PlanForAllProcessedBidResponsesStage(endpoint string, account *config.Account) Plan[hookstage.AllProcessedBidResponses] {
 	return nil
 }
@@ -3,9 +3,9 @@ package hookexecution
 import (
 	"sync"
 
-	"github.com/golang/glog"
 	"github.com/prebid/prebid-server/v2/config"
 	"github.com/prebid/prebid-server/v2/hooks/hookstage"
+	"github.com/prebid/prebid-server/v2/logger"
 	"github.com/prebid/prebid-server/v2/privacy"
 )
 
@@ -17,6 +17,10 @@ type executionContext struct {
 	account         *config.Account
 	moduleContexts  *moduleContexts
 	activityControl privacy.ActivityControl
+	// canarySeed decides which requests run the canary versions of modules, consistently across stages.
+	canarySeed uint64
+	// skippedModules are the modules whose hooks aren't run, such as optional modules while the server is short on memory.
+	skippedModules map[string]struct{}
 }
 
 func (ctx executionContext) getModuleContext(moduleName string) hookstage.ModuleInvocationContext {
@@ -30,7 +34,7 @@ func (ctx executionContext) getModuleContext(moduleName string) hookstage.Module
 	if ctx.account != nil {
 		cfg, err := ctx.account.Hooks.Modules.ModuleConfig(moduleName)
 		if err != nil {
-			glog.Warningf("Failed to get account config for %s module: %s", moduleName, err)
+			logger.Warningf("Failed to get account config for %s module: %s", moduleName, err)
 		}
 
 		moduleInvocationCtx.AccountConfig = cfg
@@ -2,7 +2,9 @@ package hookexecution
 
 import (
 	"context"
+	"encoding/binary"
 	"fmt"
+	"hash/fnv"
 	"strings"
 	"sync"
 	"time"
@@ -70,6 +72,10 @@ func executeGroup[H any, P any](
 	resp := make(chan hookResponse[P])
 
 	for _, hook := range group.Hooks {
+		if _, skipped := executionCtx.skippedModules[hook.Module]; skipped {
+			continue
+		}
+		hook = chooseCanary(hook, executionCtx.canarySeed)
 		mCtx := executionCtx.getModuleContext(hook.Module)
 		newPayload := handleModuleActivities(hook.Code, executionCtx.activityControl, payload, executionCtx.account)
 		wg.Add(1)
@@ -89,6 +95,24 @@ func executeGroup[H any, P any](
 	return handleHookResponses(executionCtx, hookResponses, payload, metricEngine)
 }
 
+// chooseCanary returns the canary version of the hook if the request is among the canary's share of the
+// requests. The draw only depends on the seed and the module, so a request runs the same version of a
+// module at each stage.
+func chooseCanary[H any](hook hooks.HookWrapper[H], seed uint64) hooks.HookWrapper[H] {
+	if hook.Canary == nil || hook.CanaryPercent <= 0 {
+		return hook
+	}
+
+	h := fnv.New64a()
+	binary.Write(h, binary.LittleEndian, seed)
+	h.Write([]byte(hook.Module))
+	draw := float64(h.Sum64()%10000) / 100
+	if draw < hook.CanaryPercent {
+		return *hook.Canary
+	}
+	return hook
+}
+
 func executeHook[H any, P any](
 	moduleCtx hookstage.ModuleInvocationContext,
 	hw hooks.HookWrapper[H],
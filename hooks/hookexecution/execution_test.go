@@ -5,6 +5,7 @@ import (
 
 	"github.com/prebid/openrtb/v20/openrtb2"
 	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/hooks"
 	"github.com/prebid/prebid-server/v2/hooks/hookstage"
 	"github.com/prebid/prebid-server/v2/openrtb_ext"
 	"github.com/prebid/prebid-server/v2/privacy"
@@ -233,3 +234,50 @@ func TestHandleModuleActivitiesNoBidderRequestPayload(t *testing.T) {
 		})
 	}
 }
+
+func TestChooseCanary(t *testing.T) {
+	canary := &hooks.HookWrapper[hookstage.Entrypoint]{Module: "foobar_v2", Code: "foo"}
+
+	testCases := []struct {
+		description    string
+		canaryPercent  float64
+		canary         *hooks.HookWrapper[hookstage.Entrypoint]
+		expectedCanary int
+	}{
+		{description: "No canary", canaryPercent: 100, canary: nil, expectedCanary: 0},
+		{description: "Canary never chosen", canaryPercent: 0, canary: canary, expectedCanary: 0},
+		{description: "Canary always chosen", canaryPercent: 100, canary: canary, expectedCanary: 1000},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			hook := hooks.HookWrapper[hookstage.Entrypoint]{Module: "foobar", Code: "foo", Canary: test.canary, CanaryPercent: test.canaryPercent}
+			chosen := 0
+			for seed := uint64(0); seed < 1000; seed++ {
+				if chooseCanary(hook, seed).Module == "foobar_v2" {
+					chosen++
+				}
+			}
+			assert.Equal(t, test.expectedCanary, chosen)
+		})
+	}
+}
+
+func TestChooseCanarySharesRequests(t *testing.T) {
+	hook := hooks.HookWrapper[hookstage.Entrypoint]{
+		Module:        "foobar",
+		Code:          "foo",
+		Canary:        &hooks.HookWrapper[hookstage.Entrypoint]{Module: "foobar_v2", Code: "foo"},
+		CanaryPercent: 25,
+	}
+
+	chosen := 0
+	for seed := uint64(0); seed < 10000; seed++ {
+		first := chooseCanary(hook, seed)
+		assert.Equal(t, first, chooseCanary(hook, seed), "the same request must always run the same version")
+		if first.Module == "foobar_v2" {
+			chosen++
+		}
+	}
+	assert.InDelta(t, 2500, chosen, 250)
+}
@@ -2,6 +2,7 @@ package hookexecution
 
 import (
 	"context"
+	"math/rand"
 	"net/http"
 	"sync"
 
@@ -29,6 +30,7 @@ const (
 	entityAuctionRequest           entity = "auction-request"
 	entityAuctionResponse          entity = "auction_response"
 	entityAllProcessedBidResponses entity = "all_processed_bid_responses"
+	entityRawBidderResponsePerSeat entity = "raw_bidder_response_per_seat"
 )
 
 type StageExecutor interface {
@@ -37,6 +39,7 @@ type StageExecutor interface {
 	ExecuteProcessedAuctionStage(req *openrtb_ext.RequestWrapper) error
 	ExecuteBidderRequestStage(req *openrtb_ext.RequestWrapper, bidder string) *RejectError
 	ExecuteRawBidderResponseStage(response *adapters.BidderResponse, bidder string) *RejectError
+	ExecuteRawBidderResponsePerSeatStage(request *openrtb_ext.RequestWrapper, seatBids map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid) (map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid, *RejectError)
 	ExecuteAllProcessedBidResponsesStage(adapterBids map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid)
 	ExecuteAuctionResponseStage(response *openrtb2.BidResponse)
 }
@@ -45,6 +48,7 @@ type HookStageExecutor interface {
 	StageExecutor
 	SetAccount(account *config.Account)
 	SetActivityControl(activityControl privacy.ActivityControl)
+	SkipModules(modules []string)
 	GetOutcomes() []StageOutcome
 }
 
@@ -57,6 +61,8 @@ type hookExecutor struct {
 	moduleContexts  *moduleContexts
 	metricEngine    metrics.MetricsEngine
 	activityControl privacy.ActivityControl
+	canarySeed      uint64
+	skippedModules  map[string]struct{}
 	// Mutex needed for BidderRequest and RawBidderResponse Stages as they are run in several goroutines
 	sync.Mutex
 }
@@ -68,6 +74,7 @@ func NewHookExecutor(builder hooks.ExecutionPlanBuilder, endpoint string, me met
 		stageOutcomes:  []StageOutcome{},
 		moduleContexts: &moduleContexts{ctxs: make(map[string]hookstage.ModuleContext)},
 		metricEngine:   me,
+		canarySeed:     rand.Uint64(),
 	}
 }
 
@@ -84,6 +91,20 @@ func (e *hookExecutor) SetActivityControl(activityControl privacy.ActivityContro
 	e.activityControl = activityControl
 }
 
+// SkipModules keeps the modules' hooks from being run at the stages executed from then on, including the
+// canary versions of the modules.
+func (e *hookExecutor) SkipModules(modules []string) {
+	if len(modules) == 0 {
+		return
+	}
+	if e.skippedModules == nil {
+		e.skippedModules = make(map[string]struct{}, len(modules))
+	}
+	for _, module := range modules {
+		e.skippedModules[module] = struct{}{}
+	}
+}
+
 func (e *hookExecutor) GetOutcomes() []StageOutcome {
 	return e.stageOutcomes
 }
@@ -242,6 +263,39 @@ func (e *hookExecutor) ExecuteRawBidderResponseStage(response *adapters.BidderRe
 	return reject
 }
 
+// ExecuteRawBidderResponsePerSeatStage returns the seatbids the hooks leave, or none if a hook rejects them.
+func (e *hookExecutor) ExecuteRawBidderResponsePerSeatStage(request *openrtb_ext.RequestWrapper, seatBids map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid) (map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid, *RejectError) {
+	plan := e.planBuilder.PlanForRawBidderResponsePerSeatStage(e.endpoint, e.account)
+	if len(plan) == 0 {
+		return seatBids, nil
+	}
+
+	handler := func(
+		ctx context.Context,
+		moduleCtx hookstage.ModuleInvocationContext,
+		hook hookstage.RawBidderResponsePerSeat,
+		payload hookstage.RawBidderResponsePerSeatPayload,
+	) (hookstage.HookResult[hookstage.RawBidderResponsePerSeatPayload], error) {
+		return hook.HandleRawBidderResponsePerSeatHook(ctx, moduleCtx, payload)
+	}
+
+	stageName := hooks.StageRawBidderResponsePerSeat.String()
+	executionCtx := e.newContext(stageName)
+	payload := hookstage.RawBidderResponsePerSeatPayload{Request: request, SeatBids: seatBids}
+
+	outcome, payload, contexts, reject := executeStage(executionCtx, plan, payload, handler, e.metricEngine)
+	outcome.Entity = entityRawBidderResponsePerSeat
+	outcome.Stage = stageName
+
+	e.saveModuleContexts(contexts)
+	e.pushStageOutcome(outcome)
+
+	if reject != nil {
+		return map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid{}, reject
+	}
+	return payload.SeatBids, nil
+}
+
 func (e *hookExecutor) ExecuteAllProcessedBidResponsesStage(adapterBids map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid) {
 	plan := e.planBuilder.PlanForAllProcessedBidResponsesStage(e.endpoint, e.account)
 	if len(plan) == 0 {
@@ -303,6 +357,8 @@ func (e *hookExecutor) newContext(stage string) executionContext {
 		moduleContexts:  e.moduleContexts,
 		stage:           stage,
 		activityControl: e.activityControl,
+		canarySeed:      e.canarySeed,
+		skippedModules:  e.skippedModules,
 	}
 }
 
@@ -326,6 +382,8 @@ func (executor EmptyHookExecutor) SetAccount(_ *config.Account) {}
 
 func (executor EmptyHookExecutor) SetActivityControl(_ privacy.ActivityControl) {}
 
+func (executor EmptyHookExecutor) SkipModules(_ []string) {}
+
 func (executor EmptyHookExecutor) GetOutcomes() []StageOutcome {
 	return []StageOutcome{}
 }
@@ -350,6 +408,10 @@ func (executor EmptyHookExecutor) ExecuteRawBidderResponseStage(_ *adapters.Bidd
 	return nil
 }
 
+func (executor EmptyHookExecutor) ExecuteRawBidderResponsePerSeatStage(_ *openrtb_ext.RequestWrapper, seatBids map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid) (map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid, *RejectError) {
+	return seatBids, nil
+}
+
 func (executor EmptyHookExecutor) ExecuteAllProcessedBidResponsesStage(_ map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid) {
 }
 
@@ -21,6 +21,7 @@ import (
 	"github.com/prebid/prebid-server/v2/privacy"
 	"github.com/prebid/prebid-server/v2/util/ptrutil"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/mock"
 )
 
@@ -1707,6 +1708,103 @@ func TestExecuteAllProcessedBidResponsesStage(t *testing.T) {
 	}
 }
 
+// rawBidderResponsePerSeatPlanBuilder plans the hooks, each in a group of its own, at the raw_bidder_response_per_seat stage.
+type rawBidderResponsePerSeatPlanBuilder struct {
+	hooks.EmptyPlanBuilder
+	hooks []hookstage.RawBidderResponsePerSeat
+}
+
+func (b rawBidderResponsePerSeatPlanBuilder) PlanForRawBidderResponsePerSeatStage(_ string, _ *config.Account) hooks.Plan[hookstage.RawBidderResponsePerSeat] {
+	plan := hooks.Plan[hookstage.RawBidderResponsePerSeat]{}
+	for _, hook := range b.hooks {
+		plan = append(plan, hooks.Group[hookstage.RawBidderResponsePerSeat]{
+			Timeout: 10 * time.Millisecond,
+			Hooks:   []hooks.HookWrapper[hookstage.RawBidderResponsePerSeat]{{Module: "foobar", Code: "foo", Hook: hook}},
+		})
+	}
+	return plan
+}
+
+func TestExecuteRawBidderResponsePerSeatStage(t *testing.T) {
+	lowBid := &entities.PbsOrtbBid{Bid: &openrtb2.Bid{ID: "low", Price: 1}}
+	highBid := &entities.PbsOrtbBid{Bid: &openrtb2.Bid{ID: "high", Price: 4}}
+	vetoedBid := &entities.PbsOrtbBid{Bid: &openrtb2.Bid{ID: "vetoed", Price: 2}}
+
+	testCases := []struct {
+		description      string
+		givenHooks       []hookstage.RawBidderResponsePerSeat
+		expectedSeatBids map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid
+		expectedReject   bool
+		expectedActions  []Action
+		expectedOutcomes int
+	}{
+		{
+			description: "Seatbids not changed if hook execution plan empty",
+			expectedSeatBids: map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid{
+				"low-bidder":    {Seat: "low-bidder", Bids: []*entities.PbsOrtbBid{lowBid}},
+				"high-bidder":   {Seat: "high-bidder", Bids: []*entities.PbsOrtbBid{highBid}},
+				"vetoed-bidder": {Seat: "vetoed-bidder", Bids: []*entities.PbsOrtbBid{vetoedBid}},
+			},
+		},
+		{
+			description: "Seats vetoed and re-priced with sight of every seat",
+			givenHooks:  []hookstage.RawBidderResponsePerSeat{mockVetoSeatHook{}, mockRepriceSeatHook{}},
+			expectedSeatBids: map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid{
+				"low-bidder":  {Seat: "low-bidder", Bids: []*entities.PbsOrtbBid{lowBid}},
+				"high-bidder": {Seat: "high-bidder", Bids: []*entities.PbsOrtbBid{{Bid: &openrtb2.Bid{ID: "high", Price: 2}}}},
+			},
+			expectedActions:  []Action{ActionUpdate, ActionUpdate},
+			expectedOutcomes: 1,
+		},
+		{
+			description:      "Stage execution can be rejected, which drops every seat",
+			givenHooks:       []hookstage.RawBidderResponsePerSeat{mockRejectHook{}},
+			expectedSeatBids: map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid{},
+			expectedReject:   true,
+			expectedActions:  []Action{ActionReject},
+			expectedOutcomes: 1,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			givenSeatBids := map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid{
+				"low-bidder":    {Seat: "low-bidder", Bids: []*entities.PbsOrtbBid{lowBid}},
+				"high-bidder":   {Seat: "high-bidder", Bids: []*entities.PbsOrtbBid{highBid}},
+				"vetoed-bidder": {Seat: "vetoed-bidder", Bids: []*entities.PbsOrtbBid{vetoedBid}},
+			}
+			exec := NewHookExecutor(rawBidderResponsePerSeatPlanBuilder{hooks: test.givenHooks}, EndpointAuction, &metricsConfig.NilMetricsEngine{})
+			privacyConfig := getModuleActivities("foo", false, false)
+			exec.SetActivityControl(privacy.NewActivityControl(privacyConfig))
+
+			seatBids, reject := exec.ExecuteRawBidderResponsePerSeatStage(&openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{ID: "request"}}, givenSeatBids)
+
+			assert.Equal(t, test.expectedSeatBids, seatBids, "Incorrect seatbids.")
+			assert.Len(t, givenSeatBids, 3, "The given seatbids mustn't be changed.")
+			assert.Equal(t, float64(4), highBid.Bid.Price, "The given bids mustn't be changed.")
+			if test.expectedReject {
+				assert.Equal(t, &RejectError{0, HookID{ModuleCode: "foobar", HookImplCode: "foo"}, hooks.StageRawBidderResponsePerSeat.String()}, reject)
+			} else {
+				assert.Nil(t, reject)
+			}
+
+			stageOutcomes := exec.GetOutcomes()
+			require.Len(t, stageOutcomes, test.expectedOutcomes, "Incorrect stage outcomes.")
+			var actions []Action
+			for _, outcome := range stageOutcomes {
+				assert.Equal(t, entityRawBidderResponsePerSeat, outcome.Entity)
+				assert.Equal(t, hooks.StageRawBidderResponsePerSeat.String(), outcome.Stage)
+				for _, group := range outcome.Groups {
+					for _, result := range group.InvocationResults {
+						actions = append(actions, result.Action)
+					}
+				}
+			}
+			assert.Equal(t, test.expectedActions, actions)
+		})
+	}
+}
+
 func TestExecuteAuctionResponseStage(t *testing.T) {
 	foobarModuleCtx := &moduleContexts{ctxs: map[string]hookstage.ModuleContext{"foobar": nil}}
 	resp := &openrtb2.BidResponse{CustomData: "some-custom-data"}
@@ -2058,6 +2156,52 @@ func TestInterStageContextCommunication(t *testing.T) {
 	}}, exec.moduleContexts, "Wrong module contexts after executing auction-response hook.")
 }
 
+func TestSkipModules(t *testing.T) {
+	body := []byte(`{"name": "John", "last_name": "Doe"}`)
+	testCases := []struct {
+		description    string
+		skippedModules []string
+		expectedBody   string
+		expectedHooks  int
+	}{
+		{
+			description:   "no-modules-skipped",
+			expectedBody:  `{"last_name": "Doe", "foo": "bar"}`,
+			expectedHooks: 5,
+		},
+		{
+			description:    "other-module-skipped",
+			skippedModules: []string{"acme"},
+			expectedBody:   `{"last_name": "Doe", "foo": "bar"}`,
+			expectedHooks:  5,
+		},
+		{
+			description:    "module-skipped",
+			skippedModules: []string{"acme", "foobar"},
+			expectedBody:   string(body),
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			exec := NewHookExecutor(TestApplyHookMutationsBuilder{}, EndpointAuction, &metricsConfig.NilMetricsEngine{})
+			exec.SkipModules(test.skippedModules)
+			req, err := http.NewRequest(http.MethodPost, "https://prebid.com/openrtb2/auction", bytes.NewReader(body))
+			require.NoError(t, err)
+
+			newBody, reject := exec.ExecuteEntrypointStage(req, body)
+			assert.Nil(t, reject)
+			assert.JSONEq(t, test.expectedBody, string(newBody))
+
+			hooksRun := 0
+			for _, group := range exec.GetOutcomes()[0].Groups {
+				hooksRun += len(group.InvocationResults)
+			}
+			assert.Equal(t, test.expectedHooks, hooksRun)
+		})
+	}
+}
+
 type TestApplyHookMutationsBuilder struct {
 	hooks.EmptyPlanBuilder
 }
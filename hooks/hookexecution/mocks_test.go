@@ -5,6 +5,7 @@ import (
 	"errors"
 	"time"
 
+	"github.com/prebid/prebid-server/v2/exchange/entities"
 	"github.com/prebid/prebid-server/v2/hooks/hookstage"
 	"github.com/prebid/prebid-server/v2/openrtb_ext"
 	"github.com/prebid/prebid-server/v2/util/ptrutil"
@@ -94,6 +95,10 @@ func (e mockRejectHook) HandleRawBidderResponseHook(_ context.Context, _ hooksta
 	return hookstage.HookResult[hookstage.RawBidderResponsePayload]{Reject: true}, nil
 }
 
+func (e mockRejectHook) HandleRawBidderResponsePerSeatHook(_ context.Context, _ hookstage.ModuleInvocationContext, _ hookstage.RawBidderResponsePerSeatPayload) (hookstage.HookResult[hookstage.RawBidderResponsePerSeatPayload], error) {
+	return hookstage.HookResult[hookstage.RawBidderResponsePerSeatPayload]{Reject: true}, nil
+}
+
 func (e mockRejectHook) HandleAllProcessedBidResponsesHook(_ context.Context, _ hookstage.ModuleInvocationContext, _ hookstage.AllProcessedBidResponsesPayload) (hookstage.HookResult[hookstage.AllProcessedBidResponsesPayload], error) {
 	return hookstage.HookResult[hookstage.AllProcessedBidResponsesPayload]{Reject: true}, nil
 }
@@ -362,6 +367,41 @@ func (e mockUpdateBidderResponseHook) HandleRawBidderResponseHook(_ context.Cont
 	return hookstage.HookResult[hookstage.RawBidderResponsePayload]{ChangeSet: c}, nil
 }
 
+type mockVetoSeatHook struct{}
+
+func (e mockVetoSeatHook) HandleRawBidderResponsePerSeatHook(_ context.Context, _ hookstage.ModuleInvocationContext, _ hookstage.RawBidderResponsePerSeatPayload) (hookstage.HookResult[hookstage.RawBidderResponsePerSeatPayload], error) {
+	c := hookstage.ChangeSet[hookstage.RawBidderResponsePerSeatPayload]{}
+	c.RawBidderResponsePerSeat().Veto("vetoed-bidder")
+	return hookstage.HookResult[hookstage.RawBidderResponsePerSeatPayload]{ChangeSet: c}, nil
+}
+
+// mockRepriceSeatHook halves the prices of the bids of the seat with the highest bid.
+type mockRepriceSeatHook struct{}
+
+func (e mockRepriceSeatHook) HandleRawBidderResponsePerSeatHook(_ context.Context, _ hookstage.ModuleInvocationContext, payload hookstage.RawBidderResponsePerSeatPayload) (hookstage.HookResult[hookstage.RawBidderResponsePerSeatPayload], error) {
+	var highestSeat openrtb_ext.BidderName
+	var highestPrice float64
+	for seat, seatBid := range payload.SeatBids {
+		for _, bid := range seatBid.Bids {
+			if bid.Bid.Price > highestPrice {
+				highestSeat, highestPrice = seat, bid.Bid.Price
+			}
+		}
+	}
+
+	var repriced []*entities.PbsOrtbBid
+	for _, bid := range payload.SeatBids[highestSeat].Bids {
+		ortbBid := *bid.Bid
+		ortbBid.Price /= 2
+		repricedBid := *bid
+		repricedBid.Bid = &ortbBid
+		repriced = append(repriced, &repricedBid)
+	}
+	c := hookstage.ChangeSet[hookstage.RawBidderResponsePerSeatPayload]{}
+	c.RawBidderResponsePerSeat().UpdateBids(highestSeat, repriced)
+	return hookstage.HookResult[hookstage.RawBidderResponsePerSeatPayload]{ChangeSet: c}, nil
+}
+
 type mockUpdateBiddersResponsesHook struct{}
 
 func (e mockUpdateBiddersResponsesHook) HandleAllProcessedBidResponsesHook(_ context.Context, _ hookstage.ModuleInvocationContext, _ hookstage.AllProcessedBidResponsesPayload) (hookstage.HookResult[hookstage.AllProcessedBidResponsesPayload], error) {
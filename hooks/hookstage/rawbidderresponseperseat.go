@@ -0,0 +1,35 @@
+package hookstage
+
+import (
+	"context"
+
+	"github.com/prebid/prebid-server/v2/exchange/entities"
+	"github.com/prebid/prebid-server/v2/openrtb_ext"
+)
+
+// RawBidderResponsePerSeat hooks are invoked once every bidder has responded,
+// over the seatbids of all the bidders at once, before the bids are
+// floored, targeted and cached. They let modules compare the seats
+// with each other, and veto, change or re-price their bids.
+//
+// At this stage, account config is available,
+// so it can be configured at the account-level execution plan,
+// the account-level module config is passed to hooks.
+//
+// Rejection results in ignoring the bids of every seat.
+type RawBidderResponsePerSeat interface {
+	HandleRawBidderResponsePerSeatHook(
+		context.Context,
+		ModuleInvocationContext,
+		RawBidderResponsePerSeatPayload,
+	) (HookResult[RawBidderResponsePerSeatPayload], error)
+}
+
+// RawBidderResponsePerSeatPayload consists of the seatbids of the auction
+// by seat, and the auction's request for context.
+// Hooks are allowed to veto seats and replace their bids using mutations,
+// and mustn't change the request.
+type RawBidderResponsePerSeatPayload struct {
+	Request  *openrtb_ext.RequestWrapper
+	SeatBids map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid
+}
@@ -0,0 +1,71 @@
+package hookstage
+
+import (
+	"errors"
+
+	"github.com/prebid/prebid-server/v2/exchange/entities"
+	"github.com/prebid/prebid-server/v2/openrtb_ext"
+)
+
+func (c *ChangeSet[T]) RawBidderResponsePerSeat() ChangeSetRawBidderResponsePerSeat[T] {
+	return ChangeSetRawBidderResponsePerSeat[T]{changeSet: c}
+}
+
+type ChangeSetRawBidderResponsePerSeat[T any] struct {
+	changeSet *ChangeSet[T]
+}
+
+func (c ChangeSetRawBidderResponsePerSeat[T]) castPayload(p T) (RawBidderResponsePerSeatPayload, error) {
+	if payload, ok := any(p).(RawBidderResponsePerSeatPayload); ok {
+		return payload, nil
+	}
+	return RawBidderResponsePerSeatPayload{}, errors.New("failed to cast RawBidderResponsePerSeatPayload")
+}
+
+// Veto drops the seatbids of the seats from the auction.
+func (c ChangeSetRawBidderResponsePerSeat[T]) Veto(seats ...openrtb_ext.BidderName) {
+	c.mutateSeatBids(func(seatBids map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid) {
+		for _, seat := range seats {
+			delete(seatBids, seat)
+		}
+	}, MutationDelete, "seatbids")
+}
+
+// UpdateBids replaces the bids of the seat, such as with re-priced copies of them. A seat without bids is
+// dropped from the auction.
+func (c ChangeSetRawBidderResponsePerSeat[T]) UpdateBids(seat openrtb_ext.BidderName, bids []*entities.PbsOrtbBid) {
+	c.mutateSeatBids(func(seatBids map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid) {
+		seatBid, ok := seatBids[seat]
+		if !ok {
+			return
+		}
+		if len(bids) == 0 {
+			delete(seatBids, seat)
+			return
+		}
+		updated := *seatBid
+		updated.Bids = bids
+		seatBids[seat] = &updated
+	}, MutationUpdate, "seatbids", string(seat), "bids")
+}
+
+// mutateSeatBids adds a mutation which changes a copy of the seatbids, so that the seatbids the other hooks
+// of the group were given are left as they were.
+func (c ChangeSetRawBidderResponsePerSeat[T]) mutateSeatBids(mutate func(map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid), t MutationType, key ...string) {
+	c.changeSet.AddMutation(func(p T) (T, error) {
+		perSeatPayload, err := c.castPayload(p)
+		if err != nil {
+			return p, err
+		}
+		seatBids := make(map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid, len(perSeatPayload.SeatBids))
+		for seat, seatBid := range perSeatPayload.SeatBids {
+			seatBids[seat] = seatBid
+		}
+		mutate(seatBids)
+		perSeatPayload.SeatBids = seatBids
+		if payload, ok := any(perSeatPayload).(T); ok {
+			return payload, nil
+		}
+		return p, errors.New("failed to cast RawBidderResponsePerSeatPayload")
+	}, t, key...)
+}
@@ -3,9 +3,9 @@ package hooks
 import (
 	"time"
 
-	"github.com/golang/glog"
 	"github.com/prebid/prebid-server/v2/config"
 	"github.com/prebid/prebid-server/v2/hooks/hookstage"
+	"github.com/prebid/prebid-server/v2/logger"
 )
 
 type Stage string
@@ -17,6 +17,7 @@ const (
 	StageProcessedAuctionRequest  Stage = "processed_auction_request"
 	StageBidderRequest            Stage = "bidder_request"
 	StageRawBidderResponse        Stage = "raw_bidder_response"
+	StageRawBidderResponsePerSeat Stage = "raw_bidder_response_per_seat"
 	StageAllProcessedBidResponses Stage = "all_processed_bid_responses"
 	StageAuctionResponse          Stage = "auction_response"
 )
@@ -39,6 +40,7 @@ type ExecutionPlanBuilder interface {
 	PlanForProcessedAuctionStage(endpoint string, account *config.Account) Plan[hookstage.ProcessedAuctionRequest]
 	PlanForBidderRequestStage(endpoint string, account *config.Account) Plan[hookstage.BidderRequest]
 	PlanForRawBidderResponseStage(endpoint string, account *config.Account) Plan[hookstage.RawBidderResponse]
+	PlanForRawBidderResponsePerSeatStage(endpoint string, account *config.Account) Plan[hookstage.RawBidderResponsePerSeat]
 	PlanForAllProcessedBidResponsesStage(endpoint string, account *config.Account) Plan[hookstage.AllProcessedBidResponses]
 	PlanForAuctionResponseStage(endpoint string, account *config.Account) Plan[hookstage.AuctionResponse]
 }
@@ -65,6 +67,10 @@ type HookWrapper[T any] struct {
 	Code string
 	// Hook is an instance of the specific hook interface.
 	Hook T
+	// Canary, if set, wraps the same hook of the module's canary version,
+	// which is run in its place for CanaryPercent of the requests.
+	Canary        *HookWrapper[T]
+	CanaryPercent float64
 }
 
 // NewExecutionPlanBuilder returns a new instance of the ExecutionPlanBuilder interface.
@@ -136,6 +142,16 @@ func (p PlanBuilder) PlanForRawBidderResponseStage(endpoint string, account *con
 	)
 }
 
+func (p PlanBuilder) PlanForRawBidderResponsePerSeatStage(endpoint string, account *config.Account) Plan[hookstage.RawBidderResponsePerSeat] {
+	return getMergedPlan(
+		p.hooks,
+		account,
+		endpoint,
+		StageRawBidderResponsePerSeat,
+		p.repo.GetRawBidderResponsePerSeatHook,
+	)
+}
+
 func (p PlanBuilder) PlanForAllProcessedBidResponsesStage(endpoint string, account *config.Account) Plan[hookstage.AllProcessedBidResponses] {
 	return getMergedPlan(
 		p.hooks,
@@ -169,17 +185,39 @@ func getMergedPlan[T any](
 	if account != nil && account.Hooks.ExecutionPlan.Endpoints != nil {
 		accountPlan = account.Hooks.ExecutionPlan
 	}
+	canaries := getCanaries(cfg, account)
 
-	plan := getPlan(getHookFn, cfg.HostExecutionPlan, endpoint, stage)
-	plan = append(plan, getPlan(getHookFn, accountPlan, endpoint, stage)...)
+	plan := getPlan(getHookFn, cfg.HostExecutionPlan, endpoint, stage, canaries)
+	plan = append(plan, getPlan(getHookFn, accountPlan, endpoint, stage, canaries)...)
 
 	return plan
 }
 
-func getPlan[T any](getHookFn hookFn[T], cfg config.HookExecutionPlan, endpoint string, stage Stage) Plan[T] {
+// getCanaries returns the canary of each module, by module code. The account's canaries replace the
+// host's for the same modules.
+func getCanaries(cfg config.Hooks, account *config.Account) map[string]config.ModuleCanary {
+	var accountCanaries []config.ModuleCanary
+	if account != nil {
+		accountCanaries = account.Hooks.Canaries
+	}
+	if len(cfg.Canaries) == 0 && len(accountCanaries) == 0 {
+		return nil
+	}
+
+	canaries := make(map[string]config.ModuleCanary, len(cfg.Canaries)+len(accountCanaries))
+	for _, canary := range cfg.Canaries {
+		canaries[canary.ModuleCode] = canary
+	}
+	for _, canary := range accountCanaries {
+		canaries[canary.ModuleCode] = canary
+	}
+	return canaries
+}
+
+func getPlan[T any](getHookFn hookFn[T], cfg config.HookExecutionPlan, endpoint string, stage Stage, canaries map[string]config.ModuleCanary) Plan[T] {
 	plan := make(Plan[T], 0, len(cfg.Endpoints[endpoint].Stages[stage.String()].Groups))
 	for _, groupCfg := range cfg.Endpoints[endpoint].Stages[stage.String()].Groups {
-		group := getGroup(getHookFn, groupCfg)
+		group := getGroup(getHookFn, groupCfg, canaries)
 		if len(group.Hooks) > 0 {
 			plan = append(plan, group)
 		}
@@ -188,7 +226,7 @@ func getPlan[T any](getHookFn hookFn[T], cfg config.HookExecutionPlan, endpoint
 	return plan
 }
 
-func getGroup[T any](getHookFn hookFn[T], cfg config.HookExecutionGroup) Group[T] {
+func getGroup[T any](getHookFn hookFn[T], cfg config.HookExecutionGroup, canaries map[string]config.ModuleCanary) Group[T] {
 	group := Group[T]{
 		Timeout: time.Duration(cfg.Timeout) * time.Millisecond,
 		Hooks:   make([]HookWrapper[T], 0, len(cfg.HookSequence)),
@@ -196,9 +234,18 @@ func getGroup[T any](getHookFn hookFn[T], cfg config.HookExecutionGroup) Group[T
 
 	for _, hookCfg := range cfg.HookSequence {
 		if h, ok := getHookFn(hookCfg.ModuleCode); ok {
-			group.Hooks = append(group.Hooks, HookWrapper[T]{Module: hookCfg.ModuleCode, Code: hookCfg.HookImplCode, Hook: h})
+			hook := HookWrapper[T]{Module: hookCfg.ModuleCode, Code: hookCfg.HookImplCode, Hook: h}
+			if canary, ok := canaries[hookCfg.ModuleCode]; ok && canary.Percent > 0 {
+				if ch, ok := getHookFn(canary.CanaryModuleCode); ok {
+					hook.Canary = &HookWrapper[T]{Module: canary.CanaryModuleCode, Code: hookCfg.HookImplCode, Hook: ch}
+					hook.CanaryPercent = canary.Percent
+				} else {
+					logger.Warningf("Not found canary hook while building hook execution plan: %s %s", canary.CanaryModuleCode, hookCfg.HookImplCode)
+				}
+			}
+			group.Hooks = append(group.Hooks, hook)
 		} else {
-			glog.Warningf("Not found hook while building hook execution plan: %s %s", hookCfg.ModuleCode, hookCfg.HookImplCode)
+			logger.Warningf("Not found hook while building hook execution plan: %s %s", hookCfg.ModuleCode, hookCfg.HookImplCode)
 		}
 	}
 
@@ -558,6 +558,112 @@ func TestPlanForRawBidderResponseStage(t *testing.T) {
 	}
 }
 
+func TestPlanForRawBidderResponsePerSeatStage(t *testing.T) {
+	const group1 string = `{"timeout":  5, "hook_sequence": [{"module_code": "foobar", "hook_impl_code": "foo"}]}`
+	const group2 string = `{"timeout": 10, "hook_sequence": [{"module_code": "foobar", "hook_impl_code": "bar"}, {"module_code": "ortb2blocking", "hook_impl_code": "block_request"}]}`
+	const group3 string = `{"timeout": 15, "hook_sequence": [{"module_code": "prebid", "hook_impl_code": "baz"}]}`
+	const hostPlanData string = `{"endpoints": {"/openrtb2/auction": {"stages": {"raw_bidder_response_per_seat": {"groups": [` + group1 + `]}}}}}`
+	const defaultAccountPlanData string = `{"endpoints": {"/openrtb2/auction": {"stages": {"raw_bidder_response_per_seat": {"groups": [` + group2 + `,` + group1 + `]}}}, "/openrtb2/amp": {"stages": {"entrypoint": {"groups": [` + group1 + `]}}}}}`
+	const accountPlanData string = `{"execution_plan": {"endpoints": {"/openrtb2/auction": {"stages": {"raw_bidder_response_per_seat": {"groups": [` + group3 + `]}}}}}}`
+
+	hooks := map[string]interface{}{
+		"foobar":        fakeRawBidderResponsePerSeatHook{},
+		"ortb2blocking": fakeRawBidderResponsePerSeatHook{},
+		"prebid":        fakeRawBidderResponsePerSeatHook{},
+	}
+
+	testCases := map[string]struct {
+		givenEndpoint               string
+		givenHostPlanData           []byte
+		givenDefaultAccountPlanData []byte
+		giveAccountPlanData         []byte
+		givenHooks                  map[string]interface{}
+		expectedPlan                Plan[hookstage.RawBidderResponsePerSeat]
+	}{
+		"Account-specific execution plan rewrites default-account execution plan": {
+			givenEndpoint:               "/openrtb2/auction",
+			givenHostPlanData:           []byte(hostPlanData),
+			givenDefaultAccountPlanData: []byte(defaultAccountPlanData),
+			giveAccountPlanData:         []byte(accountPlanData),
+			givenHooks:                  hooks,
+			expectedPlan: Plan[hookstage.RawBidderResponsePerSeat]{
+				// first group from host-level plan
+				Group[hookstage.RawBidderResponsePerSeat]{
+					Timeout: 5 * time.Millisecond,
+					Hooks: []HookWrapper[hookstage.RawBidderResponsePerSeat]{
+						{Module: "foobar", Code: "foo", Hook: fakeRawBidderResponsePerSeatHook{}},
+					},
+				},
+				// then come groups from account-level plan (default-account-level plan ignored)
+				Group[hookstage.RawBidderResponsePerSeat]{
+					Timeout: 15 * time.Millisecond,
+					Hooks: []HookWrapper[hookstage.RawBidderResponsePerSeat]{
+						{Module: "prebid", Code: "baz", Hook: fakeRawBidderResponsePerSeatHook{}},
+					},
+				},
+			},
+		},
+		"Works with only account-specific plan": {
+			givenEndpoint:               "/openrtb2/auction",
+			givenHostPlanData:           []byte(`{}`),
+			givenDefaultAccountPlanData: []byte(`{}`),
+			giveAccountPlanData:         []byte(accountPlanData),
+			givenHooks:                  hooks,
+			expectedPlan: Plan[hookstage.RawBidderResponsePerSeat]{
+				Group[hookstage.RawBidderResponsePerSeat]{
+					Timeout: 15 * time.Millisecond,
+					Hooks: []HookWrapper[hookstage.RawBidderResponsePerSeat]{
+						{Module: "prebid", Code: "baz", Hook: fakeRawBidderResponsePerSeatHook{}},
+					},
+				},
+			},
+		},
+		"Works with empty account-specific execution plan": {
+			givenEndpoint:               "/openrtb2/auction",
+			givenHostPlanData:           []byte(hostPlanData),
+			givenDefaultAccountPlanData: []byte(defaultAccountPlanData),
+			giveAccountPlanData:         []byte(`{}`),
+			givenHooks:                  hooks,
+			expectedPlan: Plan[hookstage.RawBidderResponsePerSeat]{
+				Group[hookstage.RawBidderResponsePerSeat]{
+					Timeout: 5 * time.Millisecond,
+					Hooks: []HookWrapper[hookstage.RawBidderResponsePerSeat]{
+						{Module: "foobar", Code: "foo", Hook: fakeRawBidderResponsePerSeatHook{}},
+					},
+				},
+				Group[hookstage.RawBidderResponsePerSeat]{
+					Timeout: 10 * time.Millisecond,
+					Hooks: []HookWrapper[hookstage.RawBidderResponsePerSeat]{
+						{Module: "foobar", Code: "bar", Hook: fakeRawBidderResponsePerSeatHook{}},
+						{Module: "ortb2blocking", Code: "block_request", Hook: fakeRawBidderResponsePerSeatHook{}},
+					},
+				},
+				Group[hookstage.RawBidderResponsePerSeat]{
+					Timeout: 5 * time.Millisecond,
+					Hooks: []HookWrapper[hookstage.RawBidderResponsePerSeat]{
+						{Module: "foobar", Code: "foo", Hook: fakeRawBidderResponsePerSeatHook{}},
+					},
+				},
+			},
+		},
+	}
+
+	for name, test := range testCases {
+		t.Run(name, func(t *testing.T) {
+			account := new(config.Account)
+			if err := jsonutil.UnmarshalValid(test.giveAccountPlanData, &account.Hooks); err != nil {
+				t.Fatal(err)
+			}
+
+			planBuilder, err := getPlanBuilder(test.givenHooks, test.givenHostPlanData, test.givenDefaultAccountPlanData)
+			if assert.NoError(t, err, "Failed to init hook execution plan builder") {
+				plan := planBuilder.PlanForRawBidderResponsePerSeatStage(test.givenEndpoint, account)
+				assert.Equal(t, test.expectedPlan, plan)
+			}
+		})
+	}
+}
+
 func TestPlanForAllProcessedBidResponsesStage(t *testing.T) {
 	const group1 string = `{"timeout":  5, "hook_sequence": [{"module_code": "foobar", "hook_impl_code": "foo"}]}`
 	const group2 string = `{"timeout": 10, "hook_sequence": [{"module_code": "foobar", "hook_impl_code": "bar"}, {"module_code": "ortb2blocking", "hook_impl_code": "block_request"}]}`
@@ -770,6 +876,83 @@ func TestPlanForAuctionResponseStage(t *testing.T) {
 	}
 }
 
+func TestPlanCanaries(t *testing.T) {
+	const planData string = `{"endpoints": {"/openrtb2/auction": {"stages": {"raw_auction_request": {"groups": [{"timeout": 5, "hook_sequence": [{"module_code": "foobar", "hook_impl_code": "foo"}, {"module_code": "prebid", "hook_impl_code": "baz"}]}]}}}}}`
+
+	hooks := map[string]interface{}{
+		"foobar":    fakeRawAuctionHook{},
+		"foobar_v2": fakeRawAuctionHook{},
+		"foobar_v3": fakeRawAuctionHook{},
+		"prebid":    fakeRawAuctionHook{},
+	}
+	foobarV2 := &HookWrapper[hookstage.RawAuctionRequest]{Module: "foobar_v2", Code: "foo", Hook: fakeRawAuctionHook{}}
+	foobarV3 := &HookWrapper[hookstage.RawAuctionRequest]{Module: "foobar_v3", Code: "foo", Hook: fakeRawAuctionHook{}}
+
+	testCases := map[string]struct {
+		givenHostCanaries    []config.ModuleCanary
+		givenAccountCanaries []config.ModuleCanary
+		expectedHooks        []HookWrapper[hookstage.RawAuctionRequest]
+	}{
+		"No canaries": {
+			expectedHooks: []HookWrapper[hookstage.RawAuctionRequest]{
+				{Module: "foobar", Code: "foo", Hook: fakeRawAuctionHook{}},
+				{Module: "prebid", Code: "baz", Hook: fakeRawAuctionHook{}},
+			},
+		},
+		"Host canary": {
+			givenHostCanaries: []config.ModuleCanary{{ModuleCode: "foobar", CanaryModuleCode: "foobar_v2", Percent: 10}},
+			expectedHooks: []HookWrapper[hookstage.RawAuctionRequest]{
+				{Module: "foobar", Code: "foo", Hook: fakeRawAuctionHook{}, Canary: foobarV2, CanaryPercent: 10},
+				{Module: "prebid", Code: "baz", Hook: fakeRawAuctionHook{}},
+			},
+		},
+		"Account canary replaces host canary": {
+			givenHostCanaries:    []config.ModuleCanary{{ModuleCode: "foobar", CanaryModuleCode: "foobar_v2", Percent: 10}},
+			givenAccountCanaries: []config.ModuleCanary{{ModuleCode: "foobar", CanaryModuleCode: "foobar_v3", Percent: 50}},
+			expectedHooks: []HookWrapper[hookstage.RawAuctionRequest]{
+				{Module: "foobar", Code: "foo", Hook: fakeRawAuctionHook{}, Canary: foobarV3, CanaryPercent: 50},
+				{Module: "prebid", Code: "baz", Hook: fakeRawAuctionHook{}},
+			},
+		},
+		"Account turns host canary off": {
+			givenHostCanaries:    []config.ModuleCanary{{ModuleCode: "foobar", CanaryModuleCode: "foobar_v2", Percent: 10}},
+			givenAccountCanaries: []config.ModuleCanary{{ModuleCode: "foobar", CanaryModuleCode: "foobar_v2", Percent: 0}},
+			expectedHooks: []HookWrapper[hookstage.RawAuctionRequest]{
+				{Module: "foobar", Code: "foo", Hook: fakeRawAuctionHook{}},
+				{Module: "prebid", Code: "baz", Hook: fakeRawAuctionHook{}},
+			},
+		},
+		"Canary module not found": {
+			givenHostCanaries: []config.ModuleCanary{{ModuleCode: "foobar", CanaryModuleCode: "unknown", Percent: 10}},
+			expectedHooks: []HookWrapper[hookstage.RawAuctionRequest]{
+				{Module: "foobar", Code: "foo", Hook: fakeRawAuctionHook{}},
+				{Module: "prebid", Code: "baz", Hook: fakeRawAuctionHook{}},
+			},
+		},
+	}
+
+	for name, test := range testCases {
+		t.Run(name, func(t *testing.T) {
+			var hostPlan config.HookExecutionPlan
+			if err := jsonutil.UnmarshalValid([]byte(planData), &hostPlan); err != nil {
+				t.Fatal(err)
+			}
+			repo, err := NewHookRepository(hooks)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			planBuilder := NewExecutionPlanBuilder(config.Hooks{Enabled: true, HostExecutionPlan: hostPlan, Canaries: test.givenHostCanaries}, repo)
+			account := &config.Account{Hooks: config.AccountHooks{Canaries: test.givenAccountCanaries}}
+
+			plan := planBuilder.PlanForRawAuctionStage("/openrtb2/auction", account)
+			if assert.Len(t, plan, 1) {
+				assert.Equal(t, test.expectedHooks, plan[0].Hooks)
+			}
+		})
+	}
+}
+
 func getPlanBuilder(
 	moduleHooks map[string]interface{},
 	hostPlanData, accountPlanData []byte,
@@ -851,6 +1034,16 @@ func (f fakeRawBidderResponseHook) HandleRawBidderResponseHook(
 	return hookstage.HookResult[hookstage.RawBidderResponsePayload]{}, nil
 }
 
+type fakeRawBidderResponsePerSeatHook struct{}
+
+func (f fakeRawBidderResponsePerSeatHook) HandleRawBidderResponsePerSeatHook(
+	_ context.Context,
+	_ hookstage.ModuleInvocationContext,
+	_ hookstage.RawBidderResponsePerSeatPayload,
+) (hookstage.HookResult[hookstage.RawBidderResponsePerSeatPayload], error) {
+	return hookstage.HookResult[hookstage.RawBidderResponsePerSeatPayload]{}, nil
+}
+
 type fakeAllProcessedBidResponsesHook struct{}
 
 func (f fakeAllProcessedBidResponsesHook) HandleAllProcessedBidResponsesHook(
@@ -19,6 +19,7 @@ type HookRepository interface {
 	GetProcessedAuctionHook(id string) (hookstage.ProcessedAuctionRequest, bool)
 	GetBidderRequestHook(id string) (hookstage.BidderRequest, bool)
 	GetRawBidderResponseHook(id string) (hookstage.RawBidderResponse, bool)
+	GetRawBidderResponsePerSeatHook(id string) (hookstage.RawBidderResponsePerSeat, bool)
 	GetAllProcessedBidResponsesHook(id string) (hookstage.AllProcessedBidResponses, bool)
 	GetAuctionResponseHook(id string) (hookstage.AuctionResponse, bool)
 }
@@ -42,13 +43,14 @@ func NewHookRepository(hooks map[string]interface{}) (HookRepository, error) {
 }
 
 type hookRepository struct {
-	entrypointHooks              map[string]hookstage.Entrypoint
-	rawAuctionHooks              map[string]hookstage.RawAuctionRequest
-	processedAuctionHooks        map[string]hookstage.ProcessedAuctionRequest
-	bidderRequestHooks           map[string]hookstage.BidderRequest
-	rawBidderResponseHooks       map[string]hookstage.RawBidderResponse
-	allProcessedBidResponseHooks map[string]hookstage.AllProcessedBidResponses
-	auctionResponseHooks         map[string]hookstage.AuctionResponse
+	entrypointHooks               map[string]hookstage.Entrypoint
+	rawAuctionHooks               map[string]hookstage.RawAuctionRequest
+	processedAuctionHooks         map[string]hookstage.ProcessedAuctionRequest
+	bidderRequestHooks            map[string]hookstage.BidderRequest
+	rawBidderResponseHooks        map[string]hookstage.RawBidderResponse
+	rawBidderResponsePerSeatHooks map[string]hookstage.RawBidderResponsePerSeat
+	allProcessedBidResponseHooks  map[string]hookstage.AllProcessedBidResponses
+	auctionResponseHooks          map[string]hookstage.AuctionResponse
 }
 
 func (r *hookRepository) GetEntrypointHook(id string) (hookstage.Entrypoint, bool) {
@@ -71,6 +73,10 @@ func (r *hookRepository) GetRawBidderResponseHook(id string) (hookstage.RawBidde
 	return getHook(r.rawBidderResponseHooks, id)
 }
 
+func (r *hookRepository) GetRawBidderResponsePerSeatHook(id string) (hookstage.RawBidderResponsePerSeat, bool) {
+	return getHook(r.rawBidderResponsePerSeatHooks, id)
+}
+
 func (r *hookRepository) GetAllProcessedBidResponsesHook(id string) (hookstage.AllProcessedBidResponses, bool) {
 	return getHook(r.allProcessedBidResponseHooks, id)
 }
@@ -118,6 +124,13 @@ func (r *hookRepository) add(id string, hook interface{}) error {
 		}
 	}
 
+	if h, ok := hook.(hookstage.RawBidderResponsePerSeat); ok {
+		hasAnyHooks = true
+		if r.rawBidderResponsePerSeatHooks, err = addHook(r.rawBidderResponsePerSeatHooks, h, id); err != nil {
+			return err
+		}
+	}
+
 	if h, ok := hook.(hookstage.AllProcessedBidResponses); ok {
 		hasAnyHooks = true
 		if r.allProcessedBidResponseHooks, err = addHook(r.allProcessedBidResponseHooks, h, id); err != nil {
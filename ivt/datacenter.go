@@ -0,0 +1,85 @@
+package ivt
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/logger"
+	"github.com/prebid/prebid-server/v2/util/task"
+)
+
+// datacenterFetchTimeout bounds the download of ivt.datacenter_ips_url.
+const datacenterFetchTimeout = 30 * time.Second
+
+// NewReloadTask returns a task which reloads the filter's datacenter ranges from ivt.datacenter_ips_file and
+// ivt.datacenter_ips_url, or nil if there's nothing to reload. The ranges at the URL are first loaded when the
+// task starts, and the task only reloads them once if ivt.datacenter_ips_refresh_minutes is 0.
+func NewReloadTask(filter *Filter, cfg config.IVT, client *http.Client) *task.TickerTask {
+	if filter == nil || (cfg.DatacenterIPsURL == "" && (cfg.DatacenterIPsFile == "" || cfg.DatacenterIPsRefreshMinutes <= 0)) {
+		return nil
+	}
+	reloader := &datacenterReloader{
+		filter: filter,
+		file:   cfg.DatacenterIPsFile,
+		url:    cfg.DatacenterIPsURL,
+		client: client,
+	}
+	return task.NewTickerTask(time.Duration(cfg.DatacenterIPsRefreshMinutes)*time.Minute, reloader)
+}
+
+// datacenterReloader reloads the datacenter ranges of a filter.
+type datacenterReloader struct {
+	filter *Filter
+	file   string
+	url    string
+	client *http.Client
+}
+
+// Run implements task.Runner. If either list can't be loaded, the ranges already loaded are kept.
+func (r *datacenterReloader) Run() error {
+	lines, err := loadLines(r.file)
+	if err != nil {
+		logger.Errorf("Failed to reload ivt.datacenter_ips_file: %v", err)
+		return err
+	}
+	if r.url != "" {
+		fetched, err := r.fetch()
+		if err != nil {
+			logger.Errorf("Failed to reload ivt.datacenter_ips_url: %v", err)
+			return err
+		}
+		lines = append(lines, fetched...)
+	}
+
+	ranges, err := newIPRanges(lines)
+	if err != nil {
+		logger.Errorf("Failed to reload the datacenter IP ranges: %v", err)
+		return err
+	}
+	r.filter.datacenterIPs.Store(ranges)
+	logger.Infof("Reloaded %d datacenter IP ranges", len(ranges.ranges))
+	return nil
+}
+
+func (r *datacenterReloader) fetch() ([]string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), datacenterFetchTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, r.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		io.Copy(io.Discard, resp.Body)
+		return nil, fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+	return readLines(resp.Body)
+}
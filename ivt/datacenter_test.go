@@ -0,0 +1,97 @@
+package ivt
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewReloadTask(t *testing.T) {
+	filter, err := NewFilter(config.IVT{Enabled: true})
+	require.NoError(t, err)
+
+	testCases := []struct {
+		description  string
+		filter       *Filter
+		cfg          config.IVT
+		expectedTask bool
+	}{
+		{
+			description: "screening off",
+			cfg:         config.IVT{DatacenterIPsURL: "https://lists.prebid.org/datacenters.csv"},
+		},
+		{
+			description: "no datacenter lists",
+			filter:      filter,
+			cfg:         config.IVT{DatacenterIPsRefreshMinutes: 60},
+		},
+		{
+			description: "file loaded once",
+			filter:      filter,
+			cfg:         config.IVT{DatacenterIPsFile: "/etc/pbs/datacenters.csv"},
+		},
+		{
+			description:  "file refreshed",
+			filter:       filter,
+			cfg:          config.IVT{DatacenterIPsFile: "/etc/pbs/datacenters.csv", DatacenterIPsRefreshMinutes: 60},
+			expectedTask: true,
+		},
+		{
+			description:  "url loaded once",
+			filter:       filter,
+			cfg:          config.IVT{DatacenterIPsURL: "https://lists.prebid.org/datacenters.csv"},
+			expectedTask: true,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			task := NewReloadTask(test.filter, test.cfg, http.DefaultClient)
+			assert.Equal(t, test.expectedTask, task != nil)
+		})
+	}
+}
+
+func TestDatacenterReloaderRun(t *testing.T) {
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+		w.Write([]byte("# cidr,provider\n192.0.2.0/24,url-cloud\n"))
+	}))
+	defer server.Close()
+
+	file := writeFile(t, "198.51.100.0/24,file-cloud\n")
+	filter, err := NewFilter(config.IVT{Enabled: true, DatacenterIPsFile: file})
+	require.NoError(t, err)
+	reloader := &datacenterReloader{filter: filter, file: file, url: server.URL, client: server.Client()}
+
+	fromFile := &openrtb2.Device{IP: "198.51.100.20"}
+	fromURL := &openrtb2.Device{IP: "192.0.2.20"}
+	assert.Equal(t, []metrics.IVTReason{metrics.IVTDatacenterIP}, filter.Check(fromFile))
+	assert.Empty(t, filter.Check(fromURL), "the URL is only loaded by the task")
+
+	require.NoError(t, reloader.Run())
+	assert.Equal(t, "file-cloud", filter.Datacenter(fromFile))
+	assert.Equal(t, "url-cloud", filter.Datacenter(fromURL))
+
+	require.NoError(t, os.WriteFile(file, []byte("203.0.113.0/24,new-cloud\n"), 0600))
+	require.NoError(t, reloader.Run())
+	assert.Empty(t, filter.Check(fromFile), "ranges removed from the file are dropped")
+	assert.Equal(t, "new-cloud", filter.Datacenter(&openrtb2.Device{IP: "203.0.113.20"}))
+
+	status = http.StatusInternalServerError
+	assert.EqualError(t, reloader.Run(), "unexpected status code 500")
+	assert.Equal(t, "url-cloud", filter.Datacenter(fromURL), "the ranges already loaded are kept when reloading fails")
+
+	status = http.StatusOK
+	require.NoError(t, os.WriteFile(file, []byte("198.51.100.0/33\n"), 0600))
+	assert.Error(t, reloader.Run())
+	assert.Equal(t, "new-cloud", filter.Datacenter(&openrtb2.Device{IP: "203.0.113.20"}))
+}
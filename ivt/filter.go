@@ -0,0 +1,146 @@
+// Package ivt screens requests for basic invalid traffic (IVT) before they're auctioned: devices whose user
+// agent is a known spider or bot, requests from blocked or datacenter IP addresses, and blocked advertising IDs.
+// The datacenter ranges may be reloaded periodically, since hosting providers add ranges all the time.
+package ivt
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strings"
+	"sync/atomic"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/metrics"
+)
+
+// Filter finds the reasons a request's device is invalid traffic.
+type Filter struct {
+	bots          *botList
+	blockedIPs    *ipRanges
+	datacenterIPs atomic.Pointer[ipRanges]
+	blockedIFAs   map[string]struct{}
+}
+
+// NewFilter loads the lists the filter checks devices against. It returns nil if screening is off.
+func NewFilter(cfg config.IVT) (*Filter, error) {
+	if !cfg.Enabled {
+		return nil, nil
+	}
+
+	bots, err := loadBotList(cfg.Bots)
+	if err != nil {
+		return nil, err
+	}
+
+	blockedIPs, err := loadLines(cfg.BlockedIPsFile)
+	if err != nil {
+		return nil, fmt.Errorf("ivt.blocked_ips_file: %v", err)
+	}
+	blockedIPRanges, err := newIPRanges(append(append([]string{}, cfg.BlockedIPs...), blockedIPs...))
+	if err != nil {
+		return nil, fmt.Errorf("ivt.blocked_ips: %v", err)
+	}
+
+	datacenterIPs, err := loadLines(cfg.DatacenterIPsFile)
+	if err != nil {
+		return nil, fmt.Errorf("ivt.datacenter_ips_file: %v", err)
+	}
+	datacenterIPRanges, err := newIPRanges(datacenterIPs)
+	if err != nil {
+		return nil, fmt.Errorf("ivt.datacenter_ips_file: %v", err)
+	}
+
+	blockedIFAs, err := loadLines(cfg.BlockedIFAsFile)
+	if err != nil {
+		return nil, fmt.Errorf("ivt.blocked_ifas_file: %v", err)
+	}
+	ifas := make(map[string]struct{}, len(cfg.BlockedIFAs)+len(blockedIFAs))
+	for _, ifa := range append(append([]string{}, cfg.BlockedIFAs...), blockedIFAs...) {
+		ifas[strings.ToLower(ifa)] = struct{}{}
+	}
+
+	filter := &Filter{
+		bots:        bots,
+		blockedIPs:  blockedIPRanges,
+		blockedIFAs: ifas,
+	}
+	filter.datacenterIPs.Store(datacenterIPRanges)
+	return filter, nil
+}
+
+// Check returns the reasons the device is invalid traffic, or none if it isn't.
+func (f *Filter) Check(device *openrtb2.Device) []metrics.IVTReason {
+	if f == nil || device == nil {
+		return nil
+	}
+
+	var reasons []metrics.IVTReason
+	if device.UA != "" && f.bots.matches(device.UA) {
+		reasons = append(reasons, metrics.IVTBotUserAgent)
+	}
+
+	ips := deviceIPs(device)
+	if f.blockedIPs.containsAny(ips) {
+		reasons = append(reasons, metrics.IVTBlockedIP)
+	}
+	if f.datacenterIPs.Load().containsAny(ips) {
+		reasons = append(reasons, metrics.IVTDatacenterIP)
+	}
+
+	if device.IFA != "" {
+		if _, blocked := f.blockedIFAs[strings.ToLower(device.IFA)]; blocked {
+			reasons = append(reasons, metrics.IVTBlockedIFA)
+		}
+	}
+	return reasons
+}
+
+// Datacenter returns the hosting provider of the datacenter the device's IP address belongs to, if the
+// datacenter list names it.
+func (f *Filter) Datacenter(device *openrtb2.Device) string {
+	if f == nil || device == nil {
+		return ""
+	}
+	found, _ := f.datacenterIPs.Load().findAny(deviceIPs(device))
+	return found.name
+}
+
+func deviceIPs(device *openrtb2.Device) []net.IP {
+	ips := make([]net.IP, 0, 2)
+	for _, address := range []string{device.IP, device.IPv6} {
+		if ip := net.ParseIP(address); ip != nil {
+			ips = append(ips, ip)
+		}
+	}
+	return ips
+}
+
+// loadLines returns the lines of the file which aren't empty or comments, or none if the path is empty.
+func loadLines(path string) ([]string, error) {
+	if path == "" {
+		return nil, nil
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return readLines(file)
+}
+
+func readLines(r io.Reader) ([]string, error) {
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return lines, scanner.Err()
+}
@@ -0,0 +1,173 @@
+package ivt
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const browserUA = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36"
+
+func writeFile(t *testing.T, contents string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "list.txt")
+	require.NoError(t, os.WriteFile(path, []byte(contents), 0600))
+	return path
+}
+
+func TestNewFilterDisabled(t *testing.T) {
+	filter, err := NewFilter(config.IVT{Enabled: false, BlockedIPs: []string{"invalid"}})
+	assert.NoError(t, err)
+	assert.Nil(t, filter)
+	assert.Empty(t, filter.Check(&openrtb2.Device{UA: "Googlebot/2.1"}))
+}
+
+func TestNewFilterErrors(t *testing.T) {
+	testCases := []struct {
+		description string
+		cfg         config.IVT
+		expectedErr string
+	}{
+		{
+			description: "missing bots list",
+			cfg:         config.IVT{Enabled: true, Bots: config.IVTBots{ListFile: "/nonexistent/bots.txt"}},
+			expectedErr: "ivt.bots.list_file: open /nonexistent/bots.txt: no such file or directory",
+		},
+		{
+			description: "invalid blocked IP",
+			cfg:         config.IVT{Enabled: true, BlockedIPs: []string{"203.0.113"}},
+			expectedErr: "ivt.blocked_ips: invalid IP address or CIDR block: 203.0.113",
+		},
+		{
+			description: "invalid datacenter block",
+			cfg:         config.IVT{Enabled: true, DatacenterIPsFile: writeFile(t, "198.51.100.0/33\n")},
+			expectedErr: "ivt.datacenter_ips_file: invalid IP address or CIDR block: 198.51.100.0/33",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			_, err := NewFilter(test.cfg)
+			assert.EqualError(t, err, test.expectedErr)
+		})
+	}
+}
+
+func TestCheck(t *testing.T) {
+	filter, err := NewFilter(config.IVT{
+		Enabled: true,
+		Bots: config.IVTBots{
+			Builtin:     true,
+			ListFile:    writeFile(t, "# pattern|active|start\nexamplebot|1|0\nretired-bot|0|0\nJava/|1|1\n"),
+			ExcludeFile: writeFile(t, "googlebot-image-preview|1\n"),
+		},
+		BlockedIPs:        []string{"203.0.113.7", "2001:db8::/32"},
+		BlockedIPsFile:    writeFile(t, "192.0.2.0/24\n"),
+		DatacenterIPsFile: writeFile(t, "# cidr,provider\n198.51.100.0/24,example-cloud\n"),
+		BlockedIFAs:       []string{"AAAAAAAA-1111-2222-3333-444444444444"},
+	})
+	require.NoError(t, err)
+
+	testCases := []struct {
+		description string
+		device      *openrtb2.Device
+		expected    []metrics.IVTReason
+	}{
+		{
+			description: "nil device",
+			device:      nil,
+		},
+		{
+			description: "valid traffic",
+			device:      &openrtb2.Device{UA: browserUA, IP: "100.64.1.1", IFA: "bbbbbbbb-1111-2222-3333-444444444444"},
+		},
+		{
+			description: "builtin bot",
+			device:      &openrtb2.Device{UA: "Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)"},
+			expected:    []metrics.IVTReason{metrics.IVTBotUserAgent},
+		},
+		{
+			description: "bot from list",
+			device:      &openrtb2.Device{UA: "ExampleBot/1.0"},
+			expected:    []metrics.IVTReason{metrics.IVTBotUserAgent},
+		},
+		{
+			description: "inactive bot from list",
+			device:      &openrtb2.Device{UA: "retired-bot/1.0"},
+		},
+		{
+			description: "bot matched at start",
+			device:      &openrtb2.Device{UA: "Java/1.8.0_151"},
+			expected:    []metrics.IVTReason{metrics.IVTBotUserAgent},
+		},
+		{
+			description: "bot pattern not at start",
+			device:      &openrtb2.Device{UA: "Mozilla/5.0 Java/1.8.0_151"},
+		},
+		{
+			description: "excluded bot",
+			device:      &openrtb2.Device{UA: "Googlebot-Image-Preview/1.0"},
+		},
+		{
+			description: "blocked IP",
+			device:      &openrtb2.Device{UA: browserUA, IP: "203.0.113.7"},
+			expected:    []metrics.IVTReason{metrics.IVTBlockedIP},
+		},
+		{
+			description: "blocked IP from file",
+			device:      &openrtb2.Device{UA: browserUA, IP: "192.0.2.200"},
+			expected:    []metrics.IVTReason{metrics.IVTBlockedIP},
+		},
+		{
+			description: "blocked IPv6",
+			device:      &openrtb2.Device{UA: browserUA, IPv6: "2001:db8:1::1"},
+			expected:    []metrics.IVTReason{metrics.IVTBlockedIP},
+		},
+		{
+			description: "datacenter IP",
+			device:      &openrtb2.Device{UA: browserUA, IP: "198.51.100.20"},
+			expected:    []metrics.IVTReason{metrics.IVTDatacenterIP},
+		},
+		{
+			description: "blocked IFA",
+			device:      &openrtb2.Device{UA: browserUA, IFA: "aaaaaaaa-1111-2222-3333-444444444444"},
+			expected:    []metrics.IVTReason{metrics.IVTBlockedIFA},
+		},
+		{
+			description: "several reasons",
+			device:      &openrtb2.Device{UA: "HeadlessChrome/120.0", IP: "198.51.100.20", IPv6: "2001:db8::1"},
+			expected:    []metrics.IVTReason{metrics.IVTBotUserAgent, metrics.IVTBlockedIP, metrics.IVTDatacenterIP},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			assert.Equal(t, test.expected, filter.Check(test.device))
+		})
+	}
+}
+
+func TestDatacenter(t *testing.T) {
+	filter, err := NewFilter(config.IVT{Enabled: true, DatacenterIPsFile: writeFile(t, "198.51.100.0/24,example-cloud\n203.0.113.0/24\n")})
+	require.NoError(t, err)
+
+	assert.Equal(t, "example-cloud", filter.Datacenter(&openrtb2.Device{IP: "198.51.100.20"}))
+	assert.Equal(t, "", filter.Datacenter(&openrtb2.Device{IP: "203.0.113.20"}), "the list doesn't name the provider")
+	assert.Equal(t, "", filter.Datacenter(&openrtb2.Device{IP: "192.0.2.1"}))
+	assert.Equal(t, "", filter.Datacenter(nil))
+
+	var nilFilter *Filter
+	assert.Equal(t, "", nilFilter.Datacenter(&openrtb2.Device{IP: "198.51.100.20"}))
+}
+
+func TestCheckWithoutBuiltinBots(t *testing.T) {
+	filter, err := NewFilter(config.IVT{Enabled: true})
+	require.NoError(t, err)
+	assert.Empty(t, filter.Check(&openrtb2.Device{UA: "Googlebot/2.1"}))
+}
@@ -0,0 +1,204 @@
+package ivt
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+
+	"github.com/prebid/prebid-server/v2/config"
+)
+
+// builtinBots are user agent fragments of well known crawlers and headless browsers. They're specific enough
+// not to match the user agents of real browsers and devices. HTTP libraries are left out, since device.ua is
+// taken from the User-Agent header of server to server requests which don't set it.
+var builtinBots = []string{
+	"googlebot",
+	"adsbot-google",
+	"mediapartners-google",
+	"bingbot",
+	"bingpreview",
+	"yandexbot",
+	"baiduspider",
+	"duckduckbot",
+	"slurp",
+	"applebot",
+	"ahrefsbot",
+	"semrushbot",
+	"mj12bot",
+	"dotbot",
+	"petalbot",
+	"facebookexternalhit",
+	"headlesschrome",
+	"phantomjs",
+	"scrapy",
+}
+
+// botPattern is a fragment of the user agents of a spider or bot, which may have to be at the start.
+type botPattern struct {
+	pattern string
+	atStart bool
+}
+
+func (p botPattern) matches(ua string) bool {
+	if p.atStart {
+		return strings.HasPrefix(ua, p.pattern)
+	}
+	return strings.Contains(ua, p.pattern)
+}
+
+// botList matches user agents case-insensitively against the patterns of spiders and bots, except for those
+// which also match an exclusion.
+type botList struct {
+	include []botPattern
+	exclude []botPattern
+}
+
+func loadBotList(cfg config.IVTBots) (*botList, error) {
+	list := &botList{}
+	if cfg.Builtin {
+		for _, pattern := range builtinBots {
+			list.include = append(list.include, botPattern{pattern: pattern})
+		}
+	}
+
+	include, err := loadLines(cfg.ListFile)
+	if err != nil {
+		return nil, fmt.Errorf("ivt.bots.list_file: %v", err)
+	}
+	list.include = append(list.include, parseBotPatterns(include)...)
+
+	exclude, err := loadLines(cfg.ExcludeFile)
+	if err != nil {
+		return nil, fmt.Errorf("ivt.bots.exclude_file: %v", err)
+	}
+	list.exclude = parseBotPatterns(exclude)
+	return list, nil
+}
+
+// parseBotPatterns parses lines of the IAB/ABC International Spiders and Bots List: a pattern, then an active
+// flag and a flag to match only at the start of the user agent, separated by |. Inactive patterns are skipped.
+// The flags are optional, so a plain list of patterns may be given as well.
+func parseBotPatterns(lines []string) []botPattern {
+	patterns := make([]botPattern, 0, len(lines))
+	for _, line := range lines {
+		fields := strings.Split(line, "|")
+		pattern := strings.ToLower(strings.TrimSpace(fields[0]))
+		if pattern == "" {
+			continue
+		}
+		if len(fields) > 1 && strings.TrimSpace(fields[1]) == "0" {
+			continue
+		}
+		atStart := len(fields) > 2 && strings.TrimSpace(fields[2]) == "1"
+		patterns = append(patterns, botPattern{pattern: pattern, atStart: atStart})
+	}
+	return patterns
+}
+
+func (l *botList) matches(ua string) bool {
+	ua = strings.ToLower(ua)
+	for _, include := range l.include {
+		if include.matches(ua) {
+			for _, exclude := range l.exclude {
+				if exclude.matches(ua) {
+					return false
+				}
+			}
+			return true
+		}
+	}
+	return false
+}
+
+// ipRange is an inclusive range of IPv6 addresses. IPv4 addresses are held in their IPv4-mapped form.
+type ipRange struct {
+	first net.IP
+	last  net.IP
+	// name is who the range belongs to, such as the hosting provider of a datacenter range, if the list says
+	name string
+}
+
+// ipRanges finds addresses in a sorted list of ranges, which may be long enough that checking each of them
+// would be slow.
+type ipRanges struct {
+	ranges []ipRange
+}
+
+// newIPRanges parses IP addresses and CIDR blocks, and merges those which overlap. A merged range keeps the
+// name of the range which starts first.
+func newIPRanges(entries []string) (*ipRanges, error) {
+	ranges := make([]ipRange, 0, len(entries))
+	for _, entry := range entries {
+		// lines of CSV files, such as published datacenter lists, have the block first and then its owner
+		fields := strings.SplitN(entry, ",", 3)
+		entry = strings.TrimSpace(fields[0])
+		var name string
+		if len(fields) > 1 {
+			name = strings.Trim(strings.TrimSpace(fields[1]), `"`)
+		}
+		if _, block, err := net.ParseCIDR(entry); err == nil {
+			first := block.IP.To16()
+			last := make(net.IP, net.IPv6len)
+			mask := block.Mask
+			if len(mask) == net.IPv4len {
+				mask = append(net.CIDRMask(96, 128)[:12], mask...)
+			}
+			for i := range first {
+				last[i] = first[i] | ^mask[i]
+			}
+			ranges = append(ranges, ipRange{first: first, last: last, name: name})
+		} else if ip := net.ParseIP(entry); ip != nil {
+			ranges = append(ranges, ipRange{first: ip.To16(), last: ip.To16(), name: name})
+		} else {
+			return nil, fmt.Errorf("invalid IP address or CIDR block: %s", entry)
+		}
+	}
+
+	sort.Slice(ranges, func(i, j int) bool { return bytes.Compare(ranges[i].first, ranges[j].first) < 0 })
+	merged := make([]ipRange, 0, len(ranges))
+	for _, r := range ranges {
+		if n := len(merged); n > 0 && bytes.Compare(r.first, merged[n-1].last) <= 0 {
+			if bytes.Compare(r.last, merged[n-1].last) > 0 {
+				merged[n-1].last = r.last
+			}
+			continue
+		}
+		merged = append(merged, r)
+	}
+	return &ipRanges{ranges: merged}, nil
+}
+
+func (r *ipRanges) contains(ip net.IP) bool {
+	_, ok := r.find(ip)
+	return ok
+}
+
+// find returns the range which contains the address, if any.
+func (r *ipRanges) find(ip net.IP) (ipRange, bool) {
+	ip = ip.To16()
+	if ip == nil {
+		return ipRange{}, false
+	}
+	// the first range which starts after the address; the one before it is the only one which may contain it
+	i := sort.Search(len(r.ranges), func(i int) bool { return bytes.Compare(r.ranges[i].first, ip) > 0 })
+	if i > 0 && bytes.Compare(ip, r.ranges[i-1].last) <= 0 {
+		return r.ranges[i-1], true
+	}
+	return ipRange{}, false
+}
+
+func (r *ipRanges) containsAny(ips []net.IP) bool {
+	_, ok := r.findAny(ips)
+	return ok
+}
+
+func (r *ipRanges) findAny(ips []net.IP) (ipRange, bool) {
+	for _, ip := range ips {
+		if found, ok := r.find(ip); ok {
+			return found, true
+		}
+	}
+	return ipRange{}, false
+}
@@ -0,0 +1,72 @@
+package ivt
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseBotPatterns(t *testing.T) {
+	patterns := parseBotPatterns([]string{"ExampleBot", "crawler|1|0", "retired|0|0", "Java/|1|1", " |1|0"})
+	assert.Equal(t, []botPattern{
+		{pattern: "examplebot"},
+		{pattern: "crawler"},
+		{pattern: "java/", atStart: true},
+	}, patterns)
+}
+
+func TestIPRanges(t *testing.T) {
+	ranges, err := newIPRanges([]string{"10.0.0.0/16", "10.0.128.0/17", "10.0.255.255", "10.2.0.0/16", "2001:db8::/48", "192.0.2.9"})
+	require.NoError(t, err)
+	assert.Len(t, ranges.ranges, 4, "overlapping ranges are merged")
+
+	testCases := []struct {
+		ip       string
+		expected bool
+	}{
+		{ip: "10.0.0.0", expected: true},
+		{ip: "10.0.200.1", expected: true},
+		{ip: "10.0.255.255", expected: true},
+		{ip: "10.1.0.0", expected: false},
+		{ip: "10.2.3.4", expected: true},
+		{ip: "9.255.255.255", expected: false},
+		{ip: "192.0.2.9", expected: true},
+		{ip: "192.0.2.10", expected: false},
+		{ip: "2001:db8::1", expected: true},
+		{ip: "2001:db8:1::1", expected: false},
+		{ip: "::ffff:10.0.0.1", expected: true},
+	}
+
+	for _, test := range testCases {
+		assert.Equal(t, test.expected, ranges.contains(net.ParseIP(test.ip)), test.ip)
+	}
+	assert.False(t, ranges.contains(nil))
+}
+
+func TestIPRangesNames(t *testing.T) {
+	ranges, err := newIPRanges([]string{`198.51.100.0/24,"Example Cloud",US`, "198.51.100.128/25,other-cloud", "203.0.113.0/24", "2001:db8::/32,v6-cloud"})
+	require.NoError(t, err)
+
+	testCases := []struct {
+		ip           string
+		expectedName string
+	}{
+		{ip: "198.51.100.200", expectedName: "Example Cloud"},
+		{ip: "203.0.113.1", expectedName: ""},
+		{ip: "2001:db8::1", expectedName: "v6-cloud"},
+	}
+
+	for _, test := range testCases {
+		found, ok := ranges.find(net.ParseIP(test.ip))
+		assert.True(t, ok, test.ip)
+		assert.Equal(t, test.expectedName, found.name, test.ip)
+	}
+}
+
+func TestIPRangesEmpty(t *testing.T) {
+	ranges, err := newIPRanges(nil)
+	require.NoError(t, err)
+	assert.False(t, ranges.containsAny([]net.IP{net.ParseIP("10.0.0.1")}))
+}
@@ -0,0 +1,187 @@
+// Package latencybudget divides the tmax of an auction between its stages, so that a slow stage can't
+// eat into the time the stages after it need to finish before the auction times out.
+package latencybudget
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/metrics"
+	"github.com/prebid/prebid-server/v2/openrtb_ext"
+)
+
+// Budget tracks how the tmax of a single auction is spent.
+//
+// The stored requests stage may use its share of tmax. The bidders stage must finish early enough to leave
+// the shares of the hooks and cache write stages, which run after it. The cache write stage may use
+// whatever is left. Hooks run at several points of the auction, each bounded by the timeouts in the hooks
+// execution plan, so the hooks stage is given no deadline of its own: its share only holds time back from
+// the bidders, and the time hooks take is recorded against it.
+//
+// A nil *Budget is valid, sets no deadlines and records nothing.
+type Budget struct {
+	storedRequestsShare float64
+	hooksShare          float64
+	cacheWriteShare     float64
+	start               time.Time
+	now                 func() time.Time
+
+	lock   sync.Mutex
+	tmax   time.Duration
+	stages map[metrics.LatencyBudgetStage]*stageUsage
+}
+
+type stageUsage struct {
+	allocated    time.Duration
+	hasAllocated bool
+	used         time.Duration
+}
+
+// New returns the budget of an auction which started at start, or nil if latency budgets are disabled.
+func New(cfg config.LatencyBudget, start time.Time) *Budget {
+	if !cfg.Enabled {
+		return nil
+	}
+	return &Budget{
+		storedRequestsShare: cfg.StoredRequests,
+		hooksShare:          cfg.Hooks,
+		cacheWriteShare:     cfg.CacheWrite,
+		start:               start,
+		now:                 time.Now,
+		stages:              make(map[metrics.LatencyBudgetStage]*stageUsage, len(metrics.LatencyBudgetStages())),
+	}
+}
+
+// SetTmax sets the time the auction has in total. It may be called again once the tmax of the auction is
+// final, for instance after stored requests have been merged into it. Until it's called, or if tmax is 0,
+// no stage is given a deadline.
+func (b *Budget) SetTmax(tmax time.Duration) {
+	if b == nil {
+		return
+	}
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.tmax = tmax
+}
+
+// Start begins a stage. The returned context is done once the stage's share of the budget runs out, and
+// the returned func must be called when the stage ends to record the time it took.
+func (b *Budget) Start(ctx context.Context, stage metrics.LatencyBudgetStage) (context.Context, func()) {
+	if b == nil {
+		return ctx, func() {}
+	}
+	begin := b.now()
+	cancel := context.CancelFunc(func() {})
+
+	b.lock.Lock()
+	if deadline, ok := b.deadlineLocked(stage); ok {
+		usage := b.usageLocked(stage)
+		if !usage.hasAllocated {
+			usage.allocated = deadline.Sub(begin)
+			if usage.allocated < 0 {
+				usage.allocated = 0
+			}
+			usage.hasAllocated = true
+		}
+		ctx, cancel = context.WithDeadline(ctx, deadline)
+	}
+	b.lock.Unlock()
+
+	var once sync.Once
+	return ctx, func() {
+		once.Do(func() {
+			cancel()
+			b.Record(stage, b.now().Sub(begin))
+		})
+	}
+}
+
+// Record adds to the time used by a stage.
+func (b *Budget) Record(stage metrics.LatencyBudgetStage, used time.Duration) {
+	if b == nil {
+		return
+	}
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.usageLocked(stage).used += used
+}
+
+// Report describes how the budget was divided and spent, for the debug output of the auction. It returns
+// nil if the auction has no tmax.
+func (b *Budget) Report() *openrtb_ext.ExtResponseLatencyBudget {
+	if b == nil {
+		return nil
+	}
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if b.tmax <= 0 {
+		return nil
+	}
+
+	report := &openrtb_ext.ExtResponseLatencyBudget{
+		TmaxMillis: b.tmax.Milliseconds(),
+		Stages:     make(map[string]openrtb_ext.ExtResponseLatencyBudgetStage, len(b.stages)),
+	}
+	for stage, usage := range b.stages {
+		report.Stages[string(stage)] = openrtb_ext.ExtResponseLatencyBudgetStage{
+			AllocatedMillis: b.allocatedLocked(stage, usage).Milliseconds(),
+			UsedMillis:      usage.used.Milliseconds(),
+		}
+	}
+	return report
+}
+
+// RecordMetrics records the time taken by each stage which ran, and whether it overran its share of the
+// budget.
+func (b *Budget) RecordMetrics(me metrics.MetricsEngine) {
+	if b == nil {
+		return
+	}
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if b.tmax <= 0 {
+		return
+	}
+
+	for stage, usage := range b.stages {
+		me.RecordLatencyBudgetStage(stage, usage.used, usage.used > b.allocatedLocked(stage, usage))
+	}
+}
+
+// deadlineLocked returns the time by which a stage must end, if it has a deadline of its own.
+func (b *Budget) deadlineLocked(stage metrics.LatencyBudgetStage) (time.Time, bool) {
+	if b.tmax <= 0 {
+		return time.Time{}, false
+	}
+	switch stage {
+	case metrics.LatencyBudgetStoredRequests:
+		return b.start.Add(b.share(b.storedRequestsShare)), true
+	case metrics.LatencyBudgetBidders:
+		return b.start.Add(b.tmax - b.share(b.hooksShare) - b.share(b.cacheWriteShare)), true
+	case metrics.LatencyBudgetCacheWrite:
+		return b.start.Add(b.tmax), true
+	}
+	return time.Time{}, false
+}
+
+func (b *Budget) allocatedLocked(stage metrics.LatencyBudgetStage, usage *stageUsage) time.Duration {
+	if stage == metrics.LatencyBudgetHooks {
+		return b.share(b.hooksShare)
+	}
+	return usage.allocated
+}
+
+func (b *Budget) usageLocked(stage metrics.LatencyBudgetStage) *stageUsage {
+	usage, ok := b.stages[stage]
+	if !ok {
+		usage = &stageUsage{}
+		b.stages[stage] = usage
+	}
+	return usage
+}
+
+func (b *Budget) share(share float64) time.Duration {
+	return time.Duration(float64(b.tmax) * share)
+}
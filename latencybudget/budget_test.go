@@ -0,0 +1,180 @@
+package latencybudget
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/metrics"
+	"github.com/prebid/prebid-server/v2/openrtb_ext"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testConfig = config.LatencyBudget{Enabled: true, StoredRequests: 0.1, Hooks: 0.05, CacheWrite: 0.15}
+
+func newTestBudget(start time.Time, now *time.Time) *Budget {
+	budget := New(testConfig, start)
+	budget.now = func() time.Time { return *now }
+	return budget
+}
+
+func TestNew(t *testing.T) {
+	assert.Nil(t, New(config.LatencyBudget{Enabled: false}, time.Now()))
+	assert.NotNil(t, New(testConfig, time.Now()))
+}
+
+func TestNilBudget(t *testing.T) {
+	var budget *Budget
+	budget.SetTmax(time.Second)
+
+	ctx, end := budget.Start(context.Background(), metrics.LatencyBudgetBidders)
+	_, hasDeadline := ctx.Deadline()
+	assert.False(t, hasDeadline)
+	end()
+
+	budget.Record(metrics.LatencyBudgetHooks, time.Millisecond)
+	assert.Nil(t, budget.Report())
+	budget.RecordMetrics(&metrics.MetricsEngineMock{})
+}
+
+func TestStartDeadlines(t *testing.T) {
+	start := time.Now()
+	now := start.Add(10 * time.Millisecond)
+
+	testCases := []struct {
+		name             string
+		stage            metrics.LatencyBudgetStage
+		expectedDeadline time.Time
+		expectedOk       bool
+	}{
+		{
+			name:             "stored-requests",
+			stage:            metrics.LatencyBudgetStoredRequests,
+			expectedDeadline: start.Add(100 * time.Millisecond),
+			expectedOk:       true,
+		},
+		{
+			name:             "bidders-leave-hooks-and-cache-write-shares",
+			stage:            metrics.LatencyBudgetBidders,
+			expectedDeadline: start.Add(800 * time.Millisecond),
+			expectedOk:       true,
+		},
+		{
+			name:             "cache-write",
+			stage:            metrics.LatencyBudgetCacheWrite,
+			expectedDeadline: start.Add(time.Second),
+			expectedOk:       true,
+		},
+		{
+			name:       "hooks",
+			stage:      metrics.LatencyBudgetHooks,
+			expectedOk: false,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			budget := newTestBudget(start, &now)
+			budget.SetTmax(time.Second)
+
+			ctx, end := budget.Start(context.Background(), test.stage)
+			defer end()
+			deadline, ok := ctx.Deadline()
+			assert.Equal(t, test.expectedOk, ok)
+			assert.Equal(t, test.expectedDeadline, deadline)
+		})
+	}
+}
+
+func TestStartWithoutTmax(t *testing.T) {
+	now := time.Now()
+	budget := newTestBudget(now, &now)
+
+	ctx, end := budget.Start(context.Background(), metrics.LatencyBudgetBidders)
+	defer end()
+	_, hasDeadline := ctx.Deadline()
+	assert.False(t, hasDeadline)
+}
+
+func TestStartKeepsEarlierParentDeadline(t *testing.T) {
+	now := time.Now()
+	budget := newTestBudget(now, &now)
+	budget.SetTmax(time.Second)
+
+	parent, cancel := context.WithDeadline(context.Background(), now.Add(50*time.Millisecond))
+	defer cancel()
+	ctx, end := budget.Start(parent, metrics.LatencyBudgetBidders)
+	defer end()
+
+	deadline, _ := ctx.Deadline()
+	assert.Equal(t, now.Add(50*time.Millisecond), deadline)
+}
+
+func TestEndCancelsStageContext(t *testing.T) {
+	now := time.Now()
+	budget := newTestBudget(now, &now)
+	budget.SetTmax(time.Second)
+
+	ctx, end := budget.Start(context.Background(), metrics.LatencyBudgetStoredRequests)
+	end()
+	end()
+	assert.Equal(t, context.Canceled, ctx.Err())
+}
+
+func TestReport(t *testing.T) {
+	start := time.Now()
+	now := start
+	budget := newTestBudget(start, &now)
+
+	budget.Record(metrics.LatencyBudgetHooks, 5*time.Millisecond)
+	assert.Nil(t, budget.Report(), "nothing to report without a tmax")
+
+	budget.SetTmax(time.Second)
+
+	_, endStoredRequests := budget.Start(context.Background(), metrics.LatencyBudgetStoredRequests)
+	now = now.Add(150 * time.Millisecond)
+	endStoredRequests()
+
+	_, endBidders := budget.Start(context.Background(), metrics.LatencyBudgetBidders)
+	now = now.Add(600 * time.Millisecond)
+	endBidders()
+
+	// a stage which starts after its deadline has nothing left
+	now = start.Add(1100 * time.Millisecond)
+	_, endCacheWrite := budget.Start(context.Background(), metrics.LatencyBudgetCacheWrite)
+	now = now.Add(20 * time.Millisecond)
+	endCacheWrite()
+
+	expected := &openrtb_ext.ExtResponseLatencyBudget{
+		TmaxMillis: 1000,
+		Stages: map[string]openrtb_ext.ExtResponseLatencyBudgetStage{
+			"stored_requests": {AllocatedMillis: 100, UsedMillis: 150},
+			"bidders":         {AllocatedMillis: 650, UsedMillis: 600},
+			"cache_write":     {AllocatedMillis: 0, UsedMillis: 20},
+			"hooks":           {AllocatedMillis: 50, UsedMillis: 5},
+		},
+	}
+	assert.Equal(t, expected, budget.Report())
+
+	me := &metrics.MetricsEngineMock{}
+	me.On("RecordLatencyBudgetStage", metrics.LatencyBudgetStoredRequests, 150*time.Millisecond, true).Once()
+	me.On("RecordLatencyBudgetStage", metrics.LatencyBudgetBidders, 600*time.Millisecond, false).Once()
+	me.On("RecordLatencyBudgetStage", metrics.LatencyBudgetCacheWrite, 20*time.Millisecond, true).Once()
+	me.On("RecordLatencyBudgetStage", metrics.LatencyBudgetHooks, 5*time.Millisecond, false).Once()
+	budget.RecordMetrics(me)
+	me.AssertExpectations(t)
+}
+
+func TestContext(t *testing.T) {
+	assert.Nil(t, FromContext(context.Background()))
+
+	ctx := WithBudget(context.Background(), nil)
+	assert.Equal(t, context.Background(), ctx, "a nil budget shouldn't wrap the context")
+
+	budget := New(testConfig, time.Now())
+	ctx = WithBudget(context.Background(), budget)
+	require.NotNil(t, FromContext(ctx))
+	assert.Same(t, budget, FromContext(ctx))
+}
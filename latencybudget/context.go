@@ -0,0 +1,19 @@
+package latencybudget
+
+import "context"
+
+type budgetKey struct{}
+
+// WithBudget returns a context which carries the budget of the auction to the stages run under it.
+func WithBudget(ctx context.Context, budget *Budget) context.Context {
+	if budget == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, budgetKey{}, budget)
+}
+
+// FromContext returns the budget of the auction, or nil if it doesn't have one.
+func FromContext(ctx context.Context) *Budget {
+	budget, _ := ctx.Value(budgetKey{}).(*Budget)
+	return budget
+}
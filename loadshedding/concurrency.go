@@ -0,0 +1,167 @@
+package loadshedding
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/prebid/prebid-server/v2/config"
+)
+
+// ErrOverloaded is returned when a request can't be admitted because the server is overloaded.
+var ErrOverloaded = errors.New("server is overloaded")
+
+// ConcurrencyLimiter bounds the number of requests processed at once, and adapts the bound to the load
+// the server can sustain.
+//
+// Requests beyond the limit wait in a queue. The time they spend waiting is the signal: once the CPU is
+// saturated, requests take longer, slots free up more slowly and the queueing delay grows. The limit is
+// adjusted with AIMD. It grows by one every limit's worth of requests admitted within the target delay,
+// and is cut by the backoff ratio when a request waits longer than that, at most once per target delay
+// so that a single burst isn't counted many times over. Requests which would wait longer than the
+// maximum queue wait, or which find the queue full, are rejected right away rather than being left to
+// time out further down the line.
+//
+// A nil *ConcurrencyLimiter is valid and admits every request.
+type ConcurrencyLimiter struct {
+	minLimit     float64
+	maxLimit     float64
+	backoffRatio float64
+	targetDelay  time.Duration
+	maxQueueWait time.Duration
+	maxQueueSize int
+	now          func() time.Time
+
+	lock         sync.Mutex
+	limit        float64
+	inFlight     int
+	queue        list.List
+	lastDecrease time.Time
+}
+
+// NewConcurrencyLimiter returns a limiter for the given config, or nil if concurrency limiting is disabled.
+func NewConcurrencyLimiter(cfg config.ConcurrencyLoadShedding) *ConcurrencyLimiter {
+	if !cfg.Enabled {
+		return nil
+	}
+	return &ConcurrencyLimiter{
+		minLimit:     float64(cfg.MinLimit),
+		maxLimit:     float64(cfg.MaxLimit),
+		backoffRatio: cfg.BackoffRatio,
+		targetDelay:  time.Duration(cfg.TargetQueueDelayMs) * time.Millisecond,
+		maxQueueWait: time.Duration(cfg.MaxQueueWaitMs) * time.Millisecond,
+		maxQueueSize: cfg.MaxQueueSize,
+		now:          time.Now,
+		limit:        float64(cfg.InitialLimit),
+	}
+}
+
+// Acquire admits a request, waiting in the queue if the limit has been reached. The returned func must
+// be called once the request is done. ErrOverloaded is returned if the request is rejected, and the
+// context's error if it's done while the request is waiting.
+func (l *ConcurrencyLimiter) Acquire(ctx context.Context) (func(), error) {
+	if l == nil {
+		return func() {}, nil
+	}
+	start := l.now()
+
+	l.lock.Lock()
+	if l.queue.Len() == 0 && l.inFlight < int(l.limit) {
+		l.inFlight++
+		l.lock.Unlock()
+		return l.release, nil
+	}
+	if l.queue.Len() >= l.maxQueueSize {
+		l.decreaseLocked(start)
+		l.lock.Unlock()
+		return nil, ErrOverloaded
+	}
+	ready := make(chan struct{})
+	element := l.queue.PushBack(ready)
+	l.lock.Unlock()
+
+	timer := time.NewTimer(l.maxQueueWait)
+	defer timer.Stop()
+
+	var err error
+	select {
+	case <-ready:
+		l.admitted(l.now().Sub(start))
+		return l.release, nil
+	case <-timer.C:
+		err = ErrOverloaded
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	select {
+	case <-ready:
+		// a slot was handed over while the wait was ending, so give it back
+		l.inFlight--
+		l.grantLocked()
+	default:
+		l.queue.Remove(element)
+	}
+	if err == ErrOverloaded {
+		l.decreaseLocked(l.now())
+	}
+	return nil, err
+}
+
+// Limit returns the current limit on the number of requests processed at once.
+func (l *ConcurrencyLimiter) Limit() int {
+	if l == nil {
+		return 0
+	}
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	return int(l.limit)
+}
+
+// admitted adjusts the limit for a request which was admitted after waiting in the queue.
+func (l *ConcurrencyLimiter) admitted(queueDelay time.Duration) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	if queueDelay > l.targetDelay {
+		l.decreaseLocked(l.now())
+		return
+	}
+	l.limit += 1 / l.limit
+	if l.limit > l.maxLimit {
+		l.limit = l.maxLimit
+	}
+	l.grantLocked()
+}
+
+func (l *ConcurrencyLimiter) release() {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	l.inFlight--
+	l.grantLocked()
+}
+
+func (l *ConcurrencyLimiter) decreaseLocked(now time.Time) {
+	if now.Sub(l.lastDecrease) < l.targetDelay {
+		return
+	}
+	l.lastDecrease = now
+	l.limit *= l.backoffRatio
+	if l.limit < l.minLimit {
+		l.limit = l.minLimit
+	}
+}
+
+// grantLocked hands free slots to the requests at the front of the queue.
+func (l *ConcurrencyLimiter) grantLocked() {
+	for l.queue.Len() > 0 && l.inFlight < int(l.limit) {
+		ready := l.queue.Remove(l.queue.Front()).(chan struct{})
+		l.inFlight++
+		close(ready)
+	}
+}
@@ -0,0 +1,156 @@
+package loadshedding
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestLimiter(limit int, maxQueueSize int, maxQueueWait time.Duration) *ConcurrencyLimiter {
+	return NewConcurrencyLimiter(config.ConcurrencyLoadShedding{
+		Enabled:            true,
+		InitialLimit:       limit,
+		MinLimit:           1,
+		MaxLimit:           limit * 2,
+		BackoffRatio:       0.5,
+		TargetQueueDelayMs: 10,
+		MaxQueueWaitMs:     int(maxQueueWait / time.Millisecond),
+		MaxQueueSize:       maxQueueSize,
+	})
+}
+
+func TestNewConcurrencyLimiter(t *testing.T) {
+	assert.Nil(t, NewConcurrencyLimiter(config.ConcurrencyLoadShedding{Enabled: false, InitialLimit: 10}))
+	assert.Equal(t, 10, newTestLimiter(10, 0, time.Second).Limit())
+}
+
+func TestNilConcurrencyLimiter(t *testing.T) {
+	var limiter *ConcurrencyLimiter
+	release, err := limiter.Acquire(context.Background())
+	require.NoError(t, err)
+	release()
+	assert.Equal(t, 0, limiter.Limit())
+}
+
+func TestConcurrencyLimiterQueuesOverLimit(t *testing.T) {
+	limiter := newTestLimiter(2, 10, time.Minute)
+
+	release1, err := limiter.Acquire(context.Background())
+	require.NoError(t, err)
+	_, err = limiter.Acquire(context.Background())
+	require.NoError(t, err)
+
+	admitted := make(chan error)
+	go func() {
+		_, err := limiter.Acquire(context.Background())
+		admitted <- err
+	}()
+
+	select {
+	case <-admitted:
+		t.Fatal("request over the limit should wait in the queue")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	release1()
+	assert.NoError(t, <-admitted)
+	assert.Equal(t, 2, limiter.inFlight)
+	assert.Equal(t, 0, limiter.queue.Len())
+}
+
+func TestConcurrencyLimiterRejectsWhenQueueFull(t *testing.T) {
+	limiter := newTestLimiter(1, 0, time.Minute)
+
+	_, err := limiter.Acquire(context.Background())
+	require.NoError(t, err)
+
+	_, err = limiter.Acquire(context.Background())
+	assert.Equal(t, ErrOverloaded, err)
+	assert.Equal(t, 1, limiter.Limit(), "limit should not drop below the minimum")
+}
+
+func TestConcurrencyLimiterRejectsAfterMaxQueueWait(t *testing.T) {
+	limiter := newTestLimiter(4, 10, 10*time.Millisecond)
+	for i := 0; i < 4; i++ {
+		_, err := limiter.Acquire(context.Background())
+		require.NoError(t, err)
+	}
+
+	_, err := limiter.Acquire(context.Background())
+	assert.Equal(t, ErrOverloaded, err)
+	assert.Equal(t, 0, limiter.queue.Len())
+	assert.Equal(t, 2, limiter.Limit(), "limit should back off")
+}
+
+func TestConcurrencyLimiterCanceledWhileQueued(t *testing.T) {
+	limiter := newTestLimiter(1, 10, time.Minute)
+	_, err := limiter.Acquire(context.Background())
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = limiter.Acquire(ctx)
+	assert.Equal(t, context.Canceled, err)
+	assert.Equal(t, 0, limiter.queue.Len())
+	assert.Equal(t, 1, limiter.inFlight)
+	assert.Equal(t, 1, limiter.Limit(), "a canceled request says nothing about the load")
+}
+
+func TestConcurrencyLimiterAdmitted(t *testing.T) {
+	t.Run("within-target-delay", func(t *testing.T) {
+		limiter := newTestLimiter(4, 10, time.Minute)
+		for i := 0; i < 4; i++ {
+			limiter.admitted(time.Millisecond)
+		}
+		// about one more per limit's worth of requests
+		assert.InDelta(t, 4.92, limiter.limit, 0.01)
+		assert.Equal(t, 4, limiter.Limit())
+
+		for i := 0; i < 100; i++ {
+			limiter.admitted(time.Millisecond)
+		}
+		assert.Equal(t, 8, limiter.Limit(), "limit should not grow past the maximum")
+	})
+
+	t.Run("over-target-delay", func(t *testing.T) {
+		now := time.Now()
+		limiter := newTestLimiter(8, 10, time.Minute)
+		limiter.now = func() time.Time { return now }
+
+		limiter.admitted(20 * time.Millisecond)
+		assert.Equal(t, 4, limiter.Limit())
+
+		// a burst of slow requests only backs off once per target delay
+		limiter.admitted(20 * time.Millisecond)
+		assert.Equal(t, 4, limiter.Limit())
+
+		now = now.Add(10 * time.Millisecond)
+		limiter.admitted(20 * time.Millisecond)
+		assert.Equal(t, 2, limiter.Limit())
+	})
+}
+
+func TestConcurrencyLimiterGrantsRaisedLimit(t *testing.T) {
+	limiter := newTestLimiter(1, 10, time.Minute)
+	_, err := limiter.Acquire(context.Background())
+	require.NoError(t, err)
+
+	admitted := make(chan error)
+	go func() {
+		_, err := limiter.Acquire(context.Background())
+		admitted <- err
+	}()
+	require.Eventually(t, func() bool {
+		limiter.lock.Lock()
+		defer limiter.lock.Unlock()
+		return limiter.queue.Len() == 1
+	}, time.Second, time.Millisecond)
+
+	// raising the limit hands the new slot to the queued request without waiting for a release
+	limiter.admitted(time.Millisecond)
+	assert.NoError(t, <-admitted)
+}
@@ -0,0 +1,44 @@
+package loadshedding
+
+import (
+	"context"
+
+	"github.com/prebid/prebid-server/v2/config"
+)
+
+type degradedKey struct{}
+
+// WithDegraded returns a context which marks the request as one to serve without optional work.
+func WithDegraded(ctx context.Context) context.Context {
+	return context.WithValue(ctx, degradedKey{}, true)
+}
+
+// IsDegraded returns true if the request should be served without optional work, such as debug output.
+func IsDegraded(ctx context.Context) bool {
+	degraded, _ := ctx.Value(degradedKey{}).(bool)
+	return degraded
+}
+
+// ModuleSkipper keeps modules from being run for the rest of a request.
+type ModuleSkipper interface {
+	SkipModules(modules []string)
+}
+
+// SkipOptionalModules keeps the optional modules from being run if the request is degraded. It must be called
+// before the first hook stage is executed, so that a module runs at either all of its stages or none of them.
+func SkipOptionalModules(ctx context.Context, skipper ModuleSkipper, modules []string) {
+	if IsDegraded(ctx) {
+		skipper.SkipModules(modules)
+	}
+}
+
+// SkipDebug turns off debug output for the account if the request is degraded, and reports whether it did.
+// Debug output is the first optional work to give up when the server is short on memory: it copies the
+// request and every call to the bidders into the response, which can take more memory than the auction.
+func SkipDebug(ctx context.Context, account *config.Account) bool {
+	if !IsDegraded(ctx) {
+		return false
+	}
+	account.DebugAllow = false
+	return true
+}
@@ -0,0 +1,71 @@
+package loadshedding
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockModuleSkipper struct {
+	skipped []string
+}
+
+func (m *mockModuleSkipper) SkipModules(modules []string) {
+	m.skipped = append(m.skipped, modules...)
+}
+
+func TestSkipOptionalModules(t *testing.T) {
+	testCases := []struct {
+		description     string
+		ctx             context.Context
+		expectedSkipped []string
+	}{
+		{
+			description: "not-degraded",
+			ctx:         context.Background(),
+		},
+		{
+			description:     "degraded",
+			ctx:             WithDegraded(context.Background()),
+			expectedSkipped: []string{"acme.foo", "acme.bar"},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			skipper := &mockModuleSkipper{}
+			SkipOptionalModules(test.ctx, skipper, []string{"acme.foo", "acme.bar"})
+			assert.Equal(t, test.expectedSkipped, skipper.skipped)
+		})
+	}
+}
+
+func TestSkipDebug(t *testing.T) {
+	testCases := []struct {
+		description        string
+		ctx                context.Context
+		expectedSkipped    bool
+		expectedDebugAllow bool
+	}{
+		{
+			description:        "not-degraded",
+			ctx:                context.Background(),
+			expectedDebugAllow: true,
+		},
+		{
+			description:     "degraded",
+			ctx:             WithDegraded(context.Background()),
+			expectedSkipped: true,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			account := &config.Account{DebugAllow: true}
+			assert.Equal(t, test.expectedSkipped, SkipDebug(test.ctx, account))
+			assert.Equal(t, test.expectedDebugAllow, account.DebugAllow)
+		})
+	}
+}
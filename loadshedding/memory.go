@@ -0,0 +1,113 @@
+// Package loadshedding protects the server when it's overloaded, by rejecting requests or skipping
+// optional work for them before the process runs out of memory or every auction times out.
+package loadshedding
+
+import (
+	"math"
+	"runtime/debug"
+	"runtime/metrics"
+	"sync/atomic"
+	"time"
+
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/logger"
+	"github.com/prebid/prebid-server/v2/util/task"
+)
+
+// Level is how much load is being shed.
+type Level int32
+
+const (
+	// LevelNormal - requests are served as usual
+	LevelNormal Level = iota
+	// LevelDegraded - requests are served without optional work
+	LevelDegraded
+	// LevelShed - requests are rejected
+	LevelShed
+)
+
+func (l Level) String() string {
+	switch l {
+	case LevelDegraded:
+		return "degraded"
+	case LevelShed:
+		return "shed"
+	default:
+		return "normal"
+	}
+}
+
+// These are the memory classes the runtime compares with GOMEMLIMIT: everything mapped by the
+// runtime, less the heap memory returned to the operating system.
+const (
+	totalMemoryMetric    = "/memory/classes/total:bytes"
+	releasedMemoryMetric = "/memory/classes/heap/released:bytes"
+)
+
+// MemoryMonitor samples the memory used by the process and maps it to a Level. A nil *MemoryMonitor
+// is valid and always reports LevelNormal.
+type MemoryMonitor struct {
+	degradeBytes uint64
+	shedBytes    uint64
+	level        atomic.Int32
+	readUsage    func() uint64
+}
+
+// NewMemoryMonitor returns a monitor for the given config, or nil if memory load shedding is disabled.
+func NewMemoryMonitor(cfg config.MemoryLoadShedding) *MemoryMonitor {
+	if !cfg.Enabled {
+		return nil
+	}
+	limit := cfg.LimitBytes
+	if limit == 0 {
+		limit = debug.SetMemoryLimit(-1)
+	}
+	if limit <= 0 || limit == math.MaxInt64 {
+		logger.Warning("Memory load shedding is disabled because there is no memory limit")
+		return nil
+	}
+	return &MemoryMonitor{
+		degradeBytes: uint64(float64(limit) * cfg.DegradeThreshold),
+		shedBytes:    uint64(float64(limit) * cfg.ShedThreshold),
+		readUsage:    readMemoryUsage,
+	}
+}
+
+// NewMemoryMonitorTask returns a task which keeps the monitor's level up to date at the configured interval.
+func NewMemoryMonitorTask(monitor *MemoryMonitor, cfg config.MemoryLoadShedding) *task.TickerTask {
+	return task.NewTickerTask(time.Duration(cfg.CheckIntervalMs)*time.Millisecond, monitor)
+}
+
+// Level returns the level as of the last sample.
+func (m *MemoryMonitor) Level() Level {
+	if m == nil {
+		return LevelNormal
+	}
+	return Level(m.level.Load())
+}
+
+// Run implements task.Runner.
+func (m *MemoryMonitor) Run() error {
+	usage := m.readUsage()
+
+	level := LevelNormal
+	if usage >= m.shedBytes {
+		level = LevelShed
+	} else if usage >= m.degradeBytes {
+		level = LevelDegraded
+	}
+
+	if previous := Level(m.level.Swap(int32(level))); previous != level {
+		logger.Warningf("Memory load shedding level changed from %s to %s at %d bytes in use", previous, level, usage)
+	}
+	return nil
+}
+
+func readMemoryUsage() uint64 {
+	samples := []metrics.Sample{
+		{Name: totalMemoryMetric},
+		{Name: releasedMemoryMetric},
+	}
+	metrics.Read(samples)
+	return samples[0].Value.Uint64() - samples[1].Value.Uint64()
+}
@@ -0,0 +1,91 @@
+package loadshedding
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewMemoryMonitor(t *testing.T) {
+	assert.Nil(t, NewMemoryMonitor(config.MemoryLoadShedding{Enabled: false, LimitBytes: 1000}), "disabled")
+
+	monitor := NewMemoryMonitor(config.MemoryLoadShedding{Enabled: true, LimitBytes: 1000, DegradeThreshold: 0.8, ShedThreshold: 0.9})
+	require.NotNil(t, monitor)
+	assert.Equal(t, uint64(800), monitor.degradeBytes)
+	assert.Equal(t, uint64(900), monitor.shedBytes)
+}
+
+func TestMemoryMonitorRun(t *testing.T) {
+	testCases := []struct {
+		name          string
+		usage         uint64
+		expectedLevel Level
+	}{
+		{
+			name:          "below-degrade-threshold",
+			usage:         799,
+			expectedLevel: LevelNormal,
+		},
+		{
+			name:          "at-degrade-threshold",
+			usage:         800,
+			expectedLevel: LevelDegraded,
+		},
+		{
+			name:          "at-shed-threshold",
+			usage:         900,
+			expectedLevel: LevelShed,
+		},
+		{
+			name:          "over-limit",
+			usage:         2000,
+			expectedLevel: LevelShed,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			monitor := &MemoryMonitor{
+				degradeBytes: 800,
+				shedBytes:    900,
+				readUsage:    func() uint64 { return test.usage },
+			}
+			assert.NoError(t, monitor.Run())
+			assert.Equal(t, test.expectedLevel, monitor.Level())
+		})
+	}
+}
+
+func TestMemoryMonitorRecovers(t *testing.T) {
+	usage := uint64(950)
+	monitor := &MemoryMonitor{
+		degradeBytes: 800,
+		shedBytes:    900,
+		readUsage:    func() uint64 { return usage },
+	}
+
+	monitor.Run()
+	assert.Equal(t, LevelShed, monitor.Level())
+
+	usage = 100
+	monitor.Run()
+	assert.Equal(t, LevelNormal, monitor.Level())
+}
+
+func TestNilMemoryMonitor(t *testing.T) {
+	var monitor *MemoryMonitor
+	assert.Equal(t, LevelNormal, monitor.Level())
+}
+
+func TestReadMemoryUsage(t *testing.T) {
+	assert.Greater(t, readMemoryUsage(), uint64(0))
+}
+
+func TestDegradedContext(t *testing.T) {
+	ctx := context.Background()
+	assert.False(t, IsDegraded(ctx))
+	assert.True(t, IsDegraded(WithDegraded(ctx)))
+}
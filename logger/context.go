@@ -0,0 +1,117 @@
+package logger
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"sync/atomic"
+)
+
+const (
+	// RequestIDHeader carries the ID of the request. One is made up for requests without it.
+	RequestIDHeader = "X-Request-Id"
+	// TraceParentHeader carries the W3C trace context the trace ID is read from.
+	TraceParentHeader = "Traceparent"
+)
+
+type requestInfoKey struct{}
+
+// requestInfo holds the IDs logged with the lines of a request. The account ID is only known once the
+// request has been read, so it's set on the shared requestInfo rather than in a new context.
+type requestInfo struct {
+	requestID string
+	traceID   string
+	accountID atomic.Value // string
+}
+
+// WithRequest returns a context whose lines are logged with the request and trace IDs.
+func WithRequest(ctx context.Context, requestID, traceID string) context.Context {
+	return context.WithValue(ctx, requestInfoKey{}, &requestInfo{requestID: requestID, traceID: traceID})
+}
+
+// SetAccountID sets the account ID the lines of the request the context belongs to are logged with. It
+// does nothing if the context doesn't belong to a request.
+func SetAccountID(ctx context.Context, accountID string) {
+	if info := requestInfoFromContext(ctx); info != nil {
+		info.accountID.Store(accountID)
+	}
+}
+
+func requestInfoFromContext(ctx context.Context) *requestInfo {
+	if ctx == nil {
+		return nil
+	}
+	info, _ := ctx.Value(requestInfoKey{}).(*requestInfo)
+	return info
+}
+
+func (i *requestInfo) RequestID() string {
+	if i == nil {
+		return ""
+	}
+	return i.requestID
+}
+
+func (i *requestInfo) TraceID() string {
+	if i == nil {
+		return ""
+	}
+	return i.traceID
+}
+
+func (i *requestInfo) AccountID() string {
+	if i == nil {
+		return ""
+	}
+	accountID, _ := i.accountID.Load().(string)
+	return accountID
+}
+
+func (i *requestInfo) glogSuffix() string {
+	if i == nil {
+		return ""
+	}
+	var suffix strings.Builder
+	for _, field := range [][2]string{{"request_id", i.RequestID()}, {"account_id", i.AccountID()}, {"trace_id", i.TraceID()}} {
+		if field[1] != "" {
+			suffix.WriteString(" " + field[0] + "=" + field[1])
+		}
+	}
+	return suffix.String()
+}
+
+// RequestContext puts the request and trace IDs of each request in its context, so they're logged with its
+// lines. The request ID is also set on the response.
+func RequestContext(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestID := r.Header.Get(RequestIDHeader)
+		if requestID == "" || len(requestID) > 128 {
+			requestID = newRequestID()
+		}
+		w.Header().Set(RequestIDHeader, requestID)
+
+		ctx := WithRequest(r.Context(), requestID, parseTraceID(r.Header.Get(TraceParentHeader)))
+		handler.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+func newRequestID() string {
+	id := make([]byte, 16)
+	rand.Read(id)
+	return hex.EncodeToString(id)
+}
+
+// parseTraceID returns the trace ID of a traceparent header, which looks like:
+// 00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01. It returns "" if the header isn't valid.
+func parseTraceID(traceParent string) string {
+	parts := strings.Split(traceParent, "-")
+	if len(parts) < 4 || len(parts[1]) != 32 || strings.Trim(parts[1], "0") == "" {
+		return ""
+	}
+	if _, err := hex.DecodeString(parts[1]); err != nil {
+		return ""
+	}
+	return strings.ToLower(parts[1])
+}
@@ -0,0 +1,81 @@
+package logger
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestContext(t *testing.T) {
+	testCases := []struct {
+		description       string
+		headers           map[string]string
+		expectedRequestID string
+		expectedTraceID   string
+	}{
+		{
+			description:       "IDs from headers",
+			headers:           map[string]string{"X-Request-Id": "req-1", "Traceparent": "00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01"},
+			expectedRequestID: "req-1",
+			expectedTraceID:   "4bf92f3577b34da6a3ce929d0e0e4736",
+		},
+		{
+			description:     "Request ID made up",
+			headers:         map[string]string{"Traceparent": "00-00000000000000000000000000000000-00f067aa0ba902b7-01"},
+			expectedTraceID: "",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			var info *requestInfo
+			handler := RequestContext(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				SetAccountID(r.Context(), "1001")
+				info = requestInfoFromContext(r.Context())
+			}))
+
+			req := httptest.NewRequest(http.MethodPost, "/openrtb2/auction", nil)
+			for name, value := range test.headers {
+				req.Header.Set(name, value)
+			}
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+
+			if assert.NotNil(t, info) {
+				if test.expectedRequestID != "" {
+					assert.Equal(t, test.expectedRequestID, info.RequestID())
+				} else {
+					assert.Len(t, info.RequestID(), 32)
+				}
+				assert.Equal(t, info.RequestID(), w.Header().Get(RequestIDHeader))
+				assert.Equal(t, test.expectedTraceID, info.TraceID())
+				assert.Equal(t, "1001", info.AccountID())
+			}
+		})
+	}
+}
+
+func TestSetAccountIDWithoutRequest(t *testing.T) {
+	SetAccountID(context.Background(), "1001")
+	assert.Nil(t, requestInfoFromContext(context.Background()))
+	assert.Equal(t, "", requestInfoFromContext(context.Background()).AccountID())
+}
+
+func TestParseTraceID(t *testing.T) {
+	testCases := []struct {
+		traceParent string
+		expected    string
+	}{
+		{traceParent: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", expected: "4bf92f3577b34da6a3ce929d0e0e4736"},
+		{traceParent: "", expected: ""},
+		{traceParent: "00-4bf92f3577b34da6-00f067aa0ba902b7-01", expected: ""},
+		{traceParent: "00-zzf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", expected: ""},
+	}
+
+	for _, test := range testCases {
+		assert.Equal(t, test.expected, parseTraceID(test.traceParent), test.traceParent)
+	}
+}
@@ -0,0 +1,137 @@
+package logger
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Level is the severity of a line. Lines below the level of their component and account aren't logged.
+type Level int
+
+const (
+	LevelDebug Level = iota
+	LevelInfo
+	LevelWarning
+	LevelError
+	LevelFatal
+	// levelExit is logged as fatal, but exits with status 1
+	levelExit
+)
+
+func (l Level) String() string {
+	switch l {
+	case LevelDebug:
+		return "debug"
+	case LevelInfo:
+		return "info"
+	case LevelWarning:
+		return "warning"
+	case LevelError:
+		return "error"
+	default:
+		return "fatal"
+	}
+}
+
+// ParseLevel returns the level with the given name.
+func ParseLevel(name string) (Level, error) {
+	switch strings.ToLower(name) {
+	case "debug":
+		return LevelDebug, nil
+	case "info":
+		return LevelInfo, nil
+	case "warning", "warn":
+		return LevelWarning, nil
+	case "error":
+		return LevelError, nil
+	}
+	return LevelInfo, fmt.Errorf("unknown log level: %s", name)
+}
+
+// Levels holds the names of the levels lines are logged at. The level of the account a line is logged for
+// is used over the level of its component, which is used over the default level. A component's level also
+// applies to the components within it, such as exchange/entities for exchange.
+type Levels struct {
+	Level      string            `json:"level"`
+	Components map[string]string `json:"components,omitempty"`
+	Accounts   map[string]string `json:"accounts,omitempty"`
+}
+
+type levels struct {
+	config     Levels
+	level      Level
+	components map[string]Level
+	accounts   map[string]Level
+}
+
+func defaultLevels() *levels {
+	return &levels{config: Levels{Level: LevelInfo.String()}, level: LevelInfo}
+}
+
+// SetLevels replaces the levels lines are logged at. An empty default level is info.
+func SetLevels(cfg Levels) error {
+	parsed, err := parseLevels(cfg)
+	if err != nil {
+		return err
+	}
+	std.levels.Store(parsed)
+	return nil
+}
+
+// GetLevels returns the levels lines are logged at.
+func GetLevels() Levels {
+	return std.levels.Load().config
+}
+
+func parseLevels(cfg Levels) (*levels, error) {
+	if cfg.Level == "" {
+		cfg.Level = LevelInfo.String()
+	}
+	level, err := ParseLevel(cfg.Level)
+	if err != nil {
+		return nil, err
+	}
+
+	parsed := &levels{config: cfg, level: level}
+	if parsed.components, err = parseLevelMap(cfg.Components); err != nil {
+		return nil, fmt.Errorf("component %v", err)
+	}
+	if parsed.accounts, err = parseLevelMap(cfg.Accounts); err != nil {
+		return nil, fmt.Errorf("account %v", err)
+	}
+	return parsed, nil
+}
+
+func parseLevelMap(names map[string]string) (map[string]Level, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+	parsed := make(map[string]Level, len(names))
+	for key, name := range names {
+		level, err := ParseLevel(name)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", key, err)
+		}
+		parsed[key] = level
+	}
+	return parsed, nil
+}
+
+func (l *levels) enabled(level Level, component, accountID string) bool {
+	if accountID != "" {
+		if accountLevel, ok := l.accounts[accountID]; ok {
+			return level >= accountLevel
+		}
+	}
+	for len(l.components) > 0 && component != "" {
+		if componentLevel, ok := l.components[component]; ok {
+			return level >= componentLevel
+		}
+		lastSlash := strings.LastIndex(component, "/")
+		if lastSlash < 0 {
+			break
+		}
+		component = component[:lastSlash]
+	}
+	return level >= l.level
+}
@@ -0,0 +1,63 @@
+package logger
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLevels(t *testing.T) {
+	testCases := []struct {
+		description string
+		levels      Levels
+		expectedErr string
+	}{
+		{description: "empty", levels: Levels{}},
+		{description: "valid", levels: Levels{Level: "WARN", Components: map[string]string{"exchange": "debug"}, Accounts: map[string]string{"1001": "error"}}},
+		{description: "invalid default", levels: Levels{Level: "loud"}, expectedErr: "unknown log level: loud"},
+		{description: "invalid component", levels: Levels{Components: map[string]string{"exchange": "loud"}}, expectedErr: "component exchange: unknown log level: loud"},
+		{description: "invalid account", levels: Levels{Accounts: map[string]string{"1001": "loud"}}, expectedErr: "account 1001: unknown log level: loud"},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			_, err := parseLevels(test.levels)
+			if test.expectedErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, test.expectedErr)
+			}
+		})
+	}
+}
+
+func TestLevelsEnabled(t *testing.T) {
+	l, err := parseLevels(Levels{
+		Level:      "info",
+		Components: map[string]string{"exchange": "error", "exchange/entities": "debug"},
+		Accounts:   map[string]string{"1001": "debug", "1002": "error"},
+	})
+	require.NoError(t, err)
+
+	testCases := []struct {
+		description string
+		level       Level
+		component   string
+		accountID   string
+		expected    bool
+	}{
+		{description: "default", level: LevelInfo, component: "router", expected: true},
+		{description: "below default", level: LevelDebug, component: "router", expected: false},
+		{description: "component", level: LevelWarning, component: "exchange", expected: false},
+		{description: "within component", level: LevelWarning, component: "exchange/inprocess", expected: false},
+		{description: "more specific component", level: LevelDebug, component: "exchange/entities", expected: true},
+		{description: "account over component", level: LevelDebug, component: "exchange", accountID: "1001", expected: true},
+		{description: "account over default", level: LevelWarning, component: "router", accountID: "1002", expected: false},
+		{description: "account without level", level: LevelInfo, component: "router", accountID: "1003", expected: true},
+	}
+
+	for _, test := range testCases {
+		assert.Equal(t, test.expected, l.enabled(test.level, test.component, test.accountID), test.description)
+	}
+}
@@ -0,0 +1,274 @@
+// Package logger writes the server's logs, as JSON lines or through glog. Each line has the component it's
+// logged from, which is the package's path within the module, and lines logged while a request is handled
+// have its request, account and trace IDs. The level logged at can be changed at runtime for each
+// component and account. Lines of high volume classes may be sampled, and sensitive values are redacted
+// before lines are written.
+package logger
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/golang/glog"
+)
+
+const (
+	// FormatJSON writes each line as a JSON object.
+	FormatJSON = "json"
+	// FormatGlog writes the lines through glog, with the fields appended to the message.
+	FormatGlog = "glog"
+)
+
+// modulePrefix is trimmed from package paths to make component names.
+const modulePrefix = "github.com/prebid/prebid-server/v2/"
+
+// callerDepth is the number of frames between the caller of an exported log function and log.
+const callerDepth = 3
+
+type logger struct {
+	format atomic.Value // string
+	levels atomic.Pointer[levels]
+	policy atomic.Pointer[policy]
+
+	mu     sync.Mutex
+	out    io.Writer
+	now    func() time.Time
+	exit   func(code int)
+	random func() float64
+	caller func(depth int) (component string, file string, line int)
+}
+
+var std = newLogger(os.Stderr)
+
+func newLogger(out io.Writer) *logger {
+	l := &logger{out: out, now: time.Now, exit: os.Exit, random: rand.Float64, caller: caller}
+	l.format.Store(FormatGlog)
+	l.levels.Store(defaultLevels())
+	l.policy.Store(&policy{})
+	return l
+}
+
+// Configure sets the format the lines are written in, the levels they're logged at, and the rules applied
+// to them. An empty format is json.
+func Configure(format string, levels Levels, policy Policy) error {
+	if format == "" {
+		format = FormatJSON
+	}
+	if format != FormatJSON && format != FormatGlog {
+		return fmt.Errorf("unknown log format: %s", format)
+	}
+	if err := SetLevels(levels); err != nil {
+		return err
+	}
+	if err := SetPolicy(policy); err != nil {
+		return err
+	}
+	std.format.Store(format)
+	return nil
+}
+
+// Entry logs lines with the request, account and trace IDs in its context, sampled as lines of its class.
+type Entry struct {
+	ctx   context.Context
+	class Class
+}
+
+// Ctx returns an Entry which logs lines with the IDs of the request the context belongs to.
+func Ctx(ctx context.Context) Entry {
+	return Entry{ctx: ctx}
+}
+
+// WithClass returns an Entry which logs lines of the given class, outside of any request.
+func WithClass(class Class) Entry {
+	return Entry{class: class}
+}
+
+// WithClass returns a copy of the Entry which logs lines of the given class.
+func (e Entry) WithClass(class Class) Entry {
+	e.class = class
+	return e
+}
+
+func (e Entry) Debugf(format string, args ...interface{}) {
+	std.logf(e, LevelDebug, format, args...)
+}
+
+func (e Entry) Infof(format string, args ...interface{}) {
+	std.logf(e, LevelInfo, format, args...)
+}
+
+func (e Entry) Warningf(format string, args ...interface{}) {
+	std.logf(e, LevelWarning, format, args...)
+}
+
+func (e Entry) Errorf(format string, args ...interface{}) {
+	std.logf(e, LevelError, format, args...)
+}
+
+func Debugf(format string, args ...interface{}) {
+	std.logf(Entry{}, LevelDebug, format, args...)
+}
+
+func Infof(format string, args ...interface{}) {
+	std.logf(Entry{}, LevelInfo, format, args...)
+}
+
+func Info(args ...interface{}) {
+	std.log(Entry{}, LevelInfo, args...)
+}
+
+func Warningf(format string, args ...interface{}) {
+	std.logf(Entry{}, LevelWarning, format, args...)
+}
+
+func Warning(args ...interface{}) {
+	std.log(Entry{}, LevelWarning, args...)
+}
+
+func Errorf(format string, args ...interface{}) {
+	std.logf(Entry{}, LevelError, format, args...)
+}
+
+func Error(args ...interface{}) {
+	std.log(Entry{}, LevelError, args...)
+}
+
+// Fatalf logs the line, whatever the level, and exits with status 255.
+func Fatalf(format string, args ...interface{}) {
+	std.logf(Entry{}, LevelFatal, format, args...)
+}
+
+// Fatal logs the line, whatever the level, and exits with status 255.
+func Fatal(args ...interface{}) {
+	std.log(Entry{}, LevelFatal, args...)
+}
+
+// Exitf logs the line at the error level, whatever the level, and exits with status 1.
+func Exitf(format string, args ...interface{}) {
+	std.logf(Entry{}, levelExit, format, args...)
+}
+
+// DebugEnabled returns true if debug lines are logged for the caller's component.
+func DebugEnabled() bool {
+	component, _, _ := std.caller(1)
+	return std.levels.Load().enabled(LevelDebug, component, "")
+}
+
+func (l *logger) logf(e Entry, level Level, format string, args ...interface{}) {
+	l.write(e, level, func() string { return fmt.Sprintf(format, args...) })
+}
+
+func (l *logger) log(e Entry, level Level, args ...interface{}) {
+	l.write(e, level, func() string { return fmt.Sprint(args...) })
+}
+
+func (l *logger) write(e Entry, level Level, message func() string) {
+	component, file, line := l.caller(callerDepth)
+	info := requestInfoFromContext(e.ctx)
+	if level < LevelFatal && !l.levels.Load().enabled(level, component, info.AccountID()) {
+		return
+	}
+	policy := l.policy.Load()
+	if level < LevelFatal && !policy.sampled(e.class, l.random) {
+		return
+	}
+	msg := policy.redact(strings.TrimSuffix(message(), "\n"))
+
+	if l.format.Load() == FormatGlog {
+		writeGlog(level, msg+info.glogSuffix())
+		return
+	}
+
+	entry := jsonLine{
+		Time:      l.now().UTC().Format(time.RFC3339Nano),
+		Level:     level.String(),
+		Component: component,
+		Caller:    fmt.Sprintf("%s:%d", file, line),
+		Message:   msg,
+		RequestID: info.RequestID(),
+		AccountID: info.AccountID(),
+		TraceID:   info.TraceID(),
+	}
+	encoded, err := json.Marshal(entry)
+	if err == nil {
+		l.mu.Lock()
+		l.out.Write(append(encoded, '\n'))
+		l.mu.Unlock()
+	}
+
+	switch level {
+	case LevelFatal:
+		l.exit(255)
+	case levelExit:
+		l.exit(1)
+	}
+}
+
+type jsonLine struct {
+	Time      string `json:"time"`
+	Level     string `json:"level"`
+	Component string `json:"component"`
+	Caller    string `json:"caller"`
+	Message   string `json:"msg"`
+	RequestID string `json:"request_id,omitempty"`
+	AccountID string `json:"account_id,omitempty"`
+	TraceID   string `json:"trace_id,omitempty"`
+}
+
+// glogDepth is the number of frames between the caller of an exported log function and glog.
+const glogDepth = callerDepth + 1
+
+func writeGlog(level Level, msg string) {
+	switch level {
+	case LevelDebug, LevelInfo:
+		glog.InfoDepth(glogDepth, msg)
+	case LevelWarning:
+		glog.WarningDepth(glogDepth, msg)
+	case LevelError:
+		glog.ErrorDepth(glogDepth, msg)
+	case LevelFatal:
+		glog.FatalDepth(glogDepth, msg)
+	case levelExit:
+		glog.ExitDepth(glogDepth, msg)
+	}
+}
+
+var components sync.Map // program counter to component
+
+// caller returns the component, file and line of the caller the given number of frames up.
+func caller(depth int) (string, string, int) {
+	pc, file, line, ok := runtime.Caller(depth + 1)
+	if !ok {
+		return "", "", 0
+	}
+	if component, ok := components.Load(pc); ok {
+		return component.(string), filepath.Base(file), line
+	}
+
+	component := ""
+	if fn := runtime.FuncForPC(pc); fn != nil {
+		component = componentOf(fn.Name())
+	}
+	components.Store(pc, component)
+	return component, filepath.Base(file), line
+}
+
+// componentOf returns the path within the module of the package of the function with the given name.
+func componentOf(funcName string) string {
+	pkg := funcName
+	lastSlash := strings.LastIndex(pkg, "/")
+	if dot := strings.Index(pkg[lastSlash+1:], "."); dot >= 0 {
+		pkg = pkg[:lastSlash+1+dot]
+	}
+	return strings.TrimPrefix(pkg, modulePrefix)
+}
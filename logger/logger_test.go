@@ -0,0 +1,153 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// useTestLogger replaces the logger with one which writes JSON lines to the returned buffer, until the
+// test ends.
+func useTestLogger(t *testing.T) (*bytes.Buffer, *[]int) {
+	out := &bytes.Buffer{}
+	exits := &[]int{}
+
+	l := newLogger(out)
+	l.format.Store(FormatJSON)
+	l.now = func() time.Time { return time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC) }
+	l.exit = func(code int) { *exits = append(*exits, code) }
+
+	previous := std
+	std = l
+	t.Cleanup(func() { std = previous })
+	return out, exits
+}
+
+func readLines(t *testing.T, out *bytes.Buffer) []map[string]string {
+	var lines []map[string]string
+	decoder := json.NewDecoder(out)
+	for decoder.More() {
+		var line map[string]string
+		require.NoError(t, decoder.Decode(&line))
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func TestJSONLines(t *testing.T) {
+	out, _ := useTestLogger(t)
+
+	ctx := WithRequest(context.Background(), "req-1", "4bf92f3577b34da6a3ce929d0e0e4736")
+	SetAccountID(ctx, "1001")
+
+	Infof("plain %d", 1)
+	Ctx(ctx).Warningf("in request %s", "a")
+	Error("trailing newline\n")
+
+	lines := readLines(t, out)
+	require.Len(t, lines, 3)
+	assert.Equal(t, map[string]string{
+		"time":      "2024-01-02T03:04:05Z",
+		"level":     "info",
+		"component": "logger",
+		"caller":    lines[0]["caller"],
+		"msg":       "plain 1",
+	}, lines[0])
+	assert.Regexp(t, `^logger_test\.go:\d+$`, lines[0]["caller"])
+	assert.Equal(t, map[string]string{
+		"time":       "2024-01-02T03:04:05Z",
+		"level":      "warning",
+		"component":  "logger",
+		"caller":     lines[1]["caller"],
+		"msg":        "in request a",
+		"request_id": "req-1",
+		"account_id": "1001",
+		"trace_id":   "4bf92f3577b34da6a3ce929d0e0e4736",
+	}, lines[1])
+	assert.Equal(t, "trailing newline", lines[2]["msg"])
+}
+
+func TestLevels(t *testing.T) {
+	out, _ := useTestLogger(t)
+	require.NoError(t, SetLevels(Levels{Level: "warning", Accounts: map[string]string{"1001": "debug"}}))
+
+	ctx := WithRequest(context.Background(), "req-1", "")
+	SetAccountID(ctx, "1001")
+
+	Infof("dropped")
+	Debugf("dropped")
+	Warningf("kept")
+	Ctx(ctx).Debugf("kept for account")
+	Ctx(context.Background()).Infof("dropped without account")
+
+	var messages []string
+	for _, line := range readLines(t, out) {
+		messages = append(messages, line["msg"])
+	}
+	assert.Equal(t, []string{"kept", "kept for account"}, messages)
+	assert.False(t, DebugEnabled())
+}
+
+func TestFatal(t *testing.T) {
+	out, exits := useTestLogger(t)
+	require.NoError(t, SetLevels(Levels{Level: "error"}))
+
+	Fatalf("fatal %d", 1)
+	Exitf("exit %d", 2)
+
+	lines := readLines(t, out)
+	require.Len(t, lines, 2)
+	assert.Equal(t, "fatal", lines[0]["level"])
+	assert.Equal(t, "fatal 1", lines[0]["msg"])
+	assert.Equal(t, "exit 2", lines[1]["msg"])
+	assert.Equal(t, []int{255, 1}, *exits)
+}
+
+func TestConfigure(t *testing.T) {
+	useTestLogger(t)
+
+	assert.EqualError(t, Configure("xml", Levels{}, Policy{}), "unknown log format: xml")
+	assert.EqualError(t, Configure("json", Levels{Level: "loud"}, Policy{}), "unknown log level: loud")
+
+	assert.NoError(t, Configure("", Levels{Level: "error"}, Policy{}))
+	assert.Equal(t, FormatJSON, std.format.Load())
+	assert.Equal(t, Levels{Level: "error"}, GetLevels())
+}
+
+func TestComponentOf(t *testing.T) {
+	testCases := []struct {
+		funcName string
+		expected string
+	}{
+		{funcName: "github.com/prebid/prebid-server/v2/exchange.(*exchange).HoldAuction", expected: "exchange"},
+		{funcName: "github.com/prebid/prebid-server/v2/endpoints/openrtb2.(*endpointDeps).Auction.func1", expected: "endpoints/openrtb2"},
+		{funcName: "main.main", expected: "main"},
+		{funcName: "github.com/golang/glog.Info", expected: "github.com/golang/glog"},
+	}
+
+	for _, test := range testCases {
+		assert.Equal(t, test.expected, componentOf(test.funcName), test.funcName)
+	}
+}
+
+func TestPolicy(t *testing.T) {
+	out, _ := useTestLogger(t)
+	std.random = func() float64 { return 0.5 }
+	require.NoError(t, SetPolicy(Policy{Sampling: map[Class]float64{ClassBidderErrors: 0.1}, RedactFields: []string{"ip"}}))
+
+	WithClass(ClassBidderErrors).Warningf("dropped")
+	WithClass(ClassValidationWarnings).Warningf("kept")
+	Ctx(context.Background()).WithClass(ClassBidderErrors).Errorf("dropped")
+	Errorf(`request {"ip":"1.2.3.4"}`)
+
+	var messages []string
+	for _, line := range readLines(t, out) {
+		messages = append(messages, line["msg"])
+	}
+	assert.Equal(t, []string{"kept", `request {"ip":"[REDACTED]"}`}, messages)
+}
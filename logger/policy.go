@@ -0,0 +1,127 @@
+package logger
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Class groups lines which are logged often enough that only a sample of them may be wanted.
+type Class string
+
+const (
+	// ClassBidderErrors are lines about calls to bidders which failed.
+	ClassBidderErrors Class = "bidder_errors"
+	// ClassValidationWarnings are lines about problems with requests which didn't stop the auction.
+	ClassValidationWarnings Class = "validation_warnings"
+)
+
+// Classes are the classes whose lines may be sampled.
+var Classes = []Class{ClassBidderErrors, ClassValidationWarnings}
+
+// redacted replaces the values of redacted fields and the text matched by redaction patterns.
+const redacted = "[REDACTED]"
+
+// Policy holds the rules applied to lines before they're written.
+type Policy struct {
+	// Sampling is the share of the lines of a class which are logged, in the range [0, 1]. All the lines of
+	// classes without a rate are logged.
+	Sampling map[Class]float64
+	// RedactFields are the names of the fields whose values are redacted, both as JSON object members
+	// ("ip":"...") and as query parameters (ip=...).
+	RedactFields []string
+	// RedactPatterns are regular expressions whose matches are redacted.
+	RedactPatterns []string
+}
+
+type policy struct {
+	sampling  map[Class]float64
+	redactors []redactor
+}
+
+type redactor struct {
+	pattern     *regexp.Regexp
+	replacement string
+}
+
+// SetPolicy replaces the rules applied to lines before they're written.
+func SetPolicy(cfg Policy) error {
+	parsed, err := parsePolicy(cfg)
+	if err != nil {
+		return err
+	}
+	std.policy.Store(parsed)
+	return nil
+}
+
+// ValidatePolicy returns the errors SetPolicy would fail with for the given rules.
+func ValidatePolicy(cfg Policy) []error {
+	var errs []error
+	for class, rate := range cfg.Sampling {
+		if !knownClass(class) {
+			errs = append(errs, fmt.Errorf("unknown log class: %s", class))
+		}
+		if rate < 0 || rate > 1 {
+			errs = append(errs, fmt.Errorf("sampling rate of %s must be in the range [0, 1]. Got %f", class, rate))
+		}
+	}
+	for _, field := range cfg.RedactFields {
+		if field == "" {
+			errs = append(errs, fmt.Errorf("redacted field names must not be empty"))
+		}
+	}
+	for _, pattern := range cfg.RedactPatterns {
+		if _, err := regexp.Compile(pattern); err != nil {
+			errs = append(errs, fmt.Errorf("invalid redaction pattern %s: %v", pattern, err))
+		}
+	}
+	return errs
+}
+
+func parsePolicy(cfg Policy) (*policy, error) {
+	if errs := ValidatePolicy(cfg); len(errs) > 0 {
+		return nil, errs[0]
+	}
+
+	parsed := &policy{sampling: cfg.Sampling}
+	if len(cfg.RedactFields) > 0 {
+		fields := make([]string, len(cfg.RedactFields))
+		for i, field := range cfg.RedactFields {
+			fields[i] = regexp.QuoteMeta(field)
+		}
+		names := strings.Join(fields, "|")
+		parsed.redactors = append(parsed.redactors,
+			redactor{pattern: regexp.MustCompile(`("(?:` + names + `)"\s*:\s*")(?:[^"\\]|\\.)*"`), replacement: "${1}" + redacted + `"`},
+			redactor{pattern: regexp.MustCompile(`\b((?:` + names + `)=)[^&\s"]*`), replacement: "${1}" + redacted},
+		)
+	}
+	for _, pattern := range cfg.RedactPatterns {
+		parsed.redactors = append(parsed.redactors, redactor{pattern: regexp.MustCompile(pattern), replacement: redacted})
+	}
+	return parsed, nil
+}
+
+func knownClass(class Class) bool {
+	for _, known := range Classes {
+		if class == known {
+			return true
+		}
+	}
+	return false
+}
+
+// sampled returns true if a line of the class should be logged, given a random number in the range [0, 1).
+func (p *policy) sampled(class Class, random func() float64) bool {
+	if class == "" {
+		return true
+	}
+	rate, ok := p.sampling[class]
+	return !ok || rate >= 1 || random() < rate
+}
+
+func (p *policy) redact(msg string) string {
+	for _, r := range p.redactors {
+		msg = r.pattern.ReplaceAllString(msg, r.replacement)
+	}
+	return msg
+}
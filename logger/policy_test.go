@@ -0,0 +1,108 @@
+package logger
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidatePolicy(t *testing.T) {
+	testCases := []struct {
+		description  string
+		policy       Policy
+		expectedErrs []error
+	}{
+		{
+			description: "empty",
+			policy:      Policy{},
+		},
+		{
+			description: "valid",
+			policy:      Policy{Sampling: map[Class]float64{ClassBidderErrors: 0, ClassValidationWarnings: 1}, RedactFields: []string{"ip"}, RedactPatterns: []string{`\d+`}},
+		},
+		{
+			description: "invalid",
+			policy:      Policy{Sampling: map[Class]float64{ClassBidderErrors: -1, "everything": 0.5}, RedactFields: []string{""}, RedactPatterns: []string{"["}},
+			expectedErrs: []error{
+				errors.New("sampling rate of bidder_errors must be in the range [0, 1]. Got -1.000000"),
+				errors.New("unknown log class: everything"),
+				errors.New("redacted field names must not be empty"),
+				errors.New("invalid redaction pattern [: error parsing regexp: missing closing ]: `[`"),
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			assert.ElementsMatch(t, test.expectedErrs, ValidatePolicy(test.policy))
+		})
+	}
+}
+
+func TestSampled(t *testing.T) {
+	p, err := parsePolicy(Policy{Sampling: map[Class]float64{ClassBidderErrors: 0.25, ClassValidationWarnings: 0}})
+	require.NoError(t, err)
+
+	testCases := []struct {
+		description string
+		class       Class
+		random      float64
+		expected    bool
+	}{
+		{description: "no class", class: "", random: 0.9, expected: true},
+		{description: "within rate", class: ClassBidderErrors, random: 0.2, expected: true},
+		{description: "above rate", class: ClassBidderErrors, random: 0.25, expected: false},
+		{description: "zero rate", class: ClassValidationWarnings, random: 0, expected: false},
+	}
+
+	for _, test := range testCases {
+		assert.Equal(t, test.expected, p.sampled(test.class, func() float64 { return test.random }), test.description)
+	}
+
+	unsampled, err := parsePolicy(Policy{})
+	require.NoError(t, err)
+	assert.True(t, unsampled.sampled(ClassBidderErrors, func() float64 { return 0.99 }))
+}
+
+func TestRedact(t *testing.T) {
+	p, err := parsePolicy(Policy{RedactFields: []string{"ip", "ifa", "gdpr_consent"}, RedactPatterns: []string{`\b\d{3}-\d{4}\b`}})
+	require.NoError(t, err)
+
+	testCases := []struct {
+		description string
+		msg         string
+		expected    string
+	}{
+		{
+			description: "JSON members",
+			msg:         `body:{"device":{"ip":"1.2.3.4","ifa" : "abc-123","ua":"agent"}}`,
+			expected:    `body:{"device":{"ip":"[REDACTED]","ifa" : "[REDACTED]","ua":"agent"}}`,
+		},
+		{
+			description: "JSON member with escaped quote",
+			msg:         `{"ifa":"a\"b","w":1}`,
+			expected:    `{"ifa":"[REDACTED]","w":1}`,
+		},
+		{
+			description: "query parameters",
+			msg:         "uri(https://bidder.com/sync?gdpr=1&gdpr_consent=CPX&zip=123)",
+			expected:    "uri(https://bidder.com/sync?gdpr=1&gdpr_consent=[REDACTED]&zip=123)",
+		},
+		{
+			description: "pattern",
+			msg:         "call 555-1234 now",
+			expected:    "call [REDACTED] now",
+		},
+		{
+			description: "untouched",
+			msg:         `{"zip":"12345","ipv6":"::1"}`,
+			expected:    `{"zip":"12345","ipv6":"::1"}`,
+		},
+	}
+
+	for _, test := range testCases {
+		assert.Equal(t, test.expected, p.redact(test.msg), test.description)
+	}
+}
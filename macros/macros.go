@@ -19,6 +19,15 @@ type EndpointTemplateParams struct {
 	SupplyId    string
 }
 
+// TimeoutNotificationTemplateParams specifies macros for bidder timeout notification urls. The values are
+// query escaped.
+type TimeoutNotificationTemplateParams struct {
+	Bidder    string
+	RequestID string
+	ImpIDs    string
+	Timeout   string
+}
+
 // UserSyncPrivacy specifies privacy policy macros, represented as strings, for user sync urls.
 type UserSyncPrivacy struct {
 	GDPR        string
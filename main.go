@@ -2,22 +2,26 @@ package main
 
 import (
 	"flag"
+	"fmt"
 	"math/rand"
 	"net/http"
+	"os"
 	"path/filepath"
 	"runtime"
+	"strings"
 	"time"
 
 	jsoniter "github.com/json-iterator/go"
 	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/configwatch"
 	"github.com/prebid/prebid-server/v2/currency"
+	"github.com/prebid/prebid-server/v2/logger"
 	"github.com/prebid/prebid-server/v2/openrtb_ext"
 	"github.com/prebid/prebid-server/v2/router"
 	"github.com/prebid/prebid-server/v2/server"
 	"github.com/prebid/prebid-server/v2/util/jsonutil"
 	"github.com/prebid/prebid-server/v2/util/task"
 
-	"github.com/golang/glog"
 	"github.com/spf13/viper"
 )
 
@@ -26,21 +30,43 @@ func init() {
 	jsoniter.RegisterExtension(&jsonutil.RawMessageExtension{})
 }
 
+var configOverlays = flag.String("config_overlays", "", "Comma separated list of config files merged in order on top of the base pbs config file. Later files take precedence.")
+var dumpConfig = flag.Bool("dump_config", false, "Print the effective merged configuration, with secrets redacted, then exit.")
+var validateConfig = flag.Bool("validate_config", false, "Load and validate the configuration, bidder info, bidder params schemas, stored data backends and modules, then exit without starting any listeners.")
+
 func main() {
 	flag.Parse() // required for glog flags and testing package flags
 
+	if *validateConfig {
+		os.Exit(runPreflight())
+	}
+
+	if *dumpConfig {
+		os.Exit(runDumpConfig())
+	}
+
 	bidderInfoPath, err := filepath.Abs(infoDirectory)
 	if err != nil {
-		glog.Exitf("Unable to build configuration directory path: %v", err)
+		logger.Exitf("Unable to build configuration directory path: %v", err)
 	}
 
 	bidderInfos, err := config.LoadBidderInfoFromDisk(bidderInfoPath)
 	if err != nil {
-		glog.Exitf("Unable to load bidder configurations: %v", err)
+		logger.Exitf("Unable to load bidder configurations: %v", err)
 	}
 	cfg, err := loadConfig(bidderInfos)
 	if err != nil {
-		glog.Exitf("Configuration could not be loaded or did not pass validation: %v", err)
+		logger.Exitf("Configuration could not be loaded or did not pass validation: %v", err)
+	}
+	if err := logger.Configure(cfg.Logging.Format, cfg.Logging.Levels(), cfg.Logging.Policy()); err != nil {
+		logger.Exitf("Logging could not be configured: %v", err)
+	}
+	engine, err := jsonutil.SetEngine(cfg.JSON.Engine)
+	if err != nil {
+		logger.Exitf("JSON engine could not be set: %v", err)
+	}
+	if cfg.JSON.Engine != "" && engine != cfg.JSON.Engine {
+		logger.Warningf("json.engine %s isn't built into this binary, %s is used instead", cfg.JSON.Engine, engine)
 	}
 
 	// Create a soft memory limit on the total amount of memory that PBS uses to tune the behavior
@@ -51,9 +77,9 @@ func main() {
 	garbageCollectionThreshold := make([]byte, cfg.GarbageCollectorThreshold)
 	defer runtime.KeepAlive(garbageCollectionThreshold)
 
-	err = serve(cfg)
+	err = serve(cfg, func() (*config.Configuration, error) { return loadConfig(bidderInfos) })
 	if err != nil {
-		glog.Exitf("prebid-server failed: %v", err)
+		logger.Exitf("prebid-server failed: %v", err)
 	}
 }
 
@@ -61,26 +87,104 @@ const configFileName = "pbs"
 const infoDirectory = "./static/bidder-info"
 
 func loadConfig(bidderInfos config.BidderInfos) (*config.Configuration, error) {
+	v, err := setupViper(bidderInfos)
+	if err != nil {
+		return nil, err
+	}
+	return config.New(v, bidderInfos, openrtb_ext.NormalizeBidderName)
+}
+
+func setupViper(bidderInfos config.BidderInfos) (*viper.Viper, error) {
 	v := viper.New()
 	config.SetupViper(v, configFileName, bidderInfos)
-	return config.New(v, bidderInfos, openrtb_ext.NormalizeBidderName)
+	if len(*configOverlays) > 0 {
+		if err := config.MergeOverlays(v, strings.Split(*configOverlays, ",")); err != nil {
+			return nil, err
+		}
+	}
+	return v, nil
 }
 
-func serve(cfg *config.Configuration) error {
-	fetchingInterval := time.Duration(cfg.CurrencyConverter.FetchIntervalSeconds) * time.Second
-	staleRatesThreshold := time.Duration(cfg.CurrencyConverter.StaleRatesSeconds) * time.Second
-	currencyConverter := currency.NewRateConverter(&http.Client{}, cfg.CurrencyConverter.FetchURL, staleRatesThreshold)
+// configFiles returns the config files the config is loaded from: the pbs config file, if one was found, and
+// the overlays.
+func configFiles() []string {
+	v := viper.New()
+	v.SetConfigName(configFileName)
+	v.AddConfigPath(".")
+	v.AddConfigPath("/etc/config")
 
-	currencyConverterTickerTask := task.NewTickerTask(fetchingInterval, currencyConverter)
-	currencyConverterTickerTask.Start()
+	var files []string
+	if err := v.ReadInConfig(); err == nil {
+		files = append(files, v.ConfigFileUsed())
+	}
+	for _, file := range strings.Split(*configOverlays, ",") {
+		if file = strings.TrimSpace(file); file != "" {
+			files = append(files, file)
+		}
+	}
+	return files
+}
+
+// runDumpConfig prints the effective configuration after merging defaults, config files, overlays and
+// environment variables. It returns the process exit code.
+func runDumpConfig() int {
+	bidderInfos, err := loadBidderInfos(infoDirectory)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Unable to load bidder configurations: %v\n", err)
+		return 1
+	}
+	v, err := setupViper(bidderInfos)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	if err := config.DumpEffectiveConfig(v, os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	return 0
+}
+
+// runPreflight reports every configuration problem found by preflight to standard error and
+// returns the process exit code.
+func runPreflight() int {
+	errs := preflight(infoDirectory, paramsDirectory, loadConfig)
+	if len(errs) == 0 {
+		fmt.Fprintln(os.Stdout, "Configuration is valid.")
+		return 0
+	}
+	fmt.Fprintf(os.Stderr, "Configuration is invalid (%d errors):\n", len(errs))
+	for _, err := range errs {
+		fmt.Fprintf(os.Stderr, "  - %v\n", err)
+	}
+	return 1
+}
+
+func serve(cfg *config.Configuration, reload func() (*config.Configuration, error)) error {
+	fetchingInterval := time.Duration(cfg.CurrencyConverter.FetchIntervalSeconds) * time.Second
+	currencyConverter := currency.NewRateConverterFromConfig(&http.Client{}, cfg.CurrencyConverter)
 
 	r, err := router.New(cfg, currencyConverter)
 	if err != nil {
 		return err
 	}
 
+	// the rates are first fetched once the metrics engine they're recorded in exists, still before the
+	// server starts listening
+	currencyConverter.SetMetricsEngine(r.MetricsEngine)
+	currencyConverterTickerTask := task.NewTickerTask(fetchingInterval, currencyConverter)
+	currencyConverterTickerTask.Start()
+
+	if cfg.ConfigWatch.Enabled {
+		files := configFiles()
+		logger.Infof("Watching the config files %s for changes every %d seconds", strings.Join(files, ", "), cfg.ConfigWatch.Interval)
+		configWatchTask := task.NewTickerTask(cfg.ConfigWatch.IntervalDuration(), configwatch.NewWatcher(cfg, files, reload, r.BidderMaintenance))
+		configWatchTask.Start()
+		defer configWatchTask.Stop()
+	}
+
 	corsRouter := router.SupportCORS(r)
-	server.Listen(cfg, router.NoCache{Handler: corsRouter}, router.Admin(currencyConverter, fetchingInterval), r.MetricsEngine)
+	server.Listen(cfg, router.NoCache{Handler: logger.RequestContext(corsRouter)}, router.Admin(cfg, currencyConverter, fetchingInterval, r.AuctionReplay, r.BidLandscape, r.ResponseOverrides, r.StoredRequestVersions, r.AnalyticsIngest, r.BidderKillSwitches), r.MetricsEngine)
 
 	r.Shutdown()
 	return nil
@@ -1,10 +1,12 @@
 package main
 
 import (
+	"errors"
 	"os"
 	"testing"
 
 	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/openrtb_ext"
 	"github.com/stretchr/testify/assert"
 
 	"github.com/spf13/viper"
@@ -65,3 +67,65 @@ func TestViperEnv(t *testing.T) {
 	assert.Equal(t, 60, v.Get("host_cookie.ttl_days"), "Config With Underscores")
 	assert.ElementsMatch(t, []string{"1.1.1.1/24", "2.2.2.2/24"}, v.Get("request_validation.ipv4_private_networks"), "Arrays")
 }
+
+func TestPreflight(t *testing.T) {
+	validLoad := func(bidderInfos config.BidderInfos) (*config.Configuration, error) {
+		v := viper.New()
+		config.SetupViper(v, "", bidderInfos)
+		v.Set("gdpr.default_value", "0")
+		return config.New(v, bidderInfos, openrtb_ext.NormalizeBidderName)
+	}
+
+	testCases := []struct {
+		description      string
+		infoDir          string
+		paramsDir        string
+		load             func(config.BidderInfos) (*config.Configuration, error)
+		expectedErrCount int
+		expectedErrPart  string
+	}{
+		{
+			description:      "valid",
+			infoDir:          infoDirectory,
+			paramsDir:        paramsDirectory,
+			load:             validLoad,
+			expectedErrCount: 0,
+		},
+		{
+			description:      "invalid-config",
+			infoDir:          infoDirectory,
+			paramsDir:        paramsDirectory,
+			load:             loadConfig,
+			expectedErrCount: 1,
+			expectedErrPart:  "config: gdpr.default_value is required",
+		},
+		{
+			description:      "missing-params-dir",
+			infoDir:          infoDirectory,
+			paramsDir:        "./does-not-exist",
+			load:             validLoad,
+			expectedErrCount: 1,
+			expectedErrPart:  "bidder-params:",
+		},
+		{
+			description: "config-load-failure-skips-dependent-checks",
+			infoDir:     infoDirectory,
+			paramsDir:   paramsDirectory,
+			load: func(config.BidderInfos) (*config.Configuration, error) {
+				return nil, errors.New("failed")
+			},
+			expectedErrCount: 1,
+			expectedErrPart:  "config: failed",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			errs := preflight(test.infoDir, test.paramsDir, test.load)
+			assert.Len(t, errs, test.expectedErrCount)
+			if test.expectedErrPart != "" && len(errs) > 0 {
+				assert.Contains(t, errs[0].Error(), test.expectedErrPart)
+			}
+		})
+	}
+}
@@ -0,0 +1,213 @@
+// Package metering counts the billable usage of each account, its auctions, the imps in them and the requests
+// made to bidders for them, and exports it to the host's billing sink. It also enforces the usage quotas of
+// accounts, with the rate limiting counters, so quotas are shared by the fleet when the counters are.
+//
+// Usage is counted as auctions are held, rather than derived from the metrics, so each report has the exact
+// usage counted by the instance since its last report. Reports which can't be exported are kept and sent
+// again at the next export, with the same ID, so the sink can tell a report it already has from a new one.
+package metering
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/errortypes"
+	"github.com/prebid/prebid-server/v2/logger"
+	"github.com/prebid/prebid-server/v2/metrics"
+	"github.com/prebid/prebid-server/v2/ratelimit"
+	"github.com/prebid/prebid-server/v2/util/task"
+	"github.com/prebid/prebid-server/v2/util/uuidutil"
+)
+
+// Usage is the billable usage of an account.
+type Usage struct {
+	Auctions    int64 `json:"auctions"`
+	Imps        int64 `json:"imps"`
+	BidderCalls int64 `json:"bidder_calls"`
+}
+
+func (u *Usage) add(other Usage) {
+	u.Auctions += other.Auctions
+	u.Imps += other.Imps
+	u.BidderCalls += other.BidderCalls
+}
+
+// Report is the usage of each account counted by an instance between Start and End.
+type Report struct {
+	ID       string           `json:"id"`
+	Start    time.Time        `json:"start"`
+	End      time.Time        `json:"end"`
+	Accounts map[string]Usage `json:"accounts"`
+}
+
+// Meter counts the usage of accounts and enforces their quotas. Usage is only counted if metering is enabled,
+// while quotas are enforced for the accounts which have them either way. A nil *Meter is valid, counts
+// nothing and enforces no quotas.
+type Meter struct {
+	limiter *ratelimit.Limiter
+	enabled bool
+	sink    *sink
+	ids     uuidutil.UUIDGenerator
+	now     func() time.Time
+
+	mutex sync.Mutex
+	start time.Time
+	usage map[string]*Usage
+
+	// exportMutex keeps exports from overlapping, and guards unsent
+	exportMutex sync.Mutex
+	unsent      []Report
+}
+
+// NewMeter builds a Meter which enforces quotas with the limiter's counters, and exports usage to the sink if
+// metering is enabled.
+func NewMeter(cfg config.Metering, limiter *ratelimit.Limiter, client *http.Client) *Meter {
+	m := &Meter{
+		limiter: limiter,
+		enabled: cfg.Enabled,
+		ids:     uuidutil.UUIDRandomGenerator{},
+		now:     time.Now,
+		usage:   make(map[string]*Usage),
+	}
+	if cfg.Enabled {
+		m.sink = &sink{
+			client:  client,
+			url:     cfg.SinkURL,
+			timeout: time.Duration(cfg.SinkTimeoutMS) * time.Millisecond,
+		}
+	}
+	m.start = m.now().UTC()
+	return m
+}
+
+// NewExportTask returns a task which exports the usage counted by the meter every
+// metering.export_interval_seconds, or nil if metering is disabled.
+func NewExportTask(m *Meter, cfg config.Metering) *task.TickerTask {
+	if m == nil || !cfg.Enabled {
+		return nil
+	}
+	return task.NewTickerTask(time.Duration(cfg.ExportIntervalSeconds)*time.Second, m)
+}
+
+// CheckQuota returns an error if the account has used up any of its quotas for the current period. A quota
+// is only used up once it's been reached, so the auction which reaches it is held, even if it goes over.
+func (m *Meter) CheckQuota(ctx context.Context, account *config.Account) error {
+	if m == nil || account == nil || !account.UsageQuota.Enabled() {
+		return nil
+	}
+	quota := account.UsageQuota
+	for _, q := range quotasOf(quota) {
+		if q.max > 0 && m.limiter.Exhausted(ctx, metrics.RateLimitUsageQuota, quotaKey(account.ID, q.name), q.max, quota.Window()) {
+			return &errortypes.RateLimited{Message: fmt.Sprintf("Account %s has used up its quota of %d %s for the %s", account.ID, q.max, q.description, quota.Period)}
+		}
+	}
+	return nil
+}
+
+// Record counts the usage of an auction of the account, for its quotas and for billing.
+func (m *Meter) Record(ctx context.Context, account *config.Account, usage Usage) {
+	if m == nil || account == nil {
+		return
+	}
+	quota := account.UsageQuota
+	for _, q := range quotasOf(quota) {
+		if q.max > 0 {
+			m.limiter.Count(ctx, metrics.RateLimitUsageQuota, quotaKey(account.ID, q.name), q.countOf(usage), quota.Window())
+		}
+	}
+	if !m.enabled {
+		return
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	counted, ok := m.usage[account.ID]
+	if !ok {
+		counted = &Usage{}
+		m.usage[account.ID] = counted
+	}
+	counted.add(usage)
+}
+
+// Run exports the usage counted since the last export, after any reports which failed to export before. It
+// implements task.Runner.
+func (m *Meter) Run() error {
+	m.exportMutex.Lock()
+	defer m.exportMutex.Unlock()
+
+	report, err := m.takeReport()
+	if err != nil {
+		logger.Errorf("Failed to make the usage report: %v", err)
+		return err
+	}
+	if report != nil {
+		m.unsent = append(m.unsent, *report)
+	}
+	for len(m.unsent) > 0 {
+		if err := m.sink.send(m.unsent[0]); err != nil {
+			logger.Warningf("Failed to export %d usage reports to metering.sink_url, which will be sent again at the next export: %v", len(m.unsent), err)
+			return err
+		}
+		m.unsent = m.unsent[1:]
+	}
+	return nil
+}
+
+// Close exports the usage counted since the last export.
+func (m *Meter) Close() {
+	if m == nil || !m.enabled {
+		return
+	}
+	m.Run()
+}
+
+// takeReport returns the usage counted since the last report, and starts counting again. It returns nil if
+// nothing was counted.
+func (m *Meter) takeReport() (*Report, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if len(m.usage) == 0 {
+		return nil, nil
+	}
+	id, err := m.ids.Generate()
+	if err != nil {
+		return nil, err
+	}
+
+	report := &Report{
+		ID:       id,
+		Start:    m.start,
+		End:      m.now().UTC(),
+		Accounts: make(map[string]Usage, len(m.usage)),
+	}
+	for account, usage := range m.usage {
+		report.Accounts[account] = *usage
+	}
+	m.start = report.End
+	m.usage = make(map[string]*Usage, len(m.usage))
+	return report, nil
+}
+
+// quota is one of the quotas of an account.
+type quota struct {
+	name        string
+	description string
+	max         int64
+	countOf     func(Usage) int64
+}
+
+func quotasOf(cfg config.AccountUsageQuota) []quota {
+	return []quota{
+		{name: "auctions", description: "auctions", max: cfg.MaxAuctions, countOf: func(u Usage) int64 { return u.Auctions }},
+		{name: "imps", description: "imps", max: cfg.MaxImps, countOf: func(u Usage) int64 { return u.Imps }},
+		{name: "bidder_calls", description: "bidder calls", max: cfg.MaxBidderCalls, countOf: func(u Usage) int64 { return u.BidderCalls }},
+	}
+}
+
+func quotaKey(accountID, name string) string {
+	return "usage_quota:" + accountID + ":" + name
+}
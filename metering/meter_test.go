@@ -0,0 +1,189 @@
+package metering
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/errortypes"
+	"github.com/prebid/prebid-server/v2/metrics"
+	"github.com/prebid/prebid-server/v2/ratelimit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeIDs struct {
+	next int
+}
+
+func (f *fakeIDs) Generate() (string, error) {
+	f.next++
+	return "report-" + strconv.Itoa(f.next), nil
+}
+
+func newTestLimiter() *ratelimit.Limiter {
+	me := &metrics.MetricsEngineMock{}
+	me.On("RecordRateLimit", metrics.RateLimitUsageQuota, mock.Anything).Return()
+	return ratelimit.NewLocalLimiter(me)
+}
+
+func newTestMeter(sinkURL string) *Meter {
+	meter := NewMeter(config.Metering{Enabled: true, ExportIntervalSeconds: 60, SinkURL: sinkURL, SinkTimeoutMS: 1000}, newTestLimiter(), http.DefaultClient)
+	meter.ids = &fakeIDs{}
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	meter.start = now
+	meter.now = func() time.Time {
+		now = now.Add(time.Minute)
+		return now
+	}
+	return meter
+}
+
+// newSink returns a sink which answers with the given status codes in turn, then with 200, and the reports it
+// received.
+func newSink(t *testing.T, statusCodes ...int) (*httptest.Server, chan string) {
+	received := make(chan string, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		received <- string(body)
+		if len(statusCodes) > 0 {
+			w.WriteHeader(statusCodes[0])
+			statusCodes = statusCodes[1:]
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server, received
+}
+
+func TestNilMeter(t *testing.T) {
+	var meter *Meter
+	account := &config.Account{ID: "1001", UsageQuota: config.AccountUsageQuota{Period: config.UsageQuotaPeriodDay, MaxAuctions: 1}}
+
+	assert.NoError(t, meter.CheckQuota(context.Background(), account))
+	meter.Record(context.Background(), account, Usage{Auctions: 1})
+	meter.Close()
+	assert.Nil(t, NewExportTask(meter, config.Metering{Enabled: true}))
+}
+
+func TestNewExportTask(t *testing.T) {
+	meter := NewMeter(config.Metering{}, newTestLimiter(), http.DefaultClient)
+	assert.Nil(t, NewExportTask(meter, config.Metering{}))
+	assert.NotNil(t, NewExportTask(meter, config.Metering{Enabled: true, ExportIntervalSeconds: 60}))
+}
+
+func TestExport(t *testing.T) {
+	server, received := newSink(t)
+	meter := newTestMeter(server.URL)
+
+	meter.Record(context.Background(), &config.Account{ID: "1001"}, Usage{Auctions: 1, Imps: 2, BidderCalls: 3})
+	meter.Record(context.Background(), &config.Account{ID: "1001"}, Usage{Auctions: 1, Imps: 1, BidderCalls: 2})
+	meter.Record(context.Background(), &config.Account{ID: "1002"}, Usage{Auctions: 1, Imps: 1})
+	require.NoError(t, meter.Run())
+
+	assert.JSONEq(t, `{"id":"report-1","start":"2024-05-01T12:00:00Z","end":"2024-05-01T12:01:00Z","accounts":{
+		"1001":{"auctions":2,"imps":3,"bidder_calls":5},
+		"1002":{"auctions":1,"imps":1,"bidder_calls":0}
+	}}`, <-received)
+
+	// nothing is sent until there's usage again, and the next report starts where the last one ended
+	require.NoError(t, meter.Run())
+	assert.Empty(t, received)
+	meter.Record(context.Background(), &config.Account{ID: "1002"}, Usage{Auctions: 1, Imps: 4, BidderCalls: 8})
+	meter.Close()
+	assert.JSONEq(t, `{"id":"report-2","start":"2024-05-01T12:01:00Z","end":"2024-05-01T12:02:00Z","accounts":{
+		"1002":{"auctions":1,"imps":4,"bidder_calls":8}
+	}}`, <-received)
+}
+
+func TestExportFailed(t *testing.T) {
+	server, received := newSink(t, http.StatusServiceUnavailable)
+	meter := newTestMeter(server.URL)
+
+	meter.Record(context.Background(), &config.Account{ID: "1001"}, Usage{Auctions: 1, Imps: 1, BidderCalls: 1})
+	assert.EqualError(t, meter.Run(), "unexpected status code 503")
+	assert.Contains(t, <-received, `"id":"report-1"`)
+
+	// the report which failed is sent again as it was, before the usage counted since
+	meter.Record(context.Background(), &config.Account{ID: "1001"}, Usage{Auctions: 1, Imps: 2, BidderCalls: 2})
+	require.NoError(t, meter.Run())
+	assert.JSONEq(t, `{"id":"report-1","start":"2024-05-01T12:00:00Z","end":"2024-05-01T12:01:00Z","accounts":{
+		"1001":{"auctions":1,"imps":1,"bidder_calls":1}
+	}}`, <-received)
+	assert.JSONEq(t, `{"id":"report-2","start":"2024-05-01T12:01:00Z","end":"2024-05-01T12:02:00Z","accounts":{
+		"1001":{"auctions":1,"imps":2,"bidder_calls":2}
+	}}`, <-received)
+	assert.Empty(t, meter.unsent)
+}
+
+func TestMeteringDisabled(t *testing.T) {
+	meter := NewMeter(config.Metering{}, newTestLimiter(), http.DefaultClient)
+
+	meter.Record(context.Background(), &config.Account{ID: "1001"}, Usage{Auctions: 1})
+	assert.Empty(t, meter.usage)
+	meter.Close()
+}
+
+func TestCheckQuota(t *testing.T) {
+	tests := []struct {
+		description   string
+		quota         config.AccountUsageQuota
+		usage         []Usage
+		expectedError string
+	}{
+		{
+			description: "no-quota",
+			usage:       []Usage{{Auctions: 100, Imps: 100, BidderCalls: 100}},
+		},
+		{
+			description: "under-quota",
+			quota:       config.AccountUsageQuota{Period: config.UsageQuotaPeriodDay, MaxAuctions: 3, MaxImps: 10},
+			usage:       []Usage{{Auctions: 1, Imps: 4}, {Auctions: 1, Imps: 5}},
+		},
+		{
+			description:   "auctions-used-up",
+			quota:         config.AccountUsageQuota{Period: config.UsageQuotaPeriodDay, MaxAuctions: 2, MaxImps: 10},
+			usage:         []Usage{{Auctions: 1, Imps: 1}, {Auctions: 1, Imps: 1}},
+			expectedError: "Account 1001 has used up its quota of 2 auctions for the day",
+		},
+		{
+			description:   "imps-gone-over",
+			quota:         config.AccountUsageQuota{Period: config.UsageQuotaPeriodHour, MaxImps: 10},
+			usage:         []Usage{{Auctions: 1, Imps: 8}, {Auctions: 1, Imps: 4}},
+			expectedError: "Account 1001 has used up its quota of 10 imps for the hour",
+		},
+		{
+			description:   "bidder-calls-used-up",
+			quota:         config.AccountUsageQuota{Period: config.UsageQuotaPeriodDay, MaxBidderCalls: 6},
+			usage:         []Usage{{Auctions: 1, BidderCalls: 6}},
+			expectedError: "Account 1001 has used up its quota of 6 bidder calls for the day",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			meter := NewMeter(config.Metering{}, newTestLimiter(), http.DefaultClient)
+			account := &config.Account{ID: "1001", UsageQuota: test.quota}
+
+			for _, usage := range test.usage {
+				require.NoError(t, meter.CheckQuota(context.Background(), account))
+				meter.Record(context.Background(), account, usage)
+			}
+			err := meter.CheckQuota(context.Background(), account)
+
+			if test.expectedError == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, test.expectedError)
+			assert.Equal(t, errortypes.RateLimitedErrorCode, errortypes.ReadCode(err))
+			assert.NoError(t, meter.CheckQuota(context.Background(), &config.Account{ID: "1002", UsageQuota: test.quota}), "other accounts have their own quotas")
+		})
+	}
+}
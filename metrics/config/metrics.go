@@ -142,6 +142,34 @@ func (me *MultiMetricsEngine) RecordAdapterConnections(bidderName openrtb_ext.Bi
 	}
 }
 
+// RecordAdapterConnectionWarmup across all engines
+func (me *MultiMetricsEngine) RecordAdapterConnectionWarmup(adapterName openrtb_ext.BidderName, result metrics.ConnectionWarmupResult) {
+	for _, thisME := range *me {
+		thisME.RecordAdapterConnectionWarmup(adapterName, result)
+	}
+}
+
+// RecordAdapterTLSHandshake across all engines
+func (me *MultiMetricsEngine) RecordAdapterTLSHandshake(adapterName openrtb_ext.BidderName, resumed bool) {
+	for _, thisME := range *me {
+		thisME.RecordAdapterTLSHandshake(adapterName, resumed)
+	}
+}
+
+// RecordAdapterHedgedRequest across all engines
+func (me *MultiMetricsEngine) RecordAdapterHedgedRequest(adapterName openrtb_ext.BidderName, won bool) {
+	for _, thisME := range *me {
+		thisME.RecordAdapterHedgedRequest(adapterName, won)
+	}
+}
+
+// RecordAdapterMaintenance across all engines
+func (me *MultiMetricsEngine) RecordAdapterMaintenance(adapterName openrtb_ext.BidderName, reason metrics.BidderMaintenance) {
+	for _, thisME := range *me {
+		thisME.RecordAdapterMaintenance(adapterName, reason)
+	}
+}
+
 // Times the DNS resolution process
 func (me *MultiMetricsEngine) RecordDNSTime(dnsLookupTime time.Duration) {
 	for _, thisME := range *me {
@@ -161,6 +189,160 @@ func (me *MultiMetricsEngine) RecordBidderServerResponseTime(bidderServerRespons
 	}
 }
 
+// RecordBidderRequestPool across all engines
+func (me *MultiMetricsEngine) RecordBidderRequestPool(runningWorkers int, queuedRequests int) {
+	for _, thisME := range *me {
+		thisME.RecordBidderRequestPool(runningWorkers, queuedRequests)
+	}
+}
+
+// RecordAdapterResponseCacheResult across all engines
+func (me *MultiMetricsEngine) RecordAdapterResponseCacheResult(adapterName openrtb_ext.BidderName, cacheResult metrics.CacheResult) {
+	for _, thisME := range *me {
+		thisME.RecordAdapterResponseCacheResult(adapterName, cacheResult)
+	}
+}
+
+// RecordBidderRequestShed across all engines
+func (me *MultiMetricsEngine) RecordBidderRequestShed(adapterName openrtb_ext.BidderName) {
+	for _, thisME := range *me {
+		thisME.RecordBidderRequestShed(adapterName)
+	}
+}
+
+// RecordLoadShedding across all engines
+func (me *MultiMetricsEngine) RecordLoadShedding(action metrics.LoadSheddingAction) {
+	for _, thisME := range *me {
+		thisME.RecordLoadShedding(action)
+	}
+}
+
+// RecordLatencyBudgetStage across all engines
+func (me *MultiMetricsEngine) RecordLatencyBudgetStage(stage metrics.LatencyBudgetStage, used time.Duration, overrun bool) {
+	for _, thisME := range *me {
+		thisME.RecordLatencyBudgetStage(stage, used, overrun)
+	}
+}
+
+// RecordTrafficShadow across all engines
+func (me *MultiMetricsEngine) RecordTrafficShadow(status metrics.TrafficShadowStatus) {
+	for _, thisME := range *me {
+		thisME.RecordTrafficShadow(status)
+	}
+}
+
+// RecordTestRequest across all engines
+func (me *MultiMetricsEngine) RecordTestRequest(requestType metrics.RequestType) {
+	for _, thisME := range *me {
+		thisME.RecordTestRequest(requestType)
+	}
+}
+
+// RecordWebhook across all engines
+func (me *MultiMetricsEngine) RecordWebhook(status metrics.WebhookStatus) {
+	for _, thisME := range *me {
+		thisME.RecordWebhook(status)
+	}
+}
+
+// RecordAuctionEventWebhook across all engines
+func (me *MultiMetricsEngine) RecordAuctionEventWebhook(status metrics.WebhookStatus) {
+	for _, thisME := range *me {
+		thisME.RecordAuctionEventWebhook(status)
+	}
+}
+
+// RecordCurrencyRatesFetch across all engines
+func (me *MultiMetricsEngine) RecordCurrencyRatesFetch(status metrics.CurrencyRatesFetchStatus) {
+	for _, thisME := range *me {
+		thisME.RecordCurrencyRatesFetch(status)
+	}
+}
+
+// RecordCurrencyRatesStale across all engines
+func (me *MultiMetricsEngine) RecordCurrencyRatesStale(stale bool) {
+	for _, thisME := range *me {
+		thisME.RecordCurrencyRatesStale(stale)
+	}
+}
+
+// RecordAdapterCanary across all engines
+func (me *MultiMetricsEngine) RecordAdapterCanary(labels metrics.AdapterCanaryLabels, bids int, length time.Duration) {
+	for _, thisME := range *me {
+		thisME.RecordAdapterCanary(labels, bids, length)
+	}
+}
+
+// RecordAdapterPIIViolation across all engines
+func (me *MultiMetricsEngine) RecordAdapterPIIViolation(labels metrics.AdapterPIIViolationLabels) {
+	for _, thisME := range *me {
+		thisME.RecordAdapterPIIViolation(labels)
+	}
+}
+
+// RecordAdapterFieldsWithheld across all engines
+func (me *MultiMetricsEngine) RecordAdapterFieldsWithheld(adapterName openrtb_ext.BidderName, fields int) {
+	for _, thisME := range *me {
+		thisME.RecordAdapterFieldsWithheld(adapterName, fields)
+	}
+}
+
+// RecordAdapterFloorConversion across all engines
+func (me *MultiMetricsEngine) RecordAdapterFloorConversion(adapterName openrtb_ext.BidderName, success bool) {
+	for _, thisME := range *me {
+		thisME.RecordAdapterFloorConversion(adapterName, success)
+	}
+}
+
+// RecordIVT across all engines
+func (me *MultiMetricsEngine) RecordIVT(reason metrics.IVTReason, action metrics.IVTAction) {
+	for _, thisME := range *me {
+		thisME.RecordIVT(reason, action)
+	}
+}
+
+// RecordRequestLimitExceeded across all engines
+func (me *MultiMetricsEngine) RecordRequestLimitExceeded(limit metrics.RequestLimit) {
+	for _, thisME := range *me {
+		thisME.RecordRequestLimitExceeded(limit)
+	}
+}
+
+// RecordRequestNormalization across all engines
+func (me *MultiMetricsEngine) RecordRequestNormalization(fix metrics.RequestNormalization) {
+	for _, thisME := range *me {
+		thisME.RecordRequestNormalization(fix)
+	}
+}
+
+// RecordAPIKey across all engines
+func (me *MultiMetricsEngine) RecordAPIKey(status metrics.APIKeyStatus) {
+	for _, thisME := range *me {
+		thisME.RecordAPIKey(status)
+	}
+}
+
+// RecordRateLimit across all engines
+func (me *MultiMetricsEngine) RecordRateLimit(limit metrics.RateLimit, outcome metrics.RateLimitOutcome) {
+	for _, thisME := range *me {
+		thisME.RecordRateLimit(limit, outcome)
+	}
+}
+
+// RecordGeoLookup across all engines
+func (me *MultiMetricsEngine) RecordGeoLookup(status metrics.GeoLookupStatus, duration time.Duration) {
+	for _, thisME := range *me {
+		thisME.RecordGeoLookup(status, duration)
+	}
+}
+
+// RecordContentClassification across all engines
+func (me *MultiMetricsEngine) RecordContentClassification(status metrics.ContentClassificationStatus, duration time.Duration) {
+	for _, thisME := range *me {
+		thisME.RecordContentClassification(status, duration)
+	}
+}
+
 // RecordAdapterBidReceived across all engines
 func (me *MultiMetricsEngine) RecordAdapterBidReceived(labels metrics.AdapterLabels, bidType openrtb_ext.BidType, hasAdm bool) {
 	for _, thisME := range *me {
@@ -238,6 +420,13 @@ func (me *MultiMetricsEngine) RecordAccountCacheResult(cacheResult metrics.Cache
 	}
 }
 
+// RecordCurrencyConversionCacheResult across all engines
+func (me *MultiMetricsEngine) RecordCurrencyConversionCacheResult(cacheResult metrics.CacheResult, inc int) {
+	for _, thisME := range *me {
+		thisME.RecordCurrencyConversionCacheResult(cacheResult, inc)
+	}
+}
+
 // RecordPrebidCacheRequestTime across all engines
 func (me *MultiMetricsEngine) RecordPrebidCacheRequestTime(success bool, length time.Duration) {
 	for _, thisME := range *me {
@@ -412,6 +601,22 @@ func (me *NilMetricsEngine) RecordAdapterRequest(labels metrics.AdapterLabels) {
 func (me *NilMetricsEngine) RecordAdapterConnections(bidderName openrtb_ext.BidderName, connWasReused bool, connWaitTime time.Duration) {
 }
 
+// RecordAdapterConnectionWarmup as a noop
+func (me *NilMetricsEngine) RecordAdapterConnectionWarmup(adapterName openrtb_ext.BidderName, result metrics.ConnectionWarmupResult) {
+}
+
+// RecordAdapterTLSHandshake as a noop
+func (me *NilMetricsEngine) RecordAdapterTLSHandshake(adapterName openrtb_ext.BidderName, resumed bool) {
+}
+
+// RecordAdapterHedgedRequest as a noop
+func (me *NilMetricsEngine) RecordAdapterHedgedRequest(adapterName openrtb_ext.BidderName, won bool) {
+}
+
+// RecordAdapterMaintenance as a noop
+func (me *NilMetricsEngine) RecordAdapterMaintenance(adapterName openrtb_ext.BidderName, reason metrics.BidderMaintenance) {
+}
+
 // RecordDNSTime as a noop
 func (me *NilMetricsEngine) RecordDNSTime(dnsLookupTime time.Duration) {
 }
@@ -424,6 +629,94 @@ func (me *NilMetricsEngine) RecordTLSHandshakeTime(tlsHandshakeTime time.Duratio
 func (me *NilMetricsEngine) RecordBidderServerResponseTime(bidderServerResponseTime time.Duration) {
 }
 
+// RecordBidderRequestPool as a noop
+func (me *NilMetricsEngine) RecordBidderRequestPool(runningWorkers int, queuedRequests int) {
+}
+
+// RecordAdapterResponseCacheResult as a noop
+func (me *NilMetricsEngine) RecordAdapterResponseCacheResult(adapterName openrtb_ext.BidderName, cacheResult metrics.CacheResult) {
+}
+
+// RecordBidderRequestShed as a noop
+func (me *NilMetricsEngine) RecordBidderRequestShed(adapterName openrtb_ext.BidderName) {
+}
+
+// RecordLoadShedding as a noop
+func (me *NilMetricsEngine) RecordLoadShedding(action metrics.LoadSheddingAction) {
+}
+
+// RecordLatencyBudgetStage as a noop
+func (me *NilMetricsEngine) RecordLatencyBudgetStage(stage metrics.LatencyBudgetStage, used time.Duration, overrun bool) {
+}
+
+// RecordTrafficShadow as a noop
+func (me *NilMetricsEngine) RecordTrafficShadow(status metrics.TrafficShadowStatus) {
+}
+
+// RecordTestRequest as a noop
+func (me *NilMetricsEngine) RecordTestRequest(requestType metrics.RequestType) {
+}
+
+// RecordWebhook as a noop
+func (me *NilMetricsEngine) RecordWebhook(status metrics.WebhookStatus) {
+}
+
+// RecordAuctionEventWebhook as a noop
+func (me *NilMetricsEngine) RecordAuctionEventWebhook(status metrics.WebhookStatus) {
+}
+
+// RecordCurrencyRatesFetch as a noop
+func (me *NilMetricsEngine) RecordCurrencyRatesFetch(status metrics.CurrencyRatesFetchStatus) {
+}
+
+// RecordCurrencyRatesStale as a noop
+func (me *NilMetricsEngine) RecordCurrencyRatesStale(stale bool) {
+}
+
+// RecordAdapterCanary as a noop
+func (me *NilMetricsEngine) RecordAdapterCanary(labels metrics.AdapterCanaryLabels, bids int, length time.Duration) {
+}
+
+// RecordAdapterPIIViolation as a noop
+func (me *NilMetricsEngine) RecordAdapterPIIViolation(labels metrics.AdapterPIIViolationLabels) {
+}
+
+// RecordAdapterFieldsWithheld as a noop
+func (me *NilMetricsEngine) RecordAdapterFieldsWithheld(adapterName openrtb_ext.BidderName, fields int) {
+}
+
+// RecordAdapterFloorConversion as a noop
+func (me *NilMetricsEngine) RecordAdapterFloorConversion(adapterName openrtb_ext.BidderName, success bool) {
+}
+
+// RecordIVT as a noop
+func (me *NilMetricsEngine) RecordIVT(reason metrics.IVTReason, action metrics.IVTAction) {
+}
+
+// RecordRequestLimitExceeded as a noop
+func (me *NilMetricsEngine) RecordRequestLimitExceeded(limit metrics.RequestLimit) {
+}
+
+// RecordRequestNormalization as a noop
+func (me *NilMetricsEngine) RecordRequestNormalization(fix metrics.RequestNormalization) {
+}
+
+// RecordAPIKey as a noop
+func (me *NilMetricsEngine) RecordAPIKey(status metrics.APIKeyStatus) {
+}
+
+// RecordRateLimit as a noop
+func (me *NilMetricsEngine) RecordRateLimit(limit metrics.RateLimit, outcome metrics.RateLimitOutcome) {
+}
+
+// RecordGeoLookup as a noop
+func (me *NilMetricsEngine) RecordGeoLookup(status metrics.GeoLookupStatus, duration time.Duration) {
+}
+
+// RecordContentClassification as a noop
+func (me *NilMetricsEngine) RecordContentClassification(status metrics.ContentClassificationStatus, duration time.Duration) {
+}
+
 // RecordAdapterBidReceived as a noop
 func (me *NilMetricsEngine) RecordAdapterBidReceived(labels metrics.AdapterLabels, bidType openrtb_ext.BidType, hasAdm bool) {
 }
@@ -468,6 +761,10 @@ func (me *NilMetricsEngine) RecordStoredImpCacheResult(cacheResult metrics.Cache
 func (me *NilMetricsEngine) RecordAccountCacheResult(cacheResult metrics.CacheResult, inc int) {
 }
 
+// RecordCurrencyConversionCacheResult as a noop
+func (me *NilMetricsEngine) RecordCurrencyConversionCacheResult(cacheResult metrics.CacheResult, inc int) {
+}
+
 // RecordPrebidCacheRequestTime as a noop
 func (me *NilMetricsEngine) RecordPrebidCacheRequestTime(success bool, length time.Duration) {
 }
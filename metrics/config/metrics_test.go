@@ -142,6 +142,8 @@ func TestMultiMetricsEngine(t *testing.T) {
 	metricsEngine.RecordStoredReqCacheResult(metrics.CacheHit, 4)
 	metricsEngine.RecordStoredImpCacheResult(metrics.CacheHit, 5)
 	metricsEngine.RecordAccountCacheResult(metrics.CacheHit, 6)
+	metricsEngine.RecordCurrencyConversionCacheResult(metrics.CacheMiss, 7)
+	metricsEngine.RecordCurrencyConversionCacheResult(metrics.CacheHit, 8)
 
 	metricsEngine.RecordAdapterGDPRRequestBlocked(openrtb_ext.BidderAppnexus)
 
@@ -187,6 +189,8 @@ func TestMultiMetricsEngine(t *testing.T) {
 	VerifyMetrics(t, "StoredReqCache.Hit", goEngine.StoredReqCacheMeter[metrics.CacheHit].Count(), 4)
 	VerifyMetrics(t, "StoredImpCache.Hit", goEngine.StoredImpCacheMeter[metrics.CacheHit].Count(), 5)
 	VerifyMetrics(t, "AccountCache.Hit", goEngine.AccountCacheMeter[metrics.CacheHit].Count(), 6)
+	VerifyMetrics(t, "CurrencyConversionCache.Miss", goEngine.CurrencyConversionCacheMeter[metrics.CacheMiss].Count(), 7)
+	VerifyMetrics(t, "CurrencyConversionCache.Hit", goEngine.CurrencyConversionCacheMeter[metrics.CacheHit].Count(), 8)
 
 	VerifyMetrics(t, "AdapterMetrics.appNexus.GDPRRequestBlocked", goEngine.AdapterMetrics[strings.ToLower(string(openrtb_ext.BidderAppnexus))].GDPRRequestBlocked.Count(), 1)
 
@@ -6,8 +6,8 @@ import (
 	"sync"
 	"time"
 
-	"github.com/golang/glog"
 	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/logger"
 	"github.com/prebid/prebid-server/v2/openrtb_ext"
 	metrics "github.com/rcrowley/go-metrics"
 )
@@ -31,9 +31,30 @@ type Metrics struct {
 	StoredReqCacheMeter            map[CacheResult]metrics.Meter
 	StoredImpCacheMeter            map[CacheResult]metrics.Meter
 	AccountCacheMeter              map[CacheResult]metrics.Meter
+	CurrencyConversionCacheMeter   map[CacheResult]metrics.Meter
 	DNSLookupTimer                 metrics.Timer
 	TLSHandshakeTimer              metrics.Timer
 	BidderServerResponseTimer      metrics.Timer
+	BidderRequestPoolRunning       metrics.Gauge
+	BidderRequestPoolQueued        metrics.Gauge
+	BidderRequestShedMeter         metrics.Meter
+	AdapterResponseCacheMeter      map[CacheResult]metrics.Meter
+	LoadSheddingMeters             map[LoadSheddingAction]metrics.Meter
+	IVTMeters                      map[IVTReason]map[IVTAction]metrics.Meter
+	RequestLimitMeters             map[RequestLimit]metrics.Meter
+	RequestNormalizationMeters     map[RequestNormalization]metrics.Meter
+	APIKeyMeters                   map[APIKeyStatus]metrics.Meter
+	RateLimitMeters                map[RateLimit]map[RateLimitOutcome]metrics.Meter
+	GeoLookupTimers                map[GeoLookupStatus]metrics.Timer
+	ContentClassificationTimers    map[ContentClassificationStatus]metrics.Timer
+	LatencyBudgetStageTimers       map[LatencyBudgetStage]metrics.Timer
+	LatencyBudgetOverrunMeters     map[LatencyBudgetStage]metrics.Meter
+	TrafficShadowMeters            map[TrafficShadowStatus]metrics.Meter
+	TestRequestMeters              map[RequestType]metrics.Meter
+	WebhookMeters                  map[WebhookStatus]metrics.Meter
+	AuctionEventWebhookMeters      map[WebhookStatus]metrics.Meter
+	CurrencyRatesFetchMeters       map[CurrencyRatesFetchStatus]metrics.Meter
+	CurrencyRatesStale             metrics.Gauge
 	StoredResponsesMeter           metrics.Meter
 
 	// Metrics for OpenRTB requests specifically
@@ -99,6 +120,12 @@ type AdapterMetrics struct {
 	ConnCreated        metrics.Counter
 	ConnReused         metrics.Counter
 	ConnWaitTime       metrics.Timer
+	ConnWarmupMeters   map[ConnectionWarmupResult]metrics.Meter
+	TLSHandshakeFull   metrics.Counter
+	TLSResumed         metrics.Counter
+	HedgesWon          metrics.Meter
+	HedgesLost         metrics.Meter
+	MaintenanceMeters  map[BidderMaintenance]metrics.Meter
 	GDPRRequestBlocked metrics.Meter
 
 	BidValidationCreativeSizeErrorMeter metrics.Meter
@@ -172,6 +199,7 @@ func NewBlankMetrics(registry metrics.Registry, exchanges []string, disabledMetr
 		StoredReqCacheMeter:            make(map[CacheResult]metrics.Meter),
 		StoredImpCacheMeter:            make(map[CacheResult]metrics.Meter),
 		AccountCacheMeter:              make(map[CacheResult]metrics.Meter),
+		CurrencyConversionCacheMeter:   make(map[CacheResult]metrics.Meter),
 		AmpNoCookieMeter:               blankMeter,
 		CookieSyncMeter:                blankMeter,
 		CookieSyncStatusMeter:          make(map[CookieSyncStatus]metrics.Meter),
@@ -208,8 +236,76 @@ func NewBlankMetrics(registry metrics.Registry, exchanges []string, disabledMetr
 		exchanges: exchanges,
 		modules:   getModuleNames(moduleStageNames),
 
-		OverheadTimer:             makeBlankOverheadTimerMetrics(),
-		BidderServerResponseTimer: blankTimer,
+		OverheadTimer:               makeBlankOverheadTimerMetrics(),
+		BidderServerResponseTimer:   blankTimer,
+		BidderRequestPoolRunning:    metrics.NilGauge{},
+		BidderRequestPoolQueued:     metrics.NilGauge{},
+		BidderRequestShedMeter:      blankMeter,
+		AdapterResponseCacheMeter:   make(map[CacheResult]metrics.Meter),
+		LoadSheddingMeters:          make(map[LoadSheddingAction]metrics.Meter),
+		IVTMeters:                   make(map[IVTReason]map[IVTAction]metrics.Meter),
+		RequestLimitMeters:          make(map[RequestLimit]metrics.Meter),
+		RequestNormalizationMeters:  make(map[RequestNormalization]metrics.Meter),
+		APIKeyMeters:                make(map[APIKeyStatus]metrics.Meter),
+		RateLimitMeters:             make(map[RateLimit]map[RateLimitOutcome]metrics.Meter),
+		GeoLookupTimers:             make(map[GeoLookupStatus]metrics.Timer),
+		ContentClassificationTimers: make(map[ContentClassificationStatus]metrics.Timer),
+		LatencyBudgetStageTimers:    make(map[LatencyBudgetStage]metrics.Timer),
+		LatencyBudgetOverrunMeters:  make(map[LatencyBudgetStage]metrics.Meter),
+		TrafficShadowMeters:         make(map[TrafficShadowStatus]metrics.Meter),
+		TestRequestMeters:           make(map[RequestType]metrics.Meter),
+		WebhookMeters:               make(map[WebhookStatus]metrics.Meter),
+		AuctionEventWebhookMeters:   make(map[WebhookStatus]metrics.Meter),
+		CurrencyRatesFetchMeters:    make(map[CurrencyRatesFetchStatus]metrics.Meter),
+		CurrencyRatesStale:          metrics.NilGauge{},
+	}
+
+	for _, action := range LoadSheddingActions() {
+		newMetrics.LoadSheddingMeters[action] = blankMeter
+	}
+	for _, reason := range IVTReasons() {
+		newMetrics.IVTMeters[reason] = make(map[IVTAction]metrics.Meter)
+		for _, action := range IVTActions() {
+			newMetrics.IVTMeters[reason][action] = blankMeter
+		}
+	}
+	for _, limit := range RequestLimits() {
+		newMetrics.RequestLimitMeters[limit] = blankMeter
+	}
+	for _, fix := range RequestNormalizations() {
+		newMetrics.RequestNormalizationMeters[fix] = blankMeter
+	}
+	for _, status := range APIKeyStatuses() {
+		newMetrics.APIKeyMeters[status] = blankMeter
+	}
+	for _, limit := range RateLimits() {
+		newMetrics.RateLimitMeters[limit] = make(map[RateLimitOutcome]metrics.Meter)
+		for _, outcome := range RateLimitOutcomes() {
+			newMetrics.RateLimitMeters[limit][outcome] = blankMeter
+		}
+	}
+	for _, status := range GeoLookupStatuses() {
+		newMetrics.GeoLookupTimers[status] = blankTimer
+	}
+	for _, status := range ContentClassificationStatuses() {
+		newMetrics.ContentClassificationTimers[status] = blankTimer
+	}
+	for _, stage := range LatencyBudgetStages() {
+		newMetrics.LatencyBudgetStageTimers[stage] = blankTimer
+		newMetrics.LatencyBudgetOverrunMeters[stage] = blankMeter
+	}
+	for _, status := range TrafficShadowStatuses() {
+		newMetrics.TrafficShadowMeters[status] = blankMeter
+	}
+	for _, requestType := range RequestTypes() {
+		newMetrics.TestRequestMeters[requestType] = blankMeter
+	}
+	for _, status := range WebhookStatuses() {
+		newMetrics.WebhookMeters[status] = blankMeter
+		newMetrics.AuctionEventWebhookMeters[status] = blankMeter
+	}
+	for _, status := range CurrencyRatesFetchStatuses() {
+		newMetrics.CurrencyRatesFetchMeters[status] = blankMeter
 	}
 
 	for _, a := range exchanges {
@@ -231,6 +327,8 @@ func NewBlankMetrics(registry metrics.Registry, exchanges []string, disabledMetr
 		newMetrics.StoredReqCacheMeter[c] = blankMeter
 		newMetrics.StoredImpCacheMeter[c] = blankMeter
 		newMetrics.AccountCacheMeter[c] = blankMeter
+		newMetrics.CurrencyConversionCacheMeter[c] = blankMeter
+		newMetrics.AdapterResponseCacheMeter[c] = blankMeter
 	}
 
 	for _, v := range TCFVersions() {
@@ -301,6 +399,55 @@ func NewMetrics(registry metrics.Registry, exchanges []openrtb_ext.BidderName, d
 	newMetrics.StoredResponsesMeter = metrics.GetOrRegisterMeter("stored_responses", registry)
 	newMetrics.OverheadTimer = makeOverheadTimerMetrics(registry)
 	newMetrics.BidderServerResponseTimer = metrics.GetOrRegisterTimer("bidder_server_response_time_seconds", registry)
+	newMetrics.BidderRequestPoolRunning = metrics.GetOrRegisterGauge("bidder_request_pool.running_workers", registry)
+	newMetrics.BidderRequestPoolQueued = metrics.GetOrRegisterGauge("bidder_request_pool.queued_requests", registry)
+	newMetrics.BidderRequestShedMeter = metrics.GetOrRegisterMeter("bidder_request_pool.shed_requests", registry)
+	for _, action := range LoadSheddingActions() {
+		newMetrics.LoadSheddingMeters[action] = metrics.GetOrRegisterMeter(fmt.Sprintf("load_shedding.%s", action), registry)
+	}
+	for _, reason := range IVTReasons() {
+		for _, action := range IVTActions() {
+			newMetrics.IVTMeters[reason][action] = metrics.GetOrRegisterMeter(fmt.Sprintf("ivt.%s.%s", reason, action), registry)
+		}
+	}
+	for _, limit := range RequestLimits() {
+		newMetrics.RequestLimitMeters[limit] = metrics.GetOrRegisterMeter(fmt.Sprintf("request_limits.%s.exceeded", limit), registry)
+	}
+	for _, fix := range RequestNormalizations() {
+		newMetrics.RequestNormalizationMeters[fix] = metrics.GetOrRegisterMeter(fmt.Sprintf("request_normalizations.%s", fix), registry)
+	}
+	for _, status := range APIKeyStatuses() {
+		newMetrics.APIKeyMeters[status] = metrics.GetOrRegisterMeter(fmt.Sprintf("api_keys.%s", status), registry)
+	}
+	for _, limit := range RateLimits() {
+		for _, outcome := range RateLimitOutcomes() {
+			newMetrics.RateLimitMeters[limit][outcome] = metrics.GetOrRegisterMeter(fmt.Sprintf("rate_limits.%s.%s", limit, outcome), registry)
+		}
+	}
+	for _, status := range GeoLookupStatuses() {
+		newMetrics.GeoLookupTimers[status] = metrics.GetOrRegisterTimer(fmt.Sprintf("geolocation.lookup.%s", status), registry)
+	}
+	for _, status := range ContentClassificationStatuses() {
+		newMetrics.ContentClassificationTimers[status] = metrics.GetOrRegisterTimer(fmt.Sprintf("content_classification.%s", status), registry)
+	}
+	for _, stage := range LatencyBudgetStages() {
+		newMetrics.LatencyBudgetStageTimers[stage] = metrics.GetOrRegisterTimer(fmt.Sprintf("latency_budget.%s.used", stage), registry)
+		newMetrics.LatencyBudgetOverrunMeters[stage] = metrics.GetOrRegisterMeter(fmt.Sprintf("latency_budget.%s.overrun", stage), registry)
+	}
+	for _, status := range TrafficShadowStatuses() {
+		newMetrics.TrafficShadowMeters[status] = metrics.GetOrRegisterMeter(fmt.Sprintf("traffic_shadow.%s", status), registry)
+	}
+	for _, requestType := range RequestTypes() {
+		newMetrics.TestRequestMeters[requestType] = metrics.GetOrRegisterMeter(fmt.Sprintf("test_requests.%s", requestType), registry)
+	}
+	for _, status := range WebhookStatuses() {
+		newMetrics.WebhookMeters[status] = metrics.GetOrRegisterMeter(fmt.Sprintf("webhooks.%s", status), registry)
+		newMetrics.AuctionEventWebhookMeters[status] = metrics.GetOrRegisterMeter(fmt.Sprintf("auction_event_webhooks.%s", status), registry)
+	}
+	for _, status := range CurrencyRatesFetchStatuses() {
+		newMetrics.CurrencyRatesFetchMeters[status] = metrics.GetOrRegisterMeter(fmt.Sprintf("currency_rates.%s", status), registry)
+	}
+	newMetrics.CurrencyRatesStale = metrics.GetOrRegisterGauge("currency_rates.stale", registry)
 
 	for _, dt := range StoredDataTypes() {
 		for _, ft := range StoredDataFetchTypes() {
@@ -351,6 +498,8 @@ func NewMetrics(registry metrics.Registry, exchanges []openrtb_ext.BidderName, d
 		newMetrics.StoredReqCacheMeter[cacheRes] = metrics.GetOrRegisterMeter(fmt.Sprintf("stored_request_cache_%s", string(cacheRes)), registry)
 		newMetrics.StoredImpCacheMeter[cacheRes] = metrics.GetOrRegisterMeter(fmt.Sprintf("stored_imp_cache_%s", string(cacheRes)), registry)
 		newMetrics.AccountCacheMeter[cacheRes] = metrics.GetOrRegisterMeter(fmt.Sprintf("account_cache_%s", string(cacheRes)), registry)
+		newMetrics.CurrencyConversionCacheMeter[cacheRes] = metrics.GetOrRegisterMeter(fmt.Sprintf("currency_conversion_cache_%s", string(cacheRes)), registry)
+		newMetrics.AdapterResponseCacheMeter[cacheRes] = metrics.GetOrRegisterMeter(fmt.Sprintf("adapter_response_cache_%s", string(cacheRes)), registry)
 	}
 
 	newMetrics.RequestsQueueTimer["video"][true] = metrics.GetOrRegisterTimer("queued_requests.video.accepted", registry)
@@ -400,11 +549,23 @@ func makeBlankAdapterMetrics(disabledMetrics config.DisabledMetrics) *AdapterMet
 		BidsReceivedMeter: blankMeter,
 		PanicMeter:        blankMeter,
 		MarkupMetrics:     makeBlankBidMarkupMetrics(),
+		ConnWarmupMeters:  make(map[ConnectionWarmupResult]metrics.Meter),
+		HedgesWon:         blankMeter,
+		HedgesLost:        blankMeter,
+		MaintenanceMeters: make(map[BidderMaintenance]metrics.Meter),
+	}
+	for _, result := range ConnectionWarmupResults() {
+		newAdapter.ConnWarmupMeters[result] = blankMeter
+	}
+	for _, reason := range BidderMaintenanceReasons() {
+		newAdapter.MaintenanceMeters[reason] = blankMeter
 	}
 	if !disabledMetrics.AdapterConnectionMetrics {
 		newAdapter.ConnCreated = metrics.NilCounter{}
 		newAdapter.ConnReused = metrics.NilCounter{}
 		newAdapter.ConnWaitTime = &metrics.NilTimer{}
+		newAdapter.TLSHandshakeFull = metrics.NilCounter{}
+		newAdapter.TLSResumed = metrics.NilCounter{}
 	}
 	if !disabledMetrics.AdapterGDPRRequestBlocked {
 		newAdapter.GDPRRequestBlocked = blankMeter
@@ -477,6 +638,16 @@ func registerAdapterMetrics(registry metrics.Registry, adapterOrAccount string,
 	am.ConnCreated = metrics.GetOrRegisterCounter(fmt.Sprintf("%[1]s.%[2]s.connections_created", adapterOrAccount, exchange), registry)
 	am.ConnReused = metrics.GetOrRegisterCounter(fmt.Sprintf("%[1]s.%[2]s.connections_reused", adapterOrAccount, exchange), registry)
 	am.ConnWaitTime = metrics.GetOrRegisterTimer(fmt.Sprintf("%[1]s.%[2]s.connection_wait_time", adapterOrAccount, exchange), registry)
+	am.TLSHandshakeFull = metrics.GetOrRegisterCounter(fmt.Sprintf("%[1]s.%[2]s.tls_handshakes.full", adapterOrAccount, exchange), registry)
+	am.TLSResumed = metrics.GetOrRegisterCounter(fmt.Sprintf("%[1]s.%[2]s.tls_handshakes.resumed", adapterOrAccount, exchange), registry)
+	am.HedgesWon = metrics.GetOrRegisterMeter(fmt.Sprintf("%[1]s.%[2]s.hedged_requests.won", adapterOrAccount, exchange), registry)
+	am.HedgesLost = metrics.GetOrRegisterMeter(fmt.Sprintf("%[1]s.%[2]s.hedged_requests.lost", adapterOrAccount, exchange), registry)
+	for result := range am.ConnWarmupMeters {
+		am.ConnWarmupMeters[result] = metrics.GetOrRegisterMeter(fmt.Sprintf("%s.%s.connection_warmup.%s", adapterOrAccount, exchange, result), registry)
+	}
+	for reason := range am.MaintenanceMeters {
+		am.MaintenanceMeters[reason] = metrics.GetOrRegisterMeter(fmt.Sprintf("%s.%s.maintenance.%s", adapterOrAccount, exchange, reason), registry)
+	}
 	for err := range am.ErrorMeters {
 		am.ErrorMeters[err] = metrics.GetOrRegisterMeter(fmt.Sprintf("%s.%s.requests.%s", adapterOrAccount, exchange, err), registry)
 	}
@@ -680,7 +851,7 @@ func (me *Metrics) RecordAdapterPanic(labels AdapterLabels) {
 	lowerCaseAdapterName := strings.ToLower(adapterStr)
 	am, ok := me.AdapterMetrics[lowerCaseAdapterName]
 	if !ok {
-		glog.Errorf("Trying to run adapter metrics on %s: adapter metrics not found", adapterStr)
+		logger.Errorf("Trying to run adapter metrics on %s: adapter metrics not found", adapterStr)
 		return
 	}
 	am.PanicMeter.Mark(1)
@@ -692,7 +863,7 @@ func (me *Metrics) RecordAdapterRequest(labels AdapterLabels) {
 	lowerCaseAdapter := strings.ToLower(adapterStr)
 	am, ok := me.AdapterMetrics[lowerCaseAdapter]
 	if !ok {
-		glog.Errorf("Trying to run adapter metrics on %s: adapter metrics not found", adapterStr)
+		logger.Errorf("Trying to run adapter metrics on %s: adapter metrics not found", adapterStr)
 		return
 	}
 
@@ -709,7 +880,7 @@ func (me *Metrics) RecordAdapterRequest(labels AdapterLabels) {
 			aam.GotBidsMeter.Mark(1)
 		}
 	default:
-		glog.Warningf("No go-metrics logged for AdapterBids value: %s", labels.AdapterBids)
+		logger.Warningf("No go-metrics logged for AdapterBids value: %s", labels.AdapterBids)
 	}
 	for errType := range labels.AdapterErrors {
 		am.ErrorMeters[errType].Mark(1)
@@ -732,7 +903,7 @@ func (me *Metrics) RecordAdapterConnections(adapterName openrtb_ext.BidderName,
 	lowerCaseAdapterName := strings.ToLower(string(adapterName))
 	am, ok := me.AdapterMetrics[lowerCaseAdapterName]
 	if !ok {
-		glog.Errorf("Trying to log adapter connection metrics for %s: adapter not found", string(adapterName))
+		logger.Errorf("Trying to log adapter connection metrics for %s: adapter not found", string(adapterName))
 		return
 	}
 
@@ -744,6 +915,70 @@ func (me *Metrics) RecordAdapterConnections(adapterName openrtb_ext.BidderName,
 	am.ConnWaitTime.Update(connWaitTime)
 }
 
+// RecordAdapterConnectionWarmup implements a part of the MetricsEngine interface.
+func (me *Metrics) RecordAdapterConnectionWarmup(adapterName openrtb_ext.BidderName, result ConnectionWarmupResult) {
+	lowerCaseAdapterName := strings.ToLower(string(adapterName))
+	am, ok := me.AdapterMetrics[lowerCaseAdapterName]
+	if !ok {
+		logger.Errorf("Trying to log adapter connection warmup metrics for %s: adapter not found", string(adapterName))
+		return
+	}
+	if meter, ok := am.ConnWarmupMeters[result]; ok {
+		meter.Mark(1)
+	}
+}
+
+// RecordAdapterTLSHandshake implements a part of the MetricsEngine interface. Records whether the TLS
+// handshake of a new connection to the bidder resumed a cached session.
+func (me *Metrics) RecordAdapterTLSHandshake(adapterName openrtb_ext.BidderName, resumed bool) {
+	if me.MetricsDisabled.AdapterConnectionMetrics {
+		return
+	}
+	lowerCaseAdapterName := strings.ToLower(string(adapterName))
+	am, ok := me.AdapterMetrics[lowerCaseAdapterName]
+	if !ok {
+		logger.Errorf("Trying to log adapter TLS handshake metrics for %s: adapter not found", string(adapterName))
+		return
+	}
+
+	if resumed {
+		am.TLSResumed.Inc(1)
+	} else {
+		am.TLSHandshakeFull.Inc(1)
+	}
+}
+
+// RecordAdapterHedgedRequest implements a part of the MetricsEngine interface. Records a request sent to
+// the bidder a second time, and whether its answer was the one used.
+func (me *Metrics) RecordAdapterHedgedRequest(adapterName openrtb_ext.BidderName, won bool) {
+	lowerCaseAdapterName := strings.ToLower(string(adapterName))
+	am, ok := me.AdapterMetrics[lowerCaseAdapterName]
+	if !ok {
+		logger.Errorf("Trying to log adapter hedged request metrics for %s: adapter not found", string(adapterName))
+		return
+	}
+
+	if won {
+		am.HedgesWon.Mark(1)
+	} else {
+		am.HedgesLost.Mark(1)
+	}
+}
+
+// RecordAdapterMaintenance implements a part of the MetricsEngine interface. Records an auction the bidder
+// was left out of for maintenance.
+func (me *Metrics) RecordAdapterMaintenance(adapterName openrtb_ext.BidderName, reason BidderMaintenance) {
+	lowerCaseAdapterName := strings.ToLower(string(adapterName))
+	am, ok := me.AdapterMetrics[lowerCaseAdapterName]
+	if !ok {
+		logger.Errorf("Trying to log adapter maintenance metrics for %s: adapter not found", string(adapterName))
+		return
+	}
+	if meter, ok := am.MaintenanceMeters[reason]; ok {
+		meter.Mark(1)
+	}
+}
+
 func (me *Metrics) RecordDNSTime(dnsLookupTime time.Duration) {
 	me.DNSLookupTimer.Update(dnsLookupTime)
 }
@@ -756,6 +991,171 @@ func (me *Metrics) RecordBidderServerResponseTime(bidderServerResponseTime time.
 	me.BidderServerResponseTimer.Update(bidderServerResponseTime)
 }
 
+// RecordBidderRequestPool implements a part of the MetricsEngine interface.
+func (me *Metrics) RecordBidderRequestPool(runningWorkers int, queuedRequests int) {
+	me.BidderRequestPoolRunning.Update(int64(runningWorkers))
+	me.BidderRequestPoolQueued.Update(int64(queuedRequests))
+}
+
+// RecordAdapterResponseCacheResult implements a part of the MetricsEngine interface.
+func (me *Metrics) RecordAdapterResponseCacheResult(adapterName openrtb_ext.BidderName, cacheResult CacheResult) {
+	me.AdapterResponseCacheMeter[cacheResult].Mark(1)
+}
+
+// RecordBidderRequestShed implements a part of the MetricsEngine interface.
+func (me *Metrics) RecordBidderRequestShed(adapterName openrtb_ext.BidderName) {
+	me.BidderRequestShedMeter.Mark(1)
+}
+
+// RecordLoadShedding implements a part of the MetricsEngine interface.
+func (me *Metrics) RecordLoadShedding(action LoadSheddingAction) {
+	if meter, ok := me.LoadSheddingMeters[action]; ok {
+		meter.Mark(1)
+	}
+}
+
+// RecordIVT implements a part of the MetricsEngine interface.
+func (me *Metrics) RecordIVT(reason IVTReason, action IVTAction) {
+	if meter, ok := me.IVTMeters[reason][action]; ok {
+		meter.Mark(1)
+	}
+}
+
+// RecordRequestLimitExceeded implements a part of the MetricsEngine interface.
+func (me *Metrics) RecordRequestLimitExceeded(limit RequestLimit) {
+	if meter, ok := me.RequestLimitMeters[limit]; ok {
+		meter.Mark(1)
+	}
+}
+
+// RecordRequestNormalization implements a part of the MetricsEngine interface.
+func (me *Metrics) RecordRequestNormalization(fix RequestNormalization) {
+	if meter, ok := me.RequestNormalizationMeters[fix]; ok {
+		meter.Mark(1)
+	}
+}
+
+// RecordAPIKey implements a part of the MetricsEngine interface.
+func (me *Metrics) RecordAPIKey(status APIKeyStatus) {
+	if meter, ok := me.APIKeyMeters[status]; ok {
+		meter.Mark(1)
+	}
+}
+
+// RecordRateLimit implements a part of the MetricsEngine interface.
+func (me *Metrics) RecordRateLimit(limit RateLimit, outcome RateLimitOutcome) {
+	if meter, ok := me.RateLimitMeters[limit][outcome]; ok {
+		meter.Mark(1)
+	}
+}
+
+// RecordGeoLookup implements a part of the MetricsEngine interface.
+func (me *Metrics) RecordGeoLookup(status GeoLookupStatus, duration time.Duration) {
+	if timer, ok := me.GeoLookupTimers[status]; ok {
+		timer.Update(duration)
+	}
+}
+
+// RecordContentClassification implements a part of the MetricsEngine interface.
+func (me *Metrics) RecordContentClassification(status ContentClassificationStatus, duration time.Duration) {
+	if timer, ok := me.ContentClassificationTimers[status]; ok {
+		timer.Update(duration)
+	}
+}
+
+// RecordLatencyBudgetStage implements a part of the MetricsEngine interface.
+func (me *Metrics) RecordLatencyBudgetStage(stage LatencyBudgetStage, used time.Duration, overrun bool) {
+	if timer, ok := me.LatencyBudgetStageTimers[stage]; ok {
+		timer.Update(used)
+	}
+	if meter, ok := me.LatencyBudgetOverrunMeters[stage]; ok && overrun {
+		meter.Mark(1)
+	}
+}
+
+// RecordTrafficShadow implements a part of the MetricsEngine interface.
+func (me *Metrics) RecordTrafficShadow(status TrafficShadowStatus) {
+	if meter, ok := me.TrafficShadowMeters[status]; ok {
+		meter.Mark(1)
+	}
+}
+
+// RecordTestRequest implements a part of the MetricsEngine interface. Records a request with test=1, which
+// is kept out of the request metrics when test traffic is kept apart from production traffic.
+func (me *Metrics) RecordTestRequest(requestType RequestType) {
+	if meter, ok := me.TestRequestMeters[requestType]; ok {
+		meter.Mark(1)
+	}
+}
+
+// RecordWebhook implements a part of the MetricsEngine interface.
+func (me *Metrics) RecordWebhook(status WebhookStatus) {
+	if meter, ok := me.WebhookMeters[status]; ok {
+		meter.Mark(1)
+	}
+}
+
+// RecordAuctionEventWebhook implements a part of the MetricsEngine interface.
+func (me *Metrics) RecordAuctionEventWebhook(status WebhookStatus) {
+	if meter, ok := me.AuctionEventWebhookMeters[status]; ok {
+		meter.Mark(1)
+	}
+}
+
+// RecordCurrencyRatesFetch implements a part of the MetricsEngine interface.
+func (me *Metrics) RecordCurrencyRatesFetch(status CurrencyRatesFetchStatus) {
+	if meter, ok := me.CurrencyRatesFetchMeters[status]; ok {
+		meter.Mark(1)
+	}
+}
+
+// RecordCurrencyRatesStale implements a part of the MetricsEngine interface.
+func (me *Metrics) RecordCurrencyRatesStale(stale bool) {
+	if stale {
+		me.CurrencyRatesStale.Update(1)
+	} else {
+		me.CurrencyRatesStale.Update(0)
+	}
+}
+
+// RecordAdapterCanary implements a part of the MetricsEngine interface. The metrics are registered the
+// first time they're recorded, since only the adapters which have a canary configuration record them.
+func (me *Metrics) RecordAdapterCanary(labels AdapterCanaryLabels, bids int, length time.Duration) {
+	prefix := fmt.Sprintf("adapter.%s.canary.%s", strings.ToLower(string(labels.Adapter)), labels.Version)
+	if labels.Success {
+		metrics.GetOrRegisterMeter(prefix+".requests.ok", me.MetricsRegistry).Mark(1)
+	} else {
+		metrics.GetOrRegisterMeter(prefix+".requests.err", me.MetricsRegistry).Mark(1)
+	}
+	metrics.GetOrRegisterMeter(prefix+".bids", me.MetricsRegistry).Mark(int64(bids))
+	metrics.GetOrRegisterTimer(prefix+".request_time", me.MetricsRegistry).Update(length)
+}
+
+// RecordAdapterPIIViolation implements a part of the MetricsEngine interface. The metrics are registered the
+// first time they're recorded, since only the requests sampled by the PII scanner record them.
+func (me *Metrics) RecordAdapterPIIViolation(labels AdapterPIIViolationLabels) {
+	name := fmt.Sprintf("adapter.%s.pii_violations.%s.%s", strings.ToLower(string(labels.Adapter)), labels.Source, labels.Type)
+	metrics.GetOrRegisterMeter(name, me.MetricsRegistry).Mark(1)
+}
+
+// RecordAdapterFieldsWithheld implements a part of the MetricsEngine interface. The metrics are registered the
+// first time they're recorded, since only the bidders accounts withhold fields from record them.
+func (me *Metrics) RecordAdapterFieldsWithheld(adapterName openrtb_ext.BidderName, fields int) {
+	name := fmt.Sprintf("adapter.%s.fields_withheld", strings.ToLower(string(adapterName)))
+	metrics.GetOrRegisterMeter(name, me.MetricsRegistry).Mark(int64(fields))
+}
+
+// RecordAdapterFloorConversion implements a part of the MetricsEngine interface. The metrics are registered the
+// first time they're recorded, since only the bidders which take floors in another currency record them.
+func (me *Metrics) RecordAdapterFloorConversion(adapterName openrtb_ext.BidderName, success bool) {
+	outcome := "success"
+	if !success {
+		outcome = "failure"
+	}
+	name := fmt.Sprintf("adapter.%s.floor_conversions.%s", strings.ToLower(string(adapterName)), outcome)
+	metrics.GetOrRegisterMeter(name, me.MetricsRegistry).Mark(1)
+}
+
 // RecordAdapterBidReceived implements a part of the MetricsEngine interface.
 // This tracks how many bids from each Bidder use `adm` vs. `nurl.
 func (me *Metrics) RecordAdapterBidReceived(labels AdapterLabels, bidType openrtb_ext.BidType, hasAdm bool) {
@@ -763,7 +1163,7 @@ func (me *Metrics) RecordAdapterBidReceived(labels AdapterLabels, bidType openrt
 	lowerCaseAdapterName := strings.ToLower(adapterStr)
 	am, ok := me.AdapterMetrics[lowerCaseAdapterName]
 	if !ok {
-		glog.Errorf("Trying to run adapter bid metrics on %s: adapter metrics not found", adapterStr)
+		logger.Errorf("Trying to run adapter bid metrics on %s: adapter metrics not found", adapterStr)
 		return
 	}
 
@@ -781,7 +1181,7 @@ func (me *Metrics) RecordAdapterBidReceived(labels AdapterLabels, bidType openrt
 			metricsForType.NurlMeter.Mark(1)
 		}
 	} else {
-		glog.Errorf("bid/adm metrics map entry does not exist for type %s. This is a bug, and should be reported.", bidType)
+		logger.Errorf("bid/adm metrics map entry does not exist for type %s. This is a bug, and should be reported.", bidType)
 	}
 }
 
@@ -791,7 +1191,7 @@ func (me *Metrics) RecordAdapterPrice(labels AdapterLabels, cpm float64) {
 	lowercaseAdapter := strings.ToLower(adapterStr)
 	am, ok := me.AdapterMetrics[lowercaseAdapter]
 	if !ok {
-		glog.Errorf("Trying to run adapter price metrics on %s: adapter metrics not found", adapterStr)
+		logger.Errorf("Trying to run adapter price metrics on %s: adapter metrics not found", adapterStr)
 		return
 	}
 	// Adapter metrics
@@ -808,7 +1208,7 @@ func (me *Metrics) RecordAdapterTime(labels AdapterLabels, length time.Duration)
 	lowercaseAdapter := strings.ToLower(adapterStr)
 	am, ok := me.AdapterMetrics[lowercaseAdapter]
 	if !ok {
-		glog.Errorf("Trying to run adapter latency metrics on %s: adapter metrics not found", string(labels.Adapter))
+		logger.Errorf("Trying to run adapter latency metrics on %s: adapter metrics not found", string(labels.Adapter))
 		return
 	}
 	// Adapter metrics
@@ -876,6 +1276,12 @@ func (me *Metrics) RecordAccountCacheResult(cacheResult CacheResult, inc int) {
 	me.AccountCacheMeter[cacheResult].Mark(int64(inc))
 }
 
+// RecordCurrencyConversionCacheResult implements a part of the MetricsEngine interface. Records the
+// hits and misses of the currency rates memoized for an auction.
+func (me *Metrics) RecordCurrencyConversionCacheResult(cacheResult CacheResult, inc int) {
+	me.CurrencyConversionCacheMeter[cacheResult].Mark(int64(inc))
+}
+
 // RecordPrebidCacheRequestTime implements a part of the MetricsEngine interface. Records the
 // amount of time taken to store the auction result in Prebid Cache.
 func (me *Metrics) RecordPrebidCacheRequestTime(success bool, length time.Duration) {
@@ -934,7 +1340,7 @@ func (me *Metrics) RecordAdapterGDPRRequestBlocked(adapterName openrtb_ext.Bidde
 
 	am, ok := me.AdapterMetrics[strings.ToLower(adapterStr)]
 	if !ok {
-		glog.Errorf("Trying to log adapter GDPR request blocked metric for %s: adapter not found", adapterStr)
+		logger.Errorf("Trying to log adapter GDPR request blocked metric for %s: adapter not found", adapterStr)
 		return
 	}
 
@@ -957,7 +1363,7 @@ func (me *Metrics) RecordBidValidationCreativeSizeError(adapter openrtb_ext.Bidd
 	adapterStr := string(adapter)
 	am, ok := me.AdapterMetrics[strings.ToLower(adapterStr)]
 	if !ok {
-		glog.Errorf("Trying to run adapter metrics on %s: adapter metrics not found", adapterStr)
+		logger.Errorf("Trying to run adapter metrics on %s: adapter metrics not found", adapterStr)
 		return
 	}
 	am.BidValidationCreativeSizeErrorMeter.Mark(1)
@@ -972,7 +1378,7 @@ func (me *Metrics) RecordBidValidationCreativeSizeWarn(adapter openrtb_ext.Bidde
 	adapterStr := string(adapter)
 	am, ok := me.AdapterMetrics[strings.ToLower(adapterStr)]
 	if !ok {
-		glog.Errorf("Trying to run adapter metrics on %s: adapter metrics not found", adapterStr)
+		logger.Errorf("Trying to run adapter metrics on %s: adapter metrics not found", adapterStr)
 		return
 	}
 	am.BidValidationCreativeSizeWarnMeter.Mark(1)
@@ -987,7 +1393,7 @@ func (me *Metrics) RecordBidValidationSecureMarkupError(adapter openrtb_ext.Bidd
 	adapterStr := string(adapter)
 	am, ok := me.AdapterMetrics[strings.ToLower(adapterStr)]
 	if !ok {
-		glog.Errorf("Trying to run adapter metrics on %s: adapter metrics not found", adapterStr)
+		logger.Errorf("Trying to run adapter metrics on %s: adapter metrics not found", adapterStr)
 		return
 	}
 	am.BidValidationSecureMarkupErrorMeter.Mark(1)
@@ -1002,7 +1408,7 @@ func (me *Metrics) RecordBidValidationSecureMarkupWarn(adapter openrtb_ext.Bidde
 	adapterStr := string(adapter)
 	am, ok := me.AdapterMetrics[strings.ToLower(adapterStr)]
 	if !ok {
-		glog.Errorf("Trying to run adapter metrics on %s: adapter metrics not found", adapterStr)
+		logger.Errorf("Trying to run adapter metrics on %s: adapter metrics not found", adapterStr)
 		return
 	}
 	am.BidValidationSecureMarkupWarnMeter.Mark(1)
@@ -1138,7 +1544,7 @@ func (me *Metrics) getModuleMetric(labels ModuleLabels) (*ModuleMetrics, error)
 	mm, ok := me.ModuleMetrics[labels.Module][labels.Stage]
 	if !ok {
 		err := fmt.Errorf("Trying to run module %s metrics for stage %s: module metrics not found", labels.Module, labels.Stage)
-		glog.Errorf(err.Error())
+		logger.Errorf(err.Error())
 		return nil, err
 	}
 
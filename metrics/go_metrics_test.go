@@ -1154,6 +1154,284 @@ func TestRecordAdapterPanic(t *testing.T) {
 	assert.Equal(t, m.AdapterMetrics[lowerCaseAdapterName].PanicMeter.Count(), int64(1))
 }
 
+func TestRecordBidderRequestPool(t *testing.T) {
+	registry := metrics.NewRegistry()
+	m := NewMetrics(registry, []openrtb_ext.BidderName{openrtb_ext.BidderAppnexus}, config.DisabledMetrics{}, nil, nil)
+	m.RecordBidderRequestPool(10, 3)
+	m.RecordBidderRequestShed(openrtb_ext.BidderAppnexus)
+	assert.Equal(t, int64(10), m.BidderRequestPoolRunning.Value())
+	assert.Equal(t, int64(3), m.BidderRequestPoolQueued.Value())
+	assert.Equal(t, int64(1), m.BidderRequestShedMeter.Count())
+}
+
+func TestRecordAdapterResponseCacheResult(t *testing.T) {
+	registry := metrics.NewRegistry()
+	m := NewMetrics(registry, []openrtb_ext.BidderName{openrtb_ext.BidderAppnexus}, config.DisabledMetrics{}, nil, nil)
+	m.RecordAdapterResponseCacheResult(openrtb_ext.BidderAppnexus, CacheHit)
+	m.RecordAdapterResponseCacheResult(openrtb_ext.BidderAppnexus, CacheHit)
+	m.RecordAdapterResponseCacheResult(openrtb_ext.BidderAppnexus, CacheMiss)
+	assert.Equal(t, int64(2), m.AdapterResponseCacheMeter[CacheHit].Count())
+	assert.Equal(t, int64(1), m.AdapterResponseCacheMeter[CacheMiss].Count())
+}
+
+func TestRecordLoadShedding(t *testing.T) {
+	registry := metrics.NewRegistry()
+	m := NewMetrics(registry, []openrtb_ext.BidderName{openrtb_ext.BidderAppnexus}, config.DisabledMetrics{}, nil, nil)
+	m.RecordLoadShedding(LoadSheddingMemoryRejected)
+	m.RecordLoadShedding(LoadSheddingMemoryRejected)
+	m.RecordLoadShedding(LoadSheddingMemoryDegraded)
+	assert.Equal(t, int64(2), m.LoadSheddingMeters[LoadSheddingMemoryRejected].Count())
+	assert.Equal(t, int64(1), m.LoadSheddingMeters[LoadSheddingMemoryDegraded].Count())
+}
+
+func TestRecordTrafficShadow(t *testing.T) {
+	registry := metrics.NewRegistry()
+	m := NewMetrics(registry, []openrtb_ext.BidderName{openrtb_ext.BidderAppnexus}, config.DisabledMetrics{}, nil, nil)
+	m.RecordTrafficShadow(TrafficShadowSent)
+	m.RecordTrafficShadow(TrafficShadowSent)
+	m.RecordTrafficShadow(TrafficShadowDropped)
+	assert.Equal(t, int64(2), m.TrafficShadowMeters[TrafficShadowSent].Count())
+	assert.Equal(t, int64(0), m.TrafficShadowMeters[TrafficShadowFailed].Count())
+	assert.Equal(t, int64(1), m.TrafficShadowMeters[TrafficShadowDropped].Count())
+}
+
+func TestRecordTestRequest(t *testing.T) {
+	registry := metrics.NewRegistry()
+	m := NewMetrics(registry, []openrtb_ext.BidderName{openrtb_ext.BidderAppnexus}, config.DisabledMetrics{}, nil, nil)
+	m.RecordTestRequest(ReqTypeORTB2Web)
+	m.RecordTestRequest(ReqTypeORTB2Web)
+	m.RecordTestRequest(ReqTypeAMP)
+	assert.Equal(t, int64(2), m.TestRequestMeters[ReqTypeORTB2Web].Count())
+	assert.Equal(t, int64(1), m.TestRequestMeters[ReqTypeAMP].Count())
+	assert.Equal(t, int64(0), m.TestRequestMeters[ReqTypeORTB2App].Count())
+}
+
+func TestRecordWebhook(t *testing.T) {
+	registry := metrics.NewRegistry()
+	m := NewMetrics(registry, []openrtb_ext.BidderName{openrtb_ext.BidderAppnexus}, config.DisabledMetrics{}, nil, nil)
+	m.RecordWebhook(WebhookDelivered)
+	m.RecordWebhook(WebhookDelivered)
+	m.RecordWebhook(WebhookFailed)
+	assert.Equal(t, int64(2), m.WebhookMeters[WebhookDelivered].Count())
+	assert.Equal(t, int64(1), m.WebhookMeters[WebhookFailed].Count())
+	assert.Equal(t, int64(0), m.WebhookMeters[WebhookDropped].Count())
+}
+
+func TestRecordAuctionEventWebhook(t *testing.T) {
+	registry := metrics.NewRegistry()
+	m := NewMetrics(registry, []openrtb_ext.BidderName{openrtb_ext.BidderAppnexus}, config.DisabledMetrics{}, nil, nil)
+	m.RecordAuctionEventWebhook(WebhookDelivered)
+	m.RecordAuctionEventWebhook(WebhookDropped)
+	assert.Equal(t, int64(1), registry.Get("auction_event_webhooks.delivered").(metrics.Meter).Count())
+	assert.Equal(t, int64(1), registry.Get("auction_event_webhooks.dropped").(metrics.Meter).Count())
+	assert.Equal(t, int64(0), m.WebhookMeters[WebhookDelivered].Count())
+}
+
+func TestRecordCurrencyRates(t *testing.T) {
+	registry := metrics.NewRegistry()
+	m := NewMetrics(registry, []openrtb_ext.BidderName{openrtb_ext.BidderAppnexus}, config.DisabledMetrics{}, nil, nil)
+	m.RecordCurrencyRatesFetch(CurrencyRatesFailover)
+	m.RecordCurrencyRatesStale(true)
+	assert.Equal(t, int64(1), registry.Get("currency_rates.failover").(metrics.Meter).Count())
+	assert.Equal(t, int64(0), registry.Get("currency_rates.fetched").(metrics.Meter).Count())
+	assert.Equal(t, int64(1), registry.Get("currency_rates.stale").(metrics.Gauge).Value())
+
+	m.RecordCurrencyRatesStale(false)
+	assert.Equal(t, int64(0), registry.Get("currency_rates.stale").(metrics.Gauge).Value())
+}
+
+func TestRecordAdapterCanary(t *testing.T) {
+	registry := metrics.NewRegistry()
+	m := NewMetrics(registry, []openrtb_ext.BidderName{openrtb_ext.BidderAppnexus}, config.DisabledMetrics{}, nil, nil)
+	m.RecordAdapterCanary(AdapterCanaryLabels{Adapter: openrtb_ext.BidderAppnexus, Version: CanaryControl, Success: true}, 2, 100*time.Millisecond)
+	m.RecordAdapterCanary(AdapterCanaryLabels{Adapter: openrtb_ext.BidderAppnexus, Version: CanaryCanary, Success: true}, 1, 50*time.Millisecond)
+	m.RecordAdapterCanary(AdapterCanaryLabels{Adapter: openrtb_ext.BidderAppnexus, Version: CanaryCanary, Success: false}, 0, 10*time.Millisecond)
+
+	assert.Equal(t, int64(1), registry.Get("adapter.appnexus.canary.control.requests.ok").(metrics.Meter).Count())
+	assert.Equal(t, int64(2), registry.Get("adapter.appnexus.canary.control.bids").(metrics.Meter).Count())
+	assert.Equal(t, int64(1), registry.Get("adapter.appnexus.canary.canary.requests.ok").(metrics.Meter).Count())
+	assert.Equal(t, int64(1), registry.Get("adapter.appnexus.canary.canary.requests.err").(metrics.Meter).Count())
+	assert.Equal(t, int64(1), registry.Get("adapter.appnexus.canary.canary.bids").(metrics.Meter).Count())
+	assert.Equal(t, int64(2), registry.Get("adapter.appnexus.canary.canary.request_time").(metrics.Timer).Count())
+}
+
+func TestRecordAdapterPIIViolation(t *testing.T) {
+	registry := metrics.NewRegistry()
+	m := NewMetrics(registry, []openrtb_ext.BidderName{openrtb_ext.BidderAppnexus}, config.DisabledMetrics{}, nil, nil)
+	m.RecordAdapterPIIViolation(AdapterPIIViolationLabels{Adapter: openrtb_ext.BidderAppnexus, Source: PIIViolationSourceAdapter, Type: PIIViolationEmail})
+	m.RecordAdapterPIIViolation(AdapterPIIViolationLabels{Adapter: openrtb_ext.BidderAppnexus, Source: PIIViolationSourceAdapter, Type: PIIViolationEmail})
+	m.RecordAdapterPIIViolation(AdapterPIIViolationLabels{Adapter: openrtb_ext.BidderAppnexus, Source: PIIViolationSourceModule, Type: PIIViolationUserID})
+
+	assert.Equal(t, int64(2), registry.Get("adapter.appnexus.pii_violations.adapter.email").(metrics.Meter).Count())
+	assert.Equal(t, int64(1), registry.Get("adapter.appnexus.pii_violations.module.user_id").(metrics.Meter).Count())
+	assert.Nil(t, registry.Get("adapter.appnexus.pii_violations.request.email"))
+}
+
+func TestRecordAdapterFieldsWithheld(t *testing.T) {
+	registry := metrics.NewRegistry()
+	m := NewMetrics(registry, []openrtb_ext.BidderName{openrtb_ext.BidderAppnexus}, config.DisabledMetrics{}, nil, nil)
+	m.RecordAdapterFieldsWithheld(openrtb_ext.BidderAppnexus, 2)
+	m.RecordAdapterFieldsWithheld(openrtb_ext.BidderAppnexus, 3)
+
+	assert.Equal(t, int64(5), registry.Get("adapter.appnexus.fields_withheld").(metrics.Meter).Count())
+	assert.Nil(t, registry.Get("adapter.rubicon.fields_withheld"))
+}
+
+func TestRecordAdapterFloorConversion(t *testing.T) {
+	registry := metrics.NewRegistry()
+	m := NewMetrics(registry, []openrtb_ext.BidderName{openrtb_ext.BidderAppnexus}, config.DisabledMetrics{}, nil, nil)
+	m.RecordAdapterFloorConversion(openrtb_ext.BidderAppnexus, true)
+	m.RecordAdapterFloorConversion(openrtb_ext.BidderAppnexus, true)
+	m.RecordAdapterFloorConversion(openrtb_ext.BidderAppnexus, false)
+
+	assert.Equal(t, int64(2), registry.Get("adapter.appnexus.floor_conversions.success").(metrics.Meter).Count())
+	assert.Equal(t, int64(1), registry.Get("adapter.appnexus.floor_conversions.failure").(metrics.Meter).Count())
+	assert.Nil(t, registry.Get("adapter.rubicon.floor_conversions.success"))
+}
+
+func TestRecordLatencyBudgetStage(t *testing.T) {
+	registry := metrics.NewRegistry()
+	m := NewMetrics(registry, []openrtb_ext.BidderName{openrtb_ext.BidderAppnexus}, config.DisabledMetrics{}, nil, nil)
+	m.RecordLatencyBudgetStage(LatencyBudgetBidders, 200*time.Millisecond, false)
+	m.RecordLatencyBudgetStage(LatencyBudgetBidders, 300*time.Millisecond, true)
+	assert.Equal(t, int64(2), m.LatencyBudgetStageTimers[LatencyBudgetBidders].Count())
+	assert.Equal(t, int64(500*time.Millisecond), m.LatencyBudgetStageTimers[LatencyBudgetBidders].Sum())
+	assert.Equal(t, int64(1), m.LatencyBudgetOverrunMeters[LatencyBudgetBidders].Count())
+	assert.Equal(t, int64(0), m.LatencyBudgetOverrunMeters[LatencyBudgetCacheWrite].Count())
+}
+
+func TestRecordIVT(t *testing.T) {
+	registry := metrics.NewRegistry()
+	m := NewMetrics(registry, []openrtb_ext.BidderName{openrtb_ext.BidderAppnexus}, config.DisabledMetrics{}, nil, nil)
+	m.RecordIVT(IVTBotUserAgent, IVTBlocked)
+	m.RecordIVT(IVTBotUserAgent, IVTBlocked)
+	m.RecordIVT(IVTDatacenterIP, IVTTagged)
+	assert.Equal(t, int64(2), m.IVTMeters[IVTBotUserAgent][IVTBlocked].Count())
+	assert.Equal(t, int64(0), m.IVTMeters[IVTBotUserAgent][IVTTagged].Count())
+	assert.Equal(t, int64(1), m.IVTMeters[IVTDatacenterIP][IVTTagged].Count())
+}
+
+func TestRecordRequestLimitExceeded(t *testing.T) {
+	registry := metrics.NewRegistry()
+	m := NewMetrics(registry, []openrtb_ext.BidderName{openrtb_ext.BidderAppnexus}, config.DisabledMetrics{}, nil, nil)
+	m.RecordRequestLimitExceeded(RequestLimitImps)
+	m.RecordRequestLimitExceeded(RequestLimitImps)
+	m.RecordRequestLimitExceeded(RequestLimitRequestSize)
+	assert.Equal(t, int64(2), m.RequestLimitMeters[RequestLimitImps].Count())
+	assert.Equal(t, int64(0), m.RequestLimitMeters[RequestLimitEIDs].Count())
+	assert.Equal(t, int64(1), m.RequestLimitMeters[RequestLimitRequestSize].Count())
+}
+
+func TestRecordRequestNormalization(t *testing.T) {
+	registry := metrics.NewRegistry()
+	m := NewMetrics(registry, []openrtb_ext.BidderName{openrtb_ext.BidderAppnexus}, config.DisabledMetrics{}, nil, nil)
+	m.RecordRequestNormalization(RequestNormalizationStringifiedNumber)
+	m.RecordRequestNormalization(RequestNormalizationStringifiedNumber)
+	m.RecordRequestNormalization(RequestNormalizationConsentLocation)
+	assert.Equal(t, int64(2), m.RequestNormalizationMeters[RequestNormalizationStringifiedNumber].Count())
+	assert.Equal(t, int64(0), m.RequestNormalizationMeters[RequestNormalizationEnumCase].Count())
+	assert.Equal(t, int64(1), m.RequestNormalizationMeters[RequestNormalizationConsentLocation].Count())
+}
+
+func TestRecordAPIKey(t *testing.T) {
+	registry := metrics.NewRegistry()
+	m := NewMetrics(registry, []openrtb_ext.BidderName{openrtb_ext.BidderAppnexus}, config.DisabledMetrics{}, nil, nil)
+	m.RecordAPIKey(APIKeyAccepted)
+	m.RecordAPIKey(APIKeyAccepted)
+	m.RecordAPIKey(APIKeyRevoked)
+	assert.Equal(t, int64(2), m.APIKeyMeters[APIKeyAccepted].Count())
+	assert.Equal(t, int64(0), m.APIKeyMeters[APIKeyMissing].Count())
+	assert.Equal(t, int64(1), m.APIKeyMeters[APIKeyRevoked].Count())
+}
+
+func TestRecordRateLimit(t *testing.T) {
+	registry := metrics.NewRegistry()
+	m := NewMetrics(registry, []openrtb_ext.BidderName{openrtb_ext.BidderAppnexus}, config.DisabledMetrics{}, nil, nil)
+	m.RecordRateLimit(RateLimitBidderQPS, RateLimitLimited)
+	m.RecordRateLimit(RateLimitBidderQPS, RateLimitLimited)
+	m.RecordRateLimit(RateLimitDealPacing, RateLimitError)
+	assert.Equal(t, int64(2), m.RateLimitMeters[RateLimitBidderQPS][RateLimitLimited].Count())
+	assert.Equal(t, int64(0), m.RateLimitMeters[RateLimitBidderQPS][RateLimitAllowed].Count())
+	assert.Equal(t, int64(1), m.RateLimitMeters[RateLimitDealPacing][RateLimitError].Count())
+}
+
+func TestRecordGeoLookup(t *testing.T) {
+	registry := metrics.NewRegistry()
+	m := NewMetrics(registry, []openrtb_ext.BidderName{openrtb_ext.BidderAppnexus}, config.DisabledMetrics{}, nil, nil)
+	m.RecordGeoLookup(GeoLookupFound, 20*time.Microsecond)
+	m.RecordGeoLookup(GeoLookupFound, 30*time.Microsecond)
+	m.RecordGeoLookup(GeoLookupNotFound, 10*time.Microsecond)
+	assert.Equal(t, int64(2), m.GeoLookupTimers[GeoLookupFound].Count())
+	assert.Equal(t, int64(50*time.Microsecond), m.GeoLookupTimers[GeoLookupFound].Sum())
+	assert.Equal(t, int64(1), m.GeoLookupTimers[GeoLookupNotFound].Count())
+	assert.Equal(t, int64(0), m.GeoLookupTimers[GeoLookupError].Count())
+}
+
+func TestRecordContentClassification(t *testing.T) {
+	registry := metrics.NewRegistry()
+	m := NewMetrics(registry, []openrtb_ext.BidderName{openrtb_ext.BidderAppnexus}, config.DisabledMetrics{}, nil, nil)
+	m.RecordContentClassification(ContentClassificationClassified, 20*time.Millisecond)
+	m.RecordContentClassification(ContentClassificationCached, 10*time.Microsecond)
+	m.RecordContentClassification(ContentClassificationCached, 20*time.Microsecond)
+	assert.Equal(t, int64(1), m.ContentClassificationTimers[ContentClassificationClassified].Count())
+	assert.Equal(t, int64(2), m.ContentClassificationTimers[ContentClassificationCached].Count())
+	assert.Equal(t, int64(30*time.Microsecond), m.ContentClassificationTimers[ContentClassificationCached].Sum())
+	assert.Equal(t, int64(0), m.ContentClassificationTimers[ContentClassificationError].Count())
+}
+
+func TestRecordAdapterConnectionWarmup(t *testing.T) {
+	registry := metrics.NewRegistry()
+	m := NewMetrics(registry, []openrtb_ext.BidderName{openrtb_ext.BidderAppnexus}, config.DisabledMetrics{}, nil, nil)
+	m.RecordAdapterConnectionWarmup(openrtb_ext.BidderAppnexus, ConnectionWarmupCreated)
+	m.RecordAdapterConnectionWarmup(openrtb_ext.BidderAppnexus, ConnectionWarmupReused)
+	m.RecordAdapterConnectionWarmup(openrtb_ext.BidderAppnexus, ConnectionWarmupReused)
+	m.RecordAdapterConnectionWarmup(openrtb_ext.BidderName("unknown"), ConnectionWarmupFailed)
+
+	am := m.AdapterMetrics[string(openrtb_ext.BidderAppnexus)]
+	assert.Equal(t, int64(1), am.ConnWarmupMeters[ConnectionWarmupCreated].Count())
+	assert.Equal(t, int64(2), am.ConnWarmupMeters[ConnectionWarmupReused].Count())
+	assert.Equal(t, int64(0), am.ConnWarmupMeters[ConnectionWarmupFailed].Count())
+}
+
+func TestRecordAdapterTLSHandshake(t *testing.T) {
+	registry := metrics.NewRegistry()
+	m := NewMetrics(registry, []openrtb_ext.BidderName{openrtb_ext.BidderAppnexus}, config.DisabledMetrics{}, nil, nil)
+	m.RecordAdapterTLSHandshake(openrtb_ext.BidderAppnexus, false)
+	m.RecordAdapterTLSHandshake(openrtb_ext.BidderAppnexus, true)
+	m.RecordAdapterTLSHandshake(openrtb_ext.BidderAppnexus, true)
+	m.RecordAdapterTLSHandshake(openrtb_ext.BidderName("unknown"), true)
+
+	am := m.AdapterMetrics[string(openrtb_ext.BidderAppnexus)]
+	assert.Equal(t, int64(1), am.TLSHandshakeFull.Count())
+	assert.Equal(t, int64(2), am.TLSResumed.Count())
+}
+
+func TestRecordAdapterHedgedRequest(t *testing.T) {
+	registry := metrics.NewRegistry()
+	m := NewMetrics(registry, []openrtb_ext.BidderName{openrtb_ext.BidderAppnexus}, config.DisabledMetrics{}, nil, nil)
+	m.RecordAdapterHedgedRequest(openrtb_ext.BidderAppnexus, true)
+	m.RecordAdapterHedgedRequest(openrtb_ext.BidderAppnexus, false)
+	m.RecordAdapterHedgedRequest(openrtb_ext.BidderAppnexus, false)
+	m.RecordAdapterHedgedRequest(openrtb_ext.BidderName("unknown"), true)
+
+	am := m.AdapterMetrics[string(openrtb_ext.BidderAppnexus)]
+	assert.Equal(t, int64(1), am.HedgesWon.Count())
+	assert.Equal(t, int64(2), am.HedgesLost.Count())
+}
+
+func TestRecordAdapterMaintenance(t *testing.T) {
+	registry := metrics.NewRegistry()
+	m := NewMetrics(registry, []openrtb_ext.BidderName{openrtb_ext.BidderAppnexus}, config.DisabledMetrics{}, nil, nil)
+	m.RecordAdapterMaintenance(openrtb_ext.BidderAppnexus, BidderMaintenanceWindow)
+	m.RecordAdapterMaintenance(openrtb_ext.BidderAppnexus, BidderMaintenanceKillSwitch)
+	m.RecordAdapterMaintenance(openrtb_ext.BidderAppnexus, BidderMaintenanceKillSwitch)
+	m.RecordAdapterMaintenance(openrtb_ext.BidderName("unknown"), BidderMaintenanceWindow)
+
+	am := m.AdapterMetrics[string(openrtb_ext.BidderAppnexus)]
+	assert.Equal(t, int64(1), am.MaintenanceMeters[BidderMaintenanceWindow].Count())
+	assert.Equal(t, int64(2), am.MaintenanceMeters[BidderMaintenanceKillSwitch].Count())
+}
+
 func TestRecordAdapterPrice(t *testing.T) {
 	registry := metrics.NewRegistry()
 	syncerKeys := []string{"foo"}
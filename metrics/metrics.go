@@ -131,6 +131,418 @@ func StoredDataErrors() []StoredDataError {
 	}
 }
 
+// ConnectionWarmupResult describes the outcome of a request made to warm up a connection to a bidder endpoint
+type ConnectionWarmupResult string
+
+const (
+	// ConnectionWarmupCreated - a new connection was opened to the bidder endpoint
+	ConnectionWarmupCreated ConnectionWarmupResult = "created"
+	// ConnectionWarmupReused - an idle connection to the bidder endpoint was still open and was kept alive
+	ConnectionWarmupReused ConnectionWarmupResult = "reused"
+	// ConnectionWarmupFailed - the bidder endpoint could not be reached
+	ConnectionWarmupFailed ConnectionWarmupResult = "failed"
+)
+
+func ConnectionWarmupResults() []ConnectionWarmupResult {
+	return []ConnectionWarmupResult{
+		ConnectionWarmupCreated,
+		ConnectionWarmupReused,
+		ConnectionWarmupFailed,
+	}
+}
+
+// BidderMaintenance describes why a bidder was left out of an auction for maintenance
+type BidderMaintenance string
+
+const (
+	// BidderMaintenanceWindow - a maintenance window of the host or the account was in effect
+	BidderMaintenanceWindow BidderMaintenance = "window"
+	// BidderMaintenanceKillSwitch - the bidder's kill switch was turned on through the admin server
+	BidderMaintenanceKillSwitch BidderMaintenance = "kill_switch"
+)
+
+func BidderMaintenanceReasons() []BidderMaintenance {
+	return []BidderMaintenance{
+		BidderMaintenanceWindow,
+		BidderMaintenanceKillSwitch,
+	}
+}
+
+// LoadSheddingAction describes what was done with a request because the server was overloaded
+type LoadSheddingAction string
+
+const (
+	// LoadSheddingMemoryRejected - the request was rejected because memory usage crossed the shedding threshold
+	LoadSheddingMemoryRejected LoadSheddingAction = "memory_rejected"
+	// LoadSheddingMemoryDegraded - optional work was skipped because memory usage crossed the degradation threshold
+	LoadSheddingMemoryDegraded LoadSheddingAction = "memory_degraded"
+	// LoadSheddingConcurrencyRejected - the request was rejected because the concurrency limit was reached and it couldn't be queued
+	LoadSheddingConcurrencyRejected LoadSheddingAction = "concurrency_rejected"
+)
+
+func LoadSheddingActions() []LoadSheddingAction {
+	return []LoadSheddingAction{
+		LoadSheddingMemoryRejected,
+		LoadSheddingMemoryDegraded,
+		LoadSheddingConcurrencyRejected,
+	}
+}
+
+// IVTReason is why a request was found to be invalid traffic
+type IVTReason string
+
+const (
+	// IVTBotUserAgent - device.ua matched the spiders and bots list
+	IVTBotUserAgent IVTReason = "bot_ua"
+	// IVTBlockedIP - device.ip or device.ipv6 is blocked
+	IVTBlockedIP IVTReason = "blocked_ip"
+	// IVTDatacenterIP - device.ip or device.ipv6 belongs to a datacenter
+	IVTDatacenterIP IVTReason = "datacenter_ip"
+	// IVTBlockedIFA - device.ifa is blocked
+	IVTBlockedIFA IVTReason = "blocked_ifa"
+)
+
+func IVTReasons() []IVTReason {
+	return []IVTReason{
+		IVTBotUserAgent,
+		IVTBlockedIP,
+		IVTDatacenterIP,
+		IVTBlockedIFA,
+	}
+}
+
+// IVTAction is what was done with a request found to be invalid traffic
+type IVTAction string
+
+const (
+	// IVTBlocked - the request was rejected
+	IVTBlocked IVTAction = "blocked"
+	// IVTTagged - the request was auctioned with device.ext.ivt set
+	IVTTagged IVTAction = "tagged"
+)
+
+func IVTActions() []IVTAction {
+	return []IVTAction{
+		IVTBlocked,
+		IVTTagged,
+	}
+}
+
+// RequestLimit is an account's cap on the size or complexity of its requests
+type RequestLimit string
+
+const (
+	// RequestLimitImps - the request had more imps than the account allows
+	RequestLimitImps RequestLimit = "imps"
+	// RequestLimitEIDs - the request had more user eids than the account allows
+	RequestLimitEIDs RequestLimit = "eids"
+	// RequestLimitDataSegments - the request had more user.data segments than the account allows
+	RequestLimitDataSegments RequestLimit = "data_segments"
+	// RequestLimitRequestSize - the request body was larger than the account allows
+	RequestLimitRequestSize RequestLimit = "request_size"
+)
+
+func RequestLimits() []RequestLimit {
+	return []RequestLimit{
+		RequestLimitImps,
+		RequestLimitEIDs,
+		RequestLimitDataSegments,
+		RequestLimitRequestSize,
+	}
+}
+
+// RequestNormalization is a fix of a well known mistake, applied to a request before it's validated
+type RequestNormalization string
+
+const (
+	// RequestNormalizationStringifiedNumber - a number was sent as a string
+	RequestNormalizationStringifiedNumber RequestNormalization = "stringified_number"
+	// RequestNormalizationEnumCase - a currency code or price granularity was sent in the wrong case
+	RequestNormalizationEnumCase RequestNormalization = "enum_case"
+	// RequestNormalizationConsentLocation - a consent string or privacy signal was sent in the wrong object
+	RequestNormalizationConsentLocation RequestNormalization = "consent_location"
+)
+
+func RequestNormalizations() []RequestNormalization {
+	return []RequestNormalization{
+		RequestNormalizationStringifiedNumber,
+		RequestNormalizationEnumCase,
+		RequestNormalizationConsentLocation,
+	}
+}
+
+// APIKeyStatus is the outcome of checking the API key of a request
+type APIKeyStatus string
+
+const (
+	// APIKeyAccepted - the request had a valid key within its rate limit
+	APIKeyAccepted APIKeyStatus = "accepted"
+	// APIKeyMissing - the request had no key, which is rejected only if its account requires one
+	APIKeyMissing APIKeyStatus = "missing"
+	// APIKeyInvalid - the request had a key the account doesn't have
+	APIKeyInvalid APIKeyStatus = "invalid"
+	// APIKeyRevoked - the request had a key the account has revoked
+	APIKeyRevoked APIKeyStatus = "revoked"
+	// APIKeyRateLimited - the request's key had gone over its rate limit
+	APIKeyRateLimited APIKeyStatus = "rate_limited"
+)
+
+func APIKeyStatuses() []APIKeyStatus {
+	return []APIKeyStatus{
+		APIKeyAccepted,
+		APIKeyMissing,
+		APIKeyInvalid,
+		APIKeyRevoked,
+		APIKeyRateLimited,
+	}
+}
+
+// RateLimit is a limit enforced with the rate limiting counters, which may be shared by the fleet
+type RateLimit string
+
+const (
+	// RateLimitAPIKey - the rate limit of an account's API key
+	RateLimitAPIKey RateLimit = "api_key"
+	// RateLimitBidderQPS - the cap on the requests per second sent to a bidder
+	RateLimitBidderQPS RateLimit = "bidder_qps"
+	// RateLimitDealPacing - the most bids for a deal in a pacing window
+	RateLimitDealPacing RateLimit = "deal_pacing"
+	// RateLimitUsageQuota - the most auctions, imps or bidder calls of an account in a quota period
+	RateLimitUsageQuota RateLimit = "usage_quota"
+	// RateLimitTimeoutNotification - the most timeout notifications sent to a bidder in a second
+	RateLimitTimeoutNotification RateLimit = "timeout_notification"
+)
+
+func RateLimits() []RateLimit {
+	return []RateLimit{
+		RateLimitAPIKey,
+		RateLimitBidderQPS,
+		RateLimitDealPacing,
+		RateLimitUsageQuota,
+		RateLimitTimeoutNotification,
+	}
+}
+
+// RateLimitOutcome is the outcome of checking a rate limit
+type RateLimitOutcome string
+
+const (
+	// RateLimitAllowed - the limit hadn't been reached
+	RateLimitAllowed RateLimitOutcome = "allowed"
+	// RateLimitLimited - the limit had been reached
+	RateLimitLimited RateLimitOutcome = "limited"
+	// RateLimitError - the counter couldn't be reached, so the limit wasn't enforced
+	RateLimitError RateLimitOutcome = "error"
+)
+
+func RateLimitOutcomes() []RateLimitOutcome {
+	return []RateLimitOutcome{
+		RateLimitAllowed,
+		RateLimitLimited,
+		RateLimitError,
+	}
+}
+
+// GeoLookupStatus is the outcome of looking up where a device is from its IP address
+type GeoLookupStatus string
+
+const (
+	// GeoLookupFound - the IP address was found
+	GeoLookupFound GeoLookupStatus = "found"
+	// GeoLookupNotFound - the IP address isn't in the database, such as a private address
+	GeoLookupNotFound GeoLookupStatus = "not_found"
+	// GeoLookupError - the lookup failed
+	GeoLookupError GeoLookupStatus = "error"
+)
+
+func GeoLookupStatuses() []GeoLookupStatus {
+	return []GeoLookupStatus{
+		GeoLookupFound,
+		GeoLookupNotFound,
+		GeoLookupError,
+	}
+}
+
+// ContentClassificationStatus is the outcome of classifying the content of a request's page or app
+type ContentClassificationStatus string
+
+const (
+	// ContentClassificationCached - the content's classification was cached
+	ContentClassificationCached ContentClassificationStatus = "cached"
+	// ContentClassificationClassified - the classifier classified the content
+	ContentClassificationClassified ContentClassificationStatus = "classified"
+	// ContentClassificationError - the classifier failed or timed out
+	ContentClassificationError ContentClassificationStatus = "error"
+	// ContentClassificationSkipped - the auction had too little time left to call the classifier
+	ContentClassificationSkipped ContentClassificationStatus = "skipped"
+)
+
+func ContentClassificationStatuses() []ContentClassificationStatus {
+	return []ContentClassificationStatus{
+		ContentClassificationCached,
+		ContentClassificationClassified,
+		ContentClassificationError,
+		ContentClassificationSkipped,
+	}
+}
+
+// LatencyBudgetStage is a stage of an auction which is given a share of its tmax
+type LatencyBudgetStage string
+
+const (
+	LatencyBudgetStoredRequests LatencyBudgetStage = "stored_requests"
+	LatencyBudgetHooks          LatencyBudgetStage = "hooks"
+	LatencyBudgetBidders        LatencyBudgetStage = "bidders"
+	LatencyBudgetCacheWrite     LatencyBudgetStage = "cache_write"
+)
+
+func LatencyBudgetStages() []LatencyBudgetStage {
+	return []LatencyBudgetStage{
+		LatencyBudgetStoredRequests,
+		LatencyBudgetHooks,
+		LatencyBudgetBidders,
+		LatencyBudgetCacheWrite,
+	}
+}
+
+// TrafficShadowStatus is what became of a copy of a request meant for the shadow host
+type TrafficShadowStatus string
+
+const (
+	// TrafficShadowSent - the copy was sent to the shadow host
+	TrafficShadowSent TrafficShadowStatus = "sent"
+	// TrafficShadowFailed - the copy couldn't be scrubbed or sent
+	TrafficShadowFailed TrafficShadowStatus = "failed"
+	// TrafficShadowDropped - the copy was dropped because too many were already waiting to be sent
+	TrafficShadowDropped TrafficShadowStatus = "dropped"
+)
+
+func TrafficShadowStatuses() []TrafficShadowStatus {
+	return []TrafficShadowStatus{
+		TrafficShadowSent,
+		TrafficShadowFailed,
+		TrafficShadowDropped,
+	}
+}
+
+// WebhookStatus is what became of a webhook event meant for an endpoint
+type WebhookStatus string
+
+const (
+	// WebhookDelivered - the endpoint accepted the event
+	WebhookDelivered WebhookStatus = "delivered"
+	// WebhookFailed - the endpoint didn't accept the event after all its attempts
+	WebhookFailed WebhookStatus = "failed"
+	// WebhookDropped - the event was dropped because too many were already waiting to be sent to the endpoint
+	WebhookDropped WebhookStatus = "dropped"
+)
+
+func WebhookStatuses() []WebhookStatus {
+	return []WebhookStatus{
+		WebhookDelivered,
+		WebhookFailed,
+		WebhookDropped,
+	}
+}
+
+// CurrencyRatesFetchStatus is the outcome of an update of the currency rates
+type CurrencyRatesFetchStatus string
+
+const (
+	// CurrencyRatesFetched - the rates were fetched from the source with the highest priority
+	CurrencyRatesFetched CurrencyRatesFetchStatus = "fetched"
+	// CurrencyRatesFailover - the rates were fetched from a source of lower priority, since those above it failed
+	CurrencyRatesFailover CurrencyRatesFetchStatus = "failover"
+	// CurrencyRatesFailed - none of the sources could be fetched
+	CurrencyRatesFailed CurrencyRatesFetchStatus = "failed"
+)
+
+func CurrencyRatesFetchStatuses() []CurrencyRatesFetchStatus {
+	return []CurrencyRatesFetchStatus{
+		CurrencyRatesFetched,
+		CurrencyRatesFailover,
+		CurrencyRatesFailed,
+	}
+}
+
+// CanaryVersion is the version of a component a request was handled by, when a canary version of it is
+// being ramped up
+type CanaryVersion string
+
+const (
+	// CanaryControl - the request was handled by the current version
+	CanaryControl CanaryVersion = "control"
+	// CanaryCanary - the request was handled by the canary version
+	CanaryCanary CanaryVersion = "canary"
+)
+
+func CanaryVersions() []CanaryVersion {
+	return []CanaryVersion{
+		CanaryControl,
+		CanaryCanary,
+	}
+}
+
+// AdapterCanaryLabels defines the labels of the metrics which compare an adapter's canary configuration
+// with its current one.
+type AdapterCanaryLabels struct {
+	Adapter openrtb_ext.BidderName
+	Version CanaryVersion
+	// Success is false if the request to the bidder failed
+	Success bool
+}
+
+// PIIViolationType is the kind of personal data found in a request to a bidder which shouldn't have it
+type PIIViolationType string
+
+const (
+	// PIIViolationEmail - an email address
+	PIIViolationEmail PIIViolationType = "email"
+	// PIIViolationPreciseGeo - a latitude or longitude more precise than the privacy policies allow the bidder
+	PIIViolationPreciseGeo PIIViolationType = "precise_geo"
+	// PIIViolationUserID - an ID of the user or device which the privacy policies removed from the bidder's request
+	PIIViolationUserID PIIViolationType = "user_id"
+	// PIIViolationPattern - a match of a pattern configured by the host
+	PIIViolationPattern PIIViolationType = "pattern"
+)
+
+func PIIViolationTypes() []PIIViolationType {
+	return []PIIViolationType{
+		PIIViolationEmail,
+		PIIViolationPreciseGeo,
+		PIIViolationUserID,
+		PIIViolationPattern,
+	}
+}
+
+// PIIViolationSource is what put personal data in a request to a bidder
+type PIIViolationSource string
+
+const (
+	// PIIViolationSourceRequest - the data was in the bidder's request after the privacy policies were applied
+	PIIViolationSourceRequest PIIViolationSource = "request"
+	// PIIViolationSourceModule - a module added the data to the bidder's request
+	PIIViolationSourceModule PIIViolationSource = "module"
+	// PIIViolationSourceAdapter - the adapter added the data when it built the HTTP request
+	PIIViolationSourceAdapter PIIViolationSource = "adapter"
+)
+
+func PIIViolationSources() []PIIViolationSource {
+	return []PIIViolationSource{
+		PIIViolationSourceRequest,
+		PIIViolationSourceModule,
+		PIIViolationSourceAdapter,
+	}
+}
+
+// AdapterPIIViolationLabels defines the labels of the metric which counts the personal data found in
+// requests to an adapter.
+type AdapterPIIViolationLabels struct {
+	Adapter openrtb_ext.BidderName
+	Source  PIIViolationSource
+	Type    PIIViolationType
+}
+
 // Label typecasting. Se below the type definitions for possible values
 
 // DemandSource : Demand source enumeration
@@ -435,9 +847,35 @@ type MetricsEngine interface {
 	RecordOverheadTime(overHead OverheadType, length time.Duration)
 	RecordAdapterRequest(labels AdapterLabels)
 	RecordAdapterConnections(adapterName openrtb_ext.BidderName, connWasReused bool, connWaitTime time.Duration)
+	RecordAdapterConnectionWarmup(adapterName openrtb_ext.BidderName, result ConnectionWarmupResult)
+	RecordAdapterTLSHandshake(adapterName openrtb_ext.BidderName, resumed bool)
+	RecordAdapterHedgedRequest(adapterName openrtb_ext.BidderName, won bool)
+	RecordAdapterMaintenance(adapterName openrtb_ext.BidderName, reason BidderMaintenance)
 	RecordDNSTime(dnsLookupTime time.Duration)
 	RecordTLSHandshakeTime(tlsHandshakeTime time.Duration)
 	RecordBidderServerResponseTime(bidderServerResponseTime time.Duration)
+	RecordBidderRequestPool(runningWorkers int, queuedRequests int)
+	RecordBidderRequestShed(adapterName openrtb_ext.BidderName)
+	RecordAdapterResponseCacheResult(adapterName openrtb_ext.BidderName, cacheResult CacheResult)
+	RecordLoadShedding(action LoadSheddingAction)
+	RecordIVT(reason IVTReason, action IVTAction)
+	RecordRequestLimitExceeded(limit RequestLimit)
+	RecordRequestNormalization(fix RequestNormalization)
+	RecordAPIKey(status APIKeyStatus)
+	RecordRateLimit(limit RateLimit, outcome RateLimitOutcome)
+	RecordGeoLookup(status GeoLookupStatus, duration time.Duration)
+	RecordContentClassification(status ContentClassificationStatus, duration time.Duration)
+	RecordLatencyBudgetStage(stage LatencyBudgetStage, used time.Duration, overrun bool)
+	RecordTrafficShadow(status TrafficShadowStatus)
+	RecordTestRequest(requestType RequestType)
+	RecordWebhook(status WebhookStatus)
+	RecordAuctionEventWebhook(status WebhookStatus)
+	RecordCurrencyRatesFetch(status CurrencyRatesFetchStatus)
+	RecordCurrencyRatesStale(stale bool)
+	RecordAdapterCanary(labels AdapterCanaryLabels, bids int, length time.Duration)
+	RecordAdapterPIIViolation(labels AdapterPIIViolationLabels)
+	RecordAdapterFieldsWithheld(adapterName openrtb_ext.BidderName, fields int)
+	RecordAdapterFloorConversion(adapterName openrtb_ext.BidderName, success bool)
 	RecordAdapterPanic(labels AdapterLabels)
 	RecordAdapterBidReceived(labels AdapterLabels, bidType openrtb_ext.BidType, hasAdm bool)
 	RecordAdapterPrice(labels AdapterLabels, cpm float64)
@@ -449,6 +887,7 @@ type MetricsEngine interface {
 	RecordStoredReqCacheResult(cacheResult CacheResult, inc int)
 	RecordStoredImpCacheResult(cacheResult CacheResult, inc int)
 	RecordAccountCacheResult(cacheResult CacheResult, inc int)
+	RecordCurrencyConversionCacheResult(cacheResult CacheResult, inc int)
 	RecordStoredDataFetchTime(labels StoredDataLabels, length time.Duration)
 	RecordStoredDataError(labels StoredDataLabels)
 	RecordPrebidCacheRequestTime(success bool, length time.Duration)
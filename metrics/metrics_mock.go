@@ -67,6 +67,26 @@ func (me *MetricsEngineMock) RecordAdapterConnections(bidderName openrtb_ext.Bid
 	me.Called(bidderName, connWasReused, connWaitTime)
 }
 
+// RecordAdapterConnectionWarmup mock
+func (me *MetricsEngineMock) RecordAdapterConnectionWarmup(adapterName openrtb_ext.BidderName, result ConnectionWarmupResult) {
+	me.Called(adapterName, result)
+}
+
+// RecordAdapterTLSHandshake mock
+func (me *MetricsEngineMock) RecordAdapterTLSHandshake(adapterName openrtb_ext.BidderName, resumed bool) {
+	me.Called(adapterName, resumed)
+}
+
+// RecordAdapterHedgedRequest mock
+func (me *MetricsEngineMock) RecordAdapterHedgedRequest(adapterName openrtb_ext.BidderName, won bool) {
+	me.Called(adapterName, won)
+}
+
+// RecordAdapterMaintenance mock
+func (me *MetricsEngineMock) RecordAdapterMaintenance(adapterName openrtb_ext.BidderName, reason BidderMaintenance) {
+	me.Called(adapterName, reason)
+}
+
 // RecordDNSTime mock
 func (me *MetricsEngineMock) RecordDNSTime(dnsLookupTime time.Duration) {
 	me.Called(dnsLookupTime)
@@ -81,6 +101,116 @@ func (me *MetricsEngineMock) RecordBidderServerResponseTime(bidderServerResponse
 	me.Called(bidderServerResponseTime)
 }
 
+// RecordBidderRequestPool mock
+func (me *MetricsEngineMock) RecordBidderRequestPool(runningWorkers int, queuedRequests int) {
+	me.Called(runningWorkers, queuedRequests)
+}
+
+// RecordBidderRequestShed mock
+func (me *MetricsEngineMock) RecordBidderRequestShed(adapterName openrtb_ext.BidderName) {
+	me.Called(adapterName)
+}
+
+// RecordAdapterResponseCacheResult mock
+func (me *MetricsEngineMock) RecordAdapterResponseCacheResult(adapterName openrtb_ext.BidderName, cacheResult CacheResult) {
+	me.Called(adapterName, cacheResult)
+}
+
+// RecordLoadShedding mock
+func (me *MetricsEngineMock) RecordLoadShedding(action LoadSheddingAction) {
+	me.Called(action)
+}
+
+// RecordLatencyBudgetStage mock
+func (me *MetricsEngineMock) RecordLatencyBudgetStage(stage LatencyBudgetStage, used time.Duration, overrun bool) {
+	me.Called(stage, used, overrun)
+}
+
+// RecordTrafficShadow mock
+func (me *MetricsEngineMock) RecordTrafficShadow(status TrafficShadowStatus) {
+	me.Called(status)
+}
+
+// RecordTestRequest mock
+func (me *MetricsEngineMock) RecordTestRequest(requestType RequestType) {
+	me.Called(requestType)
+}
+
+// RecordWebhook mock
+func (me *MetricsEngineMock) RecordWebhook(status WebhookStatus) {
+	me.Called(status)
+}
+
+// RecordAuctionEventWebhook mock
+func (me *MetricsEngineMock) RecordAuctionEventWebhook(status WebhookStatus) {
+	me.Called(status)
+}
+
+// RecordCurrencyRatesFetch mock
+func (me *MetricsEngineMock) RecordCurrencyRatesFetch(status CurrencyRatesFetchStatus) {
+	me.Called(status)
+}
+
+// RecordCurrencyRatesStale mock
+func (me *MetricsEngineMock) RecordCurrencyRatesStale(stale bool) {
+	me.Called(stale)
+}
+
+// RecordAdapterCanary mock
+func (me *MetricsEngineMock) RecordAdapterCanary(labels AdapterCanaryLabels, bids int, length time.Duration) {
+	me.Called(labels, bids, length)
+}
+
+// RecordAdapterPIIViolation mock
+func (me *MetricsEngineMock) RecordAdapterPIIViolation(labels AdapterPIIViolationLabels) {
+	me.Called(labels)
+}
+
+// RecordAdapterFieldsWithheld mock
+func (me *MetricsEngineMock) RecordAdapterFieldsWithheld(adapterName openrtb_ext.BidderName, fields int) {
+	me.Called(adapterName, fields)
+}
+
+// RecordAdapterFloorConversion mock
+func (me *MetricsEngineMock) RecordAdapterFloorConversion(adapterName openrtb_ext.BidderName, success bool) {
+	me.Called(adapterName, success)
+}
+
+// RecordIVT mock
+func (me *MetricsEngineMock) RecordIVT(reason IVTReason, action IVTAction) {
+	me.Called(reason, action)
+}
+
+// RecordRequestLimitExceeded mock
+func (me *MetricsEngineMock) RecordRequestLimitExceeded(limit RequestLimit) {
+	me.Called(limit)
+}
+
+// RecordRequestNormalization mock
+func (me *MetricsEngineMock) RecordRequestNormalization(fix RequestNormalization) {
+	me.Called(fix)
+}
+
+// RecordAPIKey mock
+func (me *MetricsEngineMock) RecordAPIKey(status APIKeyStatus) {
+	me.Called(status)
+}
+
+// RecordRateLimit mock
+func (me *MetricsEngineMock) RecordRateLimit(limit RateLimit, outcome RateLimitOutcome) {
+	me.Called(limit, outcome)
+}
+
+// RecordGeoLookup mock
+func (me *MetricsEngineMock) RecordGeoLookup(status GeoLookupStatus, duration time.Duration) {
+	me.Called(status, duration)
+}
+
+// RecordContentClassification mock
+func (me *MetricsEngineMock) RecordContentClassification(status ContentClassificationStatus, duration time.Duration) {
+	me.Called(status, duration)
+}
+
 // RecordAdapterBidReceived mock
 func (me *MetricsEngineMock) RecordAdapterBidReceived(labels AdapterLabels, bidType openrtb_ext.BidType, hasAdm bool) {
 	me.Called(labels, bidType, hasAdm)
@@ -136,6 +266,11 @@ func (me *MetricsEngineMock) RecordAccountCacheResult(cacheResult CacheResult, i
 	me.Called(cacheResult, inc)
 }
 
+// RecordCurrencyConversionCacheResult mock
+func (me *MetricsEngineMock) RecordCurrencyConversionCacheResult(cacheResult CacheResult, inc int) {
+	me.Called(cacheResult, inc)
+}
+
 // RecordPrebidCacheRequestTime mock
 func (me *MetricsEngineMock) RecordPrebidCacheRequestTime(success bool, length time.Duration) {
 	me.Called(success, length)
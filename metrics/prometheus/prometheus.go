@@ -35,6 +35,7 @@ type Metrics struct {
 	storedImpressionsCacheResult *prometheus.CounterVec
 	storedRequestCacheResult     *prometheus.CounterVec
 	accountCacheResult           *prometheus.CounterVec
+	currencyConversionCache      *prometheus.CounterVec
 	storedAccountFetchTimer      *prometheus.HistogramVec
 	storedAccountErrors          *prometheus.CounterVec
 	storedAMPFetchTimer          *prometheus.HistogramVec
@@ -58,6 +59,32 @@ type Metrics struct {
 	adsCertRequests              *prometheus.CounterVec
 	adsCertSignTimer             prometheus.Histogram
 	bidderServerResponseTimer    prometheus.Histogram
+	bidderRequestPoolRunning     prometheus.Gauge
+	bidderRequestPoolQueued      prometheus.Gauge
+	bidderRequestsShed           *prometheus.CounterVec
+	adapterResponseCache         *prometheus.CounterVec
+	loadShedding                 *prometheus.CounterVec
+	ivtRequests                  *prometheus.CounterVec
+	requestLimitsExceeded        *prometheus.CounterVec
+	requestNormalizations        *prometheus.CounterVec
+	apiKeyRequests               *prometheus.CounterVec
+	rateLimitChecks              *prometheus.CounterVec
+	geoLookupTimer               *prometheus.HistogramVec
+	contentClassificationTimer   *prometheus.HistogramVec
+	latencyBudgetStageTimer      *prometheus.HistogramVec
+	latencyBudgetOverruns        *prometheus.CounterVec
+	trafficShadowRequests        *prometheus.CounterVec
+	testRequests                 *prometheus.CounterVec
+	webhookEvents                *prometheus.CounterVec
+	auctionEventWebhookEvents    *prometheus.CounterVec
+	currencyRatesFetches         *prometheus.CounterVec
+	currencyRatesStale           prometheus.Gauge
+	adapterCanaryRequests        *prometheus.CounterVec
+	adapterCanaryBids            *prometheus.CounterVec
+	adapterCanaryRequestsTimer   *prometheus.HistogramVec
+	adapterPIIViolations         *prometheus.CounterVec
+	adapterFieldsWithheld        *prometheus.CounterVec
+	adapterFloorConversions      *prometheus.CounterVec
 
 	// Adapter Metrics
 	adapterBids                           *prometheus.CounterVec
@@ -70,6 +97,10 @@ type Metrics struct {
 	adapterReusedConnections              *prometheus.CounterVec
 	adapterCreatedConnections             *prometheus.CounterVec
 	adapterConnectionWaitTime             *prometheus.HistogramVec
+	adapterTLSHandshakes                  *prometheus.CounterVec
+	adapterHedgedRequests                 *prometheus.CounterVec
+	adapterMaintenance                    *prometheus.CounterVec
+	adapterConnectionWarmups              *prometheus.CounterVec
 	adapterGDPRBlockedRequests            *prometheus.CounterVec
 	adapterBidResponseValidationSizeError *prometheus.CounterVec
 	adapterBidResponseValidationSizeWarn  *prometheus.CounterVec
@@ -111,15 +142,20 @@ const (
 	cacheResultLabel     = "cache_result"
 	connectionErrorLabel = "connection_error"
 	cookieLabel          = "cookie"
+	fixLabel             = "fix"
 	hasBidsLabel         = "has_bids"
 	isAudioLabel         = "audio"
 	isBannerLabel        = "banner"
 	isNativeLabel        = "native"
 	isVideoLabel         = "video"
+	limitLabel           = "limit"
 	markupDeliveryLabel  = "delivery"
 	optOutLabel          = "opt_out"
+	resumedLabel         = "resumed"
+	wonLabel             = "won"
 	overheadTypeLabel    = "overhead_type"
 	privacyBlockedLabel  = "privacy_blocked"
+	reasonLabel          = "reason"
 	requestStatusLabel   = "request_status"
 	requestTypeLabel     = "request_type"
 	stageLabel           = "stage"
@@ -127,6 +163,8 @@ const (
 	successLabel         = "success"
 	syncerLabel          = "syncer"
 	versionLabel         = "version"
+	violationLabel       = "violation"
+	warmupResultLabel    = "warmup_result"
 )
 
 const (
@@ -244,6 +282,11 @@ func NewMetrics(cfg config.PrometheusMetrics, disabledMetrics config.DisabledMet
 		"Count of account cache lookups by hits or miss.",
 		[]string{cacheResultLabel})
 
+	metrics.currencyConversionCache = newCounter(cfg, reg,
+		"currency_conversion_cache_performance",
+		"Count of currency rate lookups within an auction by whether the rate was already memoized for the auction.",
+		[]string{cacheResultLabel})
+
 	metrics.storedAccountFetchTimer = newHistogramVec(cfg, reg,
 		"stored_account_fetch_time_seconds",
 		"Seconds to fetch stored accounts labeled by fetch type",
@@ -398,8 +441,28 @@ func NewMetrics(cfg config.PrometheusMetrics, disabledMetrics config.DisabledMet
 			"Seconds from when the connection was requested until it is either created or reused",
 			[]string{adapterLabel},
 			standardTimeBuckets)
+
+		metrics.adapterTLSHandshakes = newCounter(cfg, reg,
+			"adapter_tls_handshakes",
+			"Count of TLS handshakes of new connections to adapter bidder endpoints, labeled by whether a cached session was resumed.",
+			[]string{adapterLabel, resumedLabel})
 	}
 
+	metrics.adapterHedgedRequests = newCounter(cfg, reg,
+		"adapter_hedged_requests",
+		"Count of requests sent to adapter bidders a second time because the first was slow, labeled by whether the second answer was the one used.",
+		[]string{adapterLabel, wonLabel})
+
+	metrics.adapterMaintenance = newCounter(cfg, reg,
+		"adapter_maintenance",
+		"Count of auctions adapter bidders were left out of for maintenance, labeled by whether a maintenance window or a kill switch was in effect.",
+		[]string{adapterLabel, reasonLabel})
+
+	metrics.adapterConnectionWarmups = newCounter(cfg, reg,
+		"adapter_connection_warmups",
+		"Count of requests made to warm up connections to adapter bidder endpoints, labeled by adapter and whether a connection was created, reused or failed.",
+		[]string{adapterLabel, warmupResultLabel})
+
 	metrics.adapterBidResponseValidationSizeError = newCounter(cfg, reg,
 		"adapter_response_validation_size_err",
 		"Count that tracks number of bids removed from bid response that had a creative size greater than maxWidth/maxHeight",
@@ -432,11 +495,142 @@ func NewMetrics(cfg config.PrometheusMetrics, disabledMetrics config.DisabledMet
 		[]string{adapterLabel},
 		standardTimeBuckets)
 
+	metrics.adapterCanaryRequests = newCounter(cfg, reg,
+		"adapter_canary_requests",
+		"Count of requests to adapters which have a canary configuration, labeled by adapter, version and whether they succeeded.",
+		[]string{adapterLabel, versionLabel, successLabel})
+
+	metrics.adapterCanaryBids = newCounter(cfg, reg,
+		"adapter_canary_bids",
+		"Count of bids made by adapters which have a canary configuration, labeled by adapter and version.",
+		[]string{adapterLabel, versionLabel})
+
+	metrics.adapterCanaryRequestsTimer = newHistogramVec(cfg, reg,
+		"adapter_canary_request_time_seconds",
+		"Seconds to resolve each request to adapters which have a canary configuration, labeled by adapter and version.",
+		[]string{adapterLabel, versionLabel},
+		standardTimeBuckets)
+
+	metrics.adapterPIIViolations = newCounter(cfg, reg,
+		"adapter_pii_violations",
+		"Count of personal data found in requests to adapters by the PII scanner, labeled by adapter, source and type.",
+		[]string{adapterLabel, sourceLabel, violationLabel})
+
+	metrics.adapterFieldsWithheld = newCounter(cfg, reg,
+		"adapter_fields_withheld",
+		"Count of fields removed from requests to adapters by the accounts' bidder field policies, labeled by adapter.",
+		[]string{adapterLabel})
+
+	metrics.adapterFloorConversions = newCounter(cfg, reg,
+		"adapter_floor_conversions",
+		"Count of imp floors converted to the currency of adapters, labeled by adapter and whether they could be converted.",
+		[]string{adapterLabel, successLabel})
+
 	metrics.bidderServerResponseTimer = newHistogram(cfg, reg,
 		"bidder_server_response_time_seconds",
 		"Duration needed to send HTTP request and receive response back from bidder server.",
 		standardTimeBuckets)
 
+	metrics.bidderRequestPoolRunning = newGaugeWithoutLabels(cfg, reg,
+		"bidder_request_pool_running_workers",
+		"Number of workers making bidder requests in the bidder request pool.")
+
+	metrics.bidderRequestPoolQueued = newGaugeWithoutLabels(cfg, reg,
+		"bidder_request_pool_queued_requests",
+		"Number of bidder requests waiting in the bidder request pool queue.")
+
+	metrics.bidderRequestsShed = newCounter(cfg, reg,
+		"bidder_requests_shed",
+		"Count of bidder requests dropped because the bidder request pool queue was full, labeled by adapter.",
+		[]string{adapterLabel})
+
+	metrics.adapterResponseCache = newCounter(cfg, reg,
+		"adapter_response_cache",
+		"Count of bidder requests eligible for the bidder response cache, labeled by adapter and whether the response was cached.",
+		[]string{adapterLabel, cacheResultLabel})
+
+	metrics.loadShedding = newCounter(cfg, reg,
+		"load_shedding",
+		"Count of requests rejected or served without optional work because the server was overloaded, labeled by action.",
+		[]string{actionLabel})
+
+	metrics.ivtRequests = newCounter(cfg, reg,
+		"ivt_requests",
+		"Count of requests found to be invalid traffic, labeled by reason and by whether they were blocked or tagged.",
+		[]string{reasonLabel, actionLabel})
+
+	metrics.requestLimitsExceeded = newCounter(cfg, reg,
+		"request_limits_exceeded",
+		"Count of requests rejected for exceeding their account's limits on size or complexity, labeled by limit.",
+		[]string{limitLabel})
+
+	metrics.requestNormalizations = newCounter(cfg, reg,
+		"request_normalizations",
+		"Count of fixes of well known mistakes applied to requests before they were validated, labeled by fix.",
+		[]string{fixLabel})
+
+	metrics.apiKeyRequests = newCounter(cfg, reg,
+		"api_key_requests",
+		"Count of requests whose API key was checked, labeled by whether it was accepted or why it was rejected.",
+		[]string{statusLabel})
+
+	metrics.rateLimitChecks = newCounter(cfg, reg,
+		"rate_limit_checks",
+		"Count of checks of the API key rate limits, bidder QPS caps and deal pacing, labeled by limit and outcome.",
+		[]string{limitLabel, statusLabel})
+
+	metrics.geoLookupTimer = newHistogramVec(cfg, reg,
+		"geolocation_lookup_time_seconds",
+		"Seconds to look up where a device is from its IP address, labeled by whether it was found.",
+		[]string{statusLabel},
+		[]float64{0.00001, 0.00005, 0.0001, 0.0005, 0.001, 0.005, 0.01})
+
+	metrics.contentClassificationTimer = newHistogramVec(cfg, reg,
+		"content_classification_time_seconds",
+		"Seconds to classify the content of a request's page or app, labeled by outcome.",
+		[]string{statusLabel},
+		[]float64{0.00001, 0.0001, 0.001, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25})
+
+	metrics.latencyBudgetStageTimer = newHistogramVec(cfg, reg,
+		"latency_budget_stage_time_seconds",
+		"Seconds spent in each stage of an auction run with a latency budget, labeled by stage.",
+		[]string{stageLabel},
+		standardTimeBuckets)
+
+	metrics.latencyBudgetOverruns = newCounter(cfg, reg,
+		"latency_budget_overruns",
+		"Count of auction stages which took longer than their share of the latency budget, labeled by stage.",
+		[]string{stageLabel})
+
+	metrics.trafficShadowRequests = newCounter(cfg, reg,
+		"traffic_shadow_requests",
+		"Count of sampled requests meant to be copied to the shadow host, labeled by whether they were sent, failed or were dropped.",
+		[]string{statusLabel})
+
+	metrics.testRequests = newCounter(cfg, reg,
+		"test_requests",
+		"Count of requests with test=1 kept out of the request metrics, labeled by type.",
+		[]string{requestTypeLabel})
+
+	metrics.webhookEvents = newCounter(cfg, reg,
+		"webhook_events",
+		"Count of webhook events meant for an endpoint, labeled by whether they were delivered, failed or were dropped.",
+		[]string{statusLabel})
+
+	metrics.auctionEventWebhookEvents = newCounter(cfg, reg,
+		"auction_event_webhook_events",
+		"Count of win, loss and render events meant for an account's webhook, labeled by whether they were delivered, failed or were dropped.",
+		[]string{statusLabel})
+
+	metrics.currencyRatesFetches = newCounter(cfg, reg,
+		"currency_rates",
+		"Count of updates of the currency rates, labeled by whether the rates were fetched from the source with the highest priority, failed over to another source or weren't fetched at all.",
+		[]string{statusLabel})
+
+	metrics.currencyRatesStale = newGaugeWithoutLabels(cfg, reg,
+		"currency_rates_stale",
+		"1 if the currency rates haven't been fetched for longer than currency_converter.stale_alarm_seconds, 0 otherwise.")
+
 	metrics.syncerRequests = newCounter(cfg, reg,
 		"syncer_requests",
 		"Count of cookie sync requests where a syncer is a candidate to be synced labeled by syncer key and status.",
@@ -598,6 +792,18 @@ func newCounterWithoutLabels(cfg config.PrometheusMetrics, registry *prometheus.
 	return counter
 }
 
+func newGaugeWithoutLabels(cfg config.PrometheusMetrics, registry *prometheus.Registry, name, help string) prometheus.Gauge {
+	opts := prometheus.GaugeOpts{
+		Namespace: cfg.Namespace,
+		Subsystem: cfg.Subsystem,
+		Name:      name,
+		Help:      help,
+	}
+	gauge := prometheus.NewGauge(opts)
+	registry.MustRegister(gauge)
+	return gauge
+}
+
 func newHistogramVec(cfg config.PrometheusMetrics, registry *prometheus.Registry, name, help string, labels []string, buckets []float64) *prometheus.HistogramVec {
 	opts := prometheus.HistogramOpts{
 		Namespace: cfg.Namespace,
@@ -801,6 +1007,42 @@ func (m *Metrics) RecordAdapterConnections(adapterName openrtb_ext.BidderName, c
 	}).Observe(connWaitTime.Seconds())
 }
 
+// RecordAdapterTLSHandshake counts the TLS handshakes of new connections to adapter bidders by whether
+// they resumed a cached session.
+func (m *Metrics) RecordAdapterTLSHandshake(adapterName openrtb_ext.BidderName, resumed bool) {
+	if m.metricsDisabled.AdapterConnectionMetrics {
+		return
+	}
+	m.adapterTLSHandshakes.With(prometheus.Labels{
+		adapterLabel: strings.ToLower(string(adapterName)),
+		resumedLabel: strconv.FormatBool(resumed),
+	}).Inc()
+}
+
+// RecordAdapterHedgedRequest counts the requests sent to adapter bidders a second time, by whether the
+// second answer was the one used.
+func (m *Metrics) RecordAdapterHedgedRequest(adapterName openrtb_ext.BidderName, won bool) {
+	m.adapterHedgedRequests.With(prometheus.Labels{
+		adapterLabel: strings.ToLower(string(adapterName)),
+		wonLabel:     strconv.FormatBool(won),
+	}).Inc()
+}
+
+// RecordAdapterMaintenance counts the auctions adapter bidders were left out of for maintenance.
+func (m *Metrics) RecordAdapterMaintenance(adapterName openrtb_ext.BidderName, reason metrics.BidderMaintenance) {
+	m.adapterMaintenance.With(prometheus.Labels{
+		adapterLabel: strings.ToLower(string(adapterName)),
+		reasonLabel:  string(reason),
+	}).Inc()
+}
+
+func (m *Metrics) RecordAdapterConnectionWarmup(adapterName openrtb_ext.BidderName, result metrics.ConnectionWarmupResult) {
+	m.adapterConnectionWarmups.With(prometheus.Labels{
+		adapterLabel:      strings.ToLower(string(adapterName)),
+		warmupResultLabel: string(result),
+	}).Inc()
+}
+
 func (m *Metrics) RecordDNSTime(dnsLookupTime time.Duration) {
 	m.dnsLookupTimer.Observe(dnsLookupTime.Seconds())
 }
@@ -813,6 +1055,162 @@ func (m *Metrics) RecordBidderServerResponseTime(bidderServerResponseTime time.D
 	m.bidderServerResponseTimer.Observe(bidderServerResponseTime.Seconds())
 }
 
+func (m *Metrics) RecordBidderRequestPool(runningWorkers int, queuedRequests int) {
+	m.bidderRequestPoolRunning.Set(float64(runningWorkers))
+	m.bidderRequestPoolQueued.Set(float64(queuedRequests))
+}
+
+func (m *Metrics) RecordBidderRequestShed(adapterName openrtb_ext.BidderName) {
+	m.bidderRequestsShed.With(prometheus.Labels{
+		adapterLabel: strings.ToLower(string(adapterName)),
+	}).Inc()
+}
+
+func (m *Metrics) RecordAdapterResponseCacheResult(adapterName openrtb_ext.BidderName, cacheResult metrics.CacheResult) {
+	m.adapterResponseCache.With(prometheus.Labels{
+		adapterLabel:     strings.ToLower(string(adapterName)),
+		cacheResultLabel: string(cacheResult),
+	}).Inc()
+}
+
+func (m *Metrics) RecordLoadShedding(action metrics.LoadSheddingAction) {
+	m.loadShedding.With(prometheus.Labels{
+		actionLabel: string(action),
+	}).Inc()
+}
+
+func (m *Metrics) RecordIVT(reason metrics.IVTReason, action metrics.IVTAction) {
+	m.ivtRequests.With(prometheus.Labels{
+		reasonLabel: string(reason),
+		actionLabel: string(action),
+	}).Inc()
+}
+
+func (m *Metrics) RecordRequestLimitExceeded(limit metrics.RequestLimit) {
+	m.requestLimitsExceeded.With(prometheus.Labels{
+		limitLabel: string(limit),
+	}).Inc()
+}
+
+func (m *Metrics) RecordRequestNormalization(fix metrics.RequestNormalization) {
+	m.requestNormalizations.With(prometheus.Labels{
+		fixLabel: string(fix),
+	}).Inc()
+}
+
+func (m *Metrics) RecordAPIKey(status metrics.APIKeyStatus) {
+	m.apiKeyRequests.With(prometheus.Labels{
+		statusLabel: string(status),
+	}).Inc()
+}
+
+func (m *Metrics) RecordRateLimit(limit metrics.RateLimit, outcome metrics.RateLimitOutcome) {
+	m.rateLimitChecks.With(prometheus.Labels{
+		limitLabel:  string(limit),
+		statusLabel: string(outcome),
+	}).Inc()
+}
+
+func (m *Metrics) RecordGeoLookup(status metrics.GeoLookupStatus, duration time.Duration) {
+	m.geoLookupTimer.With(prometheus.Labels{
+		statusLabel: string(status),
+	}).Observe(duration.Seconds())
+}
+
+func (m *Metrics) RecordContentClassification(status metrics.ContentClassificationStatus, duration time.Duration) {
+	m.contentClassificationTimer.With(prometheus.Labels{
+		statusLabel: string(status),
+	}).Observe(duration.Seconds())
+}
+
+func (m *Metrics) RecordLatencyBudgetStage(stage metrics.LatencyBudgetStage, used time.Duration, overrun bool) {
+	m.latencyBudgetStageTimer.With(prometheus.Labels{
+		stageLabel: string(stage),
+	}).Observe(used.Seconds())
+
+	if overrun {
+		m.latencyBudgetOverruns.With(prometheus.Labels{
+			stageLabel: string(stage),
+		}).Inc()
+	}
+}
+
+func (m *Metrics) RecordTrafficShadow(status metrics.TrafficShadowStatus) {
+	m.trafficShadowRequests.With(prometheus.Labels{
+		statusLabel: string(status),
+	}).Inc()
+}
+
+func (m *Metrics) RecordTestRequest(requestType metrics.RequestType) {
+	m.testRequests.With(prometheus.Labels{
+		requestTypeLabel: string(requestType),
+	}).Inc()
+}
+
+func (m *Metrics) RecordWebhook(status metrics.WebhookStatus) {
+	m.webhookEvents.With(prometheus.Labels{
+		statusLabel: string(status),
+	}).Inc()
+}
+
+func (m *Metrics) RecordAuctionEventWebhook(status metrics.WebhookStatus) {
+	m.auctionEventWebhookEvents.With(prometheus.Labels{
+		statusLabel: string(status),
+	}).Inc()
+}
+
+func (m *Metrics) RecordCurrencyRatesFetch(status metrics.CurrencyRatesFetchStatus) {
+	m.currencyRatesFetches.With(prometheus.Labels{
+		statusLabel: string(status),
+	}).Inc()
+}
+
+func (m *Metrics) RecordCurrencyRatesStale(stale bool) {
+	if stale {
+		m.currencyRatesStale.Set(1)
+	} else {
+		m.currencyRatesStale.Set(0)
+	}
+}
+
+func (m *Metrics) RecordAdapterCanary(labels metrics.AdapterCanaryLabels, bids int, length time.Duration) {
+	adapter := strings.ToLower(string(labels.Adapter))
+	m.adapterCanaryRequests.With(prometheus.Labels{
+		adapterLabel: adapter,
+		versionLabel: string(labels.Version),
+		successLabel: strconv.FormatBool(labels.Success),
+	}).Inc()
+	m.adapterCanaryBids.With(prometheus.Labels{
+		adapterLabel: adapter,
+		versionLabel: string(labels.Version),
+	}).Add(float64(bids))
+	m.adapterCanaryRequestsTimer.With(prometheus.Labels{
+		adapterLabel: adapter,
+		versionLabel: string(labels.Version),
+	}).Observe(length.Seconds())
+}
+
+func (m *Metrics) RecordAdapterPIIViolation(labels metrics.AdapterPIIViolationLabels) {
+	m.adapterPIIViolations.With(prometheus.Labels{
+		adapterLabel:   strings.ToLower(string(labels.Adapter)),
+		sourceLabel:    string(labels.Source),
+		violationLabel: string(labels.Type),
+	}).Inc()
+}
+
+func (m *Metrics) RecordAdapterFieldsWithheld(adapterName openrtb_ext.BidderName, fields int) {
+	m.adapterFieldsWithheld.With(prometheus.Labels{
+		adapterLabel: strings.ToLower(string(adapterName)),
+	}).Add(float64(fields))
+}
+
+func (m *Metrics) RecordAdapterFloorConversion(adapterName openrtb_ext.BidderName, success bool) {
+	m.adapterFloorConversions.With(prometheus.Labels{
+		adapterLabel: strings.ToLower(string(adapterName)),
+		successLabel: strconv.FormatBool(success),
+	}).Inc()
+}
+
 func (m *Metrics) RecordAdapterPanic(labels metrics.AdapterLabels) {
 	m.adapterPanics.With(prometheus.Labels{
 		adapterLabel: strings.ToLower(string(labels.Adapter)),
@@ -895,6 +1293,12 @@ func (m *Metrics) RecordAccountCacheResult(cacheResult metrics.CacheResult, inc
 	}).Add(float64(inc))
 }
 
+func (m *Metrics) RecordCurrencyConversionCacheResult(cacheResult metrics.CacheResult, inc int) {
+	m.currencyConversionCache.With(prometheus.Labels{
+		cacheResultLabel: string(cacheResult),
+	}).Add(float64(inc))
+}
+
 func (m *Metrics) RecordPrebidCacheRequestTime(success bool, length time.Duration) {
 	m.prebidCacheWriteTimer.With(prometheus.Labels{
 		successLabel: strconv.FormatBool(success),
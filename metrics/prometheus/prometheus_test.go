@@ -1159,6 +1159,15 @@ func TestStoredImpCacheResultMetric(t *testing.T) {
 		})
 }
 
+func TestCurrencyConversionCacheResultMetric(t *testing.T) {
+	m := createMetricsForTesting()
+	m.RecordCurrencyConversionCacheResult(metrics.CacheHit, 12)
+	m.RecordCurrencyConversionCacheResult(metrics.CacheMiss, 3)
+
+	assertCounterVecValue(t, "", "currencyConversionCache:hit", m.currencyConversionCache, 12, prometheus.Labels{cacheResultLabel: string(metrics.CacheHit)})
+	assertCounterVecValue(t, "", "currencyConversionCache:miss", m.currencyConversionCache, 3, prometheus.Labels{cacheResultLabel: string(metrics.CacheMiss)})
+}
+
 func TestAccountCacheResultMetric(t *testing.T) {
 	m := createMetricsForTesting()
 
@@ -1516,6 +1525,261 @@ func TestRecordBidderServerResponseTime(t *testing.T) {
 	}
 }
 
+func TestRecordBidderRequestPool(t *testing.T) {
+	pm := createMetricsForTesting()
+	pm.RecordBidderRequestPool(10, 3)
+	pm.RecordBidderRequestShed(openrtb_ext.BidderName("Adapter"))
+
+	m := dto.Metric{}
+	pm.bidderRequestPoolRunning.Write(&m)
+	assert.Equal(t, float64(10), m.GetGauge().GetValue())
+	pm.bidderRequestPoolQueued.Write(&m)
+	assert.Equal(t, float64(3), m.GetGauge().GetValue())
+	assertCounterVecValue(t, "", "bidderRequestsShed", pm.bidderRequestsShed, 1, prometheus.Labels{adapterLabel: "adapter"})
+}
+
+func TestRecordAdapterResponseCacheResult(t *testing.T) {
+	pm := createMetricsForTesting()
+	pm.RecordAdapterResponseCacheResult(openrtb_ext.BidderName("Adapter"), metrics.CacheHit)
+	pm.RecordAdapterResponseCacheResult(openrtb_ext.BidderName("Adapter"), metrics.CacheHit)
+	pm.RecordAdapterResponseCacheResult(openrtb_ext.BidderName("Adapter"), metrics.CacheMiss)
+
+	assertCounterVecValue(t, "", "adapterResponseCache", pm.adapterResponseCache, 2, prometheus.Labels{adapterLabel: "adapter", cacheResultLabel: "hit"})
+	assertCounterVecValue(t, "", "adapterResponseCache", pm.adapterResponseCache, 1, prometheus.Labels{adapterLabel: "adapter", cacheResultLabel: "miss"})
+}
+
+func TestRecordLoadShedding(t *testing.T) {
+	pm := createMetricsForTesting()
+	pm.RecordLoadShedding(metrics.LoadSheddingMemoryRejected)
+	pm.RecordLoadShedding(metrics.LoadSheddingMemoryRejected)
+	pm.RecordLoadShedding(metrics.LoadSheddingMemoryDegraded)
+
+	assertCounterVecValue(t, "", "loadShedding", pm.loadShedding, 2, prometheus.Labels{actionLabel: string(metrics.LoadSheddingMemoryRejected)})
+	assertCounterVecValue(t, "", "loadShedding", pm.loadShedding, 1, prometheus.Labels{actionLabel: string(metrics.LoadSheddingMemoryDegraded)})
+}
+
+func TestRecordTrafficShadow(t *testing.T) {
+	pm := createMetricsForTesting()
+	pm.RecordTrafficShadow(metrics.TrafficShadowSent)
+	pm.RecordTrafficShadow(metrics.TrafficShadowSent)
+	pm.RecordTrafficShadow(metrics.TrafficShadowFailed)
+
+	assertCounterVecValue(t, "", "trafficShadowRequests", pm.trafficShadowRequests, 2, prometheus.Labels{statusLabel: string(metrics.TrafficShadowSent)})
+	assertCounterVecValue(t, "", "trafficShadowRequests", pm.trafficShadowRequests, 1, prometheus.Labels{statusLabel: string(metrics.TrafficShadowFailed)})
+}
+
+func TestRecordTestRequest(t *testing.T) {
+	pm := createMetricsForTesting()
+	pm.RecordTestRequest(metrics.ReqTypeORTB2Web)
+	pm.RecordTestRequest(metrics.ReqTypeORTB2Web)
+	pm.RecordTestRequest(metrics.ReqTypeAMP)
+
+	assertCounterVecValue(t, "", "testRequests", pm.testRequests, 2, prometheus.Labels{requestTypeLabel: string(metrics.ReqTypeORTB2Web)})
+	assertCounterVecValue(t, "", "testRequests", pm.testRequests, 1, prometheus.Labels{requestTypeLabel: string(metrics.ReqTypeAMP)})
+}
+
+func TestRecordWebhook(t *testing.T) {
+	pm := createMetricsForTesting()
+	pm.RecordWebhook(metrics.WebhookDelivered)
+	pm.RecordWebhook(metrics.WebhookDelivered)
+	pm.RecordWebhook(metrics.WebhookDropped)
+
+	assertCounterVecValue(t, "", "webhookEvents", pm.webhookEvents, 2, prometheus.Labels{statusLabel: string(metrics.WebhookDelivered)})
+	assertCounterVecValue(t, "", "webhookEvents", pm.webhookEvents, 1, prometheus.Labels{statusLabel: string(metrics.WebhookDropped)})
+}
+
+func TestRecordAuctionEventWebhook(t *testing.T) {
+	pm := createMetricsForTesting()
+	pm.RecordAuctionEventWebhook(metrics.WebhookDelivered)
+	pm.RecordAuctionEventWebhook(metrics.WebhookFailed)
+
+	assertCounterVecValue(t, "", "auctionEventWebhookEvents", pm.auctionEventWebhookEvents, 1, prometheus.Labels{statusLabel: string(metrics.WebhookDelivered)})
+	assertCounterVecValue(t, "", "auctionEventWebhookEvents", pm.auctionEventWebhookEvents, 1, prometheus.Labels{statusLabel: string(metrics.WebhookFailed)})
+}
+
+func TestRecordCurrencyRates(t *testing.T) {
+	pm := createMetricsForTesting()
+	pm.RecordCurrencyRatesFetch(metrics.CurrencyRatesFetched)
+	pm.RecordCurrencyRatesFetch(metrics.CurrencyRatesFailover)
+	pm.RecordCurrencyRatesFetch(metrics.CurrencyRatesFailover)
+	pm.RecordCurrencyRatesStale(true)
+
+	assertCounterVecValue(t, "", "currencyRatesFetches", pm.currencyRatesFetches, 1, prometheus.Labels{statusLabel: string(metrics.CurrencyRatesFetched)})
+	assertCounterVecValue(t, "", "currencyRatesFetches", pm.currencyRatesFetches, 2, prometheus.Labels{statusLabel: string(metrics.CurrencyRatesFailover)})
+	m := dto.Metric{}
+	pm.currencyRatesStale.Write(&m)
+	assert.Equal(t, float64(1), m.GetGauge().GetValue())
+}
+
+func TestRecordAdapterCanary(t *testing.T) {
+	pm := createMetricsForTesting()
+	pm.RecordAdapterCanary(metrics.AdapterCanaryLabels{Adapter: openrtb_ext.BidderAppnexus, Version: metrics.CanaryControl, Success: true}, 2, 100*time.Millisecond)
+	pm.RecordAdapterCanary(metrics.AdapterCanaryLabels{Adapter: openrtb_ext.BidderAppnexus, Version: metrics.CanaryCanary, Success: false}, 0, 10*time.Millisecond)
+
+	assertCounterVecValue(t, "", "adapterCanaryRequests", pm.adapterCanaryRequests, 1, prometheus.Labels{adapterLabel: "appnexus", versionLabel: "control", successLabel: "true"})
+	assertCounterVecValue(t, "", "adapterCanaryRequests", pm.adapterCanaryRequests, 1, prometheus.Labels{adapterLabel: "appnexus", versionLabel: "canary", successLabel: "false"})
+	assertCounterVecValue(t, "", "adapterCanaryBids", pm.adapterCanaryBids, 2, prometheus.Labels{adapterLabel: "appnexus", versionLabel: "control"})
+	assertCounterVecValue(t, "", "adapterCanaryBids", pm.adapterCanaryBids, 0, prometheus.Labels{adapterLabel: "appnexus", versionLabel: "canary"})
+	assertHistogram(t, "adapterCanaryRequestsTimer", getHistogramFromHistogramVec(pm.adapterCanaryRequestsTimer, versionLabel, "canary"), 1, 0.01)
+}
+
+func TestRecordAdapterPIIViolation(t *testing.T) {
+	pm := createMetricsForTesting()
+	pm.RecordAdapterPIIViolation(metrics.AdapterPIIViolationLabels{Adapter: openrtb_ext.BidderAppnexus, Source: metrics.PIIViolationSourceAdapter, Type: metrics.PIIViolationEmail})
+	pm.RecordAdapterPIIViolation(metrics.AdapterPIIViolationLabels{Adapter: openrtb_ext.BidderAppnexus, Source: metrics.PIIViolationSourceAdapter, Type: metrics.PIIViolationEmail})
+	pm.RecordAdapterPIIViolation(metrics.AdapterPIIViolationLabels{Adapter: openrtb_ext.BidderAppnexus, Source: metrics.PIIViolationSourceModule, Type: metrics.PIIViolationUserID})
+
+	assertCounterVecValue(t, "", "adapterPIIViolations", pm.adapterPIIViolations, 2, prometheus.Labels{adapterLabel: "appnexus", sourceLabel: "adapter", violationLabel: "email"})
+	assertCounterVecValue(t, "", "adapterPIIViolations", pm.adapterPIIViolations, 1, prometheus.Labels{adapterLabel: "appnexus", sourceLabel: "module", violationLabel: "user_id"})
+}
+
+func TestRecordAdapterFieldsWithheld(t *testing.T) {
+	pm := createMetricsForTesting()
+	pm.RecordAdapterFieldsWithheld(openrtb_ext.BidderAppnexus, 2)
+	pm.RecordAdapterFieldsWithheld(openrtb_ext.BidderAppnexus, 3)
+
+	assertCounterVecValue(t, "", "adapterFieldsWithheld", pm.adapterFieldsWithheld, 5, prometheus.Labels{adapterLabel: "appnexus"})
+}
+
+func TestRecordAdapterFloorConversion(t *testing.T) {
+	pm := createMetricsForTesting()
+	pm.RecordAdapterFloorConversion(openrtb_ext.BidderAppnexus, true)
+	pm.RecordAdapterFloorConversion(openrtb_ext.BidderAppnexus, true)
+	pm.RecordAdapterFloorConversion(openrtb_ext.BidderAppnexus, false)
+
+	assertCounterVecValue(t, "", "adapterFloorConversions", pm.adapterFloorConversions, 2, prometheus.Labels{adapterLabel: "appnexus", successLabel: "true"})
+	assertCounterVecValue(t, "", "adapterFloorConversions", pm.adapterFloorConversions, 1, prometheus.Labels{adapterLabel: "appnexus", successLabel: "false"})
+}
+
+func TestRecordLatencyBudgetStage(t *testing.T) {
+	pm := createMetricsForTesting()
+	pm.RecordLatencyBudgetStage(metrics.LatencyBudgetBidders, 200*time.Millisecond, false)
+	pm.RecordLatencyBudgetStage(metrics.LatencyBudgetBidders, 300*time.Millisecond, true)
+	pm.RecordLatencyBudgetStage(metrics.LatencyBudgetCacheWrite, 10*time.Millisecond, false)
+
+	bidders := getHistogramFromHistogramVec(pm.latencyBudgetStageTimer, stageLabel, string(metrics.LatencyBudgetBidders))
+	assertHistogram(t, "bidders", bidders, 2, 0.5)
+	cacheWrite := getHistogramFromHistogramVec(pm.latencyBudgetStageTimer, stageLabel, string(metrics.LatencyBudgetCacheWrite))
+	assertHistogram(t, "cache_write", cacheWrite, 1, 0.01)
+
+	assertCounterVecValue(t, "", "latencyBudgetOverruns", pm.latencyBudgetOverruns, 1, prometheus.Labels{stageLabel: string(metrics.LatencyBudgetBidders)})
+	assertCounterVecValue(t, "", "latencyBudgetOverruns", pm.latencyBudgetOverruns, 0, prometheus.Labels{stageLabel: string(metrics.LatencyBudgetCacheWrite)})
+}
+
+func TestRecordIVT(t *testing.T) {
+	pm := createMetricsForTesting()
+	pm.RecordIVT(metrics.IVTBotUserAgent, metrics.IVTBlocked)
+	pm.RecordIVT(metrics.IVTBotUserAgent, metrics.IVTBlocked)
+	pm.RecordIVT(metrics.IVTDatacenterIP, metrics.IVTTagged)
+
+	assertCounterVecValue(t, "", "ivtRequests", pm.ivtRequests, 2, prometheus.Labels{reasonLabel: string(metrics.IVTBotUserAgent), actionLabel: string(metrics.IVTBlocked)})
+	assertCounterVecValue(t, "", "ivtRequests", pm.ivtRequests, 1, prometheus.Labels{reasonLabel: string(metrics.IVTDatacenterIP), actionLabel: string(metrics.IVTTagged)})
+}
+
+func TestRecordRequestLimitExceeded(t *testing.T) {
+	pm := createMetricsForTesting()
+	pm.RecordRequestLimitExceeded(metrics.RequestLimitImps)
+	pm.RecordRequestLimitExceeded(metrics.RequestLimitImps)
+	pm.RecordRequestLimitExceeded(metrics.RequestLimitDataSegments)
+
+	assertCounterVecValue(t, "", "requestLimitsExceeded", pm.requestLimitsExceeded, 2, prometheus.Labels{limitLabel: string(metrics.RequestLimitImps)})
+	assertCounterVecValue(t, "", "requestLimitsExceeded", pm.requestLimitsExceeded, 1, prometheus.Labels{limitLabel: string(metrics.RequestLimitDataSegments)})
+}
+
+func TestRecordRequestNormalization(t *testing.T) {
+	pm := createMetricsForTesting()
+	pm.RecordRequestNormalization(metrics.RequestNormalizationEnumCase)
+	pm.RecordRequestNormalization(metrics.RequestNormalizationEnumCase)
+	pm.RecordRequestNormalization(metrics.RequestNormalizationConsentLocation)
+
+	assertCounterVecValue(t, "", "requestNormalizations", pm.requestNormalizations, 2, prometheus.Labels{fixLabel: string(metrics.RequestNormalizationEnumCase)})
+	assertCounterVecValue(t, "", "requestNormalizations", pm.requestNormalizations, 1, prometheus.Labels{fixLabel: string(metrics.RequestNormalizationConsentLocation)})
+}
+
+func TestRecordAPIKey(t *testing.T) {
+	pm := createMetricsForTesting()
+	pm.RecordAPIKey(metrics.APIKeyAccepted)
+	pm.RecordAPIKey(metrics.APIKeyAccepted)
+	pm.RecordAPIKey(metrics.APIKeyRateLimited)
+
+	assertCounterVecValue(t, "", "apiKeyRequests", pm.apiKeyRequests, 2, prometheus.Labels{statusLabel: string(metrics.APIKeyAccepted)})
+	assertCounterVecValue(t, "", "apiKeyRequests", pm.apiKeyRequests, 1, prometheus.Labels{statusLabel: string(metrics.APIKeyRateLimited)})
+}
+
+func TestRecordRateLimit(t *testing.T) {
+	pm := createMetricsForTesting()
+	pm.RecordRateLimit(metrics.RateLimitBidderQPS, metrics.RateLimitLimited)
+	pm.RecordRateLimit(metrics.RateLimitBidderQPS, metrics.RateLimitLimited)
+	pm.RecordRateLimit(metrics.RateLimitDealPacing, metrics.RateLimitError)
+
+	assertCounterVecValue(t, "", "rateLimitChecks", pm.rateLimitChecks, 2, prometheus.Labels{limitLabel: string(metrics.RateLimitBidderQPS), statusLabel: string(metrics.RateLimitLimited)})
+	assertCounterVecValue(t, "", "rateLimitChecks", pm.rateLimitChecks, 1, prometheus.Labels{limitLabel: string(metrics.RateLimitDealPacing), statusLabel: string(metrics.RateLimitError)})
+}
+
+func TestRecordGeoLookup(t *testing.T) {
+	pm := createMetricsForTesting()
+	pm.RecordGeoLookup(metrics.GeoLookupFound, 20*time.Microsecond)
+	pm.RecordGeoLookup(metrics.GeoLookupFound, 30*time.Microsecond)
+	pm.RecordGeoLookup(metrics.GeoLookupError, 10*time.Microsecond)
+
+	found := getHistogramFromHistogramVec(pm.geoLookupTimer, statusLabel, string(metrics.GeoLookupFound))
+	assertHistogram(t, "found", found, 2, 0.00005)
+	lookupErrors := getHistogramFromHistogramVec(pm.geoLookupTimer, statusLabel, string(metrics.GeoLookupError))
+	assertHistogram(t, "error", lookupErrors, 1, 0.00001)
+}
+
+func TestRecordContentClassification(t *testing.T) {
+	pm := createMetricsForTesting()
+	pm.RecordContentClassification(metrics.ContentClassificationClassified, 20*time.Millisecond)
+	pm.RecordContentClassification(metrics.ContentClassificationSkipped, 0)
+	pm.RecordContentClassification(metrics.ContentClassificationSkipped, 0)
+
+	classified := getHistogramFromHistogramVec(pm.contentClassificationTimer, statusLabel, string(metrics.ContentClassificationClassified))
+	assertHistogram(t, "classified", classified, 1, 0.02)
+	skipped := getHistogramFromHistogramVec(pm.contentClassificationTimer, statusLabel, string(metrics.ContentClassificationSkipped))
+	assertHistogram(t, "skipped", skipped, 2, 0)
+}
+
+func TestRecordAdapterConnectionWarmup(t *testing.T) {
+	pm := createMetricsForTesting()
+	pm.RecordAdapterConnectionWarmup(openrtb_ext.BidderName("Adapter"), metrics.ConnectionWarmupCreated)
+	pm.RecordAdapterConnectionWarmup(openrtb_ext.BidderName("Adapter"), metrics.ConnectionWarmupFailed)
+	pm.RecordAdapterConnectionWarmup(openrtb_ext.BidderName("Adapter"), metrics.ConnectionWarmupFailed)
+
+	assertCounterVecValue(t, "", "adapterConnectionWarmups", pm.adapterConnectionWarmups, 1, prometheus.Labels{adapterLabel: "adapter", warmupResultLabel: "created"})
+	assertCounterVecValue(t, "", "adapterConnectionWarmups", pm.adapterConnectionWarmups, 2, prometheus.Labels{adapterLabel: "adapter", warmupResultLabel: "failed"})
+}
+
+func TestRecordAdapterTLSHandshake(t *testing.T) {
+	pm := createMetricsForTesting()
+	pm.RecordAdapterTLSHandshake(openrtb_ext.BidderName("Adapter"), false)
+	pm.RecordAdapterTLSHandshake(openrtb_ext.BidderName("Adapter"), true)
+	pm.RecordAdapterTLSHandshake(openrtb_ext.BidderName("Adapter"), true)
+
+	assertCounterVecValue(t, "", "adapterTLSHandshakes", pm.adapterTLSHandshakes, 1, prometheus.Labels{adapterLabel: "adapter", resumedLabel: "false"})
+	assertCounterVecValue(t, "", "adapterTLSHandshakes", pm.adapterTLSHandshakes, 2, prometheus.Labels{adapterLabel: "adapter", resumedLabel: "true"})
+}
+
+func TestRecordAdapterHedgedRequest(t *testing.T) {
+	pm := createMetricsForTesting()
+	pm.RecordAdapterHedgedRequest(openrtb_ext.BidderName("Adapter"), true)
+	pm.RecordAdapterHedgedRequest(openrtb_ext.BidderName("Adapter"), false)
+	pm.RecordAdapterHedgedRequest(openrtb_ext.BidderName("Adapter"), false)
+
+	assertCounterVecValue(t, "", "adapterHedgedRequests", pm.adapterHedgedRequests, 1, prometheus.Labels{adapterLabel: "adapter", wonLabel: "true"})
+	assertCounterVecValue(t, "", "adapterHedgedRequests", pm.adapterHedgedRequests, 2, prometheus.Labels{adapterLabel: "adapter", wonLabel: "false"})
+}
+
+func TestRecordAdapterMaintenance(t *testing.T) {
+	pm := createMetricsForTesting()
+	pm.RecordAdapterMaintenance(openrtb_ext.BidderName("Adapter"), metrics.BidderMaintenanceWindow)
+	pm.RecordAdapterMaintenance(openrtb_ext.BidderName("Adapter"), metrics.BidderMaintenanceKillSwitch)
+	pm.RecordAdapterMaintenance(openrtb_ext.BidderName("Adapter"), metrics.BidderMaintenanceKillSwitch)
+
+	assertCounterVecValue(t, "", "adapterMaintenance", pm.adapterMaintenance, 1, prometheus.Labels{adapterLabel: "adapter", reasonLabel: "window"})
+	assertCounterVecValue(t, "", "adapterMaintenance", pm.adapterMaintenance, 2, prometheus.Labels{adapterLabel: "adapter", reasonLabel: "kill_switch"})
+}
+
 func TestRecordAdapterConnections(t *testing.T) {
 	adapterName := openrtb_ext.BidderName("Adapter")
 	lowerCasedAdapterName := "adapter"
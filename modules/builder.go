@@ -1,6 +1,8 @@
 package modules
 
 import (
+	prebidAdstxt "github.com/prebid/prebid-server/v2/modules/prebid/adstxt"
+	prebidBidrules "github.com/prebid/prebid-server/v2/modules/prebid/bidrules"
 	prebidOrtb2blocking "github.com/prebid/prebid-server/v2/modules/prebid/ortb2blocking"
 )
 
@@ -9,6 +11,8 @@ import (
 func builders() ModuleBuilders {
 	return ModuleBuilders{
 		"prebid": {
+			"adstxt":        prebidAdstxt.Builder,
+			"bidrules":      prebidBidrules.Builder,
 			"ortb2blocking": prebidOrtb2blocking.Builder,
 		},
 	}
@@ -45,6 +45,12 @@ func createModuleStageNamesCollection(modules map[string]interface{}) (map[strin
 			moduleStageNameCollector = addModuleStageName(moduleStageNameCollector, id, stageName)
 		}
 
+		if _, ok := hook.(hookstage.RawBidderResponsePerSeat); ok {
+			added = true
+			stageName := hooks.StageRawBidderResponsePerSeat.String()
+			moduleStageNameCollector = addModuleStageName(moduleStageNameCollector, id, stageName)
+		}
+
 		if _, ok := hook.(hookstage.AllProcessedBidResponses); ok {
 			added = true
 			stageName := hooks.StageAllProcessedBidResponses.String()
@@ -4,9 +4,9 @@ import (
 	"encoding/json"
 	"fmt"
 
-	"github.com/golang/glog"
 	"github.com/prebid/prebid-server/v2/config"
 	"github.com/prebid/prebid-server/v2/hooks"
+	"github.com/prebid/prebid-server/v2/logger"
 	"github.com/prebid/prebid-server/v2/modules/moduledeps"
 	"github.com/prebid/prebid-server/v2/util/jsonutil"
 )
@@ -71,7 +71,7 @@ func (m *builder) Build(
 			}
 
 			if !isEnabled {
-				glog.Infof("Skip %s module, disabled.", id)
+				logger.Infof("Skip %s module, disabled.", id)
 				continue
 			}
 
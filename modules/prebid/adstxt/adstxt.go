@@ -0,0 +1,50 @@
+package adstxt
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+)
+
+// sellers are the seller IDs an ads.txt file authorizes, by ad system domain.
+type sellers map[string]map[string]struct{}
+
+// parseAdsTxt returns the sellers authorized by the records of an ads.txt or app-ads.txt file. Comments,
+// variables such as contact= and malformed records are skipped.
+func parseAdsTxt(data []byte) sellers {
+	authorized := make(sellers)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(nil, len(data)+1)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.IndexByte(line, '#'); i >= 0 {
+			line = line[:i]
+		}
+		fields := strings.Split(line, ",")
+		if len(fields) < 3 {
+			continue
+		}
+		domain := strings.ToLower(strings.TrimSpace(fields[0]))
+		sellerID := strings.TrimSpace(fields[1])
+		relationship := strings.ToUpper(strings.TrimSpace(fields[2]))
+		if domain == "" || sellerID == "" || (relationship != "DIRECT" && relationship != "RESELLER") {
+			continue
+		}
+		if authorized[domain] == nil {
+			authorized[domain] = make(map[string]struct{})
+		}
+		authorized[domain][sellerID] = struct{}{}
+	}
+	return authorized
+}
+
+// authorizes returns true if any of the seller IDs of the ad system domain are authorized.
+func (s sellers) authorizes(domain string, sellerIDs []string) bool {
+	ids := s[domain]
+	for _, id := range sellerIDs {
+		if _, ok := ids[id]; ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,36 @@
+package adstxt
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseAdsTxt(t *testing.T) {
+	data := []byte(`# ads.txt of example.com
+contact=ads@example.com
+subdomain=news.example.com
+
+PBS-Host.com, 1001, DIRECT, abc123
+pbs-host.com,1002,reseller # comment
+ssp.com , pub-1 , DIRECT
+malformed.com, 1003
+invalid.com, 1004, OTHER
+, 1005, DIRECT
+`)
+
+	authorized := parseAdsTxt(data)
+
+	assert.Equal(t, sellers{
+		"pbs-host.com": {"1001": {}, "1002": {}},
+		"ssp.com":      {"pub-1": {}},
+	}, authorized)
+	assert.True(t, authorized.authorizes("pbs-host.com", []string{"9999", "1002"}))
+	assert.False(t, authorized.authorizes("pbs-host.com", []string{"pub-1"}))
+	assert.False(t, authorized.authorizes("other.com", []string{"1001"}))
+}
+
+func TestParseAdsTxtEmpty(t *testing.T) {
+	assert.Empty(t, parseAdsTxt(nil))
+	assert.Empty(t, parseAdsTxt([]byte("<html><body>Not found</body></html>")))
+}
@@ -0,0 +1,161 @@
+package adstxt
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/prebid/prebid-server/v2/util/jsonutil"
+)
+
+// fileStatus is what came of crawling a file.
+type fileStatus string
+
+const (
+	// fileFound is a file which was crawled
+	fileFound fileStatus = "found"
+	// fileMissing is a domain without the file
+	fileMissing fileStatus = "missing"
+	// fileUnavailable is a file which couldn't be crawled
+	fileUnavailable fileStatus = "unavailable"
+)
+
+// fileKey is the file of a domain: ads.txt for sites, and app-ads.txt for apps.
+type fileKey struct {
+	Domain string `json:"domain"`
+	App    bool   `json:"app,omitempty"`
+}
+
+func (k fileKey) path() string {
+	if k.App {
+		return "/app-ads.txt"
+	}
+	return "/ads.txt"
+}
+
+type cacheEntry struct {
+	Status  fileStatus          `json:"status"`
+	Sellers map[string][]string `json:"sellers,omitempty"`
+	Expires time.Time           `json:"expires"`
+	sellers sellers
+}
+
+// cache keeps the crawled files, and saves them to a file so that they needn't all be crawled again when
+// the server restarts. Entries are used after they expire, until they've been crawled again.
+type cache struct {
+	mutex      sync.RWMutex
+	entries    map[fileKey]*cacheEntry
+	maxEntries int
+	file       string
+}
+
+func newCache(maxEntries int, file string) *cache {
+	return &cache{
+		entries:    make(map[fileKey]*cacheEntry),
+		maxEntries: maxEntries,
+		file:       file,
+	}
+}
+
+// get returns the entry of the file, and whether it has expired. The entry is nil if the file hasn't been
+// crawled.
+func (c *cache) get(key fileKey, now time.Time) (*cacheEntry, bool) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	entry, ok := c.entries[key]
+	if !ok {
+		return nil, true
+	}
+	return entry, !now.Before(entry.Expires)
+}
+
+func (c *cache) set(key fileKey, status fileStatus, authorized sellers, expires time.Time) {
+	entry := &cacheEntry{Status: status, Expires: expires, sellers: authorized}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if _, ok := c.entries[key]; !ok && len(c.entries) >= c.maxEntries {
+		c.evict()
+	}
+	c.entries[key] = entry
+}
+
+// evict removes the entry which expires first. It's only called when the cache is full, which should be
+// rare enough for a scan of the entries not to matter.
+func (c *cache) evict() {
+	var oldestKey fileKey
+	var oldest *cacheEntry
+	for key, entry := range c.entries {
+		if oldest == nil || entry.Expires.Before(oldest.Expires) {
+			oldestKey, oldest = key, entry
+		}
+	}
+	delete(c.entries, oldestKey)
+}
+
+type savedEntry struct {
+	fileKey
+	cacheEntry
+}
+
+// save writes the cache to its file. It's written to a temporary file first, so that the file is never
+// left half written.
+func (c *cache) save() error {
+	c.mutex.RLock()
+	saved := make([]savedEntry, 0, len(c.entries))
+	for key, entry := range c.entries {
+		sellerIDs := make(map[string][]string, len(entry.sellers))
+		for domain, ids := range entry.sellers {
+			for id := range ids {
+				sellerIDs[domain] = append(sellerIDs[domain], id)
+			}
+		}
+		saved = append(saved, savedEntry{fileKey: key, cacheEntry: cacheEntry{Status: entry.Status, Sellers: sellerIDs, Expires: entry.Expires}})
+	}
+	c.mutex.RUnlock()
+
+	data, err := jsonutil.Marshal(saved)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(c.file), filepath.Base(c.file)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), c.file)
+}
+
+// load reads the cache from its file, if there is one.
+func (c *cache) load() error {
+	data, err := os.ReadFile(c.file)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	var saved []savedEntry
+	if err := jsonutil.UnmarshalValid(data, &saved); err != nil {
+		return err
+	}
+	for _, entry := range saved {
+		authorized := make(sellers, len(entry.Sellers))
+		for domain, ids := range entry.Sellers {
+			authorized[domain] = make(map[string]struct{}, len(ids))
+			for _, id := range ids {
+				authorized[domain][id] = struct{}{}
+			}
+		}
+		c.set(entry.fileKey, entry.Status, authorized, entry.Expires)
+	}
+	return nil
+}
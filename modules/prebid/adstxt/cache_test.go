@@ -0,0 +1,81 @@
+package adstxt
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCacheGet(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := newCache(10, "")
+	key := fileKey{Domain: "example.com"}
+
+	entry, expired := c.get(key, now)
+	assert.Nil(t, entry)
+	assert.True(t, expired)
+
+	c.set(key, fileFound, sellers{"pbs-host.com": {"1001": {}}}, now.Add(time.Hour))
+	entry, expired = c.get(key, now)
+	require.NotNil(t, entry)
+	assert.Equal(t, fileFound, entry.Status)
+	assert.False(t, expired)
+
+	entry, expired = c.get(key, now.Add(time.Hour))
+	assert.NotNil(t, entry, "expired entries are used until they're crawled again")
+	assert.True(t, expired)
+
+	entry, _ = c.get(fileKey{Domain: "example.com", App: true}, now)
+	assert.Nil(t, entry, "app-ads.txt is cached apart from ads.txt")
+}
+
+func TestCacheEvictsTheEntryWhichExpiresFirst(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := newCache(2, "")
+	c.set(fileKey{Domain: "a.com"}, fileFound, nil, now.Add(2*time.Hour))
+	c.set(fileKey{Domain: "b.com"}, fileMissing, nil, now.Add(time.Hour))
+	c.set(fileKey{Domain: "a.com"}, fileFound, nil, now.Add(3*time.Hour))
+	assert.Len(t, c.entries, 2)
+
+	c.set(fileKey{Domain: "c.com"}, fileFound, nil, now.Add(4*time.Hour))
+
+	assert.Len(t, c.entries, 2)
+	assert.Contains(t, c.entries, fileKey{Domain: "a.com"})
+	assert.Contains(t, c.entries, fileKey{Domain: "c.com"})
+}
+
+func TestCacheSaveAndLoad(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "adstxt.json")
+	expires := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := newCache(10, file)
+	c.set(fileKey{Domain: "example.com"}, fileFound, sellers{"pbs-host.com": {"1001": {}, "1002": {}}}, expires)
+	c.set(fileKey{Domain: "example.com", App: true}, fileMissing, nil, expires)
+
+	require.NoError(t, c.save())
+
+	loaded := newCache(10, file)
+	require.NoError(t, loaded.load())
+	assert.Len(t, loaded.entries, 2)
+	entry := loaded.entries[fileKey{Domain: "example.com"}]
+	require.NotNil(t, entry)
+	assert.Equal(t, fileFound, entry.Status)
+	assert.True(t, expires.Equal(entry.Expires))
+	assert.Equal(t, sellers{"pbs-host.com": {"1001": {}, "1002": {}}}, entry.sellers)
+	assert.Equal(t, fileMissing, loaded.entries[fileKey{Domain: "example.com", App: true}].Status)
+}
+
+func TestCacheLoad(t *testing.T) {
+	dir := t.TempDir()
+
+	missing := newCache(10, filepath.Join(dir, "missing.json"))
+	assert.NoError(t, missing.load())
+	assert.Empty(t, missing.entries)
+
+	file := filepath.Join(dir, "invalid.json")
+	require.NoError(t, os.WriteFile(file, []byte(`{"domain":`), 0644))
+	assert.Error(t, newCache(10, file).load())
+}
@@ -0,0 +1,115 @@
+package adstxt
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/prebid/prebid-server/v2/util/jsonutil"
+)
+
+const (
+	actionTag   = "tag"
+	actionBlock = "block"
+)
+
+// hostConfig is the module config of the host. It says how ads.txt and app-ads.txt files are crawled and
+// cached.
+type hostConfig struct {
+	// SellerDomain is the ad system domain of the host in ads.txt files, for accounts which don't set one.
+	SellerDomain string `json:"seller_domain"`
+	// CacheTTLSeconds is how long a crawled file is used before it's crawled again.
+	CacheTTLSeconds int `json:"cache_ttl_seconds"`
+	// ErrorTTLSeconds is how long a crawl which failed is remembered before it's tried again.
+	ErrorTTLSeconds int `json:"error_ttl_seconds"`
+	// MaxEntries caps the number of domains in the cache.
+	MaxEntries int `json:"max_entries"`
+	// CacheFile is where the cache is saved, and loaded from on startup, if it's set.
+	CacheFile string `json:"cache_file"`
+	// SaveIntervalSeconds is how often the cache is saved to the cache file.
+	SaveIntervalSeconds int         `json:"save_interval_seconds"`
+	Crawl               crawlConfig `json:"crawl"`
+}
+
+type crawlConfig struct {
+	// PerSecond caps the number of files crawled each second.
+	PerSecond int `json:"per_second"`
+	// Workers is how many files may be crawled at once.
+	Workers int `json:"workers"`
+	// QueueSize is how many domains may wait to be crawled. Domains are dropped once the queue is full,
+	// and queued again by a later request.
+	QueueSize int `json:"queue_size"`
+	// TimeoutMs is how long a crawl may take.
+	TimeoutMs int `json:"timeout_ms"`
+	// MaxBytes is the most of a file which is read.
+	MaxBytes  int64  `json:"max_bytes"`
+	UserAgent string `json:"user_agent"`
+}
+
+func newHostConfig(data json.RawMessage) (hostConfig, error) {
+	cfg := hostConfig{
+		CacheTTLSeconds:     86400,
+		ErrorTTLSeconds:     3600,
+		MaxEntries:          100000,
+		SaveIntervalSeconds: 300,
+		Crawl: crawlConfig{
+			PerSecond: 10,
+			Workers:   4,
+			QueueSize: 1000,
+			TimeoutMs: 2000,
+			MaxBytes:  1 << 20,
+			UserAgent: "prebid-server-adstxt",
+		},
+	}
+	if len(data) > 0 {
+		if err := jsonutil.UnmarshalValid(data, &cfg); err != nil {
+			return cfg, fmt.Errorf("failed to parse config: %s", err)
+		}
+	}
+
+	if cfg.CacheTTLSeconds <= 0 || cfg.ErrorTTLSeconds <= 0 {
+		return cfg, fmt.Errorf("cache_ttl_seconds and error_ttl_seconds must be positive")
+	}
+	if cfg.MaxEntries <= 0 {
+		return cfg, fmt.Errorf("max_entries must be positive")
+	}
+	if cfg.CacheFile != "" && cfg.SaveIntervalSeconds <= 0 {
+		return cfg, fmt.Errorf("save_interval_seconds must be positive")
+	}
+	if cfg.Crawl.PerSecond <= 0 || cfg.Crawl.Workers <= 0 || cfg.Crawl.QueueSize <= 0 || cfg.Crawl.TimeoutMs <= 0 || cfg.Crawl.MaxBytes <= 0 {
+		return cfg, fmt.Errorf("crawl.per_second, crawl.workers, crawl.queue_size, crawl.timeout_ms and crawl.max_bytes must be positive")
+	}
+	return cfg, nil
+}
+
+// accountConfig is the module config of an account. It says which seller IDs of the host are the
+// account's, and what's done with requests from inventory which doesn't authorize them.
+type accountConfig struct {
+	// SellerDomain overrides the host's seller_domain.
+	SellerDomain string `json:"seller_domain"`
+	// SellerIDs are the account's seller IDs. The publisher ID of the request is used if there are none.
+	SellerIDs []string `json:"seller_ids"`
+	// Action is tag, where requests are only tagged with the result in the module's analytics tags, or
+	// block, where requests from inventory which doesn't authorize the account are rejected.
+	Action string `json:"action"`
+	// BlockUnavailable also rejects requests from inventory without an ads.txt file, or whose file
+	// couldn't be crawled, if the action is block.
+	BlockUnavailable bool `json:"block_unavailable"`
+}
+
+func newAccountConfig(data json.RawMessage, host hostConfig) (accountConfig, error) {
+	cfg := accountConfig{Action: actionTag}
+	if len(data) > 0 {
+		if err := jsonutil.UnmarshalValid(data, &cfg); err != nil {
+			return cfg, fmt.Errorf("failed to parse account config: %s", err)
+		}
+	}
+	if cfg.Action != actionTag && cfg.Action != actionBlock {
+		return cfg, fmt.Errorf("action must be %s or %s. Got %s", actionTag, actionBlock, cfg.Action)
+	}
+	if cfg.SellerDomain == "" {
+		cfg.SellerDomain = host.SellerDomain
+	}
+	cfg.SellerDomain = strings.ToLower(cfg.SellerDomain)
+	return cfg, nil
+}
@@ -0,0 +1,78 @@
+package adstxt
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewHostConfig(t *testing.T) {
+	testCases := []struct {
+		description   string
+		config        json.RawMessage
+		expectedError string
+	}{
+		{
+			description: "defaults",
+		},
+		{
+			description: "valid",
+			config:      json.RawMessage(`{"seller_domain":"pbs-host.com","cache_file":"/tmp/adstxt.json","crawl":{"workers":1}}`),
+		},
+		{
+			description:   "invalid-json",
+			config:        json.RawMessage(`{"seller_domain":`),
+			expectedError: "failed to parse config",
+		},
+		{
+			description:   "invalid-ttl",
+			config:        json.RawMessage(`{"error_ttl_seconds":0}`),
+			expectedError: "cache_ttl_seconds and error_ttl_seconds must be positive",
+		},
+		{
+			description:   "invalid-max-entries",
+			config:        json.RawMessage(`{"max_entries":-1}`),
+			expectedError: "max_entries must be positive",
+		},
+		{
+			description:   "invalid-save-interval",
+			config:        json.RawMessage(`{"cache_file":"/tmp/adstxt.json","save_interval_seconds":0}`),
+			expectedError: "save_interval_seconds must be positive",
+		},
+		{
+			description:   "invalid-crawl",
+			config:        json.RawMessage(`{"crawl":{"max_bytes":0}}`),
+			expectedError: "crawl.per_second, crawl.workers, crawl.queue_size, crawl.timeout_ms and crawl.max_bytes must be positive",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			_, err := newHostConfig(test.config)
+			if test.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, test.expectedError)
+			}
+		})
+	}
+}
+
+func TestNewAccountConfig(t *testing.T) {
+	host := hostConfig{SellerDomain: "pbs-host.com"}
+
+	cfg, err := newAccountConfig(nil, host)
+	assert.NoError(t, err)
+	assert.Equal(t, accountConfig{SellerDomain: "pbs-host.com", Action: actionTag}, cfg)
+
+	cfg, err = newAccountConfig(json.RawMessage(`{"seller_domain":"Other.com","seller_ids":["1"],"action":"block","block_unavailable":true}`), host)
+	assert.NoError(t, err)
+	assert.Equal(t, accountConfig{SellerDomain: "other.com", SellerIDs: []string{"1"}, Action: actionBlock, BlockUnavailable: true}, cfg)
+
+	_, err = newAccountConfig(json.RawMessage(`{"action":"drop"}`), host)
+	assert.EqualError(t, err, "action must be tag or block. Got drop")
+
+	_, err = newAccountConfig(json.RawMessage(`[]`), host)
+	assert.ErrorContains(t, err, "failed to parse account config")
+}
@@ -0,0 +1,145 @@
+package adstxt
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prebid/prebid-server/v2/logger"
+)
+
+var errFileMissing = errors.New("file not found")
+
+// crawler crawls the files of the domains queued by requests in the background, at most crawl.per_second a
+// second, and caches what it finds. A domain is queued once, until its crawl has been cached.
+type crawler struct {
+	client   *http.Client
+	cache    *cache
+	cfg      hostConfig
+	queue    chan fileKey
+	throttle <-chan time.Time
+	now      func() time.Time
+	// urls returns the URLs the file of a domain is crawled from, in the order they're tried
+	urls func(key fileKey) []string
+
+	mutex  sync.Mutex
+	queued map[fileKey]struct{}
+}
+
+func newCrawler(client *http.Client, cache *cache, cfg hostConfig) *crawler {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &crawler{
+		client:   client,
+		cache:    cache,
+		cfg:      cfg,
+		queue:    make(chan fileKey, cfg.Crawl.QueueSize),
+		throttle: time.NewTicker(time.Second / time.Duration(cfg.Crawl.PerSecond)).C,
+		now:      time.Now,
+		urls:     fileURLs,
+		queued:   make(map[fileKey]struct{}),
+	}
+}
+
+// fileURLs are the URLs of a file: over https, and over http if https fails, as the ads.txt spec allows.
+func fileURLs(key fileKey) []string {
+	return []string{"https://" + key.Domain + key.path(), "http://" + key.Domain + key.path()}
+}
+
+// start starts the workers which crawl the queued files.
+func (c *crawler) start() {
+	for i := 0; i < c.cfg.Crawl.Workers; i++ {
+		go func() {
+			for key := range c.queue {
+				<-c.throttle
+				c.crawl(key)
+			}
+		}()
+	}
+}
+
+// enqueue queues the file to be crawled, unless it's already queued or the queue is full.
+func (c *crawler) enqueue(key fileKey) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if _, ok := c.queued[key]; ok {
+		return
+	}
+	select {
+	case c.queue <- key:
+		c.queued[key] = struct{}{}
+	default:
+	}
+}
+
+// crawl fetches the file and caches what came of it.
+func (c *crawler) crawl(key fileKey) {
+	defer func() {
+		c.mutex.Lock()
+		delete(c.queued, key)
+		c.mutex.Unlock()
+	}()
+
+	data, err := c.fetch(key)
+	now := c.now()
+	switch {
+	case err == nil:
+		c.cache.set(key, fileFound, parseAdsTxt(data), now.Add(time.Duration(c.cfg.CacheTTLSeconds)*time.Second))
+	case errors.Is(err, errFileMissing):
+		c.cache.set(key, fileMissing, nil, now.Add(time.Duration(c.cfg.CacheTTLSeconds)*time.Second))
+	default:
+		logger.Debugf("Failed to crawl %s of %s: %v", key.path(), key.Domain, err)
+		// a file which was crawled before is kept, rather than counting as unavailable until it's crawled again
+		if entry, _ := c.cache.get(key, now); entry != nil && entry.Status == fileFound {
+			c.cache.set(key, fileFound, entry.sellers, now.Add(time.Duration(c.cfg.ErrorTTLSeconds)*time.Second))
+			return
+		}
+		c.cache.set(key, fileUnavailable, nil, now.Add(time.Duration(c.cfg.ErrorTTLSeconds)*time.Second))
+	}
+}
+
+// fetch returns the file from the first of its URLs which responds. It's missing if that URL responds
+// with a 404, or with something other than a text file.
+func (c *crawler) fetch(key fileKey) ([]byte, error) {
+	var err error
+	for _, url := range c.urls(key) {
+		var data []byte
+		if data, err = c.fetchURL(url); err == nil || errors.Is(err, errFileMissing) {
+			return data, err
+		}
+	}
+	return nil, err
+}
+
+func (c *crawler) fetchURL(url string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(c.cfg.Crawl.TimeoutMs)*time.Millisecond)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", c.cfg.Crawl.UserAgent)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, errFileMissing
+	case resp.StatusCode != http.StatusOK:
+		return nil, errors.New(resp.Status)
+	}
+	// sites without the file often respond with their home page instead
+	if contentType := resp.Header.Get("Content-Type"); contentType != "" && !strings.HasPrefix(contentType, "text/plain") {
+		return nil, errFileMissing
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, c.cfg.Crawl.MaxBytes))
+}
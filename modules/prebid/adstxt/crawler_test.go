@@ -0,0 +1,121 @@
+package adstxt
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestCrawler(t *testing.T, handler http.HandlerFunc) (*crawler, time.Time) {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	cfg, err := newHostConfig(nil)
+	require.NoError(t, err)
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := newCrawler(server.Client(), newCache(cfg.MaxEntries, ""), cfg)
+	c.now = func() time.Time { return now }
+	c.urls = func(key fileKey) []string {
+		return []string{"http://127.0.0.1:1" + key.path(), server.URL + "/" + key.Domain + key.path()}
+	}
+	return c, now
+}
+
+func TestCrawl(t *testing.T) {
+	c, now := newTestCrawler(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/example.com/ads.txt":
+			assert.Equal(t, "prebid-server-adstxt", r.Header.Get("User-Agent"))
+			w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+			w.Write([]byte("pbs-host.com, 1001, DIRECT\n"))
+		case "/example.com/app-ads.txt":
+			http.NotFound(w, r)
+		case "/html.com/ads.txt":
+			w.Header().Set("Content-Type", "text/html")
+			w.Write([]byte("<html></html>"))
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	})
+
+	testCases := []struct {
+		description     string
+		key             fileKey
+		expectedStatus  fileStatus
+		expectedSellers sellers
+		expectedExpires time.Time
+	}{
+		{
+			description:     "found",
+			key:             fileKey{Domain: "example.com"},
+			expectedStatus:  fileFound,
+			expectedSellers: sellers{"pbs-host.com": {"1001": {}}},
+			expectedExpires: now.Add(24 * time.Hour),
+		},
+		{
+			description:     "not-found",
+			key:             fileKey{Domain: "example.com", App: true},
+			expectedStatus:  fileMissing,
+			expectedExpires: now.Add(24 * time.Hour),
+		},
+		{
+			description:     "not-a-text-file",
+			key:             fileKey{Domain: "html.com"},
+			expectedStatus:  fileMissing,
+			expectedExpires: now.Add(24 * time.Hour),
+		},
+		{
+			description:     "server-error",
+			key:             fileKey{Domain: "error.com"},
+			expectedStatus:  fileUnavailable,
+			expectedExpires: now.Add(time.Hour),
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			c.crawl(test.key)
+
+			entry, _ := c.cache.get(test.key, now)
+			require.NotNil(t, entry)
+			assert.Equal(t, test.expectedStatus, entry.Status)
+			assert.Equal(t, test.expectedSellers, entry.sellers)
+			assert.Equal(t, test.expectedExpires, entry.Expires)
+		})
+	}
+}
+
+func TestCrawlKeepsFileWhichCantBeCrawledAgain(t *testing.T) {
+	c, now := newTestCrawler(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	key := fileKey{Domain: "example.com"}
+	c.cache.set(key, fileFound, sellers{"pbs-host.com": {"1001": {}}}, now)
+
+	c.crawl(key)
+
+	entry, expired := c.cache.get(key, now)
+	require.NotNil(t, entry)
+	assert.Equal(t, fileFound, entry.Status)
+	assert.Equal(t, sellers{"pbs-host.com": {"1001": {}}}, entry.sellers)
+	assert.False(t, expired)
+}
+
+func TestEnqueue(t *testing.T) {
+	cfg, err := newHostConfig([]byte(`{"crawl":{"queue_size":1}}`))
+	require.NoError(t, err)
+	c := newCrawler(nil, newCache(cfg.MaxEntries, ""), cfg)
+
+	c.enqueue(fileKey{Domain: "a.com"})
+	c.enqueue(fileKey{Domain: "a.com"})
+	c.enqueue(fileKey{Domain: "b.com"})
+
+	assert.Len(t, c.queue, 1, "files are queued once, and dropped once the queue is full")
+	assert.Equal(t, fileKey{Domain: "a.com"}, <-c.queue)
+	assert.NotContains(t, c.queued, fileKey{Domain: "b.com"})
+}
@@ -0,0 +1,210 @@
+package adstxt
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/openrtb/v20/openrtb3"
+	"github.com/prebid/prebid-server/v2/hooks/hookanalytics"
+	"github.com/prebid/prebid-server/v2/hooks/hookexecution"
+	"github.com/prebid/prebid-server/v2/hooks/hookstage"
+	"github.com/prebid/prebid-server/v2/logger"
+	"github.com/prebid/prebid-server/v2/modules/moduledeps"
+	"golang.org/x/net/publicsuffix"
+)
+
+func Builder(cfg json.RawMessage, deps moduledeps.ModuleDeps) (interface{}, error) {
+	hostCfg, err := newHostConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	cache := newCache(hostCfg.MaxEntries, hostCfg.CacheFile)
+	if hostCfg.CacheFile != "" {
+		// the files are crawled again if the cache can't be loaded, so it's not worth failing over
+		if err := cache.load(); err != nil {
+			logger.Warningf("Failed to load the ads.txt cache from %s: %v", hostCfg.CacheFile, err)
+		}
+		go saveCache(cache, time.Duration(hostCfg.SaveIntervalSeconds)*time.Second)
+	}
+
+	crawler := newCrawler(deps.HTTPClient, cache, hostCfg)
+	crawler.start()
+
+	return Module{cfg: hostCfg, cache: cache, crawler: crawler, now: time.Now}, nil
+}
+
+func saveCache(cache *cache, interval time.Duration) {
+	for range time.Tick(interval) {
+		if err := cache.save(); err != nil {
+			logger.Warningf("Failed to save the ads.txt cache to %s: %v", cache.file, err)
+		}
+	}
+}
+
+// Module verifies that the ads.txt or app-ads.txt file of the site or app of each auction request
+// authorizes the account's seller IDs. Files are crawled in the background, so requests from inventory
+// whose file hasn't been crawled yet are let through.
+type Module struct {
+	cfg     hostConfig
+	cache   *cache
+	crawler *crawler
+	now     func() time.Time
+}
+
+// verificationStatus is what came of verifying a request.
+type verificationStatus string
+
+const (
+	statusAuthorized   verificationStatus = "authorized"
+	statusUnauthorized verificationStatus = "unauthorized"
+	// statusMissing is inventory without an ads.txt file
+	statusMissing verificationStatus = "missing"
+	// statusUnavailable is inventory whose file couldn't be crawled
+	statusUnavailable verificationStatus = "unavailable"
+	// statusPending is inventory whose file hasn't been crawled yet
+	statusPending verificationStatus = "pending"
+	// statusUnknown is a request which can't be verified, since it doesn't have a domain, or there's no
+	// seller domain or seller IDs to look for
+	statusUnknown verificationStatus = "unknown"
+)
+
+// HandleProcessedAuctionHook tags the request with the result of its verification, in the module's
+// analytics tags, and rejects it if the account blocks unauthorized inventory.
+func (m Module) HandleProcessedAuctionHook(
+	_ context.Context,
+	miCtx hookstage.ModuleInvocationContext,
+	payload hookstage.ProcessedAuctionRequestPayload,
+) (hookstage.HookResult[hookstage.ProcessedAuctionRequestPayload], error) {
+	result := hookstage.HookResult[hookstage.ProcessedAuctionRequestPayload]{}
+	if payload.Request == nil || payload.Request.BidRequest == nil {
+		return result, hookexecution.NewFailure("payload contains a nil bid request")
+	}
+
+	cfg, err := newAccountConfig(miCtx.AccountConfig, m.cfg)
+	if err != nil {
+		return result, err
+	}
+
+	key, status := m.verify(payload.Request.BidRequest, cfg)
+	if cfg.Action == actionBlock {
+		switch {
+		case status == statusUnauthorized:
+			result.Reject = true
+			result.NbrCode = int(openrtb3.NoBidAuthorizationViolation)
+		case (status == statusMissing || status == statusUnavailable) && cfg.BlockUnavailable:
+			result.Reject = true
+			result.NbrCode = int(openrtb3.NoBidAuthorizationUnavailable)
+		}
+	}
+	if result.Reject {
+		result.Message = fmt.Sprintf("%s of %s doesn't authorize the seller: %s", strings.TrimPrefix(key.path(), "/"), key.Domain, status)
+	}
+	result.AnalyticsTags = analyticsTags(key, status, result.Reject)
+	return result, nil
+}
+
+// verify returns the file of the request's inventory, and whether it authorizes the account's seller IDs.
+// Files which haven't been crawled, or whose crawl has expired, are queued to be crawled.
+func (m Module) verify(req *openrtb2.BidRequest, cfg accountConfig) (fileKey, verificationStatus) {
+	key, publisherID, ok := inventoryOf(req)
+	if !ok {
+		return key, statusUnknown
+	}
+	sellerIDs := cfg.SellerIDs
+	if len(sellerIDs) == 0 && publisherID != "" {
+		sellerIDs = []string{publisherID}
+	}
+	if cfg.SellerDomain == "" || len(sellerIDs) == 0 {
+		return key, statusUnknown
+	}
+
+	entry, expired := m.cache.get(key, m.now())
+	if expired {
+		m.crawler.enqueue(key)
+	}
+	if entry == nil {
+		return key, statusPending
+	}
+	switch entry.Status {
+	case fileFound:
+		if entry.sellers.authorizes(cfg.SellerDomain, sellerIDs) {
+			return key, statusAuthorized
+		}
+		return key, statusUnauthorized
+	case fileMissing:
+		return key, statusMissing
+	default:
+		return key, statusUnavailable
+	}
+}
+
+// inventoryOf returns the file of the request's site or app, and its publisher ID. The file is at the root
+// domain of the publisher's domain, or of the site's domain or page if it has none. Apps' app-ads.txt files
+// are at the developer's domain, which must be in the app's publisher or domain.
+func inventoryOf(req *openrtb2.BidRequest) (fileKey, string, bool) {
+	var key fileKey
+	var domains []string
+	var publisherID string
+	switch {
+	case req.Site != nil:
+		if req.Site.Publisher != nil {
+			domains = append(domains, req.Site.Publisher.Domain)
+			publisherID = req.Site.Publisher.ID
+		}
+		domains = append(domains, req.Site.Domain)
+		if page, err := url.Parse(req.Site.Page); err == nil {
+			domains = append(domains, page.Hostname())
+		}
+	case req.App != nil:
+		key.App = true
+		if req.App.Publisher != nil {
+			domains = append(domains, req.App.Publisher.Domain)
+			publisherID = req.App.Publisher.ID
+		}
+		domains = append(domains, req.App.Domain)
+	default:
+		return key, "", false
+	}
+
+	for _, domain := range domains {
+		if domain == "" {
+			continue
+		}
+		rootDomain, err := publicsuffix.EffectiveTLDPlusOne(strings.ToLower(domain))
+		if err != nil {
+			continue
+		}
+		key.Domain = rootDomain
+		return key, publisherID, true
+	}
+	return key, publisherID, false
+}
+
+func analyticsTags(key fileKey, status verificationStatus, rejected bool) hookanalytics.Analytics {
+	resultStatus := hookanalytics.ResultStatusAllow
+	if rejected {
+		resultStatus = hookanalytics.ResultStatusBlock
+	}
+	values := map[string]interface{}{"status": string(status)}
+	if key.Domain != "" {
+		values["domain"] = key.Domain
+		values["file"] = strings.TrimPrefix(key.path(), "/")
+	}
+	return hookanalytics.Analytics{
+		Activities: []hookanalytics.Activity{{
+			Name:   "ads-txt-verification",
+			Status: hookanalytics.ActivityStatusSuccess,
+			Results: []hookanalytics.Result{{
+				Status:    resultStatus,
+				Values:    values,
+				AppliedTo: hookanalytics.AppliedTo{Request: true},
+			}},
+		}},
+	}
+}
@@ -0,0 +1,219 @@
+package adstxt
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/openrtb/v20/openrtb3"
+	"github.com/prebid/prebid-server/v2/hooks/hookanalytics"
+	"github.com/prebid/prebid-server/v2/hooks/hookstage"
+	"github.com/prebid/prebid-server/v2/modules/moduledeps"
+	"github.com/prebid/prebid-server/v2/openrtb_ext"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuilder(t *testing.T) {
+	module, err := Builder(json.RawMessage(`{"enabled":true,"seller_domain":"pbs-host.com"}`), moduledeps.ModuleDeps{})
+	require.NoError(t, err)
+	assert.Equal(t, "pbs-host.com", module.(Module).cfg.SellerDomain)
+
+	_, err = Builder(json.RawMessage(`{"crawl":{"per_second":0}}`), moduledeps.ModuleDeps{})
+	assert.Error(t, err)
+}
+
+func TestHandleProcessedAuctionHook(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	hostCfg, err := newHostConfig(json.RawMessage(`{"seller_domain":"pbs-host.com"}`))
+	require.NoError(t, err)
+	cache := newCache(hostCfg.MaxEntries, "")
+	cache.set(fileKey{Domain: "authorized.com"}, fileFound, sellers{"pbs-host.com": {"1001": {}}}, now.Add(time.Hour))
+	cache.set(fileKey{Domain: "unauthorized.com"}, fileFound, sellers{"pbs-host.com": {"9999": {}}}, now.Add(time.Hour))
+	cache.set(fileKey{Domain: "missing.com"}, fileMissing, nil, now.Add(time.Hour))
+	cache.set(fileKey{Domain: "developer.com", App: true}, fileFound, sellers{"pbs-host.com": {"app-1": {}}}, now.Add(time.Hour))
+	module := Module{cfg: hostCfg, cache: cache, crawler: newCrawler(nil, cache, hostCfg), now: func() time.Time { return now }}
+
+	site := func(domain string) *openrtb2.BidRequest {
+		return &openrtb2.BidRequest{Site: &openrtb2.Site{Page: "https://www." + domain + "/page", Publisher: &openrtb2.Publisher{ID: "1001"}}}
+	}
+
+	testCases := []struct {
+		description    string
+		accountConfig  string
+		request        *openrtb2.BidRequest
+		expectedStatus verificationStatus
+		expectedReject bool
+		expectedNbr    int
+	}{
+		{
+			description:    "authorized",
+			accountConfig:  `{"action":"block"}`,
+			request:        site("authorized.com"),
+			expectedStatus: statusAuthorized,
+		},
+		{
+			description:    "unauthorized-tagged",
+			request:        site("unauthorized.com"),
+			expectedStatus: statusUnauthorized,
+		},
+		{
+			description:    "unauthorized-blocked",
+			accountConfig:  `{"action":"block"}`,
+			request:        site("unauthorized.com"),
+			expectedStatus: statusUnauthorized,
+			expectedReject: true,
+			expectedNbr:    int(openrtb3.NoBidAuthorizationViolation),
+		},
+		{
+			description:    "account-seller-ids",
+			accountConfig:  `{"action":"block","seller_ids":["9999"]}`,
+			request:        site("unauthorized.com"),
+			expectedStatus: statusAuthorized,
+		},
+		{
+			description:    "account-seller-domain",
+			accountConfig:  `{"action":"block","seller_domain":"other.com"}`,
+			request:        site("authorized.com"),
+			expectedStatus: statusUnauthorized,
+			expectedReject: true,
+			expectedNbr:    int(openrtb3.NoBidAuthorizationViolation),
+		},
+		{
+			description:    "missing-blocked",
+			accountConfig:  `{"action":"block","block_unavailable":true}`,
+			request:        site("missing.com"),
+			expectedStatus: statusMissing,
+			expectedReject: true,
+			expectedNbr:    int(openrtb3.NoBidAuthorizationUnavailable),
+		},
+		{
+			description:    "missing-allowed",
+			accountConfig:  `{"action":"block"}`,
+			request:        site("missing.com"),
+			expectedStatus: statusMissing,
+		},
+		{
+			description:    "pending",
+			accountConfig:  `{"action":"block","block_unavailable":true}`,
+			request:        site("new.com"),
+			expectedStatus: statusPending,
+		},
+		{
+			description:    "app",
+			accountConfig:  `{"action":"block"}`,
+			request:        &openrtb2.BidRequest{App: &openrtb2.App{Bundle: "com.example", Publisher: &openrtb2.Publisher{ID: "app-1", Domain: "developer.com"}}},
+			expectedStatus: statusAuthorized,
+		},
+		{
+			description:    "app-without-domain",
+			accountConfig:  `{"action":"block","block_unavailable":true}`,
+			request:        &openrtb2.BidRequest{App: &openrtb2.App{Bundle: "com.example", Publisher: &openrtb2.Publisher{ID: "app-1"}}},
+			expectedStatus: statusUnknown,
+		},
+		{
+			description:    "no-seller-ids",
+			accountConfig:  `{"action":"block"}`,
+			request:        &openrtb2.BidRequest{Site: &openrtb2.Site{Domain: "unauthorized.com"}},
+			expectedStatus: statusUnknown,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			miCtx := hookstage.ModuleInvocationContext{AccountConfig: json.RawMessage(test.accountConfig)}
+			payload := hookstage.ProcessedAuctionRequestPayload{Request: &openrtb_ext.RequestWrapper{BidRequest: test.request}}
+
+			result, err := module.HandleProcessedAuctionHook(context.Background(), miCtx, payload)
+
+			require.NoError(t, err)
+			assert.Equal(t, test.expectedReject, result.Reject)
+			assert.Equal(t, test.expectedNbr, result.NbrCode)
+			require.Len(t, result.AnalyticsTags.Activities, 1)
+			activity := result.AnalyticsTags.Activities[0]
+			assert.Equal(t, "ads-txt-verification", activity.Name)
+			assert.Equal(t, string(test.expectedStatus), activity.Results[0].Values["status"])
+			if test.expectedReject {
+				assert.Equal(t, hookanalytics.ResultStatusBlock, activity.Results[0].Status)
+			} else {
+				assert.Equal(t, hookanalytics.ResultStatusAllow, activity.Results[0].Status)
+			}
+		})
+	}
+
+	assert.Contains(t, module.crawler.queued, fileKey{Domain: "new.com"}, "files which haven't been crawled are queued")
+	assert.NotContains(t, module.crawler.queued, fileKey{Domain: "authorized.com"})
+}
+
+func TestHandleProcessedAuctionHookErrors(t *testing.T) {
+	hostCfg, err := newHostConfig(nil)
+	require.NoError(t, err)
+	module := Module{cfg: hostCfg, cache: newCache(1, ""), now: time.Now}
+
+	_, err = module.HandleProcessedAuctionHook(context.Background(), hookstage.ModuleInvocationContext{}, hookstage.ProcessedAuctionRequestPayload{})
+	assert.EqualError(t, err, "hook execution failed: payload contains a nil bid request")
+
+	miCtx := hookstage.ModuleInvocationContext{AccountConfig: json.RawMessage(`{"action":"drop"}`)}
+	payload := hookstage.ProcessedAuctionRequestPayload{Request: &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{}}}
+	_, err = module.HandleProcessedAuctionHook(context.Background(), miCtx, payload)
+	assert.EqualError(t, err, "action must be tag or block. Got drop")
+}
+
+func TestInventoryOf(t *testing.T) {
+	testCases := []struct {
+		description         string
+		request             *openrtb2.BidRequest
+		expectedKey         fileKey
+		expectedPublisherID string
+		expectedOK          bool
+	}{
+		{
+			description:         "site-publisher-domain",
+			request:             &openrtb2.BidRequest{Site: &openrtb2.Site{Domain: "news.site.com", Publisher: &openrtb2.Publisher{ID: "1", Domain: "publisher.co.uk"}}},
+			expectedKey:         fileKey{Domain: "publisher.co.uk"},
+			expectedPublisherID: "1",
+			expectedOK:          true,
+		},
+		{
+			description: "site-domain",
+			request:     &openrtb2.BidRequest{Site: &openrtb2.Site{Domain: "News.Site.com"}},
+			expectedKey: fileKey{Domain: "site.com"},
+			expectedOK:  true,
+		},
+		{
+			description: "site-page",
+			request:     &openrtb2.BidRequest{Site: &openrtb2.Site{Page: "https://www.site.com:8080/page"}},
+			expectedKey: fileKey{Domain: "site.com"},
+			expectedOK:  true,
+		},
+		{
+			description: "app-domain",
+			request:     &openrtb2.BidRequest{App: &openrtb2.App{Domain: "developer.com"}},
+			expectedKey: fileKey{Domain: "developer.com", App: true},
+			expectedOK:  true,
+		},
+		{
+			description: "dooh",
+			request:     &openrtb2.BidRequest{DOOH: &openrtb2.DOOH{Domain: "screens.com"}},
+			expectedOK:  false,
+		},
+		{
+			description: "no-domain",
+			request:     &openrtb2.BidRequest{Site: &openrtb2.Site{Page: "localhost"}},
+			expectedOK:  false,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			key, publisherID, ok := inventoryOf(test.request)
+			assert.Equal(t, test.expectedOK, ok)
+			if test.expectedOK {
+				assert.Equal(t, test.expectedKey, key)
+			}
+			assert.Equal(t, test.expectedPublisherID, publisherID)
+		})
+	}
+}
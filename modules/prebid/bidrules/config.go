@@ -0,0 +1,128 @@
+package bidrules
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/prebid/prebid-server/v2/util/jsonutil"
+)
+
+const (
+	actionDrop         = "drop"
+	actionAdjustPrice  = "adjust_price"
+	actionAddTargeting = "add_targeting"
+)
+
+// hostConfig is the module config of the host. It sets the budgets account rules run within.
+type hostConfig struct {
+	// MaxRules caps the number of rules of an account.
+	MaxRules int `json:"max_rules"`
+	// MaxExpressionLength caps the length in bytes of each expression of a rule.
+	MaxExpressionLength int `json:"max_expression_length"`
+	// MaxStepsPerRequest caps the steps the expressions of a request may take, over all its bids. The rules
+	// stop being applied once they're used up.
+	MaxStepsPerRequest int `json:"max_steps_per_request"`
+	// AuditLog logs every bid a rule is applied to.
+	AuditLog bool `json:"audit_log"`
+}
+
+func newHostConfig(data json.RawMessage) (hostConfig, error) {
+	cfg := hostConfig{
+		MaxRules:            20,
+		MaxExpressionLength: 512,
+		MaxStepsPerRequest:  100000,
+	}
+	if len(data) > 0 {
+		if err := jsonutil.UnmarshalValid(data, &cfg); err != nil {
+			return cfg, fmt.Errorf("failed to parse config: %s", err)
+		}
+	}
+
+	if cfg.MaxRules <= 0 || cfg.MaxExpressionLength <= 0 || cfg.MaxStepsPerRequest <= 0 {
+		return cfg, fmt.Errorf("max_rules, max_expression_length and max_steps_per_request must be positive")
+	}
+	return cfg, nil
+}
+
+// accountConfig is the module config of an account: the rules applied to its bids, in order.
+type accountConfig struct {
+	Rules []ruleConfig `json:"rules"`
+}
+
+type ruleConfig struct {
+	// Name identifies the rule in analytics tags and logs.
+	Name string `json:"name"`
+	// When is the expression bids the rule applies to must match. The rule applies to every bid if it's empty.
+	When string `json:"when"`
+	// Action is drop, adjust_price or add_targeting.
+	Action string `json:"action"`
+	// Key is the targeting key add_targeting sets.
+	Key string `json:"key"`
+	// Value is the expression of the new price for adjust_price, or of the targeting value for add_targeting.
+	Value string `json:"value"`
+}
+
+// rule is a rule of an account whose expressions have been parsed.
+type rule struct {
+	name   string
+	when   expression
+	action string
+	key    string
+	value  expression
+}
+
+func newRules(data json.RawMessage, host hostConfig) ([]rule, error) {
+	var cfg accountConfig
+	if len(data) > 0 {
+		if err := jsonutil.UnmarshalValid(data, &cfg); err != nil {
+			return nil, fmt.Errorf("failed to parse account config: %s", err)
+		}
+	}
+	if len(cfg.Rules) > host.MaxRules {
+		return nil, fmt.Errorf("account has %d rules, but the host allows at most %d", len(cfg.Rules), host.MaxRules)
+	}
+
+	rules := make([]rule, 0, len(cfg.Rules))
+	for i, ruleCfg := range cfg.Rules {
+		r, err := newRule(ruleCfg, host)
+		if err != nil {
+			return nil, fmt.Errorf("rules[%d]: %s", i, err)
+		}
+		rules = append(rules, r)
+	}
+	return rules, nil
+}
+
+func newRule(cfg ruleConfig, host hostConfig) (rule, error) {
+	r := rule{name: cfg.Name, action: cfg.Action, key: cfg.Key}
+	if cfg.Name == "" {
+		return r, fmt.Errorf("name is required")
+	}
+
+	switch cfg.Action {
+	case actionDrop:
+	case actionAdjustPrice:
+		if cfg.Value == "" {
+			return r, fmt.Errorf("value is required to %s", cfg.Action)
+		}
+	case actionAddTargeting:
+		if cfg.Key == "" || cfg.Value == "" {
+			return r, fmt.Errorf("key and value are required to %s", cfg.Action)
+		}
+	default:
+		return r, fmt.Errorf("action must be %s, %s or %s. Got %s", actionDrop, actionAdjustPrice, actionAddTargeting, cfg.Action)
+	}
+
+	var err error
+	if cfg.When != "" {
+		if r.when, err = parseExpression(cfg.When, host.MaxExpressionLength); err != nil {
+			return r, fmt.Errorf("when: %s", err)
+		}
+	}
+	if cfg.Value != "" {
+		if r.value, err = parseExpression(cfg.Value, host.MaxExpressionLength); err != nil {
+			return r, fmt.Errorf("value: %s", err)
+		}
+	}
+	return r, nil
+}
@@ -0,0 +1,119 @@
+package bidrules
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewHostConfig(t *testing.T) {
+	testCases := []struct {
+		description   string
+		config        json.RawMessage
+		expectedError string
+	}{
+		{
+			description: "defaults",
+		},
+		{
+			description: "valid",
+			config:      json.RawMessage(`{"enabled":true,"max_rules":5,"audit_log":true}`),
+		},
+		{
+			description:   "invalid-json",
+			config:        json.RawMessage(`{"max_rules":`),
+			expectedError: "failed to parse config",
+		},
+		{
+			description:   "invalid-budget",
+			config:        json.RawMessage(`{"max_steps_per_request":0}`),
+			expectedError: "max_rules, max_expression_length and max_steps_per_request must be positive",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			_, err := newHostConfig(test.config)
+			if test.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, test.expectedError)
+			}
+		})
+	}
+}
+
+func TestNewRules(t *testing.T) {
+	host := hostConfig{MaxRules: 2, MaxExpressionLength: 32, MaxStepsPerRequest: 100}
+
+	testCases := []struct {
+		description   string
+		config        json.RawMessage
+		expectedRules int
+		expectedError string
+	}{
+		{
+			description: "no-rules",
+			config:      json.RawMessage(`{"enabled":true}`),
+		},
+		{
+			description:   "valid",
+			config:        json.RawMessage(`{"rules":[{"name":"low","when":"bid.price < 0.1","action":"drop"},{"name":"flag","action":"add_targeting","key":"hb_flag","value":"'1'"}]}`),
+			expectedRules: 2,
+		},
+		{
+			description:   "invalid-json",
+			config:        json.RawMessage(`{"rules":{}}`),
+			expectedError: "failed to parse account config",
+		},
+		{
+			description:   "too-many-rules",
+			config:        json.RawMessage(`{"rules":[{"name":"a","action":"drop"},{"name":"b","action":"drop"},{"name":"c","action":"drop"}]}`),
+			expectedError: "account has 3 rules, but the host allows at most 2",
+		},
+		{
+			description:   "no-name",
+			config:        json.RawMessage(`{"rules":[{"action":"drop"}]}`),
+			expectedError: "rules[0]: name is required",
+		},
+		{
+			description:   "unknown-action",
+			config:        json.RawMessage(`{"rules":[{"name":"a","action":"block"}]}`),
+			expectedError: "rules[0]: action must be drop, adjust_price or add_targeting. Got block",
+		},
+		{
+			description:   "no-price",
+			config:        json.RawMessage(`{"rules":[{"name":"a","action":"adjust_price"}]}`),
+			expectedError: "rules[0]: value is required to adjust_price",
+		},
+		{
+			description:   "no-targeting-key",
+			config:        json.RawMessage(`{"rules":[{"name":"a","action":"add_targeting","value":"bidder"}]}`),
+			expectedError: "rules[0]: key and value are required to add_targeting",
+		},
+		{
+			description:   "invalid-when",
+			config:        json.RawMessage(`{"rules":[{"name":"a","when":"bid.price <","action":"drop"}]}`),
+			expectedError: "rules[0]: when: unexpected end of expression at 11",
+		},
+		{
+			description:   "expression-too-long",
+			config:        json.RawMessage(`{"rules":[{"name":"a","action":"adjust_price","value":"bid.price * 0.9 * 0.9 * 0.9 * 0.9 * 0.9"}]}`),
+			expectedError: "rules[0]: value: expression is longer than 32 bytes",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			rules, err := newRules(test.config, host)
+			if test.expectedError == "" {
+				require.NoError(t, err)
+				assert.Len(t, rules, test.expectedRules)
+			} else {
+				assert.ErrorContains(t, err, test.expectedError)
+			}
+		})
+	}
+}
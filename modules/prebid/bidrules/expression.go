@@ -0,0 +1,651 @@
+package bidrules
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// errBudgetExhausted is returned once the expressions of a request have taken all the steps the host allows.
+var errBudgetExhausted = errors.New("execution budget exhausted")
+
+// expression is a parsed expression. Expressions are made of:
+//
+//   - number, 'string' or "string", true and false literals, and [lists]
+//   - the variables of the bid, like bid.price or bidder
+//   - the ==, !=, <, <=, >, >= and in comparisons
+//   - the &&, || and ! operators on booleans
+//   - the +, -, * and / operators on numbers, and + on strings
+//   - the contains, startsWith, endsWith, lower and len functions
+//
+// Expressions can't loop, call out of the module or change anything, and each node they evaluate takes a step
+// of the request's budget.
+type expression interface {
+	eval(env *env) (interface{}, error)
+}
+
+// env is what expressions are evaluated against.
+type env struct {
+	vars  variables
+	steps int
+}
+
+// step takes a step from the budget.
+func (e *env) step() error {
+	if e.steps <= 0 {
+		return errBudgetExhausted
+	}
+	e.steps--
+	return nil
+}
+
+// variables looks up the variables of the bid an expression is evaluated for.
+type variables interface {
+	lookup(name string) interface{}
+}
+
+// knownVariables are the variables expressions may use.
+var knownVariables = map[string]struct{}{
+	"bidder":      {},
+	"seat":        {},
+	"currency":    {},
+	"bid.id":      {},
+	"bid.impid":   {},
+	"bid.price":   {},
+	"bid.dealid":  {},
+	"bid.crid":    {},
+	"bid.adomain": {},
+	"bid.cat":     {},
+	"bid.w":       {},
+	"bid.h":       {},
+	"bid.type":    {},
+}
+
+// knownFunctions are the functions expressions may call, and the number of arguments they take.
+var knownFunctions = map[string]int{
+	"contains":   2,
+	"startsWith": 2,
+	"endsWith":   2,
+	"lower":      1,
+	"len":        1,
+}
+
+// parseExpression parses the expression, which may be at most maxLength bytes long.
+func parseExpression(source string, maxLength int) (expression, error) {
+	if len(source) > maxLength {
+		return nil, fmt.Errorf("expression is longer than %d bytes", maxLength)
+	}
+	tokens, err := tokenize(source)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens}
+	expr, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if next := p.peek(); next.kind != tokenEnd {
+		return nil, fmt.Errorf("unexpected %s at %d", next, next.pos)
+	}
+	return expr, nil
+}
+
+type tokenKind int
+
+const (
+	tokenEnd tokenKind = iota
+	tokenNumber
+	tokenString
+	tokenIdent
+	tokenOperator
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+func (t token) String() string {
+	if t.kind == tokenEnd {
+		return "end of expression"
+	}
+	return strconv.Quote(t.text)
+}
+
+// operators are the operators, longest first so that <= isn't read as <.
+var operators = []string{"==", "!=", "<=", ">=", "&&", "||", "<", ">", "!", "+", "-", "*", "/", "(", ")", "[", "]", ","}
+
+func tokenize(source string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(source); {
+		c := source[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case isDigit(c) || (c == '.' && i+1 < len(source) && isDigit(source[i+1])):
+			start := i
+			for i < len(source) && (isDigit(source[i]) || source[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenNumber, text: source[start:i], pos: start})
+		case c == '\'' || c == '"':
+			start := i
+			end := strings.IndexByte(source[i+1:], c)
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated string at %d", start)
+			}
+			i += end + 2
+			tokens = append(tokens, token{kind: tokenString, text: source[start+1 : i-1], pos: start})
+		case isIdentStart(c):
+			start := i
+			for i < len(source) && (isIdentStart(source[i]) || isDigit(source[i]) || source[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenIdent, text: source[start:i], pos: start})
+		default:
+			operator := ""
+			for _, op := range operators {
+				if strings.HasPrefix(source[i:], op) {
+					operator = op
+					break
+				}
+			}
+			if operator == "" {
+				return nil, fmt.Errorf("unexpected %q at %d", c, i)
+			}
+			tokens = append(tokens, token{kind: tokenOperator, text: operator, pos: i})
+			i += len(operator)
+		}
+	}
+	return append(tokens, token{kind: tokenEnd, pos: len(source)}), nil
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isIdentStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEnd {
+		p.pos++
+	}
+	return t
+}
+
+// accept consumes the next token if it's one of the operators or keywords.
+func (p *parser) accept(texts ...string) (string, bool) {
+	t := p.peek()
+	if t.kind != tokenOperator && t.kind != tokenIdent {
+		return "", false
+	}
+	for _, text := range texts {
+		if t.text == text {
+			p.pos++
+			return text, true
+		}
+	}
+	return "", false
+}
+
+func (p *parser) expect(text string) error {
+	if _, ok := p.accept(text); !ok {
+		t := p.peek()
+		return fmt.Errorf("expected %q but found %s at %d", text, t, t.pos)
+	}
+	return nil
+}
+
+func (p *parser) parseOr() (expression, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		if _, ok := p.accept("||"); !ok {
+			return left, nil
+		}
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = logicalExpr{or: true, left: left, right: right}
+	}
+}
+
+func (p *parser) parseAnd() (expression, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		if _, ok := p.accept("&&"); !ok {
+			return left, nil
+		}
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = logicalExpr{left: left, right: right}
+	}
+}
+
+func (p *parser) parseNot() (expression, error) {
+	if _, ok := p.accept("!"); ok {
+		operand, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return notExpr{operand: operand}, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *parser) parseComparison() (expression, error) {
+	left, err := p.parseAdditive()
+	if err != nil {
+		return nil, err
+	}
+	op, ok := p.accept("==", "!=", "<=", ">=", "<", ">", "in")
+	if !ok {
+		return left, nil
+	}
+	right, err := p.parseAdditive()
+	if err != nil {
+		return nil, err
+	}
+	return binaryExpr{op: op, left: left, right: right}, nil
+}
+
+func (p *parser) parseAdditive() (expression, error) {
+	left, err := p.parseMultiplicative()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op, ok := p.accept("+", "-")
+		if !ok {
+			return left, nil
+		}
+		right, err := p.parseMultiplicative()
+		if err != nil {
+			return nil, err
+		}
+		left = binaryExpr{op: op, left: left, right: right}
+	}
+}
+
+func (p *parser) parseMultiplicative() (expression, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op, ok := p.accept("*", "/")
+		if !ok {
+			return left, nil
+		}
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = binaryExpr{op: op, left: left, right: right}
+	}
+}
+
+func (p *parser) parseUnary() (expression, error) {
+	if _, ok := p.accept("-"); ok {
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return binaryExpr{op: "-", left: literalExpr{value: 0.0}, right: operand}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (expression, error) {
+	t := p.next()
+	switch t.kind {
+	case tokenNumber:
+		number, err := strconv.ParseFloat(t.text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("malformed number %s at %d", t, t.pos)
+		}
+		return literalExpr{value: number}, nil
+	case tokenString:
+		return literalExpr{value: t.text}, nil
+	case tokenIdent:
+		switch t.text {
+		case "true":
+			return literalExpr{value: true}, nil
+		case "false":
+			return literalExpr{value: false}, nil
+		}
+		if _, ok := p.accept("("); ok {
+			return p.parseCall(t)
+		}
+		if _, ok := knownVariables[t.text]; !ok {
+			return nil, fmt.Errorf("unknown variable %s at %d", t, t.pos)
+		}
+		return variableExpr{name: t.text}, nil
+	case tokenOperator:
+		switch t.text {
+		case "(":
+			expr, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			return expr, p.expect(")")
+		case "[":
+			items, err := p.parseList("]")
+			if err != nil {
+				return nil, err
+			}
+			return listExpr{items: items}, nil
+		}
+	}
+	return nil, fmt.Errorf("unexpected %s at %d", t, t.pos)
+}
+
+func (p *parser) parseCall(name token) (expression, error) {
+	arity, ok := knownFunctions[name.text]
+	if !ok {
+		return nil, fmt.Errorf("unknown function %s at %d", name, name.pos)
+	}
+	args, err := p.parseList(")")
+	if err != nil {
+		return nil, err
+	}
+	if len(args) != arity {
+		return nil, fmt.Errorf("%s takes %d arguments but was given %d at %d", name.text, arity, len(args), name.pos)
+	}
+	return callExpr{name: name.text, args: args}, nil
+}
+
+// parseList parses comma separated expressions up to the closing operator.
+func (p *parser) parseList(closing string) ([]expression, error) {
+	var items []expression
+	if _, ok := p.accept(closing); ok {
+		return items, nil
+	}
+	for {
+		item, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+		if _, ok := p.accept(","); !ok {
+			return items, p.expect(closing)
+		}
+	}
+}
+
+type literalExpr struct {
+	value interface{}
+}
+
+func (e literalExpr) eval(env *env) (interface{}, error) {
+	return e.value, env.step()
+}
+
+type variableExpr struct {
+	name string
+}
+
+func (e variableExpr) eval(env *env) (interface{}, error) {
+	return env.vars.lookup(e.name), env.step()
+}
+
+type listExpr struct {
+	items []expression
+}
+
+func (e listExpr) eval(env *env) (interface{}, error) {
+	if err := env.step(); err != nil {
+		return nil, err
+	}
+	list := make([]interface{}, 0, len(e.items))
+	for _, item := range e.items {
+		value, err := item.eval(env)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, value)
+	}
+	return list, nil
+}
+
+type notExpr struct {
+	operand expression
+}
+
+func (e notExpr) eval(env *env) (interface{}, error) {
+	if err := env.step(); err != nil {
+		return nil, err
+	}
+	operand, err := evalBool(e.operand, env, "!")
+	if err != nil {
+		return nil, err
+	}
+	return !operand, nil
+}
+
+// logicalExpr is && or ||, whose right side is only evaluated if the left side doesn't decide the result.
+type logicalExpr struct {
+	or          bool
+	left, right expression
+}
+
+func (e logicalExpr) eval(env *env) (interface{}, error) {
+	if err := env.step(); err != nil {
+		return nil, err
+	}
+	op := "&&"
+	if e.or {
+		op = "||"
+	}
+	left, err := evalBool(e.left, env, op)
+	if err != nil || left == e.or {
+		return left, err
+	}
+	return evalBool(e.right, env, op)
+}
+
+type binaryExpr struct {
+	op          string
+	left, right expression
+}
+
+func (e binaryExpr) eval(env *env) (interface{}, error) {
+	if err := env.step(); err != nil {
+		return nil, err
+	}
+	left, err := e.left.eval(env)
+	if err != nil {
+		return nil, err
+	}
+	right, err := e.right.eval(env)
+	if err != nil {
+		return nil, err
+	}
+
+	switch e.op {
+	case "==":
+		return equal(left, right)
+	case "!=":
+		eq, err := equal(left, right)
+		return !eq, err
+	case "in":
+		list, ok := right.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("in needs a list but was given %s", typeName(right))
+		}
+		return listContains(list, left), nil
+	}
+
+	switch l := left.(type) {
+	case float64:
+		if r, ok := right.(float64); ok {
+			return arithmetic(e.op, l, r)
+		}
+	case string:
+		if r, ok := right.(string); ok {
+			switch e.op {
+			case "+":
+				return l + r, nil
+			case "<":
+				return l < r, nil
+			case "<=":
+				return l <= r, nil
+			case ">":
+				return l > r, nil
+			case ">=":
+				return l >= r, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("%s can't be applied to %s and %s", e.op, typeName(left), typeName(right))
+}
+
+func arithmetic(op string, l, r float64) (interface{}, error) {
+	switch op {
+	case "+":
+		return l + r, nil
+	case "-":
+		return l - r, nil
+	case "*":
+		return l * r, nil
+	case "/":
+		if r == 0 {
+			return nil, errors.New("division by zero")
+		}
+		return l / r, nil
+	case "<":
+		return l < r, nil
+	case "<=":
+		return l <= r, nil
+	case ">":
+		return l > r, nil
+	default: // ">="
+		return l >= r, nil
+	}
+}
+
+type callExpr struct {
+	name string
+	args []expression
+}
+
+func (e callExpr) eval(env *env) (interface{}, error) {
+	if err := env.step(); err != nil {
+		return nil, err
+	}
+	args := make([]interface{}, 0, len(e.args))
+	for _, arg := range e.args {
+		value, err := arg.eval(env)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, value)
+	}
+
+	switch e.name {
+	case "contains":
+		switch container := args[0].(type) {
+		case []interface{}:
+			return listContains(container, args[1]), nil
+		case string:
+			if s, ok := args[1].(string); ok {
+				return strings.Contains(container, s), nil
+			}
+		}
+	case "startsWith", "endsWith":
+		s, ok1 := args[0].(string)
+		affix, ok2 := args[1].(string)
+		if ok1 && ok2 {
+			if e.name == "startsWith" {
+				return strings.HasPrefix(s, affix), nil
+			}
+			return strings.HasSuffix(s, affix), nil
+		}
+	case "lower":
+		if s, ok := args[0].(string); ok {
+			return strings.ToLower(s), nil
+		}
+	case "len":
+		switch value := args[0].(type) {
+		case string:
+			return float64(len(value)), nil
+		case []interface{}:
+			return float64(len(value)), nil
+		}
+	}
+
+	argTypes := make([]string, 0, len(args))
+	for _, arg := range args {
+		argTypes = append(argTypes, typeName(arg))
+	}
+	return nil, fmt.Errorf("%s can't be applied to %s", e.name, strings.Join(argTypes, " and "))
+}
+
+func evalBool(expr expression, env *env, op string) (bool, error) {
+	value, err := expr.eval(env)
+	if err != nil {
+		return false, err
+	}
+	b, ok := value.(bool)
+	if !ok {
+		return false, fmt.Errorf("%s needs a bool but was given %s", op, typeName(value))
+	}
+	return b, nil
+}
+
+func equal(left, right interface{}) (bool, error) {
+	switch left.(type) {
+	case float64, string, bool:
+		if typeName(left) == typeName(right) {
+			return left == right, nil
+		}
+	}
+	return false, fmt.Errorf("can't compare %s with %s", typeName(left), typeName(right))
+}
+
+func listContains(list []interface{}, value interface{}) bool {
+	for _, item := range list {
+		// lists can't be compared, and aren't looked for
+		if _, isList := item.([]interface{}); !isList && item == value {
+			return true
+		}
+	}
+	return false
+}
+
+func typeName(value interface{}) string {
+	switch value.(type) {
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case bool:
+		return "bool"
+	case []interface{}:
+		return "list"
+	default:
+		return "nothing"
+	}
+}
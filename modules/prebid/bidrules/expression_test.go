@@ -0,0 +1,126 @@
+package bidrules
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mapVariables map[string]interface{}
+
+func (v mapVariables) lookup(name string) interface{} {
+	return v[name]
+}
+
+var testVariables = mapVariables{
+	"bidder":      "appnexus",
+	"bid.price":   1.5,
+	"bid.dealid":  "",
+	"bid.crid":    "Creative-1",
+	"bid.adomain": []interface{}{"a.com", "b.com"},
+	"bid.w":       300.0,
+}
+
+func TestEvalExpression(t *testing.T) {
+	testCases := []struct {
+		expression string
+		expected   interface{}
+	}{
+		{expression: "1 + 2 * 3", expected: 7.0},
+		{expression: "(1 + 2) * 3", expected: 9.0},
+		{expression: "-bid.price + 2", expected: 0.5},
+		{expression: "bid.price * 0.9", expected: 1.35},
+		{expression: "bid.price >= 1.5 && bidder == 'appnexus'", expected: true},
+		{expression: `bidder != "appnexus" || bid.w < 300`, expected: false},
+		{expression: "!(bid.dealid == '')", expected: false},
+		{expression: "bidder in ['rubicon', 'appnexus']", expected: true},
+		{expression: "'c.com' in bid.adomain", expected: false},
+		{expression: "contains(bid.adomain, 'b.com')", expected: true},
+		{expression: "contains(bid.crid, 'ive')", expected: true},
+		{expression: "startsWith(lower(bid.crid), 'creative')", expected: true},
+		{expression: "endsWith(bidder, 'nexus')", expected: true},
+		{expression: "len(bid.adomain) + len(bidder)", expected: 10.0},
+		{expression: "'deal-' + bidder", expected: "deal-appnexus"},
+		{expression: "'b' > 'a'", expected: true},
+		{expression: "true", expected: true},
+		{expression: "[bid.adomain] in [bid.adomain]", expected: false},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.expression, func(t *testing.T) {
+			expr, err := parseExpression(test.expression, 512)
+			require.NoError(t, err)
+
+			value, err := expr.eval(&env{vars: testVariables, steps: 100})
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, value)
+		})
+	}
+}
+
+func TestParseExpressionErrors(t *testing.T) {
+	testCases := []struct {
+		expression    string
+		expectedError string
+	}{
+		{expression: "bid.price > ", expectedError: "unexpected end of expression at 12"},
+		{expression: "bid.cost > 1", expectedError: `unknown variable "bid.cost" at 0`},
+		{expression: "exec('rm')", expectedError: `unknown function "exec" at 0`},
+		{expression: "lower('a', 'b')", expectedError: "lower takes 1 arguments but was given 2 at 0"},
+		{expression: "'unterminated", expectedError: "unterminated string at 0"},
+		{expression: "bid.price > 1 1", expectedError: `unexpected "1" at 14`},
+		{expression: "(bid.price", expectedError: `expected ")" but found end of expression at 10`},
+		{expression: "bid.price = 1", expectedError: `unexpected '=' at 10`},
+		{expression: "1.2.3", expectedError: `malformed number "1.2.3" at 0`},
+		{expression: "bid.price > 10000", expectedError: "expression is longer than 16 bytes"},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.expression, func(t *testing.T) {
+			_, err := parseExpression(test.expression, 16)
+			assert.EqualError(t, err, test.expectedError)
+		})
+	}
+}
+
+func TestEvalExpressionErrors(t *testing.T) {
+	testCases := []struct {
+		expression    string
+		expectedError string
+	}{
+		{expression: "bidder > 1", expectedError: "> can't be applied to string and number"},
+		{expression: "bid.price == 'high'", expectedError: "can't compare number with string"},
+		{expression: "bid.adomain == bid.adomain", expectedError: "can't compare list with list"},
+		{expression: "bid.price && true", expectedError: "&& needs a bool but was given number"},
+		{expression: "bidder in 'appnexus'", expectedError: "in needs a list but was given string"},
+		{expression: "bid.price / 0", expectedError: "division by zero"},
+		{expression: "lower(bid.w)", expectedError: "lower can't be applied to number"},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.expression, func(t *testing.T) {
+			expr, err := parseExpression(test.expression, 512)
+			require.NoError(t, err)
+
+			_, err = expr.eval(&env{vars: testVariables, steps: 100})
+
+			assert.EqualError(t, err, test.expectedError)
+		})
+	}
+}
+
+func TestEvalExpressionBudget(t *testing.T) {
+	expr, err := parseExpression("bid.price > 1 && bidder == 'appnexus'", 512)
+	require.NoError(t, err)
+
+	env := &env{vars: testVariables, steps: 7}
+	value, err := expr.eval(env)
+	assert.NoError(t, err)
+	assert.Equal(t, true, value)
+	assert.Equal(t, 0, env.steps)
+
+	_, err = expr.eval(env)
+	assert.ErrorIs(t, err, errBudgetExhausted)
+}
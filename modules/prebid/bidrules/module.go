@@ -0,0 +1,282 @@
+package bidrules
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/prebid/prebid-server/v2/exchange/entities"
+	"github.com/prebid/prebid-server/v2/hooks/hookanalytics"
+	"github.com/prebid/prebid-server/v2/hooks/hookstage"
+	"github.com/prebid/prebid-server/v2/logger"
+	"github.com/prebid/prebid-server/v2/modules/moduledeps"
+	"github.com/prebid/prebid-server/v2/openrtb_ext"
+)
+
+const activityName = "bid_rules"
+
+func Builder(cfg json.RawMessage, _ moduledeps.ModuleDeps) (interface{}, error) {
+	hostCfg, err := newHostConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return Module{cfg: hostCfg}, nil
+}
+
+// Module applies the rules of an account to its bids once all of them have been collected. Rules drop bids,
+// adjust their price or add targeting keys to them, for the bids which match their expression.
+type Module struct {
+	cfg hostConfig
+}
+
+// bidChange is what the rules do to a bid.
+type bidChange struct {
+	drop          bool
+	priceAdjusted bool
+	price         float64
+	targeting     map[string]string
+}
+
+// HandleAllProcessedBidResponsesHook applies the account's rules to each bid, in order. Rules stop being
+// applied to a bid once it's dropped, and later rules see the price earlier rules adjusted. A rule whose
+// expressions fail for a bid is skipped for it, with a warning.
+func (m Module) HandleAllProcessedBidResponsesHook(
+	_ context.Context,
+	miCtx hookstage.ModuleInvocationContext,
+	payload hookstage.AllProcessedBidResponsesPayload,
+) (hookstage.HookResult[hookstage.AllProcessedBidResponsesPayload], error) {
+	result := hookstage.HookResult[hookstage.AllProcessedBidResponsesPayload]{}
+	if len(miCtx.AccountConfig) == 0 {
+		return result, nil
+	}
+
+	rules, err := newRules(miCtx.AccountConfig, m.cfg)
+	if err != nil || len(rules) == 0 {
+		return result, err
+	}
+
+	activity := hookanalytics.Activity{Name: activityName, Status: hookanalytics.ActivityStatusSuccess}
+	changes := make(map[*entities.PbsOrtbBid]*bidChange)
+	env := &env{steps: m.cfg.MaxStepsPerRequest}
+
+	// bidders are gone through in order so that the same bids use up the budget the same way
+	bidders := make([]openrtb_ext.BidderName, 0, len(payload.Responses))
+	for bidder := range payload.Responses {
+		bidders = append(bidders, bidder)
+	}
+	sort.Slice(bidders, func(i, j int) bool { return bidders[i] < bidders[j] })
+
+bidders:
+	for _, bidder := range bidders {
+		seatBid := payload.Responses[bidder]
+		if seatBid == nil {
+			continue
+		}
+		for _, bid := range seatBid.Bids {
+			if bid == nil || bid.Bid == nil {
+				continue
+			}
+			vars := &bidVariables{bidder: bidder, seatBid: seatBid, bid: bid, price: bid.Bid.Price}
+			env.vars = vars
+			change := &bidChange{}
+
+			for _, r := range rules {
+				applied, err := m.applyRule(r, env, vars, change)
+				if errors.Is(err, errBudgetExhausted) {
+					activity.Status = hookanalytics.ActivityStatusError
+					result.Warnings = append(result.Warnings, fmt.Sprintf("execution budget of %d steps exhausted, the remaining rules weren't applied", m.cfg.MaxStepsPerRequest))
+					recordChange(changes, bid, change)
+					break bidders
+				}
+				if err != nil {
+					result.Warnings = append(result.Warnings, fmt.Sprintf("rule %s failed for bid %s of %s: %s", r.name, bid.Bid.ID, bidder, err))
+					continue
+				}
+				if applied != nil {
+					activity.Results = append(activity.Results, *applied)
+				}
+				if change.drop {
+					break
+				}
+			}
+			recordChange(changes, bid, change)
+		}
+	}
+
+	if len(activity.Results) > 0 || activity.Status != hookanalytics.ActivityStatusSuccess {
+		result.AnalyticsTags = hookanalytics.Analytics{Activities: []hookanalytics.Activity{activity}}
+	}
+	if len(changes) > 0 {
+		result.ChangeSet.AddMutation(func(payload hookstage.AllProcessedBidResponsesPayload) (hookstage.AllProcessedBidResponsesPayload, error) {
+			applyChanges(payload.Responses, changes)
+			return payload, nil
+		}, hookstage.MutationUpdate, "bids")
+	}
+	return result, nil
+}
+
+// applyRule applies the rule to the bid if it matches, and returns the analytics result of what it did.
+func (m Module) applyRule(r rule, env *env, vars *bidVariables, change *bidChange) (*hookanalytics.Result, error) {
+	if r.when != nil {
+		matches, err := evalBool(r.when, env, "when")
+		if err != nil || !matches {
+			return nil, err
+		}
+	}
+
+	bid := vars.bid.Bid
+	values := map[string]interface{}{"rule": r.name, "action": r.action}
+	status := hookanalytics.ResultStatusModify
+	switch r.action {
+	case actionDrop:
+		change.drop = true
+		status = hookanalytics.ResultStatusBlock
+	case actionAdjustPrice:
+		value, err := r.value.eval(env)
+		if err != nil {
+			return nil, err
+		}
+		price, ok := value.(float64)
+		if !ok || price <= 0 {
+			return nil, fmt.Errorf("price must be a positive number, but was %v", value)
+		}
+		values["original_price"] = vars.price
+		values["price"] = price
+		vars.price = price
+		change.price = price
+		change.priceAdjusted = true
+	case actionAddTargeting:
+		value, err := r.value.eval(env)
+		if err != nil {
+			return nil, err
+		}
+		targetingValue, err := formatTargetingValue(value)
+		if err != nil {
+			return nil, err
+		}
+		if change.targeting == nil {
+			change.targeting = make(map[string]string)
+		}
+		change.targeting[r.key] = targetingValue
+		values["key"] = r.key
+		values["value"] = targetingValue
+	}
+
+	if m.cfg.AuditLog {
+		logger.Infof("Bid rule %s applied %s to bid %s of %s for imp %s: %v", r.name, r.action, bid.ID, vars.bidder, bid.ImpID, values)
+	}
+	return &hookanalytics.Result{
+		Status: status,
+		Values: values,
+		AppliedTo: hookanalytics.AppliedTo{
+			Bidder: vars.bidder.String(),
+			BidIds: []string{bid.ID},
+			ImpIds: []string{bid.ImpID},
+		},
+	}, nil
+}
+
+func recordChange(changes map[*entities.PbsOrtbBid]*bidChange, bid *entities.PbsOrtbBid, change *bidChange) {
+	if change.drop || change.priceAdjusted || len(change.targeting) > 0 {
+		changes[bid] = change
+	}
+}
+
+// applyChanges drops, reprices and adds targeting to the bids as the rules decided.
+func applyChanges(responses map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid, changes map[*entities.PbsOrtbBid]*bidChange) {
+	for _, seatBid := range responses {
+		if seatBid == nil {
+			continue
+		}
+		bids := make([]*entities.PbsOrtbBid, 0, len(seatBid.Bids))
+		for _, bid := range seatBid.Bids {
+			change, ok := changes[bid]
+			if !ok {
+				bids = append(bids, bid)
+				continue
+			}
+			if change.drop {
+				continue
+			}
+			if change.priceAdjusted {
+				bid.Bid.Price = change.price
+			}
+			if len(change.targeting) > 0 && bid.BidTargets == nil {
+				bid.BidTargets = make(map[string]string, len(change.targeting))
+			}
+			for key, value := range change.targeting {
+				bid.BidTargets[key] = value
+			}
+			bids = append(bids, bid)
+		}
+		seatBid.Bids = bids
+	}
+}
+
+func formatTargetingValue(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case string:
+		return v, nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	}
+	return "", fmt.Errorf("targeting value must be a string, number or bool, but was %s", typeName(value))
+}
+
+// bidVariables are the variables of a bid which expressions are evaluated against.
+type bidVariables struct {
+	bidder  openrtb_ext.BidderName
+	seatBid *entities.PbsOrtbSeatBid
+	bid     *entities.PbsOrtbBid
+	// price is the price of the bid as adjusted by the rules so far
+	price float64
+}
+
+func (v *bidVariables) lookup(name string) interface{} {
+	bid := v.bid.Bid
+	switch name {
+	case "bidder":
+		return v.bidder.String()
+	case "seat":
+		if v.seatBid.Seat != "" {
+			return v.seatBid.Seat
+		}
+		return v.bidder.String()
+	case "currency":
+		return v.seatBid.Currency
+	case "bid.id":
+		return bid.ID
+	case "bid.impid":
+		return bid.ImpID
+	case "bid.price":
+		return v.price
+	case "bid.dealid":
+		return bid.DealID
+	case "bid.crid":
+		return bid.CrID
+	case "bid.adomain":
+		return stringList(bid.ADomain)
+	case "bid.cat":
+		return stringList(bid.Cat)
+	case "bid.w":
+		return float64(bid.W)
+	case "bid.h":
+		return float64(bid.H)
+	case "bid.type":
+		return string(v.bid.BidType)
+	}
+	return nil
+}
+
+func stringList(values []string) []interface{} {
+	list := make([]interface{}, 0, len(values))
+	for _, value := range values {
+		list = append(list, value)
+	}
+	return list
+}
@@ -0,0 +1,167 @@
+package bidrules
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v2/exchange/entities"
+	"github.com/prebid/prebid-server/v2/hooks/hookanalytics"
+	"github.com/prebid/prebid-server/v2/hooks/hookstage"
+	"github.com/prebid/prebid-server/v2/modules/moduledeps"
+	"github.com/prebid/prebid-server/v2/openrtb_ext"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuilder(t *testing.T) {
+	module, err := Builder(json.RawMessage(`{"enabled":true,"max_rules":3}`), moduledeps.ModuleDeps{})
+	require.NoError(t, err)
+	assert.Equal(t, 3, module.(Module).cfg.MaxRules)
+
+	_, err = Builder(json.RawMessage(`{"max_rules":0}`), moduledeps.ModuleDeps{})
+	assert.Error(t, err)
+}
+
+func newTestPayload() hookstage.AllProcessedBidResponsesPayload {
+	return hookstage.AllProcessedBidResponsesPayload{
+		Responses: map[openrtb_ext.BidderName]*entities.PbsOrtbSeatBid{
+			openrtb_ext.BidderAppnexus: {
+				Currency: "USD",
+				Bids: []*entities.PbsOrtbBid{
+					{Bid: &openrtb2.Bid{ID: "bid-1", ImpID: "imp-1", Price: 0.05}, BidType: openrtb_ext.BidTypeBanner},
+					{Bid: &openrtb2.Bid{ID: "bid-2", ImpID: "imp-2", Price: 2, DealID: "deal-1"}, BidType: openrtb_ext.BidTypeVideo},
+				},
+			},
+			openrtb_ext.BidderRubicon: {
+				Currency: "USD",
+				Bids: []*entities.PbsOrtbBid{
+					{Bid: &openrtb2.Bid{ID: "bid-3", ImpID: "imp-1", Price: 1, ADomain: []string{"blocked.com"}}, BidType: openrtb_ext.BidTypeBanner},
+				},
+			},
+		},
+	}
+}
+
+func TestHandleAllProcessedBidResponsesHook(t *testing.T) {
+	hostCfg, err := newHostConfig(nil)
+	require.NoError(t, err)
+	module := Module{cfg: hostCfg}
+
+	accountConfig := json.RawMessage(`{"rules":[
+		{"name":"floor","when":"bid.price < 0.1","action":"drop"},
+		{"name":"blocked","when":"'blocked.com' in bid.adomain","action":"drop"},
+		{"name":"shade","when":"bidder == 'appnexus'","action":"adjust_price","value":"bid.price * 0.9"},
+		{"name":"deal","when":"bid.dealid != ''","action":"add_targeting","key":"hb_deal_type","value":"bid.type + '-' + bid.dealid"}
+	]}`)
+	payload := newTestPayload()
+
+	result, err := module.HandleAllProcessedBidResponsesHook(context.Background(), hookstage.ModuleInvocationContext{AccountConfig: accountConfig}, payload)
+	require.NoError(t, err)
+	assert.Empty(t, result.Warnings)
+
+	// the payload is only changed by the mutation
+	assert.Len(t, payload.Responses[openrtb_ext.BidderAppnexus].Bids, 2)
+	require.Len(t, result.ChangeSet.Mutations(), 1)
+	_, err = result.ChangeSet.Mutations()[0].Apply(payload)
+	require.NoError(t, err)
+
+	appnexusBids := payload.Responses[openrtb_ext.BidderAppnexus].Bids
+	require.Len(t, appnexusBids, 1)
+	assert.Equal(t, "bid-2", appnexusBids[0].Bid.ID)
+	assert.Equal(t, 1.8, appnexusBids[0].Bid.Price)
+	assert.Equal(t, map[string]string{"hb_deal_type": "video-deal-1"}, appnexusBids[0].BidTargets)
+	assert.Empty(t, payload.Responses[openrtb_ext.BidderRubicon].Bids)
+
+	assert.Equal(t, hookanalytics.Analytics{Activities: []hookanalytics.Activity{{
+		Name:   activityName,
+		Status: hookanalytics.ActivityStatusSuccess,
+		Results: []hookanalytics.Result{
+			{
+				Status:    hookanalytics.ResultStatusBlock,
+				Values:    map[string]interface{}{"rule": "floor", "action": "drop"},
+				AppliedTo: hookanalytics.AppliedTo{Bidder: "appnexus", BidIds: []string{"bid-1"}, ImpIds: []string{"imp-1"}},
+			},
+			{
+				Status:    hookanalytics.ResultStatusModify,
+				Values:    map[string]interface{}{"rule": "shade", "action": "adjust_price", "original_price": 2.0, "price": 1.8},
+				AppliedTo: hookanalytics.AppliedTo{Bidder: "appnexus", BidIds: []string{"bid-2"}, ImpIds: []string{"imp-2"}},
+			},
+			{
+				Status:    hookanalytics.ResultStatusModify,
+				Values:    map[string]interface{}{"rule": "deal", "action": "add_targeting", "key": "hb_deal_type", "value": "video-deal-1"},
+				AppliedTo: hookanalytics.AppliedTo{Bidder: "appnexus", BidIds: []string{"bid-2"}, ImpIds: []string{"imp-2"}},
+			},
+			{
+				Status:    hookanalytics.ResultStatusBlock,
+				Values:    map[string]interface{}{"rule": "blocked", "action": "drop"},
+				AppliedTo: hookanalytics.AppliedTo{Bidder: "rubicon", BidIds: []string{"bid-3"}, ImpIds: []string{"imp-1"}},
+			},
+		},
+	}}}, result.AnalyticsTags)
+}
+
+func TestHandleAllProcessedBidResponsesHookNoRules(t *testing.T) {
+	module := Module{cfg: hostConfig{MaxRules: 1, MaxExpressionLength: 1, MaxStepsPerRequest: 1}}
+
+	for _, accountConfig := range []json.RawMessage{nil, json.RawMessage(`{"enabled":true}`)} {
+		result, err := module.HandleAllProcessedBidResponsesHook(context.Background(), hookstage.ModuleInvocationContext{AccountConfig: accountConfig}, newTestPayload())
+		assert.NoError(t, err)
+		assert.Empty(t, result.ChangeSet.Mutations())
+		assert.Empty(t, result.AnalyticsTags.Activities)
+	}
+}
+
+func TestHandleAllProcessedBidResponsesHookInvalidConfig(t *testing.T) {
+	hostCfg, err := newHostConfig(nil)
+	require.NoError(t, err)
+
+	_, err = Module{cfg: hostCfg}.HandleAllProcessedBidResponsesHook(
+		context.Background(),
+		hookstage.ModuleInvocationContext{AccountConfig: json.RawMessage(`{"rules":[{"name":"a","action":"drop","when":"bid.cost > 1"}]}`)},
+		newTestPayload(),
+	)
+	assert.EqualError(t, err, `rules[0]: when: unknown variable "bid.cost" at 0`)
+}
+
+func TestHandleAllProcessedBidResponsesHookRuleErrors(t *testing.T) {
+	hostCfg, err := newHostConfig(nil)
+	require.NoError(t, err)
+	accountConfig := json.RawMessage(`{"rules":[
+		{"name":"bad-price","action":"adjust_price","value":"bid.price - 1"},
+		{"name":"bad-when","when":"bid.dealid","action":"drop"}
+	]}`)
+
+	result, err := Module{cfg: hostCfg}.HandleAllProcessedBidResponsesHook(context.Background(), hookstage.ModuleInvocationContext{AccountConfig: accountConfig}, newTestPayload())
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"rule bad-price failed for bid bid-1 of appnexus: price must be a positive number, but was -0.95",
+		"rule bad-when failed for bid bid-1 of appnexus: when needs a bool but was given string",
+		"rule bad-when failed for bid bid-2 of appnexus: when needs a bool but was given string",
+		"rule bad-price failed for bid bid-3 of rubicon: price must be a positive number, but was 0",
+		"rule bad-when failed for bid bid-3 of rubicon: when needs a bool but was given string",
+	}, result.Warnings)
+	assert.Len(t, result.ChangeSet.Mutations(), 1)
+}
+
+func TestHandleAllProcessedBidResponsesHookBudget(t *testing.T) {
+	module := Module{cfg: hostConfig{MaxRules: 1, MaxExpressionLength: 64, MaxStepsPerRequest: 4}}
+	accountConfig := json.RawMessage(`{"rules":[{"name":"floor","when":"bid.price < 0.1","action":"drop"}]}`)
+	payload := newTestPayload()
+
+	result, err := module.HandleAllProcessedBidResponsesHook(context.Background(), hookstage.ModuleInvocationContext{AccountConfig: accountConfig}, payload)
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"execution budget of 4 steps exhausted, the remaining rules weren't applied"}, result.Warnings)
+	require.Len(t, result.AnalyticsTags.Activities, 1)
+	assert.Equal(t, hookanalytics.ActivityStatusError, result.AnalyticsTags.Activities[0].Status)
+
+	// the first bid was dropped before the budget ran out
+	require.Len(t, result.ChangeSet.Mutations(), 1)
+	_, err = result.ChangeSet.Mutations()[0].Apply(payload)
+	require.NoError(t, err)
+	assert.Len(t, payload.Responses[openrtb_ext.BidderAppnexus].Bids, 1)
+	assert.Len(t, payload.Responses[openrtb_ext.BidderRubicon].Bids, 1)
+}
@@ -15,18 +15,27 @@ type ExtBid struct {
 // DealPriority represents priority of deal bid. If its non deal bid then value will be 0
 // DealTierSatisfied true represents corresponding bid has satisfied the deal tier
 type ExtBidPrebid struct {
-	Cache             *ExtBidPrebidCache  `json:"cache,omitempty"`
-	DealPriority      int                 `json:"dealpriority,omitempty"`
-	DealTierSatisfied bool                `json:"dealtiersatisfied,omitempty"`
-	Meta              *ExtBidPrebidMeta   `json:"meta,omitempty"`
-	Targeting         map[string]string   `json:"targeting,omitempty"`
-	TargetBidderCode  string              `json:"targetbiddercode,omitempty"`
-	Type              BidType             `json:"type,omitempty"`
-	Video             *ExtBidPrebidVideo  `json:"video,omitempty"`
-	Events            *ExtBidPrebidEvents `json:"events,omitempty"`
-	BidId             string              `json:"bidid,omitempty"`
-	Passthrough       json.RawMessage     `json:"passthrough,omitempty"`
-	Floors            *ExtBidPrebidFloors `json:"floors,omitempty"`
+	Cache             *ExtBidPrebidCache    `json:"cache,omitempty"`
+	DealPriority      int                   `json:"dealpriority,omitempty"`
+	DealTierSatisfied bool                  `json:"dealtiersatisfied,omitempty"`
+	Meta              *ExtBidPrebidMeta     `json:"meta,omitempty"`
+	Targeting         map[string]string     `json:"targeting,omitempty"`
+	TargetBidderCode  string                `json:"targetbiddercode,omitempty"`
+	Type              BidType               `json:"type,omitempty"`
+	Video             *ExtBidPrebidVideo    `json:"video,omitempty"`
+	Events            *ExtBidPrebidEvents   `json:"events,omitempty"`
+	BidId             string                `json:"bidid,omitempty"`
+	Passthrough       json.RawMessage       `json:"passthrough,omitempty"`
+	Floors            *ExtBidPrebidFloors   `json:"floors,omitempty"`
+	LineItem          *ExtBidPrebidLineItem `json:"lineitem,omitempty"`
+}
+
+// ExtBidPrebidLineItem defines the contract for bidresponse.seatbid.bid[i].ext.prebid.lineitem, the account's line
+// item the bid matched
+type ExtBidPrebidLineItem struct {
+	ID       string `json:"id"`
+	Name     string `json:"name,omitempty"`
+	Priority int    `json:"priority"`
 }
 
 // ExtBidPrebidFloors defines the contract for bidresponse.seatbid.bid[i].ext.prebid.floors
@@ -168,6 +177,12 @@ const (
 	HbEnvKeyApp string = "mobile-app"
 
 	HbCategoryDurationKey TargetingKey = "hb_pb_cat_dur"
+
+	// HbLineItemKey is the ID of the account's line item the bid matched, if any.
+	HbLineItemKey TargetingKey = "hb_li"
+
+	// HbPbEncKey is the price of the bid, encrypted with the account's price encryption keys.
+	HbPbEncKey TargetingKey = "hb_pb_enc"
 )
 
 func (key TargetingKey) BidderKey(bidder BidderName, maxLength int) string {
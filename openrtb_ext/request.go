@@ -91,8 +91,25 @@ type ExtRequestPrebid struct {
 	// - basic: excludes debugmessages and analytic_tags from output
 	// any other value or an empty string disables trace output at all.
 	Trace string `json:"trace,omitempty"`
+
+	// DebugToken is a signed token which turns on debug output, even for accounts which don't allow it.
+	// It's removed from the request once it has been checked.
+	DebugToken string `json:"debugtoken,omitempty"`
+
+	// ResolvedBidderRequests adds the request sent to each bidder to the debug output, once privacy
+	// enforcement, first party data and hooks have been applied to it. There are two options:
+	// - full: each request is returned as it is
+	// - diff: each request is returned as a JSON merge patch against ext.debug.resolvedrequest
+	// any other value or an empty string leaves them out.
+	ResolvedBidderRequests string `json:"resolvedbidderrequests,omitempty"`
 }
 
+// Options for ext.prebid.resolvedbidderrequests
+const (
+	ResolvedBidderRequestsFull = "full"
+	ResolvedBidderRequestsDiff = "diff"
+)
+
 type AdServerTarget struct {
 	Key    string `json:"key,omitempty"`
 	Source string `json:"source,omitempty"`
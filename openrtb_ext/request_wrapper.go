@@ -715,6 +715,37 @@ func (ue *UserExt) Clone() *UserExt {
 	return &clone
 }
 
+// rebuildExt returns the JSON of an ext. Unless the whole ext was replaced, only the members which changed
+// are spliced into the JSON it was unmarshaled from, and the others are copied as they are instead of being
+// marshaled again.
+func rebuildExt(raw json.RawMessage, ext map[string]json.RawMessage, replaced bool, changed []string) (json.RawMessage, error) {
+	if len(ext) == 0 {
+		return nil, nil
+	}
+	if !replaced && len(raw) != 0 {
+		if extJson, err := spliceExt(raw, ext, changed); err == nil {
+			return extJson, nil
+		}
+	}
+	return jsonutil.Marshal(ext)
+}
+
+func spliceExt(raw json.RawMessage, ext map[string]json.RawMessage, changed []string) (json.RawMessage, error) {
+	lazyExt := jsonutil.NewLazyExt(raw)
+	for _, name := range changed {
+		var err error
+		if value, ok := ext[name]; ok {
+			err = lazyExt.Set(name, value)
+		} else {
+			err = lazyExt.Delete(name)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return lazyExt.Bytes()
+}
+
 // ---------------------------------------------------------------
 // RequestExt provides an interface for request.ext
 // ---------------------------------------------------------------
@@ -722,6 +753,7 @@ func (ue *UserExt) Clone() *UserExt {
 type RequestExt struct {
 	ext         map[string]json.RawMessage
 	extDirty    bool
+	raw         json.RawMessage
 	prebid      *ExtRequestPrebid
 	prebidDirty bool
 	schain      *openrtb2.SupplyChain // ORTB 2.4 location
@@ -742,6 +774,7 @@ func (re *RequestExt) unmarshal(extJson json.RawMessage) error {
 	if err := jsonutil.Unmarshal(extJson, &re.ext); err != nil {
 		return err
 	}
+	re.raw = extJson
 
 	prebidJson, hasPrebid := re.ext[prebidKey]
 	if hasPrebid {
@@ -767,6 +800,7 @@ func (re *RequestExt) unmarshal(extJson json.RawMessage) error {
 }
 
 func (re *RequestExt) marshal() (json.RawMessage, error) {
+	var changed []string
 	if re.prebidDirty {
 		if re.prebid != nil {
 			prebidJson, err := jsonutil.Marshal(re.prebid)
@@ -781,6 +815,7 @@ func (re *RequestExt) marshal() (json.RawMessage, error) {
 		} else {
 			delete(re.ext, prebidKey)
 		}
+		changed = append(changed, prebidKey)
 		re.prebidDirty = false
 	}
 
@@ -798,14 +833,17 @@ func (re *RequestExt) marshal() (json.RawMessage, error) {
 		} else {
 			delete(re.ext, schainKey)
 		}
+		changed = append(changed, schainKey)
 		re.schainDirty = false
 	}
 
-	re.extDirty = false
-	if len(re.ext) == 0 {
-		return nil, nil
+	extJson, err := rebuildExt(re.raw, re.ext, re.extDirty, changed)
+	if err != nil {
+		return nil, err
 	}
-	return jsonutil.Marshal(re.ext)
+	re.raw = extJson
+	re.extDirty = false
+	return extJson, nil
 }
 
 func (re *RequestExt) Dirty() bool {
@@ -1624,6 +1662,7 @@ func (w *ImpWrapper) Clone() *ImpWrapper {
 type ImpExt struct {
 	ext         map[string]json.RawMessage
 	extDirty    bool
+	raw         json.RawMessage
 	prebid      *ExtImpPrebid
 	data        *ExtImpData
 	prebidDirty bool
@@ -1646,6 +1685,7 @@ func (e *ImpExt) unmarshal(extJson json.RawMessage) error {
 	if err := jsonutil.Unmarshal(extJson, &e.ext); err != nil {
 		return err
 	}
+	e.raw = extJson
 
 	prebidJson, hasPrebid := e.ext[prebidKey]
 	if hasPrebid {
@@ -1685,6 +1725,7 @@ func (e *ImpExt) unmarshal(extJson json.RawMessage) error {
 }
 
 func (e *ImpExt) marshal() (json.RawMessage, error) {
+	var changed []string
 	if e.prebidDirty {
 		if e.prebid != nil {
 			prebidJson, err := jsonutil.Marshal(e.prebid)
@@ -1699,6 +1740,7 @@ func (e *ImpExt) marshal() (json.RawMessage, error) {
 		} else {
 			delete(e.ext, prebidKey)
 		}
+		changed = append(changed, prebidKey)
 		e.prebidDirty = false
 	}
 
@@ -1712,14 +1754,17 @@ func (e *ImpExt) marshal() (json.RawMessage, error) {
 		} else {
 			delete(e.ext, "tid")
 		}
+		changed = append(changed, "tid")
 		e.tidDirty = false
 	}
 
-	e.extDirty = false
-	if len(e.ext) == 0 {
-		return nil, nil
+	extJson, err := rebuildExt(e.raw, e.ext, e.extDirty, changed)
+	if err != nil {
+		return nil, err
 	}
-	return jsonutil.Marshal(e.ext)
+	e.raw = extJson
+	e.extDirty = false
+	return extJson, nil
 }
 
 func (e *ImpExt) Dirty() bool {
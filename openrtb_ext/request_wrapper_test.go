@@ -8,6 +8,7 @@ import (
 	"github.com/prebid/prebid-server/v2/errortypes"
 	"github.com/prebid/prebid-server/v2/util/ptrutil"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestCloneRequestWrapper(t *testing.T) {
@@ -1834,6 +1835,7 @@ func TestImpWrapperGetImpExt(t *testing.T) {
 					PbAdslot: "pbadslot123",
 				},
 				prebid: &ExtImpPrebid{IsRewardedInventory: &isRewardedInventoryOne},
+				raw:    json.RawMessage(`{"prebid":{"is_rewarded_inventory":1},"other":42,"tid":"test-tid","gpid":"test-gpid","data":{"adserver":{"name":"ads","adslot":"adslot123"},"pbadslot":"pbadslot123"}}`),
 			},
 		},
 		{
@@ -1879,6 +1881,31 @@ func TestImpExtTid(t *testing.T) {
 	assert.Equal(t, true, impExt.Dirty(), "New impext should be dirty.")
 }
 
+func TestImpExtMarshalSplicesChanges(t *testing.T) {
+	w := &ImpWrapper{Imp: &openrtb2.Imp{Ext: json.RawMessage(`{"zeta": {"a" : 1}, "tid":"old", "prebid":{"is_rewarded_inventory":1}, "gpid":"x"}`)}}
+	impExt, err := w.GetImpExt()
+	require.NoError(t, err)
+
+	impExt.SetTid("new")
+	impExt.SetPrebid(nil)
+	require.NoError(t, w.RebuildImp())
+	assert.Equal(t, `{"zeta":{"a" : 1},"tid":"new","gpid":"x"}`, string(w.Ext), "unchanged members should keep their order and formatting")
+
+	impExt.SetExt(map[string]json.RawMessage{"zeta": json.RawMessage(`2`), "alpha": json.RawMessage(`1`)})
+	require.NoError(t, w.RebuildImp())
+	assert.Equal(t, `{"alpha":1,"zeta":2}`, string(w.Ext), "a replaced ext should be marshaled again")
+}
+
+func TestRequestExtMarshalSplicesChanges(t *testing.T) {
+	w := &RequestWrapper{BidRequest: &openrtb2.BidRequest{Ext: json.RawMessage(`{"zeta": [1, 2], "prebid":{"debug":true}}`)}}
+	requestExt, err := w.GetRequestExt()
+	require.NoError(t, err)
+
+	requestExt.SetSChain(&openrtb2.SupplyChain{Ver: "1.0"})
+	require.NoError(t, w.RebuildRequest())
+	assert.Equal(t, `{"zeta":[1, 2],"prebid":{"debug":true},"schain":{"complete":0,"nodes":null,"ver":"1.0"}}`, string(w.Ext))
+}
+
 func TestCloneImpWrapper(t *testing.T) {
 	testCases := []struct {
 		name           string
@@ -5,6 +5,7 @@ import (
 
 	"github.com/prebid/openrtb/v20/adcom1"
 	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v2/errortypes"
 )
 
 // ExtBidResponse defines the contract for bidresponse.ext
@@ -31,6 +32,64 @@ type ExtResponseDebug struct {
 	HttpCalls map[BidderName][]*ExtHttpCall `json:"httpcalls,omitempty"`
 	// Request after resolution of stored requests and debug overrides
 	ResolvedRequest json.RawMessage `json:"resolvedrequest,omitempty"`
+	// ResolvedBidderRequests holds the request sent to each bidder, or the difference between it and
+	// ResolvedRequest, as asked for by ext.prebid.resolvedbidderrequests
+	ResolvedBidderRequests map[BidderName]json.RawMessage `json:"resolvedbidderrequests,omitempty"`
+	// LatencyBudget defines the contract for bidresponse.ext.debug.latencybudget
+	LatencyBudget *ExtResponseLatencyBudget `json:"latencybudget,omitempty"`
+	// ResolvedFPD shows how the first party data at the paths of the account's merge rules was resolved
+	ResolvedFPD []ExtResponseResolvedFPD `json:"resolvedfpd,omitempty"`
+	// ResolvedSizes shows the formats resolved for banner imps from the device's screen
+	ResolvedSizes []ExtResponseResolvedSizes `json:"resolvedsizes,omitempty"`
+	// DynamicTmax shows how the request's tmax was adjusted by the account's dynamic tmax
+	DynamicTmax *ExtResponseDynamicTmax `json:"dynamictmax,omitempty"`
+}
+
+// ExtResponseResolvedFPD defines the contract for bidresponse.ext.debug.resolvedfpd
+type ExtResponseResolvedFPD struct {
+	Path string `json:"path"`
+	// Sources holds the data each source had at the path, by source
+	Sources map[string]json.RawMessage `json:"sources,omitempty"`
+	Value   json.RawMessage            `json:"value,omitempty"`
+	// Capped is true if the data was left out of the request for being over the size cap
+	Capped bool `json:"capped,omitempty"`
+}
+
+// ExtResponseResolvedSizes defines the contract for bidresponse.ext.debug.resolvedsizes
+type ExtResponseResolvedSizes struct {
+	ImpID string `json:"impid"`
+	// Source is interstitial, for interstitials sized by device.ext.prebid.interstitial, or device, for
+	// banners without a size
+	Source      string            `json:"source"`
+	Orientation string            `json:"orientation,omitempty"`
+	MinW        int64             `json:"minw"`
+	MinH        int64             `json:"minh"`
+	MaxW        int64             `json:"maxw"`
+	MaxH        int64             `json:"maxh"`
+	Format      []openrtb2.Format `json:"format"`
+}
+
+// ExtResponseDynamicTmax defines the contract for bidresponse.ext.debug.dynamictmax
+type ExtResponseDynamicTmax struct {
+	// RequestedMS is the tmax of the request, or 0 if it had none
+	RequestedMS    int64  `json:"requestedms"`
+	AppliedMS      int64  `json:"appliedms"`
+	Channel        string `json:"channel"`
+	ConnectionType string `json:"connectiontype,omitempty"`
+	// Bound is min, max or host_max if the tmax was held to that bound
+	Bound string `json:"bound,omitempty"`
+}
+
+// ExtResponseLatencyBudget defines the contract for bidresponse.ext.debug.latencybudget
+type ExtResponseLatencyBudget struct {
+	TmaxMillis int64                                    `json:"tmaxms"`
+	Stages     map[string]ExtResponseLatencyBudgetStage `json:"stages"`
+}
+
+// ExtResponseLatencyBudgetStage defines the contract for bidresponse.ext.debug.latencybudget.stages.{stage}
+type ExtResponseLatencyBudgetStage struct {
+	AllocatedMillis int64 `json:"allocatedms"`
+	UsedMillis      int64 `json:"usedms"`
 }
 
 // ExtResponseSyncData defines the contract for bidresponse.ext.usersync.{bidder}
@@ -51,6 +110,25 @@ type ExtResponsePrebid struct {
 	SeatNonBid []SeatNonBid `json:"seatnonbid,omitempty"`
 }
 
+// ExtSeatBid defines the contract for bidresponse.seatbid[i].ext
+type ExtSeatBid struct {
+	Prebid *ExtSeatBidPrebid `json:"prebid,omitempty"`
+}
+
+// ExtSeatBidPrebid defines the contract for bidresponse.seatbid[i].ext.prebid
+type ExtSeatBidPrebid struct {
+	CurrencyRates []ExtSeatBidCurrencyRate `json:"currencyrates,omitempty"`
+}
+
+// ExtSeatBidCurrencyRate defines the contract for bidresponse.seatbid[i].ext.prebid.currencyrates[j], the rate
+// a bid was converted to the currency of the response with.
+type ExtSeatBidCurrencyRate struct {
+	BidID string `json:"bidid"`
+	// Cur is the currency the bidder made the bid in
+	Cur  string  `json:"cur"`
+	Rate float64 `json:"rate"`
+}
+
 // FledgeResponse defines the contract for bidresponse.ext.fledge
 type Fledge struct {
 	AuctionConfigs []*FledgeAuctionConfig `json:"auctionconfigs,omitempty"`
@@ -72,8 +150,46 @@ type ExtUserSync struct {
 
 // ExtBidderMessage defines an error object to be returned, consiting of a machine readable error code, and a human readable error message string.
 type ExtBidderMessage struct {
-	Code    int    `json:"code"`
-	Message string `json:"message"`
+	Code int `json:"code"`
+	// Category is the machine readable category of the code, such as validation or privacy.
+	Category errortypes.Category `json:"category,omitempty"`
+	Message  string              `json:"message"`
+	// Position is where in the JSON the error was found, for errors about malformed JSON.
+	Position *ExtMessagePosition `json:"position,omitempty"`
+}
+
+// ExtMessagePosition defines the contract for the position of an error in the JSON it was found in, such as the
+// request or a bidder's response.
+type ExtMessagePosition struct {
+	Path     string `json:"path,omitempty"`
+	Offset   int64  `json:"offset"`
+	Expected string `json:"expected,omitempty"`
+}
+
+// NewExtBidderMessage returns the message for an error or warning, with its code and category, and its
+// position if it's about malformed JSON.
+func NewExtBidderMessage(err error) ExtBidderMessage {
+	code := errortypes.ReadCode(err)
+	return ExtBidderMessage{
+		Code:     code,
+		Category: errortypes.CategoryOf(code),
+		Message:  err.Error(),
+		Position: NewExtMessagePosition(err),
+	}
+}
+
+// NewExtMessagePosition returns the position of the error in the JSON it was found in, or nil if it isn't
+// an error about malformed JSON or its position isn't known.
+func NewExtMessagePosition(err error) *ExtMessagePosition {
+	unmarshalErr, ok := err.(*errortypes.FailedToUnmarshal)
+	if !ok || unmarshalErr.Position == nil {
+		return nil
+	}
+	return &ExtMessagePosition{
+		Path:     unmarshalErr.Position.Path,
+		Offset:   unmarshalErr.Position.Offset,
+		Expected: unmarshalErr.Position.Expected,
+	}
 }
 
 // ExtHttpCall defines the contract for a bidresponse.ext.debug.httpcalls.{bidder}[i]
@@ -421,3 +421,357 @@ func CloneBidRequestPartial(s *openrtb2.BidRequest) *openrtb2.BidRequest {
 
 	return &c
 }
+
+// CloneBidRequest performs a deep clone of the bid request.
+func CloneBidRequest(s *openrtb2.BidRequest) *openrtb2.BidRequest {
+	if s == nil {
+		return nil
+	}
+
+	// Shallow Copy (Value Fields)
+	c := *s
+
+	// Deep Copy (Pointers)
+	c.Imp = CloneImpSlice(s.Imp)
+	c.Site = CloneSite(s.Site)
+	c.App = CloneApp(s.App)
+	c.DOOH = CloneDOOH(s.DOOH)
+	c.Device = CloneDevice(s.Device)
+	c.User = CloneUser(s.User)
+	c.WSeat = sliceutil.Clone(s.WSeat)
+	c.BSeat = sliceutil.Clone(s.BSeat)
+	c.Cur = sliceutil.Clone(s.Cur)
+	c.WLang = sliceutil.Clone(s.WLang)
+	c.WLangB = sliceutil.Clone(s.WLangB)
+	c.ACat = sliceutil.Clone(s.ACat)
+	c.BCat = sliceutil.Clone(s.BCat)
+	c.BAdv = sliceutil.Clone(s.BAdv)
+	c.BApp = sliceutil.Clone(s.BApp)
+	c.Source = CloneSource(s.Source)
+	c.Regs = CloneRegs(s.Regs)
+	c.Ext = sliceutil.Clone(s.Ext)
+
+	return &c
+}
+
+func CloneImpSlice(s []openrtb2.Imp) []openrtb2.Imp {
+	if s == nil {
+		return nil
+	}
+
+	c := make([]openrtb2.Imp, len(s))
+	for i, d := range s {
+		c[i] = CloneImp(d)
+	}
+
+	return c
+}
+
+func CloneImp(s openrtb2.Imp) openrtb2.Imp {
+	// Shallow Copy (Value Fields) Occurred By Passing Argument By Value
+	// - Implicitly created by the cloned array.
+
+	// Deep Copy (Pointers)
+	s.Metric = CloneMetricSlice(s.Metric)
+	s.Banner = CloneBanner(s.Banner)
+	s.Video = CloneVideo(s.Video)
+	s.Audio = CloneAudio(s.Audio)
+	s.Native = CloneNative(s.Native)
+	s.PMP = ClonePMP(s.PMP)
+	s.ClickBrowser = ptrutil.Clone(s.ClickBrowser)
+	s.Secure = ptrutil.Clone(s.Secure)
+	s.IframeBuster = sliceutil.Clone(s.IframeBuster)
+	s.Qty = CloneQty(s.Qty)
+	s.Refresh = CloneRefresh(s.Refresh)
+	s.Ext = sliceutil.Clone(s.Ext)
+
+	return s
+}
+
+func CloneMetricSlice(s []openrtb2.Metric) []openrtb2.Metric {
+	if s == nil {
+		return nil
+	}
+
+	c := make([]openrtb2.Metric, len(s))
+	for i, d := range s {
+		c[i] = CloneMetric(d)
+	}
+
+	return c
+}
+
+func CloneMetric(s openrtb2.Metric) openrtb2.Metric {
+	// Shallow Copy (Value Fields) Occurred By Passing Argument By Value
+	// - Implicitly created by the cloned array.
+
+	// Deep Copy (Pointers)
+	s.Ext = sliceutil.Clone(s.Ext)
+
+	return s
+}
+
+func CloneBannerSlice(s []openrtb2.Banner) []openrtb2.Banner {
+	if s == nil {
+		return nil
+	}
+
+	c := make([]openrtb2.Banner, len(s))
+	for i, d := range s {
+		c[i] = *CloneBanner(&d)
+	}
+
+	return c
+}
+
+func CloneBanner(s *openrtb2.Banner) *openrtb2.Banner {
+	if s == nil {
+		return nil
+	}
+
+	// Shallow Copy (Value Fields)
+	c := *s
+
+	// Deep Copy (Pointers)
+	c.Format = CloneFormatSlice(s.Format)
+	c.W = ptrutil.Clone(s.W)
+	c.H = ptrutil.Clone(s.H)
+	c.BType = sliceutil.Clone(s.BType)
+	c.BAttr = sliceutil.Clone(s.BAttr)
+	c.Pos = ptrutil.Clone(s.Pos)
+	c.MIMEs = sliceutil.Clone(s.MIMEs)
+	c.ExpDir = sliceutil.Clone(s.ExpDir)
+	c.API = sliceutil.Clone(s.API)
+	c.Vcm = ptrutil.Clone(s.Vcm)
+	c.Ext = sliceutil.Clone(s.Ext)
+
+	return &c
+}
+
+func CloneFormatSlice(s []openrtb2.Format) []openrtb2.Format {
+	if s == nil {
+		return nil
+	}
+
+	c := make([]openrtb2.Format, len(s))
+	for i, d := range s {
+		c[i] = CloneFormat(d)
+	}
+
+	return c
+}
+
+func CloneFormat(s openrtb2.Format) openrtb2.Format {
+	// Shallow Copy (Value Fields) Occurred By Passing Argument By Value
+	// - Implicitly created by the cloned array.
+
+	// Deep Copy (Pointers)
+	s.Ext = sliceutil.Clone(s.Ext)
+
+	return s
+}
+
+func CloneVideo(s *openrtb2.Video) *openrtb2.Video {
+	if s == nil {
+		return nil
+	}
+
+	// Shallow Copy (Value Fields)
+	c := *s
+
+	// Deep Copy (Pointers)
+	c.MIMEs = sliceutil.Clone(s.MIMEs)
+	c.StartDelay = ptrutil.Clone(s.StartDelay)
+	c.Protocols = sliceutil.Clone(s.Protocols)
+	c.W = ptrutil.Clone(s.W)
+	c.H = ptrutil.Clone(s.H)
+	c.RqdDurs = sliceutil.Clone(s.RqdDurs)
+	c.Skip = ptrutil.Clone(s.Skip)
+	c.BAttr = sliceutil.Clone(s.BAttr)
+	c.BoxingAllowed = ptrutil.Clone(s.BoxingAllowed)
+	c.PlaybackMethod = sliceutil.Clone(s.PlaybackMethod)
+	c.Delivery = sliceutil.Clone(s.Delivery)
+	c.Pos = ptrutil.Clone(s.Pos)
+	c.CompanionAd = CloneBannerSlice(s.CompanionAd)
+	c.API = sliceutil.Clone(s.API)
+	c.CompanionType = sliceutil.Clone(s.CompanionType)
+	c.DurFloors = CloneDurFloorsSlice(s.DurFloors)
+	c.Ext = sliceutil.Clone(s.Ext)
+
+	return &c
+}
+
+func CloneAudio(s *openrtb2.Audio) *openrtb2.Audio {
+	if s == nil {
+		return nil
+	}
+
+	// Shallow Copy (Value Fields)
+	c := *s
+
+	// Deep Copy (Pointers)
+	c.MIMEs = sliceutil.Clone(s.MIMEs)
+	c.Protocols = sliceutil.Clone(s.Protocols)
+	c.StartDelay = ptrutil.Clone(s.StartDelay)
+	c.RqdDurs = sliceutil.Clone(s.RqdDurs)
+	c.BAttr = sliceutil.Clone(s.BAttr)
+	c.Delivery = sliceutil.Clone(s.Delivery)
+	c.CompanionAd = CloneBannerSlice(s.CompanionAd)
+	c.API = sliceutil.Clone(s.API)
+	c.CompanionType = sliceutil.Clone(s.CompanionType)
+	c.Stitched = ptrutil.Clone(s.Stitched)
+	c.NVol = ptrutil.Clone(s.NVol)
+	c.DurFloors = CloneDurFloorsSlice(s.DurFloors)
+	c.Ext = sliceutil.Clone(s.Ext)
+
+	return &c
+}
+
+func CloneDurFloorsSlice(s []openrtb2.DurFloors) []openrtb2.DurFloors {
+	if s == nil {
+		return nil
+	}
+
+	c := make([]openrtb2.DurFloors, len(s))
+	for i, d := range s {
+		c[i] = CloneDurFloors(d)
+	}
+
+	return c
+}
+
+func CloneDurFloors(s openrtb2.DurFloors) openrtb2.DurFloors {
+	// Shallow Copy (Value Fields) Occurred By Passing Argument By Value
+	// - Implicitly created by the cloned array.
+
+	// Deep Copy (Pointers)
+	s.Ext = sliceutil.Clone(s.Ext)
+
+	return s
+}
+
+func CloneNative(s *openrtb2.Native) *openrtb2.Native {
+	if s == nil {
+		return nil
+	}
+
+	// Shallow Copy (Value Fields)
+	c := *s
+
+	// Deep Copy (Pointers)
+	c.API = sliceutil.Clone(s.API)
+	c.BAttr = sliceutil.Clone(s.BAttr)
+	c.Ext = sliceutil.Clone(s.Ext)
+
+	return &c
+}
+
+func ClonePMP(s *openrtb2.PMP) *openrtb2.PMP {
+	if s == nil {
+		return nil
+	}
+
+	// Shallow Copy (Value Fields)
+	c := *s
+
+	// Deep Copy (Pointers)
+	c.Deals = CloneDealSlice(s.Deals)
+	c.Ext = sliceutil.Clone(s.Ext)
+
+	return &c
+}
+
+func CloneDealSlice(s []openrtb2.Deal) []openrtb2.Deal {
+	if s == nil {
+		return nil
+	}
+
+	c := make([]openrtb2.Deal, len(s))
+	for i, d := range s {
+		c[i] = CloneDeal(d)
+	}
+
+	return c
+}
+
+func CloneDeal(s openrtb2.Deal) openrtb2.Deal {
+	// Shallow Copy (Value Fields) Occurred By Passing Argument By Value
+	// - Implicitly created by the cloned array.
+
+	// Deep Copy (Pointers)
+	s.WSeat = sliceutil.Clone(s.WSeat)
+	s.WADomain = sliceutil.Clone(s.WADomain)
+	s.DurFloors = CloneDurFloorsSlice(s.DurFloors)
+	s.Ext = sliceutil.Clone(s.Ext)
+
+	return s
+}
+
+func CloneQty(s *openrtb2.Qty) *openrtb2.Qty {
+	if s == nil {
+		return nil
+	}
+
+	// Shallow Copy (Value Fields)
+	c := *s
+
+	// Deep Copy (Pointers)
+	c.Ext = sliceutil.Clone(s.Ext)
+
+	return &c
+}
+
+func CloneRefresh(s *openrtb2.Refresh) *openrtb2.Refresh {
+	if s == nil {
+		return nil
+	}
+
+	// Shallow Copy (Value Fields)
+	c := *s
+
+	// Deep Copy (Pointers)
+	c.RefSettings = CloneRefSettingsSlice(s.RefSettings)
+	c.Count = ptrutil.Clone(s.Count)
+	c.Ext = sliceutil.Clone(s.Ext)
+
+	return &c
+}
+
+func CloneRefSettingsSlice(s []openrtb2.RefSettings) []openrtb2.RefSettings {
+	if s == nil {
+		return nil
+	}
+
+	c := make([]openrtb2.RefSettings, len(s))
+	for i, d := range s {
+		c[i] = CloneRefSettings(d)
+	}
+
+	return c
+}
+
+func CloneRefSettings(s openrtb2.RefSettings) openrtb2.RefSettings {
+	// Shallow Copy (Value Fields) Occurred By Passing Argument By Value
+	// - Implicitly created by the cloned array.
+
+	// Deep Copy (Pointers)
+	s.Ext = sliceutil.Clone(s.Ext)
+
+	return s
+}
+
+func CloneRegs(s *openrtb2.Regs) *openrtb2.Regs {
+	if s == nil {
+		return nil
+	}
+
+	// Shallow Copy (Value Fields)
+	c := *s
+
+	// Deep Copy (Pointers)
+	c.GDPR = ptrutil.Clone(s.GDPR)
+	c.GPPSID = sliceutil.Clone(s.GPPSID)
+	c.Ext = sliceutil.Clone(s.Ext)
+
+	return &c
+}
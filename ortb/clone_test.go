@@ -1156,8 +1156,533 @@ func TestCloneBidderReq(t *testing.T) {
 		assert.NotSame(t, given.User, result.User, "user")
 		assert.NotSame(t, given.Source, result.Source, "source")
 	})
+}
+
+func TestCloneBidRequest(t *testing.T) {
+	t.Run("nil", func(t *testing.T) {
+		result := CloneBidRequest(nil)
+		assert.Nil(t, result)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		given := &openrtb2.BidRequest{}
+		result := CloneBidRequest(given)
+		assert.Equal(t, given, result)
+		assert.NotSame(t, given, result)
+	})
+
+	t.Run("populated", func(t *testing.T) {
+		given := &openrtb2.BidRequest{
+			ID:      "anyID",
+			Imp:     []openrtb2.Imp{{ID: "imp1", Secure: ptrutil.ToPtr[int8](1), Banner: &openrtb2.Banner{W: ptrutil.ToPtr[int64](300)}}},
+			Site:    &openrtb2.Site{ID: "anySite"},
+			App:     &openrtb2.App{ID: "anyApp"},
+			DOOH:    &openrtb2.DOOH{ID: "anyDOOH"},
+			Device:  &openrtb2.Device{Carrier: "anyCarrier"},
+			User:    &openrtb2.User{ID: "anyUser"},
+			Test:    1,
+			AT:      2,
+			TMax:    3,
+			WSeat:   []string{"wSeat1"},
+			BSeat:   []string{"bSeat1"},
+			AllImps: 4,
+			Cur:     []string{"USD"},
+			WLang:   []string{"en"},
+			WLangB:  []string{"en"},
+			ACat:    []string{"aCat1"},
+			BCat:    []string{"bCat1"},
+			CatTax:  adcom1.CatTaxIABContent10,
+			BAdv:    []string{"bAdv1"},
+			BApp:    []string{"bApp1"},
+			Source:  &openrtb2.Source{TID: "anyTID"},
+			Regs:    &openrtb2.Regs{GDPR: ptrutil.ToPtr[int8](1)},
+			Ext:     json.RawMessage(`{"anyField":1}`),
+		}
+		result := CloneBidRequest(given)
+		assert.Equal(t, given, result, "equality")
+		assert.NotSame(t, given, result, "pointer")
+		assert.NotSame(t, &given.Imp[0], &result.Imp[0], "imp-item")
+		assert.NotSame(t, given.Imp[0].Secure, result.Imp[0].Secure, "imp-item-secure")
+		assert.NotSame(t, given.Imp[0].Banner, result.Imp[0].Banner, "imp-item-banner")
+		assert.NotSame(t, given.Imp[0].Banner.W, result.Imp[0].Banner.W, "imp-item-banner-w")
+		assert.NotSame(t, given.Site, result.Site, "site")
+		assert.NotSame(t, given.App, result.App, "app")
+		assert.NotSame(t, given.DOOH, result.DOOH, "dooh")
+		assert.NotSame(t, given.Device, result.Device, "device")
+		assert.NotSame(t, given.User, result.User, "user")
+		assert.NotSame(t, &given.WSeat[0], &result.WSeat[0], "wseat")
+		assert.NotSame(t, &given.BSeat[0], &result.BSeat[0], "bseat")
+		assert.NotSame(t, &given.Cur[0], &result.Cur[0], "cur")
+		assert.NotSame(t, &given.WLang[0], &result.WLang[0], "wlang")
+		assert.NotSame(t, &given.WLangB[0], &result.WLangB[0], "wlangb")
+		assert.NotSame(t, &given.ACat[0], &result.ACat[0], "acat")
+		assert.NotSame(t, &given.BCat[0], &result.BCat[0], "bcat")
+		assert.NotSame(t, &given.BAdv[0], &result.BAdv[0], "badv")
+		assert.NotSame(t, &given.BApp[0], &result.BApp[0], "bapp")
+		assert.NotSame(t, given.Source, result.Source, "source")
+		assert.NotSame(t, given.Regs, result.Regs, "regs")
+		assert.NotSame(t, given.Regs.GDPR, result.Regs.GDPR, "regs-gdpr")
+		assert.NotSame(t, &given.Ext[0], &result.Ext[0], "ext")
+	})
+
+	t.Run("assumptions", func(t *testing.T) {
+		assert.ElementsMatch(t, discoverPointerFields(reflect.TypeOf(openrtb2.BidRequest{})),
+			[]string{
+				"Imp",
+				"Site",
+				"App",
+				"DOOH",
+				"Device",
+				"User",
+				"WSeat",
+				"BSeat",
+				"Cur",
+				"WLang",
+				"WLangB",
+				"ACat",
+				"BCat",
+				"BAdv",
+				"BApp",
+				"Source",
+				"Regs",
+				"Ext",
+			})
+	})
+}
+
+func TestCloneImpSlice(t *testing.T) {
+	t.Run("nil", func(t *testing.T) {
+		result := CloneImpSlice(nil)
+		assert.Nil(t, result)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		given := []openrtb2.Imp{}
+		result := CloneImpSlice(given)
+		assert.Empty(t, result)
+		assert.NotNil(t, result)
+	})
+
+	t.Run("many", func(t *testing.T) {
+		given := []openrtb2.Imp{
+			{ID: "1", Ext: json.RawMessage(`{"anyField":1}`)},
+			{ID: "2", Ext: json.RawMessage(`{"anyField":2}`)},
+		}
+		result := CloneImpSlice(given)
+		assert.Equal(t, given, result, "equality")
+		assert.NotSame(t, &given[0], &result[0], "item0-pointer")
+		assert.NotSame(t, &given[0].Ext[0], &result[0].Ext[0], "item0-pointer-ext")
+		assert.NotSame(t, &given[1], &result[1], "item1-pointer")
+		assert.NotSame(t, &given[1].Ext[0], &result[1].Ext[0], "item1-pointer-ext")
+	})
+}
+
+func TestCloneImp(t *testing.T) {
+	t.Run("populated", func(t *testing.T) {
+		given := openrtb2.Imp{
+			ID:                "anyID",
+			Metric:            []openrtb2.Metric{{Type: "anyType", Ext: json.RawMessage(`{"metric":1}`)}},
+			Banner:            &openrtb2.Banner{ID: "anyBanner"},
+			Video:             &openrtb2.Video{MIMEs: []string{"video/mp4"}},
+			Audio:             &openrtb2.Audio{MIMEs: []string{"audio/mp4"}},
+			Native:            &openrtb2.Native{Request: "anyRequest"},
+			PMP:               &openrtb2.PMP{Deals: []openrtb2.Deal{{ID: "anyDeal"}}},
+			DisplayManager:    "anyDisplayManager",
+			DisplayManagerVer: "anyDisplayManagerVer",
+			Instl:             1,
+			TagID:             "anyTagID",
+			BidFloor:          2.0,
+			BidFloorCur:       "USD",
+			ClickBrowser:      ptrutil.ToPtr[int8](1),
+			Secure:            ptrutil.ToPtr[int8](1),
+			IframeBuster:      []string{"anyBuster"},
+			Rwdd:              1,
+			SSAI:              openrtb2.AdInsertion(2),
+			Exp:               3,
+			Qty:               &openrtb2.Qty{Multiplier: 1.5, Ext: json.RawMessage(`{"qty":1}`)},
+			DT:                4.0,
+			Refresh:           &openrtb2.Refresh{Count: ptrutil.ToPtr(1), RefSettings: []openrtb2.RefSettings{{MinInt: 30}}},
+			Ext:               json.RawMessage(`{"anyField":1}`),
+		}
+		result := CloneImp(given)
+		assert.Equal(t, given, result, "equality")
+		assert.NotSame(t, &given.Metric[0], &result.Metric[0], "metric-item")
+		assert.NotSame(t, &given.Metric[0].Ext[0], &result.Metric[0].Ext[0], "metric-item-ext")
+		assert.NotSame(t, given.Banner, result.Banner, "banner")
+		assert.NotSame(t, given.Video, result.Video, "video")
+		assert.NotSame(t, &given.Video.MIMEs[0], &result.Video.MIMEs[0], "video-mimes")
+		assert.NotSame(t, given.Audio, result.Audio, "audio")
+		assert.NotSame(t, &given.Audio.MIMEs[0], &result.Audio.MIMEs[0], "audio-mimes")
+		assert.NotSame(t, given.Native, result.Native, "native")
+		assert.NotSame(t, given.PMP, result.PMP, "pmp")
+		assert.NotSame(t, &given.PMP.Deals[0], &result.PMP.Deals[0], "pmp-deals-item")
+		assert.NotSame(t, given.ClickBrowser, result.ClickBrowser, "clickbrowser")
+		assert.NotSame(t, given.Secure, result.Secure, "secure")
+		assert.NotSame(t, &given.IframeBuster[0], &result.IframeBuster[0], "iframebuster")
+		assert.NotSame(t, given.Qty, result.Qty, "qty")
+		assert.NotSame(t, &given.Qty.Ext[0], &result.Qty.Ext[0], "qty-ext")
+		assert.NotSame(t, given.Refresh, result.Refresh, "refresh")
+		assert.NotSame(t, given.Refresh.Count, result.Refresh.Count, "refresh-count")
+		assert.NotSame(t, &given.Refresh.RefSettings[0], &result.Refresh.RefSettings[0], "refresh-refsettings-item")
+		assert.NotSame(t, &given.Ext[0], &result.Ext[0], "ext")
+	})
+
+	t.Run("assumptions", func(t *testing.T) {
+		assert.ElementsMatch(t, discoverPointerFields(reflect.TypeOf(openrtb2.Imp{})),
+			[]string{
+				"Metric",
+				"Banner",
+				"Video",
+				"Audio",
+				"Native",
+				"PMP",
+				"ClickBrowser",
+				"Secure",
+				"IframeBuster",
+				"Qty",
+				"Refresh",
+				"Ext",
+			})
+	})
+}
+
+func TestCloneBanner(t *testing.T) {
+	t.Run("nil", func(t *testing.T) {
+		result := CloneBanner(nil)
+		assert.Nil(t, result)
+	})
+
+	t.Run("populated", func(t *testing.T) {
+		given := &openrtb2.Banner{
+			Format:   []openrtb2.Format{{W: 300, H: 250, Ext: json.RawMessage(`{"format":1}`)}},
+			W:        ptrutil.ToPtr[int64](300),
+			H:        ptrutil.ToPtr[int64](250),
+			WMax:     1,
+			HMax:     2,
+			WMin:     3,
+			HMin:     4,
+			BType:    []openrtb2.BannerAdType{openrtb2.BannerAdTypeXHTMLTextAd},
+			BAttr:    []adcom1.CreativeAttribute{adcom1.AttrAudioAuto},
+			Pos:      ptrutil.ToPtr(adcom1.PositionAboveFold),
+			MIMEs:    []string{"image/png"},
+			TopFrame: 1,
+			ExpDir:   []adcom1.ExpandableDirection{adcom1.ExpandableLeft},
+			API:      []adcom1.APIFramework{adcom1.APIMRAID10},
+			ID:       "anyID",
+			Vcm:      ptrutil.ToPtr[int8](1),
+			Ext:      json.RawMessage(`{"anyField":1}`),
+		}
+		result := CloneBanner(given)
+		assert.Equal(t, given, result, "equality")
+		assert.NotSame(t, given, result, "pointer")
+		assert.NotSame(t, &given.Format[0], &result.Format[0], "format-item")
+		assert.NotSame(t, &given.Format[0].Ext[0], &result.Format[0].Ext[0], "format-item-ext")
+		assert.NotSame(t, given.W, result.W, "w")
+		assert.NotSame(t, given.H, result.H, "h")
+		assert.NotSame(t, &given.BType[0], &result.BType[0], "btype")
+		assert.NotSame(t, &given.BAttr[0], &result.BAttr[0], "battr")
+		assert.NotSame(t, given.Pos, result.Pos, "pos")
+		assert.NotSame(t, &given.MIMEs[0], &result.MIMEs[0], "mimes")
+		assert.NotSame(t, &given.ExpDir[0], &result.ExpDir[0], "expdir")
+		assert.NotSame(t, &given.API[0], &result.API[0], "api")
+		assert.NotSame(t, given.Vcm, result.Vcm, "vcm")
+		assert.NotSame(t, &given.Ext[0], &result.Ext[0], "ext")
+	})
+
+	t.Run("assumptions", func(t *testing.T) {
+		assert.ElementsMatch(t, discoverPointerFields(reflect.TypeOf(openrtb2.Banner{})),
+			[]string{
+				"Format",
+				"W",
+				"H",
+				"BType",
+				"BAttr",
+				"Pos",
+				"MIMEs",
+				"ExpDir",
+				"API",
+				"Vcm",
+				"Ext",
+			})
+	})
+}
+
+func TestCloneVideo(t *testing.T) {
+	t.Run("nil", func(t *testing.T) {
+		result := CloneVideo(nil)
+		assert.Nil(t, result)
+	})
+
+	t.Run("populated", func(t *testing.T) {
+		given := &openrtb2.Video{
+			MIMEs:          []string{"video/mp4"},
+			MinDuration:    1,
+			MaxDuration:    2,
+			StartDelay:     ptrutil.ToPtr(adcom1.StartPreRoll),
+			Protocols:      []adcom1.MediaCreativeSubtype{adcom1.CreativeVAST40},
+			W:              ptrutil.ToPtr[int64](640),
+			H:              ptrutil.ToPtr[int64](480),
+			RqdDurs:        []int64{15},
+			Skip:           ptrutil.ToPtr[int8](1),
+			BAttr:          []adcom1.CreativeAttribute{adcom1.AttrAudioAuto},
+			BoxingAllowed:  ptrutil.ToPtr[int8](1),
+			PlaybackMethod: []adcom1.PlaybackMethod{adcom1.PlaybackPageLoadSoundOn},
+			Delivery:       []adcom1.DeliveryMethod{adcom1.DeliveryStreaming},
+			Pos:            ptrutil.ToPtr(adcom1.PositionAboveFold),
+			CompanionAd:    []openrtb2.Banner{{ID: "anyCompanion", W: ptrutil.ToPtr[int64](300)}},
+			API:            []adcom1.APIFramework{adcom1.APIVPAID10},
+			CompanionType:  []adcom1.CompanionType{adcom1.CompanionStatic},
+			DurFloors:      []openrtb2.DurFloors{{MinDur: 1, Ext: json.RawMessage(`{"durfloors":1}`)}},
+			Ext:            json.RawMessage(`{"anyField":1}`),
+		}
+		result := CloneVideo(given)
+		assert.Equal(t, given, result, "equality")
+		assert.NotSame(t, given, result, "pointer")
+		assert.NotSame(t, &given.MIMEs[0], &result.MIMEs[0], "mimes")
+		assert.NotSame(t, given.StartDelay, result.StartDelay, "startdelay")
+		assert.NotSame(t, &given.Protocols[0], &result.Protocols[0], "protocols")
+		assert.NotSame(t, given.W, result.W, "w")
+		assert.NotSame(t, given.H, result.H, "h")
+		assert.NotSame(t, &given.RqdDurs[0], &result.RqdDurs[0], "rqddurs")
+		assert.NotSame(t, given.Skip, result.Skip, "skip")
+		assert.NotSame(t, &given.BAttr[0], &result.BAttr[0], "battr")
+		assert.NotSame(t, given.BoxingAllowed, result.BoxingAllowed, "boxingallowed")
+		assert.NotSame(t, &given.PlaybackMethod[0], &result.PlaybackMethod[0], "playbackmethod")
+		assert.NotSame(t, &given.Delivery[0], &result.Delivery[0], "delivery")
+		assert.NotSame(t, given.Pos, result.Pos, "pos")
+		assert.NotSame(t, &given.CompanionAd[0], &result.CompanionAd[0], "companionad-item")
+		assert.NotSame(t, given.CompanionAd[0].W, result.CompanionAd[0].W, "companionad-item-w")
+		assert.NotSame(t, &given.API[0], &result.API[0], "api")
+		assert.NotSame(t, &given.CompanionType[0], &result.CompanionType[0], "companiontype")
+		assert.NotSame(t, &given.DurFloors[0], &result.DurFloors[0], "durfloors-item")
+		assert.NotSame(t, &given.DurFloors[0].Ext[0], &result.DurFloors[0].Ext[0], "durfloors-item-ext")
+		assert.NotSame(t, &given.Ext[0], &result.Ext[0], "ext")
+	})
+
+	t.Run("assumptions", func(t *testing.T) {
+		assert.ElementsMatch(t, discoverPointerFields(reflect.TypeOf(openrtb2.Video{})),
+			[]string{
+				"MIMEs",
+				"StartDelay",
+				"Protocols",
+				"W",
+				"H",
+				"RqdDurs",
+				"Skip",
+				"BAttr",
+				"BoxingAllowed",
+				"PlaybackMethod",
+				"Delivery",
+				"Pos",
+				"CompanionAd",
+				"API",
+				"CompanionType",
+				"DurFloors",
+				"Ext",
+			})
+	})
+}
+
+func TestCloneAudio(t *testing.T) {
+	t.Run("nil", func(t *testing.T) {
+		result := CloneAudio(nil)
+		assert.Nil(t, result)
+	})
+
+	t.Run("populated", func(t *testing.T) {
+		given := &openrtb2.Audio{
+			MIMEs:         []string{"audio/mp4"},
+			Protocols:     []adcom1.MediaCreativeSubtype{adcom1.CreativeDAAST10},
+			StartDelay:    ptrutil.ToPtr(adcom1.StartPreRoll),
+			RqdDurs:       []int64{15},
+			BAttr:         []adcom1.CreativeAttribute{adcom1.AttrAudioAuto},
+			Delivery:      []adcom1.DeliveryMethod{adcom1.DeliveryStreaming},
+			CompanionAd:   []openrtb2.Banner{{ID: "anyCompanion"}},
+			API:           []adcom1.APIFramework{adcom1.APIVPAID10},
+			CompanionType: []adcom1.CompanionType{adcom1.CompanionStatic},
+			Stitched:      ptrutil.ToPtr[int8](1),
+			NVol:          ptrutil.ToPtr(adcom1.VolumeNormalizationMode(1)),
+			DurFloors:     []openrtb2.DurFloors{{MinDur: 1}},
+			Ext:           json.RawMessage(`{"anyField":1}`),
+		}
+		result := CloneAudio(given)
+		assert.Equal(t, given, result, "equality")
+		assert.NotSame(t, given, result, "pointer")
+		assert.NotSame(t, &given.MIMEs[0], &result.MIMEs[0], "mimes")
+		assert.NotSame(t, &given.Protocols[0], &result.Protocols[0], "protocols")
+		assert.NotSame(t, given.StartDelay, result.StartDelay, "startdelay")
+		assert.NotSame(t, &given.RqdDurs[0], &result.RqdDurs[0], "rqddurs")
+		assert.NotSame(t, &given.BAttr[0], &result.BAttr[0], "battr")
+		assert.NotSame(t, &given.Delivery[0], &result.Delivery[0], "delivery")
+		assert.NotSame(t, &given.CompanionAd[0], &result.CompanionAd[0], "companionad-item")
+		assert.NotSame(t, &given.API[0], &result.API[0], "api")
+		assert.NotSame(t, &given.CompanionType[0], &result.CompanionType[0], "companiontype")
+		assert.NotSame(t, given.Stitched, result.Stitched, "stitched")
+		assert.NotSame(t, given.NVol, result.NVol, "nvol")
+		assert.NotSame(t, &given.DurFloors[0], &result.DurFloors[0], "durfloors-item")
+		assert.NotSame(t, &given.Ext[0], &result.Ext[0], "ext")
+	})
+
+	t.Run("assumptions", func(t *testing.T) {
+		assert.ElementsMatch(t, discoverPointerFields(reflect.TypeOf(openrtb2.Audio{})),
+			[]string{
+				"MIMEs",
+				"Protocols",
+				"StartDelay",
+				"RqdDurs",
+				"BAttr",
+				"Delivery",
+				"CompanionAd",
+				"API",
+				"CompanionType",
+				"Stitched",
+				"NVol",
+				"DurFloors",
+				"Ext",
+			})
+	})
+}
+
+func TestCloneNative(t *testing.T) {
+	t.Run("nil", func(t *testing.T) {
+		result := CloneNative(nil)
+		assert.Nil(t, result)
+	})
+
+	t.Run("populated", func(t *testing.T) {
+		given := &openrtb2.Native{
+			Request: "anyRequest",
+			Ver:     "1.2",
+			API:     []adcom1.APIFramework{adcom1.APIMRAID10},
+			BAttr:   []adcom1.CreativeAttribute{adcom1.AttrAudioAuto},
+			Ext:     json.RawMessage(`{"anyField":1}`),
+		}
+		result := CloneNative(given)
+		assert.Equal(t, given, result, "equality")
+		assert.NotSame(t, given, result, "pointer")
+		assert.NotSame(t, &given.API[0], &result.API[0], "api")
+		assert.NotSame(t, &given.BAttr[0], &result.BAttr[0], "battr")
+		assert.NotSame(t, &given.Ext[0], &result.Ext[0], "ext")
+	})
 
-	// TODO: Implement a full bid request clone and track changes using an 'assumptions' test.
+	t.Run("assumptions", func(t *testing.T) {
+		assert.ElementsMatch(t, discoverPointerFields(reflect.TypeOf(openrtb2.Native{})),
+			[]string{
+				"API",
+				"BAttr",
+				"Ext",
+			})
+	})
+}
+
+func TestClonePMP(t *testing.T) {
+	t.Run("nil", func(t *testing.T) {
+		result := ClonePMP(nil)
+		assert.Nil(t, result)
+	})
+
+	t.Run("populated", func(t *testing.T) {
+		given := &openrtb2.PMP{
+			PrivateAuction: 1,
+			Deals: []openrtb2.Deal{{
+				ID:        "anyDeal",
+				WSeat:     []string{"seat1"},
+				WADomain:  []string{"domain1"},
+				DurFloors: []openrtb2.DurFloors{{MinDur: 1}},
+				Ext:       json.RawMessage(`{"deal":1}`),
+			}},
+			Ext: json.RawMessage(`{"anyField":1}`),
+		}
+		result := ClonePMP(given)
+		assert.Equal(t, given, result, "equality")
+		assert.NotSame(t, given, result, "pointer")
+		assert.NotSame(t, &given.Deals[0], &result.Deals[0], "deals-item")
+		assert.NotSame(t, &given.Deals[0].WSeat[0], &result.Deals[0].WSeat[0], "deals-item-wseat")
+		assert.NotSame(t, &given.Deals[0].WADomain[0], &result.Deals[0].WADomain[0], "deals-item-wadomain")
+		assert.NotSame(t, &given.Deals[0].DurFloors[0], &result.Deals[0].DurFloors[0], "deals-item-durfloors")
+		assert.NotSame(t, &given.Deals[0].Ext[0], &result.Deals[0].Ext[0], "deals-item-ext")
+		assert.NotSame(t, &given.Ext[0], &result.Ext[0], "ext")
+	})
+
+	t.Run("assumptions", func(t *testing.T) {
+		assert.ElementsMatch(t, discoverPointerFields(reflect.TypeOf(openrtb2.PMP{})),
+			[]string{
+				"Deals",
+				"Ext",
+			})
+		assert.ElementsMatch(t, discoverPointerFields(reflect.TypeOf(openrtb2.Deal{})),
+			[]string{
+				"WSeat",
+				"WADomain",
+				"DurFloors",
+				"Ext",
+			})
+	})
+}
+
+func TestCloneRefresh(t *testing.T) {
+	t.Run("nil", func(t *testing.T) {
+		result := CloneRefresh(nil)
+		assert.Nil(t, result)
+	})
+
+	t.Run("assumptions", func(t *testing.T) {
+		assert.ElementsMatch(t, discoverPointerFields(reflect.TypeOf(openrtb2.Refresh{})),
+			[]string{
+				"RefSettings",
+				"Count",
+				"Ext",
+			})
+		assert.ElementsMatch(t, discoverPointerFields(reflect.TypeOf(openrtb2.RefSettings{})),
+			[]string{
+				"Ext",
+			})
+	})
+}
+
+func TestCloneRegs(t *testing.T) {
+	t.Run("nil", func(t *testing.T) {
+		result := CloneRegs(nil)
+		assert.Nil(t, result)
+	})
+
+	t.Run("populated", func(t *testing.T) {
+		given := &openrtb2.Regs{
+			COPPA:     1,
+			GDPR:      ptrutil.ToPtr[int8](1),
+			USPrivacy: "1YNN",
+			GPP:       "anyGPP",
+			GPPSID:    []int8{2},
+			Ext:       json.RawMessage(`{"anyField":1}`),
+		}
+		result := CloneRegs(given)
+		assert.Equal(t, given, result, "equality")
+		assert.NotSame(t, given, result, "pointer")
+		assert.NotSame(t, given.GDPR, result.GDPR, "gdpr")
+		assert.NotSame(t, &given.GPPSID[0], &result.GPPSID[0], "gppsid")
+		assert.NotSame(t, &given.Ext[0], &result.Ext[0], "ext")
+	})
+
+	t.Run("assumptions", func(t *testing.T) {
+		assert.ElementsMatch(t, discoverPointerFields(reflect.TypeOf(openrtb2.Regs{})),
+			[]string{
+				"GDPR",
+				"GPPSID",
+				"Ext",
+			})
+	})
+}
+
+func TestCloneLeafSlices(t *testing.T) {
+	// Types which only hold an ext, so cloning the ext is sufficient.
+	for _, typ := range []reflect.Type{
+		reflect.TypeOf(openrtb2.Metric{}),
+		reflect.TypeOf(openrtb2.Format{}),
+		reflect.TypeOf(openrtb2.DurFloors{}),
+		reflect.TypeOf(openrtb2.Qty{}),
+	} {
+		assert.ElementsMatch(t, discoverPointerFields(typ), []string{"Ext"}, typ.Name())
+	}
 }
 
 // discoverPointerFields returns the names of all fields of an object that are
@@ -8,9 +8,9 @@ import (
 	"net/url"
 	"strings"
 
-	"github.com/golang/glog"
 	"github.com/julienschmidt/httprouter"
 	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/logger"
 	"github.com/prebid/prebid-server/v2/server/ssl"
 	"github.com/prebid/prebid-server/v2/usersync"
 )
@@ -69,8 +69,8 @@ func (deps *UserSyncDeps) OptOut(w http.ResponseWriter, r *http.Request, _ httpr
 
 	err := deps.VerifyRecaptcha(rr)
 	if err != nil {
-		if glog.V(2) {
-			glog.Infof("Opt Out failed recaptcha: %v", err)
+		if logger.DebugEnabled() {
+			logger.Ctx(r.Context()).Infof("Opt Out failed recaptcha: %v", err)
 		}
 		w.WriteHeader(http.StatusUnauthorized)
 		return
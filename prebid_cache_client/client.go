@@ -13,10 +13,10 @@ import (
 	"time"
 
 	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/logger"
 	"github.com/prebid/prebid-server/v2/metrics"
 
 	"github.com/buger/jsonparser"
-	"github.com/golang/glog"
 	"golang.org/x/net/context/ctxhttp"
 )
 
@@ -94,13 +94,13 @@ func (c *clientImpl) PutJson(ctx context.Context, values []Cacheable) (uuids []s
 
 	postBody, err := encodeValues(values)
 	if err != nil {
-		logError(&errs, "Error creating JSON for prebid cache: %v", err)
+		logError(ctx, &errs, "Error creating JSON for prebid cache: %v", err)
 		return uuidsToReturn, errs
 	}
 
 	httpReq, err := http.NewRequest("POST", c.putUrl, bytes.NewReader(postBody))
 	if err != nil {
-		logError(&errs, "Error creating POST request to prebid cache: %v", err)
+		logError(ctx, &errs, "Error creating POST request to prebid cache: %v", err)
 		return uuidsToReturn, errs
 	}
 
@@ -112,7 +112,7 @@ func (c *clientImpl) PutJson(ctx context.Context, values []Cacheable) (uuids []s
 	elapsedTime := time.Since(startTime)
 	if err != nil {
 		c.metrics.RecordPrebidCacheRequestTime(false, elapsedTime)
-		logError(&errs, "Error sending the request to Prebid Cache: %v; Duration=%v, Items=%v, Payload Size=%v", err, elapsedTime, len(values), len(postBody))
+		logError(ctx, &errs, "Error sending the request to Prebid Cache: %v; Duration=%v, Items=%v, Payload Size=%v", err, elapsedTime, len(values), len(postBody))
 		return uuidsToReturn, errs
 	}
 	defer anResp.Body.Close()
@@ -120,19 +120,19 @@ func (c *clientImpl) PutJson(ctx context.Context, values []Cacheable) (uuids []s
 
 	responseBody, err := io.ReadAll(anResp.Body)
 	if anResp.StatusCode != 200 {
-		logError(&errs, "Prebid Cache call to %s returned %d: %s", c.putUrl, anResp.StatusCode, responseBody)
+		logError(ctx, &errs, "Prebid Cache call to %s returned %d: %s", c.putUrl, anResp.StatusCode, responseBody)
 		return uuidsToReturn, errs
 	}
 
 	currentIndex := 0
 	processResponse := func(uuidObj []byte, _ jsonparser.ValueType, _ int, err error) {
 		if uuid, valueType, _, err := jsonparser.Get(uuidObj, "uuid"); err != nil {
-			logError(&errs, "Prebid Cache returned a bad value at index %d. Error was: %v. Response body was: %s", currentIndex, err, string(responseBody))
+			logError(ctx, &errs, "Prebid Cache returned a bad value at index %d. Error was: %v. Response body was: %s", currentIndex, err, string(responseBody))
 		} else if valueType != jsonparser.String {
-			logError(&errs, "Prebid Cache returned a %v at index %d in: %v", valueType, currentIndex, string(responseBody))
+			logError(ctx, &errs, "Prebid Cache returned a %v at index %d in: %v", valueType, currentIndex, string(responseBody))
 		} else {
 			if uuidsToReturn[currentIndex], err = jsonparser.ParseString(uuid); err != nil {
-				logError(&errs, "Prebid Cache response index %d could not be parsed as string: %v", currentIndex, err)
+				logError(ctx, &errs, "Prebid Cache response index %d could not be parsed as string: %v", currentIndex, err)
 				uuidsToReturn[currentIndex] = ""
 			}
 		}
@@ -140,16 +140,16 @@ func (c *clientImpl) PutJson(ctx context.Context, values []Cacheable) (uuids []s
 	}
 
 	if _, err := jsonparser.ArrayEach(responseBody, processResponse, "responses"); err != nil {
-		logError(&errs, "Error interpreting Prebid Cache response: %v\nResponse was: %s", err, string(responseBody))
+		logError(ctx, &errs, "Error interpreting Prebid Cache response: %v\nResponse was: %s", err, string(responseBody))
 		return uuidsToReturn, errs
 	}
 
 	return uuidsToReturn, errs
 }
 
-func logError(errs *[]error, format string, a ...interface{}) {
+func logError(ctx context.Context, errs *[]error, format string, a ...interface{}) {
 	msg := fmt.Sprintf(format, a...)
-	glog.Error(msg)
+	logger.Ctx(ctx).Errorf("%s", msg)
 	*errs = append(*errs, errors.New(msg))
 }
 
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"path/filepath"
+
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/errortypes"
+	"github.com/prebid/prebid-server/v2/exchange"
+	metricsConf "github.com/prebid/prebid-server/v2/metrics/config"
+	"github.com/prebid/prebid-server/v2/modules"
+	"github.com/prebid/prebid-server/v2/modules/moduledeps"
+	"github.com/prebid/prebid-server/v2/openrtb_ext"
+	storedRequestsConf "github.com/prebid/prebid-server/v2/stored_requests/config"
+)
+
+const paramsDirectory = "./static/bidder-params"
+
+// preflight loads and validates the complete server configuration without starting any listeners.
+// Every step is attempted even if an earlier one fails so that all problems are reported at once.
+// Steps which depend on a successfully loaded configuration are skipped if it could not be loaded.
+func preflight(infoDir, paramsDir string, load func(config.BidderInfos) (*config.Configuration, error)) []error {
+	var errs []error
+
+	bidderInfos, err := loadBidderInfos(infoDir)
+	if err != nil {
+		errs = append(errs, fmt.Errorf("bidder-info: %v", err))
+	}
+
+	cfg, err := load(bidderInfos)
+	if err != nil {
+		errs = append(errs, flattenErrors("config", err)...)
+	}
+
+	if _, err := openrtb_ext.NewBidderParamsValidator(paramsDir); err != nil {
+		errs = append(errs, fmt.Errorf("bidder-params: %v", err))
+	}
+
+	if cfg == nil {
+		return errs
+	}
+
+	if _, adapterErrs := exchange.BuildAdapters(&http.Client{}, cfg, cfg.BidderInfos, &metricsConf.NilMetricsEngine{}); len(adapterErrs) > 0 {
+		for _, e := range adapterErrs {
+			errs = append(errs, fmt.Errorf("adapters: %v", e))
+		}
+	}
+
+	if _, transportErrs := exchange.NewBidderTransportClient(&http.Client{}, cfg.BidderInfos); len(transportErrs) > 0 {
+		for _, e := range transportErrs {
+			errs = append(errs, fmt.Errorf("adapters: %v", e))
+		}
+	}
+
+	for _, e := range storedRequestsConf.CheckStoredRequests(cfg) {
+		errs = append(errs, fmt.Errorf("stored requests: %v", e))
+	}
+
+	moduleDeps := moduledeps.ModuleDeps{HTTPClient: &http.Client{}}
+	if _, _, err := modules.NewBuilder().Build(cfg.Hooks.Modules, moduleDeps); err != nil {
+		errs = append(errs, fmt.Errorf("modules: %v", err))
+	}
+
+	return errs
+}
+
+func loadBidderInfos(infoDir string) (config.BidderInfos, error) {
+	bidderInfoPath, err := filepath.Abs(infoDir)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to build configuration directory path: %v", err)
+	}
+	return config.LoadBidderInfoFromDisk(bidderInfoPath)
+}
+
+// flattenErrors unwraps aggregate errors so each validation failure is reported on its own.
+func flattenErrors(prefix string, err error) []error {
+	aggregate, ok := err.(errortypes.AggregateError)
+	if !ok {
+		return []error{fmt.Errorf("%s: %v", prefix, err)}
+	}
+	errs := make([]error, 0, len(aggregate.Errors))
+	for _, e := range aggregate.Errors {
+		errs = append(errs, fmt.Errorf("%s: %v", prefix, e))
+	}
+	return errs
+}
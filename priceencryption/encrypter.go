@@ -0,0 +1,102 @@
+// Package priceencryption encrypts the prices sent to ad servers in targeting, so that the clearing price of
+// a bid can be billed exactly without it being readable by anything on the page.
+//
+// Prices are encrypted with the scheme ad servers use to decrypt the price macros of their creatives (the
+// DoubleClick price encryption), so that an ad server holding the account's keys can decrypt them the same
+// way. A price, in micros of its currency, is XORed with the first 8 bytes of the HMAC-SHA1 of a random 16
+// byte initialization vector under the encryption key. The initialization vector, the encrypted price and
+// the first 4 bytes of the HMAC-SHA1 of the price and the initialization vector under the integrity key are
+// then base64url encoded without padding, into 38 characters.
+package priceencryption
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+
+	"github.com/prebid/prebid-server/v2/config"
+)
+
+const (
+	ivSize        = 16
+	priceSize     = 8
+	signatureSize = 4
+
+	microsPerUnit = 1e6
+)
+
+var encoding = base64.RawURLEncoding
+
+// Encrypter encrypts and decrypts prices with the keys of an account.
+type Encrypter struct {
+	encryptionKey []byte
+	integrityKey  []byte
+	random        io.Reader
+}
+
+// NewEncrypter returns an Encrypter with the account's keys.
+func NewEncrypter(cfg config.AccountPriceEncryption) (*Encrypter, error) {
+	encryptionKey, integrityKey, err := cfg.Keys()
+	if err != nil {
+		return nil, err
+	}
+	return &Encrypter{encryptionKey: encryptionKey, integrityKey: integrityKey, random: rand.Reader}, nil
+}
+
+// Encrypt returns the price encrypted, rounded to the nearest micro. Each call uses a new initialization
+// vector, so the same price doesn't encrypt to the same text twice.
+func (e *Encrypter) Encrypt(price float64) (string, error) {
+	iv := make([]byte, ivSize)
+	if _, err := io.ReadFull(e.random, iv); err != nil {
+		return "", err
+	}
+
+	plain := make([]byte, priceSize)
+	binary.BigEndian.PutUint64(plain, uint64(math.Round(price*microsPerUnit)))
+
+	encrypted := make([]byte, 0, ivSize+priceSize+signatureSize)
+	encrypted = append(encrypted, iv...)
+	pad := sign(e.encryptionKey, iv)
+	for i := range plain {
+		encrypted = append(encrypted, plain[i]^pad[i])
+	}
+	encrypted = append(encrypted, sign(e.integrityKey, plain, iv)[:signatureSize]...)
+	return encoding.EncodeToString(encrypted), nil
+}
+
+// Decrypt returns the price which was encrypted, once its signature has been checked.
+func (e *Encrypter) Decrypt(encrypted string) (float64, error) {
+	decoded, err := encoding.DecodeString(encrypted)
+	if err != nil {
+		return 0, errors.New("the encrypted price isn't base64url encoded")
+	}
+	if len(decoded) != ivSize+priceSize+signatureSize {
+		return 0, errors.New("the encrypted price has the wrong length")
+	}
+	iv := decoded[:ivSize]
+	encryptedPrice := decoded[ivSize : ivSize+priceSize]
+	signature := decoded[ivSize+priceSize:]
+
+	plain := make([]byte, priceSize)
+	pad := sign(e.encryptionKey, iv)
+	for i := range encryptedPrice {
+		plain[i] = encryptedPrice[i] ^ pad[i]
+	}
+	if !hmac.Equal(signature, sign(e.integrityKey, plain, iv)[:signatureSize]) {
+		return 0, errors.New("the encrypted price's signature doesn't match the integrity key")
+	}
+	return float64(binary.BigEndian.Uint64(plain)) / microsPerUnit, nil
+}
+
+func sign(key []byte, data ...[]byte) []byte {
+	mac := hmac.New(sha1.New, key)
+	for _, d := range data {
+		mac.Write(d)
+	}
+	return mac.Sum(nil)
+}
@@ -0,0 +1,147 @@
+package priceencryption
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testKeys are the keys of the examples of the DoubleClick price encryption documentation.
+var testKeys = config.AccountPriceEncryption{
+	Enabled:       true,
+	EncryptionKey: "skU7Ax_NL5pPAFyKdkfZjZz2-VhIN8bjj1rVFOaJ_5o=",
+	IntegrityKey:  "arO23ykdNqUQ5LEoQ0FVmPkBd7xB5CO89PDZlSjpFxo=",
+}
+
+func TestNewEncrypter(t *testing.T) {
+	_, err := NewEncrypter(config.AccountPriceEncryption{Enabled: true, EncryptionKey: "not a key", IntegrityKey: testKeys.IntegrityKey})
+	assert.EqualError(t, err, "account_defaults.price_encryption.encryption_key must be a base64 encoded key of at least 16 bytes")
+
+	e, err := NewEncrypter(testKeys)
+	require.NoError(t, err)
+	assert.Len(t, e.encryptionKey, 32)
+	assert.Len(t, e.integrityKey, 32)
+}
+
+func TestEncrypt(t *testing.T) {
+	tests := []struct {
+		description string
+		price       float64
+		expected    string
+	}{
+		{
+			description: "documented-example",
+			price:       0.0001,
+			expected:    "YWJjMTIzZGVmNDU2Z2hpN7fhCuPemCce_6msaw",
+		},
+		{
+			description: "other-documented-example",
+			price:       0.0027,
+			expected:    "YWJjMTIzZGVmNDU2Z2hpN7fhCuPemC32prpWWw",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			e, err := NewEncrypter(testKeys)
+			require.NoError(t, err)
+			e.random = bytes.NewReader([]byte("abc123def456ghi7"))
+
+			encrypted, err := e.Encrypt(test.price)
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, encrypted)
+		})
+	}
+}
+
+func TestEncryptRandomIV(t *testing.T) {
+	e, err := NewEncrypter(testKeys)
+	require.NoError(t, err)
+
+	first, err := e.Encrypt(1.25)
+	require.NoError(t, err)
+	second, err := e.Encrypt(1.25)
+	require.NoError(t, err)
+
+	assert.Len(t, first, 38)
+	assert.NotEqual(t, first, second)
+	for _, encrypted := range []string{first, second} {
+		price, err := e.Decrypt(encrypted)
+		assert.NoError(t, err)
+		assert.Equal(t, 1.25, price)
+	}
+}
+
+func TestEncryptRoundsToMicros(t *testing.T) {
+	e, err := NewEncrypter(testKeys)
+	require.NoError(t, err)
+
+	encrypted, err := e.Encrypt(2.1234567)
+	require.NoError(t, err)
+	price, err := e.Decrypt(encrypted)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2.123457, price)
+}
+
+func TestDecrypt(t *testing.T) {
+	otherKeys := testKeys
+	otherKeys.IntegrityKey = "c2Vjb25kLWludGVncml0eS1rZXktZm9yLXRlc3Rz"
+
+	tests := []struct {
+		description   string
+		keys          config.AccountPriceEncryption
+		encrypted     string
+		expectedPrice float64
+		expectedError string
+	}{
+		{
+			description:   "valid",
+			keys:          testKeys,
+			encrypted:     "YWJjMTIzZGVmNDU2Z2hpN7fhCuPemCce_6msaw",
+			expectedPrice: 0.0001,
+		},
+		{
+			description:   "other-integrity-key",
+			keys:          otherKeys,
+			encrypted:     "YWJjMTIzZGVmNDU2Z2hpN7fhCuPemCce_6msaw",
+			expectedError: "the encrypted price's signature doesn't match the integrity key",
+		},
+		{
+			description:   "tampered",
+			keys:          testKeys,
+			encrypted:     "YWJjMTIzZGVmNDU2Z2hpN7fhCuPemCdd_6msaw",
+			expectedError: "the encrypted price's signature doesn't match the integrity key",
+		},
+		{
+			description:   "too-short",
+			keys:          testKeys,
+			encrypted:     "YWJjMTIzZGVmNDU2Z2hpN7fhCuPemC",
+			expectedError: "the encrypted price has the wrong length",
+		},
+		{
+			description:   "not-base64url",
+			keys:          testKeys,
+			encrypted:     "YWJjMTIzZGVmNDU2Z2hpN7fhCuPemCce/6msaw==",
+			expectedError: "the encrypted price isn't base64url encoded",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			e, err := NewEncrypter(test.keys)
+			require.NoError(t, err)
+
+			price, err := e.Decrypt(test.encrypted)
+
+			if test.expectedError != "" {
+				assert.EqualError(t, err, test.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, test.expectedPrice, price)
+		})
+	}
+}
@@ -0,0 +1,207 @@
+// Package piiscan finds personal data in the HTTP requests sent to bidders which the privacy policies should have
+// kept out: email addresses, latitudes and longitudes more precise than the bidder is allowed, IDs of the user and
+// device which were removed from the bidder's request, and patterns the host configures. Adapters and modules can
+// copy data from places the privacy policies don't clean, so the requests are scanned after they're built.
+package piiscan
+
+import (
+	"bytes"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/metrics"
+)
+
+// allowedGeoDecimals is the number of decimal places the privacy policies round latitudes and longitudes to
+const allowedGeoDecimals = 2
+
+// minIDLength is the length of the shortest ID looked for. Shorter IDs would match unrelated data.
+const minIDLength = 6
+
+var (
+	emailPattern = regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9-]+(\.[A-Za-z0-9-]+)*\.[A-Za-z]{2,}`)
+	geoPattern   = regexp.MustCompile(`(?i)\b(lat|lon|lng|latitude|longitude)"?\s*[:=]\s*"?-?\d{1,3}\.(\d+)`)
+)
+
+// Scanner scans requests for the personal data the host configured it to find.
+type Scanner struct {
+	enforce    bool
+	sampleRate float64
+	emails     bool
+	preciseGeo bool
+	userIDs    bool
+	patterns   []pattern
+}
+
+type pattern struct {
+	name   string
+	regexp *regexp.Regexp
+}
+
+// NewScanner returns a scanner for the config, or nil if scanning is off. The patterns must have been validated.
+func NewScanner(cfg config.PIIScanner) *Scanner {
+	if cfg.Mode != config.PIIScannerModeReport && cfg.Mode != config.PIIScannerModeEnforce {
+		return nil
+	}
+	s := &Scanner{
+		enforce:    cfg.Mode == config.PIIScannerModeEnforce,
+		sampleRate: cfg.SampleRate,
+		emails:     cfg.Emails,
+		preciseGeo: cfg.PreciseGeo,
+		userIDs:    cfg.UserIDs,
+	}
+	for name, expr := range cfg.Patterns {
+		s.patterns = append(s.patterns, pattern{name: name, regexp: regexp.MustCompile(expr)})
+	}
+	sort.Slice(s.patterns, func(i, j int) bool { return s.patterns[i].name < s.patterns[j].name })
+	return s
+}
+
+// Sample decides whether a bidder request is scanned. Every request is scanned when the scanner enforces, and
+// none when it's nil.
+func (s *Scanner) Sample(random func() float64) bool {
+	if s == nil {
+		return false
+	}
+	return s.enforce || random() < s.sampleRate
+}
+
+// NewPolicy returns what the requests sent to a bidder mustn't have, by comparing the bidder's request with the
+// auction's request it was cleaned from.
+func (s *Scanner) NewPolicy(original, bidderRequest *openrtb2.BidRequest) *Policy {
+	policy := &Policy{scanner: s}
+	if s.userIDs {
+		sent := make(map[string]struct{})
+		for _, id := range requestIDs(bidderRequest) {
+			sent[id] = struct{}{}
+		}
+		for _, id := range requestIDs(original) {
+			if _, ok := sent[id]; !ok && len(id) >= minIDLength && strings.Trim(id, "0-") != "" {
+				policy.withheldIDs = append(policy.withheldIDs, id)
+			}
+		}
+	}
+	if s.preciseGeo {
+		policy.coarseGeo = geoCoarsened(deviceGeo(original), deviceGeo(bidderRequest)) || geoCoarsened(userGeo(original), userGeo(bidderRequest))
+	}
+	return policy
+}
+
+// Policy is what the requests sent to a bidder mustn't have.
+type Policy struct {
+	scanner     *Scanner
+	withheldIDs []string
+	coarseGeo   bool
+}
+
+// Enforce is true if the requests with personal data mustn't be sent.
+func (p *Policy) Enforce() bool {
+	return p.scanner.enforce
+}
+
+// Violation is a kind of personal data found in a request.
+type Violation struct {
+	Type metrics.PIIViolationType
+	// Pattern is the name of the pattern matched, for pattern violations
+	Pattern string
+}
+
+func (v Violation) String() string {
+	if v.Pattern != "" {
+		return string(v.Type) + " " + v.Pattern
+	}
+	return string(v.Type)
+}
+
+// Scan returns the kinds of personal data found in any of the data, once each.
+func (p *Policy) Scan(data ...[]byte) []Violation {
+	var violations []Violation
+	found := func(violation Violation, match func([]byte) bool) {
+		for _, d := range data {
+			if match(d) {
+				violations = append(violations, violation)
+				return
+			}
+		}
+	}
+
+	if p.scanner.emails {
+		found(Violation{Type: metrics.PIIViolationEmail}, emailPattern.Match)
+	}
+	if p.coarseGeo {
+		found(Violation{Type: metrics.PIIViolationPreciseGeo}, hasPreciseGeo)
+	}
+	if len(p.withheldIDs) > 0 {
+		found(Violation{Type: metrics.PIIViolationUserID}, func(d []byte) bool {
+			for _, id := range p.withheldIDs {
+				if bytes.Contains(d, []byte(id)) {
+					return true
+				}
+			}
+			return false
+		})
+	}
+	for _, pattern := range p.scanner.patterns {
+		found(Violation{Type: metrics.PIIViolationPattern, Pattern: pattern.name}, pattern.regexp.Match)
+	}
+	return violations
+}
+
+func hasPreciseGeo(data []byte) bool {
+	for _, match := range geoPattern.FindAllSubmatch(data, -1) {
+		if len(match[2]) > allowedGeoDecimals {
+			return true
+		}
+	}
+	return false
+}
+
+// requestIDs returns the IDs of the user and device in the request.
+func requestIDs(request *openrtb2.BidRequest) []string {
+	var ids []string
+	if user := request.User; user != nil {
+		ids = append(ids, user.ID, user.BuyerUID)
+		for _, eid := range user.EIDs {
+			for _, uid := range eid.UIDs {
+				ids = append(ids, uid.ID)
+			}
+		}
+	}
+	if device := request.Device; device != nil {
+		ids = append(ids, device.IFA, device.DIDSHA1, device.DIDMD5, device.DPIDSHA1, device.DPIDMD5, device.MACSHA1, device.MACMD5)
+	}
+	return ids
+}
+
+func deviceGeo(request *openrtb2.BidRequest) *openrtb2.Geo {
+	if request.Device == nil {
+		return nil
+	}
+	return request.Device.Geo
+}
+
+func userGeo(request *openrtb2.BidRequest) *openrtb2.Geo {
+	if request.User == nil {
+		return nil
+	}
+	return request.User.Geo
+}
+
+// geoCoarsened is true if the bidder's geo has a latitude or longitude which is less precise than the original's,
+// or has been removed.
+func geoCoarsened(original, sent *openrtb2.Geo) bool {
+	if original == nil {
+		return false
+	}
+	if sent == nil {
+		return original.Lat != nil || original.Lon != nil
+	}
+	return coordinateCoarsened(original.Lat, sent.Lat) || coordinateCoarsened(original.Lon, sent.Lon)
+}
+
+func coordinateCoarsened(original, sent *float64) bool {
+	return original != nil && (sent == nil || *sent != *original)
+}
@@ -0,0 +1,146 @@
+package piiscan
+
+import (
+	"testing"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/metrics"
+	"github.com/prebid/prebid-server/v2/util/ptrutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewScanner(t *testing.T) {
+	assert.Nil(t, NewScanner(config.PIIScanner{Mode: config.PIIScannerModeOff}))
+	assert.Nil(t, NewScanner(config.PIIScanner{}))
+
+	scanner := NewScanner(config.PIIScanner{Mode: config.PIIScannerModeReport, Patterns: map[string]string{"ssn": `\d{3}-\d{2}-\d{4}`, "phone": `\+1\d{10}`}})
+	if assert.Len(t, scanner.patterns, 2) {
+		assert.Equal(t, "phone", scanner.patterns[0].name)
+		assert.Equal(t, "ssn", scanner.patterns[1].name)
+	}
+}
+
+func TestScannerSample(t *testing.T) {
+	random := func() float64 { return 0.5 }
+
+	var scanner *Scanner
+	assert.False(t, scanner.Sample(random), "nil")
+	assert.True(t, NewScanner(config.PIIScanner{Mode: config.PIIScannerModeReport, SampleRate: 0.6}).Sample(random))
+	assert.False(t, NewScanner(config.PIIScanner{Mode: config.PIIScannerModeReport, SampleRate: 0.4}).Sample(random))
+	assert.True(t, NewScanner(config.PIIScanner{Mode: config.PIIScannerModeEnforce, SampleRate: 0}).Sample(random))
+}
+
+func TestNewPolicy(t *testing.T) {
+	original := &openrtb2.BidRequest{
+		User: &openrtb2.User{
+			ID:   "user-12345",
+			EIDs: []openrtb2.EID{{Source: "example.com", UIDs: []openrtb2.UID{{ID: "eid-12345"}}}},
+			Geo:  &openrtb2.Geo{Country: "USA"},
+		},
+		Device: &openrtb2.Device{
+			IFA: "00000000-0000-0000-0000-000000000000",
+			Geo: &openrtb2.Geo{Lat: ptrutil.ToPtr(51.50735), Lon: ptrutil.ToPtr(-0.12776)},
+		},
+	}
+
+	testCases := []struct {
+		description         string
+		bidderRequest       *openrtb2.BidRequest
+		expectedWithheldIDs []string
+		expectedCoarseGeo   bool
+	}{
+		{
+			description:   "unchanged",
+			bidderRequest: original,
+		},
+		{
+			description: "scrubbed",
+			bidderRequest: &openrtb2.BidRequest{
+				User:   &openrtb2.User{Geo: &openrtb2.Geo{Country: "USA"}},
+				Device: &openrtb2.Device{Geo: &openrtb2.Geo{Lat: ptrutil.ToPtr(51.51), Lon: ptrutil.ToPtr(-0.13)}},
+			},
+			expectedWithheldIDs: []string{"user-12345", "eid-12345"},
+			expectedCoarseGeo:   true,
+		},
+		{
+			description: "eids and geo removed",
+			bidderRequest: &openrtb2.BidRequest{
+				User: &openrtb2.User{ID: "user-12345"},
+			},
+			expectedWithheldIDs: []string{"eid-12345"},
+			expectedCoarseGeo:   true,
+		},
+	}
+
+	scanner := NewScanner(config.PIIScanner{Mode: config.PIIScannerModeReport, PreciseGeo: true, UserIDs: true})
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			policy := scanner.NewPolicy(original, test.bidderRequest)
+			assert.Equal(t, test.expectedWithheldIDs, policy.withheldIDs)
+			assert.Equal(t, test.expectedCoarseGeo, policy.coarseGeo)
+		})
+	}
+}
+
+func TestPolicyScan(t *testing.T) {
+	scanner := NewScanner(config.PIIScanner{
+		Mode:       config.PIIScannerModeReport,
+		Emails:     true,
+		PreciseGeo: true,
+		UserIDs:    true,
+		Patterns:   map[string]string{"phone": `\+1\d{10}`},
+	})
+	policy := &Policy{scanner: scanner, withheldIDs: []string{"user-12345"}, coarseGeo: true}
+
+	testCases := []struct {
+		description string
+		data        [][]byte
+		expected    []Violation
+	}{
+		{
+			description: "clean",
+			data:        [][]byte{[]byte(`{"device":{"geo":{"lat":51.51,"lon":-0.13}},"user":{"id":"other"}}`)},
+		},
+		{
+			description: "email",
+			data:        [][]byte{[]byte(`https://bidder.com/bid?e=someone%40example.com`), []byte(`{"site":{"keywords":"someone@example.com"}}`)},
+			expected:    []Violation{{Type: metrics.PIIViolationEmail}},
+		},
+		{
+			description: "precise geo",
+			data:        [][]byte{[]byte(`{"device":{"geo":{"lat":51.50735,"lon":-0.13}}}`)},
+			expected:    []Violation{{Type: metrics.PIIViolationPreciseGeo}},
+		},
+		{
+			description: "precise geo in a query string",
+			data:        [][]byte{[]byte(`https://bidder.com/bid?latitude=51.50735`)},
+			expected:    []Violation{{Type: metrics.PIIViolationPreciseGeo}},
+		},
+		{
+			description: "withheld id and pattern",
+			data:        [][]byte{[]byte(`{"ext":{"uid":"user-12345","phone":"+12025550123"}}`)},
+			expected:    []Violation{{Type: metrics.PIIViolationUserID}, {Type: metrics.PIIViolationPattern, Pattern: "phone"}},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			assert.Equal(t, test.expected, policy.Scan(test.data...))
+		})
+	}
+}
+
+func TestPolicyScanDisabledChecks(t *testing.T) {
+	scanner := NewScanner(config.PIIScanner{Mode: config.PIIScannerModeEnforce})
+	original := &openrtb2.BidRequest{User: &openrtb2.User{ID: "user-12345"}, Device: &openrtb2.Device{Geo: &openrtb2.Geo{Lat: ptrutil.ToPtr(51.50735)}}}
+	policy := scanner.NewPolicy(original, &openrtb2.BidRequest{})
+
+	assert.True(t, policy.Enforce())
+	assert.Empty(t, policy.Scan([]byte(`{"email":"someone@example.com","id":"user-12345","lat":51.50735}`)))
+}
+
+func TestViolationString(t *testing.T) {
+	assert.Equal(t, "email", Violation{Type: metrics.PIIViolationEmail}.String())
+	assert.Equal(t, "pattern phone", Violation{Type: metrics.PIIViolationPattern, Pattern: "phone"}.String())
+}
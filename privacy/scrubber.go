@@ -10,6 +10,7 @@ import (
 	"github.com/prebid/prebid-server/v2/config"
 	"github.com/prebid/prebid-server/v2/openrtb_ext"
 	"github.com/prebid/prebid-server/v2/util/iputil"
+	"github.com/prebid/prebid-server/v2/util/ptrutil"
 )
 
 type IPConf struct {
@@ -17,8 +18,13 @@ type IPConf struct {
 	IPV4 config.IPv4
 }
 
+// The scrubbers are copy-on-write: the device, user and source objects may be shared with other
+// bidder requests, so they are shallow copied before any of their fields are changed. Nested objects
+// are replaced rather than modified.
+
 func scrubDeviceIDs(reqWrapper *openrtb_ext.RequestWrapper) {
 	if reqWrapper.Device != nil {
+		reqWrapper.Device = ptrutil.Clone(reqWrapper.Device)
 		reqWrapper.Device.DIDMD5 = ""
 		reqWrapper.Device.DIDSHA1 = ""
 		reqWrapper.Device.DPIDMD5 = ""
@@ -31,6 +37,7 @@ func scrubDeviceIDs(reqWrapper *openrtb_ext.RequestWrapper) {
 
 func scrubUserIDs(reqWrapper *openrtb_ext.RequestWrapper) {
 	if reqWrapper.User != nil {
+		reqWrapper.User = ptrutil.Clone(reqWrapper.User)
 		reqWrapper.User.Data = nil
 		reqWrapper.User.ID = ""
 		reqWrapper.User.BuyerUID = ""
@@ -43,6 +50,7 @@ func scrubUserIDs(reqWrapper *openrtb_ext.RequestWrapper) {
 
 func scrubUserDemographics(reqWrapper *openrtb_ext.RequestWrapper) {
 	if reqWrapper.User != nil {
+		reqWrapper.User = ptrutil.Clone(reqWrapper.User)
 		reqWrapper.User.BuyerUID = ""
 		reqWrapper.User.ID = ""
 		reqWrapper.User.Yob = 0
@@ -59,6 +67,8 @@ func scrubUserExt(reqWrapper *openrtb_ext.RequestWrapper, fieldName string) erro
 		ext := userExt.GetExt()
 		_, hasField := ext[fieldName]
 		if hasField {
+			// the rebuilt ext is written to the user object, so it must not be shared
+			reqWrapper.User = ptrutil.Clone(reqWrapper.User)
 			delete(ext, fieldName)
 			userExt.SetExt(ext)
 		}
@@ -66,16 +76,22 @@ func scrubUserExt(reqWrapper *openrtb_ext.RequestWrapper, fieldName string) erro
 	return nil
 }
 
-func ScrubEIDs(reqWrapper *openrtb_ext.RequestWrapper) error {
-	//transmitEids removes user.eids and user.ext.eids
+func scrubUserEIDs(reqWrapper *openrtb_ext.RequestWrapper) {
 	if reqWrapper.User != nil {
+		reqWrapper.User = ptrutil.Clone(reqWrapper.User)
 		reqWrapper.User.EIDs = nil
 	}
+}
+
+func ScrubEIDs(reqWrapper *openrtb_ext.RequestWrapper) error {
+	//transmitEids removes user.eids and user.ext.eids
+	scrubUserEIDs(reqWrapper)
 	return scrubUserExt(reqWrapper, "eids")
 }
 
 func ScrubTID(reqWrapper *openrtb_ext.RequestWrapper) {
 	if reqWrapper.Source != nil {
+		reqWrapper.Source = ptrutil.Clone(reqWrapper.Source)
 		reqWrapper.Source.TID = ""
 	}
 	impWrapper := reqWrapper.GetImp()
@@ -90,19 +106,23 @@ func scrubGEO(reqWrapper *openrtb_ext.RequestWrapper) {
 	//round user's geographic location by rounding off IP address and lat/lng data.
 	//this applies to both device.geo and user.geo
 	if reqWrapper.User != nil && reqWrapper.User.Geo != nil {
+		reqWrapper.User = ptrutil.Clone(reqWrapper.User)
 		reqWrapper.User.Geo = scrubGeoPrecision(reqWrapper.User.Geo)
 	}
 
 	if reqWrapper.Device != nil && reqWrapper.Device.Geo != nil {
+		reqWrapper.Device = ptrutil.Clone(reqWrapper.Device)
 		reqWrapper.Device.Geo = scrubGeoPrecision(reqWrapper.Device.Geo)
 	}
 }
 
 func scrubGeoFull(reqWrapper *openrtb_ext.RequestWrapper) {
 	if reqWrapper.User != nil && reqWrapper.User.Geo != nil {
+		reqWrapper.User = ptrutil.Clone(reqWrapper.User)
 		reqWrapper.User.Geo = &openrtb2.Geo{}
 	}
 	if reqWrapper.Device != nil && reqWrapper.Device.Geo != nil {
+		reqWrapper.Device = ptrutil.Clone(reqWrapper.Device)
 		reqWrapper.Device.Geo = &openrtb2.Geo{}
 	}
 
@@ -110,6 +130,7 @@ func scrubGeoFull(reqWrapper *openrtb_ext.RequestWrapper) {
 
 func scrubDeviceIP(reqWrapper *openrtb_ext.RequestWrapper, ipConf IPConf) {
 	if reqWrapper.Device != nil {
+		reqWrapper.Device = ptrutil.Clone(reqWrapper.Device)
 		reqWrapper.Device.IP = scrubIP(reqWrapper.Device.IP, ipConf.IPV4.AnonKeepBits, iputil.IPv4BitSize)
 		reqWrapper.Device.IPv6 = scrubIP(reqWrapper.Device.IPv6, ipConf.IPV6.AnonKeepBits, iputil.IPv6BitSize)
 	}
@@ -132,7 +153,7 @@ func ScrubUserFPD(reqWrapper *openrtb_ext.RequestWrapper) {
 	scrubDeviceIDs(reqWrapper)
 	scrubUserIDs(reqWrapper)
 	scrubUserExt(reqWrapper, "data")
-	reqWrapper.User.EIDs = nil
+	scrubUserEIDs(reqWrapper)
 }
 
 func ScrubGdprID(reqWrapper *openrtb_ext.RequestWrapper) {
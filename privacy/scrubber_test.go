@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v2/config"
 	"github.com/prebid/prebid-server/v2/openrtb_ext"
 	"github.com/prebid/prebid-server/v2/util/ptrutil"
 	"github.com/stretchr/testify/assert"
@@ -499,3 +500,61 @@ func TestScrubUserExtIDs(t *testing.T) {
 		assert.Equal(t, test.expected, result, test.description)
 	}
 }
+
+func TestScrubbersCopyOnWrite(t *testing.T) {
+	lat, lon := 123.456, 678.89
+	newRequest := func() *openrtb2.BidRequest {
+		return &openrtb2.BidRequest{
+			Device: &openrtb2.Device{IFA: "IFA", IP: "1.2.3.4", Geo: &openrtb2.Geo{Lat: &lat, Lon: &lon}},
+			User:   &openrtb2.User{ID: "ID", BuyerUID: "bID", EIDs: []openrtb2.EID{{Source: "src"}}, Geo: &openrtb2.Geo{Lat: &lat, Lon: &lon}, Ext: json.RawMessage(`{"data":1,"eids":[{"source":"src"}]}`)},
+			Source: &openrtb2.Source{TID: "TID"},
+		}
+	}
+	ipConf := IPConf{IPV6: config.IPv6{AnonKeepBits: 32}, IPV4: config.IPv4{AnonKeepBits: 16}}
+
+	testCases := []struct {
+		name  string
+		scrub func(*openrtb_ext.RequestWrapper)
+	}{
+		{
+			name:  "ScrubDeviceIDsIPsUserDemoExt",
+			scrub: func(rw *openrtb_ext.RequestWrapper) { ScrubDeviceIDsIPsUserDemoExt(rw, ipConf, "eids", false) },
+		},
+		{
+			name:  "ScrubDeviceIDsIPsUserDemoExt-full-geo",
+			scrub: func(rw *openrtb_ext.RequestWrapper) { ScrubDeviceIDsIPsUserDemoExt(rw, ipConf, "eids", true) },
+		},
+		{
+			name:  "ScrubUserFPD",
+			scrub: ScrubUserFPD,
+		},
+		{
+			name:  "ScrubGdprID",
+			scrub: ScrubGdprID,
+		},
+		{
+			name:  "ScrubGeoAndDeviceIP",
+			scrub: func(rw *openrtb_ext.RequestWrapper) { ScrubGeoAndDeviceIP(rw, ipConf) },
+		},
+		{
+			name:  "ScrubEIDs",
+			scrub: func(rw *openrtb_ext.RequestWrapper) { ScrubEIDs(rw) },
+		},
+		{
+			name:  "ScrubTID",
+			scrub: ScrubTID,
+		},
+	}
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			shared := newRequest()
+			reqCopy := *shared
+			brw := &openrtb_ext.RequestWrapper{BidRequest: &reqCopy}
+			test.scrub(brw)
+			brw.RebuildRequest()
+
+			assert.Equal(t, newRequest(), shared)
+			assert.NotEqual(t, newRequest(), brw.BidRequest)
+		})
+	}
+}
@@ -0,0 +1,65 @@
+// Package ratelimit keeps the counters behind the API key rate limits, bidder QPS caps and deal pacing.
+// The counters are kept in memory, which limits each instance on its own, or in Redis, which shares them
+// across every instance of the fleet.
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Counter counts events in fixed windows of time.
+type Counter interface {
+	// Add adds n to the count of the key in the current window, and returns the new count. Adding 0 reads
+	// the count.
+	Add(ctx context.Context, key string, n int64, window time.Duration) (int64, error)
+	// Close releases the resources held by the counter.
+	Close() error
+}
+
+// localCounter keeps the counts in memory, so they're only shared by the callers within this instance.
+type localCounter struct {
+	mutex  sync.Mutex
+	counts map[string]*localCount
+	now    func() time.Time
+}
+
+// localCount is the count of a key in the window it was last added to.
+type localCount struct {
+	window int64
+	count  int64
+}
+
+func newLocalCounter() *localCounter {
+	return &localCounter{
+		counts: make(map[string]*localCount),
+		now:    time.Now,
+	}
+}
+
+func (c *localCounter) Add(ctx context.Context, key string, n int64, window time.Duration) (int64, error) {
+	index := windowIndex(c.now(), window)
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	count, ok := c.counts[key]
+	if !ok {
+		count = &localCount{window: index}
+		c.counts[key] = count
+	} else if count.window != index {
+		count.window = index
+		count.count = 0
+	}
+	count.count += n
+	return count.count, nil
+}
+
+func (c *localCounter) Close() error {
+	return nil
+}
+
+// windowIndex numbers the windows since the epoch, so that every instance agrees on when a window starts.
+func windowIndex(now time.Time, window time.Duration) int64 {
+	return now.UnixNano() / int64(window)
+}
@@ -0,0 +1,36 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalCounter(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	counter := newLocalCounter()
+	counter.now = func() time.Time { return now }
+
+	count, err := counter.Add(context.Background(), "key", 1, time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+
+	count, _ = counter.Add(context.Background(), "key", 2, time.Minute)
+	assert.Equal(t, int64(3), count)
+
+	// adding nothing reads the count
+	count, _ = counter.Add(context.Background(), "key", 0, time.Minute)
+	assert.Equal(t, int64(3), count)
+
+	// other keys have their own counts
+	count, _ = counter.Add(context.Background(), "other", 1, time.Minute)
+	assert.Equal(t, int64(1), count)
+
+	// the count starts over in the next window
+	now = now.Add(time.Minute)
+	count, _ = counter.Add(context.Background(), "key", 1, time.Minute)
+	assert.Equal(t, int64(1), count)
+}
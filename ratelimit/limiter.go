@@ -0,0 +1,100 @@
+package ratelimit
+
+import (
+	"context"
+	"time"
+
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/logger"
+	"github.com/prebid/prebid-server/v2/metrics"
+)
+
+// Limiter enforces limits on how many times something happens in a window of time, with the counters of
+// the configured backend.
+//
+// Limits aren't enforced while the counters can't be reached. An outage of the Redis server shouldn't
+// take auctions down with it, and the limits are there to keep spend and traffic in check rather than to
+// be exact.
+//
+// A nil *Limiter is valid and never limits anything.
+type Limiter struct {
+	counter     Counter
+	distributed bool
+	me          metrics.MetricsEngine
+}
+
+// NewLimiter builds a Limiter with the counters of the configured backend. An error is returned if the
+// connections to Redis can't be set up, such as for missing TLS certificates.
+func NewLimiter(cfg config.RateLimiting, me metrics.MetricsEngine) (*Limiter, error) {
+	if cfg.Backend == config.RateLimitingBackendRedis {
+		counter, err := newRedisCounter(cfg.Redis)
+		if err != nil {
+			return nil, err
+		}
+		return &Limiter{counter: counter, distributed: true, me: me}, nil
+	}
+	return NewLocalLimiter(me), nil
+}
+
+// NewLocalLimiter builds a Limiter with counters kept in memory, for limits each instance enforces on its own.
+func NewLocalLimiter(me metrics.MetricsEngine) *Limiter {
+	return &Limiter{counter: newLocalCounter(), me: me}
+}
+
+// Distributed reports whether the limits are shared by every instance of the fleet, rather than each
+// instance enforcing them on its own.
+func (l *Limiter) Distributed() bool {
+	return l != nil && l.distributed
+}
+
+// Allow counts an event for the key, and reports whether it's within the most allowed in the window.
+func (l *Limiter) Allow(ctx context.Context, limit metrics.RateLimit, key string, max int64, window time.Duration) bool {
+	if l == nil {
+		return true
+	}
+	count, err := l.counter.Add(ctx, key, 1, window)
+	return l.check(ctx, limit, key, count <= max, err)
+}
+
+// Exhausted reports whether the key has had its most events in the window already, without counting one.
+func (l *Limiter) Exhausted(ctx context.Context, limit metrics.RateLimit, key string, max int64, window time.Duration) bool {
+	if l == nil {
+		return false
+	}
+	count, err := l.counter.Add(ctx, key, 0, window)
+	return !l.check(ctx, limit, key, count < max, err)
+}
+
+// Count counts n events for the key, which were allowed by an earlier check.
+func (l *Limiter) Count(ctx context.Context, limit metrics.RateLimit, key string, n int64, window time.Duration) {
+	if l == nil || n == 0 {
+		return
+	}
+	if _, err := l.counter.Add(ctx, key, n, window); err != nil {
+		logger.Ctx(ctx).Warningf("Failed to count %s events for %s: %v", limit, key, err)
+		l.me.RecordRateLimit(limit, metrics.RateLimitError)
+	}
+}
+
+// Close releases the connections to the counters.
+func (l *Limiter) Close() error {
+	if l == nil {
+		return nil
+	}
+	return l.counter.Close()
+}
+
+// check records the outcome of checking a limit, and reports whether the event is allowed.
+func (l *Limiter) check(ctx context.Context, limit metrics.RateLimit, key string, allowed bool, err error) bool {
+	if err != nil {
+		logger.Ctx(ctx).Warningf("Failed to check the %s limit of %s: %v", limit, key, err)
+		l.me.RecordRateLimit(limit, metrics.RateLimitError)
+		return true
+	}
+	if !allowed {
+		l.me.RecordRateLimit(limit, metrics.RateLimitLimited)
+		return false
+	}
+	l.me.RecordRateLimit(limit, metrics.RateLimitAllowed)
+	return true
+}
@@ -0,0 +1,82 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type failingCounter struct{}
+
+func (failingCounter) Add(ctx context.Context, key string, n int64, window time.Duration) (int64, error) {
+	return 0, errors.New("connection refused")
+}
+
+func (failingCounter) Close() error {
+	return nil
+}
+
+func TestNewLimiter(t *testing.T) {
+	local, err := NewLimiter(config.RateLimiting{Backend: config.RateLimitingBackendLocal}, &metrics.MetricsEngineMock{})
+	require.NoError(t, err)
+	assert.IsType(t, &localCounter{}, local.counter)
+	assert.False(t, local.Distributed())
+
+	redis, err := NewLimiter(config.RateLimiting{Backend: config.RateLimitingBackendRedis, Redis: config.RateLimitingRedis{RedisConnection: config.RedisConnection{Addrs: []string{"localhost:6379"}}, TimeoutMS: 20}}, &metrics.MetricsEngineMock{})
+	require.NoError(t, err)
+	assert.IsType(t, &redisCounter{}, redis.counter)
+	assert.True(t, redis.Distributed())
+
+	_, err = NewLimiter(config.RateLimiting{Backend: config.RateLimitingBackendRedis, Redis: config.RateLimitingRedis{RedisConnection: config.RedisConnection{Addrs: []string{"localhost:6379"}, TLS: config.RedisTLS{Enabled: true, RootCert: "does-not-exist.pem"}}, TimeoutMS: 20}}, &metrics.MetricsEngineMock{})
+	assert.Error(t, err)
+}
+
+func TestLimiterAllow(t *testing.T) {
+	me := &metrics.MetricsEngineMock{}
+	me.On("RecordRateLimit", metrics.RateLimitBidderQPS, metrics.RateLimitAllowed).Return().Times(2)
+	me.On("RecordRateLimit", metrics.RateLimitBidderQPS, metrics.RateLimitLimited).Return().Once()
+	limiter := NewLocalLimiter(me)
+
+	assert.True(t, limiter.Allow(context.Background(), metrics.RateLimitBidderQPS, "appnexus", 2, time.Second))
+	assert.True(t, limiter.Allow(context.Background(), metrics.RateLimitBidderQPS, "appnexus", 2, time.Second))
+	assert.False(t, limiter.Allow(context.Background(), metrics.RateLimitBidderQPS, "appnexus", 2, time.Second))
+	me.AssertExpectations(t)
+}
+
+func TestLimiterExhausted(t *testing.T) {
+	me := &metrics.MetricsEngineMock{}
+	me.On("RecordRateLimit", metrics.RateLimitDealPacing, metrics.RateLimitAllowed).Return().Once()
+	me.On("RecordRateLimit", metrics.RateLimitDealPacing, metrics.RateLimitLimited).Return().Once()
+	limiter := NewLocalLimiter(me)
+
+	assert.False(t, limiter.Exhausted(context.Background(), metrics.RateLimitDealPacing, "deal", 2, time.Hour))
+	limiter.Count(context.Background(), metrics.RateLimitDealPacing, "deal", 2, time.Hour)
+	assert.True(t, limiter.Exhausted(context.Background(), metrics.RateLimitDealPacing, "deal", 2, time.Hour))
+	me.AssertExpectations(t)
+}
+
+func TestLimiterFailsOpen(t *testing.T) {
+	me := &metrics.MetricsEngineMock{}
+	me.On("RecordRateLimit", metrics.RateLimitAPIKey, metrics.RateLimitError).Return().Times(3)
+	limiter := &Limiter{counter: failingCounter{}, distributed: true, me: me}
+
+	assert.True(t, limiter.Allow(context.Background(), metrics.RateLimitAPIKey, "key", 1, time.Second))
+	assert.False(t, limiter.Exhausted(context.Background(), metrics.RateLimitAPIKey, "key", 1, time.Second))
+	limiter.Count(context.Background(), metrics.RateLimitAPIKey, "key", 1, time.Second)
+	me.AssertExpectations(t)
+}
+
+func TestNilLimiter(t *testing.T) {
+	var limiter *Limiter
+	assert.False(t, limiter.Distributed())
+	assert.True(t, limiter.Allow(context.Background(), metrics.RateLimitAPIKey, "key", 0, time.Second))
+	assert.False(t, limiter.Exhausted(context.Background(), metrics.RateLimitDealPacing, "deal", 0, time.Hour))
+	limiter.Count(context.Background(), metrics.RateLimitDealPacing, "deal", 1, time.Hour)
+	assert.NoError(t, limiter.Close())
+}
@@ -0,0 +1,58 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/util/redisutil"
+)
+
+// redisCounter keeps the counts in Redis, so that they're shared by every instance which uses the same
+// server or cluster. Each window of a key is its own Redis key, which expires once the window is over.
+type redisCounter struct {
+	client    redis.UniversalClient
+	keyPrefix string
+	timeout   time.Duration
+	now       func() time.Time
+}
+
+func newRedisCounter(cfg config.RateLimitingRedis) (*redisCounter, error) {
+	client, err := redisutil.NewClient(cfg.RedisConnection)
+	if err != nil {
+		return nil, err
+	}
+	return &redisCounter{
+		client:    client,
+		keyPrefix: cfg.KeyPrefix,
+		timeout:   time.Duration(cfg.TimeoutMS) * time.Millisecond,
+		now:       time.Now,
+	}, nil
+}
+
+func (c *redisCounter) Add(ctx context.Context, key string, n int64, window time.Duration) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	// The expiry is set on every call rather than just the first, so that a key is never left without one
+	// if the instance which created it fails in between. It outlives the window, so that instances whose
+	// clocks are a little behind still find the count.
+	redisKey := fmt.Sprintf("%s%s:%d", c.keyPrefix, key, windowIndex(c.now(), window))
+	var count *redis.IntCmd
+	_, err := c.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		count = pipe.IncrBy(ctx, redisKey, n)
+		pipe.PExpire(ctx, redisKey, 2*window)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return count.Val(), nil
+}
+
+func (c *redisCounter) Close() error {
+	return c.client.Close()
+}
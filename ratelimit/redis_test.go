@@ -0,0 +1,84 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestRedisCounter(t *testing.T, addr string, timeoutMS int) *redisCounter {
+	counter, err := newRedisCounter(config.RateLimitingRedis{
+		RedisConnection: config.RedisConnection{Addrs: []string{addr}},
+		KeyPrefix:       "pbs:",
+		TimeoutMS:       timeoutMS,
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { counter.Close() })
+	return counter
+}
+
+func TestRedisCounter(t *testing.T) {
+	server := miniredis.RunT(t)
+	counter := newTestRedisCounter(t, server.Addr(), 1000)
+	now := time.Unix(1700000000, 0)
+	counter.now = func() time.Time { return now }
+
+	count, err := counter.Add(context.Background(), "key", 1, time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+	count, err = counter.Add(context.Background(), "key", 2, time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), count)
+	assert.Equal(t, 2*time.Minute, server.TTL("pbs:key:28333333"))
+
+	// adding 0 reads the count
+	count, err = counter.Add(context.Background(), "key", 0, time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), count)
+
+	// the next window is another key
+	now = now.Add(time.Minute)
+	count, err = counter.Add(context.Background(), "key", 1, time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+	assert.Equal(t, []string{"pbs:key:28333333", "pbs:key:28333334"}, server.Keys())
+}
+
+func TestRedisCounterErrors(t *testing.T) {
+	server := miniredis.RunT(t)
+	counter := newTestRedisCounter(t, server.Addr(), 1000)
+
+	server.SetError("LOADING Redis is loading the dataset in memory")
+	_, err := counter.Add(context.Background(), "key", 1, time.Minute)
+	assert.Error(t, err)
+
+	server.SetError("")
+	count, err := counter.Add(context.Background(), "key", 1, time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+}
+
+func TestRedisCounterUnreachable(t *testing.T) {
+	server := miniredis.RunT(t)
+	addr := server.Addr()
+	server.Close()
+	counter := newTestRedisCounter(t, addr, 10)
+
+	start := time.Now()
+	_, err := counter.Add(context.Background(), "key", 1, time.Minute)
+	assert.Error(t, err)
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func TestNewRedisCounterInvalidTLS(t *testing.T) {
+	_, err := newRedisCounter(config.RateLimitingRedis{
+		RedisConnection: config.RedisConnection{Addrs: []string{"localhost:6379"}, TLS: config.RedisTLS{Enabled: true, RootCert: "does-not-exist.pem"}},
+		TimeoutMS:       20,
+	})
+	assert.Error(t, err)
+}
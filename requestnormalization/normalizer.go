@@ -0,0 +1,302 @@
+// Package requestnormalization fixes well known mistakes in requests before they're validated, such as
+// numbers sent as strings, currency codes in lower case and consent strings sent in regs, so requests whose
+// meaning is clear are auctioned instead of rejected. Each fix is warned about, so the publisher can correct
+// their integration, and counted.
+package requestnormalization
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/buger/jsonparser"
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/errortypes"
+	"github.com/prebid/prebid-server/v2/metrics"
+	"github.com/prebid/prebid-server/v2/openrtb_ext"
+	"github.com/prebid/prebid-server/v2/util/jsonutil"
+)
+
+var (
+	integerPattern = regexp.MustCompile(`^-?(0|[1-9][0-9]*)$`)
+	numberPattern  = regexp.MustCompile(`^-?(0|[1-9][0-9]*)(\.[0-9]+)?([eE][+-]?[0-9]+)?$`)
+	currencyCode   = regexp.MustCompile(`^[a-zA-Z]{3}$`)
+)
+
+// numericField is a field which must be a number, and whether it must be an integer.
+type numericField struct {
+	path    []string
+	integer bool
+}
+
+// requestNumericFields are the fields of the request, outside of its imps, which are most often sent as strings.
+var requestNumericFields = []numericField{
+	{path: []string{"tmax"}, integer: true},
+	{path: []string{"test"}, integer: true},
+	{path: []string{"at"}, integer: true},
+	{path: []string{"allimps"}, integer: true},
+	{path: []string{"regs", "coppa"}, integer: true},
+	{path: []string{"regs", "gdpr"}, integer: true},
+	{path: []string{"regs", "ext", "gdpr"}, integer: true},
+	{path: []string{"device", "devicetype"}, integer: true},
+	{path: []string{"device", "connectiontype"}, integer: true},
+	{path: []string{"device", "lmt"}, integer: true},
+	{path: []string{"device", "dnt"}, integer: true},
+	{path: []string{"device", "js"}, integer: true},
+	{path: []string{"device", "w"}, integer: true},
+	{path: []string{"device", "h"}, integer: true},
+	{path: []string{"user", "yob"}, integer: true},
+}
+
+// impNumericFields are the fields of imps which are most often sent as strings.
+var impNumericFields = []numericField{
+	{path: []string{"bidfloor"}},
+	{path: []string{"secure"}, integer: true},
+	{path: []string{"instl"}, integer: true},
+	{path: []string{"rwdd"}, integer: true},
+	{path: []string{"banner", "w"}, integer: true},
+	{path: []string{"banner", "h"}, integer: true},
+	{path: []string{"banner", "pos"}, integer: true},
+	{path: []string{"video", "w"}, integer: true},
+	{path: []string{"video", "h"}, integer: true},
+	{path: []string{"video", "minduration"}, integer: true},
+	{path: []string{"video", "maxduration"}, integer: true},
+	{path: []string{"video", "plcmt"}, integer: true},
+	{path: []string{"ext", "prebid", "is_rewarded_inventory"}, integer: true},
+}
+
+// misplacedField is a field sent in the wrong object, and where it belongs.
+type misplacedField struct {
+	from [][]string
+	to   []string
+	// alsoTo is another place the field may already be, in which case it isn't moved
+	alsoTo []string
+}
+
+var misplacedConsentFields = []misplacedField{
+	{from: [][]string{{"regs", "consent"}, {"regs", "ext", "consent"}}, to: []string{"user", "consent"}, alsoTo: []string{"user", "ext", "consent"}},
+	{from: [][]string{{"user", "gdpr"}, {"user", "ext", "gdpr"}}, to: []string{"regs", "gdpr"}, alsoTo: []string{"regs", "ext", "gdpr"}},
+	{from: [][]string{{"user", "us_privacy"}, {"user", "ext", "us_privacy"}}, to: []string{"regs", "us_privacy"}, alsoTo: []string{"regs", "ext", "us_privacy"}},
+}
+
+// Normalizer applies the fixes the host has enabled. A nil *Normalizer is valid, and leaves requests as they are.
+type Normalizer struct {
+	cfg config.RequestNormalization
+	// numericParams are the params of each bidder whose schema says they must be numbers, by whether they
+	// must be integers
+	numericParams map[openrtb_ext.BidderName]map[string]bool
+	me            metrics.MetricsEngine
+}
+
+// NewNormalizer returns the normalizer for the host's config, or nil if normalization isn't enabled. The
+// params validator's schemas tell which bidder params must be numbers.
+func NewNormalizer(cfg config.RequestNormalization, paramsValidator openrtb_ext.BidderParamValidator, me metrics.MetricsEngine) *Normalizer {
+	if !cfg.Enabled {
+		return nil
+	}
+
+	n := &Normalizer{cfg: cfg, me: me}
+	if cfg.StringifiedNumbers && paramsValidator != nil {
+		n.numericParams = make(map[openrtb_ext.BidderName]map[string]bool)
+		for _, bidder := range openrtb_ext.CoreBidderNames() {
+			if params := numericParams(paramsValidator.Schema(bidder)); len(params) > 0 {
+				n.numericParams[bidder] = params
+			}
+		}
+	}
+	return n
+}
+
+// numericParams returns the properties of a bidder params schema which must be numbers, by whether they
+// must be integers. Properties which may also be strings are left out, since the strings are valid.
+func numericParams(schema string) map[string]bool {
+	var parsed struct {
+		Properties map[string]struct {
+			Type interface{} `json:"type"`
+		} `json:"properties"`
+	}
+	if err := jsonutil.UnmarshalValid([]byte(schema), &parsed); err != nil {
+		return nil
+	}
+
+	params := make(map[string]bool)
+	for name, property := range parsed.Properties {
+		switch property.Type {
+		case "integer":
+			params[name] = true
+		case "number":
+			params[name] = false
+		}
+	}
+	return params
+}
+
+// Normalize applies the fixes to the request, which has had stored requests merged into it, and returns it
+// with a warning for each fix.
+func (n *Normalizer) Normalize(request []byte) ([]byte, []error) {
+	if n == nil {
+		return request, nil
+	}
+
+	var warnings []error
+	if n.cfg.ConsentLocation {
+		request, warnings = n.moveConsent(request, warnings)
+	}
+	if n.cfg.StringifiedNumbers {
+		request, warnings = n.fixStringifiedNumbers(request, warnings)
+	}
+	if n.cfg.EnumCase {
+		request, warnings = n.fixEnumCase(request, warnings)
+	}
+	return request, warnings
+}
+
+func (n *Normalizer) moveConsent(request []byte, warnings []error) ([]byte, []error) {
+	for _, field := range misplacedConsentFields {
+		if exists(request, field.to) || exists(request, field.alsoTo) {
+			continue
+		}
+		for _, from := range field.from {
+			value, dataType, _, err := jsonparser.Get(request, from...)
+			if err != nil {
+				continue
+			}
+			if dataType == jsonparser.String {
+				// strings are returned without their quotes, though still escaped
+				value = []byte(`"` + string(value) + `"`)
+			}
+			moved, err := jsonparser.Set(request, value, field.to...)
+			if err != nil {
+				continue
+			}
+			request = jsonparser.Delete(moved, from...)
+			warnings = n.applied(warnings, metrics.RequestNormalizationConsentLocation,
+				"request.%s was moved to request.%s", strings.Join(from, "."), strings.Join(field.to, "."))
+			break
+		}
+	}
+	return request, warnings
+}
+
+func (n *Normalizer) fixStringifiedNumbers(request []byte, warnings []error) ([]byte, []error) {
+	for _, field := range requestNumericFields {
+		request, warnings = n.fixStringifiedNumber(request, warnings, field.integer, field.path...)
+	}
+
+	for i := 0; exists(request, []string{"imp", index(i)}); i++ {
+		for _, field := range impNumericFields {
+			request, warnings = n.fixStringifiedNumber(request, warnings, field.integer, append([]string{"imp", index(i)}, field.path...)...)
+		}
+		request, warnings = n.fixStringifiedParams(request, warnings, "imp", index(i), "ext", "prebid", "bidder")
+		request, warnings = n.fixStringifiedParams(request, warnings, "imp", index(i), "ext")
+	}
+	return request, warnings
+}
+
+// fixStringifiedParams fixes the numeric params of the bidders in the object at the path.
+func (n *Normalizer) fixStringifiedParams(request []byte, warnings []error, path ...string) ([]byte, []error) {
+	var bidders []string
+	jsonparser.ObjectEach(request, func(key []byte, _ []byte, dataType jsonparser.ValueType, _ int) error {
+		if dataType == jsonparser.Object {
+			bidders = append(bidders, string(key))
+		}
+		return nil
+	}, path...)
+
+	for _, bidder := range bidders {
+		coreBidder, ok := openrtb_ext.NormalizeBidderName(bidder)
+		if !ok {
+			continue
+		}
+		for param, integer := range n.numericParams[coreBidder] {
+			request, warnings = n.fixStringifiedNumber(request, warnings, integer, append(append(path[:len(path):len(path)], bidder), param)...)
+		}
+	}
+	return request, warnings
+}
+
+func (n *Normalizer) fixStringifiedNumber(request []byte, warnings []error, integer bool, path ...string) ([]byte, []error) {
+	value, dataType, _, err := jsonparser.Get(request, path...)
+	if err != nil || dataType != jsonparser.String {
+		return request, warnings
+	}
+	number := strings.TrimSpace(string(value))
+	if integer && !integerPattern.MatchString(number) || !integer && !numberPattern.MatchString(number) {
+		return request, warnings
+	}
+
+	fixed, err := jsonparser.Set(request, []byte(number), path...)
+	if err != nil {
+		return request, warnings
+	}
+	return fixed, n.applied(warnings, metrics.RequestNormalizationStringifiedNumber,
+		"request.%s was the string %q, and was changed to the number %s", pathName(path), string(value), number)
+}
+
+func (n *Normalizer) fixEnumCase(request []byte, warnings []error) ([]byte, []error) {
+	var currencies int
+	jsonparser.ArrayEach(request, func(_ []byte, _ jsonparser.ValueType, _ int, _ error) {
+		currencies++
+	}, "cur")
+	for i := 0; i < currencies; i++ {
+		request, warnings = n.fixCase(request, warnings, currencyCode, strings.ToUpper, "cur", index(i))
+	}
+
+	for i := 0; exists(request, []string{"imp", index(i)}); i++ {
+		request, warnings = n.fixCase(request, warnings, currencyCode, strings.ToUpper, "imp", index(i), "bidfloorcur")
+	}
+
+	return n.fixCase(request, warnings, nil, strings.ToLower, "ext", "prebid", "targeting", "pricegranularity")
+}
+
+// fixCase changes the case of the string at the path, if it matches the pattern.
+func (n *Normalizer) fixCase(request []byte, warnings []error, pattern *regexp.Regexp, toCase func(string) string, path ...string) ([]byte, []error) {
+	value, dataType, _, err := jsonparser.Get(request, path...)
+	if err != nil || dataType != jsonparser.String {
+		return request, warnings
+	}
+	original := string(value)
+	if pattern != nil && !pattern.MatchString(original) {
+		return request, warnings
+	}
+	changed := toCase(original)
+	if changed == original {
+		return request, warnings
+	}
+
+	fixed, err := jsonparser.Set(request, []byte(`"`+changed+`"`), path...)
+	if err != nil {
+		return request, warnings
+	}
+	return fixed, n.applied(warnings, metrics.RequestNormalizationEnumCase,
+		"request.%s was %q, and was changed to %q", pathName(path), original, changed)
+}
+
+func (n *Normalizer) applied(warnings []error, fix metrics.RequestNormalization, format string, args ...interface{}) []error {
+	n.me.RecordRequestNormalization(fix)
+	return append(warnings, &errortypes.Warning{
+		Message:     fmt.Sprintf(format, args...),
+		WarningCode: errortypes.RequestNormalizedWarningCode,
+	})
+}
+
+func exists(request []byte, path []string) bool {
+	_, _, _, err := jsonparser.Get(request, path...)
+	return err == nil
+}
+
+func index(i int) string {
+	return fmt.Sprintf("[%d]", i)
+}
+
+// pathName returns the path as it's written in messages, such as imp[0].banner.w.
+func pathName(path []string) string {
+	var name strings.Builder
+	for i, key := range path {
+		if i > 0 && !strings.HasPrefix(key, "[") {
+			name.WriteString(".")
+		}
+		name.WriteString(key)
+	}
+	return name.String()
+}
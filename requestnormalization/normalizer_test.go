@@ -0,0 +1,185 @@
+package requestnormalization
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/errortypes"
+	"github.com/prebid/prebid-server/v2/metrics"
+	"github.com/prebid/prebid-server/v2/openrtb_ext"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type fakeParamsValidator struct {
+	schemas map[openrtb_ext.BidderName]string
+}
+
+func (v fakeParamsValidator) Validate(name openrtb_ext.BidderName, ext json.RawMessage) error {
+	return nil
+}
+
+func (v fakeParamsValidator) Schema(name openrtb_ext.BidderName) string {
+	return v.schemas[name]
+}
+
+var paramsValidator = fakeParamsValidator{schemas: map[openrtb_ext.BidderName]string{
+	openrtb_ext.BidderAppnexus: `{"properties":{"placementId":{"type":["integer","string"]},"reserve":{"type":"number"},"member":{"type":"string"}}}`,
+	openrtb_ext.BidderRubicon:  `{"properties":{"accountId":{"type":"integer"}}}`,
+}}
+
+var allFixes = config.RequestNormalization{Enabled: true, StringifiedNumbers: true, EnumCase: true, ConsentLocation: true}
+
+func TestNewNormalizer(t *testing.T) {
+	assert.Nil(t, NewNormalizer(config.RequestNormalization{StringifiedNumbers: true}, paramsValidator, &metrics.MetricsEngineMock{}), "disabled")
+
+	normalizer := NewNormalizer(allFixes, paramsValidator, &metrics.MetricsEngineMock{})
+	assert.Equal(t, map[openrtb_ext.BidderName]map[string]bool{
+		openrtb_ext.BidderAppnexus: {"reserve": false},
+		openrtb_ext.BidderRubicon:  {"accountId": true},
+	}, normalizer.numericParams)
+}
+
+func TestNilNormalizer(t *testing.T) {
+	var normalizer *Normalizer
+	request := []byte(`{"tmax":"500"}`)
+
+	normalized, warnings := normalizer.Normalize(request)
+
+	assert.Equal(t, request, normalized)
+	assert.Empty(t, warnings)
+}
+
+func TestNormalize(t *testing.T) {
+	testCases := []struct {
+		description      string
+		cfg              config.RequestNormalization
+		request          string
+		expectedRequest  string
+		expectedWarnings []string
+		expectedFixes    map[metrics.RequestNormalization]int
+	}{
+		{
+			description:     "nothing_to_fix",
+			cfg:             allFixes,
+			request:         `{"id":"1","tmax":500,"cur":["USD"],"imp":[{"id":"a","bidfloor":1.5,"bidfloorcur":"EUR"}],"regs":{"gdpr":1},"user":{"consent":"BO"}}`,
+			expectedRequest: `{"id":"1","tmax":500,"cur":["USD"],"imp":[{"id":"a","bidfloor":1.5,"bidfloorcur":"EUR"}],"regs":{"gdpr":1},"user":{"consent":"BO"}}`,
+		},
+		{
+			description:     "stringified_numbers",
+			cfg:             allFixes,
+			request:         `{"tmax":"500","regs":{"ext":{"gdpr":"1"}},"imp":[{"id":"a","bidfloor":"1.25","banner":{"w":"300","h":250}},{"id":"b","video":{"maxduration":" 30 "}}]}`,
+			expectedRequest: `{"tmax":500,"regs":{"ext":{"gdpr":1}},"imp":[{"id":"a","bidfloor":1.25,"banner":{"w":300,"h":250}},{"id":"b","video":{"maxduration":30}}]}`,
+			expectedWarnings: []string{
+				`request.tmax was the string "500", and was changed to the number 500`,
+				`request.regs.ext.gdpr was the string "1", and was changed to the number 1`,
+				`request.imp[0].bidfloor was the string "1.25", and was changed to the number 1.25`,
+				`request.imp[0].banner.w was the string "300", and was changed to the number 300`,
+				`request.imp[1].video.maxduration was the string " 30 ", and was changed to the number 30`,
+			},
+			expectedFixes: map[metrics.RequestNormalization]int{metrics.RequestNormalizationStringifiedNumber: 5},
+		},
+		{
+			description:     "stringified_numbers_which_arent_numbers",
+			cfg:             allFixes,
+			request:         `{"tmax":"soon","imp":[{"id":"a","banner":{"w":"300.5"},"bidfloor":"1,5"}]}`,
+			expectedRequest: `{"tmax":"soon","imp":[{"id":"a","banner":{"w":"300.5"},"bidfloor":"1,5"}]}`,
+		},
+		{
+			description:     "stringified_bidder_params",
+			cfg:             allFixes,
+			request:         `{"imp":[{"id":"a","ext":{"prebid":{"bidder":{"appnexus":{"placementId":"123","reserve":"0.5","member":"958"}}},"RUBICON":{"accountId":"1001"}}}]}`,
+			expectedRequest: `{"imp":[{"id":"a","ext":{"prebid":{"bidder":{"appnexus":{"placementId":"123","reserve":0.5,"member":"958"}}},"RUBICON":{"accountId":1001}}}]}`,
+			expectedWarnings: []string{
+				`request.imp[0].ext.prebid.bidder.appnexus.reserve was the string "0.5", and was changed to the number 0.5`,
+				`request.imp[0].ext.RUBICON.accountId was the string "1001", and was changed to the number 1001`,
+			},
+			expectedFixes: map[metrics.RequestNormalization]int{metrics.RequestNormalizationStringifiedNumber: 2},
+		},
+		{
+			description:     "enum_case",
+			cfg:             allFixes,
+			request:         `{"cur":["usd","Eur"],"imp":[{"id":"a","bidfloorcur":"gbp"}],"ext":{"prebid":{"targeting":{"pricegranularity":"Medium"}}}}`,
+			expectedRequest: `{"cur":["USD","EUR"],"imp":[{"id":"a","bidfloorcur":"GBP"}],"ext":{"prebid":{"targeting":{"pricegranularity":"medium"}}}}`,
+			expectedWarnings: []string{
+				`request.cur[0] was "usd", and was changed to "USD"`,
+				`request.cur[1] was "Eur", and was changed to "EUR"`,
+				`request.imp[0].bidfloorcur was "gbp", and was changed to "GBP"`,
+				`request.ext.prebid.targeting.pricegranularity was "Medium", and was changed to "medium"`,
+			},
+			expectedFixes: map[metrics.RequestNormalization]int{metrics.RequestNormalizationEnumCase: 4},
+		},
+		{
+			description:     "enum_case_of_values_which_arent_currencies",
+			cfg:             allFixes,
+			request:         `{"cur":["dollars"],"imp":[{"id":"a","bidfloorcur":"us"}]}`,
+			expectedRequest: `{"cur":["dollars"],"imp":[{"id":"a","bidfloorcur":"us"}]}`,
+		},
+		{
+			description:     "consent_location",
+			cfg:             allFixes,
+			request:         `{"regs":{"ext":{"consent":"BO"}},"user":{"id":"u","ext":{"gdpr":"1","us_privacy":"1YNN"}}}`,
+			expectedRequest: `{"regs":{"ext":{},"gdpr":1,"us_privacy":"1YNN"},"user":{"id":"u","ext":{},"consent":"BO"}}`,
+			expectedWarnings: []string{
+				`request.regs.ext.consent was moved to request.user.consent`,
+				`request.user.ext.gdpr was moved to request.regs.gdpr`,
+				`request.user.ext.us_privacy was moved to request.regs.us_privacy`,
+				`request.regs.gdpr was the string "1", and was changed to the number 1`,
+			},
+			expectedFixes: map[metrics.RequestNormalization]int{metrics.RequestNormalizationConsentLocation: 3, metrics.RequestNormalizationStringifiedNumber: 1},
+		},
+		{
+			description:     "consent_location_already_set",
+			cfg:             allFixes,
+			request:         `{"regs":{"consent":"BO","ext":{"gdpr":0}},"user":{"ext":{"consent":"CP","gdpr":1}}}`,
+			expectedRequest: `{"regs":{"consent":"BO","ext":{"gdpr":0}},"user":{"ext":{"consent":"CP","gdpr":1}}}`,
+		},
+		{
+			description:     "fixes_disabled",
+			cfg:             config.RequestNormalization{Enabled: true},
+			request:         `{"tmax":"500","cur":["usd"],"regs":{"consent":"BO"}}`,
+			expectedRequest: `{"tmax":"500","cur":["usd"],"regs":{"consent":"BO"}}`,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			me := &metrics.MetricsEngineMock{}
+			me.On("RecordRequestNormalization", mock.Anything).Return()
+			normalizer := NewNormalizer(test.cfg, paramsValidator, me)
+
+			normalized, warnings := normalizer.Normalize([]byte(test.request))
+
+			assert.JSONEq(t, test.expectedRequest, string(normalized))
+			messages := make([]string, 0, len(warnings))
+			for _, warning := range warnings {
+				assert.Equal(t, errortypes.RequestNormalizedWarningCode, errortypes.ReadCode(warning))
+				messages = append(messages, warning.Error())
+			}
+			assert.ElementsMatch(t, test.expectedWarnings, messages)
+			me.AssertNumberOfCalls(t, "RecordRequestNormalization", countCalls(test.expectedFixes))
+			for _, fix := range metrics.RequestNormalizations() {
+				assert.Equal(t, test.expectedFixes[fix], countFix(me, fix), "fix %s", fix)
+			}
+		})
+	}
+}
+
+func countCalls(fixes map[metrics.RequestNormalization]int) int {
+	var calls int
+	for _, count := range fixes {
+		calls += count
+	}
+	return calls
+}
+
+func countFix(me *metrics.MetricsEngineMock, fix metrics.RequestNormalization) int {
+	var count int
+	for _, call := range me.Calls {
+		if call.Method == "RecordRequestNormalization" && call.Arguments.Get(0) == fix {
+			count++
+		}
+	}
+	return count
+}
@@ -0,0 +1,101 @@
+package responseoverride
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/prebid/prebid-server/v2/logger"
+	"github.com/prebid/prebid-server/v2/util/httputil"
+	"github.com/prebid/prebid-server/v2/util/jsonutil"
+)
+
+// maxRequestSize limits the body of requests which set an override.
+const maxRequestSize = 64 * 1024
+
+// setRequest is the body of a PUT request, which sets an override.
+type setRequest struct {
+	Account          string `json:"account"`
+	Bidder           string `json:"bidder"`
+	StoredResponseID string `json:"stored_response_id"`
+	DebugTokenOnly   bool   `json:"debug_token_only"`
+	// ReplaceImpID defaults to true, as for ext.prebid.storedbidresponse
+	ReplaceImpID *bool `json:"replace_imp_id"`
+	TTLSeconds   int   `json:"ttl_seconds"`
+}
+
+type deleteResponse struct {
+	Deleted int `json:"deleted"`
+}
+
+// Handler returns a handler which manages the overrides, or nil if response overrides are disabled.
+// Requests must have one of the configured tokens as their bearer token.
+//
+// GET lists the overrides in effect, of the account in the query string if there is one. PUT sets the
+// override in the body, and DELETE removes the overrides of the account and, optionally, the bidder in the
+// query string.
+func (o *Overrides) Handler() http.Handler {
+	if o == nil {
+		return nil
+	}
+	return http.HandlerFunc(o.serve)
+}
+
+func (o *Overrides) serve(w http.ResponseWriter, req *http.Request) {
+	if !httputil.HasBearerToken(req, o.tokens) {
+		w.Header().Set("WWW-Authenticate", "Bearer")
+		http.Error(w, "A valid bearer token is required", http.StatusUnauthorized)
+		return
+	}
+
+	switch req.Method {
+	case http.MethodGet:
+		httputil.WriteJSON(w, o.List(req.URL.Query().Get("account")))
+	case http.MethodPut:
+		o.serveSet(w, req)
+	case http.MethodDelete:
+		params := req.URL.Query()
+		account, bidder := params.Get("account"), params.Get("bidder")
+		if account == "" {
+			http.Error(w, "The account of the overrides to delete is required", http.StatusBadRequest)
+			return
+		}
+		deleted := o.Delete(account, bidder)
+		if deleted > 0 {
+			logger.Warningf("Response overrides of account %s deleted: bidder=%q count=%d", account, bidder, deleted)
+		}
+		httputil.WriteJSON(w, deleteResponse{Deleted: deleted})
+	default:
+		w.Header().Set("Allow", "GET, PUT, DELETE")
+		http.Error(w, "Response overrides are managed with GET, PUT and DELETE", http.StatusMethodNotAllowed)
+	}
+}
+
+func (o *Overrides) serveSet(w http.ResponseWriter, req *http.Request) {
+	body, err := io.ReadAll(io.LimitReader(req.Body, maxRequestSize))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid request: %v", err), http.StatusBadRequest)
+		return
+	}
+	var request setRequest
+	if err := jsonutil.UnmarshalValid(body, &request); err != nil {
+		http.Error(w, fmt.Sprintf("Invalid request: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	override := Override{
+		Account:          request.Account,
+		Bidder:           request.Bidder,
+		StoredResponseID: request.StoredResponseID,
+		DebugTokenOnly:   request.DebugTokenOnly,
+		ReplaceImpID:     request.ReplaceImpID == nil || *request.ReplaceImpID,
+	}
+	override, err = o.Set(req.Context(), override, time.Duration(request.TTLSeconds)*time.Second)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid request: %v", err), http.StatusBadRequest)
+		return
+	}
+	logger.Warningf("Responses of bidder %s for account %s pinned to stored response %s until %s", override.Bidder, override.Account, override.StoredResponseID, override.Expires.UTC().Format(time.RFC3339))
+	httputil.WriteJSON(w, override)
+}
@@ -0,0 +1,139 @@
+package responseoverride
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prebid/prebid-server/v2/util/timeutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler(t *testing.T) {
+	clock := timeutil.NewFakeTime(time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC))
+	o := newTestOverrides(clock, responseFetcher{"resp-1": json.RawMessage(`{}`)})
+	handler := o.Handler()
+
+	serve := func(method, target, authorization, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		if authorization != "" {
+			req.Header.Set("Authorization", authorization)
+		}
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, req)
+		return recorder
+	}
+
+	tests := []struct {
+		description    string
+		method         string
+		target         string
+		authorization  string
+		body           string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			description:    "no-token",
+			method:         http.MethodGet,
+			target:         "/stored_responses/overrides",
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			description:    "wrong-token",
+			method:         http.MethodPut,
+			target:         "/stored_responses/overrides",
+			authorization:  "Bearer other",
+			body:           `{"account":"acct","bidder":"appnexus","stored_response_id":"resp-1","ttl_seconds":60}`,
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			description:    "set",
+			method:         http.MethodPut,
+			target:         "/stored_responses/overrides",
+			authorization:  "Bearer token",
+			body:           `{"account":"acct","bidder":"appnexus","stored_response_id":"resp-1","ttl_seconds":60,"debug_token_only":true}`,
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"account":"acct","bidder":"appnexus","stored_response_id":"resp-1","debug_token_only":true,"replace_imp_id":true,"expires":"2024-05-01T12:01:00Z"}`,
+		},
+		{
+			description:    "set-without-replacing-imp-ids",
+			method:         http.MethodPut,
+			target:         "/stored_responses/overrides",
+			authorization:  "Bearer token",
+			body:           `{"account":"acct","bidder":"rubicon","stored_response_id":"resp-1","ttl_seconds":60,"replace_imp_id":false}`,
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"account":"acct","bidder":"rubicon","stored_response_id":"resp-1","debug_token_only":false,"replace_imp_id":false,"expires":"2024-05-01T12:01:00Z"}`,
+		},
+		{
+			description:    "set-invalid",
+			method:         http.MethodPut,
+			target:         "/stored_responses/overrides",
+			authorization:  "Bearer token",
+			body:           `{"account":"acct","bidder":"openx","stored_response_id":"resp-1","ttl_seconds":7200}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			description:    "set-malformed",
+			method:         http.MethodPut,
+			target:         "/stored_responses/overrides",
+			authorization:  "Bearer token",
+			body:           `{"account":`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			description:    "list",
+			method:         http.MethodGet,
+			target:         "/stored_responses/overrides?account=acct",
+			authorization:  "Bearer token",
+			expectedStatus: http.StatusOK,
+			expectedBody: `[{"account":"acct","bidder":"appnexus","stored_response_id":"resp-1","debug_token_only":true,"replace_imp_id":true,"expires":"2024-05-01T12:01:00Z"},` +
+				`{"account":"acct","bidder":"rubicon","stored_response_id":"resp-1","debug_token_only":false,"replace_imp_id":false,"expires":"2024-05-01T12:01:00Z"}]`,
+		},
+		{
+			description:    "delete-without-account",
+			method:         http.MethodDelete,
+			target:         "/stored_responses/overrides?bidder=appnexus",
+			authorization:  "Bearer token",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			description:    "delete",
+			method:         http.MethodDelete,
+			target:         "/stored_responses/overrides?account=acct&bidder=appnexus",
+			authorization:  "Bearer token",
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"deleted":1}`,
+		},
+		{
+			description:    "list-after-delete",
+			method:         http.MethodGet,
+			target:         "/stored_responses/overrides",
+			authorization:  "Bearer token",
+			expectedStatus: http.StatusOK,
+			expectedBody:   `[{"account":"acct","bidder":"rubicon","stored_response_id":"resp-1","debug_token_only":false,"replace_imp_id":false,"expires":"2024-05-01T12:01:00Z"}]`,
+		},
+		{
+			description:    "wrong-method",
+			method:         http.MethodPost,
+			target:         "/stored_responses/overrides",
+			authorization:  "Bearer token",
+			expectedStatus: http.StatusMethodNotAllowed,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			recorder := serve(test.method, test.target, test.authorization, test.body)
+
+			require.Equal(t, test.expectedStatus, recorder.Code, recorder.Body.String())
+			if test.expectedBody != "" {
+				assert.JSONEq(t, test.expectedBody, recorder.Body.String())
+			}
+		})
+	}
+}
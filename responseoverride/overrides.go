@@ -0,0 +1,215 @@
+// Package responseoverride pins a bidder's responses for an account to a stored bid response for a while, so
+// that a misbehaving bidder can be isolated, or one of its responses replayed, in production without changing
+// code or stored requests. Overrides are set on the admin server and kept in memory, so they don't survive a
+// restart, and they always expire.
+package responseoverride
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/errortypes"
+	"github.com/prebid/prebid-server/v2/stored_requests"
+	"github.com/prebid/prebid-server/v2/util/timeutil"
+)
+
+// Override pins a bidder's responses for an account to a stored bid response.
+type Override struct {
+	Account string `json:"account"`
+	Bidder  string `json:"bidder"`
+	// StoredResponseID is the ID of the stored bid response, in the bidder's own format, which the bidder's
+	// responses are pinned to
+	StoredResponseID string `json:"stored_response_id"`
+	// DebugTokenOnly limits the override to requests with a valid debug token for the account, so that only
+	// the requests of whoever is troubleshooting are pinned
+	DebugTokenOnly bool `json:"debug_token_only"`
+	// ReplaceImpID replaces the imp IDs of the stored response's bids with the IDs of the request's imps
+	ReplaceImpID bool      `json:"replace_imp_id"`
+	Expires      time.Time `json:"expires"`
+}
+
+// Pinned is the stored bid response a bidder's responses are pinned to in an auction. The bidder isn't
+// called for the auction's imps, and answers each of them with the response.
+type Pinned struct {
+	StoredResponseID string
+	Response         json.RawMessage
+	ReplaceImpID     bool
+}
+
+type key struct {
+	account string
+	bidder  string
+}
+
+// Overrides holds the overrides in effect. A nil *Overrides is valid and has none.
+type Overrides struct {
+	fetcher      stored_requests.Fetcher
+	maxTTL       time.Duration
+	maxOverrides int
+	tokens       [][]byte
+	clock        timeutil.Time
+
+	mutex     sync.RWMutex
+	overrides map[key]Override
+}
+
+// New builds the Overrides, which fetch the stored bid responses with fetcher, or returns nil if response
+// overrides are disabled.
+func New(cfg config.ResponseOverrides, fetcher stored_requests.Fetcher) *Overrides {
+	if !cfg.Enabled {
+		return nil
+	}
+	o := &Overrides{
+		fetcher:      fetcher,
+		maxTTL:       time.Duration(cfg.MaxTTLSeconds) * time.Second,
+		maxOverrides: cfg.MaxOverrides,
+		clock:        &timeutil.RealTime{},
+		overrides:    make(map[key]Override),
+	}
+	for _, token := range cfg.Tokens {
+		o.tokens = append(o.tokens, []byte(token))
+	}
+	return o
+}
+
+// Set puts the override in effect for ttl, in place of the account's override of the same bidder, if there
+// is one. The stored bid response must exist. It returns the override with its expiry.
+func (o *Overrides) Set(ctx context.Context, override Override, ttl time.Duration) (Override, error) {
+	if override.Account == "" || override.Bidder == "" || override.StoredResponseID == "" {
+		return override, errors.New("account, bidder and stored_response_id are required")
+	}
+	if ttl <= 0 || ttl > o.maxTTL {
+		return override, fmt.Errorf("ttl_seconds must be between 1 and %d", int(o.maxTTL.Seconds()))
+	}
+	responses, errs := o.fetcher.FetchResponses(ctx, []string{override.StoredResponseID})
+	if len(errs) > 0 {
+		return override, fmt.Errorf("failed to fetch stored response %s: %v", override.StoredResponseID, errs[0])
+	}
+	if len(responses[override.StoredResponseID]) == 0 {
+		return override, fmt.Errorf("stored response %s not found", override.StoredResponseID)
+	}
+
+	now := o.clock.Now()
+	override.Expires = now.Add(ttl)
+	k := key{account: override.Account, bidder: strings.ToLower(override.Bidder)}
+
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	o.removeExpired(now)
+	if _, replaced := o.overrides[k]; !replaced && len(o.overrides) >= o.maxOverrides {
+		return override, fmt.Errorf("there are already %d overrides in effect", o.maxOverrides)
+	}
+	o.overrides[k] = override
+	return override, nil
+}
+
+// Delete removes the account's override of the bidder, or all of the account's overrides if bidder is
+// empty. It returns the number of overrides removed.
+func (o *Overrides) Delete(account, bidder string) int {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	if bidder != "" {
+		k := key{account: account, bidder: strings.ToLower(bidder)}
+		if _, ok := o.overrides[k]; !ok {
+			return 0
+		}
+		delete(o.overrides, k)
+		return 1
+	}
+	deleted := 0
+	for k := range o.overrides {
+		if k.account == account {
+			delete(o.overrides, k)
+			deleted++
+		}
+	}
+	return deleted
+}
+
+// List returns the overrides in effect, of the account if one is given, by account and bidder.
+func (o *Overrides) List(account string) []Override {
+	now := o.clock.Now()
+	o.mutex.RLock()
+	overrides := make([]Override, 0, len(o.overrides))
+	for k, override := range o.overrides {
+		if (account == "" || k.account == account) && now.Before(override.Expires) {
+			overrides = append(overrides, override)
+		}
+	}
+	o.mutex.RUnlock()
+
+	sort.Slice(overrides, func(i, j int) bool {
+		if overrides[i].Account != overrides[j].Account {
+			return overrides[i].Account < overrides[j].Account
+		}
+		return strings.ToLower(overrides[i].Bidder) < strings.ToLower(overrides[j].Bidder)
+	})
+	return overrides
+}
+
+// Pinned returns the stored bid responses the account's bidders are pinned to in an auction, by lower case
+// bidder name, and a warning for each, so the response shows it isn't what the bidders would have sent.
+// Overrides limited to debug tokens only apply if the request has a valid one.
+func (o *Overrides) Pinned(ctx context.Context, account string, debugToken bool) (map[string]Pinned, []error) {
+	if o == nil {
+		return nil, nil
+	}
+	now := o.clock.Now()
+	var overrides []Override
+	o.mutex.RLock()
+	for k, override := range o.overrides {
+		if k.account == account && now.Before(override.Expires) && (debugToken || !override.DebugTokenOnly) {
+			overrides = append(overrides, override)
+		}
+	}
+	o.mutex.RUnlock()
+	if len(overrides) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]string, 0, len(overrides))
+	for _, override := range overrides {
+		ids = append(ids, override.StoredResponseID)
+	}
+	// the fetcher's errors are for responses which weren't found, which are warned about below
+	responses, _ := o.fetcher.FetchResponses(ctx, ids)
+
+	pinned := make(map[string]Pinned, len(overrides))
+	warnings := make([]error, 0, len(overrides))
+	for _, override := range overrides {
+		response := responses[override.StoredResponseID]
+		if len(response) == 0 {
+			warnings = append(warnings, &errortypes.Warning{
+				Message:     fmt.Sprintf("response override of bidder %s ignored: stored response %s not found", override.Bidder, override.StoredResponseID),
+				WarningCode: errortypes.ResponseOverrideWarningCode,
+			})
+			continue
+		}
+		pinned[strings.ToLower(override.Bidder)] = Pinned{
+			StoredResponseID: override.StoredResponseID,
+			Response:         response,
+			ReplaceImpID:     override.ReplaceImpID,
+		}
+		warnings = append(warnings, &errortypes.Warning{
+			Message:     fmt.Sprintf("responses of bidder %s are pinned to stored response %s by a response override until %s", override.Bidder, override.StoredResponseID, override.Expires.UTC().Format(time.RFC3339)),
+			WarningCode: errortypes.ResponseOverrideWarningCode,
+		})
+	}
+	return pinned, warnings
+}
+
+// removeExpired must be called with the mutex locked.
+func (o *Overrides) removeExpired(now time.Time) {
+	for k, override := range o.overrides {
+		if !now.Before(override.Expires) {
+			delete(o.overrides, k)
+		}
+	}
+}
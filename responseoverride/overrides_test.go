@@ -0,0 +1,179 @@
+package responseoverride
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/errortypes"
+	"github.com/prebid/prebid-server/v2/util/timeutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// responseFetcher has stored responses, by ID.
+type responseFetcher map[string]json.RawMessage
+
+func (f responseFetcher) FetchRequests(ctx context.Context, requestIDs []string, impIDs []string) (map[string]json.RawMessage, map[string]json.RawMessage, []error) {
+	return nil, nil, nil
+}
+
+func (f responseFetcher) FetchResponses(ctx context.Context, ids []string) (map[string]json.RawMessage, []error) {
+	data := make(map[string]json.RawMessage)
+	var errs []error
+	for _, id := range ids {
+		if response, ok := f[id]; ok {
+			data[id] = response
+		} else {
+			errs = append(errs, fmt.Errorf("stored response %s not found", id))
+		}
+	}
+	return data, errs
+}
+
+func newTestOverrides(clock timeutil.Time, fetcher responseFetcher) *Overrides {
+	o := New(config.ResponseOverrides{Enabled: true, MaxTTLSeconds: 3600, MaxOverrides: 2, Tokens: []string{"token"}}, fetcher)
+	o.clock = clock
+	return o
+}
+
+func TestPinnedNil(t *testing.T) {
+	var o *Overrides
+	pinned, warnings := o.Pinned(context.Background(), "acct", true)
+	assert.Nil(t, pinned)
+	assert.Nil(t, warnings)
+}
+
+func TestSet(t *testing.T) {
+	clock := timeutil.NewFakeTime(time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC))
+	o := newTestOverrides(clock, responseFetcher{"resp-1": json.RawMessage(`{"seatbid":[]}`)})
+	valid := Override{Account: "acct", Bidder: "appnexus", StoredResponseID: "resp-1"}
+
+	tests := []struct {
+		description   string
+		override      Override
+		ttl           time.Duration
+		expectedError string
+	}{
+		{
+			description:   "missing-bidder",
+			override:      Override{Account: "acct", StoredResponseID: "resp-1"},
+			ttl:           time.Minute,
+			expectedError: "account, bidder and stored_response_id are required",
+		},
+		{
+			description:   "ttl-too-long",
+			override:      valid,
+			ttl:           2 * time.Hour,
+			expectedError: "ttl_seconds must be between 1 and 3600",
+		},
+		{
+			description:   "no-ttl",
+			override:      valid,
+			expectedError: "ttl_seconds must be between 1 and 3600",
+		},
+		{
+			description:   "unknown-stored-response",
+			override:      Override{Account: "acct", Bidder: "appnexus", StoredResponseID: "resp-2"},
+			ttl:           time.Minute,
+			expectedError: "failed to fetch stored response resp-2: stored response resp-2 not found",
+		},
+		{
+			description: "valid",
+			override:    valid,
+			ttl:         time.Minute,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			override, err := o.Set(context.Background(), test.override, test.ttl)
+			if test.expectedError != "" {
+				assert.EqualError(t, err, test.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, clock.Now().Add(test.ttl), override.Expires)
+		})
+	}
+}
+
+func TestSetCapsOverrides(t *testing.T) {
+	clock := timeutil.NewFakeTime(time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC))
+	o := newTestOverrides(clock, responseFetcher{"resp-1": json.RawMessage(`{}`)})
+	set := func(account, bidder string, ttl time.Duration) error {
+		_, err := o.Set(context.Background(), Override{Account: account, Bidder: bidder, StoredResponseID: "resp-1"}, ttl)
+		return err
+	}
+
+	require.NoError(t, set("acct", "appnexus", time.Minute))
+	require.NoError(t, set("acct", "rubicon", time.Hour))
+	assert.EqualError(t, set("acct", "openx", time.Hour), "there are already 2 overrides in effect")
+	assert.NoError(t, set("acct", "AppNexus", time.Hour), "an override replaces the one of the same account and bidder")
+
+	clock.Advance(2 * time.Hour)
+	assert.NoError(t, set("acct", "openx", time.Hour), "expired overrides don't count")
+	assert.Equal(t, []Override{{Account: "acct", Bidder: "openx", StoredResponseID: "resp-1", Expires: clock.Now().Add(time.Hour)}}, o.List(""))
+}
+
+func TestListAndDelete(t *testing.T) {
+	clock := timeutil.NewFakeTime(time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC))
+	o := newTestOverrides(clock, responseFetcher{"resp-1": json.RawMessage(`{}`)})
+	o.maxOverrides = 10
+	for _, k := range []key{{"acct-2", "appnexus"}, {"acct-1", "rubicon"}, {"acct-1", "appnexus"}} {
+		_, err := o.Set(context.Background(), Override{Account: k.account, Bidder: k.bidder, StoredResponseID: "resp-1"}, time.Hour)
+		require.NoError(t, err)
+	}
+
+	overrides := o.List("")
+	require.Len(t, overrides, 3)
+	assert.Equal(t, []string{"acct-1", "acct-1", "acct-2"}, []string{overrides[0].Account, overrides[1].Account, overrides[2].Account})
+	assert.Equal(t, []string{"appnexus", "rubicon", "appnexus"}, []string{overrides[0].Bidder, overrides[1].Bidder, overrides[2].Bidder})
+	assert.Len(t, o.List("acct-2"), 1)
+
+	assert.Equal(t, 1, o.Delete("acct-1", "RUBICON"))
+	assert.Equal(t, 0, o.Delete("acct-1", "rubicon"))
+	assert.Equal(t, 1, o.Delete("acct-1", ""))
+	assert.Empty(t, o.List("acct-1"))
+	assert.Len(t, o.List(""), 1)
+}
+
+func TestPinned(t *testing.T) {
+	clock := timeutil.NewFakeTime(time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC))
+	fetcher := responseFetcher{"resp-1": json.RawMessage(`{"id":"1"}`), "resp-2": json.RawMessage(`{"id":"2"}`)}
+	o := newTestOverrides(clock, fetcher)
+	o.maxOverrides = 10
+	_, err := o.Set(context.Background(), Override{Account: "acct", Bidder: "AppNexus", StoredResponseID: "resp-1", ReplaceImpID: true}, time.Hour)
+	require.NoError(t, err)
+	_, err = o.Set(context.Background(), Override{Account: "acct", Bidder: "rubicon", StoredResponseID: "resp-2", DebugTokenOnly: true}, time.Hour)
+	require.NoError(t, err)
+	_, err = o.Set(context.Background(), Override{Account: "other", Bidder: "openx", StoredResponseID: "resp-1"}, time.Hour)
+	require.NoError(t, err)
+
+	pinned, warnings := o.Pinned(context.Background(), "acct", false)
+	assert.Equal(t, map[string]Pinned{"appnexus": {StoredResponseID: "resp-1", Response: json.RawMessage(`{"id":"1"}`), ReplaceImpID: true}}, pinned)
+	assert.Equal(t, []error{&errortypes.Warning{
+		Message:     "responses of bidder AppNexus are pinned to stored response resp-1 by a response override until 2024-05-01T13:00:00Z",
+		WarningCode: errortypes.ResponseOverrideWarningCode,
+	}}, warnings)
+
+	pinned, warnings = o.Pinned(context.Background(), "acct", true)
+	assert.Len(t, pinned, 2, "overrides limited to debug tokens apply to requests with one")
+	assert.Contains(t, pinned, "rubicon")
+	assert.Len(t, warnings, 2)
+
+	delete(fetcher, "resp-1")
+	pinned, warnings = o.Pinned(context.Background(), "other", false)
+	assert.Empty(t, pinned)
+	assert.Equal(t, []error{&errortypes.Warning{
+		Message:     "response override of bidder openx ignored: stored response resp-1 not found",
+		WarningCode: errortypes.ResponseOverrideWarningCode,
+	}}, warnings)
+
+	clock.Advance(time.Hour)
+	pinned, warnings = o.Pinned(context.Background(), "acct", true)
+	assert.Empty(t, pinned, "expired overrides don't apply")
+	assert.Empty(t, warnings)
+}
@@ -0,0 +1,183 @@
+// Package responsesigning signs auction responses with detached JSON Web Signatures (RFC 7515, appendix F), so
+// that wrappers and SDKs can verify a response wasn't changed by anything between them and this server.
+//
+// The signature is made over the response in the canonical form of the JSON Canonicalization Scheme (RFC 8785)
+// rather than over the bytes sent, so it still verifies after the response has been parsed and written again.
+// Verifiers canonicalize the response they receive, base64url encode it, and put it between the two dots of the
+// signature to get a compact JWS.
+package responsesigning
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"os"
+	"sort"
+
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/util/jsonutil"
+)
+
+// Header is the response header the signature is sent in.
+const Header = "X-Prebid-Signature"
+
+const algorithm = "ES256"
+
+// coordinateSize is the size of P-256 coordinates and signature halves, in bytes.
+const coordinateSize = 32
+
+// Signer signs responses with the host's keys.
+type Signer struct {
+	keys map[string]*ecdsa.PrivateKey
+	jwks []byte
+}
+
+type protectedHeader struct {
+	Algorithm string `json:"alg"`
+	KeyID     string `json:"kid"`
+}
+
+type jwk struct {
+	KeyType   string `json:"kty"`
+	Curve     string `json:"crv"`
+	X         string `json:"x"`
+	Y         string `json:"y"`
+	KeyID     string `json:"kid"`
+	Algorithm string `json:"alg"`
+	Use       string `json:"use"`
+}
+
+type jwkSet struct {
+	Keys []jwk `json:"keys"`
+}
+
+// NewSigner loads the keys responses may be signed with. It returns nil if there are none.
+func NewSigner(cfg config.ResponseSigning) (*Signer, error) {
+	if len(cfg.Keys) == 0 {
+		return nil, nil
+	}
+
+	keys := make(map[string]*ecdsa.PrivateKey, len(cfg.Keys))
+	for id, path := range cfg.Keys {
+		pemBytes, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("response_signing.keys.%s: %v", id, err)
+		}
+		key, err := parsePrivateKey(pemBytes)
+		if err != nil {
+			return nil, fmt.Errorf("response_signing.keys.%s: %v", id, err)
+		}
+		keys[id] = key
+	}
+	return newSigner(keys)
+}
+
+func newSigner(keys map[string]*ecdsa.PrivateKey) (*Signer, error) {
+	ids := make([]string, 0, len(keys))
+	for id := range keys {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	set := jwkSet{Keys: make([]jwk, 0, len(ids))}
+	for _, id := range ids {
+		public := keys[id].PublicKey
+		set.Keys = append(set.Keys, jwk{
+			KeyType:   "EC",
+			Curve:     "P-256",
+			X:         encodeFixed(public.X),
+			Y:         encodeFixed(public.Y),
+			KeyID:     id,
+			Algorithm: algorithm,
+			Use:       "sig",
+		})
+	}
+	jwks, err := jsonutil.Marshal(set)
+	if err != nil {
+		return nil, err
+	}
+	return &Signer{keys: keys, jwks: jwks}, nil
+}
+
+// parsePrivateKey reads a P-256 private key from a PKCS #8 or SEC 1 PEM block.
+func parsePrivateKey(pemBytes []byte) (*ecdsa.PrivateKey, error) {
+	block, _ := pem.Decode(pemBytes)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM block found")
+	}
+
+	var key *ecdsa.PrivateKey
+	switch block.Type {
+	case "EC PRIVATE KEY":
+		ecKey, err := x509.ParseECPrivateKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		key = ecKey
+	case "PRIVATE KEY":
+		parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		ecKey, ok := parsed.(*ecdsa.PrivateKey)
+		if !ok {
+			return nil, fmt.Errorf("the key is a %T, not an ECDSA key", parsed)
+		}
+		key = ecKey
+	default:
+		return nil, fmt.Errorf("unexpected PEM block type %s", block.Type)
+	}
+
+	if key.Curve != elliptic.P256() {
+		return nil, fmt.Errorf("the key is on curve %s, not P-256", key.Curve.Params().Name)
+	}
+	return key, nil
+}
+
+// Sign returns the detached signature of the JSON payload, made with the key of the given ID.
+func (s *Signer) Sign(keyID string, payload []byte) (string, error) {
+	key, ok := s.keys[keyID]
+	if !ok {
+		return "", fmt.Errorf("unknown response signing key %s", keyID)
+	}
+
+	canonical, err := jsonutil.Canonicalize(payload)
+	if err != nil {
+		return "", err
+	}
+	header, err := jsonutil.Marshal(protectedHeader{Algorithm: algorithm, KeyID: keyID})
+	if err != nil {
+		return "", err
+	}
+
+	encodedHeader := base64.RawURLEncoding.EncodeToString(header)
+	signingInput := encodedHeader + "." + base64.RawURLEncoding.EncodeToString(canonical)
+	digest := sha256.Sum256([]byte(signingInput))
+	r, sigS, err := ecdsa.Sign(rand.Reader, key, digest[:])
+	if err != nil {
+		return "", err
+	}
+
+	// JWS signatures are the fixed size R and S, rather than the ASN.1 of ecdsa.SignASN1
+	signature := make([]byte, 2*coordinateSize)
+	r.FillBytes(signature[:coordinateSize])
+	sigS.FillBytes(signature[coordinateSize:])
+	return encodedHeader + ".." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// JWKS returns the public keys verifiers need, as a JSON Web Key Set.
+func (s *Signer) JWKS() []byte {
+	return s.jwks
+}
+
+func encodeFixed(n *big.Int) string {
+	b := make([]byte, coordinateSize)
+	n.FillBytes(b)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
@@ -0,0 +1,136 @@
+package responsesigning
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeKey(t *testing.T, blockType string, der []byte) string {
+	path := filepath.Join(t.TempDir(), "key.pem")
+	require.NoError(t, os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0600))
+	return path
+}
+
+func TestSignVerifies(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	pkcs8, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+
+	signer, err := NewSigner(config.ResponseSigning{Keys: map[string]string{"2024": writeKey(t, "PRIVATE KEY", pkcs8)}})
+	require.NoError(t, err)
+
+	signature, err := signer.Sign("2024", []byte(`{"id":"some-request","seatbid":[{"bid":[{"id":"1","price":1.50}]}]}`))
+	require.NoError(t, err)
+
+	parts := strings.Split(signature, ".")
+	require.Len(t, parts, 3)
+	assert.Empty(t, parts[1], "the payload is detached")
+	header, err := base64.RawURLEncoding.DecodeString(parts[0])
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"alg":"ES256","kid":"2024"}`, string(header))
+
+	// the verifier gets the response written another way, and uses the public key from the key set
+	var set jwkSet
+	require.NoError(t, json.Unmarshal(signer.JWKS(), &set))
+	require.Len(t, set.Keys, 1)
+	x, _ := base64.RawURLEncoding.DecodeString(set.Keys[0].X)
+	y, _ := base64.RawURLEncoding.DecodeString(set.Keys[0].Y)
+	public := ecdsa.PublicKey{Curve: elliptic.P256(), X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+
+	canonical := `{"id":"some-request","seatbid":[{"bid":[{"id":"1","price":1.5}]}]}`
+	digest := sha256.Sum256([]byte(parts[0] + "." + base64.RawURLEncoding.EncodeToString([]byte(canonical))))
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	require.NoError(t, err)
+	require.Len(t, sig, 64)
+	assert.True(t, ecdsa.Verify(&public, digest[:], new(big.Int).SetBytes(sig[:32]), new(big.Int).SetBytes(sig[32:])))
+}
+
+func TestSignUnknownKey(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	signer, err := newSigner(map[string]*ecdsa.PrivateKey{"2024": key})
+	require.NoError(t, err)
+
+	_, err = signer.Sign("2023", []byte(`{}`))
+	assert.EqualError(t, err, "unknown response signing key 2023")
+}
+
+func TestSignInvalidPayload(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	signer, err := newSigner(map[string]*ecdsa.PrivateKey{"2024": key})
+	require.NoError(t, err)
+
+	_, err = signer.Sign("2024", []byte(`{"id":`))
+	assert.Error(t, err)
+}
+
+func TestNewSigner(t *testing.T) {
+	p256, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	sec1, err := x509.MarshalECPrivateKey(p256)
+	require.NoError(t, err)
+	p384, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	require.NoError(t, err)
+	p384DER, err := x509.MarshalECPrivateKey(p384)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		description string
+		keys        map[string]string
+		expectedErr string
+		expectNil   bool
+	}{
+		{
+			description: "no keys",
+			expectNil:   true,
+		},
+		{
+			description: "SEC 1 key",
+			keys:        map[string]string{"2024": writeKey(t, "EC PRIVATE KEY", sec1)},
+		},
+		{
+			description: "P-384 key",
+			keys:        map[string]string{"2024": writeKey(t, "EC PRIVATE KEY", p384DER)},
+			expectedErr: "response_signing.keys.2024: the key is on curve P-384, not P-256",
+		},
+		{
+			description: "not a private key",
+			keys:        map[string]string{"2024": writeKey(t, "CERTIFICATE", []byte("cert"))},
+			expectedErr: "response_signing.keys.2024: unexpected PEM block type CERTIFICATE",
+		},
+		{
+			description: "missing file",
+			keys:        map[string]string{"2024": filepath.Join(t.TempDir(), "missing.pem")},
+			expectedErr: "response_signing.keys.2024: open",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			signer, err := NewSigner(config.ResponseSigning{Keys: test.keys})
+			if test.expectedErr != "" {
+				assert.ErrorContains(t, err, test.expectedErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.expectNil, signer == nil)
+		})
+	}
+}
@@ -5,12 +5,13 @@ import (
 	"net/http/pprof"
 	"time"
 
+	"github.com/prebid/prebid-server/v2/config"
 	"github.com/prebid/prebid-server/v2/currency"
 	"github.com/prebid/prebid-server/v2/endpoints"
 	"github.com/prebid/prebid-server/v2/version"
 )
 
-func Admin(rateConverter *currency.RateConverter, rateConverterFetchingInterval time.Duration) *http.ServeMux {
+func Admin(cfg *config.Configuration, rateConverter *currency.RateConverter, rateConverterFetchingInterval time.Duration, auctionReplay http.Handler, bidLandscape http.Handler, responseOverrides http.Handler, storedRequestVersions http.Handler, analyticsIngest http.Handler, bidderKillSwitches http.Handler) *http.ServeMux {
 	// Add endpoints to the admin server
 	// Making sure to add pprof routes
 	mux := http.NewServeMux()
@@ -23,5 +24,26 @@ func Admin(rateConverter *currency.RateConverter, rateConverterFetchingInterval
 	// Register prebid-server defined admin handlers
 	mux.HandleFunc("/currency/rates", endpoints.NewCurrencyRatesEndpoint(rateConverter, rateConverterFetchingInterval))
 	mux.HandleFunc("/version", endpoints.NewVersionEndpoint(version.Ver, version.Rev))
+	mux.HandleFunc("/config/deprecated", endpoints.NewDeprecatedSettingsEndpoint(cfg.DeprecatedSettingsInUse()))
+	mux.HandleFunc("/logging/levels", endpoints.NewLogLevelsEndpoint())
+	if auctionReplay != nil {
+		mux.Handle("/auction_recording/replay", auctionReplay)
+	}
+	if bidLandscape != nil {
+		mux.Handle("/bid_landscape", bidLandscape)
+	}
+	if responseOverrides != nil {
+		mux.Handle("/stored_responses/overrides", responseOverrides)
+	}
+	if storedRequestVersions != nil {
+		mux.Handle("/stored_requests/versions", storedRequestVersions)
+		mux.Handle("/stored_requests/pins", storedRequestVersions)
+	}
+	if analyticsIngest != nil {
+		mux.Handle("/analytics/ingest", analyticsIngest)
+	}
+	if bidderKillSwitches != nil {
+		mux.Handle("/bidders/kill_switches", bidderKillSwitches)
+	}
 	return mux
 }
@@ -0,0 +1,61 @@
+package aspects
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/prebid/prebid-server/v2/loadshedding"
+	"github.com/prebid/prebid-server/v2/metrics"
+)
+
+// MemoryLoadShedding rejects requests with a 503 while the monitor reports that memory usage is past the
+// shedding threshold, and marks them as degraded while it's past the degradation threshold.
+func MemoryLoadShedding(f httprouter.Handle, monitor *loadshedding.MemoryMonitor, retryAfterSeconds int, metricsEngine metrics.MetricsEngine) httprouter.Handle {
+	if monitor == nil {
+		return f
+	}
+
+	return func(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+		switch monitor.Level() {
+		case loadshedding.LevelShed:
+			metricsEngine.RecordLoadShedding(metrics.LoadSheddingMemoryRejected)
+			writeOverloaded(w, retryAfterSeconds)
+			return
+		case loadshedding.LevelDegraded:
+			metricsEngine.RecordLoadShedding(metrics.LoadSheddingMemoryDegraded)
+			r = r.WithContext(loadshedding.WithDegraded(r.Context()))
+		}
+		f(w, r, params)
+	}
+}
+
+// ConcurrencyLimit admits requests through the limiter, rejecting them with a 503 when the server is
+// processing as many requests as it can sustain and they can't be queued.
+func ConcurrencyLimit(f httprouter.Handle, limiter *loadshedding.ConcurrencyLimiter, retryAfterSeconds int, metricsEngine metrics.MetricsEngine) httprouter.Handle {
+	if limiter == nil {
+		return f
+	}
+
+	return func(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+		release, err := limiter.Acquire(r.Context())
+		if err != nil {
+			// a canceled request has no one to respond to
+			if err == loadshedding.ErrOverloaded {
+				metricsEngine.RecordLoadShedding(metrics.LoadSheddingConcurrencyRejected)
+				writeOverloaded(w, retryAfterSeconds)
+			}
+			return
+		}
+		defer release()
+		f(w, r, params)
+	}
+}
+
+func writeOverloaded(w http.ResponseWriter, retryAfterSeconds int) {
+	if retryAfterSeconds > 0 {
+		w.Header().Set("Retry-After", strconv.Itoa(retryAfterSeconds))
+	}
+	w.WriteHeader(http.StatusServiceUnavailable)
+	w.Write([]byte("Server is overloaded"))
+}
@@ -0,0 +1,138 @@
+package aspects
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/loadshedding"
+	"github.com/prebid/prebid-server/v2/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryLoadShedding(t *testing.T) {
+	testCases := []struct {
+		name               string
+		cfg                config.MemoryLoadShedding
+		expectedAction     metrics.LoadSheddingAction
+		expectedCode       int
+		expectedRetryAfter string
+		expectedDegraded   bool
+	}{
+		{
+			name:         "normal",
+			cfg:          config.MemoryLoadShedding{Enabled: true, LimitBytes: 1 << 50, DegradeThreshold: 1, ShedThreshold: 1},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:             "degraded",
+			cfg:              config.MemoryLoadShedding{Enabled: true, LimitBytes: 1 << 50, DegradeThreshold: 1e-12, ShedThreshold: 1},
+			expectedAction:   metrics.LoadSheddingMemoryDegraded,
+			expectedCode:     http.StatusOK,
+			expectedDegraded: true,
+		},
+		{
+			name:               "shed",
+			cfg:                config.MemoryLoadShedding{Enabled: true, LimitBytes: 1, DegradeThreshold: 1, ShedThreshold: 1, RetryAfterSeconds: 2},
+			expectedAction:     metrics.LoadSheddingMemoryRejected,
+			expectedCode:       http.StatusServiceUnavailable,
+			expectedRetryAfter: "2",
+		},
+		{
+			name:           "shed-without-retry-after",
+			cfg:            config.MemoryLoadShedding{Enabled: true, LimitBytes: 1, DegradeThreshold: 1, ShedThreshold: 1},
+			expectedAction: metrics.LoadSheddingMemoryRejected,
+			expectedCode:   http.StatusServiceUnavailable,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			monitor := loadshedding.NewMemoryMonitor(test.cfg)
+			require.NotNil(t, monitor)
+			monitor.Run()
+
+			me := &metrics.MetricsEngineMock{}
+			if test.expectedAction != "" {
+				me.On("RecordLoadShedding", test.expectedAction).Once()
+			}
+
+			called, degraded := false, false
+			handler := MemoryLoadShedding(func(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+				called = true
+				degraded = loadshedding.IsDegraded(r.Context())
+			}, monitor, test.cfg.RetryAfterSeconds, me)
+
+			rw := httptest.NewRecorder()
+			handler(rw, httptest.NewRequest(http.MethodPost, "/openrtb2/auction", nil), nil)
+
+			assert.Equal(t, test.expectedCode, rw.Code)
+			assert.Equal(t, test.expectedRetryAfter, rw.Header().Get("Retry-After"))
+			assert.Equal(t, test.expectedCode == http.StatusOK, called)
+			assert.Equal(t, test.expectedDegraded, degraded)
+			me.AssertExpectations(t)
+		})
+	}
+}
+
+func TestMemoryLoadSheddingDisabled(t *testing.T) {
+	called := false
+	handler := MemoryLoadShedding(func(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+		called = true
+	}, nil, 1, &metrics.MetricsEngineMock{})
+
+	handler(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/openrtb2/auction", nil), nil)
+	assert.True(t, called)
+}
+
+func TestConcurrencyLimit(t *testing.T) {
+	limiter := loadshedding.NewConcurrencyLimiter(config.ConcurrencyLoadShedding{
+		Enabled:            true,
+		InitialLimit:       1,
+		MinLimit:           1,
+		MaxLimit:           1,
+		BackoffRatio:       0.5,
+		TargetQueueDelayMs: 10,
+		MaxQueueWaitMs:     10,
+		MaxQueueSize:       0,
+	})
+	me := &metrics.MetricsEngineMock{}
+	me.On("RecordLoadShedding", metrics.LoadSheddingConcurrencyRejected).Once()
+
+	started, finish := make(chan struct{}), make(chan struct{})
+	handler := ConcurrencyLimit(func(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+		close(started)
+		<-finish
+	}, limiter, 2, me)
+
+	first := httptest.NewRecorder()
+	done := make(chan struct{})
+	go func() {
+		handler(first, httptest.NewRequest(http.MethodPost, "/openrtb2/auction", nil), nil)
+		close(done)
+	}()
+	<-started
+
+	second := httptest.NewRecorder()
+	handler(second, httptest.NewRequest(http.MethodPost, "/openrtb2/auction", nil), nil)
+	assert.Equal(t, http.StatusServiceUnavailable, second.Code)
+	assert.Equal(t, "2", second.Header().Get("Retry-After"))
+
+	close(finish)
+	<-done
+	assert.Equal(t, http.StatusOK, first.Code)
+	me.AssertExpectations(t)
+}
+
+func TestConcurrencyLimitDisabled(t *testing.T) {
+	called := false
+	handler := ConcurrencyLimit(func(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+		called = true
+	}, nil, 1, &metrics.MetricsEngineMock{})
+
+	handler(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/openrtb2/auction", nil), nil)
+	assert.True(t, called)
+}
@@ -11,19 +11,33 @@ import (
 	"time"
 
 	analyticsBuild "github.com/prebid/prebid-server/v2/analytics/build"
+	analyticsHub "github.com/prebid/prebid-server/v2/analytics/hub"
+	"github.com/prebid/prebid-server/v2/apikey"
+	"github.com/prebid/prebid-server/v2/auctionquality"
+	"github.com/prebid/prebid-server/v2/auctionrecording"
+	"github.com/prebid/prebid-server/v2/biddermaintenance"
+	"github.com/prebid/prebid-server/v2/bidlandscape"
 	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/contentclassification"
 	"github.com/prebid/prebid-server/v2/currency"
 	"github.com/prebid/prebid-server/v2/endpoints"
 	"github.com/prebid/prebid-server/v2/endpoints/events"
 	infoEndpoints "github.com/prebid/prebid-server/v2/endpoints/info"
 	"github.com/prebid/prebid-server/v2/endpoints/openrtb2"
+	"github.com/prebid/prebid-server/v2/endpoints/openrtb3"
 	"github.com/prebid/prebid-server/v2/errortypes"
 	"github.com/prebid/prebid-server/v2/exchange"
 	"github.com/prebid/prebid-server/v2/experiment/adscert"
+	"github.com/prebid/prebid-server/v2/faultinjection"
 	"github.com/prebid/prebid-server/v2/floors"
 	"github.com/prebid/prebid-server/v2/gdpr"
+	"github.com/prebid/prebid-server/v2/geolocation"
 	"github.com/prebid/prebid-server/v2/hooks"
+	"github.com/prebid/prebid-server/v2/ivt"
+	"github.com/prebid/prebid-server/v2/loadshedding"
+	"github.com/prebid/prebid-server/v2/logger"
 	"github.com/prebid/prebid-server/v2/macros"
+	"github.com/prebid/prebid-server/v2/metering"
 	"github.com/prebid/prebid-server/v2/metrics"
 	metricsConf "github.com/prebid/prebid-server/v2/metrics/config"
 	"github.com/prebid/prebid-server/v2/modules"
@@ -31,16 +45,22 @@ import (
 	"github.com/prebid/prebid-server/v2/openrtb_ext"
 	"github.com/prebid/prebid-server/v2/pbs"
 	pbc "github.com/prebid/prebid-server/v2/prebid_cache_client"
+	"github.com/prebid/prebid-server/v2/privacy"
+	"github.com/prebid/prebid-server/v2/ratelimit"
+	"github.com/prebid/prebid-server/v2/responseoverride"
+	"github.com/prebid/prebid-server/v2/responsesigning"
 	"github.com/prebid/prebid-server/v2/router/aspects"
 	"github.com/prebid/prebid-server/v2/server/ssl"
+	"github.com/prebid/prebid-server/v2/shadowing"
 	storedRequestsConf "github.com/prebid/prebid-server/v2/stored_requests/config"
+	"github.com/prebid/prebid-server/v2/storedversions"
 	"github.com/prebid/prebid-server/v2/usersync"
 	"github.com/prebid/prebid-server/v2/util/jsonutil"
 	"github.com/prebid/prebid-server/v2/util/uuidutil"
 	"github.com/prebid/prebid-server/v2/version"
+	"github.com/prebid/prebid-server/v2/webhooks"
 
 	_ "github.com/go-sql-driver/mysql"
-	"github.com/golang/glog"
 	"github.com/julienschmidt/httprouter"
 	_ "github.com/lib/pq"
 	"github.com/rs/cors"
@@ -64,7 +84,7 @@ func newJsonDirectoryServer(schemaDirectory string, validator openrtb_ext.Bidder
 	// Slurp the files into memory first, since they're small and it minimizes request latency.
 	files, err := os.ReadDir(schemaDirectory)
 	if err != nil {
-		glog.Fatalf("Failed to read directory %s: %v", schemaDirectory, err)
+		logger.Fatalf("Failed to read directory %s: %v", schemaDirectory, err)
 	}
 
 	bidderMap := openrtb_ext.BuildBidderMap()
@@ -74,7 +94,7 @@ func newJsonDirectoryServer(schemaDirectory string, validator openrtb_ext.Bidder
 		bidder := strings.TrimSuffix(file.Name(), ".json")
 		bidderName, isValid := bidderMap[bidder]
 		if !isValid {
-			glog.Fatalf("Schema exists for an unknown bidder: %s", bidder)
+			logger.Fatalf("Schema exists for an unknown bidder: %s", bidder)
 		}
 		data[bidder] = json.RawMessage(validator.Schema(bidderName))
 	}
@@ -88,14 +108,14 @@ func newJsonDirectoryServer(schemaDirectory string, validator openrtb_ext.Bidder
 	for aliasName, bidderName := range aliases {
 		bidderData, ok := data[bidderName]
 		if !ok {
-			glog.Fatalf("Default alias (%s) exists referencing unknown bidder: %s", aliasName, bidderName)
+			logger.Fatalf("Default alias (%s) exists referencing unknown bidder: %s", aliasName, bidderName)
 		}
 		data[aliasName] = bidderData
 	}
 
 	response, err := jsonutil.Marshal(data)
 	if err != nil {
-		glog.Fatalf("Failed to marshal bidder param JSON-schema: %v", err)
+		logger.Fatalf("Failed to marshal bidder param JSON-schema: %v", err)
 	}
 
 	return func(w http.ResponseWriter, _ *http.Request, _ httprouter.Params) {
@@ -123,7 +143,23 @@ type Router struct {
 	*httprouter.Router
 	MetricsEngine   *metricsConf.DetailedMetricsEngine
 	ParamsValidator openrtb_ext.BidderParamValidator
-	Shutdown        func()
+	// AuctionReplay replays recorded auctions. It's nil unless replay is enabled.
+	AuctionReplay http.Handler
+	// BidLandscape serves the statistics of recent bids. It's nil unless the bid landscape is enabled.
+	BidLandscape http.Handler
+	// ResponseOverrides manages the overrides which pin bidders' responses. It's nil unless they're enabled.
+	ResponseOverrides http.Handler
+	// StoredRequestVersions serves the history of stored requests and manages the pins of accounts to their
+	// versions. It's nil unless stored request versions are enabled.
+	StoredRequestVersions http.Handler
+	// AnalyticsIngest logs the analytics forwarded by edge instances. It's nil unless ingest is enabled.
+	AnalyticsIngest http.Handler
+	// BidderKillSwitches turns the bidders' kill switches on and off. It's nil unless kill switches are enabled.
+	BidderKillSwitches http.Handler
+	// BidderMaintenance leaves bidders out of auctions. It's nil unless the host has maintenance windows or
+	// kill switches are enabled.
+	BidderMaintenance *biddermaintenance.Maintenance
+	Shutdown          func()
 }
 
 func New(cfg *config.Configuration, rateConvertor *currency.RateConverter) (r *Router, err error) {
@@ -139,7 +175,7 @@ func New(cfg *config.Configuration, rateConvertor *currency.RateConverter) (r *R
 	var readCertErr error
 	certPool, readCertErr = ssl.AppendPEMFileToRootCAPool(certPool, cfg.PemCertsFile)
 	if readCertErr != nil {
-		glog.Infof("Could not read certificates file: %s \n", readCertErr.Error())
+		logger.Infof("Could not read certificates file: %s \n", readCertErr.Error())
 	}
 
 	generalHttpClient := &http.Client{
@@ -194,20 +230,29 @@ func New(cfg *config.Configuration, rateConvertor *currency.RateConverter) (r *R
 	moduleDeps := moduledeps.ModuleDeps{HTTPClient: generalHttpClient, RateConvertor: rateConvertor}
 	repo, moduleStageNames, err := modules.NewBuilder().Build(cfg.Hooks.Modules, moduleDeps)
 	if err != nil {
-		glog.Fatalf("Failed to init hook modules: %v", err)
+		logger.Fatalf("Failed to init hook modules: %v", err)
 	}
 
 	// Metrics engine
 	r.MetricsEngine = metricsConf.NewMetricsEngine(cfg, openrtb_ext.CoreBidderNames(), syncerKeys, moduleStageNames)
-	shutdown, fetcher, ampFetcher, accounts, categoriesFetcher, videoFetcher, storedRespFetcher := storedRequestsConf.NewStoredRequests(cfg, r.MetricsEngine, generalHttpClient, r.Router)
+	// Webhook endpoints are sent an event when accounts or stored data change.
+	webhookNotifier := webhooks.NewNotifier(cfg.Webhooks, generalHttpClient, r.MetricsEngine)
+	// Accounts' webhooks are sent the win, loss and render events of their bids.
+	auctionEventNotifier := webhooks.NewAuctionEventNotifier(cfg.AuctionEventWebhooks, generalHttpClient, r.MetricsEngine)
+	shutdown, fetcher, ampFetcher, accounts, categoriesFetcher, videoFetcher, storedRespFetcher := storedRequestsConf.NewStoredRequests(cfg, r.MetricsEngine, generalHttpClient, r.Router, webhookNotifier)
 	// todo(zachbadgett): better shutdown
-	r.Shutdown = shutdown
+	r.Shutdown = func() {
+		shutdown()
+		webhookNotifier.Close()
+		auctionEventNotifier.Close()
+	}
 
 	analyticsRunner := analyticsBuild.New(&cfg.Analytics)
+	r.AnalyticsIngest = analyticsHub.NewIngestHandler(cfg.Analytics.Hub.Ingest, analyticsRunner)
 
 	paramsValidator, err := openrtb_ext.NewBidderParamsValidator(schemaDirectory)
 	if err != nil {
-		glog.Fatalf("Failed to create the bidder params validator. %v", err)
+		logger.Fatalf("Failed to create the bidder params validator. %v", err)
 	}
 
 	activeBidders := exchange.GetActiveBidders(cfg.BidderInfos)
@@ -223,16 +268,68 @@ func New(cfg *config.Configuration, rateConvertor *currency.RateConverter) (r *R
 	gdprPermsBuilder := gdpr.NewPermissionsBuilder(cfg.GDPR, gvlVendorIDs, vendorListFetcher)
 	tcf2CfgBuilder := gdpr.NewTCF2Config
 
-	cacheClient := pbc.NewClient(cacheHttpClient, &cfg.CacheURL, &cfg.ExtCacheURL, r.MetricsEngine)
+	if cfg.FaultInjection.Enabled {
+		logger.Warning("Fault injection is enabled. Calls to bidders, stored data and the cache may be delayed or fail on purpose.")
+	}
+
+	cacheClient := pbc.NewClient(faultinjection.WithFaults(cacheHttpClient, cfg.FaultInjection.Enabled, cfg.FaultInjection.Cache), &cfg.CacheURL, &cfg.ExtCacheURL, r.MetricsEngine)
 
-	adapters, adaptersErrs := exchange.BuildAdapters(generalHttpClient, cfg, cfg.BidderInfos, r.MetricsEngine)
+	auctionRecorder, err := auctionrecording.NewRecorder(cfg.AuctionRecording, cfg.MaxRequestSize)
+	if err != nil {
+		logger.Fatalf("Failed to create the auction recorder: %v", err)
+	}
+	if auctionRecorder != nil {
+		stopOthers := r.Shutdown
+		r.Shutdown = func() {
+			auctionRecorder.Close()
+			stopOthers()
+		}
+	}
+
+	ipConf := privacy.IPConf{IPV6: cfg.AccountDefaults.Privacy.IPv6Config, IPV4: cfg.AccountDefaults.Privacy.IPv4Config}
+	shadower := shadowing.NewShadower(cfg.TrafficShadowing, generalHttpClient, cfg.MaxRequestSize, ipConf, r.MetricsEngine)
+	if shadower != nil {
+		stopOthers := r.Shutdown
+		r.Shutdown = func() {
+			shadower.Close()
+			stopOthers()
+		}
+	}
+
+	// bidders may have their own TLS and connection pool settings, for the requests to their endpoints
+	bidderTransportClient, bidderTransportErrs := exchange.NewBidderTransportClient(generalHttpClient, cfg.BidderInfos)
+	if len(bidderTransportErrs) > 0 {
+		return nil, errortypes.NewAggregateError("Failed to configure bidder transports", bidderTransportErrs)
+	}
+	bidderHttpClient := exchange.NewInProcessBidderClient(bidderTransportClient, cfg.InProcessBidders, cfg.BidderInfos)
+	bidderHttpClient = auctionRecorder.BidderClient(faultinjection.WithFaults(bidderHttpClient, cfg.FaultInjection.Enabled, cfg.FaultInjection.Bidders))
+	adapters, adaptersErrs := exchange.BuildAdapters(bidderHttpClient, cfg, cfg.BidderInfos, r.MetricsEngine)
 	if len(adaptersErrs) > 0 {
 		errs := errortypes.NewAggregateError("Failed to initialize adapters", adaptersErrs)
 		return nil, errs
 	}
+	// connections are warmed up before the server starts accepting auctions
+	if connectionWarmup := exchange.NewConnectionWarmup(bidderTransportClient, cfg.BidderConnectionWarmup, cfg.BidderInfos, r.MetricsEngine); connectionWarmup != nil {
+		connectionWarmup.Start()
+		stopOthers := r.Shutdown
+		r.Shutdown = func() {
+			connectionWarmup.Stop()
+			stopOthers()
+		}
+	}
+	memoryMonitor := loadshedding.NewMemoryMonitor(cfg.LoadShedding.Memory)
+	if memoryMonitor != nil {
+		memoryMonitorTask := loadshedding.NewMemoryMonitorTask(memoryMonitor, cfg.LoadShedding.Memory)
+		memoryMonitorTask.Start()
+		stopOthers := r.Shutdown
+		r.Shutdown = func() {
+			memoryMonitorTask.Stop()
+			stopOthers()
+		}
+	}
 	adsCertSigner, err := adscert.NewAdCertsSigner(cfg.Experiment.AdCerts)
 	if err != nil {
-		glog.Fatalf("Failed to create ads cert signer: %v", err)
+		logger.Fatalf("Failed to create ads cert signer: %v", err)
 	}
 
 	priceFloorFetcher := floors.NewPriceFloorFetcher(cfg.PriceFloors, floorFechterHttpClient, r.MetricsEngine)
@@ -240,21 +337,112 @@ func New(cfg *config.Configuration, rateConvertor *currency.RateConverter) (r *R
 	tmaxAdjustments := exchange.ProcessTMaxAdjustments(cfg.TmaxAdjustments)
 	planBuilder := hooks.NewExecutionPlanBuilder(cfg.Hooks, repo)
 	macroReplacer := macros.NewStringIndexBasedReplacer()
-	theExchange := exchange.NewExchange(adapters, cacheClient, cfg, syncersByBidder, r.MetricsEngine, cfg.BidderInfos, gdprPermsBuilder, rateConvertor, categoriesFetcher, adsCertSigner, macroReplacer, priceFloorFetcher)
-	var uuidGenerator uuidutil.UUIDRandomGenerator
-	openrtbEndpoint, err := openrtb2.NewEndpoint(uuidGenerator, theExchange, paramsValidator, fetcher, accounts, cfg, r.MetricsEngine, analyticsRunner, disabledBidders, defReqJSON, activeBidders, storedRespFetcher, planBuilder, tmaxAdjustments)
+	// The rate limiting counters are shared by the API key rate limits, bidder QPS caps and deal pacing.
+	rateLimiter, err := ratelimit.NewLimiter(cfg.RateLimiting, r.MetricsEngine)
 	if err != nil {
-		glog.Fatalf("Failed to create the openrtb2 endpoint handler. %v", err)
+		logger.Fatalf("Failed to create the rate limiter: %v", err)
+	}
+	stopOthers := r.Shutdown
+	r.Shutdown = func() {
+		rateLimiter.Close()
+		stopOthers()
+	}
+	bidLandscape := bidlandscape.New(cfg.BidLandscape)
+	r.BidLandscape = bidLandscape.Handler()
+	auctionQuality := auctionquality.New(cfg.AuctionQuality)
+	bidderMaintenance := biddermaintenance.New(cfg.BidderMaintenance)
+	r.BidderKillSwitches = bidderMaintenance.Handler()
+	r.BidderMaintenance = bidderMaintenance
+	// Usage quotas are enforced with the rate limiting counters too. The usage left to export is exported on shutdown.
+	meter := metering.NewMeter(cfg.Metering, rateLimiter, generalHttpClient)
+	if meteringExportTask := metering.NewExportTask(meter, cfg.Metering); meteringExportTask != nil {
+		meteringExportTask.Start()
+		stopOthers := r.Shutdown
+		r.Shutdown = func() {
+			meteringExportTask.Stop()
+			meter.Close()
+			stopOthers()
+		}
+	}
+	theExchange := exchange.NewExchange(adapters, cacheClient, cfg, syncersByBidder, r.MetricsEngine, cfg.BidderInfos, gdprPermsBuilder, rateConvertor, categoriesFetcher, adsCertSigner, macroReplacer, priceFloorFetcher, exchange.Dependencies{
+		RateLimiter:    rateLimiter,
+		BidLandscape:   bidLandscape,
+		AuctionQuality: auctionQuality,
+		Meter:          meter,
+		Maintenance:    bidderMaintenance,
+		// hosts with a classifier of their own, such as a local model, pass it here instead
+		ContentClassifier: contentclassification.NewClassifier(cfg.ContentClassification, generalHttpClient),
+	})
+	var uuidGenerator uuidutil.UUIDGenerator = uuidutil.UUIDRandomGenerator{}
+	if cfg.DeterministicIDs.Enabled {
+		logger.Warningf("deterministic_ids is enabled. Generated IDs are derived from seed %d rather than random.", cfg.DeterministicIDs.Seed)
+		uuidGenerator = uuidutil.NewDeterministicGenerator(cfg.DeterministicIDs.Seed)
+	}
+	// The API keys of server to server requests are rate limited across the auction and video endpoints.
+	apiKeyAuthenticator := apikey.NewAuthenticator(rateLimiter)
+	responseSigner, err := responsesigning.NewSigner(cfg.ResponseSigning)
+	if err != nil {
+		logger.Fatalf("Failed to load the response signing keys: %v", err)
+	}
+	geoProvider, err := geolocation.NewProvider(cfg.GeoLocation)
+	if err != nil {
+		logger.Fatalf("Failed to load the geolocation database: %v", err)
+	}
+	if geoReloadTask := geolocation.NewReloadTask(geoProvider, cfg.GeoLocation); geoReloadTask != nil {
+		geoReloadTask.Start()
+		stopOthers := r.Shutdown
+		r.Shutdown = func() {
+			geoReloadTask.Stop()
+			stopOthers()
+		}
+	}
+	geoEnricher := geolocation.NewEnricher(geoProvider, r.MetricsEngine)
+	// The invalid traffic filter is shared by the auction endpoints, so its datacenter ranges are reloaded once.
+	ivtFilter, err := ivt.NewFilter(cfg.IVT)
+	if err != nil {
+		logger.Fatalf("Failed to load the invalid traffic lists: %v", err)
+	}
+	if ivtReloadTask := ivt.NewReloadTask(ivtFilter, cfg.IVT, generalHttpClient); ivtReloadTask != nil {
+		ivtReloadTask.Start()
+		stopOthers := r.Shutdown
+		r.Shutdown = func() {
+			ivtReloadTask.Stop()
+			stopOthers()
+		}
+	}
+	responseOverrides := responseoverride.New(cfg.ResponseOverrides, storedRespFetcher)
+	r.ResponseOverrides = responseOverrides.Handler()
+	storedRequestVersions := storedversions.New(cfg.StoredRequestVersions)
+	r.StoredRequestVersions = storedRequestVersions.Handler()
+	openrtbEndpoint, err := openrtb2.NewEndpoint(uuidGenerator, theExchange, paramsValidator, auctionRecorder.Fetcher(storedRequestVersions.Fetcher(config.RequestDataType, fetcher)), auctionRecorder.AccountFetcher(accounts), cfg, r.MetricsEngine, analyticsRunner, disabledBidders, defReqJSON, activeBidders, storedRespFetcher, planBuilder, tmaxAdjustments, adsCertSigner, apiKeyAuthenticator, responseSigner, geoEnricher, responseOverrides, ivtFilter, storedRequestVersions)
+	if err != nil {
+		logger.Fatalf("Failed to create the openrtb2 endpoint handler. %v", err)
 	}
 
-	ampEndpoint, err := openrtb2.NewAmpEndpoint(uuidGenerator, theExchange, paramsValidator, ampFetcher, accounts, cfg, r.MetricsEngine, analyticsRunner, disabledBidders, defReqJSON, activeBidders, storedRespFetcher, planBuilder, tmaxAdjustments)
+	var simulationEndpoint httprouter.Handle
+	if cfg.AuctionSimulation.Enabled {
+		simulationEndpoint, err = openrtb2.NewSimulationEndpoint(uuidGenerator, theExchange, paramsValidator, fetcher, accounts, cfg, r.MetricsEngine, disabledBidders, defReqJSON, activeBidders, storedRespFetcher, planBuilder, tmaxAdjustments)
+		if err != nil {
+			logger.Fatalf("Failed to create the simulation endpoint handler. %v", err)
+		}
+	}
+
+	var validationEndpoint httprouter.Handle
+	if cfg.RequestValidationEndpoint.Enabled {
+		validationEndpoint, err = openrtb2.NewValidationEndpoint(uuidGenerator, paramsValidator, fetcher, accounts, cfg, r.MetricsEngine, disabledBidders, defReqJSON, activeBidders, storedRespFetcher)
+		if err != nil {
+			logger.Fatalf("Failed to create the validation endpoint handler. %v", err)
+		}
+	}
+
+	ampEndpoint, err := openrtb2.NewAmpEndpoint(uuidGenerator, theExchange, paramsValidator, storedRequestVersions.Fetcher(config.AMPRequestDataType, ampFetcher), accounts, cfg, r.MetricsEngine, analyticsRunner, disabledBidders, defReqJSON, activeBidders, storedRespFetcher, planBuilder, tmaxAdjustments, geoEnricher, ivtFilter, storedRequestVersions)
 	if err != nil {
-		glog.Fatalf("Failed to create the amp endpoint handler. %v", err)
+		logger.Fatalf("Failed to create the amp endpoint handler. %v", err)
 	}
 
-	videoEndpoint, err := openrtb2.NewVideoEndpoint(uuidGenerator, theExchange, paramsValidator, fetcher, videoFetcher, accounts, cfg, r.MetricsEngine, analyticsRunner, disabledBidders, defReqJSON, activeBidders, cacheClient, tmaxAdjustments)
+	videoEndpoint, err := openrtb2.NewVideoEndpoint(uuidGenerator, theExchange, paramsValidator, fetcher, videoFetcher, accounts, cfg, r.MetricsEngine, analyticsRunner, disabledBidders, defReqJSON, activeBidders, cacheClient, tmaxAdjustments, apiKeyAuthenticator, geoEnricher, ivtFilter)
 	if err != nil {
-		glog.Fatalf("Failed to create the video endpoint handler. %v", err)
+		logger.Fatalf("Failed to create the video endpoint handler. %v", err)
 	}
 
 	requestTimeoutHeaders := config.RequestTimeoutHeaders{}
@@ -262,8 +450,45 @@ func New(cfg *config.Configuration, rateConvertor *currency.RateConverter) (r *R
 		videoEndpoint = aspects.QueuedRequestTimeout(videoEndpoint, cfg.RequestTimeoutHeaders, r.MetricsEngine, metrics.ReqTypeVideo)
 	}
 
+	// load shedding applies to recorded requests, but not to replays
+	openrtbEndpoint = auctionRecorder.Record("/openrtb2/auction", openrtbEndpoint)
+	r.AuctionReplay = auctionRecorder.ReplayHandler()
+	// requests turned away by load shedding aren't shadowed, so shadowing adds no work when the host is busy
+	openrtbEndpoint = shadower.Mirror(openrtbEndpoint)
+
+	// one limiter is shared by the auction endpoints, since they compete for the same CPU
+	concurrencyLimiter := loadshedding.NewConcurrencyLimiter(cfg.LoadShedding.Concurrency)
+	openrtbEndpoint = aspects.ConcurrencyLimit(openrtbEndpoint, concurrencyLimiter, cfg.LoadShedding.Concurrency.RetryAfterSeconds, r.MetricsEngine)
+	ampEndpoint = aspects.ConcurrencyLimit(ampEndpoint, concurrencyLimiter, cfg.LoadShedding.Concurrency.RetryAfterSeconds, r.MetricsEngine)
+	videoEndpoint = aspects.ConcurrencyLimit(videoEndpoint, concurrencyLimiter, cfg.LoadShedding.Concurrency.RetryAfterSeconds, r.MetricsEngine)
+	if simulationEndpoint != nil {
+		simulationEndpoint = aspects.ConcurrencyLimit(simulationEndpoint, concurrencyLimiter, cfg.LoadShedding.Concurrency.RetryAfterSeconds, r.MetricsEngine)
+	}
+	if validationEndpoint != nil {
+		validationEndpoint = aspects.ConcurrencyLimit(validationEndpoint, concurrencyLimiter, cfg.LoadShedding.Concurrency.RetryAfterSeconds, r.MetricsEngine)
+	}
+
+	openrtbEndpoint = aspects.MemoryLoadShedding(openrtbEndpoint, memoryMonitor, cfg.LoadShedding.Memory.RetryAfterSeconds, r.MetricsEngine)
+	ampEndpoint = aspects.MemoryLoadShedding(ampEndpoint, memoryMonitor, cfg.LoadShedding.Memory.RetryAfterSeconds, r.MetricsEngine)
+	videoEndpoint = aspects.MemoryLoadShedding(videoEndpoint, memoryMonitor, cfg.LoadShedding.Memory.RetryAfterSeconds, r.MetricsEngine)
+	if simulationEndpoint != nil {
+		simulationEndpoint = aspects.MemoryLoadShedding(simulationEndpoint, memoryMonitor, cfg.LoadShedding.Memory.RetryAfterSeconds, r.MetricsEngine)
+	}
+	if validationEndpoint != nil {
+		validationEndpoint = aspects.MemoryLoadShedding(validationEndpoint, memoryMonitor, cfg.LoadShedding.Memory.RetryAfterSeconds, r.MetricsEngine)
+	}
+
 	r.POST("/openrtb2/auction", openrtbEndpoint)
 	r.POST("/openrtb2/video", videoEndpoint)
+	if simulationEndpoint != nil {
+		r.POST("/openrtb2/simulate", simulationEndpoint)
+	}
+	if validationEndpoint != nil {
+		r.POST("/openrtb2/validate", validationEndpoint)
+	}
+	if cfg.OpenRTB3.Enabled {
+		r.POST("/openrtb3/auction", openrtb3.NewAuctionEndpoint(openrtbEndpoint, cfg.MaxRequestSize))
+	}
 	r.GET("/openrtb2/amp", ampEndpoint)
 	r.GET("/info/bidders", infoEndpoints.NewBiddersEndpoint(cfg.BidderInfos, defaultAliases))
 	r.GET("/info/bidders/:bidderName", infoEndpoints.NewBiddersDetailEndpoint(cfg.BidderInfos, defaultAliases))
@@ -272,6 +497,12 @@ func New(cfg *config.Configuration, rateConvertor *currency.RateConverter) (r *R
 	r.GET("/status", endpoints.NewStatusEndpoint(cfg.StatusResponse))
 	r.GET("/", serveIndex)
 	r.Handler("GET", "/version", endpoints.NewVersionEndpoint(version.Ver, version.Rev))
+	if auctionQualityEndpoint := auctionQuality.Handler(cfg, accounts, r.MetricsEngine); auctionQualityEndpoint != nil {
+		r.Handler("GET", "/auction_quality", auctionQualityEndpoint)
+	}
+	if responseSigner != nil {
+		r.Handler("GET", "/response_signing/keys", endpoints.NewResponseSigningKeysEndpoint(responseSigner))
+	}
 	r.ServeFiles("/static/*filepath", http.Dir("static"))
 
 	// vtrack endpoint
@@ -281,7 +512,7 @@ func New(cfg *config.Configuration, rateConvertor *currency.RateConverter) (r *R
 	}
 
 	// event endpoint
-	eventEndpoint := events.NewEventEndpoint(cfg, accounts, analyticsRunner, r.MetricsEngine)
+	eventEndpoint := events.NewEventEndpoint(cfg, accounts, analyticsRunner, r.MetricsEngine, auctionEventNotifier)
 	r.GET("/event", eventEndpoint)
 
 	userSyncDeps := &pbs.UserSyncDeps{
@@ -310,11 +541,11 @@ func checkSupportedUserSyncEndpoints(bidderInfos config.BidderInfos) error {
 			switch endpointLower {
 			case "iframe":
 				if info.Syncer.IFrame == nil {
-					glog.Warningf("bidder %s supports iframe user sync, but doesn't have a default and must be configured by the host", name)
+					logger.Warningf("bidder %s supports iframe user sync, but doesn't have a default and must be configured by the host", name)
 				}
 			case "redirect":
 				if info.Syncer.Redirect == nil {
-					glog.Warningf("bidder %s supports redirect user sync, but doesn't have a default and must be configured by the host", name)
+					logger.Warningf("bidder %s supports redirect user sync, but doesn't have a default and must be configured by the host", name)
 				}
 			default:
 				return fmt.Errorf("failed to load bidder info for %s, user sync supported endpoint '%s' is unrecognized", name, endpoint)
@@ -345,7 +576,9 @@ func SupportCORS(handler http.Handler) http.Handler {
 		AllowOriginFunc: func(string) bool {
 			return true
 		},
-		AllowedHeaders: []string{"Origin", "X-Requested-With", "Content-Type", "Accept"}})
+		AllowedHeaders: []string{"Origin", "X-Requested-With", "Content-Type", "Accept"},
+		// wrappers running in the browser can only read the signature if it's exposed
+		ExposedHeaders: []string{responsesigning.Header}})
 	return c.Handler(handler)
 }
 
@@ -368,13 +601,13 @@ func readDefaultRequest(defReqConfig config.DefReqConfig) (map[string]string, []
 		}
 		defReqJSON, err := os.ReadFile(defReqConfig.FileSystem.FileName)
 		if err != nil {
-			glog.Fatalf("error reading aliases from file %s: %v", defReqConfig.FileSystem.FileName, err)
+			logger.Fatalf("error reading aliases from file %s: %v", defReqConfig.FileSystem.FileName, err)
 			return aliases, []byte{}
 		}
 
 		if err := jsonutil.UnmarshalValid(defReqJSON, defReq); err != nil {
 			// we might not have aliases defined, but will atleast show that the JSON file is parsable.
-			glog.Fatalf("error parsing alias json in file %s: %v", defReqConfig.FileSystem.FileName, err)
+			logger.Fatalf("error parsing alias json in file %s: %v", defReqConfig.FileSystem.FileName, err)
 			return aliases, []byte{}
 		}
 
@@ -8,8 +8,13 @@ import (
 	"testing"
 
 	jsoniter "github.com/json-iterator/go"
+	"github.com/prebid/prebid-server/v2/auctionquality"
+	"github.com/prebid/prebid-server/v2/biddermaintenance"
+	"github.com/prebid/prebid-server/v2/bidlandscape"
 	"github.com/prebid/prebid-server/v2/config"
 	"github.com/prebid/prebid-server/v2/openrtb_ext"
+	"github.com/prebid/prebid-server/v2/responseoverride"
+	"github.com/prebid/prebid-server/v2/storedversions"
 	"github.com/prebid/prebid-server/v2/util/jsonutil"
 
 	"github.com/stretchr/testify/assert"
@@ -185,6 +190,18 @@ func TestCORSSupport(t *testing.T) {
 	assert.Equal(t, origin, rr.Header().Get("Access-Control-Allow-Origin"))
 }
 
+func TestCORSExposesResponseSignature(t *testing.T) {
+	const origin = "https://publisher-domain.com"
+	handler := func(w http.ResponseWriter, r *http.Request) {}
+	cors := SupportCORS(http.HandlerFunc(handler))
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequest("POST", "http://some-domain.com/openrtb2/auction", nil)
+	req.Header.Set("Origin", origin)
+
+	cors.ServeHTTP(rr, req)
+	assert.Equal(t, "X-Prebid-Signature", rr.Header().Get("Access-Control-Expose-Headers"))
+}
+
 func TestNoCache(t *testing.T) {
 	nc := NoCache{
 		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}),
@@ -301,3 +318,12 @@ func TestBidderParamsCompactedOutput(t *testing.T) {
 	// Assertions
 	assert.Equal(t, expectedFormattedResponse, recorder.Body.String())
 }
+
+func TestDisabledFeaturesHaveNoEndpoints(t *testing.T) {
+	// The features are nil when they're disabled, and the router leaves out the endpoints of nil handlers.
+	assert.Nil(t, bidlandscape.New(config.BidLandscape{}).Handler(), "bid landscape")
+	assert.Nil(t, auctionquality.New(config.AuctionQuality{}).Handler(&config.Configuration{}, nil, nil), "auction quality")
+	assert.Nil(t, biddermaintenance.New(config.BidderMaintenance{}).Handler(), "bidder kill switches")
+	assert.Nil(t, responseoverride.New(config.ResponseOverrides{}, nil).Handler(), "response overrides")
+	assert.Nil(t, storedversions.New(config.StoredRequestVersions{}).Handler(), "stored request versions")
+}
@@ -1,15 +1,24 @@
 package schain
 
 import (
+	"encoding/json"
+	"fmt"
+
 	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/errortypes"
 	"github.com/prebid/prebid-server/v2/openrtb_ext"
+	"github.com/prebid/prebid-server/v2/ortb"
 	"github.com/prebid/prebid-server/v2/util/jsonutil"
 )
 
-// NewSChainWriter creates an ORTB 2.5 schain writer instance
-func NewSChainWriter(reqExt *openrtb_ext.ExtRequest, hostSChainNode *openrtb2.SupplyChainNode) (*SChainWriter, error) {
+const sChainKey = "schain"
+
+// NewSChainWriter creates an ORTB 2.5 schain writer instance. The node appended to each bidder's schain is the
+// host node with the asi and sid the account sets for the bidder, if any.
+func NewSChainWriter(reqExt *openrtb_ext.ExtRequest, hostSChainNode *openrtb2.SupplyChainNode, accountSChain config.AccountSChain) (*SChainWriter, error) {
 	if !extPrebidSChainExists(reqExt) {
-		return &SChainWriter{hostSChainNode: hostSChainNode}, nil
+		return &SChainWriter{hostSChainNode: hostSChainNode, accountSChain: accountSChain}, nil
 	}
 
 	sChainsByBidder, err := BidderToPrebidSChains(reqExt.Prebid.SChains)
@@ -20,6 +29,7 @@ func NewSChainWriter(reqExt *openrtb_ext.ExtRequest, hostSChainNode *openrtb2.Su
 	writer := SChainWriter{
 		sChainsByBidder: sChainsByBidder,
 		hostSChainNode:  hostSChainNode,
+		accountSChain:   accountSChain,
 	}
 	return &writer, nil
 }
@@ -29,33 +39,56 @@ func NewSChainWriter(reqExt *openrtb_ext.ExtRequest, hostSChainNode *openrtb2.Su
 type SChainWriter struct {
 	sChainsByBidder map[string]*openrtb2.SupplyChain
 	hostSChainNode  *openrtb2.SupplyChainNode
+	accountSChain   config.AccountSChain
 }
 
 // Write selects an schain from the multi-schain ORTB 2.5 location (req.ext.prebid.schains) for the specified bidder
-// and copies it to the ORTB 2.5 location (req.source.ext). If no schain exists for the bidder in the multi-schain
-// location and no wildcard schain exists, the request is not modified.
-func (w SChainWriter) Write(req *openrtb2.BidRequest, bidder string) {
+// and copies it to the ORTB 2.5 location (req.source.ext), falling back on the request's own schain. This server's
+// node is appended to the copy, unless the schain already has a node for the same seller, in which case a warning
+// is returned. If no schain exists for the bidder and there's no node to append, the request is not modified.
+func (w SChainWriter) Write(req *openrtb2.BidRequest, bidder string) error {
 	const sChainWildCard = "*"
 	var selectedSChain *openrtb2.SupplyChain
 
 	wildCardSChain := w.sChainsByBidder[sChainWildCard]
 	bidderSChain := w.sChainsByBidder[bidder]
+	node := w.node(bidder)
 
 	// source should not be modified
-	if bidderSChain == nil && wildCardSChain == nil && w.hostSChainNode == nil {
-		return
+	if bidderSChain == nil && wildCardSChain == nil && node == nil {
+		return nil
 	}
 
-	selectedSChain = &openrtb2.SupplyChain{Ver: "1.0"}
+	sourceExt, err := parseSourceExt(req.Source)
+	if err != nil {
+		return err
+	}
 
 	if bidderSChain != nil {
 		selectedSChain = bidderSChain
 	} else if wildCardSChain != nil {
 		selectedSChain = wildCardSChain
+	} else if selectedSChain, err = requestSChain(req.Source, sourceExt); err != nil {
+		return err
+	}
+
+	if selectedSChain == nil {
+		selectedSChain = &openrtb2.SupplyChain{Ver: "1.0"}
+	} else {
+		// the schain is shared by the requests of all the bidders
+		selectedSChain = ortb.CloneSChain(selectedSChain)
 	}
 
-	schain := openrtb_ext.ExtRequestPrebidSChain{
-		SChain: *selectedSChain,
+	var warning error
+	if node != nil {
+		if containsNode(selectedSChain, *node) {
+			warning = &errortypes.Warning{
+				Message:     fmt.Sprintf("schain for bidder %s already has a node for asi %s and sid %s; this server's node was not appended", bidder, node.ASI, node.SID),
+				WarningCode: errortypes.SChainLoopWarningCode,
+			}
+		} else {
+			selectedSChain.Nodes = append(selectedSChain.Nodes, *node)
+		}
 	}
 
 	if req.Source == nil {
@@ -64,15 +97,61 @@ func (w SChainWriter) Write(req *openrtb2.BidRequest, bidder string) {
 		sourceCopy := *req.Source
 		req.Source = &sourceCopy
 	}
+	req.Source.SChain = nil
+
+	sChainJSON, err := jsonutil.Marshal(selectedSChain)
+	if err != nil {
+		return err
+	}
+	sourceExt[sChainKey] = sChainJSON
+	if req.Source.Ext, err = jsonutil.Marshal(sourceExt); err != nil {
+		return err
+	}
+	return warning
+}
 
+// node returns the node this server appends to the bidder's schain, or nil if there's none.
+func (w SChainWriter) node(bidder string) *openrtb2.SupplyChainNode {
+	accountNode, ok := w.accountSChain.Node(bidder)
+	if !ok {
+		return w.hostSChainNode
+	}
+
+	node := openrtb2.SupplyChainNode{HP: openrtb2.Int8Ptr(1)}
 	if w.hostSChainNode != nil {
-		schain.SChain.Nodes = append(schain.SChain.Nodes, *w.hostSChainNode)
+		node = ortb.CloneSupplyChainNode(*w.hostSChainNode)
+	}
+	node.ASI = accountNode.ASI
+	node.SID = accountNode.SID
+	return &node
+}
+
+// parseSourceExt returns the members of source.ext, so that the schain can be replaced without dropping them.
+func parseSourceExt(source *openrtb2.Source) (map[string]json.RawMessage, error) {
+	sourceExt := make(map[string]json.RawMessage)
+	if source == nil || len(source.Ext) == 0 {
+		return sourceExt, nil
+	}
+	if err := jsonutil.Unmarshal(source.Ext, &sourceExt); err != nil {
+		return nil, fmt.Errorf("source.ext is invalid: %v", err)
 	}
+	return sourceExt, nil
+}
 
-	sourceExt, err := jsonutil.Marshal(schain)
-	if err == nil {
-		req.Source.Ext = sourceExt
+// requestSChain returns the schain of the request itself, from the ORTB 2.5 location (req.source.ext.schain) or
+// else the ORTB 2.6 location (req.source.schain).
+func requestSChain(source *openrtb2.Source, sourceExt map[string]json.RawMessage) (*openrtb2.SupplyChain, error) {
+	if sChainJSON, ok := sourceExt[sChainKey]; ok && len(sChainJSON) > 0 && string(sChainJSON) != "null" {
+		var sChain openrtb2.SupplyChain
+		if err := jsonutil.Unmarshal(sChainJSON, &sChain); err != nil {
+			return nil, fmt.Errorf("source.ext.schain is invalid: %v", err)
+		}
+		return &sChain, nil
+	}
+	if source != nil {
+		return source.SChain, nil
 	}
+	return nil, nil
 }
 
 // extPrebidSChainExists checks if an schain exists in the ORTB 2.5 req.ext.prebid.schain location
@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 	"testing"
 
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/errortypes"
 	"github.com/prebid/prebid-server/v2/openrtb_ext"
 	"github.com/prebid/prebid-server/v2/util/jsonutil"
 
@@ -25,8 +27,10 @@ func TestSChainWriter(t *testing.T) {
 		giveRequest    openrtb2.BidRequest
 		giveBidder     string
 		giveHostSChain *openrtb2.SupplyChainNode
+		giveAccount    config.AccountSChain
 		wantRequest    openrtb2.BidRequest
 		wantError      bool
+		wantWarning    bool
 	}{
 		{
 			description: "nil source, nil ext.prebid.schains and empty host schain",
@@ -192,6 +196,106 @@ func TestSChainWriter(t *testing.T) {
 				},
 			},
 		},
+		{
+			description: "Source schain in request, host schain defined, host schain appended to the source schain",
+			giveRequest: openrtb2.BidRequest{
+				Source: &openrtb2.Source{
+					Ext: json.RawMessage(`{` + seller1SChain + `}`),
+				},
+			},
+			giveBidder: "testbidder",
+			giveHostSChain: &openrtb2.SupplyChainNode{
+				ASI: "pbshostcompany.com", SID: "00001", RID: "BidRequest", HP: openrtb2.Int8Ptr(1),
+			},
+			wantRequest: openrtb2.BidRequest{
+				Source: &openrtb2.Source{
+					Ext: json.RawMessage(`{"schain":{"complete":1,"nodes":[` + seller1Node + `,` + hostNode + `],"ver":"1.0"}}`),
+				},
+			},
+		},
+		{
+			description: "ORTB 2.6 source schain in request, host schain defined, schain moved to source.ext with host schain appended",
+			giveRequest: openrtb2.BidRequest{
+				Source: &openrtb2.Source{
+					SChain: &openrtb2.SupplyChain{Complete: 1, Ver: "1.0", Nodes: []openrtb2.SupplyChainNode{
+						{ASI: "directseller1.com", SID: "00001", RID: "BidRequest1", HP: openrtb2.Int8Ptr(1)},
+					}},
+				},
+			},
+			giveBidder: "testbidder",
+			giveHostSChain: &openrtb2.SupplyChainNode{
+				ASI: "pbshostcompany.com", SID: "00001", RID: "BidRequest", HP: openrtb2.Int8Ptr(1),
+			},
+			wantRequest: openrtb2.BidRequest{
+				Source: &openrtb2.Source{
+					Ext: json.RawMessage(`{"schain":{"complete":1,"nodes":[` + seller1Node + `,` + hostNode + `],"ver":"1.0"}}`),
+				},
+			},
+		},
+		{
+			description: "Other source.ext members are kept",
+			giveRequest: openrtb2.BidRequest{
+				Source: &openrtb2.Source{
+					Ext: json.RawMessage(`{"other":"value",` + seller1SChain + `}`),
+				},
+			},
+			giveBidder: "testbidder",
+			giveHostSChain: &openrtb2.SupplyChainNode{
+				ASI: "pbshostcompany.com", SID: "00001", RID: "BidRequest", HP: openrtb2.Int8Ptr(1),
+			},
+			wantRequest: openrtb2.BidRequest{
+				Source: &openrtb2.Source{
+					Ext: json.RawMessage(`{"other":"value","schain":{"complete":1,"nodes":[` + seller1Node + `,` + hostNode + `],"ver":"1.0"}}`),
+				},
+			},
+		},
+		{
+			description: "Account asi and sid replace those of the host schain",
+			giveRequest: openrtb2.BidRequest{},
+			giveBidder:  "testbidder",
+			giveHostSChain: &openrtb2.SupplyChainNode{
+				ASI: "pbshostcompany.com", SID: "00001", RID: "BidRequest", HP: openrtb2.Int8Ptr(1),
+			},
+			giveAccount: config.AccountSChain{ASI: "publisher.com", SID: "pub-1"},
+			wantRequest: openrtb2.BidRequest{
+				Source: &openrtb2.Source{
+					Ext: json.RawMessage(`{"schain":{"complete":0,"nodes":[{"asi":"publisher.com","sid":"pub-1","rid":"BidRequest","hp":1}],"ver":"1.0"}}`),
+				},
+			},
+		},
+		{
+			description: "Account asi and sid for the bidder, without host schain",
+			giveRequest: openrtb2.BidRequest{},
+			giveBidder:  "testbidder",
+			giveAccount: config.AccountSChain{
+				ASI:     "publisher.com",
+				SID:     "pub-1",
+				Bidders: map[string]config.AccountSChainNode{"testbidder": {ASI: "publisher.com", SID: "pub-2"}},
+			},
+			wantRequest: openrtb2.BidRequest{
+				Source: &openrtb2.Source{
+					Ext: json.RawMessage(`{"schain":{"complete":0,"nodes":[{"asi":"publisher.com","sid":"pub-2","hp":1}],"ver":"1.0"}}`),
+				},
+			},
+		},
+		{
+			description: "Schain already has the host node, host schain not appended again",
+			giveRequest: openrtb2.BidRequest{
+				Source: &openrtb2.Source{
+					Ext: json.RawMessage(`{"schain":{"complete":1,"nodes":[` + seller1Node + `,` + hostNode + `],"ver":"1.0"}}`),
+				},
+			},
+			giveBidder: "testbidder",
+			giveHostSChain: &openrtb2.SupplyChainNode{
+				ASI: "pbshostcompany.com", SID: "00001", RID: "BidRequest", HP: openrtb2.Int8Ptr(1),
+			},
+			wantRequest: openrtb2.BidRequest{
+				Source: &openrtb2.Source{
+					Ext: json.RawMessage(`{"schain":{"complete":1,"nodes":[` + seller1Node + `,` + hostNode + `],"ver":"1.0"}}`),
+				},
+			},
+			wantWarning: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -205,7 +309,7 @@ func TestSChainWriter(t *testing.T) {
 			}
 		}
 
-		writer, err := NewSChainWriter(reqExt, tt.giveHostSChain)
+		writer, err := NewSChainWriter(reqExt, tt.giveHostSChain, tt.giveAccount)
 
 		if tt.wantError {
 			assert.NotNil(t, err)
@@ -214,9 +318,37 @@ func TestSChainWriter(t *testing.T) {
 			assert.Nil(t, err)
 			assert.NotNil(t, writer)
 
-			writer.Write(&tt.giveRequest, tt.giveBidder)
+			err = writer.Write(&tt.giveRequest, tt.giveBidder)
+			if tt.wantWarning {
+				assert.True(t, errortypes.IsWarning(err), tt.description)
+			} else {
+				assert.NoError(t, err, tt.description)
+			}
 
 			assert.Equal(t, tt.wantRequest, tt.giveRequest, tt.description)
 		}
 	}
 }
+
+func TestSChainWriterDoesNotModifySharedSChain(t *testing.T) {
+	reqExt := &openrtb_ext.ExtRequest{}
+	reqExt.Prebid.SChains = []*openrtb_ext.ExtRequestPrebidSChain{
+		{
+			Bidders: []string{"*"},
+			SChain: openrtb2.SupplyChain{Complete: 1, Ver: "1.0", Nodes: []openrtb2.SupplyChainNode{
+				{ASI: "directseller1.com", SID: "00001"},
+			}},
+		},
+	}
+	hostNode := &openrtb2.SupplyChainNode{ASI: "pbshostcompany.com", SID: "00001", HP: openrtb2.Int8Ptr(1)}
+
+	writer, err := NewSChainWriter(reqExt, hostNode, config.AccountSChain{})
+	assert.NoError(t, err)
+
+	for _, bidder := range []string{"appnexus", "rubicon"} {
+		req := openrtb2.BidRequest{}
+		assert.NoError(t, writer.Write(&req, bidder))
+		assert.JSONEq(t, `{"schain":{"complete":1,"nodes":[{"asi":"directseller1.com","sid":"00001"},{"asi":"pbshostcompany.com","sid":"00001","hp":1}],"ver":"1.0"}}`, string(req.Source.Ext), bidder)
+	}
+	assert.Len(t, reqExt.Prebid.SChains[0].SChain.Nodes, 1)
+}
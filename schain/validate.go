@@ -0,0 +1,57 @@
+package schain
+
+import (
+	"fmt"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+)
+
+// Validate checks the structure of a supply chain found at the given path of the request. The chain must have
+// a version and a complete flag of 0 or 1, and each node must have an asi and sid. A node which appears twice,
+// by asi and sid, means the chain loops back through a seller it has already passed.
+func Validate(sChain *openrtb2.SupplyChain, path string) error {
+	if sChain == nil {
+		return nil
+	}
+	if sChain.Ver == "" {
+		return fmt.Errorf("%s.ver is required", path)
+	}
+	if sChain.Complete != 0 && sChain.Complete != 1 {
+		return fmt.Errorf("%s.complete must be 0 or 1. Got %d", path, sChain.Complete)
+	}
+
+	seen := make(map[nodeKey]int, len(sChain.Nodes))
+	for i, node := range sChain.Nodes {
+		if node.ASI == "" {
+			return fmt.Errorf("%s.nodes[%d].asi is required", path, i)
+		}
+		if node.SID == "" {
+			return fmt.Errorf("%s.nodes[%d].sid is required", path, i)
+		}
+		if node.HP != nil && *node.HP != 1 {
+			return fmt.Errorf("%s.nodes[%d].hp must be 1. Got %d", path, i, *node.HP)
+		}
+		key := nodeKey{asi: node.ASI, sid: node.SID}
+		if first, ok := seen[key]; ok {
+			return fmt.Errorf("%s.nodes[%d] repeats nodes[%d] (asi %s, sid %s); the supply chain loops", path, i, first, node.ASI, node.SID)
+		}
+		seen[key] = i
+	}
+	return nil
+}
+
+// nodeKey identifies a seller in a supply chain.
+type nodeKey struct {
+	asi string
+	sid string
+}
+
+// containsNode returns true if the supply chain already has a node for the seller of the given node.
+func containsNode(sChain *openrtb2.SupplyChain, node openrtb2.SupplyChainNode) bool {
+	for _, n := range sChain.Nodes {
+		if n.ASI == node.ASI && n.SID == node.SID {
+			return true
+		}
+	}
+	return false
+}
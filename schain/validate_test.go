@@ -0,0 +1,74 @@
+package schain
+
+import (
+	"testing"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidate(t *testing.T) {
+	node := func(asi, sid string) openrtb2.SupplyChainNode {
+		return openrtb2.SupplyChainNode{ASI: asi, SID: sid, HP: openrtb2.Int8Ptr(1)}
+	}
+
+	testCases := []struct {
+		description string
+		sChain      *openrtb2.SupplyChain
+		expectedErr string
+	}{
+		{
+			description: "nil",
+			sChain:      nil,
+		},
+		{
+			description: "valid",
+			sChain:      &openrtb2.SupplyChain{Ver: "1.0", Complete: 1, Nodes: []openrtb2.SupplyChainNode{node("a.com", "1"), node("b.com", "1"), {ASI: "a.com", SID: "2"}}},
+		},
+		{
+			description: "no nodes",
+			sChain:      &openrtb2.SupplyChain{Ver: "1.0"},
+		},
+		{
+			description: "missing ver",
+			sChain:      &openrtb2.SupplyChain{Complete: 1},
+			expectedErr: "request.source.ext.schain.ver is required",
+		},
+		{
+			description: "invalid complete",
+			sChain:      &openrtb2.SupplyChain{Ver: "1.0", Complete: 2},
+			expectedErr: "request.source.ext.schain.complete must be 0 or 1. Got 2",
+		},
+		{
+			description: "missing asi",
+			sChain:      &openrtb2.SupplyChain{Ver: "1.0", Nodes: []openrtb2.SupplyChainNode{node("a.com", "1"), node("", "1")}},
+			expectedErr: "request.source.ext.schain.nodes[1].asi is required",
+		},
+		{
+			description: "missing sid",
+			sChain:      &openrtb2.SupplyChain{Ver: "1.0", Nodes: []openrtb2.SupplyChainNode{node("a.com", "")}},
+			expectedErr: "request.source.ext.schain.nodes[0].sid is required",
+		},
+		{
+			description: "invalid hp",
+			sChain:      &openrtb2.SupplyChain{Ver: "1.0", Nodes: []openrtb2.SupplyChainNode{{ASI: "a.com", SID: "1", HP: openrtb2.Int8Ptr(0)}}},
+			expectedErr: "request.source.ext.schain.nodes[0].hp must be 1. Got 0",
+		},
+		{
+			description: "loop",
+			sChain:      &openrtb2.SupplyChain{Ver: "1.0", Nodes: []openrtb2.SupplyChainNode{node("a.com", "1"), node("b.com", "1"), node("a.com", "1")}},
+			expectedErr: "request.source.ext.schain.nodes[2] repeats nodes[0] (asi a.com, sid 1); the supply chain loops",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			err := Validate(test.sChain, "request.source.ext.schain")
+			if test.expectedErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, test.expectedErr)
+			}
+		})
+	}
+}
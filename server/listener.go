@@ -5,7 +5,7 @@ import (
 	"strings"
 	"time"
 
-	"github.com/golang/glog"
+	"github.com/prebid/prebid-server/v2/logger"
 	"github.com/prebid/prebid-server/v2/metrics"
 )
 
@@ -31,7 +31,7 @@ func (l *monitorableConnection) Close() error {
 		// in the core Go libs: https://github.com/golang/go/issues/4373#issuecomment-347680321
 		errString := err.Error()
 		if !strings.Contains(errString, "use of closed network connection") {
-			glog.Errorf("Error closing connection: %s", errString)
+			logger.Errorf("Error closing connection: %s", errString)
 		}
 		l.metrics.RecordConnectionClose(false)
 	}
@@ -41,7 +41,7 @@ func (l *monitorableConnection) Close() error {
 func (ln *monitorableListener) Accept() (net.Conn, error) {
 	tc, err := ln.Listener.Accept()
 	if err != nil {
-		glog.Errorf("Error accepting connection: %v", err)
+		logger.Errorf("Error accepting connection: %v", err)
 		ln.metrics.RecordConnectionAccept(false)
 		return tc, err
 	}
@@ -1,13 +1,14 @@
 package server
 
 import (
+	"fmt"
 	"net/http"
 	"strconv"
 
-	"github.com/golang/glog"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 
 	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/logger"
 	metricsconfig "github.com/prebid/prebid-server/v2/metrics/config"
 )
 
@@ -15,7 +16,7 @@ func newPrometheusServer(cfg *config.Configuration, metrics *metricsconfig.Detai
 	proMetrics := metrics.PrometheusMetrics
 
 	if proMetrics == nil {
-		glog.Fatal("Prometheus metrics configured, but a Prometheus metrics engine was not found. Cannot set up a Prometheus listener.")
+		logger.Fatal("Prometheus metrics configured, but a Prometheus metrics engine was not found. Cannot set up a Prometheus listener.")
 	}
 	return &http.Server{
 		Addr: cfg.Host + ":" + strconv.Itoa(cfg.Metrics.Prometheus.Port),
@@ -30,5 +31,5 @@ func newPrometheusServer(cfg *config.Configuration, metrics *metricsconfig.Detai
 type loggerForPrometheus struct{}
 
 func (loggerForPrometheus) Println(v ...interface{}) {
-	glog.Warningln(v...)
+	logger.Warning(fmt.Sprintln(v...))
 }
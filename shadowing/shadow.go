@@ -0,0 +1,223 @@
+// Package shadowing copies a sample of the requests to the auction endpoint to a shadow host, so a release
+// candidate deployed there can be tried out on real traffic. The copies have the personal data in them
+// scrubbed, and are sent in the background. The shadow host's responses are ignored.
+package shadowing
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/logger"
+	"github.com/prebid/prebid-server/v2/metrics"
+	"github.com/prebid/prebid-server/v2/openrtb_ext"
+	"github.com/prebid/prebid-server/v2/privacy"
+	"github.com/prebid/prebid-server/v2/util/jsonutil"
+)
+
+// ShadowHeader is set on the copies, so the shadow host can tell them apart from requests made by clients.
+const ShadowHeader = "X-Prebid-Shadow"
+
+// forwardedHeaders are the headers of the original request which are sent along with the copy. Headers
+// which identify the user, like Cookie and X-Forwarded-For, are left out.
+var forwardedHeaders = []string{"Content-Type", "User-Agent", "Accept-Language", "Sec-GPC", "Dnt"}
+
+// Shadower copies requests to the shadow host. A nil Shadower copies nothing.
+type Shadower struct {
+	url         string
+	sampleRate  float64
+	timeout     time.Duration
+	maxBodySize int64
+	ipConf      privacy.IPConf
+
+	client *http.Client
+	me     metrics.MetricsEngine
+	random func() float64
+
+	queue   chan shadowRequest
+	workers sync.WaitGroup
+}
+
+type shadowRequest struct {
+	path            string
+	query           string
+	header          http.Header
+	body            []byte
+	contentEncoding string
+}
+
+// NewShadower starts the workers which send copies to the shadow host, or returns nil if shadowing is
+// disabled. Request bodies over maxBodySize bytes aren't copied. The IP addresses in the copies are
+// masked as ipConf says.
+func NewShadower(cfg config.TrafficShadowing, client *http.Client, maxBodySize int64, ipConf privacy.IPConf, me metrics.MetricsEngine) *Shadower {
+	if !cfg.Enabled {
+		return nil
+	}
+
+	shadower := &Shadower{
+		url:         strings.TrimSuffix(cfg.URL, "/"),
+		sampleRate:  cfg.SampleRate,
+		timeout:     time.Duration(cfg.TimeoutMs) * time.Millisecond,
+		maxBodySize: maxBodySize,
+		ipConf:      ipConf,
+		client:      client,
+		me:          me,
+		random:      rand.Float64,
+		queue:       make(chan shadowRequest, cfg.QueueSize),
+	}
+	for i := 0; i < cfg.Workers; i++ {
+		shadower.workers.Add(1)
+		go shadower.work()
+	}
+	return shadower
+}
+
+// Close sends the copies which are still queued, and stops the workers.
+func (s *Shadower) Close() {
+	if s == nil {
+		return
+	}
+	close(s.queue)
+	s.workers.Wait()
+}
+
+// Mirror returns a handle which queues a copy of a sample of the requests to be sent to the shadow host
+// at the same path, before handling them as usual.
+func (s *Shadower) Mirror(handle httprouter.Handle) httprouter.Handle {
+	if s == nil {
+		return handle
+	}
+
+	return func(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+		if s.random() < s.sampleRate {
+			s.copy(r)
+		}
+		handle(w, r, params)
+	}
+}
+
+// copy queues a copy of the request, restoring its body so it can be read again by the endpoint.
+func (s *Shadower) copy(r *http.Request) {
+	var body []byte
+	if r.Body != nil {
+		var reader io.Reader = r.Body
+		if s.maxBodySize > 0 {
+			reader = io.LimitReader(r.Body, s.maxBodySize+1)
+		}
+		read, _ := io.ReadAll(reader)
+		r.Body = readCloser{Reader: io.MultiReader(bytes.NewReader(read), r.Body), Closer: r.Body}
+		if s.maxBodySize > 0 && int64(len(read)) > s.maxBodySize {
+			// The endpoint will reject the request, so there's no use in sending it to the shadow host
+			return
+		}
+		body = read
+	}
+
+	header := make(http.Header, len(forwardedHeaders)+1)
+	for _, name := range forwardedHeaders {
+		if value := r.Header.Get(name); value != "" {
+			header.Set(name, value)
+		}
+	}
+	header.Set(ShadowHeader, "1")
+
+	request := shadowRequest{
+		path:            r.URL.Path,
+		query:           r.URL.RawQuery,
+		header:          header,
+		body:            body,
+		contentEncoding: r.Header.Get("Content-Encoding"),
+	}
+	select {
+	case s.queue <- request:
+	default:
+		s.me.RecordTrafficShadow(metrics.TrafficShadowDropped)
+	}
+}
+
+func (s *Shadower) work() {
+	defer s.workers.Done()
+	for request := range s.queue {
+		if err := s.send(request); err != nil {
+			logger.Debugf("Failed to send a copy of a request to the shadow host: %v", err)
+			s.me.RecordTrafficShadow(metrics.TrafficShadowFailed)
+			continue
+		}
+		s.me.RecordTrafficShadow(metrics.TrafficShadowSent)
+	}
+}
+
+func (s *Shadower) send(request shadowRequest) error {
+	body, err := s.scrub(request.body, request.contentEncoding)
+	if err != nil {
+		return err
+	}
+
+	target := s.url + request.path
+	if request.query != "" {
+		target += "?" + request.query
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header = request.header
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+	return nil
+}
+
+// scrub removes the user's IDs and first party data from the request, and masks its IP addresses and
+// location, as is done for bidders which mustn't get them.
+func (s *Shadower) scrub(body []byte, contentEncoding string) ([]byte, error) {
+	switch contentEncoding {
+	case "":
+	case "gzip":
+		reader, err := gzip.NewReader(bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		defer reader.Close()
+		if body, err = io.ReadAll(reader); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unsupported Content-Encoding %s", contentEncoding)
+	}
+
+	request := &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{}}
+	if err := jsonutil.UnmarshalValid(body, request.BidRequest); err != nil {
+		return nil, err
+	}
+	privacy.ScrubUserFPD(request)
+	if err := privacy.ScrubEIDs(request); err != nil {
+		return nil, err
+	}
+	privacy.ScrubGeoAndDeviceIP(request, s.ipConf)
+	if err := request.RebuildRequest(); err != nil {
+		return nil, err
+	}
+	return jsonutil.Marshal(request.BidRequest)
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
@@ -0,0 +1,228 @@
+package shadowing
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/metrics"
+	"github.com/prebid/prebid-server/v2/privacy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const testRequest = `{"id":"req-1","imp":[{"id":"imp-1","banner":{"w":300,"h":250}}],` +
+	`"device":{"ip":"1.2.3.4","geo":{"lat":12.345678,"lon":98.765432}},` +
+	`"user":{"id":"user-1","buyeruid":"buyer-1","yob":1980,"ext":{"eids":[{"source":"example.com","uids":[{"id":"eid-1"}]}]}}}`
+
+const scrubbedRequest = `{"id":"req-1","imp":[{"id":"imp-1","banner":{"w":300,"h":250}}],` +
+	`"device":{"ip":"1.2.3.0","geo":{"lat":12.35,"lon":98.77}},` +
+	`"user":{}}`
+
+type shadowedRequest struct {
+	method string
+	uri    string
+	header http.Header
+	body   []byte
+}
+
+func newShadowHost(t *testing.T) (*httptest.Server, chan shadowedRequest) {
+	received := make(chan shadowedRequest, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received <- shadowedRequest{method: r.Method, uri: r.RequestURI, header: r.Header, body: body}
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(server.Close)
+	return server, received
+}
+
+func newTestShadower(url string, me metrics.MetricsEngine) *Shadower {
+	cfg := config.TrafficShadowing{
+		Enabled:    true,
+		URL:        url,
+		SampleRate: 1,
+		TimeoutMs:  1000,
+		Workers:    1,
+		QueueSize:  10,
+	}
+	ipConf := privacy.IPConf{
+		IPV4: config.IPv4{AnonKeepBits: 24},
+		IPV6: config.IPv6{AnonKeepBits: 56},
+	}
+	return NewShadower(cfg, http.DefaultClient, 10000, ipConf, me)
+}
+
+func TestMirror(t *testing.T) {
+	server, received := newShadowHost(t)
+	me := &metrics.MetricsEngineMock{}
+	me.On("RecordTrafficShadow", metrics.TrafficShadowSent).Return()
+	shadower := newTestShadower(server.URL+"/", me)
+
+	var handled []byte
+	handle := shadower.Mirror(func(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+		handled, _ = io.ReadAll(r.Body)
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/openrtb2/auction?debug=1", bytes.NewBufferString(testRequest))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "test-agent")
+	req.Header.Set("Cookie", "uids=secret")
+	req.Header.Set("X-Forwarded-For", "1.2.3.4")
+	handle(httptest.NewRecorder(), req, nil)
+	shadower.Close()
+
+	assert.Equal(t, testRequest, string(handled), "The endpoint should read the original body")
+	require.Len(t, received, 1)
+	shadowed := <-received
+	assert.Equal(t, http.MethodPost, shadowed.method)
+	assert.Equal(t, "/openrtb2/auction?debug=1", shadowed.uri)
+	assert.JSONEq(t, scrubbedRequest, string(shadowed.body))
+	assert.Equal(t, "1", shadowed.header.Get(ShadowHeader))
+	assert.Equal(t, "application/json", shadowed.header.Get("Content-Type"))
+	assert.Equal(t, "test-agent", shadowed.header.Get("User-Agent"))
+	assert.Empty(t, shadowed.header.Get("Cookie"))
+	assert.Empty(t, shadowed.header.Get("X-Forwarded-For"))
+	me.AssertExpectations(t)
+}
+
+func TestMirrorGzip(t *testing.T) {
+	server, received := newShadowHost(t)
+	me := &metrics.MetricsEngineMock{}
+	me.On("RecordTrafficShadow", metrics.TrafficShadowSent).Return()
+	shadower := newTestShadower(server.URL, me)
+
+	var compressed bytes.Buffer
+	writer := gzip.NewWriter(&compressed)
+	writer.Write([]byte(testRequest))
+	writer.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/openrtb2/auction", bytes.NewReader(compressed.Bytes()))
+	req.Header.Set("Content-Encoding", "gzip")
+	handle := shadower.Mirror(func(http.ResponseWriter, *http.Request, httprouter.Params) {})
+	handle(httptest.NewRecorder(), req, nil)
+	shadower.Close()
+
+	require.Len(t, received, 1)
+	shadowed := <-received
+	assert.JSONEq(t, scrubbedRequest, string(shadowed.body))
+	assert.Empty(t, shadowed.header.Get("Content-Encoding"))
+	me.AssertExpectations(t)
+}
+
+func TestMirrorFailures(t *testing.T) {
+	testCases := []struct {
+		description string
+		body        string
+		url         string
+	}{
+		{
+			description: "malformed-request",
+			body:        `{"id":`,
+		},
+		{
+			description: "unreachable-shadow-host",
+			body:        testRequest,
+			url:         "http://127.0.0.1:0",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			url := test.url
+			if url == "" {
+				server, _ := newShadowHost(t)
+				url = server.URL
+			}
+			me := &metrics.MetricsEngineMock{}
+			me.On("RecordTrafficShadow", metrics.TrafficShadowFailed).Return()
+			shadower := newTestShadower(url, me)
+
+			handled := false
+			handle := shadower.Mirror(func(http.ResponseWriter, *http.Request, httprouter.Params) {
+				handled = true
+			})
+			handle(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/openrtb2/auction", bytes.NewBufferString(test.body)), nil)
+			shadower.Close()
+
+			assert.True(t, handled)
+			me.AssertExpectations(t)
+		})
+	}
+}
+
+func TestMirrorSampling(t *testing.T) {
+	server, received := newShadowHost(t)
+	me := &metrics.MetricsEngineMock{}
+	me.On("RecordTrafficShadow", metrics.TrafficShadowSent).Return()
+	shadower := newTestShadower(server.URL, me)
+	shadower.sampleRate = 0.5
+
+	samples := []float64{0.7, 0.2, 0.5}
+	shadower.random = func() float64 {
+		sample := samples[0]
+		samples = samples[1:]
+		return sample
+	}
+	handle := shadower.Mirror(func(http.ResponseWriter, *http.Request, httprouter.Params) {})
+	for i := 0; i < 3; i++ {
+		handle(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/openrtb2/auction", bytes.NewBufferString(testRequest)), nil)
+	}
+	shadower.Close()
+
+	assert.Len(t, received, 1)
+	me.AssertNumberOfCalls(t, "RecordTrafficShadow", 1)
+}
+
+func TestMirrorQueueFull(t *testing.T) {
+	me := &metrics.MetricsEngineMock{}
+	me.On("RecordTrafficShadow", mock.Anything).Return()
+	shadower := newTestShadower("http://shadow.example.com", me)
+	// Stop the workers, so nothing is taken off the queue
+	close(shadower.queue)
+	shadower.workers.Wait()
+	shadower.queue = make(chan shadowRequest, 1)
+
+	handle := shadower.Mirror(func(http.ResponseWriter, *http.Request, httprouter.Params) {})
+	for i := 0; i < 2; i++ {
+		handle(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/openrtb2/auction", bytes.NewBufferString(testRequest)), nil)
+	}
+
+	assert.Len(t, shadower.queue, 1)
+	me.AssertCalled(t, "RecordTrafficShadow", metrics.TrafficShadowDropped)
+	me.AssertNumberOfCalls(t, "RecordTrafficShadow", 1)
+}
+
+func TestMirrorOversizedBody(t *testing.T) {
+	me := &metrics.MetricsEngineMock{}
+	shadower := newTestShadower("http://shadow.example.com", me)
+	shadower.maxBodySize = 10
+	defer shadower.Close()
+
+	var handled []byte
+	handle := shadower.Mirror(func(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+		handled, _ = io.ReadAll(r.Body)
+	})
+	handle(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/openrtb2/auction", bytes.NewBufferString(testRequest)), nil)
+
+	assert.Equal(t, testRequest, string(handled), "The endpoint should read the whole body")
+	assert.Empty(t, shadower.queue)
+}
+
+func TestNilShadower(t *testing.T) {
+	shadower := NewShadower(config.TrafficShadowing{}, http.DefaultClient, 0, privacy.IPConf{}, &metrics.MetricsEngineMock{})
+	assert.Nil(t, shadower)
+
+	handled := false
+	handle := shadower.Mirror(func(http.ResponseWriter, *http.Request, httprouter.Params) { handled = true })
+	handle(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/openrtb2/auction", nil), nil)
+	shadower.Close()
+
+	assert.True(t, handled)
+}
@@ -0,0 +1,205 @@
+// Package sizeresolution resolves the formats of banner imps from the device's screen: interstitials which
+// ask for it in device.ext.prebid.interstitial and, if the account enables it, banners without a size. The
+// formats come from the account's size catalog, or the host's interstitial sizes, in order of preference,
+// and may include fluid sizes which scale with the screen.
+package sizeresolution
+
+import (
+	"fmt"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/errortypes"
+	"github.com/prebid/prebid-server/v2/openrtb_ext"
+)
+
+const defaultMaxFormats = 10
+
+const (
+	orientationPortrait  = "portrait"
+	orientationLandscape = "landscape"
+)
+
+const (
+	sourceInterstitial = "interstitial"
+	sourceDevice       = "device"
+)
+
+// hostCatalog is the host's interstitial sizes, used by accounts without a catalog of their own.
+var hostCatalog = func() []config.AccountSize {
+	catalog := make([]config.AccountSize, 0, len(config.ResolvedInterstitialSizes))
+	for _, size := range config.ResolvedInterstitialSizes {
+		catalog = append(catalog, config.AccountSize{W: int64(size.Width), H: int64(size.Height)})
+	}
+	return catalog
+}()
+
+// Resolution is the formats resolved for the imps of a request.
+type Resolution struct {
+	imps []openrtb_ext.ExtResponseResolvedSizes
+}
+
+// Resolve replaces the formats of the request's banner imps which are resolved from the device's screen.
+func Resolve(req *openrtb_ext.RequestWrapper, cfg config.AccountSizeResolution) (*Resolution, error) {
+	r := &Resolution{}
+	var interstitial *openrtb_ext.ExtDeviceInt
+	var deviceExtRead bool
+	for _, imp := range req.GetImp() {
+		if imp.Banner == nil {
+			// sizes are only resolved for banners
+			continue
+		}
+		if imp.Instl == 1 && !deviceExtRead {
+			deviceExtRead = true
+			if req.Device != nil && req.Device.Ext != nil {
+				deviceExt, err := req.GetDeviceExt()
+				if err != nil {
+					return nil, err
+				}
+				if prebid := deviceExt.GetPrebid(); prebid != nil {
+					interstitial = prebid.Interstitial
+				}
+			}
+		}
+
+		var err error
+		switch {
+		case imp.Instl == 1 && interstitial != nil:
+			err = r.resolveInterstitial(imp, interstitial, req.Device, cfg)
+		case cfg.Enabled && hasNoSize(imp.Banner):
+			err = r.resolveDevice(imp, req.Device, cfg)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+func (r *Resolution) resolveInterstitial(imp *openrtb_ext.ImpWrapper, interstitial *openrtb_ext.ExtDeviceInt, device *openrtb2.Device, cfg config.AccountSizeResolution) error {
+	var maxWidth, maxHeight int64
+	if len(imp.Banner.Format) > 0 {
+		maxWidth = imp.Banner.Format[0].W
+		maxHeight = imp.Banner.Format[0].H
+	}
+	if maxWidth < 2 && maxHeight < 2 {
+		// This catches size 1x1 as "use device size"
+		if device == nil {
+			return &errortypes.BadInput{Message: fmt.Sprintf("Unable to read max interstitial size for Imp id=%s (No Device and no Format objects)", imp.ID)}
+		}
+		maxWidth = device.W
+		maxHeight = device.H
+	}
+	minWidth := (maxWidth * interstitial.MinWidthPerc) / 100
+	minHeight := (maxHeight * interstitial.MinHeightPerc) / 100
+
+	resolved := r.resolve(imp, sourceInterstitial, orientationOf(device), minWidth, maxWidth, minHeight, maxHeight, cfg)
+	if len(resolved) == 0 {
+		return &errortypes.BadInput{Message: fmt.Sprintf("Unable to set interstitial size list for Imp id=%s (No valid sizes between %dx%d and %dx%d)", imp.ID, minWidth, minHeight, maxWidth, maxHeight)}
+	}
+	return nil
+}
+
+func (r *Resolution) resolveDevice(imp *openrtb_ext.ImpWrapper, device *openrtb2.Device, cfg config.AccountSizeResolution) error {
+	if device == nil || device.W <= 0 || device.H <= 0 {
+		return &errortypes.BadInput{Message: fmt.Sprintf("Unable to resolve sizes for Imp id=%s (No device size and no Format objects)", imp.ID)}
+	}
+
+	resolved := r.resolve(imp, sourceDevice, orientationOf(device), 0, device.W, 0, device.H, cfg)
+	if len(resolved) == 0 {
+		return &errortypes.BadInput{Message: fmt.Sprintf("Unable to set size list for Imp id=%s (No valid sizes within %dx%d)", imp.ID, device.W, device.H)}
+	}
+	return nil
+}
+
+// resolve sets the formats of the imp to the catalog sizes between the min and max sizes, followed by the
+// adaptive sizes which fit the max size, and records them for debug output.
+func (r *Resolution) resolve(imp *openrtb_ext.ImpWrapper, source, orientation string, minWidth, maxWidth, minHeight, maxHeight int64, cfg config.AccountSizeResolution) []openrtb2.Format {
+	maxFormats := cfg.MaxFormats
+	if maxFormats <= 0 {
+		maxFormats = defaultMaxFormats
+	}
+	catalog := cfg.Catalog
+	if len(catalog) == 0 {
+		catalog = hostCatalog
+	}
+
+	formats := make([]openrtb2.Format, 0, maxFormats+len(cfg.Adaptive))
+	for _, size := range catalog {
+		if len(formats) >= maxFormats {
+			// we have enough sizes
+			break
+		}
+		if fitsOrientation(size.Orientation, orientation) && size.W >= minWidth && size.W <= maxWidth && size.H >= minHeight && size.H <= maxHeight {
+			formats = append(formats, openrtb2.Format{W: size.W, H: size.H})
+		}
+	}
+	for _, size := range cfg.Adaptive {
+		// accounts from the stored account backends aren't validated, so sizes without ratios are skipped
+		if size.WRatio <= 0 || size.HRatio <= 0 {
+			continue
+		}
+		// the smallest the fluid size can be has to fit the screen
+		if fitsOrientation(size.Orientation, orientation) && size.WMin <= maxWidth && size.WMin*size.HRatio/size.WRatio <= maxHeight {
+			formats = append(formats, openrtb2.Format{WRatio: size.WRatio, HRatio: size.HRatio, WMin: size.WMin})
+		}
+	}
+	if len(formats) == 0 {
+		return nil
+	}
+
+	imp.Banner.Format = formats
+	r.imps = append(r.imps, openrtb_ext.ExtResponseResolvedSizes{
+		ImpID:       imp.ID,
+		Source:      source,
+		Orientation: orientation,
+		MinW:        minWidth,
+		MinH:        minHeight,
+		MaxW:        maxWidth,
+		MaxH:        maxHeight,
+		Format:      formats,
+	})
+	return formats
+}
+
+// Debug returns the formats resolved for each imp, for the debug output of the response.
+func (r *Resolution) Debug() []openrtb_ext.ExtResponseResolvedSizes {
+	if r == nil {
+		return nil
+	}
+	return r.imps
+}
+
+// hasNoSize returns true if the banner has no size, or only the 1x1 format, which asks for the device size.
+func hasNoSize(banner *openrtb2.Banner) bool {
+	if banner.W != nil && banner.H != nil && *banner.W > 0 && *banner.H > 0 {
+		return false
+	}
+	switch len(banner.Format) {
+	case 0:
+		return true
+	case 1:
+		format := banner.Format[0]
+		return format.W < 2 && format.H < 2 && format.WRatio == 0
+	default:
+		return false
+	}
+}
+
+// orientationOf returns the orientation of the device's screen, or nothing if it's square or unknown.
+func orientationOf(device *openrtb2.Device) string {
+	switch {
+	case device == nil:
+		return ""
+	case device.W > device.H:
+		return orientationLandscape
+	case device.H > device.W:
+		return orientationPortrait
+	default:
+		return ""
+	}
+}
+
+func fitsOrientation(sizeOrientation, deviceOrientation string) bool {
+	return sizeOrientation == "" || deviceOrientation == "" || sizeOrientation == deviceOrientation
+}
@@ -0,0 +1,225 @@
+package sizeresolution
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/errortypes"
+	"github.com/prebid/prebid-server/v2/openrtb_ext"
+	"github.com/prebid/prebid-server/v2/util/ptrutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInterstitial(t *testing.T) {
+	request := &openrtb2.BidRequest{
+		ID: "some-id",
+		Imp: []openrtb2.Imp{
+			{
+				ID:     "my-imp-id",
+				Banner: &openrtb2.Banner{Format: []openrtb2.Format{{W: 300, H: 600}}},
+				Instl:  1,
+				Ext:    json.RawMessage(`{"appnexus": {"placementId": 12883451}}`),
+			},
+		},
+		Device: &openrtb2.Device{
+			H:   640,
+			W:   320,
+			Ext: json.RawMessage(`{"prebid": {"interstitial": {"minwidthperc": 60, "minheightperc": 60}}}`),
+		},
+	}
+	req := &openrtb_ext.RequestWrapper{BidRequest: request}
+
+	resolution, err := Resolve(req, config.AccountSizeResolution{})
+	require.NoError(t, err)
+	require.NoError(t, req.RebuildRequest())
+
+	targetFormat := []openrtb2.Format{
+		{W: 300, H: 600},
+		{W: 250, H: 600},
+		{W: 300, H: 480},
+		{W: 180, H: 500},
+		{W: 300, H: 500},
+		{W: 300, H: 431},
+		{W: 300, H: 430},
+		{W: 200, H: 600},
+		{W: 202, H: 600},
+		{W: 300, H: 360},
+	}
+	assert.Equal(t, targetFormat, request.Imp[0].Banner.Format)
+	assert.Equal(t, []openrtb_ext.ExtResponseResolvedSizes{{
+		ImpID:       "my-imp-id",
+		Source:      "interstitial",
+		Orientation: "portrait",
+		MinW:        180,
+		MinH:        360,
+		MaxW:        300,
+		MaxH:        600,
+		Format:      targetFormat,
+	}}, resolution.Debug())
+}
+
+func TestInterstitialWithoutPrebidDeviceExt(t *testing.T) {
+	request := &openrtb2.BidRequest{
+		ID: "some-id",
+		Imp: []openrtb2.Imp{
+			{
+				ID:     "my-imp-id",
+				Banner: &openrtb2.Banner{Format: []openrtb2.Format{{W: 300, H: 600}}},
+				Instl:  1,
+				Ext:    json.RawMessage(`{"appnexus": {"placementId": 12883451}}`),
+			},
+		},
+		Device: &openrtb2.Device{
+			H:   640,
+			W:   320,
+			Ext: json.RawMessage(`{"field": 1}`),
+		},
+	}
+
+	resolution, err := Resolve(&openrtb_ext.RequestWrapper{BidRequest: request}, config.AccountSizeResolution{})
+	require.NoError(t, err)
+
+	assert.Equal(t, []openrtb2.Format{{W: 300, H: 600}}, request.Imp[0].Banner.Format)
+	assert.Empty(t, resolution.Debug())
+}
+
+func TestResolve(t *testing.T) {
+	interstitialExt := json.RawMessage(`{"prebid":{"interstitial":{"minwidthperc":50,"minheightperc":50}}}`)
+	catalog := []config.AccountSize{
+		{W: 320, H: 480, Orientation: "portrait"},
+		{W: 480, H: 320, Orientation: "landscape"},
+		{W: 300, H: 250},
+		{W: 320, H: 50},
+		{W: 728, H: 90},
+	}
+
+	testCases := []struct {
+		description     string
+		cfg             config.AccountSizeResolution
+		imp             openrtb2.Imp
+		device          *openrtb2.Device
+		expectedFormats []openrtb2.Format
+		expectedError   error
+	}{
+		{
+			description:     "interstitial-portrait",
+			cfg:             config.AccountSizeResolution{Catalog: catalog},
+			imp:             openrtb2.Imp{ID: "1", Instl: 1, Banner: &openrtb2.Banner{Format: []openrtb2.Format{{W: 1, H: 1}}}},
+			device:          &openrtb2.Device{W: 360, H: 640, Ext: interstitialExt},
+			expectedFormats: []openrtb2.Format{{W: 320, H: 480}},
+		},
+		{
+			description:     "interstitial-landscape",
+			cfg:             config.AccountSizeResolution{Catalog: catalog},
+			imp:             openrtb2.Imp{ID: "1", Instl: 1, Banner: &openrtb2.Banner{}},
+			device:          &openrtb2.Device{W: 640, H: 360, Ext: interstitialExt},
+			expectedFormats: []openrtb2.Format{{W: 480, H: 320}},
+		},
+		{
+			description:   "interstitial-no-sizes",
+			cfg:           config.AccountSizeResolution{Catalog: catalog},
+			imp:           openrtb2.Imp{ID: "1", Instl: 1, Banner: &openrtb2.Banner{}},
+			device:        &openrtb2.Device{W: 200, H: 200, Ext: interstitialExt},
+			expectedError: &errortypes.BadInput{Message: "Unable to set interstitial size list for Imp id=1 (No valid sizes between 100x100 and 200x200)"},
+		},
+		{
+			description:     "device-disabled",
+			imp:             openrtb2.Imp{ID: "1", Banner: &openrtb2.Banner{}},
+			device:          &openrtb2.Device{W: 360, H: 640},
+			expectedFormats: nil,
+		},
+		{
+			description:     "device",
+			cfg:             config.AccountSizeResolution{Enabled: true, Catalog: catalog, MaxFormats: 2},
+			imp:             openrtb2.Imp{ID: "1", Banner: &openrtb2.Banner{Format: []openrtb2.Format{{W: 1, H: 1}}}},
+			device:          &openrtb2.Device{W: 360, H: 640},
+			expectedFormats: []openrtb2.Format{{W: 320, H: 480}, {W: 300, H: 250}},
+		},
+		{
+			description: "device-adaptive",
+			cfg: config.AccountSizeResolution{
+				Enabled:  true,
+				Catalog:  catalog,
+				Adaptive: []config.AccountAdaptiveSize{{WRatio: 32, HRatio: 5, WMin: 320}, {WRatio: 16, HRatio: 9, WMin: 640, Orientation: "landscape"}},
+			},
+			imp:             openrtb2.Imp{ID: "1", Banner: &openrtb2.Banner{}},
+			device:          &openrtb2.Device{W: 360, H: 640},
+			expectedFormats: []openrtb2.Format{{W: 320, H: 480}, {W: 300, H: 250}, {W: 320, H: 50}, {WRatio: 32, HRatio: 5, WMin: 320}},
+		},
+		{
+			description: "device-adaptive-invalid-ratio",
+			cfg: config.AccountSizeResolution{
+				Enabled:  true,
+				Catalog:  catalog,
+				Adaptive: []config.AccountAdaptiveSize{{WRatio: 0, HRatio: 5, WMin: 320}, {WRatio: 32, HRatio: -1, WMin: 320}},
+			},
+			imp:             openrtb2.Imp{ID: "1", Banner: &openrtb2.Banner{}},
+			device:          &openrtb2.Device{W: 360, H: 640},
+			expectedFormats: []openrtb2.Format{{W: 320, H: 480}, {W: 300, H: 250}, {W: 320, H: 50}},
+		},
+		{
+			description:     "device-host-sizes",
+			cfg:             config.AccountSizeResolution{Enabled: true, MaxFormats: 3},
+			imp:             openrtb2.Imp{ID: "1", Banner: &openrtb2.Banner{}},
+			device:          &openrtb2.Device{W: 1024, H: 768},
+			expectedFormats: []openrtb2.Format{{W: 300, H: 250}, {W: 728, H: 90}, {W: 160, H: 600}},
+		},
+		{
+			description:     "device-banner-has-size",
+			cfg:             config.AccountSizeResolution{Enabled: true, Catalog: catalog},
+			imp:             openrtb2.Imp{ID: "1", Banner: &openrtb2.Banner{W: ptrutil.ToPtr[int64](300), H: ptrutil.ToPtr[int64](250)}},
+			device:          &openrtb2.Device{W: 360, H: 640},
+			expectedFormats: nil,
+		},
+		{
+			description:   "device-without-size",
+			cfg:           config.AccountSizeResolution{Enabled: true, Catalog: catalog},
+			imp:           openrtb2.Imp{ID: "1", Banner: &openrtb2.Banner{}},
+			device:        &openrtb2.Device{},
+			expectedError: &errortypes.BadInput{Message: "Unable to resolve sizes for Imp id=1 (No device size and no Format objects)"},
+		},
+		{
+			description:   "device-no-sizes",
+			cfg:           config.AccountSizeResolution{Enabled: true, Catalog: catalog},
+			imp:           openrtb2.Imp{ID: "1", Banner: &openrtb2.Banner{}},
+			device:        &openrtb2.Device{W: 200, H: 40},
+			expectedError: &errortypes.BadInput{Message: "Unable to set size list for Imp id=1 (No valid sizes within 200x40)"},
+		},
+		{
+			description:     "video",
+			cfg:             config.AccountSizeResolution{Enabled: true, Catalog: catalog},
+			imp:             openrtb2.Imp{ID: "1", Video: &openrtb2.Video{}},
+			device:          &openrtb2.Device{W: 360, H: 640},
+			expectedFormats: nil,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			req := &openrtb_ext.RequestWrapper{BidRequest: &openrtb2.BidRequest{Imp: []openrtb2.Imp{test.imp}, Device: test.device}}
+
+			resolution, err := Resolve(req, test.cfg)
+
+			assert.Equal(t, test.expectedError, err)
+			if test.expectedError != nil {
+				return
+			}
+			require.NoError(t, req.RebuildRequest())
+			if test.expectedFormats == nil {
+				assert.Empty(t, resolution.Debug())
+				return
+			}
+			assert.Equal(t, test.expectedFormats, req.Imp[0].Banner.Format)
+			require.Len(t, resolution.Debug(), 1)
+			assert.Equal(t, test.expectedFormats, resolution.Debug()[0].Format)
+		})
+	}
+}
+
+func TestDebugNilResolution(t *testing.T) {
+	var resolution *Resolution
+	assert.Nil(t, resolution.Debug())
+}
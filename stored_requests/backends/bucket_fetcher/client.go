@@ -0,0 +1,263 @@
+package bucket_fetcher
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/prebid/prebid-server/v2/config"
+)
+
+// emptyPayloadHash is the hex encoded SHA-256 of an empty body, which is all the requests of the client send
+const emptyPayloadHash = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+// errObjectNotFound is returned for an object which was deleted between listing and getting it
+var errObjectNotFound = errors.New("object not found")
+
+// object is an object listed in a bucket
+type object struct {
+	Key  string `xml:"Key"`
+	ETag string `xml:"ETag"`
+}
+
+// objectStore is the part of a bucket the fetcher uses
+type objectStore interface {
+	// List lists the objects whose keys start with the prefix
+	List(ctx context.Context, prefix string) ([]object, error)
+	// Get returns the contents of the object, or errObjectNotFound
+	Get(ctx context.Context, key string) ([]byte, error)
+}
+
+// s3Client reads a bucket through the S3 REST API, which GCS implements for interoperability with S3 tools.
+// Requests are signed with AWS Signature Version 4 if it has credentials.
+type s3Client struct {
+	httpClient      *http.Client
+	bucketURL       *url.URL
+	region          string
+	accessKeyID     string
+	secretAccessKey string
+	sessionToken    string
+	now             func() time.Time
+}
+
+func newS3Client(httpClient *http.Client, cfg config.BucketFetcherConfig) (*s3Client, error) {
+	var bucketURL string
+	region := cfg.Region
+	switch {
+	case cfg.Endpoint != "":
+		bucketURL = strings.TrimSuffix(cfg.Endpoint, "/") + "/" + url.PathEscape(cfg.Name)
+	case cfg.Provider == config.BucketProviderGCS:
+		bucketURL = "https://storage.googleapis.com/" + url.PathEscape(cfg.Name)
+	default:
+		bucketURL = fmt.Sprintf("https://%s.s3.%s.amazonaws.com", cfg.Name, cfg.Region)
+	}
+	if region == "" {
+		// GCS ignores the region of the signature, but it must have one
+		region = "auto"
+	}
+
+	parsed, err := url.Parse(bucketURL)
+	if err != nil {
+		return nil, err
+	}
+	return &s3Client{
+		httpClient:      httpClient,
+		bucketURL:       parsed,
+		region:          region,
+		accessKeyID:     cfg.AccessKeyID,
+		secretAccessKey: cfg.SecretAccessKey,
+		sessionToken:    cfg.SessionToken,
+		now:             time.Now,
+	}, nil
+}
+
+type listBucketResult struct {
+	Contents              []object `xml:"Contents"`
+	IsTruncated           bool     `xml:"IsTruncated"`
+	NextContinuationToken string   `xml:"NextContinuationToken"`
+}
+
+func (c *s3Client) List(ctx context.Context, prefix string) ([]object, error) {
+	var objects []object
+	continuationToken := ""
+	for {
+		query := url.Values{"list-type": {"2"}, "prefix": {prefix}}
+		if continuationToken != "" {
+			query.Set("continuation-token", continuationToken)
+		}
+		body, err := c.do(ctx, "", query)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list objects with prefix %q: %v", prefix, err)
+		}
+
+		var result listBucketResult
+		if err := xml.Unmarshal(body, &result); err != nil {
+			return nil, fmt.Errorf("failed to parse the objects with prefix %q: %v", prefix, err)
+		}
+		objects = append(objects, result.Contents...)
+		if !result.IsTruncated || result.NextContinuationToken == "" {
+			return objects, nil
+		}
+		continuationToken = result.NextContinuationToken
+	}
+}
+
+func (c *s3Client) Get(ctx context.Context, key string) ([]byte, error) {
+	body, err := c.do(ctx, key, nil)
+	if err != nil && !errors.Is(err, errObjectNotFound) {
+		return nil, fmt.Errorf("failed to get object %s: %v", key, err)
+	}
+	return body, err
+}
+
+// do sends a GET for the key, or for the bucket itself if the key is empty, and returns the body of the response.
+func (c *s3Client) do(ctx context.Context, key string, query url.Values) ([]byte, error) {
+	requestURL := *c.bucketURL
+	if key != "" {
+		requestURL.Path = strings.TrimSuffix(requestURL.Path, "/") + "/" + key
+		requestURL.RawPath = escapePath(requestURL.Path)
+	}
+	requestURL.RawQuery = canonicalQuery(query)
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, requestURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	if c.accessKeyID != "" {
+		c.sign(httpReq)
+	}
+
+	httpResp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer httpResp.Body.Close()
+
+	body, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case httpResp.StatusCode == http.StatusNotFound && key != "":
+		return nil, errObjectNotFound
+	case httpResp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("unexpected response status %d: %s", httpResp.StatusCode, body)
+	}
+	return body, nil
+}
+
+// sign adds the AWS Signature Version 4 headers to a request without a body.
+func (c *s3Client) sign(httpReq *http.Request) {
+	now := c.now().UTC()
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	scope := date + "/" + c.region + "/s3/aws4_request"
+
+	httpReq.Header.Set("X-Amz-Content-Sha256", emptyPayloadHash)
+	httpReq.Header.Set("X-Amz-Date", amzDate)
+	headers := map[string]string{
+		"host":                 httpReq.URL.Host,
+		"x-amz-content-sha256": emptyPayloadHash,
+		"x-amz-date":           amzDate,
+	}
+	if c.sessionToken != "" {
+		httpReq.Header.Set("X-Amz-Security-Token", c.sessionToken)
+		headers["x-amz-security-token"] = c.sessionToken
+	}
+
+	headerNames := make([]string, 0, len(headers))
+	for name := range headers {
+		headerNames = append(headerNames, name)
+	}
+	sort.Strings(headerNames)
+	var canonicalHeaders strings.Builder
+	for _, name := range headerNames {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(headerNames, ";")
+
+	canonicalRequest := strings.Join([]string{
+		httpReq.Method,
+		escapePath(httpReq.URL.Path),
+		httpReq.URL.RawQuery,
+		canonicalHeaders.String(),
+		signedHeaders,
+		emptyPayloadHash,
+	}, "\n")
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		scope,
+		hexSHA256(canonicalRequest),
+	}, "\n")
+
+	signingKey := hmacSHA256([]byte("AWS4"+c.secretAccessKey), date)
+	signingKey = hmacSHA256(signingKey, c.region)
+	signingKey = hmacSHA256(signingKey, "s3")
+	signingKey = hmacSHA256(signingKey, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(signingKey, stringToSign))
+
+	httpReq.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s", c.accessKeyID, scope, signedHeaders, signature))
+}
+
+// canonicalQuery encodes the query sorted by key, with the escaping Signature Version 4 expects, so the
+// same string can be sent and signed.
+func canonicalQuery(query url.Values) string {
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, key := range keys {
+		for _, value := range query[key] {
+			pairs = append(pairs, escape(key, true)+"="+escape(value, true))
+		}
+	}
+	return strings.Join(pairs, "&")
+}
+
+func escapePath(path string) string {
+	if path == "" {
+		return "/"
+	}
+	return escape(path, false)
+}
+
+// escape percent-encodes everything but the unreserved characters of RFC 3986, and slashes unless escapeSlash.
+func escape(s string, escapeSlash bool) string {
+	var escaped strings.Builder
+	for _, b := range []byte(s) {
+		switch {
+		case 'A' <= b && b <= 'Z', 'a' <= b && b <= 'z', '0' <= b && b <= '9', b == '-', b == '_', b == '.', b == '~':
+			escaped.WriteByte(b)
+		case b == '/' && !escapeSlash:
+			escaped.WriteByte(b)
+		default:
+			fmt.Fprintf(&escaped, "%%%02X", b)
+		}
+	}
+	return escaped.String()
+}
+
+func hexSHA256(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,255 @@
+package bucket_fetcher
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	jsonpatch "gopkg.in/evanphx/json-patch.v4"
+
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/stored_requests"
+	"github.com/prebid/prebid-server/v2/stored_requests/events"
+)
+
+// The "directories" of the bucket each kind of data is in
+const (
+	requestsDirectory  = "stored_requests"
+	impsDirectory      = "stored_imps"
+	responsesDirectory = "stored_responses"
+	accountsDirectory  = "accounts"
+)
+
+// NewFetcher syncs the data in the bucket of the config, and returns a Fetcher which serves it from memory.
+//
+// The data is synced from JSON files named like the files of the file fetcher, under the prefix of the config:
+//
+//	{prefix}/stored_requests/{id}.json
+//	{prefix}/stored_imps/{id}.json
+//	{prefix}/stored_responses/{id}.json
+//	{prefix}/accounts/{id}.json
+//
+// Refresh syncs it again. Only the files whose ETag changed since the last sync are downloaded.
+func NewFetcher(client *http.Client, cfg config.BucketFetcherConfig) (*BucketFetcher, error) {
+	store, err := newS3Client(client, cfg)
+	if err != nil {
+		return nil, err
+	}
+	fetcher := newFetcher(store, cfg.Prefix, cfg.TimeoutDuration())
+	if _, err := fetcher.Refresh(); err != nil {
+		return nil, err
+	}
+	return fetcher, nil
+}
+
+func newFetcher(store objectStore, prefix string, timeout time.Duration) *BucketFetcher {
+	if prefix != "" && !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+	return &BucketFetcher{
+		store:   store,
+		prefix:  prefix,
+		timeout: timeout,
+		files:   make(map[string]file),
+		data:    make(map[string]map[string]json.RawMessage),
+		etags:   make(map[string]map[string]string),
+	}
+}
+
+// file is the last synced version of an object
+type file struct {
+	directory string
+	id        string
+	etag      string
+	data      json.RawMessage
+}
+
+// BucketFetcher fetches Stored Data synced from a bucket. This should be instantiated through the NewFetcher() function.
+type BucketFetcher struct {
+	store   objectStore
+	prefix  string
+	timeout time.Duration
+
+	// syncLock keeps syncs from running at the same time
+	syncLock sync.Mutex
+	// files are the synced objects by key. They're only used by syncs.
+	files map[string]file
+
+	lock sync.RWMutex
+	// data is the data of the files by directory, then ID
+	data map[string]map[string]json.RawMessage
+	// etags are the ETags of the files by directory, then ID, which are the versions of their data
+	etags map[string]map[string]string
+}
+
+// Refresh syncs the bucket, and returns an invalidation of the data which was changed or removed, so the
+// caches in front of the fetcher may drop their copies. The data is left as it was if the sync fails.
+func (fetcher *BucketFetcher) Refresh() (events.Invalidation, error) {
+	fetcher.syncLock.Lock()
+	defer fetcher.syncLock.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), fetcher.timeout)
+	defer cancel()
+
+	objects, err := fetcher.store.List(ctx, fetcher.prefix)
+	if err != nil {
+		return events.Invalidation{}, err
+	}
+
+	files := make(map[string]file, len(objects))
+	for _, object := range objects {
+		directory, id, ok := fetcher.parseKey(object.Key)
+		if !ok {
+			continue
+		}
+		if synced, ok := fetcher.files[object.Key]; ok && synced.etag == object.ETag {
+			files[object.Key] = synced
+			continue
+		}
+
+		data, err := fetcher.store.Get(ctx, object.Key)
+		if errors.Is(err, errObjectNotFound) {
+			continue
+		}
+		if err != nil {
+			return events.Invalidation{}, err
+		}
+		files[object.Key] = file{directory: directory, id: id, etag: object.ETag, data: data}
+	}
+
+	data := make(map[string]map[string]json.RawMessage)
+	etags := make(map[string]map[string]string)
+	for _, f := range files {
+		if data[f.directory] == nil {
+			data[f.directory] = make(map[string]json.RawMessage)
+			etags[f.directory] = make(map[string]string)
+		}
+		data[f.directory][f.id] = f.data
+		etags[f.directory][f.id] = f.etag
+	}
+
+	fetcher.lock.Lock()
+	invalidation := events.Invalidation{
+		Requests:  changedIDs(fetcher.data[requestsDirectory], data[requestsDirectory]),
+		Imps:      changedIDs(fetcher.data[impsDirectory], data[impsDirectory]),
+		Responses: changedIDs(fetcher.data[responsesDirectory], data[responsesDirectory]),
+		Accounts:  changedIDs(fetcher.data[accountsDirectory], data[accountsDirectory]),
+	}
+	fetcher.data = data
+	fetcher.etags = etags
+	fetcher.lock.Unlock()
+
+	fetcher.files = files
+	return invalidation, nil
+}
+
+// parseKey returns the directory and ID of the data at the key, if it's data.
+func (fetcher *BucketFetcher) parseKey(key string) (directory, id string, ok bool) {
+	directory, name, ok := strings.Cut(strings.TrimPrefix(key, fetcher.prefix), "/")
+	if !ok || !strings.HasSuffix(name, ".json") || strings.Contains(name, "/") {
+		return "", "", false
+	}
+	switch directory {
+	case requestsDirectory, impsDirectory, responsesDirectory, accountsDirectory:
+		return directory, strings.TrimSuffix(name, ".json"), true
+	}
+	return "", "", false
+}
+
+// changedIDs returns the IDs present in before which are either missing from after or have different contents.
+func changedIDs(before, after map[string]json.RawMessage) []string {
+	var ids []string
+	for id, data := range before {
+		if newData, ok := after[id]; !ok || !bytes.Equal(data, newData) {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+func (fetcher *BucketFetcher) FetchRequests(ctx context.Context, requestIDs []string, impIDs []string) (map[string]json.RawMessage, map[string]json.RawMessage, []error) {
+	fetcher.lock.RLock()
+	defer fetcher.lock.RUnlock()
+
+	requestData, errs := fetcher.get(requestsDirectory, "Request", requestIDs, nil)
+	impData, errs := fetcher.get(impsDirectory, "Imp", impIDs, errs)
+	return requestData, impData, errs
+}
+
+// FetchRequestVersions returns the ETags of the files of the stored requests and imps.
+func (fetcher *BucketFetcher) FetchRequestVersions(ctx context.Context, requestIDs []string, impIDs []string) (map[string]string, map[string]string) {
+	fetcher.lock.RLock()
+	defer fetcher.lock.RUnlock()
+
+	return fetcher.versions(requestsDirectory, requestIDs), fetcher.versions(impsDirectory, impIDs)
+}
+
+func (fetcher *BucketFetcher) FetchResponses(ctx context.Context, ids []string) (map[string]json.RawMessage, []error) {
+	fetcher.lock.RLock()
+	defer fetcher.lock.RUnlock()
+
+	data, _ := fetcher.get(responsesDirectory, "Response", ids, nil)
+	return data, nil
+}
+
+// FetchAccount fetches the config of the account, and merges it into the defaults.
+func (fetcher *BucketFetcher) FetchAccount(ctx context.Context, accountDefaultsJSON json.RawMessage, accountID string) (json.RawMessage, []error) {
+	if len(accountID) == 0 {
+		return nil, []error{fmt.Errorf("Cannot look up an empty accountID")}
+	}
+	fetcher.lock.RLock()
+	accountJSON, ok := fetcher.data[accountsDirectory][accountID]
+	fetcher.lock.RUnlock()
+	if !ok {
+		return nil, []error{stored_requests.NotFoundError{
+			ID:       accountID,
+			DataType: "Account",
+		}}
+	}
+
+	completeJSON, err := jsonpatch.MergePatch(accountDefaultsJSON, accountJSON)
+	if err != nil {
+		return nil, []error{err}
+	}
+	return completeJSON, nil
+}
+
+func (fetcher *BucketFetcher) FetchCategories(ctx context.Context, primaryAdServer, publisherId, iabCategory string) (string, error) {
+	return "", nil
+}
+
+// get returns the data of the IDs in the directory, and appends a NotFoundError for each ID without any.
+func (fetcher *BucketFetcher) get(directory, dataType string, ids []string, errs []error) (map[string]json.RawMessage, []error) {
+	if len(ids) == 0 {
+		return nil, errs
+	}
+	data := make(map[string]json.RawMessage, len(ids))
+	for _, id := range ids {
+		if value, ok := fetcher.data[directory][id]; ok {
+			data[id] = value
+		} else {
+			errs = append(errs, stored_requests.NotFoundError{
+				ID:       id,
+				DataType: dataType,
+			})
+		}
+	}
+	return data, errs
+}
+
+// versions returns the ETags of the files of the IDs in the directory, leaving out the IDs without one.
+func (fetcher *BucketFetcher) versions(directory string, ids []string) map[string]string {
+	versions := make(map[string]string, len(ids))
+	for _, id := range ids {
+		if etag, ok := fetcher.etags[directory][id]; ok {
+			versions[id] = etag
+		}
+	}
+	return versions
+}
@@ -0,0 +1,137 @@
+package bucket_fetcher
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/prebid/prebid-server/v2/stored_requests"
+	"github.com/prebid/prebid-server/v2/stored_requests/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeObject struct {
+	etag string
+	data string
+}
+
+// fakeStore is a bucket in memory, which counts the objects downloaded
+type fakeStore struct {
+	objects map[string]fakeObject
+	listErr error
+	gets    []string
+}
+
+func (s *fakeStore) List(ctx context.Context, prefix string) ([]object, error) {
+	if s.listErr != nil {
+		return nil, s.listErr
+	}
+	var objects []object
+	for key, o := range s.objects {
+		objects = append(objects, object{Key: key, ETag: o.etag})
+	}
+	return objects, nil
+}
+
+func (s *fakeStore) Get(ctx context.Context, key string) ([]byte, error) {
+	s.gets = append(s.gets, key)
+	o, ok := s.objects[key]
+	if !ok {
+		return nil, errObjectNotFound
+	}
+	return []byte(o.data), nil
+}
+
+func newTestStore() *fakeStore {
+	return &fakeStore{objects: map[string]fakeObject{
+		"pbs/stored_requests/req-1.json":   {"1", `{"id":"req-1"}`},
+		"pbs/stored_imps/imp-1.json":       {"1", `{"id":"imp-1"}`},
+		"pbs/stored_responses/resp-1.json": {"1", `{"seatbid":[]}`},
+		"pbs/accounts/acc-1.json":          {"1", `{"disabled":true}`},
+		"pbs/accounts/nested/acc-2.json":   {"1", `{}`},
+		"pbs/accounts/readme.txt":          {"1", `hello`},
+		"pbs/other/acc-3.json":             {"1", `{}`},
+		"other/accounts/acc-4.json":        {"1", `{}`},
+	}}
+}
+
+func TestRefresh(t *testing.T) {
+	store := newTestStore()
+	fetcher := newFetcher(store, "pbs", time.Second)
+
+	invalidation, err := fetcher.Refresh()
+	require.NoError(t, err)
+	assert.Equal(t, events.Invalidation{}, invalidation)
+	sort.Strings(store.gets)
+	assert.Equal(t, []string{"pbs/accounts/acc-1.json", "pbs/stored_imps/imp-1.json", "pbs/stored_requests/req-1.json", "pbs/stored_responses/resp-1.json"}, store.gets)
+
+	// only changed objects are downloaded again
+	store.gets = nil
+	store.objects["pbs/stored_requests/req-1.json"] = fakeObject{"2", `{"id":"req-1","changed":true}`}
+	store.objects["pbs/stored_requests/req-2.json"] = fakeObject{"1", `{"id":"req-2"}`}
+	store.objects["pbs/stored_imps/imp-1.json"] = fakeObject{"2", `{"id":"imp-1"}`}
+	delete(store.objects, "pbs/accounts/acc-1.json")
+
+	invalidation, err = fetcher.Refresh()
+	require.NoError(t, err)
+	assert.Equal(t, events.Invalidation{Requests: []string{"req-1"}, Accounts: []string{"acc-1"}}, invalidation)
+	sort.Strings(store.gets)
+	assert.Equal(t, []string{"pbs/stored_imps/imp-1.json", "pbs/stored_requests/req-1.json", "pbs/stored_requests/req-2.json"}, store.gets)
+
+	requestData, _, errs := fetcher.FetchRequests(context.Background(), []string{"req-1", "req-2"}, nil)
+	assert.Empty(t, errs)
+	assert.Equal(t, map[string]json.RawMessage{
+		"req-1": json.RawMessage(`{"id":"req-1","changed":true}`),
+		"req-2": json.RawMessage(`{"id":"req-2"}`),
+	}, requestData)
+
+	requestVersions, impVersions := fetcher.FetchRequestVersions(context.Background(), []string{"req-1", "req-2", "req-3"}, []string{"imp-1"})
+	assert.Equal(t, map[string]string{"req-1": "2", "req-2": "1"}, requestVersions)
+	assert.Equal(t, map[string]string{"imp-1": "2"}, impVersions)
+}
+
+func TestRefreshError(t *testing.T) {
+	store := newTestStore()
+	fetcher := newFetcher(store, "pbs/", time.Second)
+	_, err := fetcher.Refresh()
+	require.NoError(t, err)
+
+	store.listErr = errors.New("bucket unavailable")
+	_, err = fetcher.Refresh()
+	assert.EqualError(t, err, "bucket unavailable")
+
+	// the data synced before is kept
+	_, errs := fetcher.FetchAccount(context.Background(), json.RawMessage(`{}`), "acc-1")
+	assert.Empty(t, errs)
+}
+
+func TestFetch(t *testing.T) {
+	fetcher := newFetcher(newTestStore(), "pbs", time.Second)
+	_, err := fetcher.Refresh()
+	require.NoError(t, err)
+
+	requestData, impData, errs := fetcher.FetchRequests(context.Background(), []string{"req-1", "req-2"}, []string{"imp-1"})
+	assert.Equal(t, map[string]json.RawMessage{"req-1": json.RawMessage(`{"id":"req-1"}`)}, requestData)
+	assert.Equal(t, map[string]json.RawMessage{"imp-1": json.RawMessage(`{"id":"imp-1"}`)}, impData)
+	assert.Equal(t, []error{stored_requests.NotFoundError{ID: "req-2", DataType: "Request"}}, errs)
+
+	responseData, errs := fetcher.FetchResponses(context.Background(), []string{"resp-1", "resp-2"})
+	assert.Equal(t, map[string]json.RawMessage{"resp-1": json.RawMessage(`{"seatbid":[]}`)}, responseData)
+	assert.Empty(t, errs)
+
+	account, errs := fetcher.FetchAccount(context.Background(), json.RawMessage(`{"disabled":false,"ccpa":{"enabled":true}}`), "acc-1")
+	assert.Empty(t, errs)
+	assert.JSONEq(t, `{"disabled":true,"ccpa":{"enabled":true}}`, string(account))
+
+	for _, accountID := range []string{"acc-2", "acc-3", "acc-4"} {
+		_, errs = fetcher.FetchAccount(context.Background(), json.RawMessage(`{}`), accountID)
+		assert.Equal(t, []error{stored_requests.NotFoundError{ID: accountID, DataType: "Account"}}, errs, "only files directly in the directories of the prefix are data")
+	}
+
+	_, errs = fetcher.FetchAccount(context.Background(), json.RawMessage(`{}`), "")
+	assert.Len(t, errs, 1)
+}
@@ -6,7 +6,7 @@ import (
 
 	"github.com/lib/pq"
 
-	"github.com/golang/glog"
+	"github.com/prebid/prebid-server/v2/logger"
 	"github.com/prebid/prebid-server/v2/stored_requests"
 	"github.com/prebid/prebid-server/v2/stored_requests/backends/db_provider"
 )
@@ -18,13 +18,13 @@ func NewFetcher(
 ) stored_requests.AllFetcher {
 
 	if provider == nil {
-		glog.Fatalf("The Database Stored Request Fetcher requires a database connection. Please report this as a bug.")
+		logger.Fatalf("The Database Stored Request Fetcher requires a database connection. Please report this as a bug.")
 	}
 	if queryTemplate == "" {
-		glog.Fatalf("The Database Stored Request Fetcher requires a queryTemplate. Please report this as a bug.")
+		logger.Fatalf("The Database Stored Request Fetcher requires a queryTemplate. Please report this as a bug.")
 	}
 	if responseQueryTemplate == "" {
-		glog.Fatalf("The Database Stored Response Fetcher requires a responseQueryTemplate. Please report this as a bug.")
+		logger.Fatalf("The Database Stored Response Fetcher requires a responseQueryTemplate. Please report this as a bug.")
 	}
 	return &dbFetcher{
 		provider:              provider,
@@ -62,7 +62,7 @@ func (fetcher *dbFetcher) FetchRequests(ctx context.Context, requestIDs []string
 	rows, err := fetcher.provider.QueryContext(ctx, fetcher.queryTemplate, params...)
 	if err != nil {
 		if err != context.DeadlineExceeded && !isBadInput(err) {
-			glog.Errorf("Error reading from Stored Request DB: %s", err.Error())
+			logger.Ctx(ctx).Errorf("Error reading from Stored Request DB: %s", err.Error())
 			errs := appendErrors("Request", requestIDs, nil, nil)
 			errs = appendErrors("Imp", impIDs, nil, errs)
 			return nil, nil, errs
@@ -71,7 +71,7 @@ func (fetcher *dbFetcher) FetchRequests(ctx context.Context, requestIDs []string
 	}
 	defer func() {
 		if err := rows.Close(); err != nil {
-			glog.Errorf("error closing DB connection: %v", err)
+			logger.Ctx(ctx).Errorf("error closing DB connection: %v", err)
 		}
 	}()
 
@@ -93,7 +93,7 @@ func (fetcher *dbFetcher) FetchRequests(ctx context.Context, requestIDs []string
 		case "imp":
 			storedImpData[id] = data
 		default:
-			glog.Errorf("Database result set with id=%s has invalid type: %s. This will be ignored.", id, dataType)
+			logger.Ctx(ctx).Errorf("Database result set with id=%s has invalid type: %s. This will be ignored.", id, dataType)
 		}
 	}
 
@@ -127,7 +127,7 @@ func (fetcher *dbFetcher) FetchResponses(ctx context.Context, ids []string) (dat
 	}
 	defer func() {
 		if err := rows.Close(); err != nil {
-			glog.Errorf("error closing DB connection: %v", err)
+			logger.Ctx(ctx).Errorf("error closing DB connection: %v", err)
 		}
 	}()
 
@@ -3,9 +3,10 @@ package db_provider
 import (
 	"context"
 	"database/sql"
+	"fmt"
 
-	"github.com/golang/glog"
 	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/logger"
 )
 
 type DbProvider interface {
@@ -19,6 +20,17 @@ type DbProvider interface {
 }
 
 func NewDbProvider(dataType config.DataType, cfg config.DatabaseConnection) DbProvider {
+	provider, err := OpenDbProvider(dataType, cfg)
+	if err != nil {
+		logger.Fatal(err)
+		return nil
+	}
+	return provider
+}
+
+// OpenDbProvider creates a provider for the configured driver, opens the connection and pings
+// the database. Unlike NewDbProvider, it returns an error instead of exiting the process.
+func OpenDbProvider(dataType config.DataType, cfg config.DatabaseConnection) (DbProvider, error) {
 	var provider DbProvider
 
 	switch cfg.Driver {
@@ -31,18 +43,18 @@ func NewDbProvider(dataType config.DataType, cfg config.DatabaseConnection) DbPr
 			cfg: cfg,
 		}
 	default:
-		glog.Fatalf("Unsupported database driver %s", cfg.Driver)
-		return nil
+		return nil, fmt.Errorf("Unsupported database driver %s", cfg.Driver)
 	}
 
 	if err := provider.Open(); err != nil {
-		glog.Fatalf("Failed to open %s database connection: %v", dataType, err)
+		return nil, fmt.Errorf("Failed to open %s database connection: %v", dataType, err)
 	}
 	if err := provider.Ping(); err != nil {
-		glog.Fatalf("Failed to ping %s database: %v", dataType, err)
+		provider.Close()
+		return nil, fmt.Errorf("Failed to ping %s database: %v", dataType, err)
 	}
 
-	return provider
+	return provider, nil
 }
 
 type QueryParam struct {
@@ -1,13 +1,16 @@
 package file_fetcher
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
 	"os"
 	"strings"
+	"sync"
 
 	"github.com/prebid/prebid-server/v2/stored_requests"
+	"github.com/prebid/prebid-server/v2/stored_requests/events"
 	"github.com/prebid/prebid-server/v2/util/jsonutil"
 	jsonpatch "gopkg.in/evanphx/json-patch.v4"
 )
@@ -19,15 +22,57 @@ import (
 // For example, when asked to fetch the request with ID == "23", it will return the data from "directory/23.json".
 func NewFileFetcher(directory string) (stored_requests.AllFetcher, error) {
 	storedData, err := collectStoredData(directory, FileSystem{make(map[string]FileSystem), make(map[string]json.RawMessage)}, nil)
-	return &eagerFetcher{storedData, nil}, err
+	return &eagerFetcher{FileSystem: storedData, directory: directory}, err
 }
 
 type eagerFetcher struct {
 	FileSystem FileSystem
 	Categories map[string]map[string]stored_requests.Category
+	directory  string
+	mutex      sync.RWMutex
+}
+
+// Refresh re-reads the fetcher's directory and replaces the data held in memory. It returns an invalidation
+// listing the IDs of every stored request, imp, response and account which was modified or removed so that
+// any caches in front of this fetcher may drop their stale copies. Categories aren't cached in front of
+// fetchers, so the categories parsed so far are just dropped, and parsed again from the new files. The data
+// in memory is left untouched if the directory cannot be read.
+func (fetcher *eagerFetcher) Refresh() (events.Invalidation, error) {
+	storedData, err := collectStoredData(fetcher.directory, FileSystem{make(map[string]FileSystem), make(map[string]json.RawMessage)}, nil)
+	if err != nil {
+		return events.Invalidation{}, err
+	}
+
+	fetcher.mutex.Lock()
+	defer fetcher.mutex.Unlock()
+
+	invalidation := events.Invalidation{
+		Requests:  changedIDs(fetcher.FileSystem.Directories["stored_requests"].Files, storedData.Directories["stored_requests"].Files),
+		Imps:      changedIDs(fetcher.FileSystem.Directories["stored_imps"].Files, storedData.Directories["stored_imps"].Files),
+		Responses: changedIDs(fetcher.FileSystem.Directories["stored_responses"].Files, storedData.Directories["stored_responses"].Files),
+		Accounts:  changedIDs(fetcher.FileSystem.Directories["accounts"].Files, storedData.Directories["accounts"].Files),
+	}
+	fetcher.FileSystem = storedData
+	fetcher.Categories = nil
+
+	return invalidation, nil
+}
+
+// changedIDs returns the IDs present in before which are either missing from after or have different contents.
+func changedIDs(before, after map[string]json.RawMessage) []string {
+	var ids []string
+	for id, data := range before {
+		if newData, ok := after[id]; !ok || !bytes.Equal(data, newData) {
+			ids = append(ids, id)
+		}
+	}
+	return ids
 }
 
 func (fetcher *eagerFetcher) FetchRequests(ctx context.Context, requestIDs []string, impIDs []string) (map[string]json.RawMessage, map[string]json.RawMessage, []error) {
+	fetcher.mutex.RLock()
+	defer fetcher.mutex.RUnlock()
+
 	storedRequests := fetcher.FileSystem.Directories["stored_requests"].Files
 	storedImpressions := fetcher.FileSystem.Directories["stored_imps"].Files
 	errs := appendErrors("Request", requestIDs, storedRequests, nil)
@@ -35,8 +80,12 @@ func (fetcher *eagerFetcher) FetchRequests(ctx context.Context, requestIDs []str
 	return storedRequests, storedImpressions, errs
 }
 
+// FetchResponses fetches the stored responses in the stored_responses directory.
 func (fetcher *eagerFetcher) FetchResponses(ctx context.Context, ids []string) (data map[string]json.RawMessage, errs []error) {
-	return nil, nil
+	fetcher.mutex.RLock()
+	defer fetcher.mutex.RUnlock()
+
+	return fetcher.FileSystem.Directories["stored_responses"].Files, nil
 }
 
 // FetchAccount fetches the host account configuration for a publisher
@@ -44,6 +93,9 @@ func (fetcher *eagerFetcher) FetchAccount(ctx context.Context, accountDefaultsJS
 	if len(accountID) == 0 {
 		return nil, []error{fmt.Errorf("Cannot look up an empty accountID")}
 	}
+	fetcher.mutex.RLock()
+	defer fetcher.mutex.RUnlock()
+
 	accountJSON, ok := fetcher.FileSystem.Directories["accounts"].Files[accountID]
 	if !ok {
 		return nil, []error{stored_requests.NotFoundError{
@@ -66,6 +118,10 @@ func (fetcher *eagerFetcher) FetchCategories(ctx context.Context, primaryAdServe
 		fileName = primaryAdServer + "_" + publisherId
 	}
 
+	// Categories are lazily parsed and cached, which mutates the fetcher state
+	fetcher.mutex.Lock()
+	defer fetcher.mutex.Unlock()
+
 	if fetcher.Categories == nil {
 		fetcher.Categories = make(map[string]map[string]stored_requests.Category)
 	}
@@ -118,7 +174,12 @@ func collectStoredData(directory string, fileSystem FileSystem, err error) (File
 	data := make(map[string]json.RawMessage)
 
 	for _, fileInfo := range fileInfos {
-		if fileInfo.IsDir() {
+		// Skip hidden entries, such as the "..data" links and timestamped directories which Kubernetes
+		// creates when mounting a ConfigMap or Secret volume
+		if strings.HasPrefix(fileInfo.Name(), ".") {
+			continue
+		}
+		if isDir(directory, fileInfo) {
 
 			fs := FileSystem{make(map[string]FileSystem), make(map[string]json.RawMessage)}
 			fileSys, innerErr := collectStoredData(directory+"/"+fileInfo.Name(), fs, err)
@@ -143,6 +204,15 @@ func collectStoredData(directory string, fileSystem FileSystem, err error) (File
 	return fileSystem, err
 }
 
+// isDir reports whether the entry is a directory, following symbolic links.
+func isDir(directory string, entry os.DirEntry) bool {
+	if entry.Type()&os.ModeSymlink == 0 {
+		return entry.IsDir()
+	}
+	info, err := os.Stat(fmt.Sprintf("%s/%s", directory, entry.Name()))
+	return err == nil && info.IsDir()
+}
+
 func appendErrors(dataType string, ids []string, data map[string]json.RawMessage, errs []error) []error {
 	for _, id := range ids {
 		if _, ok := data[id]; !ok {
@@ -4,6 +4,8 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/prebid/prebid-server/v2/stored_requests"
@@ -179,3 +181,71 @@ func TestCategoriesFetcherNoCategoriesFile(t *testing.T) {
 	assert.Equal(t, fmt.Errorf("Unable to find mapping file for adserver: 'test', publisherId: 'not_exists'"),
 		fetchingErr, "Categories were loaded incorrectly")
 }
+
+func TestRefresh(t *testing.T) {
+	dir := t.TempDir()
+	writeFile := func(path, content string) {
+		full := filepath.Join(dir, path)
+		assert.NoError(t, os.MkdirAll(filepath.Dir(full), 0755))
+		assert.NoError(t, os.WriteFile(full, []byte(content), 0644))
+	}
+	writeFile("stored_requests/1.json", `{"id":"1"}`)
+	writeFile("stored_requests/2.json", `{"id":"2"}`)
+	writeFile("stored_imps/imp.json", `{"id":"imp"}`)
+	writeFile("accounts/acc.json", `{"disabled":false}`)
+	writeFile("stored_responses/resp.json", `{"seatbid":[]}`)
+	writeFile("freewheel/freewheel.json", `{"IAB1-1":{"id":"Sports"}}`)
+
+	fetcher, err := NewFileFetcher(dir)
+	assert.NoError(t, err)
+	category, err := fetcher.FetchCategories(context.Background(), "freewheel", "", "IAB1-1")
+	assert.NoError(t, err)
+	assert.Equal(t, "Sports", category)
+
+	// Mimic a Kubernetes volume update: modify one request, remove another and add a new one
+	writeFile("stored_requests/1.json", `{"id":"1-updated"}`)
+	assert.NoError(t, os.Remove(filepath.Join(dir, "stored_requests/2.json")))
+	writeFile("stored_requests/3.json", `{"id":"3"}`)
+	writeFile("..2024_01_01/stored_requests/4.json", `{"id":"4"}`)
+	writeFile("stored_responses/resp.json", `{"seatbid":[{"seat":"appnexus"}]}`)
+	writeFile("freewheel/freewheel.json", `{"IAB1-1":{"id":"News"}}`)
+
+	invalidation, err := fetcher.(*eagerFetcher).Refresh()
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{"1", "2"}, invalidation.Requests)
+	assert.Empty(t, invalidation.Imps)
+	assert.Equal(t, []string{"resp"}, invalidation.Responses)
+	assert.Empty(t, invalidation.Accounts)
+
+	storedReqs, storedImps, errs := fetcher.FetchRequests(context.Background(), []string{"1", "2", "3"}, []string{"imp"})
+	assert.JSONEq(t, `{"id":"1-updated"}`, string(storedReqs["1"]))
+	assert.JSONEq(t, `{"id":"3"}`, string(storedReqs["3"]))
+	assert.JSONEq(t, `{"id":"imp"}`, string(storedImps["imp"]))
+	assert.Equal(t, []error{stored_requests.NotFoundError{ID: "2", DataType: "Request"}}, errs)
+	assert.NotContains(t, fetcher.(*eagerFetcher).FileSystem.Directories, "..2024_01_01")
+
+	storedResps, errs := fetcher.FetchResponses(context.Background(), []string{"resp"})
+	assert.Empty(t, errs)
+	assert.JSONEq(t, `{"seatbid":[{"seat":"appnexus"}]}`, string(storedResps["resp"]))
+
+	category, err = fetcher.FetchCategories(context.Background(), "freewheel", "", "IAB1-1")
+	assert.NoError(t, err)
+	assert.Equal(t, "News", category)
+}
+
+func TestRefreshFailureKeepsData(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, os.MkdirAll(filepath.Join(dir, "stored_requests"), 0755))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "stored_requests", "1.json"), []byte(`{"id":"1"}`), 0644))
+
+	fetcher, err := NewFileFetcher(dir)
+	assert.NoError(t, err)
+	assert.NoError(t, os.RemoveAll(dir))
+
+	_, err = fetcher.(*eagerFetcher).Refresh()
+	assert.Error(t, err)
+
+	storedReqs, _, errs := fetcher.FetchRequests(context.Background(), []string{"1"}, nil)
+	assert.Empty(t, errs)
+	assert.JSONEq(t, `{"id":"1"}`, string(storedReqs["1"]))
+}
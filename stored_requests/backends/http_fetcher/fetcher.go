@@ -9,11 +9,11 @@ import (
 	"net/url"
 	"strings"
 
+	"github.com/prebid/prebid-server/v2/logger"
 	"github.com/prebid/prebid-server/v2/stored_requests"
 	"github.com/prebid/prebid-server/v2/util/jsonutil"
 	jsonpatch "gopkg.in/evanphx/json-patch.v4"
 
-	"github.com/golang/glog"
 	"golang.org/x/net/context/ctxhttp"
 )
 
@@ -55,9 +55,9 @@ func NewFetcher(client *http.Client, endpoint string) *HttpFetcher {
 	// `&request-ids=...&imp-ids=...`.
 
 	if _, err := url.Parse(endpoint); err != nil {
-		glog.Fatalf(`Invalid endpoint "%s": %v`, endpoint, err)
+		logger.Fatalf(`Invalid endpoint "%s": %v`, endpoint, err)
 	}
-	glog.Infof("Making http_fetcher for endpoint %v", endpoint)
+	logger.Infof("Making http_fetcher for endpoint %v", endpoint)
 
 	urlPrefix := endpoint
 	if strings.Contains(endpoint, "?") {
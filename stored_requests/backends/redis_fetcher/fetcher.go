@@ -0,0 +1,168 @@
+package redis_fetcher
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	jsonpatch "gopkg.in/evanphx/json-patch.v4"
+
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/stored_requests"
+	"github.com/prebid/prebid-server/v2/util/redisutil"
+)
+
+const (
+	requestKeyType  = "request"
+	impKeyType      = "imp"
+	responseKeyType = "response"
+	accountKeyType  = "account"
+)
+
+// NewFetcher returns a Fetcher which reads data from the Redis server or cluster of the config.
+//
+// Each piece of data is a string holding its JSON, at a key made of the config's key prefix, its type and its ID:
+//
+//	{key_prefix}request:{id}
+//	{key_prefix}imp:{id}
+//	{key_prefix}response:{id}
+//	{key_prefix}account:{id}
+//
+// All the keys of a fetch are read in one pipeline, which a cluster splits into one pipeline for each node.
+func NewFetcher(cfg config.RedisFetcherConfig) (*RedisFetcher, error) {
+	client, err := redisutil.NewClient(cfg.RedisConnection)
+	if err != nil {
+		return nil, err
+	}
+	return newFetcher(client, cfg.KeyPrefix, cfg.TimeoutDuration()), nil
+}
+
+func newFetcher(client redis.UniversalClient, keyPrefix string, timeout time.Duration) *RedisFetcher {
+	return &RedisFetcher{
+		client:    client,
+		keyPrefix: keyPrefix,
+		timeout:   timeout,
+	}
+}
+
+// RedisFetcher fetches Stored Data from Redis. This should be instantiated through the NewFetcher() function.
+type RedisFetcher struct {
+	client    redis.UniversalClient
+	keyPrefix string
+	timeout   time.Duration
+}
+
+func (fetcher *RedisFetcher) FetchRequests(ctx context.Context, requestIDs []string, impIDs []string) (map[string]json.RawMessage, map[string]json.RawMessage, []error) {
+	if len(requestIDs) == 0 && len(impIDs) == 0 {
+		return nil, nil, nil
+	}
+
+	data, err := fetcher.fetch(ctx, []string{requestKeyType, impKeyType}, requestIDs, impIDs)
+	if err != nil {
+		return nil, nil, []error{fmt.Errorf("Error fetching Stored Requests via redis: %v", err)}
+	}
+
+	errs := appendErrors("Request", requestIDs, data[0], nil)
+	errs = appendErrors("Imp", impIDs, data[1], errs)
+	return data[0], data[1], errs
+}
+
+func (fetcher *RedisFetcher) FetchResponses(ctx context.Context, ids []string) (map[string]json.RawMessage, []error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	data, err := fetcher.fetch(ctx, []string{responseKeyType}, ids)
+	if err != nil {
+		return nil, []error{fmt.Errorf("Error fetching Stored Responses via redis: %v", err)}
+	}
+	return data[0], nil
+}
+
+// FetchAccount fetches the config of the account, and merges it into the defaults.
+func (fetcher *RedisFetcher) FetchAccount(ctx context.Context, accountDefaultsJSON json.RawMessage, accountID string) (json.RawMessage, []error) {
+	if len(accountID) == 0 {
+		return nil, []error{fmt.Errorf("Cannot look up an empty accountID")}
+	}
+
+	data, err := fetcher.fetch(ctx, []string{accountKeyType}, []string{accountID})
+	if err != nil {
+		return nil, []error{fmt.Errorf("Error fetching account %s via redis: %v", accountID, err)}
+	}
+	accountJSON, ok := data[0][accountID]
+	if !ok {
+		return nil, []error{stored_requests.NotFoundError{
+			ID:       accountID,
+			DataType: "Account",
+		}}
+	}
+
+	completeJSON, err := jsonpatch.MergePatch(accountDefaultsJSON, accountJSON)
+	if err != nil {
+		return nil, []error{err}
+	}
+	return completeJSON, nil
+}
+
+func (fetcher *RedisFetcher) FetchCategories(ctx context.Context, primaryAdServer, publisherId, iabCategory string) (string, error) {
+	return "", nil
+}
+
+// Close closes the connections to Redis.
+func (fetcher *RedisFetcher) Close() error {
+	return fetcher.client.Close()
+}
+
+// fetch gets the data of each list of IDs, whose keys are of the key type at the same index, in a single pipeline.
+// IDs without data are left out of the data returned.
+func (fetcher *RedisFetcher) fetch(ctx context.Context, keyTypes []string, idLists ...[]string) ([]map[string]json.RawMessage, error) {
+	ctx, cancel := context.WithTimeout(ctx, fetcher.timeout)
+	defer cancel()
+
+	cmds := make([][]*redis.StringCmd, len(idLists))
+	_, err := fetcher.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for i, ids := range idLists {
+			cmds[i] = make([]*redis.StringCmd, 0, len(ids))
+			for _, id := range ids {
+				cmds[i] = append(cmds[i], pipe.Get(ctx, fetcher.keyPrefix+keyTypes[i]+":"+id))
+			}
+		}
+		return nil
+	})
+	// the pipeline's error is that of its first failed command, which is a redis.Nil for missing data, so the
+	// errors of the other commands are checked too
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return nil, err
+	}
+
+	data := make([]map[string]json.RawMessage, len(idLists))
+	for i, ids := range idLists {
+		data[i] = make(map[string]json.RawMessage, len(ids))
+		for j, cmd := range cmds[i] {
+			value, err := cmd.Bytes()
+			if errors.Is(err, redis.Nil) {
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			data[i][ids[j]] = value
+		}
+	}
+	return data, nil
+}
+
+func appendErrors(dataType string, ids []string, data map[string]json.RawMessage, errs []error) []error {
+	for _, id := range ids {
+		if _, ok := data[id]; !ok {
+			errs = append(errs, stored_requests.NotFoundError{
+				ID:       id,
+				DataType: dataType,
+			})
+		}
+	}
+	return errs
+}
@@ -0,0 +1,100 @@
+package redis_fetcher
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/stored_requests"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestFetcher(t *testing.T, keyPrefix string) (*RedisFetcher, *miniredis.Miniredis) {
+	server := miniredis.RunT(t)
+	fetcher, err := NewFetcher(config.RedisFetcherConfig{
+		RedisConnection: config.RedisConnection{Addrs: []string{server.Addr()}},
+		KeyPrefix:       keyPrefix,
+		Timeout:         1000,
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { fetcher.Close() })
+	return fetcher, server
+}
+
+func TestFetchRequests(t *testing.T) {
+	fetcher, server := newTestFetcher(t, "pbs:")
+	server.Set("pbs:request:req-1", `{"id":"req-1"}`)
+	server.Set("pbs:imp:imp-1", `{"id":"imp-1"}`)
+	server.Set("pbs:imp:imp-2", `{"id":"imp-2"}`)
+	server.Set("request:req-2", `{"id":"unprefixed"}`)
+
+	requestData, impData, errs := fetcher.FetchRequests(context.Background(), []string{"req-1", "req-2"}, []string{"imp-1", "imp-2"})
+
+	assert.Equal(t, map[string]json.RawMessage{"req-1": json.RawMessage(`{"id":"req-1"}`)}, requestData)
+	assert.Equal(t, map[string]json.RawMessage{
+		"imp-1": json.RawMessage(`{"id":"imp-1"}`),
+		"imp-2": json.RawMessage(`{"id":"imp-2"}`),
+	}, impData)
+	assert.Equal(t, []error{stored_requests.NotFoundError{ID: "req-2", DataType: "Request"}}, errs)
+}
+
+func TestFetchRequestsNoIDs(t *testing.T) {
+	fetcher, _ := newTestFetcher(t, "")
+
+	requestData, impData, errs := fetcher.FetchRequests(context.Background(), nil, nil)
+
+	assert.Nil(t, requestData)
+	assert.Nil(t, impData)
+	assert.Empty(t, errs)
+}
+
+func TestFetchResponses(t *testing.T) {
+	fetcher, server := newTestFetcher(t, "")
+	server.Set("response:resp-1", `{"seatbid":[]}`)
+
+	data, errs := fetcher.FetchResponses(context.Background(), []string{"resp-1", "resp-2"})
+
+	assert.Equal(t, map[string]json.RawMessage{"resp-1": json.RawMessage(`{"seatbid":[]}`)}, data)
+	assert.Empty(t, errs)
+}
+
+func TestFetchAccount(t *testing.T) {
+	fetcher, server := newTestFetcher(t, "")
+	server.Set("account:acc-1", `{"id":"acc-1","disabled":true}`)
+
+	account, errs := fetcher.FetchAccount(context.Background(), json.RawMessage(`{"disabled":false,"ccpa":{"enabled":true}}`), "acc-1")
+	assert.Empty(t, errs)
+	assert.JSONEq(t, `{"id":"acc-1","disabled":true,"ccpa":{"enabled":true}}`, string(account))
+
+	account, errs = fetcher.FetchAccount(context.Background(), json.RawMessage(`{}`), "acc-2")
+	assert.Nil(t, account)
+	assert.Equal(t, []error{stored_requests.NotFoundError{ID: "acc-2", DataType: "Account"}}, errs)
+
+	_, errs = fetcher.FetchAccount(context.Background(), json.RawMessage(`{}`), "")
+	assert.Len(t, errs, 1)
+}
+
+func TestFetchErrors(t *testing.T) {
+	fetcher, server := newTestFetcher(t, "")
+	server.Set("request:req-1", `{"id":"req-1"}`)
+	server.HSet("imp:imp-1", "id", "imp-1")
+
+	// data which isn't a string fails the fetch
+	requestData, impData, errs := fetcher.FetchRequests(context.Background(), []string{"req-1"}, []string{"imp-1"})
+	assert.Nil(t, requestData)
+	assert.Nil(t, impData)
+	require.Len(t, errs, 1)
+	assert.ErrorContains(t, errs[0], "Error fetching Stored Requests via redis: WRONGTYPE")
+
+	server.Close()
+
+	_, errs = fetcher.FetchResponses(context.Background(), []string{"resp-1"})
+	require.Len(t, errs, 1)
+	assert.ErrorContains(t, errs[0], "Error fetching Stored Responses via redis")
+	_, errs = fetcher.FetchAccount(context.Background(), nil, "acc-1")
+	require.Len(t, errs, 1)
+	assert.ErrorContains(t, errs[0], "Error fetching account acc-1 via redis")
+}
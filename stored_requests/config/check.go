@@ -0,0 +1,59 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/stored_requests/backends/db_provider"
+	"github.com/prebid/prebid-server/v2/stored_requests/backends/file_fetcher"
+)
+
+// CheckStoredRequests verifies that every stored data backend referenced by the configuration
+// is reachable: database connections are opened and pinged, filesystem directories are loaded
+// and HTTP endpoints are parsed. Unlike NewStoredRequests it never exits the process, so it
+// may be used to report every problem at once before the server starts.
+func CheckStoredRequests(cfg *config.Configuration) []error {
+	var errs []error
+	for _, sr := range []*config.StoredRequests{
+		&cfg.StoredRequests,
+		&cfg.StoredRequestsAMP,
+		&cfg.CategoryMapping,
+		&cfg.StoredVideo,
+		&cfg.Accounts,
+		&cfg.StoredResponses,
+	} {
+		errs = append(errs, checkBackends(sr)...)
+	}
+	return errs
+}
+
+func checkBackends(cfg *config.StoredRequests) []error {
+	var errs []error
+
+	if cfg.Database.ConnectionInfo.Database != "" {
+		provider, err := db_provider.OpenDbProvider(cfg.DataType(), cfg.Database.ConnectionInfo)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s.database: %v", cfg.Section(), err))
+		} else {
+			provider.Close()
+		}
+	}
+
+	if cfg.Files.Enabled {
+		if _, err := file_fetcher.NewFileFetcher(cfg.Files.Path); err != nil {
+			errs = append(errs, fmt.Errorf("%s.filesystem: %v", cfg.Section(), err))
+		}
+	}
+
+	for _, endpoint := range []string{cfg.HTTP.Endpoint, cfg.HTTPEvents.Endpoint} {
+		if endpoint == "" {
+			continue
+		}
+		if _, err := url.ParseRequestURI(endpoint); err != nil {
+			errs = append(errs, fmt.Errorf("%s.http: invalid endpoint %s: %v", cfg.Section(), endpoint, err))
+		}
+	}
+
+	return errs
+}
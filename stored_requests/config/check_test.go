@@ -0,0 +1,59 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckBackends(t *testing.T) {
+	testCases := []struct {
+		description    string
+		config         *config.StoredRequests
+		expectedErrors int
+	}{
+		{
+			description:    "Empty config",
+			config:         typedConfig(config.RequestDataType, &config.StoredRequests{}),
+			expectedErrors: 0,
+		},
+		{
+			description: "Valid filesystem and http endpoint",
+			config: typedConfig(config.RequestDataType, &config.StoredRequests{
+				Files: config.FileFetcherConfig{Enabled: true, Path: "../backends/file_fetcher/test"},
+				HTTP:  config.HTTPFetcherConfig{Endpoint: "http://stored-requests.prebid.com"},
+			}),
+			expectedErrors: 0,
+		},
+		{
+			description: "Missing filesystem directory",
+			config: typedConfig(config.RequestDataType, &config.StoredRequests{
+				Files: config.FileFetcherConfig{Enabled: true, Path: "./does-not-exist"},
+			}),
+			expectedErrors: 1,
+		},
+		{
+			description: "Invalid http endpoints",
+			config: typedConfig(config.RequestDataType, &config.StoredRequests{
+				HTTP:       config.HTTPFetcherConfig{Endpoint: "not a url"},
+				HTTPEvents: config.HTTPEventsConfig{Endpoint: "::"},
+			}),
+			expectedErrors: 2,
+		},
+		{
+			description: "Unsupported database driver",
+			config: typedConfig(config.RequestDataType, &config.StoredRequests{
+				Database: config.DatabaseConfig{
+					ConnectionInfo: config.DatabaseConnection{Driver: "oracle", Database: "db"},
+				},
+			}),
+			expectedErrors: 1,
+		},
+	}
+
+	for _, test := range testCases {
+		errs := checkBackends(test.config)
+		assert.Len(t, errs, test.expectedErrors, test.description)
+	}
+}
@@ -2,27 +2,37 @@ package config
 
 import (
 	"context"
+	"encoding/base64"
+	"fmt"
 	"net/http"
 	"time"
 
+	"github.com/prebid/prebid-server/v2/logger"
 	"github.com/prebid/prebid-server/v2/metrics"
 
-	"github.com/golang/glog"
 	"github.com/julienschmidt/httprouter"
 	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/faultinjection"
 	"github.com/prebid/prebid-server/v2/stored_requests"
+	"github.com/prebid/prebid-server/v2/stored_requests/backends/bucket_fetcher"
 	"github.com/prebid/prebid-server/v2/stored_requests/backends/db_fetcher"
 	"github.com/prebid/prebid-server/v2/stored_requests/backends/db_provider"
 	"github.com/prebid/prebid-server/v2/stored_requests/backends/empty_fetcher"
 	"github.com/prebid/prebid-server/v2/stored_requests/backends/file_fetcher"
 	"github.com/prebid/prebid-server/v2/stored_requests/backends/http_fetcher"
+	"github.com/prebid/prebid-server/v2/stored_requests/backends/redis_fetcher"
 	"github.com/prebid/prebid-server/v2/stored_requests/caches/memory"
 	"github.com/prebid/prebid-server/v2/stored_requests/caches/nil_cache"
 	"github.com/prebid/prebid-server/v2/stored_requests/events"
 	apiEvents "github.com/prebid/prebid-server/v2/stored_requests/events/api"
+	"github.com/prebid/prebid-server/v2/stored_requests/events/bus"
 	databaseEvents "github.com/prebid/prebid-server/v2/stored_requests/events/database"
+	filesEvents "github.com/prebid/prebid-server/v2/stored_requests/events/files"
 	httpEvents "github.com/prebid/prebid-server/v2/stored_requests/events/http"
+	postgresEvents "github.com/prebid/prebid-server/v2/stored_requests/events/postgres"
+	"github.com/prebid/prebid-server/v2/stored_requests/secrets"
 	"github.com/prebid/prebid-server/v2/util/task"
+	"github.com/prebid/prebid-server/v2/webhooks"
 )
 
 // CreateStoredRequests returns three things:
@@ -35,11 +45,11 @@ import (
 //
 // As a side-effect, it will add some endpoints to the router if the config calls for it.
 // In the future we should look for ways to simplify this so that it's not doing two things.
-func CreateStoredRequests(cfg *config.StoredRequests, metricsEngine metrics.MetricsEngine, client *http.Client, router *httprouter.Router, provider db_provider.DbProvider) (fetcher stored_requests.AllFetcher, shutdown func()) {
+func CreateStoredRequests(cfg *config.StoredRequests, metricsEngine metrics.MetricsEngine, client *http.Client, router *httprouter.Router, provider db_provider.DbProvider, decrypter *secrets.Decrypter, observer events.Observer, storedDataBus *bus.Bus) (fetcher stored_requests.AllFetcher, shutdown func()) {
 	// Create database connection if given options for one
 	if cfg.Database.ConnectionInfo.Database != "" {
 		if provider == nil {
-			glog.Infof("Connecting to Database for Stored %s. Driver=%s, DB=%s, host=%s, port=%d, user=%s",
+			logger.Infof("Connecting to Database for Stored %s. Driver=%s, DB=%s, host=%s, port=%d, user=%s",
 				cfg.DataType(),
 				cfg.Database.ConnectionInfo.Driver,
 				cfg.Database.ConnectionInfo.Database,
@@ -51,32 +61,105 @@ func CreateStoredRequests(cfg *config.StoredRequests, metricsEngine metrics.Metr
 
 		// Error out if config is trying to use multiple database connections for different stored requests (not supported yet)
 		if provider.Config() != cfg.Database.ConnectionInfo {
-			glog.Fatal("Multiple database connection settings found in config, only a single database connection is currently supported.")
+			logger.Fatal("Multiple database connection settings found in config, only a single database connection is currently supported.")
 		}
 	}
 
-	eventProducers := newEventProducers(cfg, client, provider, metricsEngine, router)
-	fetcher = newFetcher(cfg, client, provider)
+	eventProducers := newEventProducers(cfg, client, provider, metricsEngine, router, storedDataBus)
+	var redisFetcher *redis_fetcher.RedisFetcher
+	if len(cfg.Redis.Addrs) > 0 {
+		redisFetcher = newRedis(cfg)
+	}
+	var bucketFetcher *bucket_fetcher.BucketFetcher
+	if cfg.Bucket.Name != "" {
+		bucketFetcher = newBucket(cfg, client)
+	}
+	fetcher, fileFetcher := newFetcher(cfg, client, provider, redisFetcher, bucketFetcher)
+
+	var listenNotifyProducer *postgresEvents.PostgresEventProducer
+	if cfg.Database.ListenNotify.Channel != "" && provider != nil {
+		listenNotifyProducer = newListenNotify(cfg, provider, metricsEngine)
+		eventProducers = append(eventProducers, listenNotifyProducer)
+	}
+
+	var fileWatchTask *task.TickerTask
+	if refresher, ok := fileFetcher.(filesEvents.Refresher); ok && cfg.Files.WatchInterval > 0 {
+		logger.Infof("Watching Stored %s data at path %s for changes every %d seconds", cfg.DataType(), cfg.Files.Path, cfg.Files.WatchInterval)
+		fileEventProducer := filesEvents.NewFileEventProducer(cfg.DataType(), refresher)
+		fileWatchTask = task.NewTickerTask(cfg.Files.WatchIntervalDuration(), fileEventProducer)
+		eventProducers = append(eventProducers, fileEventProducer)
+	}
 
-	var shutdown1 func()
+	var bucketSyncTask *task.TickerTask
+	if bucketFetcher != nil {
+		bucketEventProducer := filesEvents.NewFileEventProducer(cfg.DataType(), bucketFetcher)
+		if cfg.Bucket.SyncInterval > 0 {
+			logger.Infof("Syncing Stored %s data from bucket %s every %d seconds", cfg.DataType(), cfg.Bucket.Name, cfg.Bucket.SyncInterval)
+			bucketSyncTask = task.NewTickerTask(cfg.Bucket.SyncIntervalDuration(), bucketEventProducer)
+		}
+		if cfg.Bucket.RefreshEndpoint != "" {
+			router.POST(cfg.Bucket.RefreshEndpoint, newBucketRefreshHandler(cfg.DataType(), bucketEventProducer))
+		}
+		eventProducers = append(eventProducers, bucketEventProducer)
+	}
+
+	if decrypter != nil {
+		fetcher = secrets.WithDecryption(fetcher, decrypter)
+	}
+
+	var shutdown1, shutdown2 func()
 
 	if cfg.InMemoryCache.Type != "" {
 		cache := newCache(cfg)
+		if decrypter != nil {
+			cache = secrets.WithDecryptedSaves(cache, decrypter)
+		}
 		fetcher = stored_requests.WithCache(fetcher, cache, metricsEngine)
-		shutdown1 = addListeners(cache, eventProducers)
+		shutdown1 = addListeners(cache, eventProducers, observer)
+		if storedDataBus != nil {
+			// the observer of the instance which received the events has been told about them already
+			shutdown2 = addListeners(cache, []events.EventProducer{storedDataBus.Subscribe(cfg.DataType())}, nil)
+		}
+	} else if observer != nil {
+		// there's no cache to update, but the observer is still told about the events
+		shutdown1 = addListeners(newNilCache(), eventProducers, observer)
+	}
+
+	if fileWatchTask != nil {
+		fileWatchTask.Start()
+	}
+	if bucketSyncTask != nil {
+		bucketSyncTask.Start()
 	}
 
 	shutdown = func() {
+		if fileWatchTask != nil {
+			fileWatchTask.Stop()
+		}
+		if bucketSyncTask != nil {
+			bucketSyncTask.Stop()
+		}
+		if listenNotifyProducer != nil {
+			listenNotifyProducer.Stop()
+		}
+		if redisFetcher != nil {
+			if err := redisFetcher.Close(); err != nil {
+				logger.Errorf("Error closing Redis connections: %v", err)
+			}
+		}
 		if shutdown1 != nil {
 			shutdown1()
 		}
+		if shutdown2 != nil {
+			shutdown2()
+		}
 
 		if provider == nil {
 			return
 		}
 
 		if err := provider.Close(); err != nil {
-			glog.Errorf("Error closing DB connection: %v", err)
+			logger.Errorf("Error closing DB connection: %v", err)
 		}
 	}
 
@@ -97,7 +180,7 @@ func CreateStoredRequests(cfg *config.StoredRequests, metricsEngine metrics.Metr
 //
 // As a side-effect, it will add some endpoints to the router if the config calls for it.
 // In the future we should look for ways to simplify this so that it's not doing two things.
-func NewStoredRequests(cfg *config.Configuration, metricsEngine metrics.MetricsEngine, client *http.Client, router *httprouter.Router) (shutdown func(),
+func NewStoredRequests(cfg *config.Configuration, metricsEngine metrics.MetricsEngine, client *http.Client, router *httprouter.Router, notifier *webhooks.Notifier) (shutdown func(),
 	fetcher stored_requests.Fetcher,
 	ampFetcher stored_requests.Fetcher,
 	accountsFetcher stored_requests.AccountFetcher,
@@ -106,20 +189,22 @@ func NewStoredRequests(cfg *config.Configuration, metricsEngine metrics.MetricsE
 	storedRespFetcher stored_requests.Fetcher) {
 
 	var provider db_provider.DbProvider
+	decrypter := newDecrypter(&cfg.StoredDataEncryption, client)
+	storedDataBus := newStoredDataBus(&cfg.StoredDataBus, metricsEngine)
 
-	fetcher1, shutdown1 := CreateStoredRequests(&cfg.StoredRequests, metricsEngine, client, router, provider)
-	fetcher2, shutdown2 := CreateStoredRequests(&cfg.StoredRequestsAMP, metricsEngine, client, router, provider)
-	fetcher3, shutdown3 := CreateStoredRequests(&cfg.CategoryMapping, metricsEngine, client, router, provider)
-	fetcher4, shutdown4 := CreateStoredRequests(&cfg.StoredVideo, metricsEngine, client, router, provider)
-	fetcher5, shutdown5 := CreateStoredRequests(&cfg.Accounts, metricsEngine, client, router, provider)
-	fetcher6, shutdown6 := CreateStoredRequests(&cfg.StoredResponses, metricsEngine, client, router, provider)
+	fetcher1, shutdown1 := CreateStoredRequests(&cfg.StoredRequests, metricsEngine, client, router, provider, decrypter, notifier.StoredDataObserver(config.RequestDataType), storedDataBus)
+	fetcher2, shutdown2 := CreateStoredRequests(&cfg.StoredRequestsAMP, metricsEngine, client, router, provider, decrypter, notifier.StoredDataObserver(config.AMPRequestDataType), storedDataBus)
+	fetcher3, shutdown3 := CreateStoredRequests(&cfg.CategoryMapping, metricsEngine, client, router, provider, decrypter, notifier.StoredDataObserver(config.CategoryDataType), storedDataBus)
+	fetcher4, shutdown4 := CreateStoredRequests(&cfg.StoredVideo, metricsEngine, client, router, provider, decrypter, notifier.StoredDataObserver(config.VideoDataType), storedDataBus)
+	fetcher5, shutdown5 := CreateStoredRequests(&cfg.Accounts, metricsEngine, client, router, provider, decrypter, notifier.StoredDataObserver(config.AccountDataType), storedDataBus)
+	fetcher6, shutdown6 := CreateStoredRequests(&cfg.StoredResponses, metricsEngine, client, router, provider, decrypter, notifier.StoredDataObserver(config.ResponseDataType), storedDataBus)
 
-	fetcher = fetcher1.(stored_requests.Fetcher)
-	ampFetcher = fetcher2.(stored_requests.Fetcher)
-	categoriesFetcher = fetcher3.(stored_requests.CategoryFetcher)
-	videoFetcher = fetcher4.(stored_requests.Fetcher)
-	accountsFetcher = fetcher5.(stored_requests.AccountFetcher)
-	storedRespFetcher = fetcher6.(stored_requests.Fetcher)
+	fetcher = withFaults(cfg, fetcher1)
+	ampFetcher = withFaults(cfg, fetcher2)
+	categoriesFetcher = withFaults(cfg, fetcher3)
+	videoFetcher = withFaults(cfg, fetcher4)
+	accountsFetcher = withFaults(cfg, fetcher5)
+	storedRespFetcher = withFaults(cfg, fetcher6)
 
 	shutdown = func() {
 		shutdown1()
@@ -128,16 +213,62 @@ func NewStoredRequests(cfg *config.Configuration, metricsEngine metrics.MetricsE
 		shutdown4()
 		shutdown5()
 		shutdown6()
+		if storedDataBus != nil {
+			storedDataBus.Close()
+		}
 	}
 
 	return
 }
 
-func addListeners(cache stored_requests.Cache, eventProducers []events.EventProducer) (shutdown func()) {
+// withFaults injects the configured faults into the calls to the fetcher, in front of its cache, so
+// cached data may fail too.
+func withFaults(cfg *config.Configuration, fetcher stored_requests.AllFetcher) stored_requests.AllFetcher {
+	return faultinjection.WithFetcherFaults(fetcher, cfg.FaultInjection.Enabled, cfg.FaultInjection.StoredRequests)
+}
+
+// newDecrypter returns the Decrypter for encrypted stored data, or nil if decryption is disabled.
+func newDecrypter(cfg *config.StoredDataEncryption, client *http.Client) *secrets.Decrypter {
+	if !cfg.Enabled {
+		return nil
+	}
+	if cfg.KeyProvider == config.StoredDataKeyProviderKMS {
+		logger.Infof("Decrypting stored data with data keys unwrapped by KMS endpoint %s", cfg.KMS.Endpoint)
+		return secrets.NewDecrypter(secrets.NewKMSKeyProvider(client, cfg.KMS.Endpoint, cfg.KMS.TimeoutDuration()))
+	}
+	masterKey, err := base64.StdEncoding.DecodeString(cfg.MasterKey)
+	if err != nil {
+		logger.Fatalf("Invalid stored_data_encryption.master_key: %v", err)
+	}
+	keyProvider, err := secrets.NewLocalKeyProvider(masterKey)
+	if err != nil {
+		logger.Fatalf("Invalid stored_data_encryption.master_key: %v", err)
+	}
+	return secrets.NewDecrypter(keyProvider)
+}
+
+// newStoredDataBus returns the Bus which propagates stored data events to the other instances, or nil if
+// it's disabled.
+func newStoredDataBus(cfg *config.StoredDataBus, metricsEngine metrics.MetricsEngine) *bus.Bus {
+	if !cfg.Enabled {
+		return nil
+	}
+	logger.Infof("Propagating stored data events to other instances on Redis channel %s. addrs=%v, cluster=%t", cfg.Channel, cfg.Redis.Addrs, cfg.Redis.Cluster)
+	storedDataBus, err := bus.NewBus(*cfg, metricsEngine)
+	if err != nil {
+		logger.Fatalf("Failed to create the stored data bus: %v", err)
+	}
+	return storedDataBus
+}
+
+func addListeners(cache stored_requests.Cache, eventProducers []events.EventProducer, observer events.Observer) (shutdown func()) {
 	listeners := make([]*events.EventListener, 0, len(eventProducers))
 
 	for _, ep := range eventProducers {
 		listener := events.SimpleEventListener()
+		if observer != nil {
+			listener = events.ObservedEventListener(observer)
+		}
 		go listener.Listen(cache, ep)
 		listeners = append(listeners, listener)
 	}
@@ -149,15 +280,17 @@ func addListeners(cache stored_requests.Cache, eventProducers []events.EventProd
 	}
 }
 
-func newFetcher(cfg *config.StoredRequests, client *http.Client, provider db_provider.DbProvider) (fetcher stored_requests.AllFetcher) {
-	idList := make(stored_requests.MultiFetcher, 0, 3)
+// newFetcher returns the consolidated fetcher for the configured backends, along with the filesystem
+// fetcher on its own if one is configured so it can be watched for changes.
+func newFetcher(cfg *config.StoredRequests, client *http.Client, provider db_provider.DbProvider, redisFetcher *redis_fetcher.RedisFetcher, bucketFetcher *bucket_fetcher.BucketFetcher) (fetcher stored_requests.AllFetcher, fileFetcher stored_requests.AllFetcher) {
+	idList := make(stored_requests.MultiFetcher, 0, 4)
 
 	if cfg.Files.Enabled {
-		fFetcher := newFilesystem(cfg.DataType(), cfg.Files.Path)
-		idList = append(idList, fFetcher)
+		fileFetcher = newFilesystem(cfg.DataType(), cfg.Files.Path)
+		idList = append(idList, fileFetcher)
 	}
 	if cfg.Database.FetcherQueries.QueryTemplate != "" {
-		glog.Infof("Loading Stored %s data via Database.\nQuery: %s", cfg.DataType(), cfg.Database.FetcherQueries.QueryTemplate)
+		logger.Infof("Loading Stored %s data via Database.\nQuery: %s", cfg.DataType(), cfg.Database.FetcherQueries.QueryTemplate)
 		idList = append(idList, db_fetcher.NewFetcher(provider,
 			cfg.Database.FetcherQueries.QueryTemplate, cfg.Database.FetcherQueries.QueryTemplate))
 	} else if cfg.Database.CacheInitialization.Query != "" && cfg.Database.PollUpdates.Query != "" {
@@ -165,24 +298,56 @@ func newFetcher(cfg *config.StoredRequests, client *http.Client, provider db_pro
 		idList = append(idList, empty_fetcher.EmptyFetcher{})
 	}
 	if cfg.HTTP.Endpoint != "" {
-		glog.Infof("Loading Stored %s data via HTTP. endpoint=%s", cfg.DataType(), cfg.HTTP.Endpoint)
+		logger.Infof("Loading Stored %s data via HTTP. endpoint=%s", cfg.DataType(), cfg.HTTP.Endpoint)
 		idList = append(idList, http_fetcher.NewFetcher(client, cfg.HTTP.Endpoint))
 	}
+	if redisFetcher != nil {
+		idList = append(idList, redisFetcher)
+	}
+	if bucketFetcher != nil {
+		idList = append(idList, bucketFetcher)
+	}
 
 	fetcher = consolidate(cfg.DataType(), idList)
 	return
 }
 
-func newCache(cfg *config.StoredRequests) stored_requests.Cache {
-	cache := stored_requests.Cache{
-		Requests:  &nil_cache.NilCache{},
-		Imps:      &nil_cache.NilCache{},
-		Responses: &nil_cache.NilCache{},
-		Accounts:  &nil_cache.NilCache{},
+func newRedis(cfg *config.StoredRequests) *redis_fetcher.RedisFetcher {
+	logger.Infof("Loading Stored %s data via Redis. addrs=%v, cluster=%t", cfg.DataType(), cfg.Redis.Addrs, cfg.Redis.Cluster)
+	fetcher, err := redis_fetcher.NewFetcher(cfg.Redis)
+	if err != nil {
+		logger.Fatalf("Failed to create a %s RedisFetcher: %v", cfg.DataType(), err)
+	}
+	return fetcher
+}
+
+func newBucket(cfg *config.StoredRequests, client *http.Client) *bucket_fetcher.BucketFetcher {
+	logger.Infof("Loading Stored %s data from %s bucket %s, prefix=%s", cfg.DataType(), cfg.Bucket.Provider, cfg.Bucket.Name, cfg.Bucket.Prefix)
+	fetcher, err := bucket_fetcher.NewFetcher(client, cfg.Bucket)
+	if err != nil {
+		logger.Fatalf("Failed to create a %s BucketFetcher: %v", cfg.DataType(), err)
+	}
+	return fetcher
+}
+
+// newBucketRefreshHandler returns a handler which syncs the bucket when called, rather than waiting for the
+// next sync.
+func newBucketRefreshHandler(dataType config.DataType, producer *filesEvents.FileEventProducer) httprouter.Handle {
+	return func(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+		if err := producer.Run(); err != nil {
+			w.WriteHeader(http.StatusBadGateway)
+			fmt.Fprintf(w, "Failed to sync Stored %s data: %v\n", dataType, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
 	}
+}
+
+func newCache(cfg *config.StoredRequests) stored_requests.Cache {
+	cache := newNilCache()
 	switch {
 	case cfg.InMemoryCache.Type == "none":
-		glog.Warningf("No %s cache configured. The %s Fetcher backend will be used for all data requests", cfg.DataType(), cfg.DataType())
+		logger.Warningf("No %s cache configured. The %s Fetcher backend will be used for all data requests", cfg.DataType(), cfg.DataType())
 	case cfg.DataType() == config.AccountDataType:
 		cache.Accounts = memory.NewCache(cfg.InMemoryCache.Size, cfg.InMemoryCache.TTL, "Accounts")
 	default:
@@ -193,9 +358,22 @@ func newCache(cfg *config.StoredRequests) stored_requests.Cache {
 	return cache
 }
 
-func newEventProducers(cfg *config.StoredRequests, client *http.Client, provider db_provider.DbProvider, metricsEngine metrics.MetricsEngine, router *httprouter.Router) (eventProducers []events.EventProducer) {
+func newNilCache() stored_requests.Cache {
+	return stored_requests.Cache{
+		Requests:  &nil_cache.NilCache{},
+		Imps:      &nil_cache.NilCache{},
+		Responses: &nil_cache.NilCache{},
+		Accounts:  &nil_cache.NilCache{},
+	}
+}
+
+func newEventProducers(cfg *config.StoredRequests, client *http.Client, provider db_provider.DbProvider, metricsEngine metrics.MetricsEngine, router *httprouter.Router, storedDataBus *bus.Bus) (eventProducers []events.EventProducer) {
 	if cfg.CacheEvents.Enabled {
-		eventProducers = append(eventProducers, newEventsAPI(router, cfg.CacheEvents.Endpoint))
+		apiEventProducer := newEventsAPI(router, cfg.CacheEvents.Endpoint)
+		if storedDataBus != nil {
+			apiEventProducer = storedDataBus.Relay(cfg.DataType(), apiEventProducer)
+		}
+		eventProducers = append(eventProducers, apiEventProducer)
 	}
 	if cfg.HTTPEvents.RefreshRate != 0 && cfg.HTTPEvents.Endpoint != "" {
 		eventProducers = append(eventProducers, newHttpEvents(client, cfg.HTTPEvents.TimeoutDuration(), cfg.HTTPEvents.RefreshRateDuration(), cfg.HTTPEvents.Endpoint))
@@ -219,6 +397,21 @@ func newEventProducers(cfg *config.StoredRequests, client *http.Client, provider
 	return
 }
 
+func newListenNotify(cfg *config.StoredRequests, provider db_provider.DbProvider, metricsEngine metrics.MetricsEngine) *postgresEvents.PostgresEventProducer {
+	connString, err := provider.ConnString()
+	if err != nil {
+		logger.Fatalf("Failed to build the connection string to LISTEN for Stored %s changes: %v", cfg.DataType(), err)
+	}
+	return postgresEvents.NewPostgresEventProducer(postgresEvents.PostgresEventProducerConfig{
+		ConnString:           connString,
+		Channel:              cfg.Database.ListenNotify.Channel,
+		RequestType:          cfg.DataType(),
+		MinReconnectInterval: cfg.Database.ListenNotify.MinReconnectIntervalDuration(),
+		MaxReconnectInterval: cfg.Database.ListenNotify.MaxReconnectIntervalDuration(),
+		MetricsEngine:        metricsEngine,
+	})
+}
+
 func newEventsAPI(router *httprouter.Router, endpoint string) events.EventProducer {
 	producer, handler := apiEvents.NewEventsAPI()
 	router.POST(endpoint, handler)
@@ -234,10 +427,10 @@ func newHttpEvents(client *http.Client, timeout time.Duration, refreshRate time.
 }
 
 func newFilesystem(dataType config.DataType, configPath string) stored_requests.AllFetcher {
-	glog.Infof("Loading Stored %s data from filesystem at path %s", dataType, configPath)
+	logger.Infof("Loading Stored %s data from filesystem at path %s", dataType, configPath)
 	fetcher, err := file_fetcher.NewFileFetcher(configPath)
 	if err != nil {
-		glog.Fatalf("Failed to create a %s FileFetcher: %v", dataType, err)
+		logger.Fatalf("Failed to create a %s FileFetcher: %v", dataType, err)
 	}
 	return fetcher
 }
@@ -247,9 +440,9 @@ func consolidate(dataType config.DataType, fetchers []stored_requests.AllFetcher
 	if len(fetchers) == 0 {
 		switch dataType {
 		case config.RequestDataType:
-			glog.Warning("No Stored Request support configured. request.imp[i].ext.prebid.storedrequest will be ignored. If you need this, check your app config")
+			logger.Warning("No Stored Request support configured. request.imp[i].ext.prebid.storedrequest will be ignored. If you need this, check your app config")
 		default:
-			glog.Warningf("No Stored %s support configured. If you need this, check your app config", dataType)
+			logger.Warningf("No Stored %s support configured. If you need this, check your app config", dataType)
 		}
 		return empty_fetcher.EmptyFetcher{}
 	} else if len(fetchers) == 1 {
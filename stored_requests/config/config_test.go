@@ -4,14 +4,19 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"regexp"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
 	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/alicebob/miniredis/v2"
 	"github.com/julienschmidt/httprouter"
 	"github.com/prebid/prebid-server/v2/config"
 	"github.com/prebid/prebid-server/v2/metrics"
@@ -19,9 +24,11 @@ import (
 	"github.com/prebid/prebid-server/v2/stored_requests/backends/db_provider"
 	"github.com/prebid/prebid-server/v2/stored_requests/backends/empty_fetcher"
 	"github.com/prebid/prebid-server/v2/stored_requests/backends/http_fetcher"
+	"github.com/prebid/prebid-server/v2/stored_requests/backends/redis_fetcher"
 	"github.com/prebid/prebid-server/v2/stored_requests/events"
 	httpEvents "github.com/prebid/prebid-server/v2/stored_requests/events/http"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 func typedConfig(dataType config.DataType, sr *config.StoredRequests) *config.StoredRequests {
@@ -117,7 +124,7 @@ func TestNewEmptyFetcher(t *testing.T) {
 	}
 
 	for _, test := range testCases {
-		fetcher := newFetcher(test.config, nil, db_provider.DbProviderMock{})
+		fetcher, _ := newFetcher(test.config, nil, db_provider.DbProviderMock{}, nil, nil)
 		assert.NotNil(t, fetcher, "The fetcher should be non-nil.")
 		if test.emptyFetcher {
 			assert.Equal(t, empty_fetcher.EmptyFetcher{}, fetcher, "Empty fetcher should be returned")
@@ -128,11 +135,11 @@ func TestNewEmptyFetcher(t *testing.T) {
 }
 
 func TestNewHTTPFetcher(t *testing.T) {
-	fetcher := newFetcher(&config.StoredRequests{
+	fetcher, _ := newFetcher(&config.StoredRequests{
 		HTTP: config.HTTPFetcherConfig{
 			Endpoint: "stored-requests.prebid.com",
 		},
-	}, nil, nil)
+	}, nil, nil, nil, nil)
 	if httpFetcher, ok := fetcher.(*http_fetcher.HttpFetcher); ok {
 		if httpFetcher.Endpoint != "stored-requests.prebid.com?" {
 			t.Errorf("The HTTP fetcher is using the wrong endpoint. Expected %s, got %s", "stored-requests.prebid.com?", httpFetcher.Endpoint)
@@ -142,6 +149,17 @@ func TestNewHTTPFetcher(t *testing.T) {
 	}
 }
 
+func TestNewRedisFetcher(t *testing.T) {
+	redisFetcher, err := redis_fetcher.NewFetcher(config.RedisFetcherConfig{RedisConnection: config.RedisConnection{Addrs: []string{"localhost:6379"}}, Timeout: 100})
+	require.NoError(t, err)
+	defer redisFetcher.Close()
+
+	fetcher, _ := newFetcher(&config.StoredRequests{
+		Redis: config.RedisFetcherConfig{RedisConnection: config.RedisConnection{Addrs: []string{"localhost:6379"}}, Timeout: 100},
+	}, nil, nil, redisFetcher, nil)
+	assert.Equal(t, redisFetcher, fetcher)
+}
+
 func TestNewHTTPEvents(t *testing.T) {
 	handler := func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusInternalServerError)
@@ -158,7 +176,7 @@ func TestNewHTTPEvents(t *testing.T) {
 
 	metricsMock := &metrics.MetricsEngineMock{}
 
-	evProducers := newEventProducers(cfg, server1.Client(), nil, metricsMock, nil)
+	evProducers := newEventProducers(cfg, server1.Client(), nil, metricsMock, nil, nil)
 	assertSliceLength(t, evProducers, 1)
 	assertHttpWithURL(t, evProducers[0], server1.URL)
 }
@@ -224,7 +242,7 @@ func TestNewDatabaseEventProducers(t *testing.T) {
 	}
 	mock.ExpectQuery("^" + regexp.QuoteMeta(cfg.Database.CacheInitialization.Query) + "$").WillReturnError(errors.New("Query failed"))
 
-	evProducers := newEventProducers(cfg, client, provider, metricsMock, nil)
+	evProducers := newEventProducers(cfg, client, provider, metricsMock, nil, nil)
 	assertProducerLength(t, evProducers, 1)
 
 	assertExpectationsMet(t, mock)
@@ -242,6 +260,124 @@ func TestNewEventsAPI(t *testing.T) {
 	}
 }
 
+func TestCreateStoredRequestsObservedWithoutCache(t *testing.T) {
+	router := httprouter.New()
+	observer := &fakeObserver{saves: make(chan events.Save, 1)}
+	cfg := typedConfig(config.AccountDataType, &config.StoredRequests{
+		CacheEvents: config.CacheEventsConfig{Enabled: true, Endpoint: "/accounts"},
+	})
+	_, shutdown := CreateStoredRequests(cfg, &metrics.MetricsEngineMock{}, nil, router, nil, nil, observer, nil)
+	defer shutdown()
+
+	handle, _, _ := router.Lookup("POST", "/accounts")
+	require.NotNil(t, handle)
+	request := httptest.NewRequest("POST", "/accounts", strings.NewReader(`{"accounts":{"1001":{"id":"1001"}}}`))
+	handle(httptest.NewRecorder(), request, nil)
+
+	save := <-observer.saves
+	assert.Equal(t, map[string]json.RawMessage{"1001": json.RawMessage(`{"id":"1001"}`)}, save.Accounts)
+}
+
+func TestCreateStoredRequestsWithBus(t *testing.T) {
+	server := miniredis.RunT(t)
+	busConfig := config.StoredDataBus{Enabled: true, Channel: "pbs-stored-data", Timeout: 1000, Redis: config.RedisConnection{Addrs: []string{server.Addr()}}}
+	metricsEngine := &metrics.MetricsEngineMock{}
+	metricsEngine.On("RecordAccountCacheResult", mock.Anything, mock.Anything)
+
+	// two instances of a fleet, each with its own cache
+	newInstance := func() (stored_requests.AllFetcher, *httprouter.Router) {
+		router := httprouter.New()
+		storedDataBus := newStoredDataBus(&busConfig, metricsEngine)
+		cfg := typedConfig(config.AccountDataType, &config.StoredRequests{
+			CacheEvents:   config.CacheEventsConfig{Enabled: true, Endpoint: "/accounts"},
+			InMemoryCache: config.InMemoryCache{Type: "unbounded", TTL: -1},
+		})
+		fetcher, shutdown := CreateStoredRequests(cfg, metricsEngine, nil, router, nil, nil, nil, storedDataBus)
+		t.Cleanup(func() {
+			shutdown()
+			storedDataBus.Close()
+		})
+		return fetcher, router
+	}
+	fetcher1, router1 := newInstance()
+	fetcher2, _ := newInstance()
+	require.Eventually(t, func() bool {
+		return server.PubSubNumSub("pbs-stored-data")["pbs-stored-data"] == 2
+	}, time.Second, 5*time.Millisecond)
+
+	handle, _, _ := router1.Lookup("POST", "/accounts")
+	require.NotNil(t, handle)
+	handle(httptest.NewRecorder(), httptest.NewRequest("POST", "/accounts", strings.NewReader(`{"accounts":{"1001":{"id":"1001"}}}`)), nil)
+
+	for _, fetcher := range []stored_requests.AllFetcher{fetcher1, fetcher2} {
+		assert.Eventually(t, func() bool {
+			account, errs := fetcher.FetchAccount(context.Background(), json.RawMessage(`{}`), "1001")
+			return len(errs) == 0 && string(account) == `{"id":"1001"}`
+		}, time.Second, 5*time.Millisecond)
+	}
+}
+
+func TestCreateStoredRequestsWithBucket(t *testing.T) {
+	var lock sync.Mutex
+	account := `{"id":"1001","disabled":true}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		defer lock.Unlock()
+		if r.URL.Path == "/pbs-data" {
+			fmt.Fprintf(w, `<ListBucketResult><Contents><Key>accounts/1001.json</Key><ETag>"%d"</ETag></Contents></ListBucketResult>`, len(account))
+			return
+		}
+		fmt.Fprint(w, account)
+	}))
+	defer server.Close()
+
+	metricsEngine := &metrics.MetricsEngineMock{}
+	metricsEngine.On("RecordAccountCacheResult", mock.Anything, mock.Anything)
+	router := httprouter.New()
+	cfg := typedConfig(config.AccountDataType, &config.StoredRequests{
+		Bucket:        config.BucketFetcherConfig{Provider: "gcs", Name: "pbs-data", Endpoint: server.URL, Timeout: 1000, RefreshEndpoint: "/accounts/refresh"},
+		InMemoryCache: config.InMemoryCache{Type: "unbounded", TTL: -1},
+	})
+	fetcher, shutdown := CreateStoredRequests(cfg, metricsEngine, server.Client(), router, nil, nil, nil, nil)
+	defer shutdown()
+
+	data, errs := fetcher.FetchAccount(context.Background(), json.RawMessage(`{}`), "1001")
+	assert.Empty(t, errs)
+	assert.JSONEq(t, `{"id":"1001","disabled":true}`, string(data))
+
+	lock.Lock()
+	account = `{"id":"1001","disabled":false}`
+	lock.Unlock()
+	handle, _, _ := router.Lookup("POST", "/accounts/refresh")
+	require.NotNil(t, handle)
+	recorder := httptest.NewRecorder()
+	handle(recorder, httptest.NewRequest("POST", "/accounts/refresh", nil), nil)
+	assert.Equal(t, http.StatusNoContent, recorder.Code)
+
+	// the cached copy is invalidated by the listener
+	assert.Eventually(t, func() bool {
+		data, errs := fetcher.FetchAccount(context.Background(), json.RawMessage(`{}`), "1001")
+		return len(errs) == 0 && strings.Contains(string(data), `"disabled":false`)
+	}, time.Second, 5*time.Millisecond)
+
+	server.Close()
+	recorder = httptest.NewRecorder()
+	handle(recorder, httptest.NewRequest("POST", "/accounts/refresh", nil), nil)
+	assert.Equal(t, http.StatusBadGateway, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "Failed to sync Stored Account data")
+}
+
+type fakeObserver struct {
+	saves chan events.Save
+}
+
+func (o *fakeObserver) Saved(save events.Save) {
+	o.saves <- save
+}
+
+func (o *fakeObserver) Invalidated(invalidation events.Invalidation) {
+}
+
 func assertProducerLength(t *testing.T, producers []events.EventProducer, expectedLength int) {
 	t.Helper()
 	if len(producers) != expectedLength {
@@ -0,0 +1,264 @@
+package bus
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/logger"
+	"github.com/prebid/prebid-server/v2/metrics"
+	"github.com/prebid/prebid-server/v2/stored_requests/events"
+	"github.com/prebid/prebid-server/v2/util/jsonutil"
+	"github.com/prebid/prebid-server/v2/util/redisutil"
+	"github.com/prebid/prebid-server/v2/util/uuidutil"
+)
+
+var storedDataTypeMetricMap = map[config.DataType]metrics.StoredDataType{
+	config.RequestDataType:    metrics.RequestDataType,
+	config.CategoryDataType:   metrics.CategoryDataType,
+	config.VideoDataType:      metrics.VideoDataType,
+	config.AMPRequestDataType: metrics.AMPDataType,
+	config.AccountDataType:    metrics.AccountDataType,
+	config.ResponseDataType:   metrics.ResponseDataType,
+}
+
+// message is what is published on the channel for each event. Instance is the ID of the publishing
+// instance, so it can skip its own events, which it has applied already.
+type message struct {
+	Instance     string               `json:"instance"`
+	DataType     config.DataType      `json:"data_type"`
+	Save         *events.Save         `json:"save,omitempty"`
+	Invalidation *events.Invalidation `json:"invalidation,omitempty"`
+}
+
+// Bus propagates the saves and invalidations of Stored Data received by one instance to every other
+// instance of the fleet through a Redis pub/sub channel.
+//
+// The events of a producer passed to Relay are published after they're passed on locally, and the events
+// published by other instances come out of the producer returned by Subscribe for their data type. Events
+// published while an instance is disconnected from Redis are missed by it, so the caches should keep a
+// TTL as a fallback.
+type Bus struct {
+	client        redis.UniversalClient
+	pubsub        *redis.PubSub
+	channel       string
+	timeout       time.Duration
+	instance      string
+	metricsEngine metrics.MetricsEngine
+
+	lock        sync.Mutex
+	subscribers map[config.DataType]*producer
+	stop        chan struct{}
+	closeOnce   sync.Once
+}
+
+// NewBus connects to the Redis server or cluster of the config and subscribes to the channel. The
+// connection is made in the background, and remade if it's lost.
+func NewBus(cfg config.StoredDataBus, metricsEngine metrics.MetricsEngine) (*Bus, error) {
+	client, err := redisutil.NewClient(cfg.Redis)
+	if err != nil {
+		return nil, err
+	}
+	instance, err := uuidutil.UUIDRandomGenerator{}.Generate()
+	if err != nil {
+		return nil, err
+	}
+	return newBus(client, cfg.Channel, cfg.TimeoutDuration(), instance, metricsEngine), nil
+}
+
+func newBus(client redis.UniversalClient, channel string, timeout time.Duration, instance string, metricsEngine metrics.MetricsEngine) *Bus {
+	b := &Bus{
+		client:        client,
+		pubsub:        client.Subscribe(context.Background(), channel),
+		channel:       channel,
+		timeout:       timeout,
+		instance:      instance,
+		metricsEngine: metricsEngine,
+		subscribers:   make(map[config.DataType]*producer),
+		stop:          make(chan struct{}),
+	}
+	go b.receive(b.pubsub.Channel())
+	return b
+}
+
+// Relay returns a producer of the events of the source, which also publishes them for the other instances.
+func (b *Bus) Relay(dataType config.DataType, source events.EventProducer) events.EventProducer {
+	relayed := newProducer()
+	go func() {
+		for {
+			select {
+			case save := <-source.Saves():
+				if !relayed.sendSave(save, b.stop) {
+					return
+				}
+				b.publish(message{DataType: dataType, Save: &save})
+			case invalidation := <-source.Invalidations():
+				if !relayed.sendInvalidation(invalidation, b.stop) {
+					return
+				}
+				b.publish(message{DataType: dataType, Invalidation: &invalidation})
+			case <-b.stop:
+				return
+			}
+		}
+	}()
+	return relayed
+}
+
+// Subscribe returns a producer of the events of the data type published by the other instances.
+func (b *Bus) Subscribe(dataType config.DataType) events.EventProducer {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if p, ok := b.subscribers[dataType]; ok {
+		return p
+	}
+	p := newProducer()
+	b.subscribers[dataType] = p
+	return p
+}
+
+// Close stops relaying and receiving events, and closes the connections to Redis.
+func (b *Bus) Close() {
+	b.closeOnce.Do(func() {
+		close(b.stop)
+		if err := b.pubsub.Close(); err != nil {
+			logger.Warningf("Failed to unsubscribe from the stored data bus channel %s: %v", b.channel, err)
+		}
+		if err := b.client.Close(); err != nil {
+			logger.Warningf("Failed to close the stored data bus Redis connections: %v", err)
+		}
+	})
+}
+
+func (b *Bus) publish(msg message) {
+	msg.Instance = b.instance
+	payload, err := jsonutil.Marshal(msg)
+	if err != nil {
+		logger.Errorf("Failed to encode a Stored %s event for the stored data bus: %v", msg.DataType, err)
+		b.recordError(msg.DataType, metrics.StoredDataErrorUndefined)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), b.timeout)
+	defer cancel()
+	if err := b.client.Publish(ctx, b.channel, payload).Err(); err != nil {
+		logger.Errorf("Failed to publish a Stored %s event on the stored data bus channel %s. Other instances keep their data until it expires: %v", msg.DataType, b.channel, err)
+		b.recordError(msg.DataType, metrics.StoredDataErrorNetwork)
+	}
+}
+
+func (b *Bus) receive(messages <-chan *redis.Message) {
+	for {
+		select {
+		case <-b.stop:
+			return
+		case redisMessage, ok := <-messages:
+			if !ok {
+				return
+			}
+			b.handleMessage(redisMessage)
+		}
+	}
+}
+
+func (b *Bus) handleMessage(redisMessage *redis.Message) {
+	var msg message
+	if err := jsonutil.UnmarshalValid([]byte(redisMessage.Payload), &msg); err != nil {
+		logger.Warningf("Message on the stored data bus channel %s is malformed and will be ignored: %v", b.channel, err)
+		b.recordError(msg.DataType, metrics.StoredDataErrorUndefined)
+		return
+	}
+	if msg.Instance == b.instance {
+		return
+	}
+
+	b.lock.Lock()
+	subscriber, ok := b.subscribers[msg.DataType]
+	b.lock.Unlock()
+	if !ok {
+		return
+	}
+
+	if msg.Save != nil {
+		subscriber.sendSave(*msg.Save, b.stop)
+	}
+	if msg.Invalidation != nil {
+		subscriber.sendInvalidation(*msg.Invalidation, b.stop)
+	}
+}
+
+// recordError records the error for the data type. Errors of messages whose data type is unknown, such as
+// messages which can't be parsed, are only logged, since the metrics have no data type to count them under.
+func (b *Bus) recordError(dataType config.DataType, errorType metrics.StoredDataError) {
+	metricDataType, ok := storedDataTypeMetricMap[dataType]
+	if !ok {
+		return
+	}
+	b.metricsEngine.RecordStoredDataError(
+		metrics.StoredDataLabels{
+			DataType: metricDataType,
+			Error:    errorType,
+		})
+}
+
+// producer is an EventProducer whose events are sent to it by the bus
+type producer struct {
+	saves         chan events.Save
+	invalidations chan events.Invalidation
+}
+
+func newProducer() *producer {
+	return &producer{
+		saves:         make(chan events.Save),
+		invalidations: make(chan events.Invalidation),
+	}
+}
+
+func (p *producer) Saves() <-chan events.Save {
+	return p.saves
+}
+
+func (p *producer) Invalidations() <-chan events.Invalidation {
+	return p.invalidations
+}
+
+// sendSave sends the save unless the bus is stopped first, and returns whether it was sent.
+func (p *producer) sendSave(save events.Save, stop <-chan struct{}) bool {
+	if stopped(stop) {
+		return false
+	}
+	select {
+	case p.saves <- save:
+		return true
+	case <-stop:
+		return false
+	}
+}
+
+// sendInvalidation sends the invalidation unless the bus is stopped first, and returns whether it was sent.
+func (p *producer) sendInvalidation(invalidation events.Invalidation, stop <-chan struct{}) bool {
+	if stopped(stop) {
+		return false
+	}
+	select {
+	case p.invalidations <- invalidation:
+		return true
+	case <-stop:
+		return false
+	}
+}
+
+// stopped returns whether the bus is stopped. The events a stopped bus still has are dropped rather than
+// sent if the receiver happens to be ready before the stop is seen.
+func stopped(stop <-chan struct{}) bool {
+	select {
+	case <-stop:
+		return true
+	default:
+		return false
+	}
+}
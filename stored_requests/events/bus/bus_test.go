@@ -0,0 +1,164 @@
+package bus
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/metrics"
+	"github.com/prebid/prebid-server/v2/stored_requests/events"
+	gometrics "github.com/rcrowley/go-metrics"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const testChannel = "pbs-stored-data"
+
+func newTestBus(t *testing.T, server *miniredis.Miniredis, instance string, metricsEngine metrics.MetricsEngine) *Bus {
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	b := newBus(client, testChannel, time.Second, instance, metricsEngine)
+	t.Cleanup(b.Close)
+	return b
+}
+
+// waitForSubscribers waits for the buses to have subscribed, since events published before are missed
+func waitForSubscribers(t *testing.T, server *miniredis.Miniredis, count int) {
+	require.Eventually(t, func() bool {
+		return server.PubSubNumSub(testChannel)[testChannel] == count
+	}, time.Second, 5*time.Millisecond)
+}
+
+type fakeProducer struct {
+	saves         chan events.Save
+	invalidations chan events.Invalidation
+}
+
+func (p *fakeProducer) Saves() <-chan events.Save {
+	return p.saves
+}
+
+func (p *fakeProducer) Invalidations() <-chan events.Invalidation {
+	return p.invalidations
+}
+
+func TestRelayToOtherInstances(t *testing.T) {
+	server := miniredis.RunT(t)
+	source := &fakeProducer{saves: make(chan events.Save), invalidations: make(chan events.Invalidation)}
+
+	publisher := newTestBus(t, server, "instance-1", &metrics.MetricsEngineMock{})
+	relayed := publisher.Relay(config.AccountDataType, source)
+	ownEvents := publisher.Subscribe(config.AccountDataType)
+
+	receiver := newTestBus(t, server, "instance-2", &metrics.MetricsEngineMock{})
+	remoteAccountEvents := receiver.Subscribe(config.AccountDataType)
+	remoteRequestEvents := receiver.Subscribe(config.RequestDataType)
+	waitForSubscribers(t, server, 2)
+
+	save := events.Save{Accounts: map[string]json.RawMessage{"acc-1": json.RawMessage(`{"disabled":true}`)}}
+	source.saves <- save
+	assert.Equal(t, save, <-relayed.Saves(), "the save should be passed on locally")
+	assert.Equal(t, save, <-remoteAccountEvents.Saves(), "the save should be received by the other instance")
+
+	invalidation := events.Invalidation{Accounts: []string{"acc-1"}}
+	source.invalidations <- invalidation
+	assert.Equal(t, invalidation, <-relayed.Invalidations())
+	assert.Equal(t, invalidation, <-remoteAccountEvents.Invalidations())
+
+	// an instance skips its own events, and the events of other data types
+	select {
+	case <-ownEvents.Invalidations():
+		t.Error("the publisher shouldn't receive its own events")
+	case <-remoteRequestEvents.Invalidations():
+		t.Error("the events of accounts shouldn't be received as requests")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestSubscribeReturnsSameProducer(t *testing.T) {
+	b := newTestBus(t, miniredis.RunT(t), "instance-1", &metrics.MetricsEngineMock{})
+
+	assert.Same(t, b.Subscribe(config.RequestDataType), b.Subscribe(config.RequestDataType))
+	assert.NotSame(t, b.Subscribe(config.RequestDataType), b.Subscribe(config.AMPRequestDataType))
+}
+
+func TestMalformedMessage(t *testing.T) {
+	testCases := []struct {
+		description string
+		payload     string
+	}{
+		{
+			description: "truncated-json",
+			payload:     `{"data_type":`,
+		},
+		{
+			description: "not-json",
+			payload:     `not json`,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			server := miniredis.RunT(t)
+			// the go-metrics engine has no meters for an unknown data type, so it panics if one is recorded
+			metricsEngine := metrics.NewBlankMetrics(gometrics.NewRegistry(), nil, config.DisabledMetrics{}, nil)
+
+			b := newTestBus(t, server, "instance-1", metricsEngine)
+			subscriber := b.Subscribe(config.RequestDataType)
+			waitForSubscribers(t, server, 1)
+
+			server.Publish(testChannel, test.payload)
+			server.Publish(testChannel, `{"instance":"instance-2","data_type":"Request","invalidation":{"requests":["req-1"]}}`)
+
+			assert.Equal(t, events.Invalidation{Requests: []string{"req-1"}}, <-subscriber.Invalidations())
+		})
+	}
+}
+
+func TestPublishError(t *testing.T) {
+	server := miniredis.RunT(t)
+	metricsEngine := &metrics.MetricsEngineMock{}
+	recorded := make(chan struct{})
+	metricsEngine.On("RecordStoredDataError", metrics.StoredDataLabels{
+		DataType: metrics.RequestDataType,
+		Error:    metrics.StoredDataErrorNetwork,
+	}).Once().Run(func(mock.Arguments) { close(recorded) })
+	source := &fakeProducer{saves: make(chan events.Save), invalidations: make(chan events.Invalidation)}
+
+	b := newTestBus(t, server, "instance-1", metricsEngine)
+	relayed := b.Relay(config.RequestDataType, source)
+	server.Close()
+
+	// the event is still passed on locally
+	source.invalidations <- events.Invalidation{Requests: []string{"req-1"}}
+	assert.Equal(t, events.Invalidation{Requests: []string{"req-1"}}, <-relayed.Invalidations())
+	select {
+	case <-recorded:
+	case <-time.After(time.Second):
+		t.Error("the publish error should have been recorded")
+	}
+}
+
+func TestClose(t *testing.T) {
+	source := &fakeProducer{saves: make(chan events.Save), invalidations: make(chan events.Invalidation)}
+	b := newTestBus(t, miniredis.RunT(t), "instance-1", &metrics.MetricsEngineMock{})
+	relayed := b.Relay(config.RequestDataType, source)
+
+	b.Close()
+	b.Close()
+
+	go func() {
+		select {
+		case source.saves <- events.Save{}:
+		case <-time.After(100 * time.Millisecond):
+		}
+	}()
+	select {
+	case <-relayed.Saves():
+		t.Error("a closed bus shouldn't relay events")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
@@ -29,12 +29,19 @@ type EventProducer interface {
 	Invalidations() <-chan Invalidation
 }
 
+// Observer is told about the saves and invalidations a listener has propagated to the cache
+type Observer interface {
+	Saved(save Save)
+	Invalidated(invalidation Invalidation)
+}
+
 // EventListener provides information about how many events a listener has processed
 // and a mechanism to stop the listener goroutine
 type EventListener struct {
 	stop         chan struct{}
 	onSave       func()
 	onInvalidate func()
+	observer     Observer
 }
 
 // SimpleEventListener creates a new EventListener that solely propagates cache updates and invalidations
@@ -55,6 +62,15 @@ func NewEventListener(onSave func(), onInvalidate func()) *EventListener {
 	}
 }
 
+// ObservedEventListener creates a new EventListener that tells the observer about the cache updates and
+// invalidations it propagates
+func ObservedEventListener(observer Observer) *EventListener {
+	return &EventListener{
+		stop:     make(chan struct{}),
+		observer: observer,
+	}
+}
+
 // Stop the event listener
 func (e *EventListener) Stop() {
 	e.stop <- struct{}{}
@@ -72,6 +88,9 @@ func (e *EventListener) Listen(cache stored_requests.Cache, events EventProducer
 			if e.onSave != nil {
 				e.onSave()
 			}
+			if e.observer != nil {
+				e.observer.Saved(save)
+			}
 		case invalidation := <-events.Invalidations():
 			cache.Requests.Invalidate(context.Background(), invalidation.Requests)
 			cache.Imps.Invalidate(context.Background(), invalidation.Imps)
@@ -80,6 +99,9 @@ func (e *EventListener) Listen(cache stored_requests.Cache, events EventProducer
 			if e.onInvalidate != nil {
 				e.onInvalidate()
 			}
+			if e.observer != nil {
+				e.observer.Invalidated(invalidation)
+			}
 		case <-e.stop:
 			return
 		}
@@ -87,6 +87,55 @@ func TestListen(t *testing.T) {
 	}
 }
 
+func TestListenObserved(t *testing.T) {
+	ep := &fakeProducer{
+		saves:         make(chan Save),
+		invalidations: make(chan Invalidation),
+	}
+	cache := stored_requests.Cache{
+		Requests:  memory.NewCache(256*1024, -1, "Requests"),
+		Imps:      memory.NewCache(256*1024, -1, "Imps"),
+		Responses: memory.NewCache(256*1024, -1, "Responses"),
+		Accounts:  memory.NewCache(256*1024, -1, "Account"),
+	}
+	observer := &fakeObserver{
+		saves:         make(chan Save),
+		invalidations: make(chan Invalidation),
+	}
+	listener := ObservedEventListener(observer)
+
+	go listener.Listen(cache, ep)
+	defer listener.Stop()
+
+	save := Save{Accounts: map[string]json.RawMessage{"1": json.RawMessage(`{"id": "1"}`)}}
+	ep.saves <- save
+	if observed := <-observer.saves; !reflect.DeepEqual(observed, save) {
+		t.Errorf("Observed save %v, expected %v", observed, save)
+	}
+	if accountData := cache.Accounts.Get(context.Background(), []string{"1"}); !reflect.DeepEqual(accountData, save.Accounts) {
+		t.Error("The save should be propagated to the cache before it's observed")
+	}
+
+	invalidation := Invalidation{Accounts: []string{"1"}}
+	ep.invalidations <- invalidation
+	if observed := <-observer.invalidations; !reflect.DeepEqual(observed, invalidation) {
+		t.Errorf("Observed invalidation %v, expected %v", observed, invalidation)
+	}
+}
+
+type fakeObserver struct {
+	saves         chan Save
+	invalidations chan Invalidation
+}
+
+func (o *fakeObserver) Saved(save Save) {
+	o.saves <- save
+}
+
+func (o *fakeObserver) Invalidated(invalidation Invalidation) {
+	o.invalidations <- invalidation
+}
+
 type fakeProducer struct {
 	saves         chan Save
 	invalidations chan Invalidation
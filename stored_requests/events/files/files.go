@@ -0,0 +1,75 @@
+package files
+
+import (
+	"sync"
+
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/logger"
+	"github.com/prebid/prebid-server/v2/stored_requests/events"
+)
+
+// Refresher is implemented by fetchers which can reload their data at runtime, like from disk or a bucket.
+type Refresher interface {
+	Refresh() (events.Invalidation, error)
+}
+
+// FileEventProducer reloads a filesystem backed fetcher each time it is run and produces invalidation
+// events for the stored data which changed on disk. It is intended to be run periodically with a
+// task.TickerTask so that updates to mounted volumes, such as Kubernetes ConfigMaps and Secrets,
+// are picked up without restarting the server.
+type FileEventProducer struct {
+	dataType      config.DataType
+	refresher     Refresher
+	lock          sync.Mutex
+	pending       events.Invalidation
+	saves         chan events.Save
+	invalidations chan events.Invalidation
+}
+
+func NewFileEventProducer(dataType config.DataType, refresher Refresher) *FileEventProducer {
+	return &FileEventProducer{
+		dataType:      dataType,
+		refresher:     refresher,
+		saves:         make(chan events.Save, 1),
+		invalidations: make(chan events.Invalidation, 1),
+	}
+}
+
+// Run reloads the fetcher data. Invalidations which can't be delivered because a previous event has not
+// been consumed yet are kept and merged into the next event, so no change is lost. It may be run by more
+// than one caller, like a ticker and an admin endpoint.
+func (e *FileEventProducer) Run() error {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	invalidation, err := e.refresher.Refresh()
+	if err != nil {
+		logger.Warningf("Failed to reload Stored %s data: %v", e.dataType, err)
+		return err
+	}
+
+	e.pending.Requests = append(e.pending.Requests, invalidation.Requests...)
+	e.pending.Imps = append(e.pending.Imps, invalidation.Imps...)
+	e.pending.Accounts = append(e.pending.Accounts, invalidation.Accounts...)
+	e.pending.Responses = append(e.pending.Responses, invalidation.Responses...)
+
+	if len(e.pending.Requests)+len(e.pending.Imps)+len(e.pending.Accounts)+len(e.pending.Responses) == 0 {
+		return nil
+	}
+
+	select {
+	case e.invalidations <- e.pending:
+		logger.Infof("Reloaded Stored %s data", e.dataType)
+		e.pending = events.Invalidation{}
+	default:
+	}
+	return nil
+}
+
+func (e *FileEventProducer) Saves() <-chan events.Save {
+	return e.saves
+}
+
+func (e *FileEventProducer) Invalidations() <-chan events.Invalidation {
+	return e.invalidations
+}
@@ -0,0 +1,65 @@
+package files
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/stored_requests/events"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeRefresher struct {
+	invalidations []events.Invalidation
+	err           error
+}
+
+func (r *fakeRefresher) Refresh() (events.Invalidation, error) {
+	if r.err != nil {
+		return events.Invalidation{}, r.err
+	}
+	invalidation := r.invalidations[0]
+	r.invalidations = r.invalidations[1:]
+	return invalidation, nil
+}
+
+func TestRunSendsInvalidations(t *testing.T) {
+	refresher := &fakeRefresher{invalidations: []events.Invalidation{
+		{Requests: []string{"req1"}},
+		{},
+		{Imps: []string{"imp1"}, Accounts: []string{"acc1"}},
+	}}
+	producer := NewFileEventProducer(config.RequestDataType, refresher)
+
+	assert.NoError(t, producer.Run())
+	assert.Equal(t, events.Invalidation{Requests: []string{"req1"}}, <-producer.Invalidations())
+
+	assert.NoError(t, producer.Run())
+	assert.Len(t, producer.Invalidations(), 0, "no event expected when nothing changed")
+
+	assert.NoError(t, producer.Run())
+	assert.Equal(t, events.Invalidation{Imps: []string{"imp1"}, Accounts: []string{"acc1"}}, <-producer.Invalidations())
+}
+
+func TestRunMergesUndeliveredInvalidations(t *testing.T) {
+	refresher := &fakeRefresher{invalidations: []events.Invalidation{
+		{Requests: []string{"req1"}},
+		{Requests: []string{"req2"}},
+		{Imps: []string{"imp1"}},
+	}}
+	producer := NewFileEventProducer(config.RequestDataType, refresher)
+
+	assert.NoError(t, producer.Run())
+	assert.NoError(t, producer.Run())
+	assert.Equal(t, events.Invalidation{Requests: []string{"req1"}}, <-producer.Invalidations())
+
+	assert.NoError(t, producer.Run())
+	assert.Equal(t, events.Invalidation{Requests: []string{"req2"}, Imps: []string{"imp1"}}, <-producer.Invalidations())
+}
+
+func TestRunRefreshError(t *testing.T) {
+	producer := NewFileEventProducer(config.RequestDataType, &fakeRefresher{err: errors.New("failed")})
+
+	assert.Error(t, producer.Run())
+	assert.Len(t, producer.Invalidations(), 0)
+}
@@ -12,10 +12,9 @@ import (
 	"golang.org/x/net/context/ctxhttp"
 
 	"github.com/buger/jsonparser"
+	"github.com/prebid/prebid-server/v2/logger"
 	"github.com/prebid/prebid-server/v2/stored_requests/events"
 	"github.com/prebid/prebid-server/v2/util/jsonutil"
-
-	"github.com/golang/glog"
 )
 
 // NewHTTPEvents makes an EventProducer which creates events by pinging an external HTTP API
@@ -77,7 +76,7 @@ func NewHTTPEvents(client *httpCore.Client, endpoint string, ctxProducer func()
 		saves:         make(chan events.Save, 1),
 		invalidations: make(chan events.Invalidation, 1),
 	}
-	glog.Infof("Loading HTTP cache from GET %s", endpoint)
+	logger.Infof("Loading HTTP cache from GET %s", endpoint)
 	e.fetchAll()
 
 	go e.refresh(time.Tick(refreshRate))
@@ -119,7 +118,7 @@ func (e *HTTPEvents) refresh(ticker <-chan time.Time) {
 
 			// Error with url parsing
 			if urlErr != nil {
-				glog.Errorf("Disabling refresh HTTP cache from GET '%s': %v", e.Endpoint, urlErr)
+				logger.Errorf("Disabling refresh HTTP cache from GET '%s': %v", e.Endpoint, urlErr)
 				return
 			}
 
@@ -135,7 +134,7 @@ func (e *HTTPEvents) refresh(ticker <-chan time.Time) {
 			// Convert to string
 			endpoint := endpointUrl.String()
 
-			glog.Infof("Refreshing HTTP cache from GET '%s'", endpoint)
+			logger.Infof("Refreshing HTTP cache from GET '%s'", endpoint)
 
 			ctx, cancel := e.ctxProducer()
 			resp, err := ctxhttp.Get(ctx, e.client, endpoint)
@@ -168,25 +167,25 @@ func (e *HTTPEvents) refresh(ticker <-chan time.Time) {
 // It returns true if everything was successful, and false if any errors occurred.
 func (e *HTTPEvents) parse(endpoint string, resp *httpCore.Response, err error) (*responseContract, bool) {
 	if err != nil {
-		glog.Errorf("Failed call: GET %s for Stored Requests: %v", endpoint, err)
+		logger.Errorf("Failed call: GET %s for Stored Requests: %v", endpoint, err)
 		return nil, false
 	}
 	defer resp.Body.Close()
 
 	respBytes, err := io.ReadAll(resp.Body)
 	if err != nil {
-		glog.Errorf("Failed to read body of GET %s for Stored Requests: %v", endpoint, err)
+		logger.Errorf("Failed to read body of GET %s for Stored Requests: %v", endpoint, err)
 		return nil, false
 	}
 
 	if resp.StatusCode != httpCore.StatusOK {
-		glog.Errorf("Got %d response from GET %s for Stored Requests. Response body was: %s", resp.StatusCode, endpoint, string(respBytes))
+		logger.Errorf("Got %d response from GET %s for Stored Requests. Response body was: %s", resp.StatusCode, endpoint, string(respBytes))
 		return nil, false
 	}
 
 	var respObj responseContract
 	if err := jsonutil.UnmarshalValid(respBytes, &respObj); err != nil {
-		glog.Errorf("Failed to unmarshal body of GET %s for Stored Requests: %v", endpoint, err)
+		logger.Errorf("Failed to unmarshal body of GET %s for Stored Requests: %v", endpoint, err)
 		return nil, false
 	}
 
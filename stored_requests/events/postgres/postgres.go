@@ -0,0 +1,163 @@
+package postgres
+
+import (
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/logger"
+	"github.com/prebid/prebid-server/v2/metrics"
+	"github.com/prebid/prebid-server/v2/stored_requests/events"
+	"github.com/prebid/prebid-server/v2/util/jsonutil"
+)
+
+// pingInterval is how often an idle connection is checked, since a connection which was lost without the
+// server closing it would otherwise only be noticed by the next notification.
+const pingInterval = 90 * time.Second
+
+var storedDataTypeMetricMap = map[config.DataType]metrics.StoredDataType{
+	config.RequestDataType:    metrics.RequestDataType,
+	config.CategoryDataType:   metrics.CategoryDataType,
+	config.VideoDataType:      metrics.VideoDataType,
+	config.AMPRequestDataType: metrics.AMPDataType,
+	config.AccountDataType:    metrics.AccountDataType,
+	config.ResponseDataType:   metrics.ResponseDataType,
+}
+
+// listener is the part of a pq.Listener the event producer uses
+type listener interface {
+	Listen(channel string) error
+	NotificationChannel() <-chan *pq.Notification
+	Ping() error
+	Close() error
+}
+
+type PostgresEventProducerConfig struct {
+	ConnString           string
+	Channel              string
+	RequestType          config.DataType
+	MinReconnectInterval time.Duration
+	MaxReconnectInterval time.Duration
+	MetricsEngine        metrics.MetricsEngine
+}
+
+// PostgresEventProducer invalidates the Stored Data the database announces a change of with NOTIFY on a
+// channel, so caches drop it as soon as it changes rather than on the next poll. The payload of each
+// notification names the changed data, like:
+//
+//	{
+//	  "requests": ["request1", "request2"],
+//	  "imps": ["imp1"],
+//	  "responses": ["resp1"],
+//	  "accounts": ["acc1"]
+//	}
+//
+// which a trigger on the table of the data may send with pg_notify. The data is fetched again on its next use.
+// Notifications sent while the connection is lost are missed, so polling for updates should be kept as a
+// fallback.
+type PostgresEventProducer struct {
+	cfg           PostgresEventProducerConfig
+	listener      listener
+	invalidations chan events.Invalidation
+	saves         chan events.Save
+	stop          chan struct{}
+}
+
+// NewPostgresEventProducer connects to the database and starts listening on the channel. The connection
+// is made in the background, and remade if it's lost.
+func NewPostgresEventProducer(cfg PostgresEventProducerConfig) *PostgresEventProducer {
+	e := newPostgresEventProducer(cfg)
+	e.listener = pq.NewListener(cfg.ConnString, cfg.MinReconnectInterval, cfg.MaxReconnectInterval, e.onListenerEvent)
+	go e.listen()
+	return e
+}
+
+func newPostgresEventProducer(cfg PostgresEventProducerConfig) *PostgresEventProducer {
+	return &PostgresEventProducer{
+		cfg:           cfg,
+		invalidations: make(chan events.Invalidation, 1),
+		saves:         make(chan events.Save, 1),
+		stop:          make(chan struct{}),
+	}
+}
+
+func (e *PostgresEventProducer) Saves() <-chan events.Save {
+	return e.saves
+}
+
+func (e *PostgresEventProducer) Invalidations() <-chan events.Invalidation {
+	return e.invalidations
+}
+
+// Stop stops listening and closes the connection.
+func (e *PostgresEventProducer) Stop() {
+	close(e.stop)
+	if err := e.listener.Close(); err != nil {
+		logger.Warningf("Failed to close the Stored %s LISTEN connection: %v", e.cfg.RequestType, err)
+	}
+}
+
+func (e *PostgresEventProducer) listen() {
+	if err := e.listener.Listen(e.cfg.Channel); err != nil {
+		select {
+		case <-e.stop:
+		default:
+			logger.Errorf("Failed to LISTEN on channel %s for Stored %s changes: %v", e.cfg.Channel, e.cfg.RequestType, err)
+			e.recordError(metrics.StoredDataErrorUndefined)
+		}
+		return
+	}
+	logger.Infof("Listening on channel %s for Stored %s changes", e.cfg.Channel, e.cfg.RequestType)
+
+	notifications := e.listener.NotificationChannel()
+	for {
+		select {
+		case <-e.stop:
+			return
+		case notification := <-notifications:
+			e.handleNotification(notification)
+		case <-time.After(pingInterval):
+			go e.listener.Ping()
+		}
+	}
+}
+
+func (e *PostgresEventProducer) handleNotification(notification *pq.Notification) {
+	// a nil notification follows a reconnection, and the changes made while the connection was lost are missed
+	if notification == nil {
+		logger.Warningf("Reconnected to LISTEN for Stored %s changes. Changes made while disconnected are applied by the next poll.", e.cfg.RequestType)
+		return
+	}
+
+	var invalidation events.Invalidation
+	if err := jsonutil.UnmarshalValid([]byte(notification.Extra), &invalidation); err != nil {
+		logger.Warningf("Stored %s change notification on channel %s is malformed and will be ignored: %v", e.cfg.RequestType, notification.Channel, err)
+		e.recordError(metrics.StoredDataErrorUndefined)
+		return
+	}
+	if len(invalidation.Requests) == 0 && len(invalidation.Imps) == 0 && len(invalidation.Responses) == 0 && len(invalidation.Accounts) == 0 {
+		return
+	}
+
+	select {
+	case e.invalidations <- invalidation:
+	case <-e.stop:
+	}
+}
+
+func (e *PostgresEventProducer) onListenerEvent(event pq.ListenerEventType, err error) {
+	switch event {
+	case pq.ListenerEventDisconnected, pq.ListenerEventConnectionAttemptFailed:
+		logger.Warningf("Lost the LISTEN connection for Stored %s changes: %v", e.cfg.RequestType, err)
+		e.recordError(metrics.StoredDataErrorNetwork)
+	}
+}
+
+func (e *PostgresEventProducer) recordError(errorType metrics.StoredDataError) {
+	e.cfg.MetricsEngine.RecordStoredDataError(
+		metrics.StoredDataLabels{
+			DataType: storedDataTypeMetricMap[e.cfg.RequestType],
+			Error:    errorType,
+		})
+}
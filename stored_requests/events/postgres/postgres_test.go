@@ -0,0 +1,138 @@
+package postgres
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/metrics"
+	"github.com/prebid/prebid-server/v2/stored_requests/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type fakeListener struct {
+	listenErr     error
+	channels      []string
+	notifications chan *pq.Notification
+	closed        bool
+}
+
+func (l *fakeListener) Listen(channel string) error {
+	l.channels = append(l.channels, channel)
+	return l.listenErr
+}
+
+func (l *fakeListener) NotificationChannel() <-chan *pq.Notification {
+	return l.notifications
+}
+
+func (l *fakeListener) Ping() error {
+	return nil
+}
+
+func (l *fakeListener) Close() error {
+	l.closed = true
+	return nil
+}
+
+func newTestEventProducer(listener *fakeListener, metricsEngine metrics.MetricsEngine) *PostgresEventProducer {
+	e := newPostgresEventProducer(PostgresEventProducerConfig{
+		Channel:       "stored_data",
+		RequestType:   config.RequestDataType,
+		MetricsEngine: metricsEngine,
+	})
+	e.listener = listener
+	return e
+}
+
+func TestInvalidations(t *testing.T) {
+	listener := &fakeListener{notifications: make(chan *pq.Notification)}
+	e := newTestEventProducer(listener, &metrics.MetricsEngineMock{})
+	go e.listen()
+
+	listener.notifications <- &pq.Notification{Channel: "stored_data", Extra: `{"requests":["request1"],"imps":["imp1","imp2"],"accounts":["acc1"]}`}
+
+	select {
+	case invalidation := <-e.Invalidations():
+		assert.Equal(t, events.Invalidation{
+			Requests: []string{"request1"},
+			Imps:     []string{"imp1", "imp2"},
+			Accounts: []string{"acc1"},
+		}, invalidation)
+	case <-time.After(time.Second):
+		t.Fatal("the notification wasn't turned into an invalidation")
+	}
+
+	e.Stop()
+	assert.Equal(t, []string{"stored_data"}, listener.channels)
+	assert.True(t, listener.closed)
+}
+
+func TestIgnoredNotifications(t *testing.T) {
+	testCases := []struct {
+		name         string
+		notification *pq.Notification
+		expectError  bool
+	}{
+		{
+			name:         "reconnected",
+			notification: nil,
+		},
+		{
+			name:         "nothing-changed",
+			notification: &pq.Notification{Channel: "stored_data", Extra: `{"requests":[]}`},
+		},
+		{
+			name:         "malformed",
+			notification: &pq.Notification{Channel: "stored_data", Extra: `request1`},
+			expectError:  true,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			metricsEngine := &metrics.MetricsEngineMock{}
+			if test.expectError {
+				metricsEngine.On("RecordStoredDataError", metrics.StoredDataLabels{
+					DataType: metrics.RequestDataType,
+					Error:    metrics.StoredDataErrorUndefined,
+				}).Once()
+			}
+			e := newTestEventProducer(&fakeListener{}, metricsEngine)
+
+			e.handleNotification(test.notification)
+
+			assert.Empty(t, e.Invalidations())
+			metricsEngine.AssertExpectations(t)
+		})
+	}
+}
+
+func TestListenError(t *testing.T) {
+	metricsEngine := &metrics.MetricsEngineMock{}
+	metricsEngine.On("RecordStoredDataError", mock.Anything).Once()
+	e := newTestEventProducer(&fakeListener{listenErr: errors.New("permission denied")}, metricsEngine)
+
+	e.listen()
+
+	metricsEngine.AssertExpectations(t)
+}
+
+func TestConnectionLost(t *testing.T) {
+	metricsEngine := &metrics.MetricsEngineMock{}
+	metricsEngine.On("RecordStoredDataError", metrics.StoredDataLabels{
+		DataType: metrics.RequestDataType,
+		Error:    metrics.StoredDataErrorNetwork,
+	}).Twice()
+	e := newTestEventProducer(&fakeListener{}, metricsEngine)
+
+	e.onListenerEvent(pq.ListenerEventConnected, nil)
+	e.onListenerEvent(pq.ListenerEventDisconnected, errors.New("connection reset"))
+	e.onListenerEvent(pq.ListenerEventConnectionAttemptFailed, errors.New("connection refused"))
+	e.onListenerEvent(pq.ListenerEventReconnected, nil)
+
+	metricsEngine.AssertExpectations(t)
+}
@@ -2,9 +2,13 @@ package stored_requests
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"strconv"
 
+	"github.com/buger/jsonparser"
 	"github.com/prebid/prebid-server/v2/metrics"
 )
 
@@ -43,6 +47,30 @@ type AllFetcher interface {
 	CategoryFetcher
 }
 
+// VersionFetcher is implemented by Fetchers whose backends keep a version of each stored request and imp,
+// such as the ETag of the object a bucket stores it in.
+type VersionFetcher interface {
+	// FetchRequestVersions returns the current versions of the stored requests and imps, by ID. IDs whose
+	// version isn't known are left out.
+	FetchRequestVersions(ctx context.Context, requestIDs []string, impIDs []string) (requestVersions map[string]string, impVersions map[string]string)
+}
+
+// FetchRequestVersions returns the versions of the stored requests and imps the fetcher knows, or none if it
+// isn't a VersionFetcher. Fetchers which wrap another use it to pass the versions of the one they wrap on.
+func FetchRequestVersions(ctx context.Context, fetcher interface{}, requestIDs []string, impIDs []string) (map[string]string, map[string]string) {
+	if versionFetcher, ok := fetcher.(VersionFetcher); ok {
+		return versionFetcher.FetchRequestVersions(ctx, requestIDs, impIDs)
+	}
+	return nil, nil
+}
+
+// ContentVersion returns the version of stored data whose backend doesn't keep versions, which is a hash of
+// its content, so that every instance gives the same data the same version.
+func ContentVersion(data json.RawMessage) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:8])
+}
+
 // NotFoundError is an error type to flag that an ID was not found by the Fetcher.
 // This was added to support Multifetcher and any other case where we might expect
 // that all IDs would not be found, and want to disentangle those errors from the others.
@@ -193,6 +221,10 @@ func (f *fetcherWithCache) FetchRequests(ctx context.Context, requestIDs []strin
 	return
 }
 
+func (f *fetcherWithCache) FetchRequestVersions(ctx context.Context, requestIDs []string, impIDs []string) (map[string]string, map[string]string) {
+	return FetchRequestVersions(ctx, f.fetcher, requestIDs, impIDs)
+}
+
 func (f *fetcherWithCache) FetchResponses(ctx context.Context, ids []string) (data map[string]json.RawMessage, errs []error) {
 	data = f.cache.Responses.Get(ctx, ids)
 
@@ -210,22 +242,95 @@ func (f *fetcherWithCache) FetchResponses(ctx context.Context, ids []string) (da
 	return
 }
 
+// maxAccountInheritanceDepth limits how many ancestors an account may inherit its configuration from.
+const maxAccountInheritanceDepth = 5
+
+// FetchAccount fetches the account and, if it names a parent account, resolves the config inherited
+// from its ancestors. The resolved config is cached under the account ID, and a cached child is only
+// served while every one of its ancestors is still cached, so invalidating a parent also refreshes
+// its children on their next fetch.
 func (f *fetcherWithCache) FetchAccount(ctx context.Context, acccountDefaultJSON json.RawMessage, accountID string) (account json.RawMessage, errs []error) {
-	accountData := f.cache.Accounts.Get(ctx, []string{accountID})
-	// TODO: add metrics
-	if account, ok := accountData[accountID]; ok {
+	return f.fetchAccount(ctx, acccountDefaultJSON, accountID, nil)
+}
+
+func (f *fetcherWithCache) fetchAccount(ctx context.Context, acccountDefaultJSON json.RawMessage, accountID string, descendants []string) (account json.RawMessage, errs []error) {
+	if account, ok := f.cachedAccount(ctx, accountID, 0); ok {
 		f.metricsEngine.RecordAccountCacheResult(metrics.CacheHit, 1)
 		return account, errs
 	} else {
 		f.metricsEngine.RecordAccountCacheResult(metrics.CacheMiss, 1)
 	}
 	account, errs = f.fetcher.FetchAccount(ctx, acccountDefaultJSON, accountID)
-	if len(errs) == 0 {
-		f.cache.Accounts.Save(ctx, map[string]json.RawMessage{accountID: account})
+	if len(errs) > 0 {
+		return account, errs
 	}
+	if parentID := accountParent(account); parentID != "" {
+		if account, errs = f.inheritAccount(ctx, acccountDefaultJSON, accountID, parentID, descendants); len(errs) > 0 {
+			return nil, errs
+		}
+	}
+	f.cache.Accounts.Save(ctx, map[string]json.RawMessage{accountID: account})
 	return account, errs
 }
 
+// inheritAccount resolves the parent account and merges the account over it instead of over the host defaults.
+func (f *fetcherWithCache) inheritAccount(ctx context.Context, acccountDefaultJSON json.RawMessage, accountID, parentID string, descendants []string) (json.RawMessage, []error) {
+	descendants = append(descendants[:len(descendants):len(descendants)], accountID)
+	for _, id := range descendants {
+		if id == parentID {
+			return nil, []error{fmt.Errorf("account %s has an inheritance cycle through parent account %s", accountID, parentID)}
+		}
+	}
+	if len(descendants) > maxAccountInheritanceDepth {
+		return nil, []error{fmt.Errorf("account %s exceeds the maximum inheritance depth of %d", descendants[0], maxAccountInheritanceDepth)}
+	}
+
+	parent, errs := f.fetchAccount(ctx, acccountDefaultJSON, parentID, descendants)
+	if len(errs) > 0 {
+		for i := range errs {
+			errs[i] = fmt.Errorf("parent account %s of account %s: %v", parentID, accountID, errs[i])
+		}
+		return nil, errs
+	}
+
+	account, errs := f.fetcher.FetchAccount(ctx, parent, accountID)
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	// The merge copies the parent's ID, which must not leak into the child
+	account, err := jsonparser.Set(account, []byte(strconv.Quote(accountID)), "id")
+	if err != nil {
+		return nil, []error{err}
+	}
+	return account, nil
+}
+
+// cachedAccount returns the cached account config, provided that all of its ancestors are also cached.
+func (f *fetcherWithCache) cachedAccount(ctx context.Context, accountID string, depth int) (json.RawMessage, bool) {
+	account, ok := f.cache.Accounts.Get(ctx, []string{accountID})[accountID]
+	if !ok {
+		return nil, false
+	}
+	if parentID := accountParent(account); parentID != "" {
+		if depth >= maxAccountInheritanceDepth {
+			return nil, false
+		}
+		if _, ok := f.cachedAccount(ctx, parentID, depth+1); !ok {
+			return nil, false
+		}
+	}
+	return account, true
+}
+
+// accountParent returns the ID of the account's parent, or an empty string if it has none.
+func accountParent(account json.RawMessage) string {
+	parentID, err := jsonparser.GetString(account, "parent")
+	if err != nil {
+		return ""
+	}
+	return parentID
+}
+
 func (f *fetcherWithCache) FetchCategories(ctx context.Context, primaryAdServer, publisherId, iabCategory string) (string, error) {
 	return "", nil
 }
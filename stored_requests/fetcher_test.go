@@ -11,6 +11,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	jsonpatch "gopkg.in/evanphx/json-patch.v4"
 )
 
 func setupFetcherWithCacheDeps() (*mockCache, *mockCache, *mockCache, *mockFetcher, AllFetcher, *metrics.MetricsEngineMock) {
@@ -372,3 +373,129 @@ func (c *mockCache) Save(ctx context.Context, data map[string]json.RawMessage) {
 func (c *mockCache) Invalidate(ctx context.Context, ids []string) {
 	c.Called(ctx, ids)
 }
+
+func TestAccountInheritance(t *testing.T) {
+	accounts := map[string]json.RawMessage{
+		"grandparent": json.RawMessage(`{"id":"grandparent","debug_allow":false,"price_floors":{"enabled":true}}`),
+		"parent":      json.RawMessage(`{"id":"parent","parent":"grandparent","privacy":{"ipv6":{"anon_keep_bits":48}}}`),
+		"child":       json.RawMessage(`{"parent":"parent","price_floors":{"enabled":false}}`),
+		"orphan":      json.RawMessage(`{"parent":"missing"}`),
+		"cycle_a":     json.RawMessage(`{"parent":"cycle_b"}`),
+		"cycle_b":     json.RawMessage(`{"parent":"cycle_a"}`),
+	}
+	defaults := json.RawMessage(`{"id":"","parent":"","debug_allow":true}`)
+
+	tests := []struct {
+		description  string
+		accountID    string
+		expectedJSON string
+		expectError  bool
+	}{
+		{
+			description:  "no parent",
+			accountID:    "grandparent",
+			expectedJSON: `{"id":"grandparent","parent":"","debug_allow":false,"price_floors":{"enabled":true}}`,
+		},
+		{
+			description:  "inherits from every ancestor with selective overrides",
+			accountID:    "child",
+			expectedJSON: `{"id":"child","parent":"parent","debug_allow":false,"price_floors":{"enabled":false},"privacy":{"ipv6":{"anon_keep_bits":48}}}`,
+		},
+		{
+			description: "missing parent",
+			accountID:   "orphan",
+			expectError: true,
+		},
+		{
+			description: "inheritance cycle",
+			accountID:   "cycle_a",
+			expectError: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			cache := &mapCache{data: map[string]json.RawMessage{}}
+			metricsEngine := &metrics.MetricsEngineMock{}
+			metricsEngine.On("RecordAccountCacheResult", mock.Anything, mock.Anything)
+			fetcher := WithCache(&mergingAccountFetcher{accounts: accounts}, Cache{&nil_cache.NilCache{}, &nil_cache.NilCache{}, &nil_cache.NilCache{}, cache}, metricsEngine)
+
+			account, errs := fetcher.FetchAccount(context.Background(), defaults, test.accountID)
+			if test.expectError {
+				assert.NotEmpty(t, errs)
+				assert.Nil(t, account)
+				assert.NotContains(t, cache.data, test.accountID, "unresolved accounts must not be cached")
+				return
+			}
+			assert.Empty(t, errs)
+			assert.JSONEq(t, test.expectedJSON, string(account))
+			assert.JSONEq(t, test.expectedJSON, string(cache.data[test.accountID]))
+		})
+	}
+}
+
+func TestAccountInheritanceParentInvalidation(t *testing.T) {
+	accounts := map[string]json.RawMessage{
+		"parent": json.RawMessage(`{"debug_allow":true}`),
+		"child":  json.RawMessage(`{"parent":"parent"}`),
+	}
+	cache := &mapCache{data: map[string]json.RawMessage{}}
+	metricsEngine := &metrics.MetricsEngineMock{}
+	metricsEngine.On("RecordAccountCacheResult", mock.Anything, mock.Anything)
+	fetcher := WithCache(&mergingAccountFetcher{accounts: accounts}, Cache{&nil_cache.NilCache{}, &nil_cache.NilCache{}, &nil_cache.NilCache{}, cache}, metricsEngine)
+	ctx := context.Background()
+
+	account, errs := fetcher.FetchAccount(ctx, json.RawMessage(`{}`), "child")
+	assert.Empty(t, errs)
+	assert.JSONEq(t, `{"id":"child","parent":"parent","debug_allow":true}`, string(account))
+
+	accounts["parent"] = json.RawMessage(`{"debug_allow":false}`)
+	cache.Invalidate(ctx, []string{"parent"})
+
+	account, errs = fetcher.FetchAccount(ctx, json.RawMessage(`{}`), "child")
+	assert.Empty(t, errs)
+	assert.JSONEq(t, `{"id":"child","parent":"parent","debug_allow":false}`, string(account), "child must be re-resolved once its parent is invalidated")
+}
+
+type mergingAccountFetcher struct {
+	mockFetcher
+	accounts map[string]json.RawMessage
+}
+
+func (f *mergingAccountFetcher) FetchAccount(ctx context.Context, defaultAccountsJSON json.RawMessage, accountID string) (json.RawMessage, []error) {
+	account, ok := f.accounts[accountID]
+	if !ok {
+		return nil, []error{NotFoundError{accountID, "Account"}}
+	}
+	merged, err := jsonpatch.MergePatch(defaultAccountsJSON, account)
+	if err != nil {
+		return nil, []error{err}
+	}
+	return merged, nil
+}
+
+type mapCache struct {
+	data map[string]json.RawMessage
+}
+
+func (c *mapCache) Get(ctx context.Context, ids []string) map[string]json.RawMessage {
+	found := make(map[string]json.RawMessage, len(ids))
+	for _, id := range ids {
+		if data, ok := c.data[id]; ok {
+			found[id] = data
+		}
+	}
+	return found
+}
+
+func (c *mapCache) Save(ctx context.Context, data map[string]json.RawMessage) {
+	for id, value := range data {
+		c.data[id] = value
+	}
+}
+
+func (c *mapCache) Invalidate(ctx context.Context, ids []string) {
+	for _, id := range ids {
+		delete(c.data, id)
+	}
+}
@@ -36,6 +36,27 @@ func (mf MultiFetcher) FetchRequests(ctx context.Context, requestIDs []string, i
 	return
 }
 
+// FetchRequestVersions returns the versions of the first fetcher which knows the version of each ID. As with
+// FetchRequests, fetchers which come first win.
+func (mf MultiFetcher) FetchRequestVersions(ctx context.Context, requestIDs []string, impIDs []string) (requestVersions map[string]string, impVersions map[string]string) {
+	requestVersions = make(map[string]string, len(requestIDs))
+	impVersions = make(map[string]string, len(impIDs))
+	for _, f := range mf {
+		theseRequestVersions, theseImpVersions := FetchRequestVersions(ctx, f, requestIDs, impIDs)
+		addVersions(requestVersions, theseRequestVersions)
+		addVersions(impVersions, theseImpVersions)
+	}
+	return
+}
+
+func addVersions(versions map[string]string, more map[string]string) {
+	for id, version := range more {
+		if _, ok := versions[id]; !ok {
+			versions[id] = version
+		}
+	}
+}
+
 func (mf MultiFetcher) FetchResponses(ctx context.Context, ids []string) (data map[string]json.RawMessage, errs []error) {
 	return nil, nil
 }
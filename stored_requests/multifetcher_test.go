@@ -176,3 +176,26 @@ func TestMultiFetcherAccountNotFound(t *testing.T) {
 	assert.Nil(t, account)
 	assert.EqualError(t, errs[0], NotFoundError{"MISSING", "Account"}.Error())
 }
+
+// versionedFetcher is a mockFetcher whose backend keeps versions
+type versionedFetcher struct {
+	mockFetcher
+	requestVersions map[string]string
+	impVersions     map[string]string
+}
+
+func (f *versionedFetcher) FetchRequestVersions(ctx context.Context, requestIDs []string, impIDs []string) (map[string]string, map[string]string) {
+	return f.requestVersions, f.impVersions
+}
+
+func TestMultiFetcherRequestVersions(t *testing.T) {
+	f1 := &mockFetcher{}
+	f2 := &versionedFetcher{requestVersions: map[string]string{"req-1": "a", "req-2": "b"}, impVersions: map[string]string{"imp-1": "c"}}
+	f3 := &versionedFetcher{requestVersions: map[string]string{"req-2": "d", "req-3": "e"}}
+	fetcher := MultiFetcher{f1, f2, f3}
+
+	requestVersions, impVersions := fetcher.FetchRequestVersions(context.Background(), []string{"req-1", "req-2", "req-3"}, []string{"imp-1"})
+
+	assert.Equal(t, map[string]string{"req-1": "a", "req-2": "b", "req-3": "e"}, requestVersions, "fetchers which come first win")
+	assert.Equal(t, map[string]string{"imp-1": "c"}, impVersions)
+}
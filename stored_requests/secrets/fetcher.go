@@ -0,0 +1,99 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/prebid/prebid-server/v2/logger"
+	"github.com/prebid/prebid-server/v2/stored_requests"
+)
+
+// WithDecryption returns a Fetcher which decrypts the encrypted values in the data returned by the given Fetcher.
+// Entries which fail to decrypt are dropped and reported as errors.
+func WithDecryption(fetcher stored_requests.AllFetcher, decrypter *Decrypter) stored_requests.AllFetcher {
+	return &decryptingFetcher{
+		fetcher:   fetcher,
+		decrypter: decrypter,
+	}
+}
+
+type decryptingFetcher struct {
+	fetcher   stored_requests.AllFetcher
+	decrypter *Decrypter
+}
+
+func (f *decryptingFetcher) FetchRequests(ctx context.Context, requestIDs []string, impIDs []string) (requestData map[string]json.RawMessage, impData map[string]json.RawMessage, errs []error) {
+	requestData, impData, errs = f.fetcher.FetchRequests(ctx, requestIDs, impIDs)
+	errs = f.decryptAll(ctx, requestData, errs)
+	errs = f.decryptAll(ctx, impData, errs)
+	return
+}
+
+func (f *decryptingFetcher) FetchRequestVersions(ctx context.Context, requestIDs []string, impIDs []string) (map[string]string, map[string]string) {
+	return stored_requests.FetchRequestVersions(ctx, f.fetcher, requestIDs, impIDs)
+}
+
+func (f *decryptingFetcher) FetchResponses(ctx context.Context, ids []string) (data map[string]json.RawMessage, errs []error) {
+	data, errs = f.fetcher.FetchResponses(ctx, ids)
+	errs = f.decryptAll(ctx, data, errs)
+	return
+}
+
+func (f *decryptingFetcher) FetchAccount(ctx context.Context, accountDefaultsJSON json.RawMessage, accountID string) (json.RawMessage, []error) {
+	account, errs := f.fetcher.FetchAccount(ctx, accountDefaultsJSON, accountID)
+	if len(errs) > 0 {
+		return account, errs
+	}
+	account, err := f.decrypter.DecryptJSON(ctx, account)
+	if err != nil {
+		return nil, []error{fmt.Errorf("decrypting account %s: %v", accountID, err)}
+	}
+	return account, nil
+}
+
+func (f *decryptingFetcher) FetchCategories(ctx context.Context, primaryAdServer, publisherId, iabCategory string) (string, error) {
+	return f.fetcher.FetchCategories(ctx, primaryAdServer, publisherId, iabCategory)
+}
+
+func (f *decryptingFetcher) decryptAll(ctx context.Context, data map[string]json.RawMessage, errs []error) []error {
+	for id, value := range data {
+		decrypted, err := f.decrypter.DecryptJSON(ctx, value)
+		if err != nil {
+			delete(data, id)
+			errs = append(errs, fmt.Errorf("decrypting stored data %s: %v", id, err))
+			continue
+		}
+		data[id] = decrypted
+	}
+	return errs
+}
+
+// WithDecryptedSaves returns a Cache which decrypts data saved into it by event producers before
+// delegating to the given Cache. Entries which fail to decrypt are logged and not saved.
+func WithDecryptedSaves(cache stored_requests.Cache, decrypter *Decrypter) stored_requests.Cache {
+	return stored_requests.Cache{
+		Requests:  &decryptingCache{cache.Requests, decrypter},
+		Imps:      &decryptingCache{cache.Imps, decrypter},
+		Responses: &decryptingCache{cache.Responses, decrypter},
+		Accounts:  &decryptingCache{cache.Accounts, decrypter},
+	}
+}
+
+type decryptingCache struct {
+	stored_requests.CacheJSON
+	decrypter *Decrypter
+}
+
+func (c *decryptingCache) Save(ctx context.Context, data map[string]json.RawMessage) {
+	decrypted := make(map[string]json.RawMessage, len(data))
+	for id, value := range data {
+		plaintext, err := c.decrypter.DecryptJSON(ctx, value)
+		if err != nil {
+			logger.Ctx(ctx).Errorf("Not caching stored data %s: decryption failed: %v", id, err)
+			continue
+		}
+		decrypted[id] = plaintext
+	}
+	c.CacheJSON.Save(ctx, decrypted)
+}
@@ -0,0 +1,92 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/prebid/prebid-server/v2/stored_requests"
+	"github.com/prebid/prebid-server/v2/stored_requests/backends/empty_fetcher"
+	"github.com/prebid/prebid-server/v2/stored_requests/caches/memory"
+	"github.com/prebid/prebid-server/v2/stored_requests/caches/nil_cache"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type staticFetcher struct {
+	empty_fetcher.EmptyFetcher
+	data map[string]json.RawMessage
+}
+
+func (f *staticFetcher) FetchRequests(ctx context.Context, requestIDs []string, impIDs []string) (map[string]json.RawMessage, map[string]json.RawMessage, []error) {
+	requestData := make(map[string]json.RawMessage)
+	for _, id := range requestIDs {
+		requestData[id] = f.data[id]
+	}
+	return requestData, map[string]json.RawMessage{}, nil
+}
+
+func (f *staticFetcher) FetchAccount(ctx context.Context, accountDefaultsJSON json.RawMessage, accountID string) (json.RawMessage, []error) {
+	return f.data[accountID], nil
+}
+
+func TestWithDecryption(t *testing.T) {
+	provider, err := NewLocalKeyProvider(testMasterKey)
+	require.NoError(t, err)
+	secret := encryptForTest(t, provider, "secret")
+	fetcher := WithDecryption(&staticFetcher{data: map[string]json.RawMessage{
+		"plain":     json.RawMessage(`{"id":"plain"}`),
+		"encrypted": json.RawMessage(`{"ext":{"key":"` + secret + `"}}`),
+		"broken":    json.RawMessage(`{"key":"enc:v1:local:AAAA:AAAA"}`),
+	}}, NewDecrypter(provider))
+
+	requestData, _, errs := fetcher.FetchRequests(context.Background(), []string{"plain", "encrypted", "broken"}, nil)
+	assert.Len(t, errs, 1)
+	assert.JSONEq(t, `{"id":"plain"}`, string(requestData["plain"]))
+	assert.JSONEq(t, `{"ext":{"key":"secret"}}`, string(requestData["encrypted"]))
+	assert.NotContains(t, requestData, "broken", "entries which fail to decrypt must be dropped")
+
+	account, errs := fetcher.FetchAccount(context.Background(), nil, "encrypted")
+	assert.Empty(t, errs)
+	assert.JSONEq(t, `{"ext":{"key":"secret"}}`, string(account))
+
+	account, errs = fetcher.FetchAccount(context.Background(), nil, "broken")
+	assert.Len(t, errs, 1)
+	assert.Nil(t, account)
+}
+
+func TestWithDecryptedSaves(t *testing.T) {
+	provider, err := NewLocalKeyProvider(testMasterKey)
+	require.NoError(t, err)
+	secret := encryptForTest(t, provider, "secret")
+	accounts := memory.NewCache(1024, -1, "Accounts")
+	cache := WithDecryptedSaves(stored_requests.Cache{
+		Requests:  &nil_cache.NilCache{},
+		Imps:      &nil_cache.NilCache{},
+		Responses: &nil_cache.NilCache{},
+		Accounts:  accounts,
+	}, NewDecrypter(failingOrLocal{provider}))
+
+	ctx := context.Background()
+	cache.Accounts.Save(ctx, map[string]json.RawMessage{
+		"good": json.RawMessage(`{"key":"` + secret + `"}`),
+		"bad":  json.RawMessage(`{"key":"enc:v1:unknown:AAAA:AAAA"}`),
+	})
+
+	saved := accounts.Get(ctx, []string{"good", "bad"})
+	assert.JSONEq(t, `{"key":"secret"}`, string(saved["good"]))
+	assert.NotContains(t, saved, "bad")
+}
+
+// failingOrLocal fails to unwrap keys with an unknown key ID
+type failingOrLocal struct {
+	local *LocalKeyProvider
+}
+
+func (p failingOrLocal) UnwrapDataKey(ctx context.Context, keyID string, wrappedKey []byte) ([]byte, error) {
+	if keyID != "local" {
+		return nil, errors.New("unknown key")
+	}
+	return p.local.UnwrapDataKey(ctx, keyID, wrappedKey)
+}
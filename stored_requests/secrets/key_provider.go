@@ -0,0 +1,110 @@
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/prebid/prebid-server/v2/util/jsonutil"
+)
+
+// LocalKeyProvider wraps and unwraps data keys with a master key held in the host config.
+type LocalKeyProvider struct {
+	masterKey []byte
+}
+
+// NewLocalKeyProvider returns a LocalKeyProvider for the given 256 bit AES master key.
+func NewLocalKeyProvider(masterKey []byte) (*LocalKeyProvider, error) {
+	if len(masterKey) != 32 {
+		return nil, fmt.Errorf("master key must be 32 bytes. Got %d", len(masterKey))
+	}
+	return &LocalKeyProvider{masterKey: masterKey}, nil
+}
+
+// UnwrapDataKey implements KeyProvider. The key ID is not used, since there is a single master key.
+func (p *LocalKeyProvider) UnwrapDataKey(ctx context.Context, keyID string, wrappedKey []byte) ([]byte, error) {
+	return open(p.masterKey, wrappedKey)
+}
+
+// NewDataKey generates a data key, returning both its plaintext and its wrapped form.
+func (p *LocalKeyProvider) NewDataKey() (dataKey, wrappedKey []byte, err error) {
+	dataKey = make([]byte, 32)
+	if _, err = io.ReadFull(rand.Reader, dataKey); err != nil {
+		return nil, nil, err
+	}
+	if wrappedKey, err = seal(p.masterKey, dataKey); err != nil {
+		return nil, nil, err
+	}
+	return dataKey, wrappedKey, nil
+}
+
+// KMSKeyProvider unwraps data keys by calling a KMS over HTTP.
+//
+// The KMS receives a POST with the body {"key_id":"...","ciphertext":"<base64>"} and must respond
+// with {"plaintext":"<base64>"}.
+type KMSKeyProvider struct {
+	client   *http.Client
+	endpoint string
+	timeout  time.Duration
+}
+
+// NewKMSKeyProvider returns a KMSKeyProvider which calls the given endpoint.
+func NewKMSKeyProvider(client *http.Client, endpoint string, timeout time.Duration) *KMSKeyProvider {
+	return &KMSKeyProvider{
+		client:   client,
+		endpoint: endpoint,
+		timeout:  timeout,
+	}
+}
+
+type kmsDecryptRequest struct {
+	KeyID      string `json:"key_id"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+type kmsDecryptResponse struct {
+	Plaintext []byte `json:"plaintext"`
+}
+
+// UnwrapDataKey implements KeyProvider.
+func (p *KMSKeyProvider) UnwrapDataKey(ctx context.Context, keyID string, wrappedKey []byte) ([]byte, error) {
+	body, err := jsonutil.Marshal(kmsDecryptRequest{KeyID: keyID, Ciphertext: wrappedKey})
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, p.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	httpResp, err := p.client.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer httpResp.Body.Close()
+	respBody, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if httpResp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("KMS responded with status %d", httpResp.StatusCode)
+	}
+
+	var resp kmsDecryptResponse
+	if err := json.Unmarshal(respBody, &resp); err != nil {
+		return nil, fmt.Errorf("KMS response is malformed: %v", err)
+	}
+	if len(resp.Plaintext) == 0 {
+		return nil, errors.New("KMS response has no plaintext")
+	}
+	return resp.Plaintext, nil
+}
@@ -0,0 +1,186 @@
+// Package secrets decrypts envelope-encrypted values embedded in stored data.
+//
+// An encrypted value is a JSON string of the form
+//
+//	enc:v1:<key id>:<wrapped data key>:<nonce and ciphertext>
+//
+// where the binary parts are unpadded standard base64. The data key is a 256 bit AES key, wrapped by
+// the KeyProvider, and the value is sealed with AES-GCM using that data key. Decryption replaces the
+// encrypted string with the plaintext string.
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/prebid/prebid-server/v2/util/jsonutil"
+)
+
+const valuePrefix = "enc:v1:"
+
+// KeyProvider unwraps the data keys which encrypted values are sealed with.
+//
+// Implementations must be safe for concurrent use.
+type KeyProvider interface {
+	UnwrapDataKey(ctx context.Context, keyID string, wrappedKey []byte) ([]byte, error)
+}
+
+// Decrypter decrypts encrypted values, caching unwrapped data keys so the KeyProvider is only
+// consulted once per data key.
+type Decrypter struct {
+	keyProvider KeyProvider
+	dataKeys    sync.Map
+}
+
+// NewDecrypter returns a Decrypter which unwraps data keys with the given provider.
+func NewDecrypter(keyProvider KeyProvider) *Decrypter {
+	return &Decrypter{keyProvider: keyProvider}
+}
+
+// IsEncrypted returns true if the value is an encrypted value.
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, valuePrefix)
+}
+
+// Decrypt returns the plaintext of an encrypted value.
+func (d *Decrypter) Decrypt(ctx context.Context, value string) (string, error) {
+	if !IsEncrypted(value) {
+		return "", errors.New("value is not encrypted")
+	}
+	parts := strings.Split(strings.TrimPrefix(value, valuePrefix), ":")
+	if len(parts) != 3 {
+		return "", errors.New("encrypted value is malformed")
+	}
+	keyID := parts[0]
+	wrappedKey, err := base64.RawStdEncoding.DecodeString(parts[1])
+	if err != nil {
+		return "", fmt.Errorf("encrypted value has a malformed data key: %v", err)
+	}
+	sealed, err := base64.RawStdEncoding.DecodeString(parts[2])
+	if err != nil {
+		return "", fmt.Errorf("encrypted value has a malformed ciphertext: %v", err)
+	}
+
+	dataKey, err := d.dataKey(ctx, keyID, parts[1], wrappedKey)
+	if err != nil {
+		return "", err
+	}
+	plaintext, err := open(dataKey, sealed)
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
+
+func (d *Decrypter) dataKey(ctx context.Context, keyID, encodedKey string, wrappedKey []byte) ([]byte, error) {
+	cacheKey := keyID + ":" + encodedKey
+	if dataKey, ok := d.dataKeys.Load(cacheKey); ok {
+		return dataKey.([]byte), nil
+	}
+	dataKey, err := d.keyProvider.UnwrapDataKey(ctx, keyID, wrappedKey)
+	if err != nil {
+		return nil, fmt.Errorf("unwrapping data key %s: %v", keyID, err)
+	}
+	d.dataKeys.Store(cacheKey, dataKey)
+	return dataKey, nil
+}
+
+// DecryptJSON replaces every encrypted string value in the JSON document with its plaintext.
+// Documents without encrypted values are returned unchanged.
+func (d *Decrypter) DecryptJSON(ctx context.Context, data json.RawMessage) (json.RawMessage, error) {
+	if !bytes.Contains(data, []byte(valuePrefix)) {
+		return data, nil
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var doc interface{}
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, err
+	}
+	doc, err := d.decryptValue(ctx, doc)
+	if err != nil {
+		return nil, err
+	}
+	return jsonutil.Marshal(doc)
+}
+
+func (d *Decrypter) decryptValue(ctx context.Context, value interface{}) (interface{}, error) {
+	var err error
+	switch v := value.(type) {
+	case string:
+		if IsEncrypted(v) {
+			return d.Decrypt(ctx, v)
+		}
+	case map[string]interface{}:
+		for key, child := range v {
+			if v[key], err = d.decryptValue(ctx, child); err != nil {
+				return nil, err
+			}
+		}
+	case []interface{}:
+		for i, child := range v {
+			if v[i], err = d.decryptValue(ctx, child); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return value, nil
+}
+
+// Encrypt seals the plaintext with the data key, producing an encrypted value which carries the
+// wrapped form of the data key. It is used by tooling which provisions encrypted values.
+func Encrypt(keyID string, dataKey, wrappedKey []byte, plaintext string) (string, error) {
+	if strings.Contains(keyID, ":") {
+		return "", errors.New("key id must not contain ':'")
+	}
+	sealed, err := seal(dataKey, []byte(plaintext))
+	if err != nil {
+		return "", err
+	}
+	return valuePrefix + keyID + ":" + base64.RawStdEncoding.EncodeToString(wrappedKey) + ":" + base64.RawStdEncoding.EncodeToString(sealed), nil
+}
+
+func seal(key, plaintext []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	return gcm.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+func open(key, sealed []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed) < gcm.NonceSize() {
+		return nil, errors.New("encrypted value is too short")
+	}
+	plaintext, err := gcm.Open(nil, sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():], nil)
+	if err != nil {
+		return nil, errors.New("encrypted value failed authentication")
+	}
+	return plaintext, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
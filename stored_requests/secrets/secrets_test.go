@@ -0,0 +1,155 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testMasterKey = []byte("0123456789abcdef0123456789abcdef")
+
+type countingKeyProvider struct {
+	KeyProvider
+	calls int
+}
+
+func (p *countingKeyProvider) UnwrapDataKey(ctx context.Context, keyID string, wrappedKey []byte) ([]byte, error) {
+	p.calls++
+	return p.KeyProvider.UnwrapDataKey(ctx, keyID, wrappedKey)
+}
+
+func encryptForTest(t *testing.T, provider *LocalKeyProvider, plaintext string) string {
+	dataKey, wrappedKey, err := provider.NewDataKey()
+	require.NoError(t, err)
+	value, err := Encrypt("local", dataKey, wrappedKey, plaintext)
+	require.NoError(t, err)
+	return value
+}
+
+func TestDecryptJSON(t *testing.T) {
+	provider, err := NewLocalKeyProvider(testMasterKey)
+	require.NoError(t, err)
+	apiKey := encryptForTest(t, provider, "partner-api-key")
+	token := encryptForTest(t, provider, "token")
+
+	tests := []struct {
+		description  string
+		input        string
+		expectedJSON string
+		expectError  bool
+	}{
+		{
+			description:  "no encrypted values",
+			input:        `{"id":"1","tmax":500}`,
+			expectedJSON: `{"id":"1","tmax":500}`,
+		},
+		{
+			description:  "nested encrypted values",
+			input:        `{"hooks":{"modules":{"vendor":{"module":{"api_key":"` + apiKey + `","tokens":["` + token + `"],"big":12345678901234567890}}}}}`,
+			expectedJSON: `{"hooks":{"modules":{"vendor":{"module":{"api_key":"partner-api-key","tokens":["token"],"big":12345678901234567890}}}}}`,
+		},
+		{
+			description: "tampered value",
+			input:       `{"api_key":"` + apiKey[:len(apiKey)-4] + `AAAA"}`,
+			expectError: true,
+		},
+		{
+			description: "malformed value",
+			input:       `{"api_key":"enc:v1:local:not-base64"}`,
+			expectError: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			decrypter := NewDecrypter(provider)
+			result, err := decrypter.DecryptJSON(context.Background(), json.RawMessage(test.input))
+			if test.expectError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.JSONEq(t, test.expectedJSON, string(result))
+		})
+	}
+}
+
+func TestDecryptCachesDataKeys(t *testing.T) {
+	localProvider, err := NewLocalKeyProvider(testMasterKey)
+	require.NoError(t, err)
+	value := encryptForTest(t, localProvider, "secret")
+	provider := &countingKeyProvider{KeyProvider: localProvider}
+	decrypter := NewDecrypter(provider)
+
+	for i := 0; i < 3; i++ {
+		plaintext, err := decrypter.Decrypt(context.Background(), value)
+		assert.NoError(t, err)
+		assert.Equal(t, "secret", plaintext)
+	}
+	assert.Equal(t, 1, provider.calls, "data key should only be unwrapped once")
+}
+
+func TestNewLocalKeyProviderInvalidKey(t *testing.T) {
+	_, err := NewLocalKeyProvider([]byte("short"))
+	assert.Error(t, err)
+}
+
+func TestKMSKeyProvider(t *testing.T) {
+	dataKey := []byte("fedcba9876543210fedcba9876543210")
+	tests := []struct {
+		description string
+		status      int
+		body        string
+		expectError bool
+	}{
+		{
+			description: "success",
+			status:      http.StatusOK,
+		},
+		{
+			description: "error status",
+			status:      http.StatusForbidden,
+			expectError: true,
+		},
+		{
+			description: "malformed response",
+			status:      http.StatusOK,
+			body:        `{"plaintext":`,
+			expectError: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var req kmsDecryptRequest
+				if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.KeyID != "tenant-key" || string(req.Ciphertext) != "wrapped" {
+					w.WriteHeader(http.StatusBadRequest)
+					return
+				}
+				w.WriteHeader(test.status)
+				if test.body != "" {
+					w.Write([]byte(test.body))
+					return
+				}
+				json.NewEncoder(w).Encode(kmsDecryptResponse{Plaintext: dataKey})
+			}))
+			defer server.Close()
+
+			provider := NewKMSKeyProvider(server.Client(), server.URL, time.Second)
+			key, err := provider.UnwrapDataKey(context.Background(), "tenant-key", []byte("wrapped"))
+			if test.expectError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, dataKey, key)
+		})
+	}
+}
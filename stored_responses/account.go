@@ -0,0 +1,118 @@
+package stored_responses
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/errortypes"
+	"github.com/prebid/prebid-server/v2/openrtb_ext"
+	"github.com/prebid/prebid-server/v2/stored_requests"
+)
+
+// anyImpID is the imp ID of the account mappings which apply to every imp
+const anyImpID = "*"
+
+// ProcessAccountStoredBidResponses returns the stored bid responses the account maps the imps and bidders of the
+// request to, by lower case bidder name and imp ID, along with whether the imp IDs of their bids are replaced.
+// Only the bidders of each imp are mapped, and imps with a stored auction response are left out. It also returns
+// a warning for each bidder answered, so the response shows the bids aren't what the bidders would have sent,
+// and for each stored response which wasn't found.
+func ProcessAccountStoredBidResponses(ctx context.Context, requestWrapper *openrtb_ext.RequestWrapper, cfg config.AccountStoredBidResponses, debugToken bool, storedRespFetcher stored_requests.Fetcher) (BidderImpsWithBidResponses, BidderImpReplaceImpID, []error) {
+	if !cfg.Enabled || len(cfg.Mappings) == 0 || (cfg.DebugTokenOnly && !debugToken) {
+		return nil, nil, nil
+	}
+
+	// lower case bidder -> imp id -> mapping
+	mappings := make(map[string]map[string]config.AccountStoredBidResponse, len(cfg.Mappings))
+	for _, mapping := range cfg.Mappings {
+		if mapping.ImpID == "" || mapping.Bidder == "" || mapping.ID == "" {
+			continue
+		}
+		bidder := strings.ToLower(mapping.Bidder)
+		if mappings[bidder] == nil {
+			mappings[bidder] = make(map[string]config.AccountStoredBidResponse)
+		}
+		mappings[bidder][mapping.ImpID] = mapping
+	}
+
+	// lower case bidder -> imp id -> mapping which applies
+	applied := make(map[string]map[string]config.AccountStoredBidResponse)
+	var ids []string
+	for _, imp := range requestWrapper.GetImp() {
+		impExt, err := imp.GetImpExt()
+		if err != nil {
+			return nil, nil, []error{err}
+		}
+		prebid := impExt.GetPrebid()
+		if prebid == nil || prebid.StoredAuctionResponse != nil {
+			continue
+		}
+		for bidderName := range prebid.Bidder {
+			bidder := strings.ToLower(bidderName)
+			mapping, ok := mappings[bidder][imp.ID]
+			if !ok {
+				mapping, ok = mappings[bidder][anyImpID]
+			}
+			if !ok {
+				continue
+			}
+			if applied[bidder] == nil {
+				applied[bidder] = make(map[string]config.AccountStoredBidResponse)
+			}
+			applied[bidder][imp.ID] = mapping
+			ids = append(ids, mapping.ID)
+		}
+	}
+	if len(ids) == 0 {
+		return nil, nil, nil
+	}
+
+	// the fetcher's errors are for responses which weren't found, which are warned about below
+	storedResponses, _ := storedRespFetcher.FetchResponses(ctx, ids)
+
+	bidderImpResponses := BidderImpsWithBidResponses{}
+	bidderImpReplaceImpID := BidderImpReplaceImpID{}
+	var warnings []error
+	for _, bidder := range sortedKeys(applied) {
+		impMappings := applied[bidder]
+		var impIDs []string
+		for _, impID := range sortedKeys(impMappings) {
+			mapping := impMappings[impID]
+			response := storedResponses[mapping.ID]
+			if len(response) == 0 {
+				warnings = append(warnings, &errortypes.Warning{
+					Message:     fmt.Sprintf("account stored bid response of bidder %s for imp %s ignored: stored response %s not found", mapping.Bidder, impID, mapping.ID),
+					WarningCode: errortypes.AccountStoredBidResponseWarningCode,
+				})
+				continue
+			}
+			if bidderImpResponses[openrtb_ext.BidderName(bidder)] == nil {
+				bidderImpResponses[openrtb_ext.BidderName(bidder)] = make(map[string]json.RawMessage)
+				bidderImpReplaceImpID[bidder] = make(map[string]bool)
+			}
+			bidderImpResponses[openrtb_ext.BidderName(bidder)][impID] = response
+			bidderImpReplaceImpID[bidder][impID] = mapping.ReplaceImpID == nil || *mapping.ReplaceImpID
+			impIDs = append(impIDs, impID)
+		}
+		if len(impIDs) > 0 {
+			warnings = append(warnings, &errortypes.Warning{
+				Message:     fmt.Sprintf("bidder %s answers imps %s with stored bid responses of the account", bidder, strings.Join(impIDs, ", ")),
+				WarningCode: errortypes.AccountStoredBidResponseWarningCode,
+			})
+		}
+	}
+	return bidderImpResponses, bidderImpReplaceImpID, warnings
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
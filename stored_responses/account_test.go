@@ -0,0 +1,88 @@
+package stored_responses
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/errortypes"
+	"github.com/prebid/prebid-server/v2/openrtb_ext"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProcessAccountStoredBidResponses(t *testing.T) {
+	noReplace := false
+	fetcher := &mockStoredBidResponseFetcher{map[string]json.RawMessage{
+		"resp-1": json.RawMessage(`{"id":"resp-1"}`),
+		"resp-2": json.RawMessage(`{"id":"resp-2"}`),
+	}}
+	request := &openrtb2.BidRequest{Imp: []openrtb2.Imp{
+		{ID: "imp-1", Ext: json.RawMessage(`{"prebid":{"bidder":{"appnexus":{},"rubicon":{}}}}`)},
+		{ID: "imp-2", Ext: json.RawMessage(`{"prebid":{"bidder":{"appnexus":{},"openx":{}}}}`)},
+		{ID: "imp-3", Ext: json.RawMessage(`{"prebid":{"bidder":{"appnexus":{}},"storedauctionresponse":{"id":"auction-1"}}}`)},
+	}}
+	mappings := []config.AccountStoredBidResponse{
+		{ImpID: "*", Bidder: "AppNexus", ID: "resp-1"},
+		{ImpID: "imp-2", Bidder: "appnexus", ID: "resp-2", ReplaceImpID: &noReplace},
+		{ImpID: "imp-1", Bidder: "openx", ID: "resp-1"},
+		{ImpID: "imp-2", Bidder: "openx", ID: "resp-3"},
+		{ImpID: "imp-1", Bidder: "pubmatic", ID: "resp-1"},
+	}
+
+	testCases := []struct {
+		description       string
+		config            config.AccountStoredBidResponses
+		debugToken        bool
+		expectedResponses BidderImpsWithBidResponses
+		expectedReplace   BidderImpReplaceImpID
+		expectedWarnings  []string
+	}{
+		{
+			description: "disabled",
+			config:      config.AccountStoredBidResponses{Mappings: mappings},
+		},
+		{
+			description: "debug-token-only-without-token",
+			config:      config.AccountStoredBidResponses{Enabled: true, DebugTokenOnly: true, Mappings: mappings},
+		},
+		{
+			description: "debug-token-only-with-token",
+			config:      config.AccountStoredBidResponses{Enabled: true, DebugTokenOnly: true, Mappings: mappings[:1]},
+			debugToken:  true,
+			expectedResponses: BidderImpsWithBidResponses{
+				"appnexus": {"imp-1": json.RawMessage(`{"id":"resp-1"}`), "imp-2": json.RawMessage(`{"id":"resp-1"}`)},
+			},
+			expectedReplace:  BidderImpReplaceImpID{"appnexus": {"imp-1": true, "imp-2": true}},
+			expectedWarnings: []string{"bidder appnexus answers imps imp-1, imp-2 with stored bid responses of the account"},
+		},
+		{
+			description: "exact-imps-win-and-missing-responses-are-ignored",
+			config:      config.AccountStoredBidResponses{Enabled: true, Mappings: mappings},
+			expectedResponses: BidderImpsWithBidResponses{
+				"appnexus": {"imp-1": json.RawMessage(`{"id":"resp-1"}`), "imp-2": json.RawMessage(`{"id":"resp-2"}`)},
+			},
+			expectedReplace: BidderImpReplaceImpID{"appnexus": {"imp-1": true, "imp-2": false}},
+			expectedWarnings: []string{
+				"bidder appnexus answers imps imp-1, imp-2 with stored bid responses of the account",
+				"account stored bid response of bidder openx for imp imp-2 ignored: stored response resp-3 not found",
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			responses, replace, errs := ProcessAccountStoredBidResponses(context.Background(), &openrtb_ext.RequestWrapper{BidRequest: request}, test.config, test.debugToken, fetcher)
+
+			assert.Equal(t, test.expectedResponses, responses)
+			assert.Equal(t, test.expectedReplace, replace)
+			var warnings []string
+			for _, err := range errs {
+				assert.Equal(t, errortypes.AccountStoredBidResponseWarningCode, errortypes.ReadCode(err))
+				warnings = append(warnings, err.Error())
+			}
+			assert.Equal(t, test.expectedWarnings, warnings)
+		})
+	}
+}
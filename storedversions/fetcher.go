@@ -0,0 +1,40 @@
+package storedversions
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/stored_requests"
+)
+
+// Fetcher returns a fetcher which adds the stored requests and imps it returns to the history of the config
+// section of dataType whenever they change.
+func (v *Versions) Fetcher(dataType config.DataType, fetcher stored_requests.Fetcher) stored_requests.Fetcher {
+	if v == nil {
+		return fetcher
+	}
+	return &versionsFetcher{Fetcher: fetcher, versions: v, section: dataType.Section()}
+}
+
+type versionsFetcher struct {
+	stored_requests.Fetcher
+	versions *Versions
+	section  string
+}
+
+func (f *versionsFetcher) FetchRequests(ctx context.Context, requestIDs []string, impIDs []string) (map[string]json.RawMessage, map[string]json.RawMessage, []error) {
+	requestData, impData, errs := f.Fetcher.FetchRequests(ctx, requestIDs, impIDs)
+
+	changedRequests := f.versions.changed(f.section, TypeRequest, requestData)
+	changedImps := f.versions.changed(f.section, TypeImp, impData)
+	if len(changedRequests) == 0 && len(changedImps) == 0 {
+		return requestData, impData, errs
+	}
+
+	// the backend's versions are only fetched for new data, which is rare
+	requestVersions, impVersions := stored_requests.FetchRequestVersions(ctx, f.Fetcher, changedRequests, changedImps)
+	f.versions.add(f.section, TypeRequest, changedRequests, requestData, requestVersions)
+	f.versions.add(f.section, TypeImp, changedImps, impData, impVersions)
+	return requestData, impData, errs
+}
@@ -0,0 +1,138 @@
+package storedversions
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"time"
+
+	"github.com/prebid/prebid-server/v2/logger"
+	"github.com/prebid/prebid-server/v2/util/httputil"
+	"github.com/prebid/prebid-server/v2/util/jsonutil"
+)
+
+// maxRequestSize limits the body of requests which set a pin.
+const maxRequestSize = 64 * 1024
+
+// pinRequest is the body of a PUT request, which sets a pin.
+type pinRequest struct {
+	Account string `json:"account"`
+	Section string `json:"section"`
+	Type    string `json:"type"`
+	ID      string `json:"id"`
+	Version string `json:"version"`
+}
+
+// scopeInstance is the scope of every response. The versions and pins are those of the instance which served
+// the request: the other instances have their own, and they're lost when the instance restarts.
+const scopeInstance = "instance"
+
+type historyResponse struct {
+	Scope    string    `json:"scope"`
+	Versions []Version `json:"versions"`
+}
+
+type pinsResponse struct {
+	Scope string `json:"scope"`
+	Pins  []Pin  `json:"pins"`
+}
+
+type pinResponse struct {
+	Scope string `json:"scope"`
+	Pin   Pin    `json:"pin"`
+}
+
+type unpinResponse struct {
+	Scope    string `json:"scope"`
+	Unpinned int    `json:"unpinned"`
+}
+
+// Handler returns a handler which serves the history and manages the pins, or nil if stored request versions
+// are disabled. Requests must have one of the configured tokens as their bearer token.
+//
+// Requests to a path ending in /pins manage the pins: GET lists the pins in effect, of the account in the
+// query string if there is one, PUT sets the pin in the body, and DELETE removes the pins of the account and,
+// optionally, the stored request or imp in the query string. GET requests to any other path list the history
+// of the stored request or imp in the query string. Only this instance's versions and pins are served and
+// changed, which every response says with its scope.
+func (v *Versions) Handler() http.Handler {
+	if v == nil {
+		return nil
+	}
+	return http.HandlerFunc(v.serve)
+}
+
+func (v *Versions) serve(w http.ResponseWriter, req *http.Request) {
+	if !httputil.HasBearerToken(req, v.tokens) {
+		w.Header().Set("WWW-Authenticate", "Bearer")
+		http.Error(w, "A valid bearer token is required", http.StatusUnauthorized)
+		return
+	}
+	if path.Base(req.URL.Path) == "pins" {
+		v.servePins(w, req)
+		return
+	}
+	if req.Method != http.MethodGet {
+		w.Header().Set("Allow", "GET")
+		http.Error(w, "Stored request versions are listed with GET", http.StatusMethodNotAllowed)
+		return
+	}
+	params := req.URL.Query()
+	section, dataType, id := params.Get("section"), params.Get("type"), params.Get("id")
+	if dataType == "" || id == "" {
+		http.Error(w, "The type and id of the stored request or imp are required", http.StatusBadRequest)
+		return
+	}
+	httputil.WriteJSON(w, historyResponse{Scope: scopeInstance, Versions: v.History(section, dataType, id)})
+}
+
+func (v *Versions) servePins(w http.ResponseWriter, req *http.Request) {
+	switch req.Method {
+	case http.MethodGet:
+		httputil.WriteJSON(w, pinsResponse{Scope: scopeInstance, Pins: v.Pins(req.URL.Query().Get("account"))})
+	case http.MethodPut:
+		v.servePin(w, req)
+	case http.MethodDelete:
+		params := req.URL.Query()
+		account, section, dataType, id := params.Get("account"), params.Get("section"), params.Get("type"), params.Get("id")
+		if account == "" {
+			http.Error(w, "The account of the pins to remove is required", http.StatusBadRequest)
+			return
+		}
+		unpinned := v.Unpin(account, section, dataType, id)
+		if unpinned > 0 {
+			logger.Warningf("Stored request pins of account %s removed: section=%q type=%q id=%q count=%d", account, section, dataType, id, unpinned)
+		}
+		httputil.WriteJSON(w, unpinResponse{Scope: scopeInstance, Unpinned: unpinned})
+	default:
+		w.Header().Set("Allow", "GET, PUT, DELETE")
+		http.Error(w, "Stored request pins are managed with GET, PUT and DELETE", http.StatusMethodNotAllowed)
+	}
+}
+
+func (v *Versions) servePin(w http.ResponseWriter, req *http.Request) {
+	body, err := io.ReadAll(io.LimitReader(req.Body, maxRequestSize))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid request: %v", err), http.StatusBadRequest)
+		return
+	}
+	var request pinRequest
+	if err := jsonutil.UnmarshalValid(body, &request); err != nil {
+		http.Error(w, fmt.Sprintf("Invalid request: %v", err), http.StatusBadRequest)
+		return
+	}
+	pin, err := v.Pin(Pin{
+		Account: request.Account,
+		Section: request.Section,
+		Type:    request.Type,
+		ID:      request.ID,
+		Version: request.Version,
+	})
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid request: %v", err), http.StatusBadRequest)
+		return
+	}
+	logger.Warningf("Account %s pinned to version %s of %s %s of %s at %s", pin.Account, pin.Version, pin.Type, pin.ID, pin.Section, pin.PinnedAt.UTC().Format(time.RFC3339))
+	httputil.WriteJSON(w, pinResponse{Scope: scopeInstance, Pin: pin})
+}
@@ -0,0 +1,165 @@
+package storedversions
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/util/timeutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler(t *testing.T) {
+	clock := timeutil.NewFakeTime(time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC))
+	v := newTestVersions(clock)
+	fetcher := v.Fetcher(config.RequestDataType, &requestFetcher{
+		requests:        map[string]json.RawMessage{"req-1": json.RawMessage(`{"v":1}`)},
+		requestVersions: map[string]string{"req-1": "etag-1"},
+	})
+	fetcher.FetchRequests(context.Background(), []string{"req-1"}, nil)
+	handler := v.Handler()
+
+	serve := func(method, target, authorization, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		if authorization != "" {
+			req.Header.Set("Authorization", authorization)
+		}
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, req)
+		return recorder
+	}
+
+	tests := []struct {
+		description    string
+		method         string
+		target         string
+		authorization  string
+		body           string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			description:    "no-token",
+			method:         http.MethodGet,
+			target:         "/stored_requests/versions?type=request&id=req-1",
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			description:    "wrong-token",
+			method:         http.MethodPut,
+			target:         "/stored_requests/pins",
+			authorization:  "Bearer other",
+			body:           `{"account":"acct","type":"request","id":"req-1","version":"etag-1"}`,
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			description:    "history",
+			method:         http.MethodGet,
+			target:         "/stored_requests/versions?type=request&id=req-1",
+			authorization:  "Bearer token",
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"scope":"instance","versions":[{"version":"etag-1","since":"2024-05-01T12:00:00Z","data":{"v":1}}]}`,
+		},
+		{
+			description:    "history-of-other-section",
+			method:         http.MethodGet,
+			target:         "/stored_requests/versions?section=stored_amp_req&type=request&id=req-1",
+			authorization:  "Bearer token",
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"scope":"instance","versions":[]}`,
+		},
+		{
+			description:    "history-without-id",
+			method:         http.MethodGet,
+			target:         "/stored_requests/versions?type=request",
+			authorization:  "Bearer token",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			description:    "history-wrong-method",
+			method:         http.MethodPut,
+			target:         "/stored_requests/versions",
+			authorization:  "Bearer token",
+			expectedStatus: http.StatusMethodNotAllowed,
+		},
+		{
+			description:    "pin",
+			method:         http.MethodPut,
+			target:         "/stored_requests/pins",
+			authorization:  "Bearer token",
+			body:           `{"account":"acct","type":"request","id":"req-1","version":"etag-1"}`,
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"scope":"instance","pin":{"account":"acct","section":"stored_requests","type":"request","id":"req-1","version":"etag-1","pinned_at":"2024-05-01T12:00:00Z"}}`,
+		},
+		{
+			description:    "pin-unknown-version",
+			method:         http.MethodPut,
+			target:         "/stored_requests/pins",
+			authorization:  "Bearer token",
+			body:           `{"account":"acct","type":"request","id":"req-1","version":"etag-0"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			description:    "pin-malformed",
+			method:         http.MethodPut,
+			target:         "/stored_requests/pins",
+			authorization:  "Bearer token",
+			body:           `{"account":`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			description:    "list",
+			method:         http.MethodGet,
+			target:         "/stored_requests/pins?account=acct",
+			authorization:  "Bearer token",
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"scope":"instance","pins":[{"account":"acct","section":"stored_requests","type":"request","id":"req-1","version":"etag-1","pinned_at":"2024-05-01T12:00:00Z"}]}`,
+		},
+		{
+			description:    "unpin-without-account",
+			method:         http.MethodDelete,
+			target:         "/stored_requests/pins?type=request&id=req-1",
+			authorization:  "Bearer token",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			description:    "unpin",
+			method:         http.MethodDelete,
+			target:         "/stored_requests/pins?account=acct&type=request&id=req-1",
+			authorization:  "Bearer token",
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"scope":"instance","unpinned":1}`,
+		},
+		{
+			description:    "list-after-unpin",
+			method:         http.MethodGet,
+			target:         "/stored_requests/pins",
+			authorization:  "Bearer token",
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"scope":"instance","pins":[]}`,
+		},
+		{
+			description:    "pins-wrong-method",
+			method:         http.MethodPost,
+			target:         "/stored_requests/pins",
+			authorization:  "Bearer token",
+			expectedStatus: http.StatusMethodNotAllowed,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			recorder := serve(test.method, test.target, test.authorization, test.body)
+			require.Equal(t, test.expectedStatus, recorder.Code, recorder.Body.String())
+			if test.expectedBody != "" {
+				assert.JSONEq(t, test.expectedBody, recorder.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,303 @@
+// Package storedversions keeps the last versions of the stored requests and imps served to auctions, and pins
+// accounts to one of them, so that a broken stored request edit can be rolled back for the accounts it broke
+// at once, while the stored request is being fixed. Versions and pins are kept in memory: each instance only
+// knows the versions it served since it started, each instance must be given the pins, and neither survives a
+// restart.
+package storedversions
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/stored_requests"
+	"github.com/prebid/prebid-server/v2/util/timeutil"
+)
+
+// The types of stored data which have versions
+const (
+	TypeRequest = "request"
+	TypeImp     = "imp"
+)
+
+// sections are the config sections whose stored requests and imps have versions
+var sections = map[string]bool{
+	config.RequestDataType.Section():    true,
+	config.AMPRequestDataType.Section(): true,
+}
+
+// Version is a version of a stored request or imp.
+type Version struct {
+	// Version is the version its backend keeps, such as an ETag, or a hash of its content if the backend
+	// keeps none
+	Version string `json:"version"`
+	// Since is when this instance started serving the version
+	Since time.Time       `json:"since"`
+	Data  json.RawMessage `json:"data"`
+}
+
+// Pin serves an account a previous version of a stored request or imp instead of its current one.
+type Pin struct {
+	Account string `json:"account"`
+	// Section is the config section of the stored request or imp: stored_requests or stored_amp_req
+	Section  string    `json:"section"`
+	Type     string    `json:"type"`
+	ID       string    `json:"id"`
+	Version  string    `json:"version"`
+	PinnedAt time.Time `json:"pinned_at"`
+
+	// data is kept with the pin, so that it outlives the version in the history
+	data json.RawMessage
+}
+
+type dataKey struct {
+	section  string
+	dataType string
+	id       string
+}
+
+// Versions holds the history of the stored requests and imps, and the pins in effect. A nil *Versions is
+// valid, and keeps no history.
+type Versions struct {
+	maxVersions int
+	maxPins     int
+	tokens      [][]byte
+	clock       timeutil.Time
+
+	mutex sync.RWMutex
+	// history has the versions of each stored request and imp, oldest first
+	history map[dataKey][]Version
+	// pins are by account
+	pins    map[string]map[dataKey]Pin
+	pinsLen int
+}
+
+// New builds the Versions, or returns nil if stored request versions are disabled.
+func New(cfg config.StoredRequestVersions) *Versions {
+	if !cfg.Enabled {
+		return nil
+	}
+	v := &Versions{
+		maxVersions: cfg.MaxVersions,
+		maxPins:     cfg.MaxPins,
+		clock:       &timeutil.RealTime{},
+		history:     make(map[dataKey][]Version),
+		pins:        make(map[string]map[dataKey]Pin),
+	}
+	for _, token := range cfg.Tokens {
+		v.tokens = append(v.tokens, []byte(token))
+	}
+	return v
+}
+
+// History returns the versions of a stored request or imp this instance served, newest first. The section
+// defaults to stored_requests.
+func (v *Versions) History(section, dataType, id string) []Version {
+	if section == "" {
+		section = config.RequestDataType.Section()
+	}
+
+	v.mutex.RLock()
+	defer v.mutex.RUnlock()
+
+	history := v.history[dataKey{section: section, dataType: dataType, id: id}]
+	versions := make([]Version, 0, len(history))
+	for i := len(history) - 1; i >= 0; i-- {
+		versions = append(versions, history[i])
+	}
+	return versions
+}
+
+// Pin serves the account the version of the stored request or imp in place of its current one, instead of
+// the version it was pinned to before, if it was. The version must be in the history, and the section
+// defaults to stored_requests. It returns the pin.
+func (v *Versions) Pin(pin Pin) (Pin, error) {
+	if pin.Account == "" || pin.ID == "" || pin.Version == "" {
+		return pin, errors.New("account, id and version are required")
+	}
+	if pin.Section == "" {
+		pin.Section = config.RequestDataType.Section()
+	}
+	if !sections[pin.Section] {
+		return pin, fmt.Errorf("section must be %s or %s", config.RequestDataType.Section(), config.AMPRequestDataType.Section())
+	}
+	if pin.Type != TypeRequest && pin.Type != TypeImp {
+		return pin, fmt.Errorf("type must be %s or %s", TypeRequest, TypeImp)
+	}
+	k := dataKey{section: pin.Section, dataType: pin.Type, id: pin.ID}
+
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+	for _, version := range v.history[k] {
+		if version.Version == pin.Version {
+			pin.data = version.Data
+		}
+	}
+	if pin.data == nil {
+		return pin, fmt.Errorf("version %s of %s %s isn't in the history of this instance", pin.Version, pin.Type, pin.ID)
+	}
+	_, replaced := v.pins[pin.Account][k]
+	if !replaced && v.pinsLen >= v.maxPins {
+		return pin, fmt.Errorf("there are already %d pins in effect", v.maxPins)
+	}
+	if !replaced {
+		v.pinsLen++
+	}
+	if v.pins[pin.Account] == nil {
+		v.pins[pin.Account] = make(map[dataKey]Pin)
+	}
+	pin.PinnedAt = v.clock.Now()
+	v.pins[pin.Account][k] = pin
+	return pin, nil
+}
+
+// Unpin removes the account's pin of the stored request or imp, or all of the account's pins if id is empty.
+// It returns the number of pins removed.
+func (v *Versions) Unpin(account, section, dataType, id string) int {
+	if section == "" {
+		section = config.RequestDataType.Section()
+	}
+
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+	if id == "" {
+		removed := len(v.pins[account])
+		delete(v.pins, account)
+		v.pinsLen -= removed
+		return removed
+	}
+	k := dataKey{section: section, dataType: dataType, id: id}
+	if _, ok := v.pins[account][k]; !ok {
+		return 0
+	}
+	delete(v.pins[account], k)
+	if len(v.pins[account]) == 0 {
+		delete(v.pins, account)
+	}
+	v.pinsLen--
+	return 1
+}
+
+// Pins returns the pins in effect, of the account if one is given, by account, section, type and ID.
+func (v *Versions) Pins(account string) []Pin {
+	v.mutex.RLock()
+	pins := make([]Pin, 0, v.pinsLen)
+	for pinnedAccount, accountPins := range v.pins {
+		if account != "" && pinnedAccount != account {
+			continue
+		}
+		for _, pin := range accountPins {
+			pins = append(pins, pin)
+		}
+	}
+	v.mutex.RUnlock()
+
+	sort.Slice(pins, func(i, j int) bool {
+		a, b := pins[i], pins[j]
+		if a.Account != b.Account {
+			return a.Account < b.Account
+		}
+		if a.Section != b.Section {
+			return a.Section < b.Section
+		}
+		if a.Type != b.Type {
+			return a.Type < b.Type
+		}
+		return a.ID < b.ID
+	})
+	return pins
+}
+
+// Pinned returns the stored requests and imps of the config section of dataType, with the data of those the
+// account is pinned to a version of replaced by it. The maps given aren't changed, since fetchers' data may
+// only be read.
+func (v *Versions) Pinned(dataType config.DataType, account string, requests, imps map[string]json.RawMessage) (map[string]json.RawMessage, map[string]json.RawMessage) {
+	if v == nil {
+		return requests, imps
+	}
+	section := dataType.Section()
+
+	v.mutex.RLock()
+	defer v.mutex.RUnlock()
+	accountPins := v.pins[account]
+	if len(accountPins) == 0 {
+		return requests, imps
+	}
+	return pinnedData(accountPins, section, TypeRequest, requests), pinnedData(accountPins, section, TypeImp, imps)
+}
+
+func pinnedData(pins map[dataKey]Pin, section, dataType string, data map[string]json.RawMessage) map[string]json.RawMessage {
+	var pinned map[string]json.RawMessage
+	for id := range data {
+		pin, ok := pins[dataKey{section: section, dataType: dataType, id: id}]
+		if !ok {
+			continue
+		}
+		if pinned == nil {
+			pinned = make(map[string]json.RawMessage, len(data))
+			for id, value := range data {
+				pinned[id] = value
+			}
+		}
+		pinned[id] = pin.data
+	}
+	if pinned == nil {
+		return data
+	}
+	return pinned
+}
+
+// changed returns the IDs of the data whose content differs from the last version in the history.
+func (v *Versions) changed(section, dataType string, data map[string]json.RawMessage) []string {
+	var ids []string
+	v.mutex.RLock()
+	defer v.mutex.RUnlock()
+	for id, value := range data {
+		history := v.history[dataKey{section: section, dataType: dataType, id: id}]
+		if len(history) == 0 || !bytes.Equal(history[len(history)-1].Data, value) {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// add adds the data of the IDs to the history as their latest versions, which are the versions given for them,
+// or hashes of their content if they have none. Older versions beyond the most kept are dropped.
+func (v *Versions) add(section, dataType string, ids []string, data map[string]json.RawMessage, backendVersions map[string]string) {
+	now := v.clock.Now()
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+	for _, id := range ids {
+		k := dataKey{section: section, dataType: dataType, id: id}
+		history := v.history[k]
+		value := data[id]
+		// another fetch may have added it in the meantime
+		if len(history) > 0 && bytes.Equal(history[len(history)-1].Data, value) {
+			continue
+		}
+
+		version, ok := backendVersions[id]
+		if !ok || (len(history) > 0 && history[len(history)-1].Version == version) {
+			// the backend's version is behind its data, which happens while a cache is being updated
+			version = stored_requests.ContentVersion(value)
+		}
+
+		// a version served again, like an edit which was reverted, is moved to the end
+		kept := make([]Version, 0, len(history)+1)
+		for _, previous := range history {
+			if previous.Version != version {
+				kept = append(kept, previous)
+			}
+		}
+		kept = append(kept, Version{Version: version, Since: now, Data: value})
+		if len(kept) > v.maxVersions {
+			kept = kept[len(kept)-v.maxVersions:]
+		}
+		v.history[k] = kept
+	}
+}
@@ -0,0 +1,219 @@
+package storedversions
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/stored_requests"
+	"github.com/prebid/prebid-server/v2/util/timeutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// requestFetcher has stored requests and imps, by ID, and the versions its backend keeps of some of them.
+type requestFetcher struct {
+	requests        map[string]json.RawMessage
+	imps            map[string]json.RawMessage
+	requestVersions map[string]string
+}
+
+func (f *requestFetcher) FetchRequests(ctx context.Context, requestIDs []string, impIDs []string) (map[string]json.RawMessage, map[string]json.RawMessage, []error) {
+	requests := make(map[string]json.RawMessage)
+	for _, id := range requestIDs {
+		if data, ok := f.requests[id]; ok {
+			requests[id] = data
+		}
+	}
+	imps := make(map[string]json.RawMessage)
+	for _, id := range impIDs {
+		if data, ok := f.imps[id]; ok {
+			imps[id] = data
+		}
+	}
+	return requests, imps, nil
+}
+
+func (f *requestFetcher) FetchResponses(ctx context.Context, ids []string) (map[string]json.RawMessage, []error) {
+	return nil, nil
+}
+
+func (f *requestFetcher) FetchRequestVersions(ctx context.Context, requestIDs []string, impIDs []string) (map[string]string, map[string]string) {
+	return f.requestVersions, nil
+}
+
+func newTestVersions(clock timeutil.Time) *Versions {
+	v := New(config.StoredRequestVersions{Enabled: true, MaxVersions: 3, MaxPins: 2, Tokens: []string{"token"}})
+	v.clock = clock
+	return v
+}
+
+func TestNilPassesThrough(t *testing.T) {
+	var v *Versions
+	backend := &requestFetcher{}
+	assert.Same(t, backend, v.Fetcher(config.RequestDataType, backend))
+
+	requests := map[string]json.RawMessage{"req-1": json.RawMessage(`{}`)}
+	pinnedRequests, pinnedImps := v.Pinned(config.RequestDataType, "acct", requests, nil)
+	assert.Equal(t, requests, pinnedRequests)
+	assert.Nil(t, pinnedImps)
+}
+
+func TestFetcherHistory(t *testing.T) {
+	clock := timeutil.NewFakeTime(time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC))
+	v := newTestVersions(clock)
+	backend := &requestFetcher{
+		requests: map[string]json.RawMessage{"req-1": json.RawMessage(`{"v":1}`)},
+		imps:     map[string]json.RawMessage{"imp-1": json.RawMessage(`{"v":1}`)},
+	}
+	fetcher := v.Fetcher(config.RequestDataType, backend)
+	fetch := func() {
+		_, _, errs := fetcher.FetchRequests(context.Background(), []string{"req-1"}, []string{"imp-1"})
+		require.Empty(t, errs)
+		clock.Advance(time.Minute)
+	}
+
+	fetch()
+	fetch()
+	backend.requests["req-1"] = json.RawMessage(`{"v":2}`)
+	backend.requestVersions = map[string]string{"req-1": "etag-2"}
+	fetch()
+	backend.requests["req-1"] = json.RawMessage(`{"v":3}`)
+	backend.requestVersions = map[string]string{"req-1": "etag-3"}
+	fetch()
+	// the backend hasn't caught up with the data yet
+	backend.requests["req-1"] = json.RawMessage(`{"v":4}`)
+	fetch()
+
+	v1 := stored_requests.ContentVersion(json.RawMessage(`{"v":1}`))
+	v4 := stored_requests.ContentVersion(json.RawMessage(`{"v":4}`))
+	assert.Equal(t, []Version{
+		{Version: v4, Since: clock.Now().Add(-time.Minute), Data: json.RawMessage(`{"v":4}`)},
+		{Version: "etag-3", Since: clock.Now().Add(-2 * time.Minute), Data: json.RawMessage(`{"v":3}`)},
+		{Version: "etag-2", Since: clock.Now().Add(-3 * time.Minute), Data: json.RawMessage(`{"v":2}`)},
+	}, v.History("", TypeRequest, "req-1"), "only the last versions are kept")
+	assert.Equal(t, []Version{
+		{Version: v1, Since: clock.Now().Add(-5 * time.Minute), Data: json.RawMessage(`{"v":1}`)},
+	}, v.History("stored_requests", TypeImp, "imp-1"), "data which didn't change has one version")
+	assert.Empty(t, v.History("stored_amp_req", TypeRequest, "req-1"), "sections have their own history")
+
+	// a version served again moves to the end
+	backend.requests["req-1"] = json.RawMessage(`{"v":2}`)
+	backend.requestVersions = map[string]string{"req-1": "etag-2"}
+	fetch()
+	history := v.History("", TypeRequest, "req-1")
+	require.Len(t, history, 3)
+	assert.Equal(t, []string{"etag-2", v4, "etag-3"}, []string{history[0].Version, history[1].Version, history[2].Version})
+}
+
+func TestPin(t *testing.T) {
+	clock := timeutil.NewFakeTime(time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC))
+	v := newTestVersions(clock)
+	fetcher := v.Fetcher(config.RequestDataType, &requestFetcher{
+		requests:        map[string]json.RawMessage{"req-1": json.RawMessage(`{"v":1}`), "req-2": json.RawMessage(`{}`)},
+		imps:            map[string]json.RawMessage{"imp-1": json.RawMessage(`{"v":1}`)},
+		requestVersions: map[string]string{"req-1": "etag-1", "req-2": "etag-1"},
+	})
+	fetcher.FetchRequests(context.Background(), []string{"req-1", "req-2"}, []string{"imp-1"})
+	impVersion := stored_requests.ContentVersion(json.RawMessage(`{"v":1}`))
+
+	tests := []struct {
+		description   string
+		pin           Pin
+		expectedError string
+	}{
+		{
+			description: "request",
+			pin:         Pin{Account: "acct-1", Type: TypeRequest, ID: "req-1", Version: "etag-1"},
+		},
+		{
+			description: "imp",
+			pin:         Pin{Account: "acct-1", Section: "stored_requests", Type: TypeImp, ID: "imp-1", Version: impVersion},
+		},
+		{
+			description: "replaced",
+			pin:         Pin{Account: "acct-1", Type: TypeRequest, ID: "req-1", Version: "etag-1"},
+		},
+		{
+			description:   "too-many",
+			pin:           Pin{Account: "acct-2", Type: TypeRequest, ID: "req-2", Version: "etag-1"},
+			expectedError: "there are already 2 pins in effect",
+		},
+		{
+			description:   "missing-fields",
+			pin:           Pin{Account: "acct-1", Type: TypeRequest, ID: "req-1"},
+			expectedError: "account, id and version are required",
+		},
+		{
+			description:   "unknown-section",
+			pin:           Pin{Account: "acct-1", Section: "stored_video_req", Type: TypeRequest, ID: "req-1", Version: "etag-1"},
+			expectedError: "section must be stored_requests or stored_amp_req",
+		},
+		{
+			description:   "unknown-type",
+			pin:           Pin{Account: "acct-1", Type: "response", ID: "req-1", Version: "etag-1"},
+			expectedError: "type must be request or imp",
+		},
+		{
+			description:   "unknown-version",
+			pin:           Pin{Account: "acct-1", Type: TypeRequest, ID: "req-1", Version: "etag-0"},
+			expectedError: "version etag-0 of request req-1 isn't in the history of this instance",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			pin, err := v.Pin(test.pin)
+			if test.expectedError != "" {
+				assert.EqualError(t, err, test.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, clock.Now(), pin.PinnedAt)
+			assert.Equal(t, "stored_requests", pin.Section)
+		})
+	}
+
+	pins := v.Pins("")
+	require.Len(t, pins, 2)
+	assert.Equal(t, []string{"imp-1", "req-1"}, []string{pins[0].ID, pins[1].ID})
+	assert.Empty(t, v.Pins("acct-2"))
+
+	assert.Equal(t, 0, v.Unpin("acct-1", "", TypeRequest, "req-2"))
+	assert.Equal(t, 1, v.Unpin("acct-1", "", TypeRequest, "req-1"))
+	assert.Equal(t, 1, v.Unpin("acct-1", "", "", ""))
+	assert.Empty(t, v.Pins(""))
+
+	_, err := v.Pin(Pin{Account: "acct-2", Type: TypeRequest, ID: "req-2", Version: "etag-1"})
+	assert.NoError(t, err, "unpinning makes room for other pins")
+}
+
+func TestPinned(t *testing.T) {
+	clock := timeutil.NewFakeTime(time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC))
+	v := newTestVersions(clock)
+	backend := &requestFetcher{
+		requests: map[string]json.RawMessage{"req-1": json.RawMessage(`{"v":1}`)},
+		imps:     map[string]json.RawMessage{"imp-1": json.RawMessage(`{"v":1}`), "imp-2": json.RawMessage(`{"v":1}`)},
+	}
+	fetcher := v.Fetcher(config.RequestDataType, backend)
+	fetcher.FetchRequests(context.Background(), []string{"req-1"}, []string{"imp-1", "imp-2"})
+	_, err := v.Pin(Pin{Account: "acct", Type: TypeImp, ID: "imp-1", Version: stored_requests.ContentVersion(json.RawMessage(`{"v":1}`))})
+	require.NoError(t, err)
+
+	// the broken edit
+	backend.imps["imp-1"] = json.RawMessage(`{"v":2}`)
+	backend.imps["imp-2"] = json.RawMessage(`{"v":2}`)
+	requests, imps, _ := fetcher.FetchRequests(context.Background(), []string{"req-1"}, []string{"imp-1", "imp-2"})
+
+	pinnedRequests, pinnedImps := v.Pinned(config.RequestDataType, "acct", requests, imps)
+	assert.Equal(t, requests, pinnedRequests)
+	assert.Equal(t, map[string]json.RawMessage{"imp-1": json.RawMessage(`{"v":1}`), "imp-2": json.RawMessage(`{"v":2}`)}, pinnedImps)
+	assert.Equal(t, json.RawMessage(`{"v":2}`), imps["imp-1"], "the fetcher's data isn't changed")
+
+	_, pinnedImps = v.Pinned(config.RequestDataType, "other", requests, imps)
+	assert.Equal(t, imps, pinnedImps, "other accounts get the current versions")
+	_, pinnedImps = v.Pinned(config.AMPRequestDataType, "acct", requests, imps)
+	assert.Equal(t, imps, pinnedImps, "pins only apply to their section")
+}
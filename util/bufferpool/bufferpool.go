@@ -0,0 +1,66 @@
+// Package bufferpool provides pooled byte buffers for reading payloads on the auction hot path.
+//
+// Buffers never leave this package's callers: data read into a pooled buffer is copied out before it is
+// returned, since request and response bodies are routinely retained, either whole or as sub-slices, by
+// adapters, modules and analytics long after the call which read them.
+package bufferpool
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"sync"
+)
+
+// ErrTooLarge is returned by ReadAllLimit when the data is larger than the limit.
+var ErrTooLarge = errors.New("data exceeds the size limit")
+
+// maxPooledCap is the largest buffer capacity returned to the pool, so one outsized payload doesn't
+// pin its buffer in memory indefinitely.
+const maxPooledCap = 1 << 20
+
+var pool = sync.Pool{
+	New: func() interface{} {
+		return new(bytes.Buffer)
+	},
+}
+
+// Get returns an empty buffer from the pool.
+func Get() *bytes.Buffer {
+	return pool.Get().(*bytes.Buffer)
+}
+
+// Put resets the buffer and returns it to the pool. The buffer must not be used afterwards.
+func Put(buf *bytes.Buffer) {
+	if buf.Cap() > maxPooledCap {
+		return
+	}
+	buf.Reset()
+	pool.Put(buf)
+}
+
+// ReadAll reads from r until EOF and returns an exactly sized copy of the data. Unlike io.ReadAll, which
+// grows its result repeatedly while reading, it reads into a pooled buffer and allocates once.
+func ReadAll(r io.Reader) ([]byte, error) {
+	return ReadAllLimit(r, 0)
+}
+
+// ReadAllLimit is like ReadAll, but stops reading and returns ErrTooLarge as soon as more than limit bytes
+// have been read, so an oversized payload is never buffered in full. A limit <= 0 means no limit.
+func ReadAllLimit(r io.Reader, limit int64) ([]byte, error) {
+	buf := Get()
+	defer Put(buf)
+
+	if limit > 0 {
+		r = io.LimitReader(r, limit+1)
+	}
+	if _, err := buf.ReadFrom(r); err != nil {
+		return nil, err
+	}
+	if limit > 0 && int64(buf.Len()) > limit {
+		return nil, ErrTooLarge
+	}
+	data := make([]byte, buf.Len())
+	copy(data, buf.Bytes())
+	return data, nil
+}
@@ -0,0 +1,145 @@
+package bufferpool
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strconv"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadAll(t *testing.T) {
+	tests := []struct {
+		description  string
+		reader       io.Reader
+		expectedData []byte
+		expectError  bool
+	}{
+		{
+			description:  "empty",
+			reader:       bytes.NewReader(nil),
+			expectedData: []byte{},
+		},
+		{
+			description:  "data",
+			reader:       bytes.NewReader([]byte(`{"id":"1"}`)),
+			expectedData: []byte(`{"id":"1"}`),
+		},
+		{
+			description:  "larger than the maximum pooled capacity",
+			reader:       bytes.NewReader(bytes.Repeat([]byte("a"), maxPooledCap+1)),
+			expectedData: bytes.Repeat([]byte("a"), maxPooledCap+1),
+		},
+		{
+			description: "read error",
+			reader:      iotest.ErrReader(errors.New("read failed")),
+			expectError: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			data, err := ReadAll(test.reader)
+			if test.expectError {
+				assert.Error(t, err)
+				assert.Nil(t, data)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.expectedData, data)
+			assert.Equal(t, len(data), cap(data), "data should be exactly sized")
+		})
+	}
+}
+
+func TestReadAllLimit(t *testing.T) {
+	tests := []struct {
+		description  string
+		data         []byte
+		limit        int64
+		expectedData []byte
+		expectedErr  error
+	}{
+		{
+			description:  "under the limit",
+			data:         []byte("abc"),
+			limit:        4,
+			expectedData: []byte("abc"),
+		},
+		{
+			description:  "at the limit",
+			data:         []byte("abcd"),
+			limit:        4,
+			expectedData: []byte("abcd"),
+		},
+		{
+			description: "over the limit",
+			data:        []byte("abcde"),
+			limit:       4,
+			expectedErr: ErrTooLarge,
+		},
+		{
+			description:  "no limit",
+			data:         []byte("abcde"),
+			limit:        0,
+			expectedData: []byte("abcde"),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			data, err := ReadAllLimit(bytes.NewReader(test.data), test.limit)
+			assert.Equal(t, test.expectedErr, err)
+			assert.Equal(t, test.expectedData, data)
+		})
+	}
+}
+
+func TestReadAllLimitStopsReading(t *testing.T) {
+	reader := bytes.NewReader(bytes.Repeat([]byte("a"), 10*maxPooledCap))
+	_, err := ReadAllLimit(reader, 1024)
+	assert.Equal(t, ErrTooLarge, err)
+	assert.Greater(t, reader.Len(), 9*maxPooledCap, "reading should stop shortly after the limit")
+}
+
+func TestReadAllDoesNotShareBuffers(t *testing.T) {
+	first, err := ReadAll(bytes.NewReader([]byte("first")))
+	assert.NoError(t, err)
+	_, err = ReadAll(bytes.NewReader([]byte("second")))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("first"), first, "data must not be overwritten by a later read")
+}
+
+func TestPutResetsBuffer(t *testing.T) {
+	buf := Get()
+	buf.WriteString("data")
+	Put(buf)
+	assert.Equal(t, 0, buf.Len())
+
+	large := bytes.NewBuffer(make([]byte, 0, maxPooledCap+1))
+	large.WriteString("data")
+	Put(large)
+	assert.Equal(t, 4, large.Len(), "oversized buffers are dropped rather than reset and pooled")
+}
+
+func BenchmarkReadAll(b *testing.B) {
+	for _, size := range []int{1 << 10, 16 << 10, 256 << 10} {
+		payload := bytes.Repeat([]byte("a"), size)
+
+		b.Run("io.ReadAll/"+strconv.Itoa(size), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				io.ReadAll(bytes.NewReader(payload))
+			}
+		})
+		b.Run("bufferpool.ReadAll/"+strconv.Itoa(size), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				ReadAll(bytes.NewReader(payload))
+			}
+		})
+	}
+}
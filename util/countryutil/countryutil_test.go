@@ -0,0 +1,16 @@
+package countryutil
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToAlpha3(t *testing.T) {
+	alpha3, ok := ToAlpha3("DE")
+	assert.True(t, ok)
+	assert.Equal(t, "DEU", alpha3)
+
+	_, ok = ToAlpha3("DEU")
+	assert.False(t, ok)
+}
@@ -0,0 +1,21 @@
+package httputil
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+)
+
+// HasBearerToken reports whether the request has one of the tokens as its bearer token. Every token is
+// compared, in constant time, so that the time taken doesn't give away how close a guess was.
+func HasBearerToken(req *http.Request, tokens [][]byte) bool {
+	presented, ok := strings.CutPrefix(req.Header.Get("Authorization"), "Bearer ")
+	if !ok || presented == "" {
+		return false
+	}
+	authorized := 0
+	for _, token := range tokens {
+		authorized |= subtle.ConstantTimeCompare([]byte(presented), token)
+	}
+	return authorized == 1
+}
@@ -0,0 +1,70 @@
+package httputil
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHasBearerToken(t *testing.T) {
+	tokens := [][]byte{[]byte("token1"), []byte("token2")}
+
+	testCases := []struct {
+		description   string
+		authorization string
+		tokens        [][]byte
+		expected      bool
+	}{
+		{
+			description:   "first-token",
+			authorization: "Bearer token1",
+			tokens:        tokens,
+			expected:      true,
+		},
+		{
+			description:   "second-token",
+			authorization: "Bearer token2",
+			tokens:        tokens,
+			expected:      true,
+		},
+		{
+			description:   "wrong-token",
+			authorization: "Bearer token3",
+			tokens:        tokens,
+		},
+		{
+			description:   "prefix-of-token",
+			authorization: "Bearer token",
+			tokens:        tokens,
+		},
+		{
+			description:   "not-bearer",
+			authorization: "Basic token1",
+			tokens:        tokens,
+		},
+		{
+			description: "missing",
+			tokens:      tokens,
+		},
+		{
+			description:   "empty-token",
+			authorization: "Bearer ",
+			tokens:        [][]byte{{}},
+		},
+		{
+			description:   "no-tokens",
+			authorization: "Bearer token1",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/", nil)
+			if test.authorization != "" {
+				req.Header.Set("Authorization", test.authorization)
+			}
+			assert.Equal(t, test.expected, HasBearerToken(req, test.tokens))
+		})
+	}
+}
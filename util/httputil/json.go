@@ -0,0 +1,12 @@
+package httputil
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// WriteJSON writes the value as the JSON body of a 200 OK response.
+func WriteJSON(w http.ResponseWriter, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(value)
+}
@@ -0,0 +1,141 @@
+package jsonutil
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/prebid/prebid-server/v2/errortypes"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCanonicalize(t *testing.T) {
+	testCases := []struct {
+		description string
+		given       string
+		expected    string
+		expectedErr bool
+	}{
+		{
+			description: "whitespace is removed and members are sorted",
+			given:       "{ \"b\": [1, 2, {\"z\": null, \"a\": true}],\n  \"a\": \"x\" }",
+			expected:    `{"a":"x","b":[1,2,{"a":true,"z":null}]}`,
+		},
+		{
+			description: "numbers from RFC 8785",
+			given:       `[333333333.33333329, 1E30, 4.50, 2e-3, 0.000000000000000000000000001, -0, 1e-7, 0.000001, 100]`,
+			expected:    `[333333333.3333333,1e+30,4.5,0.002,1e-27,0,1e-7,0.000001,100]`,
+		},
+		{
+			description: "strings keep unicode and escape only what they must",
+			given:       `{"s":"€é<>&\/\"\\\n\u0001\u001f"}`,
+			expected:    "{\"s\":\"€é<>&/\\\"\\\\\\n\\u0001\\u001f\"}",
+		},
+		{
+			description: "members are sorted by UTF-16 code units",
+			given:       "{\"\U0001F600\":1,\"ﬁ\":2}",
+			expected:    "{\"\U0001F600\":1,\"ﬁ\":2}",
+		},
+		{
+			description: "nested empty values",
+			given:       `{"a":{},"b":[]}`,
+			expected:    `{"a":{},"b":[]}`,
+		},
+		{
+			description: "number out of range",
+			given:       `{"a":1e400}`,
+			expectedErr: true,
+		},
+		{
+			description: "invalid json",
+			given:       `{"a":`,
+			expectedErr: true,
+		},
+		{
+			description: "trailing data",
+			given:       `{"a":1} {"b":2}`,
+			expectedErr: true,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			result, err := Canonicalize([]byte(test.given))
+			if test.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, string(result))
+		})
+	}
+}
+
+func TestMarshalCanonical(t *testing.T) {
+	type imp struct {
+		ID  string          `json:"id"`
+		Ext json.RawMessage `json:"ext,omitempty"`
+	}
+	type request struct {
+		ID    string            `json:"id"`
+		Imp   []imp             `json:"imp"`
+		TMax  int64             `json:"tmax,omitempty"`
+		Price float64           `json:"price"`
+		Tags  map[string]string `json:"tags,omitempty"`
+	}
+
+	testCases := []struct {
+		description string
+		given       interface{}
+		expected    string
+		expectedErr error
+	}{
+		{
+			description: "struct fields, map keys and raw messages are sorted",
+			given: request{
+				ID:    "req-1",
+				Imp:   []imp{{ID: "imp-1", Ext: json.RawMessage("{\"prebid\": {\"b\": 2, \"a\": 1}, \"data\": \"<x>\"}")}},
+				TMax:  500,
+				Price: 1.50,
+				Tags:  map[string]string{"z": "last", "a": "first"},
+			},
+			expected: `{"id":"req-1","imp":[{"ext":{"data":"<x>","prebid":{"a":1,"b":2}},"id":"imp-1"}],"price":1.5,"tags":{"a":"first","z":"last"},"tmax":500}`,
+		},
+		{
+			description: "nil",
+			given:       nil,
+			expected:    `null`,
+		},
+		{
+			description: "unsupported value",
+			given:       math.Inf(1),
+			expectedErr: &errortypes.FailedToMarshal{Message: "unsupported value: +Inf"},
+		},
+		{
+			description: "invalid raw message is null, as Marshal writes it",
+			given:       imp{ID: "imp-1", Ext: json.RawMessage(`{"a":`)},
+			expected:    `{"ext":null,"id":"imp-1"}`,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			result, err := MarshalCanonical(test.given)
+			if test.expectedErr != nil {
+				assert.Equal(t, test.expectedErr, err)
+				assert.Nil(t, result)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, string(result))
+		})
+	}
+}
+
+func TestMarshalCanonicalIsDeterministic(t *testing.T) {
+	first, err := MarshalCanonical(map[string]interface{}{"b": []int{1, 2}, "a": map[string]int{"y": 1, "x": 2}})
+	assert.NoError(t, err)
+	second, err := MarshalCanonical(map[string]interface{}{"a": map[string]int{"x": 2, "y": 1}, "b": json.RawMessage(`[ 1, 2.0 ]`)})
+	assert.NoError(t, err)
+	assert.Equal(t, string(first), string(second))
+}
@@ -0,0 +1,85 @@
+package jsonutil
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Names of the JSON engines
+const (
+	EngineJsoniter = "jsoniter"
+	EngineStdlib   = "stdlib"
+	EngineSonic    = "sonic"
+)
+
+// Engine unmarshals and marshals JSON for Unmarshal, UnmarshalValid and Marshal.
+type Engine interface {
+	// Unmarshal unmarshals without validating json.RawMessage fields
+	Unmarshal(data []byte, v interface{}) error
+	// UnmarshalValid validates the data as it unmarshals it
+	UnmarshalValid(data []byte, v interface{}) error
+	Marshal(v interface{}) ([]byte, error)
+}
+
+// engines are the engines built into this binary. sonic is only built on amd64, with the versions of Go it
+// supports.
+var engines = map[string]Engine{
+	EngineJsoniter: jsoniterEngine{},
+	EngineStdlib:   stdlibEngine{},
+}
+
+var engine Engine = jsoniterEngine{}
+
+// IsEngine returns whether the name is of one of the JSON engines, whether or not it's built into this
+// binary.
+func IsEngine(name string) bool {
+	return name == EngineJsoniter || name == EngineStdlib || name == EngineSonic
+}
+
+// SetEngine selects the engine Unmarshal, UnmarshalValid and Marshal use, which is jsoniter if the name is
+// empty. It must be called before the server starts, since it isn't safe to call while they're in use. If
+// the engine isn't built into this binary, jsoniter is used instead. It returns the name of the engine in
+// use.
+func SetEngine(name string) (string, error) {
+	if name == "" {
+		name = EngineJsoniter
+	}
+	if !IsEngine(name) {
+		return "", fmt.Errorf("unknown JSON engine %s", name)
+	}
+	selected, ok := engines[name]
+	if !ok {
+		name, selected = EngineJsoniter, engines[EngineJsoniter]
+	}
+	engine = selected
+	return name, nil
+}
+
+type jsoniterEngine struct{}
+
+func (jsoniterEngine) Unmarshal(data []byte, v interface{}) error {
+	return jsonConfigValidationOff.Unmarshal(data, v)
+}
+
+func (jsoniterEngine) UnmarshalValid(data []byte, v interface{}) error {
+	return jsonConfigValidationOn.Unmarshal(data, v)
+}
+
+func (jsoniterEngine) Marshal(v interface{}) ([]byte, error) {
+	return jsonConfigValidationOn.Marshal(v)
+}
+
+// stdlibEngine always validates, since encoding/json has no way not to.
+type stdlibEngine struct{}
+
+func (stdlibEngine) Unmarshal(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
+
+func (stdlibEngine) UnmarshalValid(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
+
+func (stdlibEngine) Marshal(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
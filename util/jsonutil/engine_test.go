@@ -0,0 +1,62 @@
+package jsonutil
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/prebid/prebid-server/v2/errortypes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetEngine(t *testing.T) {
+	defer SetEngine(EngineJsoniter)
+
+	name, err := SetEngine(EngineStdlib)
+	assert.NoError(t, err)
+	assert.Equal(t, EngineStdlib, name)
+	assert.Equal(t, stdlibEngine{}, engine)
+
+	name, err = SetEngine(EngineSonic)
+	assert.NoError(t, err)
+	if _, ok := engines[EngineSonic]; ok {
+		assert.Equal(t, EngineSonic, name)
+	} else {
+		assert.Equal(t, EngineJsoniter, name, "jsoniter should be used where sonic isn't built")
+		assert.Equal(t, jsoniterEngine{}, engine)
+	}
+
+	name, err = SetEngine("")
+	assert.NoError(t, err)
+	assert.Equal(t, EngineJsoniter, name)
+
+	_, err = SetEngine("gojay")
+	assert.EqualError(t, err, "unknown JSON engine gojay")
+}
+
+func TestEngines(t *testing.T) {
+	type request struct {
+		ID  string          `json:"id"`
+		Ext json.RawMessage `json:"ext,omitempty"`
+	}
+	defer SetEngine(EngineJsoniter)
+
+	for name := range engines {
+		t.Run(name, func(t *testing.T) {
+			_, err := SetEngine(name)
+			require.NoError(t, err)
+
+			var req request
+			assert.NoError(t, UnmarshalValid([]byte(`{"id":"req-1","ext":{"prebid":{}}}`), &req))
+			assert.Equal(t, request{ID: "req-1", Ext: json.RawMessage(`{"prebid":{}}`)}, req)
+
+			var invalid request
+			err = UnmarshalValid([]byte(`{"id":1}`), &invalid)
+			assert.IsType(t, &errortypes.FailedToUnmarshal{}, err)
+
+			data, err := Marshal(request{ID: "req-1", Ext: json.RawMessage(`{"prebid":{}}`)})
+			assert.NoError(t, err)
+			assert.Equal(t, `{"id":"req-1","ext":{"prebid":{}}}`, string(data))
+		})
+	}
+}
@@ -3,7 +3,10 @@ package jsonutil
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"io"
+	"reflect"
+	"strconv"
 	"strings"
 	"unsafe"
 
@@ -17,9 +20,115 @@ var colon = byte(':')
 var sqBracket = byte(']')
 var closingCurlyBracket = byte('}')
 
+// wildcard is an element name which stands for every member of an object
+const wildcard = "*"
+
+// elementRange is the range of an element in a json byte array
+type elementRange struct {
+	start int64
+	end   int64
+}
+
 // Finds element in json byte array with any level of nesting
+// - An element name of "*" stands for every member of the object, or element of the list, at that level, but
+// can't be the last one
+// - Elements of json lists are named by their index, such as "imp", "0", "ext"
+// - First found element is returned
 func FindElement(extension []byte, elementNames ...string) (bool, int64, int64, error) {
-	elementName := elementNames[0]
+	ranges, err := findElements(extension, false, elementNames...)
+	if err != nil {
+		return false, -1, -1, err
+	}
+	if len(ranges) == 0 {
+		return false, -1, -1, nil
+	}
+	return true, ranges[0].start, ranges[0].end, nil
+}
+
+// findElements returns the ranges of the elements found, in order, or of the first one unless all are
+// wanted. There can only be more than one if there's a wildcard in the path.
+func findElements(extension []byte, all bool, elementNames ...string) ([]elementRange, error) {
+	if elementNames[0] == wildcard {
+		if len(elementNames) == 1 {
+			return nil, errors.New("a wildcard can't be the last element name")
+		}
+		members, ok := objectMembers(extension)
+		if !ok {
+			members, _ = arrayElements(extension)
+		}
+		var ranges []elementRange
+		for _, member := range members {
+			found, err := findElements(member.value, all, elementNames[1:]...)
+			if err != nil {
+				return nil, err
+			}
+			for _, r := range found {
+				ranges = append(ranges, elementRange{start: int64(member.valueStart) + r.start, end: int64(member.valueStart) + r.end})
+			}
+			if !all && len(ranges) > 0 {
+				break
+			}
+		}
+		return ranges, nil
+	}
+
+	if index, err := strconv.ParseUint(elementNames[0], 10, 31); err == nil {
+		if elements, ok := arrayElements(extension); ok {
+			return findArrayElement(elements, int(index), all, elementNames[1:]...)
+		}
+	}
+
+	found, startIndex, endIndex, err := findFirstElement(extension, elementNames[0], len(elementNames) > 1)
+	if err != nil || !found {
+		return nil, err
+	}
+	if len(elementNames) == 1 {
+		return []elementRange{{start: startIndex, end: endIndex}}, nil
+	}
+	ranges, err := findElements(extension[startIndex:endIndex], all, elementNames[1:]...)
+	if err != nil {
+		return nil, err
+	}
+	for i := range ranges {
+		ranges[i].start += startIndex
+		ranges[i].end += startIndex
+	}
+	return ranges, nil
+}
+
+// findArrayElement returns the range of the element of a list at the index, with the comma which separates
+// it from the next element, or else the one before it, or the ranges of the elements found in it if
+// there are more element names.
+func findArrayElement(elements []rawMember, index int, all bool, elementNames ...string) ([]elementRange, error) {
+	if index >= len(elements) {
+		return nil, nil
+	}
+	element := elements[index]
+	start, end := int64(element.valueStart), int64(element.valueStart+len(element.value))
+	if len(elementNames) > 0 {
+		ranges, err := findElements(element.value, all, elementNames...)
+		if err != nil {
+			return nil, err
+		}
+		for i := range ranges {
+			ranges[i].start += start
+			ranges[i].end += start
+		}
+		return ranges, nil
+	}
+
+	if index < len(elements)-1 {
+		end = int64(elements[index+1].valueStart)
+	} else if index > 0 {
+		previous := elements[index-1]
+		start = int64(previous.valueStart + len(previous.value))
+	}
+	return []elementRange{{start: start, end: end}}, nil
+}
+
+// findFirstElement finds the first element with the name at any level of nesting, and returns its range
+// with the comma which separates it from the next element, or the range of its value if it's nested.
+func findFirstElement(extension []byte, elementName string, nested bool) (bool, int64, int64, error) {
 	buf := bytes.NewBuffer(extension)
 	dec := json.NewDecoder(buf)
 	found := false
@@ -41,19 +150,15 @@ func FindElement(extension []byte, elementNames ...string) (bool, int64, int64,
 			}
 			endIndex = dec.InputOffset()
 
-			if dec.More() {
+			// dec.More() may be true at the end of the input, as it is for nested elements, which are
+			// searched without their closing bracket, so the comma is only taken if there is one
+			if commaIndex := bytes.IndexByte(extension[endIndex:], comma); dec.More() && commaIndex != -1 {
 				//if there were other elements before
 				if extension[startIndex] == comma {
 					startIndex++
 				}
-				for {
-					//structure has more elements, need to find index of comma
-					if extension[endIndex] == comma {
-						endIndex++
-						break
-					}
-					endIndex++
-				}
+				//structure has more elements, the comma after the element is dropped with it
+				endIndex += int64(commaIndex) + 1
 			}
 			found = true
 			break
@@ -61,60 +166,122 @@ func FindElement(extension []byte, elementNames ...string) (bool, int64, int64,
 			startIndex = dec.InputOffset()
 		}
 	}
-	if found {
-		if len(elementNames) == 1 {
-			return found, startIndex, endIndex, nil
-		} else if len(elementNames) > 1 {
-			for {
-				//find the beginning of nested element
-				if extension[startIndex] == colon {
-					startIndex++
-					break
-				}
-				startIndex++
-			}
-			for {
-				if endIndex == int64(len(extension)) {
-					endIndex--
-				}
+	if !found || !nested {
+		return found, startIndex, endIndex, nil
+	}
 
-				//if structure had more elements, need to find index of comma at the end
-				if extension[endIndex] == sqBracket || extension[endIndex] == closingCurlyBracket {
-					break
-				}
+	for {
+		//find the beginning of nested element
+		if extension[startIndex] == colon {
+			startIndex++
+			break
+		}
+		startIndex++
+	}
+	for {
+		if endIndex == int64(len(extension)) {
+			endIndex--
+		}
 
-				if extension[endIndex] == comma {
-					endIndex--
-					break
-				} else {
-					endIndex--
-				}
-			}
-			if found {
-				found, startInd, endInd, err := FindElement(extension[startIndex:endIndex], elementNames[1:]...)
-				return found, startIndex + startInd, startIndex + endInd, err
-			}
-			return found, startIndex, startIndex, nil
+		//if structure had more elements, need to find index of comma at the end
+		if extension[endIndex] == sqBracket || extension[endIndex] == closingCurlyBracket {
+			break
+		}
+
+		if extension[endIndex] == comma {
+			endIndex--
+			break
+		} else {
+			endIndex--
 		}
 	}
 	return found, startIndex, endIndex, nil
 }
 
 // Drops element from json byte array
-// - Doesn't support drop element from json list
+// - Elements of json lists are named by their index, such as "imp", "0", "ext", and can be dropped too
 // - Keys in the path can skip levels
 // - First found element will be removed
+// - An element name of "*" stands for every member of the object, or element of the list, at that level, and
+// every element found through it is removed, so that prebid.bidder.*.params.secret drops the secret of
+// every bidder
 func DropElement(extension []byte, elementNames ...string) ([]byte, error) {
-	found, startIndex, endIndex, err := FindElement(extension, elementNames...)
+	ranges, err := findElements(extension, true, elementNames...)
 	if err != nil {
 		return nil, err
 	}
-	if found {
-		extension = append(extension[:startIndex], extension[endIndex:]...)
+	// the ranges are in order, so the last is removed first to keep the others in place
+	for i := len(ranges) - 1; i >= 0; i-- {
+		extension = append(extension[:ranges[i].start], extension[ranges[i].end:]...)
 	}
 	return extension, nil
 }
 
+// Replaces the value of element in json byte array with newValue, which must be valid json
+// - Keys in the path can skip levels, as with DropElement
+// - First found element will be replaced
+// - The rest of the json, commas included, is left as it is
+// - The json byte array isn't modified; a new one is returned
+func ReplaceElement(extension []byte, newValue []byte, elementNames ...string) ([]byte, error) {
+	if !json.Valid(newValue) {
+		return nil, errors.New("new value is not valid json")
+	}
+	found, startIndex, endIndex, err := FindElement(extension, elementNames...)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return extension, nil
+	}
+	valueStart, valueEnd, err := elementValue(extension, startIndex, endIndex)
+	if err != nil {
+		return nil, err
+	}
+
+	replaced := make([]byte, 0, len(extension)-(valueEnd-valueStart)+len(newValue))
+	replaced = append(replaced, extension[:valueStart]...)
+	replaced = append(replaced, newValue...)
+	replaced = append(replaced, extension[valueEnd:]...)
+	return replaced, nil
+}
+
+// ExtractElement finds element in json byte array, as FindElement does, and unmarshals just its value into
+// a T. It returns false if the element isn't found.
+func ExtractElement[T any](extension []byte, elementNames ...string) (T, bool, error) {
+	var value T
+	found, startIndex, endIndex, err := FindElement(extension, elementNames...)
+	if err != nil || !found {
+		return value, false, err
+	}
+	valueStart, valueEnd, err := elementValue(extension, startIndex, endIndex)
+	if err != nil {
+		return value, false, err
+	}
+	if err := Unmarshal(extension[valueStart:valueEnd], &value); err != nil {
+		return value, false, err
+	}
+	return value, true, nil
+}
+
+// elementValue returns the range of the value of the element FindElement found in the range, which starts
+// with the element's name, after a comma if there's one before it.
+func elementValue(extension []byte, startIndex, endIndex int64) (int, int, error) {
+	member := extension[startIndex:endIndex]
+	i := skipSpace(member, 0)
+	if i < len(member) && member[i] == comma {
+		i = skipSpace(member, i+1)
+	}
+	if i >= len(member) || member[i] != '"' {
+		return -1, -1, errors.New("element not found at the expected offset")
+	}
+	i = skipSpace(member, skipString(member, i))
+	if i >= len(member) || member[i] != colon {
+		return -1, -1, errors.New("element not found at the expected offset")
+	}
+	valueStart := int(startIndex) + skipSpace(member, i+1)
+	return valueStart, skipValue(extension, valueStart), nil
+}
+
 // jsonConfigValidationOn attempts to maintain compatibility with the standard library which
 // includes enabling validation
 var jsonConfigValidationOn = jsoniter.ConfigCompatibleWithStandardLibrary
@@ -129,10 +296,11 @@ var jsonConfigValidationOff = jsoniter.Config{
 // Unmarshal unmarshals a byte slice into the specified data structure without performing
 // any validation on the data. An unmarshal error is returned if a non-validation error occurs.
 func Unmarshal(data []byte, v interface{}) error {
-	err := jsonConfigValidationOff.Unmarshal(data, v)
+	err := engine.Unmarshal(data, v)
 	if err != nil {
 		return &errortypes.FailedToUnmarshal{
-			Message: tryExtractErrorMessage(err),
+			Message:  tryExtractErrorMessage(err),
+			Position: tryExtractErrorPosition(data, v),
 		}
 	}
 	return nil
@@ -141,9 +309,10 @@ func Unmarshal(data []byte, v interface{}) error {
 // UnmarshalValid validates and unmarshals a byte slice into the specified data structure
 // returning an error if validation fails
 func UnmarshalValid(data []byte, v interface{}) error {
-	if err := jsonConfigValidationOn.Unmarshal(data, v); err != nil {
+	if err := engine.UnmarshalValid(data, v); err != nil {
 		return &errortypes.FailedToUnmarshal{
-			Message: tryExtractErrorMessage(err),
+			Message:  tryExtractErrorMessage(err),
+			Position: tryExtractErrorPosition(data, v),
 		}
 	}
 	return nil
@@ -152,7 +321,7 @@ func UnmarshalValid(data []byte, v interface{}) error {
 // Marshal marshals a data structure into a byte slice without performing any validation
 // on the data. A marshal error is returned if a non-validation error occurs.
 func Marshal(v interface{}) ([]byte, error) {
-	data, err := jsonConfigValidationOn.Marshal(v)
+	data, err := engine.Marshal(v)
 	if err != nil {
 		return nil, &errortypes.FailedToMarshal{
 			Message: err.Error(),
@@ -208,6 +377,123 @@ func tryExtractErrorMessage(err error) string {
 	return msg[msgStartIndex+2 : msgEndIndex]
 }
 
+// tryExtractErrorPosition attempts to find where in the data the error unmarshaling it into v was found. The
+// errors returned from the json-iter package only show the bytes around the error, so the data is unmarshaled
+// again with the standard library, whose errors have the offset of the error, and the type expected where the
+// value had another type. Returns nil if the standard library doesn't find the error.
+func tryExtractErrorPosition(data []byte, v interface{}) *errortypes.JSONPosition {
+	typ := reflect.TypeOf(v)
+	if typ == nil || typ.Kind() != reflect.Pointer {
+		return nil
+	}
+
+	var position errortypes.JSONPosition
+	switch err := json.Unmarshal(data, reflect.New(typ.Elem()).Interface()).(type) {
+	case *json.SyntaxError:
+		position.Offset = err.Offset
+	case *json.UnmarshalTypeError:
+		position.Offset = err.Offset
+		position.Expected = jsonTypeOf(err.Type)
+	default:
+		return nil
+	}
+	position.Path = pathAt(data, position.Offset)
+	return &position
+}
+
+// jsonTypeOf returns the json type a value of the Go type is unmarshaled from.
+func jsonTypeOf(typ reflect.Type) string {
+	for typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+	switch typ.Kind() {
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Struct, reflect.Map:
+		return "object"
+	case reflect.Slice, reflect.Array:
+		return "array"
+	}
+	return ""
+}
+
+// pathFrame is an object or array the path passes through.
+type pathFrame struct {
+	array bool
+	// index is the index of the array element being read
+	index int
+	// key is the name of the object member being read
+	key       string
+	expectKey bool
+}
+
+// pathAt returns the path, such as imp[0].banner.format[1].w, of the last value or object member name which
+// starts before the offset of the json data. The path of the root value is empty.
+func pathAt(data []byte, offset int64) string {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	var frames []pathFrame
+	path := ""
+
+	endValue := func() {
+		if len(frames) == 0 {
+			return
+		}
+		if frame := &frames[len(frames)-1]; frame.array {
+			frame.index++
+		} else {
+			frame.expectKey = true
+		}
+	}
+
+	for {
+		start := dec.InputOffset()
+		token, err := dec.Token()
+		if err != nil || start >= offset {
+			return path
+		}
+
+		switch token {
+		case json.Delim('{'), json.Delim('['):
+			path = buildPath(frames)
+			frames = append(frames, pathFrame{array: token == json.Delim('['), expectKey: true})
+		case json.Delim('}'), json.Delim(']'):
+			frames = frames[:len(frames)-1]
+			path = buildPath(frames)
+			endValue()
+		default:
+			if name, ok := token.(string); ok && len(frames) > 0 && !frames[len(frames)-1].array && frames[len(frames)-1].expectKey {
+				frames[len(frames)-1].key = name
+				frames[len(frames)-1].expectKey = false
+				path = buildPath(frames)
+				continue
+			}
+			path = buildPath(frames)
+			endValue()
+		}
+	}
+}
+
+func buildPath(frames []pathFrame) string {
+	var path strings.Builder
+	for _, frame := range frames {
+		if frame.array {
+			path.WriteString("[" + strconv.Itoa(frame.index) + "]")
+			continue
+		}
+		if path.Len() > 0 {
+			path.WriteByte('.')
+		}
+		path.WriteString(frame.key)
+	}
+	return path.String()
+}
+
 // isLikelyDetailedErrorMessage checks if the json unmarshal error contains enough information such
 // that the caller clearly understands the context, where the structure name is not needed.
 func isLikelyDetailedErrorMessage(msg string) bool {
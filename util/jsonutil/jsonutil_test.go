@@ -10,6 +10,8 @@ import (
 
 	jsoniter "github.com/json-iterator/go"
 	"github.com/modern-go/reflect2"
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/prebid-server/v2/errortypes"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -158,7 +160,111 @@ func TestDropElement(t *testing.T) {
 			errorExpected:   false,
 			errorContains:   "",
 		},
+		{
+			description:     "Drop Element Of Every Bidder",
+			input:           []byte(`{"prebid":{"bidder":{"appnexus":{"params":{"placementId":1,"secret":"a"}},"rubicon":{"params":{"secret":"b","zoneId":2}},"openx":{"params":{"unit":3}}}}}`),
+			elementToRemove: []string{"prebid", "bidder", "*", "params", "secret"},
+			output:          []byte(`{"prebid":{"bidder":{"appnexus":{"params":{"placementId":1}},"rubicon":{"params":{"zoneId":2}},"openx":{"params":{"unit":3}}}}}`),
+			errorExpected:   false,
+			errorContains:   "",
+		},
+		{
+			description:     "Drop Last Element Of Every Bidder",
+			input:           []byte(`{"bidder": {"appnexus": {"placementId": 1, "secret": "a"}, "rubicon": {"secret": "b"}}, "test": 1}`),
+			elementToRemove: []string{"bidder", "*", "secret"},
+			output:          []byte(`{"bidder": {"appnexus": {"placementId": 1}, "rubicon": {}}, "test": 1}`),
+			errorExpected:   false,
+			errorContains:   "",
+		},
+		{
+			description:     "Drop Element Of Every Member At Top Level",
+			input:           []byte(`{"appnexus":{"secret":"a","test":1},"rubicon":{"secret":"b"}}`),
+			elementToRemove: []string{"*", "secret"},
+			output:          []byte(`{"appnexus":{"test":1},"rubicon":{}}`),
+			errorExpected:   false,
+			errorContains:   "",
+		},
+		{
+			description:     "Drop Element Of Every Member Not Found",
+			input:           []byte(`{"bidder":{"appnexus":{"placementId":1},"rubicon":2}}`),
+			elementToRemove: []string{"bidder", "*", "secret"},
+			output:          []byte(`{"bidder":{"appnexus":{"placementId":1},"rubicon":2}}`),
+			errorExpected:   false,
+			errorContains:   "",
+		},
+		{
+			description:     "Drop First List Element",
+			input:           []byte(`{"imp": [{"id": "1"}, {"id": "2"}, {"id": "3"}]}`),
+			elementToRemove: []string{"imp", "0"},
+			output:          []byte(`{"imp": [{"id": "2"}, {"id": "3"}]}`),
+			errorExpected:   false,
+			errorContains:   "",
+		},
+		{
+			description:     "Drop Middle List Element",
+			input:           []byte(`{"imp":[{"id":"1"},{"id":"2"},{"id":"3"}],"test":1}`),
+			elementToRemove: []string{"imp", "1"},
+			output:          []byte(`{"imp":[{"id":"1"},{"id":"3"}],"test":1}`),
+			errorExpected:   false,
+			errorContains:   "",
+		},
+		{
+			description:     "Drop Last List Element",
+			input:           []byte(`{"imp":[{"id":"1"},{"id":"2"}],"test":1}`),
+			elementToRemove: []string{"imp", "1"},
+			output:          []byte(`{"imp":[{"id":"1"}],"test":1}`),
+			errorExpected:   false,
+			errorContains:   "",
+		},
+		{
+			description:     "Drop Only List Element",
+			input:           []byte(`{"test":1,"imp":[{"id":"1"}]}`),
+			elementToRemove: []string{"imp", "0"},
+			output:          []byte(`{"test":1,"imp":[]}`),
+			errorExpected:   false,
+			errorContains:   "",
+		},
+		{
+			description:     "Drop Element Of List Element",
+			input:           []byte(`{"imp":[{"id":"1","ext":{"prebid":{"storedauctionresponse":{"id":"a"}}}},{"id":"2","ext":{}}]}`),
+			elementToRemove: []string{"imp", "0", "ext"},
+			output:          []byte(`{"imp":[{"id":"1"},{"id":"2","ext":{}}]}`),
+			errorExpected:   false,
+			errorContains:   "",
+		},
+		{
+			description:     "Drop Element Of Every List Element",
+			input:           []byte(`{"imp":[{"id":"1","ext":{"prebid":{"storedauctionresponse":{"id":"a"},"bidder":{}}}},{"id":"2"},{"id":"3","ext":{"prebid":{"storedauctionresponse":{"id":"b"}}}}]}`),
+			elementToRemove: []string{"imp", "*", "storedauctionresponse"},
+			output:          []byte(`{"imp":[{"id":"1","ext":{"prebid":{"bidder":{}}}},{"id":"2"},{"id":"3","ext":{"prebid":{}}}]}`),
+			errorExpected:   false,
+			errorContains:   "",
+		},
+		{
+			description:     "Drop List Element Out Of Range",
+			input:           []byte(`{"imp":[{"id":"1"}]}`),
+			elementToRemove: []string{"imp", "1"},
+			output:          []byte(`{"imp":[{"id":"1"}]}`),
+			errorExpected:   false,
+			errorContains:   "",
+		},
+		{
+			description:     "Drop Numeric Object Key",
+			input:           []byte(`{"sizes":{"0":[300,250],"1":[728,90]}}`),
+			elementToRemove: []string{"sizes", "0"},
+			output:          []byte(`{"sizes":{"1":[728,90]}}`),
+			errorExpected:   false,
+			errorContains:   "",
+		},
 		//Errors
+		{
+			description:     "Error Wildcard Last",
+			input:           []byte(`{"bidder":{"appnexus":{"placementId":1}}}`),
+			elementToRemove: []string{"bidder", "*"},
+			output:          []byte(``),
+			errorExpected:   true,
+			errorContains:   "a wildcard can't be the last element name",
+		},
 		{
 			description:     "Error Decode",
 			input:           []byte(`{"consented_providers_settings": {"consented_providers": ["123",1,,1365,5678,1545,2563,1411], "test": 1}}`),
@@ -190,6 +296,154 @@ func TestDropElement(t *testing.T) {
 	}
 }
 
+func TestFindElementWildcard(t *testing.T) {
+	input := []byte(`{"bidder":{"appnexus":{"placementId":1},"rubicon":{"secret":"b"},"openx":{"secret":"c"}}}`)
+
+	found, startIndex, endIndex, err := FindElement(input, "bidder", "*", "secret")
+
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, `"secret":"b"`, string(input[startIndex:endIndex]), "the first element found should be returned")
+
+	found, _, _, err = FindElement(input, "bidder", "*", "zoneId")
+	assert.NoError(t, err)
+	assert.False(t, found)
+}
+
+func TestReplaceElement(t *testing.T) {
+	tests := []struct {
+		description   string
+		input         []byte
+		newValue      []byte
+		elementPath   []string
+		output        []byte
+		errorExpected bool
+		errorContains string
+	}{
+		{
+			description: "Replace First Element",
+			input:       []byte(`{"consent": "TESTCONSENT","consented_providers_settings": {"test": 1}}`),
+			newValue:    []byte(`"NEWCONSENT"`),
+			elementPath: []string{"consent"},
+			output:      []byte(`{"consent": "NEWCONSENT","consented_providers_settings": {"test": 1}}`),
+		},
+		{
+			description: "Replace Last Element",
+			input:       []byte(`{"consent": "TESTCONSENT","consented_providers_settings": {"test": 1}}`),
+			newValue:    []byte(`{"test": 2}`),
+			elementPath: []string{"consented_providers_settings"},
+			output:      []byte(`{"consent": "TESTCONSENT","consented_providers_settings": {"test": 2}}`),
+		},
+		{
+			description: "Replace Nested Element Before Another Element",
+			input:       []byte(`{"consent": "TESTCONSENT","consented_providers_settings": {"consented_providers": [1608,765,492],"test": 1}}`),
+			newValue:    []byte(`[1]`),
+			elementPath: []string{"consented_providers_settings", "consented_providers"},
+			output:      []byte(`{"consent": "TESTCONSENT","consented_providers_settings": {"consented_providers": [1],"test": 1}}`),
+		},
+		{
+			description: "Replace Nested Element After Another Element",
+			input:       []byte(`{"consent": "TESTCONSENT","consented_providers_settings": {"test": 1,"consented_providers": [1608,765,492]}}`),
+			newValue:    []byte(`null`),
+			elementPath: []string{"consented_providers"},
+			output:      []byte(`{"consent": "TESTCONSENT","consented_providers_settings": {"test": 1,"consented_providers": null}}`),
+		},
+		{
+			description: "Replace Element In Imp Ext",
+			input:       []byte(`{"prebid":{"bidder":{"appnexus":{"placementId":123,"keywords":"a=b"}}},"data":{"pbadslot":"/1/slot"}}`),
+			newValue:    []byte(`{"placementId":456}`),
+			elementPath: []string{"prebid", "bidder", "appnexus"},
+			output:      []byte(`{"prebid":{"bidder":{"appnexus":{"placementId":456}}},"data":{"pbadslot":"/1/slot"}}`),
+		},
+		{
+			description: "Element Not Found",
+			input:       []byte(`{"consent": "TESTCONSENT"}`),
+			newValue:    []byte(`1`),
+			elementPath: []string{"test"},
+			output:      []byte(`{"consent": "TESTCONSENT"}`),
+		},
+		{
+			description:   "Invalid New Value",
+			input:         []byte(`{"consent": "TESTCONSENT"}`),
+			newValue:      []byte(`{"test":`),
+			elementPath:   []string{"consent"},
+			errorExpected: true,
+			errorContains: "new value is not valid json",
+		},
+		{
+			description:   "Invalid Json",
+			input:         []byte(`{"consent": "TESTCONSENT" "test": 1}`),
+			newValue:      []byte(`1`),
+			elementPath:   []string{"test"},
+			errorExpected: true,
+			errorContains: "invalid character",
+		},
+	}
+	for _, tt := range tests {
+		input := append([]byte(nil), tt.input...)
+		res, err := ReplaceElement(tt.input, tt.newValue, tt.elementPath...)
+
+		if tt.errorExpected {
+			assert.Error(t, err, "Error should not be nil: %s", tt.description)
+			assert.True(t, strings.Contains(err.Error(), tt.errorContains), tt.description)
+		} else {
+			assert.NoError(t, err, "Error should be nil: %s", tt.description)
+			assert.Equal(t, string(tt.output), string(res), "Result is incorrect: %s", tt.description)
+		}
+		assert.Equal(t, input, tt.input, "Input shouldn't be modified: %s", tt.description)
+	}
+}
+
+func TestExtractElement(t *testing.T) {
+	input := []byte(`{"prebid": {"bidder": {"appnexus": {"placementId": 123, "keywords": ["a", "b"]}}, "debug": true}, "gpid": "/1/slot"}`)
+
+	type appnexusParams struct {
+		PlacementID int      `json:"placementId"`
+		Keywords    []string `json:"keywords"`
+	}
+	params, found, err := ExtractElement[appnexusParams](input, "prebid", "bidder", "appnexus")
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, appnexusParams{PlacementID: 123, Keywords: []string{"a", "b"}}, params)
+
+	debug, found, err := ExtractElement[bool](input, "prebid", "debug")
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.True(t, debug)
+
+	gpid, found, err := ExtractElement[string](input, "gpid")
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, "/1/slot", gpid)
+
+	raw, found, err := ExtractElement[json.RawMessage](input, "prebid", "bidder")
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.JSONEq(t, `{"appnexus": {"placementId": 123, "keywords": ["a", "b"]}}`, string(raw))
+
+	placementID, found, err := ExtractElement[int](input, "*", "*", "placementId")
+	assert.NoError(t, err, "wildcards should be supported as in FindElement")
+	assert.True(t, found)
+	assert.Equal(t, 123, placementID)
+
+	id, found, err := ExtractElement[string]([]byte(`{"imp":[{"id":"1"},{"id":"2"}]}`), "imp", "1", "id")
+	assert.NoError(t, err, "list indexes should be supported as in FindElement")
+	assert.True(t, found)
+	assert.Equal(t, "2", id)
+
+	_, found, err = ExtractElement[string](input, "prebid", "storedrequest")
+	assert.NoError(t, err)
+	assert.False(t, found)
+
+	_, found, err = ExtractElement[int](input, "gpid")
+	assert.Error(t, err, "the value should be unmarshaled into the type")
+	assert.False(t, found)
+
+	_, found, err = ExtractElement[string]([]byte(`{"gpid": "/1/slot" "test": 1}`), "test")
+	assert.Error(t, err)
+	assert.False(t, found)
+}
+
 func TestTryExtractErrorMessage(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -246,6 +500,67 @@ func TestTryExtractErrorMessage(t *testing.T) {
 	}
 }
 
+func TestUnmarshalErrorPosition(t *testing.T) {
+	tests := []struct {
+		name             string
+		givenJSON        string
+		expectedPosition *errortypes.JSONPosition
+	}{
+		{
+			name:             "wrong-type-in-array",
+			givenJSON:        `{"id":"1","imp":[{"id":"a"},{"id":"b","banner":{"format":[{"w":1},{"w":"300"}]}}]}`,
+			expectedPosition: &errortypes.JSONPosition{Path: "imp[1].banner.format[1].w", Offset: 76, Expected: "number"},
+		},
+		{
+			name:             "object-instead-of-array",
+			givenJSON:        `{"id":"1","imp":[{"id":"a","banner":{"format":{"w":1}}}]}`,
+			expectedPosition: &errortypes.JSONPosition{Path: "imp[0].banner.format", Offset: 47, Expected: "array"},
+		},
+		{
+			name:             "string-instead-of-object",
+			givenJSON:        `{"id":"1","imp":[{"id":"a","banner":"x"}]}`,
+			expectedPosition: &errortypes.JSONPosition{Path: "imp[0].banner", Offset: 39, Expected: "object"},
+		},
+		{
+			name:             "syntax",
+			givenJSON:        `{"id":"1","imp":[{"id":"a", "banner": x}]}`,
+			expectedPosition: &errortypes.JSONPosition{Path: "imp[0].banner", Offset: 39},
+		},
+		{
+			name:             "truncated",
+			givenJSON:        `{"id":"1","imp":[{"id":"a"}`,
+			expectedPosition: &errortypes.JSONPosition{Path: "imp[0]", Offset: 27},
+		},
+		{
+			name:             "root",
+			givenJSON:        `[]`,
+			expectedPosition: &errortypes.JSONPosition{Path: "", Offset: 1, Expected: "object"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var request openrtb2.BidRequest
+			err := UnmarshalValid([]byte(test.givenJSON), &request)
+			if assert.IsType(t, &errortypes.FailedToUnmarshal{}, err) {
+				assert.Equal(t, test.expectedPosition, err.(*errortypes.FailedToUnmarshal).Position)
+			}
+
+			err = Unmarshal([]byte(test.givenJSON), &request)
+			if assert.IsType(t, &errortypes.FailedToUnmarshal{}, err) {
+				assert.Equal(t, test.expectedPosition, err.(*errortypes.FailedToUnmarshal).Position)
+			}
+		})
+	}
+}
+
+func TestTryExtractErrorPositionUnknown(t *testing.T) {
+	var request openrtb2.BidRequest
+	assert.Nil(t, tryExtractErrorPosition([]byte(`{"id":"1"}`), &request), "the standard library finds no error")
+	assert.Nil(t, tryExtractErrorPosition([]byte(`{"id":1}`), request), "not a pointer")
+	assert.Nil(t, tryExtractErrorPosition([]byte(`{"id":1}`), nil))
+}
+
 func TestCreateEncoder(t *testing.T) {
 	testCases := []struct {
 		desc               string
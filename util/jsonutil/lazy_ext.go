@@ -0,0 +1,188 @@
+package jsonutil
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+)
+
+var errLazyExtNotObject = errors.New("ext isn't a JSON object")
+
+// LazyExt holds the JSON of an ext object and only parses as much of it as is used. The offsets of its
+// members are found on the first access, without unmarshaling their values, and a value is only unmarshaled
+// when it's asked for. Until a member is set or deleted, Bytes returns the JSON it was made with. After that,
+// it splices the changed members into the JSON, copying the others as they are, instead of marshaling the
+// whole object again.
+type LazyExt struct {
+	raw     json.RawMessage
+	scanned bool
+	err     error
+	members []rawMember
+	// index is the position in members of the last member with each name, which is the one that counts
+	index map[string]int
+	// changes are the values members have been set to, nil if they've been deleted
+	changes map[string]json.RawMessage
+	// added are the names of the members set which weren't in raw, in the order they were set
+	added []string
+}
+
+// NewLazyExt returns a LazyExt of the JSON, which is empty if there's no ext.
+func NewLazyExt(raw json.RawMessage) *LazyExt {
+	return &LazyExt{raw: raw}
+}
+
+func (e *LazyExt) scan() error {
+	if e.scanned {
+		return e.err
+	}
+	e.scanned = true
+	if len(bytes.TrimSpace(e.raw)) == 0 {
+		return nil
+	}
+	if !json.Valid(e.raw) {
+		e.err = errLazyExtNotObject
+		return e.err
+	}
+	members, ok := objectMembers(e.raw)
+	if !ok {
+		e.err = errLazyExtNotObject
+		return e.err
+	}
+	e.members = members
+	e.index = make(map[string]int, len(members))
+	for i, member := range members {
+		e.index[memberKey(member.name)] = i
+	}
+	return nil
+}
+
+// Get returns the JSON of the member with the name, and whether it's there.
+func (e *LazyExt) Get(name string) (json.RawMessage, bool, error) {
+	if err := e.scan(); err != nil {
+		return nil, false, err
+	}
+	if value, changed := e.changes[name]; changed {
+		return value, value != nil, nil
+	}
+	i, ok := e.index[name]
+	if !ok {
+		return nil, false, nil
+	}
+	return json.RawMessage(e.members[i].value), true, nil
+}
+
+// Unmarshal unmarshals the member with the name into v, and returns whether it's there. v is left as it is
+// if the member isn't there.
+func (e *LazyExt) Unmarshal(name string, v interface{}) (bool, error) {
+	value, ok, err := e.Get(name)
+	if err != nil || !ok {
+		return false, err
+	}
+	return true, Unmarshal(value, v)
+}
+
+// Set sets the JSON of the member with the name, adding it after the others if it isn't there.
+func (e *LazyExt) Set(name string, value json.RawMessage) error {
+	if err := e.scan(); err != nil {
+		return err
+	}
+	if value == nil {
+		value = json.RawMessage("null")
+	}
+	e.change(name, value)
+	return nil
+}
+
+// Marshal sets the member with the name to v, marshaled.
+func (e *LazyExt) Marshal(name string, v interface{}) error {
+	value, err := Marshal(v)
+	if err != nil {
+		return err
+	}
+	return e.Set(name, value)
+}
+
+// Delete removes the member with the name, if it's there.
+func (e *LazyExt) Delete(name string) error {
+	if err := e.scan(); err != nil {
+		return err
+	}
+	if _, ok, _ := e.Get(name); ok {
+		e.change(name, nil)
+	}
+	return nil
+}
+
+func (e *LazyExt) change(name string, value json.RawMessage) {
+	if e.changes == nil {
+		e.changes = make(map[string]json.RawMessage)
+	}
+	_, inRaw := e.index[name]
+	_, changed := e.changes[name]
+	if !inRaw && !changed {
+		e.added = append(e.added, name)
+	}
+	e.changes[name] = value
+}
+
+// Dirty returns whether members have been set or deleted since the LazyExt was made.
+func (e *LazyExt) Dirty() bool {
+	return len(e.changes) != 0
+}
+
+// Bytes returns the JSON of the ext, which is nil if it has no members left after being changed.
+func (e *LazyExt) Bytes() (json.RawMessage, error) {
+	if !e.Dirty() {
+		return e.raw, nil
+	}
+	if err := e.scan(); err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	buf.Grow(len(e.raw))
+	buf.WriteByte('{')
+	empty := true
+	writeMember := func(name, value []byte) {
+		if !empty {
+			buf.WriteByte(',')
+		}
+		empty = false
+		buf.Write(name)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+
+	written := make(map[string]bool, len(e.changes))
+	for _, member := range e.members {
+		key := memberKey(member.name)
+		value, changed := e.changes[key]
+		if !changed {
+			writeMember(member.name, member.value)
+			continue
+		}
+		// a changed member replaces every member with its name, where the first of them was
+		if value == nil || written[key] {
+			continue
+		}
+		written[key] = true
+		writeMember(member.name, value)
+	}
+	for _, name := range e.added {
+		value := e.changes[name]
+		if value == nil {
+			continue
+		}
+		quoted, err := json.Marshal(name)
+		if err != nil {
+			return nil, err
+		}
+		writeMember(quoted, value)
+	}
+
+	if empty {
+		return nil, nil
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
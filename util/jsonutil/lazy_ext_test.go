@@ -0,0 +1,169 @@
+package jsonutil
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLazyExtGet(t *testing.T) {
+	ext := NewLazyExt(json.RawMessage(`{ "prebid": {"debug": true}, "a\"b": [1, 2], "gpid": "x", "gpid": "y" }`))
+
+	value, ok, err := ext.Get("prebid")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, `{"debug": true}`, string(value))
+
+	value, ok, err = ext.Get(`a"b`)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, `[1, 2]`, string(value))
+
+	value, ok, err = ext.Get("gpid")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, `"y"`, string(value), "the last member with a name counts")
+
+	_, ok, err = ext.Get("data")
+	assert.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestLazyExtUnmarshal(t *testing.T) {
+	ext := NewLazyExt(json.RawMessage(`{"prebid":{"debug":true}}`))
+
+	var prebid struct {
+		Debug bool `json:"debug"`
+	}
+	ok, err := ext.Unmarshal("prebid", &prebid)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.True(t, prebid.Debug)
+
+	var data map[string]string
+	ok, err = ext.Unmarshal("data", &data)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+	assert.Nil(t, data)
+}
+
+func TestLazyExtBytes(t *testing.T) {
+	testCases := []struct {
+		description string
+		raw         string
+		change      func(ext *LazyExt) error
+		expected    string
+	}{
+		{
+			description: "unchanged is returned as it is",
+			raw:         `{ "b": 1,  "a": {"x" : 2} }`,
+			change:      func(ext *LazyExt) error { return nil },
+			expected:    `{ "b": 1,  "a": {"x" : 2} }`,
+		},
+		{
+			description: "set member keeps its place",
+			raw:         `{"b": 1, "a": {"x" : 2}, "c": 3}`,
+			change:      func(ext *LazyExt) error { return ext.Set("a", json.RawMessage(`{"y":3}`)) },
+			expected:    `{"b":1,"a":{"y":3},"c":3}`,
+		},
+		{
+			description: "added member comes last",
+			raw:         `{"b":1}`,
+			change: func(ext *LazyExt) error {
+				if err := ext.Set("z", json.RawMessage(`true`)); err != nil {
+					return err
+				}
+				return ext.Marshal("a\"", "v")
+			},
+			expected: `{"b":1,"z":true,"a\"":"v"}`,
+		},
+		{
+			description: "deleted member",
+			raw:         `{"b":1,"a":2,"c":3}`,
+			change:      func(ext *LazyExt) error { return ext.Delete("a") },
+			expected:    `{"b":1,"c":3}`,
+		},
+		{
+			description: "deleted added member",
+			raw:         `{"b":1}`,
+			change: func(ext *LazyExt) error {
+				if err := ext.Set("a", json.RawMessage(`2`)); err != nil {
+					return err
+				}
+				return ext.Delete("a")
+			},
+			expected: `{"b":1}`,
+		},
+		{
+			description: "set duplicate member",
+			raw:         `{"a":1,"b":2,"a":3}`,
+			change:      func(ext *LazyExt) error { return ext.Set("a", json.RawMessage(`4`)) },
+			expected:    `{"a":4,"b":2}`,
+		},
+		{
+			description: "escaped name",
+			raw:         `{"\u0061":1}`,
+			change:      func(ext *LazyExt) error { return ext.Set("a", json.RawMessage(`2`)) },
+			expected:    `{"\u0061":2}`,
+		},
+		{
+			description: "empty ext",
+			raw:         ``,
+			change:      func(ext *LazyExt) error { return ext.Set("a", json.RawMessage(`1`)) },
+			expected:    `{"a":1}`,
+		},
+		{
+			description: "all members deleted",
+			raw:         `{"a":1}`,
+			change:      func(ext *LazyExt) error { return ext.Delete("a") },
+			expected:    ``,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			ext := NewLazyExt(json.RawMessage(test.raw))
+			require.NoError(t, test.change(ext))
+
+			data, err := ext.Bytes()
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, string(data))
+		})
+	}
+}
+
+func TestLazyExtInvalid(t *testing.T) {
+	for _, raw := range []string{`[1]`, `{"a":`, `"a"`} {
+		ext := NewLazyExt(json.RawMessage(raw))
+
+		_, _, err := ext.Get("a")
+		assert.EqualError(t, err, "ext isn't a JSON object", raw)
+		assert.EqualError(t, ext.Set("a", json.RawMessage(`1`)), "ext isn't a JSON object", raw)
+	}
+}
+
+var benchmarkExt = json.RawMessage(`{"prebid":{"bidder":{"appnexus":{"placementId":12883451},"rubicon":{"accountId":1001,"siteId":113932,"zoneId":535510}},"storedrequest":{"id":"stored-imp-1"},"is_rewarded_inventory":1},"data":{"pbadslot":"/1111/homepage/top","adserver":{"name":"gam","adslot":"/1111/homepage"}},"gpid":"/1111/homepage/top#div-1","tid":"d5e4d9c4-7c6b-4a37-b4e3-0e8fcc3d5d52","skadn":{"version":"2.0","sourceapp":"880047117","skadnetids":["cstr6suwn9.skadnetwork","2fnua5tdw4.skadnetwork"]}}`)
+
+func BenchmarkLazyExt(b *testing.B) {
+	tid := json.RawMessage(`"9c2f4b3e-1a4d-4c1e-9a9f-3b5f6c7d8e9f"`)
+
+	b.Run("map", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			var ext map[string]json.RawMessage
+			Unmarshal(benchmarkExt, &ext)
+			ext["tid"] = tid
+			Marshal(ext)
+		}
+	})
+
+	b.Run("lazy", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			ext := NewLazyExt(benchmarkExt)
+			ext.Set("tid", tid)
+			ext.Bytes()
+		}
+	})
+}
@@ -0,0 +1,224 @@
+package jsonutil
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+)
+
+// the errors have the messages of gopkg.in/evanphx/json-patch, which requests used to be merged with, since
+// they're returned to the client
+var errBadMergeDoc = errors.New("Invalid JSON Document")
+var errBadMergePatch = errors.New("Invalid JSON Patch")
+
+// MergeClone returns the result of applying patch to dst as an RFC 7386 JSON Merge Patch. Neither is
+// modified. Unlike a merge through map[string]interface{}, it works on the raw bytes: members of dst the
+// patch doesn't touch are copied as they are, in their order, and only objects present in both are walked.
+// Members the patch adds come after those of dst, in the order of the patch.
+func MergeClone(dst, patch []byte) ([]byte, error) {
+	if !json.Valid(dst) {
+		return nil, errBadMergeDoc
+	}
+	if !json.Valid(patch) {
+		return nil, errBadMergePatch
+	}
+
+	var buf bytes.Buffer
+	buf.Grow(len(dst) + len(patch))
+	mergeValue(&buf, dst, patch)
+	return buf.Bytes(), nil
+}
+
+// rawMember is a member of an object, with its name as it's written, quotes included, and where its value
+// starts in the object.
+type rawMember struct {
+	name       []byte
+	value      []byte
+	valueStart int
+}
+
+// mergeValue writes the result of merging patch into target, which is empty if there's nothing to merge
+// it into. Both must otherwise be valid JSON.
+func mergeValue(buf *bytes.Buffer, target, patch []byte) {
+	patchMembers, ok := objectMembers(patch)
+	if !ok {
+		buf.Write(bytes.TrimSpace(patch))
+		return
+	}
+	// a target which isn't an object is replaced by one
+	targetMembers, _ := objectMembers(target)
+
+	// only the last of the patch's members with the same name counts
+	lastPatch := make(map[string]int, len(patchMembers))
+	for i, member := range patchMembers {
+		lastPatch[memberKey(member.name)] = i
+	}
+
+	buf.WriteByte('{')
+	first := true
+	writeName := func(name []byte) {
+		if !first {
+			buf.WriteByte(',')
+		}
+		first = false
+		buf.Write(name)
+		buf.WriteByte(':')
+	}
+
+	for _, member := range targetMembers {
+		key := memberKey(member.name)
+		i, inPatch := lastPatch[key]
+		if !inPatch {
+			writeName(member.name)
+			buf.Write(member.value)
+			continue
+		}
+		if isNull(patchMembers[i].value) {
+			continue
+		}
+		writeName(member.name)
+		mergeValue(buf, member.value, patchMembers[i].value)
+		delete(lastPatch, key)
+	}
+
+	for i, member := range patchMembers {
+		key := memberKey(member.name)
+		if last, ok := lastPatch[key]; !ok || last != i || isNull(member.value) {
+			continue
+		}
+		// objects the patch adds still have their null members removed
+		writeName(member.name)
+		mergeValue(buf, nil, member.value)
+	}
+	buf.WriteByte('}')
+}
+
+// memberKey returns the name of a member, unescaped if it has escapes.
+func memberKey(name []byte) string {
+	if bytes.IndexByte(name, '\\') == -1 {
+		return string(name[1 : len(name)-1])
+	}
+	var key string
+	if err := json.Unmarshal(name, &key); err != nil {
+		return string(name)
+	}
+	return key
+}
+
+func isNull(value []byte) bool {
+	return bytes.Equal(value, []byte("null"))
+}
+
+// objectMembers splits valid JSON into the members of the object it holds, or returns false if it
+// doesn't hold an object. Values are trimmed of whitespace. The object may be missing its closing bracket,
+// as it is when FindElement searches nested elements, and members are only returned up to anything which
+// isn't valid.
+func objectMembers(data []byte) ([]rawMember, bool) {
+	i := skipSpace(data, 0)
+	if i >= len(data) || data[i] != '{' {
+		return nil, false
+	}
+	var members []rawMember
+	i = skipSpace(data, i+1)
+	for i < len(data) && data[i] == '"' {
+		nameEnd := skipString(data, i)
+		name := data[i:nameEnd]
+		i = skipSpace(data, nameEnd)
+		if i >= len(data) || data[i] != ':' {
+			break
+		}
+		i = skipSpace(data, i+1)
+		if i >= len(data) {
+			break
+		}
+		valueEnd := skipValue(data, i)
+		members = append(members, rawMember{name: name, value: data[i:valueEnd], valueStart: i})
+		i = skipSpace(data, valueEnd)
+		if i < len(data) && data[i] == ',' {
+			i = skipSpace(data, i+1)
+		}
+	}
+	return members, true
+}
+
+// arrayElements splits valid JSON into the elements of the list it holds, as members without names, or
+// returns false if it doesn't hold a list. As with objectMembers, the list may be missing its closing
+// bracket.
+func arrayElements(data []byte) ([]rawMember, bool) {
+	i := skipSpace(data, 0)
+	if i >= len(data) || data[i] != '[' {
+		return nil, false
+	}
+	var elements []rawMember
+	i = skipSpace(data, i+1)
+	for i < len(data) && data[i] != ']' {
+		valueEnd := skipValue(data, i)
+		elements = append(elements, rawMember{value: data[i:valueEnd], valueStart: i})
+		i = skipSpace(data, valueEnd)
+		if i >= len(data) || data[i] != ',' {
+			break
+		}
+		i = skipSpace(data, i+1)
+	}
+	return elements, true
+}
+
+func skipSpace(data []byte, i int) int {
+	for i < len(data) {
+		switch data[i] {
+		case ' ', '\t', '\n', '\r':
+			i++
+		default:
+			return i
+		}
+	}
+	return i
+}
+
+// skipString returns the index after the string which starts at i.
+func skipString(data []byte, i int) int {
+	for i++; i < len(data); i++ {
+		switch data[i] {
+		case '\\':
+			i++
+		case '"':
+			return i + 1
+		}
+	}
+	return i
+}
+
+// skipValue returns the index after the value which starts at i.
+func skipValue(data []byte, i int) int {
+	switch data[i] {
+	case '"':
+		return skipString(data, i)
+	case '{', '[':
+		depth := 0
+		for i < len(data) {
+			switch data[i] {
+			case '"':
+				i = skipString(data, i)
+				continue
+			case '{', '[':
+				depth++
+			case '}', ']':
+				depth--
+				if depth == 0 {
+					return i + 1
+				}
+			}
+			i++
+		}
+		return i
+	}
+	// a number, true, false or null
+	for i < len(data) {
+		switch data[i] {
+		case ',', '}', ']', ' ', '\t', '\n', '\r':
+			return i
+		}
+		i++
+	}
+	return i
+}
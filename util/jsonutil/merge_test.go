@@ -0,0 +1,73 @@
+package jsonutil
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMergeClone(t *testing.T) {
+	testCases := []struct {
+		description string
+		dst         string
+		patch       string
+		expected    string
+	}{
+		// the examples of RFC 7386, appendix A
+		{description: "replace member", dst: `{"a":"b"}`, patch: `{"a":"c"}`, expected: `{"a":"c"}`},
+		{description: "add member", dst: `{"a":"b"}`, patch: `{"b":"c"}`, expected: `{"a":"b","b":"c"}`},
+		{description: "remove member", dst: `{"a":"b"}`, patch: `{"a":null}`, expected: `{}`},
+		{description: "remove one of two members", dst: `{"a":"b","b":"c"}`, patch: `{"a":null}`, expected: `{"b":"c"}`},
+		{description: "replace array", dst: `{"a":["b"]}`, patch: `{"a":"c"}`, expected: `{"a":"c"}`},
+		{description: "replace with array", dst: `{"a":"c"}`, patch: `{"a":["b"]}`, expected: `{"a":["b"]}`},
+		{description: "nested", dst: `{"a":{"b":"c"}}`, patch: `{"a":{"b":"d","c":null}}`, expected: `{"a":{"b":"d"}}`},
+		{description: "array of objects", dst: `{"a":[{"b":"c"}]}`, patch: `{"a":[1]}`, expected: `{"a":[1]}`},
+		{description: "arrays", dst: `["a","b"]`, patch: `["c","d"]`, expected: `["c","d"]`},
+		{description: "object replaced by array", dst: `{"a":"b"}`, patch: `["c"]`, expected: `["c"]`},
+		{description: "patch of null", dst: `{"a":"foo"}`, patch: `null`, expected: `null`},
+		{description: "patch of string", dst: `{"a":"foo"}`, patch: `"bar"`, expected: `"bar"`},
+		{description: "null in patch kept out of new member", dst: `{"e":null}`, patch: `{"a":1}`, expected: `{"e":null,"a":1}`},
+		{description: "array replaced by object", dst: `[1,2]`, patch: `{"a":"b","c":null}`, expected: `{"a":"b"}`},
+		{description: "nested null removed from added object", dst: `{}`, patch: `{"a":{"bb":{"ccc":null}}}`, expected: `{"a":{"bb":{}}}`},
+		// raw bytes handling
+		{description: "untouched members keep their order and formatting", dst: `{ "z": [1, 2], "a": {"x" : 1}, "m": "s" }`, patch: `{"a":{"y":2}}`, expected: `{"z":[1, 2],"a":{"x":1,"y":2},"m":"s"}`},
+		{description: "strings with brackets and escapes", dst: `{"a":"}]\"{","b\"c":1}`, patch: `{"b\"c":2,"d":"{\\"}`, expected: `{"a":"}]\"{","b\"c":2,"d":"{\\"}`},
+		{description: "escaped names match", dst: `{"a":1}`, patch: `{"\u0061":2}`, expected: `{"a":2}`},
+		{description: "last of duplicate patch members counts", dst: `{"a":1}`, patch: `{"b":1,"a":2,"b":3}`, expected: `{"a":2,"b":3}`},
+		{description: "deeply nested", dst: `{"imp":[{"id":"1"}],"ext":{"prebid":{"targeting":{"pricegranularity":"low","includewinners":true}}}}`, patch: `{"ext":{"prebid":{"targeting":{"pricegranularity":"high","includewinners":null}}},"tmax":500}`, expected: `{"imp":[{"id":"1"}],"ext":{"prebid":{"targeting":{"pricegranularity":"high"}}},"tmax":500}`},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			dst, patch := []byte(test.dst), []byte(test.patch)
+
+			result, err := MergeClone(dst, patch)
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, string(result))
+			assert.Equal(t, test.dst, string(dst), "dst shouldn't be modified")
+			assert.Equal(t, test.patch, string(patch), "patch shouldn't be modified")
+		})
+	}
+}
+
+func TestMergeCloneInvalid(t *testing.T) {
+	_, err := MergeClone([]byte(`{"a":1}`), []byte(`{"a":`))
+	assert.EqualError(t, err, "Invalid JSON Patch")
+
+	_, err = MergeClone([]byte(`{"a":`), []byte(`{"a":1}`))
+	assert.EqualError(t, err, "Invalid JSON Document")
+
+	_, err = MergeClone(nil, []byte(`{"a":`))
+	assert.EqualError(t, err, "Invalid JSON Document", "the document is checked first")
+}
+
+func BenchmarkMergeClone(b *testing.B) {
+	dst := []byte(`{"id":"some-request-id","site":{"page":"prebid.org","publisher":{"id":"1001"}},"imp":[{"id":"imp-1","banner":{"format":[{"w":300,"h":250},{"w":300,"h":600}]},"ext":{"appnexus":{"placementId":12883451},"rubicon":{"accountId":1001,"siteId":113932,"zoneId":535510}}}],"ext":{"prebid":{"targeting":{"pricegranularity":"med","includewinners":true,"includebidderkeys":true},"cache":{"bids":{}}}}}`)
+	patch := []byte(`{"id":"another-id","tmax":500,"ext":{"prebid":{"targeting":{"pricegranularity":"high"}}}}`)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		MergeClone(dst, patch)
+	}
+}
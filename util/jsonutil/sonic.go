@@ -0,0 +1,38 @@
+//go:build amd64 && !go1.23
+
+package jsonutil
+
+import (
+	"github.com/bytedance/sonic"
+)
+
+func init() {
+	engines[EngineSonic] = sonicEngine{}
+}
+
+// sonicConfigValidationOn is compatible with the standard library. Strings are copied, so that values
+// don't hold on to the buffers they were unmarshaled from.
+var sonicConfigValidationOn = sonic.ConfigStd
+
+// sonicConfigValidationOff doesn't validate json.RawMessage fields
+var sonicConfigValidationOff = sonic.Config{
+	EscapeHTML:              true,
+	SortMapKeys:             true,
+	CompactMarshaler:        true,
+	CopyString:              true,
+	NoValidateJSONMarshaler: true,
+}.Froze()
+
+type sonicEngine struct{}
+
+func (sonicEngine) Unmarshal(data []byte, v interface{}) error {
+	return sonicConfigValidationOff.Unmarshal(data, v)
+}
+
+func (sonicEngine) UnmarshalValid(data []byte, v interface{}) error {
+	return sonicConfigValidationOn.Unmarshal(data, v)
+}
+
+func (sonicEngine) Marshal(v interface{}) ([]byte, error) {
+	return sonicConfigValidationOn.Marshal(v)
+}
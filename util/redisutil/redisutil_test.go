@@ -0,0 +1,38 @@
+package redisutil
+
+import (
+	"testing"
+
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewClient(t *testing.T) {
+	client, err := NewClient(config.RedisConnection{Addrs: []string{"localhost:6379"}, DB: 2})
+	require.NoError(t, err)
+	assert.IsType(t, &redis.Client{}, client)
+	assert.Equal(t, 2, client.(*redis.Client).Options().DB)
+
+	client, err = NewClient(config.RedisConnection{Addrs: []string{"redis-1:6379", "redis-2:6379"}, Cluster: true})
+	require.NoError(t, err)
+	assert.IsType(t, &redis.ClusterClient{}, client)
+
+	_, err = NewClient(config.RedisConnection{Addrs: []string{"localhost:6379"}, TLS: config.RedisTLS{Enabled: true, RootCert: "does-not-exist.pem"}})
+	assert.Error(t, err)
+}
+
+func TestNewTLSConfig(t *testing.T) {
+	tlsConfig, err := newTLSConfig(config.RedisTLS{})
+	assert.NoError(t, err)
+	assert.Nil(t, tlsConfig)
+
+	tlsConfig, err = newTLSConfig(config.RedisTLS{Enabled: true})
+	assert.NoError(t, err)
+	require.NotNil(t, tlsConfig)
+	assert.Nil(t, tlsConfig.RootCAs)
+
+	_, err = newTLSConfig(config.RedisTLS{Enabled: true, RootCert: "does-not-exist.pem"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,29 @@
+package timeutil
+
+import (
+	"sync"
+	"time"
+)
+
+// FakeTime is a Time for tests which stands still until it's advanced.
+type FakeTime struct {
+	mutex sync.Mutex
+	now   time.Time
+}
+
+func NewFakeTime(now time.Time) *FakeTime {
+	return &FakeTime{now: now}
+}
+
+func (f *FakeTime) Now() time.Time {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return f.now
+}
+
+// Advance moves the time forward by d.
+func (f *FakeTime) Advance(d time.Duration) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.now = f.now.Add(d)
+}
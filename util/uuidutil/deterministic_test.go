@@ -0,0 +1,45 @@
+package uuidutil
+
+import (
+	"testing"
+
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeterministicGenerator(t *testing.T) {
+	generate := func(generator UUIDGenerator) string {
+		id, err := generator.Generate()
+		require.NoError(t, err)
+		return id
+	}
+
+	first := NewDeterministicGenerator(42)
+	firstIDs := []string{generate(first), generate(first), generate(first.Sequence("bid")), generate(first.Sequence("imp"))}
+
+	// The same seed gives the same UUIDs, whichever order the sequences are called in
+	second := NewDeterministicGenerator(42)
+	impID := generate(second.Sequence("imp"))
+	bidID := generate(second.Sequence("bid"))
+	secondIDs := []string{generate(second), generate(second), bidID, impID}
+	assert.Equal(t, firstIDs, secondIDs)
+
+	seen := make(map[string]struct{})
+	for _, id := range firstIDs {
+		parsed, err := uuid.FromString(id)
+		require.NoError(t, err)
+		assert.Equal(t, uuid.V4, parsed.Version(), id)
+		assert.Equal(t, uuid.VariantRFC4122, parsed.Variant(), id)
+		seen[id] = struct{}{}
+	}
+	assert.Len(t, seen, len(firstIDs), "The UUIDs should all be different")
+
+	other := NewDeterministicGenerator(43)
+	assert.NotEqual(t, firstIDs[0], generate(other), "A different seed should give different UUIDs")
+}
+
+func TestNilDeterministicGenerator(t *testing.T) {
+	var generator *DeterministicGenerator
+	assert.Equal(t, UUIDRandomGenerator{}, generator.Sequence("bid"))
+}
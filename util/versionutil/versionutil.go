@@ -0,0 +1,41 @@
+package versionutil
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// Version is a major.minor.patch version, such as the version of an SDK.
+type Version [3]int
+
+// Parse parses a version such as 2.1.0. The minor and patch versions may be left out, and anything after a
+// hyphen or plus sign, such as -beta1, is ignored.
+func Parse(v string) (Version, error) {
+	var version Version
+	if i := strings.IndexAny(v, "-+"); i >= 0 {
+		v = v[:i]
+	}
+	parts := strings.Split(v, ".")
+	if len(parts) > len(version) {
+		return Version{}, errors.New("expected major.minor.patch format")
+	}
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return Version{}, errors.New("expected major.minor.patch format")
+		}
+		version[i] = n
+	}
+	return version, nil
+}
+
+// AtLeast returns true if the version is the same as or later than the other.
+func (v Version) AtLeast(other Version) bool {
+	for i := range v {
+		if v[i] != other[i] {
+			return v[i] > other[i]
+		}
+	}
+	return true
+}
@@ -0,0 +1,45 @@
+package versionutil
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	testCases := []struct {
+		version     string
+		expected    Version
+		expectedErr bool
+	}{
+		{version: "2.1.0", expected: Version{2, 1, 0}},
+		{version: "2.1", expected: Version{2, 1, 0}},
+		{version: "3", expected: Version{3, 0, 0}},
+		{version: "2.1.5-beta1", expected: Version{2, 1, 5}},
+		{version: "2.1.5+build7", expected: Version{2, 1, 5}},
+		{version: "", expectedErr: true},
+		{version: "2.x", expectedErr: true},
+		{version: "2.-1", expectedErr: true},
+		{version: "1.2.3.4", expectedErr: true},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.version, func(t *testing.T) {
+			version, err := Parse(test.version)
+			if test.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, version)
+		})
+	}
+}
+
+func TestAtLeast(t *testing.T) {
+	assert.True(t, Version{2, 1, 0}.AtLeast(Version{2, 1, 0}))
+	assert.True(t, Version{2, 10, 0}.AtLeast(Version{2, 9, 9}))
+	assert.True(t, Version{3, 0, 0}.AtLeast(Version{2, 9, 9}))
+	assert.False(t, Version{2, 0, 9}.AtLeast(Version{2, 1, 0}))
+	assert.False(t, Version{1, 9, 9}.AtLeast(Version{2, 0, 0}))
+}
@@ -0,0 +1,227 @@
+package webhooks
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/prebid/prebid-server/v2/analytics"
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/logger"
+	"github.com/prebid/prebid-server/v2/metrics"
+	"github.com/prebid/prebid-server/v2/util/jsonutil"
+	"github.com/prebid/prebid-server/v2/util/uuidutil"
+)
+
+// AuctionEventsType is the type of the batches of auction events, sent in the EventHeader.
+const AuctionEventsType = "auction_events"
+
+// AuctionEventBatch is the body of the requests sent to the webhooks of accounts.
+type AuctionEventBatch struct {
+	ID      string         `json:"id"`
+	Account string         `json:"account"`
+	Time    time.Time      `json:"time"`
+	Events  []AuctionEvent `json:"events"`
+}
+
+// AuctionEvent is an event received by /event for one of the account's bids.
+type AuctionEvent struct {
+	Type        string `json:"type"`
+	VType       string `json:"vtype,omitempty"`
+	BidID       string `json:"bidid"`
+	Bidder      string `json:"bidder,omitempty"`
+	Integration string `json:"integration,omitempty"`
+	// Timestamp is the one given to /event, if any
+	Timestamp int64 `json:"timestamp,omitempty"`
+	// Time is when /event received the event
+	Time time.Time `json:"time"`
+}
+
+// AuctionEventNotifier sends the win, loss and render events of accounts to the webhooks they configure.
+// Events are batched by account and webhook, and the batches are sent by a pool of workers, so that a
+// webhook which is down doesn't hold up the others for more than its timeout. A nil AuctionEventNotifier
+// sends nothing.
+type AuctionEventNotifier struct {
+	ids       uuidutil.UUIDGenerator
+	now       func() time.Time
+	me        metrics.MetricsEngine
+	sender    *sender
+	batchSize int
+	interval  time.Duration
+
+	queue   chan queuedAuctionEvent
+	batches chan auctionEventBatch
+
+	// mutex keeps events from being queued while the queue is closed
+	mutex   sync.RWMutex
+	closed  bool
+	stop    chan struct{}
+	workers sync.WaitGroup
+}
+
+// auctionEventWebhook is where a batch is sent. Accounts which share a webhook still get their own batches.
+type auctionEventWebhook struct {
+	account string
+	url     string
+	secret  string
+}
+
+type queuedAuctionEvent struct {
+	webhook auctionEventWebhook
+	event   AuctionEvent
+}
+
+type auctionEventBatch struct {
+	webhook auctionEventWebhook
+	events  []AuctionEvent
+}
+
+// NewAuctionEventNotifier starts the batching of events and the workers which send the batches, or returns
+// nil if auction event webhooks are disabled.
+func NewAuctionEventNotifier(cfg config.AuctionEventWebhooks, client *http.Client, me metrics.MetricsEngine) *AuctionEventNotifier {
+	if !cfg.Enabled {
+		return nil
+	}
+
+	n := &AuctionEventNotifier{
+		ids:       uuidutil.UUIDRandomGenerator{},
+		now:       time.Now,
+		me:        me,
+		batchSize: cfg.BatchSize,
+		interval:  time.Duration(cfg.BatchIntervalMs) * time.Millisecond,
+		queue:     make(chan queuedAuctionEvent, cfg.QueueSize),
+		batches:   make(chan auctionEventBatch),
+		stop:      make(chan struct{}),
+	}
+	n.sender = &sender{
+		client:      client,
+		timeout:     time.Duration(cfg.TimeoutMs) * time.Millisecond,
+		maxAttempts: cfg.MaxAttempts,
+		retryDelay:  time.Duration(cfg.RetryDelayMs) * time.Millisecond,
+		now:         time.Now,
+		stop:        n.stop,
+	}
+	n.workers.Add(1 + cfg.Workers)
+	go n.batch()
+	for i := 0; i < cfg.Workers; i++ {
+		go n.work()
+	}
+	return n
+}
+
+// Notify queues the event for the account's webhook, if the account has one which takes events of its
+// type. The event is dropped if too many are waiting to be batched already.
+func (n *AuctionEventNotifier) Notify(account *config.Account, request *analytics.EventRequest) {
+	if n == nil || account == nil || request == nil || !account.EventWebhook.Sends(string(request.Type)) {
+		return
+	}
+
+	queued := queuedAuctionEvent{
+		webhook: auctionEventWebhook{
+			account: account.ID,
+			url:     account.EventWebhook.URL,
+			secret:  account.EventWebhook.Secret,
+		},
+		event: AuctionEvent{
+			Type:        string(request.Type),
+			VType:       string(request.VType),
+			BidID:       request.BidID,
+			Bidder:      request.Bidder,
+			Integration: request.Integration,
+			Timestamp:   request.Timestamp,
+			Time:        n.now().UTC(),
+		},
+	}
+	n.mutex.RLock()
+	defer n.mutex.RUnlock()
+	if n.closed {
+		return
+	}
+	select {
+	case n.queue <- queued:
+	default:
+		logger.Warningf("Dropped the %s event of bid %s for the webhook of account %s, since too many events are waiting", request.Type, request.BidID, account.ID)
+		n.me.RecordAuctionEventWebhook(metrics.WebhookDropped)
+	}
+}
+
+// Close sends the events which are still queued, without retrying the batches which fail, and stops the
+// workers.
+func (n *AuctionEventNotifier) Close() {
+	if n == nil {
+		return
+	}
+	n.mutex.Lock()
+	n.closed = true
+	close(n.stop)
+	close(n.queue)
+	n.mutex.Unlock()
+	n.workers.Wait()
+}
+
+// batch gathers the queued events into a batch for each webhook, which is sent once it's full or once the
+// batch interval has passed.
+func (n *AuctionEventNotifier) batch() {
+	defer n.workers.Done()
+	defer close(n.batches)
+
+	pending := make(map[auctionEventWebhook][]AuctionEvent)
+	flush := func() {
+		for webhook, events := range pending {
+			n.batches <- auctionEventBatch{webhook: webhook, events: events}
+			delete(pending, webhook)
+		}
+	}
+	ticker := time.NewTicker(n.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case queued, ok := <-n.queue:
+			if !ok {
+				flush()
+				return
+			}
+			events := append(pending[queued.webhook], queued.event)
+			if len(events) >= n.batchSize {
+				n.batches <- auctionEventBatch{webhook: queued.webhook, events: events}
+				delete(pending, queued.webhook)
+				continue
+			}
+			pending[queued.webhook] = events
+		case <-ticker.C:
+			flush()
+		}
+	}
+}
+
+func (n *AuctionEventNotifier) work() {
+	defer n.workers.Done()
+	for b := range n.batches {
+		status := metrics.WebhookDelivered
+		if err := n.send(b); err != nil {
+			logger.Warningf("Failed to send %d events to the webhook of account %s: %v", len(b.events), b.webhook.account, err)
+			status = metrics.WebhookFailed
+		}
+		for range b.events {
+			n.me.RecordAuctionEventWebhook(status)
+		}
+	}
+}
+
+func (n *AuctionEventNotifier) send(b auctionEventBatch) error {
+	id, err := n.ids.Generate()
+	if err != nil {
+		return err
+	}
+	body, err := jsonutil.Marshal(AuctionEventBatch{
+		ID:      id,
+		Account: b.webhook.account,
+		Time:    n.now().UTC(),
+		Events:  b.events,
+	})
+	if err != nil {
+		return err
+	}
+	return n.sender.deliver(b.webhook.url, []byte(b.webhook.secret), id, AuctionEventsType, body)
+}
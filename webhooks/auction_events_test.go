@@ -0,0 +1,151 @@
+package webhooks
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/prebid/prebid-server/v2/analytics"
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/metrics"
+	"github.com/prebid/prebid-server/v2/util/jsonutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestAuctionEventNotifier(me metrics.MetricsEngine, batchSize int, batchIntervalMs int) *AuctionEventNotifier {
+	notifier := NewAuctionEventNotifier(config.AuctionEventWebhooks{
+		Enabled:         true,
+		TimeoutMs:       1000,
+		MaxAttempts:     3,
+		RetryDelayMs:    1,
+		BatchSize:       batchSize,
+		BatchIntervalMs: batchIntervalMs,
+		QueueSize:       10,
+		Workers:         1,
+	}, http.DefaultClient, me)
+	notifier.ids = fakeIDs{}
+	notifier.now = func() time.Time { return time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC) }
+	return notifier
+}
+
+func webhookAccount(id, url string, events ...string) *config.Account {
+	return &config.Account{ID: id, EventWebhook: config.AccountEventWebhook{Enabled: true, URL: url, Secret: "secret-" + id, Events: events}}
+}
+
+func TestNewAuctionEventNotifierDisabled(t *testing.T) {
+	notifier := NewAuctionEventNotifier(config.AuctionEventWebhooks{Enabled: false}, http.DefaultClient, &metrics.MetricsEngineMock{})
+	assert.Nil(t, notifier)
+
+	// a nil notifier does nothing
+	notifier.Notify(webhookAccount("1001", "http://hooks.prebid.org"), &analytics.EventRequest{Type: analytics.Win, BidID: "bid"})
+	notifier.Close()
+}
+
+func TestNotifyAuctionEvents(t *testing.T) {
+	server, received := newEndpoint(t)
+	me := &metrics.MetricsEngineMock{}
+	me.On("RecordAuctionEventWebhook", metrics.WebhookDelivered).Return()
+	notifier := newTestAuctionEventNotifier(me, 2, 60000)
+	account := webhookAccount("1001", server.URL)
+
+	notifier.Notify(account, &analytics.EventRequest{Type: analytics.Win, BidID: "bid-1", Bidder: "appnexus", Timestamp: 1714564700, Integration: "web"})
+	notifier.Notify(account, &analytics.EventRequest{Type: analytics.Vast, VType: analytics.Start, BidID: "bid-2", Bidder: "rubicon"})
+	batch := <-received
+	notifier.Close()
+
+	assert.JSONEq(t, `{"id":"event-1","account":"1001","time":"2024-05-01T12:00:00Z","events":[
+		{"type":"win","bidid":"bid-1","bidder":"appnexus","integration":"web","timestamp":1714564700,"time":"2024-05-01T12:00:00Z"},
+		{"type":"vast","vtype":"start","bidid":"bid-2","bidder":"rubicon","time":"2024-05-01T12:00:00Z"}
+	]}`, string(batch.body))
+	assert.Equal(t, "event-1", batch.header.Get(IDHeader))
+	assert.Equal(t, AuctionEventsType, batch.header.Get(EventHeader))
+	assertSigned(t, "secret-1001", batch)
+	assert.Empty(t, received)
+	me.AssertNumberOfCalls(t, "RecordAuctionEventWebhook", 2)
+}
+
+func TestNotifyAuctionEventsBatchInterval(t *testing.T) {
+	server, received := newEndpoint(t)
+	me := &metrics.MetricsEngineMock{}
+	me.On("RecordAuctionEventWebhook", metrics.WebhookDelivered).Return()
+	notifier := newTestAuctionEventNotifier(me, 100, 10)
+	defer notifier.Close()
+
+	notifier.Notify(webhookAccount("1001", server.URL), &analytics.EventRequest{Type: analytics.Imp, BidID: "bid-1"})
+
+	select {
+	case batch := <-received:
+		assert.Contains(t, string(batch.body), `"bidid":"bid-1"`)
+	case <-time.After(5 * time.Second):
+		t.Fatal("the batch should have been sent once the batch interval passed")
+	}
+}
+
+func TestNotifyAuctionEventsByAccount(t *testing.T) {
+	server, received := newEndpoint(t)
+	me := &metrics.MetricsEngineMock{}
+	me.On("RecordAuctionEventWebhook", metrics.WebhookDelivered).Return()
+	notifier := newTestAuctionEventNotifier(me, 100, 60000)
+
+	notifier.Notify(webhookAccount("1001", server.URL), &analytics.EventRequest{Type: analytics.Win, BidID: "bid-1"})
+	notifier.Notify(webhookAccount("1002", server.URL), &analytics.EventRequest{Type: analytics.Win, BidID: "bid-2"})
+	notifier.Notify(webhookAccount("1003", server.URL, "loss"), &analytics.EventRequest{Type: analytics.Win, BidID: "bid-3"})
+	notifier.Notify(&config.Account{ID: "1004"}, &analytics.EventRequest{Type: analytics.Win, BidID: "bid-4"})
+	notifier.Close()
+
+	assert.Len(t, received, 2, "accounts sharing a webhook should still get their own batches")
+	accounts := map[string]bool{}
+	for i := 0; i < 2; i++ {
+		batch := <-received
+		var decoded AuctionEventBatch
+		require.NoError(t, jsonutil.Unmarshal(batch.body, &decoded))
+		assertSigned(t, "secret-"+decoded.Account, batch)
+		accounts[decoded.Account] = true
+	}
+	assert.Equal(t, map[string]bool{"1001": true, "1002": true}, accounts)
+	me.AssertNumberOfCalls(t, "RecordAuctionEventWebhook", 2)
+}
+
+func TestNotifyAuctionEventsFailed(t *testing.T) {
+	server, received := newEndpoint(t, http.StatusBadRequest, http.StatusBadRequest, http.StatusBadRequest)
+	me := &metrics.MetricsEngineMock{}
+	me.On("RecordAuctionEventWebhook", metrics.WebhookFailed).Return()
+	notifier := newTestAuctionEventNotifier(me, 1, 60000)
+
+	notifier.Notify(webhookAccount("1001", server.URL), &analytics.EventRequest{Type: analytics.Loss, BidID: "bid-1"})
+	for i := 0; i < 3; i++ {
+		batch := <-received
+		assert.Equal(t, "event-1", batch.header.Get(IDHeader), "retries should keep the ID of the batch")
+	}
+	notifier.Close()
+	me.AssertNumberOfCalls(t, "RecordAuctionEventWebhook", 1)
+	assert.Empty(t, received)
+}
+
+func TestNotifyAuctionEventsDropped(t *testing.T) {
+	me := &metrics.MetricsEngineMock{}
+	me.On("RecordAuctionEventWebhook", mock.Anything).Return()
+	notifier := &AuctionEventNotifier{
+		ids:   fakeIDs{},
+		now:   time.Now,
+		me:    me,
+		queue: make(chan queuedAuctionEvent, 1),
+	}
+	account := webhookAccount("1001", "http://hooks.prebid.org")
+
+	notifier.Notify(account, &analytics.EventRequest{Type: analytics.Win, BidID: "bid-1"})
+	notifier.Notify(account, &analytics.EventRequest{Type: analytics.Win, BidID: "bid-2"})
+	me.AssertCalled(t, "RecordAuctionEventWebhook", metrics.WebhookDropped)
+	me.AssertNumberOfCalls(t, "RecordAuctionEventWebhook", 1)
+}
+
+func TestNotifyAuctionEventsAfterClose(t *testing.T) {
+	server, received := newEndpoint(t)
+	notifier := newTestAuctionEventNotifier(&metrics.MetricsEngineMock{}, 1, 60000)
+	notifier.Close()
+
+	notifier.Notify(webhookAccount("1001", server.URL), &analytics.EventRequest{Type: analytics.Win, BidID: "bid-1"})
+	assert.Empty(t, received)
+}
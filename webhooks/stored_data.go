@@ -0,0 +1,55 @@
+package webhooks
+
+import (
+	"encoding/json"
+	"sort"
+
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/stored_requests/events"
+)
+
+// StoredDataObserver returns an observer which sends an event when stored data of the type is saved or
+// invalidated, whether through the admin API or by one of the backends. The events are named by the
+// section of the stored data, such as accounts.saved or stored_requests.invalidated. A nil Notifier
+// returns a nil observer.
+func (n *Notifier) StoredDataObserver(dataType config.DataType) events.Observer {
+	if n == nil {
+		return nil
+	}
+	return &storedDataObserver{notifier: n, section: dataType.Section()}
+}
+
+type storedDataObserver struct {
+	notifier *Notifier
+	section  string
+}
+
+func (o *storedDataObserver) Saved(save events.Save) {
+	o.notifier.Notify(o.section+".saved", EventData{
+		Requests:  sortedKeys(save.Requests),
+		Imps:      sortedKeys(save.Imps),
+		Accounts:  sortedKeys(save.Accounts),
+		Responses: sortedKeys(save.Responses),
+	})
+}
+
+func (o *storedDataObserver) Invalidated(invalidation events.Invalidation) {
+	o.notifier.Notify(o.section+".invalidated", EventData{
+		Requests:  invalidation.Requests,
+		Imps:      invalidation.Imps,
+		Accounts:  invalidation.Accounts,
+		Responses: invalidation.Responses,
+	})
+}
+
+func sortedKeys(data map[string]json.RawMessage) []string {
+	if len(data) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(data))
+	for key := range data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
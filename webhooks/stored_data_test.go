@@ -0,0 +1,39 @@
+package webhooks
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/metrics"
+	"github.com/prebid/prebid-server/v2/stored_requests/events"
+	"github.com/prebid/prebid-server/v2/util/jsonutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStoredDataObserver(t *testing.T) {
+	server, received := newEndpoint(t)
+	me := &metrics.MetricsEngineMock{}
+	me.On("RecordWebhook", metrics.WebhookDelivered).Return()
+	notifier := newTestNotifier(me, config.WebhookEndpoint{URL: server.URL, Secret: "secret"})
+
+	observer := notifier.StoredDataObserver(config.RequestDataType)
+	observer.Saved(events.Save{
+		Requests: map[string]json.RawMessage{"req-2": json.RawMessage(`{}`), "req-1": json.RawMessage(`{}`)},
+		Imps:     map[string]json.RawMessage{"imp-1": json.RawMessage(`{}`)},
+	})
+	observer.Invalidated(events.Invalidation{Responses: []string{"resp-1"}})
+	// nothing changed, so nothing is sent
+	observer.Saved(events.Save{})
+	notifier.Close()
+
+	require.Len(t, received, 2)
+	var saved, invalidated Event
+	require.NoError(t, jsonutil.Unmarshal((<-received).body, &saved))
+	require.NoError(t, jsonutil.Unmarshal((<-received).body, &invalidated))
+	assert.Equal(t, "stored_requests.saved", saved.Type)
+	assert.Equal(t, EventData{Requests: []string{"req-1", "req-2"}, Imps: []string{"imp-1"}}, saved.Data)
+	assert.Equal(t, "stored_requests.invalidated", invalidated.Type)
+	assert.Equal(t, EventData{Responses: []string{"resp-1"}}, invalidated.Data)
+}
@@ -0,0 +1,250 @@
+// Package webhooks sends events to external systems, such as cache purgers, audit logs and publisher
+// dashboards, when accounts or stored data change, so they don't have to poll for changes. Events are
+// signed with the secret of each endpoint, and are sent in the background, in the order they happened.
+// It also sends the win, loss and render events of accounts' bids to the webhooks of the accounts.
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/logger"
+	"github.com/prebid/prebid-server/v2/metrics"
+	"github.com/prebid/prebid-server/v2/util/jsonutil"
+	"github.com/prebid/prebid-server/v2/util/uuidutil"
+)
+
+const (
+	// IDHeader has the ID of the event, which stays the same when it's retried
+	IDHeader = "X-Prebid-Webhook-Id"
+	// EventHeader has the type of the event
+	EventHeader = "X-Prebid-Webhook-Event"
+	// SignatureHeader has the time the event was sent at and its signature, as t=<unix time>,v1=<signature>
+	SignatureHeader = "X-Prebid-Webhook-Signature"
+)
+
+// Event is the body of the requests sent to the endpoints.
+type Event struct {
+	ID   string    `json:"id"`
+	Type string    `json:"type"`
+	Time time.Time `json:"time"`
+	Data EventData `json:"data"`
+}
+
+// EventData has the IDs of what changed. The data itself isn't sent, since account configs may have
+// secrets in them.
+type EventData struct {
+	Requests  []string `json:"requests,omitempty"`
+	Imps      []string `json:"imps,omitempty"`
+	Accounts  []string `json:"accounts,omitempty"`
+	Responses []string `json:"responses,omitempty"`
+}
+
+func (d EventData) empty() bool {
+	return len(d.Requests) == 0 && len(d.Imps) == 0 && len(d.Accounts) == 0 && len(d.Responses) == 0
+}
+
+// Notifier sends events to the webhook endpoints. Each endpoint has its own queue and worker, so that an
+// endpoint which is down doesn't hold up the others. A nil Notifier sends nothing.
+type Notifier struct {
+	endpoints []*endpoint
+	ids       uuidutil.UUIDGenerator
+	now       func() time.Time
+	me        metrics.MetricsEngine
+
+	// mutex keeps events from being queued while the queues are closed
+	mutex   sync.RWMutex
+	closed  bool
+	stop    chan struct{}
+	workers sync.WaitGroup
+}
+
+type endpoint struct {
+	url    string
+	secret []byte
+	// events are the types of events sent to the endpoint, or nil for all of them
+	events map[string]bool
+	queue  chan Event
+}
+
+// NewNotifier starts the workers which send events to the endpoints, or returns nil if webhooks are
+// disabled.
+func NewNotifier(cfg config.Webhooks, client *http.Client, me metrics.MetricsEngine) *Notifier {
+	if !cfg.Enabled {
+		return nil
+	}
+
+	n := &Notifier{
+		ids:  uuidutil.UUIDRandomGenerator{},
+		now:  time.Now,
+		me:   me,
+		stop: make(chan struct{}),
+	}
+	sender := &sender{
+		client:      client,
+		timeout:     time.Duration(cfg.TimeoutMs) * time.Millisecond,
+		maxAttempts: cfg.MaxAttempts,
+		retryDelay:  time.Duration(cfg.RetryDelayMs) * time.Millisecond,
+		now:         time.Now,
+		stop:        n.stop,
+	}
+	for _, endpointCfg := range cfg.Endpoints {
+		e := &endpoint{
+			url:    endpointCfg.URL,
+			secret: []byte(endpointCfg.Secret),
+			queue:  make(chan Event, cfg.QueueSize),
+		}
+		if len(endpointCfg.Events) > 0 {
+			e.events = make(map[string]bool, len(endpointCfg.Events))
+			for _, eventType := range endpointCfg.Events {
+				e.events[eventType] = true
+			}
+		}
+		n.endpoints = append(n.endpoints, e)
+		n.workers.Add(1)
+		go n.work(e, sender)
+	}
+	return n
+}
+
+// Notify queues an event for the endpoints which take its type. Events are dropped for endpoints which
+// have too many waiting to be sent already.
+func (n *Notifier) Notify(eventType string, data EventData) {
+	if n == nil || data.empty() {
+		return
+	}
+
+	id, err := n.ids.Generate()
+	if err != nil {
+		logger.Errorf("Failed to generate an ID for the %s webhook event: %v", eventType, err)
+		return
+	}
+	event := Event{ID: id, Type: eventType, Time: n.now().UTC(), Data: data}
+	n.mutex.RLock()
+	defer n.mutex.RUnlock()
+	if n.closed {
+		return
+	}
+	for _, e := range n.endpoints {
+		if e.events != nil && !e.events[eventType] {
+			continue
+		}
+		select {
+		case e.queue <- event:
+		default:
+			logger.Warningf("Dropped the %s webhook event %s for %s, which has too many events waiting", eventType, id, e.url)
+			n.me.RecordWebhook(metrics.WebhookDropped)
+		}
+	}
+}
+
+// Close sends the events which are still queued, without retrying the ones which fail, and stops the
+// workers.
+func (n *Notifier) Close() {
+	if n == nil {
+		return
+	}
+	n.mutex.Lock()
+	n.closed = true
+	close(n.stop)
+	for _, e := range n.endpoints {
+		close(e.queue)
+	}
+	n.mutex.Unlock()
+	n.workers.Wait()
+}
+
+func (n *Notifier) work(e *endpoint, s *sender) {
+	defer n.workers.Done()
+	for event := range e.queue {
+		if err := s.send(e, event); err != nil {
+			logger.Warningf("Failed to send the %s webhook event %s to %s: %v", event.Type, event.ID, e.url, err)
+			n.me.RecordWebhook(metrics.WebhookFailed)
+			continue
+		}
+		n.me.RecordWebhook(metrics.WebhookDelivered)
+	}
+}
+
+// sender posts events to endpoints, retrying them with exponential backoff until they're accepted with a
+// 2xx response.
+type sender struct {
+	client      *http.Client
+	timeout     time.Duration
+	maxAttempts int
+	retryDelay  time.Duration
+	now         func() time.Time
+	stop        chan struct{}
+}
+
+func (s *sender) send(e *endpoint, event Event) error {
+	body, err := jsonutil.Marshal(event)
+	if err != nil {
+		return err
+	}
+	return s.deliver(e.url, e.secret, event.ID, event.Type, body)
+}
+
+// deliver posts the body until it's accepted or it has been sent maxAttempts times.
+func (s *sender) deliver(url string, secret []byte, id, eventType string, body []byte) error {
+	var err error
+	delay := s.retryDelay
+	for attempt := 1; ; attempt++ {
+		err = s.post(url, secret, id, eventType, body)
+		if err == nil || attempt >= s.maxAttempts {
+			return err
+		}
+		select {
+		case <-time.After(delay):
+		case <-s.stop:
+			return err
+		}
+		delay *= 2
+	}
+}
+
+func (s *sender) post(url string, secret []byte, id, eventType string, body []byte) error {
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(IDHeader, id)
+	req.Header.Set(EventHeader, eventType)
+	// the signature is made when the event is sent, so that receivers can reject old events being replayed
+	req.Header.Set(SignatureHeader, sign(secret, s.now().Unix(), body))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// sign returns the signature header of an event body sent at the timestamp. The signature is the hex
+// encoded HMAC-SHA256 of the timestamp, a dot and the body.
+func sign(secret []byte, timestamp int64, body []byte) string {
+	t := strconv.FormatInt(timestamp, 10)
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(t))
+	mac.Write([]byte{'.'})
+	mac.Write(body)
+	return "t=" + t + ",v1=" + hex.EncodeToString(mac.Sum(nil))
+}
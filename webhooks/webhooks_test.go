@@ -0,0 +1,191 @@
+package webhooks
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/prebid/prebid-server/v2/config"
+	"github.com/prebid/prebid-server/v2/metrics"
+	"github.com/prebid/prebid-server/v2/util/jsonutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type receivedEvent struct {
+	header http.Header
+	body   []byte
+}
+
+// newEndpoint starts an endpoint which answers with the given status codes in turn, and 200 after them.
+func newEndpoint(t *testing.T, statuses ...int) (*httptest.Server, chan receivedEvent) {
+	received := make(chan receivedEvent, 10)
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received <- receivedEvent{header: r.Header, body: body}
+		if call := int(atomic.AddInt32(&calls, 1)); call <= len(statuses) {
+			w.WriteHeader(statuses[call-1])
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(server.Close)
+	return server, received
+}
+
+type fakeIDs struct{}
+
+func (fakeIDs) Generate() (string, error) {
+	return "event-1", nil
+}
+
+func newTestNotifier(me metrics.MetricsEngine, endpoints ...config.WebhookEndpoint) *Notifier {
+	notifier := NewNotifier(config.Webhooks{
+		Enabled:      true,
+		Endpoints:    endpoints,
+		TimeoutMs:    1000,
+		MaxAttempts:  3,
+		RetryDelayMs: 1,
+		QueueSize:    10,
+	}, http.DefaultClient, me)
+	notifier.ids = fakeIDs{}
+	notifier.now = func() time.Time { return time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC) }
+	return notifier
+}
+
+func TestNewNotifierDisabled(t *testing.T) {
+	notifier := NewNotifier(config.Webhooks{Enabled: false}, http.DefaultClient, &metrics.MetricsEngineMock{})
+	assert.Nil(t, notifier)
+
+	// a nil notifier does nothing
+	notifier.Notify("accounts.saved", EventData{Accounts: []string{"1001"}})
+	assert.Nil(t, notifier.StoredDataObserver(config.AccountDataType))
+	notifier.Close()
+}
+
+func TestNotify(t *testing.T) {
+	server, received := newEndpoint(t)
+	me := &metrics.MetricsEngineMock{}
+	me.On("RecordWebhook", metrics.WebhookDelivered).Return()
+	notifier := newTestNotifier(me, config.WebhookEndpoint{URL: server.URL, Secret: "secret"})
+
+	notifier.Notify("accounts.saved", EventData{Accounts: []string{"1001"}})
+	event := <-received
+	notifier.Close()
+
+	assert.JSONEq(t, `{"id":"event-1","type":"accounts.saved","time":"2024-05-01T12:00:00Z","data":{"accounts":["1001"]}}`, string(event.body))
+	assert.Equal(t, "application/json", event.header.Get("Content-Type"))
+	assert.Equal(t, "event-1", event.header.Get(IDHeader))
+	assert.Equal(t, "accounts.saved", event.header.Get(EventHeader))
+	assertSigned(t, "secret", event)
+	me.AssertNumberOfCalls(t, "RecordWebhook", 1)
+}
+
+func TestNotifyEventTypes(t *testing.T) {
+	accountsServer, accountsReceived := newEndpoint(t)
+	allServer, allReceived := newEndpoint(t)
+	me := &metrics.MetricsEngineMock{}
+	me.On("RecordWebhook", metrics.WebhookDelivered).Return()
+	notifier := newTestNotifier(me,
+		config.WebhookEndpoint{URL: accountsServer.URL, Secret: "secret", Events: []string{"accounts.saved"}},
+		config.WebhookEndpoint{URL: allServer.URL, Secret: "other-secret"},
+	)
+
+	notifier.Notify("stored_requests.saved", EventData{Requests: []string{"req"}})
+	notifier.Notify("accounts.saved", EventData{Accounts: []string{"1001"}})
+	notifier.Notify("accounts.saved", EventData{})
+	notifier.Close()
+
+	assert.Len(t, accountsReceived, 1)
+	assert.Equal(t, "accounts.saved", (<-accountsReceived).header.Get(EventHeader))
+	assert.Len(t, allReceived, 2)
+	assert.Equal(t, "stored_requests.saved", (<-allReceived).header.Get(EventHeader))
+	event := <-allReceived
+	assert.Equal(t, "accounts.saved", event.header.Get(EventHeader))
+	assertSigned(t, "other-secret", event)
+}
+
+func TestNotifyRetries(t *testing.T) {
+	server, received := newEndpoint(t, http.StatusInternalServerError, http.StatusServiceUnavailable)
+	me := &metrics.MetricsEngineMock{}
+	me.On("RecordWebhook", metrics.WebhookDelivered).Return()
+	notifier := newTestNotifier(me, config.WebhookEndpoint{URL: server.URL, Secret: "secret"})
+
+	notifier.Notify("accounts.invalidated", EventData{Accounts: []string{"1001"}})
+	for i := 0; i < 3; i++ {
+		event := <-received
+		assert.Equal(t, "event-1", event.header.Get(IDHeader), "retries should keep the ID of the event")
+	}
+	notifier.Close()
+	me.AssertCalled(t, "RecordWebhook", metrics.WebhookDelivered)
+}
+
+func TestNotifyFailed(t *testing.T) {
+	server, received := newEndpoint(t, http.StatusBadRequest, http.StatusBadRequest, http.StatusBadRequest)
+	me := &metrics.MetricsEngineMock{}
+	me.On("RecordWebhook", metrics.WebhookFailed).Return()
+	notifier := newTestNotifier(me, config.WebhookEndpoint{URL: server.URL, Secret: "secret"})
+
+	notifier.Notify("accounts.saved", EventData{Accounts: []string{"1001"}})
+	for i := 0; i < 3; i++ {
+		<-received
+	}
+	notifier.Close()
+	me.AssertCalled(t, "RecordWebhook", metrics.WebhookFailed)
+	assert.Empty(t, received)
+}
+
+func TestNotifyDropped(t *testing.T) {
+	me := &metrics.MetricsEngineMock{}
+	me.On("RecordWebhook", mock.Anything).Return()
+	notifier := &Notifier{
+		endpoints: []*endpoint{{url: "http://hooks.prebid.org", queue: make(chan Event, 1)}},
+		ids:       fakeIDs{},
+		now:       time.Now,
+		me:        me,
+		stop:      make(chan struct{}),
+	}
+
+	notifier.Notify("accounts.saved", EventData{Accounts: []string{"1001"}})
+	notifier.Notify("accounts.saved", EventData{Accounts: []string{"1002"}})
+	me.AssertCalled(t, "RecordWebhook", metrics.WebhookDropped)
+	me.AssertNumberOfCalls(t, "RecordWebhook", 1)
+}
+
+func TestNotifyAfterClose(t *testing.T) {
+	server, received := newEndpoint(t)
+	notifier := newTestNotifier(&metrics.MetricsEngineMock{}, config.WebhookEndpoint{URL: server.URL, Secret: "secret"})
+	notifier.Close()
+
+	notifier.Notify("accounts.saved", EventData{Accounts: []string{"1001"}})
+	assert.Empty(t, received)
+}
+
+func TestSign(t *testing.T) {
+	assert.Equal(t, "t=1714564800,v1=6772f83f980eaa45c478fbaeb2e3661d945e7ba97f73c65ac97333a82742e16f", sign([]byte("secret"), 1714564800, []byte(`{}`)))
+}
+
+func assertSigned(t *testing.T, secret string, event receivedEvent) {
+	t.Helper()
+	header := event.header.Get(SignatureHeader)
+	require.Regexp(t, `^t=\d{10},v1=[0-9a-f]{64}$`, header)
+	timestamp, signature := header[2:12], header[16:]
+	assert.Equal(t, expectedSignature(secret, timestamp, string(event.body)), signature)
+
+	var decoded Event
+	require.NoError(t, jsonutil.Unmarshal(event.body, &decoded))
+}
+
+func expectedSignature(secret, timestamp, body string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "." + body))
+	return hex.EncodeToString(mac.Sum(nil))
+}